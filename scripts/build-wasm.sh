@@ -0,0 +1,14 @@
+#!/bin/bash
+# Build the wheel the WebAssembly (Pyodide) package installs
+
+set -e
+
+echo "🧩 Building wheel for the WebAssembly package..."
+rm -rf wasm/dist
+python3 -m build --wheel --outdir wasm/dist
+
+wheel=$(cd wasm/dist && ls shannon_codebase_insight-*-py3-none-any.whl | head -n 1)
+printf '{"wheel": "%s"}\n' "$wheel" > wasm/dist/manifest.json
+
+echo "✅ WebAssembly package built!"
+echo "📄 Output: wasm/dist/$wheel"
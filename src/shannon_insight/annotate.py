@@ -0,0 +1,203 @@
+"""Metric comments written into the source, above each function.
+
+For editors that cannot run the language server, ``shannon-insight
+annotate`` writes one comment above every function (above its decorators
+or annotations, at the function's indentation)::
+
+    # shannon: complexity=17 nesting=4 lines=62 churn=high
+    @retry
+    def sync(self, batch):
+
+    // shannon: complexity=3 nesting=1 lines=12 churn=low
+    func parse(s string) (Config, error) {
+
+complexity is 1 + decision points, nesting the deepest nesting inside the
+function, lines its length. churn rates the file against the repository's
+other files by commits in the last ``git_max_commits``: the top fifth is
+high, the next three tenths medium, the rest low; it is left out without git
+history.
+
+Running it again updates the comments in place, and ``--remove`` deletes
+them. Only lines of exactly this shape (``shannon:`` then ``key=value``
+pairs) are touched, so the edit is reversible and leaves other comments
+alone. Notebooks are skipped: their cells are JSON.
+"""
+
+from __future__ import annotations
+
+import re
+import subprocess
+from bisect import bisect_left
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import TYPE_CHECKING, Optional
+
+from .logging_config import get_logger
+from .scanning.notebook import is_notebook
+from .signals.function_outliers import function_complexity
+
+if TYPE_CHECKING:
+    from .scanning.syntax import FileSyntax, FunctionDef
+
+logger = get_logger(__name__)
+
+MARKER = "shannon:"
+
+# Languages whose line comments start with # or --; the rest use //
+_HASH_COMMENTS = frozenset({"python", "ruby", "yaml", "hcl", "shell", "r", "perl", "elixir"})
+_DASH_COMMENTS = frozenset({"sql", "lua", "haskell"})
+
+ANNOTATION_RE = re.compile(r"^[ \t]*(?:#|//|--) shannon: [a-z_]+=\S+(?: [a-z_]+=\S+)*[ \t]*$")
+
+# Lines above a function that belong to it: decorators and annotations
+_DECORATOR_RE = re.compile(r"^[ \t]*@")
+
+_GIT_TIMEOUT_SECONDS = 30
+
+
+@dataclass
+class AnnotateResult:
+    changed: list[str] = field(default_factory=list)  # files rewritten (or to be)
+    functions: int = 0  # annotations written, or removed with remove=True
+    skipped: list[str] = field(default_factory=list)  # notebooks, unreadable files
+
+    def to_dict(self) -> dict:
+        return {"changed": self.changed, "functions": self.functions, "skipped": self.skipped}
+
+
+def comment_prefix(language: str) -> str:
+    if language in _HASH_COMMENTS:
+        return "#"
+    if language in _DASH_COMMENTS:
+        return "--"
+    return "//"
+
+
+def annotation(fn: FunctionDef, lines: list[str], language: str, churn: Optional[str]) -> str:
+    """The comment text for one function, without indentation."""
+    body = lines[fn.start_line - 1 : fn.end_line]
+    parts = [
+        f"complexity={function_complexity(body)}",
+        f"nesting={fn.nesting_depth}",
+        f"lines={max(1, fn.end_line - fn.start_line + 1)}",
+    ]
+    if churn is not None:
+        parts.append(f"churn={churn}")
+    return f"{comment_prefix(language)} {MARKER} {' '.join(parts)}"
+
+
+def remove_annotations(text: str) -> tuple[str, int]:
+    """*text* without annotation lines, and how many were removed."""
+    lines = text.splitlines(keepends=True)
+    kept = [line for line in lines if not ANNOTATION_RE.match(line.rstrip("\r\n"))]
+    return "".join(kept), len(lines) - len(kept)
+
+
+def annotate_text(text: str, syntax: FileSyntax, churn: Optional[str] = None) -> tuple[str, int]:
+    """*text* with an up-to-date annotation above every function.
+
+    Line numbers in *syntax* refer to *text* as given, existing annotations
+    included. Returns the new text and the number of functions annotated.
+    """
+    lines = text.splitlines(keepends=True)
+    bare = [line.rstrip("\r\n") for line in lines]
+    newline = "\r\n" if lines and lines[0].endswith("\r\n") else "\n"
+    edits: dict[int, tuple[bool, str]] = {}  # line index -> (replace?, new line)
+    for fn in _functions(syntax):
+        if not 1 <= fn.start_line <= fn.end_line <= len(lines):
+            continue
+        anchor = fn.start_line - 1
+        while anchor > 0 and _DECORATOR_RE.match(bare[anchor - 1]):
+            anchor -= 1
+        first = bare[fn.start_line - 1]
+        indent = first[: len(first) - len(first.lstrip())]
+        line = indent + annotation(fn, bare, syntax.language, churn) + newline
+        if anchor > 0 and ANNOTATION_RE.match(bare[anchor - 1]):
+            edits[anchor - 1] = (True, line)
+        else:
+            edits.setdefault(anchor, (False, line))
+    for index in sorted(edits, reverse=True):
+        replace, line = edits[index]
+        if replace:
+            lines[index] = line
+        else:
+            lines.insert(index, line)
+    return "".join(lines), len(edits)
+
+
+def annotate_files(
+    root: Path,
+    syntax: dict[str, FileSyntax],
+    remove: bool = False,
+    churn: Optional[dict[str, str]] = None,
+    dry_run: bool = False,
+) -> AnnotateResult:
+    """Write (or with *remove*, delete) annotations in the files of *syntax*."""
+    result = AnnotateResult()
+    for path in sorted(syntax):
+        if is_notebook(Path(path)):
+            result.skipped.append(path)
+            continue
+        full = root / path
+        try:
+            text = full.read_text(encoding="utf-8")
+        except (OSError, UnicodeDecodeError) as e:
+            logger.debug(f"Cannot read {path}: {e}")
+            result.skipped.append(path)
+            continue
+        if remove:
+            updated, count = remove_annotations(text)
+        else:
+            level = churn.get(path, "low") if churn is not None else None
+            updated, count = annotate_text(text, syntax[path], level)
+        result.functions += count
+        if updated == text:
+            continue
+        result.changed.append(path)
+        if not dry_run:
+            full.write_text(updated, encoding="utf-8")
+    return result
+
+
+def churn_levels(root: Path, max_commits: int) -> Optional[dict[str, str]]:
+    """path -> "low" | "medium" | "high" by commits; None without git history."""
+    try:
+        out = subprocess.run(
+            ["git", "-C", str(root), "log", f"-n{max_commits}", "--format=", "--name-only"],
+            capture_output=True,
+            text=True,
+            timeout=_GIT_TIMEOUT_SECONDS,
+        )
+    except (FileNotFoundError, subprocess.TimeoutExpired) as e:
+        logger.debug(f"git log failed: {e}")
+        return None
+    if out.returncode != 0:
+        return None
+    counts = Counter(line for line in out.stdout.splitlines() if line)
+    return rank_churn(counts)
+
+
+def rank_churn(counts: dict[str, int]) -> dict[str, str]:
+    """Bucket files by commit count: top 20% high, next 30% medium, rest low.
+
+    A file's rank is the share of files with fewer commits, so files tied on
+    count share a level and a repository of equally changed files is all low.
+    """
+    values = sorted(counts.values())
+    levels = {}
+    for path, count in counts.items():
+        below = bisect_left(values, count) / len(values)
+        levels[path] = "high" if below >= 0.8 else "medium" if below >= 0.5 else "low"
+    return levels
+
+
+def _functions(syntax: FileSyntax) -> list[FunctionDef]:
+    """Functions and methods of a file, each once."""
+    seen: dict[int, FunctionDef] = {}
+    for fn in syntax.functions:
+        seen.setdefault(fn.start_line, fn)
+    for cls in syntax.classes:
+        for fn in cls.methods:
+            seen.setdefault(fn.start_line, fn)
+    return list(seen.values())
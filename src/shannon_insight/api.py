@@ -25,26 +25,29 @@ from typing import Optional
 from .config import load_config
 from .environment import discover_environment
 from .logging_config import get_logger, setup_logging
+from .scanning.grammars import grammar_extensions
 from .session import AnalysisSession
 
 logger = get_logger(__name__)
 
 
-def _has_historical_data(path: Path, min_snapshots: int = 3) -> bool:
+def _has_historical_data(
+    path: Path, min_snapshots: int = 3, url: Optional[str] = None
+) -> bool:
     """Check if historical data is available for persistence finders.
 
-    Returns True if .shannon/history.db exists and has at least min_snapshots.
+    Returns True if the history store has at least min_snapshots.
     This auto-enables chronic_problem and architecture_erosion finders.
     """
-    db_path = path / ".shannon" / "history.db"
-    if not db_path.exists():
+    from .persistence import HistoryDB
+
+    history = HistoryDB(str(path), url=url)
+    if not history.exists():
         return False
 
     try:
-        import sqlite3
-
-        with sqlite3.connect(str(db_path)) as conn:
-            cursor = conn.execute("SELECT COUNT(*) FROM snapshots")
+        with history as db:
+            cursor = db.conn.execute("SELECT COUNT(*) FROM snapshots")
             count: int = cursor.fetchone()[0]
             return count >= min_snapshots
     except Exception:
@@ -54,6 +57,7 @@ def _has_historical_data(path: Path, min_snapshots: int = 3) -> bool:
 def analyze(
     path: str = ".",
     config_file: Optional[Path] = None,
+    project_dir: Optional[Path] = None,
     **overrides,
 ):
     """Analyze a codebase and return findings.
@@ -69,6 +73,7 @@ def analyze(
     Args:
         path: Path to codebase root (default: current directory)
         config_file: Optional explicit config file path
+        project_dir: Directory holding shannon-insight.toml (default: cwd)
         **overrides: Configuration overrides (e.g., verbose=True, max_findings=100)
 
     Returns:
@@ -111,7 +116,7 @@ def analyze(
     enable_provenance = overrides.pop("enable_provenance", False)
 
     # 1. Load configuration
-    config = load_config(config_file=config_file, **overrides)
+    config = load_config(config_file=config_file, project_dir=project_dir, **overrides)
     logger.debug(f"Configuration loaded: {config.verbosity} mode")
 
     # Use config.enable_provenance if not explicitly overridden via API
@@ -129,6 +134,7 @@ def analyze(
         Path(path),
         allow_hidden_files=config.allow_hidden_files,
         follow_symlinks=config.follow_symlinks,
+        extra_extensions=grammar_extensions(config.grammars),
     )
     logger.info(
         f"Environment discovered: {env.file_count} files, "
@@ -143,8 +149,8 @@ def analyze(
     # 4. Run analysis kernel
     from .insights.kernel import InsightKernel
 
-    # Auto-detect historical data: enable persistence finders if history.db has snapshots
-    enable_persistence_finders = _has_historical_data(Path(path))
+    # Auto-detect historical data: enable persistence finders if history has snapshots
+    enable_persistence_finders = _has_historical_data(Path(path), url=config.history_url)
     if enable_persistence_finders:
         logger.debug("Historical data detected, enabling persistence finders")
 
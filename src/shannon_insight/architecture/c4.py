@@ -0,0 +1,536 @@
+"""C4-model architecture diagrams generated from the source tree.
+
+The repository is the software system. Its containers are the services of
+a monorepo: every directory holding a build manifest (go.mod, package.json,
+pyproject.toml, Cargo.toml, pom.xml, a Dockerfile...) or declared as an sbt
+sub-project, each file belonging to the deepest one above it. A repository
+without any is one container. Containers with no parsed source are dropped.
+
+Components are the packages of a container, grouped to the depth
+determine_module_depth picks for the container's own files (or --depth).
+
+Relationships come from two places:
+
+    imports  a file of one container or component importing a file of
+             another (shared libraries, generated clients)
+    http     a URL literal naming another service (``http://billing:8080``,
+             ``"https://payments-service/charge"``); the host's first label
+             is compared with container names, ignoring case, punctuation
+             and a -service / -svc / -api / -server suffix
+
+Entry points (see graph/reachability.py) describe each container, and a
+"User" person uses the containers with HTTP handlers or CLI commands.
+
+The model is written as Structurizr DSL or as PlantUML using the C4-PlantUML
+standard library, at container level (one diagram of the services) or
+component level (the services with their components).
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import Callable, Optional, Sequence
+
+from ..diligence.inventory import tracked_files
+from ..graph.reachability import find_entry_points
+from ..graph.sbt import discover_sbt_projects
+from ..hygiene.sources import SourceSet
+from .modules import _group_by_depth, determine_module_depth
+
+C4_FORMATS = ("structurizr", "plantuml")
+C4_LEVELS = ("container", "component")
+
+SERVICE_MANIFESTS = frozenset(
+    {
+        "go.mod",
+        "package.json",
+        "pyproject.toml",
+        "setup.py",
+        "Cargo.toml",
+        "pom.xml",
+        "build.gradle",
+        "build.gradle.kts",
+        "Gemfile",
+        "composer.json",
+        "Dockerfile",
+        "Containerfile",
+    }
+)
+
+_URL_RE = re.compile(r"""["'`](?:https?|grpcs?|wss?)://([A-Za-z0-9][A-Za-z0-9_.-]*)""")
+_HOST_SUFFIX_RE = re.compile(r"(?:service|svc|api|server)$")
+
+# Entry kind -> how a container description counts it
+_ENTRY_LABELS = {
+    "main": ("main", "mains"),
+    "http": ("HTTP handler", "HTTP handlers"),
+    "consumer": ("consumer", "consumers"),
+    "cli": ("CLI command", "CLI commands"),
+}
+
+_LANGUAGE_NAMES = {
+    "cpp": "C++",
+    "csharp": "C#",
+    "javascript": "JavaScript",
+    "typescript": "TypeScript",
+    "php": "PHP",
+    "sql": "SQL",
+    "yaml": "YAML",
+    "hcl": "HCL",
+    "proto": "Protocol Buffers",
+}
+
+
+@dataclass
+class Component:
+    """A package group inside a container."""
+
+    id: str
+    name: str  # directory relative to the container, or the container name
+    path: str  # directory relative to the repository root
+    language: str = ""
+    files: list[str] = field(default_factory=list)
+    entries: dict[str, int] = field(default_factory=dict)  # entry kind -> count
+
+    @property
+    def user_facing(self) -> bool:
+        return _user_facing(self.entries)
+
+
+@dataclass
+class Container:
+    """A deployable service: a directory with its own build manifest."""
+
+    id: str
+    name: str
+    path: str  # directory relative to the repository root, "." for the root
+    manifests: list[str] = field(default_factory=list)
+    language: str = ""
+    files: list[str] = field(default_factory=list)
+    entries: dict[str, int] = field(default_factory=dict)  # entry kind -> count
+    components: list[Component] = field(default_factory=list)
+
+    @property
+    def description(self) -> str:
+        counts = [
+            f"{n} {labels[n != 1]}"
+            for kind, labels in _ENTRY_LABELS.items()
+            if (n := self.entries.get(kind, 0))
+        ]
+        where = self.path if self.path != "." else "repository root"
+        return f"{where}: {', '.join(counts)}" if counts else where
+
+    @property
+    def user_facing(self) -> bool:
+        return _user_facing(self.entries)
+
+
+@dataclass(frozen=True)
+class Relation:
+    """A dependency between two containers or two components."""
+
+    source: str  # element id
+    target: str
+    kind: str  # "imports" or "http"
+    count: int  # importing files, or URL literals
+
+    @property
+    def description(self) -> str:
+        return "Calls" if self.kind == "http" else "Uses"
+
+    @property
+    def technology(self) -> str:
+        if self.kind == "http":
+            return "HTTP"
+        return f"imports ({self.count} file{'s' if self.count != 1 else ''})"
+
+
+@dataclass
+class C4Model:
+    """Containers, components and relationships of one repository.
+
+    Attributes:
+        system: Software system name (the repository directory)
+        containers: Services, by path
+        relations: Container -> container relationships
+        component_relations: Component -> component relationships
+    """
+
+    system: str
+    containers: list[Container] = field(default_factory=list)
+    relations: list[Relation] = field(default_factory=list)
+    component_relations: list[Relation] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {
+            "system": self.system,
+            "containers": [
+                {
+                    "id": c.id,
+                    "name": c.name,
+                    "path": c.path,
+                    "manifests": c.manifests,
+                    "language": c.language,
+                    "files": len(c.files),
+                    "entry_points": c.entries,
+                    "components": [
+                        {
+                            "id": m.id,
+                            "name": m.name,
+                            "path": m.path,
+                            "files": len(m.files),
+                            "entry_points": m.entries,
+                        }
+                        for m in c.components
+                    ],
+                }
+                for c in self.containers
+            ],
+            "relations": [_relation_dict(r) for r in self.relations],
+            "component_relations": [_relation_dict(r) for r in self.component_relations],
+        }
+
+
+def find_containers(
+    root_name: str, paths: list[str], sbt_directories: Sequence[str] = ()
+) -> list[Container]:
+    """Containers declared by the manifests among *paths*, by path.
+
+    Args:
+        root_name: Name of the root container (the repository directory)
+        paths: Repository files, POSIX paths relative to the root
+        sbt_directories: Directories of sbt sub-projects
+    """
+    manifests: dict[str, list[str]] = {}
+    for path in paths:
+        name = PurePosixPath(path).name
+        if name in SERVICE_MANIFESTS:
+            directory = PurePosixPath(path).parent.as_posix()
+            manifests.setdefault(directory, []).append(name)
+    for directory in sbt_directories:
+        manifests.setdefault(directory, []).append("build.sbt")
+    manifests.setdefault(".", [])
+
+    names = Counter(PurePosixPath(d).name for d in manifests if d != ".")
+    taken = {"user", "system"}  # the person and the software system
+    containers = []
+    for directory in sorted(manifests):
+        if directory == ".":
+            name = root_name
+        else:
+            short = PurePosixPath(directory).name
+            name = short if names[short] == 1 else directory
+        containers.append(
+            Container(_identifier(name, taken), name, directory, sorted(manifests[directory]))
+        )
+    return containers
+
+
+def build_model(
+    sources: SourceSet,
+    imports: dict[str, list[str]],
+    depth: Optional[int] = None,
+    paths: Optional[list[str]] = None,
+) -> C4Model:
+    """The C4 model of the repository *sources* were loaded from.
+
+    Args:
+        sources: Parsed sources of the repository
+        imports: path -> paths it imports (the dependency graph adjacency)
+        depth: Component directory depth below each container (None = auto)
+        paths: Repository files (default: tracked_files), for the manifests
+    """
+    root = sources.root
+    paths = tracked_files(root) if paths is None else paths
+    sbt = [p.directory for p in discover_sbt_projects(root) if p.directory != "."]
+    containers = find_containers(root.name or "system", paths, sbt)
+
+    owner: dict[str, Container] = {}
+    for path in sorted(sources.syntax):
+        container = _container_of(path, containers)
+        container.files.append(path)
+        owner[path] = container
+    containers = [c for c in containers if c.files]
+
+    component_of: dict[str, Component] = {}
+    taken = {c.id for c in containers}
+    for container in containers:
+        container.language = _dominant_language(container.files, sources)
+        relative = {_relative(path, container.path): path for path in container.files}
+        groups = _group_by_depth(
+            list(relative), determine_module_depth(list(relative)) if depth is None else depth
+        )
+        for group, members in sorted(groups.items()):
+            name = container.name if group == "." else group
+            path = (PurePosixPath(container.path) / group).as_posix()
+            files = [relative[m] for m in members]
+            component = Component(
+                _identifier(f"{container.id}_{name}", taken),
+                name,
+                path,
+                _dominant_language(files, sources),
+                files,
+            )
+            container.components.append(component)
+            for file in files:
+                component_of[file] = component
+
+    for entry in find_entry_points(sources.syntax):
+        path = entry.symbol.path
+        if path in owner:
+            for element in (owner[path], component_of[path]):
+                element.entries[entry.kind] = element.entries.get(entry.kind, 0) + 1
+
+    calls = _http_calls(sources, owner, containers)
+    by_id = {c.id: c for c in containers}
+    relations = _import_relations(imports, {p: c.id for p, c in owner.items()})
+    relations += _http_relations(calls, lambda path, callee: (owner[path].id, callee))
+    component_relations = _import_relations(
+        imports, {p: c.id for p, c in component_of.items()}
+    )
+    component_relations += _http_relations(
+        calls, lambda path, callee: (component_of[path].id, _http_target(by_id[callee]).id)
+    )
+    return C4Model(
+        system=root.name or "system",
+        containers=containers,
+        relations=sorted(relations, key=lambda r: (r.source, r.target, r.kind)),
+        component_relations=sorted(
+            component_relations, key=lambda r: (r.source, r.target, r.kind)
+        ),
+    )
+
+
+# ── Writers ────────────────────────────────────────────────────────
+
+
+def render(model: C4Model, fmt: str = "structurizr", level: str = "container") -> str:
+    """*model* as Structurizr DSL or C4-PlantUML, at container or component level."""
+    if fmt not in C4_FORMATS:
+        raise ValueError(f"unknown C4 format {fmt!r}, expected one of {C4_FORMATS}")
+    if level not in C4_LEVELS:
+        raise ValueError(f"unknown C4 level {level!r}, expected one of {C4_LEVELS}")
+    writer = _structurizr if fmt == "structurizr" else _plantuml
+    return "\n".join(writer(model, level == "component")) + "\n"
+
+
+def _structurizr(model: C4Model, components: bool) -> list[str]:
+    name = _quote(model.system)
+    lines = [
+        f'workspace "{name}" "Generated by shannon-insight from the source tree" {{',
+        "    model {",
+        '        user = person "User"',
+        f'        system = softwareSystem "{name}" {{',
+    ]
+    for c in model.containers:
+        header = (
+            f'            {c.id} = container "{_quote(c.name)}" "{_quote(c.description)}" '
+            f'"{_technology(c.language)}"'
+        )
+        if not (components and c.components):
+            lines.append(header)
+            continue
+        lines.append(header + " {")
+        for m in c.components:
+            lines.append(
+                f'                {m.id} = component "{_quote(m.name)}" "{_quote(m.path)}" '
+                f'"{_technology(m.language)}"'
+            )
+        lines.append("            }")
+    lines.append("        }")
+    for c in model.containers:
+        if c.user_facing:
+            lines.append(f'        user -> {c.id} "Uses" "{_entry_technology(c.entries)}"')
+        for m in c.components if components else []:
+            if m.user_facing:
+                lines.append(f'        user -> {m.id} "Uses" "{_entry_technology(m.entries)}"')
+    relations = model.relations + (model.component_relations if components else [])
+    for r in relations:
+        lines.append(f'        {r.source} -> {r.target} "{r.description}" "{r.technology}"')
+    lines += [
+        "    }",
+        "",
+        "    views {",
+        "        systemContext system {",
+        "            include *",
+        "            autolayout lr",
+        "        }",
+        "        container system {",
+        "            include *",
+        "            autolayout lr",
+        "        }",
+    ]
+    if components:
+        for c in model.containers:
+            if c.components:
+                lines += [
+                    f"        component {c.id} {{",
+                    "            include *",
+                    "            autolayout lr",
+                    "        }",
+                ]
+    lines += ["    }", "}"]
+    return lines
+
+
+def _plantuml(model: C4Model, components: bool) -> list[str]:
+    library = "C4_Component" if components else "C4_Container"
+    lines = [
+        "@startuml",
+        f"!include <C4/{library}>",
+        "",
+        f"title {_quote(model.system)} -- generated by shannon-insight",
+        "",
+        'Person(user, "User")',
+        f'System_Boundary(system, "{_quote(model.system)}") {{',
+    ]
+    for c in model.containers:
+        args = f'"{_quote(c.name)}", "{_technology(c.language)}", "{_quote(c.description)}"'
+        if not (components and c.components):
+            lines.append(f"    Container({c.id}, {args})")
+            continue
+        lines.append(f'    Container_Boundary({c.id}, "{_quote(c.name)}") {{')
+        for m in c.components:
+            lines.append(
+                f'        Component({m.id}, "{_quote(m.name)}", "{_technology(m.language)}", '
+                f'"{_quote(m.path)}")'
+            )
+        lines.append("    }")
+    lines += ["}", ""]
+    # A boundary cannot be the end of a relationship; components carry them instead
+    bounded = {c.id for c in model.containers if components and c.components}
+    users = [c for c in model.containers if c.id not in bounded]
+    users += [m for c in model.containers if c.id in bounded for m in c.components]
+    for element in users:
+        if element.user_facing:
+            technology = _entry_technology(element.entries)
+            lines.append(f'Rel(user, {element.id}, "Uses", "{technology}")')
+    relations = [
+        r for r in model.relations if r.source not in bounded and r.target not in bounded
+    ]
+    relations += model.component_relations if components else []
+    for r in relations:
+        lines.append(f'Rel({r.source}, {r.target}, "{r.description}", "{r.technology}")')
+    lines += ["", "SHOW_LEGEND()", "@enduml"]
+    return lines
+
+
+# ── Helpers ────────────────────────────────────────────────────────
+
+
+def _container_of(path: str, containers: list[Container]) -> Container:
+    """The deepest container whose directory holds *path* (the root one otherwise)."""
+    best = next(c for c in containers if c.path == ".")
+    for c in containers:
+        if c.path != "." and path.startswith(c.path + "/"):
+            if best.path == "." or len(c.path) > len(best.path):
+                best = c
+    return best
+
+
+def _relative(path: str, directory: str) -> str:
+    return path if directory == "." else path[len(directory) + 1 :]
+
+
+def _dominant_language(files: list[str], sources: SourceSet) -> str:
+    counts = Counter(sources.language_of(f) for f in files)
+    counts.pop("unknown", None)
+    return min(counts, key=lambda lang: (-counts[lang], lang)) if counts else ""
+
+
+def _import_relations(imports: dict[str, list[str]], element_of: dict[str, str]) -> list[Relation]:
+    """Relations between the elements files belong to, counting importing files."""
+    importers: dict[tuple[str, str], set[str]] = {}
+    for path, targets in imports.items():
+        source = element_of.get(path)
+        if source is None:
+            continue
+        for target in targets:
+            other = element_of.get(target)
+            if other is not None and other != source:
+                importers.setdefault((source, other), set()).add(path)
+    return [
+        Relation(source, target, "imports", len(files))
+        for (source, target), files in sorted(importers.items())
+    ]
+
+
+def _http_calls(
+    sources: SourceSet, owner: dict[str, Container], containers: list[Container]
+) -> Counter[tuple[str, str]]:
+    """(calling file, called container id) -> URL literals naming that container."""
+    by_host = {_service_key(c.name): c for c in containers}
+    by_host.update({_service_key(PurePosixPath(c.path).name): c for c in containers})
+    by_host.pop("", None)
+    calls: Counter[tuple[str, str]] = Counter()
+    for path, content in sources.content.items():
+        caller = owner.get(path)
+        if caller is None:
+            continue
+        for match in _URL_RE.finditer(content):
+            callee = by_host.get(_service_key(match.group(1).split(".")[0]))
+            if callee is not None and callee is not caller:
+                calls[(path, callee.id)] += 1
+    return calls
+
+
+def _http_relations(
+    calls: Counter[tuple[str, str]],
+    ends: Callable[[str, str], tuple[str, str]],
+) -> list[Relation]:
+    """HTTP relations between the elements *ends* maps each (file, container id) call to."""
+    counts: Counter[tuple[str, str]] = Counter()
+    for (path, callee), n in calls.items():
+        counts[ends(path, callee)] += n
+    return [Relation(s, t, "http", n) for (s, t), n in sorted(counts.items())]
+
+
+def _http_target(container: Container) -> Component:
+    """The component HTTP calls to *container* land on: its first with HTTP handlers."""
+    handlers = [m for m in container.components if m.entries.get("http")]
+    return (handlers or container.components)[0]
+
+
+def _service_key(name: str) -> str:
+    """*name* lowercased, without punctuation or a service-like suffix."""
+    key = re.sub(r"[^a-z0-9]", "", name.lower())
+    stripped = _HOST_SUFFIX_RE.sub("", key)
+    return stripped or key
+
+
+def _identifier(name: str, taken: set[str]) -> str:
+    """A unique identifier for *name* valid in both Structurizr DSL and PlantUML."""
+    base = re.sub(r"[^A-Za-z0-9_]+", "_", name).strip("_") or "element"
+    if base[0].isdigit():
+        base = "_" + base
+    identifier = base
+    n = 2
+    while identifier in taken:
+        identifier = f"{base}_{n}"
+        n += 1
+    taken.add(identifier)
+    return identifier
+
+
+def _technology(language: str) -> str:
+    return _LANGUAGE_NAMES.get(language, language.capitalize())
+
+
+def _user_facing(entries: dict[str, int]) -> bool:
+    return bool(entries.get("http") or entries.get("cli"))
+
+
+def _entry_technology(entries: dict[str, int]) -> str:
+    kinds = [k for k in ("http", "cli") if entries.get(k)]
+    return ", ".join("HTTP" if k == "http" else "CLI" for k in kinds)
+
+
+def _quote(text: str) -> str:
+    return text.replace('"', "'")
+
+
+def _relation_dict(r: Relation) -> dict:
+    return {"source": r.source, "target": r.target, "kind": r.kind, "count": r.count}
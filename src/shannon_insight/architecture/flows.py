@@ -0,0 +1,549 @@
+"""Service-to-service request flows: client calls matched to the routes they hit.
+
+Services are the containers of the C4 model (see c4.py): directories with
+a build manifest, every file belonging to the deepest one above it. Each
+service declares inbound endpoints, and every outbound call a service
+makes is matched to an endpoint of another one:
+
+    http  routes declared with decorators or router calls --
+          @app.get("/users/{id}") and @router.route (Flask, FastAPI),
+          @GetMapping / @RequestMapping and @Get / @Controller prefixes
+          (Spring, NestJS), app.get("/x") / router.post (Express, Koa,
+          Fastify), mux.HandleFunc("GET /x") / r.GET("/x") (net/http,
+          gorilla, chi, gin, echo), path("users/<int:id>/") in urls.py and
+          get "/x" in routes.rb -- are hit by URL path literals in another
+          service: "http://users:8080/users/42", f"{USERS}/users/{uid}",
+          "/users/" + id, fmt.Sprintf("%s/users/%d", base, id)
+    grpc  services registered on a server (RegisterUsersServer,
+          add_UsersServicer_to_server, UsersGrpc.UsersImplBase,
+          Users.UsersBase) are hit by the stubs of another service
+          (NewUsersClient, UsersStub, UsersGrpc.newBlockingStub, new
+          Users.UsersClient); each call of one of the service's RPCs, as
+          declared in a .proto file, is one call
+
+Path parameters in any syntax ({id}, :id, <int:id>, %s, ${id}) match any
+one segment, and a literal ending in "/" is followed by one. A call naming
+a host, directly or through a base URL constant of its file (USERS =
+"http://users:8080", then f"{USERS}/x" or USERS + "/x"), only matches the
+service the host names (c4's host matching), and counts against it even
+when none of its routes match; a host that is not a service is external
+and ignored. The HTTP method, when the call's line names one
+(requests.post, http.MethodPut, axios.delete), must be one the route
+accepts. When routes of several services match a call equally well, it is
+counted as ambiguous rather than guessed.
+
+The flows are a map for on-call runbooks, exported as Mermaid (renders in
+Markdown) or Graphviz DOT, each edge labelled with its call count and its
+busiest endpoints.
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import Optional, Sequence
+
+from ..diligence.inventory import tracked_files
+from ..graph.sbt import discover_sbt_projects
+from ..hygiene.sources import SourceSet
+from ..semantics.roles import TEST_PATH_PATTERNS
+from .c4 import Container, _container_of, _service_key, find_containers
+
+FLOW_FORMATS = ("mermaid", "dot")
+PROTOCOLS = ("http", "grpc")
+
+# Endpoints named on a diagram edge, busiest first
+EDGE_ENDPOINTS = 3
+
+ANY_METHOD = "ANY"
+_METHODS = ("GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS")
+
+# ── Inbound routes ────────────────────────────────────────────────
+
+# @app.get("/x"), @router.route("/x", methods=["POST"]), @app.api_route
+_DECORATOR_ROUTE_RE = re.compile(
+    r"@\w+(?:\.\w+)*\.(get|post|put|patch|delete|head|options|route|api_route|websocket)"
+    r"\(\s*[rbu]?[\"']([^\"']*)[\"']([^\n]*)"
+)
+# @GetMapping("/x"), @RequestMapping(value = "/x"), @Get(':id') (NestJS)
+_ANNOTATION_ROUTE_RE = re.compile(
+    r"@(Get|Post|Put|Patch|Delete|Request)(?:Mapping)?\(\s*(?:(?:value|path)\s*=\s*)?"
+    r"\{?\s*[\"']([^\"']*)[\"']([^\n]*)"
+)
+# Class-level prefixes: @RequestMapping("/api") / @Controller('users') before the class
+_PREFIX_RE = re.compile(
+    r"@(?:RequestMapping|Controller)\(\s*(?:(?:value|path)\s*=\s*)?\{?\s*[\"']([^\"']*)[\"']"
+)
+_CLASS_RE = re.compile(r"\b(?:class|interface|object)\s+\w+")
+# app.get('/x', handler), router.post("/x", ...), fastify.all(`/x`, ...); the
+# handler argument tells them from client calls such as api.get('/x')
+_ROUTER_ROUTE_RE = re.compile(
+    r"\b(?:app|router|server|fastify|routes?)\.(get|post|put|patch|delete|head|options|all)"
+    r"\(\s*[\"'`]([^\"'`]*)[\"'`]\s*,"
+)
+# mux.HandleFunc("GET /x", h), r.GET("/x", h), r.Get("/x", h), e.Any("/x", h)
+_GO_ROUTE_RE = re.compile(
+    r"\.(HandleFunc|Handle|GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|Get|Post|Put|Patch|Delete"
+    r"|Head|Options|Any)\(\s*\"([^\"]*)\"\s*,([^\n]*)"
+)
+# path("users/<int:id>/", view) and re_path in Django urls.py
+_DJANGO_ROUTE_RE = re.compile(r"\b(?:re_)?path\(\s*r?[\"']([^\"']*)[\"']")
+# get "/x", to: "users#show" in config/routes.rb
+_RAILS_ROUTE_RE = re.compile(r"^\s*(get|post|put|patch|delete)\s+[\"']([^\"']+)[\"']", re.M)
+_METHODS_ARG_RE = re.compile(r"methods\s*=\s*[\[(]([^\])]*)")
+_GO_METHODS_RE = re.compile(r"\.Methods\(([^)]*)\)")
+_SPRING_METHOD_RE = re.compile(r"RequestMethod\.(\w+)")
+
+# ── gRPC servers and clients ──────────────────────────────────────
+
+_GRPC_SERVER_RES = (
+    re.compile(r"\bRegister(\w+)Server\("),  # Go
+    re.compile(r"\badd_(\w+)Servicer_to_server\("),  # Python
+    re.compile(r"\b(\w+)Grpc\.\1ImplBase\b"),  # Java, Kotlin
+    re.compile(r"\b(\w+)\.\1Base\b"),  # C#
+)
+_GRPC_CLIENT_RES = (
+    re.compile(r"\bNew(\w+)Client\("),  # Go
+    re.compile(r"\b(\w+)Stub\("),  # Python
+    re.compile(r"\b(\w+)Grpc\.new(?:Blocking|Future)?Stub\("),  # Java, Kotlin
+    re.compile(r"\bnew\s+(?:\w+\.)*(\w+)Client\("),  # C#, Node
+)
+
+# ── Outbound HTTP calls ───────────────────────────────────────────
+
+# A path literal, optionally after a scheme and host or a base-URL placeholder
+_CALL_RE = re.compile(
+    r"[\"'`](?:(?:https?)://([A-Za-z0-9][\w.-]*)(?::\d+)?|\$?\{(\w*)[^}\"'`]*\}|%[sv])?"
+    r"(/[^\"'`\s]*)[\"'`]"
+)
+# USERS = "http://users:8080", usersUrl: 'http://users' -- base URLs named in a file
+_BASE_URL_RE = re.compile(r"\b(\w+)\s*[:=]\s*[\"'`]https?://([A-Za-z0-9][\w.-]*)")
+# USERS + "/users", baseURL+"/x": a base URL concatenated before the literal
+_CONCAT_RE = re.compile(r"\b(\w+)\s*\+\s*$")
+# requests.post, http.MethodPut, restTemplate.getForObject, method: "DELETE"
+_CALL_METHOD_RE = re.compile(
+    r"(?:\b|(?<=Method))(get|post|put|patch|delete|head|options)(?:\b|(?=For[A-Z]))", re.I
+)
+_PARAM_RE = re.compile(r"^(?:\{.*\}|\$\{.*\}|:\w+|<.*>|\*+|%\w|\(.*\)|\[.*\]|)$")
+_LOCAL_HOSTS = frozenset({"localhost", "127.0.0.1", "0.0.0.0"})
+
+
+@dataclass(frozen=True)
+class Endpoint:
+    """An inbound endpoint a service declares."""
+
+    container: str  # container id
+    protocol: str  # one of PROTOCOLS
+    method: str  # HTTP method or ANY_METHOD; the RPC for grpc
+    path: str  # route as declared; the gRPC service for grpc
+    file: str
+    line: int
+
+    @property
+    def label(self) -> str:
+        if self.protocol == "grpc":
+            return f"{self.path}/{self.method}"
+        return self.path if self.method == ANY_METHOD else f"{self.method} {self.path}"
+
+    @property
+    def segments(self) -> tuple[str, ...]:
+        return _segments(self.path)
+
+
+@dataclass(frozen=True)
+class Call:
+    """An outbound call and the endpoint of another service it was matched to."""
+
+    container: str
+    file: str
+    line: int
+    endpoint: Endpoint
+
+
+@dataclass
+class FlowEdge:
+    """Calls from one service to another over one protocol."""
+
+    source: str
+    target: str
+    protocol: str
+    calls: int = 0
+    endpoints: Counter = field(default_factory=Counter)  # endpoint label -> calls
+    files: set[str] = field(default_factory=set)
+
+    def busiest(self, n: int = EDGE_ENDPOINTS) -> list[tuple[str, int]]:
+        return sorted(self.endpoints.items(), key=lambda item: (-item[1], item[0]))[:n]
+
+
+@dataclass
+class FlowMap:
+    """Services, their endpoints and the calls between them.
+
+    Attributes:
+        system: Repository name
+        containers: Services, by path
+        endpoints: Inbound endpoints of every service
+        calls: Outbound calls matched to another service's endpoint
+        ambiguous: Calls matching endpoints of several services equally well
+    """
+
+    system: str
+    containers: list[Container] = field(default_factory=list)
+    endpoints: list[Endpoint] = field(default_factory=list)
+    calls: list[Call] = field(default_factory=list)
+    ambiguous: int = 0
+
+    def edges(self) -> list[FlowEdge]:
+        """Service-to-service edges, most calls first."""
+        edges: dict[tuple[str, str, str], FlowEdge] = {}
+        for call in self.calls:
+            target = call.endpoint
+            key = (call.container, target.container, target.protocol)
+            edge = edges.setdefault(key, FlowEdge(*key))
+            edge.calls += 1
+            edge.endpoints[target.label] += 1
+            edge.files.add(call.file)
+        return sorted(edges.values(), key=lambda e: (-e.calls, e.source, e.target, e.protocol))
+
+    def name_of(self, container_id: str) -> str:
+        return next((c.name for c in self.containers if c.id == container_id), container_id)
+
+    def to_dict(self) -> dict:
+        return {
+            "system": self.system,
+            "services": [
+                {
+                    "id": c.id,
+                    "name": c.name,
+                    "path": c.path,
+                    "endpoints": sum(1 for e in self.endpoints if e.container == c.id),
+                }
+                for c in self.containers
+            ],
+            "edges": [
+                {
+                    "source": e.source,
+                    "target": e.target,
+                    "protocol": e.protocol,
+                    "calls": e.calls,
+                    "files": len(e.files),
+                    "endpoints": dict(e.busiest(len(e.endpoints))),
+                }
+                for e in self.edges()
+            ],
+            "calls": [
+                {
+                    "source": c.container,
+                    "file": c.file,
+                    "line": c.line,
+                    "target": c.endpoint.container,
+                    "protocol": c.endpoint.protocol,
+                    "endpoint": c.endpoint.label,
+                    "endpoint_file": c.endpoint.file,
+                    "endpoint_line": c.endpoint.line,
+                }
+                for c in self.calls
+            ],
+            "ambiguous_calls": self.ambiguous,
+        }
+
+
+def build_flows(sources: SourceSet, paths: Optional[list[str]] = None) -> FlowMap:
+    """The request flows between the services of the repository *sources* came from.
+
+    Args:
+        sources: Parsed sources of the repository
+        paths: Repository files (default: tracked_files), for the manifests
+    """
+    root = sources.root
+    paths = tracked_files(root) if paths is None else paths
+    sbt = [p.directory for p in discover_sbt_projects(root) if p.directory != "."]
+    containers = find_containers(root.name or "system", paths, sbt)
+    owner = {path: _container_of(path, containers) for path in sorted(sources.syntax)}
+    containers = [c for c in containers if c in owner.values()]
+
+    rpcs = proto_rpcs(sources)
+    endpoints: list[Endpoint] = []
+    declared: dict[str, set[int]] = {}  # path -> offsets of route literals
+    for path, container in owner.items():
+        content = sources.content.get(path, "")
+        routes, offsets = find_routes(path, content, container.id)
+        endpoints += routes
+        endpoints += find_grpc_servers(path, content, container.id, rpcs)
+        declared[path] = offsets
+
+    flows = FlowMap(root.name or "system", containers, endpoints)
+    by_host = {_service_key(c.name): c.id for c in containers}
+    by_host.update({_service_key(PurePosixPath(c.path).name): c.id for c in containers})
+    by_host.pop("", None)
+    http = [e for e in endpoints if e.protocol == "http"]
+    servers = {e.path: e for e in endpoints if e.protocol == "grpc"}
+    for path, container in owner.items():
+        if _is_test(path):
+            continue
+        content = sources.content.get(path, "")
+        for line, offset, host, literal in _http_literals(content):
+            if offset in declared[path]:
+                continue
+            matches = match_call(literal, _call_method(content, offset), http, container.id)
+            if host and host not in _LOCAL_HOSTS:
+                target = by_host.get(_service_key(host.split(".")[0]))
+                if target is None or target == container.id:
+                    continue  # external, or the service calling itself
+                # The host names the service even when no declared route matches
+                matches = [e for e in matches if e.container == target] or [
+                    Endpoint(target, "http", ANY_METHOD, literal, "", 0)
+                ]
+            if len({e.container for e in matches}) > 1:
+                flows.ambiguous += 1
+            elif matches:
+                flows.calls.append(Call(container.id, path, line, matches[0]))
+        for line, endpoint in _grpc_calls(content, servers, rpcs, container.id):
+            flows.calls.append(Call(container.id, path, line, endpoint))
+    flows.calls.sort(key=lambda c: (c.container, c.file, c.line))
+    return flows
+
+
+def find_routes(path: str, content: str, container: str) -> tuple[list[Endpoint], set[int]]:
+    """HTTP routes *path* declares, and the offsets of their path literals."""
+    name = PurePosixPath(path).name
+    routes: list[Endpoint] = []
+    offsets: set[int] = set()
+
+    def add(method: str, route: str, offset: int) -> None:
+        route = route if route.startswith("/") else "/" + route
+        routes.append(Endpoint(container, "http", method, route, path, _line(content, offset)))
+        offsets.add(offset)
+
+    for m in _DECORATOR_ROUTE_RE.finditer(content):
+        verb, rest = m[1].upper(), m[3]
+        if verb in ("ROUTE", "API_ROUTE"):
+            listed = _METHODS_ARG_RE.search(rest)
+            for method in _methods_in(listed[1] if listed else "") or [ANY_METHOD]:
+                add(method, m[2], m.start(2) - 1)
+        else:
+            add(verb if verb in _METHODS else ANY_METHOD, m[2], m.start(2) - 1)
+
+    class_at = _CLASS_RE.search(content)
+    prefixes = [
+        p[1] for p in _PREFIX_RE.finditer(content) if class_at and p.start() < class_at.start()
+    ]
+    prefix = prefixes[0].rstrip("/") if prefixes else ""
+    for m in _ANNOTATION_ROUTE_RE.finditer(content):
+        if class_at and m.start() < class_at.start():
+            offsets.add(m.start(2) - 1)  # the class prefix itself
+            continue
+        verb = m[1].upper()
+        methods = [verb] if verb != "REQUEST" else []
+        methods = methods or _methods_in(" ".join(_SPRING_METHOD_RE.findall(m[3])))
+        for method in methods or [ANY_METHOD]:
+            add(method, _join(prefix, m[2]), m.start(2) - 1)
+
+    for m in _ROUTER_ROUTE_RE.finditer(content):
+        verb = m[1].upper()
+        add(verb if verb in _METHODS else ANY_METHOD, m[2], m.start(2) - 1)
+
+    if path.endswith(".go"):
+        for m in _GO_ROUTE_RE.finditer(content):
+            method, route = m[1].upper(), m[2]
+            if " " in route:  # Go 1.22 patterns: "GET /users/{id}"
+                method, _, route = route.partition(" ")
+            if not route.startswith("/"):
+                continue
+            if method not in _METHODS:
+                listed = _GO_METHODS_RE.search(m[3])
+                method = (_methods_in(listed[1] if listed else "") or [ANY_METHOD])[0]
+            add(method, route, m.start(2) - 1)
+
+    if name == "urls.py":
+        for m in _DJANGO_ROUTE_RE.finditer(content):
+            add(ANY_METHOD, m[1].lstrip("^").rstrip("$"), m.start(1) - 1)
+    if name == "routes.rb":
+        for m in _RAILS_ROUTE_RE.finditer(content):
+            add(m[1].upper(), m[2], m.start(2) - 1)
+    return routes, offsets
+
+
+def find_grpc_servers(
+    path: str, content: str, container: str, rpcs: dict[str, list[str]]
+) -> list[Endpoint]:
+    """One endpoint per RPC of every gRPC service registered in *path* (one if unknown)."""
+    endpoints = []
+    for pattern in _GRPC_SERVER_RES:
+        for m in pattern.finditer(content):
+            service = m[1]
+            line = _line(content, m.start())
+            for rpc in rpcs.get(service, ["*"]):
+                endpoints.append(Endpoint(container, "grpc", rpc, service, path, line))
+    return endpoints
+
+
+def proto_rpcs(sources: SourceSet) -> dict[str, list[str]]:
+    """gRPC service name -> its RPC names, from the .proto files of the codebase."""
+    rpcs: dict[str, list[str]] = {}
+    for syntax in sources.syntax.values():
+        if syntax.language != "proto":
+            continue
+        for cls in syntax.classes:
+            if cls.is_abstract:
+                names = [fn.name.rpartition(".")[2] for fn in cls.methods]
+                rpcs.setdefault(cls.name, []).extend(names)
+    return rpcs
+
+
+def match_call(
+    literal: str, method: Optional[str], endpoints: Sequence[Endpoint], caller: str
+) -> list[Endpoint]:
+    """The most specific endpoints of other services *literal* can reach."""
+    call = _segments(literal)
+    if literal.endswith("/"):
+        call += ("*",)
+    if not any(s != "*" for s in call):
+        return []
+    best: list[Endpoint] = []
+    best_score = 0
+    for endpoint in endpoints:
+        if endpoint.container == caller:
+            continue
+        if method and endpoint.method not in (ANY_METHOD, method):
+            continue
+        route = endpoint.segments
+        if len(route) != len(call):
+            continue
+        if not all(a == b or "*" in (a, b) for a, b in zip(call, route)):
+            continue
+        # A parameter is likelier to fill a parameter than a literal segment
+        score = sum(1 for a, b in zip(call, route) if a == b)
+        if score > best_score:
+            best, best_score = [endpoint], score
+        elif score == best_score and score:
+            best.append(endpoint)
+    return best
+
+
+# ── Writers ────────────────────────────────────────────────────────
+
+
+def render(flows: FlowMap, fmt: str = "mermaid") -> str:
+    """*flows* as a Mermaid flowchart or a Graphviz digraph."""
+    if fmt not in FLOW_FORMATS:
+        raise ValueError(f"unknown flow format {fmt!r}, expected one of {FLOW_FORMATS}")
+    writer = _mermaid if fmt == "mermaid" else _dot
+    return "\n".join(writer(flows)) + "\n"
+
+
+def _mermaid(flows: FlowMap) -> list[str]:
+    lines = ["flowchart LR"]
+    for c in _drawn(flows):
+        lines.append(f'    {c.id}["{_escape(c.name)}"]')
+    for e in flows.edges():
+        label = "<br/>".join([_edge_title(e)] + [_escape(n) for n, _ in e.busiest()])
+        lines.append(f'    {e.source} -->|"{label}"| {e.target}')
+    return lines
+
+
+def _dot(flows: FlowMap) -> list[str]:
+    lines = [
+        f'digraph "{_escape(flows.system)}" {{',
+        "    rankdir=LR;",
+        "    node [shape=box];",
+    ]
+    for c in _drawn(flows):
+        lines.append(f'    {c.id} [label="{_escape(c.name)}"];')
+    for e in flows.edges():
+        label = "\\n".join([_edge_title(e)] + [_escape(n) for n, _ in e.busiest()])
+        lines.append(f'    {e.source} -> {e.target} [label="{label}"];')
+    lines.append("}")
+    return lines
+
+
+def _drawn(flows: FlowMap) -> list[Container]:
+    """Services that take part in a flow or declare endpoints."""
+    used = {c.container for c in flows.calls} | {e.container for e in flows.endpoints}
+    return [c for c in flows.containers if c.id in used]
+
+
+def _edge_title(edge: FlowEdge) -> str:
+    kind = "HTTP" if edge.protocol == "http" else "gRPC"
+    return f"{edge.calls} {kind} call{'s' if edge.calls != 1 else ''}"
+
+
+def _escape(text: str) -> str:
+    return text.replace('"', "'")
+
+
+# ── Helpers ────────────────────────────────────────────────────────
+
+
+def _segments(path: str) -> tuple[str, ...]:
+    """Path segments, parameters in any syntax as "*"."""
+    path = path.split("?")[0].split("#")[0]
+    return tuple("*" if _PARAM_RE.match(s) or "{" in s else s for s in path.split("/") if s)
+
+
+def _join(prefix: str, route: str) -> str:
+    if not prefix:
+        return route
+    return prefix + ("/" + route.lstrip("/") if route.strip("/") else "")
+
+
+def _methods_in(text: str) -> list[str]:
+    """HTTP methods named in *text*: methods=["GET", "POST"], RequestMethod.PUT."""
+    return [w.upper() for w in re.findall(r"\w+", text) if w.upper() in _METHODS]
+
+
+def _http_literals(content: str):
+    """(line, offset, host, path) of every path literal, hosts of base URLs resolved."""
+    bases = {m[1]: m[2] for m in _BASE_URL_RE.finditer(content)}
+    for m in _CALL_RE.finditer(content):
+        host = m[1] or bases.get(m[2] or "", "")
+        if not m[1] and not m[2]:
+            line_start = content.rfind("\n", 0, m.start()) + 1
+            concat = _CONCAT_RE.search(content, line_start, m.start())
+            host = bases.get(concat[1], "") if concat else ""
+        yield _line(content, m.start()), m.start(), host, m[3]
+
+
+def _call_method(content: str, offset: int) -> Optional[str]:
+    """The HTTP method named on the line before *offset*, nearest first."""
+    start = content.rfind("\n", 0, offset) + 1
+    names = _CALL_METHOD_RE.findall(content[start:offset])
+    return names[-1].upper() if names else None
+
+
+def _grpc_calls(
+    content: str, servers: dict[str, Endpoint], rpcs: dict[str, list[str]], caller: str
+):
+    """(line, endpoint) of every RPC called on a stub of another service's gRPC server."""
+    clients = set()
+    for pattern in _GRPC_CLIENT_RES:
+        for m in pattern.finditer(content):
+            server = servers.get(m[1])
+            if server is not None and server.container != caller:
+                clients.add((m[1], _line(content, m.start())))
+    for service, line in sorted(clients):
+        names = rpcs.get(service, [])
+        called = [
+            (_line(content, m.start()), name)
+            for name in names
+            # Python and Go call RPCs as declared, Java stubs in lowerCamelCase
+            for m in re.finditer(rf"\.(?:{name}|{name[:1].lower()}{name[1:]})\(", content)
+        ]
+        if not called:
+            called = [(line, "*")]
+        for call_line, name in called:
+            yield call_line, _grpc_endpoint(servers[service], name)
+
+
+def _grpc_endpoint(server: Endpoint, rpc: str) -> Endpoint:
+    return Endpoint(server.container, "grpc", rpc, server.path, server.file, server.line)
+
+
+def _line(content: str, offset: int) -> int:
+    return content.count("\n", 0, offset) + 1
+
+
+def _is_test(path: str) -> bool:
+    return any(pattern.search(path.lower()) for pattern in TEST_PATH_PATTERNS)
@@ -0,0 +1,174 @@
+"""Ports-and-adapters (hexagonal architecture) conformance.
+
+The ``hexagonal`` config table declares which files form each ring of the
+hexagon, as path patterns:
+
+    [hexagonal]
+    domain = ["internal/domain"]
+    ports = ["internal/ports", "app/*/ports"]
+    adapters = ["internal/adapters", "cmd"]
+
+A pattern matches the file or directory it names and everything below
+it; ``*``, ``?`` and ``[...]`` are wildcards, and ``*`` crosses
+directories. A file in several rings belongs to the first of domain,
+ports, adapters; files in none are ``other``. Each ring may import only
+the rings it is allowed to:
+
+    domain     domain                 the core depends on nothing else
+    ports      domain, ports
+    adapters   ports, adapters        adapters only touch the core via ports
+
+``[hexagonal.allow]`` replaces a ring's list (``adapters = ["ports",
+"adapters", "domain"]`` to let adapters map domain types); ``other`` is
+allowed by name. Files outside the rings are unconstrained. Every import
+edge a rule forbids is a ``hexagonal_violation`` finding.
+"""
+
+from __future__ import annotations
+
+import fnmatch
+from dataclasses import dataclass, field
+from typing import Any, Optional
+
+HEXAGONAL_VIOLATION_TYPE = "hexagonal_violation"
+
+RINGS = ("domain", "ports", "adapters")
+OTHER = "other"
+
+DEFAULT_ALLOW: dict[str, frozenset[str]] = {
+    "domain": frozenset({"domain"}),
+    "ports": frozenset({"domain", "ports"}),
+    "adapters": frozenset({"ports", "adapters"}),
+}
+
+# Severity by the ring that imports: a leaking core matters most
+_SEVERITY = {"domain": 0.8, "ports": 0.65, "adapters": 0.5}
+
+_SUGGESTIONS = {
+    "domain": "Put the dependency behind a port the domain owns, or move the code into the domain.",
+    "ports": "Ports describe what the core needs; keep adapter and framework types out of them.",
+    "adapters": "Reach the core through its ports, or widen hexagonal.allow if this is intended.",
+}
+
+
+@dataclass
+class HexagonalSpec:
+    """The rings' path patterns and which rings each may import."""
+
+    patterns: dict[str, list[str]]
+    allow: dict[str, frozenset[str]] = field(default_factory=lambda: dict(DEFAULT_ALLOW))
+
+    def ring_of(self, path: str) -> str:
+        """The ring *path* belongs to, ``other`` if none."""
+        for ring in RINGS:
+            if any(_matches(path, pattern) for pattern in self.patterns.get(ring, [])):
+                return ring
+        return OTHER
+
+
+@dataclass
+class HexagonalViolation:
+    """An import edge from *source* to *target* that the rules forbid."""
+
+    source: str
+    target: str
+    source_ring: str
+    target_ring: str
+
+    @property
+    def rule(self) -> str:
+        return f"{self.source_ring} -> {self.target_ring}"
+
+
+@dataclass
+class HexagonalReport:
+    """Ring of every file in a ring, and the forbidden edges."""
+
+    rings: dict[str, str]
+    violations: list[HexagonalViolation]
+
+    def counts(self) -> dict[str, int]:
+        """Files per ring."""
+        return {ring: sum(1 for r in self.rings.values() if r == ring) for ring in RINGS}
+
+
+def resolve_spec(table: Optional[dict[str, Any]]) -> Optional[HexagonalSpec]:
+    """The spec the ``hexagonal`` config table declares, validated; None when empty."""
+    table = table or {}
+    if not isinstance(table, dict):
+        raise ValueError("hexagonal must be a table")
+    unknown = sorted(set(table) - {*RINGS, "allow"})
+    if unknown:
+        raise ValueError(f"hexagonal: unknown keys {unknown}; expected {[*RINGS, 'allow']}")
+    patterns: dict[str, list[str]] = {}
+    for ring in RINGS:
+        values = table.get(ring, [])
+        if not isinstance(values, list) or not all(isinstance(v, str) and v for v in values):
+            raise ValueError(f"hexagonal.{ring} must be a list of path patterns")
+        patterns[ring] = [v.strip("/") for v in values]
+    if not any(patterns.values()):
+        if "allow" in table:
+            raise ValueError("hexagonal.allow needs at least one of domain, ports, adapters")
+        return None
+
+    allow = dict(DEFAULT_ALLOW)
+    overrides = table.get("allow", {})
+    if not isinstance(overrides, dict):
+        raise ValueError("hexagonal.allow must be a table")
+    for ring, targets in overrides.items():
+        if ring not in RINGS:
+            raise ValueError(f"hexagonal.allow: unknown ring {ring!r}; expected {list(RINGS)}")
+        valid = {*RINGS, OTHER}
+        if not isinstance(targets, list) or not all(t in valid for t in targets):
+            raise ValueError(f"hexagonal.allow.{ring} must list rings from {sorted(valid)}")
+        allow[ring] = frozenset(targets)
+    return HexagonalSpec(patterns, allow)
+
+
+def check_hexagonal(spec: HexagonalSpec, adjacency: dict[str, list[str]]) -> HexagonalReport:
+    """Classify the files of the import graph and find the edges *spec* forbids.
+
+    Args:
+        spec: Rings and allowed imports
+        adjacency: adjacency[A] contains B when A imports B
+    """
+    nodes = set(adjacency) | {t for targets in adjacency.values() for t in targets}
+    rings = {path: spec.ring_of(path) for path in sorted(nodes)}
+    violations = []
+    for source in sorted(adjacency):
+        source_ring = rings[source]
+        if source_ring == OTHER:
+            continue
+        for target in sorted(set(adjacency[source])):
+            if target != source and rings[target] not in spec.allow[source_ring]:
+                violations.append(HexagonalViolation(source, target, source_ring, rings[target]))
+    return HexagonalReport({p: ring for p, ring in rings.items() if ring != OTHER}, violations)
+
+
+def to_findings(violations: list[HexagonalViolation]) -> list:
+    """Convert violations to ``hexagonal_violation`` findings, one per edge."""
+    from ..insights.models import Evidence, Finding
+
+    return [
+        Finding(
+            finding_type=HEXAGONAL_VIOLATION_TYPE,
+            severity=_SEVERITY[v.source_ring],
+            title=f"{v.source_ring.capitalize()} file {v.source} imports {v.target_ring} "
+            f"file {v.target}",
+            files=[v.source, v.target],
+            evidence=[
+                Evidence(signal="rule", value=0.0, percentile=0.0, description=v.rule),
+            ],
+            suggestion=_SUGGESTIONS[v.source_ring],
+            effort="MEDIUM",
+            scope="FILE_PAIR",
+            identity_hint=v.target,
+        )
+        for v in violations
+    ]
+
+
+def _matches(path: str, pattern: str) -> bool:
+    if any(c in pattern for c in "*?["):
+        return fnmatch.fnmatchcase(path, pattern) or fnmatch.fnmatchcase(path, pattern + "/*")
+    return path == pattern or path.startswith(pattern + "/")
@@ -5,12 +5,16 @@ Detects module boundaries from directory structure. The algorithm:
 2. Determine granularity: choose depth where most directories have 3-15 files
 3. Assign files to modules at the chosen depth
 4. Fallback for flat projects: use Louvain communities as synthetic modules
+
+A multi-project sbt build (two or more sub-projects in build.sbt) declares
+its modules, so each sub-project's directory is a module instead.
 """
 
 from collections import Counter, defaultdict
 from pathlib import Path
 from typing import Optional
 
+from ..graph.sbt import SbtProject, discover_sbt_projects, project_of
 from .models import Module
 
 
@@ -107,27 +111,14 @@ def detect_modules(
     if not file_paths:
         return {}
 
-    # Auto-detect depth if not specified
-    if module_depth is None:
-        module_depth = determine_module_depth(file_paths)
-
-    # Group files by module
-    module_files: dict[str, list[str]] = defaultdict(list)
-
-    for file_path in file_paths:
-        parts = Path(file_path).parts[:-1]  # Directory parts
-
-        if module_depth == 0 or len(parts) == 0:
-            # Flat project: all files in root module
-            module_path = "."
-        elif len(parts) < module_depth:
-            # File is above module depth - use its full directory path
-            module_path = str(Path(*parts))
-        else:
-            # File is at or below module depth - use first N parts
-            module_path = str(Path(*parts[:module_depth]))
-
-        module_files[module_path].append(file_path)
+    sbt_projects = discover_sbt_projects(root_dir) if root_dir and module_depth is None else []
+    if len(sbt_projects) >= 2:
+        module_files = _group_by_sbt_project(file_paths, sbt_projects)
+    else:
+        # Auto-detect depth if not specified
+        if module_depth is None:
+            module_depth = determine_module_depth(file_paths)
+        module_files = _group_by_depth(file_paths, module_depth)
 
     # Create Module objects
     modules: dict[str, Module] = {}
@@ -150,6 +141,39 @@ def detect_modules(
     return modules
 
 
+def _group_by_depth(file_paths: list[str], module_depth: int) -> dict[str, list[str]]:
+    """Group files by their directory path truncated to *module_depth* parts."""
+    module_files: dict[str, list[str]] = defaultdict(list)
+
+    for file_path in file_paths:
+        parts = Path(file_path).parts[:-1]  # Directory parts
+
+        if module_depth == 0 or len(parts) == 0:
+            # Flat project: all files in root module
+            module_path = "."
+        elif len(parts) < module_depth:
+            # File is above module depth - use its full directory path
+            module_path = str(Path(*parts))
+        else:
+            # File is at or below module depth - use first N parts
+            module_path = str(Path(*parts[:module_depth]))
+
+        module_files[module_path].append(file_path)
+
+    return module_files
+
+
+def _group_by_sbt_project(
+    file_paths: list[str], projects: list[SbtProject]
+) -> dict[str, list[str]]:
+    """Group files by the sbt sub-project directory holding them ("." for the rest)."""
+    module_files: dict[str, list[str]] = defaultdict(list)
+    for file_path in file_paths:
+        project = project_of(file_path, projects)
+        module_files[project.directory if project else "."].append(file_path)
+    return module_files
+
+
 def _create_community_modules(
     file_paths: list[str],
     communities: dict[str, int],
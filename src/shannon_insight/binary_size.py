@@ -0,0 +1,503 @@
+"""Go binary size attributed to packages, and set against their source.
+
+Symbol sizes come from the binary's symbol table: ELF binaries are read
+directly, other formats (Mach-O, PE) through ``go tool nm -size``. BSS
+symbols occupy no space in the file and are left out. Each symbol belongs
+to the package its name starts with (``example.com/shop/store.(*DB).Get``);
+compiler-generated tables (``go:func.*``, ``type:.eq.*``) belong to no
+package and are counted as runtime tables. The main package path and the
+dependency modules come from the build info Go embeds in every binary.
+
+Packages under a module declared by a go.mod in the repository are local
+and are matched to their directory; the others are grouped by dependency
+module, or as the standard library. A local package is bloated when it
+holds BLOAT_MIN_BYTES or more and its share of the local binary size is
+BLOAT_RATIO times its share of the local source lines, the usual causes
+being embedded assets, generated tables and generic instantiations.
+"""
+
+from __future__ import annotations
+
+import shutil
+import struct
+import subprocess
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import Optional
+from urllib.parse import unquote
+
+from .diligence.inventory import tracked_files
+from .hygiene.sources import SourceSet
+
+BLOAT_MIN_BYTES = 32 * 1024
+BLOAT_RATIO = 4.0
+
+STANDARD_LIBRARY = "(standard library)"
+RUNTIME_TABLES = "(runtime tables)"
+
+_ELF_MAGIC = b"\x7fELF"
+_SHT_SYMTAB = 2
+_SHT_NOBITS = 8
+_SHF_EXECINSTR = 0x4
+_SHN_LORESERVE = 0xFF00
+_BUILDINFO_MAGIC = b"\xff Go buildinf:"
+_NM_TIMEOUT_SECONDS = 120
+_BUILD_TIMEOUT_SECONDS = 600
+
+
+class BinaryError(Exception):
+    """The binary cannot be read or built."""
+
+
+@dataclass(frozen=True)
+class Symbol:
+    name: str
+    size: int
+    text: bool  # code, as opposed to read-only or initialised data
+
+
+@dataclass
+class GoBinary:
+    """Symbols and build info of a compiled Go binary.
+
+    Attributes:
+        path: The binary
+        file_size: Bytes on disk
+        symbols: Sized symbols that occupy space in the file
+        main_path: Import path of the main package ("" without build info)
+        modules: Main and dependency module paths from the build info
+    """
+
+    path: Path
+    file_size: int
+    symbols: list[Symbol] = field(default_factory=list)
+    main_path: str = ""
+    modules: list[str] = field(default_factory=list)
+
+
+@dataclass
+class PackageSize:
+    """Binary bytes of one package, and its source when it is local."""
+
+    package: str  # import path
+    text_bytes: int = 0
+    data_bytes: int = 0
+    directory: Optional[str] = None  # repo directory of a local package
+    lines: int = 0  # non-blank source lines, tests excluded
+    functions: int = 0
+    largest_symbol: str = ""
+    largest_symbol_bytes: int = 0
+    generic_bytes: int = 0  # bytes of generic instantiations (``F[go.shape.int]``)
+    ratio: float = 0.0  # share of local bytes / share of local lines
+    causes: list[str] = field(default_factory=list)
+
+    @property
+    def bytes(self) -> int:
+        return self.text_bytes + self.data_bytes
+
+    @property
+    def bytes_per_line(self) -> float:
+        return self.bytes / self.lines if self.lines else 0.0
+
+    @property
+    def bloated(self) -> bool:
+        return self.bytes >= BLOAT_MIN_BYTES and self.ratio >= BLOAT_RATIO
+
+    def to_dict(self) -> dict:
+        return {
+            "package": self.package,
+            "directory": self.directory,
+            "bytes": self.bytes,
+            "text_bytes": self.text_bytes,
+            "data_bytes": self.data_bytes,
+            "lines": self.lines,
+            "functions": self.functions,
+            "bytes_per_line": round(self.bytes_per_line, 1),
+            "ratio": round(self.ratio, 2),
+            "bloated": self.bloated,
+            "largest_symbol": self.largest_symbol,
+            "largest_symbol_bytes": self.largest_symbol_bytes,
+            "causes": self.causes,
+        }
+
+
+@dataclass
+class BinarySizeReport:
+    """A binary's size by local package and by dependency."""
+
+    binary: str
+    file_size: int
+    main_path: str
+    local: list[PackageSize] = field(default_factory=list)  # largest first
+    external: dict[str, int] = field(default_factory=dict)  # module -> bytes, largest first
+
+    @property
+    def symbol_bytes(self) -> int:
+        return sum(p.bytes for p in self.local) + sum(self.external.values())
+
+    @property
+    def local_bytes(self) -> int:
+        return sum(p.bytes for p in self.local)
+
+    def bloated(self) -> list[PackageSize]:
+        """Bloated local packages, most disproportionate first."""
+        return sorted(
+            (p for p in self.local if p.bloated), key=lambda p: (-p.ratio, p.package)
+        )
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "binary": self.binary,
+            "file_size": self.file_size,
+            "symbol_bytes": self.symbol_bytes,
+            "local_bytes": self.local_bytes,
+            "main_path": self.main_path,
+            "bloated": [p.to_dict() for p in self.bloated()],
+            "local": [p.to_dict() for p in self.local[:top]],
+            "external": [
+                {"module": module, "bytes": size}
+                for module, size in list(self.external.items())[:top]
+            ],
+        }
+
+
+# ── Reading binaries ───────────────────────────────────────────────
+
+
+def read_binary(path: Path) -> GoBinary:
+    """Read the symbols and build info of the Go binary at *path*.
+
+    Raises:
+        BinaryError: Unreadable file, no symbol table, or a non-ELF binary
+            without a Go toolchain to read it
+    """
+    try:
+        data = path.read_bytes()
+    except OSError as e:
+        raise BinaryError(f"cannot read {path}: {e}") from e
+    if data.startswith(_ELF_MAGIC):
+        binary = _read_elf(path, data)
+    else:
+        binary = _read_with_go_tool(path, len(data))
+    if not binary.symbols:
+        raise BinaryError(f"{path} has no symbol table (built with -ldflags=-s?)")
+    return binary
+
+
+def build_binary(root: Path, package: str, output_dir: Path) -> Path:
+    """``go build`` *package* (``./cmd/server``) under *root* into *output_dir*."""
+    go = shutil.which("go")
+    if go is None:
+        raise BinaryError("go is not on PATH; pass a compiled binary instead")
+    output = output_dir / (PurePosixPath(package).name or "main")
+    try:
+        result = subprocess.run(
+            [go, "build", "-o", str(output), package],
+            cwd=root,
+            capture_output=True,
+            text=True,
+            timeout=_BUILD_TIMEOUT_SECONDS,
+        )
+    except (OSError, subprocess.TimeoutExpired) as e:
+        raise BinaryError(f"go build {package} failed: {e}") from e
+    if result.returncode != 0:
+        raise BinaryError(f"go build {package} failed:\n{result.stderr.strip()}")
+    return output
+
+
+def _read_elf(path: Path, data: bytes) -> GoBinary:
+    if len(data) < 64 or data[4] not in (1, 2) or data[5] not in (1, 2):
+        raise BinaryError(f"{path} is not a valid ELF file")
+    wide = data[4] == 2
+    end = "<" if data[5] == 1 else ">"
+    try:
+        if wide:
+            (shoff,) = struct.unpack_from(end + "Q", data, 0x28)
+            shentsize, shnum, shstrndx = struct.unpack_from(end + "HHH", data, 0x3A)
+            header = end + "IIQQQQIIQQ"
+        else:
+            (shoff,) = struct.unpack_from(end + "I", data, 0x20)
+            shentsize, shnum, shstrndx = struct.unpack_from(end + "HHH", data, 0x2E)
+            header = end + "IIIIIIIIII"
+        sections = [
+            struct.unpack_from(header, data, shoff + i * shentsize) for i in range(shnum)
+        ]
+    except struct.error as e:
+        raise BinaryError(f"{path} has a truncated ELF header: {e}") from e
+
+    # (name offset, type, flags, addr, offset, size, link, info, align, entsize)
+    names_at = sections[shstrndx][4] if shstrndx < len(sections) else 0
+
+    def name_of(section: tuple) -> str:
+        return _c_string(data, names_at + section[0])
+
+    binary = GoBinary(path, len(data))
+    for section in sections:
+        if name_of(section) == ".go.buildinfo":
+            _parse_buildinfo(binary, data[section[4] : section[4] + section[5]])
+    for section in sections:
+        if section[1] != _SHT_SYMTAB:
+            continue
+        strings_at = sections[section[6]][4]
+        entry = end + ("IBBHQQ" if wide else "IIIBBH")
+        table = data[section[4] : section[4] + section[5]]
+        table = table[: len(table) - len(table) % struct.calcsize(entry)]
+        for fields in struct.iter_unpack(entry, table):
+            if wide:
+                name_at, _, _, index, _, size = fields
+            else:
+                name_at, _, size, _, _, index = fields
+            if not size or index == 0 or index >= _SHN_LORESERVE or index >= len(sections):
+                continue
+            target = sections[index]
+            if target[1] == _SHT_NOBITS:
+                continue
+            text = bool(target[2] & _SHF_EXECINSTR)
+            binary.symbols.append(Symbol(_c_string(data, strings_at + name_at), size, text))
+    return binary
+
+
+def _c_string(data: bytes, start: int) -> str:
+    end = data.find(b"\0", start)
+    return data[start : end if end >= 0 else len(data)].decode("utf-8", errors="replace")
+
+
+def _parse_buildinfo(binary: GoBinary, section: bytes) -> None:
+    """Main package and modules from a Go 1.18+ ``.go.buildinfo`` section."""
+    if not section.startswith(_BUILDINFO_MAGIC) or len(section) < 32:
+        return
+    if not section[15] & 0x2:  # older binaries point into the data segment instead
+        return
+    strings = []
+    position = 32
+    for _ in range(2):  # Go version, then module info
+        length, shift = 0, 0
+        while position < len(section):
+            byte = section[position]
+            position += 1
+            length |= (byte & 0x7F) << shift
+            shift += 7
+            if not byte & 0x80:
+                break
+        strings.append(section[position : position + length])
+        position += length
+    modinfo = strings[1]
+    # Module info is wrapped in 16-byte sentinels
+    if len(modinfo) >= 33 and modinfo[-17:-16] == b"\n":
+        modinfo = modinfo[16:-16]
+    _parse_modinfo(binary, modinfo.decode("utf-8", errors="replace"))
+
+
+def _parse_modinfo(binary: GoBinary, modinfo: str) -> None:
+    """The ``path``, ``mod`` and ``dep`` lines ``go version -m`` prints."""
+    for line in modinfo.splitlines():
+        parts = line.strip().split("\t")
+        if len(parts) < 2:
+            continue
+        if parts[0] == "path":
+            binary.main_path = parts[1]
+        elif parts[0] in ("mod", "dep"):
+            binary.modules.append(parts[1])
+
+
+def _read_with_go_tool(path: Path, file_size: int) -> GoBinary:
+    go = shutil.which("go")
+    if go is None:
+        raise BinaryError(f"{path} is not an ELF binary and go is not on PATH to read it")
+    try:
+        nm = subprocess.run(
+            [go, "tool", "nm", "-size", str(path)],
+            capture_output=True,
+            text=True,
+            timeout=_NM_TIMEOUT_SECONDS,
+        )
+        version = subprocess.run(
+            [go, "version", "-m", str(path)],
+            capture_output=True,
+            text=True,
+            timeout=_NM_TIMEOUT_SECONDS,
+        )
+    except (OSError, subprocess.TimeoutExpired) as e:
+        raise BinaryError(f"go tool nm {path} failed: {e}") from e
+    if nm.returncode != 0:
+        raise BinaryError(f"go tool nm {path} failed: {nm.stderr.strip()}")
+
+    binary = GoBinary(path, file_size)
+    for line in nm.stdout.splitlines():
+        # address size type name (names may contain spaces)
+        parts = line.split(None, 3)
+        if len(parts) < 4 or not parts[1].isdigit() or parts[2] not in "TtRrDd":
+            continue
+        if int(parts[1]):
+            binary.symbols.append(Symbol(parts[3], int(parts[1]), parts[2] in "Tt"))
+    if version.returncode == 0:
+        _parse_modinfo(binary, version.stdout)
+    return binary
+
+
+# ── Attribution ────────────────────────────────────────────────────
+
+
+def package_of_symbol(name: str) -> Optional[str]:
+    """Import path a symbol belongs to; None for compiler-generated tables."""
+    if name.startswith("go:"):
+        return None
+    if name.startswith("type:"):
+        name = name[len("type:") :].lstrip("*")
+        if name.startswith("."):  # type:.eq.M16, type:.namedata.*
+            return None
+    slash = _last_slash(name)
+    dot = name.find(".", slash + 1)
+    if dot <= 0:
+        return None
+    package = unquote(name[:dot])
+    return package[len("vendor/") :] if package.startswith("vendor/") else package
+
+
+def short_symbol(name: str) -> str:
+    """*name* with its package's import path cut to the last element."""
+    prefix = ""
+    if name.startswith("type:"):
+        stripped = name[len("type:") :].lstrip("*")
+        prefix, name = name[: len(name) - len(stripped)], stripped
+    return prefix + name[_last_slash(name) + 1 :]
+
+
+def _last_slash(name: str) -> int:
+    """Index of the last "/" of the import path a symbol name starts with."""
+    head = name
+    for stop in "([":
+        if stop in head:
+            head = head[: head.index(stop)]
+    return head.rfind("/")
+
+
+def local_modules(root: Path, paths: Optional[list[str]] = None) -> dict[str, str]:
+    """Module path -> directory (relative to *root*) of every go.mod in the repo."""
+    paths = tracked_files(root) if paths is None else paths
+    modules = {}
+    for path in paths:
+        if PurePosixPath(path).name != "go.mod":
+            continue
+        try:
+            text = (root / path).read_text(encoding="utf-8", errors="replace")
+        except OSError:
+            continue
+        for line in text.splitlines():
+            parts = line.split("//")[0].split()
+            if len(parts) == 2 and parts[0] == "module":
+                modules[parts[1].strip('"')] = PurePosixPath(path).parent.as_posix()
+                break
+    return modules
+
+
+def analyze_binary(
+    binary: GoBinary,
+    sources: SourceSet,
+    modules: Optional[dict[str, str]] = None,
+) -> BinarySizeReport:
+    """Attribute *binary*'s symbols to packages and compare local ones with their source.
+
+    Args:
+        binary: The binary read by read_binary
+        sources: Parsed sources of the repository it was built from
+        modules: Local module path -> directory (default: local_modules)
+    """
+    modules = local_modules(sources.root) if modules is None else modules
+    packages: dict[str, PackageSize] = {}
+    external: dict[str, int] = {}
+    for symbol in binary.symbols:
+        package = package_of_symbol(symbol.name)
+        if package == "main" and binary.main_path:
+            package = binary.main_path
+        directory = _directory(package, modules) if package else None
+        if package is None or directory is None:
+            group = _external_group(package, binary.modules)
+            external[group] = external.get(group, 0) + symbol.size
+            continue
+        entry = packages.setdefault(package, PackageSize(package, directory=directory))
+        if symbol.text:
+            entry.text_bytes += symbol.size
+        else:
+            entry.data_bytes += symbol.size
+        if "[" in symbol.name:
+            entry.generic_bytes += symbol.size
+        if symbol.size > entry.largest_symbol_bytes:
+            entry.largest_symbol = short_symbol(symbol.name)
+            entry.largest_symbol_bytes = symbol.size
+
+    _measure_source(packages, sources)
+    local_bytes = sum(p.bytes for p in packages.values())
+    local_lines = sum(p.lines for p in packages.values())
+    for entry in packages.values():
+        if local_bytes and local_lines:
+            line_share = max(entry.lines, 1) / local_lines
+            entry.ratio = (entry.bytes / local_bytes) / line_share
+        if entry.bloated:
+            entry.causes = _causes(entry, sources)
+
+    return BinarySizeReport(
+        binary=str(binary.path),
+        file_size=binary.file_size,
+        main_path=binary.main_path,
+        local=sorted(packages.values(), key=lambda p: (-p.bytes, p.package)),
+        external=dict(sorted(external.items(), key=lambda kv: (-kv[1], kv[0]))),
+    )
+
+
+def _directory(package: str, modules: dict[str, str]) -> Optional[str]:
+    """Repo directory of a package under a local module, None if not local."""
+    best = None
+    for module in modules:
+        if package == module or package.startswith(module + "/"):
+            if best is None or len(module) > len(best):
+                best = module
+    if best is None:
+        return None
+    relative = PurePosixPath(modules[best]) / package[len(best) :].lstrip("/")
+    return relative.as_posix()
+
+
+def _external_group(package: Optional[str], modules: list[str]) -> str:
+    if package is None:
+        return RUNTIME_TABLES
+    owners = [m for m in modules if package == m or package.startswith(m + "/")]
+    if owners:
+        return max(owners, key=len)
+    first = package.split("/", 1)[0]
+    return package if "." in first else STANDARD_LIBRARY
+
+
+def _measure_source(packages: dict[str, PackageSize], sources: SourceSet) -> None:
+    by_directory = {p.directory: p for p in packages.values()}
+    for path, content in sources.content.items():
+        if not path.endswith(".go") or path.endswith("_test.go"):
+            continue
+        entry = by_directory.get(SourceSet.package_of(path))
+        if entry is None:
+            continue
+        entry.lines += sum(1 for line in content.splitlines() if line.strip())
+        syntax = sources.syntax.get(path)
+        if syntax is not None:
+            entry.functions += len(syntax.functions)
+
+
+def _causes(entry: PackageSize, sources: SourceSet) -> list[str]:
+    """Likely reasons a package is large for its source."""
+    causes = []
+    files = [
+        content
+        for path, content in sources.content.items()
+        if path.endswith(".go") and SourceSet.package_of(path) == entry.directory
+    ]
+    if any("//go:embed" in content for content in files):
+        causes.append("embedded files")
+    if any("Code generated" in content[:2000] for content in files):
+        causes.append("generated code")
+    if entry.largest_symbol_bytes * 2 >= entry.bytes:
+        causes.append(f"large symbol {entry.largest_symbol}")
+    if entry.generic_bytes * 2 >= entry.bytes:
+        causes.append("generic instantiations")
+    if not files:
+        causes.append("no source in the repository")
+    return causes
@@ -1,7 +1,9 @@
 """
 Caching system for Shannon Insight.
 
-Uses diskcache for SQLite-based persistent caching.
+Uses diskcache for SQLite-based persistent caching. Keys are built from
+root-relative paths and line-ending-normalized content (see portable.py), so a
+cache directory shared between macOS, Linux and Windows machines hits on all.
 """
 
 import hashlib
@@ -13,6 +15,7 @@ from typing import Any, Callable, Optional
 from diskcache import Cache
 
 from .logging_config import get_logger
+from .portable import file_digest, portable_path
 
 logger = get_logger(__name__)
 
@@ -29,7 +32,11 @@ class AnalysisCache:
     """
 
     def __init__(
-        self, cache_dir: str = ".shannon-cache", ttl_hours: int = 24, enabled: bool = True
+        self,
+        cache_dir: str = ".shannon-cache",
+        ttl_hours: int = 24,
+        enabled: bool = True,
+        root_dir: Optional[Path] = None,
     ):
         """
         Initialize cache.
@@ -38,8 +45,10 @@ class AnalysisCache:
             cache_dir: Directory for cache storage
             ttl_hours: Time-to-live in hours
             enabled: Whether caching is enabled
+            root_dir: Project root; file keys use paths relative to it
         """
         self.enabled = enabled
+        self.root_dir = root_dir
         self.ttl_seconds = ttl_hours * 3600
 
         if self.enabled:
@@ -51,14 +60,16 @@ class AnalysisCache:
 
     def _get_file_key(self, filepath: Path, config_hash: str) -> str:
         """
-        Generate cache key from file metadata and configuration.
+        Generate a portable cache key from file content and configuration.
 
         The key is based on:
-        - File path
-        - File modification time
-        - File size
+        - Root-relative path with forward slashes
+        - Content digest with normalized line endings
         - Configuration hash
 
+        Modification times and absolute paths are left out: they differ
+        between checkouts, which would keep shared caches from ever hitting.
+
         Args:
             filepath: File path
             config_hash: Hash of configuration settings
@@ -66,14 +77,14 @@ class AnalysisCache:
         Returns:
             Cache key string
         """
+        path = portable_path(filepath, self.root_dir)
         try:
-            stat = filepath.stat()
-            key_data = f"{filepath}:{stat.st_mtime}:{stat.st_size}:{config_hash}"
-            return hashlib.sha256(key_data.encode()).hexdigest()
+            digest = file_digest(filepath)
         except OSError:
-            # If we can't stat the file, generate key from path only
-            key_data = f"{filepath}:{config_hash}"
-            return hashlib.sha256(key_data.encode()).hexdigest()
+            # If we can't read the file, generate key from path only
+            digest = ""
+        key_data = f"{path}:{digest}:{config_hash}"
+        return hashlib.sha256(key_data.encode()).hexdigest()
 
     def get(self, key: str) -> Optional[Any]:
         """
@@ -195,16 +206,48 @@ class AnalysisCache:
             self.cache.close()
 
 
+# Settings that never change analysis results (per-machine or per-run)
+_RESULT_NEUTRAL_KEYS = frozenset(
+    {
+        "workers",
+        "timeout_seconds",
+        "analyzer_workers",
+        "cache_enabled",
+        "cache_dir",
+        "cache_ttl_hours",
+        "verbosity",
+        "enable_provenance",
+        "provenance_retention_hours",
+        "gate_fast_budget_seconds",
+        "baseline_rotation",
+        "baseline_interval_minutes",
+        "baseline_branch",
+        "drain_timeout_seconds",
+        "job_queue",
+        "history_url",
+        "history_keep_all_days",
+        "history_keep_daily_days",
+        "history_keep_weekly_days",
+        "history_auto_prune",
+        "remote_mirror_dir",
+    }
+)
+
+
 def compute_config_hash(config: dict) -> str:
     """
     Compute hash of configuration for cache invalidation.
 
+    Per-machine settings (worker count, cache location, verbosity, ...) are
+    left out so that machines sharing a cache agree on the hash.
+
     Args:
         config: Configuration dictionary
 
     Returns:
         SHA256 hash of configuration
     """
+    relevant = {k: v for k, v in config.items() if k not in _RESULT_NEUTRAL_KEYS}
     # Sort keys for consistent hashing
-    config_str = json.dumps(config, sort_keys=True)
+    config_str = json.dumps(relevant, sort_keys=True)
     return hashlib.sha256(config_str.encode()).hexdigest()[:16]
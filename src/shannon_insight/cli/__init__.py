@@ -15,7 +15,39 @@ app: typer.Typer = typer.Typer(
 # Import subcommands to register them
 from ._explain import explain as _explain  # noqa: F401, E402
 from .analyze import main as _main_callback  # noqa: F401, E402
+from .annotate import annotate as _annotate  # noqa: F401, E402
+from .binsize import binsize as _binsize  # noqa: F401, E402
 from .build_history import build_history as _build_history  # noqa: F401, E402
+from .bundle import bundle_app  # noqa: E402
+from .c4 import c4 as _c4  # noqa: F401, E402
+from .centrality import centrality as _centrality  # noqa: F401, E402
+from .complexity_blame import complexity_blame as _complexity_blame  # noqa: F401, E402
+from .coupling import coupling as _coupling  # noqa: F401, E402
+from .coverage import coverage as _coverage  # noqa: F401, E402
+from .density import density as _density  # noqa: F401, E402
+from .duplication import duplication as _duplication  # noqa: F401, E402
+from .embedded import embedded as _embedded  # noqa: F401, E402
+from .flows import flows as _flows  # noqa: F401, E402
+from .functions import functions as _functions  # noqa: F401, E402
+from .gate import gate as _gate  # noqa: F401, E402
 from .health import health as _health  # noqa: F401, E402
-from .history import history as _history  # noqa: F401, E402
+from .hygiene import hygiene_app  # noqa: E402
+from .history import history_app  # noqa: E402
+from .meta import meta as _meta  # noqa: F401, E402
+from .mobile import mobile as _mobile  # noqa: F401, E402
+from .onboard import onboard as _onboard  # noqa: F401, E402
+from .pii import pii as _pii  # noqa: F401, E402
+from .proto import proto as _proto  # noqa: F401, E402
+from .recent import recent as _recent  # noqa: F401, E402
+from .rules import rules_app  # noqa: E402
 from .serve import serve as _serve  # noqa: F401, E402
+from .sql import sql as _sql  # noqa: F401, E402
+from .surface import surface as _surface  # noqa: F401, E402
+from .taint import taint as _taint  # noqa: F401, E402
+from .terraform import terraform as _terraform  # noqa: F401, E402
+from .triage import triage as _triage  # noqa: F401, E402
+
+app.add_typer(bundle_app, name="bundle")
+app.add_typer(history_app, name="history")
+app.add_typer(hygiene_app, name="hygiene")
+app.add_typer(rules_app, name="rules")
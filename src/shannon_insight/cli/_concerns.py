@@ -7,6 +7,8 @@ Organizes findings into human-understandable concerns (dimensions of health):
 4. STABILITY - Files that keep changing
 5. TEAM - Knowledge and collaboration risks
 6. BROKEN - Code that doesn't work properly
+7. SECURITY - Crypto policy violations and auth flow issues
+8. ERRORS - Errors that are dropped or can no longer be matched
 
 Each concern has:
 - A health metric (0-10)
@@ -38,7 +40,7 @@ class Concern:
     metric_keys: list[str]  # Global/composite signals for this concern
 
 
-# The 6 concerns (dimensions of health)
+# The 7 concerns (dimensions of health)
 CONCERNS = [
     Concern(
         key="complexity",
@@ -49,6 +51,21 @@ CONCERNS = [
             {
                 "god_file",
                 "high_risk_hub",
+                "load_bearing_function",
+                "complexity_outlier",
+                "untested_complexity",
+                "deep_nesting",
+                "god_class",
+                "low_cohesion",
+                "long_parameter_list",
+                "data_clump",
+                "long_procedure",
+                "nested_dynamic_block",
+                "variable_count_outlier",
+                "deep_yaml_nesting",
+                "values_file_outlier",
+                "oversized_resource",
+                "vocabulary_drift",
             }
         ),
         metric_keys=["avg_cognitive_load", "max_nesting", "avg_function_count"],
@@ -64,6 +81,13 @@ CONCERNS = [
                 "accidental_coupling",
                 "dead_dependency",
                 "copy_paste_clone",
+                "code_clone",
+                "orchestrator_function",
+                "duplicate_files",
+                "duplicate_yaml_block",
+                "duplicate_string_resource",
+                "repeated_literal",
+                "notebook_drift",
             }
         ),
         metric_keys=["wiring_score", "cycle_count", "coupling_density"],
@@ -79,6 +103,8 @@ CONCERNS = [
                 "zone_of_pain",
                 "boundary_mismatch",
                 "flat_architecture",
+                "hexagonal_violation",
+                "wide_api_surface",
             }
         ),
         metric_keys=["architecture_health", "modularity", "layer_violation_count"],
@@ -121,12 +147,30 @@ CONCERNS = [
             {
                 "phantom_imports",
                 "orphan_code",
+                "dead_code",
                 "hollow_code",
                 "incomplete_implementation",
+                "unused_resource",
             }
         ),
         metric_keys=["phantom_import_count", "orphan_count", "stub_ratio"],
     ),
+    Concern(
+        key="security",
+        name="Security",
+        icon="🔒",
+        description="Weak or misconfigured cryptography and token handling",
+        finding_types=frozenset({"crypto_policy_violation", "auth_flow_issue"}),
+        metric_keys=[],
+    ),
+    Concern(
+        key="errors",
+        name="Error Hygiene",
+        icon="🩹",
+        description="Errors that are dropped or can no longer be matched",
+        finding_types=frozenset({"error_hygiene_issue"}),
+        metric_keys=[],
+    ),
 ]
 
 # Build reverse mapping: finding_type -> concern
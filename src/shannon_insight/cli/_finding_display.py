@@ -60,6 +60,9 @@ def _extract_data_points(finding: Finding) -> list[tuple[str, str]]:
     """
     points = []
     for e in finding.evidence:
+        if e.signal == "reference":
+            continue  # example functions, shown separately by format_finding_detail
+
         # Format value with percentile if available
         if e.percentile and e.percentile > 0:
             pctl_str = f"({e.percentile:.0f}th pctl)"
@@ -122,6 +125,181 @@ FINDING_DISPLAY: dict = {
         "data_points": ["function_count", "cognitive_load", "lines"],
         "interpretation": "High function count and complexity. Multiple responsibilities likely.",
     },
+    "complexity_outlier": {
+        "label": "Unusually Complex Function",
+        "icon": "🌀",
+        "color": "magenta",
+        "data_points": ["complexity", "typical_complexity", "lines"],
+        "interpretation": "Far more decision points than functions of similar size in this repo.",
+    },
+    "untested_complexity": {
+        "label": "Complex Untested Function",
+        "icon": "🕳️",
+        "color": "red",
+        "data_points": ["complexity", "coverage"],
+        "interpretation": "Complex enough to break in many ways, and tests barely run it.",
+    },
+    "deep_nesting": {
+        "label": "Deeply Nested Function",
+        "icon": "🪆",
+        "color": "magenta",
+        "data_points": ["max_nesting", "avg_nesting"],
+        "interpretation": "Blocks stacked so deep a reader must hold every enclosing condition.",
+    },
+    "load_bearing_function": {
+        "label": "Load-Bearing Function",
+        "icon": "🏗️",
+        "color": "red",
+        "data_points": ["fan_in", "fan_out"],
+        "interpretation": "Called from so many places that any change to it ripples widely.",
+    },
+    "orchestrator_function": {
+        "label": "Orchestrator Function",
+        "icon": "🎼",
+        "color": "yellow",
+        "data_points": ["fan_out", "fan_in"],
+        "interpretation": "Coordinates so many functions that it changes whenever they do.",
+    },
+    "god_class": {
+        "label": "God Class",
+        "icon": "🏛️",
+        "color": "magenta",
+        "data_points": ["weighted_method_count", "methods", "extensions"],
+        "interpretation": "Many methods with high summed complexity. Too many responsibilities.",
+    },
+    "wide_api_surface": {
+        "label": "Wide API Surface",
+        "icon": "🚪",
+        "color": "yellow",
+        "data_points": ["api_surface", "api_surface_per_kloc"],
+        "interpretation": "Exports far more per line than other packages. Every name is a promise.",
+    },
+    "low_cohesion": {
+        "label": "Low Cohesion",
+        "icon": "🧩",
+        "color": "magenta",
+        "data_points": ["lcom4", "methods"],
+        "interpretation": "Methods fall into groups that share no fields. Several types in one.",
+    },
+    "long_parameter_list": {
+        "label": "Long Parameter List",
+        "icon": "🧾",
+        "color": "yellow",
+        "data_points": ["parameter_count"],
+        "interpretation": "Too many parameters to pass right. Often several jobs in one function.",
+    },
+    "data_clump": {
+        "label": "Data Clump",
+        "icon": "📦",
+        "color": "yellow",
+        "data_points": ["clump_size", "functions"],
+        "interpretation": "The same values passed together again and again. A missing struct.",
+    },
+    "long_procedure": {
+        "label": "Long Stored Procedure",
+        "icon": "📜",
+        "color": "magenta",
+        "data_points": ["lines", "statement_complexity"],
+        "interpretation": "Hundreds of lines of logic in the database, where it is hard to test.",
+    },
+    "crypto_policy_violation": {
+        "label": "Crypto Policy Violation",
+        "icon": "🔒",
+        "color": "red",
+        "data_points": ["rule", "occurrences"],
+        "interpretation": "Crypto the policy bans: weak algorithm, short key or unchecked TLS.",
+    },
+    "auth_flow_issue": {
+        "label": "Auth Flow Issue",
+        "icon": "🔑",
+        "color": "red",
+        "data_points": ["rule", "occurrences"],
+        "interpretation": "Token handling a review would reject: default secret, no expiry check.",
+    },
+    "error_hygiene_issue": {
+        "label": "Error Hygiene Issue",
+        "icon": "🩹",
+        "color": "yellow",
+        "data_points": ["rule", "occurrences"],
+        "interpretation": "An error dropped, matched by its message, or rewrapped without %w.",
+    },
+    "comment_debt": {
+        "label": "Comment Debt",
+        "icon": "📝",
+        "color": "dim",
+        "data_points": ["tag", "age_days", "owner"],
+        "interpretation": "A TODO/FIXME comment left in the code, with its age and owner.",
+    },
+    "nested_dynamic_block": {
+        "label": "Nested Dynamic Block",
+        "icon": "🪆",
+        "color": "magenta",
+        "data_points": ["dynamic_depth"],
+        "interpretation": "Dynamic blocks generating dynamic blocks. Hard to read back or plan.",
+    },
+    "variable_count_outlier": {
+        "label": "Variable Count Outlier",
+        "icon": "🎛️",
+        "color": "magenta",
+        "data_points": ["variables", "typical_variables", "resources"],
+        "interpretation": "Far more input variables than other modules. Likely several modules.",
+    },
+    "duplicate_yaml_block": {
+        "label": "Duplicate YAML Block",
+        "icon": "📑",
+        "color": "yellow",
+        "data_points": ["duplicate_lines", "copies"],
+        "interpretation": "The same configuration pasted in several places. Edit one, edit all.",
+    },
+    "deep_yaml_nesting": {
+        "label": "Deep YAML Nesting",
+        "icon": "🪜",
+        "color": "magenta",
+        "data_points": ["yaml_depth"],
+        "interpretation": "Configuration nested so deep that indentation mistakes go unnoticed.",
+    },
+    "values_file_outlier": {
+        "label": "Values File Outlier",
+        "icon": "📦",
+        "color": "magenta",
+        "data_points": ["values", "typical_values"],
+        "interpretation": "Far more settings than other YAML files. The chart exposes everything.",
+    },
+    "duplicate_string_resource": {
+        "label": "Duplicate String Resource",
+        "icon": "🔤",
+        "color": "yellow",
+        "data_points": ["names"],
+        "interpretation": "One text under several names. Each is translated, and they drift apart.",
+    },
+    "repeated_literal": {
+        "label": "Repeated Literal",
+        "icon": "🔁",
+        "color": "yellow",
+        "data_points": ["literal_uses"],
+        "interpretation": "One hard-coded value in many places. A change has to find them all.",
+    },
+    "notebook_drift": {
+        "label": "Notebook Drift",
+        "icon": "📓",
+        "color": "yellow",
+        "data_points": ["similarity_ratio", "changed_count"],
+        "interpretation": "A notebook and a module each keep a copy. Fixes reach only one.",
+    },
+    "unused_resource": {
+        "label": "Unused Resource",
+        "icon": "🗑️",
+        "color": "dim",
+        "data_points": ["declared", "variants", "dynamic_lookups"],
+        "interpretation": "Shipped in every build and translated, but nothing refers to it.",
+    },
+    "oversized_resource": {
+        "label": "Oversized Resource",
+        "icon": "🐘",
+        "color": "magenta",
+        "data_points": ["size", "scenes"],
+        "interpretation": "A heavy asset or crowded storyboard. Slows installs, builds or merges.",
+    },
     "orphan_code": {
         "label": "Orphan File",
         "icon": "🔌",
@@ -129,6 +307,13 @@ FINDING_DISPLAY: dict = {
         "data_points": ["in_degree", "is_orphan"],
         "interpretation": "No other files import this. Either standalone or unused.",
     },
+    "dead_code": {
+        "label": "Dead Code",
+        "icon": "🪦",
+        "color": "dim",
+        "data_points": ["dead_code_confidence", "function_lines"],
+        "interpretation": "No caller, import or mention of this function anywhere in the repo.",
+    },
     "hollow_code": {
         "label": "Stub-Heavy File",
         "icon": "🕳️",
@@ -157,6 +342,20 @@ FINDING_DISPLAY: dict = {
         "data_points": ["compression_ratio", "lines"],
         "interpretation": "Files with very similar content detected by compression analysis.",
     },
+    "code_clone": {
+        "label": "Duplicated Code",
+        "icon": "📋",
+        "color": "yellow",
+        "data_points": ["clone_tokens", "copies"],
+        "interpretation": "The same code, or the same up to names, in several places.",
+    },
+    "duplicate_files": {
+        "label": "Identical Copies",
+        "icon": "📑",
+        "color": "yellow",
+        "data_points": ["copies", "lines"],
+        "interpretation": "The same file exists at several paths. Only the first was analyzed.",
+    },
     # === Coupling Issues ===
     "hidden_coupling": {
         "label": "Co-Change Without Import",
@@ -194,6 +393,13 @@ FINDING_DISPLAY: dict = {
         "data_points": ["layer_violation_count", "depth"],
         "interpretation": "Dependency skips architectural layers (e.g., presentation directly imports data).",
     },
+    "hexagonal_violation": {
+        "label": "Hexagonal Architecture Violation",
+        "icon": "⬡",
+        "color": "red",
+        "data_points": ["rule"],
+        "interpretation": "An import the ports-and-adapters rules forbid, e.g. domain -> adapters.",
+    },
     "zone_of_pain": {
         "label": "Unstable Abstraction",
         "icon": "💢",
@@ -288,6 +494,13 @@ FINDING_DISPLAY: dict = {
         "data_points": ["naming_drift", "concept_count"],
         "interpretation": "File/function names don't match content patterns in this area.",
     },
+    "vocabulary_drift": {
+        "label": "Vocabulary Drift",
+        "icon": "🔤",
+        "color": "dim",
+        "data_points": ["vocabulary_drift", "variants"],
+        "interpretation": "One concept goes by several names here. Readers must learn they match.",
+    },
 }
 
 
@@ -327,6 +540,7 @@ def format_finding_detail(finding: Finding) -> list[str]:
     - Type and severity
     - Files affected
     - Data points from evidence
+    - Reference examples (e.g. similar-sized functions for complexity_outlier)
     - Neutral interpretation
     """
     lines = []
@@ -358,6 +572,13 @@ def format_finding_detail(finding: Finding) -> list[str]:
             lines.append(f"  {label}: {val}")
         lines.append("")
 
+    references = [e.description for e in finding.evidence if e.signal == "reference"]
+    if references:
+        lines.append("[bold]Similar functions:[/bold]")
+        for description in references:
+            lines.append(f"  {description}")
+        lines.append("")
+
     # Interpretation
     interp = display.get("interpretation", "")
     if interp:
@@ -0,0 +1,236 @@
+"""Line-oriented output formats for editor and tool integrations.
+
+Each format renders findings as one line per (finding, file) pair so that
+existing editor task runners can parse them without a dedicated plugin:
+
+    editor     path:line:col: message               (classic compiler style)
+    vscode     path:line:col: severity: message     (VS Code problem matcher)
+    quickfix   path:line:col:T:message              (Vim errorformat %f:%l:%c:%t:%m)
+
+``quickfix-json`` emits the same items as a JSON document whose ``items``
+can be passed straight to ``setqflist()``. Both quickfix shapes are covered by
+``QUICKFIX_VERSION``: fields are only ever added, and any rename or removal
+bumps the version.
+
+``sarif`` is a SARIF 2.1.0 log for code scanning uploads (GitHub, GitLab,
+Azure DevOps): one rule per finding type and one result per finding, located
+in each of its files, with a SARIF fix for each machine-applicable patch the
+finding carries. ``sarif`` and ``html`` are documents rather than lines,
+usually written to a file by a sink (see sinks.py).
+
+Findings are file-scoped, so the location points at the top of the file
+(line 1, column 1) unless the caller supplies a more precise line.
+"""
+
+from __future__ import annotations
+
+from typing import TYPE_CHECKING, Callable, Optional
+
+if TYPE_CHECKING:
+    from ..insights.models import Finding, Patch
+
+# Formats selectable via ``--format``
+OUTPUT_FORMATS = (
+    "rich",
+    "json",
+    "editor",
+    "vscode",
+    "quickfix",
+    "quickfix-json",
+    "sarif",
+    "html",
+)
+
+# Report presets selectable via ``--preset`` (rich and json formats only)
+PRESETS = ("due-diligence",)
+
+# Version of the quickfix line/JSON contract (see module docstring)
+QUICKFIX_VERSION = 1
+
+SARIF_SCHEMA = "https://json.schemastore.org/sarif-2.1.0.json"
+
+# Resolves the most relevant line for a (finding, file) pair; None = line 1
+LineResolver = Callable[["Finding", str], Optional[int]]
+
+
+def editor_severity(severity: float) -> str:
+    """Map a 0-1 severity onto compiler-style diagnostic levels."""
+    if severity >= 0.8:
+        return "error"
+    if severity >= 0.4:
+        return "warning"
+    return "info"
+
+
+def _message(finding: Finding, path: str) -> str:
+    """Single-line message for a finding, naming any partner files."""
+    others = [f for f in finding.files if f != path]
+    msg = f"{finding.title} [{finding.finding_type}]"
+    if others:
+        msg += f" (with {', '.join(others[:3])})"
+    return " ".join(msg.split())
+
+
+def _locations(
+    findings: list[Finding], resolve_line: LineResolver | None
+) -> list[tuple[str, int, Finding]]:
+    """Expand findings into (path, line, finding) triples."""
+    rows = []
+    for finding in findings:
+        for path in finding.files:
+            line = resolve_line(finding, path) if resolve_line else None
+            rows.append((path, max(1, line or 1), finding))
+    return rows
+
+
+def format_editor(findings: list[Finding], resolve_line: LineResolver | None = None) -> list[str]:
+    """Render findings as ``path:line:col: message`` lines."""
+    return [
+        f"{path}:{line}:1: {_message(finding, path)}"
+        for path, line, finding in _locations(findings, resolve_line)
+    ]
+
+
+def format_vscode(findings: list[Finding], resolve_line: LineResolver | None = None) -> list[str]:
+    """Render findings as ``path:line:col: severity: message`` lines.
+
+    Matches this ``problemMatcher`` pattern in ``.vscode/tasks.json``::
+
+        "regexp": "^(.*):(\\\\d+):(\\\\d+):\\\\s+(error|warning|info):\\\\s+(.*)$",
+        "file": 1, "line": 2, "column": 3, "severity": 4, "message": 5
+    """
+    return [
+        f"{path}:{line}:1: {editor_severity(finding.severity)}: {_message(finding, path)}"
+        for path, line, finding in _locations(findings, resolve_line)
+    ]
+
+
+def _quickfix_type(severity: float) -> str:
+    """Single-letter quickfix type (E/W/I) for Vim's ``%t``."""
+    return editor_severity(severity)[0].upper()
+
+
+def quickfix_items(findings: list[Finding], resolve_line: LineResolver | None = None) -> list[dict]:
+    """Quickfix entries using ``setqflist()`` field names."""
+    return [
+        {
+            "filename": path,
+            "lnum": line,
+            "col": 1,
+            "type": _quickfix_type(finding.severity),
+            "text": _message(finding, path),
+            "finding_type": finding.finding_type,
+            "severity": round(finding.severity, 3),
+        }
+        for path, line, finding in _locations(findings, resolve_line)
+    ]
+
+
+def format_quickfix(findings: list[Finding], resolve_line: LineResolver | None = None) -> list[str]:
+    """Render findings for ``:cexpr`` with ``errorformat=%f:%l:%c:%t:%m``."""
+    return [
+        f"{item['filename']}:{item['lnum']}:{item['col']}:{item['type']}:{item['text']}"
+        for item in quickfix_items(findings, resolve_line)
+    ]
+
+
+def quickfix_document(findings: list[Finding], resolve_line: LineResolver | None = None) -> dict:
+    """Versioned quickfix JSON document: ``{"version", "title", "items"}``."""
+    return {
+        "version": QUICKFIX_VERSION,
+        "title": "shannon-insight",
+        "items": quickfix_items(findings, resolve_line),
+    }
+
+
+def _sarif_level(severity: float) -> str:
+    """SARIF result level; SARIF says ``note`` where editors say ``info``."""
+    level = editor_severity(severity)
+    return "note" if level == "info" else level
+
+
+def sarif_document(
+    findings: list[Finding], version: str, resolve_line: LineResolver | None = None
+) -> dict:
+    """SARIF 2.1.0 log with one run of the ``shannon-insight`` tool."""
+    from ._finding_display import get_display_config
+
+    rule_ids = sorted({f.finding_type for f in findings})
+    rules = []
+    for rule_id in rule_ids:
+        display = get_display_config(rule_id)
+        rule = {"id": rule_id, "name": display["label"].replace(" ", "")}
+        rule["shortDescription"] = {"text": display["label"]}
+        if display.get("interpretation"):
+            rule["fullDescription"] = {"text": display["interpretation"]}
+        rules.append(rule)
+    locations: dict[int, list[dict]] = {}
+    for path, line, finding in _locations(findings, resolve_line):
+        locations.setdefault(id(finding), []).append(
+            {
+                "physicalLocation": {
+                    "artifactLocation": {"uri": path},
+                    "region": {"startLine": line},
+                }
+            }
+        )
+    results = []
+    for finding in findings:
+        result = {
+            "ruleId": finding.finding_type,
+            "ruleIndex": rule_ids.index(finding.finding_type),
+            "level": _sarif_level(finding.severity),
+            "message": {"text": " ".join(finding.title.split())},
+            "locations": locations.get(id(finding), []),
+            "properties": {
+                "severity": round(finding.severity, 3),
+                "suggestion": finding.suggestion,
+            },
+        }
+        if finding.patches:
+            result["fixes"] = [_sarif_fix(finding, patch) for patch in finding.patches]
+        results.append(result)
+    return {
+        "$schema": SARIF_SCHEMA,
+        "version": "2.1.0",
+        "runs": [
+            {
+                "tool": {
+                    "driver": {
+                        "name": "shannon-insight",
+                        "version": version,
+                        "informationUri": "https://github.com/namanagarwal/shannon-insight",
+                        "rules": rules,
+                    }
+                },
+                "results": results,
+            }
+        ],
+    }
+
+
+def _sarif_fix(finding: Finding, patch: Patch) -> dict:
+    """SARIF fix replacing the patched lines with the patch's replacement.
+
+    The deleted region runs to the start of the line after the patch, so it
+    takes the line ends with it and the inserted text brings its own.
+    """
+    return {
+        "description": {"text": finding.suggestion},
+        "artifactChanges": [
+            {
+                "artifactLocation": {"uri": patch.path},
+                "replacements": [
+                    {
+                        "deletedRegion": {
+                            "startLine": patch.start_line,
+                            "startColumn": 1,
+                            "endLine": patch.end_line + 1,
+                            "endColumn": 1,
+                        },
+                        "insertedContent": {"text": patch.replacement + "\n"},
+                    }
+                ],
+            }
+        ],
+    }
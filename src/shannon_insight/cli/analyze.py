@@ -1,32 +1,62 @@
 """Main analysis command - simplified and clean."""
 
+from contextlib import ExitStack
 from pathlib import Path
 from typing import Optional
 
 import typer
 
 from ..api import analyze
+from ..config import load_config
 from ..logging_config import setup_logging
+from ..remote import checkout, parse_remote
+from ..sinks import Output, Run, StdoutSink, parse_sink, run_sinks
 from . import app
 from ._common import console
+from ._formats import (
+    OUTPUT_FORMATS,
+    PRESETS,
+    format_editor,
+    format_quickfix,
+    format_vscode,
+    quickfix_document,
+    sarif_document,
+)
 
 
 @app.callback(invoke_without_command=True, no_args_is_help=False)
 def main(
     ctx: typer.Context,
-    path: Path = typer.Argument(
+    path: str = typer.Argument(
         ".",
-        help="Project root to analyze (default: current directory)",
-        exists=True,
-        file_okay=False,
-        dir_okay=True,
-        readable=True,
+        help=(
+            "Project root to analyze (default: current directory), or a git URL "
+            "with an optional @branch, @tag or @commit to analyze read-only"
+        ),
     ),
     json_output: bool = typer.Option(
         False,
         "--json",
         help="Output in machine-readable JSON format",
     ),
+    output_format: Optional[list[str]] = typer.Option(
+        None,
+        "--format",
+        "-f",
+        help=(
+            "Output format, repeatable: rich | json | editor (path:line:col) "
+            "| vscode (problem matcher) | quickfix (Vim errorformat) | quickfix-json "
+            "| sarif | html (default: rich)"
+        ),
+    ),
+    sink: Optional[list[str]] = typer.Option(
+        None,
+        "--sink",
+        help=(
+            "Where outputs go, repeatable: stdout | DIR | s3://bucket/prefix "
+            "| slack[:URL] | github | gitlab (default: stdout)"
+        ),
+    ),
     verbose: bool = typer.Option(
         False,
         "--verbose",
@@ -67,6 +97,27 @@ def main(
         "--trace",
         help="Enable provenance tracking for signal computation",
     ),
+    preset: Optional[str] = typer.Option(
+        None,
+        "--preset",
+        help="Report preset instead of findings: due-diligence",
+    ),
+    output: Optional[Path] = typer.Option(
+        None,
+        "--output",
+        "-o",
+        help="Also write the preset report to this Markdown file",
+    ),
+    offline: bool = typer.Option(
+        False,
+        "--offline",
+        help="Do not query package registries for dependency freshness",
+    ),
+    include_generated: bool = typer.Option(
+        False,
+        "--include-generated",
+        help="Score generated files (protoc output, Code generated headers, minified JS)",
+    ),
     version: bool = typer.Option(
         False,
         "--version",
@@ -84,6 +135,10 @@ def main(
         shannon-insight /path/to/code
         shannon-insight --verbose --max-findings 100
         shannon-insight --json --fail-on high
+        shannon-insight --format editor
+        shannon-insight --format sarif --format html --sink reports/ --sink slack
+        shannon-insight https://github.com/org/repo@v2.1
+        shannon-insight --preset due-diligence -o report.md
     """
     # Handle version
     if version:
@@ -92,6 +147,50 @@ def main(
         console.print(f"Shannon Insight v{__version__}")
         raise typer.Exit(0)
 
+    formats = list(dict.fromkeys(output_format or ["rich"]))
+    for name in formats:
+        if name not in OUTPUT_FORMATS:
+            console.print(
+                f"[red]Error:[/red] Unknown format '{name}'. "
+                f"Choose from: {', '.join(OUTPUT_FORMATS)}"
+            )
+            raise typer.Exit(2)
+    if json_output:
+        formats = ["json"]
+    if preset is not None and preset not in PRESETS:
+        console.print(
+            f"[red]Error:[/red] Unknown preset '{preset}'. Choose from: {', '.join(PRESETS)}"
+        )
+        raise typer.Exit(2)
+    if preset is not None and (len(formats) > 1 or formats[0] not in ("rich", "json") or sink):
+        console.print(
+            "[red]Error:[/red] --preset supports only one of the rich and json formats, "
+            "without --sink"
+        )
+        raise typer.Exit(2)
+    sinks = _resolve_sinks(formats, sink or ["stdout"])
+    # Rich goes to the terminal whatever the sinks; the other formats to every sink
+    rendered = [name for name in formats if name != "rich"]
+    to_stdout = any(isinstance(s, StdoutSink) for s in sinks)
+    output_format = formats[0]
+
+    try:
+        remote = parse_remote(path)
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+    if remote is None and not Path(path).is_dir():
+        console.print(f"[red]Error:[/red] Directory '{path}' does not exist")
+        raise typer.Exit(2)
+
+    # Subcommands read a local directory; a remote is only checked out for analysis
+    if remote is not None and ctx.invoked_subcommand:
+        console.print(
+            f"[red]Error:[/red] '{ctx.invoked_subcommand}' needs a local directory; "
+            f"clone {remote.url} and pass its path"
+        )
+        raise typer.Exit(2)
+
     # Store path in context for subcommands
     ctx.obj = ctx.obj or {}
     try:
@@ -99,6 +198,7 @@ def main(
     except (FileNotFoundError, OSError):
         target = Path(path).absolute()
     ctx.obj["path"] = target
+    ctx.obj["config"] = config
 
     # If subcommand invoked, don't run analysis
     if ctx.invoked_subcommand:
@@ -106,29 +206,52 @@ def main(
 
     # Setup logging
     setup_logging(verbose=verbose)
+    project = Path(remote.url).stem if remote is not None else target.name
+    delivered = True
 
     try:
-        # Run analysis using new API
-        result, snapshot = analyze(
-            path=str(target),
-            config_file=config,
-            verbose=verbose,
-            workers=workers,
-            max_findings=max_findings,
-            enable_provenance=trace,
-        )
-
-        # Output results
-        if json_output:
-            _output_json(result, snapshot)
-        else:
-            _output_rich(result, snapshot, verbose=verbose)
+        with ExitStack() as stack:
+            if remote is not None:
+                # Checked out into a temporary directory, removed once reported
+                if "rich" in formats:
+                    console.print(f"[dim]Fetching {remote.url}@{remote.ref or 'HEAD'}...[/dim]")
+                mirror_dir = load_config(config_file=config).remote_mirror_dir
+                target = stack.enter_context(checkout(remote, mirror_dir))
+
+            # Run analysis using new API
+            result, snapshot = analyze(
+                path=str(target),
+                config_file=config,
+                verbose=verbose,
+                workers=workers,
+                max_findings=max_findings,
+                enable_provenance=trace,
+                # Only when given, so include_generated in the config still applies
+                **({"include_generated": True} if include_generated else {}),
+            )
+
+            # Output results
+            if preset is not None:
+                _output_preset(target, result, snapshot, output_format, output, offline)
+            else:
+                if "rich" in formats:
+                    _output_rich(result, snapshot, verbose=verbose)
+                # Rendered while a remote checkout still exists (html embeds sources)
+                run = Run(
+                    [Output(name, _render(name, result, snapshot)) for name in rendered],
+                    result.findings,
+                    snapshot.file_count,
+                    project,
+                )
+                delivered = _deliver(sinks, run, quiet=to_stdout and bool(rendered))
 
         # Handle fail-on threshold for CI/CD
         if fail_on:
             exit_code = _check_fail_threshold(result, fail_on)
             if exit_code != 0:
                 raise typer.Exit(exit_code)
+        if not delivered:
+            raise typer.Exit(1)
 
     except KeyboardInterrupt:
         console.print("\n[yellow]Analysis interrupted[/yellow]")
@@ -142,11 +265,62 @@ def main(
         raise typer.Exit(1)
 
 
-def _output_json(result, snapshot):
-    """Output results in JSON format."""
+def _resolve_sinks(formats: list[str], specs: list[str]) -> list:
+    """The sinks of the --sink values, checked against the formats; exits 2 if unusable."""
+    try:
+        sinks = [parse_sink(spec) for spec in dict.fromkeys(specs)]
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+    rendered = [name for name in formats if name != "rich"]
+    if any(isinstance(s, StdoutSink) for s in sinks) and len(formats) > 1:
+        console.print(
+            "[red]Error:[/red] only one format fits on stdout; "
+            "write the others with --sink DIR (or s3://, slack)"
+        )
+        raise typer.Exit(2)
+    if not rendered and any(s.needs_outputs and not isinstance(s, StdoutSink) for s in sinks):
+        console.print("[red]Error:[/red] nothing to write to the sinks; add a --format")
+        raise typer.Exit(2)
+    return sinks
+
+
+def _render(output_format: str, result, snapshot) -> str:
+    """One format as text for the sinks."""
     import json
 
-    output = {
+    if output_format == "json":
+        return json.dumps(_json_document(result, snapshot), indent=2)
+    if output_format == "quickfix-json":
+        return json.dumps(quickfix_document(result.findings), indent=2)
+    if output_format == "sarif":
+        from .. import __version__
+
+        return json.dumps(sarif_document(result.findings, __version__), indent=2)
+    if output_format == "html":
+        from ..visualization import render_report
+
+        return render_report(snapshot)
+    formatters = {"editor": format_editor, "vscode": format_vscode, "quickfix": format_quickfix}
+    return "\n".join(formatters[output_format](result.findings))
+
+
+def _deliver(sinks, run, quiet: bool) -> bool:
+    """Hand the outputs to every sink; False when one of them failed."""
+    results = run_sinks(sinks, run)
+    for outcome in results:
+        # Nothing but the document when it is on stdout
+        if outcome.ok and outcome.detail and not quiet:
+            console.print(f"[dim]{outcome.sink}: {outcome.detail}[/dim]")
+    failed = [outcome for outcome in results if not outcome.ok]
+    for outcome in failed:
+        console.print(f"[red]Error:[/red] sink {outcome.sink} failed: {outcome.error}")
+    return not failed
+
+
+def _json_document(result, snapshot) -> dict:
+    """Findings and summary of the json format."""
+    return {
         "findings": [
             {
                 "type": f.finding_type,
@@ -172,8 +346,27 @@ def _output_json(result, snapshot):
             "total_findings": len(result.findings),
         },
     }
-    # Use print() instead of console.print() to avoid Rich formatting/wrapping
-    print(json.dumps(output, indent=2))
+
+
+def _output_preset(target, result, snapshot, output_format, output, offline):
+    """Output the due-diligence report (the only preset)."""
+    from ..diligence import build_report
+
+    titles = [f.title for f in result.findings]
+    report = build_report(target, snapshot, titles, offline=offline)
+    markdown = report.render_markdown()
+    if output is not None:
+        output.write_text(markdown, encoding="utf-8")
+    if output_format == "json":
+        import json
+
+        print(json.dumps(report.to_dict(), indent=2))
+    else:
+        from rich.markdown import Markdown
+
+        console.print(Markdown(markdown))
+        if output is not None:
+            console.print(f"[dim]Report written to {output}[/dim]")
 
 
 def _output_rich(result, snapshot, verbose: bool = False):
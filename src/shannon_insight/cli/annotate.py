@@ -0,0 +1,99 @@
+"""Annotate CLI command -- metric comments above functions, in the source."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+
+from . import app
+from ._common import console, resolve_settings
+
+
+@app.command()
+def annotate(
+    ctx: typer.Context,
+    paths: Optional[list[Path]] = typer.Argument(
+        None,
+        help="Files or directories to annotate (default: the whole repository)",
+    ),
+    remove: bool = typer.Option(
+        False,
+        "--remove",
+        help="Delete the annotations instead of writing them",
+    ),
+    dry_run: bool = typer.Option(
+        False,
+        "--dry-run",
+        help="List the files that would change without writing them",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Write function metrics as comments above each function.
+
+    For editors that cannot run the language server: every function gets
+    one comment such as [dim]# shannon: complexity=17 nesting=4 lines=62
+    churn=high[/dim] above it (and above its decorators). Running it again
+    updates the comments; --remove deletes them and nothing else. Notebooks
+    are skipped.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight annotate
+
+      shannon-insight annotate src/billing --dry-run
+
+      shannon-insight annotate --remove
+    """
+    from ..annotate import annotate_files, churn_levels
+    from ..hygiene import load_sources
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    settings = resolve_settings(config=obj.get("config"))
+
+    prefixes = []
+    for path in paths or []:
+        try:
+            rel = path.resolve().relative_to(root).as_posix()
+        except ValueError:
+            console.print(f"[red]Error:[/red] {path} is not inside {root}")
+            raise typer.Exit(2)
+        prefixes.append("" if rel == "." else rel)
+
+    sources = load_sources(root, settings)
+    syntax = {
+        path: file_syntax
+        for path, file_syntax in sources.syntax.items()
+        if not prefixes or any(_under(path, prefix) for prefix in prefixes)
+    }
+    churn = None if remove else churn_levels(sources.root, settings.git_max_commits)
+    result = annotate_files(sources.root, syntax, remove=remove, churn=churn, dry_run=dry_run)
+
+    if json_output:
+        print(json.dumps({**result.to_dict(), "dry_run": dry_run, "remove": remove}, indent=2))
+        return
+
+    verb = "Would update" if dry_run else "Updated"
+    what = "removed from" if remove else "on"
+    console.print()
+    console.print(
+        f"[bold cyan]ANNOTATE[/bold cyan] -- {result.functions} annotations {what} "
+        f"{len(syntax)} files; {verb.lower()} {len(result.changed)}"
+    )
+    for path in result.changed:
+        console.print(f"  {path}")
+    if result.skipped:
+        console.print(f"[dim]Skipped {len(result.skipped)} notebooks or unreadable files[/dim]")
+    if not remove and churn is None:
+        console.print("[dim]No git history: churn left out[/dim]")
+    console.print()
+
+
+def _under(path: str, prefix: str) -> bool:
+    return not prefix or path == prefix or path.startswith(prefix + "/")
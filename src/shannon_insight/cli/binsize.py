@@ -0,0 +1,156 @@
+"""Binary size CLI command -- Go binary bytes per package, against their source."""
+
+import json
+import tempfile
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console, resolve_settings
+
+
+@app.command()
+def binsize(
+    ctx: typer.Context,
+    binary: Optional[Path] = typer.Argument(
+        None,
+        help="Compiled Go binary (with its symbol table) built from this repository",
+    ),
+    build: Optional[str] = typer.Option(
+        None,
+        "--build",
+        "-b",
+        help="Build this package (e.g. ./cmd/server) with go build and measure it",
+    ),
+    top: int = typer.Option(
+        20,
+        "--top",
+        "-n",
+        help="Entries per table",
+        min=1,
+        max=1000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Attribute a Go binary's size to packages and flag disproportionate ones.
+
+    Symbol sizes are summed per package. Packages of this repository are
+    set against their source lines, and those holding a much larger share
+    of the binary than of the source are reported as bloated, with likely
+    causes (embedded files, generated code, a large table, generics).
+    Dependencies are summed per module.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight binsize bin/server
+
+      shannon-insight binsize --build ./cmd/server
+
+      shannon-insight binsize bin/server --json
+    """
+    from ..binary_size import BinaryError, analyze_binary, build_binary, read_binary
+    from ..hygiene import load_sources
+
+    if (binary is None) == (build is None):
+        console.print("[red]Error:[/red] give either a binary or --build PACKAGE")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    try:
+        if build is not None:
+            with tempfile.TemporaryDirectory(prefix="shannon-binsize-") as scratch:
+                go_binary = read_binary(build_binary(root, build, Path(scratch)))
+        else:
+            go_binary = read_binary(binary)  # type: ignore[arg-type]
+    except BinaryError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    report = analyze_binary(go_binary, sources)
+    if build is not None:
+        report.binary = build  # the binary itself was in a scratch directory
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    console.print(
+        f"[bold cyan]BINARY SIZE[/bold cyan] -- {report.binary}: {_kib(report.file_size)}, "
+        f"{_kib(report.symbol_bytes)} in symbols, {_kib(report.local_bytes)} from this repository"
+    )
+    if report.main_path:
+        console.print(f"  main package {report.main_path}")
+
+    if report.local:
+        console.print()
+        console.print("[bold cyan]LOCAL PACKAGES[/bold cyan] -- binary share against source share")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Package", min_width=24)
+        table.add_column("Size", justify="right")
+        table.add_column("Code", justify="right")
+        table.add_column("Data", justify="right")
+        table.add_column("Lines", justify="right")
+        table.add_column("Bytes/line", justify="right")
+        table.add_column("Ratio", justify="right")
+        for p in report.local[:top]:
+            ratio = f"{p.ratio:.1f}"
+            table.add_row(
+                p.directory or p.package,
+                _kib(p.bytes),
+                _kib(p.text_bytes),
+                _kib(p.data_bytes),
+                str(p.lines),
+                f"{p.bytes_per_line:.0f}",
+                f"[red]{ratio}[/red]" if p.bloated else ratio,
+            )
+        console.print(table)
+    else:
+        console.print(
+            "[yellow]No symbols from this repository:[/yellow] no go.mod here declares "
+            "the binary's packages"
+        )
+
+    bloated = report.bloated()
+    if bloated:
+        console.print()
+        console.print("[bold red]BLOATED[/bold red] -- far larger in the binary than in source")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Package", min_width=24)
+        table.add_column("Size", justify="right")
+        table.add_column("Ratio", justify="right")
+        table.add_column("Likely cause")
+        for p in bloated[:top]:
+            table.add_row(
+                p.directory or p.package,
+                _kib(p.bytes),
+                f"{p.ratio:.1f}",
+                ", ".join(p.causes) or "--",
+            )
+        console.print(table)
+
+    console.print()
+    console.print("[bold cyan]DEPENDENCIES[/bold cyan]")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Module", min_width=24)
+    table.add_column("Size", justify="right")
+    table.add_column("Share", justify="right")
+    for module, size in list(report.external.items())[:top]:
+        share = size / report.symbol_bytes if report.symbol_bytes else 0.0
+        table.add_row(module, _kib(size), f"{share:.0%}")
+    console.print(table)
+    console.print()
+
+
+def _kib(size: int) -> str:
+    return f"{size / 1024:.1f} KiB"
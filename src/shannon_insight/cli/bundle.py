@@ -0,0 +1,287 @@
+"""Result bundle CLI commands -- write, inspect, query and compare .sib files."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from ._common import console
+
+bundle_app: typer.Typer = typer.Typer(
+    help="Compressed result bundles with random access (write, info, query, compare)",
+    no_args_is_help=True,
+    rich_markup_mode="rich",
+)
+
+
+def _open(path: Path):
+    from ..persistence.bundle import BundleError, ResultBundle
+
+    try:
+        return ResultBundle(path)
+    except (OSError, BundleError, ValueError) as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+
+
+@bundle_app.command()
+def write(
+    ctx: typer.Context,
+    output: Path = typer.Argument(..., help="Bundle file to write (conventionally *.sib)"),
+    snapshot_id: Optional[int] = typer.Option(
+        None,
+        "--snapshot",
+        help="Export this history snapshot instead of running a new analysis",
+    ),
+    codec: Optional[str] = typer.Option(
+        None,
+        "--codec",
+        help="zstd | zlib (default: zstd when installed)",
+    ),
+):
+    """
+    Analyze the project (or export a saved snapshot) into a result bundle.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight bundle write results.sib
+
+      shannon-insight bundle write baseline.sib --snapshot 12
+    """
+    from ..persistence.bundle import BundleError, write_bundle
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+
+    if snapshot_id is not None:
+        from ..persistence import HistoryDB
+        from ..persistence.reader import load_tensor_snapshot
+
+        try:
+            with HistoryDB(str(root)) as db:
+                snapshot = load_tensor_snapshot(db.conn, snapshot_id)
+        except ValueError as e:
+            console.print(f"[red]Error:[/red] {e}")
+            raise typer.Exit(2)
+    else:
+        from ..api import analyze
+
+        _, snapshot = analyze(path=str(root), config_file=obj.get("config"), quiet=True)
+
+    try:
+        size = write_bundle(output, snapshot, codec=codec)
+    except (ValueError, BundleError) as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+    console.print(
+        f"[green]Wrote[/green] {output} -- {snapshot.file_count} files, "
+        f"{len(snapshot.findings)} findings, {size / 1024:.0f} KiB"
+    )
+
+
+@bundle_app.command()
+def info(
+    path: Path = typer.Argument(..., help="Bundle file"),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """Show bundle metadata and global signals without loading file records."""
+    with _open(path) as bundle:
+        meta = bundle.meta
+        doc = {
+            "codec": bundle.codec,
+            "size_bytes": path.stat().st_size,
+            "files": len(bundle.files()),
+            "modules": len(bundle.modules()),
+            **meta,
+        }
+    if json_output:
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    console.print(f"[bold cyan]BUNDLE[/bold cyan] -- {path.name} ({doc['codec']})")
+    table = Table(show_header=False, box=None, pad_edge=False)
+    table.add_column("Key", style="bold")
+    table.add_column("Value")
+    for key in ("timestamp", "commit_sha", "analyzed_path", "files", "modules", "size_bytes"):
+        table.add_row(key, str(doc.get(key)))
+    console.print(table)
+    if meta.get("global_signals"):
+        signals = Table(show_header=True, pad_edge=True)
+        signals.add_column("Global signal")
+        signals.add_column("Value", justify="right")
+        for name, value in sorted(meta["global_signals"].items()):
+            signals.add_row(name, f"{value:.4g}" if isinstance(value, float) else str(value))
+        console.print(signals)
+    console.print()
+
+
+@bundle_app.command()
+def query(
+    path: Path = typer.Argument(..., help="Bundle file"),
+    files: Optional[list[str]] = typer.Option(
+        None,
+        "--file",
+        "-f",
+        help="File to look up (repeatable)",
+    ),
+    signal: Optional[list[str]] = typer.Option(
+        None,
+        "--signal",
+        "-s",
+        help="Only these signals (repeatable)",
+    ),
+    findings: bool = typer.Option(
+        False,
+        "--findings",
+        help="List findings instead of file signals",
+    ),
+    finding_type: Optional[str] = typer.Option(
+        None,
+        "--type",
+        help="With --findings, only this finding type",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Look up file signals or findings in a bundle.
+
+    Only the blocks holding the requested files are decompressed.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight bundle query results.sib -f src/app.py -s cognitive_load
+
+      shannon-insight bundle query results.sib --findings --type god_file --json
+    """
+    with _open(path) as bundle:
+        if findings:
+            records = bundle.findings()
+            if finding_type:
+                records = [f for f in records if f.finding_type == finding_type]
+            _print_findings(records, json_output)
+            return
+        if not files:
+            console.print("[red]Error:[/red] pass --file or --findings.")
+            raise typer.Exit(2)
+        rows = {}
+        for name in files:
+            signals = bundle.file_signals(name)
+            if signals is not None and signal:
+                signals = {k: v for k, v in signals.items() if k in signal}
+            rows[name] = signals
+
+    if json_output:
+        print(json.dumps(rows, indent=2))
+        return
+    for name, signals in rows.items():
+        console.print()
+        if signals is None:
+            console.print(f"[yellow]{name}: not in bundle[/yellow]")
+            continue
+        table = Table(title=name, show_header=True, pad_edge=True)
+        table.add_column("Signal")
+        table.add_column("Value", justify="right")
+        for key, value in sorted(signals.items()):
+            table.add_row(key, f"{value:.4g}" if isinstance(value, float) else str(value))
+        console.print(table)
+    console.print()
+
+
+def _print_findings(records, json_output: bool) -> None:
+    if json_output:
+        from dataclasses import asdict
+
+        print(json.dumps([asdict(f) for f in records], indent=2))
+        return
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Severity", justify="right")
+    table.add_column("Type")
+    table.add_column("Title")
+    table.add_column("Files")
+    for f in sorted(records, key=lambda r: -r.severity):
+        table.add_row(f"{f.severity:.2f}", f.finding_type, f.title, ", ".join(f.files[:3]))
+    console.print(table)
+
+
+@bundle_app.command()
+def compare(
+    old: Path = typer.Argument(..., help="Earlier bundle"),
+    new: Path = typer.Argument(..., help="Later bundle"),
+    signal: Optional[list[str]] = typer.Option(
+        None,
+        "--signal",
+        "-s",
+        help="Only compare these signals (repeatable)",
+    ),
+    threshold: float = typer.Option(
+        0.01,
+        "--threshold",
+        help="Minimum absolute change to report",
+    ),
+    limit: int = typer.Option(
+        20,
+        "--limit",
+        "-n",
+        help="Maximum changed files to show",
+        min=1,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Compare per-file signals of two bundles, streaming one block at a time.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight bundle compare main.sib pr.sib -s cognitive_load
+    """
+    from ..persistence.bundle import compare_bundles
+
+    with _open(old) as old_bundle, _open(new) as new_bundle:
+        result = compare_bundles(old_bundle, new_bundle, threshold, signal or None)
+
+    if json_output:
+        print(json.dumps(result.to_dict(), indent=2))
+        return
+
+    console.print()
+    console.print(
+        f"[bold cyan]COMPARE[/bold cyan] -- {len(result.worsening)} worsening, "
+        f"{len(result.improving)} improving, {len(result.files_added)} added, "
+        f"{len(result.files_removed)} removed"
+    )
+    ranked = sorted(
+        result.signal_deltas.items(),
+        key=lambda item: -max(abs(d.delta) for d in item[1]),
+    )[:limit]
+    if ranked:
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("File", min_width=24)
+        table.add_column("Signal")
+        table.add_column("Old", justify="right")
+        table.add_column("New", justify="right")
+        for path, deltas in ranked:
+            for i, d in enumerate(deltas):
+                color = {"worsening": "red", "improving": "green"}.get(d.trend, "dim")
+                table.add_row(
+                    path if i == 0 else "",
+                    d.signal_name,
+                    f"{d.old_value:.4g}",
+                    f"[{color}]{d.new_value:.4g}[/{color}]",
+                )
+        console.print(table)
+    console.print()
@@ -0,0 +1,97 @@
+"""C4 CLI command -- architecture diagrams generated from the source tree."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def c4(
+    ctx: typer.Context,
+    fmt: str = typer.Option(
+        "structurizr",
+        "--format",
+        "-f",
+        help="structurizr (Structurizr DSL) or plantuml (C4-PlantUML)",
+    ),
+    level: str = typer.Option(
+        "container",
+        "--level",
+        "-l",
+        help="container (services only) or component (services and their packages)",
+    ),
+    depth: Optional[int] = typer.Option(
+        None,
+        "--depth",
+        help="Directory depth of components below each service (default: auto)",
+        min=0,
+    ),
+    output: Optional[Path] = typer.Option(
+        None,
+        "--output",
+        "-o",
+        help="File to write (default: stdout)",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output the detected model as JSON instead of a diagram",
+    ),
+):
+    """
+    Export a C4 architecture diagram of the services in this repository.
+
+    Every directory with a build manifest (go.mod, package.json,
+    pyproject.toml, Cargo.toml, pom.xml, a Dockerfile...) is a container,
+    described by its entry points. Relationships are imports between
+    services and URL literals naming another service. Regenerate the
+    diagram in CI to keep architecture docs in step with the code.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight c4 -o docs/architecture.dsl
+
+      shannon-insight c4 -f plantuml -l component -o docs/components.puml
+
+      shannon-insight c4 --json
+    """
+    from ..architecture.c4 import C4_FORMATS, C4_LEVELS, build_model, render
+    from ..graph.builder import build_dependency_graph
+    from ..hygiene import load_sources
+    from ._common import resolve_settings
+
+    if fmt not in C4_FORMATS:
+        console.print(f"[red]Error:[/red] --format must be one of: {', '.join(C4_FORMATS)}")
+        raise typer.Exit(2)
+    if level not in C4_LEVELS:
+        console.print(f"[red]Error:[/red] --level must be one of: {', '.join(C4_LEVELS)}")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    graph = build_dependency_graph(list(sources.syntax.values()), str(root))
+    model = build_model(sources, graph.adjacency, depth)
+    if json_output:
+        text = json.dumps(model.to_dict(), indent=2) + "\n"
+    else:
+        text = render(model, fmt, level)
+
+    if output is None:
+        print(text, end="")
+        return
+    output.write_text(text, encoding="utf-8")
+    components = sum(len(c.components) for c in model.containers)
+    console.print(
+        f"[green]Wrote[/green] {output} -- {len(model.containers)} containers, "
+        f"{components} components, {len(model.relations)} container relationships"
+    )
@@ -0,0 +1,143 @@
+"""Centrality CLI command -- the symbols and packages everything else leans on."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+_SORT_KEYS = ("pagerank", "betweenness")
+
+
+@app.command()
+def centrality(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        15,
+        "--top",
+        "-n",
+        help="Entries per table",
+        min=1,
+        max=1000,
+    ),
+    by: str = typer.Option(
+        "pagerank",
+        "--by",
+        help="Order symbols and packages by: pagerank, betweenness",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Rank functions, classes and packages by call/import-graph centrality.
+
+    PageRank marks what is called (or imported) by code that is itself
+    heavily used; betweenness marks what sits on the paths between other
+    code. Hotspots are called functions that are both central and complex,
+    the places where a change is hardest and matters most.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight centrality
+
+      shannon-insight centrality --by betweenness -n 30
+
+      shannon-insight centrality --json
+    """
+    from ..graph.builder import build_dependency_graph
+    from ..graph.centrality import build_centrality, symbol_complexity
+    from ..hygiene import load_sources
+    from ..signals.function_outliers import collect_functions
+    from ._common import resolve_settings
+
+    if by not in _SORT_KEYS:
+        console.print(f"[red]Error:[/red] --by must be one of: {', '.join(_SORT_KEYS)}")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    graph = build_dependency_graph(list(sources.syntax.values()), str(root))
+    report = build_centrality(
+        sources.syntax,
+        graph.adjacency,
+        symbol_complexity(collect_functions(sources.syntax, sources.content)),
+    )
+    report.symbols.sort(key=lambda r: (-getattr(r, by), r.symbol.id))
+    report.packages.sort(key=lambda p: (-getattr(p, by), p.package))
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    if report.call_graph_available:
+        hotspots = report.hotspots(top)
+        if hotspots:
+            console.print("[bold cyan]HOTSPOTS[/bold cyan] -- central and complex")
+            table = Table(show_header=True, pad_edge=True)
+            table.add_column("Function", min_width=24)
+            table.add_column("Complexity", justify="right")
+            table.add_column("Callers", justify="right")
+            table.add_column("Score", justify="right")
+            for h in hotspots:
+                table.add_row(
+                    f"{h.rank.symbol.path}:{h.rank.symbol.line} {h.rank.symbol.name}",
+                    str(h.complexity),
+                    str(h.rank.callers),
+                    f"{h.score:.2f}",
+                )
+            console.print(table)
+            console.print()
+
+        console.print(f"[bold cyan]CENTRAL SYMBOLS[/bold cyan] -- by {by}")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Symbol", min_width=24)
+        table.add_column("Kind")
+        table.add_column("PageRank", justify="right")
+        table.add_column("Betweenness", justify="right")
+        table.add_column("Callers", justify="right")
+        for rank in report.symbols[:top]:
+            line = f":{rank.symbol.line}" if rank.symbol.line else ""
+            table.add_row(
+                f"{rank.symbol.path}{line} {rank.symbol.name}",
+                rank.symbol.kind,
+                f"{rank.pagerank:.4f}",
+                f"{rank.betweenness:.4f}",
+                str(rank.callers),
+            )
+        console.print(table)
+    else:
+        console.print(
+            "[yellow]No call graph:[/yellow] calls are only extracted by tree-sitter "
+            "(pip install shannon-codebase-insight\\[parsing])"
+        )
+    console.print()
+
+    console.print(f"[bold cyan]CENTRAL PACKAGES[/bold cyan] -- by {by}")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Files", justify="right")
+    table.add_column("PageRank", justify="right")
+    table.add_column("Betweenness", justify="right")
+    table.add_column("Dependents", justify="right")
+    for package in report.packages[:top]:
+        table.add_row(
+            package.package,
+            str(package.files),
+            f"{package.pagerank:.4f}",
+            f"{package.betweenness:.4f}",
+            str(package.dependents),
+        )
+    console.print(table)
+    console.print()
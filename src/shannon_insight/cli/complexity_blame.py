@@ -0,0 +1,124 @@
+"""Complexity-blame CLI command -- the commits that made a function complex."""
+
+import json
+from datetime import datetime, timezone
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command("complexity-blame")
+def complexity_blame(
+    ctx: typer.Context,
+    path: str = typer.Argument(..., help="File the function is in, relative to the project root"),
+    function: str = typer.Argument(..., help="Function name, Class.method for methods"),
+    top: int = typer.Option(
+        10,
+        "--top",
+        "-n",
+        help="Commits to list as adding the most complexity",
+        min=1,
+    ),
+    max_commits: int = typer.Option(
+        500,
+        "--max-commits",
+        help="Commits of the file to read, newest first",
+        min=1,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Show how a function's complexity grew, commit by commit.
+
+    Re-parses the function in every commit that changed its file (following
+    renames) and in that commit's parent, then lists its complexity over
+    time and the commits that added the most of it.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight complexity-blame src/engine.py Engine.run
+
+      shannon-insight complexity-blame pkg/parse.go parseExpr --top 5 --json
+    """
+    from ..scanning.gobuild import go_build_config
+    from ..scanning.syntax_extractor import SyntaxExtractor
+    from ..temporal.complexity_blame import blame_complexity
+    from ._common import resolve_settings
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    settings = resolve_settings(config=obj.get("config"))
+    extractor = SyntaxExtractor(
+        max_workers=1,
+        c_defines=settings.c_defines,
+        c_conditionals=settings.c_conditionals,
+        grammars=settings.grammars,
+        go_build=go_build_config(settings),
+    )
+    try:
+        blame = blame_complexity(root, path, function, extractor, max_commits)
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+
+    if json_output:
+        print(json.dumps(blame.to_dict(top), indent=2))
+        return
+
+    current = blame.current
+    console.print()
+    state = f"complexity {current['complexity']}" if current else "removed"
+    console.print(
+        f"[bold cyan]COMPLEXITY BLAME[/bold cyan] -- {function} in {path}: {state}, "
+        f"{len(blame.versions)} changing commits of {blame.commits} read"
+    )
+    if blame.truncated:
+        console.print(f"[dim]Stopped after {blame.commits} commits; raise --max-commits.[/dim]")
+    console.print()
+
+    growth = blame.growth(top)
+    if not growth:
+        console.print("[green]No commit made this function more complex.[/green]")
+        console.print()
+        return
+
+    console.print(f"[bold cyan]ADDED MOST COMPLEXITY[/bold cyan] ({len(growth)})")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Commit")
+    table.add_column("Date")
+    table.add_column("Author")
+    table.add_column("Added", justify="right")
+    table.add_column("After", justify="right")
+    table.add_column("Subject", max_width=60)
+    for version in growth:
+        table.add_row(
+            version.commit[:8],
+            _date(version.timestamp),
+            version.author,
+            f"{version.added:+d}",
+            str(version.complexity),
+            version.subject,
+        )
+    console.print(table)
+    console.print()
+
+    console.print("[bold cyan]OVER TIME[/bold cyan]")
+    for version in blame.versions:
+        bar = "#" * min(version.complexity, 60)
+        console.print(
+            f"  {_date(version.timestamp)}  {version.commit[:8]}  "
+            f"{version.complexity:>4} {version.added:+4d}  [yellow]{bar}[/yellow]"
+        )
+    console.print()
+
+
+def _date(timestamp: int) -> str:
+    return datetime.fromtimestamp(timestamp, timezone.utc).strftime("%Y-%m-%d")
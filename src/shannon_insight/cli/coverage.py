@@ -0,0 +1,149 @@
+"""Coverage CLI command -- risk matrix of function complexity against test coverage."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def coverage(
+    ctx: typer.Context,
+    report_path: Optional[Path] = typer.Option(
+        None,
+        "--report",
+        "-r",
+        help="Coverage report: Cobertura XML, LCOV or Go cover profile "
+        "(default: coverage_report, else coverage.xml, lcov.info, coverage.out, ...)",
+    ),
+    complexity: int = typer.Option(
+        10,
+        "--complexity",
+        help="Functions at or above this cyclomatic complexity count as complex",
+        min=1,
+    ),
+    min_coverage: float = typer.Option(
+        0.5,
+        "--coverage",
+        help="Functions below this share of lines run count as untested",
+        min=0.0,
+        max=1.0,
+    ),
+    top: int = typer.Option(
+        15,
+        "--top",
+        "-n",
+        help="High-risk functions to list",
+        min=1,
+        max=1000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Join a coverage report with function complexity into a risk matrix.
+
+    Reads coverage.py/Cobertura XML, LCOV tracefiles or Go cover profiles
+    (go test -coverprofile). Each function's coverage is the share of its
+    instrumented lines that ran; complex functions with low coverage are the
+    riskiest to change and are listed by complexity times uncovered share.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight coverage
+
+      shannon-insight coverage -r coverage.out --complexity 15
+
+      shannon-insight coverage --json
+    """
+    from ..coverage import find_report, load_line_coverage
+    from ..hygiene import load_sources
+    from ..signals.coverage_risk import analyze_coverage_risk
+    from ._common import resolve_settings
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    settings = resolve_settings(config=obj.get("config"))
+    if report_path is None and settings.coverage_report:
+        report_path = root / settings.coverage_report
+    report_path = report_path or find_report(root)
+    if report_path is None or not report_path.is_file():
+        console.print(
+            "[red]Error:[/red] no coverage report found "
+            "(pass --report, or write coverage.xml, lcov.info or coverage.out)"
+        )
+        raise typer.Exit(2)
+
+    line_coverage = load_line_coverage(root, report_path)
+    if not line_coverage:
+        console.print(f"[red]Error:[/red] no line coverage in {report_path}")
+        raise typer.Exit(2)
+
+    sources = load_sources(root, settings)
+    risk = analyze_coverage_risk(
+        sources.syntax, sources.content, line_coverage, complexity, min_coverage
+    )
+
+    if json_output:
+        data = {"report": str(report_path), **risk.to_dict(top)}
+        print(json.dumps(data, indent=2))
+        return
+
+    console.print()
+    console.print("[bold cyan]COVERAGE RISK[/bold cyan]")
+    overall = risk.line_coverage()
+    console.print(
+        f"  {len(risk.functions)} functions measured, {risk.unmeasured} not in the report"
+        + (f"; {overall:.1%} of their lines ran" if overall is not None else "")
+    )
+    console.print()
+
+    matrix = risk.matrix()
+    table = Table(show_header=True, pad_edge=True, title="Functions by complexity and coverage")
+    table.add_column("")
+    table.add_column(f"Coverage < {min_coverage:.0%}", justify="right")
+    table.add_column(f"Coverage >= {min_coverage:.0%}", justify="right")
+    table.add_row(
+        f"Complexity >= {complexity}",
+        f"[bold red]{matrix['high']['low']}[/bold red]",
+        str(matrix["high"]["high"]),
+    )
+    table.add_row(
+        f"Complexity < {complexity}",
+        str(matrix["low"]["low"]),
+        f"[green]{matrix['low']['high']}[/green]",
+    )
+    console.print(table)
+    console.print()
+
+    hotspots = risk.hotspots()
+    if not hotspots:
+        console.print("[green]No complex function is below the coverage threshold.[/green]")
+        console.print()
+        return
+
+    console.print("[bold cyan]HIGH RISK[/bold cyan] -- complex and barely tested, riskiest first")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Function", min_width=30)
+    table.add_column("Complexity", justify="right")
+    table.add_column("Coverage", justify="right")
+    table.add_column("Lines run", justify="right")
+    for fc in hotspots[:top]:
+        table.add_row(
+            fc.function.label,
+            str(fc.function.complexity),
+            f"{fc.coverage:.0%}",
+            f"{fc.covered}/{fc.instrumented}",
+        )
+    console.print(table)
+    if len(hotspots) > top:
+        console.print(f"[dim]... and {len(hotspots) - top} more[/dim]")
+    console.print()
@@ -0,0 +1,128 @@
+"""Density CLI command -- how much the codebase says per byte, line and name."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+from .health import _sparkline
+
+_SORT_KEYS = ("density", "ratio", "lines", "exponent")
+
+
+@app.command()
+def density(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        15,
+        "--top",
+        "-n",
+        help="Packages to list",
+        min=1,
+        max=1000,
+    ),
+    by: str = typer.Option(
+        "density",
+        "--by",
+        help="Order packages by: density, ratio, lines, exponent",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Measure information density: compression, redundancy and vocabulary growth.
+
+    The codebase's compression ratio is placed against typical source code,
+    cross-file redundancy estimates how much is repeated between files, and
+    the vocabulary growth curve shows how fast new identifiers keep
+    appearing (its Heaps' law exponent). Packages are listed by compressed
+    bits per line next to their lines and files.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight density
+
+      shannon-insight density --by exponent -n 30
+
+      shannon-insight density --json
+    """
+    from ..hygiene import load_sources
+    from ..signals.information_density import analyze_density
+    from ._common import resolve_settings
+
+    if by not in _SORT_KEYS:
+        console.print(f"[red]Error:[/red] --by must be one of: {', '.join(_SORT_KEYS)}")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    report = analyze_density(sources.syntax, sources.content)
+    keys = {
+        "density": lambda p: -p.information_density,
+        "ratio": lambda p: -p.compression_ratio,
+        "lines": lambda p: -p.lines,
+        "exponent": lambda p: -(p.vocabulary_exponent or 0.0),
+    }
+    report.packages.sort(key=lambda p: (keys[by](p), p.package))
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    exponent = report.vocabulary_exponent
+    console.print()
+    console.print("[bold cyan]INFORMATION DENSITY[/bold cyan]")
+    console.print(
+        f"  {report.files} files, {report.lines:,} non-blank lines, {report.raw_bytes:,} bytes"
+    )
+    console.print(
+        f"  Compression ratio      {report.compression_ratio:.3f} ({report.band}; "
+        "source code is typically 0.20-0.45)"
+    )
+    console.print(f"  Cross-file redundancy  {report.cross_file_redundancy:.1%}")
+    console.print(f"  Bits per line          {report.information_density:.1f}")
+    console.print(
+        "  Vocabulary exponent    " + (f"{exponent:.3f}" if exponent is not None else "--")
+    )
+    if report.curve:
+        read, distinct = report.curve[-1]
+        console.print(
+            f"  Vocabulary growth      {_sparkline([v for _, v in report.curve])} "
+            f"{distinct:,} distinct of {read:,} identifiers"
+        )
+    console.print()
+
+    console.print(f"[bold cyan]PACKAGES[/bold cyan] -- by {by}")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Files", justify="right")
+    table.add_column("Lines", justify="right")
+    table.add_column("Ratio", justify="right")
+    table.add_column("Bits/line", justify="right")
+    table.add_column("Identifiers", justify="right")
+    table.add_column("Exponent", justify="right")
+    for package in report.packages[:top]:
+        table.add_row(
+            package.package,
+            str(package.files),
+            str(package.lines),
+            f"{package.compression_ratio:.3f}",
+            f"{package.information_density:.1f}",
+            f"{package.distinct_identifiers}/{package.identifiers}",
+            f"{package.vocabulary_exponent:.3f}"
+            if package.vocabulary_exponent is not None
+            else "--",
+        )
+    console.print(table)
+    console.print()
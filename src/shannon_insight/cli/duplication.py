@@ -0,0 +1,124 @@
+"""Duplication CLI command -- copied code as clone classes, and its share per package."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def duplication(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        15,
+        "--top",
+        "-n",
+        help="Clone classes and packages to list",
+        min=1,
+        max=1000,
+    ),
+    min_tokens: int = typer.Option(
+        0,
+        "--min-tokens",
+        help="Shortest clone in tokens (default: clone_min_tokens, 50)",
+        min=0,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Find duplicated code: token-based type-1 and type-2 clone classes.
+
+    Runs of at least clone_min_tokens tokens copied into two or more places
+    are grouped into clone classes: type-1 copies are identical, type-2
+    copies differ only in identifiers and literals. Comments, layout and
+    import lines are ignored. Packages are listed by the share of their
+    lines that lie inside a copy.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight duplication
+
+      shannon-insight duplication --min-tokens 100 -n 30
+
+      shannon-insight duplication --json
+    """
+    from ..hygiene import load_sources
+    from ..semantics.roles import classify_role
+    from ..signals.duplication import analyze_duplication
+    from ._common import resolve_settings
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    settings = resolve_settings(config=obj.get("config"))
+    sources = load_sources(root, settings)
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    roles = {path: classify_role(fs, str(root)).value for path, fs in sources.syntax.items()}
+    report = analyze_duplication(
+        sources.syntax,
+        sources.content,
+        min_tokens or settings.thresholds.clone_min_tokens,
+        roles,
+    )
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    console.print("[bold cyan]DUPLICATION[/bold cyan]")
+    console.print(
+        f"  {report.duplicated_lines:,} of {report.lines:,} lines "
+        f"({report.duplication_ratio:.1%}) lie in {len(report.classes)} clone classes"
+    )
+    console.print()
+
+    if report.classes:
+        console.print("[bold cyan]CLONE CLASSES[/bold cyan] -- most duplicated tokens first")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Type", justify="right")
+        table.add_column("Tokens", justify="right")
+        table.add_column("Lines", justify="right")
+        table.add_column("Copies", justify="right")
+        table.add_column("Locations", min_width=30)
+        for clone in report.classes[:top]:
+            locations = ", ".join(f.location for f in clone.fragments[:3])
+            if len(clone.fragments) > 3:
+                locations += f" +{len(clone.fragments) - 3}"
+            table.add_row(
+                str(clone.clone_type),
+                str(clone.tokens),
+                str(clone.lines),
+                str(len(clone.fragments)),
+                locations,
+            )
+        console.print(table)
+        console.print()
+
+    console.print("[bold cyan]PACKAGES[/bold cyan] -- by duplicated share")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Files", justify="right")
+    table.add_column("Lines", justify="right")
+    table.add_column("Duplicated", justify="right")
+    table.add_column("Share", justify="right")
+    for package in report.packages[:top]:
+        table.add_row(
+            package.package,
+            str(package.files),
+            str(package.lines),
+            str(package.duplicated_lines),
+            f"{package.duplication:.1%}",
+        )
+    console.print(table)
+    console.print()
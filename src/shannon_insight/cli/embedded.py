@@ -0,0 +1,124 @@
+"""Embedded CLI command -- SQL and HTML inside string literals."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def embedded(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        15,
+        "--top",
+        "-n",
+        help="Entries per table",
+        min=1,
+        max=1000,
+    ),
+    language: Optional[str] = typer.Option(
+        None,
+        "--language",
+        "-l",
+        help="Only snippets of this language: sql, html",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Measure SQL and HTML embedded in string literals of other languages.
+
+    Queries in Go raw strings or Python triple-quoted strings and markup in
+    template literals or heredocs are measured like SQL files and
+    templates: statement complexity and tables for SQL, element nesting
+    and template branches for HTML. Each function holding snippets is
+    listed with its own complexity next to the complexity embedded in it.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight embedded
+
+      shannon-insight embedded --language sql -n 30
+
+      shannon-insight embedded --json
+    """
+    from ..hygiene import load_sources
+    from ..scanning.embedded import EMBEDDED_LANGUAGES
+    from ..signals.embedded_code import collect_embedded
+    from ._common import resolve_settings
+
+    if language is not None and language not in EMBEDDED_LANGUAGES:
+        console.print(
+            f"[red]Error:[/red] --language must be one of: {', '.join(EMBEDDED_LANGUAGES)}"
+        )
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    report = collect_embedded(sources.syntax, sources.content, language)
+    if not report.snippets:
+        console.print(f"[red]Error:[/red] no embedded SQL or HTML found in {root}")
+        raise typer.Exit(2)
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    counts = ", ".join(f"{n} {lang.upper()}" for lang, n in report.by_language().items() if n)
+    console.print(
+        f"[bold cyan]EMBEDDED[/bold cyan] -- {counts} snippets "
+        f"in {len(report.functions)} functions"
+    )
+    console.print()
+
+    console.print("[bold cyan]HOST FUNCTIONS[/bold cyan] -- most embedded complexity first")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Function", min_width=24)
+    table.add_column("Snippets", justify="right")
+    table.add_column("Embedded", justify="right")
+    table.add_column("Own", justify="right")
+    for fn in report.functions[:top]:
+        embedded_complexity = str(fn.embedded_complexity)
+        if fn.embedded_complexity > fn.complexity:
+            embedded_complexity = f"[yellow]{embedded_complexity}[/yellow]"
+        table.add_row(
+            fn.label,
+            f"{fn.snippets} {'/'.join(fn.languages)}",
+            embedded_complexity,
+            str(fn.complexity),
+        )
+    console.print(table)
+    console.print()
+
+    console.print("[bold cyan]SNIPPETS[/bold cyan] -- most complex first")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Snippet", min_width=24)
+    table.add_column("Language")
+    table.add_column("Complexity", justify="right")
+    table.add_column("Lines", justify="right")
+    table.add_column("Tables / depth")
+    for snippet in report.snippets[:top]:
+        if snippet.language == "sql":
+            detail = ", ".join(snippet.tables)
+        else:
+            detail = f"depth {snippet.depth}"
+        table.add_row(
+            snippet.label,
+            snippet.language,
+            str(snippet.complexity),
+            str(snippet.lines),
+            detail,
+        )
+    console.print(table)
+    console.print()
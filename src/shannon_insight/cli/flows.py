@@ -0,0 +1,128 @@
+"""Flows CLI command -- service-to-service request flows and their diagram."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def flows(
+    ctx: typer.Context,
+    fmt: Optional[str] = typer.Option(
+        None,
+        "--format",
+        "-f",
+        help="Export a diagram instead of the table: mermaid or dot (Graphviz)",
+    ),
+    output: Optional[Path] = typer.Option(
+        None,
+        "--output",
+        "-o",
+        help="File to write the diagram to (default: stdout)",
+    ),
+    top: int = typer.Option(
+        30,
+        "--top",
+        "-n",
+        help="Edges to list",
+        min=1,
+        max=1000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Map request flows between the services of a monorepo.
+
+    Outbound HTTP calls (URL path literals) and gRPC stubs in one service
+    are matched to the routes and gRPC servers another service declares,
+    giving a service-to-service call map with a call count and the busiest
+    endpoints per edge. Services are found as in the c4 command: every
+    directory with a build manifest. Export the map as Mermaid or Graphviz
+    for on-call runbooks, and regenerate it in CI to keep them current.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight flows
+
+      shannon-insight flows -f mermaid -o docs/runbooks/flows.mmd
+
+      shannon-insight flows -f dot | dot -Tsvg > flows.svg
+
+      shannon-insight flows --json
+    """
+    from ..architecture.flows import FLOW_FORMATS, build_flows, render
+    from ..hygiene import load_sources
+    from ._common import resolve_settings
+
+    if fmt is not None and fmt not in FLOW_FORMATS:
+        console.print(f"[red]Error:[/red] --format must be one of: {', '.join(FLOW_FORMATS)}")
+        raise typer.Exit(2)
+    if output is not None and fmt is None:
+        console.print("[red]Error:[/red] --output writes a diagram; choose one with --format")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    flow_map = build_flows(sources)
+    edges = flow_map.edges()
+    if json_output:
+        print(json.dumps(flow_map.to_dict(), indent=2))
+        return
+    if fmt is not None:
+        text = render(flow_map, fmt)
+        if output is None:
+            print(text, end="")
+            return
+        output.write_text(text, encoding="utf-8")
+        console.print(
+            f"[green]Wrote[/green] {output} -- {len(edges)} flows between "
+            f"{len(flow_map.containers)} services"
+        )
+        return
+
+    console.print()
+    console.print(
+        f"[bold cyan]REQUEST FLOWS[/bold cyan] -- {len(flow_map.containers)} services, "
+        f"{len(flow_map.endpoints)} endpoints, {len(flow_map.calls)} matched calls"
+    )
+    if not edges:
+        console.print("  [dim]No call from one service matched an endpoint of another.[/dim]")
+    else:
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("From", min_width=12)
+        table.add_column("To", min_width=12)
+        table.add_column("Protocol")
+        table.add_column("Calls", justify="right")
+        table.add_column("Files", justify="right")
+        table.add_column("Busiest endpoints")
+        for edge in edges[:top]:
+            table.add_row(
+                flow_map.name_of(edge.source),
+                flow_map.name_of(edge.target),
+                edge.protocol,
+                str(edge.calls),
+                str(len(edge.files)),
+                ", ".join(f"{name} ({n})" for name, n in edge.busiest()),
+            )
+        console.print(table)
+    if flow_map.ambiguous:
+        console.print(
+            f"  [dim]{flow_map.ambiguous} calls matched endpoints of several services "
+            "and were left out[/dim]"
+        )
+    console.print()
@@ -0,0 +1,120 @@
+"""Functions CLI command -- how function size is spread within each package."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+_SORT_KEYS = ("gini", "share", "lines", "functions")
+
+
+@app.command()
+def functions(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        15,
+        "--top",
+        "-n",
+        help="Packages to list",
+        min=1,
+        max=1000,
+    ),
+    by: str = typer.Option(
+        "gini",
+        "--by",
+        help="Order packages by: gini, share, lines, functions",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Show how evenly code is spread over the functions of each package.
+
+    Per package: the number of functions, the median and 90th percentile
+    function length, the Gini coefficient of function lengths (0 when all
+    functions are the same size, near 1 when one function holds the code),
+    and the longest function with its share of the package's function
+    lines. A package dominated by one 800-line function stands out from one
+    of evenly sized functions with the same total.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight functions
+
+      shannon-insight functions --by share -n 30
+
+      shannon-insight functions --json
+    """
+    from ..hygiene import load_sources
+    from ..signals.function_stats import aggregate_functions
+    from ._common import resolve_settings
+
+    if by not in _SORT_KEYS:
+        console.print(f"[red]Error:[/red] --by must be one of: {', '.join(_SORT_KEYS)}")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    stats = aggregate_functions(
+        (path, syntax, sources.content.get(path, "")) for path, syntax in sources.syntax.items()
+    )
+    if not stats.packages:
+        console.print(f"[red]Error:[/red] no functions found in {root}")
+        raise typer.Exit(2)
+
+    keys = {
+        "gini": lambda p: -p.size_gini,
+        "share": lambda p: -p.longest_share,
+        "lines": lambda p: -p.lines,
+        "functions": lambda p: -p.functions,
+    }
+    packages = sorted(stats.packages.values(), key=lambda p: (keys[by](p), p.package))
+    total = stats.total()
+
+    if json_output:
+        output = {
+            "functions": total.functions,
+            "lines": total.lines,
+            "size_gini": round(total.size_gini, 4),
+            "packages": [p.to_dict() for p in packages[:top]],
+        }
+        print(json.dumps(output, indent=2))
+        return
+
+    console.print()
+    console.print("[bold cyan]FUNCTION SIZES[/bold cyan]")
+    console.print(
+        f"  {total.functions:,} functions, {total.lines:,} lines, "
+        f"median {total.line_digest.quantile(0.5):.0f} lines, Gini {total.size_gini:.2f}"
+    )
+    console.print()
+
+    console.print(f"[bold cyan]PACKAGES[/bold cyan] -- by {by}")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Functions", justify="right")
+    table.add_column("p50", justify="right")
+    table.add_column("p90", justify="right")
+    table.add_column("Gini", justify="right")
+    table.add_column("Longest")
+    table.add_column("Share", justify="right")
+    for package in packages[:top]:
+        table.add_row(
+            package.package or ".",
+            str(package.functions),
+            f"{package.line_digest.quantile(0.5):.0f}",
+            f"{package.line_digest.quantile(0.9):.0f}",
+            f"{package.size_gini:.2f}",
+            f"{package.longest} -- {package.max_lines} lines",
+            f"{package.longest_share:.0%}",
+        )
+    console.print(table)
+    console.print()
@@ -0,0 +1,430 @@
+"""Gate CLI command -- pass/fail quality checks for CI."""
+
+import json
+from dataclasses import replace
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def gate(
+    ctx: typer.Context,
+    fail_on: Optional[str] = typer.Option(
+        None,
+        "--fail-on",
+        help="Fail if findings meet threshold: high | medium | any",
+    ),
+    ratchet: bool = typer.Option(
+        False,
+        "--ratchet",
+        help="Fail only if a file exceeds its own recorded metric ceilings",
+    ),
+    ratchet_file: Optional[Path] = typer.Option(
+        None,
+        "--ratchet-file",
+        help="Ceilings file (default: ratchet_file from config)",
+    ),
+    dry_run: bool = typer.Option(
+        False,
+        "--dry-run",
+        help="Check the ratchet without writing tightened ceilings",
+    ),
+    fast: bool = typer.Option(
+        False,
+        "--fast",
+        help="Ratchet only files changed since the merge base, without a full analysis",
+    ),
+    base: Optional[str] = typer.Option(
+        None,
+        "--base",
+        help="Mainline ref for --fast (default: baseline_branch, origin/HEAD, main, master)",
+    ),
+    budget: Optional[int] = typer.Option(
+        None,
+        "--budget",
+        min=1,
+        help="Time budget in seconds for --fast (default: gate_fast_budget_seconds)",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+    comment: Optional[Path] = typer.Option(
+        None,
+        "--comment",
+        help="Also write the outcome as Markdown, for a pull request comment",
+    ),
+    reset_rollout: Optional[list[str]] = typer.Option(
+        None,
+        "--reset-rollout",
+        help="Restart the warn-only window of a gate_rollout rule (repeatable)",
+    ),
+):
+    """
+    Run the analysis and exit 1 if the quality gate fails.
+
+    With --ratchet, each file's worst values of ratchet_metrics are recorded
+    in the ratchet file (shannon-ratchet.json by default, meant to be
+    committed). A file fails the gate only by exceeding its own ceiling;
+    improved files get tighter ceilings, written back when the gate passes.
+
+    With --fast (for merge queues), only files changed since the merge base
+    with the mainline are re-measured against the recorded ceilings, within a
+    time budget. Fast runs never write the ratchet file and exit 2 if the
+    budget runs out before every changed file is checked.
+
+    A failed check states which metric crossed which threshold, by how much,
+    and the smallest change that would pass it. --comment writes the same as
+    Markdown for CI to post on the pull request.
+
+    Rules in the gate_rollout table (finding types for --fail-on, metrics
+    for --ratchet) only warn for their first days and/or runs, counted in
+    the history store, before they can fail the gate.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight gate --ratchet
+
+      shannon-insight gate --fast --budget 30
+
+      shannon-insight gate --ratchet --dry-run --json
+
+      shannon-insight gate --fail-on high
+
+      shannon-insight gate --ratchet --comment gate.md
+
+      shannon-insight gate --reset-rollout deep_nesting
+    """
+    from ..api import analyze
+    from ..config import load_config
+    from ..gate.ratchet import apply_ratchet, current_values, load_ceilings, save_ceilings
+    from ..gate.rollout import demote_violations, split_findings, warned_rules
+    from ..gate.sensitivity import finding_remedies, ratchet_remedies
+    from .analyze import _check_fail_threshold
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    config_file = obj.get("config")
+
+    settings = load_config(config_file=config_file)
+
+    if reset_rollout:
+        _reset_rollout(root, settings, reset_rollout)
+    if not fail_on and not ratchet and not fast:
+        if reset_rollout:
+            return
+        console.print("[red]Error:[/red] nothing to check. Pass --ratchet and/or --fail-on.")
+        raise typer.Exit(2)
+
+    if fast:
+        _fast_gate(root, settings, ratchet_file, base, budget, fail_on, json_output, comment)
+        return
+
+    result, snapshot = analyze(path=str(root), config_file=config_file, quiet=json_output)
+    rollout = _rollout(root, settings)
+    warned = warned_rules(rollout)
+
+    failed = False
+    doc: dict = {}
+    remedies: list = []
+    warnings: list = []
+
+    if ratchet:
+        budget_path = ratchet_file or root / settings.ratchet_file
+        try:
+            ceilings = load_ceilings(budget_path)
+        except (ValueError, KeyError, json.JSONDecodeError) as e:
+            console.print(f"[red]Error:[/red] cannot read {budget_path}: {e}")
+            raise typer.Exit(2)
+        current = current_values(snapshot.file_signals, settings.ratchet_metrics)
+        outcome = apply_ratchet(ceilings or {}, current)
+        demote_violations(outcome, warned)
+        written = outcome.passed and not dry_run and (ceilings is None or outcome.changed)
+        if written:
+            save_ceilings(budget_path, outcome.ceilings, settings.ratchet_metrics)
+        failed = failed or not outcome.passed
+        found = ratchet_remedies(root, outcome.violations, settings)
+        remedies.extend(found)
+        doc["ratchet"] = {
+            **outcome.to_dict(),
+            "file": str(budget_path),
+            "written": written,
+            "remedies": [r.to_dict() for r in found],
+        }
+        if not json_output:
+            _print_ratchet(outcome, budget_path, created=ceilings is None, written=written)
+            _print_remedies(found)
+
+    if fail_on:
+        enforced, held_back = split_findings(result.findings, warned)
+        exit_code = _check_fail_threshold(replace(result, findings=enforced), fail_on)
+        failed = failed or exit_code != 0
+        found = finding_remedies(enforced, fail_on) if exit_code else []
+        remedies.extend(found)
+        warnings = finding_remedies(held_back, fail_on)
+        doc["fail_on"] = {
+            "threshold": fail_on,
+            "passed": exit_code == 0,
+            "remedies": [r.to_dict() for r in found],
+            "warnings": [r.to_dict() for r in warnings],
+        }
+        if not json_output:
+            _print_remedies(found)
+
+    ratchet_warnings = doc["ratchet"]["warnings"] if ratchet else []
+    if rollout:
+        doc["rollout"] = [s.to_dict() for s in rollout]
+        if not json_output:
+            _print_rollout(rollout, ratchet_warnings, warnings)
+
+    _notify(settings, root, not failed, doc, snapshot.commit_sha)
+    if comment is not None:
+        notes = _rollout_notes(rollout, ratchet_warnings, warnings)
+        _write_comment(comment, not failed, remedies, notes)
+    if json_output:
+        doc["passed"] = not failed
+        print(json.dumps(doc, indent=2))
+    elif not failed:
+        console.print("[green]Gate passed.[/green]")
+
+    if failed:
+        raise typer.Exit(1)
+
+
+def _fast_gate(
+    root: Path,
+    settings,
+    ratchet_file: Optional[Path],
+    base: Optional[str],
+    budget: Optional[int],
+    fail_on: Optional[str],
+    json_output: bool,
+    comment: Optional[Path],
+) -> None:
+    from ..gate.fast import FAST_METRICS, run_fast_gate
+    from ..gate.ratchet import load_ceilings
+    from ..gate.rollout import demote_violations, warned_rules
+    from ..gate.sensitivity import ratchet_remedies
+    from ..server.baseline import detect_mainline
+
+    if fail_on:
+        console.print("[red]Error:[/red] --fail-on needs a full analysis; drop it with --fast.")
+        raise typer.Exit(2)
+    if not any(m in FAST_METRICS for m in settings.ratchet_metrics):
+        console.print(
+            "[red]Error:[/red] none of ratchet_metrics can be measured per file "
+            f"(--fast supports: {', '.join(FAST_METRICS)})."
+        )
+        raise typer.Exit(2)
+
+    budget_path = ratchet_file or root / settings.ratchet_file
+    try:
+        ceilings = load_ceilings(budget_path)
+    except (ValueError, KeyError, json.JSONDecodeError) as e:
+        console.print(f"[red]Error:[/red] cannot read {budget_path}: {e}")
+        raise typer.Exit(2)
+    if ceilings is None:
+        console.print(
+            f"[red]Error:[/red] {budget_path.name} not found. "
+            "Record ceilings with a full `gate --ratchet` run first."
+        )
+        raise typer.Exit(2)
+
+    base_ref = base or settings.baseline_branch or detect_mainline(str(root))
+    if base_ref is None:
+        console.print("[red]Error:[/red] no mainline branch found. Pass --base.")
+        raise typer.Exit(2)
+    try:
+        outcome = run_fast_gate(
+            root, settings, ceilings, base_ref, budget or settings.gate_fast_budget_seconds
+        )
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+    rollout = _rollout(root, settings)
+    demote_violations(outcome.ratchet, warned_rules(rollout))
+
+    remedies = ratchet_remedies(root, outcome.ratchet.violations, settings)
+    doc = {
+        "ratchet": {
+            **outcome.to_dict(),
+            "file": str(budget_path),
+            "remedies": [r.to_dict() for r in remedies],
+        }
+    }
+    if rollout:
+        doc["rollout"] = [s.to_dict() for s in rollout]
+    _notify(settings, root, outcome.passed and outcome.complete, doc)
+    if comment is not None:
+        notes = _rollout_notes(rollout, doc["ratchet"]["warnings"], [])
+        if outcome.unchecked:
+            notes.append(
+                f"Time budget exhausted; {len(outcome.unchecked)} changed files not checked."
+            )
+        _write_comment(comment, outcome.passed, remedies, notes)
+    if json_output:
+        doc["passed"] = outcome.passed
+        print(json.dumps(doc, indent=2))
+    else:
+        _print_fast(outcome, remedies)
+        if rollout:
+            _print_rollout(rollout, doc["ratchet"]["warnings"], [])
+
+    if not outcome.complete:
+        raise typer.Exit(2)
+    if not outcome.passed:
+        raise typer.Exit(1)
+
+
+def _rollout(root: Path, settings) -> list:
+    """Count this run for every gate_rollout rule; where each rule stands."""
+    from ..gate.rollout import parse_rollout, record_run
+    from ..persistence import HistoryDB
+
+    rules = parse_rollout(settings.gate_rollout)
+    if not rules:
+        return []
+    try:
+        with HistoryDB(str(root), url=settings.history_url) as db:
+            return record_run(db.conn, rules)
+    except Exception as e:
+        console.print(f"[red]Error:[/red] cannot track gate_rollout in the history store: {e}")
+        raise typer.Exit(2)
+
+
+def _reset_rollout(root: Path, settings, rules: list[str]) -> None:
+    from ..gate.rollout import reset_rules
+    from ..persistence import HistoryDB
+
+    try:
+        with HistoryDB(str(root), url=settings.history_url) as db:
+            removed = reset_rules(db.conn, rules)
+    except Exception as e:
+        console.print(f"[red]Error resetting rollout:[/red] {e}")
+        raise typer.Exit(1)
+    console.print(f"Reset the warn-only window of {removed} of {len(rules)} rule(s).")
+
+
+def _rollout_notes(rollout: list, ratchet_warnings: list, finding_warnings: list) -> list[str]:
+    """One line per warn-only rule that would have failed the gate."""
+    counts: dict[str, int] = {}
+    for v in ratchet_warnings:
+        counts[v["metric"]] = counts.get(v["metric"], 0) + 1
+    for r in finding_warnings:
+        counts[r.metric] = counts.get(r.metric, 0) + 1
+    return [
+        f"Warn-only: {s.rule.rule} would fail the gate {counts[s.rule.rule]} time(s); "
+        f"{s.describe()}."
+        for s in rollout
+        if s.warn_only and counts.get(s.rule.rule)
+    ]
+
+
+def _print_rollout(rollout: list, ratchet_warnings: list, finding_warnings: list) -> None:
+    console.print("[bold cyan]ROLLOUT[/bold cyan]")
+    for s in rollout:
+        if s.warn_only:
+            state = f"[yellow]warn-only[/yellow], {s.describe()}"
+        else:
+            state = "[green]enforced[/green]"
+        console.print(f"  {s.rule.rule}: {state} (run {s.runs})")
+    for line in _rollout_notes(rollout, ratchet_warnings, finding_warnings):
+        console.print(f"  [yellow]{line}[/yellow]")
+    for v in ratchet_warnings:
+        console.print(
+            f"    {v['path']}: {v['metric']} {v['value']:g} over ceiling {v['ceiling']:g}"
+        )
+    for r in finding_warnings:
+        console.print(f"    {r.path}: {r.metric} severity {r.value:g}")
+    console.print()
+
+
+def _notify(
+    settings, root: Path, passed: bool, checks: dict, commit_sha: Optional[str] = None
+) -> None:
+    """Send the gate outcome to the webhooks subscribed to gate events."""
+    from ..webhooks import deliver, gate_event, parse_webhooks
+
+    hooks = parse_webhooks(settings.webhooks)
+    if hooks:
+        deliver(hooks, [gate_event(passed, checks, root.name, commit_sha)])
+
+
+def _write_comment(path: Path, passed: bool, remedies: list, notes: Optional[list] = None) -> None:
+    from ..gate.sensitivity import markdown_comment
+
+    path.write_text(markdown_comment(passed, remedies, notes), encoding="utf-8")
+
+
+def _print_remedies(remedies) -> None:
+    if not remedies:
+        return
+    console.print("[bold]To pass:[/bold]")
+    for r in remedies:
+        console.print(f"  {r.path}: {r.describe()}")
+    console.print()
+
+
+def _print_fast(outcome, remedies) -> None:
+    console.print()
+    console.print(
+        f"[bold cyan]FAST GATE[/bold cyan] -- {len(outcome.changed)} files changed since "
+        f"{outcome.base}, {len(outcome.ratchet.violations)} violations "
+        f"[dim]({outcome.elapsed:.1f}s)[/dim]"
+    )
+    if outcome.ratchet.violations:
+        _print_violations(outcome.ratchet.violations)
+        _print_remedies(remedies)
+    if outcome.skipped_metrics:
+        console.print(
+            f"[dim]Not checked without a full analysis: {', '.join(outcome.skipped_metrics)}[/dim]"
+        )
+    if outcome.unchecked:
+        console.print(
+            f"[yellow]Time budget exhausted; {len(outcome.unchecked)} changed files "
+            "not checked.[/yellow]"
+        )
+    elif outcome.passed:
+        console.print("[green]Gate passed.[/green]")
+    console.print()
+
+
+def _print_violations(violations) -> None:
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("File", min_width=24)
+    table.add_column("Metric")
+    table.add_column("Ceiling", justify="right")
+    table.add_column("Now", justify="right")
+    for v in violations:
+        table.add_row(v.path, v.metric, f"{v.ceiling:g}", f"[red]{v.value:g}[/red]")
+    console.print(table)
+
+
+def _print_ratchet(outcome, budget_path: Path, created: bool, written: bool) -> None:
+    console.print()
+    if created:
+        state = "recorded" if written else "not written (dry run)"
+        console.print(
+            f"[bold cyan]RATCHET[/bold cyan] -- ceilings for {len(outcome.added)} files {state} "
+            f"in {budget_path.name}"
+        )
+        return
+
+    console.print(
+        f"[bold cyan]RATCHET[/bold cyan] -- {len(outcome.violations)} violations, "
+        f"{len(outcome.tightened)} tightened, {len(outcome.added)} new files, "
+        f"{len(outcome.removed)} removed"
+    )
+    if outcome.violations:
+        _print_violations(outcome.violations)
+    elif outcome.changed:
+        verb = "Updated" if written else "Would update"
+        console.print(f"[dim]{verb} {budget_path.name}; commit it to lock in the gains.[/dim]")
+    console.print()
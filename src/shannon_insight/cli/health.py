@@ -28,6 +28,25 @@ _HEALTH_METRICS = {
     "modularity": ("Module separation", "higher_better", "boundaries"),
     "fiedler_value": ("Connectivity", "higher_better", "connectivity"),
     "total_edges": ("Dependencies", "neutral", "dependencies"),
+    "comment_debt_count": ("TODO/FIXME comments", "lower_better", "comment debt"),
+    "comment_debt_median_age_days": ("Median TODO age (days)", "lower_better", "comment debt"),
+    "deprecated_call_sites": ("Deprecated API call sites", "lower_better", "deprecations"),
+    "format_drift_files": ("Files off formatter style", "lower_better", "formatting"),
+    "corpus_compression_ratio": ("Compression ratio", "neutral", "information density"),
+    "cross_file_redundancy": ("Cross-file redundancy", "lower_better", "information density"),
+    "information_density": ("Compressed bits per line", "neutral", "information density"),
+    "vocabulary_exponent": ("Vocabulary growth exponent", "neutral", "information density"),
+    "function_complexity_p50": ("Median function complexity", "lower_better", "functions"),
+    "function_complexity_p90": ("90th pct function complexity", "lower_better", "functions"),
+    "function_lines_p90": ("90th pct function length", "lower_better", "functions"),
+    "duplication_ratio": ("Duplicated lines", "lower_better", "duplication"),
+    "clone_classes": ("Clone classes", "lower_better", "duplication"),
+    "error_hygiene_issues": ("Error hygiene issues", "lower_better", "error handling"),
+    "line_coverage": ("Line coverage", "higher_better", "coverage"),
+    "untested_complex_functions": ("Complex functions untested", "lower_better", "coverage"),
+    "api_surface": ("Exported identifiers", "neutral", "API surface"),
+    "magic_numbers": ("Magic numbers", "lower_better", "literals"),
+    "repeated_literals": ("Repeated literals", "lower_better", "literals"),
 }
 
 
@@ -66,9 +85,7 @@ def health(
     from ..persistence.queries import HistoryQuery
 
     resolved = ctx.obj.get("path", Path.cwd()).resolve()
-    db_path = resolved / ".shannon" / "history.db"
-
-    if not db_path.exists():
+    if not HistoryDB(str(resolved)).exists():
         console.print(
             "[yellow]No history found.[/yellow] "
             "Run [bold]shannon-insight --save[/bold] first to create snapshots."
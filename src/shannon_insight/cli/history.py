@@ -1,17 +1,22 @@
-"""History CLI command -- list past analysis snapshots."""
+"""History CLI commands -- list, prune, export, import and compare analysis snapshots."""
 
 import json
 from pathlib import Path
+from typing import Optional
 
 import typer
 
 from ..persistence import HistoryDB
 from ..persistence.reader import list_snapshots
-from . import app
 from ._common import console
 
+history_app: typer.Typer = typer.Typer(
+    help="List, prune, export, import and compare past analysis snapshots",
+    rich_markup_mode="rich",
+)
 
-@app.command()
+
+@history_app.callback(invoke_without_command=True)
 def history(
     ctx: typer.Context,
     limit: int = typer.Option(
@@ -41,11 +46,13 @@ def history(
       shannon-insight history --json
 
       shannon-insight history --limit 5
+
+      shannon-insight history prune --dry-run
     """
+    if ctx.invoked_subcommand is not None:
+        return
     resolved = ctx.obj.get("path", Path.cwd()).resolve()
-    db_path = resolved / ".shannon" / "history.db"
-
-    if not db_path.exists():
+    if not HistoryDB(str(resolved)).exists():
         console.print(
             "[yellow]No history found.[/yellow] "
             "Run [bold]shannon-insight --save[/bold] first to create a snapshot."
@@ -72,6 +79,331 @@ def history(
         raise typer.Exit(1)
 
 
+@history_app.command()
+def prune(
+    ctx: typer.Context,
+    keep_all_days: Optional[int] = typer.Option(
+        None, "--keep-all-days", help="Keep every snapshot younger than this", min=0
+    ),
+    keep_daily_days: Optional[int] = typer.Option(
+        None, "--keep-daily-days", help="Then the last snapshot of each day", min=0
+    ),
+    keep_weekly_days: Optional[int] = typer.Option(
+        None, "--keep-weekly-days", help="Then the last snapshot of each week", min=0
+    ),
+    dry_run: bool = typer.Option(
+        False,
+        "--dry-run",
+        help="Report what would be removed without changing anything",
+    ),
+    vacuum: bool = typer.Option(
+        True,
+        "--vacuum/--no-vacuum",
+        help="Give freed space back to the filesystem (SQLite)",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Thin out old snapshots according to the retention policy.
+
+    Keeps every snapshot from the last day, then one per day for 90 days,
+    then one per week for 2 years (see the history_keep_* settings). The
+    baseline and the newest snapshot are always kept. Signals of removed
+    snapshots are rolled up into per-period aggregates first.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight history prune --dry-run
+
+      shannon-insight history prune --keep-daily-days 30 --json
+    """
+    from ..persistence.retention import RetentionPolicy, prune_history
+
+    overrides = {
+        f"history_{name}": value
+        for name, value in (
+            ("keep_all_days", keep_all_days),
+            ("keep_daily_days", keep_daily_days),
+            ("keep_weekly_days", keep_weekly_days),
+        )
+        if value is not None
+    }
+    settings = _settings(ctx, **overrides)
+    db = HistoryDB(str(_root(ctx)), url=settings.history_url)
+    if not db.exists():
+        console.print("[yellow]No history found.[/yellow]")
+        raise typer.Exit(0)
+
+    try:
+        with db:
+            result = prune_history(
+                db, RetentionPolicy.from_config(settings), dry_run=dry_run, vacuum=vacuum
+            )
+    except Exception as e:
+        console.print(f"[red]Error pruning history:[/red] {e}")
+        raise typer.Exit(1)
+
+    if json_output:
+        print(json.dumps(result.to_dict(), indent=2))
+        return
+    verb = "Would remove" if dry_run else "Removed"
+    console.print(f"{verb} [bold]{len(result.removed)}[/bold] snapshot(s), keeping {result.kept}.")
+    if result.rollup_rows:
+        console.print(f"[dim]Rolled up {result.rollup_rows} signal aggregate(s).[/dim]")
+
+
+_URL_HELP = "'sqlite' (.shannon/history.db) or a postgresql:// URL [default: history_url]"
+
+
+@history_app.command("export")
+def export_archive(
+    ctx: typer.Context,
+    output: Path = typer.Argument(..., help="Archive to write (conventionally *.jsonl.gz)"),
+    anonymize: bool = typer.Option(
+        False,
+        "--anonymize",
+        help="Hash paths and commits, drop finding text, for sharing",
+    ),
+    url: Optional[str] = typer.Option(None, "--url", help=_URL_HELP),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Export the snapshot history into a portable archive.
+
+    The archive (gzip-compressed JSON Lines) can be imported into any
+    history store, SQLite or Postgres, including one that already has
+    history.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight history export history.jsonl.gz --url sqlite
+
+      shannon-insight history export shared.jsonl.gz --anonymize
+    """
+    from ..persistence.archive import export_history
+
+    db = _history_db(ctx, url)
+    if not db.exists():
+        console.print("[yellow]No history found.[/yellow]")
+        raise typer.Exit(0)
+    try:
+        with db:
+            stats = export_history(db, output, anonymize=anonymize)
+    except Exception as e:
+        console.print(f"[red]Error exporting history:[/red] {e}")
+        raise typer.Exit(1)
+
+    if json_output:
+        print(json.dumps(stats.to_dict(), indent=2))
+        return
+    console.print(
+        f"[green]Exported[/green] {stats.snapshots} snapshot(s) to {output} "
+        f"({output.stat().st_size / 1024:.0f} KiB)"
+    )
+
+
+@history_app.command("import")
+def import_archive(
+    ctx: typer.Context,
+    archive: Path = typer.Argument(..., help="Archive written by history export", exists=True),
+    rewrite: Optional[list[str]] = typer.Option(
+        None,
+        "--rewrite",
+        help="Move paths under OLD to NEW (OLD=NEW, repeatable; empty OLD matches all)",
+    ),
+    url: Optional[str] = typer.Option(None, "--url", help=_URL_HELP),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Merge a history archive into this project's history store.
+
+    Imported snapshots get new ids; ones already present (same commit and
+    timestamp) are skipped. Use --rewrite when the files moved, e.g. after
+    splitting a repository.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight history import history.jsonl.gz --url postgresql://db/shannon
+
+      shannon-insight history import monorepo.jsonl.gz --rewrite services/billing=
+    """
+    from ..persistence.archive import ArchiveError, import_history, path_rewriter
+
+    prefixes = []
+    for pair in rewrite or []:
+        old, sep, new = pair.partition("=")
+        if not sep:
+            console.print(f"[red]Error:[/red] --rewrite expects OLD=NEW, got {pair!r}")
+            raise typer.Exit(2)
+        prefixes.append((old, new))
+
+    rewriter = path_rewriter(prefixes) if prefixes else None
+    try:
+        with _history_db(ctx, url) as db:
+            stats = import_history(db, archive, rewrite=rewriter)
+    except ArchiveError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+    except Exception as e:
+        console.print(f"[red]Error importing history:[/red] {e}")
+        raise typer.Exit(1)
+
+    if json_output:
+        print(json.dumps(stats.to_dict(), indent=2))
+        return
+    console.print(f"[green]Imported[/green] {stats.snapshots} snapshot(s) from {archive}.")
+    if stats.skipped:
+        console.print(f"[dim]{stats.skipped} snapshot(s) were already present.[/dim]")
+
+
+@history_app.command("grammar-impact")
+def grammar_impact(
+    ctx: typer.Context,
+    before_id: Optional[int] = typer.Option(
+        None, "--from", help="Snapshot parsed with the old grammars [default: last upgrade]"
+    ),
+    after_id: Optional[int] = typer.Option(
+        None, "--to", help="Snapshot parsed with the new grammars"
+    ),
+    top: int = typer.Option(10, "--top", help="Files to list per language", min=0),
+    url: Optional[str] = typer.Option(None, "--url", help=_URL_HELP),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Show which metric changes came from a parser upgrade, not the code.
+
+    Compares two snapshots whose grammar versions differ -- by default the
+    most recent consecutive pair -- and reports, per upgraded language, how
+    the parsed signals moved in files whose code did not change.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight history grammar-impact
+
+      shannon-insight history grammar-impact --from 41 --to 42 --json
+    """
+    from ..persistence.grammar_impact import grammar_impact as measure
+    from ..persistence.grammar_impact import latest_upgrade, recorded_grammars
+
+    if (before_id is None) != (after_id is None):
+        console.print("[red]Error:[/red] --from and --to must be given together")
+        raise typer.Exit(2)
+
+    db = _history_db(ctx, url)
+    if not db.exists():
+        console.print("[yellow]No history found.[/yellow]")
+        raise typer.Exit(0)
+
+    try:
+        with db:
+            if before_id is None:
+                pair = latest_upgrade(recorded_grammars(db.conn))
+                if pair is None:
+                    console.print(
+                        "[yellow]No grammar change recorded between consecutive snapshots."
+                        "[/yellow]"
+                    )
+                    raise typer.Exit(0)
+                before_id, after_id = pair
+            impact = measure(db.conn, _root(ctx), before_id, after_id)
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+    except typer.Exit:
+        raise
+    except Exception as e:
+        console.print(f"[red]Error reading history:[/red] {e}")
+        raise typer.Exit(1)
+
+    if json_output:
+        print(json.dumps(impact.to_dict(), indent=2))
+        return
+    _grammar_impact_rich(impact, top)
+
+
+def _grammar_impact_rich(impact, top: int) -> None:
+    from rich.table import Table
+
+    console.print()
+    console.print(f"[bold]Grammar impact[/bold]  snapshot {impact.before_id} -> {impact.after_id}")
+    if impact.config_changed:
+        console.print("[yellow]The configuration changed too and may explain some shifts.[/yellow]")
+    if not impact.git_diff:
+        console.print("[dim]Commits not comparable; unchanged files guessed by line count.[/dim]")
+    if not impact.languages:
+        console.print("[green]No language changed parser between these snapshots.[/green]")
+        return
+
+    for lang in impact.languages:
+        console.print()
+        console.print(f"[bold cyan]{lang.language}[/bold cyan]  {lang.before} -> {lang.after}")
+        console.print(
+            f"  {len(lang.files)} of {lang.unchanged} unchanged file(s) moved; "
+            f"{lang.code_changed} changed file(s) not attributed"
+        )
+        totals = lang.signal_totals()
+        if not totals:
+            continue
+        table = Table(show_lines=False, pad_edge=True)
+        table.add_column("Signal", style="bold")
+        table.add_column("Files", justify="right")
+        table.add_column("Mean change", justify="right", style="yellow")
+        for signal, (count, mean) in totals.items():
+            table.add_row(signal, str(count), f"{mean:+.2f}")
+        console.print(table)
+        for shift in lang.files[:top]:
+            moves = ", ".join(
+                f"{signal} {old:g}->{new:g}" for signal, (old, new) in shift.shifts.items()
+            )
+            console.print(f"  [dim]{shift.path}[/dim]  {moves}")
+        if len(lang.files) > top:
+            console.print(f"  [dim]... and {len(lang.files) - top} more[/dim]")
+    console.print()
+
+
+def _root(ctx: typer.Context) -> Path:
+    return ctx.obj.get("path", Path.cwd()).resolve()
+
+
+def _settings(ctx: typer.Context, **overrides):
+    """Settings of the project, with the top-level --config applied."""
+    from ..config import load_config
+
+    try:
+        return load_config(config_file=ctx.obj.get("config"), project_dir=_root(ctx), **overrides)
+    except Exception as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+
+
+def _history_db(ctx: typer.Context, url: Optional[str]) -> HistoryDB:
+    """The store named by --url, else the configured one."""
+    if url is None:
+        url = _settings(ctx).history_url
+    elif url == "sqlite":
+        url = ""
+    elif not url.startswith(("postgres://", "postgresql://")):
+        console.print("[red]Error:[/red] --url must be 'sqlite' or a postgresql:// URL")
+        raise typer.Exit(2)
+    return HistoryDB(str(_root(ctx)), url=url)
+
+
 def _output_json(snapshots, baseline_id=None):
     """Machine-readable JSON output."""
     for s in snapshots:
@@ -0,0 +1,1259 @@
+"""Hygiene CLI commands -- repo-wide consistency reports over source text."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from ._common import console, resolve_settings
+
+hygiene_app: typer.Typer = typer.Typer(
+    help=(
+        "Code hygiene reports (idioms, naming, spelling, TODOs, formatting, license headers,"
+        " fixtures, ...)"
+    ),
+    no_args_is_help=True,
+    rich_markup_mode="rich",
+)
+
+
+def _settings(ctx: typer.Context):
+    return resolve_settings(config=(ctx.obj or {}).get("config"))
+
+
+def _load(ctx: typer.Context, settings=None):
+    """Load sources for the root/config given to the top-level command."""
+    from ..hygiene import load_sources
+
+    root = (ctx.obj or {}).get("path", Path.cwd()).resolve()
+    return load_sources(root, settings or _settings(ctx))
+
+
+@hygiene_app.command()
+def idioms(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        10,
+        "--top",
+        "-n",
+        help="Number of most divergent packages to show",
+        min=1,
+        max=500,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Report packages whose idioms diverge most from the repo-dominant style.
+
+    Clusters error handling, Go receiver naming, constructor prefixes and
+    function casing into variants, takes the most common variant per
+    language as the norm, and ranks packages by their deviating share.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene idioms
+
+      shannon-insight /path/to/repo hygiene idioms --json
+    """
+    from ..hygiene.idioms import analyze_idioms
+
+    report = analyze_idioms(_load(ctx))
+    report.packages = report.packages[:top]
+
+    if json_output:
+        print(json.dumps(report.to_dict(), indent=2))
+        return
+
+    if not report.norms:
+        console.print("[yellow]Not enough idiom occurrences to establish a norm.[/yellow]")
+        return
+
+    console.print()
+    console.print("[bold cyan]IDIOM NORMS[/bold cyan]")
+    norms = Table(show_header=True, pad_edge=True)
+    norms.add_column("Dimension")
+    norms.add_column("Language")
+    norms.add_column("Dominant")
+    norms.add_column("Consistency", justify="right")
+    for norm in report.norms:
+        norms.add_row(norm.dimension, norm.language, norm.dominant, f"{norm.consistency:.0%}")
+    console.print(norms)
+
+    divergent = [p for p in report.packages if p.deviations]
+    console.print()
+    if not divergent:
+        console.print("[green]Every package follows the dominant idioms.[/green]")
+        return
+
+    console.print("[bold cyan]MOST DIVERGENT PACKAGES[/bold cyan]")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Divergence", justify="right")
+    table.add_column("Deviations")
+    for pkg in divergent:
+        details = ", ".join(
+            f"{d.dimension}: {d.variant} x{d.count} (norm {d.dominant})"
+            for d in pkg.deviations[:3]
+        )
+        table.add_row(pkg.package, f"{pkg.divergence:.0%}", details)
+    console.print(table)
+    console.print()
+
+
+@hygiene_app.command()
+def naming(
+    ctx: typer.Context,
+    rule: Optional[str] = typer.Option(
+        None,
+        "--rule",
+        "-r",
+        help="Only show one rule: case | abbreviation | stutter",
+    ),
+    limit: int = typer.Option(
+        50,
+        "--limit",
+        "-n",
+        help="Maximum issues to show",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Check naming conventions (low-severity style rules).
+
+    Per-language casing for functions and types, initialism spelling
+    (ID vs Id), and package-name stutter (repository.UserRepository).
+    Rules are configured with [naming_rules.<language>] tables.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene naming
+
+      shannon-insight hygiene naming --rule stutter --json
+    """
+    from ..hygiene.naming import analyze_naming
+
+    settings = _settings(ctx)
+    try:
+        report = analyze_naming(_load(ctx, settings), settings.naming_rules)
+    except ValueError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+
+    if rule is not None:
+        report.issues = [i for i in report.issues if i.rule == rule]
+    report.issues = report.issues[:limit]
+
+    if json_output:
+        print(json.dumps(report.to_dict(), indent=2))
+        return
+
+    console.print()
+    if not report.issues:
+        console.print(f"[green]No naming issues[/green] across {report.identifiers} identifiers.")
+        return
+
+    summary = ", ".join(f"{n} {r}" for r, n in report.counts().items())
+    console.print(f"[bold cyan]NAMING[/bold cyan] -- {summary} ({report.identifiers} checked)")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Location", min_width=24)
+    table.add_column("Rule")
+    table.add_column("Message")
+    for issue in report.issues:
+        table.add_row(f"{issue.path}:{issue.line}", issue.rule, issue.message)
+    console.print(table)
+    console.print()
+
+
+@hygiene_app.command()
+def glossary(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        30,
+        "--top",
+        "-n",
+        help="Number of domain terms to show",
+        min=1,
+        max=1000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Extract the domain vocabulary and report mixed synonyms.
+
+    Clusters identifier words by abbreviation (org / organization) and
+    spelling (organisation / organization), then lists packages that use
+    several variants of the same term.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene glossary
+
+      shannon-insight hygiene glossary --top 100 --json
+    """
+    from ..hygiene.glossary import analyze_glossary
+
+    report = analyze_glossary(_load(ctx))
+    report.terms = report.terms[:top]
+
+    if json_output:
+        print(json.dumps(report.to_dict(), indent=2))
+        return
+
+    console.print()
+    console.print(f"[bold cyan]GLOSSARY[/bold cyan] -- top {len(report.terms)} domain terms")
+    terms = Table(show_header=True, pad_edge=True)
+    terms.add_column("Term", min_width=16)
+    terms.add_column("Uses", justify="right")
+    terms.add_column("Variants")
+    for term in report.terms:
+        variants = ", ".join(f"{v} ({n})" for v, n in term.variants.items())
+        terms.add_row(term.canonical, str(term.count), variants)
+    console.print(terms)
+
+    console.print()
+    if not report.conflicts:
+        console.print("[green]No package mixes synonyms of the same term.[/green]")
+        return
+
+    console.print("[bold cyan]MIXED SYNONYMS[/bold cyan]")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Term")
+    table.add_column("Variants")
+    for conflict in report.conflicts:
+        variants = ", ".join(
+            f"{v} x{n} ({conflict.examples[v]})" for v, n in conflict.variants.items()
+        )
+        table.add_row(conflict.package, conflict.canonical, variants)
+    console.print(table)
+    console.print()
+
+
+@hygiene_app.command()
+def spelling(
+    ctx: typer.Context,
+    kind: Optional[str] = typer.Option(
+        None,
+        "--kind",
+        "-k",
+        help="Only show one kind: api | identifier | comment | string",
+    ),
+    limit: int = typer.Option(
+        50,
+        "--limit",
+        "-n",
+        help="Maximum issues to show",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Spellcheck identifiers, comments and string literals.
+
+    Splits camelCase/snake_case identifiers and matches words against a
+    table of common misspellings. Typos in exported definitions are listed
+    first. Allowlist words with spelling_allowlist in the config file.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene spelling
+
+      shannon-insight hygiene spelling --kind api --json
+    """
+    from ..hygiene.spelling import analyze_spelling
+
+    settings = _settings(ctx)
+    report = analyze_spelling(_load(ctx, settings), settings.spelling_allowlist)
+    if kind is not None:
+        report.issues = [i for i in report.issues if i.kind == kind]
+    report.issues = report.issues[:limit]
+
+    if json_output:
+        print(json.dumps(report.to_dict(), indent=2))
+        return
+
+    console.print()
+    if not report.issues:
+        console.print("[green]No misspellings found.[/green]")
+        return
+
+    summary = ", ".join(f"{n} {k}" for k, n in report.counts().items())
+    console.print(f"[bold cyan]SPELLING[/bold cyan] -- {summary}")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Location", min_width=24)
+    table.add_column("Kind")
+    table.add_column("Typo")
+    table.add_column("Suggestion")
+    for issue in report.issues:
+        typo = issue.word if issue.token.lower() == issue.word else f"{issue.word} ({issue.token})"
+        table.add_row(f"{issue.path}:{issue.line}", issue.kind, typo, issue.suggestion)
+    console.print(table)
+    console.print()
+
+
+@hygiene_app.command()
+def todos(
+    ctx: typer.Context,
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum comments to list (oldest first)",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Report TODO/FIXME/HACK/XXX comment debt with age and owner.
+
+    Age and owner come from git blame. When snapshot history exists,
+    each comment is marked new or persisting, and comments removed since
+    earlier snapshots are counted as resolved.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene todos
+
+      shannon-insight hygiene todos --json
+    """
+    from ..hygiene.todos import collect_comment_debt, lifecycle_states, summarize_by_package
+    from ..persistence import HistoryDB
+
+    sources = _load(ctx)
+    items = collect_comment_debt(sources.root, sources.content, use_git=sources.is_git_repo)
+    lifecycle: dict = {}
+    history = HistoryDB(str(sources.root))
+    if history.exists():
+        from ..persistence.queries import get_finding_lifecycle_map
+
+        with history as db:
+            lifecycle = get_finding_lifecycle_map(db.conn)
+    states, resolved = lifecycle_states(items, lifecycle)
+    packages = summarize_by_package(items)
+
+    rows = sorted(zip(items, states), key=lambda r: -(r[0].age_days or 0.0))[:limit]
+
+    if json_output:
+        doc = {
+            "total": len(items),
+            "resolved": resolved,
+            "packages": [p.__dict__ for p in packages],
+            "items": [
+                {
+                    "path": item.path,
+                    "line": item.line,
+                    "tag": item.tag,
+                    "text": item.text,
+                    "owner": item.owner,
+                    "age_days": item.age_days,
+                    "severity": item.severity,
+                    "state": state,
+                }
+                for item, state in rows
+            ],
+        }
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    if not items:
+        console.print("[green]No TODO/FIXME/HACK/XXX comments.[/green]")
+        return
+
+    console.print(
+        f"[bold cyan]COMMENT DEBT[/bold cyan] -- {len(items)} comments "
+        f"in {len(packages)} packages ({resolved} resolved in history)"
+    )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Count", justify="right")
+    table.add_column("Median age", justify="right")
+    table.add_column("Tags")
+    for pkg in packages:
+        age = "--" if pkg.median_age_days is None else f"{pkg.median_age_days:.0f}d"
+        tags = ", ".join(f"{tag} {n}" for tag, n in pkg.by_tag.items())
+        table.add_row(pkg.package, str(pkg.count), age, tags)
+    console.print(table)
+
+    console.print()
+    console.print("[bold cyan]OLDEST[/bold cyan]")
+    oldest = Table(show_header=True, pad_edge=True)
+    oldest.add_column("Location", min_width=24)
+    oldest.add_column("Age", justify="right")
+    oldest.add_column("Owner")
+    oldest.add_column("State")
+    oldest.add_column("Comment")
+    for item, state in rows:
+        age = "--" if item.age_days is None else f"{item.age_days:.0f}d"
+        oldest.add_row(
+            f"{item.path}:{item.line}", age, item.owner or "--", state, f"{item.tag}: {item.text}"
+        )
+    console.print(oldest)
+    console.print()
+
+
+@hygiene_app.command()
+def deprecations(
+    ctx: typer.Context,
+    symbol: Optional[str] = typer.Option(
+        None,
+        "--symbol",
+        "-s",
+        help="List every call site of one deprecated symbol",
+    ),
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum symbols (or call sites) to show",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Count remaining call sites of deprecated symbols.
+
+    Recognises Go "// Deprecated:" doc comments, Python @deprecated and
+    DeprecationWarning, and JSDoc @deprecated. When snapshot history
+    exists, the total is shown as a trend across recent snapshots.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene deprecations
+
+      shannon-insight hygiene deprecations --symbol OldClient
+    """
+    from ..hygiene.deprecations import analyze_deprecations
+    from ..persistence import HistoryDB
+
+    sources = _load(ctx)
+    report = analyze_deprecations(sources)
+    trend: list[float] = []
+    history = HistoryDB(str(sources.root))
+    if history.exists():
+        from ..persistence.queries import HistoryQuery
+
+        with history as db:
+            points = HistoryQuery(db.conn).codebase_health(20)
+        trend = [
+            p.metrics["deprecated_call_sites"]
+            for p in points
+            if p.metrics.get("deprecated_call_sites") is not None
+        ]
+
+    if symbol is not None:
+        report.symbols = [s for s in report.symbols if s.name == symbol]
+        if not report.symbols:
+            console.print(f"[red]Error:[/red] no deprecated symbol named {symbol!r}")
+            raise typer.Exit(1)
+
+    if json_output:
+        doc = report.to_dict()
+        doc["deprecated"] = doc["deprecated"][:limit]
+        doc["trend"] = trend
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    if symbol is not None:
+        dep = report.symbols[0]
+        console.print(
+            f"[bold cyan]{dep.name}[/bold cyan] -- deprecated at {dep.path}:{dep.line}, "
+            f"{dep.count} call sites"
+        )
+        if dep.note:
+            console.print(f"  [dim]{dep.note}[/dim]")
+        for site in dep.call_sites[:limit]:
+            console.print(f"  {site}")
+        console.print()
+        return
+
+    if not report.symbols:
+        console.print("[green]No deprecated symbols found.[/green]")
+        return
+
+    console.print(
+        f"[bold cyan]DEPRECATIONS[/bold cyan] -- {len(report.symbols)} deprecated symbols, "
+        f"{report.call_sites} call sites"
+    )
+    if len(trend) >= 2:
+        console.print(
+            f"  trend over {len(trend)} snapshots: "
+            f"{int(trend[0])} -> {int(trend[-1])} call sites"
+        )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Symbol", min_width=20)
+    table.add_column("Defined at")
+    table.add_column("Call sites", justify="right")
+    table.add_column("Note")
+    for dep in report.symbols[:limit]:
+        table.add_row(dep.name, f"{dep.path}:{dep.line}", str(dep.count), dep.note or "--")
+    console.print(table)
+
+    packages = report.by_package()
+    if packages:
+        console.print()
+        console.print("[bold cyan]BY PACKAGE[/bold cyan]")
+        by_package = Table(show_header=True, pad_edge=True)
+        by_package.add_column("Package", min_width=20)
+        by_package.add_column("Call sites", justify="right")
+        for package, count in list(packages.items())[:limit]:
+            by_package.add_row(package, str(count))
+        console.print(by_package)
+    console.print()
+
+
+@hygiene_app.command("format")
+def format_drift(
+    ctx: typer.Context,
+    language: Optional[str] = typer.Option(
+        None,
+        "--language",
+        "-l",
+        help="Only check one language: go | python | javascript | typescript | tsx",
+    ),
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum files to list",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Count lines that drift from each language's canonical formatter.
+
+    Go files are checked against gofmt's rules, Python against black's and
+    JavaScript/TypeScript against prettier's, using the line length, quote
+    and other options configured in pyproject.toml and .prettierrc. The
+    formatters themselves are not run. When snapshot history exists, the
+    number of drifting files is shown as a trend across recent snapshots.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene format
+
+      shannon-insight hygiene format --language python --limit 10
+    """
+    from ..hygiene.formatting import FORMATTERS, analyze_formatting
+    from ..persistence import HistoryDB
+
+    if language is not None and language not in FORMATTERS:
+        console.print(
+            f"[red]Error:[/red] --language must be one of {', '.join(FORMATTERS)}, "
+            f"got {language!r}"
+        )
+        raise typer.Exit(2)
+
+    sources = _load(ctx)
+    report = analyze_formatting(sources)
+    if language is not None:
+        report.files = [f for f in report.files if f.language == language]
+    trend: list[float] = []
+    history = HistoryDB(str(sources.root))
+    if history.exists() and language is None:
+        from ..persistence.queries import HistoryQuery
+
+        with history as db:
+            points = HistoryQuery(db.conn).codebase_health(20)
+        trend = [
+            p.metrics["format_drift_files"]
+            for p in points
+            if p.metrics.get("format_drift_files") is not None
+        ]
+
+    if json_output:
+        doc = report.to_dict(limit)
+        doc["trend"] = trend
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    if not report.files:
+        console.print("[green]No Go, Python, JavaScript or TypeScript files found.[/green]")
+        console.print()
+        return
+
+    drifted = report.drifted()
+    console.print(
+        f"[bold cyan]FORMAT DRIFT[/bold cyan] -- {len(drifted)} of {len(report.files)} files, "
+        f"{report.drift_lines} lines a formatter would change"
+    )
+    if len(trend) >= 2:
+        console.print(
+            f"  trend over {len(trend)} snapshots: {int(trend[0])} -> {int(trend[-1])} files"
+        )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Language", min_width=12)
+    table.add_column("Formatter")
+    table.add_column("Files", justify="right")
+    table.add_column("Drifted", justify="right")
+    table.add_column("Lines off", justify="right")
+    for name, entry in report.by_language().items():
+        table.add_row(
+            name,
+            entry["formatter"],
+            str(entry["files"]),
+            str(entry["drifted"]),
+            f"{entry['drift_lines']} / {entry['lines']}",
+        )
+    console.print(table)
+
+    if not drifted:
+        console.print()
+        console.print("[green]Every file matches its formatter.[/green]")
+        console.print()
+        return
+
+    console.print()
+    console.print("[bold cyan]BY RULE[/bold cyan]")
+    by_rule = Table(show_header=True, pad_edge=True)
+    by_rule.add_column("Rule", min_width=16)
+    by_rule.add_column("Lines", justify="right")
+    for rule, count in report.by_rule().items():
+        by_rule.add_row(rule, str(count))
+    console.print(by_rule)
+
+    console.print()
+    console.print("[bold cyan]BY PACKAGE[/bold cyan]")
+    by_package = Table(show_header=True, pad_edge=True)
+    by_package.add_column("Package", min_width=20)
+    by_package.add_column("Lines", justify="right")
+    for package, count in list(report.by_package().items())[:limit]:
+        by_package.add_row(package, str(count))
+    console.print(by_package)
+
+    console.print()
+    console.print("[bold cyan]FILES[/bold cyan]")
+    files = Table(show_header=True, pad_edge=True)
+    files.add_column("File", min_width=24)
+    files.add_column("Lines off", justify="right")
+    files.add_column("Rules")
+    files.add_column("First line", justify="right")
+    for f in drifted[:limit]:
+        rules = ", ".join(f"{rule} {n}" for rule, n in f.rules().items())
+        files.add_row(f.path, f"{f.drift_lines} / {f.lines}", rules, str(min(f.issues)))
+    console.print(files)
+    console.print()
+
+
+@hygiene_app.command("license")
+def license_headers(
+    ctx: typer.Context,
+    fix: bool = typer.Option(
+        False,
+        "--fix",
+        help="Insert the header into files that have none",
+    ),
+    template: Optional[Path] = typer.Option(
+        None,
+        "--template",
+        "-t",
+        help="Header template file (overrides license_header in config)",
+        exists=True,
+        dir_okay=False,
+    ),
+    owner: Optional[str] = typer.Option(
+        None,
+        "--owner",
+        help="Copyright owner for {owner} (overrides license_owner in config)",
+    ),
+    limit: int = typer.Option(
+        50,
+        "--limit",
+        "-n",
+        help="Maximum files to list",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Check that source files carry the required license header.
+
+    The header comes from license_header in the config file (or --template),
+    with {year} and {owner} placeholders. --fix inserts the header, with the
+    current year, into files that have none; malformed headers are only
+    reported.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene license
+
+      shannon-insight hygiene license --template HEADER.txt --owner "Acme Inc" --fix
+    """
+    import datetime
+
+    from ..hygiene.license import analyze_license, insert_header, render_header
+
+    settings = _settings(ctx)
+    text = template.read_text(encoding="utf-8") if template else settings.license_header
+    if not text.strip():
+        console.print(
+            "[red]Error:[/red] no license header configured. "
+            "Set license_header in shannon-insight.toml or pass --template."
+        )
+        raise typer.Exit(2)
+    owner = owner if owner is not None else settings.license_owner
+
+    sources = _load(ctx, settings)
+    report = analyze_license(sources, text, owner)
+
+    fixed: list[str] = []
+    if fix:
+        year = datetime.date.today().year
+        for issue in report.issues:
+            if issue.status != "missing":
+                continue
+            header = render_header(text, sources.language_of(issue.path), owner, year)
+            if header is None:
+                continue
+            target = sources.root / issue.path
+            target.write_text(insert_header(sources.content[issue.path], header), encoding="utf-8")
+            fixed.append(issue.path)
+        report.issues = [i for i in report.issues if i.path not in fixed]
+
+    if json_output:
+        doc = report.to_dict()
+        doc["fixed"] = fixed
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    if fixed:
+        console.print(f"[green]Inserted header into {len(fixed)} files.[/green]")
+    if not report.issues:
+        console.print(f"[green]All {report.checked} files carry the license header.[/green]")
+        console.print()
+        return
+
+    console.print(
+        f"[bold cyan]LICENSE HEADERS[/bold cyan] -- {len(report.issues)} of "
+        f"{report.checked} files without the required header"
+    )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Package", min_width=20)
+    table.add_column("Missing", justify="right")
+    table.add_column("Malformed", justify="right")
+    for package, counts in report.by_package().items():
+        table.add_row(package, str(counts.get("missing", 0)), str(counts.get("malformed", 0)))
+    console.print(table)
+
+    console.print()
+    files = Table(show_header=True, pad_edge=True)
+    files.add_column("File", min_width=24)
+    files.add_column("Status")
+    files.add_column("Found")
+    for issue in report.issues[:limit]:
+        files.add_row(issue.path, issue.status, issue.detail or "--")
+    console.print(files)
+    console.print()
+    if any(i.status == "missing" for i in report.issues) and not fix:
+        console.print("[dim]Run with --fix to insert missing headers.[/dim]")
+
+
+@hygiene_app.command()
+def crypto(
+    ctx: typer.Context,
+    category: Optional[str] = typer.Option(
+        None,
+        "--category",
+        "-c",
+        help="Only this kind of usage: hash, cipher, key, jwt, tls, library",
+    ),
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum violations to list",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Inventory cryptographic API usage and check it against the crypto policy.
+
+    Lists the hashes, ciphers, key sizes, JWT signing algorithms, TLS
+    settings and crypto libraries in use, then every usage the policy does
+    not allow: banned algorithms and modes (MD5, SHA-1, DES, ECB, TLS 1.0,
+    disabled certificate checks, ...), keys below the minimum size and,
+    when approved_libraries is set, other crypto libraries. Configure the
+    policy in the [crypto_policy] table of shannon-insight.toml.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene crypto
+
+      shannon-insight hygiene crypto --category jwt
+    """
+    from ..hygiene.crypto import CRYPTO_CATEGORIES, analyze_crypto, resolve_policy
+
+    if category is not None and category not in CRYPTO_CATEGORIES:
+        console.print(
+            f"[red]Error:[/red] --category must be one of: {', '.join(CRYPTO_CATEGORIES)}"
+        )
+        raise typer.Exit(2)
+
+    settings = _settings(ctx)
+    sources = _load(ctx, settings)
+    report = analyze_crypto(sources.syntax, sources.content, resolve_policy(settings.crypto_policy))
+    if category is not None:
+        report.usages = [u for u in report.usages if u.category == category]
+        report.violations = [v for v in report.violations if v.usage.category == category]
+
+    if json_output:
+        doc = report.to_dict()
+        doc["violations"] = doc["violations"][:limit]
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    if not report.usages:
+        console.print("[green]No cryptographic API usage found.[/green]")
+        console.print()
+        return
+
+    console.print(
+        f"[bold cyan]CRYPTO[/bold cyan] -- {len(report.usages)} usages in "
+        f"{len({u.path for u in report.usages})} files"
+    )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Category")
+    table.add_column("In use", min_width=30)
+    for name, counts in report.inventory().items():
+        table.add_row(name, ", ".join(f"{alg} ({n})" for alg, n in counts.items()))
+    console.print(table)
+    console.print()
+
+    if not report.violations:
+        console.print("[green]No crypto policy violations.[/green]")
+        console.print()
+        return
+
+    console.print(f"[bold red]POLICY VIOLATIONS[/bold red] -- {len(report.violations)}")
+    violations = Table(show_header=True, pad_edge=True)
+    violations.add_column("Location", min_width=24)
+    violations.add_column("Rule")
+    violations.add_column("Detail")
+    for violation in report.violations[:limit]:
+        violations.add_row(
+            f"{violation.usage.path}:{violation.usage.line}", violation.rule, violation.detail
+        )
+    console.print(violations)
+    console.print()
+
+
+@hygiene_app.command()
+def auth(
+    ctx: typer.Context,
+    rule: Optional[str] = typer.Option(
+        None,
+        "--rule",
+        "-r",
+        help=(
+            "Only one rule: default_secret, expiry_not_verified, token_without_expiry, "
+            "unvalidated_claim, refresh_accepts_expired"
+        ),
+    ),
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum issues to list",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Review JWT and token auth code for the mistakes complexity metrics miss.
+
+    Reports signing secrets with literal defaults or fallbacks, token
+    verification without expiry checks (or with expiry only checked when
+    present), tokens issued without an expiry, verification that never
+    checks the audience or issuer, and refresh functions that accept
+    expired tokens. Test files are skipped.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene auth
+
+      shannon-insight hygiene auth --rule default_secret
+    """
+    from ..hygiene.auth import AUTH_RULES, analyze_auth
+
+    if rule is not None and rule not in AUTH_RULES:
+        console.print(f"[red]Error:[/red] --rule must be one of: {', '.join(AUTH_RULES)}")
+        raise typer.Exit(2)
+
+    sources = _load(ctx)
+    report = analyze_auth(sources.syntax, sources.content)
+    if rule is not None:
+        report.issues = [i for i in report.issues if i.rule == rule]
+
+    if json_output:
+        doc = report.to_dict()
+        doc["issues"] = doc["issues"][:limit]
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    if not report.issue_sites and not report.verify_sites and not report.issues:
+        console.print("[green]No token handling found.[/green]")
+        console.print()
+        return
+
+    console.print(
+        f"[bold cyan]AUTH[/bold cyan] -- {report.issue_sites} calls issuing tokens, "
+        f"{report.verify_sites} verifying them"
+    )
+    if not report.issues:
+        console.print("[green]No auth flow issues.[/green]")
+        console.print()
+        return
+
+    counts = ", ".join(f"{name} {n}" for name, n in report.counts().items())
+    console.print(f"[bold red]ISSUES[/bold red] -- {len(report.issues)} ({counts})")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Location", min_width=24)
+    table.add_column("Rule")
+    table.add_column("Detail")
+    for issue in report.issues[:limit]:
+        table.add_row(f"{issue.path}:{issue.line}", issue.rule, issue.detail)
+    console.print(table)
+    console.print()
+
+
+@hygiene_app.command()
+def errors(
+    ctx: typer.Context,
+    rule: Optional[str] = typer.Option(
+        None,
+        "--rule",
+        "-r",
+        help=(
+            "Only one rule: swallowed_error, empty_error_branch, string_matched_error, "
+            "unwrapped_error"
+        ),
+    ),
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum issues to list",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Review Go error handling for errors that are lost or can no longer be matched.
+
+    Reports errors discarded with _ = err, empty if err != nil branches,
+    errors recognised by their message (strings.Contains(err.Error(), ...))
+    instead of errors.Is or errors.As, and errors rewrapped without %w.
+    Error checks and issues are counted per package. Test files are skipped.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene errors
+
+      shannon-insight hygiene errors --rule string_matched_error
+    """
+    from ..hygiene.errors import ERROR_RULES, analyze_errors
+
+    if rule is not None and rule not in ERROR_RULES:
+        console.print(f"[red]Error:[/red] --rule must be one of: {', '.join(ERROR_RULES)}")
+        raise typer.Exit(2)
+
+    sources = _load(ctx)
+    report = analyze_errors(sources.content)
+    if rule is not None:
+        report.issues = [i for i in report.issues if i.rule == rule]
+
+    if json_output:
+        doc = report.to_dict()
+        doc["issues"] = doc["issues"][:limit]
+        print(json.dumps(doc, indent=2))
+        return
+
+    console.print()
+    if not report.checks and not report.issues:
+        console.print("[green]No Go error handling found.[/green]")
+        console.print()
+        return
+
+    console.print(
+        f"[bold cyan]ERRORS[/bold cyan] -- {report.check_count} error checks, "
+        f"{report.typed_check_count} errors.Is/errors.As matches"
+    )
+    if not report.issues:
+        console.print("[green]No error hygiene issues.[/green]")
+        console.print()
+        return
+
+    counts = ", ".join(f"{name} {n}" for name, n in report.counts().items())
+    console.print(f"[bold red]ISSUES[/bold red] -- {len(report.issues)} ({counts})")
+    packages = Table(show_header=True, pad_edge=True)
+    packages.add_column("Package", min_width=24)
+    packages.add_column("Checks", justify="right")
+    packages.add_column("Issues", justify="right")
+    for package, n in report.by_package().items():
+        packages.add_row(package, str(report.checks.get(package, 0)), str(n))
+    console.print(packages)
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Location", min_width=24)
+    table.add_column("Rule")
+    table.add_column("Detail")
+    for issue in report.issues[:limit]:
+        table.add_row(f"{issue.path}:{issue.line}", issue.rule, issue.detail)
+    console.print(table)
+    console.print()
+
+
+@hygiene_app.command()
+def fixtures(
+    ctx: typer.Context,
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum fixtures to list per table",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Find test fixtures and golden files nothing references anymore.
+
+    Files under fixtures/, testdata/, __snapshots__/ and the like, and
+    *.golden and *.snap files, are stale when no source file names them,
+    a directory holding them, or (on a line that lists it) their fixture
+    directory. Fixtures of 64 KiB or more and ten times the size of the
+    code reading them are reported as oversized.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene fixtures
+
+      shannon-insight hygiene fixtures --json
+    """
+    from ..hygiene.fixtures import collect_fixtures
+
+    root = (ctx.obj or {}).get("path", Path.cwd()).resolve()
+    report = collect_fixtures(root)
+
+    if json_output:
+        print(json.dumps(report.to_dict(limit), indent=2))
+        return
+
+    console.print()
+    if not report.fixtures:
+        console.print("[green]No test fixtures found.[/green]")
+        console.print()
+        return
+
+    stale = report.stale()
+    console.print(
+        f"[bold cyan]FIXTURES[/bold cyan] -- {len(report.fixtures)} files, "
+        f"{len(stale)} unreferenced ({_kib(sum(f.size for f in stale))})"
+    )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Directory", min_width=24)
+    table.add_column("Files", justify="right")
+    table.add_column("Size", justify="right")
+    table.add_column("Stale", justify="right")
+    table.add_column("Stale size", justify="right")
+    for r in report.by_root()[:limit]:
+        table.add_row(r.root, str(r.files), _kib(r.size), str(r.stale), _kib(r.stale_size))
+    console.print(table)
+
+    if stale:
+        console.print()
+        console.print("[bold red]STALE[/bold red] -- referenced by no source file")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Fixture", min_width=24)
+        table.add_column("Size", justify="right")
+        for f in stale[:limit]:
+            table.add_row(f.path, _kib(f.size))
+        console.print(table)
+
+    oversized = report.oversized()
+    if oversized:
+        console.print()
+        console.print(
+            "[bold yellow]OVERSIZED[/bold yellow] -- much larger than the code reading them"
+        )
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Fixture", min_width=24)
+        table.add_column("Size", justify="right")
+        table.add_column("x code", justify="right")
+        table.add_column("Referenced by")
+        for f in oversized[:limit]:
+            table.add_row(f.path, _kib(f.size), f"{f.ratio:.0f}", ", ".join(f.referenced_by))
+        console.print(table)
+    console.print()
+
+
+@hygiene_app.command()
+def literals(
+    ctx: typer.Context,
+    min_repeats: Optional[int] = typer.Option(
+        None,
+        "--min-repeats",
+        "-r",
+        help="Report literals used more than this many times (default: literal_repeat_threshold)",
+        min=1,
+        max=1000,
+    ),
+    limit: int = typer.Option(
+        30,
+        "--limit",
+        "-n",
+        help="Maximum files and literals to list",
+        min=1,
+        max=5000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Count magic numbers and find string and number literals repeated across files.
+
+    Per file: numeric literals other than 0, 1 and 2 (and how many per 100
+    code lines), string literals, and strings the file already used. Across
+    files: every literal used more than --min-repeats times, like a context
+    key or an expiry hard-coded in several handlers. Constant declarations,
+    imports, comments, docstrings and test files are skipped.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight hygiene literals
+
+      shannon-insight hygiene literals --min-repeats 2 --json
+    """
+    from ..hygiene.literals import analyze_literals
+
+    settings = _settings(ctx)
+    sources = _load(ctx, settings)
+    threshold = min_repeats or settings.literal_repeat_threshold
+    languages = {path: syntax.language for path, syntax in sources.syntax.items()}
+    report = analyze_literals(languages, sources.content, threshold)
+
+    if json_output:
+        print(json.dumps(report.to_dict(limit), indent=2))
+        return
+
+    console.print()
+    if not report.files:
+        console.print("[green]No Python or C-like source files found.[/green]")
+        console.print()
+        return
+
+    console.print(
+        f"[bold cyan]LITERALS[/bold cyan] -- {report.magic_numbers} magic numbers, "
+        f"{report.strings} string literals in {len(report.files)} files"
+    )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("File", min_width=24)
+    table.add_column("Magic numbers", justify="right")
+    table.add_column("Per 100 lines", justify="right")
+    table.add_column("Strings", justify="right")
+    table.add_column("Repeated", justify="right")
+    for f in [f for f in report.files if f.magic_numbers or f.repeated_strings][:limit]:
+        table.add_row(
+            f.path,
+            str(f.magic_numbers),
+            f"{f.magic_density:.1f}",
+            str(f.strings),
+            str(f.repeated_strings),
+        )
+    console.print(table)
+
+    console.print()
+    if not report.repeated:
+        console.print(f"[green]No literal is used more than {threshold} times.[/green]")
+        console.print()
+        return
+    console.print(
+        f"[bold yellow]REPEATED[/bold yellow] -- {len(report.repeated)} literals "
+        f"used more than {threshold} times"
+    )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Literal", min_width=24)
+    table.add_column("Kind")
+    table.add_column("Uses", justify="right")
+    table.add_column("Files")
+    for r in report.repeated[:limit]:
+        shown = r.value if r.kind == "number" else f'"{r.value}"'
+        files = r.files
+        listed = ", ".join(files[:3]) + (f" and {len(files) - 3} more" if len(files) > 3 else "")
+        table.add_row(shown, r.kind, str(len(r.uses)), listed)
+    console.print(table)
+    console.print()
+
+
+def _kib(size: int) -> str:
+    return f"{size / 1024:.1f} KiB"
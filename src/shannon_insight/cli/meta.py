@@ -0,0 +1,97 @@
+"""Meta CLI command -- list the metric and finding types Shannon Insight reports."""
+
+import json
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+_KINDS = ("all", "metrics", "findings")
+
+_DIRECTION_STYLE = {
+    "high_is_bad": "[red]higher is worse[/red]",
+    "high_is_good": "[green]higher is better[/green]",
+    "neutral": "[dim]neutral[/dim]",
+}
+
+
+@app.command()
+def meta(
+    kind: str = typer.Argument("all", help="What to list: all, metrics or findings"),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    List every metric and finding type with its units and direction.
+
+    Metrics show their unit, whether higher values are worse, and the
+    scopes (file, module, global) they are reported at. Finding types
+    show their dashboard category, health concern and, for rule-based
+    checks, the rule ids. The JSON output is the registry dashboards read
+    to build their UI; it is also served at GET /api/meta.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight meta
+
+      shannon-insight meta metrics
+
+      shannon-insight meta --json
+    """
+    from ..meta import registry
+
+    if kind not in _KINDS:
+        console.print(f"[red]Error:[/red] unknown kind {kind!r} (use {', '.join(_KINDS)})")
+        raise typer.Exit(2)
+
+    data = registry()
+    if kind == "metrics":
+        data.pop("findings")
+    elif kind == "findings":
+        data.pop("metrics")
+
+    if json_output:
+        print(json.dumps(data, indent=2))
+        return
+
+    console.print()
+    if "metrics" in data:
+        table = Table(title=f"Metrics ({len(data['metrics'])})", title_justify="left")
+        table.add_column("ID", style="cyan")
+        table.add_column("Label")
+        table.add_column("Unit")
+        table.add_column("Direction")
+        table.add_column("Scopes", style="dim")
+        for m in data["metrics"]:
+            table.add_row(
+                m["id"],
+                m["label"],
+                m["unit"],
+                _DIRECTION_STYLE[m["direction"]],
+                ", ".join(m["scopes"]),
+            )
+        console.print(table)
+        console.print()
+
+    if "findings" in data:
+        table = Table(title=f"Finding types ({len(data['findings'])})", title_justify="left")
+        table.add_column("ID", style="cyan")
+        table.add_column("Label")
+        table.add_column("Category")
+        table.add_column("Concern")
+        table.add_column("Rules", style="dim")
+        for f in data["findings"]:
+            table.add_row(
+                f["id"],
+                f["label"],
+                data["categories"].get(f["category"], "-"),
+                data["concerns"].get(f["concern"], "-"),
+                ", ".join(f["rules"]),
+            )
+        console.print(table)
+        console.print()
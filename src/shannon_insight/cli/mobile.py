@@ -0,0 +1,130 @@
+"""Mobile CLI command -- Android and iOS resource debt."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def mobile(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        20,
+        "--top",
+        "-n",
+        help="Rows to show per table",
+        min=1,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Check Android and iOS resource files.
+
+    Reads res/ directories and AndroidManifest.xml, .strings tables, asset
+    catalogs, storyboards, xibs and property lists, and lists texts defined
+    under several names, resources nothing refers to, and resource files
+    that are too large. Resources looked up by computed names cannot be
+    seen; when the code does that, unused resources are only likely unused.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight mobile
+
+      shannon-insight mobile --top 50
+
+      shannon-insight mobile --json
+    """
+    from ..diligence.inventory import tracked_files
+    from ..file_ops import should_skip_file
+    from ..hygiene import load_sources
+    from ..signals.mobile_resources import collect_mobile_resources, resource_kind
+    from ._common import resolve_settings
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    settings = resolve_settings(config=obj.get("config"))
+    paths = [
+        path
+        for path in tracked_files(root)
+        if resource_kind(path) is not None
+        and not should_skip_file(root / path, settings.exclude_patterns)
+    ]
+    if not paths:
+        console.print(f"[red]Error:[/red] no Android or iOS resource files found in {root}")
+        raise typer.Exit(2)
+    sources = load_sources(root, settings)
+    report = collect_mobile_resources(root, paths, sources.content)
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    platforms = ", ".join(
+        f"{count} {'iOS' if name == 'ios' else 'Android'}"
+        for name, count in sorted(report.platforms().items())
+    )
+    console.print()
+    console.print(
+        f"[bold cyan]MOBILE[/bold cyan] -- {len(report.files)} resource files ({platforms}), "
+        f"{len(report.resources)} resources"
+    )
+    console.print()
+
+    if report.duplicates:
+        console.print(f"[bold cyan]DUPLICATE STRINGS[/bold cyan] ({len(report.duplicates)})")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Text", min_width=20)
+        table.add_column("Names")
+        table.add_column("Declared")
+        for duplicate in report.duplicates[:top]:
+            table.add_row(
+                duplicate.value,
+                ", ".join(r.name for r in duplicate.resources),
+                "\n".join(f"{r.path}:{r.line}" for r in duplicate.resources),
+            )
+        console.print(table)
+        console.print()
+
+    if report.unused:
+        console.print(f"[bold cyan]UNUSED[/bold cyan] ({len(report.unused)})")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Resource", min_width=20)
+        table.add_column("Declared")
+        table.add_column("Copies", justify="right")
+        for item in report.unused[:top]:
+            resource = item.resource
+            where = f"{resource.path}:{resource.line}" if item.is_entry else resource.path
+            table.add_row(resource.key, where, str(item.variants))
+        console.print(table)
+        if any(item.dynamic_lookups for item in report.unused):
+            console.print(
+                "[dim]The code also looks resources up by computed names; "
+                "check before deleting.[/dim]"
+            )
+        console.print()
+
+    if report.oversized:
+        console.print(f"[bold cyan]OVERSIZED[/bold cyan] ({len(report.oversized)})")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("File", min_width=20)
+        table.add_column("KiB", justify="right")
+        table.add_column("Scenes", justify="right")
+        for oversized in report.oversized[:top]:
+            file = oversized.file
+            scenes = str(file.scenes) if file.kind in ("storyboard", "xib") else ""
+            table.add_row(file.path, str(file.size // 1024), scenes)
+        console.print(table)
+        console.print()
+
+    if not (report.duplicates or report.unused or report.oversized):
+        console.print("[green]No duplicate, unused or oversized resources.[/green]")
+        console.print()
@@ -0,0 +1,127 @@
+"""PII CLI command -- where tagged personal data flows."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+_KIND_COLORS = {"log": "yellow", "response": "cyan", "third_party": "red"}
+
+
+@app.command()
+def pii(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        30,
+        "--top",
+        "-n",
+        help="Flows to show",
+        min=1,
+        max=1000,
+    ),
+    kind: Optional[str] = typer.Option(
+        None,
+        "--kind",
+        "-k",
+        help="Only flows of this kind: log, response, third_party",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Show where fields tagged as personal data flow.
+
+    Fields are tagged in the [bold]pii_fields[/bold] config table
+    ("User.email" = "contact"). Reads of a tagged field are followed through
+    assignments within each function to logging calls, HTTP responses and
+    calls on third-party SDKs. Masked or redacted values are not reported.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight pii
+
+      shannon-insight pii --kind third_party
+
+      shannon-insight pii --json
+    """
+    from ..graph.builder import build_dependency_graph
+    from ..graph.pii import FLOW_KINDS, find_pii_flows, parse_pii_fields
+    from ..hygiene import load_sources
+    from ._common import resolve_settings
+
+    if kind is not None and kind not in FLOW_KINDS:
+        console.print(f"[red]Error:[/red] --kind must be one of: {', '.join(FLOW_KINDS)}")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    settings = resolve_settings(config=obj.get("config"))
+    fields = parse_pii_fields(settings.pii_fields)
+    if not fields:
+        console.print(
+            "[red]Error:[/red] no PII fields configured; tag them in the pii_fields table "
+            "of shannon-insight.toml"
+        )
+        raise typer.Exit(2)
+
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, settings)
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    graph = build_dependency_graph(list(sources.syntax.values()), str(root))
+    report = find_pii_flows(sources.syntax, sources.content, fields, graph.external_imports)
+    if kind is not None:
+        report.flows = [flow for flow in report.flows if flow.kind == kind]
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    counts = ", ".join(f"{n} {k.replace('_', '-')}" for k, n in report.by_kind().items() if n)
+    console.print(
+        f"[bold cyan]PII FLOWS[/bold cyan] -- {counts or 'none found'} "
+        f"({report.functions} functions analyzed)"
+    )
+    fields_table = Table(show_header=True, pad_edge=True)
+    fields_table.add_column("Field")
+    fields_table.add_column("Category")
+    fields_table.add_column("Defined in", min_width=24)
+    fields_table.add_column("Flows", justify="right")
+    flows_by_tag = report.by_tag()
+    for f in report.fields:
+        models = report.models.get(f.tag)
+        if models is None:
+            defined = "[dim]any model[/dim]"
+        else:
+            defined = ", ".join(models) or f"[yellow]{f.model} not found[/yellow]"
+        fields_table.add_row(f.tag, f.category, defined, str(flows_by_tag[f.tag]))
+    console.print(fields_table)
+
+    if report.flows:
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Kind")
+        table.add_column("Location", min_width=24)
+        table.add_column("Field")
+        table.add_column("Read")
+        table.add_column("Call")
+        for flow in report.flows[:top]:
+            color = _KIND_COLORS[flow.kind]
+            table.add_row(
+                f"[{color}]{flow.kind}[/{color}]",
+                f"{flow.path}:{flow.line} {flow.function}",
+                f"{flow.tag} ({flow.category})",
+                flow.read,
+                flow.call,
+            )
+        console.print(table)
+    console.print()
@@ -0,0 +1,90 @@
+"""Proto CLI command -- gRPC services, RPCs and messages, and their stubs."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def proto(
+    ctx: typer.Context,
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    List the Protocol Buffers services, RPCs and messages.
+
+    Reads every .proto file: its package, services with their RPCs
+    (request and response types, streaming), messages and enums, and the
+    protoc stubs generated from it (user.pb.go, user_grpc.pb.go,
+    user_pb2.py, ...). Stubs depend on their .proto file in the dependency
+    graph, so servers and clients in different languages are linked
+    through the definition they share.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight proto
+
+      shannon-insight proto --json
+    """
+    from ..hygiene import load_sources
+    from ..scanning.proto import catalog_protos
+    from ._common import resolve_settings
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    catalog = catalog_protos(sources.syntax, sources.content)
+    if not catalog.files:
+        console.print(f"[red]Error:[/red] no .proto files found in {root}")
+        raise typer.Exit(2)
+
+    if json_output:
+        print(json.dumps(catalog.to_dict(), indent=2))
+        return
+
+    services = catalog.services()
+    messages = sum(1 for p in catalog.files.values() for m in p.messages if m.kind == "message")
+    console.print()
+    console.print(
+        f"[bold cyan]PROTO[/bold cyan] -- {len(catalog.files)} files, "
+        f"{len(services)} services, {messages} messages"
+    )
+    console.print()
+
+    if services:
+        console.print("[bold cyan]SERVICES[/bold cyan]")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Service", min_width=20)
+        table.add_column("RPC")
+        table.add_column("Request -> Response")
+        table.add_column("Stubs")
+        for path, service in services:
+            stubs = ", ".join(catalog.stubs.get(path, [])) or "[dim]none[/dim]"
+            for index, rpc in enumerate(service.rpcs or [None]):
+                signature = ""
+                if rpc is not None:
+                    request = f"stream {rpc.request}" if rpc.client_streaming else rpc.request
+                    response = f"stream {rpc.response}" if rpc.server_streaming else rpc.response
+                    signature = f"{request} -> {response}"
+                table.add_row(
+                    f"{service.name} ({path}:{service.start_line})" if index == 0 else "",
+                    rpc.name if rpc is not None else "[dim]no RPCs[/dim]",
+                    signature,
+                    stubs if index == 0 else "",
+                )
+        console.print(table)
+        console.print()
+
+    unlinked = sorted(path for path in catalog.files if path not in catalog.stubs)
+    if unlinked and catalog.stubs:
+        console.print(f"[dim]No generated stubs found for: {', '.join(unlinked)}[/dim]")
+        console.print()
@@ -0,0 +1,98 @@
+"""Custom query rule CLI commands -- test rule packs against their fixtures."""
+
+import json
+
+import typer
+
+from ._common import console
+
+rules_app: typer.Typer = typer.Typer(
+    help="Custom tree-sitter query rules (test rule packs against annotated fixtures)",
+    no_args_is_help=True,
+    rich_markup_mode="rich",
+)
+
+_STATUS_STYLE = {
+    "pass": "[green]PASS[/green]",
+    "fail": "[bold red]FAIL[/bold red]",
+    "error": "[bold red]ERROR[/bold red]",
+    "untested": "[yellow]UNTESTED[/yellow]",
+}
+
+
+@rules_app.command("test")
+def test_rules(
+    paths: list[str] = typer.Argument(
+        ..., help="Rule files or rule pack directories (searched recursively; dir/... too)"
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Run each query rule on its annotated fixtures and report red/green.
+
+    Fixtures sit beside the rule (rule.toml -> rule.go, rule.bad.go) or in a
+    directory named after it (rule/). A comment "ruleid: <id>" marks a line
+    the rule must match, "ok: <id>" a line it must not; a match on any
+    unmarked line fails the rule too. Exits 1 when a rule fails or cannot
+    be loaded.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight rules test ./rules/...
+
+      shannon-insight rules test rules/no-fmt-println.toml --json
+    """
+    from ..rules import RuleError, run_rule_tests
+    from ..scanning.treesitter_parser import TREE_SITTER_AVAILABLE
+
+    if not TREE_SITTER_AVAILABLE:
+        console.print(
+            "[red]Error:[/red] query rules run on tree-sitter "
+            "(pip install shannon-codebase-insight\\[parsing])"
+        )
+        raise typer.Exit(2)
+
+    try:
+        report = run_rule_tests(paths)
+    except RuleError as e:
+        console.print(f"[red]Error:[/red] {e}")
+        raise typer.Exit(2)
+
+    if json_output:
+        print(json.dumps(report.to_dict(), indent=2))
+        raise typer.Exit(0 if report.passed else 1)
+
+    console.print()
+    if not report.results:
+        console.print("[yellow]No rule files found.[/yellow]")
+        console.print()
+        return
+
+    for result in report.results:
+        name = result.rule.id if result.rule is not None else result.path
+        fixtures = len(result.fixtures)
+        console.print(
+            f"{_STATUS_STYLE[result.status]} {name} "
+            f"[dim]({fixtures} fixture{'s' if fixtures != 1 else ''})[/dim]"
+        )
+        if result.error is not None:
+            console.print(f"  {result.error}")
+        for fixture in result.fixtures:
+            if fixture.missing:
+                lines = ", ".join(map(str, fixture.missing))
+                console.print(f"  {fixture.path}: not matched at line(s) {lines}")
+            if fixture.unexpected:
+                lines = ", ".join(map(str, fixture.unexpected))
+                console.print(f"  {fixture.path}: unexpected match at line(s) {lines}")
+
+    counts = ", ".join(f"{n} {status}" for status, n in report.counts().items())
+    console.print()
+    style = "green" if report.passed else "bold red"
+    console.print(f"[{style}]{counts}[/{style}]")
+    console.print()
+    if not report.passed:
+        raise typer.Exit(1)
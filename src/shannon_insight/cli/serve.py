@@ -21,6 +21,16 @@ def serve(
     config: Optional[Path] = typer.Option(None, "-c", "--config", help="Config file"),
     workers: Optional[int] = typer.Option(None, "-w", "--workers", help="Parallel workers"),
     verbose: bool = typer.Option(False, "-v", "--verbose", help="Verbose logging"),
+    bundle: Optional[Path] = typer.Option(
+        None, "--bundle", help="Result bundle to serve under /api/bundle", exists=True
+    ),
+    tenants: Optional[Path] = typer.Option(
+        None,
+        "--tenants",
+        help="Tenant manifest (TOML): serve several repositories under /t/<name>/",
+        exists=True,
+        dir_okay=False,
+    ),
 ) -> None:
     """Start a live dashboard that watches for file changes."""
     console.print(
@@ -36,15 +46,23 @@ def serve(
         console.print(f"[red]{exc}[/red]")
         raise typer.Exit(1)
 
-    # Get path from parent callback (shannon-insight [PATH] serve)
-    root_dir = str(ctx.obj.get("path", Path.cwd()).resolve())
-    settings = resolve_settings(config=config, workers=workers, verbose=verbose)
-
     if verbose:
         logging.basicConfig(level=logging.DEBUG)
     else:
         logging.basicConfig(level=logging.WARNING)
 
+    if tenants is not None:
+        from ..server.lifecycle import launch_multi_tenant_server
+
+        launch_multi_tenant_server(
+            manifest_path=str(tenants), console=console, host=host, port=port, verbose=verbose
+        )
+        return
+
+    # Get path from parent callback (shannon-insight [PATH] serve)
+    root_dir = str(ctx.obj.get("path", Path.cwd()).resolve())
+    settings = resolve_settings(config=config, workers=workers, verbose=verbose)
+
     # Delegate to the lifecycle manager
     from ..server.lifecycle import launch_server
 
@@ -56,4 +74,5 @@ def serve(
         port=port,
         no_browser=no_browser,
         verbose=verbose,
+        bundle_path=str(bundle) if bundle else None,
     )
@@ -0,0 +1,108 @@
+"""SQL CLI command -- statement complexity, long procedures and table usage."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def sql(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        15,
+        "--top",
+        "-n",
+        help="Entries per table",
+        min=1,
+        max=1000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Measure the SQL files: migrations, views and stored procedures.
+
+    Lists the most complex statements (joins, subqueries, CASE branches,
+    boolean conditions), the longest functions, procedures and triggers,
+    and the tables referenced by the most SQL files. Tables created in one
+    file and altered or queried in another are linked in the dependency
+    graph, so migrations show up as dependents of the table they change.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight sql
+
+      shannon-insight sql -n 30
+
+      shannon-insight sql --json
+    """
+    from ..hygiene import load_sources
+    from ..signals.sql_statements import LONG_PROCEDURE_LINES, collect_sql
+    from ._common import resolve_settings
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    report = collect_sql(sources.syntax, sources.content)
+    if not report.statements:
+        console.print(f"[red]Error:[/red] no SQL files found in {root}")
+        raise typer.Exit(2)
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    console.print(
+        f"[bold cyan]SQL[/bold cyan] -- {len(report.statements)} statements "
+        f"in {report.files} files"
+    )
+    console.print()
+
+    console.print("[bold cyan]COMPLEX STATEMENTS[/bold cyan]")
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Statement", min_width=24)
+    table.add_column("Complexity", justify="right")
+    table.add_column("Lines", justify="right")
+    for statement in report.statements[:top]:
+        table.add_row(statement.label, str(statement.complexity), str(statement.lines))
+    console.print(table)
+    console.print()
+
+    if report.procedures:
+        console.print("[bold cyan]PROCEDURES[/bold cyan] -- longest first")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Routine", min_width=24)
+        table.add_column("Lines", justify="right")
+        table.add_column("Complexity", justify="right")
+        for procedure in report.procedures[:top]:
+            lines = str(procedure.lines)
+            if procedure.lines >= LONG_PROCEDURE_LINES:
+                lines = f"[red]{lines}[/red]"
+            table.add_row(
+                f"{procedure.path}:{procedure.line} {procedure.name}",
+                lines,
+                str(procedure.complexity),
+            )
+        console.print(table)
+        console.print()
+
+    if report.tables:
+        console.print("[bold cyan]TABLES[/bold cyan] -- most referenced first")
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Table", min_width=20)
+        table.add_column("Files", justify="right")
+        table.add_column("Referenced from")
+        for name, paths in list(report.tables.items())[:top]:
+            shown = ", ".join(paths[:3]) + (f" (+{len(paths) - 3})" if len(paths) > 3 else "")
+            table.add_row(name, str(len(paths)), shown)
+        console.print(table)
+        console.print()
@@ -0,0 +1,138 @@
+"""Surface CLI command -- entry points and the code each one reaches."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def surface(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        20,
+        "--top",
+        "-n",
+        help="Entries per table",
+        min=1,
+        max=1000,
+    ),
+    kind: Optional[str] = typer.Option(
+        None,
+        "--kind",
+        "-k",
+        help="Only entry points of this kind: main, http, consumer, cli",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Map the attack surface: entry points and what each one reaches.
+
+    Entry points are main functions, HTTP handlers, message consumers and
+    CLI commands. For each, the call graph gives the functions it can
+    reach, their total and worst cyclomatic complexity, and the summed
+    risk score of the files involved. Functions no entry point reaches
+    are listed as unreachable: dead code, or code only called dynamically.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight surface
+
+      shannon-insight surface --kind http -n 50
+
+      shannon-insight surface --json
+    """
+    from ..api import analyze
+    from ..graph.builder import build_dependency_graph
+    from ..graph.centrality import symbol_complexity
+    from ..graph.reachability import ENTRY_KINDS, build_surface
+    from ..hygiene import load_sources
+    from ..signals.function_outliers import collect_functions
+    from ._common import resolve_settings
+
+    if kind is not None and kind not in ENTRY_KINDS:
+        console.print(f"[red]Error:[/red] --kind must be one of: {', '.join(ENTRY_KINDS)}")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    config_file = obj.get("config")
+    sources = load_sources(root, resolve_settings(config=config_file))
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    _, snapshot = analyze(path=str(root), config_file=config_file, quiet=True)
+    graph = build_dependency_graph(list(sources.syntax.values()), str(root))
+    report = build_surface(
+        sources.syntax,
+        graph.adjacency,
+        symbol_complexity(collect_functions(sources.syntax, sources.content)),
+        {
+            path: float(signals.get("risk_score", 0.0))
+            for path, signals in snapshot.file_signals.items()
+        },
+    )
+    if kind is not None:
+        report.entries = [r for r in report.entries if r.entry.kind == kind]
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    counts = ", ".join(f"{n} {k}" for k, n in report.by_kind().items() if n)
+    console.print(f"[bold cyan]ENTRY POINTS[/bold cyan] -- {counts or 'none found'}")
+    if report.entries:
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Entry point", min_width=24)
+        table.add_column("Kind")
+        table.add_column("Reaches", justify="right")
+        table.add_column("Files", justify="right")
+        table.add_column("Complexity", justify="right")
+        table.add_column("Worst", justify="right")
+        table.add_column("Risk", justify="right")
+        for reach in report.entries[:top]:
+            symbol = reach.entry.symbol
+            table.add_row(
+                f"{symbol.path}:{symbol.line} {symbol.name}",
+                f"{reach.entry.kind} ({reach.entry.trigger})",
+                str(len(reach.reachable)),
+                str(reach.files),
+                str(reach.complexity),
+                str(reach.max_complexity),
+                f"{reach.risk:.2f}",
+            )
+        console.print(table)
+    console.print()
+
+    if not report.call_graph_available:
+        console.print(
+            "[yellow]No call graph:[/yellow] calls are only extracted by tree-sitter "
+            "(pip install shannon-codebase-insight\\[parsing])"
+        )
+    elif report.entries:
+        console.print(
+            f"[bold cyan]UNREACHABLE[/bold cyan] -- {len(report.unreachable)} functions "
+            "no entry point calls"
+        )
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Function", min_width=24)
+        table.add_column("Complexity", justify="right")
+        for symbol in report.unreachable[:top]:
+            complexity = report.complexity.get(symbol.id)
+            table.add_row(
+                f"{symbol.path}:{symbol.line} {symbol.name}",
+                str(complexity) if complexity is not None else "-",
+            )
+        console.print(table)
+    console.print()
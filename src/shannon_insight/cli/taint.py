@@ -0,0 +1,124 @@
+"""Taint CLI command -- candidate paths from inputs to sensitive calls."""
+
+import json
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+_CONFIDENCE_COLORS = {"high": "red", "medium": "yellow", "low": "dim"}
+
+
+@app.command()
+def taint(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        30,
+        "--top",
+        "-n",
+        help="Paths to show",
+        min=1,
+        max=1000,
+    ),
+    confidence: str = typer.Option(
+        "low",
+        "--confidence",
+        help="Minimum confidence: high, medium, low",
+    ),
+    sink: Optional[str] = typer.Option(
+        None,
+        "--sink",
+        "-k",
+        help="Only paths into this kind of sink: sql, exec, path, template",
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Find candidate injection paths from inputs to sensitive calls.
+
+    Inputs are request parameters (and HTTP handler parameters) and
+    environment variables; sensitive calls are SQL execution, command
+    execution, file access by path and template rendering. Values are
+    followed through assignments and into called functions over the call
+    graph. Every path is a candidate to review, ranked by confidence.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight taint
+
+      shannon-insight taint --confidence high --sink sql
+
+      shannon-insight taint --json
+    """
+    from ..graph.builder import build_dependency_graph
+    from ..graph.taint import CONFIDENCE_LEVELS, SINK_KINDS, find_taint_paths
+    from ..hygiene import load_sources
+    from ._common import resolve_settings
+
+    if confidence not in CONFIDENCE_LEVELS:
+        console.print(
+            f"[red]Error:[/red] --confidence must be one of: {', '.join(CONFIDENCE_LEVELS)}"
+        )
+        raise typer.Exit(2)
+    if sink is not None and sink not in SINK_KINDS:
+        console.print(f"[red]Error:[/red] --sink must be one of: {', '.join(SINK_KINDS)}")
+        raise typer.Exit(2)
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    if not sources.syntax:
+        console.print(f"[red]Error:[/red] no source files found in {root}")
+        raise typer.Exit(2)
+
+    graph = build_dependency_graph(list(sources.syntax.values()), str(root))
+    report = find_taint_paths(sources.syntax, sources.content, graph.adjacency)
+    allowed = CONFIDENCE_LEVELS[: CONFIDENCE_LEVELS.index(confidence) + 1]
+    report.paths = [
+        p
+        for p in report.paths
+        if p.confidence in allowed and (sink is None or p.sink.kind == sink)
+    ]
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    console.print()
+    counts = ", ".join(f"{n} {level}" for level, n in report.by_confidence().items() if n)
+    console.print(
+        f"[bold cyan]TAINT PATHS[/bold cyan] -- {counts or 'none found'} "
+        f"({report.functions} functions analyzed)"
+    )
+    if report.paths:
+        table = Table(show_header=True, pad_edge=True)
+        table.add_column("Confidence")
+        table.add_column("Input", min_width=24)
+        table.add_column("Sink", min_width=24)
+        table.add_column("Via")
+        for path in report.paths[:top]:
+            color = _CONFIDENCE_COLORS[path.confidence]
+            source, target = path.source, path.sink
+            table.add_row(
+                f"[{color}]{path.confidence}[/{color}]",
+                f"{source.path}:{source.line} {source.expression}",
+                f"{target.path}:{target.line} {target.kind} {target.call}",
+                " -> ".join(symbol.split(":")[-1] for symbol in path.via),
+            )
+        console.print(table)
+    console.print()
+
+    if not report.call_graph_available:
+        console.print(
+            "[yellow]No call graph:[/yellow] only paths within one function were followed; "
+            "calls are extracted by tree-sitter (pip install shannon-codebase-insight\\[parsing])"
+        )
+        console.print()
@@ -0,0 +1,121 @@
+"""Terraform CLI command -- modules, nested dynamic blocks and variable outliers."""
+
+import json
+from pathlib import Path
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+
+@app.command()
+def terraform(
+    ctx: typer.Context,
+    top: int = typer.Option(
+        20,
+        "--top",
+        "-n",
+        help="Entries per table",
+        min=1,
+        max=1000,
+    ),
+    json_output: bool = typer.Option(
+        False,
+        "--json",
+        help="Output in machine-readable JSON format",
+    ),
+):
+    """
+    Measure the Terraform modules: resources, variables and dynamic blocks.
+
+    Every directory of .tf files is a module. Lists the modules with their
+    resource, data source, variable, output and module-call counts, the
+    dynamic blocks nested inside other dynamic blocks, and the modules that
+    declare far more variables than the others (modified z-score, as for
+    file outliers). Local module sources link modules in the dependency
+    graph, and module sources that do not exist are phantom imports.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight terraform
+
+      shannon-insight terraform -n 50
+
+      shannon-insight terraform --json
+    """
+    from ..hygiene import load_sources
+    from ..signals.terraform_modules import DEEP_DYNAMIC_DEPTH, collect_terraform
+    from ._common import resolve_settings
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    sources = load_sources(root, resolve_settings(config=obj.get("config")))
+    report = collect_terraform(sources.syntax, sources.content)
+    if not report.modules:
+        console.print(f"[red]Error:[/red] no Terraform files found in {root}")
+        raise typer.Exit(2)
+
+    if json_output:
+        print(json.dumps(report.to_dict(top), indent=2))
+        return
+
+    outliers = {o.module.path for o in report.variable_outliers}
+    console.print()
+    console.print(
+        f"[bold cyan]TERRAFORM[/bold cyan] -- {len(report.modules)} modules, "
+        f"{sum(m.resources for m in report.modules)} resources"
+    )
+    table = Table(show_header=True, pad_edge=True)
+    table.add_column("Module", min_width=20)
+    table.add_column("Resources", justify="right")
+    table.add_column("Data", justify="right")
+    table.add_column("Variables", justify="right")
+    table.add_column("Outputs", justify="right")
+    table.add_column("Calls", justify="right")
+    table.add_column("Dynamic depth", justify="right")
+    for module in report.modules[:top]:
+        variables = str(len(module.variables))
+        if module.path in outliers:
+            variables = f"[red]{variables}[/red]"
+        depth = str(module.max_dynamic_depth)
+        if module.max_dynamic_depth >= DEEP_DYNAMIC_DEPTH:
+            depth = f"[red]{depth}[/red]"
+        table.add_row(
+            module.label,
+            str(module.resources),
+            str(module.data_sources),
+            variables,
+            str(module.outputs),
+            str(module.module_calls),
+            depth,
+        )
+    console.print(table)
+    console.print()
+
+    if report.variable_outliers:
+        console.print("[bold cyan]VARIABLE OUTLIERS[/bold cyan]")
+        for outlier in report.variable_outliers:
+            console.print(
+                f"  {outlier.module.label}: {len(outlier.module.variables)} variables, "
+                f"typical {outlier.typical_variables:g} (modified z {outlier.modified_z:.1f})"
+            )
+        console.print()
+
+    if report.dynamic_blocks:
+        console.print("[bold cyan]NESTED DYNAMIC BLOCKS[/bold cyan] -- deepest first")
+        nested = Table(show_header=True, pad_edge=True)
+        nested.add_column("Location", min_width=24)
+        nested.add_column("Block")
+        nested.add_column("Depth", justify="right")
+        nested.add_column("Dynamic blocks")
+        for sample in report.dynamic_blocks[:top]:
+            nested.add_row(
+                f"{sample.path}:{sample.line}",
+                sample.block,
+                str(sample.depth),
+                " > ".join(sample.chain),
+            )
+        console.print(nested)
+        console.print()
@@ -0,0 +1,224 @@
+"""Triage CLI command -- walk through new findings and record a decision for each."""
+
+from collections import Counter
+from datetime import date
+from pathlib import Path
+from typing import Optional
+
+import typer
+from rich.table import Table
+
+from . import app
+from ._common import console
+
+# Findings analyzed per triage session; the usual max_findings cap hides the rest
+_MAX_FINDINGS = 1000
+
+_CHOICES = {
+    "d": "dismiss",
+    "s": "suppress",
+    "a": "assign",
+    "t": "ticket",
+    "n": "next",
+    "q": "quit",
+}
+
+
+@app.command()
+def triage(
+    ctx: typer.Context,
+    triage_file: Optional[Path] = typer.Option(
+        None,
+        "--file",
+        "-f",
+        help="Triage file (default: triage_file from config)",
+    ),
+    finding_type: Optional[str] = typer.Option(
+        None,
+        "--type",
+        "-t",
+        help="Only triage findings of this type",
+    ),
+    min_severity: float = typer.Option(
+        0.0,
+        "--min-severity",
+        help="Skip findings below this severity",
+        min=0.0,
+        max=1.0,
+    ),
+    context: int = typer.Option(
+        5,
+        "--context",
+        "-C",
+        help="Lines of code shown around the finding",
+        min=0,
+        max=50,
+    ),
+):
+    """
+    Walk through findings not triaged yet, one at a time.
+
+    For each finding, shows the code around it, its evidence and similar
+    findings that were fixed or triaged before, then asks what to do:
+
+      [bold]d[/bold]ismiss   not a problem here; hidden from later analyses
+      [bold]s[/bold]uppress  hide this finding type on this file
+      [bold]a[/bold]ssign    record who owns the fix
+      [bold]t[/bold]icket    open a new issue (triage_ticket_url) and record it
+      [bold]n[/bold]ext      decide later; [bold]q[/bold]uit stops the session
+
+    All decisions are written to the triage file (commit it) when the
+    session ends.
+
+    [bold cyan]Examples:[/bold cyan]
+
+      shannon-insight triage
+
+      shannon-insight triage --type god_class --min-severity 0.6
+    """
+    from ..api import analyze
+    from ..config import load_config
+    from ..insights.triage import ACTIONS, load_triage, save_triage, untriaged
+
+    obj = ctx.obj or {}
+    root = obj.get("path", Path.cwd()).resolve()
+    config_file = obj.get("config")
+    settings = load_config(config_file=config_file)
+    path = triage_file or root / settings.triage_file
+    try:
+        decisions = load_triage(path)
+    except (ValueError, KeyError, TypeError) as e:
+        console.print(f"[red]Error:[/red] cannot read {path}: {e}")
+        raise typer.Exit(2)
+
+    _, snapshot = analyze(
+        path=str(root), config_file=config_file, max_findings=_MAX_FINDINGS, quiet=True
+    )
+    pending = [
+        r
+        for r in untriaged(snapshot.findings, decisions)
+        if r.severity >= min_severity and (finding_type is None or r.finding_type == finding_type)
+    ]
+    if not pending:
+        console.print("[green]No findings left to triage.[/green]")
+        return
+
+    resolved = _resolved_findings(root, settings, {r.finding_type for r in pending})
+    made: Counter[str] = Counter()
+    try:
+        for index, record in enumerate(pending, 1):
+            action = _triage_one(
+                root, record, f"{index}/{len(pending)}", decisions, resolved, settings, context
+            )
+            if action == "quit":
+                break
+            if action != "next":
+                made[action] += 1
+    except (KeyboardInterrupt, typer.Abort):
+        console.print()  # keep the decisions made so far
+
+    if not made:
+        console.print("[dim]No decisions made; triage file unchanged.[/dim]")
+        return
+    save_triage(path, decisions)
+    summary = ", ".join(f"{made[a]} {a}" for a in ACTIONS if made[a])
+    console.print(f"[green]Recorded {summary} in {path}[/green]")
+
+
+def _triage_one(root, record, position, decisions, resolved, settings, context) -> str:
+    """Show one finding, ask what to do and record it; the action chosen."""
+    from ..insights.triage import Decision, code_excerpt, similar_cases
+    from ._finding_display import get_display_config, get_severity_display
+
+    display = get_display_config(record.finding_type)
+    icon, color, label = get_severity_display(record.severity)
+    console.print()
+    console.rule(f"[bold]{position}[/bold]")
+    console.print(
+        f"{display['icon']} [{display['color']}]{display['label']}[/{display['color']}]  "
+        f"{icon} [{color}]{label}[/{color}] ({record.severity:.2f})"
+    )
+    console.print(f"[bold]{record.title}[/bold]")
+    for file in record.files[:5]:
+        console.print(f"  [cyan]{file}[/cyan]")
+    if len(record.files) > 5:
+        console.print(f"  [dim]... and {len(record.files) - 5} more[/dim]")
+
+    excerpt = code_excerpt(root, record, context) if context else None
+    if excerpt:
+        _, start, lines = excerpt
+        console.print()
+        for number, line in enumerate(lines, start):
+            console.print(f"[dim]{number:>5}[/dim]  {line}", markup=False, highlight=False)
+
+    if record.evidence:
+        table = Table(show_header=False, box=None, pad_edge=False)
+        table.add_column(style="dim")
+        table.add_column()
+        for e in record.evidence:
+            table.add_row(e.signal, e.description or f"{e.value:g}")
+        console.print()
+        console.print(table)
+
+    cases = similar_cases(record, resolved.get(record.finding_type, []), decisions)
+    if cases:
+        console.print()
+        console.print("[bold]Similar cases[/bold]")
+        for case in cases:
+            console.print(f"  [dim]{case.outcome}[/dim]  {case.title}")
+
+    console.print()
+    choice = typer.prompt(
+        "[d]ismiss [s]uppress [a]ssign [t]icket [n]ext [q]uit",
+        default="n",
+        type=typer.Choice(list(_CHOICES), case_sensitive=False),
+        show_choices=False,
+    ).lower()
+    action = _CHOICES[choice]
+    if action in ("next", "quit"):
+        return action
+
+    decision = Decision(
+        identity_key=record.identity_key,
+        finding_type=record.finding_type,
+        title=record.title,
+        files=list(record.files),
+        action=action,
+        decided=date.today().isoformat(),
+    )
+    if action == "assign":
+        decision.owner = typer.prompt("Owner")
+    elif action == "ticket":
+        decision.ticket = _open_ticket(record, settings)
+    decision.reason = typer.prompt("Reason (optional)", default="", show_default=False)
+    decisions.decide(decision)
+    return action
+
+
+def _open_ticket(record, settings) -> str:
+    """Open a new-issue page when triage_ticket_url is set; the reference to record."""
+    import webbrowser
+
+    from ..insights.triage import ticket_url
+
+    if settings.triage_ticket_url:
+        url = ticket_url(settings.triage_ticket_url, record)
+        if not webbrowser.open(url):
+            console.print(f"Open this URL to file the ticket:\n{url}", markup=False)
+    return typer.prompt("Ticket (URL or id)")
+
+
+def _resolved_findings(root: Path, settings, finding_types: set[str]) -> dict[str, list]:
+    """Fixed findings of each type from the history store; none without history."""
+    from ..persistence import HistoryDB
+    from ..persistence.queries import get_resolved_findings
+
+    db = HistoryDB(str(root), url=settings.history_url)
+    if not db.exists():
+        return {}
+    try:
+        with db:
+            return {t: get_resolved_findings(db.conn, t) for t in sorted(finding_types)}
+    except Exception as e:
+        console.print(f"[yellow]Similar cases unavailable: {e}[/yellow]")
+        return {}
@@ -18,6 +18,7 @@ Example:
 from __future__ import annotations
 
 import os
+import re
 from dataclasses import dataclass, field
 from pathlib import Path
 from typing import Any, Literal, Optional, get_type_hints
@@ -26,6 +27,12 @@ from .exceptions import ShannonInsightError
 
 # Type aliases for clarity
 Verbosity = Literal["quiet", "normal", "verbose"]
+ComplexityNormalization = Literal["none", "function_length", "decision_point"]
+TokenEntropyScope = Literal["all", "identifiers"]
+TokenEntropyNormalization = Literal["none", "length"]
+BaselineRotation = Literal["off", "schedule", "merge"]
+CConditionals = Literal["evaluate", "all"]
+GoConstraints = Literal["evaluate", "all"]
 
 
 @dataclass(frozen=True)
@@ -45,7 +52,13 @@ class ThresholdConfig:
         Clone Detection:
             clone_ncd_threshold: NCD below this = clone (0.0=identical, 1.0=different)
             clone_min_lines: Minimum lines to consider for clone detection
-            clone_lsh_file_threshold: File count to switch to LSH pre-filtering
+            clone_lsh_file_threshold: File count at which candidate pairs come
+                from winnowing fingerprints instead of all-pairs comparison
+            clone_guarantee_tokens: Copied runs of at least this many tokens
+                are always found by winnowing (higher = less memory)
+            clone_noise_tokens: Copied runs shorter than this are ignored
+            clone_min_tokens: Shortest run of tokens reported as a type-1/type-2
+                code_clone and counted in duplication
 
         Hub Detection (HIGH_RISK_HUB):
             hub_pagerank_pctl: PageRank percentile threshold
@@ -87,6 +100,9 @@ class ThresholdConfig:
     clone_ncd_threshold: float = 0.30
     clone_min_lines: int = 20  # Skip trivial files (was 10 bytes)
     clone_lsh_file_threshold: int = 1000
+    clone_guarantee_tokens: int = 40  # Winnowing guarantee threshold t
+    clone_noise_tokens: int = 8  # Winnowing noise threshold k
+    clone_min_tokens: int = 50  # Token-based clone classes (signals/duplication.py)
 
     # === Hub Detection (HIGH_RISK_HUB) ===
     # IQR-based: Q3 + 0.5×IQR ≈ 87th percentile, using 0.90 for safety
@@ -188,6 +204,12 @@ class ThresholdConfig:
         # Positive integers
         if self.clone_min_lines < 1:
             raise ValueError("clone_min_lines must be at least 1")
+        if self.clone_noise_tokens < 1:
+            raise ValueError("clone_noise_tokens must be at least 1")
+        if self.clone_guarantee_tokens < self.clone_noise_tokens:
+            raise ValueError("clone_guarantee_tokens must be at least clone_noise_tokens")
+        if self.clone_min_tokens < 1:
+            raise ValueError("clone_min_tokens must be at least 1")
         if self.tier_absolute_limit < 1:
             raise ValueError("tier_absolute_limit must be at least 1")
 
@@ -212,6 +234,8 @@ class AnalysisConfig:
         Performance tuning:
             workers: Number of parallel workers (None = auto-detect)
             timeout_seconds: Timeout for file operations
+            analyzer_workers: Analyzers run concurrently once their inputs
+                are ready (1 = one at a time)
 
         Caching:
             cache_enabled: Enable disk caching for faster re-analysis
@@ -222,6 +246,11 @@ class AnalysisConfig:
             exclude_patterns: Glob patterns to exclude from analysis
             max_file_size_mb: Maximum file size to analyze (MB)
             max_files: Maximum number of files to analyze
+            collapse_identical_files: Analyze content-identical files (vendored
+                or copied trees) once and report the copies as duplicate_files
+            include_generated: Score generated files (protoc output, files with
+                a "Code generated" header, minified JS) like any other; by
+                default they are left out of entropy and complexity scoring
 
         Git integration:
             git_max_commits: Maximum commits to analyze (0 = unlimited)
@@ -230,10 +259,157 @@ class AnalysisConfig:
         Output control:
             max_findings: Maximum findings to return
             verbosity: Logging verbosity level
+            collapse_noisy_findings: Collapse a finding type that fires on
+                most files of a directory subtree (generated or third-party
+                code) into one finding for the directory
+            noise_threshold: Share of a subtree's files a finding type must
+                fire on for its findings there to be collapsed
+            noise_min_files: Fewest files a finding type must fire on in a
+                subtree for its findings there to be collapsed
+
+        Metric fairness:
+            complexity_normalization: How the complexity term of cognitive_load
+                is normalized for function size: "none", "function_length"
+                (discount long functions) or "decision_point" (mean cost per
+                decision point, weighted by nesting)
+            maintainability_weights: Overrides for the weights of the
+                Maintainability Index terms: volume, complexity, lines and
+                comments; see shannon_insight.signals.maintainability
+            health_formula: Weighted formula codebase_health is computed
+                with, over architecture, wiring, bus_factor, modularity,
+                duplication, team, nesting, churn and maintainability
+                ("0.4*nesting + 0.3*duplication + 0.3*churn"; "" = the
+                default). Health labels follow it; file ranking and
+                finding severities do not. See
+                shannon_insight.signals.composites
+            token_entropy_scope: Tokens token_entropy is computed over:
+                "all" or "identifiers" (words that are not keywords)
+            token_entropy_normalization: "none" (bits) or "length" (divided
+                by log2 of the token count, so files of any length score 0-1)
+            nesting_threshold: Functions nested deeper than this many levels
+                are reported as deep_nesting, whatever their complexity
+            fan_in_threshold: Functions called by more than this many others
+                are reported as load_bearing_function
+            fan_out_threshold: Functions calling more than this many others
+                are reported as orchestrator_function
+            parameter_threshold: Functions taking more than this many
+                parameters are reported as long_parameter_list
+
+        C/C++ preprocessor:
+            c_defines: Macros defined when choosing #if/#ifdef branches, as
+                NAME or NAME=VALUE (like -D); __cplusplus is defined for C++
+            c_conditionals: "evaluate" parses only the branches those macros
+                select, "all" parses every branch as written
+
+        Go build configuration:
+            go_os: GOOS the Go files are built for
+            go_arch: GOARCH the Go files are built for
+            go_tags: Extra build tags (like -tags)
+            go_cgo: Whether cgo is enabled (CGO_ENABLED=1)
+            go_constraints: "evaluate" skips the Go files this configuration
+                does not compile (//go:build, _GOOS/_GOARCH names, cgo),
+                "all" analyzes every file
+
+        Custom grammars:
+            grammars: Compiled tree-sitter grammars for languages without
+                built-in support, keyed by language name, each a table with
+                library, extensions and the node types read as function,
+                class, import, call and nesting; see
+                shannon_insight.scanning.grammars
+
+        Style rules:
+            naming_rules: Per-language naming convention overrides, keyed by
+                language ({"go": {"abbreviations": "upper"}}); see
+                shannon_insight.hygiene.naming for keys and defaults
+            spelling_allowlist: Words never reported by the spelling check
+            license_header: Required license header text, without comment
+                markers; {year} and {owner} are placeholders ("" disables)
+            license_owner: Copyright owner substituted for {owner}
+            literal_repeat_threshold: String and number literals used more
+                than this many times across the codebase are reported as
+                repeated_literal findings
+
+        Coverage:
+            coverage_report: Coverage report (Cobertura XML, LCOV or a Go
+                cover profile) joined with function complexity; empty means
+                coverage.xml, lcov.info, coverage/lcov.info, coverage.out or
+                cover.out under the root, when present
+
+        Architecture rules:
+            hexagonal: Ports-and-adapters rings as path patterns (domain,
+                ports, adapters) and an optional allow table of the rings
+                each may import; see shannon_insight.architecture.hexagonal
+
+        Security:
+            crypto_policy: Overrides for the crypto policy: banned_algorithms,
+                min_key_bits (merged per algorithm) and approved_libraries;
+                see shannon_insight.hygiene.crypto for the defaults
+            pii_fields: Fields holding personal data, as "Model.field" or
+                "field" -> category ({"User.email": "contact"}), for
+                ``shannon-insight pii``
+
+        Quality gate:
+            ratchet_file: Per-file metric ceilings for ``gate --ratchet``,
+                relative to the project root (commit it)
+            ratchet_metrics: Numeric file signals whose ceilings are ratcheted
+            gate_fast_budget_seconds: Time budget for ``gate --fast``; files not
+                checked within it make the gate incomplete (exit 2)
+            gate_rollout: Rules (finding types or ratchet metrics) that only
+                warn for their first ``days`` days and/or ``runs`` gate runs
+                ({"deep_nesting": {"days": 14}}); see
+                shannon_insight.gate.rollout
+
+        Triage:
+            triage_file: Decisions from ``shannon-insight triage`` (dismiss,
+                suppress, assign, ticket), relative to the project root
+                (commit it); dismissed and suppressed findings are hidden
+            triage_ticket_url: New-issue URL opened for "ticket", with
+                {title}, {body}, {type} and {file} filled in
+                ("https://github.com/org/repo/issues/new?title={title}&body={body}")
+
+        Webhooks:
+            webhooks: Endpoints receiving finding_created, finding_resolved
+                and gate events, each a table with url, events,
+                finding_types, min_severity, headers and template; see
+                shannon_insight.webhooks
+
+        Baseline rotation (serve mode):
+            baseline_rotation: "off", "schedule" (re-baseline every
+                baseline_interval_minutes) or "merge" (when the branch tip moves)
+            baseline_interval_minutes: Period for "schedule" rotation
+            baseline_branch: Mainline ref to baseline ("" = origin/HEAD, main, master)
+
+        Serve mode:
+            drain_timeout_seconds: On SIGTERM, how long the running analysis may
+                take to finish; queued ones persist and resume on restart
+            job_queue: Analysis queue backend: "" (in memory, snapshotted to
+                .shannon/jobs.json), "memory", or a redis:// or postgresql://
+                URL to share one queue between server replicas
+            history_url: Snapshot history store: "" (.shannon/history.db) or a
+                postgresql:// URL shared by server replicas; used by every
+                command that reads or writes history
+
+        History retention (``history prune``):
+            history_keep_all_days: Keep every snapshot younger than this
+            history_keep_daily_days: Then the last snapshot of each day
+            history_keep_weekly_days: Then the last snapshot of each week;
+                older snapshots are removed, their signals kept as rollups
+            history_auto_prune: Prune once a day while serving
+
+        Remote repositories:
+            remote_mirror_dir: Where bare mirrors of analyzed URLs are cached
+                and fetched on reuse ("" = shallow fetch into a temporary
+                directory each time, without git history)
 
         Feature flags:
             enable_validation: Enable phase validation contracts
             enable_history: Auto-save snapshots to .shannon/ directory
+            enable_comment_debt: Track TODO/FIXME/HACK/XXX comments (with git
+                blame age and owner) as comment_debt findings
+            disabled_analyzers: Optional analyzers to skip, by name
+                (e.g. ["literals"]); their findings and
+                snapshot signals are left out. See
+                shannon_insight.insights.analyzers.OPTIONAL_ANALYZERS
 
         Provenance tracking:
             enable_provenance: Enable signal provenance tracking (off by default)
@@ -252,6 +428,7 @@ class AnalysisConfig:
     # Performance tuning
     workers: Optional[int] = None  # None = auto-detect from CPU cores
     timeout_seconds: int = 10
+    analyzer_workers: int = 4
 
     # Caching
     cache_enabled: bool = True
@@ -290,6 +467,8 @@ class AnalysisConfig:
     )
     max_file_size_mb: float = 10.0
     max_files: int = 10000
+    collapse_identical_files: bool = True
+    include_generated: bool = False
 
     # Git integration
     git_max_commits: int = 5000
@@ -298,10 +477,89 @@ class AnalysisConfig:
     # Output control
     max_findings: int = 50
     verbosity: Verbosity = "normal"
+    collapse_noisy_findings: bool = True
+    noise_threshold: float = 0.8
+    noise_min_files: int = 10
+
+    # Metric fairness
+    complexity_normalization: ComplexityNormalization = "none"
+    maintainability_weights: dict[str, float] = field(default_factory=dict)
+    health_formula: str = ""
+    token_entropy_scope: TokenEntropyScope = "all"
+    token_entropy_normalization: TokenEntropyNormalization = "none"
+    nesting_threshold: int = 4
+    fan_in_threshold: int = 10
+    fan_out_threshold: int = 10
+    parameter_threshold: int = 5
+
+    # C/C++ preprocessor
+    c_defines: list[str] = field(default_factory=list)
+    c_conditionals: CConditionals = "evaluate"
+
+    # Go build configuration
+    go_os: str = "linux"
+    go_arch: str = "amd64"
+    go_tags: list[str] = field(default_factory=list)
+    go_cgo: bool = True
+    go_constraints: GoConstraints = "evaluate"
+
+    # Custom grammars
+    grammars: dict[str, dict[str, Any]] = field(default_factory=dict)
+
+    # Style rules
+    naming_rules: dict[str, dict[str, str]] = field(default_factory=dict)
+    spelling_allowlist: list[str] = field(default_factory=list)
+    license_header: str = ""
+    license_owner: str = ""
+    literal_repeat_threshold: int = 4
+
+    # Coverage
+    coverage_report: str = ""
+
+    # Architecture rules
+    hexagonal: dict[str, Any] = field(default_factory=dict)
+
+    # Security
+    crypto_policy: dict[str, Any] = field(default_factory=dict)
+    pii_fields: dict[str, str] = field(default_factory=dict)
+
+    # Quality gate
+    ratchet_file: str = "shannon-ratchet.json"
+    ratchet_metrics: list[str] = field(default_factory=lambda: ["cognitive_load", "max_nesting"])
+    gate_fast_budget_seconds: int = 30
+    gate_rollout: dict[str, dict[str, int]] = field(default_factory=dict)
+
+    # Triage
+    triage_file: str = "shannon-triage.json"
+    triage_ticket_url: str = ""
+
+    # Webhooks
+    webhooks: list[dict[str, Any]] = field(default_factory=list)
+
+    # Baseline rotation (serve mode)
+    baseline_rotation: BaselineRotation = "off"
+    baseline_interval_minutes: int = 60
+    baseline_branch: str = ""
+
+    # Serve mode
+    drain_timeout_seconds: int = 30
+    job_queue: str = ""
+    history_url: str = ""
+
+    # History retention
+    history_keep_all_days: int = 1
+    history_keep_daily_days: int = 90
+    history_keep_weekly_days: int = 730
+    history_auto_prune: bool = False
+
+    # Remote repositories
+    remote_mirror_dir: str = ""
 
     # Feature flags
     enable_validation: bool = True
     enable_history: bool = True
+    enable_comment_debt: bool = True
+    disabled_analyzers: list[str] = field(default_factory=list)
 
     # Provenance tracking
     enable_provenance: bool = False
@@ -329,6 +587,8 @@ class AnalysisConfig:
             raise ValueError("workers must be at least 1")
         if self.timeout_seconds < 1:
             raise ValueError("timeout_seconds must be at least 1")
+        if not 1 <= self.analyzer_workers <= 32:
+            raise ValueError("analyzer_workers must be between 1 and 32")
 
         # Validate cache parameters
         if self.cache_ttl_hours < 0:
@@ -349,6 +609,140 @@ class AnalysisConfig:
         # Validate output
         if self.max_findings < 1:
             raise ValueError("max_findings must be at least 1")
+        if not 0.0 < self.noise_threshold <= 1.0:
+            raise ValueError("noise_threshold must be between 0 (exclusive) and 1")
+        if self.noise_min_files < 2:
+            raise ValueError("noise_min_files must be at least 2")
+
+        # Validate metric normalization
+        if self.complexity_normalization not in ("none", "function_length", "decision_point"):
+            raise ValueError(
+                "complexity_normalization must be one of: none, function_length, decision_point"
+            )
+        from .signals.maintainability import resolve_weights
+
+        resolve_weights(self.maintainability_weights)
+        if self.health_formula:
+            from .signals.composites import parse_health_formula
+
+            parse_health_formula(self.health_formula)
+        if self.token_entropy_scope not in ("all", "identifiers"):
+            raise ValueError("token_entropy_scope must be one of: all, identifiers")
+        if self.token_entropy_normalization not in ("none", "length"):
+            raise ValueError("token_entropy_normalization must be one of: none, length")
+        if self.nesting_threshold < 1:
+            raise ValueError("nesting_threshold must be at least 1")
+        if self.fan_in_threshold < 1:
+            raise ValueError("fan_in_threshold must be at least 1")
+        if self.fan_out_threshold < 1:
+            raise ValueError("fan_out_threshold must be at least 1")
+        if self.parameter_threshold < 1:
+            raise ValueError("parameter_threshold must be at least 1")
+        if self.literal_repeat_threshold < 1:
+            raise ValueError("literal_repeat_threshold must be at least 1")
+
+        # Validate feature flags
+        if self.disabled_analyzers:
+            from .insights.analyzers import OPTIONAL_ANALYZERS
+
+            unknown = sorted(set(self.disabled_analyzers) - set(OPTIONAL_ANALYZERS))
+            if unknown:
+                raise ValueError(
+                    f"disabled_analyzers: unknown analyzers {', '.join(unknown)} "
+                    f"(known: {', '.join(OPTIONAL_ANALYZERS)})"
+                )
+
+        # Validate C/C++ preprocessor
+        if self.c_conditionals not in ("evaluate", "all"):
+            raise ValueError("c_conditionals must be one of: evaluate, all")
+        from .scanning.preprocessor import parse_defines
+
+        try:
+            parse_defines(self.c_defines)
+        except ValueError as e:
+            raise ValueError(f"c_defines: {e}") from None
+
+        # Validate Go build configuration
+        if self.go_constraints not in ("evaluate", "all"):
+            raise ValueError("go_constraints must be one of: evaluate, all")
+        from .scanning.gobuild import KNOWN_ARCH, KNOWN_OS
+
+        if self.go_os not in KNOWN_OS:
+            raise ValueError(f"go_os: unknown GOOS {self.go_os!r}")
+        if self.go_arch not in KNOWN_ARCH:
+            raise ValueError(f"go_arch: unknown GOARCH {self.go_arch!r}")
+        bad_tags = [t for t in self.go_tags if not re.fullmatch(r"[\w.]+", t)]
+        if bad_tags:
+            raise ValueError(f"go_tags: invalid build tag {bad_tags[0]!r}")
+
+        # Validate custom grammars
+        from .scanning.grammars import parse_grammars
+
+        parse_grammars(self.grammars)
+
+        # Validate style rules
+        if not all(isinstance(rule, dict) for rule in self.naming_rules.values()):
+            raise ValueError("naming_rules must map language names to tables")
+
+        # Validate architecture rules
+        from .architecture.hexagonal import resolve_spec
+
+        resolve_spec(self.hexagonal)
+
+        # Validate security
+        from .hygiene.crypto import resolve_policy
+
+        resolve_policy(self.crypto_policy)
+        from .graph.pii import parse_pii_fields
+
+        parse_pii_fields(self.pii_fields)
+
+        # Validate quality gate
+        if not self.ratchet_file:
+            raise ValueError("ratchet_file must not be empty")
+        if not self.ratchet_metrics:
+            raise ValueError("ratchet_metrics must name at least one signal")
+        if not 1 <= self.gate_fast_budget_seconds <= 3600:
+            raise ValueError("gate_fast_budget_seconds must be between 1 and 3600")
+        from .gate.rollout import parse_rollout
+
+        parse_rollout(self.gate_rollout)
+
+        # Validate triage
+        if not self.triage_file:
+            raise ValueError("triage_file must not be empty")
+
+        # Validate webhooks
+        from .webhooks import parse_webhooks
+
+        parse_webhooks(self.webhooks)
+
+        # Validate baseline rotation
+        if self.baseline_rotation not in ("off", "schedule", "merge"):
+            raise ValueError("baseline_rotation must be one of: off, schedule, merge")
+        if not 1 <= self.baseline_interval_minutes <= 10080:
+            raise ValueError("baseline_interval_minutes must be between 1 and 10080")
+        if not 0 <= self.drain_timeout_seconds <= 3600:
+            raise ValueError("drain_timeout_seconds must be between 0 and 3600")
+        if self.job_queue not in ("", "memory") and not self.job_queue.startswith(
+            ("redis://", "rediss://", "unix://", "postgres://", "postgresql://")
+        ):
+            raise ValueError("job_queue must be '', 'memory', or a redis:// or postgresql:// URL")
+        if self.history_url and not self.history_url.startswith(("postgres://", "postgresql://")):
+            raise ValueError("history_url must be '' or a postgresql:// URL")
+
+        # Validate history retention
+        if not (
+            0
+            <= self.history_keep_all_days
+            <= self.history_keep_daily_days
+            <= self.history_keep_weekly_days
+            <= 36500
+        ):
+            raise ValueError(
+                "history retention must satisfy 0 <= history_keep_all_days <= "
+                "history_keep_daily_days <= history_keep_weekly_days <= 36500"
+            )
 
         # Validate provenance
         if self.provenance_retention_hours < 0:
@@ -365,19 +759,24 @@ class AnalysisConfig:
         return self.cache_ttl_hours * 3600
 
 
-def load_config(config_file: Optional[Path] = None, **overrides) -> AnalysisConfig:
+def load_config(
+    config_file: Optional[Path] = None,
+    project_dir: Optional[Path] = None,
+    **overrides,
+) -> AnalysisConfig:
     """Load configuration with auto-discovery and merging.
 
     Configuration sources are merged in priority order (lowest to highest):
         1. Defaults (AnalysisConfig field defaults)
         2. Global config (~/.shannon-insight.toml)
-        3. Project config (./shannon-insight.toml)
+        3. Project config (shannon-insight.toml in project_dir, default cwd)
         4. Explicit config file (if config_file provided)
         5. Environment variables (SHANNON_* prefix)
         6. CLI overrides (kwargs)
 
     Args:
         config_file: Optional explicit config file path
+        project_dir: Directory holding the project config (default: cwd)
         **overrides: Direct overrides (typically from CLI flags)
 
     Returns:
@@ -405,7 +804,7 @@ def load_config(config_file: Optional[Path] = None, **overrides) -> AnalysisConf
             raise ShannonInsightError(f"Invalid global config '{global_config}': {e}")
 
     # 2. Try project config
-    project_config = Path.cwd() / "shannon-insight.toml"
+    project_config = (project_dir or Path.cwd()) / "shannon-insight.toml"
     if project_config.exists():
         try:
             merged.update(_load_toml_file(project_config))
@@ -536,8 +935,8 @@ def _parse_env_value(value: str, type_hint: Any, field_name: str) -> Any:
         if non_none_types:
             type_hint = non_none_types[0]
 
-    # Skip list types (like exclude_patterns) - too complex for env vars
-    if origin is list or type_hint is list:
+    # Skip list/dict types (like exclude_patterns) - too complex for env vars
+    if origin in (list, dict) or type_hint in (list, dict):
         return None
 
     # Bool: accept true/false/1/0/yes/no
@@ -0,0 +1,144 @@
+"""Line coverage ingestion from common report formats.
+
+Supported inputs (auto-detected by content):
+    - Cobertura XML (``coverage.xml`` from coverage.py/pytest-cov,
+      gocover-cobertura, ...)
+    - LCOV tracefiles (``lcov.info``)
+    - Go cover profiles (``go test -coverprofile=coverage.out``)
+
+Paths are normalized to be relative to the analyzed root with forward
+slashes, so they line up with FileSyntax paths. Go profiles name files by
+import path; the module path from ``go.mod`` is stripped from them.
+"""
+
+from __future__ import annotations
+
+import re
+import xml.etree.ElementTree as ET
+from pathlib import Path, PurePosixPath
+
+from .logging_config import get_logger
+
+logger = get_logger(__name__)
+
+# Searched in order when no explicit report path is given
+DEFAULT_REPORTS = ("coverage.xml", "lcov.info", "coverage/lcov.info", "coverage.out", "cover.out")
+
+# path -> {line_number: hit_count}
+LineCoverage = dict[str, dict[int, int]]
+
+
+def find_report(root: Path) -> Path | None:
+    """First default coverage report present under *root*, if any."""
+    for name in DEFAULT_REPORTS:
+        candidate = root / name
+        if candidate.is_file():
+            return candidate
+    return None
+
+
+def load_line_coverage(root: Path, report: Path | None = None) -> LineCoverage:
+    """Load per-line hit counts, or an empty mapping if no report is usable."""
+    report = report or find_report(root)
+    if report is None:
+        return {}
+    try:
+        text = report.read_text(encoding="utf-8", errors="replace")
+    except OSError as e:
+        logger.debug(f"Cannot read coverage report {report}: {e}")
+        return {}
+
+    try:
+        if text.lstrip().startswith("<"):
+            return _parse_cobertura(text, root)
+        if text.lstrip().startswith("mode:"):
+            return _parse_go_profile(text, root)
+        return _parse_lcov(text, root)
+    except Exception as e:
+        logger.warning(f"Unparseable coverage report {report}: {e}")
+        return {}
+
+
+def _relative(path: str, root: Path, sources: list[str]) -> str:
+    """Best-effort mapping of a report path onto a root-relative path."""
+    candidates = [Path(path)] + [Path(src) / path for src in sources]
+    for candidate in candidates:
+        try:
+            if candidate.is_absolute():
+                return PurePosixPath(candidate.resolve().relative_to(root.resolve())).as_posix()
+        except ValueError:
+            continue
+        if (root / candidate).exists():
+            return PurePosixPath(candidate).as_posix()
+    return PurePosixPath(path.replace("\\", "/")).as_posix()
+
+
+def _parse_cobertura(text: str, root: Path) -> LineCoverage:
+    tree = ET.fromstring(text)
+    sources = [s.text.strip() for s in tree.iter("source") if s.text]
+    result: LineCoverage = {}
+    for cls in tree.iter("class"):
+        filename = cls.get("filename")
+        if not filename:
+            continue
+        lines = result.setdefault(_relative(filename, root, sources), {})
+        for line in cls.iter("line"):
+            number, hits = line.get("number"), line.get("hits")
+            if number is not None and hits is not None:
+                lines[int(number)] = lines.get(int(number), 0) + int(hits)
+    return result
+
+
+def _parse_lcov(text: str, root: Path) -> LineCoverage:
+    result: LineCoverage = {}
+    current: dict[int, int] | None = None
+    for raw in text.splitlines():
+        line = raw.strip()
+        if line.startswith("SF:"):
+            current = result.setdefault(_relative(line[3:], root, []), {})
+        elif line.startswith("DA:") and current is not None:
+            parts = line[3:].split(",")
+            if len(parts) >= 2:
+                number, hits = int(parts[0]), int(parts[1])
+                current[number] = current.get(number, 0) + hits
+        elif line == "end_of_record":
+            current = None
+    return result
+
+
+# path:startLine.startCol,endLine.endCol statements count
+_GO_BLOCK_RE = re.compile(
+    r"^(?P<path>.+):(?P<start>\d+)\.\d+,(?P<end>\d+)\.\d+ \d+ (?P<count>\d+)$"
+)
+
+
+def _parse_go_profile(text: str, root: Path) -> LineCoverage:
+    module = _go_module(root)
+    result: LineCoverage = {}
+    paths: dict[str, str] = {}
+    for raw in text.splitlines():
+        match = _GO_BLOCK_RE.match(raw.strip())
+        if match is None:
+            continue
+        path = match["path"]
+        if path not in paths:
+            if module and path.startswith(f"{module}/"):
+                paths[path] = path[len(module) + 1 :]
+            else:
+                paths[path] = _relative(path, root, [])
+        lines = result.setdefault(paths[path], {})
+        count = int(match["count"])
+        # Blocks share their boundary lines; a line counts as run if any block on it ran
+        for number in range(int(match["start"]), int(match["end"]) + 1):
+            lines[number] = max(lines.get(number, 0), count)
+    return result
+
+
+def _go_module(root: Path) -> str:
+    """Module path declared in root/go.mod, or "" without one."""
+    try:
+        text = (root / "go.mod").read_text(encoding="utf-8", errors="replace")
+    except OSError:
+        return ""
+    match = re.search(r"^module\s+(\S+)", text, re.MULTILINE)
+    return match[1].strip('"') if match else ""
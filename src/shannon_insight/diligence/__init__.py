@@ -0,0 +1,22 @@
+"""Due-diligence reports: an executive summary of a codebase for acquirers.
+
+``shannon-insight --preset due-diligence`` bundles size, language mix,
+complexity distribution, bus factor, license scan, dependency freshness
+and test ratio into one report, with the key risks up front.
+
+Usage:
+    from shannon_insight.api import analyze
+    from shannon_insight.diligence import build_report
+
+    result, snapshot = analyze("/path/to/repo")
+    titles = [f.title for f in result.findings]
+    report = build_report(Path("/path/to/repo"), snapshot, titles)
+    print(report.render_markdown())
+"""
+
+from .report import DiligenceReport, build_report
+
+__all__ = [
+    "DiligenceReport",
+    "build_report",
+]
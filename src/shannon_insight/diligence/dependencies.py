@@ -0,0 +1,298 @@
+"""Declared dependencies and how far behind their latest releases they are.
+
+Manifests are read wherever they are in the tree (monorepos have several):
+
+    pypi   requirements*.txt, pyproject.toml ([project] and Poetry tables)
+    npm    package.json (dependencies and devDependencies)
+    go     go.mod (direct requirements; ``// indirect`` ones are skipped)
+    cargo  Cargo.toml ([dependencies], [dev-dependencies], [build-dependencies])
+
+Path, git and workspace dependencies are not registry packages and are left
+out. The latest release of each package comes from its public registry;
+lookups that fail (offline, private package) leave the status unknown.
+"""
+
+from __future__ import annotations
+
+import json
+import re
+import urllib.error
+import urllib.request
+from concurrent.futures import ThreadPoolExecutor
+from dataclasses import dataclass
+from pathlib import Path, PurePosixPath
+from typing import Any, Callable, Optional
+from urllib.parse import quote
+
+from ..logging_config import get_logger
+from .inventory import tracked_files
+
+logger = get_logger(__name__)
+
+# (ecosystem, package name) -> latest version, or None if unknown
+LatestLookup = Callable[[str, str], Optional[str]]
+
+CURRENT = "current"
+OUTDATED = "outdated"  # behind, but within the same major version
+MAJOR_BEHIND = "major behind"
+UNPINNED = "unpinned"  # no minimum version declared
+UNKNOWN = "unknown"
+
+_REGISTRY_TIMEOUT_SECONDS = 5
+_REQUIREMENTS_RE = re.compile(r"^requirements[\w.-]*\.txt$")
+_PEP508_RE = re.compile(r"^\s*([A-Za-z0-9][\w.-]*)\s*(?:\[[^\]]*\])?\s*([^;]*)")
+_VERSION_RE = re.compile(r"v?(\d+(?:\.\d+)*)")
+# The lower bound of a Python or npm version range
+_LOWER_BOUND_RE = re.compile(r"(?:^|[\s,])(?:===?|~=|>=|>|\^|~|v)?\s*(\d+(?:\.\d+)*)")
+
+
+@dataclass(frozen=True)
+class Dependency:
+    """A dependency declared in a manifest.
+
+    Attributes:
+        name: Package name as the registry knows it
+        ecosystem: "pypi", "npm", "go" or "cargo"
+        spec: Version requirement as written ("" = any)
+        manifest: Manifest path relative to the root
+        dev: Development-only (devDependencies, dev extras...)
+    """
+
+    name: str
+    ecosystem: str
+    spec: str
+    manifest: str
+    dev: bool = False
+
+    @property
+    def declared(self) -> Optional[str]:
+        """Lowest version the requirement allows (``1.4`` for ``>=1.4,<2``)."""
+        if self.ecosystem in ("go", "cargo"):
+            match = _VERSION_RE.search(self.spec)
+            return match.group(1) if match else None
+        match = _LOWER_BOUND_RE.search(self.spec)
+        if match is None or self.spec.lstrip().startswith("<"):
+            return None
+        return match.group(1)
+
+
+@dataclass(frozen=True)
+class Freshness:
+    """A dependency, its latest release and how far behind it is."""
+
+    dependency: Dependency
+    latest: Optional[str]
+    status: str
+
+
+def find_dependencies(root: Path) -> list[Dependency]:
+    """Dependencies declared in every manifest under *root*."""
+    parsers: dict[str, Callable[[str, str], list[Dependency]]] = {
+        "pyproject.toml": _parse_pyproject,
+        "package.json": _parse_package_json,
+        "go.mod": _parse_go_mod,
+        "Cargo.toml": _parse_cargo_toml,
+    }
+    dependencies: list[Dependency] = []
+    for path in tracked_files(root):
+        name = PurePosixPath(path).name
+        parser = _parse_requirements if _REQUIREMENTS_RE.match(name) else parsers.get(name)
+        if parser is None:
+            continue
+        try:
+            text = (root / path).read_text(encoding="utf-8", errors="replace")
+            dependencies.extend(parser(text, path))
+        except (OSError, ValueError) as e:
+            logger.debug(f"Cannot parse {path}: {e}")
+    return dependencies
+
+
+def check_freshness(
+    dependencies: list[Dependency],
+    lookup: Optional[LatestLookup] = None,
+    workers: int = 8,
+) -> list[Freshness]:
+    """Compare each dependency with its latest release (lookup=None: registries)."""
+    lookup = lookup or registry_latest
+    packages = sorted({(d.ecosystem, d.name) for d in dependencies})
+    with ThreadPoolExecutor(max_workers=workers) as executor:
+        latest = dict(zip(packages, executor.map(lambda p: lookup(*p), packages)))
+    results = []
+    for dependency in dependencies:
+        newest = latest[(dependency.ecosystem, dependency.name)]
+        results.append(Freshness(dependency, newest, _status(dependency.declared, newest)))
+    return results
+
+
+def registry_latest(ecosystem: str, name: str) -> Optional[str]:
+    """Latest release of a package from its public registry; None on any failure."""
+    urls = {
+        "pypi": f"https://pypi.org/pypi/{quote(name)}/json",
+        "npm": f"https://registry.npmjs.org/{quote(name, safe='@/')}/latest",
+        "cargo": f"https://crates.io/api/v1/crates/{quote(name)}",
+        # The Go proxy escapes capitals as !lower
+        "go": "https://proxy.golang.org/{}/@latest".format(
+            re.sub(r"[A-Z]", lambda m: "!" + m.group(0).lower(), name)
+        ),
+    }
+    url = urls.get(ecosystem)
+    if url is None:
+        return None
+    # crates.io rejects requests without a User-Agent
+    request = urllib.request.Request(url, headers={"User-Agent": "shannon-insight"})
+    try:
+        with urllib.request.urlopen(request, timeout=_REGISTRY_TIMEOUT_SECONDS) as response:
+            data = json.load(response)
+    except (urllib.error.URLError, OSError, ValueError) as e:
+        logger.debug(f"Registry lookup failed for {ecosystem}:{name}: {e}")
+        return None
+    version: Any = {
+        "pypi": lambda d: d["info"]["version"],
+        "npm": lambda d: d["version"],
+        "cargo": lambda d: d["crate"]["max_stable_version"],
+        "go": lambda d: d["Version"],
+    }[ecosystem]
+    try:
+        return str(version(data)).lstrip("v")
+    except (KeyError, TypeError):
+        return None
+
+
+def _status(declared: Optional[str], latest: Optional[str]) -> str:
+    if latest is None:
+        return UNKNOWN
+    if declared is None:
+        return UNPINNED
+    have, newest = _version_tuple(declared), _version_tuple(latest)
+    if not have or not newest:
+        return UNKNOWN
+    # 2.5 and 2.5.0 are the same release
+    width = max(len(have), len(newest))
+    have, newest = have + (0,) * (width - len(have)), newest + (0,) * (width - len(newest))
+    if _breaking_part(newest) > _breaking_part(have):
+        return MAJOR_BEHIND
+    return OUTDATED if newest > have else CURRENT
+
+
+def _version_tuple(version: str) -> tuple[int, ...]:
+    match = _VERSION_RE.match(version.strip())
+    return tuple(int(part) for part in match.group(1).split(".")) if match else ()
+
+
+def _breaking_part(version: tuple[int, ...]) -> tuple[int, ...]:
+    """Components whose change is breaking: the major, or 0.minor before 1.0."""
+    return version[:2] if version[0] == 0 else version[:1]
+
+
+# ── Manifest parsers ───────────────────────────────────────────────
+
+
+def _parse_requirements(text: str, manifest: str) -> list[Dependency]:
+    dev = bool(re.search(r"dev|test|lint|doc", PurePosixPath(manifest).name))
+    dependencies = []
+    for line in text.splitlines():
+        line = line.split(" #")[0].strip()
+        if not line or line.startswith(("#", "-")) or "://" in line:
+            continue
+        dependency = _pep508(line, manifest, dev)
+        if dependency is not None:
+            dependencies.append(dependency)
+    return dependencies
+
+
+def _parse_pyproject(text: str, manifest: str) -> list[Dependency]:
+    data = _load_toml(text)
+    project = data.get("project", {})
+    dependencies = [_pep508(req, manifest, False) for req in project.get("dependencies", [])]
+    for requirements in project.get("optional-dependencies", {}).values():
+        dependencies.extend(_pep508(req, manifest, True) for req in requirements)
+
+    poetry = data.get("tool", {}).get("poetry", {})
+    groups = [(poetry.get("dependencies", {}), False), (poetry.get("dev-dependencies", {}), True)]
+    groups.extend(
+        (group.get("dependencies", {}), True) for group in poetry.get("group", {}).values()
+    )
+    for table, dev in groups:
+        for name, spec in table.items():
+            if name == "python":
+                continue
+            version = spec.get("version") if isinstance(spec, dict) else spec
+            if isinstance(version, str):
+                dependencies.append(Dependency(name, "pypi", version, manifest, dev))
+    return [d for d in dependencies if d is not None]
+
+
+def _parse_package_json(text: str, manifest: str) -> list[Dependency]:
+    data = json.loads(text)
+    dependencies = []
+    for key, dev in (("dependencies", False), ("devDependencies", True)):
+        for name, spec in (data.get(key) or {}).items():
+            # git+https:, file:, link:, workspace:* and user/repo are not registry packages
+            if isinstance(spec, str) and ":" not in spec and "/" not in spec:
+                dependencies.append(Dependency(name, "npm", spec, manifest, dev))
+    return dependencies
+
+
+def _parse_go_mod(text: str, manifest: str) -> list[Dependency]:
+    dependencies = []
+    in_block = False
+    for line in text.splitlines():
+        line = line.strip()
+        if line.startswith("require ("):
+            in_block = True
+            continue
+        if in_block and line == ")":
+            in_block = False
+            continue
+        if line.startswith("require "):
+            line = line[len("require ") :]
+        elif not in_block:
+            continue
+        if "// indirect" in line:
+            continue
+        parts = line.split()
+        if len(parts) >= 2:
+            dependencies.append(Dependency(parts[0], "go", parts[1], manifest))
+    return dependencies
+
+
+def _parse_cargo_toml(text: str, manifest: str) -> list[Dependency]:
+    data = _load_toml(text)
+    dependencies = []
+    for key, dev in (
+        ("dependencies", False),
+        ("dev-dependencies", True),
+        ("build-dependencies", True),
+    ):
+        for name, spec in data.get(key, {}).items():
+            if isinstance(spec, dict):
+                if "path" in spec or "git" in spec or spec.get("workspace"):
+                    continue
+                name = spec.get("package", name)
+                spec = spec.get("version", "")
+            if isinstance(spec, str):
+                dependencies.append(Dependency(name, "cargo", spec, manifest, dev))
+    return dependencies
+
+
+def _pep508(requirement: str, manifest: str, dev: bool) -> Optional[Dependency]:
+    match = _PEP508_RE.match(requirement)
+    if match is None or "@" in match.group(2):  # name @ https://... is a direct reference
+        return None
+    spec = match.group(2).strip().strip("()")
+    return Dependency(match.group(1), "pypi", spec, manifest, dev)
+
+
+def _load_toml(text: str) -> dict[str, Any]:
+    try:
+        import tomllib
+    except ImportError:
+        try:
+            import tomli as tomllib  # type: ignore
+        except ImportError:
+            raise ValueError("TOML support requires Python 3.11+ or 'tomli'") from None
+    try:
+        data: dict[str, Any] = tomllib.loads(text)
+    except tomllib.TOMLDecodeError as e:
+        raise ValueError(str(e)) from None
+    return data
@@ -0,0 +1,85 @@
+"""File inventory for due-diligence reports: every tracked file, tests included.
+
+The analysis itself skips test files and vendored trees, but size, language
+mix and test ratio have to count them (tests) or set them apart (vendor).
+"""
+
+from __future__ import annotations
+
+import os
+import subprocess
+from dataclasses import dataclass
+from pathlib import Path, PurePosixPath
+
+from ..scanning.languages import SKIP_DIRS, detect_language
+from ..scanning.notebook import is_notebook, notebook_source
+from ..semantics.roles import TEST_PATH_PATTERNS
+
+# Checked-in third-party code: not the project's own, but its licenses count
+VENDOR_DIRS = frozenset({"vendor", "third_party"})
+
+
+@dataclass(frozen=True)
+class SourceFile:
+    """One source file: path relative to the root, language, line count."""
+
+    path: str
+    language: str
+    lines: int
+    is_test: bool
+
+
+def tracked_files(root: Path, include_vendored: bool = False) -> list[str]:
+    """All files under *root* (git-tracked when it is a repository), POSIX paths.
+
+    Directories in SKIP_DIRS (vendor, node_modules, build output...) are left
+    out; *include_vendored* keeps vendor/ and third_party/.
+    """
+    skip_dirs = SKIP_DIRS - VENDOR_DIRS if include_vendored else SKIP_DIRS
+    paths: list[str] = []
+    try:
+        result = subprocess.run(
+            ["git", "-C", str(root), "ls-files", "-z"],
+            capture_output=True,
+            text=True,
+            timeout=30,
+        )
+        if result.returncode == 0:
+            paths = [p for p in result.stdout.split("\0") if p]
+    except (subprocess.TimeoutExpired, FileNotFoundError):
+        pass
+    if not paths:
+        for dirpath, dirnames, filenames in os.walk(root):
+            dirnames[:] = [d for d in dirnames if d not in skip_dirs and not d.startswith(".")]
+            rel_dir = Path(dirpath).relative_to(root)
+            paths.extend((rel_dir / name).as_posix() for name in filenames)
+    return sorted(
+        p for p in paths if not any(part in skip_dirs for part in PurePosixPath(p).parts[:-1])
+    )
+
+
+def source_inventory(root: Path) -> list[SourceFile]:
+    """Source files under *root* with their language and line count.
+
+    A notebook counts the lines of its code cells, not of its JSON.
+    """
+    inventory = []
+    for path in tracked_files(root):
+        language = detect_language(path)
+        if language == "unknown":
+            continue
+        try:
+            if is_notebook(path):
+                source = notebook_source((root / path).read_text("utf-8", errors="replace"))
+                if source is None:
+                    continue
+                lines = source.count("\n")
+            else:
+                with open(root / path, "rb") as f:
+                    lines = sum(1 for _ in f)
+        except OSError:
+            continue
+        path_lower = path.lower()
+        is_test = any(pattern.search(path_lower) for pattern in TEST_PATH_PATTERNS)
+        inventory.append(SourceFile(path, language, lines, is_test))
+    return inventory
@@ -0,0 +1,162 @@
+"""License scan: license files and SPDX tags, with copyleft flagged.
+
+License files (LICENSE, COPYING...) are found anywhere in the tree, vendored
+directories included, and identified by the phrases of their title and
+grant; ``SPDX-License-Identifier`` tags are read from source headers.
+Copyleft matters to an acquirer: shipping GPL code can oblige them to
+publish their own source, and AGPL extends that to network services.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import Optional
+
+from .inventory import SourceFile, tracked_files
+
+_LICENSE_FILE_RE = re.compile(r"^(?:licen[cs]e|copying|unlicense)(?:[._-][\w.-]*)?$", re.I)
+_SPDX_RE = re.compile(r"SPDX-License-Identifier:\s*([\w.+\-() ]+?)\s*(?:\*/|-->|$)", re.M)
+
+# Only the head of a file is read: titles and grants come first
+_HEAD_BYTES = 4096
+
+# (SPDX id, phrases that must all appear in the head); the license whose
+# first phrase appears earliest wins, since license texts name other
+# licenses further down (the GPL preamble mentions the LGPL)
+_FINGERPRINTS: tuple[tuple[str, tuple[str, ...]], ...] = (
+    ("AGPL-3.0", ("gnu affero general public license",)),
+    ("LGPL-3.0", ("gnu lesser general public license", "version 3")),
+    ("LGPL-2.1", ("gnu lesser general public license", "version 2.1")),
+    ("LGPL-2.0", ("gnu library general public license",)),
+    ("GPL-3.0", ("gnu general public license", "version 3")),
+    ("GPL-2.0", ("gnu general public license", "version 2")),
+    ("MPL-2.0", ("mozilla public license", "2.0")),
+    ("EPL-2.0", ("eclipse public license", "2.0")),
+    ("Apache-2.0", ("apache license", "version 2.0")),
+    ("BSD-3-Clause", ("redistribution and use in source and binary forms", "neither the name")),
+    ("BSD-2-Clause", ("redistribution and use in source and binary forms",)),
+    ("MIT", ("permission is hereby granted, free of charge",)),
+    ("ISC", ("permission to use, copy, modify, and/or distribute this software",)),
+    ("Unlicense", ("free and unencumbered software released into the public domain",)),
+)
+
+PERMISSIVE = "permissive"
+WEAK_COPYLEFT = "weak copyleft"
+STRONG_COPYLEFT = "strong copyleft"
+UNKNOWN = "unknown"
+
+# SPDX id prefix -> category; anything else is unknown
+_CATEGORIES = (
+    ("AGPL-", STRONG_COPYLEFT),
+    ("GPL-", STRONG_COPYLEFT),
+    ("LGPL-", WEAK_COPYLEFT),
+    ("MPL-", WEAK_COPYLEFT),
+    ("EPL-", WEAK_COPYLEFT),
+    ("CDDL-", WEAK_COPYLEFT),
+    ("Apache-", PERMISSIVE),
+    ("BSD-", PERMISSIVE),
+    ("MIT", PERMISSIVE),
+    ("ISC", PERMISSIVE),
+    ("Unlicense", PERMISSIVE),
+    ("Zlib", PERMISSIVE),
+    ("0BSD", PERMISSIVE),
+    ("CC0-", PERMISSIVE),
+)
+_RANK = {PERMISSIVE: 0, UNKNOWN: 1, WEAK_COPYLEFT: 2, STRONG_COPYLEFT: 3}
+
+
+@dataclass(frozen=True)
+class LicenseFile:
+    """A license file and what it was identified as (None = not recognized)."""
+
+    path: str
+    license: Optional[str]
+    category: str
+
+
+@dataclass
+class LicenseScan:
+    """Licenses found in a repository.
+
+    Attributes:
+        project: Licenses of the top-level license files
+        files: Every license file, vendored ones included
+        spdx: SPDX expression -> number of source files tagged with it
+        copyleft: (path, license) of copyleft license files and tagged sources
+    """
+
+    project: list[str] = field(default_factory=list)
+    files: list[LicenseFile] = field(default_factory=list)
+    spdx: dict[str, int] = field(default_factory=dict)
+    copyleft: list[tuple[str, str]] = field(default_factory=list)
+
+
+def identify_license(text: str) -> Optional[str]:
+    """SPDX id of a license text, or None if it is not one we know."""
+    head = " ".join(text[:_HEAD_BYTES].lower().split())
+    best: Optional[tuple[int, int, str]] = None
+    for order, (spdx_id, phrases) in enumerate(_FINGERPRINTS):
+        if not all(phrase in head for phrase in phrases):
+            continue
+        candidate = (head.index(phrases[0]), order, spdx_id)
+        if best is None or candidate < best:
+            best = candidate
+    return best[2] if best else None
+
+
+def license_category(expression: Optional[str]) -> str:
+    """Category of an SPDX expression; ``A OR B`` is as restrictive as the laxer choice."""
+    if not expression:
+        return UNKNOWN
+    alternatives = []
+    for alternative in re.split(r"\s+OR\s+", expression.strip("() ")):
+        categories = [_category_of(term) for term in re.split(r"\s+AND\s+", alternative)]
+        alternatives.append(max(categories, key=_RANK.__getitem__))
+    return min(alternatives, key=_RANK.__getitem__)
+
+
+def _category_of(spdx_id: str) -> str:
+    spdx_id = spdx_id.strip("() ").split(" WITH ")[0]
+    for prefix, category in _CATEGORIES:
+        if spdx_id.startswith(prefix):
+            return category
+    return UNKNOWN
+
+
+def scan_licenses(root: Path, sources: list[SourceFile]) -> LicenseScan:
+    """Find and classify license files under *root* and SPDX tags in *sources*."""
+    scan = LicenseScan()
+    for path in tracked_files(root, include_vendored=True):
+        if not _LICENSE_FILE_RE.match(PurePosixPath(path).name):
+            continue
+        text = _read_head(root / path)
+        if text is None:
+            continue
+        spdx_id = identify_license(text)
+        category = license_category(spdx_id)
+        scan.files.append(LicenseFile(path, spdx_id, category))
+        if "/" not in path:
+            scan.project.append(spdx_id or "unrecognized")
+        if category in (WEAK_COPYLEFT, STRONG_COPYLEFT):
+            scan.copyleft.append((path, spdx_id or ""))
+
+    for source in sources:
+        text = _read_head(root / source.path)
+        match = _SPDX_RE.search(text) if text else None
+        if match is None:
+            continue
+        expression = match.group(1).strip()
+        scan.spdx[expression] = scan.spdx.get(expression, 0) + 1
+        if license_category(expression) in (WEAK_COPYLEFT, STRONG_COPYLEFT):
+            scan.copyleft.append((source.path, expression))
+    return scan
+
+
+def _read_head(path: Path) -> Optional[str]:
+    try:
+        with open(path, "rb") as f:
+            return f.read(_HEAD_BYTES).decode("utf-8", errors="replace")
+    except OSError:
+        return None
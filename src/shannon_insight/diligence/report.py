@@ -0,0 +1,363 @@
+"""The due-diligence report: one executive summary of a codebase.
+
+Built from an analysis snapshot plus what the analysis leaves out (tests,
+license files, manifests), then rendered as Markdown or a JSON-ready dict.
+Key risks come first, in plain language; the sections behind them carry
+the numbers.
+"""
+
+from __future__ import annotations
+
+import math
+from collections import defaultdict
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Optional
+
+from ..persistence.models import TensorSnapshot
+from .dependencies import (
+    MAJOR_BEHIND,
+    OUTDATED,
+    Freshness,
+    LatestLookup,
+    check_freshness,
+    find_dependencies,
+)
+from .inventory import source_inventory
+from .licenses import STRONG_COPYLEFT, LicenseScan, license_category, scan_licenses
+
+# Thresholds behind the key risks
+_LOW_TEST_RATIO = 0.2  # test lines per line of code
+_HIGH_COGNITIVE_LOAD_PERCENTILE = 0.9
+_DEEP_NESTING = 5
+_TOP_FILES = 5
+
+
+@dataclass
+class LanguageShare:
+    """Files and lines of one language (tests included)."""
+
+    language: str
+    files: int
+    lines: int
+
+
+@dataclass
+class DiligenceReport:
+    """Everything the due-diligence preset reports on.
+
+    Attributes:
+        name: Repository name
+        commit: Commit analyzed, if known
+        health: Codebase health on the 1-10 scale
+        code_files, code_lines: Non-test source files and their lines
+        test_files, test_lines: Test files and their lines
+        languages: Language mix, largest first
+        cognitive_load: p50, p90 and max cognitive load across files
+        deeply_nested: Files nested _DEEP_NESTING levels or more
+        most_complex: (path, cognitive load) of the most complex files
+        has_history: Whether git history was available for ownership
+        team_size: Distinct recent authors
+        single_owner_share: Share of code lines in files with a bus factor of 1
+        single_owner_modules: Modules whose bus factor is 1
+        licenses: License scan
+        dependencies: Freshness of declared dependencies
+        freshness_checked: False when registries were not consulted (--offline)
+        top_findings: Titles of the most severe findings
+    """
+
+    name: str
+    commit: Optional[str] = None
+    health: float = 0.0
+    code_files: int = 0
+    code_lines: int = 0
+    test_files: int = 0
+    test_lines: int = 0
+    languages: list[LanguageShare] = field(default_factory=list)
+    cognitive_load: dict[str, float] = field(default_factory=dict)
+    deeply_nested: int = 0
+    most_complex: list[tuple[str, float]] = field(default_factory=list)
+    has_history: bool = False
+    team_size: int = 0
+    single_owner_share: float = 0.0
+    single_owner_modules: list[str] = field(default_factory=list)
+    licenses: LicenseScan = field(default_factory=LicenseScan)
+    dependencies: list[Freshness] = field(default_factory=list)
+    freshness_checked: bool = True
+    top_findings: list[str] = field(default_factory=list)
+
+    @property
+    def health_label(self) -> str:
+        """Healthy, Moderate, At Risk or Critical, as in the dashboard."""
+        if self.health >= 8:
+            return "Healthy"
+        if self.health >= 6:
+            return "Moderate"
+        if self.health >= 4:
+            return "At Risk"
+        return "Critical"
+
+    @property
+    def test_ratio(self) -> float:
+        """Test lines per line of code."""
+        return self.test_lines / self.code_lines if self.code_lines else 0.0
+
+    def key_risks(self) -> list[str]:
+        """The report's headline: what an acquirer should look at first."""
+        risks = []
+        strong = [
+            path
+            for path, expression in self.licenses.copyleft
+            if license_category(expression) == STRONG_COPYLEFT
+        ]
+        if strong:
+            risks.append(
+                f"Strong copyleft (GPL/AGPL) licensed code in {len(strong)} place(s), "
+                f"e.g. {strong[0]}"
+            )
+        elif self.licenses.copyleft:
+            risks.append(f"Weak copyleft licensed code in {len(self.licenses.copyleft)} place(s)")
+        if not self.licenses.project:
+            risks.append("No top-level license file")
+        if self.has_history and self.single_owner_share >= 0.5:
+            risks.append(
+                f"{self.single_owner_share:.0%} of the code is known to a single author"
+            )
+        if self.code_lines and self.test_ratio < _LOW_TEST_RATIO:
+            risks.append(f"Low test coverage by volume: {self.test_ratio:.2f} test lines per line")
+        major = [f for f in self.dependencies if f.status == MAJOR_BEHIND]
+        if major:
+            risks.append(f"{len(major)} dependencies are a major version or more behind")
+        if self.health < 4:
+            risks.append(f"Codebase health is critical ({self.health:.1f}/10)")
+        return risks
+
+    def to_dict(self) -> dict[str, Any]:
+        """JSON-serializable form of the report."""
+        return {
+            "name": self.name,
+            "commit": self.commit,
+            "health": {"score": round(self.health, 1), "label": self.health_label},
+            "key_risks": self.key_risks(),
+            "size": {
+                "code_files": self.code_files,
+                "code_lines": self.code_lines,
+                "test_files": self.test_files,
+                "test_lines": self.test_lines,
+                "test_ratio": round(self.test_ratio, 3),
+            },
+            "languages": [vars(share) for share in self.languages],
+            "complexity": {
+                "cognitive_load": {k: round(v, 2) for k, v in self.cognitive_load.items()},
+                "deeply_nested_files": self.deeply_nested,
+                "most_complex": [
+                    {"path": path, "cognitive_load": round(load, 2)}
+                    for path, load in self.most_complex
+                ],
+            },
+            "bus_factor": {
+                "has_history": self.has_history,
+                "team_size": self.team_size,
+                "single_owner_share": round(self.single_owner_share, 3),
+                "single_owner_modules": self.single_owner_modules,
+            },
+            "licenses": {
+                "project": self.licenses.project,
+                "files": [vars(f) for f in self.licenses.files],
+                "spdx": self.licenses.spdx,
+                "copyleft": [
+                    {"path": path, "license": expression}
+                    for path, expression in self.licenses.copyleft
+                ],
+            },
+            "dependencies": {
+                "freshness_checked": self.freshness_checked,
+                "packages": [
+                    {
+                        "name": f.dependency.name,
+                        "ecosystem": f.dependency.ecosystem,
+                        "spec": f.dependency.spec,
+                        "manifest": f.dependency.manifest,
+                        "dev": f.dependency.dev,
+                        "latest": f.latest,
+                        "status": f.status,
+                    }
+                    for f in self.dependencies
+                ],
+            },
+            "top_findings": self.top_findings,
+        }
+
+    def render_markdown(self) -> str:
+        """The report as a Markdown document."""
+        lines = [f"# Due-diligence report: {self.name}", ""]
+        if self.commit:
+            lines += [f"Commit `{self.commit[:12]}`", ""]
+        lines += [f"**Health: {self.health:.1f}/10 ({self.health_label})**", ""]
+
+        lines += ["## Key risks", ""]
+        risks = self.key_risks()
+        lines += [f"- {risk}" for risk in risks] if risks else ["- None identified"]
+
+        lines += ["", "## Size", ""]
+        lines += [
+            f"- {self.code_lines:,} lines of code in {self.code_files:,} files",
+            f"- {self.test_lines:,} lines of tests in {self.test_files:,} files "
+            f"({self.test_ratio:.2f} test lines per line of code)",
+        ]
+
+        total_lines = sum(share.lines for share in self.languages) or 1
+        lines += ["", "## Languages", "", "| Language | Files | Lines | Share |"]
+        lines += ["|---|---:|---:|---:|"]
+        lines += [
+            f"| {s.language} | {s.files:,} | {s.lines:,} | {s.lines / total_lines:.0%} |"
+            for s in self.languages
+        ]
+
+        lines += ["", "## Complexity", ""]
+        if self.cognitive_load:
+            load = self.cognitive_load
+            lines.append(
+                f"- Cognitive load: median {load['p50']:.1f}, "
+                f"90th percentile {load['p90']:.1f}, max {load['max']:.1f}"
+            )
+            lines.append(f"- {self.deeply_nested} files nested {_DEEP_NESTING}+ levels deep")
+            lines += [f"- `{path}`: {value:.1f}" for path, value in self.most_complex]
+        else:
+            lines.append("- No analyzable files")
+
+        lines += ["", "## Bus factor", ""]
+        if self.has_history:
+            lines.append(f"- {self.team_size} active authors")
+            lines.append(
+                f"- {self.single_owner_share:.0%} of the code is in files with a single author"
+            )
+            if self.single_owner_modules:
+                modules = ", ".join(f"`{m}`" for m in self.single_owner_modules)
+                lines.append(f"- Single-owner modules: {modules}")
+        else:
+            lines.append("- Unavailable: no git history")
+
+        lines += ["", "## Licenses", ""]
+        project = ", ".join(self.licenses.project) or "none found"
+        lines.append(f"- Project license: {project}")
+        if self.licenses.spdx:
+            tags = ", ".join(f"{k} ({v})" for k, v in sorted(self.licenses.spdx.items()))
+            lines.append(f"- SPDX tags in sources: {tags}")
+        lines += [
+            f"- Copyleft: `{path}` ({expression or 'unrecognized'})"
+            for path, expression in self.licenses.copyleft
+        ]
+
+        lines += ["", "## Dependencies", ""]
+        if not self.dependencies:
+            lines.append("- No dependency manifests found")
+        else:
+            counts: dict[str, int] = defaultdict(int)
+            for f in self.dependencies:
+                counts[f.status] += 1
+            summary = ", ".join(f"{n} {status}" for status, n in sorted(counts.items()))
+            lines.append(f"- {len(self.dependencies)} declared: {summary}")
+            if not self.freshness_checked:
+                lines.append("- Registries were not consulted (offline)")
+            lines += [
+                f"- `{f.dependency.name}` {f.dependency.spec or '(any)'} -> {f.latest} "
+                f"({f.status})"
+                for f in self.dependencies
+                if f.status in (MAJOR_BEHIND, OUTDATED)
+            ]
+
+        lines += ["", "## Top findings", ""]
+        lines += [f"- {title}" for title in self.top_findings] or ["- None"]
+        return "\n".join(lines) + "\n"
+
+
+def build_report(
+    root: Path,
+    snapshot: TensorSnapshot,
+    findings_titles: list[str],
+    lookup: Optional[LatestLookup] = None,
+    offline: bool = False,
+) -> DiligenceReport:
+    """Assemble the report for the repository at *root*.
+
+    Args:
+        root: Repository root that was analyzed
+        snapshot: Snapshot of the analysis of *root*
+        findings_titles: Finding titles, most severe first
+        lookup: Latest-version lookup (default: public registries)
+        offline: Skip registry lookups; dependency statuses stay unknown
+    """
+    report = DiligenceReport(name=root.name, commit=snapshot.commit_sha)
+    report.health = snapshot.global_signals.get("codebase_health", 0.0) * 10
+    report.top_findings = findings_titles[:_TOP_FILES]
+
+    inventory = source_inventory(root)
+    languages: dict[str, LanguageShare] = {}
+    for source in inventory:
+        share = languages.setdefault(source.language, LanguageShare(source.language, 0, 0))
+        share.files += 1
+        share.lines += source.lines
+        if source.is_test:
+            report.test_files += 1
+            report.test_lines += source.lines
+        else:
+            report.code_files += 1
+            report.code_lines += source.lines
+    report.languages = sorted(languages.values(), key=lambda s: (-s.lines, s.language))
+
+    _add_complexity(report, snapshot)
+    _add_bus_factor(report, snapshot)
+    report.licenses = scan_licenses(root, inventory)
+
+    dependencies = find_dependencies(root)
+    report.freshness_checked = not offline
+    report.dependencies = check_freshness(
+        dependencies, lookup=(lambda ecosystem, name: None) if offline else lookup
+    )
+    return report
+
+
+def _add_complexity(report: DiligenceReport, snapshot: TensorSnapshot) -> None:
+    loads = sorted(
+        (float(signals.get("cognitive_load", 0.0)), path)
+        for path, signals in snapshot.file_signals.items()
+    )
+    if not loads:
+        return
+    values = [load for load, _ in loads]
+    report.cognitive_load = {
+        "p50": _percentile(values, 0.5),
+        "p90": _percentile(values, _HIGH_COGNITIVE_LOAD_PERCENTILE),
+        "max": values[-1],
+    }
+    report.deeply_nested = sum(
+        1
+        for signals in snapshot.file_signals.values()
+        if signals.get("max_nesting", 0) >= _DEEP_NESTING
+    )
+    report.most_complex = [(path, load) for load, path in reversed(loads[-_TOP_FILES:])]
+
+
+def _add_bus_factor(report: DiligenceReport, snapshot: TensorSnapshot) -> None:
+    report.has_history = snapshot.commits_analyzed > 0
+    if not report.has_history:
+        return
+    report.team_size = int(snapshot.global_signals.get("team_size", 1))
+    total = single = 0.0
+    for signals in snapshot.file_signals.values():
+        lines = float(signals.get("lines", 0))
+        total += lines
+        if signals.get("bus_factor", 1.0) <= 1.0:
+            single += lines
+    report.single_owner_share = single / total if total else 0.0
+    report.single_owner_modules = sorted(
+        module
+        for module, signals in snapshot.module_signals.items()
+        if signals.get("module_bus_factor", 1.0) <= 1.0
+    )
+
+
+def _percentile(sorted_values: list[float], q: float) -> float:
+    """Nearest-rank percentile of an ascending list."""
+    return sorted_values[max(0, math.ceil(q * len(sorted_values)) - 1)]
@@ -19,10 +19,16 @@ import os
 import subprocess
 from dataclasses import dataclass, field
 from pathlib import Path
-from typing import Optional
+from typing import Collection, Optional
 
 from .logging_config import get_logger
-from .scanning.languages import SKIP_DIRS
+from .scanning.languages import (
+    SKIP_DIRS,
+    detect_language,
+    filename_language,
+    read_shebang,
+    shebang_language,
+)
 
 logger = get_logger(__name__)
 
@@ -38,7 +44,8 @@ class Environment:
         root: Absolute path to codebase root
         file_count: Total source files found (excludes common ignore patterns)
         file_paths: List of discovered file paths (relative to root)
-        detected_languages: Programming languages detected from file extensions
+        detected_languages: Programming languages detected from file extensions,
+            build file names and shebang lines
         is_git_repo: Whether target is inside a git repository
         git_branch: Current git branch name (None if not a git repo)
         has_tree_sitter: Whether tree-sitter parsing is available
@@ -59,12 +66,14 @@ def discover_environment(
     root: Path | str,
     allow_hidden_files: bool = False,
     follow_symlinks: bool = False,
+    extra_extensions: Collection[str] = (),
 ) -> Environment:
     """Discover environment facts about the target codebase.
 
     This function performs fast discovery using git when available:
     - File counting: uses `git ls-files` (fast, uses index)
-    - Language detection: scans file extensions
+    - Language detection: scans file extensions; files without one are
+      recognised by name (Dockerfile, Makefile) or shebang line
     - Git info: checks if repo exists and current branch
     - Capabilities: checks for tree-sitter availability
 
@@ -72,6 +81,8 @@ def discover_environment(
         root: Path to codebase root directory
         allow_hidden_files: Include hidden files (starting with .)
         follow_symlinks: Follow symbolic links during discovery
+        extra_extensions: Source extensions beyond the built-in ones (those
+            of custom grammars)
 
     Returns:
         Immutable Environment instance
@@ -101,17 +112,20 @@ def discover_environment(
     # Discover files and languages
     if is_git:
         # Fast path: use git index
-        files = _get_git_files(root_path, allow_hidden_files=allow_hidden_files)
+        files = _get_git_files(
+            root_path, allow_hidden_files=allow_hidden_files, extra_extensions=extra_extensions
+        )
     else:
         # Fallback: manual walk
         files = _walk_directory(
             root_path,
             allow_hidden_files=allow_hidden_files,
             follow_symlinks=follow_symlinks,
+            extra_extensions=extra_extensions,
         )
 
     file_count = len(files)
-    languages = _detect_languages(files)
+    languages = _detect_languages(files, root_path)
 
     # Discover capabilities
     has_tree_sitter = _check_tree_sitter_available()
@@ -179,7 +193,9 @@ def _get_git_branch(root: Path) -> Optional[str]:
     return None
 
 
-def _get_git_files(root: Path, allow_hidden_files: bool = False) -> list[Path]:
+def _get_git_files(
+    root: Path, allow_hidden_files: bool = False, extra_extensions: Collection[str] = ()
+) -> list[Path]:
     """Get list of files from git index that exist on disk.
 
     Uses `git ls-files` to get tracked files. This is much faster than
@@ -191,6 +207,7 @@ def _get_git_files(root: Path, allow_hidden_files: bool = False) -> list[Path]:
     Args:
         root: Git repository root
         allow_hidden_files: Include hidden files (starting with .)
+        extra_extensions: Source extensions beyond the built-in ones
 
     Returns:
         List of relative file paths (only those that exist on disk)
@@ -206,7 +223,9 @@ def _get_git_files(root: Path, allow_hidden_files: bool = False) -> list[Path]:
             files = []
             for line in result.stdout.splitlines():
                 line = line.strip()
-                if not line or not _is_source_file(line, allow_hidden_files):
+                if not line or not _is_source_file(
+                    line, allow_hidden_files, extra_extensions, root
+                ):
                     continue
                 # Only include files that actually exist on disk
                 file_path = root / line
@@ -217,13 +236,16 @@ def _get_git_files(root: Path, allow_hidden_files: bool = False) -> list[Path]:
         logger.warning("git ls-files failed, falling back to directory walk")
 
     # Fallback to manual walk
-    return _walk_directory(root, allow_hidden_files=allow_hidden_files)
+    return _walk_directory(
+        root, allow_hidden_files=allow_hidden_files, extra_extensions=extra_extensions
+    )
 
 
 def _walk_directory(
     root: Path,
     allow_hidden_files: bool = False,
     follow_symlinks: bool = False,
+    extra_extensions: Collection[str] = (),
 ) -> list[Path]:
     """Manually walk directory tree to find source files.
 
@@ -234,6 +256,7 @@ def _walk_directory(
         root: Directory root
         allow_hidden_files: Include hidden files (starting with .)
         follow_symlinks: Follow symbolic links
+        extra_extensions: Source extensions beyond the built-in ones
 
     Returns:
         List of relative file paths
@@ -248,18 +271,31 @@ def _walk_directory(
         if item.is_symlink() and not follow_symlinks:
             continue
 
-        if item.is_file() and _is_source_file(str(item.relative_to(root)), allow_hidden_files):
+        if item.is_file() and _is_source_file(
+            str(item.relative_to(root)), allow_hidden_files, extra_extensions, root
+        ):
             files.append(item.relative_to(root))
 
     return files
 
 
-def _is_source_file(path: str, allow_hidden_files: bool = False) -> bool:
+def _is_source_file(
+    path: str,
+    allow_hidden_files: bool = False,
+    extra_extensions: Collection[str] = (),
+    root: Optional[Path] = None,
+) -> bool:
     """Check if file is a source code file.
 
+    Build files are recognised by name (Dockerfile, Makefile), and files
+    without an extension by their shebang line (``#!/usr/bin/env python3``).
+
     Args:
         path: File path (relative or absolute)
         allow_hidden_files: Include hidden files (starting with .)
+        extra_extensions: Source extensions beyond the built-in ones
+        root: Directory *path* is relative to; without it, shebang lines
+            are not read
 
     Returns:
         True if file appears to be source code
@@ -271,34 +307,56 @@ def _is_source_file(path: str, allow_hidden_files: bool = False) -> bool:
     # Check for source code extensions
     source_extensions = {
         ".py",
+        ".ipynb",
         ".js",
         ".ts",
         ".tsx",
         ".jsx",
+        ".vue",
+        ".svelte",
         ".go",
         ".java",
         ".rs",
         ".rb",
         ".cpp",
         ".cc",
+        ".cxx",
         ".c",
         ".h",
         ".hpp",
+        ".hh",
+        ".hxx",
         ".cs",
         ".php",
         ".swift",
         ".kt",
+        ".kts",
         ".scala",
+        ".sql",
+        ".tf",
+        ".hcl",
+        ".yaml",
+        ".yml",
+        ".proto",
     }
 
-    return Path(path).suffix.lower() in source_extensions
+    suffix = Path(path).suffix.lower()
+    if suffix in source_extensions or suffix in extra_extensions:
+        return True
+    if filename_language(path) != "unknown":
+        return True
+    if suffix or root is None:
+        return False
+    return shebang_language(read_shebang(root / path)) != "unknown"
 
 
-def _detect_languages(files: list[Path]) -> set[str]:
+def _detect_languages(files: list[Path], root: Optional[Path] = None) -> set[str]:
     """Detect programming languages from file extensions.
 
     Args:
         files: List of file paths
+        root: Directory the paths are relative to, to read the shebang line
+            of files without an extension
 
     Returns:
         Set of language names (lowercase)
@@ -306,10 +364,13 @@ def _detect_languages(files: list[Path]) -> set[str]:
     # Extension to language mapping
     ext_to_lang = {
         ".py": "python",
+        ".ipynb": "python",
         ".js": "javascript",
         ".ts": "typescript",
         ".tsx": "typescript",
         ".jsx": "javascript",
+        ".vue": "javascript",
+        ".svelte": "javascript",
         ".go": "go",
         ".java": "java",
         ".rs": "rust",
@@ -319,11 +380,21 @@ def _detect_languages(files: list[Path]) -> set[str]:
         ".c": "c",
         ".h": "c",
         ".hpp": "cpp",
+        ".cxx": "cpp",
+        ".hh": "cpp",
+        ".hxx": "cpp",
         ".cs": "csharp",
         ".php": "php",
         ".swift": "swift",
         ".kt": "kotlin",
+        ".kts": "kotlin",
         ".scala": "scala",
+        ".sql": "sql",
+        ".tf": "hcl",
+        ".hcl": "hcl",
+        ".yaml": "yaml",
+        ".yml": "yaml",
+        ".proto": "proto",
     }
 
     languages = set()
@@ -331,6 +402,11 @@ def _detect_languages(files: list[Path]) -> set[str]:
         ext = file.suffix.lower()
         if ext in ext_to_lang:
             languages.add(ext_to_lang[ext])
+        elif not ext or filename_language(file) != "unknown":
+            head = read_shebang(root / file) if root is not None and not ext else None
+            language = detect_language(file, head)
+            if language != "unknown":
+                languages.add(language)
 
     return languages
 
@@ -0,0 +1,19 @@
+"""CI gate checks run on top of an analysis snapshot.
+
+Usage:
+    from shannon_insight.gate.ratchet import apply_ratchet, current_values, load_ceilings
+
+    current = current_values(snapshot.file_signals, ["cognitive_load", "max_nesting"])
+    result = apply_ratchet(load_ceilings(path) or {}, current)
+
+    # Merge queues: only files changed since the merge base, no full analysis
+    from shannon_insight.gate.fast import run_fast_gate
+
+    result = run_fast_gate(root, settings, load_ceilings(path), "origin/main", 30)
+
+    # What each violation would need to pass, and a PR comment
+    from shannon_insight.gate.sensitivity import markdown_comment, ratchet_remedies
+
+    remedies = ratchet_remedies(root, result.ratchet.violations, settings)
+    body = markdown_comment(result.passed, remedies)
+"""
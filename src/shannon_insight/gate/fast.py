@@ -0,0 +1,184 @@
+"""Diff-only ratchet check for merge queues.
+
+``gate --fast`` skips the full analysis. Only files the branch changed since
+its merge base with the mainline are re-measured, and they are compared with
+the cached ceilings in the ratchet file. Unchanged files passed the last full
+gate and are not looked at.
+
+Only signals that come from a single file's syntax can be re-measured this way
+(``FAST_METRICS``); other ``ratchet_metrics`` are left to the full gate. The
+time budget is checked between files: files not reached are reported as
+unchecked and the result is incomplete rather than passed.
+"""
+
+from __future__ import annotations
+
+import subprocess
+import time
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Callable, Optional
+
+from .ratchet import RatchetResult, apply_ratchet, current_values
+
+# File signals computable from one file without the rest of the codebase
+FAST_METRICS = (
+    "lines",
+    "function_count",
+    "class_count",
+    "max_nesting",
+    "import_count",
+    "impl_gini",
+    "stub_ratio",
+    "cognitive_load",
+)
+
+_GIT_TIMEOUT_SECONDS = 10
+
+
+@dataclass
+class FastGateResult:
+    base: str
+    changed: list[str]
+    ratchet: RatchetResult
+    unchecked: list[str] = field(default_factory=list)  # not reached within the budget
+    skipped_metrics: list[str] = field(default_factory=list)  # need a full analysis
+    elapsed: float = 0.0
+
+    @property
+    def complete(self) -> bool:
+        return not self.unchecked
+
+    @property
+    def passed(self) -> bool:
+        return self.complete and self.ratchet.passed
+
+    def to_dict(self) -> dict:
+        return {
+            **self.ratchet.to_dict(),
+            "passed": self.passed,
+            "complete": self.complete,
+            "base": self.base,
+            "changed": self.changed,
+            "unchecked": self.unchecked,
+            "skipped_metrics": self.skipped_metrics,
+            "elapsed_seconds": round(self.elapsed, 3),
+        }
+
+
+def changed_files(root: Path, base: str) -> Optional[list[str]]:
+    """Files added or modified since the merge base of HEAD and *base*.
+
+    Paths are relative to *root*, which may be a subdirectory of the
+    repository; changes outside it are left out, as are deleted files (they
+    have nothing to check). Returns None when git cannot compute the diff.
+    """
+
+    def git(*args: str) -> Optional[str]:
+        try:
+            result = subprocess.run(
+                ["git", "-C", str(root), *args],
+                capture_output=True,
+                text=True,
+                timeout=_GIT_TIMEOUT_SECONDS,
+            )
+        except (FileNotFoundError, subprocess.TimeoutExpired):
+            return None
+        return result.stdout if result.returncode == 0 else None
+
+    merge_base = git("merge-base", "HEAD", base)
+    if merge_base is None:
+        return None
+    diff = git("diff", "--relative", "--name-only", "--diff-filter=ACMR", merge_base.strip())
+    if diff is None:
+        return None
+    return sorted(line for line in diff.splitlines() if line)
+
+
+def measure_file(root: Path, rel_path: str, mode: str, extractor: Any) -> Optional[dict]:
+    """FAST_METRICS signals of one file, or None if it cannot be parsed."""
+    from ..signals.complexity import cognitive_load
+
+    content: dict[str, str] = {}
+    syntax = extractor.extract(root / rel_path, root, content)
+    if syntax is None:
+        return None
+    return {
+        "lines": syntax.lines,
+        "function_count": syntax.function_count,
+        "class_count": syntax.class_count,
+        "max_nesting": syntax.max_nesting,
+        "import_count": syntax.import_count,
+        "impl_gini": syntax.impl_gini,
+        "stub_ratio": syntax.stub_ratio,
+        "cognitive_load": cognitive_load(syntax, content.get(rel_path), mode),
+    }
+
+
+def _is_analyzed(root: Path, rel_path: str, settings: Any) -> bool:
+    from ..file_ops import should_skip_file
+    from ..scanning.grammars import grammar_extensions
+    from ..scanning.languages import detect_language, read_shebang
+
+    path = Path(rel_path)
+    head = None if path.suffix else read_shebang(root / path)
+    known = detect_language(path, head) != "unknown" or (
+        path.suffix.lower() in grammar_extensions(settings.grammars)
+    )
+    return known and not should_skip_file(path, settings.exclude_patterns)
+
+
+def run_fast_gate(
+    root: Path,
+    settings: Any,
+    ceilings: dict[str, dict[str, float]],
+    base: str,
+    budget_seconds: float,
+    clock: Callable[[], float] = time.monotonic,
+) -> FastGateResult:
+    """Check the files changed since *base* against *ceilings* within the budget.
+
+    Raises:
+        ValueError: If the diff against *base* cannot be computed.
+    """
+    from ..scanning.gobuild import go_build_config
+    from ..scanning.syntax_extractor import SyntaxExtractor
+
+    started = clock()
+    changed = changed_files(root, base)
+    if changed is None:
+        raise ValueError(f"cannot diff HEAD against {base!r}")
+
+    metrics = [m for m in settings.ratchet_metrics if m in FAST_METRICS]
+    skipped = [m for m in settings.ratchet_metrics if m not in FAST_METRICS]
+    candidates = [
+        p for p in changed if p in ceilings or _is_analyzed(root, p, settings)
+    ]
+
+    extractor = SyntaxExtractor(
+        max_workers=1,
+        c_defines=settings.c_defines,
+        c_conditionals=settings.c_conditionals,
+        grammars=settings.grammars,
+        go_build=go_build_config(settings),
+    )
+    signals: dict[str, dict] = {}
+    unchecked: list[str] = []
+    for index, rel_path in enumerate(candidates):
+        if clock() - started >= budget_seconds:
+            unchecked = candidates[index:]
+            break
+        measured = measure_file(root, rel_path, settings.complexity_normalization, extractor)
+        if measured is not None:
+            signals[rel_path] = measured
+
+    current = current_values(signals, metrics)
+    scoped = {p: ceilings[p] for p in current if p in ceilings}
+    return FastGateResult(
+        base=base,
+        changed=changed,
+        ratchet=apply_ratchet(scoped, current),
+        unchecked=unchecked,
+        skipped_metrics=skipped,
+        elapsed=clock() - started,
+    )
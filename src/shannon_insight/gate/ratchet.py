@@ -0,0 +1,137 @@
+"""Per-file metric ceilings that can only tighten.
+
+The ratchet file (``ratchet_file`` config, committed to the repo) records each
+file's worst accepted value for every ``ratchet_metrics`` signal. A gate run
+compares the current snapshot against it:
+
+    violation  a file exceeds its own recorded ceiling
+    tightened  a file improved; its ceiling drops to the new value
+    added      a new file; its current values become its ceiling
+    removed    a deleted file; its ceilings are dropped
+
+The updated ceilings are written back only when there are no violations, so
+quality can only trend one way.
+"""
+
+from __future__ import annotations
+
+import json
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Optional
+
+RATCHET_VERSION = 1
+
+# Tolerance for float noise between runs
+_EPSILON = 1e-6
+_PRECISION = 4
+
+
+@dataclass
+class Violation:
+    """A file above its recorded ceiling."""
+
+    path: str
+    metric: str
+    ceiling: float
+    value: float
+
+
+@dataclass
+class Tightening:
+    path: str
+    metric: str
+    old: float
+    new: float
+
+
+@dataclass
+class RatchetResult:
+    violations: list[Violation] = field(default_factory=list)
+    warnings: list[Violation] = field(default_factory=list)  # of warn-only metrics (rollout.py)
+    tightened: list[Tightening] = field(default_factory=list)
+    added: list[str] = field(default_factory=list)
+    removed: list[str] = field(default_factory=list)
+    ceilings: dict[str, dict[str, float]] = field(default_factory=dict)  # updated budget
+
+    @property
+    def passed(self) -> bool:
+        return not self.violations
+
+    @property
+    def changed(self) -> bool:
+        return bool(self.tightened or self.added or self.removed)
+
+    def to_dict(self) -> dict:
+        return {
+            "passed": self.passed,
+            "violations": [v.__dict__ for v in self.violations],
+            "warnings": [v.__dict__ for v in self.warnings],
+            "tightened": [t.__dict__ for t in self.tightened],
+            "added": self.added,
+            "removed": self.removed,
+        }
+
+
+def load_ceilings(path: Path) -> Optional[dict[str, dict[str, float]]]:
+    """Ceilings from a ratchet file; None when the file does not exist."""
+    if not path.exists():
+        return None
+    data = json.loads(path.read_text(encoding="utf-8"))
+    if data.get("version") != RATCHET_VERSION:
+        raise ValueError(f"{path}: unsupported ratchet version {data.get('version')!r}")
+    return {p: {m: float(v) for m, v in values.items()} for p, values in data["files"].items()}
+
+
+def save_ceilings(path: Path, ceilings: dict[str, dict[str, float]], metrics: list[str]) -> None:
+    """Write ceilings with sorted keys so diffs of the committed file stay small."""
+    data = {
+        "version": RATCHET_VERSION,
+        "metrics": list(metrics),
+        "files": {p: dict(sorted(ceilings[p].items())) for p in sorted(ceilings)},
+    }
+    path.write_text(json.dumps(data, indent=2) + "\n", encoding="utf-8")
+
+
+def current_values(
+    file_signals: dict[str, dict[str, Any]], metrics: list[str]
+) -> dict[str, dict[str, float]]:
+    """Numeric values of *metrics* per file from snapshot ``file_signals``."""
+    values: dict[str, dict[str, float]] = {}
+    for path, signals in file_signals.items():
+        row = {}
+        for metric in metrics:
+            value = signals.get(metric)
+            if isinstance(value, (int, float)) and not isinstance(value, bool):
+                row[metric] = round(float(value), _PRECISION)
+        if row:
+            values[path] = row
+    return values
+
+
+def apply_ratchet(
+    ceilings: dict[str, dict[str, float]],
+    current: dict[str, dict[str, float]],
+) -> RatchetResult:
+    """Compare *current* values with *ceilings* and compute the tightened budget."""
+    result = RatchetResult()
+    for path in sorted(current):
+        values = current[path]
+        recorded = ceilings.get(path)
+        if recorded is None:
+            result.added.append(path)
+            result.ceilings[path] = dict(values)
+            continue
+        updated = dict(recorded)
+        for metric, value in sorted(values.items()):
+            ceiling = recorded.get(metric)
+            if ceiling is None:
+                updated[metric] = value  # metric newly ratcheted
+            elif value > ceiling + _EPSILON:
+                result.violations.append(Violation(path, metric, ceiling, value))
+            elif value < ceiling - _EPSILON:
+                result.tightened.append(Tightening(path, metric, ceiling, value))
+                updated[metric] = value
+        result.ceilings[path] = updated
+    result.removed = sorted(set(ceilings) - set(current))
+    return result
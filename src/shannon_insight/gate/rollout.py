@@ -0,0 +1,158 @@
+"""Warn-only rollout windows for gate rules.
+
+Turning on a new rule fails every pipeline that already breaks it, on the
+day it is turned on. Rules listed in the ``gate_rollout`` config table go
+through two phases instead:
+
+    [gate_rollout.deep_nesting]     # a finding type, checked by --fail-on
+    days = 14
+    [gate_rollout.max_nesting]      # a ratchet metric, checked by --ratchet
+    runs = 50
+
+    warn     the rule's failures are reported but do not fail the gate
+    enforce  once ``days`` days have passed since the first gate run that
+             saw the rule, and the gate has run ``runs`` times since
+             (whichever comes later when both are set)
+
+The window is tracked in the history store (``gate_rollout`` table: when
+each rule was first seen and how many gate runs have seen it), so
+pipelines sharing a store (``history_url``) share the window. A SQLite
+store that CI discards after each job restarts the window every run; keep
+``.shannon/`` in the CI cache or use Postgres.
+
+A rule taken out of the table and put back later continues its old
+window; ``shannon-insight gate --reset-rollout RULE`` starts it over.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from datetime import datetime, timedelta, timezone
+from typing import Any, Optional
+
+from .ratchet import RatchetResult
+
+
+@dataclass(frozen=True)
+class RolloutRule:
+    """A rule and how long it stays warn-only."""
+
+    rule: str
+    days: int = 0
+    runs: int = 0
+
+
+@dataclass
+class RolloutStatus:
+    """Where a rule is in its rollout, as of one gate run."""
+
+    rule: RolloutRule
+    started: datetime  # first gate run that saw the rule
+    runs: int  # gate runs that have seen it, this one included
+    now: datetime
+
+    @property
+    def enforced_at(self) -> datetime:
+        return self.started + timedelta(days=self.rule.days)
+
+    @property
+    def runs_left(self) -> int:
+        return max(0, self.rule.runs - self.runs)
+
+    @property
+    def warn_only(self) -> bool:
+        return self.now < self.enforced_at or self.runs < self.rule.runs
+
+    def describe(self) -> str:
+        """When enforcement starts, e.g. "enforced from 2026-10-30 and after 3 more runs"."""
+        parts = []
+        if self.now < self.enforced_at:
+            parts.append(f"from {self.enforced_at:%Y-%m-%d}")
+        if self.runs_left:
+            parts.append(f"after {self.runs_left} more run(s)")
+        return "enforced " + " and ".join(parts) if parts else "enforced"
+
+    def to_dict(self) -> dict[str, Any]:
+        return {
+            "rule": self.rule.rule,
+            "warn_only": self.warn_only,
+            "started": self.started.isoformat(),
+            "runs": self.runs,
+            "days": self.rule.days,
+            "required_runs": self.rule.runs,
+            "enforced_at": self.enforced_at.isoformat(),
+            "runs_left": self.runs_left,
+        }
+
+
+def parse_rollout(table: dict[str, Any]) -> list[RolloutRule]:
+    """Rules of the ``gate_rollout`` config table; ValueError when one is malformed."""
+    rules = []
+    for name, entry in sorted(table.items()):
+        where = f"gate_rollout.{name}"
+        if not isinstance(entry, dict):
+            raise ValueError(f"{where} must be a table")
+        unknown = set(entry) - {"days", "runs"}
+        if unknown:
+            raise ValueError(f"{where}: unknown keys {', '.join(sorted(unknown))}")
+        days, runs = entry.get("days", 0), entry.get("runs", 0)
+        for key, value in (("days", days), ("runs", runs)):
+            if not isinstance(value, int) or isinstance(value, bool) or value < 0:
+                raise ValueError(f"{where}: {key} must be a whole number >= 0")
+        if not days and not runs:
+            raise ValueError(f"{where}: set days and/or runs")
+        rules.append(RolloutRule(name, days, runs))
+    return rules
+
+
+def record_run(
+    conn: Any, rules: list[RolloutRule], now: Optional[datetime] = None
+) -> list[RolloutStatus]:
+    """Count one gate run for every rule and return where each stands."""
+    now = now or datetime.now(timezone.utc)
+    statuses = []
+    for rule in rules:
+        row = conn.execute(
+            "SELECT started, runs FROM gate_rollout WHERE rule = ?", (rule.rule,)
+        ).fetchone()
+        if row is None:
+            started, runs = now, 1
+            conn.execute(
+                "INSERT INTO gate_rollout (rule, started, runs) VALUES (?, ?, ?)",
+                (rule.rule, started.isoformat(), runs),
+            )
+        else:
+            started, runs = datetime.fromisoformat(row["started"]), row["runs"] + 1
+            conn.execute("UPDATE gate_rollout SET runs = ? WHERE rule = ?", (runs, rule.rule))
+        statuses.append(RolloutStatus(rule, started, runs, now))
+    conn.commit()
+    return statuses
+
+
+def reset_rules(conn: Any, names: list[str]) -> int:
+    """Forget the windows of *names*, so they start over at the next run."""
+    removed = 0
+    for name in names:
+        removed += conn.execute("DELETE FROM gate_rollout WHERE rule = ?", (name,)).rowcount
+    conn.commit()
+    return removed
+
+
+def warned_rules(statuses: list[RolloutStatus]) -> set[str]:
+    return {s.rule.rule for s in statuses if s.warn_only}
+
+
+def split_findings(findings: list, warned: set[str]) -> tuple[list, list]:
+    """(enforced, warn-only) findings, by finding type."""
+    enforced = [f for f in findings if f.finding_type not in warned]
+    return enforced, [f for f in findings if f.finding_type in warned]
+
+
+def demote_violations(result: RatchetResult, warned: set[str]) -> None:
+    """Move violations of warn-only metrics from *result*'s violations to its warnings.
+
+    Their ceilings stay as recorded, so the violations fail once the rule
+    is enforced unless the files are fixed by then.
+    """
+    result.warnings.extend(v for v in result.violations if v.metric in warned)
+    result.violations = [v for v in result.violations if v.metric not in warned]
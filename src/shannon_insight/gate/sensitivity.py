@@ -0,0 +1,268 @@
+"""What a failed gate check would need to pass.
+
+Each failure is stated as the metric that crossed its threshold, by how
+much, and the smallest change that passes it, computed from the gate config
+and the file's syntax:
+
+    max_nesting     the functions nested deeper than the ceiling, each with
+                    the depth to reach ("reduce CreateUser nesting from 6
+                    to ≤4"); all of them are needed
+    cognitive_load  log2(lines+1) * (1+complexity/10) * (1+nesting/5) * (1+gini)
+                    (see signals/complexity.py) solved for each factor with
+                    the others held fixed; any one of them passes, smallest
+                    relative change first
+    lines, counts   the value to reach, and so how many to remove
+    fail_on         each finding above the severity threshold
+
+Other metrics (graph and history signals) only state the value to reach:
+no single edit to the file determines them.
+"""
+
+from __future__ import annotations
+
+import math
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Optional
+
+from .ratchet import Violation
+
+# Severity a finding must exceed to fail --fail-on (as in cli/analyze.py)
+FAIL_ON_SEVERITY = {"high": 0.7, "medium": 0.4, "any": 0.0}
+
+_COUNTS = ("lines", "function_count", "class_count", "import_count")
+
+
+@dataclass(frozen=True)
+class Change:
+    """Bring *subject* from *current* to at most *required*; None = remove it."""
+
+    subject: str
+    current: float
+    required: Optional[float]
+
+    @property
+    def relative(self) -> float:
+        if self.required is None or self.current <= 0:
+            return 1.0
+        return (self.current - self.required) / self.current
+
+    def describe(self) -> str:
+        if self.required is None:
+            return f"resolve {self.subject}"
+        return f"reduce {self.subject} from {self.current:g} to ≤{self.required:g}"
+
+    def to_dict(self) -> dict:
+        return {**self.__dict__, "description": self.describe()}
+
+
+@dataclass
+class Remedy:
+    """A failed check: what crossed which threshold, by how much, and how to pass.
+
+    Attributes:
+        check: ratchet or fail_on
+        path: The file (ratchet), or the finding's first file (fail_on)
+        metric: The ratchet metric, or the finding type
+        value: Its value now
+        threshold: The ceiling, or the fail_on severity threshold
+        changes: Changes that pass the check
+        every: Whether all changes are needed, rather than any one of them
+    """
+
+    check: str
+    path: str
+    metric: str
+    value: float
+    threshold: float
+    changes: list[Change] = field(default_factory=list)
+    every: bool = False
+
+    @property
+    def excess(self) -> float:
+        return self.value - self.threshold
+
+    def describe(self) -> str:
+        crossed = (
+            f"{self.metric} {self.value:g} exceeds {self.threshold:g} by {_round(self.excess):g}"
+        )
+        if not self.changes:
+            return crossed
+        joined = (" and " if self.every else ", or ").join(c.describe() for c in self.changes)
+        return f"{crossed}: {joined}"
+
+    def to_dict(self) -> dict:
+        return {
+            "check": self.check,
+            "path": self.path,
+            "metric": self.metric,
+            "value": self.value,
+            "threshold": self.threshold,
+            "excess": _round(self.excess),
+            "every": self.every,
+            "changes": [c.to_dict() for c in self.changes],
+            "description": self.describe(),
+        }
+
+
+def ratchet_remedies(
+    root: Path, violations: list[Violation], settings: Any, extractor: Any = None
+) -> list[Remedy]:
+    """Remedies for ratchet violations; the violating files are parsed again.
+
+    Args:
+        root: Project root the violation paths are relative to
+        violations: From apply_ratchet
+        settings: AnalysisConfig (complexity_normalization, C preprocessor)
+        extractor: A SyntaxExtractor (default: a new single-worker one)
+    """
+    if extractor is None:
+        from ..scanning.gobuild import go_build_config
+        from ..scanning.syntax_extractor import SyntaxExtractor
+
+        extractor = SyntaxExtractor(
+            max_workers=1,
+            c_defines=settings.c_defines,
+            c_conditionals=settings.c_conditionals,
+            grammars=settings.grammars,
+            go_build=go_build_config(settings),
+        )
+    mode = settings.complexity_normalization
+    parsed: dict[str, tuple[Any, Optional[str]]] = {}
+    remedies = []
+    for v in violations:
+        if v.path not in parsed:
+            content: dict[str, str] = {}
+            syntax = extractor.extract(root / v.path, root, content)
+            parsed[v.path] = (syntax, content.get(v.path))
+        syntax, text = parsed[v.path]
+        remedy = Remedy("ratchet", v.path, v.metric, v.value, v.ceiling)
+        if syntax is not None and v.metric == "max_nesting":
+            remedy.every = True
+            remedy.changes = _nesting_changes(syntax, v.ceiling)
+        elif syntax is not None and v.metric == "cognitive_load":
+            remedy.changes = _cognitive_load_changes(syntax, text, mode, v.ceiling)
+        if not remedy.changes:
+            remedy.changes = [Change(v.metric, v.value, _required(v.metric, v.ceiling))]
+        remedies.append(remedy)
+    return remedies
+
+
+def finding_remedies(findings: list, fail_on: str) -> list[Remedy]:
+    """Remedies for the findings that fail ``--fail-on``, most severe first."""
+    threshold = FAIL_ON_SEVERITY[fail_on]
+    failing = [f for f in findings if f.severity > threshold or fail_on == "any"]
+    remedies = []
+    for f in sorted(failing, key=lambda f: -f.severity):
+        required = None if fail_on == "any" else threshold
+        subject = f"{f.finding_type} severity" if required is not None else f.title
+        remedies.append(
+            Remedy(
+                "fail_on",
+                f.files[0] if f.files else "",
+                f.finding_type,
+                round(f.severity, 3),
+                threshold,
+                [Change(subject, round(f.severity, 3), required)],
+            )
+        )
+    return remedies
+
+
+def _required(metric: str, ceiling: float) -> float:
+    return math.floor(ceiling) if metric in _COUNTS or metric == "max_nesting" else ceiling
+
+
+def _nesting_changes(syntax: Any, ceiling: float) -> list[Change]:
+    # syntax.max_nesting is the deepest of syntax.functions
+    limit = math.floor(ceiling)
+    deep = sorted(
+        (fn for fn in syntax.functions if fn.nesting_depth > limit),
+        key=lambda fn: (-fn.nesting_depth, fn.start_line),
+    )
+    return [Change(f"{fn.name} nesting", fn.nesting_depth, limit) for fn in deep]
+
+
+def _cognitive_load_changes(
+    syntax: Any, content: Optional[str], mode: str, ceiling: float
+) -> list[Change]:
+    """The value each cognitive_load factor would need, the others unchanged."""
+    from ..signals.complexity import normalized_complexity
+
+    if syntax.lines <= 0:
+        return []
+    complexity = normalized_complexity(syntax, content, mode)
+    gini = syntax.impl_gini or 0.0
+    factors = {
+        "lines": math.log2(syntax.lines + 1),
+        "complexity": 1 + complexity / 10,
+        "nesting": 1 + syntax.max_nesting / 5,
+        "gini": 1 + gini,
+    }
+    total = math.prod(factors.values())
+    changes = []
+    for name, factor in factors.items():
+        # The largest value of this factor that keeps the product under the ceiling
+        allowed = ceiling / (total / factor)
+        if name == "lines":
+            required = math.floor(2**allowed - 1)
+            if 0 < required < syntax.lines:
+                changes.append(Change("lines", syntax.lines, required))
+        elif name == "complexity" and allowed > 1:
+            required = _floor(10 * (allowed - 1))
+            if required < complexity:
+                changes.append(Change(_complexity_subject(syntax), _round(complexity), required))
+        elif name == "nesting" and allowed >= 1:
+            required = math.floor(5 * (allowed - 1))
+            if required < syntax.max_nesting:
+                changes.append(Change("max_nesting", syntax.max_nesting, required))
+        elif name == "gini" and allowed > 1:
+            required = _floor(allowed - 1)
+            if required < gini:
+                changes.append(Change("impl_gini", _round(gini), required))
+    return sorted(changes, key=lambda c: c.relative)
+
+
+def _complexity_subject(syntax: Any) -> str:
+    """The complexity term is a mean over functions; with one, it is that function's."""
+    if len(syntax.functions) == 1:
+        return f"{syntax.functions[0].name} complexity"
+    return "mean function complexity"
+
+
+def _floor(value: float) -> float:
+    return math.floor(value * 100) / 100
+
+
+def _round(value: float) -> float:
+    return round(value, 2)
+
+
+def markdown_comment(
+    passed: bool, remedies: list[Remedy], notes: Optional[list[str]] = None
+) -> str:
+    """The gate outcome as Markdown, for a pull request comment."""
+    if passed:
+        lines = ["### ✅ Quality gate passed"]
+    else:
+        lines = ["### ❌ Quality gate failed"]
+    for check in ("ratchet", "fail_on"):
+        failures = [r for r in remedies if r.check == check]
+        if not failures:
+            continue
+        title = "Ratchet ceilings" if check == "ratchet" else "Findings over the fail-on threshold"
+        lines += ["", f"**{title}**", "", "| File | Metric | Threshold | Now | Over by |"]
+        lines.append("|---|---|---:|---:|---:|")
+        for r in failures:
+            lines.append(
+                f"| `{r.path}` | {r.metric} | {r.threshold:g} | {r.value:g} | "
+                f"{_round(r.excess):g} |"
+            )
+        lines += ["", "To pass:", ""]
+        for r in failures:
+            joined = (" and " if r.every else ", or ").join(c.describe() for c in r.changes)
+            lines.append(f"- `{r.path}`: {joined}")
+    if notes:
+        lines.append("")
+        lines.extend(f"_{note}_" for note in notes)
+    return "\n".join(lines) + "\n"
@@ -23,8 +23,12 @@ LANGUAGES = [
     ("typescript", "tree-sitter-typescript"),
     ("go", "tree-sitter-go"),
     ("java", "tree-sitter-java"),
+    ("kotlin", "tree-sitter-kotlin"),
     ("rust", "tree-sitter-rust"),
     ("ruby", "tree-sitter-ruby"),
+    ("php", "tree-sitter-php"),
+    ("scala", "tree-sitter-scala"),
+    ("swift", "tree-sitter-swift"),
     ("cpp", "tree-sitter-cpp"),
 ]
 
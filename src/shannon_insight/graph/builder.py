@@ -1,10 +1,14 @@
 """Dependency graph construction from import declarations."""
 
+import posixpath
+import re
 from pathlib import Path
 from typing import Optional
 
+from ..scanning.proto import link_stubs
 from ..scanning.syntax import FileSyntax
 from .models import DependencyGraph
+from .sbt import SbtProject, dependency_closure, discover_sbt_projects, project_of
 
 # Language-specific file extensions for import resolution
 LANGUAGE_EXTENSIONS: dict[str, list[str]] = {
@@ -13,12 +17,22 @@ LANGUAGE_EXTENSIONS: dict[str, list[str]] = {
     "typescript": [".ts", ".tsx", "/index.ts", "/index.tsx", ".d.ts"],
     "javascript": [".js", ".jsx", ".mjs", ".cjs", "/index.js", "/index.jsx"],
     "java": [".java"],
+    "kotlin": [".kt", ".kts"],
+    "scala": [".scala"],
+    "swift": [".swift"],
+    "sql": [".sql"],
+    "hcl": [".tf", ".hcl"],
+    "proto": [".proto"],
     "rust": [".rs", "/mod.rs"],
     "ruby": [".rb"],
+    "php": [".php"],
     "c": [".c", ".h"],
-    "cpp": [".cpp", ".hpp", ".cc", ".hh"],
+    "cpp": [".cpp", ".hpp", ".cc", ".hh", ".cxx", ".hxx", ".h"],
 }
 
+# Directories Rails autoloads constants from (app/models/, app/models/concerns/, lib/)
+_RUBY_AUTOLOAD_ROOT_RE = re.compile(r"(?:^|/)(?:app/[^/]+/(?:concerns/)?|lib/)$")
+
 # Known stdlib/builtin modules per language (common ones to exclude from phantom tracking)
 # These are root module names that should NOT be flagged as phantom imports
 STDLIB_ROOTS: dict[str, set[str]] = {
@@ -237,6 +251,7 @@ STDLIB_ROOTS: dict[str, set[str]] = {
         "matplotlib",
     },
     "go": {
+        "C",  # cgo
         "archive",
         "bufio",
         "builtin",
@@ -411,6 +426,59 @@ STDLIB_ROOTS: dict[str, set[str]] = {
         "org.w3c",
         "org.xml",
     },
+    "kotlin": {
+        "kotlin",
+        "kotlinx",
+        "java",
+        "javax",
+        "android",
+        "androidx",
+    },
+    "scala": {
+        "scala",
+        "java",
+        "javax",
+        "jdk",
+        "sun",
+    },
+    # Swift imports name modules: the standard library and Apple's frameworks
+    "swift": {
+        "Swift",
+        "Foundation",
+        "Dispatch",
+        "Darwin",
+        "Glibc",
+        "os",
+        "ObjectiveC",
+        "CoreFoundation",
+        "UIKit",
+        "AppKit",
+        "SwiftUI",
+        "Combine",
+        "Observation",
+        "SwiftData",
+        "CoreData",
+        "CoreGraphics",
+        "CoreImage",
+        "CoreLocation",
+        "CoreML",
+        "CoreText",
+        "QuartzCore",
+        "AVFoundation",
+        "AVKit",
+        "MapKit",
+        "WebKit",
+        "StoreKit",
+        "Photos",
+        "PhotosUI",
+        "UserNotifications",
+        "Security",
+        "LocalAuthentication",
+        "Network",
+        "CryptoKit",
+        "XCTest",
+        "Testing",
+    },
     "rust": {
         "std",
         "core",
@@ -497,7 +565,11 @@ STDLIB_ROOTS: dict[str, set[str]] = {
 }
 
 
-def build_dependency_graph(file_syntax: list[FileSyntax], root_dir: str = "") -> DependencyGraph:
+def build_dependency_graph(
+    file_syntax: list[FileSyntax],
+    root_dir: str = "",
+    aliases: Optional[dict[str, str]] = None,
+) -> DependencyGraph:
     """Build dependency graph from import declarations in FileSyntax.
 
     Also tracks unresolved imports for phantom_import_count signal.
@@ -505,21 +577,87 @@ def build_dependency_graph(file_syntax: list[FileSyntax], root_dir: str = "") ->
     as unresolved — stdlib and third-party imports are excluded.
 
     Now language-aware: uses source file's language to determine resolution rules.
+
+    aliases maps paths that exist but were not analyzed (identical copies)
+    to the analyzed file; imports of an alias become edges to its target.
+
+    Scala imports resolve by package path and declared class names (see
+    _ScalaIndex); with a build.sbt in root_dir, a class defined in several
+    sub-projects resolves to the importing project's own or a dependency's.
+
+    Swift imports name modules, not files: importing a module of this
+    codebase (a directory of Swift sources, like Sources/FeedCore/) is
+    neither a phantom nor an external package.
+
+    SQL imports are table names (see scanning/sql.py) and resolve to the
+    file that creates the table: the first by path, which for numbered or
+    timestamped migrations is the one that introduced it. Tables created
+    elsewhere (by an ORM, another service) are neither phantom nor external.
+
+    HCL imports are Terraform module sources. A local source ("./modules/vpc")
+    names a directory and resolves to its main.tf, or its first .tf file by
+    path; registry and git sources are external.
+
+    Protocol Buffers imports are paths relative to an include directory, and
+    resolve to the .proto file at that path or, failing that, the first one
+    by path ending in it; google/protobuf/ well-known types are built in.
+    An import that resolves to nothing is a phantom when .proto files of the
+    codebase sit in its directory, and external (googleapis, buf modules)
+    otherwise.
+    protoc stubs (user.pb.go, user_pb2.py) get an edge to the .proto file
+    they were generated from (see scanning/proto.py).
     """
+    aliases = aliases or {}
     file_map: dict[str, FileSyntax] = {f.path: f for f in file_syntax}
     all_paths = set(file_map.keys())
     adjacency: dict[str, list[str]] = {p: [] for p in all_paths}
     reverse: dict[str, list[str]] = {p: [] for p in all_paths}
     unresolved: dict[str, list[str]] = {}  # Phase 3: track unresolved imports
+    external: dict[str, list[str]] = {}
     edge_count = 0
 
-    path_index = _build_path_index(all_paths)
+    # Aliases resolve like real files, so importing a copy is not a phantom
+    resolvable = all_paths | aliases.keys()
+    path_index = _build_path_index(resolvable)
     project_prefixes = _infer_project_prefixes(all_paths)
+    scala = (
+        _ScalaIndex(file_syntax, discover_sbt_projects(root_dir) if root_dir else [])
+        if any(fs.language == "scala" for fs in file_syntax)
+        else None
+    )
+    swift_modules = _swift_module_names(file_syntax)
+    sql_tables = _sql_table_files(file_syntax)
+    hcl_modules = _hcl_module_files(file_syntax)
+    proto_files = sorted(p for p in all_paths if file_map[p].language == "proto")
 
     for fs in file_syntax:
         language = fs.language  # Now we use the language!
         for imp in fs.import_sources:
-            resolved = _resolve_import(imp, fs.path, language, path_index, all_paths)
+            if language == "swift" and imp in swift_modules:
+                continue
+            if language == "sql":
+                resolved = sql_tables.get(imp)
+                if resolved is None:
+                    continue
+            elif language == "hcl":
+                directory = _hcl_module_dir(imp, fs.path)
+                resolved = hcl_modules.get(directory) if directory is not None else None
+            elif language == "proto":
+                if imp.startswith("google/protobuf/"):
+                    continue
+                resolved = _proto_import_file(imp, proto_files)
+                if resolved is None:
+                    # Phantom when its directory holds .proto files here, else a dependency's
+                    tracked = unresolved if _proto_dir_exists(imp, proto_files) else external
+                    tracked.setdefault(fs.path, []).append(imp)
+                    continue
+            elif language == "scala" and scala is not None:
+                resolved = scala.resolve(imp, fs.path)
+                if resolved is None and scala.in_project_package(imp):
+                    continue  # declared in a file not named after it; not a phantom
+            else:
+                resolved = _resolve_import(imp, fs.path, language, path_index, resolvable)
+            resolved = aliases.get(resolved, resolved) if resolved else resolved
             if resolved and resolved != fs.path:
                 adjacency[fs.path].append(resolved)
                 reverse[resolved].append(fs.path)
@@ -529,6 +667,15 @@ def build_dependency_graph(file_syntax: list[FileSyntax], root_dir: str = "") ->
                 if fs.path not in unresolved:
                     unresolved[fs.path] = []
                 unresolved[fs.path].append(imp)
+            elif resolved is None and _looks_external(imp, language):
+                external.setdefault(fs.path, []).append(imp)
+
+    if proto_files:
+        for stub, definition in link_stubs(all_paths).items():
+            if definition not in adjacency[stub]:
+                adjacency[stub].append(definition)
+                reverse[definition].append(stub)
+                edge_count += 1
 
     return DependencyGraph(
         adjacency=adjacency,
@@ -536,6 +683,64 @@ def build_dependency_graph(file_syntax: list[FileSyntax], root_dir: str = "") ->
         all_nodes=all_paths,
         edge_count=edge_count,
         unresolved_imports=unresolved,
+        external_imports=external,
+    )
+
+
+def _swift_module_names(file_syntax: list[FileSyntax]) -> set[str]:
+    """Names of the directories holding Swift sources: the codebase's own modules."""
+    names: set[str] = set()
+    for fs in file_syntax:
+        if fs.language == "swift":
+            names.update(Path(fs.path).parent.parts)
+    return names
+
+
+def _sql_table_files(file_syntax: list[FileSyntax]) -> dict[str, str]:
+    """Lowercase table name -> the first SQL file (by path) that creates it."""
+    tables: dict[str, str] = {}
+    for fs in sorted(file_syntax, key=lambda f: f.path):
+        if fs.language == "sql":
+            for cls in fs.classes:
+                tables.setdefault(cls.name.lower(), fs.path)
+    return tables
+
+
+def _hcl_module_files(file_syntax: list[FileSyntax]) -> dict[str, str]:
+    """Directory -> the file a module call into it resolves to: main.tf, else the first."""
+    modules: dict[str, str] = {}
+    for fs in sorted(file_syntax, key=lambda f: f.path):
+        if fs.language == "hcl":
+            directory, _, name = fs.path.rpartition("/")
+            if name == "main.tf" or directory not in modules:
+                modules[directory] = fs.path
+    return modules
+
+
+def _hcl_module_dir(source: str, importer: str) -> Optional[str]:
+    """Directory a local module source names, relative to the root ("" for the root).
+
+    None for registry and git sources.
+    """
+    if not source.startswith(("./", "../")):
+        return None
+    directory = posixpath.normpath(posixpath.join(posixpath.dirname(importer), source))
+    return "" if directory == "." else directory
+
+
+def _proto_import_file(imp: str, proto_files: list[str]) -> Optional[str]:
+    """The .proto file an import path names: exact, else the first ending in it."""
+    if imp in proto_files:
+        return imp
+    return next((p for p in proto_files if p.endswith("/" + imp)), None)
+
+
+def _proto_dir_exists(imp: str, proto_files: list[str]) -> bool:
+    """Whether a .proto file of the codebase is in the directory *imp* names."""
+    directory = posixpath.dirname(imp)
+    return any(
+        posixpath.dirname(p) == directory or posixpath.dirname(p).endswith("/" + directory)
+        for p in proto_files
     )
 
 
@@ -592,6 +797,12 @@ def _looks_internal(imp: str, language: str, project_prefixes: set[str]) -> bool
     if imp.startswith("@"):
         return False
 
+    # PHP class names: internal when the root namespace matches a directory
+    # (App\Models\User under app/)
+    if language == "php" and "\\" in imp:
+        root_namespace = imp.lstrip("\\").split("\\")[0].lower()
+        return root_namespace in {prefix.lower() for prefix in project_prefixes}
+
     # Check language-specific stdlib
     stdlib_roots = STDLIB_ROOTS.get(language, set())
     first_segment = imp.split(".")[0].split("/")[0]
@@ -610,6 +821,26 @@ def _looks_internal(imp: str, language: str, project_prefixes: set[str]) -> bool
     return False
 
 
+def _looks_external(imp: str, language: str) -> bool:
+    """Check if an unresolved, non-internal import is a third-party package.
+
+    Stdlib modules are not, and neither are C/C++ includes (system headers
+    cannot be told apart from libraries), Ruby constants (core classes),
+    global PHP classes (built in) or style sheets.
+    """
+    imp = imp.strip().strip('"')
+    if not imp or imp.startswith((".", "node:")) or language in ("c", "cpp"):
+        return False
+    if imp.endswith((".css", ".scss", ".sass", ".less", ".styl", ".stylus")):
+        return False
+    if language == "ruby" and imp[:1].isupper():
+        return False
+    if language == "php":
+        return "\\" in imp  # Vendor\Package\Class; include paths are never packages
+    first_segment = imp.split(".")[0].split("/")[0].split("::")[0]
+    return first_segment not in STDLIB_ROOTS.get(language, set())
+
+
 def _build_path_index(all_paths: set[str]) -> dict[str, str]:
     """Map dotted module paths to file paths for import resolution.
 
@@ -622,7 +853,9 @@ def _build_path_index(all_paths: set[str]) -> dict[str, str]:
         dotted = path.replace("/", ".").replace("\\", ".")
 
         # Remove known extensions
-        for ext in (".py", ".ts", ".tsx", ".js", ".jsx", ".mjs", ".go", ".java", ".rs", ".rb"):
+        for ext in (
+            ".py", ".ts", ".tsx", ".js", ".jsx", ".mjs", ".go", ".java", ".kt", ".kts", ".rs", ".rb"
+        ):
             if dotted.endswith(ext):
                 dotted = dotted[: -len(ext)]
                 break
@@ -660,10 +893,30 @@ def _resolve_import(
     """
     imp = imp.strip()
 
+    # ── C/C++ #include paths ───────────────────────────────────────
+    if language in ("c", "cpp"):
+        return _resolve_include(imp, source_path, all_paths)
+
+    # ── Ruby constants (Rails autoloading) ─────────────────────────
+    if language == "ruby" and imp[:1].isupper():
+        return _resolve_ruby_constant(imp, all_paths)
+
+    # ── PHP includes and classes (PSR-4 autoloading) ───────────────
+    if language == "php":
+        if "/" in imp or imp.endswith((".php", ".inc")):
+            return _resolve_include(imp, source_path, all_paths)
+        return _resolve_php_class(imp, all_paths)
+
     # ── Relative imports (leading dots or ./) ────────────────────────
     if imp.startswith("."):
         return _resolve_relative_import(imp, source_path, language, all_paths)
 
+    # ── Notebooks run with their own directory first on sys.path ──
+    if source_path.endswith(".ipynb"):
+        directory = posixpath.dirname(source_path).replace("/", ".")
+        if directory and f"{directory}.{imp}" in path_index:
+            return path_index[f"{directory}.{imp}"]
+
     # ── Absolute imports ───────────────────────────────────────────
     # Try exact match in index
     if imp in path_index:
@@ -687,6 +940,153 @@ def _resolve_import(
     return None
 
 
+def _resolve_include(imp: str, source_path: str, all_paths: set[str]) -> Optional[str]:
+    """Resolve an #include path the way a compiler searches for it.
+
+    First relative to the including file, then from the project root, then
+    under any include directory (``include/``, ``src/``...): the file whose
+    path ends with the include path and shares the most leading directories
+    with the including file.
+    """
+    beside_source = posixpath.join(posixpath.dirname(source_path), imp)
+    for candidate in (posixpath.normpath(beside_source), posixpath.normpath(imp)):
+        if candidate in all_paths:
+            return candidate
+
+    suffix = "/" + posixpath.normpath(imp).lstrip("./")
+    matches = [p for p in all_paths if p.endswith(suffix)]
+    if not matches:
+        return None
+    return min(matches, key=lambda p: (-_shared_directories(p, source_path), len(p), p))
+
+
+def _shared_directories(path: str, other: str) -> int:
+    """Number of leading path components *path* and *other* have in common."""
+    count = 0
+    for a, b in zip(path.split("/"), other.split("/")):
+        if a != b:
+            break
+        count += 1
+    return count
+
+
+def _resolve_ruby_constant(constant: str, all_paths: set[str]) -> Optional[str]:
+    """Resolve a Ruby constant the way Rails autoloads it.
+
+    ``Admin::UserSettings`` lives in ``admin/user_settings.rb`` under an
+    autoload root: any ``app/<kind>/`` directory, its ``concerns/``, or
+    ``lib/`` (engines nest these under their own directory).
+    """
+    # UserSettings -> user_settings, HTMLParser -> html_parser
+    words = r"(?<=[a-z0-9])(?=[A-Z])|(?<=[A-Z])(?=[A-Z][a-z])"
+    suffix = "/".join(re.sub(words, "_", part).lower() for part in constant.split("::")) + ".rb"
+    matches = [
+        p
+        for p in all_paths
+        if p.endswith(suffix) and _RUBY_AUTOLOAD_ROOT_RE.search(p[: -len(suffix)])
+    ]
+    return min(matches, key=lambda p: (len(p), p)) if matches else None
+
+
+def _resolve_php_class(name: str, all_paths: set[str]) -> Optional[str]:
+    """Resolve a fully qualified PHP class the way a PSR-4 autoloader does.
+
+    A namespace prefix maps to a base directory and the rest of the name
+    to the path below it: ``App\\Models\\User`` is ``Models/User.php`` under
+    the directory for ``App\\`` (often ``app/`` or ``src/``). The prefix
+    mapping lives in composer.json, so the longest matching path suffix
+    wins; at least the class and its parent namespace must match unless
+    the name is that short.
+    """
+    parts = name.lstrip("\\").split("\\")
+    shortest = 1 if len(parts) <= 2 else 2
+    lowered = {p.lower(): p for p in all_paths if p.endswith(".php")}
+    for length in range(len(parts), shortest - 1, -1):
+        suffix = "/".join(parts[-length:]).lower() + ".php"
+        matches = [p for low, p in lowered.items() if low == suffix or low.endswith("/" + suffix)]
+        if matches:
+            return min(matches, key=lambda p: (len(p), p))
+    return None
+
+
+class _ScalaIndex:
+    """Scala files by the fully qualified names they may declare.
+
+    Scala does not tie classes to files, but by convention ``com.acme.User``
+    is declared in ``com/acme/User.scala`` below a source root such as
+    ``src/main/scala/``, and a file may declare more classes than the one
+    it is named after. Each file is indexed under every dotted suffix of its
+    directory joined with its name and with each class it declares, so a
+    name finds its file whatever the source root.
+    """
+
+    def __init__(self, file_syntax: list[FileSyntax], projects: list[SbtProject]) -> None:
+        self.projects = projects
+        self.files: dict[str, list[str]] = {}
+        self.packages: set[str] = set()
+        for fs in file_syntax:
+            if fs.language != "scala":
+                continue
+            *directory, filename = fs.path.split("/")
+            names = {filename.rsplit(".", 1)[0]} | {cls.name for cls in fs.classes}
+            for start in range(len(directory) + 1):
+                package = directory[start:]
+                if package:
+                    self.packages.add(".".join(package))
+                for name in names:
+                    self.files.setdefault(".".join(package + [name]), []).append(fs.path)
+
+    def resolve(self, name: str, source_path: str) -> Optional[str]:
+        """File declaring *name*, or None.
+
+        Members (com.acme.Util.helper) and wildcards (com.acme._) resolve to
+        their object's file, or to the package object (package.scala).
+        """
+        parts = self._parts(name)
+        for end in range(len(parts), min(len(parts), 2) - 1, -1):
+            prefix = ".".join(parts[:end])
+            for key in (prefix, prefix + ".package"):
+                if key in self.files:
+                    return self._closest(self.files[key], source_path)
+        return None
+
+    def in_project_package(self, name: str) -> bool:
+        """True if *name* lies in a package some Scala file of the project is in."""
+        parts = self._parts(name)
+        package = parts if name.endswith("._") else parts[:-1]
+        # Two segments at least: com alone is in every com.* project
+        shortest = min(len(package), 2)
+        return any(
+            ".".join(package[:end]) in self.packages
+            for end in range(len(package), shortest - 1, -1)
+        )
+
+    def _closest(self, paths: list[str], source_path: str) -> str:
+        """Of several declaring files: the importing sbt project's, a dependency's, then the
+        one sharing the most directories with the importer."""
+        if len(paths) == 1:
+            return paths[0]
+        source = project_of(source_path, self.projects)
+        dependencies = dependency_closure(source.name, self.projects) if source else set()
+
+        def rank(path: str) -> tuple[int, int, int, str]:
+            project = project_of(path, self.projects)
+            if project is source:
+                tier = 0
+            elif project is not None and project.name in dependencies:
+                tier = 1
+            else:
+                tier = 2
+            return tier, -_shared_directories(path, source_path), len(path), path
+
+        return min(paths, key=rank)
+
+    @staticmethod
+    def _parts(name: str) -> list[str]:
+        parts = [p for p in name.strip().split(".") if p]
+        return parts[:-1] if parts and parts[-1] == "_" else parts
+
+
 def _resolve_relative_import(
     imp: str, source_path: str, language: str, all_paths: set[str]
 ) -> Optional[str]:
@@ -0,0 +1,209 @@
+"""Centrality of symbols in the call graph and packages in the import graph.
+
+Complexity says where code is hard to work on; centrality says where that
+matters. A tangled function nothing calls can wait, one on every request
+path cannot. The report puts both side by side:
+
+    symbols   functions and classes by PageRank and betweenness in the
+              call graph (see symbols.py), with cyclomatic complexity
+    packages  directories by PageRank and betweenness in the import graph,
+              file imports collapsed into package-to-package edges
+
+``CentralityReport.hotspots`` ranks functions by the product of their
+PageRank and complexity percentiles: most central and most complex first.
+"""
+
+from __future__ import annotations
+
+from bisect import bisect_right
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Iterable, Optional
+
+from ..hygiene.sources import SourceSet
+from ..math.graph import GraphMetrics
+from ..scanning.syntax import FileSyntax
+from .algorithms import compute_centrality_gini
+from .symbols import BETWEENNESS_SAMPLE, SymbolRank, call_graph, has_call_targets, rank_symbols
+
+if TYPE_CHECKING:
+    from ..signals.function_outliers import FunctionSample
+
+
+@dataclass
+class PackageRank:
+    """A package's centrality in the import graph."""
+
+    package: str
+    pagerank: float
+    betweenness: float
+    dependents: int  # other packages importing it
+    dependencies: int  # other packages it imports
+    files: int
+
+
+@dataclass
+class Hotspot:
+    """A function that is both central and complex."""
+
+    rank: SymbolRank
+    complexity: int
+    score: float  # PageRank percentile x complexity percentile, 0-1
+
+
+@dataclass
+class CentralityReport:
+    """Symbol and package centrality for one codebase.
+
+    Attributes:
+        symbols: Symbols that are called at all, highest PageRank first
+        packages: Every package, highest PageRank first
+        complexity: Symbol id -> cyclomatic complexity (functions only)
+        call_graph_available: False when no file was parsed with call targets
+    """
+
+    symbols: list[SymbolRank] = field(default_factory=list)
+    packages: list[PackageRank] = field(default_factory=list)
+    complexity: dict[str, int] = field(default_factory=dict)
+    call_graph_available: bool = True
+
+    def hotspots(self, top: int = 10) -> list[Hotspot]:
+        """Called functions ranked by PageRank percentile x complexity percentile."""
+        functions = [
+            r
+            for r in self.symbols
+            if r.symbol.kind == "function" and r.symbol.id in self.complexity
+        ]
+        pageranks = sorted(r.pagerank for r in functions)
+        complexities = sorted(self.complexity[r.symbol.id] for r in functions)
+        hotspots = []
+        for rank in functions:
+            complexity = self.complexity[rank.symbol.id]
+            score = _percentile(pageranks, rank.pagerank) * _percentile(complexities, complexity)
+            hotspots.append(Hotspot(rank, complexity, score))
+        hotspots.sort(key=lambda h: (-h.score, -h.rank.pagerank, h.rank.symbol.id))
+        return hotspots[:top]
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        global_signals: dict[str, float] = {}
+        if self.symbols:
+            pagerank = {r.symbol.id: r.pagerank for r in self.symbols}
+            global_signals["call_centrality_gini"] = compute_centrality_gini(pagerank)
+        package_signals = {
+            p.package: {"package_pagerank": p.pagerank, "package_betweenness": p.betweenness}
+            for p in self.packages
+        }
+        return global_signals, package_signals
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "call_graph_available": self.call_graph_available,
+            "symbols": [
+                {
+                    "path": r.symbol.path,
+                    "name": r.symbol.name,
+                    "kind": r.symbol.kind,
+                    "line": r.symbol.line,
+                    "pagerank": round(r.pagerank, 6),
+                    "betweenness": round(r.betweenness, 6),
+                    "callers": r.callers,
+                    "complexity": self.complexity.get(r.symbol.id),
+                }
+                for r in self.symbols[:top]
+            ],
+            "packages": [
+                {
+                    "package": p.package,
+                    "pagerank": round(p.pagerank, 6),
+                    "betweenness": round(p.betweenness, 6),
+                    "dependents": p.dependents,
+                    "dependencies": p.dependencies,
+                    "files": p.files,
+                }
+                for p in self.packages[:top]
+            ],
+            "hotspots": [
+                {
+                    "path": h.rank.symbol.path,
+                    "name": h.rank.symbol.name,
+                    "line": h.rank.symbol.line,
+                    "complexity": h.complexity,
+                    "pagerank": round(h.rank.pagerank, 6),
+                    "score": round(h.score, 4),
+                }
+                for h in self.hotspots(top or 10)
+            ],
+        }
+
+
+def rank_packages(imports: dict[str, list[str]]) -> list[PackageRank]:
+    """Packages by PageRank and betweenness of the package import graph.
+
+    Args:
+        imports: path -> paths it imports (the dependency graph adjacency,
+            with every analyzed file as a key)
+    """
+    files: dict[str, int] = {}
+    edges: dict[str, set[str]] = {}
+    for path, targets in imports.items():
+        source = SourceSet.package_of(path)
+        files[source] = files.get(source, 0) + 1
+        edges.setdefault(source, set())
+        for target in targets:
+            if SourceSet.package_of(target) != source:
+                edges[source].add(SourceSet.package_of(target))
+    adjacency = {package: sorted(targets) for package, targets in edges.items()}
+    pagerank = GraphMetrics.pagerank(adjacency)
+    betweenness = GraphMetrics.betweenness_centrality(adjacency, sample=BETWEENNESS_SAMPLE)
+    dependents: dict[str, int] = {}
+    for targets in adjacency.values():
+        for target in targets:
+            dependents[target] = dependents.get(target, 0) + 1
+    ranks = [
+        PackageRank(
+            package=package,
+            pagerank=pagerank.get(package, 0.0),
+            betweenness=betweenness.get(package, 0.0),
+            dependents=dependents.get(package, 0),
+            dependencies=len(adjacency.get(package, [])),
+            files=files.get(package, 0),
+        )
+        for package in sorted(set(adjacency) | set(dependents))
+    ]
+    return sorted(ranks, key=lambda r: (-r.pagerank, r.package))
+
+
+def build_centrality(
+    syntax: dict[str, FileSyntax],
+    imports: dict[str, list[str]],
+    complexity: Optional[dict[str, int]] = None,
+) -> CentralityReport:
+    """Centrality of every called symbol and every package.
+
+    Args:
+        syntax: path -> FileSyntax of every parsed file
+        imports: path -> paths it imports (the dependency graph adjacency)
+        complexity: symbol id (``path:name``) -> cyclomatic complexity, for
+            hotspots; see symbol_complexity
+    """
+    symbols, edges = call_graph(syntax, imports)
+    return CentralityReport(
+        symbols=rank_symbols(symbols, edges),
+        packages=rank_packages({path: imports.get(path, []) for path in syntax}),
+        complexity=dict(complexity or {}),
+        call_graph_available=has_call_targets(syntax),
+    )
+
+
+def symbol_complexity(samples: Iterable[FunctionSample]) -> dict[str, int]:
+    """Symbol id -> cyclomatic complexity; same-named functions in a file keep the highest."""
+    complexity: dict[str, int] = {}
+    for sample in samples:
+        symbol_id = f"{sample.path}:{sample.name}"
+        complexity[symbol_id] = max(complexity.get(symbol_id, 0), sample.complexity)
+    return complexity
+
+
+def _percentile(ordered: list, value: float) -> float:
+    """Fraction of *ordered* values at or below *value*."""
+    return bisect_right(ordered, value) / len(ordered) if ordered else 0.0
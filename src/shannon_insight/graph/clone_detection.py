@@ -7,23 +7,41 @@ Where C(x) = len(zlib.compress(x)).
 
 Clone threshold: NCD < 0.3
 
-For codebases with >= 1000 files, uses MinHash + LSH for pre-filtering
-to avoid O(n²) blowup. For smaller codebases, direct pairwise is fast enough.
+For codebases with >= 1000 files, candidate pairs come from winnowing
+fingerprints (see winnowing.py) instead of all-pairs comparison: only a
+fingerprint index is kept in memory, and NCD is computed for candidates
+only, loading two files at a time. For smaller codebases, direct pairwise
+is fast enough.
 """
 
 import zlib
+from collections import Counter
+from typing import Callable, Iterable, Optional
 
 from .models import ClonePair
+from .winnowing import DEFAULT_GUARANTEE_TOKENS, DEFAULT_NOISE_TOKENS, fingerprints
 
 # NCD threshold: files with NCD below this are considered clones
 CLONE_THRESHOLD = 0.3
 
-# File count threshold for switching to LSH
-LSH_FILE_THRESHOLD = 1000
+# File count threshold for switching to winnowing
+WINNOW_FILE_THRESHOLD = 1000
 
 # Minimum file size to consider (skip empty/tiny files)
 MIN_FILE_SIZE = 10
 
+# Roles that are excluded when BOTH files of a pair match (compared upper-cased:
+# the semantic analyzer stores Role values, which are lower case)
+EXCLUDED_ROLES = {"TEST", "MIGRATION"}
+
+# Fingerprints shared by more files than this are boilerplate (license
+# headers, import blocks) and would generate quadratically many candidates
+COMMON_FINGERPRINT_FILES = 50
+
+# Candidate pairs must share at least this fraction of the smaller file's
+# fingerprints; NCD < 0.3 clones share far more
+MIN_SHARED_FRACTION = 0.5
+
 
 def compute_ncd(content_a: bytes, content_b: bytes) -> float:
     """Compute Normalized Compression Distance between two byte strings.
@@ -60,8 +78,8 @@ def detect_clones(
 ) -> list[ClonePair]:
     """Detect clone pairs in a codebase.
 
-    Uses direct pairwise comparison for smaller codebases (<1000 files).
-    For larger codebases, uses MinHash+LSH pre-filtering.
+    Uses direct pairwise comparison, holding every file in memory. For
+    larger codebases (>= 1000 files) use detect_clones_winnowed().
 
     Exclusion rules:
     - Skip file pairs where BOTH files have role=TEST or role=MIGRATION
@@ -82,15 +100,6 @@ def detect_clones(
     if len(valid_files) < 2:
         return []
 
-    # Roles that are excluded when BOTH files match
-    excluded_roles = {"TEST", "MIGRATION"}
-
-    def should_skip_pair(path_a: str, path_b: str) -> bool:
-        """Check if pair should be excluded based on roles."""
-        role_a = roles.get(path_a, "")
-        role_b = roles.get(path_b, "")
-        return role_a in excluded_roles and role_b in excluded_roles
-
     # Pre-compute compressed sizes for size-ratio pre-filter.
     # Spec: only compare files with similar size (±30%) to prune O(n²).
     paths = sorted(valid_files.keys())
@@ -101,15 +110,13 @@ def detect_clones(
         size_a = sizes[path_a]
         content_a = valid_files[path_a]
         for path_b in paths[i + 1 :]:
-            if should_skip_pair(path_a, path_b):
+            if _skip_pair(path_a, path_b, roles):
                 continue
 
             # Size pre-filter: skip if sizes differ by more than 30%
             size_b = sizes[path_b]
-            if size_a > 0 and size_b > 0:
-                ratio = min(size_a, size_b) / max(size_a, size_b)
-                if ratio < 0.7:
-                    continue
+            if not _similar_size(size_a, size_b):
+                continue
 
             content_b = valid_files[path_b]
             ncd = compute_ncd(content_a, content_b)
@@ -128,6 +135,95 @@ def detect_clones(
     return clones
 
 
+def detect_clones_winnowed(
+    paths: Iterable[str],
+    load: Callable[[str], Optional[bytes]],
+    roles: dict[str, str],
+    threshold: float = CLONE_THRESHOLD,
+    guarantee_tokens: int = DEFAULT_GUARANTEE_TOKENS,
+    noise_tokens: int = DEFAULT_NOISE_TOKENS,
+) -> list[ClonePair]:
+    """Detect clone pairs in space linear in code size.
+
+    Each file is loaded once to compute its winnowing fingerprints and then
+    dropped. Files sharing at least MIN_SHARED_FRACTION of the smaller
+    file's fingerprints become candidates, which are confirmed with NCD
+    exactly as in detect_clones().
+
+    Any copied run of guarantee_tokens or more tokens yields a shared
+    fingerprint; raise it to save memory, lower it to catch shorter clones.
+
+    Args:
+        paths: File paths to consider
+        load: Returns a file's content, or None if unreadable
+        roles: Mapping of file path to role (from Phase 2)
+        threshold: NCD threshold below which files are clones (default 0.3)
+        guarantee_tokens: Shared runs at least this long are always found
+        noise_tokens: Shared runs shorter than this are ignored
+
+    Returns:
+        List of ClonePair objects for all detected clones
+    """
+    files: list[str] = []
+    sizes: list[int] = []
+    counts: list[int] = []
+    index: dict[int, list[int]] = {}
+    for path in sorted(paths):
+        content = load(path)
+        if content is None or len(content) < MIN_FILE_SIZE:
+            continue
+        prints = fingerprints(content, guarantee_tokens, noise_tokens)
+        if not prints:
+            continue
+        number = len(files)
+        files.append(path)
+        sizes.append(len(content))
+        counts.append(len(prints))
+        for fp in prints:
+            index.setdefault(fp, []).append(number)
+
+    shared: Counter[tuple[int, int]] = Counter()
+    for postings in index.values():
+        if len(postings) < 2 or len(postings) > COMMON_FINGERPRINT_FILES:
+            continue
+        for i, a in enumerate(postings):
+            for b in postings[i + 1 :]:
+                shared[(a, b)] += 1
+    del index
+
+    clones: list[ClonePair] = []
+    for (a, b), count in sorted(shared.items()):
+        path_a, path_b = files[a], files[b]
+        if count < MIN_SHARED_FRACTION * min(counts[a], counts[b]):
+            continue
+        if _skip_pair(path_a, path_b, roles) or not _similar_size(sizes[a], sizes[b]):
+            continue
+        content_a, content_b = load(path_a), load(path_b)
+        if content_a is None or content_b is None:
+            continue
+        ncd = compute_ncd(content_a, content_b)
+        if ncd < threshold:
+            clones.append(
+                ClonePair(file_a=path_a, file_b=path_b, ncd=ncd, size_a=sizes[a], size_b=sizes[b])
+            )
+    return clones
+
+
+def _skip_pair(path_a: str, path_b: str, roles: dict[str, str]) -> bool:
+    """Check if pair should be excluded based on roles."""
+    return (
+        roles.get(path_a, "").upper() in EXCLUDED_ROLES
+        and roles.get(path_b, "").upper() in EXCLUDED_ROLES
+    )
+
+
+def _similar_size(size_a: int, size_b: int) -> bool:
+    """True when the sizes differ by at most 30%."""
+    if size_a <= 0 or size_b <= 0:
+        return True
+    return min(size_a, size_b) / max(size_a, size_b) >= 0.7
+
+
 def compute_clone_ratio(clone_pairs: list[ClonePair], total_files: int) -> float:
     """Compute global clone ratio: files in any clone pair / total files.
 
@@ -0,0 +1,173 @@
+"""File-to-file coupling matrix, structural and temporal, in sparse form.
+
+Every pair of files that is coupled one way or the other is one row:
+
+    structural  whether either file imports the other (imports_ab is
+                file_a importing file_b), from the dependency graph
+    temporal    how the two change together in git history: commits in
+                common, their decayed weight, lift, and the confidence
+                P(file_b changes | file_a changes) each way (see
+                temporal/cochange.py; pairs changed together fewer than
+                twice are not recorded)
+
+``coupling`` folds both into one value for clustering: 1 when the pair is
+structurally coupled, plus the larger of the two confidences, so 0 to 2.
+Pairs lacking both are left out, which is what keeps the matrix sparse.
+
+Hidden pairs are those coupled in time only: they change together but
+neither imports the other, the shared assumption no import shows.
+"""
+
+from __future__ import annotations
+
+import csv
+import json
+from dataclasses import dataclass, field
+from typing import Iterable, Optional, TextIO
+
+COUPLING_FORMATS = ("csv", "json", "mtx")
+
+CSV_COLUMNS = (
+    "file_a",
+    "file_b",
+    "imports_ab",
+    "imports_ba",
+    "cochange_count",
+    "weight",
+    "lift",
+    "confidence_ab",
+    "confidence_ba",
+    "coupling",
+)
+
+
+@dataclass(frozen=True)
+class CouplingPair:
+    """Two coupled files, file_a < file_b."""
+
+    file_a: str
+    file_b: str
+    imports_ab: bool = False
+    imports_ba: bool = False
+    cochange_count: int = 0
+    weight: float = 0.0
+    lift: float = 0.0
+    confidence_ab: float = 0.0
+    confidence_ba: float = 0.0
+
+    @property
+    def structural(self) -> bool:
+        return self.imports_ab or self.imports_ba
+
+    @property
+    def temporal(self) -> float:
+        return max(self.confidence_ab, self.confidence_ba)
+
+    @property
+    def coupling(self) -> float:
+        return float(self.structural) + self.temporal
+
+    def to_dict(self) -> dict:
+        return {
+            **self.__dict__,
+            "weight": round(self.weight, 4),
+            "lift": round(self.lift, 4),
+            "confidence_ab": round(self.confidence_ab, 4),
+            "confidence_ba": round(self.confidence_ba, 4),
+            "coupling": round(self.coupling, 4),
+        }
+
+
+@dataclass
+class CouplingMatrix:
+    """The coupled pairs of *files*; a file's index is its row and column.
+
+    Attributes:
+        files: Every analyzed file, sorted
+        pairs: By file_a, then file_b
+    """
+
+    files: list[str] = field(default_factory=list)
+    pairs: list[CouplingPair] = field(default_factory=list)
+
+    def hidden(self, top: Optional[int] = None) -> list[CouplingPair]:
+        """Pairs without structural dependency, most coupled first."""
+        found = [p for p in self.pairs if not p.structural]
+        found.sort(key=lambda p: (-p.temporal, -p.lift, -p.cochange_count, p.file_a, p.file_b))
+        return found[:top]
+
+    def to_dict(self, pairs: Optional[list[CouplingPair]] = None) -> dict:
+        return {
+            "files": self.files,
+            "pairs": [p.to_dict() for p in (self.pairs if pairs is None else pairs)],
+        }
+
+
+def build_coupling_matrix(
+    files: Iterable[str],
+    dependency_edges: Iterable[tuple],
+    cochange_edges: Iterable[tuple],
+) -> CouplingMatrix:
+    """Join the dependency and co-change edges of a snapshot into pairs.
+
+    Args:
+        files: The analyzed files
+        dependency_edges: (importer, imported)
+        cochange_edges: (file_a, file_b, weight, lift, confidence_ab,
+            confidence_ba, cochange_count), as in TensorSnapshot
+    """
+    known = set(files)
+    rows: dict[tuple[str, str], dict] = {}
+    for src, dst in dependency_edges:
+        if src == dst or src not in known or dst not in known:
+            continue
+        a, b = sorted((src, dst))
+        rows.setdefault((a, b), {})["imports_ab" if src == a else "imports_ba"] = True
+    for a, b, weight, lift, conf_ab, conf_ba, count in cochange_edges:
+        if a not in known or b not in known:
+            continue
+        if a > b:
+            a, b, conf_ab, conf_ba = b, a, conf_ba, conf_ab
+        rows.setdefault((a, b), {}).update(
+            cochange_count=int(count),
+            weight=weight,
+            lift=lift,
+            confidence_ab=conf_ab,
+            confidence_ba=conf_ba,
+        )
+    pairs = [CouplingPair(a, b, **values) for (a, b), values in sorted(rows.items())]
+    return CouplingMatrix(sorted(known), pairs)
+
+
+def write_coupling(
+    matrix: CouplingMatrix,
+    out: TextIO,
+    fmt: str = "csv",
+    pairs: Optional[list[CouplingPair]] = None,
+) -> None:
+    """Write *pairs* (default: all of them) in one of COUPLING_FORMATS.
+
+    ``mtx`` is a Matrix Market coordinate file of the ``coupling`` values,
+    symmetric, 1-indexed by ``matrix.files`` (listed in its comment lines),
+    which scipy.io.mmread reads as a sparse matrix.
+    """
+    if pairs is None:
+        pairs = matrix.pairs
+    if fmt == "json":
+        json.dump(matrix.to_dict(pairs), out, indent=2)
+        out.write("\n")
+    elif fmt == "mtx":
+        index = {path: i for i, path in enumerate(matrix.files, 1)}
+        out.write("%%MatrixMarket matrix coordinate real symmetric\n")
+        for path, i in index.items():
+            out.write(f"% {i} {path}\n")
+        out.write(f"{len(matrix.files)} {len(matrix.files)} {len(pairs)}\n")
+        for p in pairs:
+            # Symmetric: only the lower triangle, row > column
+            out.write(f"{index[p.file_b]} {index[p.file_a]} {p.coupling:.6g}\n")
+    else:
+        writer = csv.writer(out, lineterminator="\n")
+        writer.writerow(CSV_COLUMNS)
+        for p in pairs:
+            values = map(p.to_dict().get, CSV_COLUMNS)
+            writer.writerow([int(v) if isinstance(v, bool) else v for v in values])
@@ -5,7 +5,9 @@ from pathlib import Path
 from typing import Callable, Optional
 
 from ..math.gini import Gini
+from ..math.robust import OUTLIER_Z
 from ..scanning.syntax import FileSyntax
+from ..signals.halstead import halstead
 from .algorithms import (
     compute_centrality_gini,
     compute_dag_depth,
@@ -33,6 +35,7 @@ class AnalysisEngine:
         pagerank_damping: float = 0.85,
         pagerank_iterations: int = 100,
         pagerank_tolerance: float = 1e-6,
+        aliases: Optional[dict[str, str]] = None,
     ):
         """Initialize the analysis engine.
 
@@ -43,6 +46,7 @@ class AnalysisEngine:
             pagerank_damping: Damping factor for PageRank (0.0-1.0)
             pagerank_iterations: Maximum iterations for PageRank convergence
             pagerank_tolerance: Convergence tolerance for PageRank
+            aliases: Unanalyzed path -> analyzed path, for identical copies
         """
         self.file_syntax = file_syntax
         self.root_dir = root_dir
@@ -51,6 +55,7 @@ class AnalysisEngine:
         self._pagerank_damping = pagerank_damping
         self._pagerank_iterations = pagerank_iterations
         self._pagerank_tolerance = pagerank_tolerance
+        self._aliases = aliases or {}
 
     def run(self) -> CodebaseAnalysis:
         """Run the full analysis DAG and return structured results."""
@@ -58,7 +63,7 @@ class AnalysisEngine:
         result.total_files = len(self.file_syntax)
 
         # Phase 2: Build dependency graph from imports
-        graph = build_dependency_graph(self.file_syntax, self.root_dir)
+        graph = build_dependency_graph(self.file_syntax, self.root_dir, self._aliases)
         result.graph = graph
         result.total_edges = graph.edge_count
 
@@ -123,6 +128,14 @@ class AnalysisEngine:
             if fs.function_sizes and len(fs.function_sizes) > 1:
                 fa.function_size_gini = Gini.gini_coefficient(fs.function_sizes)
 
+            # Halstead volume, difficulty and effort from the file's tokens
+            content = self._read_file_content(fs.path)
+            if content:
+                counts = halstead(content, fs.language)
+                fa.halstead_volume = counts.volume
+                fa.halstead_difficulty = counts.difficulty
+                fa.halstead_effort = counts.effort
+
             # Note: compression_ratio and cognitive_load are now computed in SignalFusion
             # to maintain proper layer separation (signal layer, not graph layer)
 
@@ -307,6 +320,7 @@ class AnalysisEngine:
                 lambda f: f.function_size_gini,
             ),
             "blast_radius_size": ("large blast radius", lambda f: float(f.blast_radius_size)),
+            "halstead_effort": ("high Halstead effort", lambda f: f.halstead_effort),
         }
 
         for _metric_name, (description, extractor) in metrics.items():
@@ -320,12 +334,9 @@ class AnalysisEngine:
             if mad == 0:
                 continue
 
-            # Modified z-score threshold. Standard is 3.5 (Iglewicz & Hoaglin),
-            # but we use 5.0 to only flag truly extreme outliers and reduce noise.
-            threshold = 5.0
             for path, val in values:
                 modified_z = 0.6745 * (val - median_val) / mad
-                if modified_z > threshold:
+                if modified_z > OUTLIER_Z:
                     outliers[path].append(
                         f"{description} (value={val:.3f}, "
                         f"median={median_val:.3f}, modified_z={modified_z:.1f})"
@@ -29,6 +29,9 @@ class DependencyGraph:
     # Phase 3: track unresolved imports for phantom_import_count signal
     unresolved_imports: dict[str, list[str]] = field(default_factory=dict)
 
+    # Third-party imports (neither internal nor stdlib), per file
+    external_imports: dict[str, list[str]] = field(default_factory=dict)
+
 
 # ── Level 4: Derived structures ────────────────────────────────────
 
@@ -129,6 +132,9 @@ class FileAnalysis:
     function_size_gini: float = 0.0
     max_function_size: int = 0
     nesting_depth: int = 0
+    halstead_volume: float = 0.0
+    halstead_difficulty: float = 0.0
+    halstead_effort: float = 0.0
 
     # Graph-level measurements for this file (Level 4 → Level 5)
     pagerank: float = 0.0
@@ -0,0 +1,431 @@
+"""Where tagged personal data flows: logs, responses and third-party SDKs.
+
+Fields are tagged as PII in the ``pii_fields`` config table, each with a
+category for the report::
+
+    [pii_fields]
+    "User.email" = "contact"
+    "User.password_hash" = "credential"
+    phone_number = "contact"
+
+``Model.field`` matches the field read from a receiver named after the
+model (user.email, current_user.email, User.email, self.email in the
+model's methods); a bare ``field`` matches it read from anything. Reads are
+attribute access and lookups by name: .email, ->email, ["email"], [:email],
+.get("email"). The capitalized name matches too (Go's user.Email).
+
+Tagged values flow to:
+
+    log          logging and printing (logger.info, logging.warning,
+                 console.log, print, fmt.Printf, Rails.logger)
+    response     response serialization (jsonify, JsonResponse, res.json,
+                 c.JSON, json.NewEncoder(w).Encode, render json:) and values
+                 returned by HTTP handlers (see reachability.py)
+    third_party  calls on a third-party package the file imports
+                 (analytics.track, sentry_sdk.set_user, Stripe::Customer.create);
+                 web frameworks, ORMs, validation and logging libraries run in
+                 process and are not counted
+
+Within a function, a value read from a tagged field taints the names it is
+assigned to, line by line as in taint.py, and a call is reported when any
+argument carries a tagged read or a tainted name. Masking or redacting the
+value (mask_email, redact, anonymize) clears it; hashing does not, a hashed
+email still identifies its owner. Values are not followed into other
+functions, and names are matched, not resolved: the report is a starting
+inventory for a privacy review, not a proof. Test files are not analyzed.
+"""
+
+from __future__ import annotations
+
+import functools
+import re
+from dataclasses import dataclass, field
+from typing import Callable, Optional
+
+from ..scanning.syntax import FileSyntax
+from .reachability import find_entry_points
+from .taint import _ASSIGN_RE, _bodies, _Body, _sink_argument
+
+FLOW_KINDS = ("log", "response", "third_party")
+
+_TAG_RE = re.compile(r"^(?:([A-Za-z_]\w*)\.)?([A-Za-z_]\w*)$")
+
+_SINK_PATTERNS = {
+    "log": re.compile(
+        r"\b(?:log|logger|logging|_log|_logger|LOG|LOGGER|Log|slog|logrus)\."
+        r"(?:debug|info|warn|warning|error|exception|critical|fatal|trace|log|"
+        r"Print\w*|Fatal\w*|Panic\w*|Debug\w*|Info\w*|Warn\w*|Error\w*)\s*\("
+        r"|\bRails\.logger\.\w+\b|\bconsole\.(?:log|info|warn|error|debug|trace)\s*\("
+        r"|(?<![\w.$])(?:print|println|printf|puts|pp|error_log|var_dump|print_r)\b\s*\(?"
+        r"|\bfmt\.(?:Print|Fprint)\w*\s*\(|\bSystem\.(?:out|err)\.print\w*\s*\("
+    ),
+    "response": re.compile(
+        r"(?<![\w.$])(?:jsonify|JsonResponse|JSONResponse|HttpResponse|make_response|Response|"
+        r"render_json)\s*\("
+        r"|\b(?:res|resp|response|reply)(?:\.status\(\s*\d+\s*\))?\.(?:json|jsonp|send)\s*\("
+        r"|\bc\.(?:JSON|IndentedJSON|JSONP)\s*\(|\bjson\.NewEncoder\([^)]*\)\.Encode\s*\("
+        r"|\bResponseEntity\.ok\s*\(|\brender\s+json:"
+    ),
+}
+_SANITIZER_RE = re.compile(
+    r"(?<![\w$])(?:[\w$]+\.)*(?i:mask|redact|anonymi[sz]e|pseudonymi[sz]e|scrub|obfuscate)"
+    r"\w*\s*\(",
+)
+_RETURN_RE = re.compile(r"^\s*return\b(.*)$")
+_IMPORT_LINE_RE = re.compile(r"\b(?:import|require|use|using)\b")
+_BOUND_RES = (
+    re.compile(r"\bimport\s+\(?([\w\s,]+?)\)?\s*(?:from\b|;|$)"),
+    re.compile(r"\bas\s+([\w$]+)"),
+    re.compile(r"\{([^}]*)\}"),
+    re.compile(r"\b(?:const|let|var)\s+([\w$]+)\s*="),
+    re.compile(r"^\s*([\w$]+)\s+\""),  # Go import alias
+)
+_NOT_NAMES = frozenset(
+    {"from", "import", "as", "const", "let", "var", "require", "type", "typeof", "default", "_"}
+)
+
+# Packages that run in process: web frameworks, ORMs, validation, logging, data
+_IN_PROCESS = frozenset(
+    {
+        # Python
+        "django",
+        "flask",
+        "fastapi",
+        "starlette",
+        "werkzeug",
+        "jinja2",
+        "rest_framework",
+        "sqlalchemy",
+        "alembic",
+        "pydantic",
+        "marshmallow",
+        "attrs",
+        "pytest",
+        "numpy",
+        "pandas",
+        "loguru",
+        "structlog",
+        # JavaScript
+        "express",
+        "react",
+        "vue",
+        "next",
+        "sequelize",
+        "mongoose",
+        "typeorm",
+        "prisma",
+        "zod",
+        "joi",
+        "lodash",
+        "winston",
+        "pino",
+        # Go
+        "gin",
+        "echo",
+        "chi",
+        "mux",
+        "gorm",
+        "zap",
+        "logrus",
+        # Ruby, Java, PHP
+        "rails",
+        "activerecord",
+        "sinatra",
+        "springframework",
+        "javax",
+        "jakarta",
+        "lombok",
+        "slf4j",
+        "log4j",
+        "hibernate",
+        "laravel",
+        "illuminate",
+        "symfony",
+        "doctrine",
+        "monolog",
+    }
+)
+
+
+@dataclass(frozen=True)
+class PiiField:
+    """A tagged field: *name* on *model*, or on any model when *model* is None."""
+
+    name: str
+    category: str
+    model: Optional[str] = None
+
+    @property
+    def tag(self) -> str:
+        return f"{self.model}.{self.name}" if self.model else self.name
+
+
+def parse_pii_fields(table: dict) -> list[PiiField]:
+    """Fields of the ``pii_fields`` config table; ValueError when one is malformed."""
+    fields = []
+    for tag, category in table.items():
+        match = _TAG_RE.match(str(tag))
+        if match is None:
+            raise ValueError(f"pii_fields: {tag!r} is not a field or Model.field name")
+        if not isinstance(category, str) or not category.strip():
+            raise ValueError(f"pii_fields: the category of {tag} must be a non-empty string")
+        fields.append(PiiField(match[2], category.strip(), match[1]))
+    return fields
+
+
+@dataclass(frozen=True)
+class PiiFlow:
+    """A tagged value reaching a log, response or third-party call."""
+
+    kind: str  # one of FLOW_KINDS
+    path: str
+    function: str
+    line: int
+    tag: str  # User.email
+    category: str
+    read: str  # where the value was read: current_user.email
+    call: str  # logger.info(, return, analytics.track(
+
+    def to_dict(self) -> dict:
+        return dict(self.__dict__)
+
+
+@dataclass
+class PiiReport:
+    """Where tagged fields flow, and which models declare them.
+
+    Attributes:
+        fields: The tagged fields, as configured
+        flows: By path, then line
+        models: ``Model.field`` tag -> files defining the model class; empty for
+            a model not found (a stale or misspelled tag)
+        functions: Number of functions analyzed
+    """
+
+    fields: list[PiiField] = field(default_factory=list)
+    flows: list[PiiFlow] = field(default_factory=list)
+    models: dict[str, list[str]] = field(default_factory=dict)
+    functions: int = 0
+
+    def by_kind(self) -> dict[str, int]:
+        counts = dict.fromkeys(FLOW_KINDS, 0)
+        for flow in self.flows:
+            counts[flow.kind] += 1
+        return counts
+
+    def by_tag(self) -> dict[str, int]:
+        counts = {f.tag: 0 for f in self.fields}
+        for flow in self.flows:
+            counts[flow.tag] = counts.get(flow.tag, 0) + 1
+        return counts
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "functions": self.functions,
+            "fields": [
+                {
+                    "tag": f.tag,
+                    "category": f.category,
+                    "models": self.models.get(f.tag),
+                    "flows": self.by_tag()[f.tag],
+                }
+                for f in self.fields
+            ],
+            "by_kind": self.by_kind(),
+            "flows": [flow.to_dict() for flow in self.flows[:top]],
+        }
+
+
+def find_pii_flows(
+    syntax: dict[str, FileSyntax],
+    contents: dict[str, str],
+    fields: list[PiiField],
+    external: dict[str, list[str]],
+) -> PiiReport:
+    """Calls that tagged values reach, within each function.
+
+    Args:
+        syntax: path -> FileSyntax of every parsed file
+        contents: path -> source text
+        fields: The tagged fields (see parse_pii_fields)
+        external: path -> third-party imports (the dependency graph's
+            external_imports)
+    """
+    report = PiiReport(fields=list(fields))
+    classes = {(path, cls.name) for path, fs in syntax.items() for cls in fs.classes}
+    for f in fields:
+        if f.model is not None:
+            report.models[f.tag] = sorted(p for p, name in classes if name == f.model)
+    if not fields:
+        return report
+
+    reads = [(f, _read_re(f.name)) for f in fields]
+    owners = {
+        id(method): cls.name
+        for fs in syntax.values()
+        for cls in fs.classes
+        for method in cls.methods
+    }
+    defines: dict[str, set[str]] = {}
+    for path, name in classes:
+        defines.setdefault(path, set()).add(name)
+    handlers = {entry.symbol.id for entry in find_entry_points(syntax) if entry.kind == "http"}
+    bodies = _bodies(syntax, contents)
+    sdk_calls: dict[str, Optional[re.Pattern]] = {}
+    found: dict[tuple, PiiFlow] = {}
+    for body in bodies.values():
+        if body.path not in sdk_calls:
+            lines = contents.get(body.path, "").splitlines()
+            sdk_calls[body.path] = _sdk_call_re(external.get(body.path, []), lines)
+        accepts = functools.partial(
+            _accepts, owner=owners.get(id(body.fn)), defines=defines.get(body.path, set())
+        )
+        for flow in _scan(body, reads, accepts, sdk_calls[body.path], body.id in handlers):
+            found.setdefault((flow.path, flow.line, flow.tag, flow.kind), flow)
+
+    report.flows = sorted(found.values(), key=lambda f: (f.path, f.line, f.kind, f.tag))
+    report.functions = len(bodies)
+    return report
+
+
+def _accepts(f: PiiField, receiver: str, owner: Optional[str], defines: set[str]) -> bool:
+    """Whether a read from *receiver* is a read of *f*.
+
+    *owner* is the class of the method reading it, *defines* the classes of its file.
+    """
+    if f.model is None:
+        return True
+    name = receiver.lstrip("@$").lower().replace("_", "")
+    if name in ("self", "this"):
+        return owner == f.model or (owner is None and f.model in defines)
+    return f.model.lower() in name
+
+
+def _read_re(name: str) -> re.Pattern:
+    """Reads of field *name*; the receiver is the first group that matched."""
+    names = "|".join(re.escape(n) for n in sorted({name, name[:1].upper() + name[1:]}))
+    receiver = r"(?<![\w$@])([@$]?[\w$]+)(?:\(\))?(?:\[[^\]\n]*\])?"
+    return re.compile(
+        receiver + r"(?:\.|->|\?\.)(?:" + names + r")(?![\w$(])"
+        r"|" + receiver + r"\[\s*(?:[\"'](?:" + names + r")[\"']|:(?:" + names + r"))\s*\]"
+        r"|" + receiver + r"\.get\(\s*[\"'](?:" + names + r")[\"']"
+    )
+
+
+def _scan(
+    body: _Body,
+    reads: list[tuple[PiiField, re.Pattern]],
+    accepts: Callable[[PiiField, str], bool],
+    sdk_call: Optional[re.Pattern],
+    handler: bool,
+) -> list[PiiFlow]:
+    """Follow tagged reads through *body*, line by line."""
+    tainted: dict[str, tuple[PiiField, str]] = {}
+    flows = []
+    patterns = list(_SINK_PATTERNS.items())
+    if sdk_call is not None:
+        patterns.append(("third_party", sdk_call))
+    text = "\n".join(body.lines)
+    offset = 0
+    for index, line in enumerate(body.lines):
+        number = body.fn.start_line + index
+        assignment = _ASSIGN_RE.match(line)
+        if assignment and index > 0:
+            targets, operator, value = assignment.groups()
+            carried = _carrier(value, reads, accepts, tainted)
+            for name in (t.strip() for t in targets.split(",")):
+                if carried is not None:
+                    tainted[name] = carried
+                elif operator != "+=":
+                    tainted.pop(name, None)
+
+        spans: list[tuple[int, int]] = []
+        for kind, pattern in patterns:
+            for match in pattern.finditer(line):
+                if any(start < match.end() and match.start() < end for start, end in spans):
+                    continue  # a logging or response call on an imported package
+                spans.append(match.span())
+                argument = _sink_argument(text, offset + match.end(), every=True)
+                carried = _carrier(argument, reads, accepts, tainted)
+                if carried is not None:
+                    flows.append(_flow(body, number, kind, match.group(0).strip(), carried))
+
+        returned = _RETURN_RE.match(line) if handler else None
+        if returned is not None:
+            carried = _carrier(returned.group(1), reads, accepts, tainted)
+            if carried is not None:
+                flows.append(_flow(body, number, "response", "return", carried))
+        offset += len(line) + 1
+    return flows
+
+
+def _flow(body: _Body, line: int, kind: str, call: str, carried: tuple[PiiField, str]) -> PiiFlow:
+    f, read = carried
+    return PiiFlow(kind, body.path, body.fn.name, line, f.tag, f.category, read, call)
+
+
+def _carrier(
+    text: str,
+    reads: list[tuple[PiiField, re.Pattern]],
+    accepts: Callable[[PiiField, str], bool],
+    tainted: dict[str, tuple[PiiField, str]],
+) -> Optional[tuple[PiiField, str]]:
+    """The tagged field whose value *text* carries, and where it was read."""
+    if not text.strip() or _SANITIZER_RE.search(text):
+        return None
+    first: Optional[tuple[int, PiiField, str]] = None
+    for f, pattern in reads:
+        for match in pattern.finditer(text):
+            receiver = next(group for group in match.groups() if group)
+            if accepts(f, receiver):
+                if first is None or match.start() < first[0]:
+                    first = (match.start(), f, match.group(0))
+                break
+    if first is not None:
+        return first[1], first[2]
+    for name, carried in tainted.items():
+        if re.search(r"(?<![\w.$])" + re.escape(name) + r"(?![\w$])", text):
+            return carried
+    return None
+
+
+def _sdk_call_re(imports: list[str], lines: list[str]) -> Optional[re.Pattern]:
+    """Calls on the third-party packages among *imports*, by the names the file binds."""
+    names: set[str] = set()
+    for imported in imports:
+        package = imported.strip().strip("\"'")
+        segments = [s.lower() for s in re.split(r"[/.@]", package) if s]
+        if not segments or any(s in _IN_PROCESS for s in segments):
+            continue
+        names.update(_package_names(package))
+        for line in lines:
+            if package in line and _IMPORT_LINE_RE.search(line):
+                names.update(_bound_names(line))
+    names = {n for n in names if re.fullmatch(r"[A-Za-z_$][\w$]*", n)} - _NOT_NAMES
+    if not names:
+        return None
+    alternatives = "|".join(re.escape(n) for n in sorted(names, key=lambda n: (-len(n), n)))
+    return re.compile(r"(?<![\w.$])(?:" + alternatives + r")(?:(?:\.|::)[\w$]+)*\s*\(")
+
+
+def _package_names(package: str) -> set[str]:
+    """Names a package is usually bound to: sentry_sdk, stripe (stripe-go), Stripe."""
+    segments = [s for s in package.lstrip("@").split("/") if s and not re.fullmatch(r"v\d+", s)]
+    last = re.sub(r"^go-|-go$|\.js$", "", segments[-1]) if segments else package
+    first_word = re.split(r"[-.]", last)[0]
+    names = {last.replace("-", "_"), first_word, first_word[:1].upper() + first_word[1:]}
+    if "/" not in package:
+        names.update({package.split(".")[0], package.split(".")[-1]})
+    return names
+
+
+def _bound_names(line: str) -> set[str]:
+    """Names an import line binds: default, named and aliased imports."""
+    names: set[str] = set()
+    for pattern in _BOUND_RES:
+        for match in pattern.finditer(line):
+            for part in match.group(1).split(","):
+                words = re.findall(r"[\w$]+", part)
+                if words:
+                    names.add(words[-1])  # "x as y", "x: y" bind y
+    return names
@@ -0,0 +1,257 @@
+"""Entry points and the code reachable from each: the attack surface.
+
+An entry point is a function that control reaches from outside the code:
+
+    main      ``main`` functions (Go, Rust, C, Java, Python scripts)
+    http      route handlers (@app.get, @router.route, @GetMapping),
+              Rails controller actions and Go ``ServeHTTP`` methods
+    consumer  message and task consumers (@app.task, @shared_task,
+              @KafkaListener, @subscriber)
+    cli       command handlers (@click.command, @app.command, @cli.group)
+
+Decorators are matched by their last segment, so ``@router.post`` and
+``@app.post`` are both HTTP handlers. Test files declare no entry points.
+
+Reachability follows the call graph (see symbols.py) from each entry point.
+Functions no entry point reaches are reported as unreachable: dead code,
+or code only called dynamically (callbacks, reflection, framework hooks),
+which a call graph cannot see. Dunder methods are never reported. Calls
+are extracted by tree-sitter only; without a call graph, or without any
+entry point (a library), no unreachable code is reported.
+"""
+
+from __future__ import annotations
+
+from collections import deque
+from dataclasses import dataclass, field
+from typing import Optional
+
+from ..scanning.rails import ACTION_DECORATOR
+from ..scanning.syntax import FileSyntax, FunctionDef
+from ..semantics.roles import TEST_PATH_PATTERNS
+from .symbols import Symbol, call_graph, has_call_targets
+
+ENTRY_KINDS = ("main", "http", "consumer", "cli")
+
+# Last decorator segment -> entry kind
+_DECORATOR_KINDS = {
+    **dict.fromkeys(
+        (
+            "route",
+            "get",
+            "post",
+            "put",
+            "patch",
+            "delete",
+            "head",
+            "options",
+            "websocket",
+            "api_view",
+            "RequestMapping",
+            "GetMapping",
+            "PostMapping",
+            "PutMapping",
+            "PatchMapping",
+            "DeleteMapping",
+            ACTION_DECORATOR,
+        ),
+        "http",
+    ),
+    **dict.fromkeys(
+        (
+            "task",
+            "shared_task",
+            "periodic_task",
+            "actor",
+            "consumer",
+            "subscriber",
+            "KafkaListener",
+            "RabbitListener",
+            "SqsListener",
+            "JmsListener",
+            "StreamListener",
+        ),
+        "consumer",
+    ),
+    **dict.fromkeys(("command", "group"), "cli"),
+}
+
+# Function name -> entry kind, for languages without decorators
+_NAME_KINDS = {"main": "main", "ServeHTTP": "http"}
+
+
+@dataclass
+class EntryPoint:
+    """A function control reaches from outside, and what makes it one."""
+
+    symbol: Symbol
+    kind: str  # one of ENTRY_KINDS
+    trigger: str  # the decorator or name that marks it
+
+
+@dataclass
+class EntryReach:
+    """The code one entry point reaches, and what it adds up to."""
+
+    entry: EntryPoint
+    reachable: list[str]  # function symbol ids, the entry point first
+    files: int
+    complexity: int  # sum over reachable functions
+    max_complexity: int
+    risk: float  # sum of the reached files' risk scores
+
+
+@dataclass
+class SurfaceReport:
+    """Entry points of one codebase and what they reach.
+
+    Attributes:
+        entries: Entry points, most complexity reached first
+        unreachable: Functions no entry point reaches, by path and line
+        complexity: Symbol id -> cyclomatic complexity (functions only)
+        call_graph_available: False when no file was parsed with call targets
+    """
+
+    entries: list[EntryReach] = field(default_factory=list)
+    unreachable: list[Symbol] = field(default_factory=list)
+    complexity: dict[str, int] = field(default_factory=dict)
+    call_graph_available: bool = True
+
+    def by_kind(self) -> dict[str, int]:
+        """Number of entry points of each kind."""
+        counts = dict.fromkeys(ENTRY_KINDS, 0)
+        for reach in self.entries:
+            counts[reach.entry.kind] += 1
+        return counts
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "call_graph_available": self.call_graph_available,
+            "entry_points": [
+                {
+                    "path": r.entry.symbol.path,
+                    "name": r.entry.symbol.name,
+                    "line": r.entry.symbol.line,
+                    "kind": r.entry.kind,
+                    "trigger": r.entry.trigger,
+                    "reachable_functions": len(r.reachable),
+                    "files": r.files,
+                    "complexity": r.complexity,
+                    "max_complexity": r.max_complexity,
+                    "risk": round(r.risk, 4),
+                }
+                for r in self.entries[:top]
+            ],
+            "unreachable": [
+                {
+                    "path": s.path,
+                    "name": s.name,
+                    "line": s.line,
+                    "complexity": self.complexity.get(s.id),
+                }
+                for s in self.unreachable[:top]
+            ],
+            "unreachable_count": len(self.unreachable),
+        }
+
+
+def find_entry_points(syntax: dict[str, FileSyntax]) -> list[EntryPoint]:
+    """Entry points of every non-test file, by path and line."""
+    entries: dict[str, EntryPoint] = {}
+    for path, fs in syntax.items():
+        if _is_test_path(path):
+            continue
+        for fn in _functions(fs):
+            found = _entry_kind(fn)
+            symbol = Symbol(path, fn.name, "function", fn.start_line)
+            if found is not None and symbol.id not in entries:
+                entries[symbol.id] = EntryPoint(symbol, *found)
+    return sorted(entries.values(), key=lambda e: (e.symbol.path, e.symbol.line))
+
+
+def build_surface(
+    syntax: dict[str, FileSyntax],
+    imports: dict[str, list[str]],
+    complexity: Optional[dict[str, int]] = None,
+    file_risk: Optional[dict[str, float]] = None,
+) -> SurfaceReport:
+    """Entry points, the code each reaches, and the code none reaches.
+
+    Args:
+        syntax: path -> FileSyntax of every parsed file
+        imports: path -> paths it imports (the dependency graph adjacency)
+        complexity: symbol id (``path:name``) -> cyclomatic complexity; see
+            centrality.symbol_complexity
+        file_risk: path -> risk score, for per-entry-point risk totals
+    """
+    complexity = dict(complexity or {})
+    file_risk = file_risk or {}
+    symbols, edges = call_graph(syntax, imports)
+
+    entries = []
+    reached: set[str] = set()
+    for entry in find_entry_points(syntax):
+        reachable = [symbols.get(i, entry.symbol) for i in _reachable(entry.symbol.id, edges)]
+        reached.update(s.id for s in reachable)
+        functions = [s.id for s in reachable if s.kind == "function"]
+        paths = {s.path for s in reachable}
+        entries.append(
+            EntryReach(
+                entry=entry,
+                reachable=functions,
+                files=len(paths),
+                complexity=sum(complexity.get(i, 0) for i in functions),
+                max_complexity=max((complexity.get(i, 0) for i in functions), default=0),
+                risk=sum(file_risk.get(p, 0.0) for p in paths),
+            )
+        )
+    entries.sort(key=lambda r: (-r.complexity, -len(r.reachable), r.entry.symbol.id))
+
+    available = has_call_targets(syntax)
+    unreachable = []
+    if available and entries:
+        unreachable = [
+            s
+            for s in symbols.values()
+            if s.kind == "function"
+            and s.id not in reached
+            and not _is_test_path(s.path)
+            and not (s.name.startswith("__") and s.name.endswith("__"))
+        ]
+        unreachable.sort(key=lambda s: (s.path, s.line, s.name))
+    return SurfaceReport(entries, unreachable, complexity, available)
+
+
+def _entry_kind(fn: FunctionDef) -> tuple[str, str] | None:
+    """(kind, trigger) if *fn* is an entry point."""
+    for decorator in fn.decorators:
+        kind = _DECORATOR_KINDS.get(decorator.split(".")[-1])
+        if kind is not None:
+            return kind, f"@{decorator}"
+    if fn.name in _NAME_KINDS:
+        return _NAME_KINDS[fn.name], fn.name
+    return None
+
+
+def _functions(fs: FileSyntax) -> list[FunctionDef]:
+    """Top-level functions and class methods, each once."""
+    methods = [m for cls in fs.classes for m in cls.methods if m not in fs.functions]
+    return fs.functions + methods
+
+
+def _reachable(start: str, edges: dict[str, list[str]]) -> list[str]:
+    """Symbol ids reachable from *start* (itself first), breadth first."""
+    seen = {start}
+    order = [start]
+    queue = deque([start])
+    while queue:
+        for callee in edges.get(queue.popleft(), []):
+            if callee not in seen:
+                seen.add(callee)
+                order.append(callee)
+                queue.append(callee)
+    return order
+
+
+def _is_test_path(path: str) -> bool:
+    return any(pattern.search(path.lower()) for pattern in TEST_PATH_PATTERNS)
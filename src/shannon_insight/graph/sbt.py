@@ -0,0 +1,131 @@
+"""sbt builds: the sub-projects of a build.sbt and how they depend on each other.
+
+A multi-project build declares each sub-project as a val:
+
+    lazy val core = project
+    lazy val api = (project in file("modules/api")).dependsOn(core % "test->test")
+    lazy val root = project.in(file(".")).aggregate(core, api)
+
+The directory is the ``file(...)`` argument, or the val's name without one.
+``dependsOn`` names the projects whose classes a project may import;
+``aggregate`` only runs tasks across projects, so it is not a dependency.
+Cross-built projects (``crossProject(JVMPlatform, JSPlatform)``) are one
+project covering their platform directories, and ``core.jvm`` aliases
+depend on it.
+
+build.sbt is Scala, so this reads the common forms rather than evaluating
+the build. Sub-projects give the architecture its modules and decide which
+of several same-named classes an import means (see graph/builder.py).
+"""
+
+from __future__ import annotations
+
+import logging
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Union
+
+logger = logging.getLogger(__name__)
+
+_COMMENT_RE = re.compile(r"/\*.*?\*/|//[^\n]*", re.S)
+_DEFINITION_RE = re.compile(r"^[ \t]*(?:lazy\s+)?val\s+(\w+|`[^`]+`)\s*=\s*", re.M)
+_PROJECT_START_RE = re.compile(
+    r"\(?\s*(?:project\b|Project\s*\(|(?:cross|js|native)Project\b|projectMatrix\b)"
+)
+_IN_FILE_RE = re.compile(r"\bin\s*\(?\s*file\s*\(\s*\"([^\"]*)\"\s*\)")
+_PROJECT_CALL_RE = re.compile(
+    r"\bProject\s*\(\s*(?:id\s*=\s*)?\"[^\"]*\"\s*,\s*(?:base\s*=\s*)?file\s*\(\s*\"([^\"]*)\"",
+)
+_DEPENDS_ON_RE = re.compile(r"\.dependsOn\s*\(((?:[^()]|\([^()]*\))*)\)")
+_ALIAS_RE = re.compile(r"^(\w+)\s*\.\s*(?:jvm|js|native)\b")
+
+
+@dataclass
+class SbtProject:
+    """One sbt sub-project.
+
+    Attributes:
+        name: The val it is declared as (what dependsOn refers to)
+        directory: Base directory relative to the build root, "." for the root
+        depends_on: Names of the projects it depends on directly
+    """
+
+    name: str
+    directory: str
+    depends_on: list[str] = field(default_factory=list)
+
+
+def discover_sbt_projects(root: Union[str, Path]) -> list[SbtProject]:
+    """Sub-projects declared in *root*/build.sbt; empty without one."""
+    build = Path(root) / "build.sbt"
+    try:
+        text = build.read_text(encoding="utf-8", errors="replace")
+    except OSError:
+        return []
+    projects = parse_build_sbt(text)
+    logger.debug(f"Found {len(projects)} sbt projects in {build}")
+    return projects
+
+
+def parse_build_sbt(text: str) -> list[SbtProject]:
+    """Sub-projects declared in the text of a build.sbt."""
+    text = _COMMENT_RE.sub("", text)
+    definitions = list(_DEFINITION_RE.finditer(text))
+    projects: list[SbtProject] = []
+    aliases: dict[str, str] = {}  # coreJVM -> core
+    for i, match in enumerate(definitions):
+        name = match.group(1).strip("`")
+        end = definitions[i + 1].start() if i + 1 < len(definitions) else len(text)
+        body = text[match.end() : end]
+        alias = _ALIAS_RE.match(body)
+        if alias:
+            aliases[name] = alias.group(1)
+            continue
+        if not _PROJECT_START_RE.match(body):
+            continue
+        location = _PROJECT_CALL_RE.search(body) or _IN_FILE_RE.search(body)
+        depends_on = []
+        for args in _DEPENDS_ON_RE.findall(body):
+            for arg in args.split(","):
+                dependency = re.match(r"\s*(?:LocalProject\s*\(\s*\")?(\w+)", arg)
+                if dependency:
+                    depends_on.append(dependency.group(1))
+        projects.append(
+            SbtProject(
+                name=name,
+                directory=_normalize_directory(location.group(1) if location else name),
+                depends_on=depends_on,
+            )
+        )
+    known = {p.name for p in projects}
+    for project in projects:
+        resolved = [aliases.get(d, d) for d in project.depends_on]
+        project.depends_on = list(dict.fromkeys(d for d in resolved if d in known))
+    return projects
+
+
+def project_of(path: str, projects: list[SbtProject]) -> SbtProject | None:
+    """The project whose directory holds *path* (the deepest one), if any."""
+    matches = [p for p in projects if p.directory == "." or path.startswith(p.directory + "/")]
+    return max(matches, key=lambda p: -1 if p.directory == "." else len(p.directory), default=None)
+
+
+def dependency_closure(name: str, projects: list[SbtProject]) -> set[str]:
+    """Names of the projects *name* depends on, directly or transitively."""
+    by_name = {p.name: p for p in projects}
+    seen: set[str] = set()
+    stack = list(by_name[name].depends_on) if name in by_name else []
+    while stack:
+        current = stack.pop()
+        if current not in seen and current != name:
+            seen.add(current)
+            stack.extend(by_name[current].depends_on if current in by_name else [])
+    return seen
+
+
+def _normalize_directory(directory: str) -> str:
+    directory = directory.strip().strip("/")
+    while directory.startswith("./"):
+        directory = directory[2:]
+    return directory or "."
@@ -0,0 +1,496 @@
+"""Candidate injection paths: untrusted input reaching a sensitive call.
+
+A lightweight, syntactic taint analysis. Inputs (sources) are:
+
+    request   request parameters, bodies, headers and cookies
+              (request.args, req.query, params[:id], r.FormValue,
+              $_GET, getParameter) and the parameters of HTTP handlers
+              (see reachability.py)
+    env       environment variables (os.environ, process.env, getenv)
+
+Sensitive calls (sinks) are:
+
+    sql       query execution (cursor.execute, db.Query, executeQuery,
+              find_by_sql, mysqli_query)
+    exec      command and code execution (os.system, subprocess,
+              exec.Command, child_process, eval)
+    path      file access by path (open, os.Open, fs.readFile,
+              send_file, new File)
+    template  rendering of strings as templates or markup
+              (render_template_string, Markup, template.HTML, innerHTML)
+
+Within a function, a value is tainted when it is read from a source or
+assigned from a tainted value, line by line. A sink is fed when its first
+argument (the query, command, path or template; later arguments are bind
+parameters, options or template context) mentions a source or a tainted
+name; a plain string literal there (a constant query) is never reported.
+Passing a value through a sanitizer (shlex.quote, escape, secure_filename,
+int(...)) clears it.
+
+Tainted arguments are followed into the called function's parameters, by
+position or keyword, over the call graph (see symbols.py) for at most
+MAX_CALL_DEPTH calls; calls into libraries are not followed. Each path
+gets a confidence:
+
+    high    the value is followed to the sink within one function or
+            through one call
+    medium  the value is followed through two or more calls
+    low     the function reading the input has the sink, or calls a
+            function whose parameters feed one, but the input is not
+            seen reaching it
+
+Environment variables are rarely attacker-controlled, so paths from env
+sources are one level less confident. Names are matched, not resolved, so
+every path is a candidate to review, not a proven vulnerability. Test files
+are not analyzed. Calls between functions are extracted by tree-sitter
+only; without it, paths within a function are still found.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from typing import Optional
+
+from ..scanning.syntax import FileSyntax, FunctionDef
+from ..semantics.roles import TEST_PATH_PATTERNS
+from .reachability import find_entry_points
+from .symbols import call_graph, has_call_targets
+
+SOURCE_KINDS = ("request", "env")
+SINK_KINDS = ("sql", "exec", "path", "template")
+CONFIDENCE_LEVELS = ("high", "medium", "low")
+
+# Calls followed from the function reading the input
+MAX_CALL_DEPTH = 4
+
+_SOURCE_PATTERNS = {
+    "request": re.compile(
+        r"\brequest\.(?:args|form|values|json|data|files|cookies|headers|GET|POST|"
+        r"query_params|body|params|query|path_params)\b"
+        r"|\breq\.(?:query|body|params|cookies|headers)\b"
+        r"|(?<![\w.])params\["
+        r"|\.(?:FormValue|PostFormValue|URL\.Query)\("
+        r"|\$_(?:GET|POST|REQUEST|COOKIE)\b"
+        r"|\.get(?:Parameter|Header|QueryString)\("
+    ),
+    "env": re.compile(
+        r"\bos\.(?:environ|getenv|Getenv|LookupEnv)\b|\bprocess\.env\b"
+        r"|\bSystem\.getenv\(|(?<![\w.])ENV\[|(?<![\w.])getenv\(|\benv::var\("
+    ),
+}
+_SINK_PATTERNS = {
+    "sql": re.compile(
+        r"(?<!\bURL)\.(?:execute|executemany|executescript|raw|Query|QueryRow|QueryContext|Exec|"
+        r"ExecContext|executeQuery|executeUpdate|prepareStatement|find_by_sql|query)\s*\("
+        r"|\b(?:mysqli_query|mysql_query|pg_query)\s*\("
+    ),
+    "exec": re.compile(
+        r"\bos\.(?:system|popen|exec\w*|spawn\w*)\s*\(|\bsubprocess\.\w+\s*\("
+        r"|\bexec\.Command(?:Context)?\s*\(|\.getRuntime\(\)\.exec\s*\("
+        r"|\bchild_process\.\w+\s*\(|(?<![\w.])(?:execSync|spawnSync|execFile)\s*\("
+        r"|(?<![\w.])(?:eval|exec|system|shell_exec|passthru|proc_open)\s*\("
+        r"|\b(?:Kernel\.system|IO\.popen)\s*\("
+    ),
+    "path": re.compile(
+        r"(?<![\w.])open\s*\(|\bos\.(?:Open|OpenFile|ReadFile|WriteFile|Create|Remove)\s*\("
+        r"|\bfs\.(?:readFile|writeFile|createReadStream|createWriteStream|unlink)\w*\s*\("
+        r"|(?<![\w.])(?:send_file|send_from_directory|FileResponse|file_get_contents|fopen)\s*\("
+        r"|\bnew\s+File(?:InputStream|OutputStream|Reader|Writer)?\s*\("
+        r"|\bFile\.(?:read|open|write)\s*\(|\bhttp\.ServeFile\s*\(|\.sendFile\s*\("
+    ),
+    "template": re.compile(
+        r"(?<![\w.])(?:render_template_string|Template|Markup|mark_safe)\s*\("
+        r"|\.from_string\s*\(|\btemplate\.(?:HTML|JS)\s*\("
+        r"|\.(?:innerHTML|outerHTML)\s*\+?=|\bdangerouslySetInnerHTML\s*="
+    ),
+}
+_SANITIZER_RE = re.compile(
+    r"(?<![\w])(?:shlex\.quote|escape\w*|html\.escape|quote|secure_filename|bleach\.clean|"
+    r"sanitize\w*|filepath\.(?:Base|Clean)|(?:os\.)?path\.basename|int|float|bool|"
+    r"uuid\.UUID|parseInt|parseFloat|Number|strconv\.Atoi|htmlspecialchars|"
+    r"escapeshellarg|Integer\.parseInt)\s*\("
+)
+_ASSIGN_RE = re.compile(
+    r"^\s*(?:(?:let|const|var|val|my|auto)\s+)?"
+    r"((?:[\w$.]+\s*,\s*)*[\w$.]+)\s*(?::\s*[\w\[\]<>., |]+?)?\s*(\+=|:=|=)(?!=)\s*(.*)$"
+)
+# A string literal without interpolation (f-strings and template literals interpolate)
+_CONSTANT_RE = re.compile(r"""\s*[rbuRBU]?(?:"[^"{]*"|'[^'{]*')\s*""")
+_RECEIVER_RE = re.compile(r"[\w$]+(?:\(\))?(?:\.[\w$]+(?:\(\))?)*$")
+_COMMENT_LINE_RE = re.compile(r"^\s*(?:#|//|/\*|\*|--)")
+
+# Handler parameters that carry the request object or framework plumbing, not input
+_NOT_INPUT_PARAMS = frozenset(
+    {"self", "cls", "this", "request", "req", "res", "resp", "response", "w", "r", "ctx", "c"}
+    | {"next", "db", "session", "background_tasks"}
+)
+
+
+@dataclass(frozen=True)
+class TaintSource:
+    """Where untrusted input enters."""
+
+    kind: str  # one of SOURCE_KINDS
+    path: str
+    function: str
+    line: int
+    expression: str  # request.args, process.env, "parameter user_id"
+
+
+@dataclass(frozen=True)
+class TaintSink:
+    """A sensitive call."""
+
+    kind: str  # one of SINK_KINDS
+    path: str
+    function: str
+    line: int
+    call: str  # the matched call: cursor.execute(
+
+
+@dataclass
+class TaintPath:
+    """Input from *source* possibly reaching *sink* through the functions in *via*."""
+
+    source: TaintSource
+    sink: TaintSink
+    via: list[str]  # function symbol ids, the source's function first
+    confidence: str  # one of CONFIDENCE_LEVELS
+
+    @property
+    def calls(self) -> int:
+        return len(self.via) - 1
+
+    def to_dict(self) -> dict:
+        return {
+            "confidence": self.confidence,
+            "source": self.source.__dict__,
+            "sink": self.sink.__dict__,
+            "via": list(self.via),
+        }
+
+
+@dataclass
+class TaintReport:
+    """Candidate injection paths of one codebase.
+
+    Attributes:
+        paths: Most confident first, then by source location
+        functions: Number of functions analyzed
+        call_graph_available: False when no file was parsed with call targets
+    """
+
+    paths: list[TaintPath] = field(default_factory=list)
+    functions: int = 0
+    call_graph_available: bool = True
+
+    def by_confidence(self) -> dict[str, int]:
+        counts = dict.fromkeys(CONFIDENCE_LEVELS, 0)
+        for path in self.paths:
+            counts[path.confidence] += 1
+        return counts
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "call_graph_available": self.call_graph_available,
+            "functions": self.functions,
+            "by_confidence": self.by_confidence(),
+            "paths": [p.to_dict() for p in self.paths[:top]],
+        }
+
+
+@dataclass
+class _Body:
+    """A function's source text, full-line comments blanked."""
+
+    id: str
+    path: str
+    fn: FunctionDef
+    lines: list[str]
+
+    @property
+    def params(self) -> list[str]:
+        params = list(self.fn.params)
+        if params and params[0] in ("self", "cls", "this"):
+            params = params[1:]
+        return params
+
+
+@dataclass
+class _Scan:
+    """What one pass over a function body found."""
+
+    sinks: list[tuple[TaintSink, Optional[TaintSource]]] = field(default_factory=list)
+    # (callee id, callee parameter -> source) for calls passing tainted arguments
+    calls: list[tuple[str, dict[str, TaintSource]]] = field(default_factory=list)
+    reads: Optional[TaintSource] = None  # first input read anywhere in the body
+
+
+def find_taint_paths(
+    syntax: dict[str, FileSyntax],
+    contents: dict[str, str],
+    imports: dict[str, list[str]],
+) -> TaintReport:
+    """Candidate paths from inputs to sensitive calls.
+
+    Args:
+        syntax: path -> FileSyntax of every parsed file
+        contents: path -> source text
+        imports: path -> paths it imports (the dependency graph adjacency)
+    """
+    _, edges = call_graph(syntax, imports)
+    bodies = _bodies(syntax, contents)
+    handlers = {entry.symbol.id for entry in find_entry_points(syntax) if entry.kind == "http"}
+
+    found: dict[tuple, TaintPath] = {}
+    param_fed: dict[str, Optional[TaintSink]] = {}
+    for body in bodies.values():
+        tainted: dict[str, TaintSource] = {}
+        if body.id in handlers:
+            tainted = {
+                name: TaintSource(
+                    "request", body.path, body.fn.name, body.fn.start_line, f"parameter {name}"
+                )
+                for name in body.params
+                if name not in _NOT_INPUT_PARAMS
+            }
+        callees = [c for c in edges.get(body.id, []) if c in bodies]
+        scan = _scan(body, tainted, [bodies[c] for c in callees])
+        source = scan.reads or next(iter(tainted.values()), None)
+        if source is None:
+            continue
+
+        for sink, carrier in scan.sinks:
+            _add(found, carrier or source, sink, [body.id], visible=carrier is not None)
+
+        passed = {callee for callee, _ in scan.calls}
+        for callee, params in scan.calls:
+            _follow(bodies, edges, bodies[callee], params, [body.id, callee], found)
+        for callee in callees:
+            if callee in passed:
+                continue
+            sink = _param_fed_sink(bodies[callee], param_fed)
+            if sink is not None:
+                _add(found, source, sink, [body.id, callee], visible=False)
+
+    rank = {level: i for i, level in enumerate(CONFIDENCE_LEVELS)}
+    paths = sorted(
+        found.values(),
+        key=lambda p: (
+            rank[p.confidence],
+            p.source.path,
+            p.source.line,
+            p.sink.path,
+            p.sink.line,
+        ),
+    )
+    return TaintReport(paths, len(bodies), has_call_targets(syntax))
+
+
+def _bodies(syntax: dict[str, FileSyntax], contents: dict[str, str]) -> dict[str, _Body]:
+    """Bodies of the functions and methods of non-test files, by symbol id."""
+    bodies: dict[str, _Body] = {}
+    for path, fs in sorted(syntax.items()):
+        if any(pattern.search(path.lower()) for pattern in TEST_PATH_PATTERNS):
+            continue
+        lines = contents.get(path, "").splitlines()
+        methods = [m for cls in fs.classes for m in cls.methods if m not in fs.functions]
+        for fn in fs.functions + methods:
+            symbol_id = f"{path}:{fn.name}"
+            if symbol_id in bodies or not 1 <= fn.start_line <= fn.end_line <= len(lines):
+                continue
+            text = [
+                "" if _COMMENT_LINE_RE.match(line) else line
+                for line in lines[fn.start_line - 1 : fn.end_line]
+            ]
+            bodies[symbol_id] = _Body(symbol_id, path, fn, text)
+    return bodies
+
+
+def _scan(body: _Body, tainted: dict[str, TaintSource], callees: list[_Body]) -> _Scan:
+    """Follow *tainted* names through *body*, line by line."""
+    tainted = dict(tainted)
+    result = _Scan()
+    text = "\n".join(body.lines)
+    offset = 0
+    for index, line in enumerate(body.lines):
+        number = body.fn.start_line + index
+        if result.reads is None:
+            result.reads = _read_source(line, body, number)
+
+        assignment = _ASSIGN_RE.match(line)
+        if assignment and index > 0:
+            targets, operator, value = assignment.groups()
+            carrier = _carrier(value, tainted, body, number)
+            for name in (t.strip() for t in targets.split(",")):
+                if carrier is not None:
+                    tainted[name] = carrier
+                elif operator != "+=":
+                    tainted.pop(name, None)
+
+        for kind, pattern in _SINK_PATTERNS.items():
+            for match in pattern.finditer(line):
+                receiver = _RECEIVER_RE.search(line, 0, match.start())
+                call = (receiver.group(0) if receiver else "") + match.group(0).strip()
+                sink = TaintSink(kind, body.path, body.fn.name, number, call)
+                # Every argument of a command can inject; otherwise the first is the payload
+                argument = _sink_argument(text, offset + match.end(), every=kind == "exec")
+                if not argument.strip() or _CONSTANT_RE.fullmatch(argument):
+                    continue  # a literal query or command: nothing to inject into
+                result.sinks.append((sink, _carrier(argument, tainted, body, number)))
+
+        for callee in callees:
+            params = _tainted_params(text, offset, line, callee, tainted, body, number)
+            if params:
+                result.calls.append((callee.id, params))
+        offset += len(line) + 1
+    return result
+
+
+def _follow(
+    bodies: dict[str, _Body],
+    edges: dict[str, list[str]],
+    body: _Body,
+    params: dict[str, TaintSource],
+    via: list[str],
+    found: dict[tuple, TaintPath],
+) -> None:
+    """Paths from tainted *params* of *body*, reached through the calls in *via*."""
+    callees = [c for c in edges.get(body.id, []) if c in bodies and c not in via]
+    scan = _scan(body, params, [bodies[c] for c in callees])
+    for sink, carrier in scan.sinks:
+        if carrier is not None:
+            _add(found, carrier, sink, via, visible=True)
+    if len(via) > MAX_CALL_DEPTH:
+        return
+    for callee, callee_params in scan.calls:
+        _follow(bodies, edges, bodies[callee], callee_params, via + [callee], found)
+
+
+def _param_fed_sink(body: _Body, cache: dict[str, Optional[TaintSink]]) -> Optional[TaintSink]:
+    """A sink of *body* fed by its own parameters, if any."""
+    if body.id not in cache:
+        placeholder = TaintSource("request", body.path, body.fn.name, body.fn.start_line, "")
+        scan = _scan(body, dict.fromkeys(body.params, placeholder), [])
+        cache[body.id] = next((sink for sink, carrier in scan.sinks if carrier), None)
+    return cache[body.id]
+
+
+def _add(
+    found: dict[tuple, TaintPath],
+    source: TaintSource,
+    sink: TaintSink,
+    via: list[str],
+    visible: bool,
+) -> None:
+    """Record a path, keeping the most confident (then shortest) per source and sink."""
+    level = 2 if not visible else (0 if len(via) <= 2 else 1)
+    if source.kind == "env":
+        level = min(level + 1, 2)
+    path = TaintPath(source, sink, list(via), CONFIDENCE_LEVELS[level])
+    key = (source.path, source.line, source.expression, sink.path, sink.line, sink.call)
+    current = found.get(key)
+    if current is None or (level, len(via)) < (
+        CONFIDENCE_LEVELS.index(current.confidence),
+        len(current.via),
+    ):
+        found[key] = path
+
+
+def _read_source(text: str, body: _Body, line: int) -> Optional[TaintSource]:
+    for kind, pattern in _SOURCE_PATTERNS.items():
+        match = pattern.search(text)
+        if match:
+            expression = match.group(0).rstrip("([")
+            receiver = _RECEIVER_RE.search(text, 0, match.start())
+            if expression.startswith(".") and receiver:
+                expression = receiver.group(0) + expression
+            return TaintSource(kind, body.path, body.fn.name, line, expression)
+    return None
+
+
+def _carrier(
+    text: str, tainted: dict[str, TaintSource], body: _Body, line: int
+) -> Optional[TaintSource]:
+    """The source whose value *text* carries, unless it is sanitized."""
+    if not text.strip() or _SANITIZER_RE.search(text):
+        return None
+    source = _read_source(text, body, line)
+    if source is not None:
+        return source
+    for name, origin in tainted.items():
+        if re.search(r"(?<![\w.$])" + re.escape(name) + r"(?![\w$])", text):
+            return origin
+    return None
+
+
+def _sink_argument(text: str, start: int, every: bool = False) -> str:
+    """The first (or *every*) argument of the call opened just before *start*.
+
+    Assignments (``el.innerHTML = ...``) have no call: the rest of the line.
+    """
+    if text[start - 1 : start] != "(":
+        end = text.find("\n", start)
+        return text[start : end if end != -1 else len(text)]
+    arguments = _arguments(text, start)
+    if every:
+        # Literal arguments can't carry input; only the others are checked
+        return ", ".join(a for a in arguments if not _CONSTANT_RE.fullmatch(a))
+    return arguments[0] if arguments else ""
+
+
+def _tainted_params(
+    text: str,
+    offset: int,
+    line: str,
+    callee: _Body,
+    tainted: dict[str, TaintSource],
+    body: _Body,
+    number: int,
+) -> dict[str, TaintSource]:
+    """Parameters of *callee* given a tainted argument by calls on *line*."""
+    params = callee.params
+    passed: dict[str, TaintSource] = {}
+    call_re = re.compile(r"(?<![\w$])" + re.escape(callee.fn.name) + r"\s*\(")
+    for match in call_re.finditer(line):
+        for position, argument in enumerate(_arguments(text, offset + match.end())):
+            keyword = re.match(r"\s*(\w+)\s*=(?!=)(.*)", argument, re.S)
+            if keyword and keyword.group(1) in params:
+                name, argument = keyword.group(1), keyword.group(2)
+            elif position < len(params):
+                name = params[position]
+            else:
+                continue
+            carrier = _carrier(argument, tainted, body, number)
+            if carrier is not None:
+                passed.setdefault(name, carrier)
+    return passed
+
+
+def _arguments(text: str, start: int) -> list[str]:
+    """Top-level arguments of the call whose ``(`` ends just before *start*."""
+    arguments = []
+    depth = 0
+    quote = ""
+    current = start
+    for i in range(start, len(text)):
+        char = text[i]
+        if quote:
+            if char == "\\":
+                continue
+            if char == quote and text[i - 1] != "\\":
+                quote = ""
+        elif char in "\"'`":
+            quote = char
+        elif char in "([{":
+            depth += 1
+        elif char in ")]}":
+            if depth == 0:
+                arguments.append(text[current:i])
+                break
+            depth -= 1
+        elif char == "," and depth == 0:
+            arguments.append(text[current:i])
+            current = i + 1
+    return [a for a in arguments if a.strip()]
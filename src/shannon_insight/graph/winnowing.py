@@ -0,0 +1,124 @@
+"""Winnowing fingerprints for memory-bounded clone detection.
+
+Schleimer, Wilkerson & Aiken, "Winnowing: Local Algorithms for Document
+Fingerprinting" (SIGMOD 2003) -- the algorithm behind MOSS.
+
+A file is tokenized, every run of k consecutive tokens (a k-gram) is hashed,
+and from each window of w = t - k + 1 consecutive hashes the minimum is kept.
+Two parameters control sensitivity:
+
+    noise threshold k      matches shorter than k tokens are never reported
+    guarantee threshold t  any match of at least t tokens shares at least
+                           one fingerprint, so it is always found
+
+Roughly 2 / (w + 1) of the k-gram hashes survive, so the fingerprint index
+grows linearly with code size and never holds file contents.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import re
+import struct
+import zlib
+from itertools import chain, compress, repeat
+from operator import le
+from typing import Sequence
+
+try:
+    import xxhash
+except ImportError:  # Declared dependency; still works (slower) without it
+    xxhash = None
+
+# Defaults: shared runs of 40+ tokens (a few lines of code) are guaranteed to
+# be found; runs under 8 tokens (single statements) are ignored as noise
+DEFAULT_GUARANTEE_TOKENS = 40
+DEFAULT_NOISE_TOKENS = 8
+
+_TOKEN_RE = re.compile(rb"\w+|[^\w\s]")
+
+# Padding for block minima beyond either end of the sequence
+_EDGE = (float("-inf"),) * 2
+
+
+# The three stages below are the hot loop of clone detection and run once
+# per file, so they iterate in C (map, zip, compress) instead of Python
+# bytecode: no per-token Python frames and, since zip reuses its result
+# tuple, no per-k-gram allocation.
+
+
+def tokenize(content: bytes) -> list[int]:
+    """Content as a list of token hashes; whitespace and layout are ignored."""
+    return list(map(zlib.crc32, _TOKEN_RE.findall(content)))
+
+
+def _blake2b_64(data: bytes) -> int:
+    return int.from_bytes(hashlib.blake2b(data, digest_size=8).digest(), "little")
+
+
+# 64-bit k-gram digest: XXH3, or BLAKE2b without xxhash (as in portable.py)
+_digest_64 = xxhash.xxh3_64_intdigest if xxhash is not None else _blake2b_64
+
+
+def kgram_hashes(tokens: Sequence[int], k: int) -> list[int]:
+    """Hash of every k-gram of *tokens* (unsigned 32-bit values).
+
+    Each k-gram is hashed as its tokens packed little-endian, so the hashes
+    are the same in every process, Python version and platform.
+    """
+    n = len(tokens)
+    if n < k:
+        return []
+    packed = struct.pack(f"<{n}I", *tokens)
+    kgrams = map(slice, range(0, 4 * (n - k + 1), 4), range(4 * k, 4 * n + 1, 4))
+    return list(map(_digest_64, map(packed.__getitem__, kgrams)))
+
+
+def winnow(hashes: Sequence[int], window: int) -> set[int]:
+    """Minimum hash of every *window* consecutive hashes.
+
+    Every window contains a whole aligned block of (window + 1) // 2 hashes,
+    so its minimum is at most the largest of the nearby block minima. Only
+    the few hashes under that bound are candidates; a candidate is selected
+    when the run of candidates not smaller than it spans a full window.
+    """
+    n = len(hashes)
+    if n == 0:
+        return set()
+    if n <= window:
+        return {min(hashes)}
+
+    size = (window + 1) // 2
+    # A window containing a hash of block j fully covers one of blocks j-2..j+2
+    mins = [*_EDGE, *map(min, (hashes[s : s + size] for s in range(0, n, size))), *_EDGE]
+    bounds = map(max, mins, mins[1:], mins[2:], mins[3:], mins[4:])
+    per_hash = chain.from_iterable(map(repeat, bounds, repeat(size)))
+    positions = list(compress(range(n), map(le, hashes, per_hash)))
+    values = list(map(hashes.__getitem__, positions))
+
+    # Nearest strictly smaller candidate on each side
+    left = [-1] * len(values)
+    right = [n] * len(values)
+    stack: list[int] = []
+    for i, value in enumerate(values):
+        while stack and values[stack[-1]] > value:
+            right[stack.pop()] = positions[i]
+        stack.append(i)
+    stack.clear()
+    for i in range(len(values) - 1, -1, -1):
+        while stack and values[stack[-1]] > values[i]:
+            left[stack.pop()] = positions[i]
+        stack.append(i)
+    return {v for v, lo, hi in zip(values, left, right) if hi - lo - 1 >= window}
+
+
+def fingerprints(
+    content: bytes,
+    guarantee_tokens: int = DEFAULT_GUARANTEE_TOKENS,
+    noise_tokens: int = DEFAULT_NOISE_TOKENS,
+) -> set[int]:
+    """Winnowed fingerprint set of *content*."""
+    if noise_tokens < 1 or guarantee_tokens < noise_tokens:
+        raise ValueError("need 1 <= noise_tokens <= guarantee_tokens")
+    window = guarantee_tokens - noise_tokens + 1
+    return winnow(kgram_hashes(tokenize(content), noise_tokens), window)
@@ -0,0 +1,20 @@
+"""Code hygiene reports: repo-wide consistency checks over source text.
+
+Unlike finders, which read fused signals from the FactStore, hygiene reports
+work directly on parsed sources (FileSyntax + content) and are exposed as
+``shannon-insight hygiene <report>`` subcommands.
+
+Usage:
+    from shannon_insight.hygiene import load_sources
+    from shannon_insight.hygiene.idioms import analyze_idioms
+
+    sources = load_sources("/path/to/repo")
+    report = analyze_idioms(sources)
+"""
+
+from .sources import SourceSet, load_sources
+
+__all__ = [
+    "SourceSet",
+    "load_sources",
+]
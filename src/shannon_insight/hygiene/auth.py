@@ -0,0 +1,428 @@
+"""Review rules for JWT and other token-based auth code.
+
+Complexity metrics see nothing wrong with a ten-line token check that
+accepts tokens which never expire. These rules look for the mistakes auth
+code keeps making:
+
+    default_secret            a signing secret with a literal fallback
+                              (``getenv("JWT_SECRET", "dev-secret")``,
+                              ``Field(default="...")``, ``|| 'secret'``),
+                              assigned a literal, or a literal passed as
+                              the key to sign or verify
+    expiry_not_verified       token verification with expiry checks turned
+                              off (``verify_exp: False``,
+                              ``ignoreExpiration: true``,
+                              ``jwt.WithoutClaimsValidation()``), or an
+                              expiry only checked when the token has one
+                              (``claims.ExpiresAt != nil && ...``)
+    token_without_expiry      a token issued with no expiry claim
+    unvalidated_claim         token verification that never checks the
+                              audience (``aud``) or issuer (``iss``)
+    refresh_accepts_expired   a refresh function that turns expiry checks
+                              off or carries on after an expired-token error
+
+Tokens are recognised by the JWT libraries of Python (PyJWT,
+python-jose), JavaScript/TypeScript (jsonwebtoken, jose), Go (golang-jwt)
+and Java (jjwt, java-jwt). A rule looks at the function around the call,
+so options built a few lines earlier count. Test files and comment lines
+are skipped. Issues are reported as ``auth_flow_issue`` findings, one per
+file, rule and subject. When expiry checks are turned off by a flag
+(``verify_exp``, ``ignoreExpiration``, ``SkipClaimsValidation``) the
+finding carries the flag's line with the check turned back on.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Optional
+
+from ..semantics.roles import TEST_PATH_PATTERNS
+from ..signals.complexity import is_comment_line
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+
+AUTH_ISSUE_TYPE = "auth_flow_issue"
+
+AUTH_RULES = (
+    "default_secret",
+    "expiry_not_verified",
+    "token_without_expiry",
+    "unvalidated_claim",
+    "refresh_accepts_expired",
+)
+
+_SEVERITY = {
+    "default_secret": 0.6,
+    "expiry_not_verified": 0.6,
+    "token_without_expiry": 0.5,
+    "unvalidated_claim": 0.3,
+    "refresh_accepts_expired": 0.7,
+}
+
+# Lines a multi-line call or assignment is followed over
+_STATEMENT_LOOKAHEAD = 12
+# Lines after catching an expired-token error searched for a rejection
+_HANDLER_LOOKAHEAD = 3
+
+
+@dataclass(frozen=True)
+class AuthIssue:
+    path: str
+    line: int
+    rule: str  # one of AUTH_RULES
+    subject: str  # the secret's name, a claim (exp, aud, iss) or the refresh function
+    detail: str
+    fix_line: int = 0  # the line the fix replaces, when the fix is mechanical
+    fix: str = ""
+
+    @property
+    def severity(self) -> float:
+        return _SEVERITY[self.rule]
+
+
+@dataclass
+class AuthReport:
+    issues: list[AuthIssue]  # worst first
+    issue_sites: int  # calls issuing tokens
+    verify_sites: int  # calls verifying tokens
+
+    def counts(self) -> dict[str, int]:
+        """Issue count per rule, in AUTH_RULES order."""
+        return {
+            rule: n for rule in AUTH_RULES if (n := sum(i.rule == rule for i in self.issues))
+        }
+
+    def to_dict(self) -> dict:
+        return {
+            "group": "security.auth",
+            "issue_sites": self.issue_sites,
+            "verify_sites": self.verify_sites,
+            "counts": self.counts(),
+            "issues": [i.__dict__ for i in self.issues],
+        }
+
+
+def analyze_auth(files: dict[str, FileSyntax], contents: dict[str, str]) -> AuthReport:
+    """Apply the auth review rules to every non-test file."""
+    issues: list[AuthIssue] = []
+    issue_sites = verify_sites = 0
+    for path in sorted(contents):
+        if any(pattern.search(path.lower()) for pattern in TEST_PATH_PATTERNS):
+            continue
+        file = _AuthFile(path, contents[path], files.get(path))
+        issues.extend(file.default_secrets())
+        issues.extend(file.token_issues())
+        issue_sites += len(file.issue_lines)
+        verify_sites += len(file.verify_lines)
+    issues.sort(key=lambda i: (-i.severity, i.path, i.line, i.rule))
+    return AuthReport(issues, issue_sites, verify_sites)
+
+
+def to_findings(issues: list[AuthIssue]) -> list:
+    """Convert issues to ``auth_flow_issue`` findings, one per file/rule/subject."""
+    from ..insights.models import Evidence, Finding, Patch
+
+    grouped: dict[tuple[str, str, str], list[AuthIssue]] = {}
+    for issue in issues:
+        grouped.setdefault((issue.path, issue.rule, issue.subject), []).append(issue)
+
+    findings = []
+    for (path, rule, subject), group in grouped.items():
+        lines = ", ".join(str(i.line) for i in group)
+        # Calls in one function share the flag that turns their checks off
+        fixes = {i.fix_line: i.fix for i in group if i.fix}
+        findings.append(
+            Finding(
+                finding_type=AUTH_ISSUE_TYPE,
+                severity=group[0].severity,
+                title=f"{group[0].detail} in {path}",
+                files=[path],
+                evidence=[
+                    Evidence(signal="rule", value=0.0, percentile=0.0, description=rule),
+                    Evidence(
+                        signal="occurrences",
+                        value=float(len(group)),
+                        percentile=0.0,
+                        description=f"line {lines}" if len(group) == 1 else f"lines {lines}",
+                    ),
+                ],
+                suggestion=_SUGGESTIONS[rule],
+                effort="LOW" if rule == "unvalidated_claim" else "MEDIUM",
+                identity_hint=f"{rule}:{subject}",
+                patches=[Patch(path, n, n, fix) for n, fix in sorted(fixes.items())],
+            )
+        )
+    return findings
+
+
+_SUGGESTIONS = {
+    "default_secret": (
+        "Read the secret from the environment or a secret store and fail at startup "
+        "when it is missing"
+    ),
+    "expiry_not_verified": "Verify the exp claim, and reject tokens that do not have one",
+    "token_without_expiry": "Give every token an exp claim",
+    "unvalidated_claim": "Pass the expected audience and issuer to the verification call",
+    "refresh_accepts_expired": (
+        "Refresh with a separate long-lived refresh token, never an expired access token"
+    ),
+}
+
+
+# ── Detection ─────────────────────────────────────────────────────
+
+_VERIFY_RE = re.compile(
+    r"\bjwt\.(?:decode|verify|Parse|ParseWithClaims)\(|\.ParseWithClaims\(|\bjwtVerify\("
+    r"|\bJwts\.parser(?:Builder)?\(|\bJWT\.require\("
+)
+_ISSUE_RE = re.compile(
+    r"\bjwt\.(?:encode|sign|New|NewWithClaims)\(|\bnew\s+SignJWT\(|\bJwts\.builder\("
+    r"|\bJWT\.create\("
+)
+_EXPIRY_RE = re.compile(
+    r"['\"]exp['\"]|\bexp\s*[:=]|expiresIn|ExpiresAt|setExpiration|withExpiresAt", re.I
+)
+_EXPIRY_DISABLED_RE = re.compile(
+    r"['\"]verify_exp['\"]\s*:\s*False|\bignoreExpiration\s*:\s*true"
+    r"|\bWithoutClaimsValidation\(|\bSkipClaimsValidation\s*:\s*true"
+)
+# Flags turning expiry checks off, and the replacement turning them back on
+_EXPIRY_FLAGS = (
+    (re.compile(r"(['\"]verify_exp['\"]\s*:\s*)False\b"), r"\1True"),
+    (re.compile(r"(\bignoreExpiration\s*:\s*)true\b"), r"\1false"),
+    (re.compile(r"(\bSkipClaimsValidation\s*:\s*)true\b"), r"\1false"),
+)
+_EXPIRY_OPTIONAL_RE = re.compile(
+    r"\bExpiresAt\s*!=\s*nil\s*&&|['\"]exp['\"]\s+in\s+\w+\s+and\b"
+    r"|\.get\(\s*['\"]exp['\"]\s*\)\s+and\b"
+)
+_CLAIM_CHECKS = {
+    "aud": ("audience", re.compile(r"audience|\baud\b", re.I)),
+    "iss": ("issuer", re.compile(r"issuer|\biss\b", re.I)),
+}
+_REFRESH_RE = re.compile(r"refresh", re.I)
+_EXPIRED_ERROR_RE = re.compile(
+    r"ExpiredSignatureError|TokenExpiredError|ErrTokenExpired|ExpiredJwtException"
+    r"|TokenExpiredException"
+)
+_REJECTS_RE = re.compile(
+    r"\b(?:raise|throw|abort)\b|\breturn\b.*(?:err|Error|40[13]|Unauthorized|None|null|nil)"
+    r"|respond\w*\(|Unauthorized|\b40[13]\b"
+)
+
+_SECRET_NAME_RE = re.compile(r"secret|signing_?key|jwt_?key|private_?key|api_?key", re.I)
+# Names holding something about a secret rather than the secret itself
+_NOT_SECRET_RE = re.compile(
+    r"(?:name|env|var|path|file|header|field|len|length|_id|url|type|prefix|ttl)s?$", re.I
+)
+_LITERAL = r"(?P<q>['\"`])(?P<value>[^'\"`\n]+)(?P=q)"
+_ASSIGN_RE = re.compile(
+    r"^\s*(?:(?:const|let|var|final|static|private|public|protected|readonly)\s+)*"
+    r"(?:[\w.]+\.)?(?P<name>\w+)\s*(?::\s*[\w\[\], |.]+?)?\s*"
+    r"(?::=|(?<![=!<>])=(?!=))\s*(?P<rhs>.*)"
+)
+_KEY_VALUE_RE = re.compile(r"['\"]?(?P<name>\w+)['\"]?\s*:\s*" + _LITERAL)
+_ENV_FALLBACK_RE = re.compile(
+    r"\b(?:getenv|getEnv|Getenv|environ\.get|env\.get|config\.get)\(\s*"
+    r"['\"](?P<name>[^'\"]+)['\"]\s*,\s*" + _LITERAL
+)
+_JS_ENV_FALLBACK_RE = re.compile(
+    r"\bprocess\.env\.(?P<name>\w+)\s*(?:\|\||\?\?)\s*" + _LITERAL
+)
+_DEFAULTS = (
+    re.compile(r"^\s*" + _LITERAL),
+    re.compile(r"\bdefault\s*=\s*" + _LITERAL),
+    re.compile(r"(?:\bor\b|\|\||\?\?)\s*" + _LITERAL),
+)
+_LITERAL_KEYS = (
+    re.compile(r"\bjwt\.(?:encode|decode|sign|verify)\(\s*[\w.\[\]\"']+\s*,\s*" + _LITERAL),
+    re.compile(r"\bSignedString\(\s*\[\]byte\(\s*" + _LITERAL),
+)
+
+
+def _is_secret_name(name: str) -> bool:
+    return bool(_SECRET_NAME_RE.search(name)) and not _NOT_SECRET_RE.search(name)
+
+
+def _is_real_value(value: str) -> bool:
+    """False for values that name a secret rather than hold one: env var names, templates."""
+    return not (value.startswith(("$", "{", "<")) or re.fullmatch(r"[A-Z][A-Z0-9_]+", value))
+
+
+class _AuthFile:
+    """One file's lines, with the function around each line and its token calls."""
+
+    def __init__(self, path: str, content: str, syntax: Optional[FileSyntax]):
+        self.path = path
+        self.lines = content.splitlines()
+        self.functions = [
+            fn
+            for fn in (_functions(syntax) if syntax is not None else [])
+            if 1 <= fn.start_line <= fn.end_line <= len(self.lines)
+        ]
+        self.verify_lines = self._matching(_VERIFY_RE)
+        self.issue_lines = self._matching(_ISSUE_RE)
+
+    def _matching(self, pattern: re.Pattern[str]) -> list[int]:
+        return [
+            n
+            for n, line in enumerate(self.lines, 1)
+            if not is_comment_line(line) and pattern.search(line)
+        ]
+
+    def _issue(
+        self, line: int, rule: str, subject: str, detail: str, fix: tuple[int, str] = (0, "")
+    ) -> AuthIssue:
+        return AuthIssue(self.path, line, rule, subject, detail, *fix)
+
+    def _scope(self, line: int) -> tuple[Optional[FunctionDef], str]:
+        """The innermost function around *line* and its text (the whole file outside one)."""
+        around = [f for f in self.functions if f.start_line <= line <= f.end_line]
+        if not around:
+            return None, "\n".join(self.lines)
+        fn = min(around, key=lambda f: f.end_line - f.start_line)
+        return fn, "\n".join(self.lines[fn.start_line - 1 : fn.end_line])
+
+    def _statement(self, line: int, start: str) -> str:
+        """*start* and the lines after it until its brackets balance."""
+        text = start
+        n = line
+        while _depth(text) > 0 and n < min(len(self.lines), line + _STATEMENT_LOOKAHEAD):
+            text += "\n" + self.lines[n]
+            n += 1
+        return text
+
+    def default_secrets(self) -> list[AuthIssue]:
+        issues = []
+        for n, line in enumerate(self.lines, 1):
+            if is_comment_line(line):
+                continue
+            found = self._secret_on_line(n, line)
+            if found is not None:
+                issues.append(self._issue(n, "default_secret", *found))
+        return issues
+
+    def _secret_on_line(self, n: int, line: str) -> Optional[tuple[str, str]]:
+        """(secret name, detail) when *line* gives a secret a literal value."""
+        for pattern in (_ENV_FALLBACK_RE, _JS_ENV_FALLBACK_RE):
+            for match in pattern.finditer(line):
+                if _is_secret_name(match["name"]) and _is_real_value(match["value"]):
+                    return match["name"], f"{match['name']} falls back to a literal secret"
+        for pattern in _LITERAL_KEYS:
+            match = pattern.search(line)
+            if match is not None and _is_real_value(match["value"]):
+                return "signing key", "Tokens are signed or verified with a literal key"
+        match = _ASSIGN_RE.match(line)
+        if match is not None and _is_secret_name(match["name"]):
+            statement = self._statement(n, match["rhs"])
+            for pattern in _DEFAULTS:
+                default = pattern.search(statement)
+                if default is not None and _is_real_value(default["value"]):
+                    return match["name"], f"{match['name']} defaults to a literal secret"
+        for match in _KEY_VALUE_RE.finditer(line):
+            if _is_secret_name(match["name"]) and _is_real_value(match["value"]):
+                return match["name"], f"{match['name']} is set to a literal secret"
+        return None
+
+    def token_issues(self) -> list[AuthIssue]:
+        issues = []
+        for n in self.issue_lines:
+            fn, scope = self._scope(n)
+            if not _EXPIRY_RE.search(scope):
+                where = f" in {fn.name}" if fn is not None else ""
+                detail = f"Token issued{where} never expires"
+                issues.append(self._issue(n, "token_without_expiry", "exp", detail))
+        for n in self.verify_lines:
+            fn, scope = self._scope(n)
+            refresh = fn is not None and _is_refresh(fn)
+            if _EXPIRY_DISABLED_RE.search(scope):
+                fix = self._expiry_fix(fn)
+                if refresh:
+                    issues.append(self._refresh_issue(n, fn, "turns expiry checks off", fix))
+                else:
+                    issues.append(
+                        self._issue(
+                            n, "expiry_not_verified", "exp", "Token expiry is not verified", fix
+                        )
+                    )
+            elif refresh and self._accepts_expired_error(fn):
+                issues.append(self._refresh_issue(n, fn, "carries on after an expired token"))
+            for claim, (label, pattern) in _CLAIM_CHECKS.items():
+                if not pattern.search(scope):
+                    issues.append(
+                        self._issue(
+                            n,
+                            "unvalidated_claim",
+                            claim,
+                            f"Token {label} ({claim}) is never validated",
+                        )
+                    )
+        if self.verify_lines:
+            for n, line in enumerate(self.lines, 1):
+                if not is_comment_line(line) and _EXPIRY_OPTIONAL_RE.search(line):
+                    issues.append(
+                        self._issue(
+                            n,
+                            "expiry_not_verified",
+                            "exp",
+                            "Expiry is only checked when the token has one",
+                        )
+                    )
+        return issues
+
+    def _refresh_issue(
+        self, line: int, fn: FunctionDef, what: str, fix: tuple[int, str] = (0, "")
+    ) -> AuthIssue:
+        return self._issue(
+            line, "refresh_accepts_expired", fn.name, f"Token refresh {fn.name} {what}", fix
+        )
+
+    def _expiry_fix(self, fn: Optional[FunctionDef]) -> tuple[int, str]:
+        """(line, fixed line) for the first flag in *fn* turning expiry checks off.
+
+        (0, "") when there is none, as with ``jwt.WithoutClaimsValidation()``,
+        whose fix is dropping the option rather than rewriting a line.
+        """
+        first, last = (fn.start_line, fn.end_line) if fn is not None else (1, len(self.lines))
+        for n in range(first, last + 1):
+            line = self.lines[n - 1]
+            if is_comment_line(line):
+                continue
+            for pattern, replacement in _EXPIRY_FLAGS:
+                if pattern.search(line):
+                    return n, pattern.sub(replacement, line, count=1)
+        return 0, ""
+
+    def _accepts_expired_error(self, fn: FunctionDef) -> bool:
+        """An expired-token error is caught in *fn* and not answered with a rejection."""
+        for n in range(fn.start_line, fn.end_line + 1):
+            line = self.lines[n - 1]
+            if not _EXPIRED_ERROR_RE.search(line) or is_comment_line(line):
+                continue
+            if re.search(r"!\s*errors\.Is\(", line):
+                return True  # every error but an expired token is rejected
+            handler = [
+                text
+                for text in self.lines[n : min(fn.end_line, n + _HANDLER_LOOKAHEAD)]
+                if text.strip()
+            ]
+            if not _REJECTS_RE.search(line) and not any(_REJECTS_RE.search(t) for t in handler):
+                return True
+        return False
+
+
+def _is_refresh(fn: FunctionDef) -> bool:
+    return bool(_REFRESH_RE.search(fn.name) or any(_REFRESH_RE.search(d) for d in fn.decorators))
+
+
+def _functions(syntax: FileSyntax) -> list[FunctionDef]:
+    methods = [m for cls in syntax.classes for m in cls.methods if m not in syntax.functions]
+    return syntax.functions + methods
+
+
+def _depth(text: str) -> int:
+    """Open brackets in *text* not closed yet (string contents ignored)."""
+    text = re.sub(r"'[^'\n]*'|\"[^\"\n]*\"|`[^`]*`", "", text)
+    return sum(text.count(c) for c in "([{") - sum(text.count(c) for c in ")]}")
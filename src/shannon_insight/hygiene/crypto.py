@@ -0,0 +1,593 @@
+"""Inventory of cryptographic API usage, checked against a policy.
+
+Usages are recognised line by line (comment lines skipped) per category:
+
+    hash     hashlib.md5, md5.Sum, MessageDigest.getInstance("SHA-1"),
+             createHash('sha256'), Digest::MD5
+    cipher   AES.new(..., AES.MODE_ECB), algorithms.TripleDES, aes.NewCipher,
+             Cipher.getInstance("AES/CBC/..."), createCipheriv('aes-256-cbc')
+    key      RSA/DSA/EC key generation, with the key size when one is given
+             (key_size=, modulusLength:, rsa.GenerateKey(r, bits), initialize)
+    jwt      signing algorithms: "HS256" next to a jwt/algorithm mention,
+             jwt.SigningMethodHS256, SignatureAlgorithm.RS256; disabled
+             signature verification
+    tls      protocol versions (ssl.PROTOCOL_TLSv1, tls.VersionTLS10,
+             SSLContext.getInstance("TLSv1")) and disabled certificate
+             verification (verify=False, InsecureSkipVerify: true)
+
+Crypto libraries are read from each file's imports (hashlib, cryptography,
+PyJWT, golang-jwt, jsonwebtoken, javax.crypto, ...).
+
+Algorithms are normalized (``SHA-1`` -> ``sha1``, ``DESede`` -> ``3des``,
+``TLSv1`` -> ``tls1.0``); disabled verification is ``no-verify`` and
+unverified JWTs ``none``. The policy (``crypto_policy`` config table) has:
+
+    banned_algorithms   algorithms and cipher modes never allowed
+    min_key_bits        minimum key size per algorithm (rsa, dsa, dh, ec)
+    approved_libraries  when non-empty, the only crypto libraries allowed
+
+Keys given in the config table replace the defaults, except
+``min_key_bits``, which is merged per algorithm. Hashes called with
+``usedforsecurity=False`` are inventoried but never violate the policy.
+Violations are reported as ``crypto_policy_violation`` findings, one per
+file, rule and algorithm.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Any, Callable, Optional
+
+from ..signals.complexity import is_comment_line
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+CRYPTO_VIOLATION_TYPE = "crypto_policy_violation"
+
+CRYPTO_RULES = ("banned_algorithm", "weak_key", "unapproved_library")
+
+CRYPTO_CATEGORIES = ("hash", "cipher", "key", "jwt", "tls", "library")
+
+DEFAULT_BANNED_ALGORITHMS = (
+    "md4",
+    "md5",
+    "sha1",
+    "des",
+    "3des",
+    "rc2",
+    "rc4",
+    "blowfish",
+    "ecb",
+    "none",
+    "no-verify",
+    "sslv2",
+    "sslv3",
+    "tls1.0",
+    "tls1.1",
+)
+DEFAULT_MIN_KEY_BITS = {"rsa": 2048, "dsa": 2048, "dh": 2048, "ec": 224}
+
+_POLICY_KEYS = ("banned_algorithms", "min_key_bits", "approved_libraries")
+
+# Severity per rule; disabled verification and unsigned tokens are worst
+_SEVERITY = {"banned_algorithm": 0.6, "weak_key": 0.6, "unapproved_library": 0.3}
+_CRITICAL_ALGORITHMS = {"none": 0.8, "no-verify": 0.7}
+
+# Import prefix -> library name
+_LIBRARIES = {
+    "hashlib": "hashlib",
+    "hmac": "hmac",
+    "secrets": "secrets",
+    "ssl": "ssl",
+    "cryptography": "cryptography",
+    "Crypto": "pycryptodome",
+    "Cryptodome": "pycryptodome",
+    "nacl": "pynacl",
+    "jwt": "pyjwt",
+    "jose": "python-jose",
+    "passlib": "passlib",
+    "bcrypt": "bcrypt",
+    "argon2": "argon2",
+    "OpenSSL": "pyopenssl",
+    "crypto": "node:crypto",
+    "node:crypto": "node:crypto",
+    "jsonwebtoken": "jsonwebtoken",
+    "bcryptjs": "bcryptjs",
+    "crypto-js": "crypto-js",
+    "node-forge": "node-forge",
+    "tweetnacl": "tweetnacl",
+    "golang.org/x/crypto": "golang.org/x/crypto",
+    "github.com/golang-jwt/jwt": "golang-jwt",
+    "github.com/dgrijalva/jwt-go": "jwt-go",
+    "javax.crypto": "javax.crypto",
+    "java.security": "java.security",
+    "javax.net.ssl": "javax.net.ssl",
+    "io.jsonwebtoken": "jjwt",
+    "com.auth0.jwt": "java-jwt",
+    "org.bouncycastle": "bouncycastle",
+    "openssl": "openssl",
+    "digest": "digest",
+}
+# Go's standard library crypto packages are libraries of their own: crypto/md5
+_GO_CRYPTO_RE = re.compile(r"^crypto/\w+$")
+
+_JWT_CONTEXT_RE = re.compile(r"jwt|\balg", re.I)
+_KEY_BITS_RE = re.compile(
+    r"(?:key_size\s*=|modulusLength\s*:|\.initialize\(|GenerateKey\([^,()]+,|"
+    r"\b(?:RSA|DSA)\.generate\()\s*(\d+)"
+)
+_NOT_FOR_SECURITY_RE = re.compile(r"usedforsecurity\s*=\s*False")
+# Lines after a key generator searched for its size (Java initialize(), Node options)
+_KEY_SIZE_LOOKAHEAD = 4
+
+
+@dataclass(frozen=True)
+class CryptoUsage:
+    """One use of a cryptographic API."""
+
+    path: str
+    line: int
+    category: str  # one of CRYPTO_CATEGORIES
+    algorithm: str  # normalized: sha256, aes, rsa, hs256, tls1.2, no-verify, a library name
+    mode: str = ""  # cipher mode (cbc, gcm, ecb), when known
+    key_bits: Optional[int] = None
+    for_security: bool = True
+
+
+@dataclass(frozen=True)
+class CryptoViolation:
+    """A usage the policy does not allow."""
+
+    usage: CryptoUsage
+    rule: str  # one of CRYPTO_RULES
+    subject: str  # the banned algorithm or mode, or the library
+    detail: str
+
+    @property
+    def severity(self) -> float:
+        return _CRITICAL_ALGORITHMS.get(self.subject, _SEVERITY[self.rule])
+
+
+@dataclass
+class CryptoPolicy:
+    banned_algorithms: frozenset[str] = frozenset(DEFAULT_BANNED_ALGORITHMS)
+    min_key_bits: dict[str, int] = field(default_factory=lambda: dict(DEFAULT_MIN_KEY_BITS))
+    approved_libraries: frozenset[str] = frozenset()  # empty: any library
+
+    def check(self, usage: CryptoUsage) -> list[CryptoViolation]:
+        """Violations of this policy by *usage*."""
+        if not usage.for_security:
+            return []
+        if usage.category == "library":
+            if self.approved_libraries and usage.algorithm not in self.approved_libraries:
+                return [
+                    CryptoViolation(
+                        usage,
+                        "unapproved_library",
+                        usage.algorithm,
+                        f"{usage.algorithm} is not an approved crypto library",
+                    )
+                ]
+            return []
+        violations = [
+            CryptoViolation(usage, "banned_algorithm", name, f"{name} ({usage.category}) is banned")
+            for name in (usage.algorithm, usage.mode)
+            if name and name in self.banned_algorithms
+        ]
+        minimum = self.min_key_bits.get(usage.algorithm)
+        if minimum and usage.key_bits is not None and usage.key_bits < minimum:
+            violations.append(
+                CryptoViolation(
+                    usage,
+                    "weak_key",
+                    usage.algorithm,
+                    f"{usage.algorithm} key of {usage.key_bits} bits, minimum {minimum}",
+                )
+            )
+        return violations
+
+
+@dataclass
+class CryptoReport:
+    usages: list[CryptoUsage]  # by path and line
+    violations: list[CryptoViolation]  # worst first
+
+    def inventory(self) -> dict[str, dict[str, int]]:
+        """Usage count per category and algorithm."""
+        counts: dict[str, dict[str, int]] = {}
+        for usage in self.usages:
+            name = f"{usage.algorithm}/{usage.mode}" if usage.mode else usage.algorithm
+            category = counts.setdefault(usage.category, {})
+            category[name] = category.get(name, 0) + 1
+        return {
+            category: dict(sorted(counts[category].items(), key=lambda kv: (-kv[1], kv[0])))
+            for category in CRYPTO_CATEGORIES
+            if category in counts
+        }
+
+    def to_dict(self) -> dict:
+        return {
+            "group": "security.crypto",
+            "inventory": self.inventory(),
+            "usages": [u.__dict__ for u in self.usages],
+            "violations": [
+                {**v.usage.__dict__, "rule": v.rule, "subject": v.subject, "detail": v.detail}
+                for v in self.violations
+            ],
+        }
+
+
+def resolve_policy(overrides: Optional[dict[str, Any]] = None) -> CryptoPolicy:
+    """The default policy with the ``crypto_policy`` config table applied, validated."""
+    overrides = overrides or {}
+    if not isinstance(overrides, dict):
+        raise ValueError("crypto_policy must be a table")
+    unknown = sorted(set(overrides) - set(_POLICY_KEYS))
+    if unknown:
+        raise ValueError(f"crypto_policy: unknown keys {unknown}; expected {list(_POLICY_KEYS)}")
+    policy = CryptoPolicy()
+    for key in ("banned_algorithms", "approved_libraries"):
+        if key in overrides:
+            values = overrides[key]
+            if not isinstance(values, list) or not all(isinstance(v, str) for v in values):
+                raise ValueError(f"crypto_policy.{key} must be a list of strings")
+            normalized = (v.lower() if key == "banned_algorithms" else v for v in values)
+            setattr(policy, key, frozenset(normalized))
+    bits = overrides.get("min_key_bits", {})
+    if not isinstance(bits, dict) or not all(
+        isinstance(v, int) and not isinstance(v, bool) and v > 0 for v in bits.values()
+    ):
+        raise ValueError("crypto_policy.min_key_bits must map algorithms to positive integers")
+    policy.min_key_bits.update({k.lower(): v for k, v in bits.items()})
+    return policy
+
+
+def analyze_crypto(
+    files: dict[str, FileSyntax],
+    contents: dict[str, str],
+    policy: Optional[CryptoPolicy] = None,
+) -> CryptoReport:
+    """Inventory crypto usage in every file and check it against *policy*."""
+    policy = policy or CryptoPolicy()
+    usages: list[CryptoUsage] = []
+    for path in sorted(contents):
+        lines = contents[path].splitlines()
+        syntax = files.get(path)
+        if syntax is not None:
+            usages.extend(_library_usages(path, syntax, lines))
+        usages.extend(_api_usages(path, lines))
+    usages.sort(key=lambda u: (u.path, u.line, u.category))
+    violations = [v for usage in usages for v in policy.check(usage)]
+    violations.sort(key=lambda v: (-v.severity, v.usage.path, v.usage.line))
+    return CryptoReport(usages, violations)
+
+
+def to_findings(violations: list[CryptoViolation]) -> list:
+    """Convert violations to ``crypto_policy_violation`` findings, one per file/rule/subject."""
+    from ..insights.models import Evidence, Finding
+
+    grouped: dict[tuple[str, str, str], list[CryptoViolation]] = {}
+    for violation in violations:
+        key = (violation.usage.path, violation.rule, violation.subject)
+        grouped.setdefault(key, []).append(violation)
+
+    findings = []
+    for (path, rule, subject), group in grouped.items():
+        first = group[0]
+        lines = ", ".join(str(v.usage.line) for v in group)
+        findings.append(
+            Finding(
+                finding_type=CRYPTO_VIOLATION_TYPE,
+                severity=max(v.severity for v in group),
+                title=f"{first.detail} in {path}",
+                files=[path],
+                evidence=[
+                    Evidence(signal="rule", value=0.0, percentile=0.0, description=rule),
+                    Evidence(
+                        signal="occurrences",
+                        value=float(len(group)),
+                        percentile=0.0,
+                        description=f"line {lines}" if len(group) == 1 else f"lines {lines}",
+                    ),
+                ],
+                suggestion=_SUGGESTIONS[rule],
+                effort="MEDIUM",
+                identity_hint=f"{rule}:{subject}",
+            )
+        )
+    return findings
+
+
+_SUGGESTIONS = {
+    "banned_algorithm": "Replace with an algorithm the crypto policy allows",
+    "weak_key": "Generate keys of at least the policy's minimum size",
+    "unapproved_library": "Use an approved crypto library",
+}
+
+
+# ── Detection ─────────────────────────────────────────────────────
+
+
+def _normalize(name: str) -> str:
+    """Lowercase algorithm name without separators: SHA-256 -> sha256, DESede -> 3des."""
+    key = re.sub(r"[-_/ ]", "", name.lower())
+    return _ALIASES.get(key, key)
+
+
+_ALIASES = {
+    "desede": "3des",
+    "tripledes": "3des",
+    "des3": "3des",
+    "desede3": "3des",
+    "arc4": "rc4",
+    "arcfour": "rc4",
+    "bf": "blowfish",
+    "sha": "sha1",
+    "sha3224": "sha3-224",
+    "sha3256": "sha3-256",
+    "sha3384": "sha3-384",
+    "sha3512": "sha3-512",
+    "ecdsa": "ec",
+    "diffiehellman": "dh",
+}
+_TLS_VERSIONS = {
+    "sslv2": "sslv2",
+    "sslv3": "sslv3",
+    "ssl30": "sslv3",
+    "tlsv1": "tls1.0",
+    "tls10": "tls1.0",
+    "tlsv10": "tls1.0",
+    "tlsv11": "tls1.1",
+    "tls11": "tls1.1",
+    "tlsv12": "tls1.2",
+    "tls12": "tls1.2",
+    "tlsv13": "tls1.3",
+    "tls13": "tls1.3",
+}
+
+
+def _tls_version(name: str) -> str:
+    key = re.sub(r"[-_.]|method$", "", name.lower())
+    return _TLS_VERSIONS.get(key, "tls")
+
+
+def _cipher_spec(spec: str) -> tuple[str, str, Optional[int]]:
+    """(algorithm, mode, key bits) of an OpenSSL-style name: aes-256-cbc, des-ede3-cbc."""
+    parts = spec.lower().split("-")
+    if parts[0] == "des" and len(parts) > 1 and parts[1].startswith("ede"):
+        return "3des", parts[-1] if len(parts) > 2 else "", None
+    bits = next((int(p) for p in parts[1:] if p.isdigit()), None)
+    modes = [p for p in parts[1:] if not p.isdigit()]
+    return _normalize(parts[0]), modes[-1] if modes else "", bits
+
+
+@dataclass(frozen=True)
+class _Detector:
+    category: str
+    pattern: re.Pattern[str]
+    # match -> (algorithm, mode, key bits); algorithm "" skips the match
+    read: Callable[[re.Match[str]], tuple[str, str, Optional[int]]]
+    needs: Optional[re.Pattern[str]] = None  # the line must also match this
+
+
+def _algorithm(match: re.Match[str]) -> tuple[str, str, Optional[int]]:
+    return _normalize(match.group("alg")), "", None
+
+
+def _fixed(algorithm: str) -> Callable[[re.Match[str]], tuple[str, str, Optional[int]]]:
+    return lambda match: (algorithm, "", None)
+
+
+def _java_cipher(match: re.Match[str]) -> tuple[str, str, Optional[int]]:
+    # Cipher.getInstance("AES") means AES/ECB/PKCS5Padding
+    parts = match.group("spec").split("/")
+    mode = parts[1].lower() if len(parts) > 1 else "ecb"
+    return _normalize(parts[0]), mode, None
+
+
+def _pycryptodome_cipher(match: re.Match[str]) -> tuple[str, str, Optional[int]]:
+    mode = re.search(r"MODE_(\w+)", match.group(0))
+    return _normalize(match.group("alg")), mode.group(1).lower() if mode else "", None
+
+
+def _go_cipher(match: re.Match[str]) -> tuple[str, str, Optional[int]]:
+    if match.group("triple"):
+        return "3des", "", None
+    return _normalize(match.group("alg")), "", None
+
+
+def _mode_only(match: re.Match[str]) -> tuple[str, str, Optional[int]]:
+    mode = re.sub(r"(?:En|De)crypter$", "", match.group("mode")).lower()
+    return "", mode, None
+
+
+def _tls(match: re.Match[str]) -> tuple[str, str, Optional[int]]:
+    return _tls_version(match.group("alg")), "", None
+
+
+_DETECTORS = [
+    # Hashes
+    _Detector(
+        "hash",
+        re.compile(
+            r"\bhashlib\.(?P<alg>md5|sha1|sha224|sha256|sha384|sha512|sha3_\d+|blake2[bs])\b"
+        ),
+        _algorithm,
+    ),
+    _Detector("hash", re.compile(r"\bhashlib\.new\(\s*['\"](?P<alg>[\w-]+)['\"]"), _algorithm),
+    _Detector(
+        "hash",
+        re.compile(r"\b(?P<alg>md4|md5|sha1|sha256|sha512)\.(?:New|Sum)\w*\("),
+        _algorithm,
+    ),
+    _Detector(
+        "hash", re.compile(r"\bMessageDigest\.getInstance\(\s*\"(?P<alg>[\w-]+)\""), _algorithm
+    ),
+    _Detector(
+        "hash", re.compile(r"\bcreate(?:Hash|Hmac)\(\s*['\"](?P<alg>[\w-]+)['\"]"), _algorithm
+    ),
+    _Detector(
+        "hash", re.compile(r"\bDigest::(?P<alg>MD5|SHA1|SHA256|SHA384|SHA512)\b"), _algorithm
+    ),
+    # Ciphers
+    _Detector(
+        "cipher",
+        re.compile(r"\b(?P<alg>AES|DES3|DES|ARC2|ARC4|Blowfish|ChaCha20)\.new\([^)\n]*"),
+        _pycryptodome_cipher,
+    ),
+    _Detector(
+        "cipher",
+        re.compile(
+            r"\balgorithms\.(?P<alg>AES|TripleDES|Blowfish|ARC4|ChaCha20|Camellia|CAST5|IDEA)\("
+        ),
+        _algorithm,
+    ),
+    _Detector("cipher", re.compile(r"\bmodes\.(?P<mode>ECB|CBC|GCM|CTR|CFB|OFB)\("), _mode_only),
+    _Detector(
+        "cipher",
+        re.compile(r"\b(?P<alg>aes|des|rc4|blowfish)\.New(?P<triple>TripleDES)?Cipher\("),
+        _go_cipher,
+    ),
+    _Detector(
+        "cipher",
+        re.compile(
+            r"\bcipher\.New(?P<mode>GCM|CBCEncrypter|CBCDecrypter|CTR|CFBEncrypter|"
+            r"CFBDecrypter|OFB)\b"
+        ),
+        _mode_only,
+    ),
+    _Detector(
+        "cipher", re.compile(r"\bCipher\.getInstance\(\s*\"(?P<spec>[\w/-]+)\""), _java_cipher
+    ),
+    _Detector(
+        "cipher",
+        re.compile(r"\bcreate(?:Cipher|Decipher)(?:iv)?\(\s*['\"](?P<spec>[\w-]+)['\"]"),
+        lambda match: _cipher_spec(match.group("spec")),
+    ),
+    # Key generation; sizes are filled in from the following lines
+    _Detector(
+        "key",
+        re.compile(r"\b(?P<alg>rsa|dsa|ec|dh)\.generate_private_key\("),
+        _algorithm,
+    ),
+    _Detector("key", re.compile(r"\b(?P<alg>RSA|DSA|ECC)\.generate\("), _algorithm),
+    _Detector("key", re.compile(r"\b(?P<alg>rsa|dsa|ecdsa)\.GenerateKey\("), _algorithm),
+    _Detector(
+        "key",
+        re.compile(r"\bKeyPairGenerator\.getInstance\(\s*\"(?P<alg>\w+)\""),
+        _algorithm,
+    ),
+    _Detector(
+        "key",
+        re.compile(r"\bgenerateKeyPair(?:Sync)?\(\s*['\"](?P<alg>\w+)['\"]"),
+        _algorithm,
+    ),
+    # JWT
+    _Detector(
+        "jwt",
+        re.compile(r"(['\"])(?P<alg>[HRPE]S(?:256|384|512)|none)\1"),
+        _algorithm,
+        needs=_JWT_CONTEXT_RE,
+    ),
+    _Detector(
+        "jwt", re.compile(r"\bSigningMethod(?P<alg>[HRPE]S(?:256|384|512)|None)\b"), _algorithm
+    ),
+    _Detector(
+        "jwt",
+        re.compile(r"\bSignatureAlgorithm\.(?P<alg>[HRPE]S(?:256|384|512)|NONE)\b"),
+        _algorithm,
+    ),
+    _Detector(
+        "jwt",
+        re.compile(
+            r"['\"]verify_signature['\"]\s*:\s*False"
+            r"|\bjwt\.decode\([^)\n]*verify\s*=\s*False"
+        ),
+        _fixed("none"),
+    ),
+    # TLS
+    _Detector(
+        "tls",
+        re.compile(r"\bssl\.PROTOCOL_(?P<alg>SSLv2|SSLv3|TLSv1(?:_[123])?|TLS\w*)\b"),
+        _tls,
+    ),
+    _Detector("tls", re.compile(r"\btls\.Version(?P<alg>SSL30|TLS1[0-3])\b"), _tls),
+    _Detector(
+        "tls",
+        re.compile(r"\bSSLContext\.getInstance\(\s*\"(?P<alg>[\w.]+)\""),
+        lambda match: (_tls_version(match.group("alg").replace(".", "")), "", None),
+    ),
+    _Detector("tls", re.compile(r"\bsecureProtocol\s*:\s*['\"](?P<alg>\w+?)_method"), _tls),
+    _Detector(
+        "tls",
+        re.compile(
+            r"\bssl\.(?:_create_unverified_context|CERT_NONE)\b|\bverify\s*=\s*False\b"
+            r"|\bcheck_hostname\s*=\s*False\b|\bInsecureSkipVerify\s*:\s*true\b"
+            r"|\brejectUnauthorized\s*:\s*false\b|\bNODE_TLS_REJECT_UNAUTHORIZED\b\W+0"
+        ),
+        _fixed("no-verify"),
+    ),
+]
+
+
+def _api_usages(path: str, lines: list[str]) -> list[CryptoUsage]:
+    usages = []
+    for lineno, line in enumerate(lines, 1):
+        if is_comment_line(line):
+            continue
+        for detector in _DETECTORS:
+            if detector.needs is not None and not detector.needs.search(line):
+                continue
+            for match in detector.pattern.finditer(line):
+                if detector.category == "tls" and "jwt" in line.lower():
+                    continue  # jwt.decode(..., verify=False) is a JWT usage
+                algorithm, mode, bits = detector.read(match)
+                if not algorithm and not mode:
+                    continue
+                if detector.category == "key" and bits is None:
+                    bits = _key_bits(lines, lineno)
+                usages.append(
+                    CryptoUsage(
+                        path=path,
+                        line=lineno,
+                        category=detector.category,
+                        algorithm=algorithm,
+                        mode=mode,
+                        key_bits=bits,
+                        for_security=not (
+                            detector.category == "hash" and _NOT_FOR_SECURITY_RE.search(line)
+                        ),
+                    )
+                )
+    return usages
+
+
+def _key_bits(lines: list[str], lineno: int) -> Optional[int]:
+    """Key size given on the generator's line or the few after it."""
+    window = " ".join(lines[lineno - 1 : lineno + _KEY_SIZE_LOOKAHEAD])
+    match = _KEY_BITS_RE.search(window)
+    return int(match.group(1)) if match else None
+
+
+def _library_usages(path: str, syntax: FileSyntax, lines: list[str]) -> list[CryptoUsage]:
+    usages = []
+    seen = set()
+    for imp in syntax.imports:
+        library = _library_of(imp.source)
+        if library is None or library in seen:
+            continue
+        seen.add(library)
+        line = next((i for i, text in enumerate(lines, 1) if imp.source in text), 0)
+        usages.append(CryptoUsage(path=path, line=line, category="library", algorithm=library))
+    return usages
+
+
+def _library_of(source: str) -> Optional[str]:
+    if _GO_CRYPTO_RE.match(source):
+        return source
+    for prefix, library in _LIBRARIES.items():
+        if source == prefix or source.startswith((prefix + ".", prefix + "/")):
+            return library
+    return None
@@ -0,0 +1,245 @@
+"""Usages of symbols marked deprecated.
+
+Deprecated definitions are recognised per language:
+
+    go          a ``// Deprecated:`` paragraph in the doc comment of a
+                func, method, type, var or const
+    python      ``@deprecated`` (PEP 702 / typing_extensions), or a
+                ``warnings.warn(..., DeprecationWarning)`` in the body
+                (attributed to the class when raised from ``__init__``)
+    js / ts     ``@deprecated`` in the JSDoc block of a function, class,
+                variable or method
+
+Call sites are matched by name (word boundary, outside comments and string
+literals) in files of the same language, so a deprecated method shares its
+count with same-named methods elsewhere. Counts are recorded with each
+snapshot (``deprecated_call_sites``) so a migration can be tracked to zero.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from typing import Optional
+
+from ..signals.complexity import is_comment_line
+from .glossary import STRING_RE
+from .sources import SourceSet
+
+# Languages whose call sites may reference each other's symbols
+_LANGUAGE_FAMILY = {"typescript": "javascript", "tsx": "javascript"}
+
+_GO_DEPRECATED_RE = re.compile(r"^\s*//\s*Deprecated:\s*(.*)")
+# Top-level declarations, plus indented specs in const/var groups and struct fields
+_GO_DECL_RE = re.compile(
+    r"^(?:func\s+(?:\([^)]*\)\s*)?(\w+)|type\s+(\w+)|(?:var|const)\s+(\w+)"
+    r"|\s+([A-Za-z_]\w*)\b)"
+)
+_PY_DEF_RE = re.compile(r"^(\s*)(?:async\s+)?(def|class)\s+(\w+)")
+_PY_DECORATOR_RE = re.compile(r"^\s*@(?:\w+\.)*deprecated\b(?:\(\s*['\"]?([^'\")]*))?")
+_PY_WARN_RE = re.compile(r"\bwarn(?:ings\.warn)?\s*\(")
+_PY_CATEGORY_RE = re.compile(r"\b(?:Pending)?DeprecationWarning\b")
+_JSDOC_DEPRECATED_RE = re.compile(r"@deprecated\b\s*(.*?)\s*(?:\*/)?\s*$")
+_JS_DECL_RE = re.compile(
+    r"^\s*(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?"
+    r"(?:function\s*\*?\s*(\w+)|class\s+(\w+)|interface\s+(\w+)|type\s+(\w+)"
+    r"|(?:const|let|var)\s+(\w+)"
+    r"|(?:(?:public|private|protected|static|readonly|get|set)\s+)*(\w+)\s*[(<:=?])"
+)
+
+_MIN_NAME_LENGTH = 3
+
+
+@dataclass
+class DeprecatedSymbol:
+    """A deprecated definition and every place it is still used."""
+
+    name: str
+    language: str
+    path: str
+    line: int
+    note: str = ""  # replacement hint from the deprecation message
+    call_sites: list[str] = field(default_factory=list)  # "path:line"
+
+    @property
+    def count(self) -> int:
+        return len(self.call_sites)
+
+
+@dataclass
+class DeprecationReport:
+    symbols: list[DeprecatedSymbol]  # most used first
+
+    @property
+    def call_sites(self) -> int:
+        return sum(s.count for s in self.symbols)
+
+    def by_package(self) -> dict[str, int]:
+        """Call sites per package, most first."""
+        counts: dict[str, int] = {}
+        for symbol in self.symbols:
+            for site in symbol.call_sites:
+                package = SourceSet.package_of(site.rsplit(":", 1)[0])
+                counts[package] = counts.get(package, 0) + 1
+        return dict(sorted(counts.items(), key=lambda kv: (-kv[1], kv[0])))
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        global_signals = {
+            "deprecated_symbols": float(len(self.symbols)),
+            "deprecated_call_sites": float(self.call_sites),
+        }
+        package_signals = {
+            package: {"deprecated_call_sites": float(n)}
+            for package, n in self.by_package().items()
+        }
+        return global_signals, package_signals
+
+    def to_dict(self) -> dict:
+        return {
+            "symbols": len(self.symbols),
+            "call_sites": self.call_sites,
+            "by_package": self.by_package(),
+            "deprecated": [{**s.__dict__, "count": s.count} for s in self.symbols],
+        }
+
+
+def _go_definitions(lines: list[str]) -> list[tuple[str, int, str]]:
+    found = []
+    note: Optional[str] = None
+    for lineno, line in enumerate(lines, 1):
+        stripped = line.strip()
+        if stripped.startswith("//"):
+            match = _GO_DEPRECATED_RE.match(line)
+            if match:
+                note = match.group(1)
+            continue
+        if note is not None:
+            decl = _GO_DECL_RE.match(line)
+            if decl:
+                name = next(g for g in decl.groups() if g)
+                found.append((name, lineno, note))
+        note = None
+    return found
+
+
+def _python_definitions(lines: list[str]) -> list[tuple[str, int, str]]:
+    found = []
+    decorated: Optional[str] = None
+    # (indent, kind, name, line) of enclosing defs, innermost last
+    stack: list[tuple[int, str, str, int]] = []
+    for lineno, line in enumerate(lines, 1):
+        if not line.strip() or is_comment_line(line):
+            continue
+        indent = len(line) - len(line.lstrip())
+        decorator = _PY_DECORATOR_RE.match(line)
+        if decorator:
+            decorated = decorator.group(1) or ""
+            continue
+        if line.lstrip().startswith("@"):
+            continue  # other decorators of the same definition
+        while stack and stack[-1][0] >= indent:
+            stack.pop()
+        definition = _PY_DEF_RE.match(line)
+        if definition:
+            kind, name = definition.group(2), definition.group(3)
+            stack.append((indent, kind, name, lineno))
+            if decorated is not None:
+                found.append((name, lineno, decorated))
+            decorated = None
+            continue
+        decorated = None
+        if _PY_WARN_RE.search(line) and stack:
+            window = " ".join(lines[lineno - 1 : lineno + 3])
+            if not _PY_CATEGORY_RE.search(window):
+                continue
+            _, _, name, def_line = stack[-1]
+            if name == "__init__" and len(stack) >= 2 and stack[-2][1] == "class":
+                _, _, name, def_line = stack[-2]
+            message = STRING_RE.search(window)
+            found.append((name, def_line, message.group()[1:-1] if message else ""))
+    return found
+
+
+def _js_definitions(lines: list[str]) -> list[tuple[str, int, str]]:
+    found = []
+    in_doc = False
+    note: Optional[str] = None
+    pending: Optional[str] = None
+    for lineno, line in enumerate(lines, 1):
+        stripped = line.strip()
+        if stripped.startswith("/**"):
+            in_doc, note = True, None
+        if in_doc:
+            match = _JSDOC_DEPRECATED_RE.search(stripped)
+            if match:
+                note = match.group(1)
+            if "*/" in stripped:
+                in_doc, pending = False, note
+            continue
+        if not stripped or stripped.startswith(("//", "@")):
+            continue
+        if pending is not None:
+            decl = _JS_DECL_RE.match(line)
+            if decl:
+                name = next(g for g in decl.groups() if g)
+                found.append((name, lineno, pending))
+        pending = None
+    return found
+
+
+_DEFINITION_FINDERS = {
+    "go": _go_definitions,
+    "python": _python_definitions,
+    "javascript": _js_definitions,
+}
+
+
+def _family(language: str) -> str:
+    return _LANGUAGE_FAMILY.get(language, language)
+
+
+def find_deprecated(sources: SourceSet) -> list[DeprecatedSymbol]:
+    """Deprecated definitions in *sources*, in path order."""
+    symbols: list[DeprecatedSymbol] = []
+    for path in sorted(sources.content):
+        language = _family(sources.language_of(path))
+        finder = _DEFINITION_FINDERS.get(language)
+        if finder is None:
+            continue
+        seen: set[str] = set()
+        for name, line, note in finder(sources.content[path].splitlines()):
+            if len(name) < _MIN_NAME_LENGTH or name in seen:
+                continue
+            seen.add(name)
+            symbols.append(DeprecatedSymbol(name, language, path, line, note.strip()))
+    return symbols
+
+
+def analyze_deprecations(sources: SourceSet) -> DeprecationReport:
+    """Find deprecated symbols and count their call sites across the repo."""
+    symbols = find_deprecated(sources)
+    by_language: dict[str, list[DeprecatedSymbol]] = {}
+    for symbol in symbols:
+        by_language.setdefault(symbol.language, []).append(symbol)
+
+    for language, group in by_language.items():
+        names = {s.name for s in group}
+        pattern = re.compile(r"\b(" + "|".join(sorted(map(re.escape, names))) + r")\b")
+        # Definition lines are not call sites
+        definitions = {(s.path, s.line) for s in group}
+        sites: dict[str, list[str]] = {name: [] for name in names}
+        for path in sorted(sources.content):
+            if _family(sources.language_of(path)) != language:
+                continue
+            for lineno, line in enumerate(sources.content[path].splitlines(), 1):
+                if (path, lineno) in definitions or is_comment_line(line):
+                    continue
+                code = STRING_RE.sub(" ", line)
+                for name in set(pattern.findall(code)):
+                    sites[name].append(f"{path}:{lineno}")
+        for symbol in group:
+            symbol.call_sites = sites[symbol.name]
+
+    symbols.sort(key=lambda s: (-s.count, s.path, s.line))
+    return DeprecationReport(symbols=symbols)
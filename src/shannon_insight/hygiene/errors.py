@@ -0,0 +1,382 @@
+"""Error hygiene rules for Go.
+
+Go returns errors as values, so a dropped error is one line and looks like
+any other. These rules look for errors that are lost or that callers can
+no longer match:
+
+    swallowed_error       an error assigned to the blank identifier
+                          (``_ = err``)
+    empty_error_branch    an ``if err != nil`` branch with nothing but
+                          blank lines or comments in it
+    string_matched_error  an error recognised by its message
+                          (``strings.Contains(err.Error(), "not found")``,
+                          ``err.Error() == "..."``, ``switch err.Error()``)
+                          rather than with ``errors.Is`` or ``errors.As``
+    unwrapped_error       an error formatted into a new one without ``%w``
+                          (``fmt.Errorf("...: %v", err)``) or copied by
+                          message (``errors.New(err.Error())``), which
+                          cuts the chain ``errors.Is`` follows; when the
+                          error is formatted with ``%v`` or ``%s`` the
+                          finding carries the line with ``%w`` instead
+
+Error variables are recognised by name: ``err``, ``errSomething`` and
+``somethingErr``. Alongside the issues the report counts error checks
+(``if ... err != nil``) and typed matches (``errors.Is`` / ``errors.As``)
+per package, so the issue count can be read against how much error
+handling there is. Test files and comment lines are skipped. Issues are
+reported as ``error_hygiene_issue`` findings, one per file, rule and
+subject.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+
+from ..semantics.roles import TEST_PATH_PATTERNS
+from ..signals.complexity import is_comment_line
+from .sources import SourceSet
+
+ERROR_HYGIENE_TYPE = "error_hygiene_issue"
+
+ERROR_RULES = (
+    "swallowed_error",
+    "empty_error_branch",
+    "string_matched_error",
+    "unwrapped_error",
+)
+
+_SEVERITY = {
+    "swallowed_error": 0.5,
+    "empty_error_branch": 0.5,
+    "string_matched_error": 0.4,
+    "unwrapped_error": 0.3,
+}
+
+
+@dataclass(frozen=True)
+class ErrorIssue:
+    path: str
+    line: int
+    rule: str  # one of ERROR_RULES
+    subject: str  # the error variable, or the message it is matched on
+    detail: str
+    fix: str = ""  # the line with the issue fixed, when the fix is mechanical
+
+    @property
+    def severity(self) -> float:
+        return _SEVERITY[self.rule]
+
+
+@dataclass
+class ErrorReport:
+    issues: list[ErrorIssue]  # worst first
+    checks: dict[str, int]  # package -> ``err != nil`` checks
+    typed_checks: dict[str, int]  # package -> errors.Is / errors.As calls
+
+    def counts(self) -> dict[str, int]:
+        """Issue count per rule, in ERROR_RULES order."""
+        return {
+            rule: n for rule in ERROR_RULES if (n := sum(i.rule == rule for i in self.issues))
+        }
+
+    def by_package(self) -> dict[str, int]:
+        """Issues per package, most first."""
+        counts: dict[str, int] = {}
+        for issue in self.issues:
+            package = SourceSet.package_of(issue.path)
+            counts[package] = counts.get(package, 0) + 1
+        return dict(sorted(counts.items(), key=lambda kv: (-kv[1], kv[0])))
+
+    @property
+    def check_count(self) -> int:
+        return sum(self.checks.values())
+
+    @property
+    def typed_check_count(self) -> int:
+        return sum(self.typed_checks.values())
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        if not self.checks and not self.issues:
+            return {}, {}
+        global_signals = {
+            "error_checks": float(self.check_count),
+            "error_hygiene_issues": float(len(self.issues)),
+        }
+        issues = self.by_package()
+        package_signals = {
+            package: {
+                "error_checks": float(self.checks.get(package, 0)),
+                "error_hygiene_issues": float(issues.get(package, 0)),
+            }
+            for package in sorted(set(self.checks) | set(issues))
+        }
+        return global_signals, package_signals
+
+    def to_dict(self) -> dict:
+        return {
+            "group": "errors",
+            "error_checks": self.check_count,
+            "typed_checks": self.typed_check_count,
+            "counts": self.counts(),
+            "by_package": self.by_package(),
+            "issues": [i.__dict__ for i in self.issues],
+        }
+
+
+def analyze_errors(contents: dict[str, str]) -> ErrorReport:
+    """Apply the error hygiene rules to every non-test Go file."""
+    issues: list[ErrorIssue] = []
+    checks: dict[str, int] = {}
+    typed_checks: dict[str, int] = {}
+    for path in sorted(contents):
+        if not path.endswith(".go"):
+            continue
+        if any(pattern.search(path.lower()) for pattern in TEST_PATH_PATTERNS):
+            continue
+        file_issues, file_checks, file_typed = _scan(path, contents[path])
+        issues.extend(file_issues)
+        package = SourceSet.package_of(path)
+        if file_checks:
+            checks[package] = checks.get(package, 0) + file_checks
+        if file_typed:
+            typed_checks[package] = typed_checks.get(package, 0) + file_typed
+    issues.sort(key=lambda i: (-i.severity, i.path, i.line, i.rule))
+    return ErrorReport(issues, checks, typed_checks)
+
+
+def to_findings(issues: list[ErrorIssue]) -> list:
+    """Convert issues to ``error_hygiene_issue`` findings, one per file/rule/subject."""
+    from ..insights.models import Evidence, Finding, Patch
+
+    grouped: dict[tuple[str, str, str], list[ErrorIssue]] = {}
+    for issue in issues:
+        grouped.setdefault((issue.path, issue.rule, issue.subject), []).append(issue)
+
+    findings = []
+    for (path, rule, subject), group in grouped.items():
+        lines = ", ".join(str(i.line) for i in group)
+        findings.append(
+            Finding(
+                finding_type=ERROR_HYGIENE_TYPE,
+                severity=group[0].severity,
+                title=f"{group[0].detail} in {path}",
+                files=[path],
+                evidence=[
+                    Evidence(signal="rule", value=0.0, percentile=0.0, description=rule),
+                    Evidence(
+                        signal="occurrences",
+                        value=float(len(group)),
+                        percentile=0.0,
+                        description=f"line {lines}" if len(group) == 1 else f"lines {lines}",
+                    ),
+                ],
+                suggestion=_SUGGESTIONS[rule],
+                effort="MEDIUM" if rule == "string_matched_error" else "LOW",
+                identity_hint=f"{rule}:{subject}",
+                patches=[Patch(path, i.line, i.line, i.fix) for i in group if i.fix],
+            )
+        )
+    return findings
+
+
+_SUGGESTIONS = {
+    "swallowed_error": "Handle or return the error, or log why it is safe to ignore",
+    "empty_error_branch": "Return, wrap or log the error instead of leaving the branch empty",
+    "string_matched_error": (
+        "Match a sentinel error with errors.Is or an error type with errors.As; "
+        "messages change without notice"
+    ),
+    "unwrapped_error": (
+        "Wrap the error with %w so callers can still match its cause with errors.Is "
+        "and errors.As"
+    ),
+}
+
+
+# ── Detection ─────────────────────────────────────────────────────
+
+_ERR = r"(?:err(?:[A-Z0-9]\w*)?|[a-z]\w*Err)"
+_MESSAGE = r"\"(?P<message>[^\"\n]*)\""
+
+_CHECK_RE = re.compile(rf"\bif\b[^{{]*\b(?P<name>{_ERR})\s*!=\s*nil\b[^{{]*\{{")
+_TYPED_RE = re.compile(r"\berrors\.(?:Is|As)\(")
+_SWALLOWED_RE = re.compile(rf"^\s*_\s*=\s*(?P<name>{_ERR})\s*$")
+_STRING_MATCHES = (
+    re.compile(
+        rf"\bstrings\.(?:Contains|HasPrefix|HasSuffix|EqualFold|Index)\(\s*"
+        rf"(?P<name>{_ERR})\.Error\(\)\s*,\s*(?:{_MESSAGE})?"
+    ),
+    re.compile(rf"\b(?P<name>{_ERR})\.Error\(\)\s*[!=]=\s*(?:{_MESSAGE})?"),
+    re.compile(rf"{_MESSAGE}\s*[!=]=\s*(?P<name>{_ERR})\.Error\(\)"),
+    re.compile(rf"\bswitch\s+(?P<name>{_ERR})\.Error\(\)"),
+)
+_ERRORF_RE = re.compile(r"\bfmt\.Errorf\(\s*(?P<q>[\"`])(?P<format>.*?)(?P=q)(?P<args>.*)")
+_ERR_ARG_RE = re.compile(rf"\b(?P<name>{_ERR})\b")
+_ERR_NAME_RE = re.compile(r"(?P<name>err|[a-z]\w*Err)(?:\.Error\(\))?")
+_VERB_RE = re.compile(r"%[-+# 0]*(?:\d+|\*|\[)?(?:\.(?:\d+|\*))?.")
+_COPIED_RE = re.compile(rf"\berrors\.New\(\s*(?P<name>{_ERR})\.Error\(\)\s*\)")
+
+
+def _scan(path: str, content: str) -> tuple[list[ErrorIssue], int, int]:
+    """(issues, error checks, typed checks) of one file."""
+    lines = content.splitlines()
+    issues: list[ErrorIssue] = []
+    checks = typed = 0
+    for n, line in enumerate(lines, 1):
+        if is_comment_line(line):
+            continue
+        code = _strip_comment(line)
+        typed += len(_TYPED_RE.findall(code))
+
+        match = _SWALLOWED_RE.match(code)
+        if match is not None:
+            name = match["name"]
+            detail = f"Error {name} is discarded with _ = {name}"
+            issues.append(ErrorIssue(path, n, "swallowed_error", name, detail))
+
+        match = _CHECK_RE.search(code)
+        if match is not None:
+            checks += 1
+            if _branch_is_empty(code[match.end() :], lines[n:]):
+                name = match["name"]
+                detail = f"Error {name} is checked and then ignored"
+                issues.append(ErrorIssue(path, n, "empty_error_branch", name, detail))
+
+        for pattern in _STRING_MATCHES:
+            match = pattern.search(code)
+            if match is not None:
+                message = match.groupdict().get("message")
+                if message:
+                    detail, subject = f'Error matched on its message "{message}"', message
+                else:
+                    detail, subject = "Error matched on its message", match["name"]
+                issues.append(ErrorIssue(path, n, "string_matched_error", subject, detail))
+                break
+
+        issue = _unwrapped(path, n, code, line)
+        if issue is not None:
+            issues.append(issue)
+    return issues, checks, typed
+
+
+def _unwrapped(path: str, n: int, code: str, line: str) -> ErrorIssue | None:
+    match = _COPIED_RE.search(code)
+    if match is not None:
+        name = match["name"]
+        detail = f"Error {name} is copied into a new error by its message"
+        return ErrorIssue(path, n, "unwrapped_error", name, detail)
+    match = _ERRORF_RE.search(code)
+    if match is None or "%w" in match["format"]:
+        return None
+    name = _error_arg(match)
+    if name is None:
+        return None
+    detail = f"Error {name} is formatted into a new error without %w"
+    return ErrorIssue(path, n, "unwrapped_error", name, detail, _wrap_fix(match, name, line))
+
+
+def _error_arg(match: re.Match[str]) -> str | None:
+    """The error an Errorf *match* formats, or None.
+
+    An argument that is exactly ``err`` or ``*Err`` (or its ``.Error()``) is
+    preferred, first one whose verb would become ``%w``, so ``errMsg`` or
+    ``wrapErr(x)`` next to ``err`` is not taken for the error; failing that,
+    the first error-like name.
+    """
+    args = _call_args(match["args"])
+    exact = [(i, m["name"]) for i, m in enumerate(map(_ERR_NAME_RE.fullmatch, args)) if m]
+    verbs = [v.group() for v in _VERB_RE.finditer(match["format"]) if v.group() != "%%"]
+    for index, name in exact:
+        if index < len(verbs) and verbs[index] in ("%v", "%s"):
+            return name
+    if exact:
+        return exact[0][1]
+    arg = _ERR_ARG_RE.search(match["args"])
+    return None if arg is None else arg["name"]
+
+
+def _wrap_fix(match: re.Match[str], name: str, line: str) -> str:
+    """*line* with the verb *name* is formatted with turned into %w, or "".
+
+    Only a ``%v`` or ``%s`` verb whose argument is exactly *name* is
+    rewritten; formats with ``*`` widths or explicit argument indexes are
+    left alone, as the verbs no longer line up with the arguments, and so
+    are formats with an escaped quote, which _ERRORF_RE cuts short.
+    """
+    if match["format"].endswith("\\"):
+        return ""
+    verbs = [v for v in _VERB_RE.finditer(match["format"]) if v.group() != "%%"]
+    if any("*" in v.group() or "[" in v.group() for v in verbs):
+        return ""
+    args = _call_args(match["args"])
+    if name not in args:
+        return ""
+    index = args.index(name)
+    if index >= len(verbs) or verbs[index].group() not in ("%v", "%s"):
+        return ""
+    start = match.start("format") + verbs[index].start()
+    return line[:start] + "%w" + line[start + 2 :]
+
+
+def _call_args(rest: str) -> list[str]:
+    """Arguments after the format of a call, *rest* starting just past the format."""
+    args: list[str] = []
+    depth = 0
+    quote = ""
+    current = ""
+    escaped = False
+    for c in rest:
+        if quote:
+            if escaped:
+                escaped = False
+            elif c == "\\" and quote != "`":
+                escaped = True
+            elif c == quote:
+                quote = ""
+        elif c in "\"'`":
+            quote = c
+        elif c in "([{":
+            depth += 1
+        elif c in ")]}":
+            if depth == 0:
+                break
+            depth -= 1
+        elif c == "," and depth == 0:
+            args.append(current.strip())
+            current = ""
+            continue
+        current += c
+    args.append(current.strip())
+    return args[1:]
+
+
+def _branch_is_empty(rest: str, following: list[str]) -> bool:
+    """Whether the block opened just before *rest* closes with nothing in it."""
+    if rest.strip():
+        return rest.strip().startswith("}")
+    for line in following:
+        stripped = _strip_comment(line).strip()
+        if stripped:
+            return stripped.startswith("}")
+    return False
+
+
+def _strip_comment(line: str) -> str:
+    """*line* without a trailing ``//`` comment (string contents kept)."""
+    quote = ""
+    i = 0
+    while i < len(line):
+        c = line[i]
+        if quote:
+            if c == "\\" and quote != "`":
+                i += 1
+            elif c == quote:
+                quote = ""
+        elif c in "\"'`":
+            quote = c
+        elif line.startswith("//", i):
+            return line[:i]
+        i += 1
+    return line
@@ -0,0 +1,245 @@
+"""Test fixtures and golden files that nothing reads, or that dwarf their tests.
+
+A fixture is any tracked file under a fixture directory (FIXTURE_DIRS:
+``fixtures/``, ``testdata/``, ``__snapshots__/``, ...) and any golden file
+(``*.golden``, ``*.snap``) elsewhere. It is referenced when a source file
+outside the fixtures (tests, test helpers, build files) names:
+
+    the file     its file name (``sample.json``), or its name without the
+                 extension, as Django's ``loaddata users`` and golden-file
+                 helpers (``golden(t, "output")``) do
+    a directory  any directory between the fixture directory and the file
+                 (``polyglot_baseline`` for a sample project read as a
+                 whole), or the fixture directory itself on a line that
+                 lists it (``glob``, ``iterdir``, ``listdir``, ``ReadDir``)
+
+A Jest snapshot (``__snapshots__/Button.test.js.snap``) is referenced while
+its test file (``Button.test.js`` next to ``__snapshots__``) exists.
+
+Names are matched as whole tokens, so a name shared with unrelated code
+counts as a reference: stale candidates err on the side of being missed.
+A referenced fixture is oversized when it is OVERSIZED_BYTES or more and
+OVERSIZED_RATIO times the combined size of the files referencing it.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import Optional
+
+from ..diligence.inventory import tracked_files
+from ..scanning.languages import DEFAULT_SOURCE_EXTENSIONS, detect_language
+
+FIXTURE_DIRS = frozenset(
+    {
+        "fixtures",
+        "fixture",
+        "__fixtures__",
+        "testdata",
+        "test_data",
+        "test-data",
+        "golden",
+        "goldens",
+        "__snapshots__",
+    }
+)
+GOLDEN_SUFFIXES = (".golden", ".snap")
+
+OVERSIZED_BYTES = 64 * 1024
+OVERSIZED_RATIO = 10.0
+
+_TOKEN_RE = re.compile(r"[\w.-]+")
+_LISTING_RE = re.compile(
+    r"\b(?:r?glob|iterdir|listdir|scandir|walk|ReadDir|readdirSync|readdir|Glob)\b"
+)
+_REFERENCE_SUFFIXES = frozenset(s.lower() for s in DEFAULT_SOURCE_EXTENSIONS)
+
+
+@dataclass
+class Fixture:
+    """A fixture file and the source files referencing it."""
+
+    path: str
+    size: int  # bytes
+    root: str  # the fixture directory, or the golden file's directory
+    referenced_by: list[str] = field(default_factory=list)
+    support_bytes: int = 0  # combined size of referenced_by
+
+    @property
+    def stale(self) -> bool:
+        return not self.referenced_by
+
+    @property
+    def ratio(self) -> float:
+        """Size relative to the code referencing it (0 when stale)."""
+        return self.size / self.support_bytes if self.support_bytes else 0.0
+
+    @property
+    def oversized(self) -> bool:
+        return (
+            not self.stale and self.size >= OVERSIZED_BYTES and self.ratio >= OVERSIZED_RATIO
+        )
+
+    def to_dict(self) -> dict:
+        return {
+            **self.__dict__,
+            "stale": self.stale,
+            "ratio": round(self.ratio, 1),
+            "oversized": self.oversized,
+        }
+
+
+@dataclass
+class FixtureRoot:
+    """A fixture directory: its files, their size and the stale share."""
+
+    root: str
+    files: int = 0
+    size: int = 0
+    stale: int = 0
+    stale_size: int = 0
+
+
+@dataclass
+class FixtureReport:
+    """Every fixture, by path."""
+
+    fixtures: list[Fixture] = field(default_factory=list)
+
+    def stale(self) -> list[Fixture]:
+        """Unreferenced fixtures, largest first."""
+        return sorted((f for f in self.fixtures if f.stale), key=lambda f: (-f.size, f.path))
+
+    def oversized(self) -> list[Fixture]:
+        """Oversized fixtures, largest relative to their tests first."""
+        return sorted((f for f in self.fixtures if f.oversized), key=lambda f: (-f.ratio, f.path))
+
+    def by_root(self) -> list[FixtureRoot]:
+        """Fixture directories, most stale bytes first."""
+        roots: dict[str, FixtureRoot] = {}
+        for f in self.fixtures:
+            root = roots.setdefault(f.root, FixtureRoot(f.root))
+            root.files += 1
+            root.size += f.size
+            if f.stale:
+                root.stale += 1
+                root.stale_size += f.size
+        return sorted(roots.values(), key=lambda r: (-r.stale_size, -r.size, r.root))
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        stale = self.stale()
+        return {
+            "fixtures": len(self.fixtures),
+            "stale": len(stale),
+            "stale_bytes": sum(f.size for f in stale),
+            "roots": [r.__dict__ for r in self.by_root()],
+            "stale_fixtures": [f.to_dict() for f in stale[:top]],
+            "oversized_fixtures": [f.to_dict() for f in self.oversized()[:top]],
+        }
+
+
+def fixture_root(path: str) -> Optional[str]:
+    """The fixture directory holding *path* (a golden file's own directory),
+    or None when *path* is not a fixture.
+    """
+    parts = PurePosixPath(path).parts
+    for index, part in enumerate(parts[:-1]):
+        if part in FIXTURE_DIRS:
+            return "/".join(parts[: index + 1])
+    if path.lower().endswith(GOLDEN_SUFFIXES):
+        return PurePosixPath(path).parent.as_posix()
+    return None
+
+
+def _is_reference_source(path: str) -> bool:
+    suffix = PurePosixPath(path).suffix.lower()
+    name = PurePosixPath(path).name
+    return (
+        detect_language(path) != "unknown"
+        or suffix in _REFERENCE_SUFFIXES
+        or name in ("Makefile", "Justfile", "Taskfile.yml", "package.json")
+    )
+
+
+def _names(path: str, root: str) -> tuple[set[str], set[str]]:
+    """(names that reference the file, directory names that reference it)."""
+    name = PurePosixPath(path).name
+    names = {name, name.split(".", 1)[0], name.rsplit(".", 1)[0]}
+    relative = PurePosixPath(path).relative_to(root).parts[:-1]
+    return {n for n in names if n}, set(relative)
+
+
+def collect_fixtures(root: Path, paths: Optional[list[str]] = None) -> FixtureReport:
+    """Find the fixtures under *root* and what references each of them.
+
+    Args:
+        root: Repository root
+        paths: Files to consider, relative to root (default: tracked_files)
+    """
+    paths = tracked_files(root) if paths is None else sorted(paths)
+    fixtures: dict[str, Fixture] = {}
+    for path in paths:
+        fixture_dir = fixture_root(path)
+        if fixture_dir is not None:
+            fixtures[path] = Fixture(path, _size(root / path), fixture_dir)
+    if not fixtures:
+        return FixtureReport()
+
+    wanted: dict[str, list[str]] = {}  # name -> fixtures it references
+    listed: dict[str, list[str]] = {}  # fixture directory name -> fixtures, when listed
+    for path, fixture in fixtures.items():
+        names, dirs = _names(path, fixture.root)
+        for name in names | dirs:
+            wanted.setdefault(name, []).append(path)
+        listed.setdefault(PurePosixPath(fixture.root).name, []).append(path)
+
+    sources = [p for p in paths if p not in fixtures and _is_reference_source(p)]
+    sizes: dict[str, int] = {}
+    references: dict[str, set[str]] = {path: set() for path in fixtures}
+    for source in sources:
+        try:
+            text = (root / source).read_text(encoding="utf-8", errors="replace")
+        except OSError:
+            continue
+        sizes[source] = len(text.encode("utf-8"))
+        tokens = set(_TOKEN_RE.findall(text))
+        for name in tokens & wanted.keys():
+            for path in wanted[name]:
+                references[path].add(source)
+        for name in tokens & listed.keys():
+            if _lists(text, name):
+                for path in listed[name]:
+                    references[path].add(source)
+
+    tracked = set(paths)
+    for path, fixture in fixtures.items():
+        test = _snapshot_test(path)
+        if test is not None and test in tracked:
+            references[path].add(test)
+            sizes.setdefault(test, _size(root / test))
+        fixture.referenced_by = sorted(references[path])
+        fixture.support_bytes = sum(sizes.get(s, 0) for s in fixture.referenced_by)
+    return FixtureReport(list(fixtures.values()))
+
+
+def _lists(text: str, name: str) -> bool:
+    """Whether a line naming directory *name* also lists a directory."""
+    word = re.compile(rf"(?<![\w.-]){re.escape(name)}(?![\w.-])")
+    return any(_LISTING_RE.search(line) and word.search(line) for line in text.splitlines())
+
+
+def _snapshot_test(path: str) -> Optional[str]:
+    """The test file a Jest snapshot belongs to."""
+    posix = PurePosixPath(path)
+    if posix.parent.name != "__snapshots__" or posix.suffix != ".snap":
+        return None
+    return (posix.parent.parent / posix.stem).as_posix()
+
+
+def _size(path: Path) -> int:
+    try:
+        return path.stat().st_size
+    except OSError:
+        return 0
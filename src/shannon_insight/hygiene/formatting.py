@@ -0,0 +1,781 @@
+"""Files that drift from their language's canonical formatter.
+
+The formatters are not run: each file is checked against the rules they
+enforce, and every line a reformat would change is counted once.
+
+    go          gofmt: tab indentation, ``) {`` and ``} else {`` spacing,
+                ``if (`` after keywords, no trailing semicolons, no
+                consecutive blank lines
+    python      black: 4-space indentation (hanging indents too), double
+                quotes, ``# comment`` spacing, spaces around assignment and
+                comparison and after commas, none around keyword arguments
+                or inside brackets, two blank lines around top-level
+                definitions and one before methods, lines within
+                line-length where they can be split
+    js / ts     prettier: 2-space indentation, double quotes, semicolons,
+                ``if (`` and ``) {`` spacing, trailing commas in multi-line
+                arrays, objects and argument lists, lines within printWidth
+                where they can be split, no consecutive blank lines
+
+Every language: no trailing whitespace, one final newline, and (gofmt and
+prettier) LF line endings. Lines under ``# fmt: off`` / ``# fmt: skip`` and
+after ``// prettier-ignore`` are left alone.
+
+The options a project gives its formatter are honoured: ``line-length`` and
+``skip-string-normalization`` in pyproject.toml ``[tool.black]`` (or
+``line-length`` and ``format.quote-style`` in ``[tool.ruff]``), and
+``printWidth``, ``tabWidth``, ``useTabs``, ``semi``, ``singleQuote`` and
+``trailingComma`` in .prettierrc(.json) or package.json's ``prettier`` key.
+Counts are recorded with each snapshot (``format_drift_files``) so drift can
+be tracked over time.
+"""
+
+from __future__ import annotations
+
+import io
+import json
+import keyword
+import re
+import tokenize
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import Any, Optional
+
+from .sources import SourceSet
+
+FORMATTERS = {
+    "go": "gofmt",
+    "python": "black",
+    "javascript": "prettier",
+    "typescript": "prettier",
+    "tsx": "prettier",
+}
+
+# Minified bundles have lines no formatter would leave
+_BUNDLE_LINE_LENGTH = 1000
+_JS_SUFFIXES = frozenset({".js", ".jsx", ".mjs", ".cjs", ".ts", ".tsx", ".mts", ".cts"})
+_PY_SUFFIXES = frozenset({".py"})
+
+_PY_DEF_RE = re.compile(r"^(?:async\s+def|def|class)\b|^@")
+_PY_FMT_OFF_RE = re.compile(r"^\s*#\s*fmt:\s*off\b")
+_PY_FMT_ON_RE = re.compile(r"^\s*#\s*fmt:\s*on\b")
+_PY_FMT_SKIP_RE = re.compile(r"#\s*fmt:\s*skip\s*$")
+_PY_CODE_TOKENS = frozenset({tokenize.NAME, tokenize.NUMBER, tokenize.OP, tokenize.STRING})
+_PY_SPACED_OPS = frozenset("== != <= >= += -= *= /= //= %= |= &= ^= >>= <<= **= @= -> :=".split())
+# Soft keywords, which may be followed by " ("
+_PY_SOFT_KEYWORDS = frozenset({"match", "case", "type", "_"})
+
+_C_BRACE_SPACING_RE = re.compile(r"\)\{|\}else\b|\belse\{")
+_C_KEYWORD_PAREN_RE = re.compile(r"(?<![\w$.])(?:if|for|while|switch|catch)\(")
+_GO_FUNC_TYPE_RE = re.compile(r"\]\s*(?:func\s*)?\(")
+_GO_KEYWORD_PAREN_RE = re.compile(r"(?<![\w.])(?:if|for|switch|return)\(")
+_JS_STATEMENT_RE = re.compile(
+    r"^\s*(?:const|let|var|return|throw|break|continue|import|"
+    r"export\s+(?:const|let|var|default|\*|\{)|export\s+type\s+\w+\s*=)\b|^\s*export\s*\{"
+)
+_JS_VALUE_END_RE = re.compile(r"[\w$)\]\"'`]$")
+_JS_CONTINUES_RE = re.compile(r"^\s*(?:[?:.+\-*/%&|^<>=,]|&&|\|\||\?\?|instanceof\b|in\b)")
+_JS_CALL_RE = re.compile(r"[\w$)\]>]$")
+_JS_CONTROL_RE = re.compile(r"\b(?:if|for|while|switch|catch|return|typeof|await|yield)$")
+# Keywords a "{" after which opens an object literal, import list or pattern
+_JS_LIST_KEYWORD_RE = re.compile(r"(?:^|[^\w$])(?:return|default|import|export|const|let|var)$")
+
+
+@dataclass(frozen=True)
+class FormatStyle:
+    """Formatter options that change the rules (defaults: black and prettier's)."""
+
+    python_line_length: int = 88
+    python_quote: Optional[str] = '"'  # None: quotes are left as written
+    js_print_width: int = 80
+    js_tab_width: int = 2
+    js_use_tabs: bool = False
+    js_semi: bool = True
+    js_quote: str = '"'
+    js_trailing_comma: str = "all"  # "all", "es5" or "none"
+
+
+@dataclass
+class FileDrift:
+    """Lines of one file a reformat would change, and the rule for each."""
+
+    path: str
+    language: str
+    lines: int
+    issues: dict[int, str] = field(default_factory=dict)  # line -> first rule broken
+
+    @property
+    def formatter(self) -> str:
+        return FORMATTERS[self.language]
+
+    @property
+    def drift_lines(self) -> int:
+        return len(self.issues)
+
+    @property
+    def ratio(self) -> float:
+        return self.drift_lines / self.lines if self.lines else 0.0
+
+    def rules(self) -> dict[str, int]:
+        """Lines per rule, most first."""
+        counts: dict[str, int] = {}
+        for rule in self.issues.values():
+            counts[rule] = counts.get(rule, 0) + 1
+        return dict(sorted(counts.items(), key=lambda kv: (-kv[1], kv[0])))
+
+    def to_dict(self) -> dict:
+        return {
+            "path": self.path,
+            "language": self.language,
+            "formatter": self.formatter,
+            "lines": self.lines,
+            "drift_lines": self.drift_lines,
+            "ratio": round(self.ratio, 3),
+            "rules": self.rules(),
+            "first_lines": sorted(self.issues)[:10],
+        }
+
+
+@dataclass
+class FormatReport:
+    """Formatter drift across every checked file."""
+
+    files: list[FileDrift]  # every file checked, most drifted lines first
+
+    def drifted(self) -> list[FileDrift]:
+        return [f for f in self.files if f.issues]
+
+    @property
+    def drift_lines(self) -> int:
+        return sum(f.drift_lines for f in self.files)
+
+    def by_language(self) -> dict[str, dict[str, Any]]:
+        """Files checked, files drifted and drifted lines per language."""
+        stats: dict[str, dict[str, Any]] = {}
+        for f in self.files:
+            entry = stats.setdefault(
+                f.language,
+                {"formatter": f.formatter, "files": 0, "drifted": 0, "lines": 0, "drift_lines": 0},
+            )
+            entry["files"] += 1
+            entry["drifted"] += bool(f.issues)
+            entry["lines"] += f.lines
+            entry["drift_lines"] += f.drift_lines
+        return dict(sorted(stats.items(), key=lambda kv: (-kv[1]["drift_lines"], kv[0])))
+
+    def by_rule(self) -> dict[str, int]:
+        counts: dict[str, int] = {}
+        for f in self.files:
+            for rule, n in f.rules().items():
+                counts[rule] = counts.get(rule, 0) + n
+        return dict(sorted(counts.items(), key=lambda kv: (-kv[1], kv[0])))
+
+    def by_package(self) -> dict[str, int]:
+        """Drifted lines per package, most first."""
+        counts: dict[str, int] = {}
+        for f in self.drifted():
+            package = SourceSet.package_of(f.path)
+            counts[package] = counts.get(package, 0) + f.drift_lines
+        return dict(sorted(counts.items(), key=lambda kv: (-kv[1], kv[0])))
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        global_signals = {
+            "format_drift_files": float(len(self.drifted())),
+            "format_drift_lines": float(self.drift_lines),
+        }
+        package_signals = {
+            package: {"format_drift_lines": float(n)} for package, n in self.by_package().items()
+        }
+        return global_signals, package_signals
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        drifted = self.drifted()
+        return {
+            "files": len(self.files),
+            "drifted": len(drifted),
+            "drift_lines": self.drift_lines,
+            "by_language": self.by_language(),
+            "by_rule": self.by_rule(),
+            "by_package": self.by_package(),
+            "drifted_files": [f.to_dict() for f in drifted[:top]],
+        }
+
+
+# ── Formatter options ──────────────────────────────────────────────
+
+
+def load_style(root: Path) -> FormatStyle:
+    """The formatter options configured under *root*."""
+    options: dict[str, Any] = {}
+
+    pyproject = _read_toml(root / "pyproject.toml").get("tool", {})
+    black, ruff = pyproject.get("black"), pyproject.get("ruff")
+    if isinstance(black, dict):
+        options["python_line_length"] = black.get("line-length", 88)
+        if black.get("skip-string-normalization"):
+            options["python_quote"] = None
+    elif isinstance(ruff, dict):
+        options["python_line_length"] = ruff.get("line-length", 88)
+        quote_style = ruff.get("format", {}).get("quote-style", "double")
+        options["python_quote"] = {"double": '"', "single": "'"}.get(quote_style)
+
+    prettier = _prettier_options(root)
+    for key, option in (
+        ("printWidth", "js_print_width"),
+        ("tabWidth", "js_tab_width"),
+        ("useTabs", "js_use_tabs"),
+        ("semi", "js_semi"),
+        ("trailingComma", "js_trailing_comma"),
+    ):
+        if key in prettier:
+            options[option] = prettier[key]
+    if prettier.get("singleQuote"):
+        options["js_quote"] = "'"
+
+    defaults = FormatStyle()
+    checked = {
+        k: v for k, v in options.items() if v is None or type(v) is type(getattr(defaults, k))
+    }
+    return FormatStyle(**checked)
+
+
+def _read_toml(path: Path) -> dict:
+    try:
+        import tomllib
+    except ImportError:
+        try:
+            import tomli as tomllib  # noqa: F811
+        except ImportError:
+            return {}
+    try:
+        with open(path, "rb") as f:
+            return tomllib.load(f)
+    except (OSError, ValueError):
+        return {}
+
+
+def _prettier_options(root: Path) -> dict:
+    for name in (".prettierrc", ".prettierrc.json"):
+        try:
+            text = (root / name).read_text(encoding="utf-8")
+        except OSError:
+            continue
+        try:
+            data = json.loads(text)
+        except ValueError:
+            data = _simple_yaml(text)
+        return data if isinstance(data, dict) else {}
+    try:
+        package = json.loads((root / "package.json").read_text(encoding="utf-8"))
+    except (OSError, ValueError):
+        return {}
+    options = package.get("prettier") if isinstance(package, dict) else None
+    return options if isinstance(options, dict) else {}
+
+
+def _simple_yaml(text: str) -> dict:
+    """Top-level ``key: value`` pairs of a YAML .prettierrc."""
+    options: dict[str, Any] = {}
+    for line in text.splitlines():
+        match = re.match(r"^(\w+)\s*:\s*(.+?)\s*$", line)
+        if match:
+            try:
+                options[match[1]] = json.loads(match[2])
+            except ValueError:
+                options[match[1]] = match[2].strip("'\"")
+    return options
+
+
+# ── Checks ─────────────────────────────────────────────────────────
+
+
+def analyze_formatting(sources: SourceSet, style: Optional[FormatStyle] = None) -> FormatReport:
+    """Check every Go, Python, JavaScript and TypeScript file in *sources*.
+
+    Args:
+        sources: Parsed sources
+        style: Formatter options (default: those configured under the root)
+    """
+    style = style if style is not None else load_style(sources.root)
+    files = []
+    for path in sorted(sources.content):
+        language = sources.language_of(path)
+        content = sources.content[path]
+        if _is_formatted(path, language, content):
+            files.append(check_file(path, language, content, style))
+    files.sort(key=lambda f: (-f.drift_lines, f.path))
+    return FormatReport(files)
+
+
+def _is_formatted(path: str, language: str, content: str) -> bool:
+    """Whether *path* is source its formatter would be run on (not a bundle)."""
+    suffix = PurePosixPath(path).suffix.lower()
+    if language == "python":
+        return suffix in _PY_SUFFIXES
+    if language in ("javascript", "typescript", "tsx"):
+        return suffix in _JS_SUFFIXES and not any(
+            len(line) > _BUNDLE_LINE_LENGTH for line in content.splitlines()
+        )
+    return language == "go"
+
+
+def check_file(path: str, language: str, content: str, style: FormatStyle) -> FileDrift:
+    """The lines of *content* its formatter would change."""
+    lines = content.split("\n")
+    if lines and lines[-1] == "":
+        lines.pop()
+    drift = FileDrift(path, language, len(lines))
+    if language == "python":
+        found = _check_python(content, lines, style)
+    else:
+        parsed = _c_like_lines(lines, raw_backticks=language == "go")
+        found = _check_go(parsed) if language == "go" else _check_js(parsed, style, path)
+        # Raw strings and template literals keep their whitespace
+        verbatim = {line.number for line in parsed if line.continued}
+        found += [
+            (n, rule)
+            for n, rule in _check_common(content, lines, language)
+            if not (rule == "trailing-space" and n in verbatim)
+        ]
+    for line, rule in sorted(found, key=lambda issue: issue[0]):
+        drift.issues.setdefault(line, rule)
+    return drift
+
+
+def _check_common(content: str, lines: list[str], language: str) -> list[tuple[int, str]]:
+    issues = []
+    for n, line in enumerate(lines, 1):
+        if line.endswith("\r"):
+            if language != "python":
+                issues.append((n, "line-endings"))
+            line = line[:-1]
+        if line != line.rstrip(" \t"):
+            issues.append((n, "trailing-space"))
+    if content and (not content.endswith("\n") or content.endswith(("\n\n", "\n\r\n"))):
+        issues.append((len(lines), "final-newline"))
+    return issues
+
+
+@dataclass
+class _Line:
+    """A C-like source line with string contents blanked and comments removed."""
+
+    number: int
+    text: str
+    code: str  # "" for lines inside a block comment or multi-line literal
+    strings: list[tuple[str, str]]  # (quote, body) of literals opening on this line
+    continued: bool  # starts inside a block comment or multi-line literal
+
+
+def _c_like_lines(lines: list[str], raw_backticks: bool = False) -> list[_Line]:
+    """Lex *lines* enough to tell code from comments and string literals.
+
+    Backslashes escape nothing in backtick literals with *raw_backticks* (Go).
+    """
+    out = []
+    block = False  # inside /* */
+    template = False  # inside a multi-line backtick literal
+    for number, text in enumerate(lines, 1):
+        text = text.rstrip("\r")
+        continued = block or template
+        code: list[str] = []
+        strings: list[tuple[str, str]] = []
+        i, n = 0, len(text)
+        while i < n:
+            if block:
+                end = text.find("*/", i)
+                block = end < 0
+                i = n if block else end + 2
+                continue
+            if template:
+                end = _closing(text, i, "`", escapes=not raw_backticks)
+                template = end >= n
+                i = end + 1
+                if not template:
+                    code.append("``")
+                continue
+            ch = text[i]
+            if text.startswith("//", i):
+                break
+            if text.startswith("/*", i):
+                block = True
+                i += 2
+                continue
+            # An apostrophe inside a word (JSX text, "don't") opens no string
+            if ch == "`" or ch == '"' or (ch == "'" and not (i and text[i - 1].isalnum())):
+                end = _closing(text, i + 1, ch, escapes=ch != "`" or not raw_backticks)
+                if ch == "`" and end >= n:
+                    template = True
+                    i = n
+                    continue
+                strings.append((ch, text[i + 1 : end]))
+                code.append(ch + ch)
+                i = end + 1
+                continue
+            code.append(ch)
+            i += 1
+        out.append(_Line(number, text, "".join(code).rstrip(), strings, continued))
+    return out
+
+
+def _closing(text: str, start: int, quote: str, escapes: bool = True) -> int:
+    """Index of the quote closing a literal opened before *start* (len(text) if open)."""
+    i = start
+    while i < len(text):
+        if escapes and text[i] == "\\":
+            i += 2
+        elif text[i] == quote:
+            return i
+        else:
+            i += 1
+    return len(text)
+
+
+def _leading(text: str) -> str:
+    return text[: len(text) - len(text.lstrip(" \t"))]
+
+
+def _check_go(parsed: list[_Line]) -> list[tuple[int, str]]:
+    issues = []
+    blank = 0
+    for line in parsed:
+        if not line.text.strip():
+            blank += 0 if line.continued else 1
+            continue
+        if blank > 1:
+            issues.append((line.number, "blank-lines"))
+        blank = 0
+        if line.continued:
+            continue
+        if " " in _leading(line.text) and not line.text.lstrip().startswith("*"):
+            issues.append((line.number, "indent"))
+        spacing = _C_BRACE_SPACING_RE.search(line.code)
+        # map[K]func(...){ and [](chan T){ open composite literals, unspaced
+        if spacing and spacing[0] == "){" and _GO_FUNC_TYPE_RE.search(line.code):
+            spacing = None
+        if spacing or _GO_KEYWORD_PAREN_RE.search(line.code):
+            issues.append((line.number, "spacing"))
+        # A lone ";" is an empty statement (after a label), which gofmt keeps
+        if line.code.endswith(";") and line.code.strip() != ";":
+            issues.append((line.number, "semicolon"))
+    return issues
+
+
+def _check_js(parsed: list[_Line], style: FormatStyle, path: str) -> list[tuple[int, str]]:
+    issues = []
+    jsx = path.endswith(("x", "X"))
+    other_quote = "'" if style.js_quote == '"' else '"'
+    # Open brackets: (kind, line) with kind "list" (array, object, call) or "group"
+    stack: list[tuple[str, int]] = []
+    previous: Optional[_Line] = None  # last line with code
+    ignore_next = False
+    blank = 0
+    for index, line in enumerate(parsed):
+        stripped = line.text.strip()
+        if not stripped:
+            blank += 0 if line.continued else 1
+            continue
+        if blank > 1:
+            issues.append((line.number, "blank-lines"))
+        blank = 0
+        if stripped.startswith("//"):
+            ignore_next = ignore_next or "prettier-ignore" in stripped
+            continue
+        # Lines starting inside a comment or template literal only close brackets
+        code = line.code.strip()
+        ignored, ignore_next = ignore_next or line.continued, False
+
+        if not ignored:
+            indent = _leading(line.text)
+            if not code and stripped.startswith("*"):
+                pass  # inside a JSDoc block
+            elif style.js_use_tabs and " " in indent.lstrip("\t"):
+                issues.append((line.number, "indent"))
+            elif not style.js_use_tabs and ("\t" in indent or len(indent) % style.js_tab_width):
+                issues.append((line.number, "indent"))
+            for quote, body in line.strings:
+                if quote == other_quote and style.js_quote not in body:
+                    # JSX attributes keep double quotes whatever singleQuote says
+                    if not (jsx and quote == '"' and f'="{body}"' in line.text):
+                        issues.append((line.number, "quotes"))
+                        break
+            if _C_BRACE_SPACING_RE.search(code) or _C_KEYWORD_PAREN_RE.search(code):
+                issues.append((line.number, "spacing"))
+            if style.js_semi:
+                if (
+                    _JS_STATEMENT_RE.match(code)
+                    and _JS_VALUE_END_RE.search(code)
+                    and not _continues(parsed, index)
+                ):
+                    issues.append((line.number, "semicolon"))
+            elif code.endswith(";") and not code.startswith("for"):
+                issues.append((line.number, "semicolon"))
+            # Template literals are printed as written
+            if (
+                len(line.text) > style.js_print_width
+                and re.search(r"[(\[{,]", code)
+                and "`" not in line.text
+            ):
+                issues.append((line.number, "line-length"))
+
+        # A line closing a multi-line list: the line before it takes a trailing comma
+        if code[:1] in ")]}" and stack and previous is not None:
+            kind, opened = stack[-1]
+            wants = style.js_trailing_comma == "all" or (
+                style.js_trailing_comma == "es5" and code[0] != ")"
+            )
+            last = previous.code.strip()
+            # A rest element (...args) never takes one
+            if kind == "list" and opened < previous.number and not last.startswith("..."):
+                if wants != last.endswith(",") and not last.endswith((";", "{", "(", "[")):
+                    issues.append((previous.number, "trailing-comma"))
+        for i, ch in enumerate(code):
+            if ch in "([{":
+                # Only a bracket ending its line puts each element on a line of its own
+                kind = _bracket_kind(code[:i], ch) if i == len(code) - 1 else "group"
+                stack.append((kind, line.number))
+            elif ch in ")]}" and stack:
+                stack.pop()
+        if code:
+            previous = line
+    return issues
+
+
+def _bracket_kind(before: str, bracket: str) -> str:
+    """"list" for an array, object literal or call argument list, else "group"."""
+    before = before.rstrip()
+    if bracket == "(":
+        if before.endswith("=>") or _JS_CONTROL_RE.search(before):
+            return "group"
+        return "list" if _JS_CALL_RE.search(before) else "group"
+    if bracket == "{":
+        # A block follows ")", "=>", "else", "try", a class or function head
+        if before.endswith((")", "=>", "else", "try", "finally", "do")) or re.search(
+            r"\b(?:class|interface|enum|namespace|function)\b[^=]*$", before
+        ):
+            return "group"
+        if re.search(r"[\w$]=$", before):
+            return "group"  # a JSX attribute: value={...}
+        if not before or before[-1] in "=(:,[?" or _JS_LIST_KEYWORD_RE.search(before):
+            return "list"
+        return "group"
+    return "list"
+
+
+def _continues(parsed: list[_Line], index: int) -> bool:
+    """Whether the statement on parsed[index] continues on the next code line."""
+    for line in parsed[index + 1 :]:
+        if line.code.strip():
+            return bool(_JS_CONTINUES_RE.match(line.code))
+    return False
+
+
+def _check_python(content: str, lines: list[str], style: FormatStyle) -> list[tuple[int, str]]:
+    issues = _check_common(content, lines, "python")
+    try:
+        tokens = list(tokenize.generate_tokens(io.StringIO(content).readline))
+    except (tokenize.TokenError, SyntaxError):
+        return issues
+    skipped = _fmt_skipped(lines)
+
+    string_rows: set[int] = set()  # rows inside (not opening) a multi-line string
+    statement_rows: set[int] = set()  # rows a logical line starts on
+    significant = []
+    indents = 0
+    fstring_start = getattr(tokenize, "FSTRING_START", None)
+    fstring_end = getattr(tokenize, "FSTRING_END", None)
+    fstring: Optional[tokenize.TokenInfo] = None
+    at_start = True
+    for tok in tokens:
+        kind, text, (row, col), (end_row, end_col), _ = tok
+        if kind == tokenize.NEWLINE:
+            at_start = True
+        elif at_start and kind in _PY_CODE_TOKENS | {fstring_start}:
+            statement_rows.add(row)
+            at_start = False
+        if fstring is not None:
+            if kind == fstring_end:
+                if end_row == fstring.start[0]:
+                    literal = lines[row - 1][fstring.start[1] : end_col]
+                    if _wants_requote(literal, style.python_quote):
+                        issues.append((row, "quotes"))
+                string_rows.update(range(fstring.start[0] + 1, end_row + 1))
+                # The whole f-string as one token, for spacing around it
+                significant.append(fstring._replace(type=tokenize.STRING, end=(end_row, end_col)))
+                fstring = None
+            continue
+        if kind == fstring_start:
+            fstring = tok
+        elif kind == tokenize.INDENT:
+            indents += 1
+            if "\t" in text or len(text) != 4 * indents:
+                issues.append((row, "indent"))
+        elif kind == tokenize.DEDENT:
+            indents -= 1
+        elif kind == tokenize.COMMENT:
+            if text != "#" and not text.startswith(("# ", "#!", "#:")):
+                issues.append((row, "comment"))
+        elif kind == tokenize.STRING:
+            string_rows.update(range(row + 1, end_row + 1))
+            if _wants_requote(text, style.python_quote):
+                issues.append((row, "quotes"))
+            significant.append(tok)
+        elif kind in _PY_CODE_TOKENS:
+            significant.append(tok)
+
+    # Rows black could break: an open bracket, or a comma with more after it
+    splittable = {
+        tok.start[0]
+        for i, tok in enumerate(significant)
+        if tok.type == tokenize.OP
+        and (
+            tok.string in ("(", "[", "{")
+            or tok.string == ","
+            and i + 1 < len(significant)
+            and significant[i + 1].start[0] == tok.start[0]
+        )
+    }
+    issues += _python_spacing(significant)
+    issues += _python_blank_lines(lines, string_rows, statement_rows)
+    for n, line in enumerate(lines, 1):
+        if n in splittable and len(line) > style.python_line_length:
+            issues.append((n, "line-length"))
+    return [
+        (n, rule)
+        for n, rule in issues
+        if n not in skipped and not (rule == "trailing-space" and n in string_rows)
+    ]
+
+
+def _wants_requote(literal: str, quote: Optional[str]) -> bool:
+    """Whether black would change the quotes of *literal* to *quote*."""
+    if quote is None:
+        return False
+    body = literal.lstrip("rRbBuUfF")
+    if body.startswith(quote):
+        return False
+    other = body[:3] if body[:3] in ("'''", '"""') else body[:1]
+    return quote not in body[len(other) : len(body) - len(other)]
+
+
+def _python_spacing(significant: list[tokenize.TokenInfo]) -> list[tuple[int, str]]:
+    issues = []
+    # Open brackets: [bracket, whether a ":" annotation was seen since the last ","]
+    stack: list[list[Any]] = []
+    for i, tok in enumerate(significant):
+        kind, text, (row, col), (_, end_col), _ = tok
+        prev = significant[i - 1] if i else None
+        nxt = significant[i + 1] if i + 1 < len(significant) else None
+        same_prev = prev is not None and prev.end[0] == row
+        space_before = same_prev and prev.end[1] < col  # type: ignore[union-attr]
+        tight_before = same_prev and prev.end[1] == col  # type: ignore[union-attr]
+        same_next = nxt is not None and nxt.start[0] == row
+        space_after = same_next and nxt.start[1] > end_col  # type: ignore[union-attr]
+        tight_after = same_next and nxt.start[1] == end_col  # type: ignore[union-attr]
+
+        # Hanging indents inside brackets are multiples of four
+        if stack and not same_prev and col % 4:
+            issues.append((row, "indent"))
+        if kind != tokenize.OP:
+            continue
+        bad = False
+        if text in "([{":
+            if (
+                text in "(["
+                and space_before
+                and prev.type == tokenize.NAME  # type: ignore[union-attr]
+                and not keyword.iskeyword(prev.string)  # type: ignore[union-attr]
+                and prev.string not in _PY_SOFT_KEYWORDS  # type: ignore[union-attr]
+            ):
+                bad = True
+            bad = bad or space_after
+            stack.append([text, False])
+        elif text in ")]}":
+            bad = space_before
+            if stack:
+                stack.pop()
+        elif text == ",":
+            closes = same_next and nxt.string in ")]}"  # type: ignore[union-attr]
+            bad = space_before or (tight_after and not closes)
+            if stack:
+                stack[-1][1] = False
+        elif text == ":":
+            if stack and stack[-1][0] == "(":
+                stack[-1][1] = True
+        elif text == "=":
+            keyword_argument = bool(stack) and stack[-1][0] == "(" and not stack[-1][1]
+            if keyword_argument:
+                bad = space_before or space_after
+            else:
+                bad = tight_before or tight_after
+        elif text in _PY_SPACED_OPS:
+            bad = tight_before or tight_after
+        if bad:
+            issues.append((row, "spacing"))
+    return issues
+
+
+def _python_blank_lines(
+    lines: list[str], string_rows: set[int], statement_rows: set[int]
+) -> list[tuple[int, str]]:
+    issues = []
+    blank = 0
+    previous: Optional[str] = None  # last non-blank line
+    statement = ""  # the last statement (its first line), stripped
+    in_top_definition = False  # the last top-level statement was a def or class
+    for n, line in enumerate(lines, 1):
+        stripped = line.strip()
+        if n in string_rows or (stripped and n not in statement_rows and stripped[0] != "#"):
+            # Inside a string, or a statement continued from an earlier line
+            blank = 0
+            previous = line
+            continue
+        if not stripped:
+            blank += 1
+            continue
+        indent = len(line) - len(line.lstrip())
+        is_comment = stripped.startswith("#")
+        is_definition = bool(_PY_DEF_RE.match(stripped))
+        if blank > 2 or (blank > 1 and indent):
+            issues.append((n, "blank-lines"))
+        elif previous is not None and not is_comment:
+            # Decorators and comments carry the blank lines of what follows them
+            attached = previous.lstrip().startswith("#") or (
+                blank == 0 and statement.startswith("@")
+            )
+            stub = previous.rstrip().endswith("...")
+            if indent == 0 and (is_definition or in_top_definition) and not attached:
+                if blank != 2 and not (stub and blank == 0):
+                    issues.append((n, "blank-lines"))
+            elif (
+                indent
+                and is_definition
+                and blank == 0
+                and not attached
+                and not stub
+                and len(previous) - len(previous.lstrip()) >= indent
+                and not previous.rstrip().endswith((":", '"""', "'''"))
+            ):
+                issues.append((n, "blank-lines"))
+        if not is_comment:
+            statement = stripped
+            if indent == 0:
+                in_top_definition = is_definition
+        blank = 0
+        previous = line
+    return issues
+
+
+def _fmt_skipped(lines: list[str]) -> set[int]:
+    """Lines black leaves alone: between ``# fmt: off`` and ``# fmt: on``, or ``# fmt: skip``."""
+    skipped = set()
+    off = False
+    for n, line in enumerate(lines, 1):
+        if _PY_FMT_OFF_RE.match(line):
+            off = True
+        elif _PY_FMT_ON_RE.match(line):
+            off = False
+        if off or _PY_FMT_SKIP_RE.search(line):
+            skipped.add(n)
+    return skipped
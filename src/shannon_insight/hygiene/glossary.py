@@ -0,0 +1,205 @@
+"""Domain vocabulary glossary and synonym inconsistencies.
+
+Identifiers are split into words (camelCase/snake_case aware), counted, and
+filtered against generic programming vocabulary to leave the domain terms.
+Words are then clustered under a canonical term:
+
+    abbreviations   org -> organization, cfg -> config, repo -> repository, ...
+    spelling        organisation -> organization, colour -> color, analyse -> analyze
+
+A cluster only matters when more than one variant is in use; the report lists
+packages where several variants of the same term appear side by side, which is
+where a ubiquitous language is actually broken.
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass, field
+
+from ..signals.complexity import is_comment_line
+from .naming import split_words
+from .sources import SourceSet
+
+# Abbreviation -> canonical term
+ABBREVIATIONS: dict[str, str] = {
+    "addr": "address",
+    "admin": "administrator",
+    "app": "application",
+    "arg": "argument",
+    "attr": "attribute",
+    "auth": "authentication",
+    "btn": "button",
+    "calc": "calculate",
+    "cfg": "config",
+    "conf": "config",
+    "configuration": "config",
+    "cnt": "count",
+    "db": "database",
+    "dest": "destination",
+    "dst": "destination",
+    "dir": "directory",
+    "doc": "document",
+    "env": "environment",
+    "idx": "index",
+    "img": "image",
+    "info": "information",
+    "mgr": "manager",
+    "msg": "message",
+    "org": "organization",
+    "orgs": "organizations",
+    "param": "parameter",
+    "params": "parameters",
+    "passwd": "password",
+    "prev": "previous",
+    "pwd": "password",
+    "repo": "repository",
+    "req": "request",
+    "resp": "response",
+    "spec": "specification",
+    "src": "source",
+    "svc": "service",
+    "temp": "temporary",
+    "tmp": "temporary",
+    "usr": "user",
+    "util": "utility",
+    "utils": "utilities",
+}
+
+# British -> American suffixes; only matters when both spellings occur
+_SPELLING_SUFFIXES = (
+    ("isation", "ization"),
+    ("isations", "izations"),
+    ("ising", "izing"),
+    ("ised", "ized"),
+    ("iser", "izer"),
+    ("ise", "ize"),
+    ("yse", "yze"),
+    ("ysing", "yzing"),
+    ("ysed", "yzed"),
+    ("our", "or"),
+    ("ours", "ors"),
+    ("ogue", "og"),
+    ("tre", "ter"),
+)
+
+# Generic programming vocabulary that says nothing about the domain
+COMMON_WORDS = frozenset(
+    """
+    add all and any args async at await bool break by byte can case catch char class const
+    continue create def default defer del delete dict do elif else enum err error except export
+    extends false final finally find float fn for from func function get go handle has if impl
+    import in init int interface into is item items key keys len let list load log main make map
+    mut new nil none not null num obj object of on or out pass print printf println pub public
+    raise remove return run save self set static str string struct super switch test the this
+    throw to true try type update use val value values var void while with yield
+    """.split()
+)
+
+_IDENT_RE = re.compile(r"[A-Za-z_][A-Za-z0-9_]*")
+# String literals (double, single, backtick); contents are not identifiers
+STRING_RE = re.compile(r'"(?:\\.|[^"\\])*"|\'(?:\\.|[^\'\\])*\'|`[^`]*`')
+
+
+def canonical_term(word: str) -> str:
+    """Canonical cluster key for a lower-case word."""
+    if word in ABBREVIATIONS:
+        return ABBREVIATIONS[word]
+    for british, american in _SPELLING_SUFFIXES:
+        if word.endswith(british) and len(word) > len(british) + 2:
+            return word[: -len(british)] + american
+    return word
+
+
+def identifier_words(line: str) -> list[str]:
+    """Lower-case words of every identifier on a code line (strings removed)."""
+    code = STRING_RE.sub(" ", line)
+    words = []
+    for ident in _IDENT_RE.findall(code):
+        words.extend(w.lower() for w in split_words(ident))
+    return words
+
+
+@dataclass
+class TermCluster:
+    """All spellings of one canonical term used in the repo."""
+
+    canonical: str
+    variants: dict[str, int]
+
+    @property
+    def count(self) -> int:
+        return sum(self.variants.values())
+
+
+@dataclass
+class SynonymConflict:
+    """Several variants of one term used in the same package."""
+
+    package: str
+    canonical: str
+    variants: dict[str, int]
+    examples: dict[str, str]  # variant -> "path:line" of first use
+
+
+@dataclass
+class GlossaryReport:
+    terms: list[TermCluster]  # domain terms, most used first
+    conflicts: list[SynonymConflict] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {
+            "terms": [
+                {"term": t.canonical, "count": t.count, "variants": t.variants} for t in self.terms
+            ],
+            "conflicts": [c.__dict__ for c in self.conflicts],
+        }
+
+
+def analyze_glossary(sources: SourceSet, min_count: int = 2) -> GlossaryReport:
+    """Extract the domain vocabulary and find packages mixing synonyms."""
+    counts: Counter = Counter()
+    # package -> canonical -> variant -> count
+    per_package: dict[str, dict[str, Counter]] = {}
+    first_use: dict[tuple[str, str], str] = {}
+
+    for path in sorted(sources.content):
+        package = SourceSet.package_of(path)
+        for lineno, line in enumerate(sources.content[path].splitlines(), 1):
+            if is_comment_line(line):
+                continue
+            for word in identifier_words(line):
+                if len(word) < 2 or word in COMMON_WORDS or word.isdigit():
+                    continue
+                counts[word] += 1
+                canonical = canonical_term(word)
+                per_package.setdefault(package, {}).setdefault(canonical, Counter())[word] += 1
+                first_use.setdefault((package, word), f"{path}:{lineno}")
+
+    clusters: dict[str, Counter] = {}
+    for word, n in counts.items():
+        clusters.setdefault(canonical_term(word), Counter())[word] += n
+
+    terms = [
+        TermCluster(canonical, dict(variants.most_common()))
+        for canonical, variants in clusters.items()
+        if sum(variants.values()) >= min_count and len(canonical) >= 3
+    ]
+    terms.sort(key=lambda t: (-t.count, t.canonical))
+
+    conflicts = []
+    for package, by_term in sorted(per_package.items()):
+        for canonical, variants in sorted(by_term.items()):
+            if len(variants) < 2:
+                continue
+            conflicts.append(
+                SynonymConflict(
+                    package=package,
+                    canonical=canonical,
+                    variants=dict(variants.most_common()),
+                    examples={v: first_use[(package, v)] for v in sorted(variants)},
+                )
+            )
+    conflicts.sort(key=lambda c: (-sum(c.variants.values()), c.package, c.canonical))
+    return GlossaryReport(terms=terms, conflicts=conflicts)
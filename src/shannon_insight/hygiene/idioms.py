@@ -0,0 +1,274 @@
+"""Intra-repo idiom consistency.
+
+Every file contributes observations along a few idiom dimensions; each
+observation is classified into a variant (a cluster of equivalent syntactic
+patterns). The repo-dominant variant per (dimension, language) is the norm,
+and packages are ranked by the share of their observations that deviate.
+
+Dimensions:
+    error_handling    go: bare ``return err`` / ``fmt.Errorf("%w")`` wrap /
+                      ``errors.Wrap`` / sentinel ``ErrX`` / panic / log.Fatal
+                      after ``if err != nil``;
+                      python: bare / broad / specific ``except``;
+                      js/ts: try-catch vs ``.catch()``
+    receiver_naming   go: short (1-2 chars) / self_this / long / unnamed
+    constructor       go: NewX / MakeX / CreateX / BuildX factory prefixes
+    function_casing   snake_case / camelCase / PascalCase (go: mixedCaps)
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass, field
+from typing import Iterator
+
+from .sources import SourceSet
+
+DIMENSIONS = ("error_handling", "receiver_naming", "constructor", "function_casing")
+
+# A (dimension, language) needs this many observations before it has a norm
+MIN_OBSERVATIONS = 3
+
+_GO_ERR_CHECK = re.compile(r"\bif\b[^{]*\berr\s*!=\s*nil\s*\{")
+_GO_RECEIVER = re.compile(r"^func\s*\(\s*(\w+)?\s*\*?\s*\w+(?:\[[^\]]*\])?\s*\)")
+_GO_RECEIVER_TYPE_ONLY = re.compile(r"^func\s*\(\s*\*?\s*[A-Z]\w*\s*\)")
+_GO_CONSTRUCTOR = re.compile(r"^func\s+(New|Make|Create|Build)[A-Z_]\w*\s*[\[(]")
+_PY_EXCEPT = re.compile(r"^\s*except\b\s*(\(?\s*[\w.]+)?")
+_JS_TRY_CATCH = re.compile(r"\bcatch\s*[({]")
+_JS_PROMISE_CATCH = re.compile(r"\.catch\s*\(")
+
+_SNAKE = re.compile(r"^[a-z][a-z0-9]*(?:_[a-z0-9]+)+$")
+_CAMEL = re.compile(r"^[a-z][a-z0-9]*(?:[A-Z][a-z0-9]*)+$")
+_PASCAL = re.compile(r"^[A-Z][a-z0-9]+(?:[A-Z][a-z0-9]*)*$")
+
+
+@dataclass
+class Observation:
+    """One classified idiom occurrence."""
+
+    dimension: str
+    language: str
+    variant: str
+    path: str
+    line: int
+
+
+@dataclass
+class DimensionNorm:
+    """Repo-wide variant distribution for one (dimension, language)."""
+
+    dimension: str
+    language: str
+    dominant: str
+    counts: dict[str, int]
+
+    @property
+    def total(self) -> int:
+        return sum(self.counts.values())
+
+    @property
+    def consistency(self) -> float:
+        """Share of observations using the dominant variant (1.0 = uniform)."""
+        return self.counts[self.dominant] / self.total if self.total else 1.0
+
+
+@dataclass
+class Deviation:
+    """Non-dominant variant usage inside one package."""
+
+    dimension: str
+    language: str
+    variant: str
+    dominant: str
+    count: int
+    example: str  # "path:line" of the first occurrence
+
+
+@dataclass
+class PackageIdioms:
+    """Idiom divergence of one package from the repo norms."""
+
+    package: str
+    observations: int
+    divergence: float  # deviating / observations, in [0, 1]
+    deviations: list[Deviation] = field(default_factory=list)
+
+
+@dataclass
+class IdiomReport:
+    norms: list[DimensionNorm]
+    packages: list[PackageIdioms]  # most divergent first
+
+    def to_dict(self) -> dict:
+        return {
+            "norms": [
+                {
+                    "dimension": n.dimension,
+                    "language": n.language,
+                    "dominant": n.dominant,
+                    "consistency": round(n.consistency, 3),
+                    "counts": n.counts,
+                }
+                for n in self.norms
+            ],
+            "packages": [
+                {
+                    "package": p.package,
+                    "observations": p.observations,
+                    "divergence": round(p.divergence, 3),
+                    "deviations": [d.__dict__ for d in p.deviations],
+                }
+                for p in self.packages
+            ],
+        }
+
+
+def function_casing(name: str, language: str) -> str | None:
+    """Casing variant of a function name; None for ambiguous single words."""
+    name = name.strip("_")
+    if _SNAKE.match(name):
+        return "snake_case"
+    if _CAMEL.match(name) or _PASCAL.match(name):
+        if language == "go":
+            # Exported vs unexported is semantic in Go, not a style choice
+            return "mixedCaps"
+        return "camelCase" if _CAMEL.match(name) else "PascalCase"
+    return None
+
+
+def _go_error_variant(line: str) -> str | None:
+    """Variant of the statement following ``if err != nil``; None if unrecognized."""
+    if "fmt.Errorf(" in line:
+        return "wrap_fmt" if "%w" in line else "fmt_errorf"
+    if re.search(r"\berrors\.(?:Wrap|Wrapf|WithMessage)\(", line):
+        return "wrap_pkg"
+    if re.search(r"\bpanic\(", line):
+        return "panic"
+    if re.search(r"\blog\.(?:Fatal|Fatalf|Panic)\w*\(", line):
+        return "log_fatal"
+    if re.search(r"\breturn\b.*\berr\s*$", line):
+        return "return_bare"
+    if re.search(r"\breturn\b.*\bErr[A-Z]\w*\s*$", line):
+        return "sentinel"
+    return None
+
+
+def _scan_text(path: str, language: str, lines: list[str]) -> Iterator[Observation]:
+    for i, line in enumerate(lines):
+        lineno = i + 1
+        if language == "go":
+            if _GO_ERR_CHECK.search(line):
+                body = next((ln.strip() for ln in lines[i + 1 : i + 4] if ln.strip()), "")
+                variant = _go_error_variant(body)
+                if variant:
+                    yield Observation("error_handling", language, variant, path, lineno)
+            match = _GO_RECEIVER.match(line)
+            if match and match.group(1):
+                name = match.group(1)
+                if name in ("self", "this", "me"):
+                    variant = "self_this"
+                else:
+                    variant = "short" if len(name) <= 2 else "long"
+                yield Observation("receiver_naming", language, variant, path, lineno)
+            elif _GO_RECEIVER_TYPE_ONLY.match(line):
+                yield Observation("receiver_naming", language, "unnamed", path, lineno)
+            match = _GO_CONSTRUCTOR.match(line)
+            if match:
+                yield Observation("constructor", language, match.group(1), path, lineno)
+        elif language == "python":
+            match = _PY_EXCEPT.match(line)
+            if match:
+                caught = (match.group(1) or "").lstrip("( ")
+                if not caught:
+                    variant = "bare_except"
+                elif caught in ("Exception", "BaseException"):
+                    variant = "broad_except"
+                else:
+                    variant = "specific_except"
+                yield Observation("error_handling", language, variant, path, lineno)
+        elif language in ("javascript", "typescript"):
+            if _JS_PROMISE_CATCH.search(line):
+                yield Observation("error_handling", language, "promise_catch", path, lineno)
+            elif _JS_TRY_CATCH.search(line):
+                yield Observation("error_handling", language, "try_catch", path, lineno)
+
+
+def collect_observations(sources: SourceSet) -> list[Observation]:
+    """Classify every idiom occurrence in the source set."""
+    observations: list[Observation] = []
+    for path, syntax in sorted(sources.syntax.items()):
+        language = syntax.language
+        for fn in syntax.functions:
+            variant = function_casing(fn.name, language)
+            if variant:
+                observations.append(
+                    Observation("function_casing", language, variant, path, fn.start_line)
+                )
+        content = sources.content.get(path)
+        if content:
+            observations.extend(_scan_text(path, language, content.splitlines()))
+    return observations
+
+
+def compute_norms(observations: list[Observation]) -> dict[tuple[str, str], DimensionNorm]:
+    """Dominant variant per (dimension, language) with enough observations."""
+    counters: dict[tuple[str, str], Counter] = {}
+    for obs in observations:
+        counters.setdefault((obs.dimension, obs.language), Counter())[obs.variant] += 1
+
+    norms = {}
+    for key, counter in counters.items():
+        if sum(counter.values()) < MIN_OBSERVATIONS:
+            continue
+        # Ties break alphabetically so the report is deterministic
+        dominant = min(counter, key=lambda v: (-counter[v], v))
+        norms[key] = DimensionNorm(key[0], key[1], dominant, dict(sorted(counter.items())))
+    return norms
+
+
+def analyze_idioms(sources: SourceSet) -> IdiomReport:
+    """Rank packages by how far their idioms diverge from the repo norms."""
+    observations = collect_observations(sources)
+    norms = compute_norms(observations)
+
+    by_package: dict[str, list[Observation]] = {}
+    for obs in observations:
+        if (obs.dimension, obs.language) in norms:
+            by_package.setdefault(SourceSet.package_of(obs.path), []).append(obs)
+
+    packages = []
+    for package, pkg_obs in by_package.items():
+        deviating: dict[tuple[str, str, str], list[Observation]] = {}
+        for obs in pkg_obs:
+            norm = norms[(obs.dimension, obs.language)]
+            if obs.variant != norm.dominant:
+                deviating.setdefault((obs.dimension, obs.language, obs.variant), []).append(obs)
+
+        deviations = [
+            Deviation(
+                dimension=dim,
+                language=lang,
+                variant=variant,
+                dominant=norms[(dim, lang)].dominant,
+                count=len(occurrences),
+                example=f"{occurrences[0].path}:{occurrences[0].line}",
+            )
+            for (dim, lang, variant), occurrences in deviating.items()
+        ]
+        deviations.sort(key=lambda d: (-d.count, d.dimension, d.variant))
+        n_deviating = sum(d.count for d in deviations)
+        packages.append(
+            PackageIdioms(
+                package=package,
+                observations=len(pkg_obs),
+                divergence=n_deviating / len(pkg_obs),
+                deviations=deviations,
+            )
+        )
+
+    packages.sort(key=lambda p: (-p.divergence, -p.observations, p.package))
+    ordered_norms = sorted(
+        norms.values(), key=lambda n: (DIMENSIONS.index(n.dimension), n.language)
+    )
+    return IdiomReport(norms=ordered_norms, packages=packages)
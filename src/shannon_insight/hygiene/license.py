@@ -0,0 +1,175 @@
+"""License header consistency.
+
+The required header is a plain-text template (``license_header`` config) with
+``{year}`` and ``{owner}`` placeholders. A file's header is its leading
+comment block (after a shebang, ``<?php`` or Python encoding line); markers are
+stripped before comparing, so ``//``, ``#``, ``--`` and ``/* ... */`` headers
+all match. ``{year}`` accepts a year, range or list (``2019-2024``, ``2021, 2023``)
+and ``{owner}`` the configured ``license_owner`` (anything when unset).
+
+    missing    no leading comment mentions a copyright or license
+    malformed  a copyright/license comment exists but does not match
+
+``insert_header`` renders the template with the current year for ``--fix``;
+only missing headers are inserted, malformed ones are left for review.
+Jupyter notebooks are not checked: their header would sit in a JSON cell.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from typing import Optional
+
+from ..scanning.notebook import is_notebook
+from .sources import SourceSet
+
+LICENSE_STATUSES = ("missing", "malformed")
+
+# Line comment marker used when inserting a header
+_LINE_COMMENT = {
+    "python": "#",
+    "ruby": "#",
+    "php": "//",
+    "go": "//",
+    "java": "//",
+    "kotlin": "//",
+    "scala": "//",
+    "swift": "//",
+    "sql": "--",
+    "hcl": "#",
+    "yaml": "#",
+    "proto": "//",
+    "javascript": "//",
+    "typescript": "//",
+    "tsx": "//",
+    "rust": "//",
+    "c": "//",
+    "cpp": "//",
+}
+
+_YEAR_PATTERN = r"\d{4}(?:\s*[-,]\s*\d{4})*"
+_PREAMBLE_RE = re.compile(r"^(?:#!|#.*coding[:=]|//go:build|// \+build|<\?php\b)")
+_COMMENT_MARKER_RE = re.compile(r"^\s*(?:/\*+|\*+/|\*|//+|#+|--+)\s?")
+_BLOCK_END_RE = re.compile(r"\s*\*+/\s*$")
+_LICENSE_HINT_RE = re.compile(r"copyright|licen[cs]e|spdx-license-identifier", re.IGNORECASE)
+
+_MAX_HEADER_LINES = 40
+
+
+@dataclass
+class HeaderIssue:
+    """A file without the required license header."""
+
+    path: str
+    status: str  # missing | malformed
+    detail: str = ""
+
+
+@dataclass
+class LicenseReport:
+    checked: int
+    issues: list[HeaderIssue]
+
+    def by_package(self) -> dict[str, dict[str, int]]:
+        """Issue counts per package and status."""
+        packages: dict[str, dict[str, int]] = {}
+        for issue in self.issues:
+            counts = packages.setdefault(SourceSet.package_of(issue.path), {})
+            counts[issue.status] = counts.get(issue.status, 0) + 1
+        return dict(sorted(packages.items()))
+
+    def to_dict(self) -> dict:
+        return {
+            "checked": self.checked,
+            "by_package": self.by_package(),
+            "issues": [i.__dict__ for i in self.issues],
+        }
+
+
+def header_pattern(template: str, owner: str = "") -> list[re.Pattern]:
+    """One regex per non-blank template line, placeholders expanded."""
+    owner_pattern = re.escape(owner) if owner else r".+?"
+    patterns = []
+    for line in (raw.strip() for raw in template.strip("\n").splitlines()):
+        if not line:
+            continue
+        escaped = re.escape(line)
+        escaped = escaped.replace(re.escape("{year}"), _YEAR_PATTERN)
+        escaped = escaped.replace(re.escape("{owner}"), owner_pattern)
+        patterns.append(re.compile(rf"^{escaped}$"))
+    return patterns
+
+
+def leading_comment(content: str) -> list[str]:
+    """Text of the leading comment block, comment markers stripped."""
+    lines = content.splitlines()[:_MAX_HEADER_LINES]
+    start = 0
+    while start < len(lines) and (_PREAMBLE_RE.match(lines[start]) or not lines[start].strip()):
+        start += 1
+    text = []
+    in_block = False
+    for line in lines[start:]:
+        stripped = line.strip()
+        if in_block or stripped.startswith(("//", "#", "/*", "--")):
+            if stripped.startswith("/*"):
+                in_block = True
+            if "*/" in stripped:
+                in_block = False
+            text.append(_BLOCK_END_RE.sub("", _COMMENT_MARKER_RE.sub("", stripped)).strip())
+        else:
+            break
+    return text
+
+
+def check_header(content: str, patterns: list[re.Pattern]) -> Optional[HeaderIssue]:
+    """None if *content* starts with the header, else the issue (path unset)."""
+    body = [line for line in leading_comment(content) if line]
+    if patterns and len(body) >= len(patterns):
+        if all(p.match(line) for p, line in zip(patterns, body)):
+            return None
+    hinted = next((line for line in body if _LICENSE_HINT_RE.search(line)), None)
+    if hinted is None:
+        return HeaderIssue(path="", status="missing")
+    return HeaderIssue(path="", status="malformed", detail=hinted)
+
+
+def render_header(template: str, language: str, owner: str, year: int) -> Optional[str]:
+    """Template as a comment block for *language*; None if the language is unknown."""
+    marker = _LINE_COMMENT.get(language)
+    if marker is None:
+        return None
+    text = template.strip("\n").replace("{year}", str(year)).replace("{owner}", owner)
+    lines = [f"{marker} {line}".rstrip() for line in text.splitlines()]
+    return "\n".join(lines) + "\n"
+
+
+def insert_header(content: str, header: str) -> str:
+    """*content* with *header* inserted after any shebang/encoding/build lines."""
+    lines = content.splitlines(keepends=True)
+    index = 0
+    while index < len(lines) and _PREAMBLE_RE.match(lines[index]):
+        index += 1
+    preamble = "".join(lines[:index])
+    rest = "".join(lines[index:]).lstrip("\n")
+    if preamble:
+        return f"{preamble}\n{header}\n{rest}"
+    return f"{header}\n{rest}"
+
+
+def analyze_license(sources: SourceSet, template: str, owner: str = "") -> LicenseReport:
+    """Check every source file for the license header."""
+    patterns = header_pattern(template, owner)
+    issues = []
+    checked = 0
+    for path in sorted(sources.content):
+        content = sources.content[path]
+        # A notebook's content is its extracted code; the file itself is JSON
+        if not content.strip() or is_notebook(path):
+            continue
+        checked += 1
+        issue = check_header(content, patterns)
+        if issue is not None:
+            issue.path = path
+            issues.append(issue)
+    return LicenseReport(checked=checked, issues=issues)
@@ -0,0 +1,354 @@
+"""Magic numbers and repeated literals.
+
+Every non-test Python and C-like source file (Go, JavaScript, TypeScript,
+Java, Kotlin, Scala, Swift, Rust, C, C++) is lexed for the literals in its
+code, comments and docstrings left out:
+
+    magic number    a numeric literal other than 0, 1 and 2 (``3600``,
+                    ``0x1F``, ``0.75``)
+    string literal  a string of two or more characters (``"user_id"``)
+
+Literals that give a value its name are not counted: constant declarations
+(``const``, ``static final``, ``#define``, upper-case module-level names in
+Python), and for strings also import paths, Go struct tags and raw strings,
+and template literals with substitutions.
+
+Per file the report counts magic numbers (and their density per 100 code
+lines), string literals, and the string literals that already appeared
+earlier in the same file. Across files, a literal used more than
+``literal_repeat_threshold`` times is reported as a ``repeated_literal``
+finding: a hard-coded context key or expiry in several handlers changes in
+several places, and a missed one fails quietly.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import io
+import math
+import re
+import tokenize
+from collections import Counter
+from dataclasses import dataclass, field
+
+from ..semantics.roles import TEST_PATH_PATTERNS
+from .formatting import _c_like_lines
+from .sources import SourceSet
+
+REPEATED_LITERAL_TYPE = "repeated_literal"
+
+# Literals used more than this many times are reported
+REPEAT_THRESHOLD = 4
+
+_C_LIKE_LANGUAGES = frozenset(
+    {"go", "javascript", "tsx", "typescript", "java", "kotlin", "scala", "swift"}
+    | {"rust", "c", "cpp"}
+)
+
+# Numbers that explain themselves
+_PLAIN_NUMBERS = frozenset({0, 1, 2})
+
+_C_NUMBER_RE = re.compile(
+    r"(?<![\w.$#])(0[xX][0-9a-fA-F_]+|0[bB][01_]+|(?:\d[\d_']*)?\.?\d[\d_']*(?:[eE][+-]?\d+)?)"
+    r"[uUlLfFdDmn]*(?![\w.])"
+)
+_C_CONST_RE = re.compile(
+    r"^\s*(?:#\s*define\b|(?:export\s+)?(?:pub(?:\([\w:]+\))?\s+)?const\b|static\s+\w+\s*:)"
+    r"|\b(?:static\s+(?:\w+\s+)*final|constexpr|const\s+val)\b"
+)
+_C_IMPORT_RE = re.compile(
+    r"^\s*(?:import\b|package\b|#\s*include\b|use\b|export\s+(?:\*|\{[^}]*\})\s*from\b)"
+    r"|\brequire\s*\(|^\s*\}?\s*from\s+[\"']"
+)
+_GO_BLOCK_RE = re.compile(r"^(import|const)\s*\($")
+_PY_CONSTANT_RE = re.compile(r"^(?:[A-Z][A-Z0-9_]*|__\w+__)\s*(?::[^=]+)?=(?!=)")
+# Tokens that mark where statements and blocks end, on no code line of their own
+_PY_LAYOUT_TOKENS = frozenset(
+    {tokenize.NEWLINE, tokenize.INDENT, tokenize.DEDENT, tokenize.ENDMARKER}
+)
+
+
+@dataclass(frozen=True)
+class Literal:
+    path: str
+    line: int
+    kind: str  # "number" or "string"
+    value: str  # the number as written, or the string's contents
+
+
+@dataclass
+class FileLiterals:
+    """Literals in one file."""
+
+    path: str
+    code_lines: int
+    literals: list[Literal] = field(default_factory=list)
+
+    @property
+    def magic_numbers(self) -> int:
+        return sum(1 for lit in self.literals if lit.kind == "number")
+
+    @property
+    def strings(self) -> int:
+        return sum(1 for lit in self.literals if lit.kind == "string")
+
+    @property
+    def repeated_strings(self) -> int:
+        """String literals already used earlier in this file."""
+        counts = Counter(lit.value for lit in self.literals if lit.kind == "string")
+        return sum(n - 1 for n in counts.values())
+
+    @property
+    def magic_density(self) -> float:
+        """Magic numbers per 100 code lines."""
+        return 100 * self.magic_numbers / self.code_lines if self.code_lines else 0.0
+
+    def to_dict(self) -> dict:
+        return {
+            "path": self.path,
+            "code_lines": self.code_lines,
+            "magic_numbers": self.magic_numbers,
+            "magic_density": round(self.magic_density, 2),
+            "strings": self.strings,
+            "repeated_strings": self.repeated_strings,
+        }
+
+
+@dataclass
+class RepeatedLiteral:
+    """One literal used in many places."""
+
+    kind: str
+    value: str
+    uses: list[Literal]
+
+    @property
+    def files(self) -> list[str]:
+        return sorted({lit.path for lit in self.uses})
+
+    @property
+    def digest(self) -> str:
+        return hashlib.sha1(f"{self.kind}:{self.value}".encode()).hexdigest()[:12]
+
+    def severity(self, threshold: int) -> float:
+        return round(min(0.5, 0.3 + 0.1 * math.log2(len(self.uses) / threshold)), 4)
+
+    def to_dict(self) -> dict:
+        return {
+            "kind": self.kind,
+            "value": self.value,
+            "uses": len(self.uses),
+            "files": self.files,
+        }
+
+
+@dataclass
+class LiteralReport:
+    files: list[FileLiterals]  # most magic numbers first
+    repeated: list[RepeatedLiteral]  # most uses first
+    threshold: int
+
+    @property
+    def magic_numbers(self) -> int:
+        return sum(f.magic_numbers for f in self.files)
+
+    @property
+    def strings(self) -> int:
+        return sum(f.strings for f in self.files)
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        if not self.files:
+            return {}, {}
+        global_signals = {
+            "magic_numbers": float(self.magic_numbers),
+            "repeated_literals": float(len(self.repeated)),
+        }
+        package_signals: dict[str, dict[str, float]] = {}
+        for f in self.files:
+            signals = package_signals.setdefault(
+                SourceSet.package_of(f.path), {"magic_numbers": 0.0, "repeated_literals": 0.0}
+            )
+            signals["magic_numbers"] += f.magic_numbers
+        for repeated in self.repeated:
+            for package in {SourceSet.package_of(path) for path in repeated.files}:
+                package_signals[package]["repeated_literals"] += 1
+        return global_signals, package_signals
+
+    def to_dict(self, limit: int | None = None) -> dict:
+        return {
+            "group": "literals",
+            "magic_numbers": self.magic_numbers,
+            "strings": self.strings,
+            "threshold": self.threshold,
+            "files": [f.to_dict() for f in self.files[:limit]],
+            "repeated": [r.to_dict() for r in self.repeated[:limit]],
+        }
+
+
+def analyze_literals(
+    languages: dict[str, str], contents: dict[str, str], threshold: int = REPEAT_THRESHOLD
+) -> LiteralReport:
+    """Collect the literals of every non-test file, keyed path -> language."""
+    files = []
+    for path in sorted(languages):
+        language = languages[path]
+        if language != "python" and language not in _C_LIKE_LANGUAGES:
+            continue
+        if any(pattern.search(path.lower()) for pattern in TEST_PATH_PATTERNS):
+            continue
+        files.append(_scan(path, language, contents.get(path, "")))
+
+    uses: dict[tuple[str, str], list[Literal]] = {}
+    for f in files:
+        for lit in f.literals:
+            uses.setdefault((lit.kind, lit.value), []).append(lit)
+    repeated = [
+        RepeatedLiteral(kind, value, found)
+        for (kind, value), found in uses.items()
+        if len(found) > threshold
+    ]
+    repeated.sort(key=lambda r: (-len(r.uses), r.kind, r.value))
+    files.sort(key=lambda f: (-f.magic_numbers, -f.repeated_strings, f.path))
+    return LiteralReport(files, repeated, threshold)
+
+
+def _scan(path: str, language: str, content: str) -> FileLiterals:
+    if language == "python":
+        return _scan_python(path, content)
+    return _scan_c_like(path, language, content)
+
+
+def _scan_python(path: str, content: str) -> FileLiterals:
+    literals: list[Literal] = []
+    code_lines: set[int] = set()
+    constant_line = 0  # line of a module-level upper-case (constant) assignment being read
+    previous = tokenize.NEWLINE
+    try:
+        tokens = list(tokenize.generate_tokens(io.StringIO(content).readline))
+    except (tokenize.TokenError, SyntaxError, IndentationError):
+        return FileLiterals(path, 0)
+    for index, token in enumerate(tokens):
+        kind, text, (line, col) = token.type, token.string, token.start
+        if kind in (tokenize.COMMENT, tokenize.NL, tokenize.ENCODING):
+            continue
+        statement_start = previous in (tokenize.NEWLINE, tokenize.INDENT, tokenize.DEDENT)
+        previous = kind
+        if kind == tokenize.NEWLINE:
+            constant_line = 0
+        elif statement_start and kind == tokenize.NAME and col == 0:
+            if _PY_CONSTANT_RE.match(token.line):
+                constant_line = line
+        if kind not in _PY_LAYOUT_TOKENS:
+            code_lines.add(line)
+        following = tokens[index + 1].type if index + 1 < len(tokens) else tokenize.ENDMARKER
+        if constant_line:
+            continue
+        if kind == tokenize.NUMBER and _is_magic(text):
+            literals.append(Literal(path, line, "number", text))
+        elif kind == tokenize.STRING:
+            # A string standing alone as a statement is a docstring
+            if statement_start and following in (tokenize.NEWLINE, tokenize.ENDMARKER):
+                continue
+            value = _python_string(text)
+            if value is not None and len(value) >= 2:
+                literals.append(Literal(path, line, "string", value))
+    return FileLiterals(path, len(code_lines), literals)
+
+
+def _python_string(token: str) -> str | None:
+    """Contents of a string token; None for f-strings, whose text is a template."""
+    prefix = token[: len(token) - len(token.lstrip("rRbBuUfF"))]
+    if "f" in prefix.lower():
+        return None
+    body = token[len(prefix) :]
+    quote = 3 if body[:3] in ('"""', "'''") else 1
+    return body[quote:-quote]
+
+
+def _scan_c_like(path: str, language: str, content: str) -> FileLiterals:
+    literals: list[Literal] = []
+    code_lines = 0
+    go = language == "go"
+    block = ""  # "import" or "const" inside a Go declaration block
+    for line in _c_like_lines(content.splitlines(), raw_backticks=go):
+        code = line.code.strip()
+        if not code:
+            continue
+        code_lines += 1
+        if block:
+            block = "" if code.startswith(")") else block
+            continue
+        opened = _GO_BLOCK_RE.match(code) if go else None
+        if opened:
+            block = opened.group(1)
+            continue
+        if _C_CONST_RE.search(code) or _C_IMPORT_RE.search(code):
+            continue
+        for match in _C_NUMBER_RE.finditer(code):
+            if _is_magic(match.group(1)):
+                literals.append(Literal(path, line.number, "number", match.group(1)))
+        for quote, body in line.strings:
+            if quote == "`" and (go or "${" in body):
+                continue
+            if quote == "'" and language not in ("javascript", "tsx", "typescript"):
+                continue  # a character literal
+            if len(body) >= 2:
+                literals.append(Literal(path, line.number, "string", body))
+    return FileLiterals(path, code_lines, literals)
+
+
+def _is_magic(text: str) -> bool:
+    cleaned = text.lower().replace("_", "").replace("'", "").rstrip("jl")
+    try:
+        if cleaned.startswith(("0x", "0b", "0o")):
+            value: float = int(cleaned, 0)
+        else:
+            value = float(cleaned)
+    except ValueError:
+        return False
+    return value not in _PLAIN_NUMBERS
+
+
+def to_findings(report: LiteralReport) -> list:
+    """Convert literals used more than the threshold to ``repeated_literal`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for repeated in report.repeated:
+        shown = repeated.value if repeated.kind == "number" else f'"{_shorten(repeated.value)}"'
+        files = repeated.files
+        places = ", ".join(f"{lit.path}:{lit.line}" for lit in repeated.uses[:10])
+        if len(repeated.uses) > 10:
+            places += f" and {len(repeated.uses) - 10} more"
+        findings.append(
+            Finding(
+                finding_type=REPEATED_LITERAL_TYPE,
+                severity=repeated.severity(report.threshold),
+                title=(
+                    f"{repeated.kind.capitalize()} literal {shown} is used "
+                    f"{len(repeated.uses)} times in {len(files)} "
+                    f"{'file' if len(files) == 1 else 'files'}"
+                ),
+                files=files,
+                evidence=[
+                    Evidence(
+                        signal="literal_uses",
+                        value=float(len(repeated.uses)),
+                        percentile=0.0,
+                        description=places,
+                    ),
+                ],
+                suggestion=(
+                    "Name it once as a constant (or a typed key) and refer to the name, "
+                    "so a change is made in one place"
+                ),
+                effort="LOW",
+                identity_hint=f"{repeated.kind}:{repeated.digest}",
+            )
+        )
+    return findings
+
+
+def _shorten(text: str, limit: int = 40) -> str:
+    return text if len(text) <= limit else text[: limit - 3] + "..."
@@ -0,0 +1,300 @@
+"""Naming convention rules, configurable per language.
+
+These are low-severity style rules, reported separately from the
+complexity/structure findings. Three rule families:
+
+    case          Function and type names follow the language's casing
+                  convention. Leading underscores (Python "unexported") and
+                  Go's exported/unexported capitalisation are both accepted.
+    abbreviation  Initialisms (ID, URL, HTTP, ...) are written consistently:
+                  "upper" (UserID), "capitalized" (UserId), "consistent"
+                  (whichever form dominates per initialism in the repo) or "off".
+    stutter       Exported names repeat their package/module name
+                  (``repository.UserRepository``, ``cache.NewCache``).
+
+Per-language rules come from ``DEFAULT_RULES`` overlaid with the
+``naming_rules`` config table, e.g.::
+
+    [naming_rules.python]
+    abbreviations = "upper"
+    stutter = "off"
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass
+from pathlib import PurePosixPath
+from typing import Iterator, Optional
+
+from .sources import SourceSet
+
+# All naming issues share this severity: style, never a quality hotspot
+NAMING_SEVERITY = 0.2
+
+CASE_STYLES = ("snake_case", "camelCase", "PascalCase", "mixedCaps", "any")
+ABBREVIATION_STYLES = ("upper", "capitalized", "consistent", "off")
+
+DEFAULT_RULES: dict[str, dict[str, str]] = {
+    "go": {"function": "mixedCaps", "type": "mixedCaps", "abbreviations": "upper", "stutter": "on"},
+    "python": {
+        "function": "snake_case",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "java": {
+        "function": "camelCase",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "kotlin": {
+        "function": "camelCase",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "javascript": {
+        "function": "camelCase",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "typescript": {
+        "function": "camelCase",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "rust": {
+        "function": "snake_case",
+        "type": "PascalCase",
+        "abbreviations": "capitalized",
+        "stutter": "on",
+    },
+    "ruby": {
+        "function": "snake_case",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "php": {
+        "function": "camelCase",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "scala": {
+        "function": "camelCase",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "swift": {
+        "function": "camelCase",
+        "type": "PascalCase",
+        "abbreviations": "consistent",
+        "stutter": "off",
+    },
+    "sql": {
+        "function": "snake_case",
+        "type": "snake_case",
+        "abbreviations": "off",
+        "stutter": "off",
+    },
+    "hcl": {
+        "function": "snake_case",
+        "type": "snake_case",
+        "abbreviations": "off",
+        "stutter": "off",
+    },
+}
+
+# Common initialisms (after golint's list)
+INITIALISMS = frozenset(
+    "ACL API ASCII CPU CSS CSV DB DNS EOF GUID HTML HTTP HTTPS ID IP JSON JWT LHS QPS RAM "
+    "RHS RPC SLA SMTP SQL SSH TCP TLS TTL UDP UI UID URI URL UTF8 UUID VM XML XMPP XSRF XSS".split()
+)
+
+_WORD_RE = re.compile(r"[A-Z]+[0-9]*(?![a-z])|[A-Z]?[a-z]+[0-9]*|[0-9]+")
+_GO_PACKAGE_RE = re.compile(r"^package\s+(\w+)", re.MULTILINE)
+
+
+@dataclass
+class NamingIssue:
+    """One naming convention violation."""
+
+    rule: str  # case | abbreviation | stutter
+    language: str
+    path: str
+    line: int
+    name: str
+    kind: str  # function | type
+    message: str
+    severity: float = NAMING_SEVERITY
+
+
+@dataclass
+class NamingReport:
+    issues: list[NamingIssue]
+    identifiers: int  # names checked
+
+    def counts(self) -> dict[str, int]:
+        return dict(sorted(Counter(i.rule for i in self.issues).items()))
+
+    def to_dict(self) -> dict:
+        return {
+            "group": "style.naming",
+            "identifiers": self.identifiers,
+            "counts": self.counts(),
+            "issues": [i.__dict__ for i in self.issues],
+        }
+
+
+def resolve_rules(overrides: Optional[dict[str, dict[str, str]]] = None) -> dict[str, dict]:
+    """DEFAULT_RULES overlaid with user overrides, validated."""
+    rules = {lang: dict(r) for lang, r in DEFAULT_RULES.items()}
+    for lang, override in (overrides or {}).items():
+        rules.setdefault(lang, {"function": "any", "type": "any", "abbreviations": "off"})
+        rules[lang].update(override)
+    for lang, rule in rules.items():
+        for key in ("function", "type"):
+            if rule.get(key, "any") not in CASE_STYLES:
+                raise ValueError(f"naming_rules.{lang}.{key} must be one of: {CASE_STYLES}")
+        if rule.get("abbreviations", "off") not in ABBREVIATION_STYLES:
+            raise ValueError(
+                f"naming_rules.{lang}.abbreviations must be one of: {ABBREVIATION_STYLES}"
+            )
+    return rules
+
+
+def split_words(name: str) -> list[str]:
+    """Split a camelCase/PascalCase/snake_case identifier into words."""
+    return _WORD_RE.findall(name)
+
+
+def case_matches(name: str, style: str) -> bool:
+    """True if *name* satisfies *style*; single lowercase words fit every style but PascalCase."""
+    bare = name.strip("_")
+    if style == "any" or not bare:
+        return True
+    has_upper = any(c.isupper() for c in bare)
+    if "_" in bare:
+        return style == "snake_case" and not has_upper
+    if style == "snake_case":
+        return not has_upper
+    if style == "camelCase":
+        return bare[0].islower()
+    if style == "PascalCase":
+        return bare[0].isupper()
+    return True  # mixedCaps: any casing without underscores
+
+
+def abbreviation_forms(name: str) -> Iterator[tuple[str, str]]:
+    """(initialism, form) for each initialism spelled "upper" or "capitalized"."""
+    for word in split_words(name):
+        upper = word.upper()
+        if upper not in INITIALISMS or word.islower():
+            continue
+        yield upper, "upper" if word.isupper() else "capitalized"
+
+
+def is_exported(name: str, language: str) -> bool:
+    """Public API by the language's convention (Go capitalisation, leading underscore)."""
+    if language == "go":
+        return name[:1].isupper()
+    return not name.startswith("_")
+
+
+def _scope_name(path: str, language: str, content: str) -> str | None:
+    """Package (go) or module (other languages) name used for stutter checks."""
+    if language == "go":
+        match = _GO_PACKAGE_RE.search(content)
+        return match.group(1) if match else None
+    stem = PurePosixPath(path).stem
+    if stem in ("__init__", "index", "mod", "lib", "main"):
+        return PurePosixPath(path).parent.name or None
+    return stem
+
+
+def stutters(name: str, scope: str) -> bool:
+    """True if *name* starts or ends with all words of *scope* (and has more)."""
+    words = [w.lower() for w in split_words(name)]
+    scope_words = [w.lower() for w in re.split(r"[_\W]+", scope) if w]
+    k = len(scope_words)
+    if not k or len(words) <= k:
+        return False
+    return words[:k] == scope_words or words[-k:] == scope_words
+
+
+def _definition_line(content: str, name: str) -> int:
+    pattern = re.compile(rf"\b(?:class|type|struct|interface|enum|trait)\s+{re.escape(name)}\b")
+    match = pattern.search(content)
+    return content.count("\n", 0, match.start()) + 1 if match else 1
+
+
+def analyze_naming(
+    sources: SourceSet, overrides: Optional[dict[str, dict[str, str]]] = None
+) -> NamingReport:
+    """Check every function and type name against the per-language rules."""
+    rules = resolve_rules(overrides)
+    # (path, line, name, kind, language, scope)
+    identifiers: list[tuple[str, int, str, str, str, Optional[str]]] = []
+    for path, syntax in sorted(sources.syntax.items()):
+        rule = rules.get(syntax.language)
+        if rule is None:
+            continue
+        content = sources.content.get(path, "")
+        scope = _scope_name(path, syntax.language, content)
+        seen: set[str] = set()
+        for fn in syntax.functions:
+            if fn.name.startswith("__") and fn.name.endswith("__"):
+                continue
+            identifiers.append((path, fn.start_line, fn.name, "function", syntax.language, scope))
+        for cls in syntax.classes:
+            if cls.name in seen:
+                continue
+            seen.add(cls.name)
+            line = _definition_line(content, cls.name)
+            identifiers.append((path, line, cls.name, "type", syntax.language, scope))
+
+    # Dominant form per (language, initialism) for "consistent" mode
+    forms: dict[tuple[str, str], Counter] = {}
+    for _, _, name, _, language, _ in identifiers:
+        for initialism, form in abbreviation_forms(name):
+            forms.setdefault((language, initialism), Counter())[form] += 1
+
+    issues: list[NamingIssue] = []
+    for path, line, name, kind, language, scope in identifiers:
+        rule = rules[language]
+
+        def issue(kind_rule: str, message: str) -> None:
+            issues.append(NamingIssue(kind_rule, language, path, line, name, kind, message))
+
+        style = rule.get(kind, "any")
+        if not case_matches(name, style):
+            visibility = "exported" if is_exported(name, language) else "unexported"
+            issue("case", f"{visibility} {kind} '{name}' is not {style}")
+
+        mode = rule.get("abbreviations", "off")
+        for initialism, form in abbreviation_forms(name):
+            expected = mode
+            if mode == "consistent":
+                counter = forms[(language, initialism)]
+                expected = min(counter, key=lambda f: (-counter[f], f))
+            if mode != "off" and form != expected:
+                spelled = initialism if expected == "upper" else initialism.capitalize()
+                issue("abbreviation", f"'{name}' should spell {initialism} as '{spelled}'")
+
+        if (
+            rule.get("stutter", "off") == "on"
+            and scope
+            and is_exported(name, language)
+            and stutters(name, scope)
+        ):
+            issue("stutter", f"'{scope}.{name}' repeats the {scope!r} scope name")
+
+    issues.sort(key=lambda i: (i.path, i.line, i.rule))
+    return NamingReport(issues=issues, identifiers=len(identifiers))
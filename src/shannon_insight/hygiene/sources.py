@@ -0,0 +1,94 @@
+"""Source loading shared by hygiene reports."""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import TYPE_CHECKING, Optional
+
+from ..environment import discover_environment
+from ..file_ops import should_skip_file
+from ..scanning.gobuild import go_build_config
+from ..scanning.grammars import grammar_extensions
+from ..scanning.syntax_extractor import SyntaxExtractor
+
+if TYPE_CHECKING:
+    from ..config import AnalysisConfig
+    from ..scanning.syntax import FileSyntax
+
+
+@dataclass
+class SourceSet:
+    """Parsed sources for one repository root.
+
+    Attributes:
+        root: Absolute repository root
+        syntax: path -> FileSyntax (paths relative to root, forward slashes)
+        content: path -> file text
+        is_git_repo: Whether root is inside a git repository
+    """
+
+    root: Path
+    syntax: dict[str, FileSyntax] = field(default_factory=dict)
+    content: dict[str, str] = field(default_factory=dict)
+    is_git_repo: bool = False
+
+    @staticmethod
+    def package_of(path: str) -> str:
+        """Package (directory) a file belongs to; "." for top-level files."""
+        parent = PurePosixPath(path).parent.as_posix()
+        return parent if parent else "."
+
+    def language_of(self, path: str) -> str:
+        syntax = self.syntax.get(path)
+        return syntax.language if syntax else "unknown"
+
+    def by_package(self) -> dict[str, list[str]]:
+        """Files grouped by package, sorted for deterministic output."""
+        groups: dict[str, list[str]] = {}
+        for path in sorted(self.syntax):
+            groups.setdefault(self.package_of(path), []).append(path)
+        return groups
+
+
+def load_sources(root: Path | str, config: Optional[AnalysisConfig] = None) -> SourceSet:
+    """Discover, filter and parse source files under *root*.
+
+    Honours ``exclude_patterns``, ``max_file_size_mb`` and ``max_files``
+    from *config* (defaults when None).
+    """
+    from ..config import AnalysisConfig
+
+    config = config or AnalysisConfig()
+    env = discover_environment(
+        Path(root),
+        allow_hidden_files=config.allow_hidden_files,
+        follow_symlinks=config.follow_symlinks,
+        extra_extensions=grammar_extensions(config.grammars),
+    )
+
+    paths: list[Path] = []
+    for rel in env.file_paths:
+        full = env.root / rel
+        if should_skip_file(Path(rel), config.exclude_patterns):
+            continue
+        try:
+            if full.stat().st_size > config.max_file_size_bytes:
+                continue
+        except OSError:
+            continue
+        paths.append(full)
+        if len(paths) >= config.max_files:
+            break
+
+    content: dict[str, str] = {}
+    extractor = SyntaxExtractor(
+        c_defines=config.c_defines,
+        c_conditionals=config.c_conditionals,
+        grammars=config.grammars,
+        go_build=go_build_config(config),
+    )
+    syntax = extractor.extract_all(paths, env.root, content_cache=content)
+    normalized = {PurePosixPath(Path(p)).as_posix(): s for p, s in syntax.items()}
+    texts = {PurePosixPath(Path(p)).as_posix(): c for p, c in content.items()}
+    return SourceSet(root=env.root, syntax=normalized, content=texts, is_git_repo=env.is_git_repo)
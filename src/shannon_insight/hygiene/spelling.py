@@ -0,0 +1,298 @@
+"""Spellcheck of identifiers, comments and string literals.
+
+Uses a table of known misspellings (in the spirit of codespell) rather than a
+full dictionary, so code jargon, abbreviations and product names never need
+allowlisting; only real typos are reported. Identifiers are split with the
+camelCase/snake_case-aware splitter, so ``recieveMessage`` and
+``recieve_message`` both surface ``recieve``.
+
+Typos in definitions of exported functions and types are flagged as
+``api`` issues and sorted first: they are the expensive ones to fix once
+callers depend on them.
+
+Words in ``spelling_allowlist`` (config) are never reported. A line containing
+``spelling: ignore`` is skipped, and a file containing ``spelling: ignore-file``
+is skipped entirely.
+"""
+
+# spelling: ignore-file
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass
+from typing import Iterable, Optional
+
+from ..signals.complexity import is_comment_line
+from .glossary import STRING_RE
+from .naming import is_exported, split_words
+from .sources import SourceSet
+
+SPELLING_KINDS = ("api", "identifier", "comment", "string")
+
+# Common misspelling -> correction
+MISSPELLINGS: dict[str, str] = {
+    "accross": "across",
+    "acheive": "achieve",
+    "adress": "address",
+    "agressive": "aggressive",
+    "alot": "a lot",
+    "allready": "already",
+    "amoung": "among",
+    "analagous": "analogous",
+    "apparant": "apparent",
+    "appearence": "appearance",
+    "arguement": "argument",
+    "assertation": "assertion",
+    "asyncronous": "asynchronous",
+    "attribtue": "attribute",
+    "availabe": "available",
+    "availible": "available",
+    "beacuse": "because",
+    "becuase": "because",
+    "begining": "beginning",
+    "beleive": "believe",
+    "boundry": "boundary",
+    "buisness": "business",
+    "calender": "calendar",
+    "catagory": "category",
+    "cemetary": "cemetery",
+    "changable": "changeable",
+    "collegue": "colleague",
+    "commited": "committed",
+    "committ": "commit",
+    "comparision": "comparison",
+    "compatability": "compatibility",
+    "compatable": "compatible",
+    "completly": "completely",
+    "concious": "conscious",
+    "conection": "connection",
+    "configuraton": "configuration",
+    "consistant": "consistent",
+    "containg": "containing",
+    "continous": "continuous",
+    "correclty": "correctly",
+    "curent": "current",
+    "definately": "definitely",
+    "defualt": "default",
+    "dependancy": "dependency",
+    "depricated": "deprecated",
+    "descripton": "description",
+    "desination": "destination",
+    "diffrent": "different",
+    "dimention": "dimension",
+    "dissapear": "disappear",
+    "embarass": "embarrass",
+    "enviroment": "environment",
+    "environemnt": "environment",
+    "equivalant": "equivalent",
+    "exaple": "example",
+    "excecute": "execute",
+    "existance": "existence",
+    "existant": "existent",
+    "explicitely": "explicitly",
+    "familar": "familiar",
+    "finaly": "finally",
+    "fucntion": "function",
+    "funtion": "function",
+    "futher": "further",
+    "garantee": "guarantee",
+    "goverment": "government",
+    "grammer": "grammar",
+    "guarentee": "guarantee",
+    "heirarchy": "hierarchy",
+    "hieght": "height",
+    "identifer": "identifier",
+    "immediatly": "immediately",
+    "implemenation": "implementation",
+    "implmentation": "implementation",
+    "incomming": "incoming",
+    "independant": "independent",
+    "indeces": "indices",
+    "initalize": "initialize",
+    "inital": "initial",
+    "intial": "initial",
+    "interupt": "interrupt",
+    "irrelevent": "irrelevant",
+    "langauge": "language",
+    "lenght": "length",
+    "libary": "library",
+    "maintainance": "maintenance",
+    "maintenence": "maintenance",
+    "managment": "management",
+    "millenium": "millennium",
+    "mispell": "misspell",
+    "neccessary": "necessary",
+    "necesary": "necessary",
+    "nieghbor": "neighbor",
+    "noticable": "noticeable",
+    "occassion": "occasion",
+    "occured": "occurred",
+    "occurence": "occurrence",
+    "occurrance": "occurrence",
+    "ommit": "omit",
+    "optionnal": "optional",
+    "orginal": "original",
+    "paramter": "parameter",
+    "paramters": "parameters",
+    "parrallel": "parallel",
+    "particularily": "particularly",
+    "peice": "piece",
+    "permision": "permission",
+    "persistant": "persistent",
+    "posession": "possession",
+    "prefered": "preferred",
+    "preformance": "performance",
+    "presense": "presence",
+    "previos": "previous",
+    "priviledge": "privilege",
+    "privilage": "privilege",
+    "probelm": "problem",
+    "proccess": "process",
+    "propery": "property",
+    "publically": "publicly",
+    "recieve": "receive",
+    "recieved": "received",
+    "reciever": "receiver",
+    "recomend": "recommend",
+    "recommanded": "recommended",
+    "reffered": "referred",
+    "refrence": "reference",
+    "relevent": "relevant",
+    "remeber": "remember",
+    "repetion": "repetition",
+    "reponse": "response",
+    "repositry": "repository",
+    "requred": "required",
+    "resouce": "resource",
+    "responsability": "responsibility",
+    "retreive": "retrieve",
+    "retrive": "retrieve",
+    "seperate": "separate",
+    "seperator": "separator",
+    "sieze": "seize",
+    "similiar": "similar",
+    "sucess": "success",
+    "succesful": "successful",
+    "successfull": "successful",
+    "sucessful": "successful",
+    "supercede": "supersede",
+    "suport": "support",
+    "supress": "suppress",
+    "surpress": "suppress",
+    "synchonous": "synchronous",
+    "tempory": "temporary",
+    "threshhold": "threshold",
+    "tommorow": "tomorrow",
+    "transfered": "transferred",
+    "truely": "truly",
+    "unkown": "unknown",
+    "unneccessary": "unnecessary",
+    "untill": "until",
+    "usefull": "useful",
+    "valdiate": "validate",
+    "varible": "variable",
+    "vaule": "value",
+    "verison": "version",
+    "wierd": "weird",
+    "writting": "writing",
+}
+
+IGNORE_LINE_MARKER = "spelling: ignore"
+IGNORE_FILE_MARKER = "spelling: ignore-file"
+
+_WORD_RE = re.compile(r"[A-Za-z][A-Za-z0-9_]*")
+_COMMENT_START_RE = re.compile(r"(?:^|\s)(?:#|//)\s")
+
+
+@dataclass
+class Misspelling:
+    """One misspelled word."""
+
+    kind: str  # api | identifier | comment | string
+    path: str
+    line: int
+    word: str
+    suggestion: str
+    token: str  # identifier or text fragment containing the word
+
+
+@dataclass
+class SpellingReport:
+    issues: list[Misspelling]
+
+    def counts(self) -> dict[str, int]:
+        return dict(Counter(i.kind for i in self.issues))
+
+    def words(self) -> list[tuple[str, str, int]]:
+        """(typo, suggestion, occurrences), most frequent first."""
+        counter = Counter((i.word, i.suggestion) for i in self.issues)
+        return [(w, s, n) for (w, s), n in counter.most_common()]
+
+    def to_dict(self) -> dict:
+        return {
+            "counts": self.counts(),
+            "words": [{"word": w, "suggestion": s, "count": n} for w, s, n in self.words()],
+            "issues": [i.__dict__ for i in self.issues],
+        }
+
+
+def _split_code_comment(line: str) -> tuple[str, str]:
+    """Split a line into (code, trailing comment)."""
+    if is_comment_line(line):
+        return "", line
+    match = _COMMENT_START_RE.search(STRING_RE.sub(lambda m: "_" * len(m.group()), line))
+    if match:
+        return line[: match.start()], line[match.start() :]
+    return line, ""
+
+
+def _typos(text: str, allowlist: frozenset[str]) -> Iterable[tuple[str, str, str]]:
+    """(word, suggestion, token) for every misspelled word in *text*."""
+    for token in _WORD_RE.findall(text):
+        for word in split_words(token):
+            lower = word.lower()
+            if lower in MISSPELLINGS and lower not in allowlist:
+                yield lower, MISSPELLINGS[lower], token
+
+
+def analyze_spelling(
+    sources: SourceSet, allowlist: Optional[Iterable[str]] = None
+) -> SpellingReport:
+    """Find known misspellings in identifiers, comments and string literals."""
+    allowed = frozenset(w.lower() for w in (allowlist or ()))
+    issues: list[Misspelling] = []
+
+    for path in sorted(sources.content):
+        content = sources.content[path]
+        if IGNORE_FILE_MARKER in content:
+            continue
+        syntax = sources.syntax.get(path)
+        language = syntax.language if syntax else "unknown"
+        definitions = set()
+        if syntax:
+            definitions = {f.name for f in syntax.functions} | {c.name for c in syntax.classes}
+
+        seen: set[tuple[int, str, str]] = set()
+        for lineno, line in enumerate(content.splitlines(), 1):
+            if IGNORE_LINE_MARKER in line:
+                continue
+            code, comment = _split_code_comment(line)
+            fragments = [("comment", comment)]
+            fragments += [("string", m.group()[1:-1]) for m in STRING_RE.finditer(code)]
+            fragments.append(("identifier", STRING_RE.sub(" ", code)))
+
+            for kind, text in fragments:
+                for word, suggestion, token in _typos(text, allowed):
+                    key = (lineno, word, token)
+                    if key in seen:
+                        continue
+                    seen.add(key)
+                    found = kind
+                    if kind == "identifier" and token in definitions:
+                        found = "api" if is_exported(token, language) else kind
+                    issues.append(Misspelling(found, path, lineno, word, suggestion, token))
+
+    issues.sort(key=lambda i: (SPELLING_KINDS.index(i.kind), i.path, i.line))
+    return SpellingReport(issues=issues)
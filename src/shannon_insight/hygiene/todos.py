@@ -0,0 +1,270 @@
+"""TODO/FIXME/HACK comment debt with blame-derived age and owner.
+
+Each marker comment becomes a ``comment_debt`` item. Its identity is the
+file plus a fingerprint of the marker text (not the line number), so a TODO
+keeps its history when surrounding code moves and is "resolved" only when the
+comment itself is removed or rewritten.
+
+The kernel emits these as findings attached to the snapshot (outside the
+ranked top-N), so they flow through the same finding lifecycle tracking as
+structural findings, and records per-package count and median age as module
+and global signals for the health dashboards.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import re
+import statistics
+import subprocess
+import time
+from dataclasses import dataclass
+from pathlib import Path, PurePosixPath
+from typing import Optional
+
+from ..logging_config import get_logger
+from ..portable import fold_case
+
+logger = get_logger(__name__)
+
+COMMENT_DEBT_TYPE = "comment_debt"
+
+# Base severity per marker tag; age adds up to AGE_SEVERITY on top
+TAG_SEVERITY = {"FIXME": 0.3, "HACK": 0.3, "XXX": 0.25, "TODO": 0.2}
+AGE_SEVERITY = 0.2
+AGE_SATURATION_DAYS = 365
+
+_MARKER_RE = re.compile(
+    r"(?:#|//|/\*|^\s*\*|--|;)\s*(TODO|FIXME|HACK|XXX)\b"
+    r"(?:\(([^)]*)\))?"  # optional assignee: TODO(alice)
+    r"[:\s-]*(.*?)\s*(?:\*/)?\s*$"
+)
+
+_BLAME_TIMEOUT_SECONDS = 30
+
+
+@dataclass
+class DebtItem:
+    """One TODO/FIXME/HACK/XXX comment."""
+
+    path: str
+    line: int
+    tag: str
+    text: str
+    assignee: Optional[str] = None  # from TODO(name)
+    author: Optional[str] = None  # blame author of the line
+    age_days: Optional[float] = None  # None when git history is unavailable
+    fingerprint: str = ""
+
+    @property
+    def package(self) -> str:
+        parent = PurePosixPath(self.path).parent.as_posix()
+        return parent if parent else "."
+
+    @property
+    def owner(self) -> Optional[str]:
+        return self.assignee or self.author
+
+    @property
+    def severity(self) -> float:
+        age = min(1.0, (self.age_days or 0.0) / AGE_SATURATION_DAYS)
+        return round(TAG_SEVERITY.get(self.tag, 0.2) + AGE_SEVERITY * age, 3)
+
+
+@dataclass
+class PackageDebt:
+    package: str
+    count: int
+    median_age_days: Optional[float]
+    by_tag: dict[str, int]
+
+
+def extract_markers(path: str, content: str) -> list[DebtItem]:
+    """Find marker comments in *content*; fingerprints are stable across line moves."""
+    items: list[DebtItem] = []
+    seen: dict[str, int] = {}
+    for lineno, line in enumerate(content.splitlines(), 1):
+        match = _MARKER_RE.search(line)
+        if not match:
+            continue
+        tag, assignee, text = match.group(1), match.group(2), match.group(3)
+        normalized = " ".join(fold_case(text).split())
+        # Identical markers in one file are told apart by their order
+        base = f"{tag}|{normalized}"
+        ordinal = seen.get(base, 0)
+        seen[base] = ordinal + 1
+        digest = hashlib.sha256(f"{base}|{ordinal}".encode()).hexdigest()[:12]
+        items.append(
+            DebtItem(
+                path=path,
+                line=lineno,
+                tag=tag,
+                text=text,
+                assignee=assignee.strip() if assignee else None,
+                fingerprint=digest,
+            )
+        )
+    return items
+
+
+def blame_lines(root: Path, path: str, lines: list[int]) -> dict[int, tuple[str, int]]:
+    """Map line number -> (author, author_time) via one ``git blame`` call.
+
+    Uncommitted lines and git failures are simply missing from the result.
+    """
+    if not lines:
+        return {}
+    args = ["git", "-C", str(root), "blame", "--line-porcelain"]
+    for n in lines:
+        args += ["-L", f"{n},{n}"]
+    try:
+        result = subprocess.run(
+            [*args, "--", path],
+            capture_output=True,
+            text=True,
+            timeout=_BLAME_TIMEOUT_SECONDS,
+        )
+    except (FileNotFoundError, subprocess.TimeoutExpired) as e:
+        logger.debug(f"git blame failed for {path}: {e}")
+        return {}
+    if result.returncode != 0:
+        return {}
+
+    blamed: dict[int, tuple[str, int]] = {}
+    final_line = 0
+    sha = ""
+    author = ""
+    for row in result.stdout.splitlines():
+        if row.startswith("\t"):
+            continue
+        parts = row.split()
+        if len(parts) >= 3 and len(parts[0]) == 40 and parts[2].isdigit():
+            sha, final_line = parts[0], int(parts[2])
+        elif row.startswith("author "):
+            author = row[len("author ") :]
+        elif row.startswith("author-time ") and sha.strip("0"):
+            blamed[final_line] = (author, int(row.split()[1]))
+    return blamed
+
+
+def collect_comment_debt(
+    root: Path | str,
+    contents: dict[str, str],
+    use_git: bool = True,
+    now: Optional[float] = None,
+) -> list[DebtItem]:
+    """Extract marker comments from *contents* and attach blame age/owner."""
+    root = Path(root)
+    now = now if now is not None else time.time()
+    items: list[DebtItem] = []
+    for path in sorted(contents):
+        markers = extract_markers(path, contents[path])
+        if markers and use_git:
+            blamed = blame_lines(root, path, [m.line for m in markers])
+            for marker in markers:
+                if marker.line in blamed:
+                    author, timestamp = blamed[marker.line]
+                    marker.author = author
+                    marker.age_days = round(max(0.0, now - timestamp) / 86400, 1)
+        items.extend(markers)
+    return items
+
+
+def summarize_by_package(items: list[DebtItem]) -> list[PackageDebt]:
+    """Per-package marker counts and median age, largest debt first."""
+    groups: dict[str, list[DebtItem]] = {}
+    for item in items:
+        groups.setdefault(item.package, []).append(item)
+
+    summaries = []
+    for package, group in groups.items():
+        ages = [i.age_days for i in group if i.age_days is not None]
+        by_tag: dict[str, int] = {}
+        for item in group:
+            by_tag[item.tag] = by_tag.get(item.tag, 0) + 1
+        summaries.append(
+            PackageDebt(
+                package=package,
+                count=len(group),
+                median_age_days=round(statistics.median(ages), 1) if ages else None,
+                by_tag=dict(sorted(by_tag.items())),
+            )
+        )
+    summaries.sort(key=lambda s: (-s.count, s.package))
+    return summaries
+
+
+def debt_signals(items: list[DebtItem]) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+    """(global, per-package) comment debt signals for snapshots."""
+    ages = [i.age_days for i in items if i.age_days is not None]
+    global_signals = {"comment_debt_count": float(len(items))}
+    if ages:
+        global_signals["comment_debt_median_age_days"] = float(statistics.median(ages))
+
+    package_signals: dict[str, dict[str, float]] = {}
+    for summary in summarize_by_package(items):
+        signals = {"comment_debt_count": float(summary.count)}
+        if summary.median_age_days is not None:
+            signals["comment_debt_median_age_days"] = summary.median_age_days
+        package_signals[summary.package] = signals
+    return global_signals, package_signals
+
+
+def identity_key(item: DebtItem) -> str:
+    """History identity key of the ``comment_debt`` finding for *item*."""
+    from ..persistence.identity import compute_identity_key
+
+    return compute_identity_key(COMMENT_DEBT_TYPE, [item.path], hint=item.fingerprint)
+
+
+def lifecycle_states(items: list[DebtItem], lifecycle: dict[str, dict]) -> tuple[list[str], int]:
+    """Lifecycle state per item plus the number of resolved markers.
+
+    *lifecycle* is ``get_finding_lifecycle_map()`` output. Items never
+    snapshotted are "new"; others are "persisting" (seen in earlier snapshots).
+    """
+    states = []
+    for item in items:
+        entry = lifecycle.get(identity_key(item))
+        states.append("persisting" if entry else "new")
+    resolved = sum(
+        1
+        for entry in lifecycle.values()
+        if entry["finding_type"] == COMMENT_DEBT_TYPE and entry["current_status"] == "resolved"
+    )
+    return states, resolved
+
+
+def to_findings(items: list[DebtItem]) -> list:
+    """Convert debt items to ``comment_debt`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for item in items:
+        evidence = [Evidence(signal="tag", value=0.0, percentile=0.0, description=item.tag)]
+        if item.age_days is not None:
+            evidence.append(
+                Evidence(
+                    signal="age_days",
+                    value=item.age_days,
+                    percentile=0.0,
+                    description=f"{item.age_days:.0f} days old",
+                )
+            )
+        if item.owner:
+            evidence.append(
+                Evidence(signal="owner", value=0.0, percentile=0.0, description=item.owner)
+            )
+        findings.append(
+            Finding(
+                finding_type=COMMENT_DEBT_TYPE,
+                severity=item.severity,
+                title=f"{item.tag} at {item.path}:{item.line}: {item.text}".rstrip(": "),
+                files=[item.path],
+                evidence=evidence,
+                suggestion="Resolve the comment or turn it into a tracked issue",
+                effort="LOW",
+                identity_hint=item.fingerprint,
+            )
+        )
+    return findings
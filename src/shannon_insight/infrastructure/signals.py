@@ -95,8 +95,16 @@ class Signal(Enum):
     BROKEN_CALL_COUNT = "broken_call_count"  # 22
     COMMUNITY = "community"  # 23
     COMPRESSION_RATIO = "compression_ratio"  # 24
+    TOKEN_ENTROPY = "token_entropy"  # 24a
     SEMANTIC_COHERENCE = "semantic_coherence"  # 25
     COGNITIVE_LOAD = "cognitive_load"  # 26
+    HALSTEAD_VOLUME = "halstead_volume"  # 26a
+    HALSTEAD_DIFFICULTY = "halstead_difficulty"  # 26b
+    HALSTEAD_EFFORT = "halstead_effort"  # 26c
+    MAINTAINABILITY_INDEX = "maintainability_index"  # 26d
+    COMMENT_DENSITY = "comment_density"  # 26e
+    COMMENTED_OUT_LINES = "commented_out_lines"  # 26f
+    UNDOCUMENTED_COMPLEXITY = "undocumented_complexity"  # 26g
 
     # ── IR5t: Temporal / git history (per-file, phase 3) ─────────────
     TOTAL_CHANGES = "total_changes"  # 27
@@ -128,6 +136,7 @@ class Signal(Enum):
     KNOWLEDGE_GINI = "knowledge_gini"  # 47
     MODULE_BUS_FACTOR = "module_bus_factor"  # 48
     MEAN_COGNITIVE_LOAD = "mean_cognitive_load"  # 49
+    MODULE_MAINTAINABILITY = "module_maintainability"  # 49a
     FILE_COUNT = "file_count"  # 50
     HEALTH_SCORE = "health_score"  # 51
 
@@ -601,6 +610,19 @@ register(
     )
 )
 
+register(
+    SignalMeta(
+        signal=Signal.TOKEN_ENTROPY,
+        dtype=float,
+        scope="file",
+        percentileable=True,
+        polarity="neutral",
+        absolute_threshold=None,
+        produced_by="signals/fusion",  # From the file's tokens, see token_entropy.py
+        phase=5,
+    )
+)
+
 register(
     SignalMeta(
         signal=Signal.SEMANTIC_COHERENCE,
@@ -627,6 +649,97 @@ register(
     )
 )
 
+register(
+    SignalMeta(
+        signal=Signal.HALSTEAD_VOLUME,
+        dtype=float,
+        scope="file",
+        percentileable=True,
+        polarity="high_is_bad",
+        absolute_threshold=None,
+        produced_by="graph/measurements",  # AnalysisEngine._measure_files, from the tokens
+        phase=0,
+    )
+)
+
+register(
+    SignalMeta(
+        signal=Signal.HALSTEAD_DIFFICULTY,
+        dtype=float,
+        scope="file",
+        percentileable=True,
+        polarity="high_is_bad",
+        absolute_threshold=None,
+        produced_by="graph/measurements",
+        phase=0,
+    )
+)
+
+register(
+    SignalMeta(
+        signal=Signal.HALSTEAD_EFFORT,
+        dtype=float,
+        scope="file",
+        percentileable=True,
+        polarity="high_is_bad",
+        absolute_threshold=None,
+        produced_by="graph/measurements",
+        phase=0,
+    )
+)
+
+register(
+    SignalMeta(
+        signal=Signal.MAINTAINABILITY_INDEX,
+        dtype=float,
+        scope="file",
+        percentileable=True,
+        polarity="high_is_good",
+        absolute_threshold=None,
+        produced_by="signals/fusion",  # From Halstead volume and file text, see maintainability.py
+        phase=5,
+    )
+)
+
+register(
+    SignalMeta(
+        signal=Signal.COMMENT_DENSITY,
+        dtype=float,
+        scope="file",
+        percentileable=True,
+        polarity="high_is_good",
+        absolute_threshold=None,
+        produced_by="signals/fusion",  # From file text, see comments.py
+        phase=5,
+    )
+)
+
+register(
+    SignalMeta(
+        signal=Signal.COMMENTED_OUT_LINES,
+        dtype=int,
+        scope="file",
+        percentileable=True,
+        polarity="high_is_bad",
+        absolute_threshold=None,
+        produced_by="signals/fusion",
+        phase=5,
+    )
+)
+
+register(
+    SignalMeta(
+        signal=Signal.UNDOCUMENTED_COMPLEXITY,
+        dtype=int,
+        scope="file",
+        percentileable=True,
+        polarity="high_is_bad",
+        absolute_threshold=None,
+        produced_by="signals/fusion",
+        phase=5,
+    )
+)
+
 # ── Per-File: IR5t Temporal (temporal/) ──────────────────────────────────
 
 register(
@@ -958,6 +1071,19 @@ register(
     )
 )
 
+register(
+    SignalMeta(
+        signal=Signal.MODULE_MAINTAINABILITY,
+        dtype=float,
+        scope="module",
+        percentileable=True,
+        polarity="high_is_good",
+        absolute_threshold=None,
+        produced_by="signals/fusion",
+        phase=5,
+    )
+)
+
 register(
     SignalMeta(
         signal=Signal.FILE_COUNT,
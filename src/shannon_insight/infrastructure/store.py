@@ -43,6 +43,7 @@ Usage:
 
 from __future__ import annotations
 
+import threading
 from datetime import datetime
 from typing import TYPE_CHECKING, Any
 
@@ -95,6 +96,8 @@ class FactStore:
         self._entities: dict[EntityId, Entity] = {}
         self._signals = SignalStore()
         self._relations = RelationGraph()
+        # Analyzers run concurrently (insights/scheduler.py); writes are serialized
+        self._write_lock = threading.Lock()
 
         # Complex object storage (replaces AnalysisStore slots)
         self._file_syntax: dict[str, FileSyntax] | None = None
@@ -298,7 +301,8 @@ class FactStore:
 
     def add_entity(self, entity: Entity) -> None:
         """Register an entity in the store."""
-        self._entities[entity.id] = entity
+        with self._write_lock:
+            self._entities[entity.id] = entity
 
     def get_entity(self, id: EntityId) -> Entity | None:
         """Look up an entity by its EntityId. Returns None if not found."""
@@ -343,18 +347,19 @@ class FactStore:
             inputs: Signal names used to compute this value (for provenance).
             formula: Human-readable formula (for provenance).
         """
-        self._signals.set(entity, signal, value)
-
-        # Record provenance if tracking is enabled
-        if self._provenance_enabled and self._provenance is not None:
-            self._provenance.record(
-                entity_path=entity.key,
-                signal=signal,
-                value=value,
-                producer=producer or "unknown",
-                inputs=inputs,
-                formula=formula,
-            )
+        with self._write_lock:
+            self._signals.set(entity, signal, value)
+
+            # Record provenance if tracking is enabled
+            if self._provenance_enabled and self._provenance is not None:
+                self._provenance.record(
+                    entity_path=entity.key,
+                    signal=signal,
+                    value=value,
+                    producer=producer or "unknown",
+                    inputs=inputs,
+                    formula=formula,
+                )
 
     def get_signal(self, entity: EntityId, signal: Signal, default: Any = None) -> Any:
         """Get the latest signal value for an entity."""
@@ -389,7 +394,8 @@ class FactStore:
 
     def add_relation(self, relation: Relation) -> None:
         """Add a relation to the graph."""
-        self._relations.add(relation)
+        with self._write_lock:
+            self._relations.add(relation)
 
     def has_relation(self, source: EntityId, type: RelationType, target: EntityId) -> bool:
         """Check if a specific relation exists."""
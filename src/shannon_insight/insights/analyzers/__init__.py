@@ -2,6 +2,35 @@
 
 from typing import TYPE_CHECKING
 
+from .clones import CloneAnalyzer
+from .dependencies import CentralityAnalyzer, HexagonalAnalyzer
+from .design import (
+    ApiSurfaceAnalyzer,
+    CohesionAnalyzer,
+    GodClassAnalyzer,
+    InformationDensityAnalyzer,
+    VocabularyDriftAnalyzer,
+)
+from .functions import (
+    CoverageRiskAnalyzer,
+    DeadCodeAnalyzer,
+    DeepNestingAnalyzer,
+    FunctionFanAnalyzer,
+    FunctionOutlierAnalyzer,
+    FunctionStatsAnalyzer,
+    NotebookDriftAnalyzer,
+    ParameterAnalyzer,
+)
+from .hygiene import (
+    AuthAnalyzer,
+    CommentDebtAnalyzer,
+    CryptoAnalyzer,
+    DeprecationAnalyzer,
+    ErrorHygieneAnalyzer,
+    FormatDriftAnalyzer,
+    LiteralAnalyzer,
+)
+from .manifests import MobileResourceAnalyzer, SqlAnalyzer, TerraformAnalyzer, YamlAnalyzer
 from .spectral import SpectralAnalyzer
 from .structural import StructuralAnalyzer
 from .temporal import TemporalAnalyzer
@@ -9,16 +38,56 @@ from .temporal import TemporalAnalyzer
 if TYPE_CHECKING:
     from ...config import AnalysisConfig
 
+# Wave 1 analyzers that each fill one report slot for its finder and the
+# snapshot, and that nothing else needs; ``disabled_analyzers`` skips them
+_OPTIONAL = (
+    CommentDebtAnalyzer,
+    DeadCodeAnalyzer,
+    DeepNestingAnalyzer,
+    DeprecationAnalyzer,
+    FormatDriftAnalyzer,
+    FunctionFanAnalyzer,
+    FunctionOutlierAnalyzer,
+    FunctionStatsAnalyzer,
+    GodClassAnalyzer,
+    CohesionAnalyzer,
+    NotebookDriftAnalyzer,
+    ParameterAnalyzer,
+    SqlAnalyzer,
+    TerraformAnalyzer,
+    VocabularyDriftAnalyzer,
+    YamlAnalyzer,
+    MobileResourceAnalyzer,
+    CryptoAnalyzer,
+    AuthAnalyzer,
+    ErrorHygieneAnalyzer,
+    HexagonalAnalyzer,
+    CentralityAnalyzer,
+    InformationDensityAnalyzer,
+    CoverageRiskAnalyzer,
+    ApiSurfaceAnalyzer,
+    LiteralAnalyzer,
+)
+
+OPTIONAL_ANALYZERS = tuple(cls.name for cls in _OPTIONAL)
+
 
 def get_default_analyzers(config: "AnalysisConfig") -> list:
-    """Return Wave 1 analyzers (topo-sorted by requires/provides).
+    """Return Wave 1 analyzers (scheduled by requires/uses/provides).
 
-    Order is determined by requires/provides dependencies:
+    Order is determined by requires/provides dependencies; analyzers with
+    no path between them run concurrently:
     1. StructuralAnalyzer: requires files, provides structural
     2. TemporalAnalyzer: requires files, provides git_history/cochange/churn
+       (git extraction starts during scanning)
     3. SpectralAnalyzer: requires structural, provides spectral
     4. SemanticAnalyzer: requires file_syntax, provides semantics/roles
-    5. ArchitectureAnalyzer: requires structural, provides architecture
+    5. CloneAnalyzer: requires file_syntax, uses roles, provides clone_pairs
+    6. ArchitectureAnalyzer: requires structural + roles, provides architecture
+    7. The OPTIONAL_ANALYZERS not in config.disabled_analyzers (comment_debt
+       also needs enable_comment_debt): each provides its own report slot,
+       mostly from file_syntax alone; hexagonal and centrality require
+       structural
 
     Args:
         config: Analysis configuration with algorithm parameters
@@ -26,6 +95,9 @@ def get_default_analyzers(config: "AnalysisConfig") -> list:
     from shannon_insight.architecture.analyzer import ArchitectureAnalyzer
     from shannon_insight.semantics.analyzer import SemanticAnalyzer
 
+    disabled = set(config.disabled_analyzers)
+    if not config.enable_comment_debt:
+        disabled.add(CommentDebtAnalyzer.name)
     return [
         StructuralAnalyzer(
             pagerank_damping=config.pagerank_damping,
@@ -38,7 +110,9 @@ def get_default_analyzers(config: "AnalysisConfig") -> list:
         ),
         SpectralAnalyzer(),
         SemanticAnalyzer(),
+        CloneAnalyzer(),
         ArchitectureAnalyzer(),
+        *(cls() for cls in _OPTIONAL if cls.name not in disabled),
     ]
 
 
@@ -0,0 +1,166 @@
+"""CloneAnalyzer — Phase 3 clone detection on file contents.
+
+Two detectors: NCD compares whole files (clone_pairs), and token-based
+clone classes find copied stretches of code inside files (duplication,
+which codebase_health reads in signal fusion).
+
+Separate from StructuralAnalyzer so that it can wait for semantic roles
+(TEST/MIGRATION pairs are excluded) without holding up the graph analysis.
+"""
+
+from pathlib import Path
+from typing import Callable, Optional
+
+from ...config import DEFAULT_THRESHOLDS
+from ...graph.clone_detection import detect_clones, detect_clones_winnowed
+from ...infrastructure.entities import EntityId, EntityType
+from ...infrastructure.relations import Relation, RelationType
+from ...logging_config import get_logger
+from ...signals.duplication import analyze_duplication
+from ..store import AnalysisStore
+
+logger = get_logger(__name__)
+
+
+class CloneAnalyzer:
+    name = "clones"
+    requires: set[str] = {"file_syntax"}
+    uses: set[str] = {"roles"}  # TEST/MIGRATION exclusion when available
+    provides: set[str] = {"clone_pairs", "duplication"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Run NCD and token-based clone detection on file contents."""
+        root = Path(store.root_dir) if store.root_dir else Path.cwd()
+
+        # Get threshold from config if available
+        thresholds = DEFAULT_THRESHOLDS
+        if store.session is not None and store.session.config is not None:
+            thresholds = store.session.config.thresholds
+
+        # Get roles if available (for TEST/MIGRATION exclusion)
+        roles: dict[str, str] = {}
+        if store.roles.available:
+            roles = store.roles.value
+
+        def load(path: str) -> Optional[bytes]:
+            # Try cache first
+            content = store.get_content(path)
+            if content is not None:
+                return content.encode("utf-8")
+            # Fallback to disk read
+            try:
+                return (root / path).read_bytes()
+            except OSError:
+                return None
+
+        self._analyze_duplication(store, load, thresholds.clone_min_tokens, roles)
+
+        # Skip files below minimum line threshold
+        paths = [
+            fm.path
+            for fm in store.file_syntax.value.values()
+            if fm.lines >= thresholds.clone_min_lines
+        ]
+        if len(paths) < 2:
+            return
+
+        if len(paths) >= thresholds.clone_lsh_file_threshold:
+            # Large codebase: fingerprint index instead of all contents + all pairs
+            clone_pairs = detect_clones_winnowed(
+                paths,
+                load,
+                roles,
+                threshold=thresholds.clone_ncd_threshold,
+                guarantee_tokens=thresholds.clone_guarantee_tokens,
+                noise_tokens=thresholds.clone_noise_tokens,
+            )
+        else:
+            file_contents: dict[str, bytes] = {}
+            for path in paths:
+                content = load(path)
+                if content is not None:
+                    file_contents[path] = content
+            if len(file_contents) < 2:
+                return
+            clone_pairs = detect_clones(
+                file_contents, roles, threshold=thresholds.clone_ncd_threshold
+            )
+        store.clone_pairs.set(clone_pairs, produced_by=self.name)
+        logger.debug(f"Clone detection: {len(clone_pairs)} pairs found")
+
+        # Sync clone pairs as CLONED_FROM relations to FactStore
+        self._sync_clone_relations(store, clone_pairs)
+
+    def _analyze_duplication(
+        self,
+        store: AnalysisStore,
+        load: Callable[[str], Optional[bytes]],
+        min_tokens: int,
+        roles: dict[str, str],
+    ) -> None:
+        """Find token-based clone classes in every file but the generated ones."""
+        generated = store.generated_files.get(default={})
+        syntax = {
+            path: fs for path, fs in store.file_syntax.value.items() if path not in generated
+        }
+        contents = {
+            path: (load(path) or b"").decode("utf-8", errors="replace") for path in syntax
+        }
+        try:
+            report = analyze_duplication(syntax, contents, min_tokens, roles)
+        except Exception as e:
+            logger.warning(f"Duplication analysis failed: {e}")
+            store.duplication.set_error(str(e), produced_by=self.name)
+            return
+        store.duplication.set(report, produced_by=self.name)
+        logger.debug(
+            f"Duplication: {len(report.classes)} clone classes, "
+            f"{report.duplication_ratio:.1%} of lines"
+        )
+
+    def _sync_clone_relations(self, store: AnalysisStore, clone_pairs: list) -> None:
+        """Add CLONED_FROM relations to FactStore for pattern detection.
+
+        COPY_PASTE_CLONE pattern checks for CLONED_FROM relations with ncd metadata.
+        """
+        if not hasattr(store, "fact_store"):
+            return
+
+        fs = store.fact_store
+
+        for pair in clone_pairs:
+            src_id = EntityId(EntityType.FILE, pair.file_a)
+            tgt_id = EntityId(EntityType.FILE, pair.file_b)
+            metadata = {
+                "ncd": pair.ncd,
+                "size_a": pair.size_a,
+                "size_b": pair.size_b,
+            }
+            # CLONED_FROM is symmetric — store in BOTH directions so FILE_PAIR
+            # predicates find the relation regardless of pair iteration order.
+            fs.add_relation(
+                Relation(
+                    type=RelationType.CLONED_FROM,
+                    source=src_id,
+                    target=tgt_id,
+                    weight=1.0 - pair.ncd,
+                    metadata=metadata,
+                )
+            )
+            reverse_metadata = {
+                "ncd": pair.ncd,
+                "size_a": pair.size_b,  # swap sizes for reverse direction
+                "size_b": pair.size_a,
+            }
+            fs.add_relation(
+                Relation(
+                    type=RelationType.CLONED_FROM,
+                    source=tgt_id,
+                    target=src_id,
+                    weight=1.0 - pair.ncd,
+                    metadata=reverse_metadata,
+                )
+            )
+
+        if clone_pairs:
+            logger.debug(f"FactStore sync: {len(clone_pairs)} CLONED_FROM relations")
@@ -0,0 +1,38 @@
+"""Dependency analyzers — checks over the import graph.
+
+They read the import graph, so they require StructuralAnalyzer's output
+and are skipped when it is missing.
+"""
+
+from ..store import AnalysisStore
+
+
+class HexagonalAnalyzer:
+    name = "hexagonal"
+    requires: set[str] = {"structural"}
+    provides: set[str] = {"hexagonal"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Check imports against the declared ports-and-adapters rings."""
+        from ...architecture.hexagonal import check_hexagonal, resolve_spec
+
+        spec = resolve_spec(store.config.hexagonal)
+        if spec is None:
+            return
+        report = check_hexagonal(spec, store.structural.value.graph.adjacency)
+        store.hexagonal.set(report, produced_by=self.name)
+
+
+class CentralityAnalyzer:
+    name = "centrality"
+    requires: set[str] = {"file_syntax", "structural"}
+    provides: set[str] = {"centrality"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Rank symbols in the call graph and packages in the import graph."""
+        from ...graph.centrality import build_centrality, symbol_complexity
+        from ...signals.function_outliers import collect_functions
+
+        complexity = symbol_complexity(collect_functions(store.files, store.contents(store.files)))
+        report = build_centrality(store.files, store.structural.value.graph.adjacency, complexity)
+        store.centrality.set(report, produced_by=self.name)
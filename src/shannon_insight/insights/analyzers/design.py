@@ -0,0 +1,78 @@
+"""Design analyzers — how types, packages and directories are organised.
+
+They judge how code is organised rather than what it does.
+"""
+
+from ..store import AnalysisStore
+
+
+class GodClassAnalyzer:
+    name = "god_classes"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"god_classes"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Flag types with many, complex methods, Swift extensions merged in."""
+        from ...signals.type_sizes import collect_types, find_god_classes
+
+        files = store.scored_files
+        god_classes = find_god_classes(collect_types(files, store.contents(files)))
+        store.god_classes.set(god_classes, produced_by=self.name)
+
+
+class CohesionAnalyzer:
+    name = "low_cohesion"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"low_cohesion"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Flag types whose methods split into groups sharing no fields (LCOM4)."""
+        from ...signals.cohesion import collect_cohesion, find_low_cohesion
+
+        files = store.scored_files
+        low_cohesion = find_low_cohesion(collect_cohesion(files, store.contents(files)))
+        store.low_cohesion.set(low_cohesion, produced_by=self.name)
+
+
+class ApiSurfaceAnalyzer:
+    name = "api_surface"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"api_surface"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Count exported identifiers per package against the package's size."""
+        from ...signals.api_surface import measure_surface
+
+        files = store.scored_files
+        store.api_surface.set(measure_surface(files, store.contents(files)), produced_by=self.name)
+
+
+class VocabularyDriftAnalyzer:
+    name = "vocabulary_drift"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"vocabulary_drift"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Find directories that spell one concept several ways."""
+        from ...signals.vocabulary_drift import DATA_LANGUAGES, find_vocabulary_drift
+
+        files = {
+            path: syntax
+            for path, syntax in store.scored_files.items()
+            if syntax.language not in DATA_LANGUAGES
+        }
+        drifted = find_vocabulary_drift(files, store.contents(files))
+        store.vocabulary_drift.set(drifted, produced_by=self.name)
+
+
+class InformationDensityAnalyzer:
+    name = "information_density"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"information_density"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Measure compression, cross-file redundancy and vocabulary growth."""
+        from ...signals.information_density import analyze_density
+
+        report = analyze_density(store.files, store.contents(store.files))
+        store.information_density.set(report, produced_by=self.name)
@@ -0,0 +1,151 @@
+"""Function analyzers — checks over the functions the parsers extracted.
+
+Generated files are left out as they are from scoring, except as
+references for dead code. Analyzers that resolve calls across files use
+the import graph when StructuralAnalyzer has built it, so they wait for it
+without needing it.
+"""
+
+from pathlib import Path
+
+from ..store import AnalysisStore
+
+
+class DeadCodeAnalyzer:
+    name = "dead_code"
+    requires: set[str] = {"file_syntax"}
+    uses: set[str] = {"structural", "roles"}
+    provides: set[str] = {"dead_code"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Find functions nothing in the repository calls or mentions."""
+        from ...signals.dead_code import find_dead_code
+
+        # Every file counts for references, generated ones included
+        contents = store.contents(store.files)
+        imports = store.structural.value.graph.adjacency if store.structural.available else {}
+        dead = find_dead_code(store.scored_files, contents, imports, store.roles.get(default={}))
+        store.dead_code.set(dead, produced_by=self.name)
+
+
+class DeepNestingAnalyzer:
+    name = "deep_nesting"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"deep_nesting"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Flag functions nested deeper than the configured threshold."""
+        from ...signals.nesting import collect_nesting, find_deep_nesting
+
+        files = store.scored_files
+        deep = find_deep_nesting(
+            collect_nesting(files, store.contents(files)), store.config.nesting_threshold
+        )
+        store.deep_nesting.set(deep, produced_by=self.name)
+
+
+class FunctionFanAnalyzer:
+    name = "function_fan"
+    requires: set[str] = {"file_syntax"}
+    uses: set[str] = {"structural"}
+    provides: set[str] = {"function_fan"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Count the distinct callers and callees of every function."""
+        from ...graph.symbols import has_call_targets
+        from ...signals.function_fan import collect_fan
+
+        files = store.scored_files
+        if not has_call_targets(files):
+            return
+        imports = store.structural.value.graph.adjacency if store.structural.available else {}
+        store.function_fan.set(collect_fan(files, imports), produced_by=self.name)
+
+
+class FunctionOutlierAnalyzer:
+    name = "function_outliers"
+    requires: set[str] = {"file_syntax"}
+    uses: set[str] = {"function_stats"}  # its complexity bands spare a pass
+    provides: set[str] = {"function_outliers"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Flag unusually complex functions, with similar-sized references."""
+        from ...signals.function_outliers import collect_functions, find_function_outliers
+
+        files = store.scored_files
+        bands = store.function_stats.value.bands if store.function_stats.available else None
+        outliers = find_function_outliers(
+            collect_functions(files, store.contents(files)), bands=bands
+        )
+        store.function_outliers.set(outliers, produced_by=self.name)
+
+
+class FunctionStatsAnalyzer:
+    name = "function_stats"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"function_stats"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Aggregate function size and complexity per package, one file at a time."""
+        from ...signals.function_stats import aggregate_functions
+
+        stats = aggregate_functions(
+            (path, syntax, store.get_content(path) or "")
+            for path, syntax in store.scored_files.items()
+        )
+        store.function_stats.set(stats, produced_by=self.name)
+
+
+class ParameterAnalyzer:
+    name = "parameters"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"parameters"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Record the parameters of every function for long lists and data clumps."""
+        from ...signals.parameters import collect_parameters
+
+        store.parameters.set(collect_parameters(store.scored_files), produced_by=self.name)
+
+
+class NotebookDriftAnalyzer:
+    name = "notebook_drift"
+    requires: set[str] = {"file_syntax"}
+    uses: set[str] = {"git_history"}
+    provides: set[str] = {"notebook_drift"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Find functions copied between notebooks and modules that have diverged."""
+        from ...scanning.notebook import is_notebook
+        from ...signals.notebook_drift import find_notebook_drift
+
+        files = store.scored_files
+        if not any(is_notebook(path) for path in files):
+            return
+        last_changed: dict[str, int] = {}
+        if store.git_history.available:
+            for commit in store.git_history.value.commits:  # newest first
+                for path in commit.files:
+                    last_changed.setdefault(path, commit.timestamp)
+        drifts = find_notebook_drift(files, store.contents(files), last_changed)
+        store.notebook_drift.set(drifts, produced_by=self.name)
+
+
+class CoverageRiskAnalyzer:
+    name = "coverage_risk"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"coverage_risk"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Join the coverage report, if there is one, with function complexity."""
+        from ...coverage import load_line_coverage
+        from ...signals.coverage_risk import analyze_coverage_risk
+
+        root = Path(store.root_dir)
+        report = store.config.coverage_report
+        coverage = load_line_coverage(root, root / report if report else None)
+        if not coverage:
+            return
+        files = store.scored_files
+        risk = analyze_coverage_risk(files, store.contents(files), coverage)
+        store.coverage_risk.set(risk, produced_by=self.name)
@@ -0,0 +1,116 @@
+"""Hygiene analyzers — comment debt and code review rules over file contents.
+
+Each reads file contents, so it runs in Wave 1 while the content cache is
+still filled, and writes one report slot that its finder and the snapshot
+read. None depends on another; they only wait for scanning.
+"""
+
+from pathlib import Path
+
+from ..store import AnalysisStore
+
+
+def _sources(store: AnalysisStore):
+    """The scanned files as a SourceSet, for the source-level hygiene checks."""
+    from ...hygiene.sources import SourceSet
+
+    return SourceSet(
+        root=Path(store.root_dir),
+        syntax=store.files,
+        content=store.contents(store.files),
+    )
+
+
+class CommentDebtAnalyzer:
+    name = "comment_debt"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"comment_debt"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Extract TODO/FIXME/HACK/XXX comments with blame age and owner."""
+        from ...hygiene.todos import collect_comment_debt
+
+        use_git = store.session is not None and store.session.env.is_git_repo
+        items = collect_comment_debt(store.root_dir, store.contents(store.files), use_git=use_git)
+        store.comment_debt.set(items, produced_by=self.name)
+
+
+class DeprecationAnalyzer:
+    name = "deprecations"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"deprecations"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Find deprecated symbols and count their remaining call sites."""
+        from ...hygiene.deprecations import analyze_deprecations
+
+        store.deprecations.set(analyze_deprecations(_sources(store)), produced_by=self.name)
+
+
+class FormatDriftAnalyzer:
+    name = "format_drift"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"format_drift"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Count lines that drift from each language's canonical formatter."""
+        from ...hygiene.formatting import analyze_formatting
+
+        store.format_drift.set(analyze_formatting(_sources(store)), produced_by=self.name)
+
+
+class CryptoAnalyzer:
+    name = "crypto"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"crypto"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Inventory crypto API usage and check it against the crypto policy."""
+        from ...hygiene.crypto import analyze_crypto, resolve_policy
+
+        policy = resolve_policy(store.config.crypto_policy)
+        report = analyze_crypto(store.files, store.contents(store.files), policy)
+        store.crypto.set(report, produced_by=self.name)
+
+
+class AuthAnalyzer:
+    name = "auth"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"auth"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Apply the JWT/auth flow review rules."""
+        from ...hygiene.auth import analyze_auth
+
+        store.auth.set(
+            analyze_auth(store.files, store.contents(store.files)), produced_by=self.name
+        )
+
+
+class ErrorHygieneAnalyzer:
+    name = "error_hygiene"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"error_hygiene"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Apply the Go error hygiene rules."""
+        from ...hygiene.errors import analyze_errors
+
+        contents = store.contents(path for path in store.files if path.endswith(".go"))
+        store.error_hygiene.set(analyze_errors(contents), produced_by=self.name)
+
+
+class LiteralAnalyzer:
+    name = "literals"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"literals"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Count magic numbers per file and find literals repeated across files."""
+        from ...hygiene.literals import analyze_literals
+
+        files = store.scored_files
+        languages = {path: syntax.language for path, syntax in files.items()}
+        threshold = store.config.literal_repeat_threshold
+        report = analyze_literals(languages, store.contents(files), threshold)
+        store.literals.set(report, produced_by=self.name)
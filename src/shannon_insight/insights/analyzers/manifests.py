@@ -0,0 +1,83 @@
+"""Manifest analyzers — one kind of non-code file each.
+
+Each only has work when the codebase has files of its kind; without them
+it returns without setting its slot, so its finder and the snapshot skip
+it the same way as when the analyzer is disabled.
+"""
+
+from pathlib import Path
+
+from ..store import AnalysisStore
+
+
+def _contents_of(store: AnalysisStore, language: str) -> dict[str, str]:
+    """Content of every scanned file in *language*."""
+    return store.contents(
+        path for path, syntax in store.files.items() if syntax.language == language
+    )
+
+
+class SqlAnalyzer:
+    name = "sql"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"sql"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Measure SQL statements and stored procedures, when there are SQL files."""
+        from ...signals.sql_statements import collect_sql
+
+        contents = _contents_of(store, "sql")
+        if contents:
+            store.sql.set(collect_sql(store.files, contents), produced_by=self.name)
+
+
+class TerraformAnalyzer:
+    name = "terraform"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"terraform"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Measure Terraform modules, when there are HCL files."""
+        from ...signals.terraform_modules import collect_terraform
+
+        contents = _contents_of(store, "hcl")
+        if contents:
+            store.terraform.set(collect_terraform(store.files, contents), produced_by=self.name)
+
+
+class YamlAnalyzer:
+    name = "yaml"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"yaml"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Measure YAML structure, when there are YAML files."""
+        from ...signals.yaml_manifests import collect_yaml
+
+        contents = _contents_of(store, "yaml")
+        if contents:
+            store.yaml.set(collect_yaml(store.files, contents), produced_by=self.name)
+
+
+class MobileResourceAnalyzer:
+    name = "mobile"
+    requires: set[str] = {"file_syntax"}
+    provides: set[str] = {"mobile"}
+
+    def analyze(self, store: AnalysisStore) -> None:
+        """Check Android and iOS resource files, when there are any."""
+        from ...diligence.inventory import tracked_files
+        from ...file_ops import should_skip_file
+        from ...signals.mobile_resources import collect_mobile_resources, resource_kind
+
+        root = Path(store.root_dir)
+        excludes = store.config.exclude_patterns
+        paths = [
+            path
+            for path in tracked_files(root)
+            if resource_kind(path) is not None and not should_skip_file(root / path, excludes)
+        ]
+        if not paths:
+            return
+        report = collect_mobile_resources(root, paths, store.contents(store.files))
+        store.mobile.set(report, produced_by=self.name)
@@ -1,11 +1,5 @@
-"""StructuralAnalyzer — wraps existing AnalysisEngine.
+"""StructuralAnalyzer — wraps existing AnalysisEngine."""
 
-Also runs Phase 3 clone detection (NCD) on file contents.
-"""
-
-from pathlib import Path
-
-from ...graph.clone_detection import detect_clones
 from ...graph.engine import AnalysisEngine
 from ...infrastructure.entities import EntityId, EntityType
 from ...infrastructure.relations import Relation, RelationType
@@ -19,7 +13,7 @@ logger = get_logger(__name__)
 class StructuralAnalyzer:
     name = "structural"
     requires: set[str] = {"file_syntax"}
-    provides: set[str] = {"structural", "clone_pairs"}
+    provides: set[str] = {"structural"}
 
     def __init__(
         self,
@@ -35,6 +29,13 @@ class StructuralAnalyzer:
         if not store.file_syntax.available:
             return
 
+        # Imports of collapsed identical copies resolve to the analyzed copy
+        aliases = {
+            copy: group.canonical
+            for group in store.duplicate_files.get(default=[])
+            for copy in group.copies
+        }
+
         # Pass content getter for cached file reads (avoids re-reading from disk)
         engine = AnalysisEngine(
             list(store.file_syntax.value.values()),
@@ -43,6 +44,7 @@ class StructuralAnalyzer:
             pagerank_damping=self.pagerank_damping,
             pagerank_iterations=self.pagerank_iterations,
             pagerank_tolerance=self.pagerank_tolerance,
+            aliases=aliases,
         )
         result = engine.run()
         store.structural.set(result, produced_by=self.name)
@@ -51,15 +53,12 @@ class StructuralAnalyzer:
         # Sync structural signals to FactStore
         self._sync_to_fact_store(store, result)
 
-        # Phase 3: Clone detection via NCD
-        self._detect_clones(store)
-
     def _sync_to_fact_store(self, store: AnalysisStore, result) -> None:
         """Sync structural analysis results to FactStore.
 
         Writes per-file graph signals (PAGERANK, BETWEENNESS, IN_DEGREE,
         OUT_DEGREE, BLAST_RADIUS_SIZE, COMMUNITY, DEPTH, IS_ORPHAN,
-        PHANTOM_IMPORT_COUNT, COMPRESSION_RATIO, COGNITIVE_LOAD) and
+        PHANTOM_IMPORT_COUNT, COMPRESSION_RATIO, COGNITIVE_LOAD, HALSTEAD_*) and
         global signals (MODULARITY, CYCLE_COUNT, CENTRALITY_GINI).
 
         Also writes IMPORTS relations for every dependency edge.
@@ -85,6 +84,9 @@ class StructuralAnalyzer:
             fs.set_signal(entity_id, Signal.PHANTOM_IMPORT_COUNT, fa.phantom_import_count)
             fs.set_signal(entity_id, Signal.COMPRESSION_RATIO, fa.compression_ratio)
             fs.set_signal(entity_id, Signal.COGNITIVE_LOAD, fa.cognitive_load)
+            fs.set_signal(entity_id, Signal.HALSTEAD_VOLUME, fa.halstead_volume)
+            fs.set_signal(entity_id, Signal.HALSTEAD_DIFFICULTY, fa.halstead_difficulty)
+            fs.set_signal(entity_id, Signal.HALSTEAD_EFFORT, fa.halstead_effort)
 
         # Global signals
         codebase_id = EntityId(EntityType.CODEBASE, store.root_dir)
@@ -109,96 +111,3 @@ class StructuralAnalyzer:
             f"FactStore sync: {len(result.files)} files, "
             f"{sum(len(t) for t in graph.adjacency.values())} IMPORTS relations"
         )
-
-    def _detect_clones(self, store: AnalysisStore) -> None:
-        """Run NCD clone detection on file contents."""
-        root = Path(store.root_dir) if store.root_dir else Path.cwd()
-
-        # Get threshold from config if available
-        clone_threshold = 0.30  # default
-        clone_min_lines = 20  # default (skip trivial files)
-        if store.session is not None and store.session.config is not None:
-            thresholds = store.session.config.thresholds
-            clone_threshold = thresholds.clone_ncd_threshold
-            clone_min_lines = thresholds.clone_min_lines
-
-        # Get file contents from cache or disk
-        file_contents: dict[str, bytes] = {}
-        for fm in store.file_syntax.value.values():
-            # Skip files below minimum line threshold
-            if fm.lines < clone_min_lines:
-                continue
-
-            # Try cache first
-            content = store.get_content(fm.path)
-            if content is not None:
-                file_contents[fm.path] = content.encode("utf-8")
-            else:
-                # Fallback to disk read
-                try:
-                    full_path = root / fm.path
-                    file_contents[fm.path] = full_path.read_bytes()
-                except OSError:
-                    pass
-
-        if len(file_contents) < 2:
-            return
-
-        # Get roles if available (for TEST/MIGRATION exclusion)
-        roles: dict[str, str] = {}
-        if store.roles.available:
-            roles = store.roles.value
-
-        clone_pairs = detect_clones(file_contents, roles, threshold=clone_threshold)
-        store.clone_pairs.set(clone_pairs, produced_by=self.name)
-        logger.debug(f"Clone detection: {len(clone_pairs)} pairs found")
-
-        # Sync clone pairs as CLONED_FROM relations to FactStore
-        self._sync_clone_relations(store, clone_pairs)
-
-    def _sync_clone_relations(self, store: AnalysisStore, clone_pairs: list) -> None:
-        """Add CLONED_FROM relations to FactStore for pattern detection.
-
-        COPY_PASTE_CLONE pattern checks for CLONED_FROM relations with ncd metadata.
-        """
-        if not hasattr(store, "fact_store"):
-            return
-
-        fs = store.fact_store
-
-        for pair in clone_pairs:
-            src_id = EntityId(EntityType.FILE, pair.file_a)
-            tgt_id = EntityId(EntityType.FILE, pair.file_b)
-            metadata = {
-                "ncd": pair.ncd,
-                "size_a": pair.size_a,
-                "size_b": pair.size_b,
-            }
-            # CLONED_FROM is symmetric — store in BOTH directions so FILE_PAIR
-            # predicates find the relation regardless of pair iteration order.
-            fs.add_relation(
-                Relation(
-                    type=RelationType.CLONED_FROM,
-                    source=src_id,
-                    target=tgt_id,
-                    weight=1.0 - pair.ncd,
-                    metadata=metadata,
-                )
-            )
-            reverse_metadata = {
-                "ncd": pair.ncd,
-                "size_a": pair.size_b,  # swap sizes for reverse direction
-                "size_b": pair.size_a,
-            }
-            fs.add_relation(
-                Relation(
-                    type=RelationType.CLONED_FROM,
-                    source=tgt_id,
-                    target=src_id,
-                    weight=1.0 - pair.ncd,
-                    metadata=reverse_metadata,
-                )
-            )
-
-        if clone_pairs:
-            logger.debug(f"FactStore sync: {len(clone_pairs)} CLONED_FROM relations")
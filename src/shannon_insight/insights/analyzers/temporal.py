@@ -2,6 +2,7 @@
 
 import subprocess
 from pathlib import Path
+from typing import Optional
 
 from ...graph.distance import compute_author_distances
 from ...infrastructure.entities import EntityId, EntityType
@@ -20,16 +21,27 @@ _MIN_COMMITS = 10
 
 class TemporalAnalyzer:
     name = "temporal"
-    requires: set[str] = set()  # No deps - git extraction is independent
+    # Git extraction needs nothing and runs in prepare(), overlapping with file
+    # scanning; the analysis itself is restricted to the scanned files
+    requires: set[str] = {"file_syntax"}
     provides: set[str] = {"git_history", "cochange", "churn", "author_distances"}
 
     def __init__(self, max_commits: int = 5000, min_commits: int = _MIN_COMMITS):
         self.max_commits = max_commits
         self.min_commits = min_commits
+        self._history: Optional[GitHistory] = None
+        self._prepared = False
 
-    def analyze(self, store: AnalysisStore) -> None:
+    def prepare(self, store: AnalysisStore) -> None:
+        """Extract git history (independent of scanned files)."""
         extractor = GitExtractor(store.root_dir, max_commits=self.max_commits)
-        history = extractor.extract()
+        self._history = extractor.extract()
+        self._prepared = True
+
+    def analyze(self, store: AnalysisStore) -> None:
+        if not self._prepared:
+            self.prepare(store)
+        history = self._history
 
         if history is None:
             logger.info("No git history available — temporal analysis skipped")
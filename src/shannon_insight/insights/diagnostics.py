@@ -78,6 +78,7 @@ def run_diagnostics(
     _check_codebase_size(store, report)
     _check_git_history_depth(store, report)
     _check_orphan_ratio(store, report)
+    _check_parse_errors(store, report)
 
     return report
 
@@ -338,3 +339,30 @@ def _check_orphan_ratio(store: AnalysisStore, report: DiagnosticReport) -> None:
                 detail="These files have no importers. May be entry points, utilities, or dead code.",
             )
         )
+
+
+def _check_parse_errors(store: AnalysisStore, report: DiagnosticReport) -> None:
+    """Report files parsed around syntax errors, and the lines left out."""
+    if not store.file_syntax.available:
+        return
+    from ..scanning.recovery import describe, error_lines
+
+    broken = {
+        path: syntax.parse_errors
+        for path, syntax in store.file_syntax.value.items()
+        if syntax.parse_errors
+    }
+    if not broken:
+        return
+    skipped = sum(error_lines(spans) for spans in broken.values())
+    worst = sorted(broken, key=lambda path: (-error_lines(broken[path]), path))[:5]
+    report.issues.append(
+        DiagnosticIssue(
+            category="data",
+            severity="info",
+            message=(
+                f"Syntax errors in {len(broken)} files: {skipped} lines left out of their metrics"
+            ),
+            detail="; ".join(f"{path}: {describe(broken[path])}" for path in worst),
+        )
+    )
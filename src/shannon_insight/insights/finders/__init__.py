@@ -5,7 +5,8 @@ Architecture:
 - Pattern executor runs patterns against FactStore
 - Produces infrastructure.Finding objects
 
-Persistence finders (require database) work with historical snapshots.
+Report finders turn the reports Wave 1 analyzers leave in the store into
+findings. Persistence finders (require database) work with historical snapshots.
 """
 
 from .architecture_erosion import ArchitectureErosionFinder
@@ -19,6 +20,7 @@ from .registry import (
     get_patterns_by_phase,
     get_patterns_by_scope,
 )
+from .reports import ReportFinder, get_comment_debt_finder, get_report_finders
 
 
 def get_persistence_finders() -> list:
@@ -42,6 +44,10 @@ __all__ = [
     "get_patterns_by_category",
     "get_patterns_by_scope",
     "get_hotspot_filtered_patterns",
+    # Report finders (analyzer reports in the store)
+    "ReportFinder",
+    "get_comment_debt_finder",
+    "get_report_finders",
     # Persistence finders (require database)
     "ArchitectureErosionFinder",
     "ChronicProblemFinder",
@@ -0,0 +1,272 @@
+"""Report finders — findings from the reports analyzers leave in the store.
+
+Most checks are not patterns over FactStore signals: an analyzer writes a
+report (dead functions, crypto policy violations, repeated literals, ...)
+to its slot, and the module that built the report knows how to turn it
+into findings. A ReportFinder connects the two: it requires the slot,
+returns nothing when the analyzer was skipped, disabled or failed, and
+passes the report and the config to the converter otherwise.
+
+REPORT_FINDERS lists them in the order their findings are ranked among
+equals; comment debt has its own finder, as its findings are snapshotted
+for their lifecycle but never ranked.
+"""
+
+from __future__ import annotations
+
+from typing import TYPE_CHECKING, Any, Callable, Optional
+
+if TYPE_CHECKING:
+    from ...config import AnalysisConfig
+    from ..models import Finding
+    from ..store import AnalysisStore
+
+# (report, config) -> findings
+Convert = Callable[[Any, "AnalysisConfig"], list["Finding"]]
+
+
+class ReportFinder:
+    """Findings from the report in one store slot."""
+
+    api_version = "2.0"
+    error_mode = "skip"
+    hotspot_filtered = False
+    tier_minimum = "ABSOLUTE"
+    deprecated = False
+    deprecation_note: Optional[str] = None
+
+    def __init__(self, slot: str, convert: Convert):
+        self.name = slot
+        self.requires = {slot}
+        self._convert = convert
+
+    def find(self, store: AnalysisStore) -> list[Finding]:
+        slot = getattr(store, self.name)
+        if not slot.available:
+            return []
+        return self._convert(slot.value, store.config)
+
+
+def _duplicate_files(groups: list, config: AnalysisConfig) -> list[Finding]:
+    """One duplicate_files finding per group of identical files."""
+    from ..models import Evidence, Finding
+
+    findings = []
+    for group in groups:
+        n_copies = len(group.copies)
+        evidence = [
+            Evidence(
+                signal="copies",
+                value=float(n_copies + 1),
+                percentile=0.0,
+                description=f"{n_copies + 1} identical copies",
+            ),
+            Evidence(
+                signal="lines",
+                value=float(group.lines),
+                percentile=0.0,
+                description=f"{group.lines} lines each",
+            ),
+        ]
+        if group.symlinks:
+            evidence.append(
+                Evidence(
+                    signal="symlinks",
+                    value=float(len(group.symlinks)),
+                    percentile=0.0,
+                    description=f"{len(group.symlinks)} via symlink",
+                )
+            )
+        findings.append(
+            Finding(
+                finding_type="duplicate_files",
+                # More copies and more duplicated lines weigh more
+                severity=min(0.7, 0.3 + 0.1 * n_copies + group.lines / 10_000),
+                title=f"{group.canonical} is duplicated at {n_copies} other path(s)",
+                files=group.paths,
+                evidence=evidence,
+                suggestion=(
+                    "Only the first path was analyzed. Depend on one copy, "
+                    "or exclude the vendored tree with exclude_patterns."
+                ),
+                effort="LOW",
+            )
+        )
+    return findings
+
+
+def _duplication(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.duplication import to_findings
+
+    return to_findings(report)
+
+
+def _dead_code(dead: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.dead_code import to_findings
+
+    return to_findings(dead)
+
+
+def _function_fan(functions: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.function_fan import find_load_bearing, find_orchestrators, to_findings
+
+    fan_in, fan_out = config.fan_in_threshold, config.fan_out_threshold
+    return to_findings(
+        find_load_bearing(functions, fan_in),
+        find_orchestrators(functions, fan_out),
+        fan_in,
+        fan_out,
+    )
+
+
+def _function_outliers(outliers: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.function_outliers import to_findings
+
+    return to_findings(outliers)
+
+
+def _deep_nesting(deep: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.nesting import to_findings
+
+    return to_findings(deep, config.nesting_threshold)
+
+
+def _god_classes(types: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.type_sizes import to_findings
+
+    return to_findings(types)
+
+
+def _low_cohesion(types: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.cohesion import to_findings
+
+    return to_findings(types)
+
+
+def _notebook_drift(drifts: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.notebook_drift import to_findings
+
+    return to_findings(drifts)
+
+
+def _parameters(functions: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.parameters import find_data_clumps, find_long_parameter_lists, to_findings
+
+    threshold = config.parameter_threshold
+    return to_findings(
+        find_long_parameter_lists(functions, threshold), find_data_clumps(functions), threshold
+    )
+
+
+def _sql(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.sql_statements import to_findings
+
+    return to_findings(report.long_procedures())
+
+
+def _terraform(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.terraform_modules import to_findings
+
+    return to_findings(report)
+
+
+def _vocabulary_drift(drifted: list, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.vocabulary_drift import to_findings
+
+    return to_findings(drifted)
+
+
+def _yaml(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.yaml_manifests import to_findings
+
+    return to_findings(report)
+
+
+def _mobile(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.mobile_resources import to_findings
+
+    return to_findings(report)
+
+
+def _crypto(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...hygiene.crypto import to_findings
+
+    return to_findings(report.violations)
+
+
+def _auth(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...hygiene.auth import to_findings
+
+    return to_findings(report.issues)
+
+
+def _error_hygiene(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...hygiene.errors import to_findings
+
+    return to_findings(report.issues)
+
+
+def _hexagonal(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...architecture.hexagonal import to_findings
+
+    return to_findings(report.violations)
+
+
+def _coverage_risk(risk: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.coverage_risk import to_findings
+
+    return to_findings(risk)
+
+
+def _api_surface(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...signals.api_surface import to_findings
+
+    return to_findings(report)
+
+
+def _literals(report: Any, config: AnalysisConfig) -> list[Finding]:
+    from ...hygiene.literals import to_findings
+
+    return to_findings(report)
+
+
+def _comment_debt(items: list, config: AnalysisConfig) -> list[Finding]:
+    from ...hygiene.todos import to_findings
+
+    return to_findings(items)
+
+
+REPORT_FINDERS = (
+    ("duplicate_files", _duplicate_files),
+    ("duplication", _duplication),
+    ("dead_code", _dead_code),
+    ("function_fan", _function_fan),
+    ("function_outliers", _function_outliers),
+    ("deep_nesting", _deep_nesting),
+    ("god_classes", _god_classes),
+    ("low_cohesion", _low_cohesion),
+    ("notebook_drift", _notebook_drift),
+    ("parameters", _parameters),
+    ("sql", _sql),
+    ("terraform", _terraform),
+    ("vocabulary_drift", _vocabulary_drift),
+    ("yaml", _yaml),
+    ("mobile", _mobile),
+    ("crypto", _crypto),
+    ("auth", _auth),
+    ("error_hygiene", _error_hygiene),
+    ("hexagonal", _hexagonal),
+    ("coverage_risk", _coverage_risk),
+    ("api_surface", _api_surface),
+    ("literals", _literals),
+)
+
+
+def get_report_finders() -> list[ReportFinder]:
+    """A finder for every report slot whose findings are ranked."""
+    return [ReportFinder(slot, convert) for slot, convert in REPORT_FINDERS]
+
+
+def get_comment_debt_finder() -> ReportFinder:
+    """The finder of TODO/FIXME comments, kept apart from the ranked findings."""
+    return ReportFinder("comment_debt", _comment_debt)
@@ -8,12 +8,13 @@ from typing import TYPE_CHECKING, Callable, Optional
 
 from ..logging_config import get_logger
 from ..persistence.models import TensorSnapshot
+from ..scanning.gobuild import go_build_config
 from ..scanning.syntax_extractor import SyntaxExtractor
 from ..session import AnalysisSession
 from .analyzers import get_default_analyzers, get_wave2_analyzers
-from .finders import get_persistence_finders
-from .kernel_toposort import resolve_analyzer_order
+from .finders import get_comment_debt_finder, get_persistence_finders, get_report_finders
 from .models import InsightResult, StoreSummary
+from .scheduler import AnalyzerScheduler
 from .store import AnalysisStore
 from .validation import (
     PhaseValidationError,
@@ -75,6 +76,7 @@ class InsightKernel:
         self.root_dir = str(session.env.root)
         self._analyzers = get_default_analyzers(session.config)
         self._wave2_analyzers = get_wave2_analyzers()
+        self._report_finders = get_report_finders()
         self._persistence_finders = get_persistence_finders() if enable_persistence_finders else []
         self._enable_provenance = enable_provenance
         self._debug_exporter: DebugExporter | None = None
@@ -115,11 +117,24 @@ class InsightKernel:
             enable_provenance=self._enable_provenance,
         )
 
+        # Analyzer prepare stages (git extraction) need no scan results and
+        # run in the background while files are parsed
+        scheduler = AnalyzerScheduler(
+            self._analyzers,
+            max_workers=self.session.config.analyzer_workers,
+            timeout=_ANALYZER_TIMEOUT_SECONDS,
+        )
+        scheduler.prepare(store)
+
         # Phase 1: Extract syntax (reads files once, caches content)
         # This replaces the old separate _scan() + _extract_syntax() steps.
         # The SyntaxExtractor reads files once and caches content for later reuse.
         _progress("Scanning files...")
         self._extract_syntax(store)
+        if self.session.config.collapse_identical_files:
+            self._collapse_duplicates(store)
+        if not self.session.config.include_generated:
+            self._detect_generated(store)
         logger.info(f"Scanned {store.file_count} files")
 
         # Sync scanned files to FactStore as entities with basic signals
@@ -129,6 +144,7 @@ class InsightKernel:
             self._debug_exporter.export_scanning(store)
 
         if store.file_count == 0:
+            scheduler.close()
             empty_result = InsightResult(
                 findings=[],
                 store_summary=StoreSummary(),
@@ -148,28 +164,13 @@ class InsightKernel:
             except PhaseValidationError as e:
                 logger.warning(f"Scanning validation failed: {e}")
 
-        # Phase 2a: Run Wave 1 analyzers (topologically sorted by requires/provides)
+        # Phase 2a: Run Wave 1 analyzers as a DAG; independent ones run concurrently
         _progress("Analyzing dependencies...")
-        for analyzer in self._resolve_order():
-            if analyzer.requires.issubset(store.available):
-                try:
-                    _progress(f"Running {analyzer.name}...")
-                    # Run with timeout to prevent hangs
-                    _run_with_timeout(
-                        lambda a=analyzer: a.analyze(store),
-                        _ANALYZER_TIMEOUT_SECONDS,
-                        analyzer.name,
-                    )
-                    logger.debug(f"Analyzer {analyzer.name} completed")
-
-                    # Debug export after each analyzer
-                    if self._debug_exporter:
-                        self._export_after_analyzer(analyzer.name, store)
-
-                except AnalyzerTimeoutError as e:
-                    logger.warning(str(e))
-                except Exception as e:
-                    logger.warning(f"Analyzer {analyzer.name} failed: {e}")
+        scheduler.run(
+            store,
+            on_start=lambda a: _progress(f"Running {a.name}..."),
+            on_done=lambda a: self._export_after_analyzer(a.name, store),
+        )
 
         # Phase validation: after structural analysis
         if self.session.config.enable_validation:
@@ -270,6 +271,13 @@ class InsightKernel:
             )
             findings.append(finding)
 
+        # Findings from the reports Wave 1 analyzers left in the store
+        for finder in self._report_finders:
+            try:
+                findings.extend(finder.find(store))
+            except Exception as e:
+                logger.warning(f"Finder {finder.name} failed: {e}")
+
         # Phase 3b: Run persistence finders (need DB connection)
         if self._persistence_finders:
             _progress("Checking history...")
@@ -284,11 +292,21 @@ class InsightKernel:
             for issue in diagnostic_report.issues:
                 logger.debug(f"  [{issue.severity}] {issue.message}")
 
-        # Phase 4: Deduplicate, rank, and cap
+        # Phase 4: Deduplicate, collapse noisy subtrees, rank, and cap
         _progress("Ranking findings...")
         from .ranking import deduplicate_findings
 
         findings = deduplicate_findings(findings)
+        if self.session.config.collapse_noisy_findings:
+            from .noise import collapse_noisy_findings
+
+            findings = collapse_noisy_findings(
+                findings,
+                list(store.files),
+                self.session.config.noise_threshold,
+                self.session.config.noise_min_files,
+            )
+        findings = self._apply_triage(findings)
         findings.sort(key=lambda f: f.severity, reverse=True)
         capped = findings[:max_findings]
 
@@ -296,6 +314,10 @@ class InsightKernel:
             findings=capped,
             store_summary=self._summarize(store),
         )
+        try:
+            result.comment_debt = get_comment_debt_finder().find(store)
+        except Exception as e:
+            logger.warning(f"Finder comment_debt failed: {e}")
         result.diagnostic_report = diagnostic_report
 
         # Debug export: findings and index
@@ -338,7 +360,13 @@ class InsightKernel:
         Uses tree-sitter if available, falls back to regex.
         """
         root = Path(self.root_dir)
-        extractor = SyntaxExtractor()
+        config = self.session.config
+        extractor = SyntaxExtractor(
+            c_defines=config.c_defines,
+            c_conditionals=config.c_conditionals,
+            grammars=config.grammars,
+            go_build=go_build_config(config),
+        )
 
         # Get file paths from environment (pre-discovered) or discover now
         if self.session.env.file_paths:
@@ -361,6 +389,52 @@ class InsightKernel:
             f"cached: {len(store._content_cache)})"
         )
 
+    def _collapse_duplicates(self, store: AnalysisStore) -> None:
+        """Keep one canonical copy of each group of content-identical files.
+
+        The other copies are dropped from file_syntax and the content cache,
+        so every later phase analyzes the content once.
+        """
+        from ..scanning.duplicates import find_identical_files
+
+        files = store.files
+        groups = find_identical_files(
+            {path: store.get_content(path) or "" for path in files},
+            {path: syntax.lines for path, syntax in files.items()},
+            root=Path(self.root_dir),
+        )
+        for group in groups:
+            for path in group.copies:
+                del files[path]
+                store._content_cache.pop(path, None)
+        store.duplicate_files.set(groups, produced_by="scanning")
+        if groups:
+            copies = sum(len(g.copies) for g in groups)
+            logger.info(f"Collapsed {copies} identical copies of {len(groups)} files")
+
+    def _detect_generated(self, store: AnalysisStore) -> None:
+        """Record the generated files, left out of entropy and complexity scoring."""
+        from ..scanning.generated import find_generated_files
+
+        generated = find_generated_files(
+            {path: store.get_content(path) or "" for path in store.files}
+        )
+        store.generated_files.set(generated, produced_by="scanning")
+        if generated:
+            logger.info(f"Excluded {len(generated)} generated files from scoring")
+
+    def _apply_triage(self, findings: list) -> list:
+        """Drop the findings dismissed or suppressed in the triage file."""
+        from .triage import apply_triage, load_triage
+
+        path = Path(self.root_dir) / self.session.config.triage_file
+        try:
+            triage = load_triage(path)
+        except (ValueError, KeyError, TypeError) as e:  # JSONDecodeError is a ValueError
+            logger.warning(f"Ignoring triage file {path}: {e}")
+            return findings
+        return apply_triage(findings, triage)
+
     def _sync_entities(self, store: AnalysisStore) -> None:
         """Sync file_syntax to FactStore entities with basic signals."""
         from ..infrastructure.entities import Entity, EntityId, EntityType
@@ -392,12 +466,13 @@ class InsightKernel:
         Returns absolute paths to source files.
         """
         from ..scanning import get_all_known_extensions
-        from ..scanning.languages import SKIP_DIRS
+        from ..scanning.grammars import grammar_extensions
+        from ..scanning.languages import SKIP_DIRS, detect_language, read_shebang
 
         root = Path(self.root_dir)
-        known_exts = get_all_known_extensions()
-        file_paths: list[Path] = []
         config = self.session.config
+        known_exts = get_all_known_extensions() | grammar_extensions(config.grammars)
+        file_paths: list[Path] = []
 
         for p in root.rglob("*"):
             if not p.is_file():
@@ -413,10 +488,12 @@ class InsightKernel:
             if should_skip_file(p, config.exclude_patterns):
                 continue
 
-            # Check extension
+            # Check extension, or the name and shebang line of build files and scripts
             ext = p.suffix.lower()
             if ext not in known_exts:
-                continue
+                head = None if ext else read_shebang(p)
+                if detect_language(p, head) == "unknown":
+                    continue
 
             # Check file size
             try:
@@ -434,21 +511,12 @@ class InsightKernel:
 
         return file_paths
 
-    def _resolve_order(self) -> list:
-        """Topologically sort analyzers by requires/provides.
-
-        Uses graphlib.TopologicalSorter (via kernel_toposort) to order
-        analyzers. Detects cycles and slot collisions at startup time
-        instead of silently appending unresolvable analyzers.
-        """
-        return resolve_analyzer_order(self._analyzers)
-
     def _run_persistence_finders(self, findings: list) -> None:
         """Run persistence-based finders with a temporary DB connection."""
         from ..persistence import HistoryDB
 
         try:
-            with HistoryDB(self.root_dir) as db:
+            with HistoryDB(self.root_dir, url=self.session.config.history_url) as db:
                 for finder in self._persistence_finders:
                     try:
                         findings.extend(finder.find(store=None, db_conn=db.conn))
@@ -9,6 +9,10 @@ Key features:
     - Cycle detection: Catches cycles immediately, not at runtime
     - Wave 2 support: run_last analyzers are sorted to the end
     - Diamond handling: Supports diamond dependencies correctly
+    - Optional inputs: ``uses`` slots order analyzers like ``requires``
+      but never cause a skip
+
+The same graph drives concurrent execution in scheduler.py.
 
 Inspired by SonarQube's DirectAcyclicGraph.sort() for MeasureComputers.
 """
@@ -66,6 +70,29 @@ def _toposort_analyzers(analyzers: list[Any]) -> list[Any]:
     if not analyzers:
         return []
 
+    ts, name_to_analyzer = build_analyzer_graph(analyzers)
+
+    # Execute topological sort
+    try:
+        order = list(ts.static_order())
+    except CycleError as e:
+        raise AnalyzerCycleError(f"Analyzer dependency cycle detected: {e}") from e
+
+    # Convert names back to analyzer objects
+    return [name_to_analyzer[name] for name in order if name in name_to_analyzer]
+
+
+def build_analyzer_graph(
+    analyzers: list[Any],
+) -> tuple[TopologicalSorter[str], dict[str, Any]]:
+    """Dependency graph of one wave of analyzers, keyed by analyzer name.
+
+    Returns:
+        (sorter, name -> analyzer); the sorter is not yet prepared
+
+    Raises:
+        SlotCollisionError: If two analyzers provide the same slot
+    """
     # Build slot -> analyzer_name mapping
     provides_map: dict[str, str] = {}
     for analyzer in analyzers:
@@ -87,8 +114,9 @@ def _toposort_analyzers(analyzers: list[Any]) -> list[Any]:
         # Add node (even if no dependencies)
         ts.add(name)
 
-        # Add edges for each requirement
-        for req in getattr(analyzer, "requires", set()):
+        # Add edges for each requirement (optional inputs order the same way)
+        inputs = set(getattr(analyzer, "requires", set())) | set(getattr(analyzer, "uses", set()))
+        for req in inputs:
             if req in provides_map:
                 # This analyzer depends on whoever provides req
                 provider = provides_map[req]
@@ -98,11 +126,4 @@ def _toposort_analyzers(analyzers: list[Any]) -> list[Any]:
             # - A requirement from another wave (Wave 2 depending on Wave 1)
             # We don't error here; the kernel skips at runtime if not available
 
-    # Execute topological sort
-    try:
-        order = list(ts.static_order())
-    except CycleError as e:
-        raise AnalyzerCycleError(f"Analyzer dependency cycle detected: {e}") from e
-
-    # Convert names back to analyzer objects
-    return [name_to_analyzer[name] for name in order if name in name_to_analyzer]
+    return ts, name_to_analyzer
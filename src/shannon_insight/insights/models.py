@@ -88,6 +88,16 @@ class Evidence:
     description: str  # "top 3% by PageRank"
 
 
+@dataclass
+class Patch:
+    """A machine-applicable fix: lines start_line..end_line of path become replacement."""
+
+    path: str
+    start_line: int
+    end_line: int
+    replacement: str  # the new lines, without a trailing newline
+
+
 @dataclass
 class Finding:
     finding_type: str  # "high_risk_hub", "hidden_coupling", etc.
@@ -99,6 +109,8 @@ class Finding:
     confidence: float = 1.0  # 0.0-1.0, how sure we are (margin-based)
     effort: str = "MEDIUM"  # LOW | MEDIUM | HIGH
     scope: str = "FILE"  # FILE | FILE_PAIR | MODULE | MODULE_PAIR | CODEBASE
+    identity_hint: Optional[str] = None  # tells apart same-type findings on one file
+    patches: list[Patch] = field(default_factory=list)  # fixes that apply as they are
 
 
 @dataclass
@@ -116,3 +128,5 @@ class InsightResult:
     findings: list[Finding]
     store_summary: StoreSummary
     diagnostic_report: object = None  # Optional DiagnosticReport
+    # TODO/FIXME comment findings: snapshotted for lifecycle, never ranked
+    comment_debt: list[Finding] = field(default_factory=list)
@@ -0,0 +1,170 @@
+"""Noise suppression: collapse a finding type that covers a whole subtree.
+
+When one finding type fires on most files of a directory subtree, the
+files rarely have a problem each: the subtree is usually generated or
+third-party code (a vendored SDK, protoc output, a migrations folder) and
+the findings bury everything else in the report. Such findings are
+collapsed into one finding of the same type for the directory, whose
+suggestion is the exclude pattern that would drop the subtree.
+
+A subtree is collapsed for a finding type when the type fires on at least
+``min_files`` of its files and on at least ``threshold`` of them. Only the
+topmost such directory is collapsed, never the project root, and only
+findings about a single file take part: a finding about two files (a
+hidden coupling) says something about both.
+"""
+
+from __future__ import annotations
+
+from collections import defaultdict
+from dataclasses import dataclass
+from typing import TYPE_CHECKING
+
+if TYPE_CHECKING:
+    from .models import Finding
+
+DEFAULT_THRESHOLD = 0.8
+DEFAULT_MIN_FILES = 10
+
+COLLAPSED_HINT = "collapsed_subtree"
+
+
+@dataclass(frozen=True)
+class NoisySubtree:
+    directory: str
+    finding_type: str
+    files: int  # files analyzed under the directory
+    flagged: int  # of those, files the finding type fires on
+    findings: int  # findings collapsed
+
+    @property
+    def share(self) -> float:
+        return self.flagged / self.files
+
+    @property
+    def exclude_pattern(self) -> str:
+        return f"{self.directory}/*"
+
+
+def find_noisy_subtrees(
+    findings: list[Finding],
+    files: list[str],
+    threshold: float = DEFAULT_THRESHOLD,
+    min_files: int = DEFAULT_MIN_FILES,
+) -> list[NoisySubtree]:
+    """Subtrees where one finding type fires on most files, topmost first."""
+    analyzed = set(files)
+    tree_files: dict[str, int] = defaultdict(int)
+    for path in analyzed:
+        for directory in _ancestors(path):
+            tree_files[directory] += 1
+
+    flagged: dict[str, list[str]] = defaultdict(list)  # type -> file of each finding
+    for finding in findings:
+        if _collapsible(finding) and finding.files[0] in analyzed:
+            flagged[finding.finding_type].append(finding.files[0])
+
+    subtrees: list[NoisySubtree] = []
+    for finding_type, paths in sorted(flagged.items()):
+        hits: dict[str, set[str]] = defaultdict(set)
+        for path in paths:
+            for directory in _ancestors(path):
+                hits[directory].add(path)
+        collapsed: list[str] = []
+        for directory in sorted(hits, key=lambda d: (d.count("/"), d)):
+            flagged_files = len(hits[directory])
+            if flagged_files < min_files or any(_under(directory, c) for c in collapsed):
+                continue
+            if flagged_files >= threshold * tree_files[directory]:
+                collapsed.append(directory)
+                count = sum(_under(path, directory) for path in paths)
+                subtrees.append(
+                    NoisySubtree(
+                        directory, finding_type, tree_files[directory], flagged_files, count
+                    )
+                )
+    return subtrees
+
+
+def collapse_noisy_findings(
+    findings: list[Finding],
+    files: list[str],
+    threshold: float = DEFAULT_THRESHOLD,
+    min_files: int = DEFAULT_MIN_FILES,
+) -> list[Finding]:
+    """*findings* with each noisy subtree's findings replaced by one for the directory."""
+    subtrees = find_noisy_subtrees(findings, files, threshold, min_files)
+    if not subtrees:
+        return findings
+
+    by_type: dict[str, list[NoisySubtree]] = defaultdict(list)
+    for subtree in subtrees:
+        by_type[subtree.finding_type].append(subtree)
+
+    kept = []
+    collapsed: dict[NoisySubtree, list[Finding]] = defaultdict(list)
+    for finding in findings:
+        subtree = _subtree_of(finding, by_type.get(finding.finding_type, []))
+        if subtree is None:
+            kept.append(finding)
+        else:
+            collapsed[subtree].append(finding)
+
+    return kept + [_aggregate(subtree, collapsed[subtree]) for subtree in subtrees]
+
+
+def _aggregate(subtree: NoisySubtree, findings: list[Finding]) -> Finding:
+    from .models import Evidence, Finding
+
+    severity = sum(f.severity for f in findings) / len(findings)
+    return Finding(
+        finding_type=subtree.finding_type,
+        severity=round(severity, 4),
+        title=(
+            f"{subtree.finding_type} on {subtree.flagged} of {subtree.files} files "
+            f"under {subtree.directory}/ (likely generated or third-party code)"
+        ),
+        files=[subtree.directory],
+        evidence=[
+            Evidence(
+                signal="flagged_files",
+                value=float(subtree.flagged),
+                percentile=0.0,
+                description=f"{subtree.share:.0%} of {subtree.files} files",
+            ),
+            Evidence(
+                signal="collapsed_findings",
+                value=float(subtree.findings),
+                percentile=0.0,
+                description=f"{subtree.findings} findings collapsed",
+            ),
+        ],
+        suggestion=(
+            f'If {subtree.directory}/ is generated or vendored, add "{subtree.exclude_pattern}" '
+            f"to exclude_patterns; otherwise fix the pattern where the code is produced"
+        ),
+        confidence=round(subtree.share, 4),
+        effort="LOW",
+        scope="MODULE",
+        identity_hint=COLLAPSED_HINT,
+    )
+
+
+def _subtree_of(finding: Finding, subtrees: list[NoisySubtree]) -> NoisySubtree | None:
+    if not _collapsible(finding):
+        return None
+    return next((s for s in subtrees if _under(finding.files[0], s.directory)), None)
+
+
+def _collapsible(finding: Finding) -> bool:
+    return finding.scope == "FILE" and len(finding.files) == 1
+
+
+def _ancestors(path: str) -> list[str]:
+    """Directories containing *path*, the root ("") left out."""
+    parts = path.split("/")[:-1]
+    return ["/".join(parts[: i + 1]) for i in range(len(parts))]
+
+
+def _under(path: str, directory: str) -> bool:
+    return path == directory or path.startswith(f"{directory}/")
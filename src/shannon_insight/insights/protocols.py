@@ -6,7 +6,9 @@ Analyzer Protocol:
     - name: Unique identifier
     - api_version: "2.0" for compatibility
     - requires: Slots that must be in store.available
+    - uses: Optional slots read when present (ordering only, never a skip)
     - provides: Slots this analyzer adds to store.available
+    - prepare(store): Optional stage that needs no slots (runs during scanning)
     - run_last: If True, runs in Wave 2 (after all Wave 1 analyzers)
     - error_mode: "fail" | "skip" | "degrade"
     - deprecated/deprecation_note: For migration
@@ -40,6 +42,8 @@ class Analyzer(Protocol):
         name: Unique identifier for this analyzer
         api_version: Semantic version for compatibility checks ("2.0" for v2)
         requires: Slots that must be in store.available before running
+        uses: Optional slots; the scheduler waits for their providers, but
+            runs the analyzer even if they are missing (optional attribute)
         provides: Slots this analyzer adds to store.available
         run_last: If True, runs in Wave 2 (after all Wave 1 analyzers)
         error_mode: How to handle errors
@@ -48,6 +52,10 @@ class Analyzer(Protocol):
             - "degrade": partial results OK, continue
         deprecated: Whether this analyzer is deprecated
         deprecation_note: Migration instructions if deprecated
+
+    Analyzers may also define ``prepare(store)`` for work that reads no
+    slots (e.g. running git log); the kernel starts it before file scanning
+    and calls ``analyze`` only after it has finished.
     """
 
     name: str
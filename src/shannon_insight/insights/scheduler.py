@@ -0,0 +1,165 @@
+"""AnalyzerScheduler — runs Wave 1 analyzers as a dependency DAG.
+
+Analyzers whose inputs are ready run concurrently on a thread pool; each
+dependent starts as soon as its own providers finish rather than after a
+whole sequential pass. Git extraction, graph algorithms and compression
+spend their time in subprocesses and C code, so threads overlap usefully.
+
+Two stages per analyzer:
+
+    prepare(store)   optional; needs nothing from the store, so it is started
+                     before file scanning and overlaps with it (git log)
+    analyze(store)   runs once every provider of ``requires`` and ``uses``
+                     has finished; skipped if a ``requires`` slot is missing
+
+An analyzer that raises is logged and its unset ``provides`` slots keep the
+error (Slot.set_error), so analyzers need no error handling of their own.
+
+With ``max_workers=1`` analyzers run one at a time in dependency order,
+matching the old sequential kernel.
+"""
+
+from __future__ import annotations
+
+import concurrent.futures
+import time
+from graphlib import CycleError
+from typing import Any, Callable, Optional
+
+from ..logging_config import get_logger
+from .kernel_toposort import AnalyzerCycleError, build_analyzer_graph
+from .store import AnalysisStore, Slot
+
+logger = get_logger(__name__)
+
+AnalyzerCallback = Optional[Callable[[Any], None]]
+
+
+class AnalyzerScheduler:
+    """Run analyzers concurrently in dependency order.
+
+    Usage::
+
+        scheduler = AnalyzerScheduler(analyzers, max_workers=4, timeout=300)
+        scheduler.prepare(store)  # before scanning
+        ...                       # scan files
+        scheduler.run(store)      # after scanning; shuts the pool down
+
+    Call close() instead of run() to abandon a prepared schedule.
+    """
+
+    def __init__(self, analyzers: list[Any], max_workers: int = 4, timeout: float = 300.0):
+        self._graph, self._analyzers = build_analyzer_graph(analyzers)
+        try:
+            self._graph.prepare()
+        except CycleError as e:
+            raise AnalyzerCycleError(f"Analyzer dependency cycle detected: {e}") from e
+        self.timeout = timeout
+        self._pool = concurrent.futures.ThreadPoolExecutor(
+            max_workers=max_workers, thread_name_prefix="analyzer"
+        )
+        self._prepared: dict[str, concurrent.futures.Future] = {}
+        self._started: dict[str, float] = {}  # set by worker threads; timeouts count from here
+        # name -> "completed" | "skipped" | "failed" | "timeout"
+        self.outcomes: dict[str, str] = {}
+
+    def prepare(self, store: AnalysisStore) -> None:
+        """Start every analyzer's prepare stage in the background."""
+        for name, analyzer in self._analyzers.items():
+            stage = getattr(analyzer, "prepare", None)
+            if stage is not None:
+                self._prepared[name] = self._pool.submit(stage, store)
+
+    def run(
+        self,
+        store: AnalysisStore,
+        on_start: AnalyzerCallback = None,
+        on_done: AnalyzerCallback = None,
+    ) -> dict[str, str]:
+        """Run all analyzers; returns the outcome per analyzer name.
+
+        on_start and on_done are called on the calling thread, so they may
+        touch progress displays and exporters without locking. on_done is
+        only called for analyzers that completed.
+        """
+        running: dict[concurrent.futures.Future, str] = {}
+        abandoned = False
+        try:
+            while self._graph.is_active():
+                for name in self._graph.get_ready():
+                    analyzer = self._analyzers[name]
+                    if not set(analyzer.requires).issubset(store.available):
+                        logger.debug(f"Analyzer {name} skipped: requires {analyzer.requires}")
+                        self.outcomes[name] = "skipped"
+                        self._graph.done(name)
+                        continue
+                    if on_start is not None:
+                        on_start(analyzer)
+                    future = self._pool.submit(self._analyze, analyzer, store)
+                    running[future] = name
+                if not running:
+                    continue  # skips may have made more analyzers ready
+
+                finished, _ = concurrent.futures.wait(
+                    running,
+                    timeout=self._next_deadline(running),
+                    return_when=concurrent.futures.FIRST_COMPLETED,
+                )
+                for future in finished:
+                    name = running.pop(future)
+                    self._record(name, future, store, on_done)
+                    self._graph.done(name)
+                for future, name in list(running.items()):
+                    started = self._started.get(name)
+                    if started is not None and time.monotonic() - started >= self.timeout:
+                        # Threads cannot be killed: leave it running, unblock dependents
+                        logger.warning(f"Analyzer '{name}' exceeded {self.timeout}s timeout")
+                        self.outcomes[name] = "timeout"
+                        abandoned = True
+                        del running[future]
+                        self._graph.done(name)
+        finally:
+            self._pool.shutdown(wait=not abandoned)
+        return self.outcomes
+
+    def close(self) -> None:
+        """Shut down without running analyzers (e.g. nothing was scanned)."""
+        for future in self._prepared.values():
+            future.cancel()
+        self._pool.shutdown(wait=True)
+
+    def _analyze(self, analyzer: Any, store: AnalysisStore) -> None:
+        prepared = self._prepared.get(analyzer.name)
+        if prepared is not None:
+            prepared.result()  # re-raises a failed prepare as this analyzer's failure
+        self._started[analyzer.name] = time.monotonic()
+        analyzer.analyze(store)
+
+    def _record(
+        self,
+        name: str,
+        future: concurrent.futures.Future,
+        store: AnalysisStore,
+        on_done: AnalyzerCallback,
+    ) -> None:
+        error = future.exception()
+        if error is not None:
+            logger.warning(f"Analyzer {name} failed: {error}")
+            self.outcomes[name] = "failed"
+            # Slots it left unset say why, for slot_status() and debug exports
+            for slot_name in self._analyzers[name].provides:
+                slot = getattr(store, slot_name, None)
+                if isinstance(slot, Slot) and not slot.available:
+                    slot.set_error(str(error), produced_by=name)
+            return
+        logger.debug(f"Analyzer {name} completed")
+        self.outcomes[name] = "completed"
+        if on_done is not None:
+            on_done(self._analyzers[name])
+
+    def _next_deadline(self, running: dict[concurrent.futures.Future, str]) -> float:
+        """Seconds until the earliest running analyzer times out."""
+        started = [self._started[name] for name in running.values() if name in self._started]
+        if not started:
+            return self.timeout
+        return max(0.0, min(started) + self.timeout - time.monotonic())
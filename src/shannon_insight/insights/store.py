@@ -15,6 +15,7 @@ into it as entities with basic signals.
 
 from __future__ import annotations
 
+from collections.abc import Iterable
 from dataclasses import dataclass, field
 from typing import TYPE_CHECKING, Any, Generic, TypeVar
 
@@ -23,6 +24,7 @@ from shannon_insight.infrastructure.signals import Signal
 from shannon_insight.infrastructure.store import FactStore
 
 if TYPE_CHECKING:
+    from shannon_insight.config import AnalysisConfig
     from shannon_insight.session import AnalysisSession
     from shannon_insight.signals.models import SignalField
 
@@ -105,6 +107,10 @@ class AnalysisStore:
 
     Slots (from v2 spec):
         - file_syntax: Dict[path, FileSyntax] from tree-sitter/regex parsing
+        - duplicate_files: List[DuplicateGroup] of content-identical files;
+          only each group's canonical copy is kept in file_syntax
+        - generated_files: Dict[path, reason] of generated files, left out of
+          entropy and complexity scoring (unset with include_generated)
         - structural: CodebaseAnalysis with graph, PageRank, SCC, Louvain
         - git_history: GitHistory with commits and file changes
         - churn: Dict[path, ChurnSeries] with per-file churn stats
@@ -113,9 +119,58 @@ class AnalysisStore:
         - roles: Dict[path, str] with file role classifications
         - spectral: SpectralSummary with Fiedler value and spectral gap
         - clone_pairs: List[ClonePair] with detected clones
+        - duplication: DuplicationReport with token-based clone classes and
+          the share of duplicated lines per package
         - author_distances: List[AuthorDistance] with author overlap metrics
         - architecture: Architecture with modules, layers, Martin metrics
         - signal_field: SignalField with all computed signals per file/module
+        - comment_debt: List[DebtItem] with TODO/FIXME markers, age and owner
+        - deep_nesting: List[FunctionNesting] of functions nested deeper than
+          nesting_threshold, with their average nesting
+        - dead_code: List[DeadFunction] of functions nothing in the repository
+          calls or mentions, with a confidence level
+        - deprecations: DeprecationReport with deprecated symbols and call sites
+        - format_drift: FormatReport with lines drifting from gofmt/black/prettier
+        - function_fan: List[FunctionFan] with the fan-in and fan-out of
+          every function in the call graph
+        - function_stats: FunctionStats with per-package counters and t-digest
+          sketches of function length and complexity
+        - function_outliers: List[FunctionOutlier] of unusually complex
+          functions with similar-sized reference functions
+        - god_classes: List[TypeSample] of types with many, complex methods
+          (Swift extensions merged into their type)
+        - low_cohesion: List[TypeCohesion] of types whose methods split into
+          groups sharing no fields or calls (LCOM4 >= 2)
+        - notebook_drift: List[NotebookDrift] of functions copied between
+          notebooks and modules that have since diverged
+        - parameters: List[FunctionParams] with the parameter names of every
+          function, receivers and variadic collectors left out
+        - sql: SqlReport with SQL statement complexity, stored procedures
+          and table references
+        - terraform: TerraformReport with Terraform modules, nested dynamic
+          blocks and variable-count outliers
+        - vocabulary_drift: List[DirectoryVocabulary] of directories that
+          spell one concept several ways (user_id, userId, uid)
+        - yaml: YamlReport with YAML files, duplicated blocks, deep
+          documents and values-file outliers
+        - mobile: MobileReport with Android and iOS resource files,
+          duplicated strings, unused resources and oversized files
+        - crypto: CryptoReport with crypto API usage and crypto policy
+          violations
+        - auth: AuthReport with JWT/auth flow review issues
+        - error_hygiene: ErrorReport with Go error hygiene issues and error
+          checks per package
+        - hexagonal: HexagonalReport with ports-and-adapters rings and
+          forbidden imports
+        - centrality: CentralityReport with call-graph symbol and import-graph
+          package PageRank/betweenness
+        - information_density: DensityReport with codebase compression,
+          cross-file redundancy, vocabulary growth and per-package density
+        - coverage_risk: CoverageRisk joining a coverage report with function
+          complexity (only when a coverage report is found)
+        - api_surface: SurfaceReport with exported identifiers per package
+        - literals: LiteralReport with magic numbers per file and literals
+          repeated across files
     """
 
     # Always-available inputs (set by kernel before analyzers run)
@@ -219,6 +274,25 @@ class AnalysisStore:
             return self.file_syntax.value
         return {}
 
+    @property
+    def scored_files(self) -> dict[str, Any]:
+        """files without the generated ones, which scoring leaves out."""
+        generated = self.generated_files.get(default={})
+        return {path: syntax for path, syntax in self.files.items() if path not in generated}
+
+    def contents(self, paths: Iterable[str]) -> dict[str, str]:
+        """Content of each of *paths*; "" for a file that cannot be read."""
+        return {path: self.get_content(path) or "" for path in paths}
+
+    @property
+    def config(self) -> AnalysisConfig:
+        """The session's config; the defaults when there is no session."""
+        if self.session is not None and self.session.config is not None:
+            return self.session.config
+        from shannon_insight.config import AnalysisConfig
+
+        return AnalysisConfig()
+
     @property
     def file_count(self) -> int:
         """Number of files in the store."""
@@ -228,6 +302,8 @@ class AnalysisStore:
 
     # Typed slots — each knows if it's populated, why not, and who wrote it
     file_syntax: Slot[dict[str, Any]] = field(default_factory=Slot)
+    duplicate_files: Slot[list[Any]] = field(default_factory=Slot)
+    generated_files: Slot[dict[str, str]] = field(default_factory=Slot)
     structural: Slot[Any] = field(default_factory=Slot)
     git_history: Slot[Any] = field(default_factory=Slot)
     churn: Slot[dict[str, Any]] = field(default_factory=Slot)
@@ -236,9 +312,36 @@ class AnalysisStore:
     roles: Slot[dict[str, str]] = field(default_factory=Slot)
     spectral: Slot[Any] = field(default_factory=Slot)
     clone_pairs: Slot[list[Any]] = field(default_factory=Slot)
+    duplication: Slot[Any] = field(default_factory=Slot)
     author_distances: Slot[list[Any]] = field(default_factory=Slot)
     architecture: Slot[Any] = field(default_factory=Slot)
     signal_field: Slot[SignalField] = field(default_factory=Slot)
+    comment_debt: Slot[list[Any]] = field(default_factory=Slot)
+    deep_nesting: Slot[list[Any]] = field(default_factory=Slot)
+    dead_code: Slot[list[Any]] = field(default_factory=Slot)
+    deprecations: Slot[Any] = field(default_factory=Slot)
+    format_drift: Slot[Any] = field(default_factory=Slot)
+    function_fan: Slot[list[Any]] = field(default_factory=Slot)
+    function_outliers: Slot[list[Any]] = field(default_factory=Slot)
+    function_stats: Slot[Any] = field(default_factory=Slot)
+    god_classes: Slot[list[Any]] = field(default_factory=Slot)
+    low_cohesion: Slot[list[Any]] = field(default_factory=Slot)
+    notebook_drift: Slot[list[Any]] = field(default_factory=Slot)
+    parameters: Slot[list[Any]] = field(default_factory=Slot)
+    sql: Slot[Any] = field(default_factory=Slot)
+    terraform: Slot[Any] = field(default_factory=Slot)
+    vocabulary_drift: Slot[list[Any]] = field(default_factory=Slot)
+    yaml: Slot[Any] = field(default_factory=Slot)
+    mobile: Slot[Any] = field(default_factory=Slot)
+    crypto: Slot[Any] = field(default_factory=Slot)
+    auth: Slot[Any] = field(default_factory=Slot)
+    error_hygiene: Slot[Any] = field(default_factory=Slot)
+    hexagonal: Slot[Any] = field(default_factory=Slot)
+    centrality: Slot[Any] = field(default_factory=Slot)
+    information_density: Slot[Any] = field(default_factory=Slot)
+    coverage_risk: Slot[Any] = field(default_factory=Slot)
+    api_surface: Slot[Any] = field(default_factory=Slot)
+    literals: Slot[Any] = field(default_factory=Slot)
 
     @property
     def available(self) -> set[str]:
@@ -258,6 +361,8 @@ class AnalysisStore:
         """Return all slot names in order."""
         return [
             "file_syntax",
+            "duplicate_files",
+            "generated_files",
             "structural",
             "git_history",
             "churn",
@@ -266,9 +371,36 @@ class AnalysisStore:
             "roles",
             "spectral",
             "clone_pairs",
+            "duplication",
             "author_distances",
             "architecture",
             "signal_field",
+            "comment_debt",
+            "deep_nesting",
+            "dead_code",
+            "deprecations",
+            "format_drift",
+            "function_fan",
+            "function_outliers",
+            "function_stats",
+            "god_classes",
+            "low_cohesion",
+            "notebook_drift",
+            "parameters",
+            "sql",
+            "terraform",
+            "vocabulary_drift",
+            "yaml",
+            "mobile",
+            "crypto",
+            "auth",
+            "error_hygiene",
+            "hexagonal",
+            "centrality",
+            "information_density",
+            "coverage_risk",
+            "api_surface",
+            "literals",
         ]
 
     def slot_status(self) -> dict[str, dict[str, Any]]:
@@ -0,0 +1,238 @@
+"""Triage decisions on findings, kept in a file committed to the repo.
+
+The triage file (``triage_file`` config) records one decision per finding,
+keyed by the finding's identity key (persistence/identity.py), so it
+survives re-runs and line shifts:
+
+    dismiss   not a problem here; this finding is hidden from analysis
+    suppress  the finding type is not wanted on this file; every finding of
+              that type whose primary file is this one is hidden
+    assign    someone owns the fix; the finding stays visible
+    ticket    tracked elsewhere (an issue URL or id); the finding stays visible
+
+Findings without a decision are "new" to triage; ``shannon-insight triage``
+walks through them one by one and writes the file once at the end. Each
+finding is shown next to similar cases: findings of the same type that were
+fixed (resolved in the snapshot history) or triaged before, nearest in the
+directory tree first.
+"""
+
+from __future__ import annotations
+
+import json
+import re
+from dataclasses import asdict, dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import TYPE_CHECKING, Any, Optional, Sequence
+from urllib.parse import quote
+
+from ..persistence.identity import compute_identity_key
+
+if TYPE_CHECKING:
+    from ..persistence.models import FindingRecord
+    from ..persistence.queries import ChronicFindingInfo
+    from .models import Finding
+
+TRIAGE_VERSION = 1
+
+ACTIONS = ("dismiss", "suppress", "assign", "ticket")
+
+# Actions that take a finding out of the analysis output
+HIDING_ACTIONS = frozenset({"dismiss", "suppress"})
+
+_PAST_TENSE = {"dismiss": "dismissed", "suppress": "suppressed", "assign": "assigned"}
+
+
+@dataclass
+class Decision:
+    """What was decided about one finding."""
+
+    identity_key: str
+    finding_type: str
+    title: str
+    files: list[str]
+    action: str  # one of ACTIONS
+    reason: str = ""
+    owner: str = ""
+    ticket: str = ""
+    decided: str = ""  # ISO date
+
+    @property
+    def outcome(self) -> str:
+        """One-line summary, as shown next to similar findings."""
+        if self.action == "assign" and self.owner:
+            detail = f"assigned to {self.owner}"
+        elif self.action == "ticket" and self.ticket:
+            detail = f"ticket {self.ticket}"
+        else:
+            detail = _PAST_TENSE.get(self.action, self.action)
+        return f"{detail}: {self.reason}" if self.reason else detail
+
+
+@dataclass
+class Triage:
+    """Every decision in a triage file."""
+
+    decisions: dict[str, Decision] = field(default_factory=dict)
+
+    def decide(self, decision: Decision) -> None:
+        if decision.action not in ACTIONS:
+            raise ValueError(f"unknown triage action {decision.action!r}")
+        self.decisions[decision.identity_key] = decision
+
+    def hides(self, identity_key: str, finding_type: str, files: Sequence[str]) -> bool:
+        """Whether a dismissal or suppression takes the finding out of the output."""
+        decision = self.decisions.get(identity_key)
+        if decision is not None and decision.action in HIDING_ACTIONS:
+            return True
+        primary = files[0] if files else ""
+        return any(
+            d.action == "suppress" and d.finding_type == finding_type and d.files[:1] == [primary]
+            for d in self.decisions.values()
+        )
+
+    def counts(self) -> dict[str, int]:
+        counts = dict.fromkeys(ACTIONS, 0)
+        for decision in self.decisions.values():
+            counts[decision.action] += 1
+        return counts
+
+
+@dataclass
+class SimilarCase:
+    """A finding of the same type that was fixed or triaged before."""
+
+    title: str
+    files: list[str]
+    outcome: str  # "fixed", or the decision's outcome
+    shared_depth: int  # directories shared with the finding being triaged
+
+
+def load_triage(path: Path) -> Triage:
+    """Decisions from a triage file; none when the file does not exist."""
+    if not path.exists():
+        return Triage()
+    data = json.loads(path.read_text(encoding="utf-8"))
+    if data.get("version") != TRIAGE_VERSION:
+        raise ValueError(f"{path}: unsupported triage version {data.get('version')!r}")
+    triage = Triage()
+    for key, entry in data["findings"].items():
+        triage.decide(Decision(identity_key=key, **entry))
+    return triage
+
+
+def save_triage(path: Path, triage: Triage) -> None:
+    """Write decisions sorted by identity key so diffs of the committed file stay small."""
+    findings: dict[str, Any] = {}
+    for key in sorted(triage.decisions):
+        entry = asdict(triage.decisions[key])
+        del entry["identity_key"]
+        findings[key] = {k: v for k, v in entry.items() if v != "" or k in ("title", "action")}
+    data = {"version": TRIAGE_VERSION, "findings": findings}
+    path.write_text(json.dumps(data, indent=2, ensure_ascii=False) + "\n", encoding="utf-8")
+
+
+def apply_triage(findings: list[Finding], triage: Triage) -> list[Finding]:
+    """Findings left once dismissals and suppressions are taken out."""
+    if not triage.decisions:
+        return findings
+    kept = []
+    for finding in findings:
+        key = compute_identity_key(finding.finding_type, finding.files, hint=finding.identity_hint)
+        if not triage.hides(key, finding.finding_type, finding.files):
+            kept.append(finding)
+    return kept
+
+
+def untriaged(records: list[FindingRecord], triage: Triage) -> list[FindingRecord]:
+    """Findings with no decision yet, most severe first."""
+    pending = [
+        r
+        for r in records
+        if r.identity_key not in triage.decisions
+        and not triage.hides(r.identity_key, r.finding_type, r.files)
+    ]
+    return sorted(pending, key=lambda r: (-r.severity, r.finding_type, r.files[:1]))
+
+
+def similar_cases(
+    record: FindingRecord,
+    resolved: list[ChronicFindingInfo],
+    triage: Triage,
+    limit: int = 3,
+) -> list[SimilarCase]:
+    """Fixed and triaged findings of the same type, nearest directory first."""
+    primary = record.files[0] if record.files else ""
+    cases = [
+        SimilarCase(r.title, r.files, "fixed", _shared_depth(primary, r.files))
+        for r in resolved
+        if r.finding_type == record.finding_type and r.identity_key != record.identity_key
+    ]
+    cases.extend(
+        SimilarCase(d.title, d.files, d.outcome, _shared_depth(primary, d.files))
+        for d in triage.decisions.values()
+        if d.finding_type == record.finding_type and d.identity_key != record.identity_key
+    )
+    cases.sort(key=lambda c: (-c.shared_depth, c.outcome != "fixed", c.title))
+    return cases[:limit]
+
+
+def ticket_url(template: str, record: FindingRecord) -> str:
+    """A new-issue URL from *template*, with {title}, {body}, {type} and {file} filled in."""
+    body = "\n".join(
+        [
+            record.title,
+            "",
+            *(f"- {path}" for path in record.files),
+            "",
+            *(f"- {e.signal}: {e.description or e.value}" for e in record.evidence),
+            "",
+            record.suggestion,
+            "",
+            f"shannon-insight finding {record.identity_key}",
+        ]
+    )
+    values = {
+        "title": record.title,
+        "body": body,
+        "type": record.finding_type,
+        "file": record.files[0] if record.files else "",
+    }
+    return template.format(**{k: quote(v, safe="") for k, v in values.items()})
+
+
+def code_excerpt(
+    root: Path, record: FindingRecord, context: int = 5
+) -> Optional[tuple[str, int, list[str]]]:
+    """(path, first line number, lines) around the finding in its primary file."""
+    if not record.files:
+        return None
+    path = record.files[0]
+    try:
+        lines = (root / path).read_text(encoding="utf-8", errors="replace").splitlines()
+    except OSError:
+        return None
+    line = _line_of(record, path)
+    start = max(line - context, 1) if line else 1
+    end = line + context if line else 2 * context
+    return path, start, lines[start - 1 : end]
+
+
+def _line_of(record: FindingRecord, path: str) -> Optional[int]:
+    """Line number of a "path:line" location in the finding's title, if any."""
+    match = re.search(re.escape(path) + r":(\d+)", record.title)
+    return int(match.group(1)) if match else None
+
+
+def _shared_depth(path: str, files: list[str]) -> int:
+    """Leading directories *path* shares with the first of *files*."""
+    if not files:
+        return 0
+    a = PurePosixPath(path).parent.parts
+    b = PurePosixPath(files[0]).parent.parts
+    depth = 0
+    for x, y in zip(a, b):
+        if x != y:
+            break
+        depth += 1
+    return depth
@@ -53,8 +53,16 @@ _NUMERIC_SIGNAL_FIELDS = (
     "blast_radius_size",
     "depth",
     "compression_ratio",
+    "token_entropy",
     "semantic_coherence",
     "cognitive_load",
+    "halstead_volume",
+    "halstead_difficulty",
+    "halstead_effort",
+    "maintainability_index",
+    "comment_density",
+    "commented_out_lines",
+    "undocumented_complexity",
     "total_changes",
     "churn_slope",
     "churn_cv",
@@ -7,6 +7,7 @@ from .gini import Gini
 from .graph import GraphMetrics
 from .identifier import IdentifierAnalyzer
 from .robust import RobustStatistics
+from .sketches import TDigest
 from .statistics import Statistics
 
 __all__ = [
@@ -18,4 +19,5 @@ __all__ = [
     "Compression",
     "Gini",
     "IdentifierAnalyzer",
+    "TDigest",
 ]
@@ -12,7 +12,7 @@ Formula (for sorted values x_1 <= x_2 <= ... <= x_n):
     G = (2 * sum(i * x_i)) / (n * sum(x_i)) - (n + 1) / n
 """
 
-from typing import Union
+from typing import Sequence, Union
 
 
 class Gini:
@@ -67,3 +67,40 @@ class Gini:
             gini *= n / (n - 1)
 
         return max(0.0, min(1.0, gini))
+
+    @staticmethod
+    def weighted_gini_coefficient(
+        points: Sequence[tuple[float, float]],
+        bias_correction: bool = True,
+    ) -> float:
+        """Gini coefficient of values given as (value, weight) pairs.
+
+        Each value counts *weight* times, so the centroids of a quantile
+        sketch stand in for the values they summarize. With unit weights
+        this equals gini_coefficient(). Returns 0.0 when there is nothing
+        to compare.
+
+        Formula (Lorenz curve, points sorted by value):
+            G = 1 - sum((F_i - F_{i-1}) * (L_i + L_{i-1}))
+        where F is the cumulative share of the weight and L of the total.
+        """
+        points = sorted((v, w) for v, w in points if w > 0)
+        count = sum(w for _, w in points)
+        total = sum(v * w for v, w in points)
+        if count <= 1 or total <= 0:
+            return 0.0
+        if any(v < 0 for v, _ in points):
+            raise ValueError("Gini requires non-negative values")
+
+        area = 0.0
+        share = 0.0  # cumulative share of the total, L_{i-1}
+        for value, weight in points:
+            step = value * weight / total
+            area += weight / count * (2 * share + step)
+            share += step
+        gini = 1.0 - area
+
+        if bias_correction:
+            gini *= count / (count - 1)
+
+        return max(0.0, min(1.0, gini))
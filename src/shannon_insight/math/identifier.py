@@ -105,6 +105,47 @@ class IdentifierAnalyzer:
             "implements",
             "throws",
             "synchronized",
+            # Kotlin
+            "fun",
+            "val",
+            "when",
+            "companion",
+            "override",
+            "suspend",
+            "internal",
+            "sealed",
+            # Scala
+            "object",
+            "implicit",
+            "lazy",
+            "given",
+            # Swift
+            "guard",
+            "extension",
+            "protocol",
+            "fileprivate",
+            "mutating",
+            # SQL
+            "join",
+            "insert",
+            "update",
+            "delete",
+            "table",
+            "create",
+            "alter",
+            "declare",
+            "procedure",
+            # HCL (Terraform)
+            "resource",
+            "variable",
+            "locals",
+            "dynamic",
+            "for_each",
+            # YAML (Kubernetes)
+            "apiVersion",
+            "kind",
+            "metadata",
+            "spec",
             # Ruby
             "require",
             "include",
@@ -117,6 +158,13 @@ class IdentifierAnalyzer:
             "unless",
             "until",
             "attr",
+            # PHP
+            "foreach",
+            "elseif",
+            "echo",
+            "isset",
+            "unset",
+            "endforeach",
             # C / C++
             "define",
             "ifdef",
@@ -4,6 +4,10 @@ from typing import Optional, Union
 
 import numpy as np
 
+# Modified z-score above which a value is an outlier. Standard is 3.5
+# (Iglewicz & Hoaglin); 5.0 flags only truly extreme values, to reduce noise.
+OUTLIER_Z = 5.0
+
 
 class RobustStatistics:
     """Robust statistical methods resistant to outliers."""
@@ -0,0 +1,145 @@
+"""Mergeable quantile sketch: the merging t-digest.
+
+A t-digest summarizes a stream of values as a sorted list of centroids
+(mean, weight). Centroids near the median absorb many values, those near
+the tails only a few, so extreme quantiles stay accurate while the sketch
+stays at about COMPRESSION centroids however many values it has seen:
+
+    k(q) = COMPRESSION / (2 pi) * asin(2q - 1)
+
+Adjacent centroids are merged while together they span at most one unit
+of k. Values are buffered and merged in batches. Two digests merge into a
+digest of both streams, so partial aggregates (per file, per worker, per
+run) combine without keeping the values themselves.
+
+Quantiles interpolate linearly between centroid means, and between the
+outermost centroids and the exact minimum and maximum. Up to a few
+hundred values every value is its own centroid and quantiles are exact up
+to that interpolation.
+
+Reference: Dunning & Ertl (2019) - Computing Extremely Accurate Quantiles
+Using t-Digests
+"""
+
+from __future__ import annotations
+
+import math
+
+# Centroids kept (about); higher is more accurate and larger
+COMPRESSION = 100.0
+
+# Values buffered before a merge, as a multiple of COMPRESSION
+BUFFER_FACTOR = 5
+
+
+class TDigest:
+    """Streaming, mergeable estimate of a distribution's quantiles."""
+
+    def __init__(self, compression: float = COMPRESSION) -> None:
+        self.compression = compression
+        self.count = 0.0
+        self.min = math.inf
+        self.max = -math.inf
+        self._centroids: list[tuple[float, float]] = []  # (mean, weight), by mean
+        self._buffer: list[tuple[float, float]] = []
+
+    def __len__(self) -> int:
+        """Centroids in the sketch."""
+        self._flush()
+        return len(self._centroids)
+
+    def add(self, value: float, weight: float = 1.0) -> None:
+        """Add *value*, counted *weight* times."""
+        self._buffer.append((float(value), float(weight)))
+        self.count += weight
+        self.min = min(self.min, value)
+        self.max = max(self.max, value)
+        if len(self._buffer) >= BUFFER_FACTOR * self.compression:
+            self._flush()
+
+    def merge(self, other: TDigest) -> None:
+        """Fold *other* into this digest; *other* is left unchanged."""
+        self._buffer.extend(other._centroids)
+        self._buffer.extend(other._buffer)
+        self.count += other.count
+        self.min = min(self.min, other.min)
+        self.max = max(self.max, other.max)
+        self._flush()
+
+    def centroids(self) -> list[tuple[float, float]]:
+        """(mean, weight) of every centroid, by mean."""
+        self._flush()
+        return list(self._centroids)
+
+    def quantile(self, q: float) -> float:
+        """Estimated value below which a fraction *q* of the values lie (0 when empty)."""
+        self._flush()
+        if not self._centroids:
+            return 0.0
+        q = min(max(q, 0.0), 1.0)
+        if len(self._centroids) == 1:
+            return self._centroids[0][0]
+        target = q * self.count
+        first_mean, first_weight = self._centroids[0]
+        if target < first_weight / 2:
+            return self.min + (first_mean - self.min) * target / (first_weight / 2)
+        last_mean, last_weight = self._centroids[-1]
+        if target > self.count - last_weight / 2:
+            tail = (self.count - target) / (last_weight / 2)
+            return self.max - (self.max - last_mean) * tail
+        # Centroid i's weight is centred on cumulative + weight / 2
+        cumulative = 0.0
+        for (left, left_weight), (right, right_weight) in zip(
+            self._centroids, self._centroids[1:]
+        ):
+            start = cumulative + left_weight / 2
+            end = cumulative + left_weight + right_weight / 2
+            if target <= end:
+                return left + (right - left) * (target - start) / (end - start)
+            cumulative += left_weight
+        return last_mean
+
+    def to_dict(self) -> dict:
+        self._flush()
+        return {
+            "count": self.count,
+            "min": self.min if self.count else None,
+            "max": self.max if self.count else None,
+            "centroids": [[mean, weight] for mean, weight in self._centroids],
+        }
+
+    @classmethod
+    def from_dict(cls, data: dict, compression: float = COMPRESSION) -> TDigest:
+        digest = cls(compression)
+        for mean, weight in data.get("centroids", []):
+            digest._buffer.append((float(mean), float(weight)))
+        digest.count = float(data.get("count", 0.0))
+        if digest.count:
+            digest.min = float(data["min"])
+            digest.max = float(data["max"])
+        digest._flush()
+        return digest
+
+    def _flush(self) -> None:
+        """Merge buffered values into the centroids."""
+        if not self._buffer:
+            return
+        points = sorted(self._centroids + self._buffer)
+        self._buffer = []
+        total = sum(weight for _, weight in points)
+        merged: list[tuple[float, float]] = []
+        mean, weight = points[0]
+        done = 0.0  # weight of the centroids already closed
+        for next_mean, next_weight in points[1:]:
+            if self._k((done + weight + next_weight) / total) - self._k(done / total) <= 1.0:
+                weight += next_weight
+                mean += (next_mean - mean) * next_weight / weight
+            else:
+                merged.append((mean, weight))
+                done += weight
+                mean, weight = next_mean, next_weight
+        merged.append((mean, weight))
+        self._centroids = merged
+
+    def _k(self, q: float) -> float:
+        return self.compression / (2 * math.pi) * math.asin(2 * min(max(q, 0.0), 1.0) - 1)
@@ -0,0 +1,432 @@
+"""Registry of the metric and finding types Shannon Insight can report.
+
+Dashboards and scripts that read snapshots, ``--json`` output or the
+server API use this registry to build their UI generically instead of
+hard-coding today's metric names. Each metric has an id, a label, a unit,
+a direction and the scopes it is reported at:
+
+    scopes     file, module (packages are snapshotted as modules) and global
+    direction  high_is_bad, high_is_good or neutral -- the polarity used
+               by the signal registry
+    unit       lines    source lines
+               count    a number of things (functions, files, commits, ...)
+               ratio    a fraction between 0 and 1
+               bits     entropy or compressed information
+               days     an age
+               score    a unitless index or composite
+               label    a categorical value
+               id       an assignment identifier (graph community)
+               flag     a boolean
+
+Each finding type has an id (the ``finding_type`` of its findings), a
+label, the dashboard category and health concern it is grouped under, and
+for rule-based checks the rule ids that can appear in a finding's
+``rule`` evidence.
+
+``REGISTRY_VERSION`` is bumped when a field is removed or changes meaning;
+new metrics, finding types and fields do not bump it.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from typing import Optional
+
+REGISTRY_VERSION = 1
+
+UNITS = ("lines", "count", "ratio", "bits", "days", "score", "label", "id", "flag")
+
+
+@dataclass(frozen=True)
+class MetricType:
+    id: str
+    label: str
+    unit: str  # one of UNITS
+    direction: str  # high_is_bad | high_is_good | neutral
+    scopes: tuple[str, ...]  # file, module, global
+    dtype: str  # int, float, str or bool
+
+    @property
+    def higher_is_worse(self) -> bool:
+        return self.direction == "high_is_bad"
+
+    def to_dict(self) -> dict:
+        return {
+            "id": self.id,
+            "label": self.label,
+            "unit": self.unit,
+            "direction": self.direction,
+            "higher_is_worse": self.higher_is_worse,
+            "scopes": list(self.scopes),
+            "dtype": self.dtype,
+        }
+
+
+@dataclass(frozen=True)
+class FindingType:
+    id: str
+    label: str
+    category: Optional[str]  # dashboard category; None when shown on its own
+    concern: Optional[str]  # health concern key; None when not scored
+    rules: tuple[str, ...]  # rule ids of rule-based checks
+    description: str
+    data_points: tuple[str, ...]  # metrics a finding's evidence refers to
+
+    def to_dict(self) -> dict:
+        return {
+            "id": self.id,
+            "label": self.label,
+            "category": self.category,
+            "concern": self.concern,
+            "rules": list(self.rules),
+            "description": self.description,
+            "data_points": list(self.data_points),
+        }
+
+
+# Label and unit of every signal in the signal registry
+_SIGNALS: dict[str, tuple[str, str]] = {
+    # Per-file: size and complexity
+    "lines": ("Lines of Code", "lines"),
+    "function_count": ("Function Count", "count"),
+    "class_count": ("Classes / Structs", "count"),
+    "max_nesting": ("Deepest Nesting Level", "count"),
+    "impl_gini": ("Implementation Distribution", "ratio"),
+    "stub_ratio": ("Stub / Empty Function Ratio", "ratio"),
+    "import_count": ("Total Import Statements", "count"),
+    "cognitive_load": ("Cognitive Complexity", "score"),
+    "halstead_volume": ("Halstead Volume", "bits"),
+    "halstead_difficulty": ("Halstead Difficulty", "score"),
+    "halstead_effort": ("Halstead Effort", "score"),
+    "maintainability_index": ("Maintainability Index", "score"),
+    "comment_density": ("Comment Density", "ratio"),
+    "commented_out_lines": ("Commented-Out Code Lines", "lines"),
+    "undocumented_complexity": ("Undocumented Complexity", "count"),
+    # Per-file: semantics
+    "role": ("File Role", "label"),
+    "concept_count": ("Semantic Concept Count", "count"),
+    "concept_entropy": ("Concept Diversity", "bits"),
+    "naming_drift": ("Naming Inconsistency", "ratio"),
+    "todo_density": ("TODO / FIXME Density", "ratio"),
+    "docstring_coverage": ("Documentation Coverage", "ratio"),
+    # Per-file: dependency graph
+    "pagerank": ("Import Centrality (PageRank)", "score"),
+    "betweenness": ("Bridge Score (Betweenness)", "score"),
+    "in_degree": ("Imported By (in-degree)", "count"),
+    "out_degree": ("Depends On (out-degree)", "count"),
+    "blast_radius_size": ("Change Impact Size", "count"),
+    "depth": ("Dependency Chain Depth", "count"),
+    "is_orphan": ("Orphan (no imports or importers)", "flag"),
+    "phantom_import_count": ("Broken Imports", "count"),
+    "broken_call_count": ("Broken Function Calls", "count"),
+    "community": ("Graph Community ID", "id"),
+    "compression_ratio": ("Code Uniqueness (compression)", "ratio"),
+    "token_entropy": ("Token Entropy", "bits"),
+    "semantic_coherence": ("Code Focus (coherence)", "ratio"),
+    # Per-file: git history
+    "total_changes": ("Total Commits", "count"),
+    "churn_trajectory": ("Change Trend", "label"),
+    "churn_slope": ("Change Rate Slope", "score"),
+    "churn_cv": ("Change Volatility (CV)", "score"),
+    "bus_factor": ("Team Knowledge Spread", "count"),
+    "author_entropy": ("Author Diversity", "bits"),
+    "fix_ratio": ("Bug-Fix Commit Ratio", "ratio"),
+    "refactor_ratio": ("Refactor Commit Ratio", "ratio"),
+    "change_entropy": ("Change Distribution", "bits"),
+    # Per-file: composites
+    "risk_score": ("Overall Risk Score", "score"),
+    "wiring_quality": ("Dependency Health", "score"),
+    "file_health_score": ("File Health Score", "score"),
+    # Per-module
+    "cohesion": ("Internal Cohesion", "ratio"),
+    "coupling": ("External Coupling", "ratio"),
+    "instability": ("Change Sensitivity", "ratio"),
+    "abstractness": ("Abstraction Level", "ratio"),
+    "main_seq_distance": ("Distance from Main Sequence", "ratio"),
+    "boundary_alignment": ("Boundary Alignment", "ratio"),
+    "layer_violation_count": ("Layer Violations", "count"),
+    "role_consistency": ("Role Consistency", "ratio"),
+    "velocity": ("Change Velocity", "score"),
+    "coordination_cost": ("Team Coordination Cost", "score"),
+    "knowledge_gini": ("Knowledge Concentration", "ratio"),
+    "module_bus_factor": ("Module Bus Factor", "count"),
+    "mean_cognitive_load": ("Average Complexity", "score"),
+    "module_maintainability": ("Maintainability Index", "score"),
+    "file_count": ("File Count", "count"),
+    "health_score": ("Module Health Score", "score"),
+    # Global
+    "modularity": ("Community Modularity", "score"),
+    "fiedler_value": ("Algebraic Connectivity", "score"),
+    "spectral_gap": ("Spectral Gap Ratio", "score"),
+    "cycle_count": ("Dependency Cycles", "count"),
+    "centrality_gini": ("Centrality Inequality", "ratio"),
+    "orphan_ratio": ("Orphan Files Ratio", "ratio"),
+    "phantom_ratio": ("Broken Import Ratio", "ratio"),
+    "glue_deficit": ("Missing Glue Modules", "ratio"),
+    "wiring_score": ("Global Wiring Quality", "score"),
+    "architecture_health": ("Architecture Health", "score"),
+    "codebase_health": ("Overall Codebase Health", "score"),
+}
+
+# Snapshot metrics outside the signal registry:
+# id -> (label, unit, direction, scopes, dtype)
+_SNAPSHOT_METRICS: dict[str, tuple[str, str, str, tuple[str, ...], str]] = {
+    "raw_risk": ("Raw Risk (pre-normalization)", "score", "high_is_bad", ("file",), "float"),
+    "clone_ratio": ("Code Clone Ratio", "ratio", "high_is_bad", ("global",), "float"),
+    "violation_rate": ("Layer Violation Rate", "ratio", "high_is_bad", ("global",), "float"),
+    "conway_alignment": ("Team-Code Alignment", "ratio", "high_is_good", ("global",), "float"),
+    "team_size": ("Total Contributors", "count", "neutral", ("global",), "int"),
+    "team_risk": ("Team Risk Score", "score", "high_is_bad", ("global",), "float"),
+    "total_edges": ("Dependencies", "count", "neutral", ("global",), "int"),
+    "active_findings": ("Issues found", "count", "high_is_bad", ("global",), "int"),
+    "comment_debt_count": (
+        "TODO/FIXME comments",
+        "count",
+        "high_is_bad",
+        ("module", "global"),
+        "int",
+    ),
+    "comment_debt_median_age_days": (
+        "Median TODO age (days)",
+        "days",
+        "high_is_bad",
+        ("module", "global"),
+        "float",
+    ),
+    "deprecated_symbols": ("Deprecated symbols", "count", "neutral", ("global",), "int"),
+    "deprecated_call_sites": (
+        "Deprecated API call sites",
+        "count",
+        "high_is_bad",
+        ("module", "global"),
+        "int",
+    ),
+    "format_drift_files": (
+        "Files off formatter style",
+        "count",
+        "high_is_bad",
+        ("global",),
+        "int",
+    ),
+    "format_drift_lines": (
+        "Lines off formatter style",
+        "lines",
+        "high_is_bad",
+        ("module", "global"),
+        "int",
+    ),
+    "call_centrality_gini": (
+        "Call Centrality Inequality",
+        "ratio",
+        "high_is_bad",
+        ("global",),
+        "float",
+    ),
+    "package_pagerank": ("Package Centrality", "score", "high_is_bad", ("module",), "float"),
+    "package_betweenness": ("Package Bridge Score", "score", "high_is_bad", ("module",), "float"),
+    "corpus_compression_ratio": (
+        "Compression ratio",
+        "ratio",
+        "neutral",
+        ("global",),
+        "float",
+    ),
+    "cross_file_redundancy": (
+        "Cross-file redundancy",
+        "ratio",
+        "high_is_bad",
+        ("global",),
+        "float",
+    ),
+    "information_density": (
+        "Compressed bits per line",
+        "bits",
+        "neutral",
+        ("module", "global"),
+        "float",
+    ),
+    "vocabulary_exponent": (
+        "Vocabulary growth exponent",
+        "score",
+        "neutral",
+        ("module", "global"),
+        "float",
+    ),
+    "function_complexity_p50": (
+        "Median function complexity",
+        "score",
+        "high_is_bad",
+        ("global",),
+        "float",
+    ),
+    "function_complexity_p90": (
+        "90th pct function complexity",
+        "score",
+        "high_is_bad",
+        ("module", "global"),
+        "float",
+    ),
+    "function_complexity_mean": (
+        "Mean function complexity",
+        "score",
+        "high_is_bad",
+        ("module",),
+        "float",
+    ),
+    "function_complexity_max": (
+        "Max function complexity",
+        "score",
+        "high_is_bad",
+        ("module",),
+        "float",
+    ),
+    "function_lines_p50": ("Median function length", "lines", "high_is_bad", ("module",), "float"),
+    "function_lines_p90": (
+        "90th pct function length",
+        "lines",
+        "high_is_bad",
+        ("module", "global"),
+        "float",
+    ),
+    "function_size_gini": (
+        "Function size inequality",
+        "ratio",
+        "high_is_bad",
+        ("module",),
+        "float",
+    ),
+    "longest_function_share": (
+        "Lines in longest function",
+        "ratio",
+        "high_is_bad",
+        ("module",),
+        "float",
+    ),
+    "duplication_ratio": ("Duplicated lines", "ratio", "high_is_bad", ("global",), "float"),
+    "duplication": ("Duplicated lines", "ratio", "high_is_bad", ("module",), "float"),
+    "duplicated_lines": (
+        "Duplicated line count",
+        "lines",
+        "high_is_bad",
+        ("module", "global"),
+        "int",
+    ),
+    "clone_classes": ("Clone classes", "count", "high_is_bad", ("global",), "int"),
+    "line_coverage": ("Line coverage", "ratio", "high_is_good", ("module", "global"), "float"),
+    "untested_complex_functions": (
+        "Complex functions untested",
+        "count",
+        "high_is_bad",
+        ("module", "global"),
+        "int",
+    ),
+    "api_surface": ("Exported identifiers", "count", "neutral", ("module", "global"), "int"),
+    "api_surface_share": ("Exported share", "ratio", "neutral", ("module",), "float"),
+    "magic_numbers": ("Magic numbers", "count", "high_is_bad", ("module", "global"), "int"),
+    "repeated_literals": (
+        "Repeated literals",
+        "count",
+        "high_is_bad",
+        ("module", "global"),
+        "int",
+    ),
+    "error_checks": ("Error checks", "count", "neutral", ("module", "global"), "int"),
+    "error_hygiene_issues": (
+        "Error hygiene issues",
+        "count",
+        "high_is_bad",
+        ("module", "global"),
+        "int",
+    ),
+}
+
+# Metrics reported at more scopes than the signal registry declares
+_EXTRA_SCOPES = {"function_count": ("module",)}
+
+_SCOPE_ORDER = ("file", "module", "global")
+
+
+def metric_types() -> list[MetricType]:
+    """Every metric in snapshots and signal output: registry signals, then the rest."""
+    from .infrastructure.signals import REGISTRY
+
+    metrics = []
+    for signal, meta in REGISTRY.items():
+        label, unit = _SIGNALS.get(signal.value, (_title(signal.value), "score"))
+        scopes = {meta.scope, *_EXTRA_SCOPES.get(signal.value, ())}
+        metrics.append(
+            MetricType(
+                id=signal.value,
+                label=label,
+                unit=unit,
+                direction=meta.polarity,
+                scopes=tuple(s for s in _SCOPE_ORDER if s in scopes),
+                dtype=meta.dtype.__name__,
+            )
+        )
+    known = {m.id for m in metrics}
+    for metric_id, (label, unit, direction, scopes, dtype) in _SNAPSHOT_METRICS.items():
+        if metric_id not in known:
+            metrics.append(MetricType(metric_id, label, unit, direction, scopes, dtype))
+    return metrics
+
+
+def finding_types() -> list[FindingType]:
+    """Every finding type the analysis can report, sorted by id."""
+    from .cli._concerns import FINDING_TO_CONCERN
+    from .cli._finding_display import get_display_config
+    from .hygiene.auth import AUTH_ISSUE_TYPE, AUTH_RULES
+    from .hygiene.crypto import CRYPTO_RULES, CRYPTO_VIOLATION_TYPE
+    from .hygiene.errors import ERROR_HYGIENE_TYPE, ERROR_RULES
+    from .hygiene.todos import COMMENT_DEBT_TYPE
+    from .insights.finders.registry import ALL_PATTERNS
+    from .server.api import CATEGORY_MAP
+
+    rules = {
+        AUTH_ISSUE_TYPE: AUTH_RULES,
+        CRYPTO_VIOLATION_TYPE: CRYPTO_RULES,
+        ERROR_HYGIENE_TYPE: ERROR_RULES,
+    }
+    patterns = {p.name: p for p in ALL_PATTERNS}
+    ids = set(CATEGORY_MAP) | set(patterns) | {COMMENT_DEBT_TYPE}
+
+    types = []
+    for finding_id in sorted(ids):
+        display = get_display_config(finding_id)
+        concern = FINDING_TO_CONCERN.get(finding_id)
+        description = display["interpretation"]
+        if not description and finding_id in patterns:
+            description = patterns[finding_id].description
+        types.append(
+            FindingType(
+                id=finding_id,
+                label=display["label"],
+                category=CATEGORY_MAP.get(finding_id),
+                concern=concern.key if concern is not None else None,
+                rules=tuple(rules.get(finding_id, ())),
+                description=description,
+                data_points=tuple(display["data_points"]),
+            )
+        )
+    return types
+
+
+def registry() -> dict:
+    """The whole registry as one JSON-serializable dict."""
+    from . import __version__
+    from .cli._concerns import CONCERNS
+    from .server.api import CATEGORY_LABELS
+
+    return {
+        "registry_version": REGISTRY_VERSION,
+        "tool_version": __version__,
+        "units": list(UNITS),
+        "metrics": [m.to_dict() for m in metric_types()],
+        "findings": [f.to_dict() for f in finding_types()],
+        "categories": dict(CATEGORY_LABELS),
+        "concerns": {c.key: c.name for c in CONCERNS},
+    }
+
+
+def _title(name: str) -> str:
+    return name.replace("_", " ").title()
@@ -0,0 +1,25 @@
+"""Onboarding: an orientation guide for engineers new to a codebase.
+
+``shannon-insight onboard [SCOPE]`` lists, for a package or service, where
+to start reading, the symbols everything else leans on, the key data
+models, the external integrations and the files to leave alone at first,
+all derived from the analysis.
+
+Usage:
+    from shannon_insight.api import analyze
+    from shannon_insight.hygiene import load_sources
+    from shannon_insight.onboarding import build_guide
+
+    _, snapshot = analyze("/path/to/repo")
+    sources = load_sources("/path/to/repo")
+    guide = build_guide(sources, snapshot, scope="services/billing")
+    print(guide.render_markdown())
+"""
+
+from .guide import OrientationGuide, build_guide, in_scope
+
+__all__ = [
+    "OrientationGuide",
+    "build_guide",
+    "in_scope",
+]
@@ -0,0 +1,308 @@
+"""The orientation guide: where a new engineer should start reading.
+
+Derived entirely from analysis data. File signals (roles, risk, centrality)
+come from a snapshot; symbols, decorators and imports from the parsed
+sources of the same tree. Sections:
+
+    entry points      files that start things (main guards, CLI commands)
+                      and the route, command and action handlers in them
+    central symbols   the functions and classes the most code depends on,
+                      by PageRank over the call graph
+    data models       classes of model files, most imported file first
+    integrations      third-party packages and how many files use each
+    riskiest files    high risk score: leave them until you know the code
+"""
+
+from __future__ import annotations
+
+from collections import defaultdict
+from dataclasses import dataclass, field
+from typing import Any, Optional
+
+from ..graph.builder import build_dependency_graph
+from ..graph.symbols import SymbolRank, call_graph, has_call_targets, rank_symbols
+from ..hygiene.sources import SourceSet
+from ..persistence.models import TensorSnapshot
+from ..scanning.rails import ACTION_DECORATOR
+from ..semantics.roles import CLI_DECORATORS, ENTRY_POINT_DECORATORS
+
+_ENTRY_ROLES = frozenset({"entry_point", "cli"})
+
+# Percentile (0-1) from which a signal counts as a reason a file is risky
+_HIGH_PERCENTILE = 0.8
+_RISK_REASONS = (
+    ("blast_radius_size", "changes ripple widely"),
+    ("cognitive_load", "hard to follow"),
+    ("total_changes", "changes often"),
+    ("pagerank", "central to the dependency graph"),
+)
+
+
+@dataclass
+class EntryPoint:
+    """A file that starts things, and the handlers it declares."""
+
+    path: str
+    role: str
+    handlers: list[str] = field(default_factory=list)  # "name (decorator)"
+
+
+@dataclass
+class DataModel:
+    """A class of a model file."""
+
+    path: str
+    name: str
+    fields: list[str]
+    importers: int  # files importing the model's file
+
+
+@dataclass
+class Integration:
+    """A third-party package and the files that import it."""
+
+    package: str
+    files: list[str]
+
+
+@dataclass
+class RiskyFile:
+    """A file to avoid changing first, and why."""
+
+    path: str
+    risk_score: float
+    reasons: list[str]
+
+
+@dataclass
+class OrientationGuide:
+    """Everything the onboarding guide reports on.
+
+    Attributes:
+        name: Repository name
+        scope: Package or directory the guide covers ("" = whole repository)
+        files: Files in scope
+        entry_points: Entry-point files, handlers first
+        central_symbols: Most called symbols in scope, most central first
+        call_graph_available: False when no file was parsed with call targets
+        data_models: Model classes, most imported first
+        integrations: Third-party packages, most used first
+        riskiest_files: Highest-risk files, riskiest first
+    """
+
+    name: str
+    scope: str = ""
+    files: int = 0
+    entry_points: list[EntryPoint] = field(default_factory=list)
+    central_symbols: list[SymbolRank] = field(default_factory=list)
+    call_graph_available: bool = True
+    data_models: list[DataModel] = field(default_factory=list)
+    integrations: list[Integration] = field(default_factory=list)
+    riskiest_files: list[RiskyFile] = field(default_factory=list)
+
+    def to_dict(self) -> dict[str, Any]:
+        """JSON-serializable form of the guide."""
+        return {
+            "name": self.name,
+            "scope": self.scope,
+            "files": self.files,
+            "entry_points": [vars(entry) for entry in self.entry_points],
+            "central_symbols": {
+                "call_graph_available": self.call_graph_available,
+                "symbols": [
+                    {
+                        "path": rank.symbol.path,
+                        "name": rank.symbol.name,
+                        "kind": rank.symbol.kind,
+                        "line": rank.symbol.line,
+                        "callers": rank.callers,
+                        "pagerank": round(rank.pagerank, 4),
+                    }
+                    for rank in self.central_symbols
+                ],
+            },
+            "data_models": [vars(model) for model in self.data_models],
+            "integrations": [vars(integration) for integration in self.integrations],
+            "riskiest_files": [
+                {"path": f.path, "risk_score": round(f.risk_score, 3), "reasons": f.reasons}
+                for f in self.riskiest_files
+            ],
+        }
+
+    def render_markdown(self) -> str:
+        """The guide as a Markdown document."""
+        title = f"{self.name}/{self.scope}" if self.scope else self.name
+        lines = [f"# Orientation guide: {title}", "", f"{self.files} files in scope.", ""]
+
+        lines += ["## Start here: entry points", ""]
+        for entry in self.entry_points:
+            lines.append(f"- `{entry.path}` ({entry.role.replace('_', ' ')})")
+            lines += [f"  - {handler}" for handler in entry.handlers]
+        if not self.entry_points:
+            lines.append("- None found: this code is a library, driven by its callers")
+
+        lines += ["", "## Central symbols", ""]
+        if not self.call_graph_available:
+            lines.append(
+                "- Call graph unavailable: install the parsing extra "
+                "(`pip install shannon-codebase-insight[parsing]`)"
+            )
+        lines += [
+            f"- `{rank.symbol.name}` in `{rank.symbol.path}`"
+            + (f":{rank.symbol.line}" if rank.symbol.line else "")
+            + f", called from {rank.callers} place(s)"
+            for rank in self.central_symbols
+        ]
+        if self.call_graph_available and not self.central_symbols:
+            lines.append("- No calls between symbols found")
+
+        lines += ["", "## Key data models", ""]
+        lines += [
+            f"- `{model.name}` in `{model.path}`"
+            + (f": {', '.join(model.fields)}" if model.fields else "")
+            for model in self.data_models
+        ] or ["- None found"]
+
+        lines += ["", "## External integrations", ""]
+        lines += [
+            f"- `{integration.package}` (used in {len(integration.files)} file(s))"
+            for integration in self.integrations
+        ] or ["- None found"]
+
+        lines += ["", "## Riskiest files: avoid changing these first", ""]
+        lines += [
+            f"- `{f.path}` (risk {f.risk_score:.2f})"
+            + (f": {', '.join(f.reasons)}" if f.reasons else "")
+            for f in self.riskiest_files
+        ] or ["- None found"]
+        return "\n".join(lines) + "\n"
+
+
+def in_scope(path: str, scope: str) -> bool:
+    """Whether *path* is *scope* or inside it ("" is the whole repository)."""
+    scope = scope.strip("/")
+    return not scope or path == scope or path.startswith(scope + "/")
+
+
+def build_guide(
+    sources: SourceSet, snapshot: TensorSnapshot, scope: str = "", top: int = 10
+) -> OrientationGuide:
+    """Assemble the orientation guide for *scope*.
+
+    Args:
+        sources: Parsed sources of the analyzed tree (the whole tree, so
+            calls and imports into the scope are counted)
+        snapshot: Snapshot of the analysis of the same tree
+        scope: Package or directory to cover, relative to the root
+        top: Entries per ranked section
+    """
+    scope = scope.strip("/")
+    paths = sorted(p for p in sources.syntax if in_scope(p, scope))
+    guide = OrientationGuide(name=sources.root.name, scope=scope, files=len(paths))
+    signals = snapshot.file_signals
+
+    graph = build_dependency_graph(list(sources.syntax.values()), str(sources.root))
+    guide.entry_points = _entry_points(sources, signals, paths)
+    guide.call_graph_available = has_call_targets(sources.syntax)
+    symbols, edges = call_graph(sources.syntax, graph.adjacency)
+    guide.central_symbols = [
+        rank for rank in rank_symbols(symbols, edges) if in_scope(rank.symbol.path, scope)
+    ][:top]
+    guide.data_models = _data_models(sources, signals, paths)[:top]
+
+    users: dict[str, set[str]] = defaultdict(set)
+    for path in paths:
+        language = sources.syntax[path].language
+        for imp in graph.external_imports.get(path, []):
+            users[package_name(imp, language)].add(path)
+    guide.integrations = sorted(
+        (Integration(package, sorted(files)) for package, files in users.items()),
+        key=lambda i: (-len(i.files), i.package),
+    )[:top]
+
+    guide.riskiest_files = _riskiest_files(snapshot, paths)[:top]
+    return guide
+
+
+def package_name(imp: str, language: str) -> str:
+    """The package an import belongs to (``requests`` for ``requests.adapters``)."""
+    imp = imp.strip().strip('"')
+    if language in ("javascript", "typescript"):
+        parts = imp.split("/")
+        return "/".join(parts[:2]) if imp.startswith("@") else parts[0]
+    if language == "go":
+        return "/".join(imp.split("/")[:3])
+    if language in ("java", "kotlin", "scala"):
+        return ".".join(imp.split(".")[:2])
+    if language == "rust":
+        return imp.split("::")[0]
+    if language == "ruby":
+        return imp.split("/")[0]
+    if language == "php":  # Vendor\\Package, as on Packagist
+        return "\\".join(imp.lstrip("\\").split("\\")[:2])
+    return imp.split(".")[0]
+
+
+def _role(signals: dict[str, dict[str, Any]], path: str) -> str:
+    return str(signals.get(path, {}).get("role", "unknown")).lower()
+
+
+def _entry_points(
+    sources: SourceSet, signals: dict[str, dict[str, Any]], paths: list[str]
+) -> list[EntryPoint]:
+    entries = []
+    for path in paths:
+        fs = sources.syntax[path]
+        role = _role(signals, path)
+        methods = [m for cls in fs.classes for m in cls.methods]
+        handlers = [
+            f"{fn.name} ({decorator})"
+            for fn in fs.functions + [m for m in methods if m not in fs.functions]
+            for decorator in fn.decorators[:1]
+            if _is_handler(decorator)
+        ]
+        if role in _ENTRY_ROLES or fs.has_main_guard or handlers:
+            entries.append(EntryPoint(path, role, handlers))
+    # Files with handlers declare the surface; bare main guards are scripts
+    return sorted(entries, key=lambda e: (not e.handlers, e.role not in _ENTRY_ROLES, e.path))
+
+
+def _is_handler(decorator: str) -> bool:
+    return (
+        decorator == ACTION_DECORATOR
+        or decorator in ENTRY_POINT_DECORATORS
+        or any(cli in decorator for cli in CLI_DECORATORS)
+    )
+
+
+def _data_models(
+    sources: SourceSet, signals: dict[str, dict[str, Any]], paths: list[str]
+) -> list[DataModel]:
+    models = [
+        DataModel(path, cls.name, list(cls.fields), int(signals[path].get("in_degree", 0)))
+        for path in paths
+        if path in signals and _role(signals, path) == "model"
+        for cls in sources.syntax[path].classes
+    ]
+    return sorted(models, key=lambda m: (-m.importers, -len(m.fields), m.path, m.name))
+
+
+def _riskiest_files(snapshot: TensorSnapshot, paths: list[str]) -> list[RiskyFile]:
+    risky = []
+    for path in paths:
+        signals = snapshot.file_signals.get(path)
+        if not signals or _role(snapshot.file_signals, path) == "test":
+            continue
+        percentiles: dict[str, float] = signals.get("percentiles") or {}
+        reasons = [
+            reason
+            for signal, reason in _RISK_REASONS
+            if percentiles.get(signal, 0.0) >= _HIGH_PERCENTILE
+        ]
+        bus_factor: Optional[float] = signals.get("bus_factor")
+        if snapshot.commits_analyzed > 0 and bus_factor is not None and bus_factor <= 1.0:
+            reasons.append("one author knows it")
+        risk = float(signals.get("risk_score", 0.0))
+        if risk > 0:
+            risky.append(RiskyFile(path, risk, reasons))
+    return sorted(risky, key=lambda f: (-f.risk_score, f.path))
@@ -0,0 +1,351 @@
+"""Portable history archives, for moving history between stores.
+
+An archive is gzip-compressed JSON Lines, readable with ``zcat``::
+
+    {"format": "shannon-history", "version": 1, "anonymized": false, ...}
+    {"table": "snapshots", "row": {"id": 12, "timestamp": "...", ...}}
+    {"table": "file_signals", "row": {"snapshot_id": 12, ...}}
+    ...
+    {"end": true, "rows": 48210}
+
+Tables are written parents first. Snapshots keep their id so the rows
+referring to them can be matched up on import; other serial ids are
+dropped. Importing assigns new snapshot ids, so archives merge into a
+store that already has history (SQLite or Postgres alike). A snapshot
+already present, same commit and timestamp, is skipped with its rows,
+which makes importing the same archive twice harmless.
+
+Anonymized archives replace every path segment and commit SHA with a
+salted hash (file extensions are kept so languages still show), drop
+finding titles, evidence and suggestions, and blank the analyzed path, so
+histories can be shared for benchmarking without revealing the code.
+"""
+
+from __future__ import annotations
+
+import gzip
+import hashlib
+import json
+import os
+import re
+from dataclasses import dataclass
+from datetime import datetime, timezone
+from pathlib import Path
+from typing import Any, Callable, Iterator, Optional, Sequence, Union
+
+from ..logging_config import get_logger
+from .identity import rekey
+
+logger = get_logger(__name__)
+
+ARCHIVE_FORMAT = "shannon-history"
+ARCHIVE_VERSION = 1
+
+# Rows per executemany on import
+_BATCH = 1000
+
+_IDENTIFIER_RE = re.compile(r"^[a-z_][a-z0-9_]*$")
+
+
+@dataclass(frozen=True)
+class _Table:
+    """How one history table is exported, rewritten and imported."""
+
+    name: str
+    serial: bool = False  # has an ``id`` that is not exported
+    snapshot: Optional[str] = "snapshot_id"  # column holding the snapshot id
+    paths: tuple[str, ...] = ()  # columns holding a file or module path
+    path_lists: tuple[str, ...] = ()  # columns holding a JSON list of paths
+    hashed: tuple[str, ...] = ()  # hashed when anonymizing
+    redacted: tuple[tuple[str, Any], ...] = ()  # replaced when anonymizing
+
+
+# Parents first. finding_lifecycle is rebuilt from the findings instead.
+_TABLES = (
+    _Table(
+        "snapshots",
+        snapshot=None,
+        hashed=("commit_sha",),
+        redacted=(("analyzed_path", ""),),
+    ),
+    _Table("file_signals", serial=True, paths=("file_path",)),
+    _Table("codebase_signals", serial=True),
+    _Table(
+        "findings",
+        serial=True,
+        path_lists=("files",),
+        hashed=("identity_key",),
+        redacted=(("title", ""), ("evidence", "[]"), ("suggestion", "")),
+    ),
+    _Table("dependency_edges", serial=True, paths=("src", "dst")),
+    _Table("signal_history", paths=("file_path",)),
+    _Table("module_signal_history", paths=("module_path",)),
+    _Table("global_signal_history"),
+    _Table("cochange_edges", paths=("file_a", "file_b")),
+    _Table("architecture_modules", paths=("module_path",)),
+    _Table("architecture_layers", path_lists=("modules",)),
+    _Table(
+        "architecture_violations",
+        serial=True,
+        paths=("source_module", "target_module"),
+    ),
+    _Table("delta_h", paths=("file_path",)),
+    _Table("communities", path_lists=("members",)),
+    _Table("node_community", paths=("file_path",)),
+    _Table("modularity_score"),
+    _Table("snapshot_grammars"),
+    _Table("baseline_history", serial=True, redacted=(("ref", None),)),
+    _Table("baseline", snapshot="snapshot_id"),
+    _Table("signal_rollups", snapshot=None, paths=("entity",)),
+    _Table("gate_rollout", snapshot=None),
+)
+_BY_NAME = {t.name: t for t in _TABLES}
+
+_REBUILD_LIFECYCLE = """
+INSERT INTO finding_lifecycle
+    (identity_key, first_seen_snapshot, last_seen_snapshot,
+     persistence_count, current_status, finding_type, severity)
+SELECT identity_key, MIN(snapshot_id), MAX(snapshot_id), COUNT(DISTINCT snapshot_id),
+       CASE WHEN MAX(snapshot_id) = (SELECT MAX(id) FROM snapshots)
+            THEN 'active' ELSE 'resolved' END,
+       MAX(finding_type), MAX(severity)
+FROM findings
+WHERE identity_key NOT IN (SELECT identity_key FROM finding_lifecycle)
+GROUP BY identity_key
+"""
+
+
+class ArchiveError(Exception):
+    """Raised for files that are not readable history archives."""
+
+
+@dataclass
+class ArchiveStats:
+    """What an export or import moved."""
+
+    snapshots: int = 0
+    skipped: int = 0  # snapshots already in the target store
+    rows: int = 0
+
+    def to_dict(self) -> dict:
+        return {"snapshots": self.snapshots, "skipped": self.skipped, "rows": self.rows}
+
+
+class _Anonymizer:
+    """Salted, consistent pseudonyms for paths and identifiers."""
+
+    def __init__(self, salt: Optional[bytes] = None) -> None:
+        self._salt = salt or os.urandom(16)
+
+    def token(self, text: str) -> str:
+        return hashlib.blake2b(text.encode("utf-8"), key=self._salt, digest_size=6).hexdigest()
+
+    def path(self, path: str) -> str:
+        if not path:
+            return path
+        parts = path.split("/")
+        stem, dot, ext = parts[-1].rpartition(".")
+        last = self.token(stem) + dot + ext if stem else self.token(parts[-1])
+        return "/".join([self.token(p) for p in parts[:-1]] + [last])
+
+
+def path_rewriter(prefixes: Sequence[tuple[str, str]]) -> Callable[[str], str]:
+    """Map paths under each ``old`` directory prefix to ``new``; first match wins.
+
+    An empty ``old`` matches every path, so ``("", "svc")`` moves a whole
+    history under ``svc/``.
+    """
+    pairs = [(old.strip("/"), new.strip("/")) for old, new in prefixes]
+
+    def rewrite(path: str) -> str:
+        for old, new in pairs:
+            if not old:
+                rest = path
+            elif path == old:
+                rest = ""
+            elif path.startswith(old + "/"):
+                rest = path[len(old) + 1 :]
+            else:
+                continue
+            return "/".join(p for p in (new, rest) if p)
+        return path
+
+    return rewrite
+
+
+def _rewrite_row(
+    table: _Table, row: dict[str, Any], path: Callable[[str], str]
+) -> dict[str, Any]:
+    for column in table.paths:
+        if row.get(column):
+            row[column] = path(row[column])
+    for column in table.path_lists:
+        if row.get(column):
+            row[column] = json.dumps([path(p) for p in json.loads(row[column])])
+    return row
+
+
+def _anonymize_row(table: _Table, row: dict[str, Any], anon: _Anonymizer) -> dict[str, Any]:
+    _rewrite_row(table, row, anon.path)
+    for column in table.hashed:
+        if row.get(column):
+            row[column] = anon.token(row[column])
+    for column, value in table.redacted:
+        if column in row:
+            row[column] = value
+    return row
+
+
+def _rows(conn: Any, table: _Table) -> Iterator[dict[str, Any]]:
+    order = table.snapshot or "1"  # snapshots and rollups: by their first column
+    for row in conn.execute(f"SELECT * FROM {table.name} ORDER BY {order}"):
+        record = dict(row)
+        if table.serial:
+            record.pop("id", None)
+        yield record
+
+
+def export_history(db: Any, path: Union[str, Path], anonymize: bool = False) -> ArchiveStats:
+    """Write the history in the connected ``HistoryDB`` *db* to *path*."""
+    from .. import __version__
+
+    anon = _Anonymizer() if anonymize else None
+    stats = ArchiveStats()
+    header = {
+        "format": ARCHIVE_FORMAT,
+        "version": ARCHIVE_VERSION,
+        "tool_version": __version__,
+        "exported_at": datetime.now(timezone.utc).isoformat(),
+        "anonymized": anonymize,
+    }
+    with gzip.open(path, "wt", encoding="utf-8") as out:
+        out.write(json.dumps(header) + "\n")
+        for table in _TABLES:
+            for row in _rows(db.conn, table):
+                if anon is not None:
+                    row = _anonymize_row(table, row, anon)
+                out.write(json.dumps({"table": table.name, "row": row}) + "\n")
+                stats.rows += 1
+                if table.name == "snapshots":
+                    stats.snapshots += 1
+        out.write(json.dumps({"end": True, "rows": stats.rows}) + "\n")
+    logger.info("Exported %d snapshots (%d rows) to %s", stats.snapshots, stats.rows, path)
+    return stats
+
+
+def _read(path: Union[str, Path]) -> Iterator[dict[str, Any]]:
+    """Archive records after the header; raises ArchiveError if damaged."""
+    try:
+        with gzip.open(path, "rt", encoding="utf-8") as src:
+            header = json.loads(src.readline() or "null")
+            if not isinstance(header, dict) or header.get("format") != ARCHIVE_FORMAT:
+                raise ArchiveError(f"{path} is not a history archive")
+            if header.get("version") != ARCHIVE_VERSION:
+                raise ArchiveError(f"unsupported history archive version {header.get('version')}")
+            rows = 0
+            for line in src:
+                record = json.loads(line)
+                if record.get("end"):
+                    if record.get("rows") != rows:
+                        raise ArchiveError(f"{path} is incomplete")
+                    return
+                rows += 1
+                yield record
+    except (OSError, EOFError, ValueError) as e:
+        raise ArchiveError(f"cannot read {path}: {e}") from e
+    raise ArchiveError(f"{path} is truncated")
+
+
+def _insert_sql(table: str, columns: Sequence[str]) -> str:
+    if table not in _BY_NAME or not all(_IDENTIFIER_RE.match(c) for c in columns):
+        raise ArchiveError(f"unexpected table or column in archive: {table}")
+    keyed = ("baseline", "signal_rollups", "gate_rollout")
+    conflict = " ON CONFLICT DO NOTHING" if table in keyed else ""
+    marks = ", ".join("?" * len(columns))
+    return f"INSERT INTO {table} ({', '.join(columns)}) VALUES ({marks}){conflict}"
+
+
+def import_history(
+    db: Any,
+    path: Union[str, Path],
+    rewrite: Optional[Callable[[str], str]] = None,
+) -> ArchiveStats:
+    """Merge the archive at *path* into the connected ``HistoryDB`` *db*.
+
+    *rewrite* maps every file and module path (see ``path_rewriter``);
+    finding identity keys are recomputed from the rewritten files so
+    findings keep matching later analyses. Everything is imported in one
+    transaction: a damaged archive leaves the store untouched.
+    """
+    conn = db.conn
+    stats = ArchiveStats()
+    existing = {
+        (r["commit_sha"], r["timestamp"]): r["id"]
+        for r in conn.execute("SELECT id, commit_sha, timestamp FROM snapshots")
+    }
+    new_ids: dict[int, int] = {}  # archive snapshot id -> id in this store
+    skipped: set[int] = set()
+    pending: list[tuple[Any, ...]] = []
+    pending_sql = ""
+
+    def flush() -> None:
+        if pending:
+            conn.executemany(pending_sql, pending)
+            pending.clear()
+
+    try:
+        for record in _read(path):
+            table = _BY_NAME.get(record.get("table", ""))
+            row = record.get("row")
+            if table is None or not isinstance(row, dict):
+                raise ArchiveError(f"unexpected record in archive: {str(record)[:80]}")
+            if rewrite is not None:
+                _rewrite_row(table, row, rewrite)
+                if table.name == "findings":
+                    files = json.loads(row.get("files") or "[]")
+                    row["identity_key"] = rekey(row["identity_key"], row["finding_type"], files)
+
+            if table.name == "snapshots":
+                flush()
+                archive_id = row.pop("id")
+                key = (row.get("commit_sha"), row.get("timestamp"))
+                if key in existing:
+                    new_ids[archive_id] = existing[key]
+                    skipped.add(archive_id)
+                    stats.skipped += 1
+                    continue
+                columns = list(row)
+                cur = conn.execute(_insert_sql("snapshots", columns), [row[c] for c in columns])
+                new_ids[archive_id] = existing[key] = cur.lastrowid
+                stats.snapshots += 1
+                stats.rows += 1
+                continue
+
+            if table.snapshot:
+                archive_id = row.get(table.snapshot)
+                if archive_id in skipped:
+                    continue
+                if archive_id not in new_ids:
+                    raise ArchiveError(f"{table.name} row refers to unknown snapshot {archive_id}")
+                row[table.snapshot] = new_ids[archive_id]
+            columns = list(row)
+            sql = _insert_sql(table.name, columns)
+            if sql != pending_sql or len(pending) >= _BATCH:
+                flush()
+                pending_sql = sql
+            pending.append(tuple(row[c] for c in columns))
+            stats.rows += 1
+        flush()
+        conn.execute(_REBUILD_LIFECYCLE)
+        conn.commit()
+    except Exception:
+        conn.rollback()
+        raise
+    logger.info(
+        "Imported %d snapshots (%d rows) from %s; %d already present",
+        stats.snapshots,
+        stats.rows,
+        path,
+        stats.skipped,
+    )
+    return stats
@@ -0,0 +1,367 @@
+"""Compressed result bundles with random access.
+
+A bundle stores one TensorSnapshot in a single file that can be queried
+without loading it whole. Records are grouped into independently compressed
+blocks; a trailing index maps every record key to its block, so reading one
+file's signals decompresses one block, not the entire run.
+
+Layout::
+
+    header   b"SIBUNDLE" + format version (1 byte) + codec id (1 byte)
+    blocks   compressed JSON objects {key: record}, keys in sorted order
+    index    compressed JSON {"blocks": [[offset, length], ...], "keys": {key: block}}
+    footer   index offset and length (little-endian u64, u64) + b"SIBEND\\0\\0"
+
+Record keys:
+
+    meta            snapshot metadata, global signals, architecture summary
+    findings        list of finding records
+    edges           dependency and cochange edges
+    file:<path>     file signals plus delta_h and community
+    module:<path>   module signals
+
+Blocks are zstd-compressed when the ``zstandard`` package is installed
+(``pip install shannon-codebase-insight[bundle]``), zlib otherwise; readers
+need whichever codec the writer used.
+"""
+
+from __future__ import annotations
+
+import json
+import struct
+import threading
+import zlib
+from dataclasses import asdict, dataclass, field
+from pathlib import Path
+from typing import Any, Iterator, Optional, Union
+
+from .models import EvidenceRecord, FindingRecord, TensorSnapshot
+
+BUNDLE_VERSION = 1
+BUNDLE_SUFFIX = ".sib"
+
+_MAGIC = b"SIBUNDLE"
+_FOOTER_MAGIC = b"SIBEND\0\0"
+_FOOTER = struct.Struct("<QQ")
+_HEADER_SIZE = len(_MAGIC) + 2
+
+# Records per compressed block: big enough to compress well, small enough
+# that a point lookup stays cheap
+BLOCK_RECORDS = 256
+
+_CODECS = {1: "zlib", 2: "zstd"}
+
+_META_FIELDS = (
+    "schema_version",
+    "tool_version",
+    "commit_sha",
+    "timestamp",
+    "analyzed_path",
+    "file_count",
+    "module_count",
+    "commits_analyzed",
+    "analyzers_ran",
+    "config_hash",
+    "grammar_versions",
+    "global_signals",
+    "modules",
+    "layers",
+    "violations",
+    "communities",
+    "modularity_score",
+)
+
+
+class BundleError(Exception):
+    """Raised for files that are not readable result bundles."""
+
+
+def _zstd() -> Any:
+    try:
+        import zstandard
+
+        return zstandard
+    except ImportError:
+        return None
+
+
+def default_codec() -> str:
+    return "zstd" if _zstd() is not None else "zlib"
+
+
+def _compress(data: bytes, codec: str) -> bytes:
+    if codec == "zstd":
+        return _zstd().ZstdCompressor(level=10).compress(data)
+    return zlib.compress(data, 6)
+
+
+def _decompress(data: bytes, codec: str) -> bytes:
+    if codec == "zstd":
+        zstandard = _zstd()
+        if zstandard is None:
+            raise BundleError(
+                "bundle is zstd-compressed; install with: "
+                "pip install shannon-codebase-insight[bundle]"
+            )
+        return zstandard.ZstdDecompressor().decompress(data)
+    return zlib.decompress(data)
+
+
+def _plain(value: Any) -> Any:
+    """JSON fallback for numpy scalars and sets found in signal dicts."""
+    if hasattr(value, "item"):
+        return value.item()
+    if isinstance(value, (set, frozenset)):
+        return sorted(value)
+    raise TypeError(f"cannot store {type(value).__name__} in a bundle")
+
+
+def _encode(value: Any) -> bytes:
+    text = json.dumps(value, separators=(",", ":"), sort_keys=True, default=_plain)
+    return text.encode("utf-8")
+
+
+def _records(snapshot: TensorSnapshot) -> dict[str, Any]:
+    records: dict[str, Any] = {
+        "meta": {name: getattr(snapshot, name) for name in _META_FIELDS},
+        "findings": [asdict(f) for f in snapshot.findings],
+        "edges": {
+            "dependency": [list(e) for e in snapshot.dependency_edges],
+            "cochange": [list(e) for e in snapshot.cochange_edges],
+        },
+    }
+    for path, signals in snapshot.file_signals.items():
+        records[f"file:{path}"] = {
+            "signals": signals,
+            "delta_h": snapshot.delta_h.get(path),
+            "community": snapshot.node_community.get(path),
+        }
+    for path, signals in snapshot.module_signals.items():
+        records[f"module:{path}"] = signals
+    return records
+
+
+def write_bundle(
+    path: Union[str, Path], snapshot: TensorSnapshot, codec: Optional[str] = None
+) -> int:
+    """Write *snapshot* as a bundle; returns the bundle size in bytes."""
+    codec = codec or default_codec()
+    codec_id = next((i for i, name in _CODECS.items() if name == codec), None)
+    if codec_id is None:
+        raise ValueError(f"unknown codec {codec!r}")
+    if codec == "zstd" and _zstd() is None:
+        raise BundleError("zstd requested but the zstandard package is not installed")
+
+    records = _records(snapshot)
+    keys = sorted(records)
+    blocks: list[list[int]] = []
+    key_block: dict[str, int] = {}
+    with open(path, "wb") as out:
+        out.write(_MAGIC + bytes([BUNDLE_VERSION, codec_id]))
+        for start in range(0, len(keys), BLOCK_RECORDS):
+            chunk = keys[start : start + BLOCK_RECORDS]
+            data = _compress(_encode({k: records[k] for k in chunk}), codec)
+            blocks.append([out.tell(), len(data)])
+            for key in chunk:
+                key_block[key] = len(blocks) - 1
+            out.write(data)
+        index = _compress(_encode({"blocks": blocks, "keys": key_block}), codec)
+        index_offset = out.tell()
+        out.write(index)
+        out.write(_FOOTER.pack(index_offset, len(index)) + _FOOTER_MAGIC)
+        return out.tell()
+
+
+def is_bundle(path: Union[str, Path]) -> bool:
+    try:
+        with open(path, "rb") as f:
+            return f.read(len(_MAGIC)) == _MAGIC
+    except OSError:
+        return False
+
+
+class ResultBundle:
+    """Random-access reader; only the key index is held in memory.
+
+    Usage::
+
+        with ResultBundle(path) as bundle:
+            bundle.meta["file_count"]
+            bundle.file_signals("src/app.py")
+            for path, signals in bundle.iter_file_signals():
+                ...
+    """
+
+    def __init__(self, path: Union[str, Path]) -> None:
+        self.path = Path(path)
+        self._file = open(self.path, "rb")
+        self._lock = threading.Lock()
+        self._cached_block: tuple[int, dict[str, Any]] = (-1, {})
+        try:
+            self._read_index()
+        except Exception:
+            self._file.close()
+            raise
+
+    def _read_index(self) -> None:
+        header = self._file.read(_HEADER_SIZE)
+        if len(header) < _HEADER_SIZE or header[: len(_MAGIC)] != _MAGIC:
+            raise BundleError(f"{self.path} is not a result bundle")
+        version, codec_id = header[len(_MAGIC)], header[len(_MAGIC) + 1]
+        if version != BUNDLE_VERSION:
+            raise BundleError(f"{self.path}: unsupported bundle version {version}")
+        if codec_id not in _CODECS:
+            raise BundleError(f"{self.path}: unknown codec id {codec_id}")
+        self.codec = _CODECS[codec_id]
+
+        footer_size = _FOOTER.size + len(_FOOTER_MAGIC)
+        if self._file.seek(0, 2) < _HEADER_SIZE + footer_size:
+            raise BundleError(f"{self.path} is truncated")
+        self._file.seek(-footer_size, 2)
+        footer = self._file.read(footer_size)
+        if footer[_FOOTER.size :] != _FOOTER_MAGIC:
+            raise BundleError(f"{self.path} is truncated")
+        offset, length = _FOOTER.unpack(footer[: _FOOTER.size])
+        self._file.seek(offset)
+        index = json.loads(_decompress(self._file.read(length), self.codec))
+        self._blocks: list[list[int]] = index["blocks"]
+        self._keys: dict[str, int] = index["keys"]
+
+    def close(self) -> None:
+        self._file.close()
+
+    def __enter__(self) -> ResultBundle:
+        return self
+
+    def __exit__(self, *exc: Any) -> None:
+        self.close()
+
+    def _block(self, number: int) -> dict[str, Any]:
+        with self._lock:
+            if self._cached_block[0] != number:
+                offset, length = self._blocks[number]
+                self._file.seek(offset)
+                data = json.loads(_decompress(self._file.read(length), self.codec))
+                self._cached_block = (number, data)
+            return self._cached_block[1]
+
+    def get(self, key: str) -> Any:
+        """Record stored under *key*, or None."""
+        number = self._keys.get(key)
+        if number is None:
+            return None
+        return self._block(number).get(key)
+
+    def keys(self, prefix: str = "") -> list[str]:
+        return sorted(k for k in self._keys if k.startswith(prefix))
+
+    @property
+    def meta(self) -> dict[str, Any]:
+        return self.get("meta") or {}
+
+    def findings(self) -> list[FindingRecord]:
+        return [_finding(raw) for raw in self.get("findings") or []]
+
+    def files(self) -> list[str]:
+        return [k[len("file:") :] for k in self.keys("file:")]
+
+    def modules(self) -> list[str]:
+        return [k[len("module:") :] for k in self.keys("module:")]
+
+    def file_signals(self, path: str) -> Optional[dict[str, Any]]:
+        record = self.get(f"file:{path}")
+        return None if record is None else record["signals"]
+
+    def module_signals(self, path: str) -> Optional[dict[str, Any]]:
+        return self.get(f"module:{path}")
+
+    def iter_file_signals(self) -> Iterator[tuple[str, dict[str, Any]]]:
+        """All file signals in path order, one block in memory at a time."""
+        for key in self.keys("file:"):
+            yield key[len("file:") :], self.get(key)["signals"]
+
+    def to_snapshot(self) -> TensorSnapshot:
+        """Load everything back into a TensorSnapshot."""
+        snapshot = TensorSnapshot(**self.meta)
+        snapshot.findings = self.findings()
+        edges = self.get("edges") or {}
+        snapshot.dependency_edges = [tuple(e) for e in edges.get("dependency", [])]
+        snapshot.cochange_edges = [tuple(e) for e in edges.get("cochange", [])]
+        for key in self.keys("file:"):
+            path, record = key[len("file:") :], self.get(key)
+            snapshot.file_signals[path] = record["signals"]
+            if record.get("delta_h") is not None:
+                snapshot.delta_h[path] = record["delta_h"]
+            if record.get("community") is not None:
+                snapshot.node_community[path] = record["community"]
+        for path in self.modules():
+            snapshot.module_signals[path] = self.module_signals(path)
+        return snapshot
+
+
+def _finding(raw: dict[str, Any]) -> FindingRecord:
+    evidence = [EvidenceRecord(**e) for e in raw.pop("evidence", [])]
+    return FindingRecord(evidence=evidence, **raw)
+
+
+@dataclass
+class BundleComparison:
+    """File-level differences between two bundles."""
+
+    files_added: list[str] = field(default_factory=list)
+    files_removed: list[str] = field(default_factory=list)
+    improving: list[str] = field(default_factory=list)
+    worsening: list[str] = field(default_factory=list)
+    signal_deltas: dict[str, list[Any]] = field(default_factory=dict)  # path -> SignalDelta
+    global_deltas: list[Any] = field(default_factory=list)
+
+    def to_dict(self) -> dict:
+        return {
+            "files_added": self.files_added,
+            "files_removed": self.files_removed,
+            "improving": self.improving,
+            "worsening": self.worsening,
+            "signal_deltas": {
+                path: [asdict(d) for d in deltas] for path, deltas in self.signal_deltas.items()
+            },
+            "global_deltas": [asdict(d) for d in self.global_deltas],
+        }
+
+
+def compare_bundles(
+    old: ResultBundle,
+    new: ResultBundle,
+    metric_threshold: float = 0.01,
+    signals: Optional[list[str]] = None,
+) -> BundleComparison:
+    """Per-file signal deltas between two bundles, streamed block by block."""
+    from .diff_engine import _classify_file_health, _diff_signal_dicts
+
+    def pick(values: dict[str, Any]) -> dict[str, Any]:
+        if signals is None:
+            return values
+        return {k: v for k, v in values.items() if k in signals}
+
+    old_files, new_files = set(old.files()), set(new.files())
+    result = BundleComparison(
+        files_added=sorted(new_files - old_files),
+        files_removed=sorted(old_files - new_files),
+    )
+    for path in sorted(old_files & new_files):
+        deltas = _diff_signal_dicts(
+            pick(old.file_signals(path)), pick(new.file_signals(path)), metric_threshold
+        )
+        if not deltas:
+            continue
+        result.signal_deltas[path] = deltas
+        health = _classify_file_health(deltas)
+        if health == "improving":
+            result.improving.append(path)
+        elif health == "worsening":
+            result.worsening.append(path)
+    result.global_deltas = _diff_signal_dicts(
+        pick(old.meta.get("global_signals", {})),
+        pick(new.meta.get("global_signals", {})),
+        metric_threshold,
+    )
+    return result
@@ -6,6 +6,7 @@ plus architecture data (modules, layers, violations) and health Laplacian delta_
 
 import subprocess
 from datetime import datetime, timezone
+from pathlib import Path
 from typing import Any, Optional
 
 from .. import __version__
@@ -132,6 +133,85 @@ def capture_tensor_snapshot(
         file_signals = _collect_file_signals(store)
         global_signals = _collect_codebase_signals(store)
 
+    # Comment debt (TODO/FIXME) counts and median age, codebase and per package
+    if store.comment_debt.available:
+        from ..hygiene.todos import debt_signals
+
+        debt_global, debt_packages = debt_signals(store.comment_debt.value)
+        global_signals.update(debt_global)
+        for package, signals in debt_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Remaining call sites of deprecated symbols, codebase and per package
+    if store.deprecations.available:
+        dep_global, dep_packages = store.deprecations.value.signals()
+        global_signals.update(dep_global)
+        for package, signals in dep_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Files and lines drifting from gofmt/black/prettier, codebase and per package
+    if store.format_drift.available:
+        fmt_global, fmt_packages = store.format_drift.value.signals()
+        global_signals.update(fmt_global)
+        for package, signals in fmt_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Call-graph concentration, and package PageRank/betweenness in the import graph
+    if store.centrality.available:
+        centrality_global, centrality_packages = store.centrality.value.signals()
+        global_signals.update(centrality_global)
+        for package, signals in centrality_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Compression, cross-file redundancy and vocabulary growth, codebase and per package
+    if store.information_density.available:
+        density_global, density_packages = store.information_density.value.signals()
+        global_signals.update(density_global)
+        for package, signals in density_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Function length and complexity percentiles, from per-package sketches
+    if store.function_stats.available:
+        fn_global, fn_packages = store.function_stats.value.signals()
+        global_signals.update(fn_global)
+        for package, signals in fn_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Go error checks and error hygiene issues, codebase and per package
+    if store.error_hygiene.available:
+        err_global, err_packages = store.error_hygiene.value.signals()
+        global_signals.update(err_global)
+        for package, signals in err_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Clone classes and the share of duplicated lines, codebase and per package
+    if store.duplication.available:
+        dup_global, dup_packages = store.duplication.value.signals()
+        global_signals.update(dup_global)
+        for package, signals in dup_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Line coverage and complex functions tests barely run, codebase and per package
+    if store.coverage_risk.available:
+        cov_global, cov_packages = store.coverage_risk.value.signals()
+        global_signals.update(cov_global)
+        for package, signals in cov_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Exported identifiers, codebase and per package, with each package's exported share
+    if store.api_surface.available:
+        api_global, api_packages = store.api_surface.value.signals()
+        global_signals.update(api_global)
+        for package, signals in api_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
+    # Magic numbers and literals repeated past the threshold, codebase and per package
+    if store.literals.available:
+        lit_global, lit_packages = store.literals.value.signals()
+        global_signals.update(lit_global)
+        for package, signals in lit_packages.items():
+            module_signals.setdefault(package, {}).update(signals)
+
     # Serialize architecture if available
     modules: list[str] = []
     layers: list[dict[str, Any]] = []
@@ -170,6 +250,17 @@ def capture_tensor_snapshot(
             node_community = dict(ga.node_community)
             modularity_score = ga.modularity_score
 
+    # Parser and grammar version behind each language
+    grammar_versions: dict[str, str] = {}
+    if store.file_syntax.available:
+        from ..scanning.grammar_versions import grammar_versions as parser_versions
+
+        grammar_versions = parser_versions(
+            (syntax.language for syntax in store.file_syntax.value.values()),
+            session.config.grammars,
+            Path(store.root_dir),
+        )
+
     # Convert findings with v2 fields
     findings = _convert_findings_v2(result)
 
@@ -184,6 +275,7 @@ def capture_tensor_snapshot(
         commits_analyzed=result.store_summary.commits_analyzed,
         analyzers_ran=analyzers_ran,
         config_hash=config_hash,
+        grammar_versions=grammar_versions,
         file_signals=file_signals,
         module_signals=module_signals,
         global_signals=global_signals,
@@ -236,8 +328,16 @@ def _serialize_file_signals(fs: FileSignals) -> dict[str, Any]:
         "broken_call_count",
         "community",
         "compression_ratio",
+        "token_entropy",
         "semantic_coherence",
         "cognitive_load",
+        "halstead_volume",
+        "halstead_difficulty",
+        "halstead_effort",
+        "maintainability_index",
+        "comment_density",
+        "commented_out_lines",
+        "undocumented_complexity",
         "total_changes",
         "churn_trajectory",
         "churn_slope",
@@ -281,6 +381,7 @@ def _serialize_module_signals(ms: ModuleSignals) -> dict[str, Any]:
         "knowledge_gini",
         "module_bus_factor",
         "mean_cognitive_load",
+        "module_maintainability",
         "file_count",
         "health_score",
     ]:
@@ -305,6 +406,7 @@ def _serialize_global_signals(gs: GlobalSignals) -> dict[str, Any]:
         "phantom_ratio",
         "glue_deficit",
         "clone_ratio",
+        "duplication_ratio",
         "violation_rate",
         "conway_alignment",
         "team_size",
@@ -321,10 +423,15 @@ def _serialize_global_signals(gs: GlobalSignals) -> dict[str, Any]:
 
 
 def _convert_findings_v2(result: InsightResult) -> list[FindingRecord]:
-    """Convert Finding objects to FindingRecord with v2 fields (confidence, effort, scope)."""
+    """Convert Finding objects to FindingRecord with v2 fields (confidence, effort, scope).
+
+    Comment debt findings are appended so their lifecycle is tracked too.
+    """
     records: list[FindingRecord] = []
-    for f in result.findings:
-        identity_key = compute_identity_key(f.finding_type, f.files)
+    for f in [*result.findings, *getattr(result, "comment_debt", [])]:
+        identity_key = compute_identity_key(
+            f.finding_type, f.files, hint=getattr(f, "identity_hint", None)
+        )
         evidence = [
             EvidenceRecord(
                 signal=e.signal,
@@ -1,15 +1,24 @@
-"""SQLite-backed history database stored in .shannon/ at the project root.
+"""History database: SQLite in .shannon/ at the project root, or Postgres.
 
 V2 schema adds:
 - signal_history: per-file signal time series
 - module_signal_history: per-module signal time series
 - global_signal_history: global signal time series
 - finding_lifecycle: finding persistence tracking
+
+signal_rollups holds the aggregated signals of pruned snapshots (see
+``persistence.retention``).
+
+The ``history_url`` setting switches the store to Postgres (see
+``persistence.postgres``) so several server replicas can share one history.
+Queries are written once, in the SQLite dialect, and behave the same on
+both stores.
 """
 
 import sqlite3
+from datetime import datetime, timezone
 from pathlib import Path
-from typing import Optional
+from typing import Any, Optional
 
 from ..logging_config import get_logger
 
@@ -19,29 +28,58 @@ logger = get_logger(__name__)
 _SCHEMA_VERSION = 2
 
 
+def _configured_url(project_root: str) -> str:
+    """The ``history_url`` setting in effect for *project_root*."""
+    from ..config import load_config
+
+    try:
+        return load_config(project_dir=Path(project_root)).history_url
+    except Exception as e:
+        logger.debug("Could not read history_url, using SQLite: %s", e)
+        return ""
+
+
 class HistoryDB:
-    """Manages the ``.shannon/history.db`` SQLite database.
+    """Manages the project's history database.
 
     Usage::
 
         with HistoryDB("/path/to/project") as db:
             save_snapshot(db.conn, snapshot)
+
+    *url* selects the store: ``""`` for ``.shannon/history.db``, or a
+    ``postgresql://`` URL. When omitted it is read from the project's
+    configuration (``history_url`` / ``SHANNON_HISTORY_URL``).
     """
 
-    def __init__(self, project_root: str) -> None:
+    def __init__(self, project_root: str, url: Optional[str] = None) -> None:
+        self.project_root = project_root
         self.db_dir: Path = Path(project_root) / ".shannon"
         self.db_path: Path = self.db_dir / "history.db"
-        self._conn: Optional[sqlite3.Connection] = None
+        self.url: str = _configured_url(project_root) if url is None else url
+        self._conn: Optional[Any] = None
 
     @property
     def conn(self) -> sqlite3.Connection:
-        """Return the active connection. Raises if not connected."""
+        """Return the active connection. Raises if not connected.
+
+        With Postgres this is a ``PostgresConnection``, which accepts the
+        same SQL and returns the same rows as ``sqlite3.Connection``.
+        """
         if self._conn is None:
             raise RuntimeError(
                 "HistoryDB is not connected. Use as context manager or call connect()."
             )
         return self._conn
 
+    @property
+    def is_postgres(self) -> bool:
+        return bool(self.url)
+
+    def exists(self) -> bool:
+        """Whether there may be history to read, without creating any."""
+        return self.is_postgres or self.db_path.exists()
+
     # ── lifecycle ─────────────────────────────────────────────────
 
     def _ensure_dir(self) -> None:
@@ -53,6 +91,11 @@ class HistoryDB:
 
     def connect(self) -> sqlite3.Connection:
         """Open (or create) the database and run migrations."""
+        if self.is_postgres:
+            from .postgres import connect
+
+            self._conn = connect(self.url, self.project_root)
+            return self._conn
         self._ensure_dir()
         conn = sqlite3.connect(str(self.db_path))
         conn.execute("PRAGMA journal_mode=WAL")
@@ -183,6 +226,19 @@ class HistoryDB:
             """
         )
 
+        # ── baseline_history (every baseline ever set) ───────────
+        c.execute(
+            """
+            CREATE TABLE IF NOT EXISTS baseline_history (
+                id          INTEGER PRIMARY KEY AUTOINCREMENT,
+                snapshot_id INTEGER NOT NULL REFERENCES snapshots(id),
+                set_at      TEXT    NOT NULL,
+                reason      TEXT    NOT NULL DEFAULT 'manual',
+                ref         TEXT
+            )
+            """
+        )
+
         # ══════════════════════════════════════════════════════════
         # V2 Tables (Phase 7)
         # ══════════════════════════════════════════════════════════
@@ -347,6 +403,48 @@ class HistoryDB:
             """
         )
 
+        # ── snapshot_grammars (parser version per language) ──────
+        c.execute(
+            """
+            CREATE TABLE IF NOT EXISTS snapshot_grammars (
+                snapshot_id  INTEGER NOT NULL,
+                language     TEXT    NOT NULL,
+                version      TEXT    NOT NULL,
+                PRIMARY KEY (snapshot_id, language),
+                FOREIGN KEY (snapshot_id) REFERENCES snapshots(id) ON DELETE CASCADE
+            )
+            """
+        )
+
+        # ── gate_rollout (warn-only windows of gate rules) ──────
+        c.execute(
+            """
+            CREATE TABLE IF NOT EXISTS gate_rollout (
+                rule     TEXT    PRIMARY KEY,
+                started  TEXT    NOT NULL,
+                runs     INTEGER NOT NULL
+            )
+            """
+        )
+
+        # ── signal_rollups (signals of pruned snapshots) ─────────
+        c.execute(
+            """
+            CREATE TABLE IF NOT EXISTS signal_rollups (
+                entity_kind  TEXT    NOT NULL,
+                entity       TEXT    NOT NULL,
+                signal_name  TEXT    NOT NULL,
+                granularity  TEXT    NOT NULL,
+                period       TEXT    NOT NULL,
+                samples      INTEGER NOT NULL,
+                value_min    REAL    NOT NULL,
+                value_max    REAL    NOT NULL,
+                value_sum    REAL    NOT NULL,
+                PRIMARY KEY (entity_kind, entity, signal_name, granularity, period)
+            )
+            """
+        )
+
         # ── indexes (v1) ──────────────────────────────────────────
         c.execute("CREATE INDEX IF NOT EXISTS idx_snapshots_commit ON snapshots(commit_sha)")
         c.execute("CREATE INDEX IF NOT EXISTS idx_snapshots_timestamp ON snapshots(timestamp)")
@@ -443,16 +541,23 @@ class HistoryDB:
 
     # ── baseline management ────────────────────────────────────────
 
-    def set_baseline(self, snapshot_id: int) -> None:
+    def set_baseline(
+        self, snapshot_id: int, reason: str = "manual", ref: Optional[str] = None
+    ) -> None:
         """Set (or replace) the baseline snapshot.
 
         The baseline table uses ``id = 1`` with a CHECK constraint so there
-        can only ever be a single row.
+        can only ever be a single row. Every change is also appended to
+        ``baseline_history``.
 
         Parameters
         ----------
         snapshot_id:
             The ``snapshots.id`` to pin as baseline.
+        reason:
+            Why the baseline changed (``manual``, ``schedule``, ``merge``).
+        ref:
+            Git ref the baseline snapshot was taken from, if any.
 
         Raises
         ------
@@ -465,9 +570,14 @@ class HistoryDB:
             raise ValueError(f"No snapshot with id={snapshot_id}")
 
         self.conn.execute(
-            "INSERT OR REPLACE INTO baseline (id, snapshot_id) VALUES (1, ?)",
+            "INSERT INTO baseline (id, snapshot_id) VALUES (1, ?) "
+            "ON CONFLICT(id) DO UPDATE SET snapshot_id = excluded.snapshot_id",
             (snapshot_id,),
         )
+        self.conn.execute(
+            "INSERT INTO baseline_history (snapshot_id, set_at, reason, ref) VALUES (?, ?, ?, ?)",
+            (snapshot_id, datetime.now(timezone.utc).isoformat(), reason, ref),
+        )
         self.conn.commit()
         logger.info("Baseline set to snapshot %d", snapshot_id)
 
@@ -478,6 +588,20 @@ class HistoryDB:
             return None
         return int(row["snapshot_id"])
 
+    def get_baseline_history(self, limit: int = 20) -> list[dict]:
+        """Most recent baseline changes first, with the snapshot's commit."""
+        rows = self.conn.execute(
+            """
+            SELECT bh.snapshot_id, bh.set_at, bh.reason, bh.ref, s.commit_sha
+            FROM baseline_history bh
+            LEFT JOIN snapshots s ON s.id = bh.snapshot_id
+            ORDER BY bh.id DESC
+            LIMIT ?
+            """,
+            (limit,),
+        ).fetchall()
+        return [dict(row) for row in rows]
+
     def clear_baseline(self) -> None:
         """Remove the baseline if one is set."""
         self.conn.execute("DELETE FROM baseline")
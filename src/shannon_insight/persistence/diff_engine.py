@@ -37,6 +37,11 @@ from .models import FindingRecord, Snapshot, TensorSnapshot
 _LOWER_IS_BETTER = frozenset(
     {
         "cognitive_load",
+        "halstead_volume",
+        "halstead_difficulty",
+        "halstead_effort",
+        "commented_out_lines",
+        "undocumented_complexity",
         "blast_radius_size",
         "nesting_depth",
         "cycle_count",
@@ -61,6 +66,20 @@ _LOWER_IS_BETTER = frozenset(
         "phantom_ratio",
         "glue_deficit",
         "clone_ratio",
+        "duplication_ratio",
+        "duplication",
+        "function_complexity_p50",
+        "function_complexity_p90",
+        "function_complexity_mean",
+        "function_complexity_max",
+        "function_lines_p50",
+        "function_lines_p90",
+        "function_size_gini",
+        "longest_function_share",
+        "error_hygiene_issues",
+        "untested_complex_functions",
+        "magic_numbers",
+        "repeated_literals",
         "violation_rate",
         "team_risk",
     }
@@ -79,6 +98,10 @@ _HIGHER_IS_BETTER = frozenset(
         "bus_factor",
         "author_entropy",
         "docstring_coverage",
+        "line_coverage",
+        "maintainability_index",
+        "module_maintainability",
+        "comment_density",
         "role_consistency",
         "module_bus_factor",
         "health_score",
@@ -101,6 +124,7 @@ _NEUTRAL = frozenset(
         "lines",
         "function_count",
         "compression_ratio",
+        "token_entropy",
         # Non-directional signals
         "class_count",
         "max_nesting",
@@ -117,6 +141,8 @@ _NEUTRAL = frozenset(
         "abstractness",
         "instability",
         "team_size",
+        "api_surface",
+        "api_surface_share",
     }
 )
 
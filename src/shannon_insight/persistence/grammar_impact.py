@@ -0,0 +1,204 @@
+"""Metric changes caused by a parser upgrade rather than by the code.
+
+Each snapshot records the parser and grammar version behind each language
+(``TensorSnapshot.grammar_versions``). When two snapshots read a language
+with different parsers, the files of that language whose code did not
+change between them can only have moved through parsing: their shifts in
+the syntax-derived signals (PARSED_SIGNALS) are attributed to the upgrade.
+
+Files whose code did change are counted but not attributed, as their
+shifts mix both causes. Changed files come from ``git diff`` between the
+snapshots' commits; without it (no commits recorded, or history rewritten)
+a file with the same line count in both is taken as unchanged.
+
+By default the most recent pair of consecutive snapshots whose parsers
+differ is compared, so the report isolates the upgrade from the code
+changes of later runs.
+"""
+
+from __future__ import annotations
+
+import subprocess
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Any, Optional
+
+from ..logging_config import get_logger
+from ..scanning.languages import detect_language
+from .reader import load_tensor_snapshot
+
+logger = get_logger(__name__)
+
+# File signals computed from the parsed syntax, as opposed to text, graph or git
+PARSED_SIGNALS = (
+    "function_count",
+    "class_count",
+    "max_nesting",
+    "impl_gini",
+    "stub_ratio",
+    "import_count",
+    "cognitive_load",
+)
+
+_GIT_TIMEOUT_SECONDS = 30
+
+
+@dataclass
+class FileShift:
+    """Parsed signals of one unchanged file that moved: signal -> (before, after)."""
+
+    path: str
+    shifts: dict[str, tuple[float, float]]
+
+    def to_dict(self) -> dict:
+        return {"path": self.path, "shifts": {k: list(v) for k, v in self.shifts.items()}}
+
+
+@dataclass
+class LanguageImpact:
+    """What changing the parser of one language did to its files' metrics."""
+
+    language: str
+    before: str  # parser and version in the older snapshot
+    after: str
+    unchanged: int  # files of the language whose code did not change
+    code_changed: int  # files whose code changed too (not attributed)
+    files: list[FileShift] = field(default_factory=list)  # unchanged files that moved
+
+    def signal_totals(self) -> dict[str, tuple[int, float]]:
+        """signal -> (files moved, mean change) over the attributed files."""
+        totals: dict[str, list[float]] = {}
+        for shift in self.files:
+            for signal, (old, new) in shift.shifts.items():
+                totals.setdefault(signal, []).append(new - old)
+        return {
+            signal: (len(deltas), sum(deltas) / len(deltas))
+            for signal, deltas in sorted(totals.items())
+        }
+
+    def to_dict(self) -> dict:
+        return {
+            "language": self.language,
+            "before": self.before,
+            "after": self.after,
+            "unchanged": self.unchanged,
+            "code_changed": self.code_changed,
+            "signals": {
+                signal: {"files": count, "mean_delta": round(mean, 4)}
+                for signal, (count, mean) in self.signal_totals().items()
+            },
+            "files": [f.to_dict() for f in self.files],
+        }
+
+
+@dataclass
+class GrammarImpact:
+    before_id: int
+    after_id: int
+    languages: list[LanguageImpact]
+    git_diff: bool  # changed files known from git, not guessed from line counts
+    config_changed: bool  # the snapshots' configurations differ too
+
+    def to_dict(self) -> dict:
+        return {
+            "before_id": self.before_id,
+            "after_id": self.after_id,
+            "git_diff": self.git_diff,
+            "config_changed": self.config_changed,
+            "languages": [lang.to_dict() for lang in self.languages],
+        }
+
+
+def recorded_grammars(conn: Any) -> dict[int, dict[str, str]]:
+    """snapshot id -> language -> parser version, for snapshots that recorded them."""
+    recorded: dict[int, dict[str, str]] = {}
+    for row in conn.execute(
+        "SELECT snapshot_id, language, version FROM snapshot_grammars ORDER BY snapshot_id"
+    ):
+        recorded.setdefault(row["snapshot_id"], {})[row["language"]] = row["version"]
+    return recorded
+
+
+def latest_upgrade(recorded: dict[int, dict[str, str]]) -> Optional[tuple[int, int]]:
+    """The most recent consecutive snapshots whose parsers differ."""
+    ids = sorted(recorded)
+    for before, after in reversed(list(zip(ids, ids[1:]))):
+        if _changed_languages(recorded[before], recorded[after]):
+            return before, after
+    return None
+
+
+def grammar_impact(conn: Any, root: Path, before_id: int, after_id: int) -> GrammarImpact:
+    """Attribute the parsed-signal shifts between two snapshots to parser changes.
+
+    Raises:
+        ValueError: A snapshot does not exist
+    """
+    before = load_tensor_snapshot(conn, before_id)
+    after = load_tensor_snapshot(conn, after_id)
+    changed = changed_files(root, before.commit_sha, after.commit_sha)
+
+    impacts = []
+    for language in _changed_languages(before.grammar_versions, after.grammar_versions):
+        impact = LanguageImpact(
+            language,
+            before.grammar_versions[language],
+            after.grammar_versions[language],
+            unchanged=0,
+            code_changed=0,
+        )
+        for path in sorted(set(before.file_signals) & set(after.file_signals)):
+            if detect_language(Path(path)) != language:
+                continue
+            old, new = before.file_signals[path], after.file_signals[path]
+            if changed is not None and path in changed:
+                impact.code_changed += 1
+                continue
+            if changed is None and old.get("lines") != new.get("lines"):
+                impact.code_changed += 1
+                continue
+            impact.unchanged += 1
+            shifts = {
+                signal: (float(old[signal]), float(new[signal]))
+                for signal in PARSED_SIGNALS
+                if isinstance(old.get(signal), (int, float))
+                and isinstance(new.get(signal), (int, float))
+                and abs(new[signal] - old[signal]) > 1e-9
+            }
+            if shifts:
+                impact.files.append(FileShift(path, shifts))
+        impacts.append(impact)
+
+    return GrammarImpact(
+        before_id=before_id,
+        after_id=after_id,
+        languages=impacts,
+        git_diff=changed is not None,
+        config_changed=before.config_hash != after.config_hash,
+    )
+
+
+def changed_files(root: Path, old: Optional[str], new: Optional[str]) -> Optional[set[str]]:
+    """Files that differ between two commits; None when git cannot tell."""
+    if old is None or new is None:
+        return None
+    if old == new:
+        return set()
+    try:
+        out = subprocess.run(
+            ["git", "-C", str(root), "diff", "--name-only", "--no-renames", old, new],
+            capture_output=True,
+            text=True,
+            timeout=_GIT_TIMEOUT_SECONDS,
+        )
+    except (FileNotFoundError, subprocess.TimeoutExpired) as e:
+        logger.debug(f"git diff failed: {e}")
+        return None
+    if out.returncode != 0:
+        return None
+    return {line for line in out.stdout.splitlines() if line}
+
+
+def _changed_languages(before: dict[str, str], after: dict[str, str]) -> list[str]:
+    """Languages read by both snapshots, with different parsers."""
+    return sorted(lang for lang in set(before) & set(after) if before[lang] != after[lang])
@@ -22,11 +22,19 @@ Rules (by finding type):
 
   Persistence-aware:
     chronic_problem, architecture_erosion -> (type, wrapped_key or "codebase")
+
+  Per-location (several per file):
+    comment_debt -> (type, files[0], hint)  where hint fingerprints the comment
+
+File paths are normalized to forward slashes first, so keys computed on
+Windows match those from macOS and Linux.
 """
 
 import hashlib
 from typing import Optional
 
+from ..portable import portable_path
+
 # Types whose identity is the single primary file.
 _SINGLE_FILE_TYPES = frozenset(
     {
@@ -60,9 +68,15 @@ _PAIR_FILE_TYPES = frozenset(
 _PRIMARY_FILE_TYPES = frozenset(
     {
         "boundary_mismatch",
+        # Directory spelling one concept several ways
+        "vocabulary_drift",
+        # Canonical copy of a group of identical files
+        "duplicate_files",
         # Phase 6 MODULE scope finders
         "layer_violation",
         "zone_of_pain",
+        # Package exporting far more than its size suggests
+        "wide_api_surface",
     }
 )
 
@@ -84,10 +98,43 @@ _WRAPPER_TYPES = frozenset(
 )
 
 
+# Types with several findings per file, told apart by an identity hint.
+_HINTED_FILE_TYPES = frozenset(
+    {
+        "auth_flow_issue",
+        "code_clone",
+        "comment_debt",
+        "complexity_outlier",
+        "crypto_policy_violation",
+        "data_clump",
+        "dead_code",
+        "deep_nesting",
+        "deep_yaml_nesting",
+        "duplicate_string_resource",
+        "duplicate_yaml_block",
+        "error_hygiene_issue",
+        "god_class",
+        "hexagonal_violation",
+        "load_bearing_function",
+        "long_parameter_list",
+        "long_procedure",
+        "low_cohesion",
+        "nested_dynamic_block",
+        "notebook_drift",
+        "orchestrator_function",
+        "repeated_literal",
+        "unused_resource",
+        "untested_complexity",
+        "variable_count_outlier",
+    }
+)
+
+
 def compute_identity_key(
     finding_type: str,
     files: list[str],
     wrapped_key: Optional[str] = None,
+    hint: Optional[str] = None,
 ) -> str:
     """Return a stable SHA-256[:16] hex digest for a finding.
 
@@ -100,18 +147,25 @@ def compute_identity_key(
     wrapped_key:
         For wrapper findings (chronic_problem), the identity key of the
         wrapped finding. This ensures chronic findings track the original.
+    hint:
+        For per-location findings (comment_debt), a fingerprint that is
+        stable while the finding's subject is unchanged.
 
     Returns
     -------
     str
         16-character hex digest that uniquely identifies this finding.
     """
+    # Same key on every OS, whatever separator the caller's paths use
+    files = [portable_path(f) for f in files]
     if finding_type in _WRAPPER_TYPES:
         # Wrapper findings use the wrapped finding's key
         key_parts = [finding_type, wrapped_key or ""]
     elif finding_type in _CODEBASE_TYPES:
         # Codebase-scope findings use "codebase" literal
         key_parts = [finding_type, "codebase"]
+    elif finding_type in _HINTED_FILE_TYPES:
+        key_parts = [finding_type, files[0] if files else "", hint or ""]
     elif finding_type in _SINGLE_FILE_TYPES:
         key_parts = [finding_type, files[0] if files else ""]
     elif finding_type in _PAIR_FILE_TYPES:
@@ -125,3 +179,14 @@ def compute_identity_key(
 
     raw = "|".join(key_parts)
     return hashlib.sha256(raw.encode("utf-8")).hexdigest()[:16]
+
+
+def rekey(identity_key: str, finding_type: str, files: list[str]) -> str:
+    """Identity key of a finding whose files were renamed.
+
+    Keys that depend on more than the files (per-location hints, wrapped
+    findings) cannot be recomputed and are returned unchanged.
+    """
+    if finding_type in _WRAPPER_TYPES or finding_type in _HINTED_FILE_TYPES:
+        return identity_key
+    return compute_identity_key(finding_type, files)
@@ -93,6 +93,8 @@ class TensorSnapshot:
     commits_analyzed: int = 0
     analyzers_ran: list[str] = field(default_factory=list)
     config_hash: str = ""
+    # language -> parser and version that read it (scanning/grammar_versions.py)
+    grammar_versions: dict[str, str] = field(default_factory=dict)
 
     # ── Per-file signals (replaces v1 file_signals) ───────────────
     # Dict[file_path, Dict[signal_name, value]]
@@ -0,0 +1,458 @@
+"""Postgres history store, for servers with several replicas.
+
+Requires the ``[postgres]`` extra. Selected by ``history_url`` (see
+``HistoryDB``); the rest of the persistence layer is written against
+sqlite3, so ``connect`` returns a connection that speaks the same dialect:
+
+- ``?`` placeholders, ``cursor.lastrowid`` and ``BEGIN`` behave as in sqlite3
+- rows support access by column name and index, like ``sqlite3.Row``
+- ``GROUP_CONCAT`` maps to ``string_agg``
+- explicit ``ASC``/``DESC`` sort NULLs first/last, as SQLite does, and text
+  columns use the "C" collation so ordering is byte-wise in both stores
+
+Each repository gets its own schema, ``shannon_<directory name>`` unless
+the URL names one with ``?schema=``. Tables are created and upgraded by the
+versioned migrations below, under an advisory lock so replicas starting
+together do not race.
+"""
+
+from __future__ import annotations
+
+import re
+from decimal import Decimal
+from functools import lru_cache
+from pathlib import Path
+from typing import Any, Iterator, Optional, Sequence, Union
+from urllib.parse import parse_qsl, urlencode, urlsplit, urlunsplit
+
+from ..logging_config import get_logger
+
+logger = get_logger(__name__)
+
+SCHEMES = ("postgres://", "postgresql://")
+
+# Tables with a serial ``id``; inserts into them report ``lastrowid``
+_SERIAL_TABLES = frozenset(
+    {
+        "snapshots",
+        "file_signals",
+        "codebase_signals",
+        "findings",
+        "dependency_edges",
+        "baseline_history",
+        "architecture_violations",
+    }
+)
+
+# Any number; keeps two replicas from migrating the same schema at once
+_MIGRATION_LOCK = 0x5348414E
+
+# (version, DDL). Append new versions; never edit a released one.
+_MIGRATIONS: list[tuple[int, str]] = [
+    (
+        1,
+        """
+        CREATE TABLE snapshots (
+            id               BIGSERIAL PRIMARY KEY,
+            schema_version   INTEGER NOT NULL DEFAULT 1,
+            tool_version     TEXT COLLATE "C" NOT NULL,
+            commit_sha       TEXT COLLATE "C",
+            timestamp        TEXT COLLATE "C" NOT NULL,
+            analyzed_path    TEXT COLLATE "C" NOT NULL,
+            file_count       INTEGER NOT NULL DEFAULT 0,
+            module_count     INTEGER NOT NULL DEFAULT 0,
+            commits_analyzed INTEGER NOT NULL DEFAULT 0,
+            analyzers_ran    TEXT NOT NULL DEFAULT '[]',
+            config_hash      TEXT COLLATE "C" NOT NULL DEFAULT ''
+        );
+        CREATE TABLE file_signals (
+            id          BIGSERIAL PRIMARY KEY,
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            file_path   TEXT COLLATE "C" NOT NULL,
+            signal_name TEXT COLLATE "C" NOT NULL,
+            value       DOUBLE PRECISION NOT NULL
+        );
+        CREATE TABLE codebase_signals (
+            id          BIGSERIAL PRIMARY KEY,
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            signal_name TEXT COLLATE "C" NOT NULL,
+            value       DOUBLE PRECISION NOT NULL
+        );
+        CREATE TABLE findings (
+            id           BIGSERIAL PRIMARY KEY,
+            snapshot_id  BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            finding_type TEXT COLLATE "C" NOT NULL,
+            identity_key TEXT COLLATE "C" NOT NULL,
+            severity     DOUBLE PRECISION NOT NULL,
+            title        TEXT COLLATE "C" NOT NULL,
+            files        TEXT NOT NULL DEFAULT '[]',
+            evidence     TEXT NOT NULL DEFAULT '[]',
+            suggestion   TEXT NOT NULL DEFAULT ''
+        );
+        CREATE TABLE dependency_edges (
+            id          BIGSERIAL PRIMARY KEY,
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            src         TEXT COLLATE "C" NOT NULL,
+            dst         TEXT COLLATE "C" NOT NULL
+        );
+        CREATE TABLE baseline (
+            id          INTEGER PRIMARY KEY CHECK (id = 1),
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id)
+        );
+        CREATE TABLE baseline_history (
+            id          BIGSERIAL PRIMARY KEY,
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id),
+            set_at      TEXT COLLATE "C" NOT NULL,
+            reason      TEXT COLLATE "C" NOT NULL DEFAULT 'manual',
+            ref         TEXT COLLATE "C"
+        );
+        CREATE TABLE signal_history (
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            file_path   TEXT COLLATE "C" NOT NULL,
+            signal_name TEXT COLLATE "C" NOT NULL,
+            value       DOUBLE PRECISION,
+            percentile  DOUBLE PRECISION,
+            PRIMARY KEY (snapshot_id, file_path, signal_name)
+        );
+        CREATE TABLE module_signal_history (
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            module_path TEXT COLLATE "C" NOT NULL,
+            signal_name TEXT COLLATE "C" NOT NULL,
+            value       DOUBLE PRECISION,
+            PRIMARY KEY (snapshot_id, module_path, signal_name)
+        );
+        CREATE TABLE global_signal_history (
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            signal_name TEXT COLLATE "C" NOT NULL,
+            value       DOUBLE PRECISION,
+            PRIMARY KEY (snapshot_id, signal_name)
+        );
+        CREATE TABLE finding_lifecycle (
+            identity_key        TEXT COLLATE "C" NOT NULL PRIMARY KEY,
+            first_seen_snapshot BIGINT NOT NULL,
+            last_seen_snapshot  BIGINT NOT NULL,
+            persistence_count   INTEGER DEFAULT 1,
+            current_status      TEXT COLLATE "C" DEFAULT 'active',
+            finding_type        TEXT COLLATE "C" NOT NULL,
+            severity            DOUBLE PRECISION
+        );
+        CREATE TABLE cochange_edges (
+            snapshot_id    BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            file_a         TEXT COLLATE "C" NOT NULL,
+            file_b         TEXT COLLATE "C" NOT NULL,
+            weight         DOUBLE PRECISION NOT NULL,
+            lift           DOUBLE PRECISION,
+            confidence_a_b DOUBLE PRECISION,
+            confidence_b_a DOUBLE PRECISION,
+            cochange_count INTEGER,
+            PRIMARY KEY (snapshot_id, file_a, file_b)
+        );
+        CREATE TABLE architecture_modules (
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            module_path TEXT COLLATE "C" NOT NULL,
+            PRIMARY KEY (snapshot_id, module_path)
+        );
+        CREATE TABLE architecture_layers (
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            depth       INTEGER NOT NULL,
+            modules     TEXT NOT NULL,
+            PRIMARY KEY (snapshot_id, depth)
+        );
+        CREATE TABLE architecture_violations (
+            id             BIGSERIAL PRIMARY KEY,
+            snapshot_id    BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            source_module  TEXT COLLATE "C" NOT NULL,
+            target_module  TEXT COLLATE "C" NOT NULL,
+            violation_type TEXT COLLATE "C" NOT NULL
+        );
+        CREATE TABLE delta_h (
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            file_path   TEXT COLLATE "C" NOT NULL,
+            value       DOUBLE PRECISION NOT NULL,
+            PRIMARY KEY (snapshot_id, file_path)
+        );
+        CREATE TABLE communities (
+            snapshot_id  BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            community_id INTEGER NOT NULL,
+            members      TEXT NOT NULL,
+            PRIMARY KEY (snapshot_id, community_id)
+        );
+        CREATE TABLE node_community (
+            snapshot_id  BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            file_path    TEXT COLLATE "C" NOT NULL,
+            community_id INTEGER NOT NULL,
+            PRIMARY KEY (snapshot_id, file_path)
+        );
+        CREATE TABLE modularity_score (
+            snapshot_id BIGINT NOT NULL PRIMARY KEY REFERENCES snapshots(id) ON DELETE CASCADE,
+            score       DOUBLE PRECISION NOT NULL
+        );
+        CREATE INDEX idx_snapshots_commit ON snapshots(commit_sha);
+        CREATE INDEX idx_snapshots_timestamp ON snapshots(timestamp);
+        CREATE INDEX idx_file_signals_snapshot ON file_signals(snapshot_id);
+        CREATE INDEX idx_file_signals_path ON file_signals(snapshot_id, file_path);
+        CREATE INDEX idx_codebase_signals_snapshot ON codebase_signals(snapshot_id);
+        CREATE INDEX idx_findings_snapshot ON findings(snapshot_id);
+        CREATE INDEX idx_findings_identity ON findings(identity_key);
+        CREATE INDEX idx_dependency_edges_snapshot ON dependency_edges(snapshot_id);
+        CREATE INDEX idx_signal_file_name ON signal_history(file_path, signal_name, snapshot_id);
+        CREATE INDEX idx_module_signal_history
+            ON module_signal_history(module_path, signal_name, snapshot_id);
+        CREATE INDEX idx_finding_type ON finding_lifecycle(finding_type, current_status);
+        CREATE INDEX idx_cochange_snapshot ON cochange_edges(snapshot_id);
+        CREATE INDEX idx_delta_h_snapshot ON delta_h(snapshot_id);
+        CREATE INDEX idx_violations_snapshot ON architecture_violations(snapshot_id);
+        """,
+    ),
+    (
+        2,
+        """
+        CREATE TABLE signal_rollups (
+            entity_kind TEXT COLLATE "C" NOT NULL,
+            entity      TEXT COLLATE "C" NOT NULL,
+            signal_name TEXT COLLATE "C" NOT NULL,
+            granularity TEXT COLLATE "C" NOT NULL,
+            period      TEXT COLLATE "C" NOT NULL,
+            samples     INTEGER NOT NULL,
+            value_min   DOUBLE PRECISION NOT NULL,
+            value_max   DOUBLE PRECISION NOT NULL,
+            value_sum   DOUBLE PRECISION NOT NULL,
+            PRIMARY KEY (entity_kind, entity, signal_name, granularity, period)
+        );
+        """,
+    ),
+    (
+        3,
+        """
+        CREATE TABLE snapshot_grammars (
+            snapshot_id BIGINT NOT NULL REFERENCES snapshots(id) ON DELETE CASCADE,
+            language    TEXT COLLATE "C" NOT NULL,
+            version     TEXT COLLATE "C" NOT NULL,
+            PRIMARY KEY (snapshot_id, language)
+        );
+        """,
+    ),
+    (
+        4,
+        """
+        CREATE TABLE gate_rollout (
+            rule    TEXT COLLATE "C" PRIMARY KEY,
+            started TEXT NOT NULL,
+            runs    INTEGER NOT NULL
+        );
+        """,
+    ),
+]
+
+SCHEMA_VERSION = _MIGRATIONS[-1][0]
+
+_SCHEMA_NAME_RE = re.compile(r"^[A-Za-z_][A-Za-z0-9_]{0,62}$")
+
+# String literal, ``?`` placeholder or ``%`` (which psycopg would read as one)
+_TOKEN_RE = re.compile(r"'(?:[^']|'')*'|\?|%")
+_GROUP_CONCAT_RE = re.compile(r"GROUP_CONCAT\(([^()]*)\)")
+_SORT_RE = re.compile(r"\b(ASC|DESC)\b(?!\s+NULLS)")
+_NULLS = {"ASC": " NULLS FIRST", "DESC": " NULLS LAST"}  # SQLite sorts NULL lowest
+_INSERT_RE = re.compile(r"^\s*INSERT\s+INTO\s+(\w+)", re.IGNORECASE)
+
+
+def _psycopg() -> Any:
+    try:
+        import psycopg
+
+        return psycopg
+    except ImportError:
+        raise ImportError(
+            "psycopg is required for the Postgres history store. "
+            "Install with: pip install shannon-codebase-insight[postgres]"
+        )
+
+
+@lru_cache(maxsize=512)
+def translate(sql: str) -> str:
+    """Rewrite a statement from the SQLite dialect used by the persistence layer."""
+    sql = _GROUP_CONCAT_RE.sub(r"string_agg(CAST(\1 AS TEXT), ',')", sql)
+    sql = _SORT_RE.sub(lambda m: m.group(1) + _NULLS[m.group(1)], sql)
+
+    def token(m: re.Match) -> str:
+        text = m.group(0)
+        if text == "?":
+            return "%s"
+        return text.replace("%", "%%")
+
+    return _TOKEN_RE.sub(token, sql)
+
+
+def split_url(url: str, project_root: str) -> tuple[str, str]:
+    """Return (connection URL, schema) for *project_root*'s history.
+
+    The schema comes from the ``schema`` query parameter, which is removed
+    before the URL reaches libpq, or defaults to ``shannon_<dir name>``.
+    """
+    parts = urlsplit(url)
+    query = parse_qsl(parts.query, keep_blank_values=True)
+    schema = next((v for k, v in query if k == "schema"), "")
+    if not schema:
+        slug = re.sub(r"[^a-z0-9_]", "_", Path(project_root).resolve().name.lower())
+        schema = f"shannon_{slug}"[:63]
+    if not _SCHEMA_NAME_RE.match(schema):
+        raise ValueError(f"Invalid history schema name {schema!r}")
+    rest = urlencode([(k, v) for k, v in query if k != "schema"])
+    return urlunsplit(parts._replace(query=rest)), schema
+
+
+class Row:
+    """sqlite3.Row look-alike: index or column-name access, iterates values."""
+
+    __slots__ = ("_keys", "_values")
+
+    def __init__(self, keys: Sequence[str], values: Sequence[Any]) -> None:
+        self._keys = keys
+        # AVG and SUM of integers come back as Decimal; SQLite gives floats
+        self._values = tuple(float(v) if isinstance(v, Decimal) else v for v in values)
+
+    def keys(self) -> list[str]:
+        return list(self._keys)
+
+    def __getitem__(self, key: Union[int, str]) -> Any:
+        if isinstance(key, str):
+            try:
+                return self._values[self._keys.index(key)]
+            except ValueError:
+                raise IndexError(f"No item with that key: {key}") from None
+        return self._values[key]
+
+    def __iter__(self) -> Iterator[Any]:
+        return iter(self._values)
+
+    def __len__(self) -> int:
+        return len(self._values)
+
+    def __eq__(self, other: object) -> bool:
+        if isinstance(other, Row):
+            return self._keys == other._keys and self._values == other._values
+        return NotImplemented
+
+    def __hash__(self) -> int:
+        return hash((tuple(self._keys), self._values))
+
+    def __repr__(self) -> str:
+        return f"Row({dict(zip(self._keys, self._values))!r})"
+
+
+class PostgresCursor:
+    """DB-API cursor translating SQLite-dialect statements."""
+
+    def __init__(self, raw: Any) -> None:
+        self._raw = raw
+        self.lastrowid: Optional[int] = None
+
+    @property
+    def rowcount(self) -> int:
+        return int(self._raw.rowcount)
+
+    @property
+    def description(self) -> Any:
+        return self._raw.description
+
+    def execute(self, sql: str, params: Sequence[Any] = ()) -> PostgresCursor:
+        if sql.strip().upper() == "BEGIN":
+            return self  # psycopg opens a transaction on the first statement
+        self.lastrowid = None
+        match = _INSERT_RE.match(sql)
+        returning = (
+            match is not None
+            and match.group(1).lower() in _SERIAL_TABLES
+            and "RETURNING" not in sql.upper()
+        )
+        if returning:
+            sql = sql.rstrip().rstrip(";") + " RETURNING id"
+        self._raw.execute(translate(sql), tuple(params))
+        if returning:
+            self.lastrowid = int(self._raw.fetchone()[0])
+        return self
+
+    def executemany(self, sql: str, seq_of_params: Sequence[Sequence[Any]]) -> PostgresCursor:
+        self.lastrowid = None
+        self._raw.executemany(translate(sql), [tuple(p) for p in seq_of_params])
+        return self
+
+    def _row(self, values: Sequence[Any]) -> Row:
+        return Row([col.name for col in self._raw.description], values)
+
+    def fetchone(self) -> Optional[Row]:
+        if self._raw.description is None:
+            return None
+        values = self._raw.fetchone()
+        return None if values is None else self._row(values)
+
+    def fetchall(self) -> list[Row]:
+        if self._raw.description is None:
+            return []
+        return [self._row(values) for values in self._raw.fetchall()]
+
+    def __iter__(self) -> Iterator[Row]:
+        return iter(self.fetchall())
+
+    def close(self) -> None:
+        self._raw.close()
+
+
+class PostgresConnection:
+    """sqlite3.Connection look-alike over a psycopg connection."""
+
+    def __init__(self, raw: Any, schema: str) -> None:
+        self._raw = raw
+        self.schema = schema
+
+    def cursor(self) -> PostgresCursor:
+        return PostgresCursor(self._raw.cursor())
+
+    def execute(self, sql: str, params: Sequence[Any] = ()) -> PostgresCursor:
+        return self.cursor().execute(sql, params)
+
+    def executemany(self, sql: str, seq_of_params: Sequence[Sequence[Any]]) -> PostgresCursor:
+        return self.cursor().executemany(sql, seq_of_params)
+
+    def commit(self) -> None:
+        self._raw.commit()
+
+    def rollback(self) -> None:
+        self._raw.rollback()
+
+    def close(self) -> None:
+        self._raw.close()
+
+
+def migrate(raw: Any, schema: str) -> None:
+    """Create *schema* and bring its tables up to SCHEMA_VERSION."""
+    with raw.transaction():
+        raw.execute("SELECT pg_advisory_xact_lock(%s)", (_MIGRATION_LOCK,))
+        raw.execute(f'CREATE SCHEMA IF NOT EXISTS "{schema}"')
+        raw.execute(f'SET LOCAL search_path TO "{schema}"')
+        raw.execute("CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)")
+        row = raw.execute("SELECT version FROM schema_version").fetchone()
+        current = row[0] if row else 0
+        for version, ddl in _MIGRATIONS:
+            if version > current:
+                logger.info("Migrating history schema %s to v%d", schema, version)
+                raw.execute(ddl)
+        if row is None:
+            raw.execute("INSERT INTO schema_version (version) VALUES (%s)", (SCHEMA_VERSION,))
+        elif current < SCHEMA_VERSION:
+            raw.execute("UPDATE schema_version SET version = %s", (SCHEMA_VERSION,))
+
+
+def connect(url: str, project_root: str) -> PostgresConnection:
+    """Open *project_root*'s history in the Postgres database at *url*."""
+    conninfo, schema = split_url(url, project_root)
+    raw = _psycopg().connect(conninfo, autocommit=True)
+    try:
+        migrate(raw, schema)
+        raw.execute(f'SET search_path TO "{schema}"')
+    except Exception:
+        raw.close()
+        raise
+    raw.autocommit = False
+    logger.debug("History store connected to Postgres schema %s", schema)
+    return PostgresConnection(raw, schema)
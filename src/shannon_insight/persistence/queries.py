@@ -7,6 +7,7 @@ V2 adds:
 - get_signal_time_series(): query signal_history for a file/signal pair
 - get_finding_history(): query finding_lifecycle for a finding's history
 - get_chronic_findings(): query findings persisting 3+ snapshots
+- get_resolved_findings(): query fixed findings of one type (for triage)
 - update_finding_lifecycle(): update lifecycle state after a new snapshot
 """
 
@@ -35,6 +36,18 @@ class HealthPoint:
     metrics: dict[str, float]
 
 
+@dataclass
+class RollupPoint:
+    """Aggregate of a signal over the pruned snapshots of one period."""
+
+    granularity: str  # "day", "week" or "month"
+    period: str  # e.g. "2025-01-06", "2025-W02", "2025-01"
+    samples: int
+    minimum: float
+    maximum: float
+    mean: float
+
+
 class HistoryQuery:
     """Read-only queries against the history database.
 
@@ -111,12 +124,12 @@ class HistoryQuery:
             snap_ids,
         ).fetchall()
 
-        # 3. Count findings per snapshot.
+        # 3. Count findings per snapshot (comment debt has its own signal).
         finding_rows = self.conn.execute(
             f"""
             SELECT snapshot_id, COUNT(*) AS cnt
             FROM findings
-            WHERE snapshot_id IN ({placeholders})
+            WHERE snapshot_id IN ({placeholders}) AND finding_type != 'comment_debt'
             GROUP BY snapshot_id
             """,
             snap_ids,
@@ -159,16 +172,22 @@ class HistoryQuery:
         ``finding_type``, ``title``, ``files``, ``severity``, ``count``.
         Ordered by descending persistence count.
         """
+        # Title, files and severity come from the latest occurrence
         rows = self.conn.execute(
             """
-            SELECT f.identity_key, f.finding_type, f.title, f.files,
-                   f.severity,
-                   GROUP_CONCAT(f.snapshot_id) AS snap_ids,
-                   COUNT(DISTINCT f.snapshot_id) AS appearance_count
-            FROM findings f
-            GROUP BY f.identity_key, f.finding_type
-            HAVING COUNT(DISTINCT f.snapshot_id) >= ?
-            ORDER BY appearance_count DESC
+            SELECT g.identity_key, g.finding_type, f.title, f.files,
+                   f.severity, g.snap_ids, g.appearance_count
+            FROM (
+                SELECT identity_key, finding_type,
+                       MAX(id) AS latest_id,
+                       GROUP_CONCAT(snapshot_id) AS snap_ids,
+                       COUNT(DISTINCT snapshot_id) AS appearance_count
+                FROM findings
+                GROUP BY identity_key, finding_type
+                HAVING COUNT(DISTINCT snapshot_id) >= ?
+            ) g
+            JOIN findings f ON f.id = g.latest_id
+            ORDER BY g.appearance_count DESC, g.identity_key ASC
             """,
             (min_snapshots,),
         ).fetchall()
@@ -259,6 +278,37 @@ class HistoryQuery:
         movers.sort(key=lambda x: x["abs_delta"], reverse=True)
         return movers[:10]
 
+    # ── rollups of pruned snapshots ───────────────────────────────────
+
+    def signal_rollups(self, entity_kind: str, entity: str, signal: str) -> list[RollupPoint]:
+        """Aggregates of *signal* kept for snapshots removed by retention.
+
+        *entity_kind* is ``"file"``, ``"module"`` or ``"codebase"`` (whose
+        *entity* is ``""``). Returns points oldest first: months, then
+        weeks, then days.
+        """
+        rows = self.conn.execute(
+            """
+            SELECT granularity, period, samples, value_min, value_max, value_sum
+            FROM signal_rollups
+            WHERE entity_kind = ? AND entity = ? AND signal_name = ?
+            ORDER BY CASE granularity WHEN 'month' THEN 0 WHEN 'week' THEN 1 ELSE 2 END,
+                     period ASC
+            """,
+            (entity_kind, entity, signal),
+        ).fetchall()
+        return [
+            RollupPoint(
+                granularity=r["granularity"],
+                period=r["period"],
+                samples=r["samples"],
+                minimum=r["value_min"],
+                maximum=r["value_max"],
+                mean=r["value_sum"] / r["samples"],
+            )
+            for r in rows
+        ]
+
 
 # ── Utility ──────────────────────────────────────────────────────────────
 
@@ -529,6 +579,7 @@ def get_chronic_findings(
     """
     # Join finding_lifecycle with findings to get files and title
     # Use last_seen_snapshot to get the most recent occurrence
+    # Comment debt is expected to persist; its age is tracked separately
     rows = conn.execute(
         """
         SELECT fl.identity_key, fl.finding_type, fl.first_seen_snapshot,
@@ -538,6 +589,7 @@ def get_chronic_findings(
         JOIN findings f ON f.identity_key = fl.identity_key
                        AND f.snapshot_id = fl.last_seen_snapshot
         WHERE fl.persistence_count >= ? AND fl.current_status = 'active'
+          AND fl.finding_type != 'comment_debt'
         ORDER BY fl.persistence_count DESC, fl.severity DESC
         LIMIT ?
         """,
@@ -569,6 +621,61 @@ def get_chronic_findings(
     return result
 
 
+def get_resolved_findings(
+    conn: sqlite3.Connection,
+    finding_type: str,
+    max_findings: int = 50,
+) -> list[ChronicFindingInfo]:
+    """Return findings of *finding_type* that were fixed, most recently seen first.
+
+    Joins with findings table for the files and title of the last snapshot
+    each finding appeared in.
+
+    Parameters
+    ----------
+    conn:
+        Open database connection.
+    finding_type:
+        The finding type (e.g., "god_class").
+    max_findings:
+        Maximum number of findings to return (default 50).
+
+    Returns
+    -------
+    list[ChronicFindingInfo]
+        Resolved findings, including file paths and title.
+    """
+    rows = conn.execute(
+        """
+        SELECT fl.identity_key, fl.finding_type, fl.first_seen_snapshot,
+               fl.last_seen_snapshot, fl.persistence_count, fl.current_status,
+               fl.severity, f.files, f.title
+        FROM finding_lifecycle fl
+        JOIN findings f ON f.identity_key = fl.identity_key
+                       AND f.snapshot_id = fl.last_seen_snapshot
+        WHERE fl.finding_type = ? AND fl.current_status = 'resolved'
+        ORDER BY fl.last_seen_snapshot DESC, fl.severity DESC
+        LIMIT ?
+        """,
+        (finding_type, max_findings),
+    ).fetchall()
+
+    return [
+        ChronicFindingInfo(
+            identity_key=r["identity_key"],
+            finding_type=r["finding_type"],
+            first_seen_snapshot=r["first_seen_snapshot"],
+            last_seen_snapshot=r["last_seen_snapshot"],
+            persistence_count=r["persistence_count"],
+            current_status=r["current_status"],
+            severity=r["severity"],
+            files=json.loads(r["files"]) if isinstance(r["files"], str) else list(r["files"] or []),
+            title=r["title"],
+        )
+        for r in rows
+    ]
+
+
 def update_finding_lifecycle(
     conn: sqlite3.Connection,
     identity_key: str,
@@ -344,6 +344,15 @@ def _hydrate_tensor(conn: sqlite3.Connection, row: sqlite3.Row) -> TensorSnapsho
     if ms_row:
         modularity_score = ms_row["score"]
 
+    # Parser and grammar version per language
+    grammar_versions = {
+        g_row["language"]: g_row["version"]
+        for g_row in conn.execute(
+            "SELECT language, version FROM snapshot_grammars WHERE snapshot_id = ?",
+            (snapshot_id,),
+        )
+    }
+
     return TensorSnapshot(
         schema_version=row["schema_version"],
         tool_version=row["tool_version"],
@@ -355,6 +364,7 @@ def _hydrate_tensor(conn: sqlite3.Connection, row: sqlite3.Row) -> TensorSnapsho
         commits_analyzed=row["commits_analyzed"],
         analyzers_ran=json.loads(row["analyzers_ran"]),
         config_hash=row["config_hash"],
+        grammar_versions=grammar_versions,
         file_signals=file_signals,
         module_signals=module_signals,
         global_signals=global_signals,
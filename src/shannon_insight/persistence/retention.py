@@ -0,0 +1,231 @@
+"""Retention and compaction of the history store.
+
+Snapshots are thinned by age, newest tier first:
+
+- younger than ``keep_all_days``: every snapshot is kept
+- younger than ``keep_daily_days``: the last snapshot of each day
+- younger than ``keep_weekly_days``: the last snapshot of each ISO week
+- older: none
+
+The baseline, the newest snapshot and any snapshot that was a baseline
+within the weekly window are always kept. Before a snapshot is deleted
+its file, module and codebase signals are folded into ``signal_rollups``:
+one row per entity, signal and period (the day, week or, past the weekly
+window, month the snapshot was taken in) holding the sample count, min,
+max and sum. Long-range trends stay queryable
+(``HistoryQuery.signal_rollups``) at a fraction of the size.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from datetime import datetime, timedelta, timezone
+from typing import Any, Optional
+
+from ..logging_config import get_logger
+
+logger = get_logger(__name__)
+
+# Keeps IN (...) lists under SQLite's bound-parameter limit
+_BATCH = 500
+
+# (table, entity kind, entity column); codebase signals have no entity
+_SIGNAL_SOURCES = (
+    ("file_signals", "file", "file_path"),
+    ("module_signal_history", "module", "module_path"),
+    ("codebase_signals", "codebase", "''"),
+)
+
+_UPSERT_ROLLUP = """
+INSERT INTO signal_rollups (
+    entity_kind, entity, signal_name, granularity, period,
+    samples, value_min, value_max, value_sum
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (entity_kind, entity, signal_name, granularity, period) DO UPDATE SET
+    samples = signal_rollups.samples + excluded.samples,
+    value_min = CASE WHEN excluded.value_min < signal_rollups.value_min
+                THEN excluded.value_min ELSE signal_rollups.value_min END,
+    value_max = CASE WHEN excluded.value_max > signal_rollups.value_max
+                THEN excluded.value_max ELSE signal_rollups.value_max END,
+    value_sum = signal_rollups.value_sum + excluded.value_sum
+"""
+
+
+@dataclass(frozen=True)
+class RetentionPolicy:
+    """How long snapshots are kept at each granularity, in days."""
+
+    keep_all_days: int = 1
+    keep_daily_days: int = 90
+    keep_weekly_days: int = 730
+
+    @classmethod
+    def from_config(cls, config: Any) -> RetentionPolicy:
+        return cls(
+            keep_all_days=config.history_keep_all_days,
+            keep_daily_days=config.history_keep_daily_days,
+            keep_weekly_days=config.history_keep_weekly_days,
+        )
+
+    def bucket(self, taken: datetime, now: datetime) -> tuple[str, str]:
+        """The (granularity, period) a snapshot taken at *taken* falls in."""
+        age = now - taken
+        if age < timedelta(days=self.keep_all_days):
+            return "all", ""
+        if age < timedelta(days=self.keep_daily_days):
+            return "day", taken.strftime("%Y-%m-%d")
+        if age < timedelta(days=self.keep_weekly_days):
+            year, week, _ = taken.isocalendar()
+            return "week", f"{year}-W{week:02d}"
+        return "month", taken.strftime("%Y-%m")
+
+
+@dataclass
+class PruneResult:
+    """Outcome of one ``prune_history`` run."""
+
+    kept: int
+    removed: list[int] = field(default_factory=list)
+    rollup_rows: int = 0
+    dry_run: bool = False
+
+    def to_dict(self) -> dict:
+        return {
+            "kept": self.kept,
+            "removed": len(self.removed),
+            "removed_ids": self.removed,
+            "rollup_rows": self.rollup_rows,
+            "dry_run": self.dry_run,
+        }
+
+
+def _parse_timestamp(value: str) -> Optional[datetime]:
+    try:
+        taken = datetime.fromisoformat(value.replace("Z", "+00:00"))
+    except (TypeError, ValueError):
+        return None
+    return taken if taken.tzinfo else taken.replace(tzinfo=timezone.utc)
+
+
+def plan(
+    snapshots: list[tuple[int, str]],
+    policy: RetentionPolicy,
+    now: datetime,
+    protected: frozenset[int] = frozenset(),
+) -> dict[int, tuple[str, str]]:
+    """Pick the snapshots to remove from *snapshots* ``(id, timestamp)``.
+
+    Returns the removed ids mapped to the rollup period they fold into.
+    Snapshots with unreadable timestamps are kept.
+    """
+    buckets: dict[int, tuple[str, str]] = {}
+    latest: dict[tuple[str, str], tuple[datetime, int]] = {}
+    newest: Optional[tuple[datetime, int]] = None
+    for snapshot_id, timestamp in snapshots:
+        taken = _parse_timestamp(timestamp)
+        if taken is None:
+            logger.warning("Keeping snapshot %d: unreadable timestamp %r", snapshot_id, timestamp)
+            continue
+        if newest is None or (taken, snapshot_id) > newest:
+            newest = (taken, snapshot_id)
+        key = policy.bucket(taken, now)
+        if key[0] == "all":
+            continue
+        buckets[snapshot_id] = key
+        if key[0] != "month" and (key not in latest or (taken, snapshot_id) > latest[key]):
+            latest[key] = (taken, snapshot_id)
+
+    keep = set(protected) | {snapshot_id for _, snapshot_id in latest.values()}
+    if newest is not None:
+        keep.add(newest[1])
+    return {sid: key for sid, key in sorted(buckets.items()) if sid not in keep}
+
+
+def _protected(conn: Any, policy: RetentionPolicy, now: datetime) -> frozenset[int]:
+    """The baseline, plus snapshots set as baseline within the weekly window."""
+    ids = set()
+    row = conn.execute("SELECT snapshot_id FROM baseline WHERE id = 1").fetchone()
+    if row is not None:
+        ids.add(row["snapshot_id"])
+    horizon = now - timedelta(days=policy.keep_weekly_days)
+    for r in conn.execute("SELECT snapshot_id, set_at FROM baseline_history").fetchall():
+        set_at = _parse_timestamp(r["set_at"])
+        if set_at is None or set_at >= horizon:
+            ids.add(r["snapshot_id"])
+    return frozenset(ids)
+
+
+def _rollup(conn: Any, removed: dict[int, tuple[str, str]]) -> int:
+    """Fold the signals of *removed* snapshots into ``signal_rollups``."""
+    written = 0
+    ids = list(removed)
+    for start in range(0, len(ids), _BATCH):
+        batch = ids[start : start + _BATCH]
+        marks = ",".join("?" * len(batch))
+        totals: dict[tuple[str, str, str, str, str], list[float]] = {}
+        for table, kind, column in _SIGNAL_SOURCES:
+            rows = conn.execute(
+                f"SELECT snapshot_id, {column} AS entity, signal_name, value FROM {table} "
+                f"WHERE snapshot_id IN ({marks}) AND value IS NOT NULL",
+                batch,
+            ).fetchall()
+            for r in rows:
+                granularity, period = removed[r["snapshot_id"]]
+                key = (kind, r["entity"], r["signal_name"], granularity, period)
+                value = float(r["value"])
+                acc = totals.get(key)
+                if acc is None:
+                    totals[key] = [1, value, value, value]
+                else:
+                    acc[0] += 1
+                    acc[1] = min(acc[1], value)
+                    acc[2] = max(acc[2], value)
+                    acc[3] += value
+        conn.executemany(_UPSERT_ROLLUP, [key + tuple(acc) for key, acc in totals.items()])
+        written += len(totals)
+    return written
+
+
+def prune_history(
+    db: Any,
+    policy: RetentionPolicy,
+    now: Optional[datetime] = None,
+    dry_run: bool = False,
+    vacuum: bool = False,
+) -> PruneResult:
+    """Apply *policy* to the history in the connected ``HistoryDB`` *db*.
+
+    Rollups and deletions happen in one transaction. With *vacuum*, a
+    SQLite store is compacted afterwards to give the space back to the
+    filesystem (Postgres reclaims it through autovacuum).
+    """
+    conn = db.conn
+    now = now or datetime.now(timezone.utc)
+    rows = conn.execute("SELECT id, timestamp FROM snapshots").fetchall()
+    snapshots = [(r["id"], r["timestamp"]) for r in rows]
+    removed = plan(snapshots, policy, now, _protected(conn, policy, now))
+    result = PruneResult(kept=len(snapshots) - len(removed), removed=list(removed), dry_run=dry_run)
+    if dry_run or not removed:
+        return result
+
+    try:
+        result.rollup_rows = _rollup(conn, removed)
+        ids = list(removed)
+        for start in range(0, len(ids), _BATCH):
+            batch = ids[start : start + _BATCH]
+            marks = ",".join("?" * len(batch))
+            conn.execute(f"DELETE FROM baseline_history WHERE snapshot_id IN ({marks})", batch)
+            conn.execute(f"DELETE FROM snapshots WHERE id IN ({marks})", batch)
+        conn.commit()
+    except Exception:
+        conn.rollback()
+        raise
+    logger.info(
+        "Pruned %d snapshots (%d kept, %d rollup rows)",
+        len(removed),
+        result.kept,
+        result.rollup_rows,
+    )
+    if vacuum and not db.is_postgres:
+        conn.execute("VACUUM")
+    return result
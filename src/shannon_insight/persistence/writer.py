@@ -224,9 +224,9 @@ def save_tensor_snapshot(conn: sqlite3.Connection, snapshot: TensorSnapshot) ->
             )
         if signal_history_rows:
             cur.executemany(
-                "INSERT OR IGNORE INTO signal_history "
+                "INSERT INTO signal_history "
                 "(snapshot_id, file_path, signal_name, value, percentile) "
-                "VALUES (?, ?, ?, ?, ?)",
+                "VALUES (?, ?, ?, ?, ?) ON CONFLICT DO NOTHING",
                 signal_history_rows,
             )
 
@@ -238,9 +238,9 @@ def save_tensor_snapshot(conn: sqlite3.Connection, snapshot: TensorSnapshot) ->
                     module_rows.append((snapshot_id, module_path, signal_name, float(value)))
         if module_rows:
             cur.executemany(
-                "INSERT OR IGNORE INTO module_signal_history "
+                "INSERT INTO module_signal_history "
                 "(snapshot_id, module_path, signal_name, value) "
-                "VALUES (?, ?, ?, ?)",
+                "VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING",
                 module_rows,
             )
 
@@ -258,8 +258,8 @@ def save_tensor_snapshot(conn: sqlite3.Connection, snapshot: TensorSnapshot) ->
             )
         if global_rows_v2:
             cur.executemany(
-                "INSERT OR IGNORE INTO global_signal_history "
-                "(snapshot_id, signal_name, value) VALUES (?, ?, ?)",
+                "INSERT INTO global_signal_history "
+                "(snapshot_id, signal_name, value) VALUES (?, ?, ?) ON CONFLICT DO NOTHING",
                 global_rows_v2,
             )
 
@@ -423,6 +423,13 @@ def save_tensor_snapshot(conn: sqlite3.Connection, snapshot: TensorSnapshot) ->
                 (snapshot_id, snapshot.modularity_score),
             )
 
+        # ── snapshot_grammars ──────────────────────────────────
+        if snapshot.grammar_versions:
+            cur.executemany(
+                "INSERT INTO snapshot_grammars (snapshot_id, language, version) VALUES (?, ?, ?)",
+                [(snapshot_id, lang, ver) for lang, ver in snapshot.grammar_versions.items()],
+            )
+
         conn.commit()
         return snapshot_id
 
@@ -0,0 +1,111 @@
+"""Platform-independent paths, content digests and case folding.
+
+Cache keys and finding fingerprints must come out identical on macOS, Linux
+and Windows, or a cache filled on a laptop never hits in CI:
+
+    paths    forward slashes, relative to the project root, Unicode NFC
+             (macOS file APIs can return decomposed NFD names); case is kept
+             as stored, since Linux file systems are case-sensitive
+    content  CRLF and lone CR line endings become LF, a UTF-8 BOM is dropped
+             (git autocrlf checkouts on Windows hash like everyone else's)
+    casing   Unicode case folding on NFC text, never the process locale
+
+Digests use XXH3-128 when the ``xxhash`` package is importable and SHA-256
+otherwise; both sides of a shared cache need the same one to hit. Content
+without carriage returns or a BOM -- nearly all of it -- is hashed in place,
+and files are streamed through a reused per-thread buffer, so digesting
+allocates nothing per file.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import threading
+import unicodedata
+from pathlib import Path, PurePath
+from typing import Any, Optional, Union
+
+try:
+    import xxhash
+except ImportError:  # Declared dependency; still works (slower) without it
+    xxhash = None
+
+_BOM = b"\xef\xbb\xbf"
+
+# Read size for file_digest; most source files fit in one read
+_CHUNK_SIZE = 1 << 20
+
+_local = threading.local()
+
+
+def portable_path(path: Union[str, PurePath], root: Optional[Union[str, PurePath]] = None) -> str:
+    """*path* as a root-relative, forward-slash, NFC string."""
+    if root is not None:
+        try:
+            path = PurePath(path).relative_to(root)
+        except ValueError:
+            pass
+    text = str(path).replace("\\", "/")
+    while text.startswith("./"):
+        text = text[2:]
+    return unicodedata.normalize("NFC", text)
+
+
+def normalize_newlines(data: bytes) -> bytes:
+    """*data* with LF line endings and no UTF-8 byte order mark."""
+    if data.startswith(_BOM):
+        data = data[len(_BOM) :]
+    return _lf(data)
+
+
+def _lf(data: bytes) -> bytes:
+    return data.replace(b"\r\n", b"\n").replace(b"\r", b"\n")
+
+
+def _new_hash() -> Any:
+    return xxhash.xxh3_128() if xxhash is not None else hashlib.sha256()
+
+
+def content_digest(data: Union[bytes, str]) -> str:
+    """Digest of *data* after line-ending normalization."""
+    if isinstance(data, str):
+        data = data.encode("utf-8")
+    digest = _new_hash()
+    if b"\r" in data or data.startswith(_BOM):
+        data = normalize_newlines(data)
+    digest.update(data)
+    return digest.hexdigest()
+
+
+def _read_buffer() -> bytearray:
+    buffer = getattr(_local, "buffer", None)
+    if buffer is None or len(buffer) != _CHUNK_SIZE:
+        buffer = _local.buffer = bytearray(_CHUNK_SIZE)
+    return buffer
+
+
+def file_digest(path: Path) -> str:
+    """Portable content digest of the file at *path*; equals content_digest()."""
+    buffer = _read_buffer()
+    digest = _new_hash()
+    pending_cr = False  # chunk ended in CR; its LF may start the next one
+    with open(path, "rb") as f, memoryview(buffer) as view:
+        n = f.readinto(buffer)
+        start = len(_BOM) if n >= len(_BOM) and buffer[: len(_BOM)] == _BOM else 0
+        while n:
+            if not pending_cr and buffer.find(b"\r", start, n) < 0:
+                digest.update(view[start:n])
+            else:
+                chunk = (b"\r" if pending_cr else b"") + view[start:n].tobytes()
+                pending_cr = chunk.endswith(b"\r")
+                digest.update(_lf(chunk[:-1] if pending_cr else chunk))
+            n = f.readinto(buffer)
+            start = 0
+    if pending_cr:
+        digest.update(b"\n")
+    return digest.hexdigest()
+
+
+def fold_case(text: str) -> str:
+    """Locale-independent caseless form of *text* for comparisons and keys."""
+    return unicodedata.normalize("NFC", text).casefold()
@@ -0,0 +1,168 @@
+"""Read-only checkouts of remote git repositories.
+
+``shannon-insight https://github.com/org/repo@v2.1`` analyzes a repository
+without cloning it by hand: the target is checked out into a temporary
+directory, analyzed, and the directory is deleted again. Nothing is ever
+pushed, and the remote's own working copies are never touched.
+
+    targets  https://, http://, ssh://, git:// and file:// URLs, or scp-like
+             git@host:org/repo; an optional @ref after the path selects a
+             branch, tag or commit (default: the remote's HEAD)
+    shallow  without a mirror directory only the selected commit is fetched,
+             so there is no git history and temporal analysis is skipped
+    mirror   with ``remote_mirror_dir`` set, a bare mirror per URL is kept
+             there and fetched on every use; the checkout is a local clone
+             of it with full history, which is fast after the first run
+
+URLs and refs are passed to git after ``--end-of-options``, and ones
+starting with ``-`` are refused, so neither can smuggle in a git option.
+git never prompts for credentials here (a private repository fails instead
+of hanging); use an SSH URL or a credential helper for those.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import os
+import re
+import shutil
+import subprocess
+import tempfile
+from contextlib import contextmanager
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Iterator, Optional
+
+from .logging_config import get_logger
+
+logger = get_logger(__name__)
+
+REMOTE_SCHEMES = ("https://", "http://", "ssh://", "git://", "file://")
+
+# user@host:path, the scp-like syntax git accepts for SSH
+_SCP_RE = re.compile(r"^[\w.-]+@[\w.-]+:(?!//)")
+
+# Clones of large repositories over slow links take a while
+_GIT_TIMEOUT_SECONDS = 900
+
+
+@dataclass(frozen=True)
+class RemoteSpec:
+    """A remote repository and the ref to analyze (None = the remote's HEAD)."""
+
+    url: str
+    ref: Optional[str] = None
+
+    @property
+    def name(self) -> str:
+        """Repository name: ``repo`` for https://github.com/org/repo.git."""
+        tail = re.split(r"[/:]", self.url.rstrip("/"))[-1]
+        name = tail[:-4] if tail.endswith(".git") else tail
+        return re.sub(r"[^\w.-]", "_", name) or "repo"
+
+
+def parse_remote(target: str) -> Optional[RemoteSpec]:
+    """Parse ``url[@ref]``; None if *target* is not a remote URL (a local path).
+
+    Raises:
+        ValueError: If the URL ends in ``@`` with no ref, or the URL or ref
+            starts with ``-`` (git would read it as an option).
+    """
+    if target.startswith(REMOTE_SCHEMES):
+        # The host part may hold user@ too; a ref can only follow the path
+        host_start = target.index("://") + 3
+        path_start = target.find("/", host_start)
+        if path_start == -1:
+            path_start = len(target)
+    elif _SCP_RE.match(target):
+        path_start = target.index(":") + 1
+    else:
+        return None
+    path, at, ref = target[path_start:].partition("@")
+    if at and not ref:
+        raise ValueError(f"missing ref after '@' in {target!r}")
+    if target.startswith("-") or ref.startswith("-"):
+        raise ValueError(f"URL or ref starts with '-' in {target!r}")
+    return RemoteSpec(url=target[:path_start] + path, ref=ref or None)
+
+
+@contextmanager
+def checkout(spec: RemoteSpec, mirror_dir: str = "") -> Iterator[Path]:
+    """Check *spec* out into a temporary directory, deleted on exit.
+
+    Args:
+        spec: Repository and ref to check out
+        mirror_dir: Directory of cached bare mirrors ("" = shallow fetch)
+
+    Yields:
+        Path of the working tree (named after the repository)
+
+    Raises:
+        RuntimeError: If git is missing, the fetch fails or the ref is unknown.
+    """
+    tmp_dir = Path(tempfile.mkdtemp(prefix="shannon-remote-"))
+    work_tree = tmp_dir / spec.name
+    try:
+        if mirror_dir:
+            mirror = _update_mirror(spec.url, Path(mirror_dir).expanduser())
+            commit = f"{spec.ref or 'HEAD'}^{{commit}}"
+            try:
+                sha = _git(
+                    ["-C", str(mirror), "rev-parse", "--verify", "--quiet", "--end-of-options"]
+                    + [commit]
+                )
+            except RuntimeError:
+                raise RuntimeError(f"{spec.url} has no ref {(spec.ref or 'HEAD')!r}") from None
+            # A local clone hard-links the mirror's objects instead of copying them
+            _git(["clone", "--quiet", "--no-checkout", str(mirror), str(work_tree)])
+            _git(["-C", str(work_tree), "checkout", "--quiet", "--detach", sha])
+        else:
+            _git(["init", "--quiet", str(work_tree)])
+            ref = spec.ref or "HEAD"
+            _git(
+                ["-C", str(work_tree), "fetch", "--quiet", "--depth", "1", "--end-of-options"]
+                + [spec.url, ref]
+            )
+            _git(["-C", str(work_tree), "checkout", "--quiet", "--detach", "FETCH_HEAD"])
+        logger.info(f"Checked out {spec.url}@{spec.ref or 'HEAD'} into {work_tree}")
+        yield work_tree
+    finally:
+        shutil.rmtree(tmp_dir, ignore_errors=True)
+
+
+def mirror_path(url: str, mirror_dir: Path) -> Path:
+    """Where the bare mirror of *url* lives under *mirror_dir*."""
+    digest = hashlib.sha256(url.encode("utf-8")).hexdigest()[:12]
+    return mirror_dir / f"{RemoteSpec(url).name}-{digest}.git"
+
+
+def _update_mirror(url: str, mirror_dir: Path) -> Path:
+    """Create the mirror of *url*, or fetch into it if it exists."""
+    mirror = mirror_path(url, mirror_dir)
+    if (mirror / "HEAD").exists():
+        _git(["-C", str(mirror), "remote", "update", "--prune"])
+    else:
+        mirror_dir.mkdir(parents=True, exist_ok=True)
+        _git(["clone", "--quiet", "--mirror", "--end-of-options", url, str(mirror)])
+    return mirror
+
+
+def _git(args: list[str]) -> str:
+    """Run git non-interactively; stdout on success."""
+    env = {**os.environ, "GIT_TERMINAL_PROMPT": "0"}
+    try:
+        result = subprocess.run(
+            ["git", *args],
+            capture_output=True,
+            text=True,
+            timeout=_GIT_TIMEOUT_SECONDS,
+            env=env,
+        )
+    except FileNotFoundError:
+        raise RuntimeError("git is not installed") from None
+    except subprocess.TimeoutExpired:
+        raise RuntimeError(f"git timed out after {_GIT_TIMEOUT_SECONDS}s") from None
+    if result.returncode != 0:
+        command = args[2] if args[0] == "-C" else args[0]
+        raise RuntimeError(f"git {command} failed: {result.stderr.strip()}")
+    return result.stdout.strip()
@@ -0,0 +1,296 @@
+"""Review comments: post machine-applicable fixes on pull and merge requests.
+
+``shannon-insight --sink github`` in a GitHub Actions pull_request run,
+or ``--sink gitlab`` in a GitLab merge request pipeline, posts one review
+comment per machine-applicable patch a finding carries
+(``Finding.patches``), with the patch as a suggested change the author can
+accept in one click. Patches on lines the pull request does not touch are
+skipped, as the forges only take comments on the diff, and a comment
+already on the pull request is not posted again, so re-running a pipeline
+does not repeat it. Finding paths are relative to the analyzed root, so
+run the analysis from the repository root.
+
+Environment:
+
+    github   GITHUB_TOKEN, GITHUB_REPOSITORY and GITHUB_EVENT_PATH (set by
+             Actions; the token needs pull-requests: write), GITHUB_API_URL
+             for GitHub Enterprise
+    gitlab   GITLAB_TOKEN (a token with the api scope; CI_JOB_TOKEN cannot
+             comment) and the CI_* merge request variables GitLab sets
+
+A refused comment fails the sink, which is reported with the others (see
+sinks.py).
+"""
+
+from __future__ import annotations
+
+import hashlib
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from collections.abc import Iterable
+from dataclasses import dataclass
+from pathlib import Path
+from typing import TYPE_CHECKING, Any, Callable, Mapping, Optional, Union
+
+from .webhooks import _TIMEOUT_SECONDS, Post, _post
+
+if TYPE_CHECKING:
+    from .insights.models import Finding, Patch
+
+# Sinks posting review comments
+REVIEW_TARGETS = ("github", "gitlab")
+
+# (url, headers) -> the decoded JSON response
+Fetch = Callable[[str, dict], Any]
+
+# Review comments fetched per page when looking for ones already posted
+_PAGE_SIZE = 100
+
+
+@dataclass
+class GitHubReview:
+    """Suggested-change comments on a GitHub pull request."""
+
+    repository: str  # owner/name
+    pull_request: int
+    commit: str  # head commit of the pull request, which the comments are on
+    token: str
+    api_url: str = "https://api.github.com"
+    post: Optional[Post] = None
+    fetch: Optional[Fetch] = None
+
+    def publish(self, findings: list[Finding]) -> str:
+        """Post the new suggested changes; a short description of what was posted."""
+        url = f"{self.api_url}/repos/{self.repository}/pulls/{self.pull_request}/comments"
+        headers = {
+            "Accept": "application/vnd.github+json",
+            "Authorization": f"Bearer {self.token}",
+            "Content-Type": "application/json",
+            "User-Agent": "shannon-insight",
+        }
+        posted = _posted_markers(
+            comment["body"] for comment in _pages(url, headers, self.fetch or _fetch)
+        )
+        comments = []
+        for finding, patch in _new_patches(findings, posted):
+            comment = {
+                "body": review_comment(finding, patch),
+                "commit_id": self.commit,
+                "path": patch.path,
+                "line": patch.end_line,
+                "side": "RIGHT",
+            }
+            if patch.start_line < patch.end_line:
+                comment.update(start_line=patch.start_line, start_side="RIGHT")
+            comments.append(comment)
+        # 422: the line is not part of the pull request's diff
+        sent, skipped = _send(self.post or _post, url, headers, comments, outside_diff=422)
+        return _review_detail(sent, skipped, f"pull request #{self.pull_request}")
+
+
+@dataclass
+class GitLabReview:
+    """Suggested-change discussions on a GitLab merge request."""
+
+    project: str  # numeric ID or full path
+    merge_request: int  # IID
+    base_commit: str  # merge base of the merge request
+    commit: str  # head commit of the merge request
+    token: str
+    api_url: str = "https://gitlab.com/api/v4"
+    post: Optional[Post] = None
+    fetch: Optional[Fetch] = None
+
+    def publish(self, findings: list[Finding]) -> str:
+        """Post the new suggested changes; a short description of what was posted."""
+        project = urllib.parse.quote(self.project, safe="")
+        url = f"{self.api_url}/projects/{project}/merge_requests/{self.merge_request}/discussions"
+        headers = {
+            "Content-Type": "application/json",
+            "PRIVATE-TOKEN": self.token,
+            "User-Agent": "shannon-insight",
+        }
+        discussions = _pages(url, headers, self.fetch or _fetch)
+        posted = _posted_markers(
+            note["body"] for discussion in discussions for note in discussion["notes"]
+        )
+        comments = [
+            {
+                "body": review_comment(finding, patch, gitlab=True),
+                "position": {
+                    "position_type": "text",
+                    "base_sha": self.base_commit,
+                    "start_sha": self.base_commit,
+                    "head_sha": self.commit,
+                    "new_path": patch.path,
+                    "new_line": patch.end_line,
+                },
+            }
+            for finding, patch in _new_patches(findings, posted)
+        ]
+        # 400: GitLab cannot place the comment, the line is not part of the diff
+        sent, skipped = _send(self.post or _post, url, headers, comments, outside_diff=400)
+        return _review_detail(sent, skipped, f"merge request !{self.merge_request}")
+
+
+Review = Union[GitHubReview, GitLabReview]
+
+
+def parse_review(target: str, environ: Mapping[str, str]) -> Review:
+    """The review of a ``github`` or ``gitlab`` sink; ValueError when the CI context is missing."""
+    if target == "github":
+        return _github_review(environ)
+    if target == "gitlab":
+        return _gitlab_review(environ)
+    raise ValueError(f"unknown review target {target!r} (use {' or '.join(REVIEW_TARGETS)})")
+
+
+def review_comment(finding: Finding, patch: Patch, gitlab: bool = False) -> str:
+    """Markdown of a review comment: the finding, its suggestion and the patch.
+
+    The patch is a ``suggestion`` block, which GitHub and GitLab render with
+    a button that commits it. GitLab counts the lines a multi-line
+    suggestion replaces from the commented (last) line up. A hidden marker
+    identifies the patch, so it is not posted twice.
+    """
+    span = patch.end_line - patch.start_line
+    # A fence longer than any backtick run in the code, which would close it
+    fence = "`" * max(3, _longest_backtick_run(patch.replacement) + 1)
+    opening = f"{fence}suggestion:-{span}+0" if gitlab and span else f"{fence}suggestion"
+    lines = [f"**{finding.title}** (`{finding.finding_type}`)", ""]
+    if finding.suggestion:
+        lines += [finding.suggestion, ""]
+    lines += [opening, patch.replacement, fence, f"<!-- {_marker(finding, patch)} -->"]
+    return "\n".join(lines)
+
+
+def _marker(finding: Finding, patch: Patch) -> str:
+    """Identity of a patch, stable while the patched code stays the same."""
+    digest = hashlib.sha1(f"{patch.path}\0{patch.replacement}".encode()).hexdigest()[:12]
+    return f"shannon-insight:{finding.finding_type}:{digest}"
+
+
+def _longest_backtick_run(text: str) -> int:
+    longest = run = 0
+    for c in text:
+        run = run + 1 if c == "`" else 0
+        longest = max(longest, run)
+    return longest
+
+
+def _posted_markers(bodies: Iterable[str]) -> set[str]:
+    """Markers (see _marker) in comments already on the pull request."""
+    markers = set()
+    for body in bodies:
+        for line in (body or "").splitlines():
+            line = line.strip()
+            if line.startswith("<!-- shannon-insight:") and line.endswith(" -->"):
+                markers.add(line[len("<!-- ") : -len(" -->")])
+    return markers
+
+
+def _new_patches(findings: list[Finding], posted: set[str]) -> list[tuple[Finding, Patch]]:
+    """(finding, patch) for every patch without a comment yet, worst finding first."""
+    pairs = []
+    for finding in sorted(findings, key=lambda f: -f.severity):
+        for patch in finding.patches:
+            marker = _marker(finding, patch)
+            if marker not in posted:
+                posted.add(marker)
+                pairs.append((finding, patch))
+    return pairs
+
+
+def _send(
+    post: Post, url: str, headers: dict, comments: list[dict], outside_diff: int
+) -> tuple[int, int]:
+    """(posted, skipped) after posting *comments* one by one.
+
+    A comment the forge refuses with *outside_diff* is skipped; any other
+    refusal stops the review, as the forge will refuse the rest too.
+    """
+    sent = skipped = 0
+    for comment in comments:
+        status = post(url, json.dumps(comment).encode(), headers)
+        if status == outside_diff:
+            skipped += 1
+        elif not 200 <= status < 300:
+            raise RuntimeError(f"HTTP {status}")
+        else:
+            sent += 1
+    return sent, skipped
+
+
+def _review_detail(sent: int, skipped: int, target: str) -> str:
+    detail = f"posted {sent} suggested changes to {target}"
+    if skipped:
+        detail += f" ({skipped} outside the diff skipped)"
+    return detail
+
+
+def _pages(url: str, headers: dict, fetch: Fetch) -> list:
+    """Every item of a paginated GitHub or GitLab list."""
+    items: list = []
+    page = 1
+    while True:
+        batch = fetch(f"{url}?per_page={_PAGE_SIZE}&page={page}", headers)
+        items.extend(batch)
+        if len(batch) < _PAGE_SIZE:
+            return items
+        page += 1
+
+
+def _fetch(url: str, headers: dict) -> Any:
+    request = urllib.request.Request(url, headers=headers)
+    try:
+        with urllib.request.urlopen(request, timeout=_TIMEOUT_SECONDS) as response:
+            return json.loads(response.read())
+    except urllib.error.HTTPError as e:
+        raise RuntimeError(f"HTTP {e.code}") from e
+
+
+def _github_review(environ: Mapping[str, str]) -> GitHubReview:
+    """The pull request of the GitHub Actions run described by *environ*."""
+    missing = [v for v in ("GITHUB_TOKEN", "GITHUB_REPOSITORY") if not environ.get(v)]
+    if missing:
+        raise ValueError(f"GitHub review needs {' and '.join(missing)}")
+    event_path = environ.get("GITHUB_EVENT_PATH", "")
+    try:
+        event = json.loads(Path(event_path).read_text(encoding="utf-8")) if event_path else {}
+    except (OSError, ValueError) as e:
+        raise ValueError(f"GitHub review cannot read GITHUB_EVENT_PATH: {e}") from e
+    pull_request = event.get("pull_request")
+    if not pull_request:
+        raise ValueError("GitHub review runs on pull_request events only")
+    return GitHubReview(
+        repository=environ["GITHUB_REPOSITORY"],
+        pull_request=int(pull_request["number"]),
+        commit=pull_request["head"]["sha"],
+        token=environ["GITHUB_TOKEN"],
+        api_url=environ.get("GITHUB_API_URL") or "https://api.github.com",
+    )
+
+
+def _gitlab_review(environ: Mapping[str, str]) -> GitLabReview:
+    """The merge request of the GitLab CI pipeline described by *environ*."""
+    if not environ.get("GITLAB_TOKEN"):
+        raise ValueError("GitLab review needs GITLAB_TOKEN, a token with the api scope")
+    if not environ.get("CI_MERGE_REQUEST_IID"):
+        raise ValueError("GitLab review runs in merge request pipelines only")
+    missing = [
+        v
+        for v in ("CI_PROJECT_ID", "CI_MERGE_REQUEST_DIFF_BASE_SHA", "CI_COMMIT_SHA")
+        if not environ.get(v)
+    ]
+    if missing:
+        raise ValueError(f"GitLab review needs {', '.join(missing)}")
+    return GitLabReview(
+        project=environ["CI_PROJECT_ID"],
+        merge_request=int(environ["CI_MERGE_REQUEST_IID"]),
+        base_commit=environ["CI_MERGE_REQUEST_DIFF_BASE_SHA"],
+        commit=environ["CI_COMMIT_SHA"],
+        token=environ["GITLAB_TOKEN"],
+        api_url=environ.get("CI_API_V4_URL") or "https://gitlab.com/api/v4",
+    )
@@ -0,0 +1,24 @@
+"""Custom tree-sitter query rules, and the harness that tests them.
+
+Organisations write their own checks as tree-sitter queries in TOML rule
+files, collected in rule packs (directories of rule files). Each rule is
+developed against fixture files annotated with the lines it must match,
+and ``shannon-insight rules test`` runs them red/green.
+
+Usage:
+    from shannon_insight.rules import run_rule_tests
+
+    report = run_rule_tests(["rules/"])
+    assert report.passed
+"""
+
+from .harness import RuleTestReport, run_rule_tests
+from .query_rules import QueryRule, RuleError, load_rule
+
+__all__ = [
+    "QueryRule",
+    "RuleError",
+    "RuleTestReport",
+    "load_rule",
+    "run_rule_tests",
+]
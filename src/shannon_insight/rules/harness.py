@@ -0,0 +1,172 @@
+"""Red/green tests for custom query rules.
+
+Each rule is run on the fixture files next to it: files named after the
+rule file (``no-fmt-println.go`` or ``no-fmt-println.legacy.go`` beside
+``no-fmt-println.toml``) and every file in a directory of that name
+(``no-fmt-println/``). Fixtures say what they expect in comments:
+
+    // ruleid: no-fmt-println
+    fmt.Println("debug")
+    log.Info("started")  // ok: no-fmt-println
+
+An annotation alone on its line applies to the next line that is neither
+blank nor another annotation; after code it applies to its own line.
+Several rule ids may be given, separated by commas. A fixture passes when
+the rule matches exactly its ``ruleid`` lines: a ``ruleid`` line without
+a match is missing (a false negative) and a match on any other line is
+unexpected (a false positive). ``ok`` marks the near misses a rule must
+keep ignoring; it documents them, since every unmarked line must not
+match anyway.
+
+A rule passes when all its fixtures pass, and is untested when it has no
+fixture with a ``ruleid`` annotation for it.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Callable, Optional
+
+from .query_rules import QueryMatcher, QueryRule, RuleError, find_rule_files, load_rule
+
+# (rule, fixture content) -> 1-based lines the rule matches
+Matcher = Callable[[QueryRule, str], list[int]]
+
+_ANNOTATION_RE = re.compile(
+    r"(?:#|//|--|/\*|<!--|;)\s*(?P<kind>ruleid|ok):\s*"
+    r"(?P<ids>[\w.\-]+(?:\s*,\s*[\w.\-]+)*)"
+)
+
+
+@dataclass
+class FixtureResult:
+    path: str
+    expected: list[int]  # lines annotated ruleid
+    matched: list[int]  # lines the rule matched
+
+    @property
+    def missing(self) -> list[int]:
+        return sorted(set(self.expected) - set(self.matched))
+
+    @property
+    def unexpected(self) -> list[int]:
+        return sorted(set(self.matched) - set(self.expected))
+
+    @property
+    def passed(self) -> bool:
+        return not self.missing and not self.unexpected
+
+
+@dataclass
+class RuleResult:
+    path: str  # the rule file
+    rule: Optional[QueryRule] = None
+    fixtures: list[FixtureResult] = field(default_factory=list)
+    error: Optional[str] = None
+
+    @property
+    def status(self) -> str:
+        """One of error, untested, fail and pass (see the module docstring)."""
+        if self.error is not None:
+            return "error"
+        if not any(f.expected for f in self.fixtures):
+            return "untested"
+        return "pass" if all(f.passed for f in self.fixtures) else "fail"
+
+    def to_dict(self) -> dict:
+        return {
+            "path": self.path,
+            "id": self.rule.id if self.rule is not None else None,
+            "status": self.status,
+            "error": self.error,
+            "fixtures": [
+                {
+                    "path": f.path,
+                    "expected": f.expected,
+                    "matched": f.matched,
+                    "missing": f.missing,
+                    "unexpected": f.unexpected,
+                }
+                for f in self.fixtures
+            ],
+        }
+
+
+@dataclass
+class RuleTestReport:
+    results: list[RuleResult]
+
+    def counts(self) -> dict[str, int]:
+        """Rules per status, in pass, fail, error, untested order."""
+        statuses = [r.status for r in self.results]
+        return {s: n for s in ("pass", "fail", "error", "untested") if (n := statuses.count(s))}
+
+    @property
+    def passed(self) -> bool:
+        """No rule failed or errored; untested rules do not fail the run."""
+        return not any(r.status in ("fail", "error") for r in self.results)
+
+    def to_dict(self) -> dict:
+        return {
+            "passed": self.passed,
+            "counts": self.counts(),
+            "rules": [r.to_dict() for r in self.results],
+        }
+
+
+def annotations(content: str, rule_id: str) -> tuple[list[int], list[int]]:
+    """(ruleid lines, ok lines) of *content* for *rule_id*."""
+    expected: list[int] = []
+    ok: list[int] = []
+    pending: list[str] = []  # kinds waiting for the next line of code
+    for n, line in enumerate(content.splitlines(), 1):
+        match = _ANNOTATION_RE.search(line)
+        before = line[: match.start()].strip() if match is not None else line.strip()
+        if before:
+            for kind in pending:
+                (expected if kind == "ruleid" else ok).append(n)
+            pending = []
+        if match is None or rule_id not in re.split(r"\s*,\s*", match["ids"]):
+            continue
+        if before:
+            (expected if match["kind"] == "ruleid" else ok).append(n)
+        else:
+            pending.append(match["kind"])
+    return expected, ok
+
+
+def fixture_files(rule_file: Path) -> list[Path]:
+    """Fixtures of the rule in *rule_file* (see the module docstring)."""
+    stem = rule_file.stem
+    beside = [
+        p
+        for p in rule_file.parent.iterdir()
+        if p.is_file() and p.name.startswith(f"{stem}.") and p.suffix != ".toml"
+    ]
+    directory = rule_file.parent / stem
+    inside = [p for p in directory.rglob("*") if p.is_file()] if directory.is_dir() else []
+    return sorted(beside) + sorted(inside)
+
+
+def run_rule_tests(paths: list[str], matcher: Optional[Matcher] = None) -> RuleTestReport:
+    """Test every rule file under *paths* against its fixtures.
+
+    Raises RuleError when a path does not exist.
+    """
+    match = matcher if matcher is not None else QueryMatcher()
+    results = []
+    for rule_file in find_rule_files(paths):
+        result = RuleResult(str(rule_file))
+        results.append(result)
+        try:
+            result.rule = load_rule(rule_file)
+            for fixture in fixture_files(rule_file):
+                content = fixture.read_text(encoding="utf-8", errors="replace")
+                expected, _ = annotations(content, result.rule.id)
+                matched = match(result.rule, content)
+                result.fixtures.append(FixtureResult(str(fixture), expected, matched))
+        except (RuleError, OSError) as e:
+            result.error = str(e)
+    return RuleTestReport(results)
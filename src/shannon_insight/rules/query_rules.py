@@ -0,0 +1,132 @@
+"""Custom tree-sitter query rules.
+
+A rule is a TOML file holding one tree-sitter query:
+
+    id = "no-fmt-println"
+    language = "go"
+    message = "Log through the structured logger, not fmt.Println"
+    severity = 0.3  # optional, 0-1
+    query = '''
+    (call_expression
+      function: (selector_expression
+        operand: (identifier) @pkg
+        field: (field_identifier) @fn)
+      (#eq? @pkg "fmt")
+      (#eq? @fn "Println")) @match
+    '''
+
+The rule matches at the first line of every node captured as ``@match``,
+or of every captured node when the query has no ``@match`` capture. A
+rule pack is a directory of rule files, searched recursively.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Any, Optional
+
+DEFAULT_SEVERITY = 0.3
+
+# Capture naming the node a rule reports; without one every capture counts
+MATCH_CAPTURE = "match"
+
+_ID_RE = re.compile(r"[A-Za-z0-9][\w.\-]*")
+_MATCH_RE = re.compile(rf"@{MATCH_CAPTURE}\b")
+
+
+class RuleError(ValueError):
+    """A rule file that cannot be read, or a query that cannot be run."""
+
+
+@dataclass(frozen=True)
+class QueryRule:
+    id: str
+    language: str
+    query: str
+    message: str
+    severity: float
+    path: str  # the rule file
+
+
+def load_rule(path: Path) -> QueryRule:
+    """Read and validate one rule file."""
+    try:
+        import tomllib
+    except ModuleNotFoundError:  # Python < 3.11
+        import tomli as tomllib  # noqa: F811
+
+    try:
+        with open(path, "rb") as f:
+            data: dict[str, Any] = tomllib.load(f)
+    except (OSError, tomllib.TOMLDecodeError) as e:
+        raise RuleError(f"{path}: {e}") from e
+
+    for key in ("id", "language", "query"):
+        if not isinstance(data.get(key), str) or not data[key].strip():
+            raise RuleError(f"{path}: '{key}' must be a non-empty string")
+    if not _ID_RE.fullmatch(data["id"]):
+        raise RuleError(f"{path}: id {data['id']!r} may only use letters, digits, _ . and -")
+    severity = data.get("severity", DEFAULT_SEVERITY)
+    if isinstance(severity, bool) or not isinstance(severity, (int, float)):
+        raise RuleError(f"{path}: 'severity' must be a number")
+    if not 0.0 <= severity <= 1.0:
+        raise RuleError(f"{path}: 'severity' must be between 0 and 1")
+    unknown = sorted(set(data) - {"id", "language", "query", "message", "severity"})
+    if unknown:
+        raise RuleError(f"{path}: unknown key(s): {', '.join(unknown)}")
+
+    return QueryRule(
+        id=data["id"],
+        language=data["language"].strip().lower(),
+        query=data["query"],
+        message=str(data.get("message") or data["id"]),
+        severity=float(severity),
+        path=str(path),
+    )
+
+
+def find_rule_files(paths: list[str]) -> list[Path]:
+    """Rule files named by *paths*: files as given, directories searched recursively.
+
+    A trailing ``/...`` (``./rules/...``) is accepted and means the same as
+    the directory.
+    """
+    found: list[Path] = []
+    for raw in paths:
+        path = Path(raw.removesuffix("/...") or ".")
+        if path.is_dir():
+            found.extend(sorted(p for p in path.rglob("*.toml") if p.is_file()))
+        elif path.is_file():
+            found.append(path)
+        else:
+            raise RuleError(f"{raw}: no such file or directory")
+    return list(dict.fromkeys(found))
+
+
+class QueryMatcher:
+    """Runs rules with tree-sitter; one parser for every rule and file."""
+
+    def __init__(self) -> None:
+        from ..scanning.treesitter_parser import TreeSitterParser
+
+        self._parser = TreeSitterParser()
+        self._checked: dict[tuple[str, str], Optional[str]] = {}
+
+    def __call__(self, rule: QueryRule, content: str) -> list[int]:
+        """Lines (1-based) where *rule* matches *content*."""
+        key = (rule.language, rule.query)
+        if key not in self._checked:
+            self._checked[key] = self._parser.query_error(rule.query, rule.language)
+        error = self._checked[key]
+        if error is not None:
+            raise RuleError(f"{rule.path}: {error}")
+
+        tree = self._parser.parse(content.encode("utf-8"), rule.language)
+        captures = self._parser.query(tree, rule.query, rule.language)
+        if _MATCH_RE.search(rule.query):
+            nodes = [node for node, name in captures if name == MATCH_CAPTURE]
+        else:
+            nodes = [node for node, _ in captures]
+        return sorted({node.start_point[0] + 1 for node in nodes})
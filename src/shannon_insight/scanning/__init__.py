@@ -7,6 +7,7 @@ Core components:
 """
 
 from .fallback import RegexFallbackScanner
+from .grammars import CustomGrammar, parse_grammars
 from .languages import (
     DEFAULT_SOURCE_EXTENSIONS,
     LANGUAGES,
@@ -39,4 +40,6 @@ __all__ = [
     "TreeSitterParser",
     "RegexFallbackScanner",
     "SyntaxExtractor",
+    "CustomGrammar",
+    "parse_grammars",
 ]
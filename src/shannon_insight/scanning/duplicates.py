@@ -0,0 +1,85 @@
+"""Content-identical file detection.
+
+Vendored libraries, copied examples and symlinked trees put the same file at
+several paths. Analyzing every copy multiplies each finding by the number of
+copies, so the kernel analyzes one canonical copy per group and reports the
+duplication itself instead.
+
+The canonical copy is the one closest to the root, then the alphabetically
+first: ``lib/util.py`` wins over ``vendor/lib/util.py``.
+"""
+
+from __future__ import annotations
+
+import os
+from collections import defaultdict
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import Optional
+
+from ..portable import content_digest
+
+# Smaller files (empty __init__.py, license headers, one-line re-exports) are
+# identical by convention, not by copying
+MIN_DUPLICATE_LINES = 10
+
+
+@dataclass
+class DuplicateGroup:
+    """Files with identical content (after line-ending normalization)."""
+
+    canonical: str  # the copy that is analyzed
+    copies: list[str]  # other paths with the same content, sorted
+    lines: int
+    digest: str
+    # Copies that are symlinks to the same file as the canonical copy
+    symlinks: list[str] = field(default_factory=list)
+
+    @property
+    def paths(self) -> list[str]:
+        return [self.canonical, *self.copies]
+
+
+def _canonical_order(path: str) -> tuple[int, str]:
+    return path.count("/"), path
+
+
+def find_identical_files(
+    contents: dict[str, str],
+    line_counts: dict[str, int],
+    root: Optional[Path] = None,
+    min_lines: int = MIN_DUPLICATE_LINES,
+) -> list[DuplicateGroup]:
+    """Group files whose content is byte-identical.
+
+    Args:
+        contents: Relative path -> file content
+        line_counts: Relative path -> line count; files below *min_lines*
+            are ignored
+        root: Project root, used to tell symlinks from real copies
+        min_lines: Smallest file worth reporting
+
+    Returns:
+        Groups of two or more files, ordered by canonical path.
+    """
+    by_digest: dict[str, list[str]] = defaultdict(list)
+    for path, content in contents.items():
+        if line_counts.get(path, 0) >= min_lines:
+            by_digest[content_digest(content)].append(path)
+
+    groups = []
+    for digest, paths in by_digest.items():
+        if len(paths) < 2:
+            continue
+        canonical, *copies = sorted(paths, key=_canonical_order)
+        group = DuplicateGroup(
+            canonical=canonical,
+            copies=sorted(copies),
+            lines=line_counts[canonical],
+            digest=digest,
+        )
+        if root is not None:
+            target = os.path.realpath(root / canonical)
+            group.symlinks = [c for c in group.copies if os.path.realpath(root / c) == target]
+        groups.append(group)
+    return sorted(groups, key=lambda g: g.canonical)
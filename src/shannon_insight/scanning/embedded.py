@@ -0,0 +1,115 @@
+"""SQL and HTML embedded in the string literals of other languages.
+
+Repositories keep queries in Go raw strings and Python triple-quoted
+strings, and markup in template literals and heredocs. ``find_snippets``
+reads every string literal of EMBEDDED_MIN_CHARS or more as:
+
+    sql   a statement: SELECT ... FROM, INSERT INTO, UPDATE ... SET,
+          DELETE FROM, WITH ... AS (, MERGE INTO, CREATE/ALTER/DROP of a
+          table, view, index or routine
+    html  markup with at least MIN_TAGS tags, one of them a closing tag
+
+String literals are triple-quoted (Python, Kotlin, Java text blocks),
+backtick (Go raw strings, JavaScript template literals, whose ``${...}``
+placeholders become ``?``), heredocs (``<<~SQL``, ``<<<HTML``; a tag naming
+the language decides it) and one-line quoted strings. A lowercase SQL
+statement must also have SQL punctuation (``=``, ``*``, a placeholder), so
+prose that starts with "select" or "update" is not read as a query.
+Literals in comments are not told apart from code.
+
+Each snippet keeps its line span in the host file, so the sub-analyzer's
+result (see signals/embedded_code.py) can be charged to the function
+around it.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from typing import Optional
+
+EMBEDDED_LANGUAGES = ("sql", "html")
+
+# Shorter strings are column lists and fragments, not snippets worth measuring
+EMBEDDED_MIN_CHARS = 80
+MIN_TAGS = 3
+
+# Languages whose files are data or queries themselves
+_NOT_HOSTS = frozenset({"sql", "hcl", "yaml", "proto", "universal"})
+
+_LITERAL_RE = re.compile(
+    r"(?P<q3>\"\"\"|''')(?P<triple>[\s\S]*?)(?P=q3)"
+    r"|`(?P<backtick>(?:\\.|[^`\\])*)`"
+    r"|<<<?[~-]?(?P<hq>['\"]?)(?P<tag>[A-Z][A-Z_]*)(?P=hq)[^\n]*\n"
+    r"(?P<heredoc>[\s\S]*?)^[ \t]*(?P=tag)\b"
+    r"|\"(?P<double>(?:\\.|[^\"\\\n])*)\""
+    r"|'(?P<single>(?:\\.|[^'\\\n])*)'",
+    re.MULTILINE,
+)
+_PLACEHOLDER_RE = re.compile(r"\$\{[^{}]*\}")
+_SQL_RE = re.compile(
+    r"^\s*\(?\s*(?P<keyword>"
+    r"SELECT\b[\s\S]*?\bFROM\b|INSERT\s+(?:IGNORE\s+)?INTO\b|UPDATE\s+\S+\s+SET\b"
+    r"|DELETE\s+FROM\b|WITH\s+(?:RECURSIVE\s+)?\w+\s+AS\s*\(|MERGE\s+INTO\b"
+    r"|(?:CREATE|ALTER|DROP)\s+(?:OR\s+REPLACE\s+)?(?:TEMP(?:ORARY)?\s+)?(?:UNIQUE\s+)?"
+    r"(?:TABLE|VIEW|INDEX|FUNCTION|PROCEDURE|TRIGGER)\b)",
+    re.IGNORECASE,
+)
+_SQL_PUNCTUATION_RE = re.compile(r"[=*?]|\$\d|%s|(?<!:):\w")
+_HTML_TAG_RE = re.compile(r"<(/?)[A-Za-z][\w.:-]*(?:\s[^<>]*)?/?>")
+
+
+@dataclass(frozen=True)
+class Snippet:
+    """An embedded snippet; lines are those of the host file."""
+
+    language: str  # one of EMBEDDED_LANGUAGES
+    start_line: int
+    end_line: int
+    text: str
+
+    @property
+    def lines(self) -> int:
+        return self.end_line - self.start_line + 1
+
+
+def is_host(language: str) -> bool:
+    return language not in _NOT_HOSTS
+
+
+def find_snippets(content: str, min_chars: int = EMBEDDED_MIN_CHARS) -> list[Snippet]:
+    """The SQL and HTML string literals of *content*, in order."""
+    snippets = []
+    for match in _LITERAL_RE.finditer(content):
+        group = next(
+            g for g in ("triple", "backtick", "heredoc", "double", "single") if match[g] is not None
+        )
+        literal = match[group]
+        if len(literal.strip()) < min_chars:
+            continue
+        text = _PLACEHOLDER_RE.sub("?", literal) if group == "backtick" else literal
+        language = snippet_language(text, match["tag"] or "")
+        if language is None:
+            continue
+        # From the first line of the snippet to its last, blank lines aside
+        lead = len(literal) - len(literal.lstrip())
+        start_line = content.count("\n", 0, match.start(group) + lead) + 1
+        end_line = start_line + literal.strip().count("\n")
+        snippets.append(Snippet(language, start_line, end_line, text))
+    return snippets
+
+
+def snippet_language(text: str, tag: str = "") -> Optional[str]:
+    """sql, html or None; a heredoc *tag* naming the language decides."""
+    for language in EMBEDDED_LANGUAGES:
+        if language.upper() in tag.upper():
+            return language
+    match = _SQL_RE.match(text)
+    if match is not None:
+        keyword = match["keyword"].split()[0]
+        if keyword.isupper() or _SQL_PUNCTUATION_RE.search(text):
+            return "sql"
+    tags = [m[1] for m in _HTML_TAG_RE.finditer(text)]
+    if len(tags) >= MIN_TAGS and "/" in tags:
+        return "html"
+    return None
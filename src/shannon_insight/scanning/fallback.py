@@ -3,7 +3,8 @@
 Used when tree-sitter is unavailable or fails to parse a file.
 Produces FileSyntax with call_targets=None to indicate fallback mode.
 
-Supports: Python, Go, TypeScript, JavaScript, Java, Rust, Ruby, C/C++
+Supports: Python, Go, TypeScript, JavaScript, Java, Kotlin, Scala, Swift, Rust, Ruby, PHP, C/C++
+and SQL (routines as functions, tables and views as classes)
 """
 
 from __future__ import annotations
@@ -11,8 +12,42 @@ from __future__ import annotations
 import re
 from dataclasses import dataclass
 
+from .sql import mask as mask_sql
+from .sql import statement_spans
 from .syntax import ClassDef, FileSyntax, FunctionDef, ImportDecl
 
+# Access specifiers and virtual in a C++ base clause
+_CPP_BASE_SPECIFIERS = ("public", "protected", "private", "virtual")
+
+# Declaration modifiers that may precede a Kotlin fun, class or object
+_KOTLIN_MODIFIERS = (
+    r"public|private|protected|internal|override|open|final|abstract|sealed|data|inner|value"
+    r"|inline|suspend|operator|infix|tailrec|external|expect|actual"
+)
+
+# Modifiers that may precede a PHP method
+_PHP_MODIFIERS = r"public|private|protected|static|abstract|final"
+
+# Modifiers that may precede a Scala def, class, trait or object
+_SCALA_MODIFIERS = (
+    r"(?:private|protected)(?:\[\w*\])?|override|final|abstract|sealed|case|implicit|lazy"
+    r"|inline|transparent|open|infix|opaque|package"
+)
+
+# Modifiers that may precede a Swift func or init, and a Swift type
+_SWIFT_MODIFIERS = (
+    r"(?:public|private|fileprivate|internal|open|package)(?:\(set\))?|final|static|class"
+    r"|override|mutating|nonmutating|convenience|required|dynamic|nonisolated|optional"
+)
+_SWIFT_TYPE_MODIFIERS = r"public|private|fileprivate|internal|open|package|final|indirect"
+_SWIFT_ATTRIBUTES = r"(?:@\w+(?:\([^)]*\))?\s+)*"
+
+# Go type parameters: [T any], [K comparable, V any], [S ~[]E, E cmp.Ordered]
+_GO_TYPE_PARAMS = r"(?:\[(?:[^\[\]\n]|\[[^\[\]\n]*\])*\])"
+
+# A possibly schema-qualified SQL name; the last part is captured
+_SQL_NAME = r"(?:[\w$]+\.|\"[^\"]+\"\.|`[^`]+`\.|\[[^\]]+\]\.)*[\"`\[]?([\w$]+)[\"`\]]?"
+
 
 @dataclass
 class RegexFallbackScanner:
@@ -151,7 +186,12 @@ class RegexFallbackScanner:
         """Get regex patterns for function definitions."""
         patterns: dict[str, list[str]] = {
             "python": [r"^\s*(?:async\s+)?def\s+(\w+)\s*\([^)]*\)"],
-            "go": [r"^func\s+(\w+)\s*\([^)]*\)"],
+            "go": [
+                # Functions and methods; type parameters (func Map[T, U any]) and generic
+                # receivers (func (s *Stack[T]) Push) included, func-typed parameters too
+                r"^func\s+(?:\([^)]*\)\s*)?(\w+)\s*" + _GO_TYPE_PARAMS + r"?\s*"
+                r"\((?:[^()]|\([^()]*\))*\)",
+            ],
             "typescript": [
                 r"^(?:export\s+)?(?:async\s+)?function\s+(\w+)\s*\([^)]*\)",
                 r"^\s*(\w+)\s*\([^)]*\)\s*{",  # method shorthand
@@ -163,10 +203,65 @@ class RegexFallbackScanner:
             "java": [
                 r"(?:public|private|protected)?\s*(?:static)?\s*\w+\s+(\w+)\s*\([^)]*\)\s*(?:throws\s+\w+)?\s*{"
             ],
-            "rust": [r"^(?:pub\s+)?(?:async\s+)?fn\s+(\w+)"],
-            "ruby": [r"^\s*def\s+(\w+)"],
-            "c": [r"^\w+\s+(\w+)\s*\([^)]*\)\s*{"],
-            "cpp": [r"^\w+(?:::\w+)*\s+(\w+)\s*\([^)]*\)\s*(?:const)?\s*{"],
+            "kotlin": [
+                # Top-level and member functions; extension functions (fun <T> List<T>.name)
+                # are matched by their own name, and suspend is just another modifier
+                r"^[ \t]*(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:" + _KOTLIN_MODIFIERS + r")\s+)*"
+                r"fun\s+(?:<[^>]*>\s*)?(?:[\w.]+(?:<[^>]*>)?\??\.)?(\w+)\s*\([^)]*\)",
+            ],
+            "scala": [
+                # Methods and abstract declarations; type parameters, curried parameter
+                # lists and parameterless defs (def size: Int) included
+                r"^[ \t]*(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:" + _SCALA_MODIFIERS + r")\s+)*"
+                r"def\s+(\w+)[ \t]*(?:\[[^\]]*\][ \t]*)?(?:\([^)]*\)[ \t]*)*",
+            ],
+            "swift": [
+                # Functions (generic ones too) and initializers; closure-typed
+                # parameters nest one level of parentheses
+                r"^[ \t]*" + _SWIFT_ATTRIBUTES + r"(?:(?:" + _SWIFT_MODIFIERS + r")\s+)*"
+                r"func\s+(\w+)[ \t]*(?:<[^>\n]*>)?[ \t]*\((?:[^()]|\([^()]*\))*\)",
+                r"^[ \t]*" + _SWIFT_ATTRIBUTES + r"(?:(?:" + _SWIFT_MODIFIERS + r")\s+)*"
+                r"(init)[?!]?[ \t]*(?:<[^>\n]*>)?[ \t]*\((?:[^()]|\([^()]*\))*\)",
+            ],
+            "sql": [
+                # Functions, procedures and triggers; MySQL DEFINER clauses included
+                r"(?i)^[ \t]*CREATE\s+(?:OR\s+(?:REPLACE|ALTER)\s+)?(?:DEFINER\s*=\s*\S+\s+)?"
+                r"(?:FUNCTION|PROCEDURE|PROC|TRIGGER)\s+(?:IF\s+NOT\s+EXISTS\s+)?" + _SQL_NAME
+                + r"(?:\s*\((?:[^()]|\([^()]*\))*\))?",
+            ],
+            "rust": [
+                # Free functions, impl/trait methods; pub(crate), const, async, unsafe, extern "C"
+                r"^[ \t]*(?:pub(?:\([^)]*\))?\s+)?(?:(?:const|async|unsafe|extern\s+\"[^\"]*\")\s+)*"
+                r"fn\s+(\w+)\s*(?:<[^>]*>)?\s*\([^)]*\)",
+                r"^[ \t]*macro_rules!\s*(\w+)",
+            ],
+            # Instance and singleton methods (def self.build), predicates and bang methods
+            "ruby": [r"^[ \t]*(?:(?:private|protected|public)\s+)?def\s+(?:self\.)?(\w+[?!]?)"],
+            # Functions and methods, by-reference returns (function &get) included
+            "php": [
+                r"^[ \t]*(?:(?:" + _PHP_MODIFIERS + r")\s+)*function\s+&?(\w+)\s*\([^)]*\)",
+            ],
+            "c": [
+                # Top-level definitions; pointer returns (char *dup) and the brace on the next line
+                r"^(?:(?:static|inline|extern|const|unsigned|signed|struct|enum)\s+)*"
+                r"(?!(?:else|return)\b)\w+[\s*]+(\w+)\s*\([^)]*\)\s*{",
+            ],
+            "cpp": [
+                # Free functions, out-of-line (Parser::reset) and in-class methods
+                r"^[ \t]*(?:(?:static|inline|virtual|constexpr|explicit|const)\s+)*"
+                r"(?!(?:if|else|while|for|switch|return|do|catch|new|delete)\b)\w[\w:<>,]*[\s*&]+"
+                r"(?:\w+::)*(?!(?:if|while|for|switch|catch)\b)(~?\w+)\s*\([^)]*\)\s*"
+                r"(?:const\s*)?(?:noexcept\s*)?(?:override\s*)?{",
+            ],
+            # name() { and function name { (POSIX and bash forms)
+            "shell": [
+                r"^[ \t]*function\s+([\w:.-]+)(?:\s*\(\s*\))?",
+                r"^[ \t]*(?!function\b)([\w:.-]+)\s*\(\s*\)",
+            ],
+            "perl": [r"^[ \t]*sub\s+(\w+)"],
+            # Build files define rules and stages, not functions
+            "dockerfile": [],
+            "make": [],
         }
         return patterns.get(language, patterns.get("python", []))
 
@@ -174,13 +269,45 @@ class RegexFallbackScanner:
         """Get regex patterns for class definitions."""
         patterns: dict[str, list[str]] = {
             "python": [r"^\s*class\s+(\w+)(?:\s*\([^)]*\))?:"],
-            "go": [r"^type\s+(\w+)\s+struct\s*{"],
+            "go": [r"^type\s+(\w+)\s*" + _GO_TYPE_PARAMS + r"?\s+struct\s*{"],
             "typescript": [r"^(?:export\s+)?class\s+(\w+)"],
             "javascript": [r"^(?:export\s+)?class\s+(\w+)"],
             "java": [r"(?:public\s+)?class\s+(\w+)"],
-            "rust": [r"^(?:pub\s+)?struct\s+(\w+)"],
-            "ruby": [r"^\s*class\s+(\w+)"],
-            "cpp": [r"^class\s+(\w+)"],
+            "kotlin": [
+                r"^[ \t]*(?:(?:" + _KOTLIN_MODIFIERS + r")\s+)*"
+                r"(?:enum\s+class|annotation\s+class|class|interface|object)\s+(\w+)"
+            ],
+            "scala": [
+                r"^[ \t]*(?:@\w+(?:\([^)]*\))?\s+)*(?:(?:" + _SCALA_MODIFIERS + r")\s+)*"
+                r"(?:class|trait|object|enum)\s+(\w+)[^{\n=]*"
+            ],
+            "swift": [
+                # class func and class var are members, not types; extension
+                # Outer.Inner extends the nested type Inner
+                r"^[ \t]*" + _SWIFT_ATTRIBUTES + r"(?:(?:" + _SWIFT_TYPE_MODIFIERS + r")\s+)*"
+                r"(?:class|struct|enum|actor|protocol|extension)\s+"
+                r"(?!(?:func|var|let|override|final|static|subscript)\b)(?:\w+\.)*(\w+)[^{\n]*"
+            ],
+            "sql": [
+                r"(?i)^[ \t]*CREATE\s+(?:OR\s+REPLACE\s+)?(?:(?:GLOBAL|LOCAL)\s+)?"
+                r"(?:(?:TEMP|TEMPORARY|UNLOGGED|MATERIALIZED)\s+)?(?:TABLE|VIEW)\s+"
+                r"(?:IF\s+NOT\s+EXISTS\s+)?" + _SQL_NAME
+            ],
+            "rust": [r"^[ \t]*(?:pub(?:\([^)]*\))?\s+)?(?:unsafe\s+)?(?:struct|enum|trait)\s+(\w+)"],
+            "ruby": [r"^\s*class\s+((?:\w+::)*\w+)"],
+            "php": [
+                r"^[ \t]*(?:(?:abstract|final|readonly)\s+)*"
+                r"(?:class|interface|trait|enum)\s+(\w+)[^{;]*"
+            ],
+            "c": [
+                r"^(?:typedef\s+)?(?:struct|union)\s+(\w+)\s*{",
+                r"^typedef\s+struct\s*{[^}]*}\s*(\w+)\s*;",
+            ],
+            "cpp": [
+                r"^[ \t]*(?:template\s*<[^>]*>\s*)?(?:class|struct|union)\s+(\w+)"
+                r"(?:\s+final)?\s*(?::[^{;]*)?{",
+                r"^typedef\s+struct\s*{[^}]*}\s*(\w+)\s*;",
+            ],
         }
         return patterns.get(language, [])
 
@@ -198,8 +325,18 @@ class RegexFallbackScanner:
                 r"require\(['\"]([^'\"]+)['\"]\)",
             ],
             "java": [r"^import\s+([\w.]+);"],
-            "rust": [r"^use\s+([\w:]+)"],
+            "kotlin": [r"^import\s+([\w.]+)"],
+            "scala": [r"^[ \t]*import\s+([\w.]*\w)"],
+            # The module: import UIKit, @testable import App, import struct Models.User
+            "swift": [
+                r"^[ \t]*(?:@\w+\s+)*import\s+"
+                r"(?:(?:typealias|struct|class|enum|protocol|let|var|func)\s+)?(\w+)"
+            ],
+            "rust": [r"^[ \t]*(?:pub(?:\([^)]*\))?\s+)?use\s+([\w:]+)"],
             "ruby": [r"^require\s+['\"]([^'\"]+)['\"]", r"^require_relative\s+['\"]([^'\"]+)['\"]"],
+            "php": [r"^use\s+(?!(?:function|const)\s)\\?([\w\\]*\w)"],
+            "c": [r'^\s*#\s*include\s*[<"]([^>"]+)[>"]'],
+            "cpp": [r'^\s*#\s*include\s*[<"]([^>"]+)[>"]'],
         }
         return patterns.get(language, [])
 
@@ -221,13 +358,163 @@ class RegexFallbackScanner:
         """Extract parameter names (best effort)."""
         # This is very approximate
         full_match = match.group(0)
-        paren_match = re.search(r"\(([^)]*)\)", full_match)
-        if paren_match:
-            params_str = paren_match.group(1)
-            # Simple split, doesn't handle complex types
-            params = [p.strip().split()[0] for p in params_str.split(",") if p.strip()]
-            return [p.split(":")[0].strip() for p in params if p]
-        return []
+        if language == "rust":
+            return self._extract_rust_params(full_match)
+        if language == "kotlin":
+            return self._extract_kotlin_params(full_match)
+        if language == "scala":
+            return self._extract_scala_params(full_match)
+        if language == "swift":
+            return self._extract_swift_params(full_match)
+        if language == "sql":
+            return self._extract_sql_params(full_match)
+        if language in ("c", "cpp"):
+            return self._extract_c_params(full_match)
+        if language == "php":
+            return re.findall(r"\$(\w+)", full_match)
+        if language == "go":
+            return self._extract_go_params(full_match)
+        open_paren = full_match.find("(")
+        if open_paren < 0:
+            return []
+        # Read on in the source: the pattern stops at the first ")", which may
+        # close a default (top: int = Option("--top")) rather than the list
+        parts = self._split_params(match.string, match.start() + open_paren + 1)
+        params = [p.split()[0] for p in parts if p.strip()]
+        return [p.split(":")[0].strip() for p in params if p]
+
+    @staticmethod
+    def _split_params(content: str, start: int) -> list[str]:
+        """Top-level comma-separated parts from *start* to the closing parenthesis."""
+        parts, depth, part, quote = [], 0, "", ""
+        for i in range(start, len(content)):
+            char = content[i]
+            if quote:
+                if char == quote and content[i - 1] != "\\":
+                    quote = ""
+            elif char in "\"'":
+                quote = char
+            elif char in "([{":
+                depth += 1
+            elif char in ")]}":
+                if depth == 0:
+                    break
+                depth -= 1
+            elif char == "," and depth == 0:
+                parts.append(part)
+                part = ""
+                continue
+            part += char
+        parts.append(part)
+        return parts
+
+    def _extract_rust_params(self, signature: str) -> list[str]:
+        """Parameter names of a Rust fn, without self (as tree-sitter reports them)."""
+        # Skip pub(crate) and generics: parameters follow the name
+        paren_match = re.search(r"\bfn\s+\w+\s*(?:<[^>]*>)?\s*\(([^)]*)\)", signature)
+        if not paren_match:
+            return []
+        params = []
+        for part in paren_match.group(1).split(","):
+            pattern = part.split(":")[0].replace("mut ", "").strip()
+            if pattern and pattern.lstrip("&") != "self":
+                params.append(pattern)
+        return params
+
+    def _extract_go_params(self, signature: str) -> list[str]:
+        """Parameter names of a Go func, after the receiver and type parameters."""
+        head = re.match(r"func\s+(?:\([^)]*\)\s*)?\w+\s*" + _GO_TYPE_PARAMS + r"?\s*\(", signature)
+        if not head:
+            return []
+        parts, depth, part = [], 0, ""
+        for char in signature[head.end() : -1] + ",":
+            depth += {"(": 1, "[": 1, "{": 1, ")": -1, "]": -1, "}": -1}.get(char, 0)
+            if char == "," and depth == 0:
+                parts.append(part.split())
+                part = ""
+            else:
+                part += char
+        # Either every parameter is named (a, b int) or none is (int, func(int) int)
+        named = any(
+            len(words) > 1
+            and re.fullmatch(r"\w+", words[0])
+            and words[0] not in ("chan", "func", "map", "struct", "interface")
+            for words in parts
+        )
+        return [words[0] for words in parts if words] if named else []
+
+    def _extract_c_params(self, signature: str) -> list[str]:
+        """Parameter names of a C/C++ function: the last identifier of each declaration."""
+        paren_match = re.search(r"\(([^)]*)\)", signature)
+        if not paren_match:
+            return []
+        params = []
+        for part in paren_match.group(1).split(","):
+            # Drop defaults and array sizes: int n = 0, char buf[16]
+            names = re.findall(r"\w+", re.sub(r"=.*|\[.*", "", part))
+            if len(names) > 1:
+                params.append(names[-1])
+        return params
+
+    def _extract_kotlin_params(self, signature: str) -> list[str]:
+        """Parameter names of a Kotlin fun, skipping the receiver and vararg/inline modifiers."""
+        paren_match = re.search(
+            r"\bfun\s+(?:<[^>]*>\s*)?(?:[\w.]+(?:<[^>]*>)?\??\.)?\w+\s*\(([^)]*)\)", signature
+        )
+        if not paren_match:
+            return []
+        params = []
+        for part in paren_match.group(1).split(","):
+            words = [w for w in part.split(":")[0].split() if not w.startswith("@")]
+            if words and ":" in part:
+                params.append(words[-1])
+        return params
+
+    def _extract_scala_params(self, signature: str) -> list[str]:
+        """Parameter names of a Scala def across its parameter lists (implicit/using too)."""
+        params = []
+        for group in re.findall(r"\(([^)]*)\)", signature):
+            for part in group.split(","):
+                words = [w for w in part.split(":")[0].split() if w not in ("implicit", "using")]
+                if words and ":" in part:
+                    params.append(words[-1])
+        return params
+
+    def _extract_swift_params(self, signature: str) -> list[str]:
+        """Internal parameter names of a Swift func: x in (_ x: Int) and (with x: Int)."""
+        paren = signature.find("(", signature.find("func ") if "func " in signature else 0)
+        params = []
+        depth, part = 0, ""
+        for char in signature[paren + 1 : -1] + ",":
+            depth += {"(": 1, ")": -1}.get(char, 0)
+            if char == "," and depth == 0:
+                words = part.split(":")[0].split()
+                if words and ":" in part:
+                    params.append(words[-1])
+                part = ""
+            else:
+                part += char
+        return params
+
+    def _extract_sql_params(self, signature: str) -> list[str]:
+        """Named parameters of a SQL routine: p_id in (IN p_id INT), id in (@id INT)."""
+        paren = signature.find("(")
+        if paren == -1:
+            return []
+        params = []
+        depth, part = 0, ""
+        for char in signature[paren + 1 : -1] + ",":
+            depth += {"(": 1, ")": -1}.get(char, 0)
+            if char == "," and depth == 0:
+                words = part.split()
+                if words and words[0].upper() in ("IN", "OUT", "INOUT", "VARIADIC"):
+                    words = words[1:]
+                if len(words) >= 2:  # unnamed parameters are just a type
+                    params.append(words[0].lstrip("@").strip('"`'))
+                part = ""
+            else:
+                part += char
+        return params
 
     def _extract_bases(self, match: re.Match, language: str) -> list[str]:
         """Extract base class names."""
@@ -236,6 +523,46 @@ class RegexFallbackScanner:
             paren_match = re.search(r"\(([^)]+)\)", full_match)
             if paren_match:
                 return [b.strip() for b in paren_match.group(1).split(",")]
+        if language == "cpp":
+            # class Parser : public Base, private util::Noncopyable<Parser> {
+            colon_match = re.search(r"\w\s*:(?!:)([^{]*){", full_match)
+            if colon_match:
+                bases = []
+                for base in colon_match.group(1).split(","):
+                    words = [w for w in base.split() if w not in _CPP_BASE_SPECIFIERS]
+                    if words:
+                        bases.append(words[-1].split("<")[0].split("::")[-1])
+                return bases
+        if language == "php":
+            # class Post extends Model implements HasMedia, Arrayable
+            head = full_match.strip()
+            bases = []
+            clauses = re.findall(r"\b(?:extends|implements)\s+(.+?)(?=\s+implements\b|$)", head)
+            for clause in clauses:
+                bases += [b.strip().lstrip("\\") for b in clause.split(",") if b.strip()]
+            return bases
+        if language == "scala":
+            # class Repo(db: Db) extends Base(db) with Closeable; Scala 3 also extends A, B
+            extends = re.search(r"\bextends\s+(.*)", full_match)
+            if extends:
+                head = re.sub(r"\[[^\]]*\]|\([^)]*\)", "", extends.group(1))
+                return [
+                    b.strip().split(".")[-1]
+                    for b in re.split(r",|\bwith\b", head)
+                    if re.fullmatch(r"[\w.]+", b.strip())
+                ]
+        if language == "swift":
+            # class FeedViewController: UIViewController, UITableViewDelegate
+            head = full_match[match.end(1) - match.start() :]
+            while re.search(r"<[^<>]*>", head):
+                head = re.sub(r"<[^<>]*>", "", head)
+            colon = re.match(r"\s*:(.*?)(?:\bwhere\b.*)?$", head)
+            if colon:
+                return [
+                    b.strip().split(".")[-1]
+                    for b in colon.group(1).split(",")
+                    if re.fullmatch(r"[\w.]+", b.strip())
+                ]
         return []
 
     def _detect_abstract(self, match: re.Match, content: str, language: str) -> bool:
@@ -245,6 +572,18 @@ class RegexFallbackScanner:
             return "ABC" in full_match or "Protocol" in full_match
         if language == "java":
             return "abstract" in match.group(0).lower()
+        if language == "rust":
+            return bool(re.search(r"\btrait\s", match.group(0)))
+        if language == "kotlin":
+            return bool(re.search(r"\b(?:abstract|sealed|interface)\s", match.group(0)))
+        if language == "php":
+            return bool(re.search(r"\b(?:abstract|interface)\s", match.group(0)))
+        if language == "scala":
+            declaration = match.group(0)[: match.start(1) - match.start()]
+            return bool(re.search(r"\b(?:abstract|trait)\s", declaration))
+        if language == "swift":
+            declaration = match.group(0)[: match.start(1) - match.start()]
+            return bool(re.search(r"\bprotocol\s", declaration))
         return False
 
     def _parse_import_match(self, match: re.Match, language: str) -> tuple[str, list[str]]:
@@ -276,6 +615,44 @@ class RegexFallbackScanner:
         # The function body starts after the closing ) of the parameter list
         text = "\n".join(lines)
 
+        if language == "scala":
+            return self._estimate_scala_body(content, start, start_line)
+
+        if language == "swift":
+            return self._estimate_swift_body(content, start)
+
+        if language == "sql":
+            return self._estimate_sql_body(content, start)
+
+        if language == "kotlin" and "{" not in lines[0]:
+            # Expression body (fun area() = w * h) or abstract fun: no braces to match
+            return len(lines[0].partition("=")[2].split()), start_line
+
+        if language == "php":
+            # Abstract and interface methods end at the signature
+            brace, semicolon = text.find("{"), text.find(";")
+            if brace == -1 or -1 < semicolon < brace:
+                return 0, start_line
+            depth = 0
+            for i in range(brace, len(text)):
+                depth += {"{": 1, "}": -1}.get(text[i], 0)
+                if depth == 0:
+                    break
+            return len(text[brace : i + 1].split()), start_line + text.count("\n", 0, i)
+
+        if language in ("c", "cpp"):
+            # The patterns end just past the body's opening brace, which may
+            # sit on a later line than the return type
+            depth = 1
+            tokens = 0
+            brace_line = content.count("\n", 0, start) + 1
+            for offset, line in enumerate(lines):
+                depth += line.count("{") - line.count("}")
+                tokens += len(line.split())
+                if depth <= 0:
+                    return tokens, brace_line + offset
+            return tokens, brace_line + len(lines) - 1
+
         # Find the opening brace of the function body
         # Skip parentheses first (for parameters), then find the {
         paren_count = 1  # We're right after the opening (
@@ -309,24 +686,79 @@ class RegexFallbackScanner:
 
         return token_count, start_line + line_count
 
-    def _estimate_nesting(self, content: str, start_line: int, end_line: int) -> int:
-        """Estimate nesting depth in function."""
-        lines = content.split("\n")[start_line - 1 : end_line]
-        max_indent = 0
-        base_indent = None
+    def _estimate_scala_body(self, content: str, start: int, start_line: int) -> tuple[int, int]:
+        """Body tokens and end line of a Scala def whose signature ends at *start*.
 
-        for line in lines:
+        Bodies are a braced block (def run() = {, or the older def run() {), an
+        expression, or an indented block (Scala 3). Abstract defs have none.
+        """
+        lines = content[start:].split("\n")
+        rest = lines[0]
+        equals = re.search(r"=(?![>=])", rest)
+        brace = rest.find("{")
+        if equals is None and brace == -1:
+            return 0, start_line
+        end_line = content.count("\n", 0, start) + 1
+        if brace != -1:
+            depth = 0
+            for i in range(start + brace, len(content)):
+                depth += {"{": 1, "}": -1}.get(content[i], 0)
+                if depth == 0:
+                    break
+            body = content[start + brace : i + 1]
+            return len(body.split()), end_line + body.count("\n")
+        # Expression body: the rest of the line and any lines indented past the def
+        def_line = content.split("\n")[start_line - 1]
+        indent = len(def_line) - len(def_line.lstrip())
+        tokens = len(rest[equals.end() :].split())
+        last = 0
+        for offset, line in enumerate(lines[1:], 1):
             if not line.strip():
                 continue
-            indent = len(line) - len(line.lstrip())
-            if base_indent is None:
-                base_indent = indent
-            relative_indent = indent - (base_indent or 0)
-            # Estimate nesting: 4 spaces or 1 tab = 1 level
-            nesting = relative_indent // 4
-            max_indent = max(max_indent, nesting)
+            if len(line) - len(line.lstrip()) <= indent:
+                break
+            tokens += len(line.split())
+            last = offset
+        return tokens, end_line + last
 
-        return max_indent
+    def _estimate_swift_body(self, content: str, start: int) -> tuple[int, int]:
+        """Body tokens and end line of a Swift func whose parameters end at *start*.
+
+        The body opens on the signature line (after any effects and return
+        type) or the next one; protocol requirements have none.
+        """
+        line_end = content.find("\n", start)
+        line_end = len(content) if line_end == -1 else line_end
+        brace = content.find("{", start, line_end)
+        if brace == -1:
+            following = content[line_end:].lstrip()
+            if not following.startswith("{"):
+                return 0, content.count("\n", 0, start) + 1
+            brace = len(content) - len(following)
+        depth = 0
+        for i in range(brace, len(content)):
+            depth += {"{": 1, "}": -1}.get(content[i], 0)
+            if depth == 0:
+                break
+        return len(content[brace : i + 1].split()), content.count("\n", 0, i) + 1
+
+    def _estimate_sql_body(self, content: str, start: int) -> tuple[int, int]:
+        """Body tokens and end line of a SQL routine: the rest of its statement.
+
+        Statements end at the delimiter outside dollar-quoted bodies and
+        BEGIN ... END blocks (see sql.py).
+        """
+        masked = mask_sql(content)
+        end = next((e for s, e in statement_spans(masked) if s <= start < e), len(content))
+        body = masked[start:end]
+        return len(body.split()), content.count("\n", 0, start + len(body.rstrip())) + 1
+
+    def _estimate_nesting(self, content: str, start_line: int, end_line: int) -> int:
+        """Estimate nesting depth in function from the indentation of its body."""
+        from ..signals.complexity import line_levels
+
+        lines = content.split("\n")[start_line - 1 : end_line]
+        return max((level for i, level in line_levels(lines) if i > 0), default=0)
 
     def _count_tokens(self, content: str, language: str) -> int:
         """Count tokens in source code (approximate)."""
@@ -342,10 +774,34 @@ class RegexFallbackScanner:
             content = re.sub(r'""".*?"""', "", content, flags=re.DOTALL)
             content = re.sub(r"'''.*?'''", "", content, flags=re.DOTALL)
             content = re.sub(r"#.*", "", content)
-        elif language in ("go", "java", "typescript", "javascript", "rust", "c", "cpp"):
+        elif language in (
+            "go",
+            "java",
+            "kotlin",
+            "scala",
+            "swift",
+            "typescript",
+            "javascript",
+            "rust",
+            "c",
+            "cpp",
+            "proto",
+        ):
             # C-style comments
             content = re.sub(r"/\*.*?\*/", "", content, flags=re.DOTALL)
             content = re.sub(r"//.*", "", content)
+        elif language == "sql":
+            content = re.sub(r"/\*.*?\*/", "", content, flags=re.DOTALL)
+            content = re.sub(r"--.*", "", content)
+        elif language == "hcl":
+            content = re.sub(r"/\*.*?\*/", "", content, flags=re.DOTALL)
+            content = re.sub(r"(?://|#).*", "", content)
+        elif language == "yaml":
+            content = re.sub(r"(?:^|(?<=\s))#.*", "", content, flags=re.MULTILINE)
+        elif language == "php":
+            # C-style and hash comments, but not #[Attribute]
+            content = re.sub(r"/\*.*?\*/", "", content, flags=re.DOTALL)
+            content = re.sub(r"(?://|#(?!\[)).*", "", content)
         return content
 
     def _estimate_complexity(
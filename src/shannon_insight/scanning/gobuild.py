@@ -0,0 +1,256 @@
+"""Go build constraints: which files one build configuration compiles.
+
+A Go file is only compiled when its build constraints hold:
+
+    //go:build linux && (amd64 || arm64)   the constraint line (Go 1.17+)
+    // +build linux,amd64 darwin           the older form, read without //go:build
+    open_windows.go, sys_linux_arm64.go    GOOS and GOARCH file name suffixes
+    import "C"                             cgo files, compiled only with cgo on
+
+Files written for other platforms define the same functions as their
+counterparts (open_unix.go and open_windows.go both define open), so parsed
+all at once they look like duplicates and make call targets ambiguous.
+GoBuildConfig picks one configuration instead, the way
+``GOOS=linux GOARCH=amd64 CGO_ENABLED=1 go build -tags ...`` would, and the
+files it would not compile are left out of the analysis. Tags that hold
+besides GOOS, GOARCH and the given ones: ``unix`` on Unix systems, ``cgo``
+with cgo on, ``gc`` and every ``go1.N`` release. A constraint that cannot
+be parsed keeps its file.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Callable, Optional
+
+if TYPE_CHECKING:
+    from ..config import AnalysisConfig
+
+# GOOS and GOARCH values that constrain files by name (go/build/syslist.go)
+KNOWN_OS = frozenset(
+    {
+        "aix",
+        "android",
+        "darwin",
+        "dragonfly",
+        "freebsd",
+        "hurd",
+        "illumos",
+        "ios",
+        "js",
+        "linux",
+        "nacl",
+        "netbsd",
+        "openbsd",
+        "plan9",
+        "solaris",
+        "wasip1",
+        "windows",
+        "zos",
+    }
+)
+KNOWN_ARCH = frozenset(
+    {
+        "386",
+        "amd64",
+        "amd64p32",
+        "arm",
+        "armbe",
+        "arm64",
+        "arm64be",
+        "loong64",
+        "mips",
+        "mipsle",
+        "mips64",
+        "mips64le",
+        "mips64p32",
+        "mips64p32le",
+        "ppc",
+        "ppc64",
+        "ppc64le",
+        "riscv",
+        "riscv64",
+        "s390",
+        "s390x",
+        "sparc",
+        "sparc64",
+        "wasm",
+    }
+)
+UNIX_OS = frozenset(
+    {
+        "aix",
+        "android",
+        "darwin",
+        "dragonfly",
+        "freebsd",
+        "hurd",
+        "illumos",
+        "ios",
+        "linux",
+        "netbsd",
+        "openbsd",
+        "solaris",
+    }
+)
+# GOOS values that also satisfy another one's tag
+_IMPLIED_OS = {"android": "linux", "illumos": "solaris", "ios": "darwin"}
+
+_TAG_RE = re.compile(r"[\w.]+")
+_RELEASE_RE = re.compile(r"go1\.\d+")
+_TOKEN_RE = re.compile(r"\s*(&&|\|\||[!()]|[\w.]+)")
+_CGO_IMPORT_RE = re.compile(r'^import\s*(?:\(\s*)?"C"', re.MULTILINE)
+
+
+@dataclass(frozen=True)
+class GoBuildConfig:
+    """A Go build configuration: GOOS, GOARCH, build tags and CGO_ENABLED."""
+
+    goos: str = "linux"
+    goarch: str = "amd64"
+    tags: frozenset[str] = frozenset()
+    cgo: bool = True
+
+    def satisfies(self, tag: str) -> bool:
+        """Whether build tag *tag* holds in this configuration."""
+        if tag in (self.goos, self.goarch, "gc") or tag in self.tags:
+            return True
+        if tag == _IMPLIED_OS.get(self.goos):
+            return True
+        if tag == "unix":
+            return self.goos in UNIX_OS
+        if tag == "cgo":
+            return self.cgo
+        return bool(_RELEASE_RE.fullmatch(tag))
+
+    def compiles(self, path: str, content: str) -> bool:
+        """Whether ``go build`` in this configuration compiles the file at *path*."""
+        if not self._name_matches(PurePosixPath(path).name):
+            return False
+        if not self.cgo and _CGO_IMPORT_RE.search(content):
+            return False
+        try:
+            return evaluate_constraint(content, self.satisfies)
+        except ValueError:
+            return True
+
+    def _name_matches(self, name: str) -> bool:
+        """The _GOOS, _GOARCH and _GOOS_GOARCH file name suffixes (go/build goodOSArchFile)."""
+        stem = name.split(".", 1)[0]
+        if stem.endswith("_test"):
+            stem = stem[: -len("_test")]
+        underscore = stem.find("_")
+        if underscore < 0:
+            return True  # linux.go is not constrained, only x_linux.go
+        parts = stem[underscore:].split("_")
+        if len(parts) >= 2 and parts[-2] in KNOWN_OS and parts[-1] in KNOWN_ARCH:
+            return self.satisfies(parts[-2]) and self.satisfies(parts[-1])
+        if parts[-1] in KNOWN_OS or parts[-1] in KNOWN_ARCH:
+            return self.satisfies(parts[-1])
+        return True
+
+
+def go_build_config(config: AnalysisConfig) -> Optional[GoBuildConfig]:
+    """The configuration *config* selects; None when every file is analyzed."""
+    if config.go_constraints == "all":
+        return None
+    return GoBuildConfig(
+        goos=config.go_os,
+        goarch=config.go_arch,
+        tags=frozenset(config.go_tags),
+        cgo=config.go_cgo,
+    )
+
+
+def build_constraint(content: str) -> Optional[str]:
+    """The file's constraint as a //go:build expression, None if unconstrained.
+
+    Only the header counts: comments and blank lines before the package
+    clause. Without a //go:build line, // +build lines are converted:
+    spaces are ORs, commas ANDs, and several lines are ANDed.
+    """
+    plus_build = []
+    in_block = False
+    for line in content.splitlines():
+        stripped = line.strip()
+        if in_block:
+            in_block = "*/" not in stripped
+            continue
+        if stripped.startswith("/*"):
+            in_block = "*/" not in stripped[2:]
+            continue
+        if stripped.startswith("//go:build"):
+            return stripped[len("//go:build") :].strip()
+        if stripped.startswith("//"):
+            words = stripped[2:].split()
+            if words and words[0] == "+build":
+                options = [" && ".join(option.split(",")) for option in words[1:]]
+                plus_build.append(" || ".join(f"({o})" for o in options) or "true")
+            continue
+        if stripped:
+            break  # the package clause
+    return " && ".join(f"({b})" for b in plus_build) if plus_build else None
+
+
+def evaluate_constraint(content: str, satisfied: Callable[[str], bool]) -> bool:
+    """Whether the file's build constraint holds when *satisfied* says which tags do.
+
+    Raises:
+        ValueError: The constraint is not a valid expression
+    """
+    expression = build_constraint(content)
+    if expression is None:
+        return True
+    return evaluate(expression, lambda tag: tag == "true" or satisfied(tag))
+
+
+def evaluate(expression: str, satisfied: Callable[[str], bool]) -> bool:
+    """Evaluate a //go:build expression (``!``, ``&&``, ``||``, parentheses).
+
+    Raises:
+        ValueError: Malformed expression
+    """
+    tokens = []
+    position = 0
+    while position < len(expression.rstrip()):
+        match = _TOKEN_RE.match(expression, position)
+        if match is None:
+            raise ValueError(f"unexpected {expression[position:].strip()!r}")
+        tokens.append(match.group(1))
+        position = match.end()
+
+    def parse_or(i: int) -> tuple[bool, int]:
+        value, i = parse_and(i)
+        while i < len(tokens) and tokens[i] == "||":
+            right, i = parse_and(i + 1)
+            value = value or right
+        return value, i
+
+    def parse_and(i: int) -> tuple[bool, int]:
+        value, i = parse_not(i)
+        while i < len(tokens) and tokens[i] == "&&":
+            right, i = parse_not(i + 1)
+            value = value and right
+        return value, i
+
+    def parse_not(i: int) -> tuple[bool, int]:
+        if i >= len(tokens):
+            raise ValueError("unexpected end of expression")
+        if tokens[i] == "!":
+            value, i = parse_not(i + 1)
+            return not value, i
+        if tokens[i] == "(":
+            value, i = parse_or(i + 1)
+            if i >= len(tokens) or tokens[i] != ")":
+                raise ValueError("missing )")
+            return value, i + 1
+        if not _TAG_RE.fullmatch(tokens[i]):
+            raise ValueError(f"unexpected {tokens[i]!r}")
+        return satisfied(tokens[i]), i + 1
+
+    value, end = parse_or(0)
+    if end != len(tokens):
+        raise ValueError(f"unexpected {tokens[end]!r}")
+    return value
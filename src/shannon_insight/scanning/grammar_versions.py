@@ -0,0 +1,66 @@
+"""Which parser, at which version, read each language of a snapshot.
+
+Upgrading a tree-sitter grammar can change function boundaries, nesting and
+imports in files nobody touched, and trend lines would show it as a change
+in the code. Every snapshot therefore records what parsed each of its
+languages:
+
+    python   "tree-sitter-python 0.23.6 (tree-sitter 0.24.0)"
+    elixir   "custom grammars/elixir.so 3f2a9c1d07b4"   (sha256 of the library)
+    sql      "regex 0.9.0"                              (the fallback scanner
+                                                         of this release)
+
+``shannon-insight history grammar-impact`` compares the snapshots either
+side of a change (see persistence/grammar_impact.py).
+"""
+
+from __future__ import annotations
+
+import hashlib
+from importlib import metadata
+from pathlib import Path
+from typing import Any, Iterable, Mapping, Optional
+
+from .. import __version__
+from .grammars import parse_grammars
+from .treesitter_parser import TREE_SITTER_AVAILABLE, _language_modules
+
+
+def grammar_versions(
+    languages: Iterable[str],
+    grammars: Optional[Mapping[str, Any]] = None,
+    root: Optional[Path] = None,
+) -> dict[str, str]:
+    """Parser and version for each of *languages*.
+
+    *grammars* is the ``grammars`` config table; custom grammar libraries
+    are resolved against *root*, as when they are loaded.
+    """
+    custom = {grammar.name: grammar for grammar in parse_grammars(grammars or {})}
+    versions = {}
+    for language in sorted(set(languages)):
+        if language in custom:
+            library = custom[language].library
+            versions[language] = f"custom {library} {_digest(Path(root or '.') / library)}"
+        elif TREE_SITTER_AVAILABLE and language in _language_modules:
+            module = _language_modules[language].__name__.replace("_", "-")
+            versions[language] = (
+                f"{module} {_distribution(module)} (tree-sitter {_distribution('tree-sitter')})"
+            )
+        else:
+            versions[language] = f"regex {__version__}"
+    return versions
+
+
+def _distribution(name: str) -> str:
+    try:
+        return metadata.version(name)
+    except metadata.PackageNotFoundError:
+        return "unknown"
+
+
+def _digest(path: Path) -> str:
+    try:
+        return hashlib.sha256(path.read_bytes()).hexdigest()[:12]
+    except OSError:
+        return "missing"
@@ -0,0 +1,349 @@
+"""Custom tree-sitter grammars, loaded from a compiled library.
+
+A language without first-class support can still be parsed with tree-sitter:
+point ``grammars`` at its compiled grammar and say which node types are
+functions, classes, imports, calls and nesting constructs::
+
+    [grammars.elixir]
+    library = "grammars/elixir.so"   # relative to the analyzed root
+    extensions = [".ex", ".exs"]
+    function = ["function_definition"]
+    class = ["module_definition"]
+    import = ["import_directive", "alias_directive"]
+    call = ["call"]
+    nesting = ["if", "case", "cond", "with"]
+
+``library`` is a shared library (``.so``, ``.dylib``, ``.dll``) exporting
+``symbol`` (default ``tree_sitter_<name>``), as built by ``tree-sitter
+build``, or a ``.wasm`` grammar, which needs a tree-sitter binding built
+with WebAssembly support and the ``wasmtime`` package. A custom grammar
+takes over its extensions from any built-in language.
+
+The tree is read generically, as no query module is written for it:
+
+    function  name from the ``name`` field (or the first identifier
+              child), parameters from the ``parameters`` field, body from
+              the ``body`` field (or the whole node)
+    class     name as for functions; its methods are the functions inside
+    import    the first string inside the node, quotes stripped (or its
+              ``path``/``source``/``name`` field)
+    call      the callee's last name segment (``repo.save`` -> ``save``),
+              from the ``function``/``name``/``method`` field; without
+              ``call`` node types, functions have no call targets
+    nesting   each enclosing node of these types adds one level
+
+A grammar that fails to load is reported once, and its files fall back to
+the regex scanner.
+"""
+
+from __future__ import annotations
+
+import ctypes
+import logging
+import re
+from dataclasses import dataclass
+from pathlib import Path
+from threading import Lock
+from typing import Any, Iterator, Mapping, Optional
+
+from .recovery import parse_with_recovery
+from .syntax import ClassDef, FileSyntax, FunctionDef, ImportDecl
+
+logger = logging.getLogger(__name__)
+
+GRAMMAR_LIBRARY_SUFFIXES = (".so", ".dylib", ".dll", ".wasm")
+
+# Node-type lists of the mapping, as written in config
+NODE_KINDS = ("function", "class", "import", "call", "nesting")
+
+_KEYS = frozenset({"library", "extensions", "symbol", *NODE_KINDS})
+_NAME_RE = re.compile(r"^[a-z][a-z0-9_-]*$")
+_CALLEE_FIELDS = ("function", "name", "method")
+_IMPORT_FIELDS = ("path", "source", "name")
+_SEGMENT_RE = re.compile(r"[\w$]+[!?]?$")
+
+
+@dataclass(frozen=True)
+class CustomGrammar:
+    """A compiled grammar and the node types it maps to FileSyntax."""
+
+    name: str
+    library: str
+    extensions: tuple[str, ...]
+    symbol: str
+    function: frozenset[str] = frozenset()
+    class_: frozenset[str] = frozenset()
+    import_: frozenset[str] = frozenset()
+    call: frozenset[str] = frozenset()
+    nesting: frozenset[str] = frozenset()
+
+
+def parse_grammars(raw: Mapping[str, Any]) -> list[CustomGrammar]:
+    """The ``grammars`` config table as CustomGrammars.
+
+    Raises:
+        ValueError: On a missing library or extensions, an unknown key, or a
+            value of the wrong type
+    """
+    grammars = []
+    for name, table in sorted(raw.items()):
+        where = f"grammars.{name}"
+        if not _NAME_RE.match(name):
+            raise ValueError(f"{where}: language names are lowercase letters, digits, - and _")
+        if not isinstance(table, Mapping):
+            raise ValueError(f"{where} must be a table")
+        unknown = sorted(set(table) - _KEYS)
+        if unknown:
+            raise ValueError(f"{where}: unknown keys {', '.join(unknown)}")
+        library = table.get("library")
+        if not isinstance(library, str) or not library.lower().endswith(
+            GRAMMAR_LIBRARY_SUFFIXES
+        ):
+            raise ValueError(
+                f"{where}.library must be a path ending in "
+                + ", ".join(GRAMMAR_LIBRARY_SUFFIXES)
+            )
+        extensions = _strings(table.get("extensions"), f"{where}.extensions")
+        if not extensions or not all(e.startswith(".") for e in extensions):
+            raise ValueError(f"{where}.extensions must list suffixes like \".ex\"")
+        symbol = table.get("symbol", f"tree_sitter_{name.replace('-', '_')}")
+        if not isinstance(symbol, str) or not symbol:
+            raise ValueError(f"{where}.symbol must be a string")
+        kinds = {k: frozenset(_strings(table.get(k), f"{where}.{k}")) for k in NODE_KINDS}
+        grammars.append(
+            CustomGrammar(
+                name,
+                library,
+                tuple(e.lower() for e in extensions),
+                symbol,
+                function=kinds["function"],
+                class_=kinds["class"],
+                import_=kinds["import"],
+                call=kinds["call"],
+                nesting=kinds["nesting"],
+            )
+        )
+    return grammars
+
+
+def _strings(value: Any, where: str) -> list[str]:
+    if value is None:
+        return []
+    if not isinstance(value, list) or not all(isinstance(v, str) and v for v in value):
+        raise ValueError(f"{where} must be a list of strings")
+    return value
+
+
+def grammar_extensions(raw: Mapping[str, Any]) -> frozenset[str]:
+    """Every extension claimed by the ``grammars`` config table."""
+    return frozenset(e for g in parse_grammars(raw) for e in g.extensions)
+
+
+def load_language(grammar: CustomGrammar, root: Path) -> Any:
+    """The tree_sitter.Language in *grammar*'s library.
+
+    Raises:
+        ImportError: tree-sitter is not installed
+        OSError: The library cannot be loaded or lacks the symbol
+    """
+    import tree_sitter
+
+    path = Path(grammar.library)
+    if not path.is_absolute():
+        path = root / path
+    if path.suffix.lower() == ".wasm":
+        from_wasm = getattr(tree_sitter.Language, "from_wasm", None)
+        if from_wasm is None:
+            raise OSError("this tree-sitter build cannot load .wasm grammars")
+        import wasmtime  # type: ignore[import-not-found]
+
+        return from_wasm(grammar.name, wasmtime.Engine(), path.read_bytes())
+
+    library = ctypes.CDLL(str(path))
+    try:
+        entry = getattr(library, grammar.symbol)
+    except AttributeError:
+        raise OSError(f"{path} does not export {grammar.symbol}") from None
+    entry.restype = ctypes.c_void_p
+    # The grammar packages on PyPI hand tree-sitter the same capsule
+    new_capsule = ctypes.pythonapi.PyCapsule_New
+    new_capsule.restype = ctypes.py_object
+    new_capsule.argtypes = (ctypes.c_void_p, ctypes.c_char_p, ctypes.c_void_p)
+    return tree_sitter.Language(new_capsule(entry(), b"tree_sitter.Language", None))
+
+
+class CustomGrammarParser:
+    """Parses the files of custom grammars into FileSyntax.
+
+    Grammars are loaded on first use, relative to the root of the first
+    file parsed with them.
+    """
+
+    def __init__(self, grammars: list[CustomGrammar]) -> None:
+        self._by_extension = {e: g for g in grammars for e in g.extensions}
+        self._languages: dict[str, Any] = {}  # name -> Language, or None if it failed
+        self._lock = Lock()
+
+    def __bool__(self) -> bool:
+        return bool(self._by_extension)
+
+    def grammar_for(self, path: Path) -> Optional[CustomGrammar]:
+        return self._by_extension.get(path.suffix.lower())
+
+    def _language(self, grammar: CustomGrammar, root: Path) -> Any:
+        with self._lock:
+            if grammar.name not in self._languages:
+                try:
+                    self._languages[grammar.name] = load_language(grammar, root)
+                except (ImportError, OSError, ValueError) as e:
+                    logger.warning(f"Cannot load the {grammar.name} grammar: {e}")
+                    self._languages[grammar.name] = None
+            return self._languages[grammar.name]
+
+    def parse_file(
+        self, content: str, path: str, grammar: CustomGrammar, root: Path, mtime: float = 0.0
+    ) -> Optional[FileSyntax]:
+        """FileSyntax of *content*, or None if the grammar is unavailable."""
+        language = self._language(grammar, root)
+        if language is None:
+            return None
+        import tree_sitter
+
+        code = content.encode("utf-8", errors="replace")
+        try:
+            parser = tree_sitter.Parser(language)
+            tree, code, parse_errors = parse_with_recovery(parser.parse, code)
+        except Exception as e:
+            logger.debug(f"{grammar.name} parse of {path} failed: {e}")
+            return None
+        syntax = extract_syntax(tree.root_node, code, grammar, path, mtime)
+        syntax.parse_errors = parse_errors
+        return syntax
+
+
+def extract_syntax(
+    root: Any, code: bytes, grammar: CustomGrammar, path: str, mtime: float = 0.0
+) -> FileSyntax:
+    """Read functions, classes and imports from a tree by *grammar*'s node types."""
+    functions: list[FunctionDef] = []
+    classes: list[ClassDef] = []
+    imports: list[ImportDecl] = []
+    # Enclosing classes and functions; a method's innermost scope is its class
+    scopes: list[tuple[Any, Optional[ClassDef]]] = []
+    for node in _walk(root):
+        while scopes and node.start_byte >= scopes[-1][0].end_byte:
+            scopes.pop()
+        if node.type in grammar.class_:
+            cls = ClassDef(_name(node, code), [], [], [])
+            classes.append(cls)
+            scopes.append((node, cls))
+        elif node.type in grammar.function:
+            fn = _function(node, code, grammar)
+            functions.append(fn)
+            if scopes and scopes[-1][1] is not None:
+                scopes[-1][1].methods.append(fn)
+            scopes.append((node, None))
+        elif node.type in grammar.import_:
+            source = _import_source(node, code)
+            if source:
+                imports.append(ImportDecl(source, []))
+
+    lines = code.count(b"\n") + 1 if code else 0
+    complexity = (
+        sum(fn.nesting_depth + 1 for fn in functions) / len(functions) if functions else 1.0
+    )
+    return FileSyntax(
+        path=path,
+        functions=functions,
+        classes=classes,
+        imports=imports,
+        language=grammar.name,
+        mtime=mtime,
+        _lines=lines,
+        _tokens=_leaves(root),
+        _complexity=complexity,
+    )
+
+
+def _walk(node: Any) -> Iterator[Any]:
+    """Every node under *node*, in source order."""
+    stack = [node]
+    while stack:
+        current = stack.pop()
+        yield current
+        stack.extend(reversed(current.children))
+
+
+def _text(node: Any, code: bytes) -> str:
+    return code[node.start_byte : node.end_byte].decode("utf-8", errors="replace")
+
+
+def _leaves(node: Any) -> int:
+    return sum(1 for n in _walk(node) if not n.children)
+
+
+def _name(node: Any, code: bytes) -> str:
+    named = node.child_by_field_name("name")
+    if named is None:
+        named = next((c for c in node.named_children if "identifier" in c.type), None)
+    return _text(named, code) if named is not None else "<anonymous>"
+
+
+def _function(node: Any, code: bytes, grammar: CustomGrammar) -> FunctionDef:
+    body = node.child_by_field_name("body") or node
+    body_tokens = _leaves(body)
+    params_node = node.child_by_field_name("parameters")
+    params = (
+        [_text(n, code) for n in _walk(params_node) if "identifier" in n.type and not n.children]
+        if params_node is not None
+        else []
+    )
+    calls = None
+    if grammar.call:
+        calls = [c for c in (_callee(n, code) for n in _walk(body) if n.type in grammar.call) if c]
+    return FunctionDef(
+        _name(node, code),
+        params,
+        body_tokens,
+        max(_leaves(node) - body_tokens, 1) if body is not node else 1,
+        _nesting(body, grammar.nesting),
+        node.start_point[0] + 1,
+        node.end_point[0] + 1,
+        call_targets=calls,
+    )
+
+
+def _nesting(node: Any, kinds: frozenset[str]) -> int:
+    deepest = 0
+    stack = [(child, 0) for child in node.children]
+    while stack:
+        current, depth = stack.pop()
+        if current.type in kinds:
+            depth += 1
+            deepest = max(deepest, depth)
+        stack.extend((child, depth) for child in current.children)
+    return deepest
+
+
+def _callee(node: Any, code: bytes) -> Optional[str]:
+    target = next(
+        (c for c in map(node.child_by_field_name, _CALLEE_FIELDS) if c is not None),
+        node.named_children[0] if node.named_children else None,
+    )
+    if target is None:
+        return None
+    match = _SEGMENT_RE.search(_text(target, code).strip())
+    return match[0] if match else None
+
+
+def _import_source(node: Any, code: bytes) -> Optional[str]:
+    for child in _walk(node):
+        if child is not node and "string" in child.type:
+            return _text(child, code).strip("\"'`<>")
+    for field in _IMPORT_FIELDS:
+        child = node.child_by_field_name(field)
+        if child is not None:
+            return _text(child, code).strip()
+    # The node's text after its keyword
+    parts = _text(node, code).split(None, 1)
+    return parts[1].strip().rstrip(";") if len(parts) == 2 else None
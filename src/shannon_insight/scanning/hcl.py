@@ -0,0 +1,273 @@
+"""HCL (Terraform) blocks and attributes, parsed from source text.
+
+A file is a body of attributes (``name = expression``) and blocks
+(``type "label" ... { body }``). The parser tokenizes comments (``#``,
+``//``, ``/* */``), quoted templates with ``${ }`` / ``%{ }``
+interpolations and heredocs (``<<EOF``, ``<<-EOF``), so braces inside them
+never open blocks, and object expressions (``tags = { ... }``) are read as
+attribute values, not blocks.
+
+``annotate_hcl`` runs after the fallback parser and fills in:
+
+    functions   one per resource, data source and module call
+                ("aws_instance.web", "data.aws_ami.ubuntu", "module.vpc"),
+                with the depth of the blocks nested inside as nesting depth
+    imports     the source of every module call; local sources ("./vpc")
+                are resolved to the module directory by the graph builder
+                (see graph/builder.py)
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from functools import lru_cache
+from typing import Iterator, Optional
+
+from .syntax import FileSyntax, FunctionDef, ImportDecl
+
+# Blocks that become functions, and how their name is prefixed
+_UNIT_PREFIXES = {"resource": "", "data": "data.", "module": "module."}
+
+_HEREDOC_RE = re.compile(r"<<(-?)([A-Za-z_][\w-]*)[ \t]*\n")
+_IDENT_RE = re.compile(r"[A-Za-z_][\w-]*")
+_NUMBER_RE = re.compile(r"\d+(?:\.\d+)?(?:[eE][+-]?\d+)?")
+_OPENERS = {"{": "}", "(": ")", "[": "]"}
+
+
+@dataclass
+class HclBlock:
+    """A block: its type, labels, attributes and nested blocks."""
+
+    type: str
+    labels: list[str]
+    start_line: int
+    end_line: int
+    attributes: dict[str, str] = field(default_factory=dict)  # name -> expression text
+    blocks: list[HclBlock] = field(default_factory=list)
+
+    @property
+    def name(self) -> str:
+        return ".".join(self.labels)
+
+    @property
+    def depth(self) -> int:
+        """Levels of blocks nested inside this one (0 when there are none)."""
+        return 1 + max((b.depth for b in self.blocks), default=-1)
+
+    def walk(self) -> Iterator[HclBlock]:
+        """This block and every block nested in it, depth first."""
+        yield self
+        for block in self.blocks:
+            yield from block.walk()
+
+    def string(self, attribute: str) -> Optional[str]:
+        """Value of an attribute set to a plain string literal."""
+        value = self.attributes.get(attribute, "")
+        if len(value) >= 2 and value[0] == value[-1] == '"' and "${" not in value:
+            return value[1:-1]
+        return None
+
+
+@dataclass(frozen=True)
+class _Token:
+    kind: str  # ident, string, number, punct, newline, heredoc
+    text: str
+    line: int
+
+
+def _tokenize(content: str) -> list[_Token]:
+    tokens: list[_Token] = []
+    i, line, n = 0, 1, len(content)
+    while i < n:
+        char = content[i]
+        if char == "\n":
+            tokens.append(_Token("newline", "\n", line))
+            i, line = i + 1, line + 1
+        elif char in " \t\r":
+            i += 1
+        elif char == "#" or content.startswith("//", i):
+            end = content.find("\n", i)
+            i = n if end == -1 else end
+        elif content.startswith("/*", i):
+            end = content.find("*/", i + 2)
+            end = n if end == -1 else end + 2
+            line += content.count("\n", i, end)
+            i = end
+        elif char == '"':
+            end = _string_end(content, i)
+            tokens.append(_Token("string", content[i:end], line))
+            line += content.count("\n", i, end)
+            i = end
+        elif content.startswith("<<", i) and (match := _HEREDOC_RE.match(content, i)):
+            end = _heredoc_end(content, match.end(), match.group(2))
+            tokens.append(_Token("heredoc", content[i:end], line))
+            line += content.count("\n", i, end)
+            i = end
+        elif match := _IDENT_RE.match(content, i):
+            tokens.append(_Token("ident", match.group(0), line))
+            i = match.end()
+        elif match := _NUMBER_RE.match(content, i):
+            tokens.append(_Token("number", match.group(0), line))
+            i = match.end()
+        else:
+            # == != <= >= => are one token, so "=" alone always assigns
+            two = content[i : i + 2]
+            text = two if two in ("==", "!=", "<=", ">=", "=>", "&&", "||") else char
+            tokens.append(_Token("punct", text, line))
+            i += len(text)
+    return tokens
+
+
+def _string_end(content: str, start: int) -> int:
+    """Index just past the quoted template starting at *start*.
+
+    Interpolations may contain braces and quoted strings of their own.
+    """
+    i, n = start + 1, len(content)
+    while i < n:
+        char = content[i]
+        if char == "\\":
+            i += 2
+        elif char == '"':
+            return i + 1
+        elif content.startswith(("${", "%{"), i):
+            i = _interpolation_end(content, i + 2)
+        elif char == "\n":
+            return i  # unterminated: stop at the end of the line
+        else:
+            i += 1
+    return n
+
+
+def _interpolation_end(content: str, start: int) -> int:
+    depth, i, n = 1, start, len(content)
+    while i < n and depth:
+        char = content[i]
+        if char == '"':
+            i = _string_end(content, i)
+            continue
+        depth += {"{": 1, "}": -1}.get(char, 0)
+        i += 1
+    return i
+
+
+def _heredoc_end(content: str, start: int, marker: str) -> int:
+    """Index just past the line closing a heredoc (the marker alone, maybe indented)."""
+    closing = re.compile(r"^[ \t]*" + re.escape(marker) + r"[ \t]*$", re.MULTILINE)
+    match = closing.search(content, start)
+    return match.end() if match else len(content)
+
+
+class _Parser:
+    def __init__(self, tokens: list[_Token]) -> None:
+        self.tokens = tokens
+        self.i = 0
+
+    def peek(self, offset: int = 0) -> Optional[_Token]:
+        j = self.i + offset
+        return self.tokens[j] if j < len(self.tokens) else None
+
+    def body(self, block: HclBlock) -> None:
+        """Parse attributes and blocks up to the closing brace (or the end)."""
+        while (token := self.peek()) is not None:
+            if token.kind == "newline" or token.text == ",":
+                self.i += 1
+            elif token.text == "}":
+                block.end_line = token.line
+                self.i += 1
+                return
+            elif token.kind == "ident" and self._next_is_equals():
+                self.i += 2
+                block.attributes[token.text] = self.expression()
+            elif token.kind == "ident" and (labels := self._labels()) is not None:
+                self.i += len(labels) + 2  # type, labels and "{"
+                child = HclBlock(token.text, labels, token.line, token.line)
+                self.body(child)
+                block.blocks.append(child)
+            else:
+                self.expression()  # not a statement; skip to the end of the line
+        block.end_line = self.tokens[-1].line if self.tokens else block.start_line
+
+    def _next_is_equals(self) -> bool:
+        following = self.peek(1)
+        return following is not None and following.text == "="
+
+    def _labels(self) -> Optional[list[str]]:
+        """Labels between a block type and its "{", or None when this is no block."""
+        labels = []
+        j = 1
+        while (token := self.peek(j)) is not None:
+            if token.text == "{":
+                return labels
+            if token.kind == "string":
+                labels.append(token.text.strip('"'))
+            elif token.kind == "ident":
+                labels.append(token.text)
+            else:
+                return None
+            j += 1
+        return None
+
+    def expression(self) -> str:
+        """Tokens up to the end of the line, brackets balanced; returns the text."""
+        stack: list[str] = []
+        parts: list[str] = []
+        while (token := self.peek()) is not None:
+            if not stack and (token.kind == "newline" or token.text in ("}", ",")):
+                break
+            self.i += 1
+            if token.text in _OPENERS:
+                stack.append(_OPENERS[token.text])
+            elif stack and token.text == stack[-1]:
+                stack.pop()
+            if token.kind != "newline":
+                parts.append(token.text)
+        return " ".join(parts)
+
+
+@lru_cache(maxsize=64)
+def _parse_cached(content: str) -> tuple[HclBlock, ...]:
+    root = HclBlock("", [], 1, 1)
+    _Parser(_tokenize(content)).body(root)
+    return tuple(root.blocks)
+
+
+def parse_hcl(content: str) -> list[HclBlock]:
+    """Top-level blocks of an HCL file.
+
+    Results are cached by content; treat the blocks as read-only.
+    """
+    return list(_parse_cached(content))
+
+
+def block_tokens(content: str, block: HclBlock) -> int:
+    lines = content.splitlines()[block.start_line - 1 : block.end_line]
+    return sum(1 for t in _tokenize("\n".join(lines)) if t.kind != "newline")
+
+
+def annotate_hcl(syntax: FileSyntax, content: str) -> None:
+    """Replace fallback results with resources, data sources and module calls."""
+    functions = []
+    imports = []
+    for block in parse_hcl(content):
+        prefix = _UNIT_PREFIXES.get(block.type)
+        if prefix is None or not block.labels:
+            continue
+        functions.append(
+            FunctionDef(
+                name=prefix + block.name,
+                params=[],
+                body_tokens=block_tokens(content, block),
+                signature_tokens=1 + len(block.labels),
+                nesting_depth=block.depth,
+                start_line=block.start_line,
+                end_line=block.end_line,
+            )
+        )
+        source = block.string("source") if block.type == "module" else None
+        if source:
+            imports.append(ImportDecl(source=source, names=[block.name]))
+    syntax.functions = functions
+    syntax.classes = []
+    syntax.imports = imports
@@ -7,6 +7,7 @@ Adding a new language:
 
 import re as _re
 from dataclasses import dataclass, field
+from typing import Optional
 
 # ── Canonical skip directories (shared across scanning and environment) ─────
 # This is THE single source of truth for directories to skip during file discovery.
@@ -30,6 +31,9 @@ SKIP_DIRS: frozenset[str] = frozenset(
         ".eggs",
         "third_party",
         "cmake-build",
+        ".terraform",
+        ".terragrunt-cache",
+        ".ipynb_checkpoints",
     }
 )
 
@@ -131,7 +135,7 @@ LANGUAGES = {
     ),
     "python": LanguageConfig(
         name="python",
-        extensions=[".py"],
+        extensions=[".py", ".ipynb"],
         comment_patterns=[_HASH_COMMENT, _TRIPLE_DQ_STR, _TRIPLE_SQ_STR],
         string_patterns=[_DOUBLE_QUOTE_STR, _SINGLE_QUOTE_STR],
         function_patterns=[r"^\s*def\s+\w+\s*\("],
@@ -172,7 +176,7 @@ LANGUAGES = {
     ),
     "javascript": LanguageConfig(
         name="javascript",
-        extensions=[".js", ".mjs", ".cjs"],
+        extensions=[".js", ".mjs", ".cjs", ".vue", ".svelte"],
         comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT],
         string_patterns=[_BACKTICK_STR, _DOUBLE_QUOTE_STR, _SINGLE_QUOTE_STR],
         function_patterns=[
@@ -291,6 +295,239 @@ LANGUAGES = {
             ("annotation", r"^\s*@\w+"),
         ],
     ),
+    "kotlin": LanguageConfig(
+        name="kotlin",
+        extensions=[".kt", ".kts"],
+        comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT],
+        string_patterns=[_DOUBLE_QUOTE_STR],
+        function_patterns=[r"\bfun\s+(?:<[^>]+>\s*)?(?:[\w.]+(?:<[^>]*>)?\??\.)?\w+\s*\("],
+        import_patterns=[r"^import\s+([\w.]+)"],
+        export_patterns=[
+            r"^(?:public\s+)?(?:(?:abstract|open|sealed|data|enum|inner)\s+)*"
+            r"(?:class|interface|object)\s+(\w+)",
+            r"^(?:public\s+)?(?:(?:inline|suspend|operator|infix)\s+)*fun\s+"
+            r"(?:<[^>]+>\s*)?(?:[\w.]+\.)?(\w+)\s*\(",
+        ],
+        complexity_keywords=["if", "else", "when", "for", "while", "catch"],
+        complexity_operators=["&&", r"\|\|", r"\?:"],
+        nesting_mode="brace",
+        struct_patterns=[r"\bclass\s+\w+", r"\bobject\s+\w+"],
+        interface_patterns=[r"\binterface\s+\w+"],
+        skip_dirs=(
+            "build",
+            ".gradle",
+            ".idea",
+            ".git",
+            "node_modules",
+            "venv",
+            ".venv",
+            "__pycache__",
+        ),
+        skip_file_prefixes=(),
+        skip_file_suffixes=("Test.kt", "Tests.kt"),
+        skip_path_fragments=("/test/", "/androidTest/"),
+        extra_ast_patterns=[
+            ("extension", r"\bfun\s+(?:<[^>]+>\s*)?[\w.]+(?:<[^>]*>)?\??\.\w+\s*\("),
+            ("suspend", r"\bsuspend\s+fun\b"),
+            ("coroutine", r"\b(?:launch|async|runBlocking|withContext|coroutineScope)\s*[({]"),
+            ("annotation", r"^\s*@\w+"),
+        ],
+    ),
+    "scala": LanguageConfig(
+        name="scala",
+        extensions=[".scala"],
+        comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT],
+        string_patterns=[_DOUBLE_QUOTE_STR],
+        function_patterns=[r"\bdef\s+\w+"],
+        import_patterns=[r"^\s*import\s+([\w.]+)"],
+        export_patterns=[
+            r"^(?:(?:abstract|sealed|final|case|implicit)\s+)*"
+            r"(?:class|trait|object|enum)\s+(\w+)",
+            r"^\s*(?:(?:override|final|implicit|inline)\s+)*def\s+(\w+)",
+        ],
+        complexity_keywords=["if", "else", "case", "for", "while", "catch"],
+        complexity_operators=["&&", r"\|\|"],
+        nesting_mode="brace",
+        struct_patterns=[r"\bclass\s+\w+", r"\bobject\s+\w+"],
+        interface_patterns=[r"\btrait\s+\w+"],
+        skip_dirs=(
+            "target",
+            ".bsp",
+            ".bloop",
+            ".metals",
+            ".idea",
+            ".git",
+            "node_modules",
+            "venv",
+            ".venv",
+            "__pycache__",
+        ),
+        skip_file_prefixes=(),
+        skip_file_suffixes=("Test.scala", "Spec.scala", "Suite.scala"),
+        skip_path_fragments=("/test/", "/it/"),
+        extra_ast_patterns=[
+            ("case_class", r"\bcase\s+class\s+\w+"),
+            ("pattern_match", r"\bmatch\b"),
+            ("implicit", r"\b(?:implicit|given|using)\b"),
+            ("for_comprehension", r"\bfor\s*\{"),
+            ("annotation", r"^\s*@\w+"),
+        ],
+    ),
+    "swift": LanguageConfig(
+        name="swift",
+        extensions=[".swift"],
+        comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT],
+        string_patterns=[_DOUBLE_QUOTE_STR],
+        function_patterns=[r"\bfunc\s+\w+"],
+        import_patterns=[
+            r"^\s*(?:@\w+\s+)*import\s+(?:(?:class|struct|enum|protocol|func)\s+)?([\w.]+)"
+        ],
+        export_patterns=[
+            r"^(?:(?:public|open|final)\s+)*(?:class|struct|enum|protocol|actor)\s+(\w+)",
+            r"^\s*(?:(?:public|open|static|class|override|final)\s+)*func\s+(\w+)",
+        ],
+        complexity_keywords=["if", "else", "guard", "case", "for", "while", "catch"],
+        complexity_operators=["&&", r"\|\|", r"\?\?"],
+        nesting_mode="brace",
+        struct_patterns=[r"\b(?:class|struct|actor)\s+\w+"],
+        interface_patterns=[r"\bprotocol\s+\w+"],
+        skip_dirs=(
+            ".build",
+            "Pods",
+            "Carthage",
+            "DerivedData",
+            ".swiftpm",
+            ".git",
+            "node_modules",
+            "venv",
+            ".venv",
+            "__pycache__",
+        ),
+        skip_file_prefixes=(),
+        skip_file_suffixes=("Tests.swift", "Test.swift"),
+        skip_path_fragments=("/Tests/", "/UITests/"),
+        extra_ast_patterns=[
+            ("extension", r"^\s*(?:(?:public|private|fileprivate|internal)\s+)?extension\s+\w+"),
+            ("optional_binding", r"\b(?:if|guard)\s+(?:let|var)\b"),
+            ("closure", r"\{\s*(?:\[[^\]]*\]\s*)?(?:\w+(?:\s*,\s*\w+)*|\([^)]*\))\s+in\b"),
+            ("async", r"\b(?:async|await)\b"),
+            ("attribute", r"^\s*@\w+"),
+        ],
+    ),
+    "sql": LanguageConfig(
+        name="sql",
+        extensions=[".sql"],
+        comment_patterns=[(r"--.*", 0), _C_BLOCK_COMMENT],
+        string_patterns=[_SINGLE_QUOTE_STR],
+        function_patterns=[r"(?i)\bCREATE\s+(?:OR\s+REPLACE\s+)?(?:FUNCTION|PROCEDURE)\s+[\w.]+"],
+        import_patterns=[r"(?i)\b(?:FROM|JOIN|INTO|UPDATE|REFERENCES)\s+([\w.]+)"],
+        export_patterns=[
+            r"(?i)^\s*CREATE\s+(?:OR\s+REPLACE\s+)?(?:TABLE|VIEW|FUNCTION|PROCEDURE)\s+"
+            r"(?:IF\s+NOT\s+EXISTS\s+)?([\w.]+)",
+        ],
+        complexity_keywords=["IF", "ELSIF", "WHEN", "LOOP", "WHILE", "JOIN", "UNION"],
+        complexity_operators=[r"\bAND\b", r"\bOR\b"],
+        nesting_mode="indent",
+        struct_patterns=[r"(?i)\bCREATE\s+TABLE\s+[\w.]+"],
+        interface_patterns=[r"(?i)\bCREATE\s+(?:OR\s+REPLACE\s+)?VIEW\s+[\w.]+"],
+        skip_dirs=(
+            ".git",
+            "node_modules",
+            "venv",
+            ".venv",
+            "__pycache__",
+        ),
+        skip_file_prefixes=(),
+        skip_path_fragments=("/testdata/", "/fixtures/"),
+        extra_ast_patterns=[
+            ("create_table", r"(?i)\bCREATE\s+TABLE\b"),
+            ("alter_table", r"(?i)\bALTER\s+TABLE\b"),
+            ("routine", r"(?i)\bCREATE\s+(?:OR\s+REPLACE\s+)?(?:FUNCTION|PROCEDURE|TRIGGER)\b"),
+            ("join", r"(?i)\bJOIN\b"),
+            ("subquery", r"(?i)\(\s*SELECT\b"),
+        ],
+    ),
+    "hcl": LanguageConfig(
+        name="hcl",
+        extensions=[".tf", ".hcl"],
+        comment_patterns=[_HASH_COMMENT, _C_LINE_COMMENT, _C_BLOCK_COMMENT],
+        string_patterns=[_DOUBLE_QUOTE_STR],
+        function_patterns=[r"^\s*(?:resource|data|module)\s+\"[^\"]+\""],
+        import_patterns=[r"^\s*source\s*=\s*\"([^\"]+)\""],
+        export_patterns=[r"^\s*output\s+\"([^\"]+)\""],
+        complexity_keywords=["for", "for_each", "count", "dynamic", "if"],
+        complexity_operators=[r"\?", "&&", r"\|\|"],
+        nesting_mode="brace",
+        struct_patterns=[r"^\s*variable\s+\"[^\"]+\""],
+        skip_dirs=(
+            ".terraform",
+            ".terragrunt-cache",
+            ".git",
+            "node_modules",
+        ),
+        skip_file_prefixes=(),
+        skip_path_fragments=("/testdata/", "/fixtures/"),
+        extra_ast_patterns=[
+            ("resource", r"^\s*resource\s+\""),
+            ("data_source", r"^\s*data\s+\""),
+            ("module_call", r"^\s*module\s+\""),
+            ("variable", r"^\s*variable\s+\""),
+            ("dynamic_block", r"^\s*dynamic\s+\""),
+        ],
+    ),
+    "yaml": LanguageConfig(
+        name="yaml",
+        extensions=[".yaml", ".yml"],
+        comment_patterns=[_HASH_COMMENT],
+        string_patterns=[_DOUBLE_QUOTE_STR],
+        function_patterns=[r"^kind:\s*(\w+)"],
+        import_patterns=[],
+        export_patterns=[],
+        complexity_keywords=["if", "else", "range", "with"],
+        complexity_operators=[],
+        nesting_mode="indent",
+        struct_patterns=[],
+        skip_dirs=(
+            ".git",
+            "node_modules",
+            ".terraform",
+        ),
+        skip_file_prefixes=("pnpm-lock",),
+        skip_path_fragments=("/testdata/", "/fixtures/"),
+        extra_ast_patterns=[
+            ("document", r"^---"),
+            ("resource_kind", r"^kind:"),
+            ("template_directive", r"\{\{-?\s*(?:if|range|with|include|define)\b"),
+        ],
+    ),
+    "proto": LanguageConfig(
+        name="proto",
+        extensions=[".proto"],
+        comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT],
+        string_patterns=[_DOUBLE_QUOTE_STR, _SINGLE_QUOTE_STR],
+        function_patterns=[r"^\s*rpc\s+\w+"],
+        import_patterns=[r"^\s*import\s+(?:public\s+|weak\s+)?[\"']([^\"']+)[\"']"],
+        export_patterns=[r"^\s*(?:message|enum|service)\s+(\w+)"],
+        complexity_keywords=["oneof", "stream"],
+        complexity_operators=[],
+        nesting_mode="brace",
+        struct_patterns=[r"^\s*message\s+\w+"],
+        interface_patterns=[r"^\s*service\s+\w+"],
+        skip_dirs=(
+            ".git",
+            "node_modules",
+            "vendor",
+        ),
+        skip_file_prefixes=(),
+        skip_path_fragments=("/testdata/",),
+        extra_ast_patterns=[
+            ("service", r"^\s*service\s+\w+"),
+            ("rpc", r"^\s*rpc\s+\w+"),
+            ("message", r"^\s*message\s+\w+"),
+            ("enum", r"^\s*enum\s+\w+"),
+            ("oneof", r"^\s*oneof\s+\w+"),
+        ],
+    ),
     "rust": LanguageConfig(
         name="rust",
         extensions=[".rs"],
@@ -318,7 +555,7 @@ LANGUAGES = {
     ),
     "c": LanguageConfig(
         name="c",
-        extensions=[".c", ".cpp", ".cc", ".cxx", ".h", ".hpp", ".hxx"],
+        extensions=[".c", ".h"],
         comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT],
         string_patterns=[_DOUBLE_QUOTE_STR],
         function_patterns=[
@@ -349,13 +586,54 @@ LANGUAGES = {
             "external",
         ),
         skip_file_prefixes=("test_",),
-        skip_file_suffixes=("_test.cpp", "_test.c"),
+        skip_file_suffixes=("_test.c",),
         skip_path_fragments=("/test/", "/tests/"),
         extra_ast_patterns=[
             ("macro", r"^\s*#define\b"),
             ("template", r"\btemplate\s*<"),
         ],
     ),
+    "cpp": LanguageConfig(
+        name="cpp",
+        extensions=[".cpp", ".cc", ".cxx", ".c++", ".hpp", ".hh", ".hxx", ".h++"],
+        comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT],
+        string_patterns=[_DOUBLE_QUOTE_STR],
+        function_patterns=[
+            r"^[a-zA-Z_][\w\s*&:<>]+\s+[\w:~]+\s*\([^)]*\)\s*(?:const\s*)?(?:noexcept\s*)?\{",
+        ],
+        import_patterns=[r'^\s*#include\s+[<"]([^>"]+)[>"]'],
+        export_patterns=[
+            r"^(?:extern\s+)?(?:static\s+)?(?:inline\s+)?\w+[\w\s*&]+\s+(\w+)\s*\([^)]*\)\s*\{",
+            r"\bclass\s+(\w+)",
+            r"\bstruct\s+(\w+)\s*\{",
+        ],
+        complexity_keywords=["if", "else", "case", "for", "while", "do", "switch", "catch"],
+        complexity_operators=["&&", r"\|\|", r"\?"],
+        nesting_mode="brace",
+        struct_patterns=[r"\bstruct\s+\w+\s*\{", r"\btypedef\s+struct\b"],
+        interface_patterns=[r"\bclass\s+\w+"],
+        skip_dirs=(
+            "build",
+            "cmake-build",
+            ".git",
+            "node_modules",
+            "venv",
+            ".venv",
+            "__pycache__",
+            "third_party",
+            "vendor",
+            "deps",
+            "external",
+        ),
+        skip_file_prefixes=("test_",),
+        skip_file_suffixes=("_test.cpp", "_test.cc"),
+        skip_path_fragments=("/test/", "/tests/"),
+        extra_ast_patterns=[
+            ("macro", r"^\s*#define\b"),
+            ("template", r"\btemplate\s*<"),
+            ("namespace", r"\bnamespace\s+\w+"),
+        ],
+    ),
     "ruby": LanguageConfig(
         name="ruby",
         extensions=[".rb"],
@@ -401,6 +679,57 @@ LANGUAGES = {
         skip_file_suffixes=("_test.rb", "_spec.rb"),
         skip_path_fragments=("/test/", "/spec/"),
     ),
+    "php": LanguageConfig(
+        name="php",
+        extensions=[".php"],
+        # "#[" opens a PHP 8 attribute, not a comment
+        comment_patterns=[_C_LINE_COMMENT, _C_BLOCK_COMMENT, (r"#(?!\[).*", 0)],
+        string_patterns=[_DOUBLE_QUOTE_STR, _SINGLE_QUOTE_STR],
+        function_patterns=[r"\bfunction\s+&?\w+\s*\("],
+        import_patterns=[
+            r"^use\s+(?:function\s+|const\s+)?\\?([\w\\]+)",
+            r"\b(?:require|include)(?:_once)?\s*\(?\s*['\"]([^'\"]+)['\"]",
+        ],
+        export_patterns=[
+            r"^\s*(?:(?:abstract|final|readonly)\s+)*(?:class|interface|trait|enum)\s+(\w+)",
+            r"^\s*(?:public\s+)?(?:static\s+)?function\s+&?(\w+)\s*\(",
+        ],
+        complexity_keywords=[
+            "if",
+            "elseif",
+            "else",
+            "case",
+            "for",
+            "foreach",
+            "while",
+            "catch",
+            "match",
+        ],
+        complexity_operators=["&&", r"\|\|", r"\?\?", r"\band\b", r"\bor\b"],
+        nesting_mode="brace",
+        struct_patterns=[r"\bclass\s+\w+", r"\btrait\s+\w+", r"\benum\s+\w+"],
+        interface_patterns=[r"\binterface\s+\w+"],
+        skip_dirs=(
+            "vendor",
+            "node_modules",
+            ".git",
+            "cache",
+            "storage",
+            "var",
+            "venv",
+            ".venv",
+            "__pycache__",
+        ),
+        skip_file_prefixes=(),
+        skip_file_suffixes=("Test.php",),
+        skip_path_fragments=("/tests/",),
+        extra_ast_patterns=[
+            ("trait_use", r"^\s*use\s+[A-Z][\w\\]*(?:\s*,\s*[A-Z][\w\\]*)*\s*[;{]"),
+            ("closure", r"\b(?:function|fn)\s*\("),
+            ("attribute", r"^\s*#\[\w+"),
+            ("static_call", r"\b[A-Z]\w*::\w+\s*\("),
+        ],
+    ),
     "universal": LanguageConfig(
         name="universal",
         extensions=[],  # set dynamically by caller
@@ -471,10 +800,6 @@ LANGUAGES = {
 # ── Default source-file extensions for universal scanner ───────────
 
 DEFAULT_SOURCE_EXTENSIONS = [
-    ".scala",
-    ".kt",
-    ".kts",
-    ".swift",
     ".m",
     ".mm",
     ".ex",
@@ -505,7 +830,6 @@ DEFAULT_SOURCE_EXTENSIONS = [
     ".groovy",
     ".gradle",
     ".dart",
-    ".php",
     ".sh",
     ".bash",
     ".zsh",
@@ -538,8 +862,6 @@ DEFAULT_SOURCE_EXTENSIONS = [
     ".ss",
     ".tf",
     ".hcl",
-    ".yaml",
-    ".yml",
     ".toml",
     ".json5",
     ".cmake",
@@ -574,11 +896,98 @@ for _lang_name, _cfg in LANGUAGES.items():
             _EXTENSION_TO_LANGUAGE[_ext] = _lang_name
 
 
-def detect_language(filepath) -> str:
+# ── Files without a source extension ───────────────────────────────
+
+# Build files recognised by name; Dockerfile.<variant> and <name>.mk too
+FILENAME_LANGUAGES: dict[str, str] = {
+    "Dockerfile": "dockerfile",
+    "Containerfile": "dockerfile",
+    "Makefile": "make",
+    "makefile": "make",
+    "GNUmakefile": "make",
+    "Rakefile": "ruby",
+    "Gemfile": "ruby",
+    "Vagrantfile": "ruby",
+}
+
+# Interpreters on a shebang line, without their version (python3.12 -> python)
+SHEBANG_LANGUAGES: dict[str, str] = {
+    "python": "python",
+    "pypy": "python",
+    "node": "javascript",
+    "nodejs": "javascript",
+    "bun": "javascript",
+    "deno": "typescript",
+    "ts-node": "typescript",
+    "tsx": "typescript",
+    "ruby": "ruby",
+    "php": "php",
+    "sh": "shell",
+    "bash": "shell",
+    "dash": "shell",
+    "ksh": "shell",
+    "zsh": "shell",
+    "perl": "perl",
+}
+
+_SHEBANG_BYTES = 256
+_INTERPRETER_RE = _re.compile(r"^([a-z][a-z-]*?)[\d.]*$")
+
+
+def filename_language(filepath) -> str:
+    """Language of a build file recognised by name (Dockerfile, Makefile), or "unknown"."""
+    from pathlib import Path
+
+    name = Path(filepath).name
+    if name in FILENAME_LANGUAGES:
+        return FILENAME_LANGUAGES[name]
+    if name.startswith(("Dockerfile.", "Containerfile.")) or name.endswith(".dockerfile"):
+        return "dockerfile"
+    if name.endswith(".mk"):
+        return "make"
+    return "unknown"
+
+
+def shebang_language(content: str) -> str:
+    """Language named by the shebang line opening *content*, or "unknown".
+
+    ``#!/usr/bin/python3``, ``#!/usr/bin/env node`` and
+    ``#!/usr/bin/env -S deno run`` name their interpreter directly or after
+    env and its options.
+    """
+    if not content.startswith("#!"):
+        return "unknown"
+    words = content[2:].split("\n", 1)[0].split()
+    if words and words[0].rsplit("/", 1)[-1] == "env":
+        words = [w for w in words[1:] if not w.startswith("-") and "=" not in w]
+    if not words:
+        return "unknown"
+    match = _INTERPRETER_RE.match(words[0].rsplit("/", 1)[-1])
+    return SHEBANG_LANGUAGES.get(match[1], "unknown") if match else "unknown"
+
+
+def read_shebang(filepath) -> str:
+    """The first line of *filepath* if it is a shebang line, else ""."""
+    try:
+        with open(filepath, "rb") as f:
+            head = f.read(_SHEBANG_BYTES)
+    except OSError:
+        return ""
+    if not head.startswith(b"#!"):
+        return ""
+    return head.split(b"\n", 1)[0].decode("utf-8", errors="replace")
+
+
+def detect_language(filepath, content: Optional[str] = None) -> str:
     """Detect language from file extension.
 
+    Files without a source extension are recognised by name (Dockerfile,
+    Makefile), and files without any extension by the shebang line opening
+    *content*, when given.
+
     Args:
         filepath: Path object or string
+        content: The file's text, or at least its first line
 
     Returns:
         Language name (e.g., "python", "go") or "unknown"
@@ -587,4 +996,10 @@ def detect_language(filepath) -> str:
 
     path = Path(filepath) if not hasattr(filepath, "suffix") else filepath
     ext = path.suffix.lower()
-    return _EXTENSION_TO_LANGUAGE.get(ext, "unknown")
+    language = _EXTENSION_TO_LANGUAGE.get(ext)
+    if language is not None:
+        return language
+    language = filename_language(path)
+    if language == "unknown" and not ext and content is not None:
+        language = shebang_language(content)
+    return language
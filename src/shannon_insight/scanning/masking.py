@@ -0,0 +1,21 @@
+"""Masking of comments and strings for the regex-based parsers.
+
+The PHP, Scala, Swift and SQL parsers match declarations with regular
+expressions over a masked copy of the file: each comment and string literal
+their mask pattern finds is replaced by ``blank``, so a keyword in a comment
+or a string is never matched, and line numbers and offsets in the masked
+text are those of the source.
+"""
+
+from __future__ import annotations
+
+import re
+
+
+def blank(match: re.Match[str]) -> str:
+    """Spaces in place of *match*, keeping newlines (line numbers) and string quotes."""
+    text = match.group(0)
+    blanked = re.sub(r"[^\n]", " ", text)
+    if text[:1] in ("'", '"'):
+        return text[0] + blanked[1:-1] + text[-1]
+    return blanked
@@ -2,14 +2,17 @@
 
 This module takes tree-sitter parse trees and produces language-agnostic
 FileSyntax objects. It handles the language-specific differences internally.
+Files with syntax errors are parsed around them (see recovery.py).
 """
 
 from __future__ import annotations
 
 import logging
+import re
 from typing import TYPE_CHECKING, Any
 
 from .queries import get_query
+from .recovery import describe, parse_with_recovery
 from .syntax import ClassDef, FileSyntax, FunctionDef, ImportDecl
 from .treesitter_parser import TREE_SITTER_AVAILABLE, TreeSitterParser
 
@@ -18,6 +21,19 @@ if TYPE_CHECKING:
 
 logger = logging.getLogger(__name__)
 
+_PURE_VIRTUAL_RE = re.compile(rb"\bvirtual\b[^;{}]*\)[^;{}]*=\s*0\s*;")
+
+# PHP call nodes, all of which name the callee in a field (or after ``new``)
+_PHP_CALL_TYPES = frozenset(
+    {
+        "function_call_expression",
+        "member_call_expression",
+        "nullsafe_member_call_expression",
+        "scoped_call_expression",
+        "object_creation_expression",
+    }
+)
+
 
 class TreeSitterNormalizer:
     """Converts tree-sitter parse trees to FileSyntax.
@@ -61,9 +77,14 @@ class TreeSitterNormalizer:
             logger.debug(f"Encoding error for {path}, falling back to regex")
             return None
 
-        tree = self._parser.parse(code_bytes, language)
+        parser = self._parser
+        tree, code_bytes, parse_errors = parse_with_recovery(
+            lambda code: parser.parse(code, language), code_bytes
+        )
         if tree is None:
             return None
+        if parse_errors:
+            logger.debug(f"{path}: syntax errors at {describe(parse_errors)}, parsed around them")
 
         functions = self._extract_functions(tree, code_bytes, language)
         classes = self._extract_classes(tree, code_bytes, language)
@@ -83,6 +104,7 @@ class TreeSitterNormalizer:
             language=language,
             has_main_guard=has_main,
             mtime=mtime,
+            parse_errors=parse_errors,
             _lines=lines,
             _tokens=tokens,
             _complexity=complexity,
@@ -135,7 +157,10 @@ class TreeSitterNormalizer:
             "method_declaration",
             "method_definition",
             "function_item",
+            "macro_definition",
             "method",
+            "protocol_function_declaration",
+            "init_declaration",
         )
 
         if capture_name.endswith(".name"):
@@ -168,16 +193,41 @@ class TreeSitterNormalizer:
     ) -> FunctionDef | None:
         """Convert a tree-sitter node to FunctionDef."""
         # Find function name
-        name = self._find_child_text(node, "identifier", code_bytes)
+        if language == "rust":
+            name = self._field_text(node, "name")
+        elif language == "kotlin":
+            # The receiver of an extension function is a type, so the name is the identifier
+            name = self._direct_child_text(node, "simple_identifier")
+        elif language in ("c", "cpp"):
+            # Parameters hold identifiers too; a method's name may be a field_identifier
+            name = self._c_function_name(node)
+        elif language in ("php", "scala"):
+            name = self._field_text(node, "name")
+        elif language == "go":
+            # The receiver (s *Stack[T]) and type parameters [T any] hold identifiers too
+            name = self._field_text(node, "name")
+        elif language == "swift":
+            name = "init" if node.type == "init_declaration" else self._field_text(node, "name")
+        else:
+            name = self._find_child_text(node, "identifier", code_bytes)
         if name is None:
             name = self._find_child_text(node, "field_identifier", code_bytes)
         if name is None:
             return None
 
         # Calculate tokens
-        body_node = self._find_child_by_type(
-            node, ("block", "compound_statement", "statement_block")
-        )
+        if node.type == "macro_definition":
+            body_node = node  # macro_rules! arms have no block; the whole macro is body
+        elif language == "kotlin":
+            # Block or expression body; abstract and interface funs have none
+            body_node = self._find_child_by_type(node, ("function_body",))
+        elif language in ("scala", "swift"):
+            # Scala abstract defs and Swift protocol requirements have no body
+            body_node = node.child_by_field_name("body")
+        else:
+            body_node = self._find_child_by_type(
+                node, ("block", "compound_statement", "statement_block")
+            )
         body_tokens = self._count_tokens(body_node, code_bytes) if body_node else 0
 
         signature_tokens = self._count_signature_tokens(node, body_node, code_bytes)
@@ -230,7 +280,8 @@ class TreeSitterNormalizer:
             if class_node is None:
                 continue
 
-            node_id = id(class_node)
+            # Dedup by position, as for functions: each capture yields its own node object
+            node_id = class_node.start_byte
             if node_id in processed_nodes:
                 continue
             processed_nodes.add(node_id)
@@ -239,8 +290,140 @@ class TreeSitterNormalizer:
             if cls is not None:
                 classes.append(cls)
 
+        if language == "rust":
+            self._attach_rust_impls(tree.root_node, classes, code_bytes)
+
         return classes
 
+    def _attach_rust_impls(self, root: Any, classes: list[ClassDef], code_bytes: bytes) -> None:
+        """Add impl block methods to their Rust type, and implemented traits as its bases.
+
+        Rust declares methods apart from the type, in ``impl Type`` and
+        ``impl Trait for Type`` blocks. Impls of types defined in other files
+        are skipped; their methods still count as functions of this file.
+        """
+        by_name = {cls.name: cls for cls in classes}
+        stack = [root]
+        while stack:
+            node = stack.pop()
+            stack.extend(reversed(node.children))
+            if node.type != "impl_item":
+                continue
+            cls = by_name.get(self._rust_type_name(node.child_by_field_name("type")) or "")
+            if cls is None:
+                continue
+            trait = self._rust_type_name(node.child_by_field_name("trait"))
+            if trait and trait not in cls.bases:
+                cls.bases.append(trait)
+            body = node.child_by_field_name("body")
+            for item in body.children if body is not None else []:
+                if item.type == "function_item":
+                    method = self._node_to_function(item, code_bytes, "rust", [])
+                    if method is not None:
+                        cls.methods.append(method)
+
+    def _rust_members(self, node: Any, code_bytes: bytes) -> tuple[list[FunctionDef], list[str]]:
+        """Trait methods (default or signature-only) and struct field names."""
+        methods: list[FunctionDef] = []
+        fields: list[str] = []
+        body = node.child_by_field_name("body")
+        for item in body.children if body is not None else []:
+            if item.type in ("function_item", "function_signature_item"):
+                method = self._node_to_function(item, code_bytes, "rust", [])
+                if method is not None:
+                    methods.append(method)
+            elif item.type == "field_declaration":
+                field = self._field_text(item, "name")
+                if field:
+                    fields.append(field)
+        return methods, fields
+
+    def _kotlin_members(
+        self, node: Any, code_bytes: bytes
+    ) -> tuple[list[FunctionDef], list[str]]:
+        """Member funs, and properties declared in the body or primary constructor."""
+        methods: list[FunctionDef] = []
+        fields: list[str] = []
+        for child in node.children:
+            if child.type == "primary_constructor":
+                for param in child.children:
+                    # Only val/var constructor parameters become properties
+                    if param.type == "class_parameter" and any(
+                        c.type in ("val", "var") for c in param.children
+                    ):
+                        name = self._direct_child_text(param, "simple_identifier")
+                        if name:
+                            fields.append(name)
+            elif child.type in ("class_body", "enum_class_body"):
+                for item in child.children:
+                    if item.type == "function_declaration":
+                        method = self._node_to_function(item, code_bytes, "kotlin", [])
+                        if method is not None:
+                            methods.append(method)
+                    elif item.type == "property_declaration":
+                        decl = self._find_child_by_type(item, ("variable_declaration",))
+                        name = self._direct_child_text(decl, "simple_identifier") if decl else None
+                        if name:
+                            fields.append(name)
+        return methods, fields
+
+    def _c_function_name(self, node: Any) -> str | None:
+        """Name of a C/C++ function: ``f``, ``reset`` for ``Parser::reset``, ``~Parser``."""
+        declarator = node.child_by_field_name("declarator")
+        # Pointer and reference return types wrap the function_declarator
+        while declarator is not None and declarator.type != "function_declarator":
+            inner = declarator.child_by_field_name("declarator")
+            if inner is None and declarator.named_children:
+                inner = declarator.named_children[-1]  # C++ reference_declarator has no field
+            declarator = inner
+        name = declarator.child_by_field_name("declarator") if declarator is not None else None
+        while name is not None and name.type in ("qualified_identifier", "template_function"):
+            name = name.child_by_field_name("name")
+        if name is None or not name.text:
+            return None
+        return str(name.text.decode("utf-8", errors="ignore"))
+
+    def _c_members(
+        self, node: Any, code_bytes: bytes, language: str
+    ) -> tuple[list[FunctionDef], list[str]]:
+        """Field names, and methods defined in a C++ class body."""
+        methods: list[FunctionDef] = []
+        fields: list[str] = []
+        spec = node.child_by_field_name("type") if node.type == "type_definition" else node
+        body = spec.child_by_field_name("body") if spec is not None else None
+        if body is None or body.type != "field_declaration_list":
+            return methods, fields  # enums list enumerators, not fields
+        for item in body.children:
+            if item.type == "function_definition":
+                method = self._node_to_function(item, code_bytes, language, [])
+                if method is not None:
+                    methods.append(method)
+            elif item.type == "field_declaration":
+                # int x, *y, z[4];  method declarations carry a function_declarator
+                for declarator in item.children_by_field_name("declarator"):
+                    while declarator.type in (
+                        "pointer_declarator",
+                        "array_declarator",
+                        "reference_declarator",
+                    ):
+                        inner = declarator.child_by_field_name("declarator")
+                        declarator = inner if inner is not None else declarator.named_children[-1]
+                    if declarator.type == "field_identifier" and declarator.text:
+                        fields.append(declarator.text.decode("utf-8", errors="ignore"))
+        return methods, fields
+
+    def _rust_type_name(self, node: Any | None) -> str | None:
+        """Bare name of a Rust type: ``Foo`` for Foo, Foo<T> or crate::Foo."""
+        if node is None:
+            return None
+        if node.type == "generic_type":
+            return self._rust_type_name(node.child_by_field_name("type"))
+        if node.type == "scoped_type_identifier":
+            return self._field_text(node, "name")
+        if node.text is None:
+            return None
+        return str(node.text.decode("utf-8", errors="ignore"))
+
     def _get_class_node(self, node: Any, capture_name: str, language: str) -> Any | None:
         """Get the class definition node from a capture."""
         class_types = {
@@ -254,9 +437,30 @@ class TreeSitterNormalizer:
             "javascript": ("class_declaration",),
             "java": ("class_declaration", "interface_declaration", "enum_declaration"),
             "rust": ("struct_item", "enum_item", "trait_item"),
+            "kotlin": ("class_declaration", "object_declaration"),
+            "scala": (
+                "class_definition",
+                "object_definition",
+                "trait_definition",
+                "enum_definition",
+            ),
+            # class_declaration covers struct, enum, actor and extension too
+            "swift": ("class_declaration", "protocol_declaration"),
             "ruby": ("class", "module"),
-            "c": ("struct_specifier", "union_specifier", "enum_specifier"),
-            "cpp": ("struct_specifier", "class_specifier", "union_specifier", "enum_specifier"),
+            "php": (
+                "class_declaration",
+                "interface_declaration",
+                "trait_declaration",
+                "enum_declaration",
+            ),
+            "c": ("struct_specifier", "union_specifier", "enum_specifier", "type_definition"),
+            "cpp": (
+                "struct_specifier",
+                "class_specifier",
+                "union_specifier",
+                "enum_specifier",
+                "type_definition",
+            ),
         }
         allowed = class_types.get(language, ())
 
@@ -278,7 +482,28 @@ class TreeSitterNormalizer:
     def _node_to_class(self, node: Any, code_bytes: bytes, language: str) -> ClassDef | None:
         """Convert a tree-sitter node to ClassDef."""
         # Find class name
-        name = self._find_child_text(node, "identifier", code_bytes)
+        if language == "rust":
+            # Field types may hold identifiers (std::string::String), so use the name field
+            name = self._field_text(node, "name")
+        elif language == "kotlin":
+            # Annotations (@Serializable) precede the name as types too
+            name = self._direct_child_text(node, "type_identifier")
+        elif language in ("c", "cpp"):
+            # typedef struct { ... } Name; is named by its declarator
+            field = "declarator" if node.type == "type_definition" else "name"
+            name = self._field_text(node, field)
+        elif language in ("ruby", "php", "scala"):
+            # The body holds identifiers too; Admin::User is a scope_resolution
+            name = self._field_text(node, "name")
+        elif language == "go":
+            # Type parameters (Stack[T any]) and array lengths ([N]int) are identifiers
+            name = self._field_text(node, "name")
+        elif language == "swift":
+            # extension Outer.Inner<T> extends Inner
+            name = self._field_text(node, "name")
+            name = name.split("<")[0].split(".")[-1].strip() if name else None
+        else:
+            name = self._find_child_text(node, "identifier", code_bytes)
         if name is None:
             name = self._find_child_text(node, "type_identifier", code_bytes)
         if name is None:
@@ -292,9 +517,15 @@ class TreeSitterNormalizer:
         # Detect if abstract
         is_abstract = self._detect_abstract_class(node, code_bytes, language)
 
-        # Methods would require nested parsing - skip for now
+        # Methods would require nested parsing - skip for now (except Rust and Kotlin)
         methods: list[FunctionDef] = []
         fields: list[str] = []
+        if language == "rust":
+            methods, fields = self._rust_members(node, code_bytes)
+        elif language == "kotlin":
+            methods, fields = self._kotlin_members(node, code_bytes)
+        elif language in ("c", "cpp"):
+            methods, fields = self._c_members(node, code_bytes, language)
 
         return ClassDef(
             name=name,
@@ -353,6 +584,20 @@ class TreeSitterNormalizer:
                 return result
         return None
 
+    def _field_text(self, node: Any, field: str) -> str | None:
+        """Text of the child in grammar field *field* (e.g. a declaration's name)."""
+        child = node.child_by_field_name(field)
+        if child is None or not child.text:
+            return None
+        return str(child.text.decode("utf-8", errors="ignore"))
+
+    def _direct_child_text(self, node: Any, child_type: str) -> str | None:
+        """Text of the first direct child of type *child_type* (no recursion)."""
+        for child in node.children:
+            if child.type == child_type and child.text:
+                return str(child.text.decode("utf-8", errors="ignore"))
+        return None
+
     def _find_child_by_type(self, node: Any, types: tuple[str, ...]) -> Any | None:
         """Find first child matching one of the types."""
         for child in node.children:
@@ -397,9 +642,18 @@ class TreeSitterNormalizer:
             "with_statement",
             "match_statement",
             "for_in_statement",
+            "foreach_statement",
             "if_expression",
             "while_expression",
             "loop_expression",
+            "for_expression",
+            "match_expression",
+            "do_statement",
+            "when_expression",
+            "do_while_statement",
+            "try_expression",
+            "guard_statement",
+            "repeat_while_statement",
         }
 
         def count_depth(n: Any, current_depth: int) -> int:
@@ -418,17 +672,61 @@ class TreeSitterNormalizer:
         params: list[str] = []
 
         param_node = self._find_child_by_type(
-            node, ("parameters", "formal_parameters", "parameter_list", "method_parameters")
+            node,
+            (
+                "parameters",
+                "formal_parameters",
+                "parameter_list",
+                "method_parameters",
+                "function_value_parameters",
+            ),
         )
+        if language == "swift":
+            # Parameters are direct children; the name is the internal one (x in with x: Int)
+            for child in node.children:
+                name = self._field_text(child, "name") if child.type == "parameter" else None
+                if name:
+                    params.append(name)
+            return params
+
         if param_node is None:
             return params
 
+        if language == "scala":
+            # Curried defs have several lists: def fold(z: A)(op: (A, B) => A)
+            for child in node.children:
+                if child.type != "parameters":
+                    continue
+                for param in child.named_children:
+                    name = self._field_text(param, "name") if param.type == "parameter" else None
+                    if name:
+                        params.append(name)
+            return params
+
+        if language == "go":
+            # Not the receiver or type parameters, which are parameter lists too;
+            # a, b int declares two
+            param_node = node.child_by_field_name("parameters")
+            for declaration in param_node.named_children if param_node is not None else []:
+                for name in declaration.children_by_field_name("name"):
+                    if name.text:
+                        params.append(name.text.decode("utf-8", errors="ignore"))
+            return params
+
+        if language == "php":
+            # simple, variadic and promoted parameters all name a $variable
+            for child in param_node.named_children:
+                variable = child.child_by_field_name("name")
+                if variable is not None and variable.text:
+                    params.append(variable.text.decode("utf-8", errors="ignore").lstrip("$"))
+            return params
+
         for child in param_node.children:
             if child.type == "identifier":
                 if child.text:
                     params.append(child.text.decode("utf-8", errors="ignore"))
-            # Handle typed parameters
-            name_node = self._find_child_by_type(child, ("identifier",))
+            # Handle typed parameters (Kotlin names are simple_identifiers)
+            name_node = self._find_child_by_type(child, ("identifier", "simple_identifier"))
             if name_node and name_node.text:
                 param_name = name_node.text.decode("utf-8", errors="ignore")
                 if param_name not in params:
@@ -446,12 +744,48 @@ class TreeSitterNormalizer:
         targets: list[str] = []
 
         def collect_calls(n: Any) -> None:
-            if n.type in ("call", "call_expression", "method_invocation"):
+            if n.type in _PHP_CALL_TYPES:
+                # PHP names the callee: helper(), $this->save(), Cache::get(), new Post
+                callee = n.child_by_field_name("name") or n.child_by_field_name("function")
+                if callee is None and n.type == "object_creation_expression":
+                    named = [c for c in n.named_children if c.type in ("name", "qualified_name")]
+                    callee = named[0] if named else None
+                if callee is not None and callee.type in ("name", "qualified_name") and callee.text:
+                    targets.append(callee.text.decode("utf-8", errors="ignore").split("\\")[-1])
+            if language == "go" and n.type == "call_expression":
+                # Instantiations (Map[int](xs), slices.Map[int](xs)) call the generic function
+                name = self._go_callee(n.child_by_field_name("function"))
+                if name:
+                    targets.append(name)
+            elif n.type in ("call", "call_expression", "method_invocation", "macro_invocation"):
                 # Try to get function/method name
                 for child in n.children:
-                    if child.type == "identifier" and child.text:
+                    if child.type in ("identifier", "simple_identifier") and child.text:
                         targets.append(child.text.decode("utf-8", errors="ignore"))
                         break
+                    if child.type == "navigation_expression":
+                        # Kotlin receiver.method(): the name is in the last navigation_suffix
+                        suffix = child.children[-1] if child.children else None
+                        name = (
+                            self._direct_child_text(suffix, "simple_identifier")
+                            if suffix is not None and suffix.type == "navigation_suffix"
+                            else None
+                        )
+                        if name:
+                            targets.append(name)
+                        break
+                    if child.type == "field_expression" and language == "scala":
+                        # repo.save(user): the field, not the receiver
+                        name = self._field_text(child, "field")
+                        if name:
+                            targets.append(name)
+                        break
+                    if child.type == "scoped_identifier":
+                        # Rust path call (Type::new, mod::helper): the last segment
+                        name = self._field_text(child, "name")
+                        if name:
+                            targets.append(name)
+                        break
                     if child.type in ("attribute", "member_expression", "field_expression"):
                         # Get the method name
                         for gc in child.children:
@@ -466,6 +800,26 @@ class TreeSitterNormalizer:
         collect_calls(body_node)
         return targets
 
+    def _go_callee(self, node: Any | None) -> str | None:
+        """Name a Go call resolves to: Map for Map(xs), Map[int](xs) and slices.Map[int](xs)."""
+        while node is not None:
+            if node.type in ("identifier", "type_identifier", "field_identifier"):
+                return str(node.text.decode("utf-8", errors="ignore")) if node.text else None
+            if node.type == "selector_expression":
+                node = node.child_by_field_name("field")
+            elif node.type == "qualified_type":
+                node = node.child_by_field_name("name")
+            elif node.type == "index_expression":
+                # Older grammars read an explicit instantiation as indexing
+                node = node.child_by_field_name("operand")
+            elif node.type == "generic_type":
+                node = node.child_by_field_name("type")
+            elif node.type == "parenthesized_expression":
+                node = node.named_children[0] if node.named_children else None
+            else:
+                return None  # func literals, calls returning funcs
+        return None
+
     def _extract_decorators(self, node: Any, code_bytes: bytes, language: str) -> list[str]:
         """Extract decorator names (Python only)."""
         if language != "python":
@@ -530,6 +884,49 @@ class TreeSitterNormalizer:
                                 if ggc.type == "identifier" and ggc.text:
                                     bases.append(ggc.text.decode("utf-8", errors="ignore"))
 
+        elif language == "rust":
+            # Supertraits: trait Service: Clone + Send
+            bounds = node.child_by_field_name("bounds") if node.type == "trait_item" else None
+            for child in bounds.children if bounds is not None else []:
+                if child.is_named and child.type != "lifetime":
+                    name = self._rust_type_name(child)
+                    if name:
+                        bases.append(name)
+
+        elif language == "kotlin":
+            # class Repo : Base(), Closeable -- constructor calls and interfaces alike
+            for child in node.children:
+                if child.type != "delegation_specifier":
+                    continue
+                user_type = self._find_child_by_type(child, ("user_type",))
+                # Qualified types (java.io.Closeable) end in the bare name
+                names = [
+                    c.text.decode("utf-8", errors="ignore")
+                    for c in (user_type.children if user_type is not None else [])
+                    if c.type == "type_identifier" and c.text
+                ]
+                if names:
+                    bases.append(names[-1])
+
+        elif language == "cpp":
+            # class Parser : public Base, private util::Noncopyable
+            for child in node.children:
+                if child.type != "base_class_clause":
+                    continue
+                for gc in child.named_children:
+                    base = gc
+                    while base.type in ("qualified_identifier", "template_type"):
+                        base = base.child_by_field_name("name")
+                    if base is not None and base.type == "type_identifier" and base.text:
+                        bases.append(base.text.decode("utf-8", errors="ignore"))
+
+        elif language == "ruby":
+            # class Post < ApplicationRecord
+            superclass = node.child_by_field_name("superclass")
+            for child in superclass.named_children if superclass is not None else []:
+                if child.text:
+                    bases.append(child.text.decode("utf-8", errors="ignore"))
+
         elif language == "java":
             for child in node.children:
                 if child.type == "superclass":
@@ -558,6 +955,21 @@ class TreeSitterNormalizer:
         if language == "rust":
             return bool(node.type == "trait_item")
 
+        if language == "swift":
+            return bool(node.type == "protocol_declaration")
+
+        if language == "cpp" and node.type == "class_specifier" and node.text:
+            # A pure virtual method: virtual void run() = 0;
+            return bool(_PURE_VIRTUAL_RE.search(node.text))
+
+        if language == "kotlin":
+            for child in node.children:
+                if child.type == "interface":
+                    return True
+                if child.type == "modifiers" and child.text:
+                    words = child.text.split()
+                    return b"abstract" in words or b"sealed" in words
+
         return False
 
     def _extract_import_source(
@@ -604,3 +1016,4 @@ class TreeSitterNormalizer:
         # Sum of nesting depths + 1 per function, averaged
         total_complexity = sum(fn.nesting_depth + 1 for fn in functions)
         return total_complexity / len(functions)
+
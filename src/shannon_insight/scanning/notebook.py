@@ -0,0 +1,139 @@
+"""Jupyter notebooks as Python modules.
+
+A notebook (``.ipynb``) is JSON: code cells hold the program, markdown cells
+the prose, and outputs (tables, base64 plots) most of the bytes.
+``notebook_source`` keeps the code cells only, each under a ``# %% [N]``
+marker line (the percent format editors and jupytext read), N being the
+cell's index in the notebook, markdown cells included, counting from 0. The
+result parses as an ordinary Python module, so a notebook is analyzed as
+one: its functions, imports and file metrics are those of its code.
+
+IPython syntax that is not Python is commented out line for line, so line
+numbers stay put: ``%magic`` and ``!shell`` lines, ``files = !ls``
+captures, ``obj?`` help lines, and the whole of a cell run by a cell magic
+(``%%bash``, ``%%sql``) whose body is not Python.
+
+``annotate_notebook`` runs after the Python parser and records the cell
+each function starts in; ``cell_at`` maps any line back to its cell.
+"""
+
+from __future__ import annotations
+
+import bisect
+import json
+import re
+from typing import Any, Optional
+
+from .syntax import FileSyntax
+
+NOTEBOOK_SUFFIX = ".ipynb"
+
+_CELL_MARKER = "# %% [{}]"
+_CELL_MARKER_RE = re.compile(r"^# %% \[(\d+)\]$")
+_IPYTHON_LINE_RE = re.compile(r"^\s*(?:[%!?]|[\w.]+\?\??\s*$|[\w.,\s]+=\s*[%!])")
+_CELL_MAGIC_RE = re.compile(r"^\s*%%(\w+)")
+# Cell magics whose body is still Python
+_PYTHON_CELL_MAGICS = frozenset({"time", "timeit", "capture", "prun", "debug"})
+
+
+def is_notebook(path: Any) -> bool:
+    return str(path).lower().endswith(NOTEBOOK_SUFFIX)
+
+
+def notebook_source(content: str) -> Optional[str]:
+    """Python source of a notebook's code cells.
+
+    None when *content* is not a notebook, or the notebook runs another
+    kernel language (R, Julia).
+    """
+    try:
+        notebook = json.loads(content)
+    except ValueError:
+        return None
+    if not isinstance(notebook, dict) or not _is_python(notebook):
+        return None
+    lines: list[str] = []
+    for index, cell in enumerate(_cells(notebook)):
+        if isinstance(cell, dict) and cell.get("cell_type") == "code":
+            lines.append(_CELL_MARKER.format(index))
+            lines.extend(_python_lines(_text(cell.get("source", cell.get("input")))))
+    return "\n".join(lines) + "\n" if lines else ""
+
+
+def _cells(notebook: dict) -> list:
+    if "cells" in notebook:
+        cells = notebook["cells"]
+        return cells if isinstance(cells, list) else []
+    # nbformat 3: cells inside worksheets
+    return [
+        cell
+        for sheet in notebook.get("worksheets") or []
+        if isinstance(sheet, dict)
+        for cell in sheet.get("cells") or []
+    ]
+
+
+def _is_python(notebook: dict) -> bool:
+    metadata = notebook.get("metadata")
+    if not isinstance(metadata, dict):
+        return True
+    for section, key in (("kernelspec", "language"), ("language_info", "name")):
+        value = metadata.get(section)
+        if isinstance(value, dict) and isinstance(value.get(key), str):
+            return value[key].lower().startswith("python")
+    return True
+
+
+def _text(source: Any) -> list[str]:
+    if isinstance(source, list):
+        source = "".join(s for s in source if isinstance(s, str))
+    return source.splitlines() if isinstance(source, str) else []
+
+
+def _python_lines(lines: list[str]) -> list[str]:
+    first = next((line for line in lines if line.strip()), "")
+    magic = _CELL_MAGIC_RE.match(first)
+    if magic is not None and magic[1] not in _PYTHON_CELL_MAGICS:
+        return ["# " + line for line in lines]
+    return [_comment(line) if _IPYTHON_LINE_RE.match(line) else line for line in lines]
+
+
+def _comment(line: str) -> str:
+    indent = len(line) - len(line.lstrip())
+    return line[:indent] + "# " + line[indent:]
+
+
+def _markers(source: str) -> list[tuple[int, int]]:
+    """(line number, cell index) of every cell marker, in order."""
+    return [
+        (number, int(match[1]))
+        for number, line in enumerate(source.splitlines(), 1)
+        if (match := _CELL_MARKER_RE.match(line))
+    ]
+
+
+def _locate(markers: list[tuple[int, int]], line: int) -> Optional[tuple[int, int]]:
+    position = bisect.bisect_left(markers, (line, -1)) - 1
+    if position < 0:
+        return None
+    number, index = markers[position]
+    return index, line - number
+
+
+def cell_at(source: str, line: int) -> Optional[tuple[int, int]]:
+    """(cell index, line within the cell, from 1) of a line of ``notebook_source``.
+
+    None for a line outside every cell.
+    """
+    return _locate(_markers(source), line)
+
+
+def annotate_notebook(syntax: FileSyntax, source: str) -> None:
+    """Set the cell of every function and method parsed from *source*."""
+    markers = _markers(source)
+    functions = list(syntax.functions)
+    for cls in syntax.classes:
+        functions.extend(cls.methods)
+    for fn in functions:
+        located = _locate(markers, fn.start_line)
+        fn.cell = located[0] if located is not None else None
@@ -0,0 +1,329 @@
+"""PHP namespaces, class members and class references, recovered from source text.
+
+PHP resolves class names against the current namespace and the file's
+``use`` imports: ``Post`` in ``namespace App\\Models;`` is
+``App\\Models\\Post``, and ``new Carbon`` after ``use Carbon\\Carbon;`` is
+``Carbon\\Carbon``. ``annotate_php`` runs after either parser and resolves
+names the same way:
+
+    imports     ``use`` statements (aliases and group uses included) and the
+                classes named by extends / implements, trait ``use``,
+                ``new``, ``X::``, ``instanceof``, ``catch`` and type
+                declarations, all as fully qualified names
+    includes    require / include (and ``_once``) of literal paths; paths
+                after ``__DIR__ .`` are relative to the including file
+    classes     methods are attached to their class, interface, trait or
+                enum; properties (promoted constructor parameters too) are
+                its fields, and extends / implements its bases
+
+Fully qualified names resolve through the PSR-4 convention in the graph
+builder: ``App\\Models\\User`` lives in ``Models/User.php`` under the
+directory mapped to ``App\\``.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+
+from .masking import blank
+from .syntax import ClassDef, FileSyntax, ImportDecl
+
+# Comments, string literals and inline HTML (outside <?php ... ?>)
+_MASK_RE = re.compile(
+    r"""\A(?!<\?).*?(?=<\?(?:php\b|=)|\Z)"""
+    r"""|'(?:\\.|[^'\\])*'|"(?:\\.|[^"\\])*"|/\*.*?\*/|//[^\n]*|#(?!\[)[^\n]*"""
+    r"""|\?>.*?(?=<\?(?:php\b|=)|\Z)""",
+    re.DOTALL,
+)
+_NAMESPACE_RE = re.compile(r"^[ \t]*namespace\s+([\w\\]+)?\s*[;{]", re.M)
+_USE_RE = re.compile(r"^[ \t]*use\s+(?!\()([^;{]+(?:\{[^}]*\})?)\s*;", re.M)
+_USE_ITEM_RE = re.compile(r"^(?:(function|const)\s+)?\\?([\w\\]+?)(?:\s+as\s+(\w+))?$", re.I)
+_CLASS_RE = re.compile(
+    r"^[ \t]*((?:(?:abstract|final|readonly)\s+)*)"
+    r"(class|interface|trait|enum)\s+(\w+)([^{;]*)\{",
+    re.M | re.I,
+)
+_EXTENDS_RE = re.compile(r"\bextends\s+(.+?)(?=\s+implements\b|$)", re.I | re.S)
+_IMPLEMENTS_RE = re.compile(r"\bimplements\s+(.+)$", re.I | re.S)
+_TRAIT_USE_RE = re.compile(r"^\s*use\s+([\\\w\s,]+?)\s*[;{]")
+_PROPERTY_RE = re.compile(
+    r"^\s*(?:(?:public|protected|private|var|static|readonly)\s+)+(?:\??[\\\w|]+\s+)?\$(\w+)",
+    re.I,
+)
+_PROMOTED_RE = re.compile(
+    r"\b(?:public|protected|private)\s+(?:readonly\s+)?(?:\??[\\\w|]+\s+)?\$(\w+)", re.I
+)
+_CONSTRUCTOR_RE = re.compile(r"\bfunction\s+__construct\s*\(([^)]*)\)", re.I)
+
+_NAME = r"\\?[A-Za-z_][\w\\]*"
+_REFERENCE_RES = (
+    re.compile(rf"\bnew\s+({_NAME})", re.I),
+    re.compile(rf"(?<![\w\\$>])({_NAME})\s*::"),
+    re.compile(rf"\binstanceof\s+({_NAME})", re.I),
+)
+_CATCH_RE = re.compile(r"\bcatch\s*\(([^)$]*)", re.I)
+_SIGNATURE_RE = re.compile(
+    r"\bfunction\s*&?\s*\w*\s*\(([^)]*)\)(?:\s*:\s*(\??[\\\w|&]+))?", re.I
+)
+_PARAM_TYPE_RE = re.compile(r"(\??[\\\w|&]+)\s+&?\s*(?:\.\.\.\s*)?\$")
+_INCLUDE_RE = re.compile(
+    r"\b(?:require|include)(?:_once)?\b\s*\(?\s*"
+    r"(__DIR__\s*\.\s*|dirname\(\s*__FILE__\s*\)\s*\.\s*)?(['\"])([^'\"$]+)\2",
+    re.I,
+)
+
+# Names that are types or scopes, never classes (new class is anonymous)
+_RESERVED = frozenset(
+    {
+        "class",
+        "self",
+        "static",
+        "parent",
+        "array",
+        "callable",
+        "iterable",
+        "object",
+        "mixed",
+        "void",
+        "null",
+        "never",
+        "false",
+        "true",
+        "bool",
+        "boolean",
+        "int",
+        "integer",
+        "float",
+        "double",
+        "string",
+        "resource",
+    }
+)
+
+
+@dataclass
+class _ClassScope:
+    """A class, interface, trait or enum body found in the text."""
+
+    kind: str
+    name: str
+    modifiers: str
+    bases: list[str]
+    start_line: int
+    end_line: int
+    body: tuple[int, int]  # offsets of the body between its braces
+    fields: list[str] = field(default_factory=list)
+    traits: list[str] = field(default_factory=list)
+
+
+def annotate_php(syntax: FileSyntax, content: str) -> None:
+    """Rebuild *syntax*'s imports as fully qualified names and fill in class members."""
+    masked = _MASK_RE.sub(blank, content)
+    namespaces = [
+        (m.start(), (m.group(1) or "").strip("\\")) for m in _NAMESPACE_RE.finditer(masked)
+    ]
+    scopes = _find_classes(masked)
+
+    aliases: dict[str, str] = {}  # lowercased alias -> fully qualified class name
+    declared: list[str] = []
+    for match in _USE_RE.finditer(masked):
+        if any(s.body[0] <= match.start() < s.body[1] for s in scopes):
+            continue  # trait use
+        for kind, name, alias in _use_items(match.group(1)):
+            if kind is None:  # functions and constants are not classes
+                aliases[(alias or name.rsplit("\\", 1)[-1]).lower()] = name
+                declared.append(name)
+
+    def qualify(name: str, offset: int) -> str | None:
+        return _qualify(name, _namespace_at(namespaces, offset), aliases)
+
+    own = {qualify(s.name, s.body[0]) for s in scopes}
+    imports: list[ImportDecl] = []
+    seen: set[str] = set()
+
+    def add(source: str | None, names: list[str]) -> None:
+        if source and source.lower() not in seen and source not in own:
+            seen.add(source.lower())
+            imports.append(ImportDecl(source=source, names=names))
+
+    def add_class(name: str | None) -> None:
+        add(name, [name.rsplit("\\", 1)[-1]] if name else [])
+
+    for name in declared:
+        add_class(name)
+    for scope in scopes:
+        for name in scope.bases + scope.traits:
+            add_class(qualify(name, scope.body[0]))
+    for offset, name in _references(masked):
+        add_class(qualify(name, offset))
+    for match in _INCLUDE_RE.finditer(content):
+        if masked[match.start()] != " ":  # not in a comment or string
+            relative, path = match.group(1), match.group(3)
+            add("./" + path.lstrip("/") if relative else path, [])
+    syntax.imports = imports
+
+    _attach_members(syntax, scopes)
+
+
+def _find_classes(masked: str) -> list[_ClassScope]:
+    """Class-like declarations with their extents, bases, properties and traits."""
+    scopes = []
+    for match in _CLASS_RE.finditer(masked):
+        modifiers, kind, name, head = match.groups()
+        open_brace = match.end() - 1
+        close_brace = _matching_brace(masked, open_brace)
+        bases = []
+        extends = _EXTENDS_RE.search(head)
+        implements = _IMPLEMENTS_RE.search(head)
+        for clause in (extends, implements):
+            if clause:
+                bases += [b.strip() for b in clause.group(1).split(",") if b.strip()]
+        scope = _ClassScope(
+            kind=kind.lower(),
+            name=name,
+            modifiers=modifiers.lower(),
+            bases=bases,
+            start_line=masked.count("\n", 0, match.start()) + 1,
+            end_line=masked.count("\n", 0, close_brace) + 1,
+            body=(open_brace + 1, close_brace),
+        )
+        _read_body(masked, scope)
+        scopes.append(scope)
+    return scopes
+
+
+def _matching_brace(text: str, open_brace: int) -> int:
+    depth = 0
+    for i in range(open_brace, len(text)):
+        if text[i] == "{":
+            depth += 1
+        elif text[i] == "}":
+            depth -= 1
+            if depth == 0:
+                return i
+    return len(text)
+
+
+def _read_body(masked: str, scope: _ClassScope) -> None:
+    """Trait uses and properties: the statements directly in the class body."""
+    start, end = scope.body
+    depth = 0
+    statement_start = start
+    for i in range(start, end):
+        char = masked[i]
+        if char in "{;" and depth == 0:
+            _read_member(masked[statement_start : i + 1], scope)
+        if char == "{":
+            depth += 1
+        elif char == "}":
+            depth -= 1
+        if char in "{};" and depth == 0:
+            statement_start = i + 1
+    constructor = _CONSTRUCTOR_RE.search(masked, start, end)
+    if constructor:
+        for name in _PROMOTED_RE.findall(constructor.group(1)):
+            if name not in scope.fields:
+                scope.fields.append(name)
+
+
+def _read_member(statement: str, scope: _ClassScope) -> None:
+    statement = re.sub(r"^\s*#\[.*$", "", statement, flags=re.M)  # attributes
+    trait_use = _TRAIT_USE_RE.match(statement)
+    if trait_use:
+        scope.traits += [t.strip() for t in trait_use.group(1).split(",") if t.strip()]
+        return
+    prop = _PROPERTY_RE.match(statement)
+    if prop:
+        # public $a = 1, $b; declares both
+        for name in [prop.group(1)] + re.findall(r",\s*\$(\w+)", statement):
+            if name not in scope.fields:
+                scope.fields.append(name)
+
+
+def _use_items(clause: str) -> list[tuple[str | None, str, str | None]]:
+    """(function/const or None, name, alias) of each name a use statement imports."""
+    clause = " ".join(clause.split())
+    kind_match = re.match(r"(function|const)\s+", clause, re.I)
+    statement_kind = kind_match.group(1).lower() if kind_match else None
+    if kind_match:
+        clause = clause[kind_match.end() :]
+    # use App\Models\{User, Post as Article};  items may carry their own kind
+    group = re.match(r"\\?([\w\\]*?)\\?\s*\{(.*)\}$", clause)
+    prefix, items = (group.group(1) + "\\", group.group(2)) if group else ("", clause)
+    result = []
+    for item in items.split(","):
+        match = _USE_ITEM_RE.match(item.strip())
+        if match is None:
+            continue
+        kind = (match.group(1) or statement_kind or "").lower() or None
+        result.append((kind, (prefix + match.group(2)).strip("\\"), match.group(3)))
+    return result
+
+
+def _references(masked: str) -> list[tuple[int, str]]:
+    """(offset, name) of every class a statement names outside ``use``."""
+    refs = []
+    for pattern in _REFERENCE_RES:
+        refs += [(m.start(1), m.group(1)) for m in pattern.finditer(masked)]
+    for match in _CATCH_RE.finditer(masked):
+        refs += [(match.start(1), t.strip()) for t in match.group(1).split("|") if t.strip()]
+    for match in _SIGNATURE_RE.finditer(masked):
+        types = _PARAM_TYPE_RE.findall(match.group(1))
+        if match.group(2):
+            types.append(match.group(2))
+        for declared in types:
+            refs += [(match.start(), t.lstrip("?")) for t in re.split(r"[|&]", declared) if t]
+    return sorted(refs)
+
+
+def _namespace_at(namespaces: list[tuple[int, str]], offset: int) -> str:
+    current = ""
+    for start, name in namespaces:
+        if start > offset:
+            break
+        current = name
+    return current
+
+
+def _qualify(name: str, namespace: str, aliases: dict[str, str]) -> str | None:
+    """Fully qualified name of a class reference, or None for types like ``int``."""
+    name = name.strip()
+    if not name or name.lower() in _RESERVED:
+        return None
+    if name.startswith("\\"):
+        return name[1:]
+    if name.lower().startswith("namespace\\"):
+        name = name[len("namespace\\") :]
+    else:
+        first, _, rest = name.partition("\\")
+        imported = aliases.get(first.lower())
+        if imported:
+            return f"{imported}\\{rest}" if rest else imported
+    return f"{namespace}\\{name}" if namespace else name
+
+
+def _attach_members(syntax: FileSyntax, scopes: list[_ClassScope]) -> None:
+    """Methods, fields, bases and abstractness of the parsed classes."""
+    unclaimed = list(syntax.classes)
+    for scope in scopes:
+        cls = _claim_class(unclaimed, scope.name)
+        if cls is None:
+            continue
+        members = [
+            fn for fn in syntax.functions if scope.start_line <= fn.start_line <= scope.end_line
+        ]
+        cls.methods = members + [m for m in cls.methods if m not in members]
+        cls.fields += [f for f in scope.fields if f not in cls.fields]
+        if not cls.bases:
+            cls.bases = [b.lstrip("\\") for b in scope.bases]
+        if scope.kind == "interface" or "abstract" in scope.modifiers.split():
+            cls.is_abstract = True
+
+
+def _claim_class(unclaimed: list[ClassDef], name: str) -> ClassDef | None:
+    for cls in unclaimed:
+        if cls.name == name:
+            unclaimed.remove(cls)
+            return cls
+    return None
@@ -0,0 +1,211 @@
+"""Preprocessor conditionals for C and C++ sources.
+
+Headers routinely wrap code in ``#ifdef`` blocks: platform branches,
+``extern "C" {`` guards, alternative definitions of the same function.
+Parsed as-is, both branches are seen at once, so functions are counted
+twice and braces opened in one branch and closed after ``#endif`` confuse
+both tree-sitter and the regex fallback.
+
+``ConditionalResolver`` picks one configuration instead, the way the
+compiler would: macros named in ``c_defines`` (plus ``__cplusplus`` for
+C++ files) are defined, every other macro is not, and ``#define`` /
+``#undef`` lines seen along the way are applied. Lines of branches not
+taken, and the conditional directives themselves, become blank lines so
+line numbers still match the file. A condition that cannot be evaluated
+(a function-like macro, say) takes its first branch.
+"""
+
+from __future__ import annotations
+
+import ast
+import re
+from typing import Optional, Sequence
+
+# Value given to __cplusplus in C++ files
+CPLUSPLUS_VERSION = "201703L"
+
+_DIRECTIVE_RE = re.compile(r"^\s*#\s*(\w+)\b\s*(.*)$", re.DOTALL)
+_DEFINE_RE = re.compile(r"^([A-Za-z_]\w*)(\([^)]*\))?\s*(.*)$", re.DOTALL)
+_DEFINED_RE = re.compile(r"\bdefined\s*(?:\(\s*([A-Za-z_]\w*)\s*\)|([A-Za-z_]\w*))")
+_COMMENT_RE = re.compile(r"/\*.*?\*/|//.*", re.DOTALL)
+# A number (suffixes dropped), an identifier or an operator
+_TOKEN_RE = re.compile(
+    r"\s*(?:(0[xX][0-9a-fA-F]+|\d+)[uUlL]*|([A-Za-z_]\w*)"
+    r"|(&&|\|\||==|!=|<=|>=|<<|>>|[-+*/%<>!~&|^()]))"
+)
+_DEFINE_ARG_RE = re.compile(r"^[A-Za-z_]\w*(?:=.*)?$")
+
+_ALLOWED_NODES = (
+    ast.Expression,
+    ast.BoolOp,
+    ast.And,
+    ast.Or,
+    ast.UnaryOp,
+    ast.Not,
+    ast.USub,
+    ast.UAdd,
+    ast.Invert,
+    ast.BinOp,
+    ast.Add,
+    ast.Sub,
+    ast.Mult,
+    ast.FloorDiv,
+    ast.Mod,
+    ast.BitAnd,
+    ast.BitOr,
+    ast.BitXor,
+    ast.LShift,
+    ast.RShift,
+    ast.Compare,
+    ast.Eq,
+    ast.NotEq,
+    ast.Lt,
+    ast.LtE,
+    ast.Gt,
+    ast.GtE,
+    ast.Constant,
+)
+
+
+def parse_defines(defines: Sequence[str]) -> dict[str, str]:
+    """``["DEBUG", "LEVEL=2"]`` -> ``{"DEBUG": "1", "LEVEL": "2"}``, as ``-D`` does."""
+    macros: dict[str, str] = {}
+    for define in defines:
+        if not _DEFINE_ARG_RE.match(define):
+            raise ValueError(f"invalid macro definition {define!r}: expected NAME or NAME=VALUE")
+        name, eq, value = define.partition("=")
+        macros[name] = value if eq else "1"
+    return macros
+
+
+class ConditionalResolver:
+    """Keeps the branches of ``#if`` blocks selected by a set of macros."""
+
+    def __init__(self, defines: Sequence[str] = ()) -> None:
+        self._defines = parse_defines(defines)
+
+    def resolve(self, content: str, cplusplus: bool = False) -> str:
+        """*content* with untaken branches and conditional directives blanked."""
+        if "#" not in content:
+            return content
+        macros = dict(self._defines)
+        if cplusplus:
+            macros.setdefault("__cplusplus", CPLUSPLUS_VERSION)
+        # (enclosing block active, some branch taken, this branch active)
+        stack: list[tuple[bool, bool, bool]] = []
+        active = True
+        out: list[str] = []
+        lines = content.split("\n")
+        i = 0
+        while i < len(lines):
+            # A directive may continue over several lines ending in a backslash
+            start = i
+            logical = lines[i]
+            while logical.endswith("\\") and i + 1 < len(lines):
+                i += 1
+                logical = logical[:-1] + " " + lines[i]
+            i += 1
+            physical = lines[start:i]
+
+            match = _DIRECTIVE_RE.match(logical)
+            keyword = match.group(1) if match else ""
+            argument = _COMMENT_RE.sub(" ", match.group(2)).strip() if match else ""
+
+            if keyword in ("if", "ifdef", "ifndef"):
+                taken = active and self._condition(keyword, argument, macros)
+                stack.append((active, taken, taken))
+                active = taken
+            elif keyword == "elif" and stack:
+                parent, taken, _ = stack[-1]
+                branch = parent and not taken and self._condition("if", argument, macros)
+                stack[-1] = (parent, taken or branch, branch)
+                active = branch
+            elif keyword == "else" and stack:
+                parent, taken, _ = stack[-1]
+                branch = parent and not taken
+                stack[-1] = (parent, True, branch)
+                active = branch
+            elif keyword == "endif" and stack:
+                active = stack.pop()[0]
+            else:
+                if active and keyword in ("define", "undef"):
+                    self._apply_define(keyword, argument, macros)
+                out.extend(physical if active else [""] * len(physical))
+                continue
+            out.extend([""] * len(physical))
+        return "\n".join(out)
+
+    def _condition(self, keyword: str, argument: str, macros: dict[str, str]) -> bool:
+        if keyword == "ifdef":
+            return argument.split()[0] in macros if argument else False
+        if keyword == "ifndef":
+            return argument.split()[0] not in macros if argument else True
+        value = evaluate(argument, macros)
+        return True if value is None else value != 0
+
+    @staticmethod
+    def _apply_define(keyword: str, argument: str, macros: dict[str, str]) -> None:
+        match = _DEFINE_RE.match(argument)
+        if match is None:
+            return
+        name, params, value = match.groups()
+        if keyword == "undef":
+            macros.pop(name, None)
+        elif params is None:
+            macros[name] = value.strip()
+        else:
+            macros[name] = "?"  # function-like: defined, but not evaluated
+
+
+def evaluate(expression: str, macros: dict[str, str], depth: int = 0) -> Optional[int]:
+    """Integer value of an ``#if`` expression, or None if it cannot be evaluated."""
+    if depth > 8:
+        return None
+    text = _DEFINED_RE.sub(
+        lambda m: "1" if (m.group(1) or m.group(2)) in macros else "0", expression
+    ).strip()
+    parts: list[str] = []
+    pos = 0
+    while pos < len(text):
+        match = _TOKEN_RE.match(text, pos)
+        if match is None or match.end() == pos:
+            return None  # ternaries, casts, character literals...
+        pos = match.end()
+        number, name, op = match.groups()
+        if number is not None:
+            try:
+                parts.append(str(_int(number)))
+            except ValueError:  # 09 is not octal
+                return None
+        elif name is not None:
+            if name not in macros:
+                parts.append("0")  # undefined identifiers are 0, as in C
+                continue
+            value = evaluate(macros[name], macros, depth + 1) if macros[name] else 0
+            if value is None:
+                return None
+            parts.append(str(value))
+        else:
+            parts.append({"&&": " and ", "||": " or ", "!": " not ", "/": "//"}.get(op, op))
+    return _safe_eval(" ".join(parts).strip())
+
+
+def _int(number: str) -> int:
+    if number[:2] in ("0x", "0X"):
+        return int(number, 16)
+    if len(number) > 1 and number.startswith("0"):
+        return int(number, 8)
+    return int(number)
+
+
+def _safe_eval(expression: str) -> Optional[int]:
+    try:
+        tree = ast.parse(expression, mode="eval")
+    except SyntaxError:
+        return None
+    if not all(isinstance(node, _ALLOWED_NODES) for node in ast.walk(tree)):
+        return None
+    try:
+        return int(eval(compile(tree, "<#if>", "eval"), {"__builtins__": {}}, {}))
+    except (ArithmeticError, ValueError, TypeError):
+        return None
@@ -0,0 +1,442 @@
+"""Protocol Buffers definitions: services, RPCs and messages, from source text.
+
+A .proto file is tokenized (comments dropped, strings kept whole) and read
+statement by statement: ``package``, ``import``, ``option``, ``message``
+(with its fields, ``oneof`` groups, maps and nested messages and enums),
+``enum`` and ``service`` with its ``rpc`` methods. ``annotate_proto`` runs
+after the fallback parser and fills in:
+
+    functions   one per RPC ("UserService.GetUser"), its request type as
+                the parameter
+    classes     services, with their RPCs as methods (abstract: they are
+                interfaces), then messages ("User", "User.Address") and
+                enums, with field and value names as fields
+    imports     the imported .proto paths; the graph builder resolves them
+                against the .proto files of the codebase
+
+protoc stubs are linked back to their definition by name: ``user.pb.go``,
+``user_grpc.pb.go``, ``user_pb2.py`` and ``user_pb2_grpc.py`` are generated
+from a ``user.proto``. When several .proto files share the name, the one
+whose directory best matches the stub's wins (``gen/go/api/v1/user.pb.go``
+comes from ``api/v1/user.proto``). The graph builder adds an edge from
+each stub to its definition (see graph/builder.py), so a Go server and a
+Python client of one service depend on the same .proto file.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import Iterable, Optional
+
+from .syntax import ClassDef, FileSyntax, FunctionDef, ImportDecl
+
+# protoc and grpc plugin outputs, longest first so _pb2_grpc.py wins over _pb2.py
+STUB_SUFFIXES = (
+    "_pb2_grpc.py",
+    "_pb2.pyi",
+    "_pb2.py",
+    "_grpc.pb.go",
+    ".pb.gw.go",
+    ".pb.go",
+    "_grpc_pb.d.ts",
+    "_grpc_pb.js",
+    "_pb.d.ts",
+    "_pb.js",
+    "_pb.ts",
+    ".pb.ts",
+    ".grpc.pb.cc",
+    ".grpc.pb.h",
+    ".pb.cc",
+    ".pb.h",
+    ".grpc.swift",
+    ".pb.swift",
+    ".pbgrpc.dart",
+    ".pbjson.dart",
+    ".pb.dart",
+)
+
+_TOKEN_RE = re.compile(
+    r'//[^\n]*|/\*[\s\S]*?\*/|"(?:\\.|[^"\\\n])*"|\'(?:\\.|[^\'\\\n])*\'|[\w.]+|\S'
+)
+_TYPE_WORDS = frozenset({"repeated", "optional", "required"})
+
+
+@dataclass
+class ProtoRpc:
+    """An RPC method: its request and response message types."""
+
+    name: str
+    request: str
+    response: str
+    start_line: int
+    end_line: int
+    client_streaming: bool = False
+    server_streaming: bool = False
+    tokens: int = 0
+
+
+@dataclass
+class ProtoService:
+    """A service and its RPCs."""
+
+    name: str
+    start_line: int
+    end_line: int
+    rpcs: list[ProtoRpc] = field(default_factory=list)
+
+
+@dataclass
+class ProtoMessage:
+    """A message or enum; nested ones are named after their parent ("User.Address")."""
+
+    name: str
+    kind: str  # "message" or "enum"
+    start_line: int
+    end_line: int
+    fields: list[str] = field(default_factory=list)
+
+
+@dataclass
+class ProtoFile:
+    """The definitions of one .proto file."""
+
+    package: str = ""
+    imports: list[str] = field(default_factory=list)
+    options: dict[str, str] = field(default_factory=dict)
+    services: list[ProtoService] = field(default_factory=list)
+    messages: list[ProtoMessage] = field(default_factory=list)
+
+
+@dataclass
+class ProtoCatalog:
+    """Every .proto file of a codebase, with the stubs generated from each."""
+
+    files: dict[str, ProtoFile]
+    stubs: dict[str, list[str]]  # .proto path -> stub paths
+
+    def services(self) -> list[tuple[str, ProtoService]]:
+        """(path, service) for every service, by path."""
+        return [(path, s) for path, proto in sorted(self.files.items()) for s in proto.services]
+
+    def to_dict(self) -> dict:
+        return {
+            "files": [
+                {
+                    "path": path,
+                    "package": proto.package,
+                    "imports": proto.imports,
+                    "services": [
+                        {
+                            "name": service.name,
+                            "line": service.start_line,
+                            "rpcs": [
+                                {
+                                    "name": rpc.name,
+                                    "request": rpc.request,
+                                    "response": rpc.response,
+                                    "client_streaming": rpc.client_streaming,
+                                    "server_streaming": rpc.server_streaming,
+                                    "line": rpc.start_line,
+                                }
+                                for rpc in service.rpcs
+                            ],
+                        }
+                        for service in proto.services
+                    ],
+                    "messages": [m.name for m in proto.messages if m.kind == "message"],
+                    "enums": [m.name for m in proto.messages if m.kind == "enum"],
+                    "stubs": self.stubs.get(path, []),
+                }
+                for path, proto in sorted(self.files.items())
+            ]
+        }
+
+
+def parse_proto(content: str) -> ProtoFile:
+    """Parse the services, messages and imports of a .proto file."""
+    return _Parser(content).parse()
+
+
+def annotate_proto(syntax: FileSyntax, content: str) -> None:
+    """Replace fallback results with RPCs, services, messages and imports."""
+    proto = parse_proto(content)
+    functions = []
+    classes = []
+    for service in proto.services:
+        methods = [
+            FunctionDef(
+                name=f"{service.name}.{rpc.name}",
+                params=[rpc.request],
+                body_tokens=rpc.tokens,
+                signature_tokens=4,
+                nesting_depth=0,
+                start_line=rpc.start_line,
+                end_line=rpc.end_line,
+            )
+            for rpc in service.rpcs
+        ]
+        functions.extend(methods)
+        classes.append(ClassDef(service.name, [], methods, [], is_abstract=True))
+    for message in proto.messages:
+        classes.append(ClassDef(message.name, [], [], list(message.fields)))
+    syntax.functions = functions
+    syntax.classes = classes
+    syntax.imports = [ImportDecl(source=path, names=[]) for path in proto.imports]
+
+
+def catalog_protos(files: dict[str, FileSyntax], contents: dict[str, str]) -> ProtoCatalog:
+    """Parse every .proto file among *files* and find the stubs generated from each."""
+    protos = {
+        path: parse_proto(contents.get(path, ""))
+        for path, syntax in files.items()
+        if syntax.language == "proto"
+    }
+    stubs: dict[str, list[str]] = {}
+    for stub, definition in link_stubs(files).items():
+        stubs.setdefault(definition, []).append(stub)
+    return ProtoCatalog(protos, stubs)
+
+
+def stub_stem(path: str) -> Optional[str]:
+    """Name of the .proto file a protoc stub was generated from, None for other files.
+
+    Hyphens and dots are folded to underscores, as the Python plugin does.
+    """
+    name = PurePosixPath(path).name
+    for suffix in STUB_SUFFIXES:
+        if name.endswith(suffix) and len(name) > len(suffix):
+            return _fold(name[: -len(suffix)])
+    return None
+
+
+def link_stubs(paths: Iterable[str]) -> dict[str, str]:
+    """Map each protoc stub among *paths* to the .proto file it was generated from."""
+    paths = sorted(paths)
+    protos: dict[str, list[str]] = {}
+    for path in paths:
+        if path.endswith(".proto"):
+            protos.setdefault(_fold(PurePosixPath(path).stem), []).append(path)
+    links = {}
+    for path in paths:
+        stem = stub_stem(path)
+        candidates = protos.get(stem, []) if stem else []
+        if candidates:
+            links[path] = max(candidates, key=lambda proto: _affinity(path, proto))
+    return links
+
+
+def _fold(stem: str) -> str:
+    return stem.replace("-", "_").replace(".", "_")
+
+
+def _affinity(stub: str, proto: str) -> tuple[int, int]:
+    """(shared trailing directories, shared leading directories); ties go to the first path."""
+    a = PurePosixPath(stub).parent.parts
+    b = PurePosixPath(proto).parent.parts
+    trailing = 0
+    while trailing < min(len(a), len(b)) and a[-1 - trailing] == b[-1 - trailing]:
+        trailing += 1
+    leading = 0
+    while leading < min(len(a), len(b)) and a[leading] == b[leading]:
+        leading += 1
+    return trailing, leading
+
+
+class _Parser:
+    """Recursive descent over the token stream; unknown statements are skipped."""
+
+    def __init__(self, content: str) -> None:
+        self.tokens: list[tuple[str, int]] = []
+        line = 1
+        position = 0
+        for match in _TOKEN_RE.finditer(content):
+            line += content.count("\n", position, match.start())
+            text = match.group()
+            if not text.startswith(("//", "/*")):
+                self.tokens.append((text, line))
+            line += text.count("\n")
+            position = match.end()
+        self.index = 0
+        self.result = ProtoFile()
+
+    def parse(self) -> ProtoFile:
+        while self._peek() is not None:
+            word = self._next()
+            if word == "package":
+                self.result.package = self._next() or ""
+                self._skip_statement()
+            elif word == "import":
+                path = self._next()
+                if path in ("public", "weak"):
+                    path = self._next()
+                if path and path[0] in "\"'":
+                    self.result.imports.append(path[1:-1])
+                self._skip_statement()
+            elif word == "option":
+                self._option(self.result.options)
+            elif word in ("message", "enum"):
+                self._definition(word, "")
+            elif word == "service":
+                self._service()
+            elif word == "{":
+                self._skip_block()
+            elif word != ";":
+                self._skip_statement()
+        return self.result
+
+    def _definition(self, kind: str, parent: str) -> None:
+        start = self._line()
+        name = self._next() or ""
+        qualified = f"{parent}.{name}" if parent else name
+        message = ProtoMessage(qualified, kind, start, start)
+        self.result.messages.append(message)
+        if self._next() != "{":
+            return
+        depth = 1
+        while depth and self._peek() is not None:
+            word = self._next()
+            if word in ("message", "enum") and depth == 1:
+                self._definition(word, qualified)
+            elif word == "{":
+                depth += 1
+            elif word == "}":
+                depth -= 1
+            elif word in ("option", "reserved", "extensions"):
+                self._skip_statement()
+            elif word == "oneof":
+                self._next()  # its name; its fields are the message's, one block down
+            elif word == ";":
+                continue
+            elif word == "map":
+                self._skip_until(">")
+                message.fields.append(self._next() or "")
+                self._skip_statement()
+            else:
+                field_name = self._field(kind, word)
+                if field_name:
+                    message.fields.append(field_name)
+        message.end_line = self._line(previous=True)
+
+    def _field(self, kind: str, first: str) -> Optional[str]:
+        """The name of the field (or enum value) starting with *first*."""
+        if kind == "enum":
+            name = first
+        else:
+            if first in _TYPE_WORDS:
+                self._next()  # the type
+            name = self._next()
+        if self._peek() != "=":
+            return None
+        self._skip_statement()
+        return name
+
+    def _service(self) -> None:
+        start = self._line()
+        service = ProtoService(self._next() or "", start, start)
+        self.result.services.append(service)
+        if self._next() != "{":
+            return
+        while self._peek() not in (None, "}"):
+            word = self._next()
+            if word == "rpc":
+                rpc = self._rpc()
+                if rpc is not None:
+                    service.rpcs.append(rpc)
+            elif word == "{":
+                self._skip_block()
+            elif word != ";":
+                self._skip_statement()
+        self._next()
+        service.end_line = self._line(previous=True)
+
+    def _rpc(self) -> Optional[ProtoRpc]:
+        first = self.index
+        start = self._line(previous=True)
+        name = self._next()
+        client_streaming, request = self._message_type()
+        if self._next() != "returns":
+            self._skip_statement()
+            return None
+        server_streaming, response = self._message_type()
+        if self._peek() == "{":
+            self._next()
+            self._skip_block()
+        else:
+            self._skip_statement()
+        return ProtoRpc(
+            name=name or "",
+            request=request,
+            response=response,
+            start_line=start,
+            end_line=self._line(previous=True),
+            client_streaming=client_streaming,
+            server_streaming=server_streaming,
+            tokens=self.index - first,
+        )
+
+    def _message_type(self) -> tuple[bool, str]:
+        """``( [stream] Type )`` -> (streaming, type)."""
+        if self._next() != "(":
+            return False, ""
+        words = []
+        while self._peek() not in (None, ")"):
+            words.append(self._next())
+        self._next()
+        streaming = len(words) > 1 and words[0] == "stream"
+        return streaming, (words[-1] if words else "").lstrip(".")
+
+    def _option(self, options: dict[str, str]) -> None:
+        words = []
+        while self._peek() not in (None, ";", "{", "}"):
+            words.append(self._next())
+        if self._peek() == "{":
+            self._next()
+            self._skip_block()
+        if "=" in words:
+            at = words.index("=")
+            value = " ".join(words[at + 1 :])
+            if len(value) >= 2 and value[0] == value[-1] and value[0] in "\"'":
+                value = value[1:-1]
+            options["".join(words[:at]).strip("()")] = value
+        if self._peek() == ";":
+            self._next()
+
+    def _skip_statement(self) -> None:
+        """Past the next ``;`` at this level, or up to a closing ``}``."""
+        while self._peek() not in (None, "}"):
+            word = self._next()
+            if word == ";":
+                return
+            if word == "{":
+                self._skip_block()
+                return
+
+    def _skip_block(self) -> None:
+        """Past the ``}`` closing a block whose ``{`` was just read."""
+        depth = 1
+        while depth and self._peek() is not None:
+            word = self._next()
+            depth += {"{": 1, "}": -1}.get(word or "", 0)
+
+    def _skip_until(self, token: str) -> None:
+        while self._peek() not in (None, token):
+            self._next()
+        self._next()
+
+    def _peek(self) -> Optional[str]:
+        return self.tokens[self.index][0] if self.index < len(self.tokens) else None
+
+    def _next(self) -> Optional[str]:
+        token = self._peek()
+        if token is not None:
+            self.index += 1
+        return token
+
+    def _line(self, previous: bool = False) -> int:
+        """Line of the next token, or of the last one read."""
+        index = self.index - 1 if previous else self.index
+        if not self.tokens:
+            return 1
+        return self.tokens[min(max(index, 0), len(self.tokens) - 1)][1]
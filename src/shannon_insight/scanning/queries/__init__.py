@@ -7,7 +7,22 @@ from __future__ import annotations
 
 from typing import TYPE_CHECKING, Any
 
-from . import c_cpp, go, java, javascript, python, ruby, rust, tsx, typescript
+from . import (
+    c_cpp,
+    cpp,
+    go,
+    java,
+    javascript,
+    kotlin,
+    php,
+    python,
+    ruby,
+    rust,
+    scala,
+    swift,
+    tsx,
+    typescript,
+)
 
 if TYPE_CHECKING:
     from types import ModuleType
@@ -20,10 +35,14 @@ QUERY_MODULES: dict[str, ModuleType] = {
     "tsx": tsx,
     "javascript": javascript,
     "java": java,
+    "kotlin": kotlin,
     "rust": rust,
     "ruby": ruby,
+    "php": php,
+    "scala": scala,
+    "swift": swift,
     "c": c_cpp,
-    "cpp": c_cpp,
+    "cpp": cpp,
 }
 
 
@@ -2,11 +2,11 @@
 
 Extracts:
     - Function definitions
-    - Struct/class definitions (C++ classes)
+    - Struct/union/enum definitions, including typedef struct { ... } Name;
     - Include directives (imports)
 
-Note: Uses C grammar which handles most C++ constructs.
-C++ specific features (namespaces, templates) use the cpp grammar.
+These queries run on C files with the C grammar. The *_cpp queries need
+the C++ grammar; queries/cpp.py combines both for C++ files.
 """
 
 # Query for function definitions (works for both C and C++)
@@ -59,6 +59,14 @@ CLASS_QUERY = """
     name: (type_identifier) @enum.name
     body: (enumerator_list) @enum.body
 ) @enum
+
+(type_definition
+    type: (struct_specifier
+        !name
+        body: (field_declaration_list) @typedef_struct.body
+    )
+    declarator: (type_identifier) @typedef_struct.name
+) @typedef_struct
 """
 
 # C++ class query
@@ -0,0 +1,30 @@
+"""Tree-sitter queries for C++.
+
+The C queries (c_cpp) plus what only the C++ grammar has:
+    - Out-of-line methods (``void Parser::reset()``)
+    - Methods defined inside the class body
+    - Classes and their base classes
+"""
+
+from . import c_cpp
+
+# Methods defined in the class body have a field_identifier name
+INLINE_METHOD_QUERY = """
+(function_definition
+    declarator: (function_declarator
+        declarator: (field_identifier) @inline_method.name
+        parameters: (parameter_list) @inline_method.params
+    )
+    body: (compound_statement) @inline_method.body
+) @inline_method
+"""
+
+
+def get_all_queries() -> dict[str, str]:
+    """Return all C++ queries as a dict."""
+    queries = c_cpp.get_all_queries()
+    return {
+        **queries,
+        "function": queries["function"] + queries["function_cpp"] + INLINE_METHOD_QUERY,
+        "class": queries["class"] + queries["class_cpp"],
+    }
@@ -0,0 +1,87 @@
+"""Tree-sitter queries for Kotlin.
+
+Extracts:
+    - Function definitions (top-level, members, extension and suspend functions)
+    - Class, interface, enum class and object declarations
+    - Import headers
+    - Calls, including coroutine builders (launch { }, async { })
+
+The Kotlin grammar has few named fields, so patterns match children by
+node type.
+"""
+
+# Query for function definitions; extension functions carry a receiver
+# type before the name and suspend functions a function_modifier
+FUNCTION_QUERY = """
+(function_declaration
+    (simple_identifier) @function.name
+    (function_value_parameters) @function.params
+) @function
+"""
+
+# Query for classes, interfaces, enum classes and objects
+CLASS_QUERY = """
+(class_declaration
+    (type_identifier) @class.name
+) @class
+
+(object_declaration
+    (type_identifier) @object.name
+) @object
+"""
+
+# Query for imports
+IMPORT_QUERY = """
+(import_header
+    (identifier) @import.name
+) @import
+"""
+
+# Query for call expressions
+CALL_QUERY = """
+(call_expression
+    (simple_identifier) @call.name
+    (call_suffix) @call.args
+) @call
+
+(call_expression
+    (navigation_expression
+        (_) @call.object
+        (navigation_suffix
+            (simple_identifier) @call.method_name
+        )
+    )
+) @call.method_call
+"""
+
+# Query for parameters
+PARAMETER_QUERY = """
+(function_value_parameters
+    (parameter
+        (simple_identifier) @param
+    )
+)
+"""
+
+# Query for modifiers (abstract, sealed, suspend)
+MODIFIER_QUERY = """
+(modifiers
+    [
+        (class_modifier)
+        (inheritance_modifier)
+        (function_modifier)
+    ] @modifier
+)
+"""
+
+
+def get_all_queries() -> dict[str, str]:
+    """Return all Kotlin queries as a dict."""
+    return {
+        "function": FUNCTION_QUERY,
+        "class": CLASS_QUERY,
+        "import": IMPORT_QUERY,
+        "call": CALL_QUERY,
+        "parameter": PARAMETER_QUERY,
+        "modifier": MODIFIER_QUERY,
+    }
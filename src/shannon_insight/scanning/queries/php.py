@@ -0,0 +1,106 @@
+"""Tree-sitter queries for PHP.
+
+Extracts:
+    - Function definitions and methods
+    - Class, interface, trait and enum declarations
+    - use statements and require/include expressions
+    - Calls, including static calls (Cache::get) and instantiation (new Post)
+
+Imports are rewritten as fully qualified class names afterwards (see
+scanning/php.py), so the import query only needs the raw clauses.
+"""
+
+# Query for functions and methods
+FUNCTION_QUERY = """
+(function_definition
+    name: (name) @function.name
+    parameters: (formal_parameters) @function.params
+) @function
+
+(method_declaration
+    name: (name) @method.name
+    parameters: (formal_parameters) @method.params
+) @method
+"""
+
+# Query for classes, interfaces, traits and enums
+CLASS_QUERY = """
+(class_declaration
+    name: (name) @class.name
+) @class
+
+(interface_declaration
+    name: (name) @interface.name
+) @interface
+
+(trait_declaration
+    name: (name) @trait.name
+) @trait
+
+(enum_declaration
+    name: (name) @enum.name
+) @enum
+"""
+
+# Query for imports
+IMPORT_QUERY = """
+(namespace_use_clause
+    [(name) (qualified_name)] @use.path
+) @use
+
+(include_expression
+    (string) @include.path
+) @include
+
+(include_once_expression
+    (string) @include.path
+) @include
+
+(require_expression
+    (string) @require.path
+) @require
+
+(require_once_expression
+    (string) @require.path
+) @require
+"""
+
+# Query for call expressions
+CALL_QUERY = """
+(function_call_expression
+    function: (_) @call.name
+    arguments: (arguments) @call.args
+) @call
+
+(member_call_expression
+    object: (_) @call.object
+    name: (name) @call.method_name
+) @call.method_call
+
+(scoped_call_expression
+    scope: (_) @call.scope
+    name: (name) @call.method_name
+) @call.static_call
+"""
+
+# Query for parameters ($ included in the variable_name)
+PARAMETER_QUERY = """
+(formal_parameters
+    [
+        (simple_parameter name: (variable_name) @param)
+        (variadic_parameter name: (variable_name) @param)
+        (property_promotion_parameter name: (variable_name) @param)
+    ]
+)
+"""
+
+
+def get_all_queries() -> dict[str, str]:
+    """Return all PHP queries as a dict."""
+    return {
+        "function": FUNCTION_QUERY,
+        "class": CLASS_QUERY,
+        "import": IMPORT_QUERY,
+        "call": CALL_QUERY,
+        "parameter": PARAMETER_QUERY,
+    }
@@ -1,9 +1,11 @@
 """Tree-sitter queries for Rust.
 
 Extracts:
-    - Function definitions (including methods, async)
-    - Struct and enum definitions
+    - Function definitions (including methods, async, trait signatures)
+    - macro_rules! definitions, analyzed like functions
+    - Struct, enum and trait definitions, and impl blocks
     - Use declarations (imports)
+    - Calls, including macro invocations
 """
 
 # Query for function definitions
@@ -18,6 +20,10 @@ FUNCTION_QUERY = """
     name: (identifier) @fn_sig.name
     parameters: (parameters) @fn_sig.params
 ) @fn_sig
+
+(macro_definition
+    name: (identifier) @macro.name
+) @macro
 """
 
 # Query for struct/enum definitions
@@ -84,6 +90,10 @@ CALL_QUERY = """
 (call_expression
     function: (scoped_identifier) @call.scoped
 ) @call.scoped_call
+
+(macro_invocation
+    macro: (identifier) @call.macro
+) @call.macro_call
 """
 
 # Query for parameters
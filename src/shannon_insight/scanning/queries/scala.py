@@ -0,0 +1,81 @@
+"""Tree-sitter queries for Scala (2 and 3).
+
+Extracts:
+    - Method definitions and abstract declarations (def)
+    - Class, case class, object, trait and enum definitions
+    - Import declarations
+    - Calls, including method calls on a receiver (repo.save(user))
+
+Imports are rewritten as fully qualified names afterwards (see
+scanning/scala.py), so the import query only needs the raw declarations.
+"""
+
+# Query for methods; declarations (abstract defs) have no body
+FUNCTION_QUERY = """
+(function_definition
+    name: (_) @function.name
+) @function
+
+(function_declaration
+    name: (_) @function.name
+) @function
+"""
+
+# Query for classes, objects, traits and enums
+CLASS_QUERY = """
+(class_definition
+    name: (_) @class.name
+) @class
+
+(object_definition
+    name: (_) @object.name
+) @object
+
+(trait_definition
+    name: (_) @trait.name
+) @trait
+
+(enum_definition
+    name: (_) @enum.name
+) @enum
+"""
+
+# Query for imports
+IMPORT_QUERY = """
+(import_declaration) @import
+"""
+
+# Query for call expressions
+CALL_QUERY = """
+(call_expression
+    function: (identifier) @call.name
+    arguments: (arguments) @call.args
+) @call
+
+(call_expression
+    function: (field_expression
+        value: (_) @call.object
+        field: (identifier) @call.method_name
+    )
+) @call.method_call
+"""
+
+# Query for parameters; curried methods have several parameter lists
+PARAMETER_QUERY = """
+(parameters
+    (parameter
+        name: (identifier) @param
+    )
+)
+"""
+
+
+def get_all_queries() -> dict[str, str]:
+    """Return all Scala queries as a dict."""
+    return {
+        "function": FUNCTION_QUERY,
+        "class": CLASS_QUERY,
+        "import": IMPORT_QUERY,
+        "call": CALL_QUERY,
+        "parameter": PARAMETER_QUERY,
+    }
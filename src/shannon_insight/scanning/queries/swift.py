@@ -0,0 +1,73 @@
+"""Tree-sitter queries for Swift.
+
+Extracts:
+    - Function, initializer and protocol requirement declarations
+    - Class, struct, enum, actor and extension declarations, and protocols
+    - Import declarations
+    - Calls, including method calls on a receiver (repo.save(user))
+
+Imports and type members are read from the source afterwards (see
+scanning/swift.py), so the import query only needs the raw declarations.
+"""
+
+# Query for functions; protocol requirements have no body
+FUNCTION_QUERY = """
+(function_declaration
+    name: (simple_identifier) @function.name
+) @function
+
+(protocol_function_declaration
+    name: (simple_identifier) @function.name
+) @function
+
+(init_declaration) @function
+"""
+
+# Query for types: class_declaration covers class, struct, enum, actor and extension
+CLASS_QUERY = """
+(class_declaration
+    name: (_) @class.name
+) @class
+
+(protocol_declaration
+    name: (_) @protocol.name
+) @protocol
+"""
+
+# Query for imports
+IMPORT_QUERY = """
+(import_declaration) @import
+"""
+
+# Query for call expressions
+CALL_QUERY = """
+(call_expression
+    (simple_identifier) @call.name
+) @call
+
+(call_expression
+    (navigation_expression
+        suffix: (navigation_suffix
+            suffix: (simple_identifier) @call.method_name
+        )
+    )
+) @call.method_call
+"""
+
+# Query for parameters; the name is the internal one (x in `with x: Int`)
+PARAMETER_QUERY = """
+(parameter
+    name: (simple_identifier) @param
+)
+"""
+
+
+def get_all_queries() -> dict[str, str]:
+    """Return all Swift queries as a dict."""
+    return {
+        "function": FUNCTION_QUERY,
+        "class": CLASS_QUERY,
+        "import": IMPORT_QUERY,
+        "call": CALL_QUERY,
+        "parameter": PARAMETER_QUERY,
+    }
@@ -0,0 +1,260 @@
+"""Ruby class structure and Rails DSL, recovered from source text.
+
+Rails code is mostly declarations (class macros) and is loaded by naming
+convention rather than ``require``, so parsing alone sees opaque method
+calls and a file graph without edges. ``annotate_ruby`` runs after either
+parser and turns the common constructs into entities:
+
+    classes       methods are attached to their class and superclasses
+                  recorded (``class Post < ApplicationRecord``)
+    associations  has_many / has_one / belongs_to / has_and_belongs_to_many
+                  :name become class fields and imports of the associated
+                  model (``class_name:`` overrides, polymorphic ones have none)
+    callbacks     before_action, after_save, validate ... :method decorate the
+                  referenced methods (``if:``/``unless:`` conditions too)
+    actions       public methods of controllers get the "action" decorator
+    constants     superclasses and include/extend/prepend targets are imports
+
+Constant imports (``Comment``, ``Admin::User``) resolve through the Rails
+autoload convention in the graph builder: ``admin/user.rb`` under
+``app/*/``, ``app/*/concerns/`` or ``lib/``.
+
+Class extent comes from indentation (``end`` at the column of its
+``class``), which is how Ruby is written in practice.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+
+from .syntax import ClassDef, FileSyntax, ImportDecl
+
+ASSOCIATIONS = frozenset({"has_many", "has_one", "belongs_to", "has_and_belongs_to_many"})
+
+CALLBACKS = frozenset(
+    {
+        "before_action",
+        "after_action",
+        "around_action",
+        "skip_before_action",
+        "prepend_before_action",
+        "before_validation",
+        "after_validation",
+        "before_save",
+        "around_save",
+        "after_save",
+        "before_create",
+        "around_create",
+        "after_create",
+        "before_update",
+        "around_update",
+        "after_update",
+        "before_destroy",
+        "around_destroy",
+        "after_destroy",
+        "after_commit",
+        "after_create_commit",
+        "after_update_commit",
+        "after_destroy_commit",
+        "after_rollback",
+        "after_initialize",
+        "after_find",
+        "validate",
+    }
+)
+
+MIXINS = frozenset({"include", "extend", "prepend"})
+
+ACTION_DECORATOR = "action"
+
+# has_many and habtm name a collection: :comments -> Comment
+_PLURAL_ASSOCIATIONS = frozenset({"has_many", "has_and_belongs_to_many"})
+
+# class Name < Base, module Name, and class << self (singleton methods, no name)
+_SCOPE_RE = re.compile(
+    r"^(\s*)(class|module)\s+(?:([A-Z][\w:]*)(?:\s*<\s*([A-Z][\w:]*))?|<<\s*self\b)"
+)
+_END_RE = re.compile(r"^(\s*)end\b")
+_DEF_RE = re.compile(r"^(\s*)(?:(private|protected|public)\s+)?def\s+(self\.)?")
+_VISIBILITY_RE = re.compile(r"^\s*(private|protected|public)\s*(?:#.*)?$")
+_MACRO_RE = re.compile(r"^\s*([a-z_]+)\s+(.+)$")
+_SYMBOL_RE = re.compile(r"(?<![\w:]):(\w+[?!]?)")
+_OPTION_RE = re.compile(r"(?:\b\w+:\s|:\w+\s*=>)")
+_CONDITION_RE = re.compile(r"\b(?:if|unless):\s*:(\w+[?!]?)")
+_CLASS_NAME_RE = re.compile(r"""class_name:\s*["']:{0,2}([\w:]+)["']""")
+_CONSTANT_RE = re.compile(r"^:{0,2}([A-Z][\w]*(?:::[A-Z]\w*)*)")
+
+
+@dataclass
+class _Scope:
+    """A class or module body found in the text."""
+
+    kind: str
+    name: str
+    base: str | None
+    indent: int
+    start_line: int
+    end_line: int = 0
+    public: set[int] = field(default_factory=set)  # lines of public defs
+    macros: list[tuple[str, str]] = field(default_factory=list)  # (macro, args)
+
+
+def annotate_ruby(syntax: FileSyntax, content: str) -> None:
+    """Add class members, Rails DSL entities and constant imports to *syntax*."""
+    scopes = _find_scopes(content)
+    imported = {imp.source for imp in syntax.imports}
+
+    def add_import(constant: str, names: list[str]) -> None:
+        if constant not in imported:
+            imported.add(constant)
+            syntax.imports.append(ImportDecl(source=constant, names=names))
+
+    owners = {id(fn): _innermost(scopes, fn.start_line) for fn in syntax.functions}
+    unclaimed = list(syntax.classes)
+    for scope in scopes:
+        if scope.base:
+            add_import(scope.base, [])
+        cls = _claim_class(unclaimed, scope)
+        for macro, args in scope.macros:
+            if macro in MIXINS:
+                match = _CONSTANT_RE.match(args)
+                if match:
+                    add_import(match.group(1), [])
+            elif macro in ASSOCIATIONS:
+                association = _association(macro, args)
+                if association is None:
+                    continue
+                name, target = association
+                if cls is not None and name not in cls.fields:
+                    cls.fields.append(name)
+                if target:
+                    add_import(target, [name])
+        if cls is None:
+            continue
+        if not cls.bases and scope.base:
+            cls.bases.append(scope.base)
+        cls.methods = [
+            fn
+            for fn in syntax.functions
+            if owners[id(fn)] is scope and fn not in cls.methods
+        ] + cls.methods
+        _decorate(cls, scope)
+
+
+def _find_scopes(content: str) -> list[_Scope]:
+    """Class and module bodies with their public defs and class macros."""
+    lines = content.splitlines()
+    scopes: list[_Scope] = []
+    open_scopes: list[tuple[_Scope, bool]] = []  # (scope, in a public section)
+    def_indent: int | None = None  # inside a method body until its end
+    for line_no, line in enumerate(lines, start=1):
+        end = _END_RE.match(line)
+        if def_indent is not None:
+            if end and len(end.group(1)) == def_indent:
+                def_indent = None
+            continue
+        match = _SCOPE_RE.match(line)
+        if match:
+            indent, kind, name, base = match.groups()
+            scope = _Scope(kind, name or "", base, len(indent), line_no)
+            scopes.append(scope)
+            if re.search(r";\s*end\s*$", line):  # class Error < StandardError; end
+                scope.end_line = line_no
+            else:
+                open_scopes.append((scope, True))
+            continue
+        if not open_scopes:
+            continue
+        scope, public = open_scopes[-1]
+        if end and len(end.group(1)) == scope.indent:
+            scope.end_line = line_no
+            open_scopes.pop()
+            continue
+        visibility = _VISIBILITY_RE.match(line)
+        if visibility:
+            open_scopes[-1] = (scope, visibility.group(1) == "public")
+            continue
+        definition = _DEF_RE.match(line)
+        if definition:
+            inline, singleton = definition.group(2), definition.group(3)
+            if (inline or ("public" if public else "private")) == "public" and not singleton:
+                scope.public.add(line_no)
+            # One-liners (def x; end) and endless defs (def x = 1) have no body
+            if not re.search(r"\bend\s*$|\)\s*=[^=~>]|^\s*def\s+[\w?!]+\s*=[^=~>]", line):
+                def_indent = len(definition.group(1))
+            continue
+        macro = _MACRO_RE.match(line)
+        if macro:
+            scope.macros.append((macro.group(1), macro.group(2)))
+    for scope, _ in open_scopes:
+        scope.end_line = len(lines)
+    return scopes
+
+
+def _claim_class(unclaimed: list[ClassDef], scope: _Scope) -> ClassDef | None:
+    """The parsed ClassDef for *scope*, matched by its unqualified name."""
+    if not scope.name:
+        return None
+    short = scope.name.split("::")[-1]
+    for cls in unclaimed:
+        if cls.name.split("::")[-1] == short:
+            unclaimed.remove(cls)
+            return cls
+    return None
+
+
+def _innermost(scopes: list[_Scope], line: int) -> _Scope | None:
+    """The most deeply nested scope containing *line*."""
+    containing = [s for s in scopes if s.start_line < line <= s.end_line]
+    return max(containing, key=lambda s: s.start_line) if containing else None
+
+
+def _association(macro: str, args: str) -> tuple[str, str | None] | None:
+    """(association name, model constant or None if polymorphic)."""
+    match = _SYMBOL_RE.match(args)
+    if match is None:
+        return None
+    name = match.group(1)
+    if re.search(r"\bpolymorphic:\s*true", args):
+        return name, None
+    class_name = _CLASS_NAME_RE.search(args)
+    if class_name:
+        return name, class_name.group(1)
+    singular = _singularize(name) if macro in _PLURAL_ASSOCIATIONS else name
+    return name, "".join(part.capitalize() for part in singular.split("_"))
+
+
+def _decorate(cls: ClassDef, scope: _Scope) -> None:
+    """Mark controller actions and methods referenced by callbacks."""
+    by_name = {fn.name.rstrip("?!="): fn for fn in cls.methods}
+    for macro, args in scope.macros:
+        if macro not in CALLBACKS:
+            continue
+        # Symbols before the first option are methods; only:/except: list actions
+        option = _OPTION_RE.search(args)
+        positional = args[: option.start()] if option else args
+        names = _SYMBOL_RE.findall(positional) + _CONDITION_RE.findall(args)
+        for name in names:
+            method = by_name.get(name.rstrip("?!="))
+            if method is not None and macro not in method.decorators:
+                method.decorators.append(macro)
+
+    is_controller = scope.name.endswith("Controller") or (scope.base or "").endswith(
+        ("Controller", "ActionController::Base", "ActionController::API")
+    )
+    if not is_controller:
+        return
+    for fn in cls.methods:
+        if fn.start_line in scope.public and ACTION_DECORATOR not in fn.decorators:
+            fn.decorators.append(ACTION_DECORATOR)
+
+
+def _singularize(word: str) -> str:
+    """English singular of a Rails collection name (the common inflections)."""
+    for plural, singular in (("ies", "y"), ("sses", "ss"), ("xes", "x"), ("ches", "ch")):
+        if word.endswith(plural):
+            return word[: -len(plural)] + singular
+    if word.endswith("s") and not word.endswith("ss"):
+        return word[:-1]
+    return word
@@ -0,0 +1,124 @@
+"""Partial parsing of files with syntax errors.
+
+tree-sitter always returns a tree, wrapping what it cannot parse in ERROR
+nodes. One broken line can swallow the valid code after it (an unclosed
+bracket in Python takes the rest of the file with it), so the functions
+and classes there are missed. parse_with_recovery blanks the line each
+ERROR node starts on, keeping byte offsets and line numbers, and parses
+again until the rest of the file is clean or MAX_PASSES is reached. The
+blanked lines, and tokens the parser inserted to recover (MISSING nodes),
+are returned as ErrorSpans so they can be reported separately.
+"""
+
+from __future__ import annotations
+
+from typing import Any, Callable, Iterator, Optional
+
+from .syntax import ErrorSpan
+
+MAX_PASSES = 16
+
+
+def parse_with_recovery(
+    parse: Callable[[bytes], Any], code: bytes
+) -> tuple[Any, bytes, list[ErrorSpan]]:
+    """Parse *code*, blanking lines with syntax errors until the rest parses.
+
+    Args:
+        parse: code -> tree-sitter Tree (or None on failure)
+        code: Source bytes
+
+    Returns:
+        (tree, code the tree was parsed from, error spans); the tree is
+        None when *parse* fails
+    """
+    tree = parse(code)
+    if tree is None or not tree.root_node.has_error:
+        return tree, code, []
+
+    blanked: set[int] = set()  # 0-indexed rows
+    for _ in range(MAX_PASSES):
+        errors = [node for node in _error_nodes(tree.root_node) if not node.is_missing]
+        if not errors:
+            break
+        rows = {node.start_point[0] for node in errors} - blanked
+        if not rows:
+            # The errors start on blanked lines: give up on their whole extent
+            rows = {
+                row
+                for node in errors
+                for row in range(node.start_point[0], node.end_point[0] + 1)
+            } - blanked
+        if not rows:
+            break
+        candidate = parse(blank_rows(code, blanked | rows))
+        if candidate is None:
+            break
+        blanked |= rows
+        tree = candidate
+
+    code = blank_rows(code, blanked)
+    spans = _row_spans(blanked)
+    for node in _error_nodes(tree.root_node):
+        if node.is_missing:
+            row = node.start_point[0] + 1
+            spans.append(ErrorSpan(row, row, missing=node.type))
+        elif node.start_point[0] not in blanked:
+            spans.append(ErrorSpan(node.start_point[0] + 1, node.end_point[0] + 1))
+    spans.sort(key=lambda s: (s.start_line, s.end_line, s.missing))
+    return tree, code, spans
+
+
+def blank_rows(code: bytes, rows: set[int]) -> bytes:
+    """*code* with every byte of the given 0-indexed rows but newlines turned into spaces."""
+    if not rows:
+        return code
+    lines = code.split(b"\n")
+    for row in rows:
+        if row < len(lines):
+            lines[row] = b" " * len(lines[row])
+    return b"\n".join(lines)
+
+
+def error_lines(spans: list[ErrorSpan]) -> int:
+    """Lines left out of the analysis."""
+    return sum(span.lines for span in spans)
+
+
+def describe(spans: list[ErrorSpan]) -> str:
+    """``lines 4-5, line 9 (missing "}")``"""
+    return ", ".join(
+        f'line {span.start_line} (missing "{span.missing}")'
+        if span.missing
+        else f"line {span.start_line}"
+        if span.start_line == span.end_line
+        else f"lines {span.start_line}-{span.end_line}"
+        for span in spans
+    )
+
+
+def _error_nodes(root: Any) -> Iterator[Any]:
+    """Outermost ERROR nodes and MISSING nodes, in source order."""
+    stack = [root]
+    while stack:
+        node = stack.pop()
+        if node.type == "ERROR" or node.is_missing:
+            yield node
+        elif node.has_error:
+            stack.extend(reversed(node.children))
+
+
+def _row_spans(rows: set[int]) -> list[ErrorSpan]:
+    """Consecutive 0-indexed rows merged into 1-indexed spans."""
+    spans: list[ErrorSpan] = []
+    start: Optional[int] = None
+    previous = -2
+    for row in sorted(rows):
+        if row != previous + 1:
+            if start is not None:
+                spans.append(ErrorSpan(start + 1, previous + 1))
+            start = row
+        previous = row
+    if start is not None:
+        spans.append(ErrorSpan(start + 1, previous + 1))
+    return spans
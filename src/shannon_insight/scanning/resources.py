@@ -0,0 +1,312 @@
+"""Android and iOS resource files: the resources they declare and reference.
+
+Resource files are not source files: they are never parsed into FileSyntax
+or placed in the dependency graph. signals/mobile_resources.py reads them
+with these functions and cross-references them with the code:
+
+    Android  res/<type>[-<qualifiers>]/<file>
+               values*/*.xml   one resource per <string>, <plurals>,
+                               <string-array>, <color>, <dimen>, ...
+               anything else   one resource per file, named by its stem
+                               (drawable-hdpi/logo.png is drawable/logo)
+             references: R.<type>.<name> in code, @<type>/<name> in XML
+             (layouts, menus, styles, AndroidManifest.xml)
+    iOS      <lang>.lproj/*.strings   one resource per "key" = "value";
+             *.xcassets               one per image, color, data or
+                                      symbol set (<name>.imageset/)
+             storyboards and xibs     scene count; image and named color
+                                      references
+             property lists           key count; string values reference
+                                      assets (launch screen images)
+
+Android resource names are normalized to the R field name: ``Theme.App``
+is ``Theme_App``. Nothing here touches the file system.
+"""
+
+from __future__ import annotations
+
+import plistlib
+import re
+from dataclasses import dataclass
+from pathlib import PurePosixPath
+from typing import Any, Optional
+
+# res/ subdirectories, by resource type (qualifiers stripped)
+ANDROID_TYPES = frozenset(
+    {
+        "anim",
+        "animator",
+        "color",
+        "drawable",
+        "font",
+        "interpolator",
+        "layout",
+        "menu",
+        "mipmap",
+        "navigation",
+        "raw",
+        "transition",
+        "values",
+        "xml",
+    }
+)
+
+# Elements of values*/ files, by the R class they are compiled into
+_VALUES_ELEMENTS = {
+    "string": "string",
+    "plurals": "plurals",
+    "string-array": "array",
+    "integer-array": "array",
+    "array": "array",
+    "color": "color",
+    "dimen": "dimen",
+    "bool": "bool",
+    "integer": "integer",
+    "fraction": "fraction",
+    "style": "style",
+}
+
+# Development-language tables of an iOS app
+IOS_DEFAULT_LOCALES = frozenset({"Base", "en"})
+
+# Asset catalog sets that code refers to by name (app icons are set by build settings)
+_ASSET_SETS = {
+    ".imageset": "image",
+    ".colorset": "color",
+    ".dataset": "data",
+    ".symbolset": "symbol",
+}
+
+_VALUES_RE = re.compile(
+    r"<(" + "|".join(sorted(_VALUES_ELEMENTS, key=len, reverse=True)) + r")\b([^>]*?)(/?)>"
+)
+_NAME_ATTR_RE = re.compile(r'\bname\s*=\s*"([^"]+)"')
+_CDATA_RE = re.compile(r"<!\[CDATA\[([\s\S]*?)\]\]>")
+_TAG_RE = re.compile(r"<[^>]+>")
+_R_REF_RE = re.compile(r"\bR\.(\w+)\.(\w+)")
+_XML_REF_RE = re.compile(r"@(?:\+)?(?:(\w[\w.]*):)?(\w+)/([\w.]+)")
+_GET_IDENTIFIER_RE = re.compile(r'\bgetIdentifier\s*\(\s*(?:"([^"]+)"\s*,)?')
+_BINDING_RE = re.compile(r"\b([A-Z]\w*?)Binding\b")
+
+_STRINGS_ENTRY_RE = re.compile(
+    r'^[ \t]*"((?:\\.|[^"\\])*)"[ \t]*=[ \t]*"((?:\\.|[^"\\])*)"[ \t]*;', re.MULTILINE
+)
+_COMMENT_RE = re.compile(r"/\*[\s\S]*?\*/|//[^\n]*")
+_SCENE_RE = re.compile(r"<scene\b")
+_IB_REF_RE = re.compile(
+    r'<(?:image|namedColor)\s+name="([^"]+)"'
+    r'|\b(?:image|selectedImage|highlightedImage|backgroundImage)="([^"]+)"'
+)
+_STRING_LITERAL_RE = re.compile(r'@?"((?:\\.|[^"\\\n])*)"')
+_MEMBER_RE = re.compile(r"\.([a-z]\w*)\b")
+_DYNAMIC_NAMED_RE = re.compile(r"\b(?:named|imageNamed|colorNamed)\s*:(?!\s*@?\")")
+
+
+@dataclass(frozen=True)
+class ResourceDecl:
+    """A declared resource: an Android R entry, an iOS string key or asset."""
+
+    platform: str  # "android" or "ios"
+    type: str  # R class ("string", "drawable", ...), or "localized_string", "image", ...
+    name: str
+    path: str
+    line: int = 1
+    value: Optional[str] = None  # text of string resources
+    default_locale: bool = True  # not a translation or qualified variant
+
+    @property
+    def key(self) -> str:
+        return f"{self.type}/{self.name}"
+
+
+def android_resource_type(path: str) -> Optional[str]:
+    """Resource type of a file under an Android res/ directory, None for other files."""
+    parts = PurePosixPath(path).parts
+    if len(parts) < 3 or parts[-3] != "res":
+        return None
+    directory = parts[-2].split("-")[0]
+    return directory if directory in ANDROID_TYPES else None
+
+
+def android_name(name: str) -> str:
+    """The R field name of a resource name."""
+    return name.replace(".", "_").replace("-", "_")
+
+
+def parse_android_values(path: str, content: str) -> list[ResourceDecl]:
+    """Resources declared in a values*/ XML file."""
+    qualifiers = PurePosixPath(path).parent.name.partition("-")[2]
+    resources = []
+    for match in _VALUES_RE.finditer(content):
+        element, attributes, empty = match.groups()
+        name = _NAME_ATTR_RE.search(attributes)
+        if name is None:
+            continue
+        resource_type = _VALUES_ELEMENTS[element]
+        value = None
+        if resource_type == "string" and not empty:
+            end = content.find(f"</{element}>", match.end())
+            if end != -1:
+                value = _text(content[match.end() : end])
+        resources.append(
+            ResourceDecl(
+                platform="android",
+                type=resource_type,
+                name=android_name(name.group(1)),
+                path=path,
+                line=content.count("\n", 0, match.start()) + 1,
+                value=value,
+                default_locale=not qualifiers,
+            )
+        )
+    return resources
+
+
+def android_file_resource(path: str) -> Optional[ResourceDecl]:
+    """The resource a non-values res/ file declares."""
+    resource_type = android_resource_type(path)
+    if resource_type is None or resource_type == "values":
+        return None
+    name = PurePosixPath(path).name.split(".")[0]
+    qualifiers = PurePosixPath(path).parent.name.partition("-")[2]
+    return ResourceDecl(
+        platform="android",
+        type=resource_type,
+        name=android_name(name),
+        path=path,
+        default_locale=not qualifiers,
+    )
+
+
+def android_references(content: str) -> set[tuple[str, str]]:
+    """(type, name) of the resources *content* refers to, code or XML.
+
+    Framework resources (``android.R.string.ok``, ``@android:color/white``)
+    are left out. View binding classes (``ActivityMainBinding``) refer to
+    their layout.
+    """
+    references = set()
+    for match in _R_REF_RE.finditer(content):
+        if content[max(0, match.start() - 8) : match.start()].endswith("android."):
+            continue
+        references.add((match.group(1), match.group(2)))
+    for match in _XML_REF_RE.finditer(content):
+        package, resource_type, name = match.groups()
+        if package != "android" and resource_type != "id":
+            references.add((resource_type, android_name(name)))
+    for match in _BINDING_RE.finditer(content):
+        layout = re.sub(r"(?<!^)(?=[A-Z])", "_", match.group(1)).lower()
+        references.add(("layout", layout))
+    return references
+
+
+def android_dynamic_names(content: str) -> tuple[set[str], bool]:
+    """Names looked up with Resources.getIdentifier, and whether any is not a literal."""
+    names = set()
+    dynamic = False
+    for match in _GET_IDENTIFIER_RE.finditer(content):
+        if match.group(1) is None:
+            dynamic = True
+        else:
+            names.add(android_name(match.group(1)))
+    return names, dynamic
+
+
+def ios_locale(path: str) -> Optional[str]:
+    """The locale of a file in a <locale>.lproj directory."""
+    parent = PurePosixPath(path).parent.name
+    return parent[: -len(".lproj")] if parent.endswith(".lproj") else None
+
+
+def parse_strings_file(path: str, content: str) -> list[ResourceDecl]:
+    """Entries of an iOS .strings table."""
+    locale = ios_locale(path)
+    content = _COMMENT_RE.sub(lambda m: "\n" * m.group().count("\n"), content)
+    return [
+        ResourceDecl(
+            platform="ios",
+            type="localized_string",
+            name=match.group(1),
+            path=path,
+            line=content.count("\n", 0, match.start()) + 1,
+            value=match.group(2),
+            default_locale=locale is None or locale in IOS_DEFAULT_LOCALES,
+        )
+        for match in _STRINGS_ENTRY_RE.finditer(content)
+    ]
+
+
+def asset_set(path: str) -> Optional[ResourceDecl]:
+    """The asset a ``Contents.json`` of an asset catalog set declares."""
+    pure = PurePosixPath(path)
+    if pure.name != "Contents.json" or not any(p.endswith(".xcassets") for p in pure.parts):
+        return None
+    directory = pure.parent
+    asset_type = _ASSET_SETS.get(directory.suffix)
+    if asset_type is None:
+        return None
+    return ResourceDecl(platform="ios", type=asset_type, name=directory.stem, path=path)
+
+
+def interface_builder_scenes(content: str) -> int:
+    """Scenes (view controllers) of a storyboard."""
+    return len(_SCENE_RE.findall(content))
+
+
+def interface_builder_references(content: str) -> set[str]:
+    """Names of the images and named colors a storyboard or xib uses."""
+    return {a or b for a, b in _IB_REF_RE.findall(content)}
+
+
+def ios_code_references(content: str) -> set[str]:
+    """String literals of Swift or Objective-C code, and the members it names.
+
+    Assets and localized strings are looked up by name (``UIImage(named:
+    "logo")``, ``Text("welcome_title")``, ``NSLocalizedString(@"key",
+    nil)``); Xcode also generates a member per asset (``Image(.logo)``).
+    """
+    names = {m.group(1) for m in _STRING_LITERAL_RE.finditer(content)}
+    names.update(m.group(1) for m in _MEMBER_RE.finditer(content))
+    return names
+
+
+def ios_dynamic_lookup(content: str) -> bool:
+    """Whether the code loads an image or color by a computed name."""
+    return _DYNAMIC_NAMED_RE.search(content) is not None
+
+
+def asset_member(name: str) -> str:
+    """The member Xcode generates for an asset: ``hero-banner`` is ``heroBanner``."""
+    words = [w for w in re.split(r"[^0-9A-Za-z]+", name) if w]
+    if not words:
+        return name
+    first = words[0][0].lower() + words[0][1:]
+    return first + "".join(w[0].upper() + w[1:] for w in words[1:])
+
+
+def plist_values(data: bytes) -> tuple[int, set[str]]:
+    """(number of keys, string values) of an XML or binary property list."""
+    try:
+        root = plistlib.loads(data)
+    except Exception:
+        return 0, set()
+    keys = 0
+    values: set[str] = set()
+    stack: list[Any] = [root]
+    while stack:
+        node = stack.pop()
+        if isinstance(node, dict):
+            keys += len(node)
+            stack.extend(node.values())
+        elif isinstance(node, list):
+            stack.extend(node)
+        elif isinstance(node, str):
+            values.add(node)
+    return keys, values
+
+
+def _text(inner: str) -> str:
+    """Text of a <string> element: markup dropped, whitespace collapsed."""
+    inner = _CDATA_RE.sub(lambda m: m.group(1), inner)
+    inner = _TAG_RE.sub("", inner)
+    return " ".join(inner.split()).strip('"')
@@ -0,0 +1,285 @@
+"""Scala imports and class members, recovered from source text.
+
+Scala imports select members of a package or object, so one clause may
+name several classes: ``import com.acme.{User, Order => Purchase}``.
+``annotate_scala`` runs after either parser and rewrites the file's
+imports as one fully qualified name each:
+
+    imports     selectors expanded (``com.acme.User``, ``com.acme.Order``),
+                renames and Scala 3 ``as`` reduced to the imported name,
+                hidden members (``User => _``) dropped, and wildcards
+                (``_``, ``*``, ``given``) as ``com.acme._``
+    classes     defs are attached to the innermost class, trait or object
+                whose body (braces, or Scala 3 indentation after ``:``)
+                holds them; val/var parameters (every parameter of a case
+                class) and val/var members are its fields
+
+The graph builder resolves the names to files by package path (see
+graph/builder.py); sbt sub-projects decide between same-named classes
+in different modules (see graph/sbt.py).
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+
+from .masking import blank
+from .syntax import ClassDef, FileSyntax, FunctionDef, ImportDecl
+
+# Triple-quoted, string and character literals, block and line comments
+_MASK_RE = re.compile(
+    r'"""[\s\S]*?"""+|"(?:\\.|[^"\\\n])*"|\'(?:\\.|[^\'\\\n])\'|/\*[\s\S]*?\*/|//[^\n]*'
+)
+_IMPORT_RE = re.compile(r"^[ \t]*import\s+((?:[^\n;{]|\{[^}]*\})+)", re.M)
+_SELECTOR_RE = re.compile(r"^(\w+|`[^`]+`)(?:\s*(?:=>|\bas\b)\s*(\w+|`[^`]+`))?$")
+_WILDCARDS = ("_", "*", "given")
+
+_MODIFIERS = (
+    r"(?:private|protected)(?:\[\w*\])?|override|final|abstract|sealed|case|implicit|lazy"
+    r"|inline|transparent|open|opaque"
+)
+_CLASS_RE = re.compile(
+    r"^[ \t]*(?:@\w+(?:\([^)]*\))?\s+)*((?:(?:" + _MODIFIERS + r")\s+)*)"
+    r"(?:(package)\s+)?(class|trait|object|enum)\s+(\w+)",
+    re.M,
+)
+_PARAM_RE = re.compile(
+    r"^\s*(?:@\w+\s+)*(?:(?:override|private|protected|final|implicit|using)(?:\[\w*\])?\s+)*"
+    r"(?:(val|var)\s+)?(\w+)\s*:"
+)
+_MEMBER_RE = re.compile(r"^\s*(?:(?:" + _MODIFIERS + r")\s+)*(?:val|var)\s+(\w+)")
+
+
+@dataclass
+class _ClassScope:
+    """A class, trait, object or enum and the extent of its body."""
+
+    kind: str
+    name: str
+    modifiers: list[str]
+    start_line: int
+    end_line: int  # last line of the body (the declaration line without one)
+    bases: list[str] = field(default_factory=list)
+    fields: list[str] = field(default_factory=list)
+
+
+def annotate_scala(syntax: FileSyntax, content: str) -> None:
+    """Rebuild *syntax*'s imports as fully qualified names and fill in class members."""
+    masked = _MASK_RE.sub(blank, content)
+
+    imports: list[ImportDecl] = []
+    seen: set[str] = set()
+    for match in _IMPORT_RE.finditer(masked):
+        for source, name in _import_items(match.group(1)):
+            if source not in seen:
+                seen.add(source)
+                imports.append(ImportDecl(source=source, names=[name] if name else []))
+    syntax.imports = imports
+
+    _attach_members(syntax, _find_classes(masked))
+
+
+def _split_top_level(text: str, separator: str = ",") -> list[str]:
+    """*text* split on *separator* outside braces, brackets and parentheses."""
+    parts, depth, current = [], 0, []
+    for char in text:
+        if char in "{[(":
+            depth += 1
+        elif char in "}])":
+            depth -= 1
+        if char == separator and depth == 0:
+            parts.append("".join(current))
+            current = []
+        else:
+            current.append(char)
+    parts.append("".join(current))
+    return [p.strip() for p in parts if p.strip()]
+
+
+def _import_items(clause: str) -> list[tuple[str, str | None]]:
+    """(fully qualified name, imported name) of everything an import clause selects."""
+    items: list[tuple[str, str | None]] = []
+    for expr in _split_top_level(" ".join(clause.split())):
+        expr = re.sub(r"^_root_\s*\.\s*", "", expr)
+        group = re.match(r"^([\w.`\s]+?)\s*\.\s*\{(.*)\}$", expr)
+        if group:
+            prefix = group.group(1).replace(" ", "")
+            selectors = _split_top_level(group.group(2))
+        else:
+            # a.b.C, a.b.C as D (Scala 3), a.b._
+            path, _, alias = expr.partition(" as ")
+            prefix, _, last = path.replace(" ", "").rpartition(".")
+            selectors = [f"{last} as {alias}" if alias else last]
+        for selector in selectors:
+            selector = selector.strip()
+            if selector.split(" ")[0] in _WILDCARDS:
+                items.append((f"{prefix}._", None))
+                continue
+            match = _SELECTOR_RE.match(selector)
+            if match is None or match.group(2) == "_":  # malformed, or hidden: {User => _}
+                continue
+            name = match.group(1).strip("`")
+            items.append((f"{prefix}.{name}" if prefix else name, match.group(2) or name))
+    return items
+
+
+def _find_classes(masked: str) -> list[_ClassScope]:
+    """Class-like declarations with their bodies' line extents, bases and fields."""
+    scopes = []
+    for match in _CLASS_RE.finditer(masked):
+        modifiers, package, kind, name = match.groups()
+        head_end, body_start, body_end = _declaration_extent(masked, match.end())
+        head = masked[match.end() : head_end]
+        scope = _ClassScope(
+            kind="package object" if package else kind,
+            name=name,
+            modifiers=modifiers.split(),
+            start_line=masked.count("\n", 0, match.start()) + 1,
+            end_line=masked.count("\n", 0, body_end) + 1,
+            bases=_bases(head),
+        )
+        _read_parameters(head, scope)
+        if body_start < body_end:
+            _read_body(masked[body_start:body_end], scope)
+        scopes.append(scope)
+    return scopes
+
+
+def _declaration_extent(masked: str, start: int) -> tuple[int, int, int]:
+    """(end of the header, start of the body, end of the body) after a class name.
+
+    The body is braced, or indented below a header ending in ``:`` (Scala 3);
+    without a body, start and end are both the end of the header.
+    """
+    depth = 0
+    i = start
+    while i < len(masked):
+        char = masked[i]
+        if char in "([":
+            depth += 1
+        elif char in ")]":
+            depth -= 1
+        elif depth == 0 and char == "{":
+            close = _matching_brace(masked, i)
+            return i, i + 1, close
+        elif depth == 0 and char == "\n":
+            following = masked[i + 1 :].lstrip()
+            if not following.startswith(("extends", "with", "derives", "{", "(")):
+                header = masked[start:i].rstrip()
+                if header.endswith(":"):  # class Repo: (Scala 3 indented body)
+                    return i, i + 1, _indented_end(masked, start, i + 1)
+                return i, i, i
+        i += 1
+    return i, i, i
+
+
+def _matching_brace(text: str, open_brace: int) -> int:
+    depth = 0
+    for i in range(open_brace, len(text)):
+        if text[i] == "{":
+            depth += 1
+        elif text[i] == "}":
+            depth -= 1
+            if depth == 0:
+                return i
+    return len(text)
+
+
+def _indented_end(masked: str, declaration: int, body_start: int) -> int:
+    """End offset of the lines after *body_start* indented deeper than the declaration."""
+    line_start = masked.rfind("\n", 0, declaration) + 1
+    line = masked[line_start:declaration]
+    indent = len(line) - len(line.lstrip())
+    end = body_start
+    offset = body_start
+    for text in masked[body_start:].split("\n"):
+        if text.strip():
+            if len(text) - len(text.lstrip()) <= indent:
+                break
+            end = offset + len(text)
+        offset += len(text) + 1
+    return end
+
+
+def _bases(head: str) -> list[str]:
+    """Bare names of the types after ``extends``: class A extends B(x) with C, or B, C."""
+    extends = re.search(r"\bextends\b(.*?)(?:\bderives\b|:\s*$|$)", head, re.S)
+    if not extends:
+        return []
+    clause = extends.group(1)
+    while True:  # type and constructor arguments, innermost first: Base(f(x))
+        stripped = re.sub(r"\[[^\[\]]*\]|\([^()]*\)", "", clause)
+        if stripped == clause:
+            break
+        clause = stripped
+    names = []
+    for part in re.split(r",|\bwith\b", clause):
+        part = part.strip()
+        if re.fullmatch(r"[\w.]+", part):
+            names.append(part.split(".")[-1])
+    return names
+
+
+def _read_parameters(head: str, scope: _ClassScope) -> None:
+    """Constructor parameters that are fields: val/var ones, or all of a case class's."""
+    lists = re.findall(r"\(([^()]*(?:\([^()]*\)[^()]*)*)\)", head.split("extends")[0])
+    for index, params in enumerate(lists):
+        for param in _split_top_level(params):
+            match = _PARAM_RE.match(param)
+            if match is None:
+                continue
+            keyword, name = match.groups()
+            is_case_field = "case" in scope.modifiers and index == 0
+            if (keyword or is_case_field) and name not in scope.fields:
+                scope.fields.append(name)
+
+
+def _read_body(body: str, scope: _ClassScope) -> None:
+    """val/var members declared directly in the body, not inside its defs."""
+    depth = 0
+    for line in body.split("\n"):
+        if depth == 0:
+            match = _MEMBER_RE.match(line)
+            if match and match.group(1) not in scope.fields:
+                scope.fields.append(match.group(1))
+        depth += line.count("{") - line.count("}")
+
+
+def _attach_members(syntax: FileSyntax, scopes: list[_ClassScope]) -> None:
+    """Methods, fields, bases and abstractness of the parsed classes."""
+    # Local defs (inside another def) are not members
+    members = [
+        fn
+        for fn in syntax.functions
+        if not any(
+            other is not fn and other.start_line < fn.start_line <= other.end_line
+            for other in syntax.functions
+        )
+    ]
+    owner: dict[int, _ClassScope] = {}
+    for fn in members:
+        enclosing = [s for s in scopes if s.start_line <= fn.start_line <= s.end_line]
+        if enclosing:
+            owner[id(fn)] = min(enclosing, key=lambda s: s.end_line - s.start_line)
+
+    unclaimed = list(syntax.classes)
+    for scope in scopes:
+        cls = _claim_class(unclaimed, scope.name)
+        if cls is None:
+            continue
+        methods: list[FunctionDef] = [fn for fn in members if owner.get(id(fn)) is scope]
+        cls.methods = methods + [m for m in cls.methods if m not in methods]
+        cls.fields += [f for f in scope.fields if f not in cls.fields]
+        if not cls.bases:
+            cls.bases = list(scope.bases)
+        if scope.kind == "trait" or "abstract" in scope.modifiers:
+            cls.is_abstract = True
+
+
+def _claim_class(unclaimed: list[ClassDef], name: str) -> ClassDef | None:
+    for cls in unclaimed:
+        if cls.name == name:
+            unclaimed.remove(cls)
+            return cls
+    return None
@@ -0,0 +1,242 @@
+"""Vue and Svelte single-file components.
+
+A component file (``.vue``, ``.svelte``) holds up to three kinds of block:
+
+    script    JavaScript or TypeScript (``lang="ts"``); Vue may have a
+              ``<script>`` and a ``<script setup>``, Svelte a module and an
+              instance script
+    template  the markup: Vue's top-level ``<template>``, or in Svelte
+              everything outside the script and style blocks
+    style     CSS (or a preprocessor), not analyzed as code
+
+``split_component`` finds the blocks and ``script_source`` keeps only the
+script lines, dedented, blanking every other line, so the result parses as
+an ordinary JavaScript/TypeScript module with the component's line numbers.
+``annotate_component`` then adds the template as one more function,
+``<template>``: its nesting depth is that of the elements and control blocks
+(``{#if}``, ``{#each}``) inside it, and its call targets are the script
+functions its expressions refer to (``@click="save"``, ``{{ total() }}``,
+``on:click={save}``), so functions used only from the markup still have
+a caller in the call graph. ``<script src="./logic.ts">`` is an import.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from typing import Any
+
+from .syntax import FileSyntax, FunctionDef, ImportDecl
+
+COMPONENT_SUFFIXES = (".vue", ".svelte")
+TEMPLATE_FUNCTION = "<template>"
+
+_BLOCK_OPEN_RE = re.compile(r"^<(script|template|style)\b([^>]*?)(/?)>")
+_ATTR_RE = re.compile(r"([\w:@.-]+)(?:\s*=\s*(?:\"([^\"]*)\"|'([^']*)'|([^\s>]+)))?")
+_TAG_RE = re.compile(r"<(/?)([A-Za-z][\w.:-]*)((?:\"[^\"]*\"|'[^']*'|[^'\">])*?)(/?)>")
+_SVELTE_BLOCK_RE = re.compile(r"\{([#/])(if|each|await|key|snippet)\b")
+_VUE_BINDING_RE = re.compile(
+    r"(?<![\w-])(?:[:@#]|v-)[\w:.\[\]-]*\s*=\s*(?:\"([^\"]*)\"|'([^']*)')"
+)
+_MUSTACHE_RE = re.compile(r"\{\{(.*?)\}\}", re.DOTALL)
+_SVELTE_EXPRESSION_RE = re.compile(r"\{([^{}]*)\}")
+_SVELTE_KEYWORD_RE = re.compile(r"^\s*(?:[#/:@]\w+(?:\s+if)?)")
+_NAME_RE = re.compile(r"(?<![\w$.])([A-Za-z_$][\w$]*)")
+_TOKEN_RE = re.compile(r"[\w$]+|[^\w\s]")
+_VOID_ELEMENTS = frozenset(
+    {
+        "area",
+        "base",
+        "br",
+        "col",
+        "embed",
+        "hr",
+        "img",
+        "input",
+        "link",
+        "meta",
+        "source",
+        "track",
+        "wbr",
+    }
+)
+_SCRIPT_LANGUAGES = {"ts": "typescript", "typescript": "typescript", "tsx": "tsx", "jsx": "tsx"}
+
+
+@dataclass
+class Block:
+    """A script, template or style block; lines are 1-indexed, content only."""
+
+    kind: str
+    start_line: int
+    end_line: int
+    attrs: dict[str, str] = field(default_factory=dict)
+    text: str = ""
+
+    @property
+    def lang(self) -> str:
+        return self.attrs.get("lang", "").lower()
+
+
+def is_component(path: Any) -> bool:
+    return str(path).lower().endswith(COMPONENT_SUFFIXES)
+
+
+def is_svelte(path: Any) -> bool:
+    return str(path).lower().endswith(".svelte")
+
+
+def split_component(content: str, svelte: bool = False) -> list[Block]:
+    """The top-level blocks of a component, in file order.
+
+    Block tags must start a line. In a Svelte component, the lines outside
+    script and style blocks form one template block.
+    """
+    lines = content.splitlines()
+    blocks: list[Block] = []
+    taken: set[int] = set()  # line numbers of script and style blocks, tags included
+    index = 0
+    while index < len(lines):
+        match = _BLOCK_OPEN_RE.match(lines[index])
+        if match is None or (svelte and match[1] == "template"):
+            index += 1
+            continue
+        kind, attrs = match[1], _attrs(match[2])
+        close = index if match[3] else _close_line(lines, index, kind)  # <script src />
+        inner = lines[index + 1 : close]
+        blocks.append(Block(kind, index + 2, close, attrs, "\n".join(inner)))
+        taken.update(range(index + 1, close + 2))
+        index = close + 1
+    if svelte:
+        blocks.extend(_svelte_markup(lines, taken))
+        blocks.sort(key=lambda b: b.start_line)
+    return blocks
+
+
+def _attrs(text: str) -> dict[str, str]:
+    return {m[1]: m[2] or m[3] or m[4] or "" for m in _ATTR_RE.finditer(text)}
+
+
+def _close_line(lines: list[str], open_index: int, kind: str) -> int:
+    """Index of the line closing the block opened at *open_index*.
+
+    Nested ``<template>`` tags (``v-if`` groups, slots) are counted.
+    """
+    depth = 0
+    for index in range(open_index, len(lines)):
+        line = lines[index]
+        if kind == "template":
+            depth += len(re.findall(r"<template\b(?:[^>]*[^/])?>", line))
+        else:
+            depth = 1
+        depth -= line.count(f"</{kind}>")
+        if depth <= 0:
+            return index
+    return len(lines)
+
+
+def _svelte_markup(lines: list[str], taken: set[int]) -> list[Block]:
+    markup = [n for n, line in enumerate(lines, 1) if n not in taken and line.strip()]
+    if not markup:
+        return []
+    start, end = markup[0], markup[-1]
+    text = "\n".join("" if n in taken else lines[n - 1] for n in range(start, end + 1))
+    return [Block("template", start, end, {}, text)]
+
+
+def script_language(blocks: list[Block]) -> str:
+    """javascript, typescript or tsx, from the ``lang`` of the script blocks."""
+    languages = {_SCRIPT_LANGUAGES.get(b.lang, "javascript") for b in blocks if b.kind == "script"}
+    for language in ("tsx", "typescript"):
+        if language in languages:
+            return language
+    return "javascript"
+
+
+def script_source(content: str, blocks: list[Block]) -> str:
+    """*content* with every line outside a script block blanked.
+
+    Script blocks are dedented: Svelte scripts are usually indented under
+    their tag, and the regex parser reads only top-level declarations.
+    """
+    lines = content.splitlines()
+    kept = [""] * len(lines)
+    for block in blocks:
+        if block.kind == "script":
+            numbers = range(block.start_line, block.end_line + 1)
+            indents = [
+                len(lines[n - 1]) - len(lines[n - 1].lstrip())
+                for n in numbers
+                if lines[n - 1].strip()
+            ]
+            indent = min(indents, default=0)
+            for number in numbers:
+                line = lines[number - 1]
+                kept[number - 1] = line[indent:] if line[:indent].isspace() else line.lstrip()
+    return "\n".join(kept) + "\n" if kept else ""
+
+
+def template_depth(text: str) -> int:
+    """Deepest nesting of elements and Svelte control blocks in *text*."""
+    depth = deepest = 0
+    events = sorted(
+        [(m.start(), m) for m in _TAG_RE.finditer(text)]
+        + [(m.start(), m) for m in _SVELTE_BLOCK_RE.finditer(text)]
+    )
+    for _, match in events:
+        if match.re is _SVELTE_BLOCK_RE:
+            closing = match[1] == "/"
+        elif match[4] or match[2].lower() in _VOID_ELEMENTS:
+            continue  # self-closing
+        else:
+            closing = match[1] == "/"
+        depth = max(depth - 1, 0) if closing else depth + 1
+        deepest = max(deepest, depth)
+    return deepest
+
+
+def template_names(text: str, svelte: bool = False) -> list[str]:
+    """Names the template's expressions refer to, in order of first use."""
+    expressions = [m[1] for m in _MUSTACHE_RE.finditer(text)]
+    expressions += [m[1] if m[1] is not None else m[2] for m in _VUE_BINDING_RE.finditer(text)]
+    if svelte:
+        expressions += [
+            _SVELTE_KEYWORD_RE.sub("", m[1]) for m in _SVELTE_EXPRESSION_RE.finditer(text)
+        ]
+    names: dict[str, None] = {}
+    for expression in expressions:
+        # Quoted strings are text, not names
+        expression = re.sub(r"\"[^\"]*\"|'[^']*'", "", expression)
+        for match in _NAME_RE.finditer(expression):
+            names.setdefault(match[1])
+    return list(names)
+
+
+def annotate_component(syntax: FileSyntax, blocks: list[Block], svelte: bool = False) -> None:
+    """Add the template as the ``<template>`` function, and script src imports."""
+    for block in blocks:
+        source = block.attrs.get("src")
+        if block.kind == "script" and source:
+            syntax.imports.append(ImportDecl(source, []))
+
+    template = next((b for b in blocks if b.kind == "template"), None)
+    if template is None or not template.text.strip():
+        return
+    defined = {fn.name for fn in syntax.functions}
+    for cls in syntax.classes:
+        defined.update(method.name for method in cls.methods)
+    calls = [name for name in template_names(template.text, svelte) if name in defined]
+    # Like the script's functions, without call targets when regex-parsed
+    parsed = any(fn.call_targets is not None for fn in syntax.functions)
+    syntax.functions.append(
+        FunctionDef(
+            TEMPLATE_FUNCTION,
+            [],
+            len(_TOKEN_RE.findall(template.text)),
+            0,
+            template_depth(template.text),
+            template.start_line,
+            template.end_line,
+            call_targets=calls if parsed else None,
+        )
+    )
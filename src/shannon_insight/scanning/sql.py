@@ -0,0 +1,302 @@
+"""SQL statements, table references and table columns, recovered from source text.
+
+Migrations and stored procedures are parsed statement by statement. A
+statement ends at the delimiter (``;``, or whatever ``DELIMITER`` set for
+MySQL) outside of dollar-quoted bodies (``$$ ... $$``) and BEGIN/CASE ...
+END blocks, or at a T-SQL ``GO`` line. ``annotate_sql`` runs after the
+fallback parser, which reads ``CREATE FUNCTION/PROCEDURE/TRIGGER`` as
+functions and ``CREATE TABLE/VIEW`` as classes, and fills in:
+
+    imports     one per table the file reads or writes (FROM, JOIN,
+                INSERT INTO, UPDATE, REFERENCES, ALTER TABLE, CREATE
+                INDEX ... ON), lowercased and without schema; names of
+                CTEs and function calls are not tables
+    classes     the columns of each CREATE TABLE, as fields
+
+The graph builder resolves a table to the file that creates it (see
+graph/builder.py), so later migrations and procedures depend on the
+migration that introduced their tables.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from functools import lru_cache
+
+from .masking import blank
+from .syntax import FileSyntax, ImportDecl
+
+# Single-quoted strings ('' escapes a quote), line and block comments
+_MASK_RE = re.compile(r"'(?:''|[^'])*'|--[^\n]*|/\*[\s\S]*?\*/")
+_DOLLAR_QUOTE_RE = re.compile(r"\$(\w*)\$")
+_WORD_RE = re.compile(r"[A-Za-z_]\w*")
+_DELIMITER_RE = re.compile(r"[ \t]*DELIMITER[ \t]+(\S+)[ \t]*(?:\n|$)", re.I)
+_GO_RE = re.compile(r"[ \t]*GO[ \t]*(?:\n|$)", re.I)
+# END IF, END LOOP... close blocks whose opener is not counted
+_END_OF_UNCOUNTED_RE = re.compile(r"\s+(?:IF|LOOP|WHILE|REPEAT|FOR)\b", re.I)
+# BEGIN; and BEGIN TRANSACTION start a transaction, not a block
+_TRANSACTION_RE = re.compile(r"\s*(?:;|TRANSACTION\b|TRAN\b|WORK\b)", re.I)
+
+_NAME = r'(?:[\w$]+|"[^"]+"|`[^`]+`|\[[^\]]+\])'
+_QUALIFIED = _NAME + r"(?:\s*\.\s*" + _NAME + r")*"
+_TABLE_REF_RE = re.compile(
+    r"\b(?:FROM|JOIN|(?:INSERT|MERGE|REPLACE)\s+INTO|UPDATE|REFERENCES|TRUNCATE|TABLE)"
+    r"\s+(?:ONLY\s+|IF\s+(?:NOT\s+)?EXISTS\s+)?(" + _QUALIFIED + r")",
+    re.I,
+)
+_ON_TABLE_RE = re.compile(r"\b(?:INDEX|TRIGGER)\b[^;]*?\bON\s+(" + _QUALIFIED + r")", re.I)
+_FROM_LIST_RE = re.compile(
+    r"\s*(?:(?:AS\s+)?(?!(?:WHERE|JOIN|ON|GROUP|ORDER|LIMIT)\b)\w+\s*)?,\s*(" + _QUALIFIED + r")",
+    re.I,
+)
+# FROM inside EXTRACT(YEAR FROM ts) and IS DISTINCT FROM x names no table
+_NOT_A_SOURCE_RE = re.compile(
+    r"\b(?:EXTRACT|SUBSTRING|TRIM|POSITION|OVERLAY)\s*\([^()]*\)|\bDISTINCT\s+FROM\b", re.I
+)
+_CTE_RE = re.compile(r"\b(\w+)\s+AS\s+(?:NOT\s+)?(?:MATERIALIZED\s+)?\(", re.I)
+_CREATE_TABLE_RE = re.compile(
+    r"\bCREATE\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:(?:TEMP|TEMPORARY|UNLOGGED)\s+)?TABLE\s+"
+    r"(?:IF\s+NOT\s+EXISTS\s+)?(" + _QUALIFIED + r")\s*\(",
+    re.I,
+)
+# Words that follow FROM/TABLE/UPDATE without naming a table
+_NOT_TABLES = frozenset(
+    {
+        "select",
+        "lateral",
+        "set",
+        "values",
+        "dual",
+        "unnest",
+        "each",
+        "function",
+        "procedure",
+        "trigger",
+        "view",
+        "index",
+        "column",
+        "constraint",
+        "statement",
+        "row",
+        "of",
+        "on",
+        "or",
+        "skip",
+        "nowait",
+    }
+)
+# Entries of a CREATE TABLE list that declare constraints, not columns
+_CONSTRAINT_WORDS = frozenset(
+    {"constraint", "primary", "foreign", "unique", "check", "index", "key", "exclude", "like"}
+)
+
+_COMPLEXITY_RE = re.compile(
+    r"\b(?:JOIN|WHEN|AND|OR|UNION|INTERSECT|EXCEPT|ELSIF|ELSEIF)\b|\(\s*SELECT\b"
+    r"|(?<!END )\b(?:IF|LOOP|WHILE)\b"
+)
+# IF EXISTS guards of DDL, BETWEEN x AND y and WHILE ... LOOP are not extra decisions
+_NOT_DECISIONS_RE = re.compile(
+    r"\b(?:TABLE|INDEX|VIEW|SCHEMA|EXTENSION|COLUMN|TYPE|FUNCTION|PROCEDURE|TRIGGER"
+    r"|SEQUENCE|CONSTRAINT|DATABASE|ROLE) IF (?:NOT )?EXISTS\b|\bBETWEEN\b|\bWHILE\b[^;]*?\bLOOP\b"
+)
+
+
+@dataclass(frozen=True)
+class SqlStatement:
+    """One statement of a SQL file."""
+
+    text: str  # comments and string contents blanked
+    start_line: int
+    end_line: int
+
+    @property
+    def keyword(self) -> str:
+        """The statement's first word, uppercased: SELECT, CREATE, ALTER..."""
+        match = _WORD_RE.search(self.text)
+        return match.group(0).upper() if match else ""
+
+    @property
+    def complexity(self) -> int:
+        return statement_complexity(self.text)
+
+
+def mask(content: str) -> str:
+    """*content* with comments and string contents blanked (line numbers kept)."""
+    return _MASK_RE.sub(blank, content)
+
+
+def split_statements(content: str) -> list[SqlStatement]:
+    """The statements of a SQL file, in order."""
+    masked = mask(content)
+    statements = []
+    for start, end in statement_spans(masked):
+        text = masked[start:end]
+        if not text.strip():
+            continue
+        lead = len(text) - len(text.lstrip())
+        statements.append(
+            SqlStatement(
+                text=text.strip(),
+                start_line=masked.count("\n", 0, start + lead) + 1,
+                end_line=masked.count("\n", 0, start + len(text.rstrip())) + 1,
+            )
+        )
+    return statements
+
+
+@lru_cache(maxsize=16)
+def statement_spans(masked: str) -> list[tuple[int, int]]:
+    """(start, end) offsets of each statement in masked text, delimiters excluded."""
+    spans: list[tuple[int, int]] = []
+    delimiter = ";"
+    depth = 0
+    start = i = 0
+    while i < len(masked):
+        if i == 0 or masked[i - 1] == "\n":
+            directive = _DELIMITER_RE.match(masked, i)
+            if directive:
+                spans.append((start, i))
+                delimiter = directive.group(1)
+                start = i = directive.end()
+                continue
+            batch = _GO_RE.match(masked, i)
+            if batch and depth <= 0:
+                spans.append((start, i))
+                start = i = batch.end()
+                depth = 0
+                continue
+        char = masked[i]
+        if char == "$":
+            quote = _DOLLAR_QUOTE_RE.match(masked, i)
+            if quote:
+                close = masked.find(quote.group(0), quote.end())
+                i = len(masked) if close == -1 else close + len(quote.group(0))
+                continue
+        word = _WORD_RE.match(masked, i) if char.isalpha() or char == "_" else None
+        if word is not None and (i == 0 or not _is_word_char(masked[i - 1])):
+            upper = word.group(0).upper()
+            if upper == "CASE" or (
+                upper == "BEGIN" and not _TRANSACTION_RE.match(masked, word.end())
+            ):
+                depth += 1
+            elif upper == "END" and not _END_OF_UNCOUNTED_RE.match(masked, word.end()):
+                depth -= 1
+            i = word.end()
+            continue
+        if depth <= 0 and masked.startswith(delimiter, i):
+            spans.append((start, i))
+            i += len(delimiter)
+            start = i
+            depth = 0
+            continue
+        i += 1
+    spans.append((start, len(masked)))
+    return [(s, e) for s, e in spans if masked[s:e].strip()]
+
+
+def statement_complexity(text: str) -> int:
+    """1 + joins, subqueries, set operations, CASE branches, AND/OR and IF/loops.
+
+    *text* should be masked (see mask), so keywords in strings and
+    comments do not count.
+    """
+    normalized = " ".join(text.split()).upper()
+    decisions = len(_COMPLEXITY_RE.findall(normalized))
+    return 1 + max(0, decisions - len(_NOT_DECISIONS_RE.findall(normalized)))
+
+
+def annotate_sql(syntax: FileSyntax, content: str) -> None:
+    """Rebuild *syntax*'s imports as referenced tables and fill in table columns."""
+    masked = mask(content)
+    syntax.imports = [ImportDecl(source=table, names=[]) for table in referenced_tables(content)]
+
+    columns = {
+        table_name(m.group(1)): _columns(masked, m.end() - 1)
+        for m in _CREATE_TABLE_RE.finditer(masked)
+    }
+    for cls in syntax.classes:
+        fields = columns.get(cls.name.lower())
+        if fields:
+            cls.fields += [f for f in fields if f not in cls.fields]
+
+
+def referenced_tables(content: str) -> list[str]:
+    """Tables *content* reads or writes, in order of first reference."""
+    masked = mask(content)
+    ctes = {m.group(1).lower() for m in _CTE_RE.finditer(masked)}
+
+    tables: list[str] = []
+    for name, is_source, end in _table_references(_NOT_A_SOURCE_RE.sub(blank, masked)):
+        table = table_name(name)
+        if is_source and masked[end:].lstrip().startswith("("):
+            continue  # a function call: FROM generate_series(1, 10)
+        if table and table not in ctes and table not in _NOT_TABLES and table not in tables:
+            tables.append(table)
+    return tables
+
+
+def table_name(name: str) -> str:
+    """Bare lowercase table name: "public"."Users" and [dbo].[users] are both users."""
+    last = re.split(r"\s*\.\s*", name.strip())[-1]
+    return last.strip('"`[]').lower()
+
+
+def _is_word_char(char: str) -> bool:
+    return char.isalnum() or char in "_$"
+
+
+def _table_references(masked: str) -> list[tuple[str, bool, int]]:
+    """(name, is a FROM/JOIN source, end offset) of every table reference, in order.
+
+    Only sources can be function calls; a name followed by parentheses
+    after INTO or REFERENCES is a table and its column list.
+    """
+    found = []
+    for match in _TABLE_REF_RE.finditer(masked):
+        keyword = match.group(0)[:4].upper()
+        is_source = keyword in ("FROM", "JOIN")
+        found.append((match.start(1), match.group(1), is_source, match.end(1)))
+        if keyword == "FROM":
+            end = match.end(1)
+            while True:  # FROM users u, orders o
+                more = _FROM_LIST_RE.match(masked, end)
+                if more is None:
+                    break
+                found.append((more.start(1), more.group(1), True, more.end(1)))
+                end = more.end(1)
+    for match in _ON_TABLE_RE.finditer(masked):
+        found.append((match.start(1), match.group(1), False, match.end(1)))
+    return [(name, is_source, end) for _, name, is_source, end in sorted(found)]
+
+
+def _columns(masked: str, open_paren: int) -> list[str]:
+    """Column names in the parenthesized list of a CREATE TABLE."""
+    depth = 0
+    for close in range(open_paren, len(masked)):
+        depth += {"(": 1, ")": -1}.get(masked[close], 0)
+        if depth == 0:
+            break
+    columns = []
+    for entry in _split_top_level(masked[open_paren + 1 : close]):
+        first = re.match(_NAME, entry)
+        if first is None:
+            continue
+        name = first.group(0).strip('"`[]')
+        if name.lower() not in _CONSTRAINT_WORDS:
+            columns.append(name)
+    return columns
+
+
+def _split_top_level(text: str) -> list[str]:
+    """*text* split on commas outside parentheses."""
+    parts, depth, current = [], 0, []
+    for char in text:
+        depth += {"(": 1, ")": -1}.get(char, 0)
+        if char == "," and depth == 0:
+            parts.append("".join(current))
+            current = []
+        else:
+            current.append(char)
+    parts.append("".join(current))
+    return [p.strip() for p in parts if p.strip()]
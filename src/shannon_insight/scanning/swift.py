@@ -0,0 +1,185 @@
+"""Swift imports and type members, recovered from source text.
+
+Swift imports whole modules; ``import struct Models.User`` and
+``@testable import App`` still import the module. ``annotate_swift`` runs
+after either parser and rewrites the file's imports and types:
+
+    imports     one per module (``Models``), with the declaration kind's
+                symbol as its name when one is given (``User``)
+    classes     funcs and inits are attached to the innermost class,
+                struct, enum, actor, protocol or extension whose braces
+                hold them; let/var members are its fields. Protocols are
+                abstract, and extensions are marked ``is_extension``
+
+An extension adds members to a type declared elsewhere, often in another
+file (``FeedViewController+DataSource.swift``). Extensions stay separate
+ClassDefs here; per-type size and complexity merge them back into the
+declaration (see signals/type_sizes.py).
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+
+from .masking import blank
+from .syntax import ClassDef, FileSyntax, FunctionDef, ImportDecl
+
+# Multi-line and single-line string literals, block and line comments
+_MASK_RE = re.compile(
+    r'"""[\s\S]*?"""|"(?:\\.|[^"\\\n])*"|/\*[\s\S]*?\*/|//[^\n]*'
+)
+_IMPORT_RE = re.compile(
+    r"^[ \t]*(?:@\w+\s+)*import\s+"
+    r"(?:(typealias|struct|class|enum|protocol|let|var|func)\s+)?(\w+(?:\.\w+)*)",
+    re.M,
+)
+
+_MODIFIERS = r"public|private|fileprivate|internal|open|package|final|indirect"
+_TYPE_RE = re.compile(
+    r"^[ \t]*(?:@\w+(?:\([^)]*\))?\s+)*((?:(?:" + _MODIFIERS + r")\s+)*)"
+    r"(class|struct|enum|actor|protocol|extension)\s+"
+    r"(?!(?:func|var|let|override|final|static|subscript)\b)((?:\w+\.)*\w+)",
+    re.M,
+)
+_MEMBER_RE = re.compile(
+    r"^\s*(?:@\w+(?:\([^)]*\))?\s+)*"
+    r"(?:(?:(?:public|private|fileprivate|internal|open|package)(?:\(set\))?"
+    r"|static|class|final|override|lazy|weak|unowned|nonisolated|dynamic)\s+)*"
+    r"(?:let|var)\s+(\w+)"
+)
+
+
+@dataclass
+class _TypeScope:
+    """A type declaration or extension and the extent of its body."""
+
+    kind: str
+    name: str
+    start_line: int
+    end_line: int  # line of the closing brace
+    bases: list[str] = field(default_factory=list)
+    fields: list[str] = field(default_factory=list)
+
+
+def annotate_swift(syntax: FileSyntax, content: str) -> None:
+    """Rebuild *syntax*'s imports as one per module and fill in type members."""
+    masked = _MASK_RE.sub(blank, content)
+
+    imports: list[ImportDecl] = []
+    by_module: dict[str, ImportDecl] = {}
+    for match in _IMPORT_RE.finditer(masked):
+        kind, path = match.groups()
+        module = path.split(".")[0]
+        decl = by_module.get(module)
+        if decl is None:
+            decl = by_module[module] = ImportDecl(source=module, names=[])
+            imports.append(decl)
+        if kind and "." in path and path.split(".")[-1] not in decl.names:
+            decl.names.append(path.split(".")[-1])
+    syntax.imports = imports
+
+    _attach_members(syntax, _find_types(masked))
+
+
+def _find_types(masked: str) -> list[_TypeScope]:
+    """Type declarations and extensions with their bodies' line extents, bases and fields."""
+    scopes = []
+    for match in _TYPE_RE.finditer(masked):
+        _, kind, name = match.groups()
+        brace = masked.find("{", match.end())
+        if brace == -1:
+            continue
+        close = _matching_brace(masked, brace)
+        scope = _TypeScope(
+            kind=kind,
+            name=name.split(".")[-1],
+            start_line=masked.count("\n", 0, match.start()) + 1,
+            end_line=masked.count("\n", 0, close) + 1,
+            bases=_bases(masked[match.end() : brace]),
+        )
+        _read_body(masked[brace + 1 : close], scope)
+        scopes.append(scope)
+    return scopes
+
+
+def _matching_brace(text: str, open_brace: int) -> int:
+    depth = 0
+    for i in range(open_brace, len(text)):
+        if text[i] == "{":
+            depth += 1
+        elif text[i] == "}":
+            depth -= 1
+            if depth == 0:
+                return i
+    return len(text)
+
+
+def _bases(head: str) -> list[str]:
+    """Bare names of the inherited types: class A<T>: B, C where T: D."""
+    while True:  # generic arguments, innermost first: Base<Result<T>>
+        stripped = re.sub(r"<[^<>]*>", "", head)
+        if stripped == head:
+            break
+        head = stripped
+    inherits = re.match(r"\s*:(.*?)(?:\bwhere\b|$)", head, re.S)
+    if not inherits:
+        return []
+    names = []
+    for part in inherits.group(1).split(","):
+        part = part.strip()
+        if re.fullmatch(r"[\w.]+", part):
+            names.append(part.split(".")[-1])
+    return names
+
+
+def _read_body(body: str, scope: _TypeScope) -> None:
+    """let/var members declared directly in the body, not inside its funcs."""
+    depth = 0
+    for line in body.split("\n"):
+        if depth == 0:
+            match = _MEMBER_RE.match(line)
+            if match and match.group(1) not in scope.fields:
+                scope.fields.append(match.group(1))
+        depth += line.count("{") - line.count("}")
+
+
+def _attach_members(syntax: FileSyntax, scopes: list[_TypeScope]) -> None:
+    """Methods, fields, bases, abstractness and extension flags of the parsed types."""
+    # Local funcs (inside another func) are not members
+    members = [
+        fn
+        for fn in syntax.functions
+        if not any(
+            other is not fn and other.start_line < fn.start_line <= other.end_line
+            for other in syntax.functions
+        )
+    ]
+    owner: dict[int, _TypeScope] = {}
+    for fn in members:
+        enclosing = [s for s in scopes if s.start_line <= fn.start_line <= s.end_line]
+        if enclosing:
+            owner[id(fn)] = min(enclosing, key=lambda s: s.end_line - s.start_line)
+
+    unclaimed = list(syntax.classes)
+    for scope in scopes:
+        cls = _claim_class(unclaimed, scope.name)
+        if cls is None:
+            continue
+        methods: list[FunctionDef] = [fn for fn in members if owner.get(id(fn)) is scope]
+        cls.methods = methods + [m for m in cls.methods if m not in methods]
+        cls.fields += [f for f in scope.fields if f not in cls.fields]
+        if not cls.bases:
+            cls.bases = list(scope.bases)
+        if scope.kind == "protocol":
+            cls.is_abstract = True
+        if scope.kind == "extension":
+            cls.is_extension = True
+
+
+def _claim_class(unclaimed: list[ClassDef], name: str) -> ClassDef | None:
+    for cls in unclaimed:
+        if cls.name == name:
+            unclaimed.remove(cls)
+            return cls
+    return None
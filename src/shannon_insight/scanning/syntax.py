@@ -2,8 +2,9 @@
 
 FileSyntax provides structured AST data for each file:
     - Per-function: body_tokens, nesting_depth, call_targets, decorators
-    - Per-class: bases, methods, fields, is_abstract
+    - Per-class: bases, methods, fields, is_abstract, is_extension
     - Per-import: source, names, resolved_path
+    - Per-file: parse_errors, the spans tree-sitter could not parse
 
 Both tree-sitter and regex fallback produce FileSyntax.
 Consumers check `fn.call_targets is not None` to detect tree-sitter parsing.
@@ -28,6 +29,8 @@ class FunctionDef:
         end_line: Ending line number (1-indexed)
         call_targets: Syntactic call targets (None if regex-parsed)
         decorators: Decorator names (e.g., ["property", "abstractmethod"])
+        cell: Index of the notebook cell the function starts in (None outside
+            notebooks)
     """
 
     name: str
@@ -39,6 +42,7 @@ class FunctionDef:
     end_line: int
     call_targets: list[str] | None = None
     decorators: list[str] = field(default_factory=list)
+    cell: int | None = None
 
     @property
     def is_stub(self) -> bool:
@@ -92,6 +96,8 @@ class ClassDef:
         methods: Methods defined in this class
         fields: Field/attribute names
         is_abstract: True if ABC, Protocol, or has abstractmethod
+        is_extension: True for a Swift extension, adding members to a type
+            declared elsewhere (possibly in another file)
     """
 
     name: str
@@ -99,6 +105,7 @@ class ClassDef:
     methods: list[FunctionDef]
     fields: list[str]
     is_abstract: bool = False
+    is_extension: bool = False
 
 
 @dataclass
@@ -121,6 +128,27 @@ class ImportDecl:
         return self.resolved_path is None
 
 
+@dataclass(frozen=True)
+class ErrorSpan:
+    """Lines a syntax error kept out of the analysis.
+
+    Attributes:
+        start_line: First line (1-indexed)
+        end_line: Last line (1-indexed)
+        missing: For a token the parser inserted to recover (``)``, ``}``),
+            that token; the span's lines were still analyzed
+    """
+
+    start_line: int
+    end_line: int
+    missing: str = ""
+
+    @property
+    def lines(self) -> int:
+        """Lines left out of the analysis (0 for an inserted token)."""
+        return 0 if self.missing else self.end_line - self.start_line + 1
+
+
 @dataclass
 class FileSyntax:
     """Complete syntax extraction for a file.
@@ -136,6 +164,8 @@ class FileSyntax:
         language: Detected language
         has_main_guard: True if `if __name__ == "__main__":` detected
         mtime: Last modified timestamp (for cache invalidation)
+        parse_errors: Syntax errors tree-sitter recovered from; functions,
+            classes and metrics cover the rest of the file (see recovery.py)
         _lines: Cached line count (set during parsing)
         _tokens: Cached token count (set during parsing)
         _complexity: Cached complexity score (set during parsing)
@@ -148,6 +178,7 @@ class FileSyntax:
     language: str
     has_main_guard: bool = False
     mtime: float = 0.0
+    parse_errors: list[ErrorSpan] = field(default_factory=list)
     # Cached metrics (set during parsing to avoid re-reading content)
     _lines: int = 0
     _tokens: int = 0
@@ -14,6 +14,33 @@ Fallback behavior:
     3. If tree-sitter not installed: use regex fallback
 
 The fallback rate is tracked. If >20% of files use fallback, a warning is logged.
+
+C and C++ files go through the ConditionalResolver first, so only the
+#if branches selected by ``c_defines`` are parsed (see preprocessor.py).
+Go files the ``go_build`` configuration would not compile (build
+constraints, _GOOS/_GOARCH names, cgo) are skipped (see gobuild.py).
+Ruby results are annotated with class members and Rails DSL entities
+afterwards (see rails.py); PHP imports are rewritten as namespace-resolved
+class names (see php.py), Scala imports as one fully qualified name per
+selected member (see scala.py), Swift imports as one per module, with
+extensions marked on their types (see swift.py), SQL imports as the
+tables each file references (see sql.py), HCL results as resources and
+module calls (see hcl.py), YAML results as Kubernetes resources or
+top-level entries (see yaml.py), and Protocol Buffers results as services,
+RPCs and messages (see proto.py).
+
+Jupyter notebooks are parsed as the Python source of their code cells, and
+that source, not the notebook JSON, is what ``content_cache`` holds; each
+function is annotated with its cell (see notebook.py).
+
+Vue and Svelte components are parsed as the JavaScript or TypeScript of
+their script blocks, other lines blanked, and their template is added as
+the ``<template>`` function (see sfc.py); ``content_cache`` holds the whole
+component.
+
+Files with the extensions of a custom grammar (the ``grammars`` config,
+see grammars.py) are parsed with that grammar, and take its name as their
+language.
 """
 
 from __future__ import annotations
@@ -23,13 +50,34 @@ import os
 from concurrent.futures import ThreadPoolExecutor, as_completed
 from pathlib import Path
 from threading import Lock
-from typing import TYPE_CHECKING
+from typing import TYPE_CHECKING, Any, Mapping, Sequence
 
+from ..portable import portable_path
 from .fallback import RegexFallbackScanner
+from .gobuild import GoBuildConfig
+from .grammars import CustomGrammarParser, parse_grammars
+from .hcl import annotate_hcl
 from .languages import detect_language
 from .normalizer import TreeSitterNormalizer
+from .notebook import annotate_notebook, is_notebook, notebook_source
+from .php import annotate_php
+from .preprocessor import ConditionalResolver
+from .proto import annotate_proto
+from .rails import annotate_ruby
+from .scala import annotate_scala
+from .sfc import (
+    annotate_component,
+    is_component,
+    is_svelte,
+    script_language,
+    script_source,
+    split_component,
+)
+from .sql import annotate_sql
+from .swift import annotate_swift
 from .syntax import FileSyntax
 from .treesitter_parser import TREE_SITTER_AVAILABLE
+from .yaml import annotate_yaml
 
 if TYPE_CHECKING:
     pass
@@ -49,14 +97,30 @@ class SyntaxExtractor:
         total_count: Total files processed
     """
 
-    def __init__(self, max_workers: int | None = None) -> None:
+    def __init__(
+        self,
+        max_workers: int | None = None,
+        c_defines: Sequence[str] = (),
+        c_conditionals: str = "evaluate",
+        grammars: Mapping[str, Any] | None = None,
+        go_build: GoBuildConfig | None = None,
+    ) -> None:
         """Initialize extractor with tree-sitter normalizer and regex fallback.
 
         Args:
             max_workers: Max parallel workers for extract_all(). Defaults to CPU count (max 8).
+            c_defines: Macros (NAME or NAME=VALUE) defined when resolving C/C++ #if blocks.
+            c_conditionals: "evaluate" keeps the selected #if branches, "all" parses
+                every branch as written.
+            grammars: The ``grammars`` config table of custom tree-sitter grammars.
+            go_build: Only Go files this configuration compiles are parsed; None
+                parses them all.
         """
         self._normalizer = TreeSitterNormalizer() if TREE_SITTER_AVAILABLE else None
         self._fallback = RegexFallbackScanner()
+        self._resolver = ConditionalResolver(c_defines) if c_conditionals == "evaluate" else None
+        self._custom = CustomGrammarParser(parse_grammars(grammars or {}))
+        self._go_build = go_build
         self._max_workers = max_workers or _DEFAULT_WORKERS
         self._lock = Lock()  # Thread-safe counter updates
         self.fallback_count = 0
@@ -74,7 +138,8 @@ class SyntaxExtractor:
             content_cache: Optional dict to store file content for later reuse
 
         Returns:
-            FileSyntax or None if file cannot be read
+            FileSyntax or None if file cannot be read or is not compiled
+            in the ``go_build`` configuration
         """
         try:
             content = file_path.read_text(encoding="utf-8", errors="replace")
@@ -82,30 +147,107 @@ class SyntaxExtractor:
         except OSError as e:
             logger.debug(f"Cannot read {file_path}: {e}")
             return None
+        return self.extract_source(
+            portable_path(file_path, root_dir), content, root_dir, mtime, content_cache
+        )
+
+    def extract_source(
+        self,
+        rel_path: str,
+        content: str,
+        root_dir: Path,
+        mtime: float = 0.0,
+        content_cache: dict[str, str] | None = None,
+    ) -> FileSyntax | None:
+        """Extract FileSyntax from the text of a file that need not exist on disk.
 
-        rel_path = str(file_path.relative_to(root_dir))
-        language = detect_language(file_path)
+        Args:
+            rel_path: Path of the file, relative to *root_dir*
+            content: Its text
+            root_dir: Root directory the path is relative to
+            mtime: Modification time recorded on the result
+            content_cache: Optional dict to store file content for later reuse
+
+        Returns:
+            FileSyntax or None as for extract()
+        """
+        file_path = Path(rel_path)
+        grammar = self._custom.grammar_for(file_path) if self._custom else None
+        language = grammar.name if grammar is not None else detect_language(file_path, content)
+
+        notebook = is_notebook(file_path)
+        if notebook:
+            source = notebook_source(content)
+            if source is None:
+                logger.debug(f"Skipping {file_path}: not a Python notebook")
+                return None
+            content = source
+
+        if (
+            language == "go"
+            and self._go_build is not None
+            and not self._go_build.compiles(rel_path, content)
+        ):
+            logger.debug(f"Skipping {file_path}: excluded by Go build constraints")
+            return None
 
         # Cache content for later reuse (e.g., compression ratio)
         if content_cache is not None:
             content_cache[rel_path] = content
 
+        blocks = None
+        if is_component(file_path):
+            blocks = split_component(content, svelte=is_svelte(file_path))
+            language = script_language(blocks)
+            content = script_source(content, blocks)
+
+        if self._resolver is not None and language in ("c", "cpp"):
+            content = self._resolver.resolve(content, cplusplus=language == "cpp")
+
         # Thread-safe counter updates
         with self._lock:
             self.total_count += 1
 
         # Try tree-sitter first
-        if self._normalizer is not None:
+        syntax = None
+        if grammar is not None:
+            syntax = self._custom.parse_file(content, rel_path, grammar, root_dir, mtime)
+            if syntax is not None:
+                with self._lock:
+                    self.treesitter_count += 1
+        elif self._normalizer is not None:
             syntax = self._normalizer.parse_file(content, rel_path, language, mtime)
             if syntax is not None:
                 with self._lock:
                     self.treesitter_count += 1
-                return syntax
 
         # Fall back to regex
-        with self._lock:
-            self.fallback_count += 1
-        return self._fallback.parse(content, rel_path, language, mtime)
+        if syntax is None:
+            with self._lock:
+                self.fallback_count += 1
+            syntax = self._fallback.parse(content, rel_path, language, mtime)
+
+        if language == "ruby":
+            annotate_ruby(syntax, content)
+        elif language == "php":
+            annotate_php(syntax, content)
+        elif language == "scala":
+            annotate_scala(syntax, content)
+        elif language == "swift":
+            annotate_swift(syntax, content)
+        elif language == "sql":
+            annotate_sql(syntax, content)
+        elif language == "hcl":
+            annotate_hcl(syntax, content)
+        elif language == "yaml":
+            annotate_yaml(syntax, content)
+        elif language == "proto":
+            annotate_proto(syntax, content)
+        if notebook:
+            annotate_notebook(syntax, content)
+        if blocks is not None:
+            annotate_component(syntax, blocks, svelte=is_svelte(file_path))
+        return syntax
 
     def extract_all(
         self,
@@ -62,6 +62,13 @@ try:
     except ImportError:
         pass
 
+    try:
+        import tree_sitter_kotlin
+
+        _language_modules["kotlin"] = tree_sitter_kotlin
+    except ImportError:
+        pass
+
     try:
         import tree_sitter_rust
 
@@ -76,6 +83,27 @@ try:
     except ImportError:
         pass
 
+    try:
+        import tree_sitter_php
+
+        _language_modules["php"] = tree_sitter_php
+    except ImportError:
+        pass
+
+    try:
+        import tree_sitter_scala
+
+        _language_modules["scala"] = tree_sitter_scala
+    except ImportError:
+        pass
+
+    try:
+        import tree_sitter_swift
+
+        _language_modules["swift"] = tree_sitter_swift
+    except ImportError:
+        pass
+
     try:
         import tree_sitter_c
 
@@ -208,6 +236,21 @@ class TreeSitterParser:
         except Exception:
             return []
 
+    def query_error(self, query_str: str, language: str) -> str | None:
+        """Why *query_str* does not compile for *language*; None when it does.
+
+        query() returns no captures for an invalid query; this tells the two
+        apart, for tools that check queries written by users.
+        """
+        lang = self._languages.get(language)
+        if not TREE_SITTER_AVAILABLE or lang is None:
+            return f"no tree-sitter grammar for {language}"
+        try:
+            _tree_sitter_module.Query(lang, query_str)
+        except Exception as e:
+            return str(e) or type(e).__name__
+        return None
+
     def is_language_supported(self, language: str) -> bool:
         """Check if a language is supported."""
         return language in self._parsers
@@ -0,0 +1,268 @@
+"""YAML documents as trees of mappings, sequences and scalars.
+
+Block-style YAML is read line by line from its indentation: ``key: value``
+entries, ``- item`` sequence entries (``- key: value`` opens a mapping
+inside the item), block scalars (``|``, ``>``), whose lines are skipped,
+and ``---`` / ``...`` document markers. Flow collections (``{a: 1}``,
+``[1, 2]``, also over several lines) and quoted strings are scalars;
+comments are dropped. Helm template directives on lines of their own
+(``{{- if .Values.ingress.enabled }}``) are skipped, so chart templates
+parse as the manifests they render.
+
+``annotate_yaml`` runs after the fallback parser and fills in:
+
+    functions   one per Kubernetes resource ("Deployment/web", from kind
+                and metadata.name); in other YAML one per top-level key,
+                or per item of a top-level sequence. Nesting depth is the
+                depth of the collections inside
+    classes, imports    cleared
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from functools import lru_cache
+from typing import Iterator, Optional
+
+from .syntax import FileSyntax, FunctionDef
+
+SEQUENCE_ITEM = "-"
+
+_DOCUMENT_START_RE = re.compile(r"^---(?:\s|$)")
+_KEY_RE = re.compile(
+    r"""^(?P<key>"[^"]*"|'[^']*'|[^\s#'"{}\[\],&*!|>%@`][^#]*?)\s*:(?:\s+(?P<value>.*)|$)"""
+)
+_BLOCK_SCALAR_RE = re.compile(r"^(?:[&!]\S*\s+)*[|>][-+0-9]*$")
+# Anchors and tags before a nested collection: "spec: &defaults", "data: !!map"
+_PROPERTIES_RE = re.compile(r"^(?:[&!]\S*\s*)+$")
+_TEMPLATE_LINE_RE = re.compile(r"^\{\{.*\}\}$")
+_WORD_RE = re.compile(r"\w+")
+
+
+@dataclass
+class YamlNode:
+    """A mapping entry, sequence item or document, with what it contains."""
+
+    key: str  # mapping key; SEQUENCE_ITEM for an item; "" for a document
+    start_line: int
+    end_line: int
+    value: str = ""  # inline scalar, unquoted
+    children: list[YamlNode] = field(default_factory=list)
+
+    @property
+    def depth(self) -> int:
+        """Levels of collections, this one included (0 for a scalar)."""
+        return 1 + max((c.depth for c in self.children), default=0) if self.children else 0
+
+    @property
+    def scalars(self) -> int:
+        """Scalar values in this node and every node inside it."""
+        return sum(c.scalars for c in self.children) if self.children else 1
+
+    def child(self, key: str) -> Optional[YamlNode]:
+        return next((c for c in self.children if c.key == key), None)
+
+    def walk(self) -> Iterator[YamlNode]:
+        """This node and every node inside it, depth first."""
+        yield self
+        for child in self.children:
+            yield from child.walk()
+
+    def deepest_chain(self) -> list[YamlNode]:
+        """Collections from this node down to the deepest one, outermost first."""
+        chain: list[YamlNode] = []
+        node: Optional[YamlNode] = self
+        while node is not None and node.children:
+            chain.append(node)
+            node = max(node.children, key=lambda c: c.depth)
+        return chain
+
+
+def key_path(chain: list[YamlNode]) -> str:
+    """Dotted key path of a chain of nodes: spec.template.spec.containers[].env[]."""
+    path = ""
+    for node in chain:
+        if node.key == SEQUENCE_ITEM:
+            path += "[]"
+        elif node.key:
+            path += ("." if path else "") + node.key
+    return path
+
+
+def resource_name(document: YamlNode) -> Optional[str]:
+    """``Kind/name`` of a Kubernetes resource document, ``Kind`` without a name."""
+    kind = document.child("kind")
+    if kind is None or not kind.value:
+        return None
+    metadata = document.child("metadata")
+    name = metadata.child("name") if metadata is not None else None
+    return f"{kind.value}/{name.value}" if name is not None and name.value else kind.value
+
+
+def strip_comment(line: str) -> str:
+    """*line* without a trailing comment (``#`` at the start or after a space)."""
+    quote = ""
+    for i, char in enumerate(line):
+        if quote:
+            if char == quote:
+                quote = ""
+        elif char in "'\"" and (i == 0 or line[i - 1] in " \t:-[{,"):
+            quote = char
+        elif char == "#" and (i == 0 or line[i - 1] in " \t"):
+            return line[:i].rstrip()
+    return line.rstrip()
+
+
+def _unquote(value: str) -> str:
+    if len(value) >= 2 and value[0] == value[-1] and value[0] in "'\"":
+        return value[1:-1]
+    return value
+
+
+def _open_brackets(text: str) -> int:
+    text = re.sub(r"'[^']*'|\"[^\"]*\"", "", text)
+    return text.count("[") + text.count("{") - text.count("]") - text.count("}")
+
+
+class _Parser:
+    def __init__(self) -> None:
+        self.documents: list[YamlNode] = []
+        self.stack: list[tuple[int, YamlNode]] = []
+        self.block_scalar: Optional[tuple[int, YamlNode]] = None  # (owner indent, owner)
+        self.flow_depth = 0  # brackets left open by a flow collection
+
+    def feed(self, lines: list[str]) -> list[YamlNode]:
+        for number, raw in enumerate(lines, 1):
+            self._line(number, raw)
+        self._end_document()
+        return self.documents
+
+    def _line(self, number: int, raw: str) -> None:
+        if self.block_scalar is not None:
+            indent = self.block_scalar[0]
+            if not raw.strip():
+                return
+            if len(raw) - len(raw.lstrip(" ")) > indent:
+                self._extend(number)
+                return
+            self.block_scalar = None
+        text = strip_comment(raw)
+        stripped = text.strip()
+        if not stripped:
+            return
+        if self.flow_depth > 0:
+            self.flow_depth += _open_brackets(stripped)
+            self._extend(number)
+            return
+        if _DOCUMENT_START_RE.match(text) or stripped == "...":
+            self._end_document()
+            return
+        if _TEMPLATE_LINE_RE.match(stripped):
+            return
+        if not self.stack:
+            self.stack = [(-1, YamlNode("", number, number))]
+        self._entry(len(text) - len(text.lstrip(" ")), stripped, number)
+        self._extend(number)
+
+    def _entry(self, indent: int, rest: str, number: int) -> None:
+        while rest:
+            if rest == SEQUENCE_ITEM or rest.startswith(SEQUENCE_ITEM + " "):
+                while self.stack[-1][0] > indent or (
+                    self.stack[-1][0] == indent and self.stack[-1][1].key == SEQUENCE_ITEM
+                ):
+                    self.stack.pop()
+                item = YamlNode(SEQUENCE_ITEM, number, number)
+                self.stack[-1][1].children.append(item)
+                self.stack.append((indent, item))
+                after = rest[1:]
+                indent += 1 + len(after) - len(after.lstrip(" "))
+                rest = after.strip()
+                continue
+            match = _KEY_RE.match(rest)
+            if match is None:
+                self._scalar(rest)  # a plain scalar, or the continuation of one
+                return
+            while self.stack[-1][0] >= indent:
+                self.stack.pop()
+            node = YamlNode(_unquote(match["key"].strip()), number, number)
+            self.stack[-1][1].children.append(node)
+            self.stack.append((indent, node))
+            self._scalar(match["value"] or "")
+            return
+
+    def _scalar(self, value: str) -> None:
+        """Set the inline value of the innermost node."""
+        indent, node = self.stack[-1]
+        if not value or _PROPERTIES_RE.match(value):
+            return
+        if _BLOCK_SCALAR_RE.match(value):
+            self.block_scalar = (indent, node)
+            return
+        if node.key and not node.value:
+            node.value = _unquote(value)
+        self.flow_depth = max(0, _open_brackets(value))
+
+    def _extend(self, number: int) -> None:
+        for _, node in self.stack:
+            node.end_line = number
+
+    def _end_document(self) -> None:
+        if self.stack and self.stack[0][1].children:
+            document = self.stack[0][1]
+            document.start_line = document.children[0].start_line
+            self.documents.append(document)
+        self.stack = []
+        self.block_scalar = None
+        self.flow_depth = 0
+
+
+@lru_cache(maxsize=64)
+def _parse_cached(content: str) -> tuple[YamlNode, ...]:
+    return tuple(_Parser().feed(content.splitlines()))
+
+
+def parse_yaml(content: str) -> list[YamlNode]:
+    """The documents of a YAML file, empty ones left out.
+
+    Results are cached by content; treat the nodes as read-only.
+    """
+    return list(_parse_cached(content))
+
+
+def _units(document: YamlNode) -> list[tuple[str, YamlNode]]:
+    resource = resource_name(document)
+    if resource is not None:
+        return [(resource, document)]
+    units = []
+    for index, child in enumerate(document.children):
+        if child.key != SEQUENCE_ITEM:
+            units.append((child.key, child))
+            continue
+        name = child.child("name")
+        units.append((name.value if name is not None and name.value else f"item {index}", child))
+    return units
+
+
+def annotate_yaml(syntax: FileSyntax, content: str) -> None:
+    """Replace fallback results with Kubernetes resources or top-level entries."""
+    lines = content.splitlines()
+    functions = []
+    for document in parse_yaml(content):
+        for name, node in _units(document):
+            body = lines[node.start_line - 1 : node.end_line]
+            text = "\n".join(strip_comment(line) for line in body)
+            functions.append(
+                FunctionDef(
+                    name=name,
+                    params=[],
+                    body_tokens=len(_WORD_RE.findall(text)),
+                    signature_tokens=1,
+                    nesting_depth=node.depth,
+                    start_line=node.start_line,
+                    end_line=node.end_line,
+                )
+            )
+    syntax.functions = functions
+    syntax.classes = []
+    syntax.imports = []
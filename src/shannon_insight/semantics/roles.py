@@ -166,8 +166,9 @@ def classify_role(syntax: FileSyntax, root_dir: str = "") -> Role:
     if _is_test_file(path_lower):
         return Role.TEST
 
-    # 2. ENTRY_POINT - __main__ guard, entry decorators, or pyproject.toml scripts
-    if syntax.has_main_guard or _has_entry_decorators(syntax):
+    # 2. ENTRY_POINT - __main__ guard, entry decorators, or pyproject.toml scripts.
+    # Notebooks are run, never imported
+    if syntax.has_main_guard or _has_entry_decorators(syntax) or path_lower.endswith(".ipynb"):
         return Role.ENTRY_POINT
 
     # Check pyproject.toml entry points
@@ -303,7 +304,16 @@ def _is_model_file(syntax: FileSyntax) -> bool:
     if not syntax.classes:
         return False
 
-    model_indicators = {"dataclass", "BaseModel", "Model", "Schema", "NamedTuple", "TypedDict"}
+    model_indicators = {
+        "dataclass",
+        "BaseModel",
+        "Model",
+        "Schema",
+        "NamedTuple",
+        "TypedDict",
+        "ActiveRecord",
+        "ApplicationRecord",
+    }
     model_count = 0
 
     # Filename hint: "models.py" or "schemas.py" with classes having >= 2 fields
@@ -351,10 +361,12 @@ def _is_service(syntax: FileSyntax) -> bool:
             if base_simple in SERVICE_BASES or "Handler" in base_simple or "View" in base_simple:
                 return True
 
-    # Check for HTTP decorators
+    # Check for HTTP decorators (and Rails controller actions)
     for fn in syntax.functions:
         for dec in fn.decorators:
-            if any(http in dec for http in ("get", "post", "put", "delete", "route")):
+            if dec == "action" or any(
+                http in dec for http in ("get", "post", "put", "delete", "route")
+            ):
                 return True
 
     return False
@@ -26,11 +26,14 @@ CATEGORY_MAP: dict[str, str] = {
     "hollow_code": "incomplete",
     "phantom_imports": "incomplete",
     "orphan_code": "incomplete",
+    "dead_code": "incomplete",
     "incomplete_implementation": "incomplete",
     "duplicate_incomplete": "incomplete",
+    "unused_resource": "incomplete",
     # fragile
     "high_risk_hub": "fragile",
     "god_file": "fragile",
+    "load_bearing_function": "fragile",
     # bug_magnet removed: duplicate of bug_attractor
     "thrashing_code": "fragile",
     "unstable_file": "fragile",
@@ -38,22 +41,50 @@ CATEGORY_MAP: dict[str, str] = {
     "bug_attractor": "fragile",
     "chronic_problem": "fragile",
     "directory_hotspot": "fragile",
+    "complexity_outlier": "fragile",
+    "untested_complexity": "fragile",
+    "deep_nesting": "fragile",
+    "god_class": "fragile",
+    "low_cohesion": "fragile",
+    "long_parameter_list": "fragile",
+    "data_clump": "fragile",
+    "long_procedure": "fragile",
+    "nested_dynamic_block": "fragile",
+    "variable_count_outlier": "fragile",
+    "deep_yaml_nesting": "fragile",
+    "values_file_outlier": "fragile",
+    "oversized_resource": "fragile",
     # tangled
     "hidden_coupling": "tangled",
     "accidental_coupling": "tangled",
     "dead_dependency": "tangled",
     "copy_paste_clone": "tangled",
+    "code_clone": "tangled",
+    "orchestrator_function": "tangled",
+    "duplicate_yaml_block": "tangled",
+    "duplicate_string_resource": "tangled",
+    "repeated_literal": "tangled",
+    "notebook_drift": "tangled",
+    "duplicate_files": "tangled",
     "layer_violation": "tangled",
     "zone_of_pain": "tangled",
     "boundary_mismatch": "tangled",
     "flat_architecture": "tangled",
     "architecture_erosion": "tangled",
+    "hexagonal_violation": "tangled",
+    "wide_api_surface": "tangled",
     "naming_drift": "tangled",
+    "vocabulary_drift": "tangled",
     # team
     "knowledge_silo": "team",
     "truck_factor": "team",
     "review_blindspot": "team",
     "conway_violation": "team",
+    # security
+    "crypto_policy_violation": "security",
+    "auth_flow_issue": "security",
+    # errors
+    "error_hygiene_issue": "errors",
 }
 
 CATEGORY_LABELS = {
@@ -61,6 +92,8 @@ CATEGORY_LABELS = {
     "fragile": "Fragile",
     "tangled": "Tangled",
     "team": "Team Risk",
+    "security": "Security",
+    "errors": "Error Hygiene",
 }
 
 
@@ -112,6 +145,7 @@ def build_dashboard_state(
     result: InsightResult,
     snapshot: TensorSnapshot,
     db_path: str | None = None,
+    history_url: str | None = None,
 ) -> dict[str, Any]:
     """Convert analysis results to the full dashboard JSON state.
 
@@ -126,6 +160,8 @@ def build_dashboard_state(
         The TensorSnapshot from analysis.
     db_path:
         Optional path to .shannon/history.db for trend data.
+    history_url:
+        The ``history_url`` setting; read from the project config if omitted.
     """
     findings = result.findings
     file_signals = snapshot.file_signals or {}
@@ -137,7 +173,7 @@ def build_dashboard_state(
     if db_path:
         project_root = Path(db_path).parent.parent
         try:
-            db = HistoryDB(str(project_root))
+            db = HistoryDB(str(project_root), url=history_url)
             db.connect()
             serializer = DashboardSerializer(db)
             logger.debug(f"Serializer created successfully for {project_root}")
@@ -159,7 +195,7 @@ def build_dashboard_state(
 
     # ── Categories ────────────────────────────────────────────────
     categories: dict[str, dict[str, Any]] = {}
-    for cat_key in ("incomplete", "fragile", "tangled", "team"):
+    for cat_key in ("incomplete", "fragile", "tangled", "team", "security", "errors"):
         cat_findings = [f for f in findings if CATEGORY_MAP.get(f.finding_type) == cat_key]
         high_count = sum(1 for f in cat_findings if f.severity >= 0.8)
         categories[cat_key] = {
@@ -196,7 +232,9 @@ def build_dashboard_state(
     # Query per-file signal trends if db available
     file_trends: dict[str, dict[str, list[float]]] = {}
     if db_path:
-        file_trends = _query_file_signal_trends(db_path, list(file_signals.keys()))
+        file_trends = _query_file_signal_trends(
+            db_path, list(file_signals.keys()), url=history_url
+        )
 
     for path, sig_dict in file_signals.items():
         # Collect findings for this file
@@ -226,7 +264,9 @@ def build_dashboard_state(
     # Query per-module signal trends if db available
     module_trends: dict[str, dict[str, list[float]]] = {}
     if db_path:
-        module_trends = _query_module_signal_trends(db_path, list(module_signals.keys()))
+        module_trends = _query_module_signal_trends(
+            db_path, list(module_signals.keys()), url=history_url
+        )
 
     for mod_path, mod_dict in module_signals.items():
         module_data = {
@@ -294,11 +334,11 @@ def build_dashboard_state(
             "movers": serializer.serialize_top_movers(),
         }
         # Add chronic findings if old query still works
-        old_trends = _query_trends(db_path) if db_path else None
+        old_trends = _query_trends(db_path, url=history_url) if db_path else None
         if old_trends and "chronic" in old_trends:
             trends["chronic"] = old_trends["chronic"]
     elif db_path:
-        trends = _query_trends(db_path)
+        trends = _query_trends(db_path, url=history_url)
 
     # ── Assemble ──────────────────────────────────────────────────
     state: dict[str, Any] = {
@@ -355,18 +395,16 @@ def _map_rich_color(color: str) -> str:
     return mapping.get(color, "var(--text)")
 
 
-def _query_trends(db_path: str) -> dict[str, Any] | None:
+def _query_trends(db_path: str, url: str | None = None) -> dict[str, Any] | None:
     """Query .shannon/history.db for trend data. Returns None on failure."""
-    path = Path(db_path)
-    if not path.exists():
-        return None
-
     # Derive project root from db_path (.shannon/history.db)
-    project_root = path.parent.parent
+    history = HistoryDB(str(Path(db_path).parent.parent), url=url)
+    if not history.exists():
+        return None
 
     trends: dict[str, Any] = {}
     try:
-        with HistoryDB(str(project_root)) as db:
+        with history as db:
             hq = HistoryQuery(db.conn)
 
             # Health trend
@@ -425,22 +463,20 @@ def _query_trends(db_path: str) -> dict[str, Any] | None:
 
 
 def _query_file_signal_trends(
-    db_path: str, file_paths: list[str], last_n: int = 10
+    db_path: str, file_paths: list[str], last_n: int = 10, url: str | None = None
 ) -> dict[str, dict[str, list[float]]]:
     """Query per-file signal trends from .shannon/history.db.
 
     Returns dict mapping file_path → {signal_name → [values oldest-to-newest]}
     Limited to last N snapshots for performance.
     """
-    path = Path(db_path)
-    if not path.exists() or not file_paths:
-        return {}
-
     # Derive project root from db_path (.shannon/history.db)
-    project_root = path.parent.parent
+    history = HistoryDB(str(Path(db_path).parent.parent), url=url)
+    if not history.exists() or not file_paths:
+        return {}
 
     try:
-        with HistoryDB(str(project_root)) as db:
+        with history as db:
             cur = db.conn.cursor()
 
             # Get last N snapshot IDs
@@ -485,22 +521,20 @@ def _query_file_signal_trends(
 
 
 def _query_module_signal_trends(
-    db_path: str, module_paths: list[str], last_n: int = 10
+    db_path: str, module_paths: list[str], last_n: int = 10, url: str | None = None
 ) -> dict[str, dict[str, list[float]]]:
     """Query per-module signal trends from .shannon/history.db.
 
     Returns dict mapping module_path → {signal_name → [values oldest-to-newest]}
     Limited to last N snapshots for performance.
     """
-    path = Path(db_path)
-    if not path.exists() or not module_paths:
-        return {}
-
     # Derive project root from db_path (.shannon/history.db)
-    project_root = path.parent.parent
+    history = HistoryDB(str(Path(db_path).parent.parent), url=url)
+    if not history.exists() or not module_paths:
+        return {}
 
     try:
-        with HistoryDB(str(project_root)) as db:
+        with history as db:
             cur = db.conn.cursor()
 
             # Get last N snapshot IDs
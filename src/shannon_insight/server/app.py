@@ -22,9 +22,14 @@ from starlette.websockets import WebSocket, WebSocketDisconnect
 from .state import ServerState
 
 if TYPE_CHECKING:
+    from ..persistence.bundle import ResultBundle
+    from .baseline import BaselineRotator
+    from .jobs import JobWorker
+    from .tenants import AnalysisQuota, TenantRegistry
     from .watcher import FileWatcher
 
 from ..persistence.database import HistoryDB
+from .heatmap import HeatmapBuilder, HeatmapError
 from .serializers import DashboardSerializer
 
 logger = logging.getLogger(__name__)
@@ -45,17 +50,51 @@ def _get_html() -> str:
     return _TEMPLATE_HTML
 
 
-def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starlette:
+def create_app(
+    state: ServerState,
+    watcher: FileWatcher | None = None,
+    rotator: BaselineRotator | None = None,
+    bundle: ResultBundle | None = None,
+    quota: AnalysisQuota | None = None,
+    jobs: JobWorker | None = None,
+) -> Starlette:
     """Build the Starlette application wired to *state*.
 
     Args:
         state: The shared server state for dashboard data
         watcher: Optional file watcher for triggering refresh
+        rotator: Optional baseline rotator for the /api/baseline endpoints
+        bundle: Optional result bundle for the /api/bundle endpoints
+        quota: Optional analysis quota; refresh and rotate answer 429 when full
+        jobs: Optional job worker; /readyz fails while it drains
     """
 
+    def _quota_exceeded() -> JSONResponse | None:
+        if quota is None or not quota.full:
+            return None
+        return JSONResponse(
+            {"error": f"Analysis quota reached ({quota.limit} concurrent)"},
+            status_code=429,
+        )
+
     async def homepage(request: Request) -> HTMLResponse:
         return HTMLResponse(_get_html())
 
+    # ── Probes (Kubernetes liveness / readiness) ────────────────────────
+
+    async def healthz(request: Request) -> JSONResponse:
+        """Liveness: the process serves requests. GET /healthz"""
+        return JSONResponse({"status": "ok"})
+
+    async def readyz(request: Request) -> JSONResponse:
+        """Readiness: an analysis is loaded and we are not draining. GET /readyz"""
+        queued = len(jobs.queue) if jobs is not None else 0
+        if jobs is not None and jobs.draining:
+            return JSONResponse({"status": "draining", "queued": queued}, status_code=503)
+        if state.get_state() is None:
+            return JSONResponse({"status": "starting", "queued": queued}, status_code=503)
+        return JSONResponse({"status": "ready", "queued": queued})
+
     async def api_state(request: Request) -> JSONResponse:
         data = state.get_state()
         if data is None:
@@ -162,6 +201,12 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
             }
         )
 
+    async def api_meta(request: Request) -> JSONResponse:
+        """Metric and finding type registry for generic dashboards. GET /api/meta"""
+        from ..meta import registry
+
+        return JSONResponse(registry())
+
     async def websocket_endpoint(websocket: WebSocket) -> None:
         await websocket.accept()
         queue: asyncio.Queue[Any] = asyncio.Queue(maxsize=32)
@@ -198,6 +243,25 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
             except Exception:
                 pass
 
+    async def rpc_endpoint(websocket: WebSocket) -> None:
+        """Stream JSON-RPC notifications (metric decorations) to editors."""
+        await websocket.accept()
+        queue: asyncio.Queue[Any] = asyncio.Queue(maxsize=256)
+        state.add_rpc_listener(queue)
+        try:
+            while True:
+                await websocket.send_json(await queue.get())
+        except (WebSocketDisconnect, asyncio.CancelledError):
+            pass
+        except Exception as exc:
+            logger.debug(f"RPC WebSocket error: {exc}")
+        finally:
+            state.remove_rpc_listener(queue)
+            try:
+                await websocket.close()
+            except Exception:
+                pass
+
     async def _ping_loop(websocket: WebSocket) -> None:
         """Send keepalive pings every 30 seconds."""
         try:
@@ -219,11 +283,14 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
                 {"error": "Refresh not available (no watcher configured)"},
                 status_code=503,
             )
+        busy = _quota_exceeded()
+        if busy is not None:
+            return busy
 
         # Run analysis in background thread to not block the request
         def _do_refresh():
             try:
-                watcher.run_analysis()
+                watcher.request_analysis("refresh")
             except Exception as exc:
                 logger.error("Refresh failed: %s", exc)
 
@@ -232,6 +299,11 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
 
     # ── History API endpoints ───────────────────────────────────────────
 
+    def _history(path: str) -> HistoryDB:
+        # The watcher's settings come from the tenant's config file, if any
+        url = watcher.settings.history_url if watcher is not None else None
+        return HistoryDB(path, url=url)
+
     def _get_analyzed_path() -> str | None:
         """Get analyzed path from current state."""
         current = state.get_state()
@@ -246,7 +318,7 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
             return JSONResponse({"error": "No analysis available"}, status_code=404)
         limit = int(request.query_params.get("limit", 50))
         try:
-            with HistoryDB(analyzed_path) as db:
+            with _history(analyzed_path) as db:
                 serializer = DashboardSerializer(db)
                 data = serializer.serialize_snapshot_list(limit=limit)
             return JSONResponse(data)
@@ -270,7 +342,7 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
         if not analyzed_path:
             return JSONResponse({"error": "No analysis available"}, status_code=404)
         try:
-            with HistoryDB(analyzed_path) as db:
+            with _history(analyzed_path) as db:
                 serializer = DashboardSerializer(db)
                 data = serializer.serialize_signal_evolution(
                     entity_type=signal_type,
@@ -290,7 +362,7 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
             return JSONResponse({"error": "No analysis available"}, status_code=404)
         limit = int(request.query_params.get("limit", 50))
         try:
-            with HistoryDB(analyzed_path) as db:
+            with _history(analyzed_path) as db:
                 serializer = DashboardSerializer(db)
                 data = serializer.serialize_finding_lifecycle(limit=limit)
             return JSONResponse(data)
@@ -305,7 +377,7 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
             return JSONResponse({"error": "No analysis available"}, status_code=404)
         snapshot_id = int(request.path_params["snapshot_id"])
         try:
-            with HistoryDB(analyzed_path) as db:
+            with _history(analyzed_path) as db:
                 serializer = DashboardSerializer(db)
                 data = serializer.serialize_snapshot_detail(snapshot_id)
             if data is None:
@@ -315,13 +387,101 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
             logger.warning(f"History snapshot detail query failed: {e}")
             return JSONResponse({"error": str(e)}, status_code=404)
 
+    # ── Baseline rotation API ───────────────────────────────────────────
+
+    async def api_baseline(request: Request) -> JSONResponse:
+        """Rotation status and baseline history. GET /api/baseline"""
+        if rotator is None:
+            return JSONResponse({"error": "Baseline rotation not configured"}, status_code=503)
+        try:
+            return JSONResponse(await asyncio.to_thread(rotator.status))
+        except Exception as e:
+            logger.warning(f"Baseline status query failed: {e}")
+            return JSONResponse({"error": str(e)}, status_code=500)
+
+    async def api_baseline_rotate(request: Request) -> JSONResponse:
+        """Re-baseline on the mainline tip, e.g. from a merge hook. POST /api/baseline/rotate"""
+        if rotator is None:
+            return JSONResponse({"error": "Baseline rotation not configured"}, status_code=503)
+        busy = _quota_exceeded()
+        if busy is not None:
+            return busy
+        reason = request.query_params.get("reason", "merge")
+        force = request.query_params.get("force", "").lower() in ("1", "true", "yes")
+        try:
+            result = await asyncio.to_thread(rotator.rotate, reason, force)
+        except Exception as e:
+            logger.error("Baseline rotation failed: %s", e)
+            return JSONResponse({"error": str(e)}, status_code=500)
+        return JSONResponse(result.to_dict())
+
+    # ── Result bundle API (random access, never fully loaded) ──────────
+
+    async def api_bundle(request: Request) -> JSONResponse:
+        """Bundle metadata and file list. GET /api/bundle"""
+        if bundle is None:
+            return JSONResponse({"error": "No bundle loaded (serve --bundle)"}, status_code=404)
+        meta = await asyncio.to_thread(lambda: bundle.meta)
+        return JSONResponse({**meta, "files": bundle.files(), "modules": bundle.modules()})
+
+    async def api_bundle_file(request: Request) -> JSONResponse:
+        """One file's signals. GET /api/bundle/file?path=..."""
+        if bundle is None:
+            return JSONResponse({"error": "No bundle loaded (serve --bundle)"}, status_code=404)
+        path = request.query_params.get("path", "")
+        signals = await asyncio.to_thread(bundle.file_signals, path)
+        if signals is None:
+            return JSONResponse({"error": f"{path} not in bundle"}, status_code=404)
+        return JSONResponse({"path": path, "signals": signals})
+
+    async def api_bundle_findings(request: Request) -> JSONResponse:
+        """Findings, optionally of one type. GET /api/bundle/findings?type=..."""
+        if bundle is None:
+            return JSONResponse({"error": "No bundle loaded (serve --bundle)"}, status_code=404)
+        finding_type = request.query_params.get("type")
+        records = await asyncio.to_thread(bundle.get, "findings") or []
+        if finding_type:
+            records = [r for r in records if r["finding_type"] == finding_type]
+        return JSONResponse({"findings": records})
+
+    # ── Heatmap overlay API ─────────────────────────────────────────────
+
+    heatmaps: dict[str, HeatmapBuilder] = {}
+
+    async def api_heatmap(request: Request) -> JSONResponse:
+        """Per-line heat data for one file: GET /api/heatmap?path=...&sha=..."""
+        analyzed_path = _get_analyzed_path()
+        if not analyzed_path:
+            return JSONResponse({"error": "No analysis available"}, status_code=404)
+        builder = heatmaps.get(analyzed_path)
+        if builder is None:
+            builder = heatmaps[analyzed_path] = HeatmapBuilder(analyzed_path)
+        try:
+            data = await asyncio.to_thread(
+                builder.build,
+                request.query_params.get("path", ""),
+                request.query_params.get("sha") or None,
+            )
+        except HeatmapError as e:
+            return JSONResponse({"error": str(e)}, status_code=404)
+        return JSONResponse(data, headers={"Access-Control-Allow-Origin": "*"})
+
     routes = [
         Route("/", homepage),
+        Route("/healthz", healthz),
+        Route("/readyz", readyz),
         Route("/api/state", api_state),
         Route("/api/refresh", api_refresh, methods=["POST"]),
         Route("/api/export/json", api_export_json),
         Route("/api/export/csv", api_export_csv),
         Route("/api/gate", api_gate),
+        Route("/api/meta", api_meta),
+        Route("/api/heatmap", api_heatmap),
+        Route("/api/baseline", api_baseline),
+        Route("/api/baseline/rotate", api_baseline_rotate, methods=["POST"]),
+        Route("/api/bundle", api_bundle),
+        Route("/api/bundle/file", api_bundle_file),
+        Route("/api/bundle/findings", api_bundle_findings),
         # History API
         Route("/api/history/snapshots", api_history_snapshots),
         Route("/api/history/findings", api_history_findings),
@@ -331,7 +491,42 @@ def create_app(state: ServerState, watcher: FileWatcher | None = None) -> Starle
             "/api/history/signal/{signal_type}/{signal_path:path}/{signal_name}", api_history_signal
         ),
         WebSocketRoute("/ws", websocket_endpoint),
+        WebSocketRoute("/rpc", rpc_endpoint),
         Mount("/static", app=StaticFiles(directory=str(_STATIC_DIR)), name="static"),
     ]
 
     return Starlette(routes=routes)
+
+
+def create_tenant_app(registry: TenantRegistry) -> Starlette:
+    """Build the multi-tenant application: one ``create_app`` per tenant.
+
+    Each tenant's API and WebSockets live under ``/t/<name>/``; the root
+    and ``/api/tenants`` list the tenants and their status.
+    """
+
+    async def tenants(request: Request) -> JSONResponse:
+        return JSONResponse(registry.status())
+
+    async def healthz(request: Request) -> JSONResponse:
+        return JSONResponse({"status": "ok"})
+
+    async def readyz(request: Request) -> JSONResponse:
+        # Tenants analyze in the background; each has its own /t/<name>/readyz
+        if registry.draining:
+            return JSONResponse({"status": "draining"}, status_code=503)
+        return JSONResponse({"status": "ready"})
+
+    routes = [
+        Route("/", tenants),
+        Route("/api/tenants", tenants),
+        Route("/healthz", healthz),
+        Route("/readyz", readyz),
+    ]
+    for name, tenant in registry.tenants.items():
+        app = create_app(
+            tenant.state, tenant.watcher, tenant.rotator, quota=tenant.quota, jobs=tenant.jobs
+        )
+        routes.append(Mount(f"/t/{name}", app=app))
+
+    return Starlette(routes=routes)
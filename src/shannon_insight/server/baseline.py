@@ -0,0 +1,210 @@
+"""Automatic baseline rotation for serve mode.
+
+Keeps the history baseline pinned to a current mainline snapshot instead of
+whatever was baselined by hand long ago. The mainline ref (``baseline_branch``
+or the detected default branch) is analyzed in a temporary git worktree, the
+snapshot is saved to ``.shannon/history.db`` and pinned as baseline. Every
+rotation is appended to ``baseline_history``, so earlier baselines stay
+queryable.
+
+Triggers (``baseline_rotation`` config):
+
+    schedule  every ``baseline_interval_minutes``
+    merge     whenever the mainline tip moves (polled), or on
+              ``POST /api/baseline/rotate`` from a merge hook
+
+Rotation is skipped when the baseline already points at the mainline tip.
+Findings that appear or disappear between the previous baseline and the new
+one are sent as finding_created / finding_resolved events to the configured
+``webhooks``.
+"""
+
+from __future__ import annotations
+
+import logging
+import shutil
+import subprocess
+import tempfile
+import threading
+from contextlib import contextmanager
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Any, Callable, Iterator, Optional
+
+from ..webhooks import deliver, finding_events, parse_webhooks
+
+logger = logging.getLogger(__name__)
+
+# How often "merge" mode checks the mainline tip
+MERGE_POLL_SECONDS = 60.0
+
+_GIT_TIMEOUT_SECONDS = 60
+
+
+@dataclass
+class RotationResult:
+    rotated: bool
+    ref: str
+    commit_sha: Optional[str] = None
+    snapshot_id: Optional[int] = None
+    reason: str = ""
+    message: str = ""
+
+    def to_dict(self) -> dict:
+        return dict(self.__dict__)
+
+
+def _git(root: str, *args: str) -> Optional[str]:
+    """Stripped stdout of a git command, or None on failure."""
+    try:
+        result = subprocess.run(
+            ["git", "-C", root, *args],
+            capture_output=True,
+            text=True,
+            timeout=_GIT_TIMEOUT_SECONDS,
+        )
+    except (FileNotFoundError, subprocess.TimeoutExpired):
+        return None
+    if result.returncode != 0:
+        return None
+    return result.stdout.strip()
+
+
+def detect_mainline(root: str) -> Optional[str]:
+    """Default branch ref: origin/HEAD's target, else local main or master."""
+    head = _git(root, "symbolic-ref", "--short", "refs/remotes/origin/HEAD")
+    if head:
+        return head
+    for branch in ("main", "master"):
+        if _git(root, "rev-parse", "--verify", "--quiet", f"refs/heads/{branch}"):
+            return branch
+    return None
+
+
+def mainline_tip(root: str, ref: str) -> Optional[str]:
+    """Commit SHA of *ref*, fetching first when it is a remote-tracking branch."""
+    remote, _, branch = ref.partition("/")
+    if branch and _git(root, "remote", "get-url", remote) is not None:
+        _git(root, "fetch", "--quiet", remote, branch)
+    return _git(root, "rev-parse", "--verify", "--quiet", f"{ref}^{{commit}}")
+
+
+@contextmanager
+def _worktree(root: str, sha: str) -> Iterator[Path]:
+    path = Path(tempfile.mkdtemp(prefix="shannon-baseline-"))
+    try:
+        if _git(root, "worktree", "add", "--detach", str(path), sha) is None:
+            raise RuntimeError(f"could not create worktree for {sha[:12]}")
+        yield path
+    finally:
+        _git(root, "worktree", "remove", "--force", str(path))
+        shutil.rmtree(path, ignore_errors=True)
+
+
+def _analyze(path: str) -> Any:
+    from ..api import analyze
+
+    _, snapshot = analyze(path=path, verbose=False, quiet=True)
+    return snapshot
+
+
+class BaselineRotator:
+    """Re-baselines history on the mainline tip on a schedule or on merge."""
+
+    def __init__(
+        self,
+        root_dir: str,
+        settings: Any,
+        analyze_fn: Callable[[str], Any] = _analyze,
+    ) -> None:
+        self.root_dir = str(Path(root_dir).resolve())
+        self.mode: str = settings.baseline_rotation
+        self.interval = settings.baseline_interval_minutes * 60.0
+        self.ref: Optional[str] = settings.baseline_branch or None
+        self.history_url: str = settings.history_url
+        self.webhooks = parse_webhooks(settings.webhooks)
+        self._analyze = analyze_fn
+
+        self._lock = threading.Lock()
+        self._stop_event = threading.Event()
+        self._thread: threading.Thread | None = None
+        self.last_result: Optional[RotationResult] = None
+
+    def start(self) -> None:
+        """Start the rotation thread (no-op when rotation is off)."""
+        if self.mode == "off" or (self._thread is not None and self._thread.is_alive()):
+            return
+        self._stop_event.clear()
+        self._thread = threading.Thread(target=self._loop, name="baseline-rotator", daemon=True)
+        self._thread.start()
+        logger.info("Baseline rotation (%s) started for %s", self.mode, self.root_dir)
+
+    def stop(self) -> None:
+        self._stop_event.set()
+        if self._thread is not None:
+            self._thread.join(timeout=5.0)
+            self._thread = None
+
+    def _loop(self) -> None:
+        period = self.interval if self.mode == "schedule" else MERGE_POLL_SECONDS
+        while not self._stop_event.is_set():
+            try:
+                self.rotate(reason=self.mode)
+            except Exception as e:
+                logger.error("Baseline rotation failed: %s", e)
+            self._stop_event.wait(period)
+
+    def rotate(self, reason: str = "manual", force: bool = False) -> RotationResult:
+        """Analyze the mainline tip and pin it as baseline unless already current."""
+        with self._lock:
+            self.last_result = self._rotate(reason, force)
+            return self.last_result
+
+    def _rotate(self, reason: str, force: bool) -> RotationResult:
+        from ..persistence import HistoryDB
+        from ..persistence.reader import load_tensor_snapshot
+
+        ref = self.ref or detect_mainline(self.root_dir)
+        if ref is None:
+            return RotationResult(False, "", reason=reason, message="no mainline branch found")
+        sha = mainline_tip(self.root_dir, ref)
+        if sha is None:
+            return RotationResult(False, ref, reason=reason, message=f"cannot resolve {ref}")
+
+        with HistoryDB(self.root_dir, url=self.history_url) as db:
+            current = db.get_baseline_history(limit=1)
+        if not force and current and current[0]["commit_sha"] == sha:
+            snapshot_id = current[0]["snapshot_id"]
+            return RotationResult(False, ref, sha, snapshot_id, reason, "baseline already current")
+
+        with _worktree(self.root_dir, sha) as path:
+            snapshot = self._analyze(str(path))
+        snapshot.commit_sha = sha
+        with HistoryDB(self.root_dir, url=self.history_url) as db:
+            previous = (
+                load_tensor_snapshot(db.conn, current[0]["snapshot_id"])
+                if current and self.webhooks
+                else None
+            )
+            snapshot_id = db.save_snapshot(snapshot)
+            db.set_baseline(snapshot_id, reason=reason, ref=ref)
+        logger.info("Baseline rotated to %s@%s (snapshot %d)", ref, sha[:12], snapshot_id)
+        if previous is not None:
+            project = Path(self.root_dir).name
+            events = finding_events(previous.findings, snapshot.findings, project, sha)
+            deliver(self.webhooks, events)
+        return RotationResult(True, ref, sha, snapshot_id, reason, "rotated")
+
+    def status(self) -> dict:
+        """Rotation settings, last result and baseline history for the API."""
+        from ..persistence import HistoryDB
+
+        with HistoryDB(self.root_dir, url=self.history_url) as db:
+            history = db.get_baseline_history()
+        return {
+            "mode": self.mode,
+            "ref": self.ref or detect_mainline(self.root_dir),
+            "interval_minutes": self.interval / 60.0,
+            "last": self.last_result.to_dict() if self.last_result else None,
+            "history": history,
+        }
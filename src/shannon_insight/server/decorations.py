@@ -0,0 +1,127 @@
+"""Per-function metric decorations streamed to editor extensions.
+
+Editor extensions connect to ``WS /rpc`` and receive JSON-RPC 2.0
+notifications whenever watched files change. Each notification carries the
+per-function metrics for one file, so an extension can draw gutter heat bars
+and trend arrows without waiting for the full analysis to finish::
+
+    {
+      "jsonrpc": "2.0",
+      "method": "shannon/metricDecorations",
+      "params": {
+        "path": "src/engine.py",
+        "version": 3,
+        "functions": [
+          {"name": "run", "start_line": 10, "end_line": 58, "lines": 49,
+           "nesting_depth": 4, "body_tokens": 312, "params": 2,
+           "heat": 0.71, "trend": "up"}
+        ]
+      }
+    }
+
+``trend`` compares against the previous notification for the same function:
+``new`` | ``up`` | ``down`` | ``same``. A deleted file is sent with an empty
+``functions`` list. Functions of a Jupyter notebook also carry ``cell``, the
+index of the cell they start in; their lines number the code extracted from
+the notebook (see scanning/notebook.py).
+"""
+
+from __future__ import annotations
+
+import logging
+from pathlib import Path
+from typing import Any
+
+from ..scanning.syntax import FileSyntax, FunctionDef
+from ..scanning.syntax_extractor import SyntaxExtractor
+
+logger = logging.getLogger(__name__)
+
+DECORATIONS_METHOD = "shannon/metricDecorations"
+
+# Saturation points for the heat score: a function at or beyond both limits
+# renders at full heat.
+_HEAT_LINES = 60
+_HEAT_NESTING = 5
+
+
+def function_heat(fn: FunctionDef) -> float:
+    """Heat in [0, 1] from function length and nesting depth (equal weight)."""
+    lines = max(1, fn.end_line - fn.start_line + 1)
+    size = min(1.0, lines / _HEAT_LINES)
+    depth = min(1.0, fn.nesting_depth / _HEAT_NESTING)
+    return round(0.5 * size + 0.5 * depth, 3)
+
+
+def function_metrics(syntax: FileSyntax) -> list[dict[str, Any]]:
+    """Per-function metric rows for one file, in source order."""
+    rows = []
+    for fn in sorted(syntax.functions, key=lambda f: f.start_line):
+        row: dict[str, Any] = {
+            "name": fn.name,
+            "start_line": fn.start_line,
+            "end_line": fn.end_line,
+            "lines": max(1, fn.end_line - fn.start_line + 1),
+            "nesting_depth": fn.nesting_depth,
+            "body_tokens": fn.body_tokens,
+            "params": len(fn.params),
+            "heat": function_heat(fn),
+        }
+        if fn.cell is not None:
+            row["cell"] = fn.cell
+        rows.append(row)
+    return rows
+
+
+class DecorationStream:
+    """Builds decoration notifications and remembers previous heat for trends.
+
+    Not thread-safe: owned by the file watcher thread.
+    """
+
+    def __init__(self, root_dir: str) -> None:
+        self.root = Path(root_dir)
+        self._extractor = SyntaxExtractor(max_workers=1)
+        self._previous: dict[str, dict[str, float]] = {}
+        self._versions: dict[str, int] = {}
+
+    def notifications(self, changed: list[str]) -> list[dict[str, Any]]:
+        """Return one JSON-RPC notification per changed path."""
+        messages = []
+        for rel_path in changed:
+            try:
+                messages.append(self._notification(rel_path))
+            except Exception as e:
+                logger.debug("Decoration metrics failed for %s: %s", rel_path, e)
+        return messages
+
+    def _notification(self, rel_path: str) -> dict[str, Any]:
+        full = self.root / rel_path
+        functions: list[dict[str, Any]] = []
+        if full.is_file():
+            syntax = self._extractor.extract(full, self.root)
+            if syntax is not None:
+                functions = function_metrics(syntax)
+
+        previous = self._previous.get(rel_path, {})
+        for row in functions:
+            row["trend"] = _trend(previous.get(row["name"]), row["heat"])
+        self._previous[rel_path] = {row["name"]: row["heat"] for row in functions}
+
+        version = self._versions.get(rel_path, 0) + 1
+        self._versions[rel_path] = version
+        return {
+            "jsonrpc": "2.0",
+            "method": DECORATIONS_METHOD,
+            "params": {"path": rel_path, "version": version, "functions": functions},
+        }
+
+
+def _trend(before: float | None, after: float) -> str:
+    if before is None:
+        return "new"
+    if after > before:
+        return "up"
+    if after < before:
+        return "down"
+    return "same"
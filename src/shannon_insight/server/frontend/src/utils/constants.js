@@ -17,6 +17,13 @@ export const SIGNAL_LABELS = {
   class_count: "Classes / Structs",
   max_nesting: "Deepest Nesting Level",
   cognitive_load: "Cognitive Complexity",
+  halstead_volume: "Halstead Volume",
+  halstead_difficulty: "Halstead Difficulty",
+  halstead_effort: "Halstead Effort",
+  maintainability_index: "Maintainability Index",
+  comment_density: "Comment Density",
+  commented_out_lines: "Commented-Out Code Lines",
+  undocumented_complexity: "Undocumented Complexity",
   todo_density: "TODO / FIXME Density",
   impl_gini: "Implementation Distribution",
   stub_ratio: "Stub / Empty Function Ratio",
@@ -34,6 +41,7 @@ export const SIGNAL_LABELS = {
   phantom_import_count: "Broken Imports",
   broken_call_count: "Broken Function Calls",
   compression_ratio: "Code Uniqueness (compression)",
+  token_entropy: "Token Entropy",
   semantic_coherence: "Code Focus (coherence)",
 
   // Semantic Structure (IR2 - Semantics)
@@ -120,6 +128,13 @@ export const SIGNAL_DESCRIPTIONS = {
   class_count: "Number of classes or structs defined",
   max_nesting: "Deepest level of nested blocks (if/for/while)",
   cognitive_load: "How hard this file is to understand (cyclomatic complexity)",
+  halstead_volume: "Size of the code in bits: tokens times log2 of distinct tokens",
+  halstead_difficulty: "How often the same operands are reused by distinct operators",
+  halstead_effort: "Estimated mental effort to read the file (difficulty x volume)",
+  maintainability_index: "0-100 from size, complexity and comments (below 10 is hard to maintain)",
+  comment_density: "Comment lines per line of code, commented-out code left out",
+  commented_out_lines: "Comment lines that read as code rather than prose",
+  undocumented_complexity: "Decision points in functions that have no doc comment",
   todo_density: "Number of TODO/FIXME comments per 100 LOC",
   impl_gini: "How evenly code is distributed across functions (Gini coefficient)",
   stub_ratio: "Fraction of functions that are empty or trivial",
@@ -137,6 +152,7 @@ export const SIGNAL_DESCRIPTIONS = {
   phantom_import_count: "Imports that point to files that do not exist",
   broken_call_count: "Function calls that cannot be resolved",
   compression_ratio: "Lower means more repetitive / duplicated code",
+  token_entropy: "How spread the file's tokens (or identifiers) are, in bits or 0-1",
   semantic_coherence: "How focused this file is on a single concept",
 
   // Semantic Structure
@@ -208,13 +224,13 @@ export const SIGNAL_CATEGORIES = [
     key: "size",
     name: "Size and Complexity",
     description: "How large and complex the file is",
-    signals: ["lines", "function_count", "class_count", "max_nesting", "cognitive_load", "todo_density", "impl_gini", "stub_ratio", "import_count"],
+    signals: ["lines", "function_count", "class_count", "max_nesting", "cognitive_load", "halstead_volume", "halstead_difficulty", "halstead_effort", "maintainability_index", "comment_density", "commented_out_lines", "undocumented_complexity", "todo_density", "impl_gini", "stub_ratio", "import_count"],
   },
   {
     key: "structure",
     name: "Position in Dependency Graph",
     description: "How this file relates to other files through imports",
-    signals: ["pagerank", "betweenness", "in_degree", "out_degree", "blast_radius_size", "depth", "community", "is_orphan", "phantom_import_count", "broken_call_count", "compression_ratio", "semantic_coherence"],
+    signals: ["pagerank", "betweenness", "in_degree", "out_degree", "blast_radius_size", "depth", "community", "is_orphan", "phantom_import_count", "broken_call_count", "compression_ratio", "token_entropy", "semantic_coherence"],
   },
   {
     key: "semantics",
@@ -284,6 +300,9 @@ export const SIGNAL_POLARITY = {
   raw_risk: true,
   churn_cv: true,
   cognitive_load: true,
+  halstead_volume: true,
+  halstead_difficulty: true,
+  halstead_effort: true,
   max_nesting: true,
   stub_ratio: true,
   phantom_import_count: true,
@@ -292,6 +311,8 @@ export const SIGNAL_POLARITY = {
   blast_radius_size: true,
   blast_radius: true,
   todo_density: true,
+  commented_out_lines: true,
+  undocumented_complexity: true,
   naming_drift: true,
   impl_gini: true,
   is_orphan: true,
@@ -307,6 +328,8 @@ export const SIGNAL_POLARITY = {
   bus_factor: false,
   compression_ratio: false,
   docstring_coverage: false,
+  maintainability_index: false,
+  comment_density: false,
   refactor_ratio: false,
   author_entropy: false,
 
@@ -322,6 +345,7 @@ export const SIGNAL_POLARITY = {
   churn_trajectory: null,
   churn_slope: null,
   change_entropy: null,
+  token_entropy: null,
   community: null,
   concept_count: null,
   finding_count: null,
@@ -355,18 +379,22 @@ export const SIGNAL_POLARITY = {
 };
 
 /** Category display order and labels. */
-export const CATEGORY_ORDER = ["incomplete", "fragile", "tangled", "team"];
+export const CATEGORY_ORDER = ["incomplete", "fragile", "tangled", "team", "security", "errors"];
 export const CATEGORY_LABELS = {
   incomplete: "Incomplete Code",
   fragile: "Fragile / Risky Code",
   tangled: "Tangled Dependencies",
   team: "Team / Ownership Risks",
+  security: "Security Policy",
+  errors: "Error Hygiene",
 };
 export const CATEGORY_DESCRIPTIONS = {
   incomplete: "Stubs, dead code, and missing implementations",
   fragile: "Code that breaks easily due to complexity or tight coupling",
   tangled: "Circular dependencies, hidden coupling, and messy imports",
   team: "Single-author files, knowledge silos, and bus factor risks",
+  security: "Banned algorithms, weak keys, and disabled certificate checks",
+  errors: "Swallowed errors, errors matched by message, and errors wrapped without %w",
 };
 
 /** Module signal labels and descriptions */
@@ -384,6 +412,7 @@ export const MODULE_SIGNAL_LABELS = {
   main_seq_distance: "Distance from Main Sequence",
   mean_cognitive_load: "Average Complexity",
   module_bus_factor: "Module Bus Factor",
+  module_maintainability: "Maintainability Index",
   role_consistency: "Role Consistency",
   velocity: "Change Velocity",
 };
@@ -402,6 +431,7 @@ export const MODULE_SIGNAL_DESCRIPTIONS = {
   main_seq_distance: "Distance from ideal abstraction/stability balance",
   mean_cognitive_load: "Average cognitive complexity across module files",
   module_bus_factor: "How many people understand this module",
+  module_maintainability: "Maintainability Index of the module files, weighted by size",
   role_consistency: "How consistent file roles are within the module",
   velocity: "Rate of change over time",
 };
@@ -417,7 +447,7 @@ export const MODULE_SIGNAL_CATEGORIES = [
     key: "quality",
     name: "Quality Indicators",
     description: "Code quality and organizational health",
-    signals: ["health_score", "boundary_alignment", "role_consistency", "mean_cognitive_load"],
+    signals: ["health_score", "boundary_alignment", "role_consistency", "mean_cognitive_load", "module_maintainability"],
   },
   {
     key: "team",
@@ -448,6 +478,7 @@ export const MODULE_SIGNAL_POLARITY = {
   cohesion: false,
   health_score: false,
   module_bus_factor: false,
+  module_maintainability: false,
   role_consistency: false,
 
   // NEUTRAL
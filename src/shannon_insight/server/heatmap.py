@@ -0,0 +1,151 @@
+"""Per-line heat data for code-host overlays (browser extensions).
+
+``GET /api/heatmap?path=<file>&sha=<commit>`` returns a compact, column-
+oriented document with one entry per source line::
+
+    {
+      "path": "src/engine.py",
+      "sha": "3f2a9c1...",          # null = working tree
+      "lines": 120,
+      "complexity": [0, 0, 1, 2, ...],   # decision points on the line
+      "recency": [0.12, 0.12, 1.0, ...], # 1.0 = most recently changed line
+      "coverage": [null, 3, 0, ...]      # hit count; null = not instrumented
+    }
+
+``recency`` comes from ``git blame`` at the requested revision and is null
+when git history is unavailable. ``coverage`` is null when no coverage
+report (see :mod:`shannon_insight.coverage`) is found. Results are cached per
+(path, sha); working-tree results are keyed by file mtime.
+"""
+
+from __future__ import annotations
+
+import re
+import subprocess
+from collections import OrderedDict
+from pathlib import Path
+from typing import Any
+
+from ..coverage import load_line_coverage
+from ..logging_config import get_logger
+from ..signals.complexity import DECISION_RE, is_comment_line
+
+logger = get_logger(__name__)
+
+_SHA_RE = re.compile(r"^[0-9a-fA-F]{4,40}$")
+
+_CACHE_SIZE = 128
+
+
+class HeatmapError(ValueError):
+    """Invalid heatmap request (bad path or revision)."""
+
+
+def line_complexity(lines: list[str]) -> list[int]:
+    """Decision points per line, ignoring full-line comments."""
+    return [0 if is_comment_line(line) else len(DECISION_RE.findall(line)) for line in lines]
+
+
+class HeatmapBuilder:
+    """Builds and caches per-line heat documents for one repository root."""
+
+    def __init__(self, root_dir: str) -> None:
+        self.root = Path(root_dir).resolve()
+        self._cache: OrderedDict[tuple[str, str], dict[str, Any]] = OrderedDict()
+        self._coverage: dict[str, dict[int, int]] | None = None
+
+    def build(self, path: str, sha: str | None = None) -> dict[str, Any]:
+        """Heat document for *path* at *sha* (working tree when None)."""
+        rel_path = self._validate_path(path)
+        if sha is not None and not _SHA_RE.match(sha):
+            raise HeatmapError(f"Invalid revision: {sha!r}")
+
+        key = (rel_path, sha or f"worktree:{self._mtime(rel_path)}")
+        cached = self._cache.get(key)
+        if cached is not None:
+            self._cache.move_to_end(key)
+            return cached
+
+        text = self._read(rel_path, sha)
+        lines = text.splitlines()
+        doc = {
+            "path": rel_path,
+            "sha": sha,
+            "lines": len(lines),
+            "complexity": line_complexity(lines),
+            "recency": self._recency(rel_path, sha, len(lines)),
+            "coverage": self._line_coverage(rel_path, len(lines)),
+        }
+
+        self._cache[key] = doc
+        if len(self._cache) > _CACHE_SIZE:
+            self._cache.popitem(last=False)
+        return doc
+
+    def _validate_path(self, path: str) -> str:
+        if not path:
+            raise HeatmapError("Missing path")
+        candidate = (self.root / path).resolve()
+        try:
+            rel = candidate.relative_to(self.root)
+        except ValueError:
+            raise HeatmapError(f"Path escapes repository root: {path}")
+        return rel.as_posix()
+
+    def _mtime(self, rel_path: str) -> float:
+        try:
+            return (self.root / rel_path).stat().st_mtime
+        except OSError:
+            raise HeatmapError(f"File not found: {rel_path}")
+
+    def _read(self, rel_path: str, sha: str | None) -> str:
+        if sha is None:
+            return (self.root / rel_path).read_text(encoding="utf-8", errors="replace")
+        out = self._git("show", f"{sha}:{rel_path}")
+        if out is None:
+            raise HeatmapError(f"{rel_path} not found at {sha}")
+        return out
+
+    def _recency(self, rel_path: str, sha: str | None, n_lines: int) -> list[float] | None:
+        args = ["blame", "--line-porcelain"]
+        if sha:
+            args.append(sha)
+        out = self._git(*args, "--", rel_path)
+        if out is None:
+            return None
+
+        times: list[int] = []
+        for line in out.splitlines():
+            if line.startswith("committer-time "):
+                times.append(int(line.split()[1]))
+        if len(times) != n_lines or not times:
+            return None
+
+        oldest, newest = min(times), max(times)
+        span = newest - oldest
+        if span == 0:
+            return [1.0] * n_lines
+        return [round((t - oldest) / span, 2) for t in times]
+
+    def _line_coverage(self, rel_path: str, n_lines: int) -> list[int | None] | None:
+        if self._coverage is None:
+            self._coverage = load_line_coverage(self.root)
+        hits = self._coverage.get(rel_path)
+        if hits is None:
+            return None
+        return [hits.get(i) for i in range(1, n_lines + 1)]
+
+    def _git(self, *args: str) -> str | None:
+        try:
+            result = subprocess.run(
+                ["git", "-C", str(self.root), *args],
+                capture_output=True,
+                text=True,
+                timeout=15,
+            )
+        except (FileNotFoundError, subprocess.TimeoutExpired) as e:
+            logger.debug(f"git {args[0]} failed: {e}")
+            return None
+        if result.returncode != 0:
+            return None
+        return result.stdout
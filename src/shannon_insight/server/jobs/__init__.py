@@ -0,0 +1,61 @@
+"""Serve-mode analysis queue with pluggable backends.
+
+Analyses requested while the server runs (file changes, refreshes) are
+queued as jobs and run one at a time by a JobWorker. The backend is picked
+by the ``job_queue`` setting:
+
+    ""                  in memory, snapshotted to .shannon/jobs.json (default)
+    "memory"            in memory only; queued jobs are lost on restart
+    "redis://..."       Redis, shared by every replica (``[redis]`` extra)
+    "postgresql://..."  Postgres, shared by every replica (``[postgres]`` extra)
+
+Replicas serving the same repository share its queue by name (the
+repository directory name, or the tenant name in multi-tenant mode), so
+each queued analysis runs on exactly one of them.
+"""
+
+from __future__ import annotations
+
+from pathlib import Path
+
+from .base import LEASE_SECONDS, MAX_ATTEMPTS, Job, JobQueue
+from .memory import JOBS_FILE, MemoryJobQueue
+from .worker import JobWorker
+
+__all__ = [
+    "JOBS_FILE",
+    "LEASE_SECONDS",
+    "MAX_ATTEMPTS",
+    "Job",
+    "JobQueue",
+    "JobWorker",
+    "MemoryJobQueue",
+    "open_job_queue",
+]
+
+REDIS_SCHEMES = ("redis://", "rediss://", "unix://")
+POSTGRES_SCHEMES = ("postgres://", "postgresql://")
+
+
+def open_job_queue(url: str, root: str, name: str = "") -> JobQueue:
+    """Open the job queue of the repository at *root*.
+
+    Args:
+        url: The ``job_queue`` setting (see module docstring)
+        root: Repository root; holds the file snapshot of the default backend
+        name: Queue name shared across replicas (default: root's directory name)
+    """
+    name = name or Path(root).name
+    if not url:
+        return MemoryJobQueue(Path(root) / ".shannon" / JOBS_FILE)
+    if url == "memory":
+        return MemoryJobQueue()
+    if url.startswith(REDIS_SCHEMES):
+        from .redis_queue import RedisJobQueue
+
+        return RedisJobQueue(url, name)
+    if url.startswith(POSTGRES_SCHEMES):
+        from .postgres_queue import PostgresJobQueue
+
+        return PostgresJobQueue(url, name)
+    raise ValueError(f"Unsupported job_queue {url!r}: use memory, redis:// or postgresql://")
@@ -0,0 +1,63 @@
+"""Job record and the queue interface every backend implements."""
+
+from __future__ import annotations
+
+import time
+import uuid
+from dataclasses import asdict, dataclass, field
+from typing import Optional, Protocol, runtime_checkable
+
+# Starts after which an interrupted job is abandoned
+MAX_ATTEMPTS = 3
+
+# A job taken by a replica that neither finishes nor returns it within this
+# time is presumed lost with its replica, and handed to another one
+LEASE_SECONDS = 1800
+
+
+@dataclass
+class Job:
+    kind: str  # handler name, e.g. "analyze"
+    reason: str = ""
+    id: str = field(default_factory=lambda: uuid.uuid4().hex[:12])
+    enqueued_at: float = field(default_factory=time.time)
+    attempts: int = 0
+
+    def to_dict(self) -> dict:
+        return asdict(self)
+
+    @classmethod
+    def from_dict(cls, data: dict) -> Job:
+        return cls(**{k: data[k] for k in ("kind", "reason", "id", "enqueued_at", "attempts")})
+
+
+@runtime_checkable
+class JobQueue(Protocol):
+    """FIFO of analysis jobs for one repository.
+
+    Contract shared by all backends:
+
+    - ``put`` does not queue a job whose kind is already pending: two file
+      changes before anyone gets to them need one analysis, not two.
+    - ``get`` hands the oldest pending job to one caller, even with several
+      replicas polling, and counts the attempt. The job stays recorded as
+      running until ``done`` or ``requeue``. A requeued job whose kind was
+      queued again meanwhile is dropped: the pending one supersedes it.
+    - A running job that is never finished (its process died) is pending
+      again on the next start, or after LEASE_SECONDS for shared backends,
+      and is dropped after MAX_ATTEMPTS.
+    - ``status`` lists ``pending`` and ``running`` jobs as dicts; with a
+      shared backend ``running`` includes other replicas' jobs.
+    """
+
+    def put(self, job: Job) -> bool: ...
+
+    def get(self, timeout: Optional[float] = None) -> Optional[Job]: ...
+
+    def requeue(self, job: Job) -> None: ...
+
+    def done(self, job: Job) -> None: ...
+
+    def status(self) -> dict: ...
+
+    def __len__(self) -> int: ...
@@ -0,0 +1,128 @@
+"""In-process job queue, snapshotted to a JSON file.
+
+The default backend. Every change is written to ``.shannon/jobs.json``, so
+a restart (a rolling Deployment update, a crash) resumes the work that was
+queued or running:
+
+    pending   jobs not started yet, in order
+    running   the job in flight; requeued first on restart, and dropped
+              after MAX_ATTEMPTS starts so a job that kills the process
+              cannot crash-loop the server
+
+One process owns the file, so it cannot be shared between replicas; use
+the Redis or Postgres backend for that.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import os
+import threading
+from pathlib import Path
+from typing import Optional
+
+from .base import MAX_ATTEMPTS, Job
+
+logger = logging.getLogger(__name__)
+
+JOBS_FILE = "jobs.json"
+
+
+class MemoryJobQueue:
+    """JobQueue kept in memory, persisted to *path* unless it is None."""
+
+    def __init__(self, path: Optional[Path] = None) -> None:
+        self.path = path
+        self._cond = threading.Condition()
+        self._pending: list[Job] = []
+        self._running: Optional[Job] = None
+        self._load()
+
+    def _load(self) -> None:
+        if self.path is None or not self.path.exists():
+            return
+        try:
+            data = json.loads(self.path.read_text())
+            interrupted = [Job.from_dict(j) for j in data.get("running", [])]
+            pending = [Job.from_dict(j) for j in data.get("pending", [])]
+        except (OSError, ValueError, KeyError, TypeError) as e:
+            logger.warning("Ignoring unreadable job queue %s: %s", self.path, e)
+            return
+        for job in interrupted:
+            if job.attempts >= MAX_ATTEMPTS:
+                logger.warning(
+                    "Dropping job %s (%s): interrupted %d times", job.id, job.kind, job.attempts
+                )
+                continue
+            if any(j.kind == job.kind for j in pending):
+                continue  # superseded by the pending job of its kind
+            self._pending.append(job)
+        self._pending.extend(pending)
+        if self._pending:
+            logger.info("Restored %d queued job(s) from %s", len(self._pending), self.path)
+
+    def _save(self) -> None:
+        if self.path is None:
+            return
+        data = {
+            "pending": [j.to_dict() for j in self._pending],
+            "running": [self._running.to_dict()] if self._running else [],
+        }
+        try:
+            self.path.parent.mkdir(parents=True, exist_ok=True)
+            tmp = self.path.with_suffix(".tmp")
+            tmp.write_text(json.dumps(data, indent=2))
+            os.replace(tmp, self.path)
+        except OSError as e:
+            logger.warning("Could not persist job queue: %s", e)
+
+    def put(self, job: Job) -> bool:
+        """Queue *job*; False if a job of the same kind is already pending."""
+        with self._cond:
+            if any(j.kind == job.kind for j in self._pending):
+                return False
+            self._pending.append(job)
+            self._save()
+            self._cond.notify()
+            return True
+
+    def get(self, timeout: Optional[float] = None) -> Optional[Job]:
+        """Take the next job and mark it running; None on timeout."""
+        with self._cond:
+            if not self._cond.wait_for(lambda: self._pending, timeout):
+                return None
+            job = self._pending.pop(0)
+            job.attempts += 1
+            self._running = job
+            self._save()
+            return job
+
+    def requeue(self, job: Job) -> None:
+        """Put the running *job* back at the front, as if never started."""
+        with self._cond:
+            job.attempts -= 1
+            if all(j.kind != job.kind for j in self._pending):
+                self._pending.insert(0, job)
+            if self._running is job:
+                self._running = None
+            self._save()
+            self._cond.notify()
+
+    def done(self, job: Job) -> None:
+        """Forget the running *job*."""
+        with self._cond:
+            if self._running is job:
+                self._running = None
+                self._save()
+
+    def status(self) -> dict:
+        with self._cond:
+            return {
+                "pending": [j.to_dict() for j in self._pending],
+                "running": [self._running.to_dict()] if self._running else [],
+            }
+
+    def __len__(self) -> int:
+        with self._cond:
+            return len(self._pending)
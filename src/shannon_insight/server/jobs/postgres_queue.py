@@ -0,0 +1,155 @@
+"""Job queue shared between replicas through Postgres.
+
+Requires the ``[postgres]`` extra. All queues live in one table, created
+on first use; a job is pending while ``lease_until`` is NULL and running
+while it holds a lease. Replicas take jobs with ``FOR UPDATE SKIP LOCKED``
+so each job goes to exactly one of them, and expired leases are returned
+to pending by whichever replica polls next.
+"""
+
+from __future__ import annotations
+
+import logging
+import threading
+import time
+from typing import Any, Optional
+
+from .base import LEASE_SECONDS, MAX_ATTEMPTS, Job
+
+logger = logging.getLogger(__name__)
+
+POLL_SECONDS = 0.5
+
+_SCHEMA = """
+CREATE TABLE IF NOT EXISTS shannon_jobs (
+    id          TEXT PRIMARY KEY,
+    queue       TEXT NOT NULL,
+    kind        TEXT NOT NULL,
+    reason      TEXT NOT NULL DEFAULT '',
+    enqueued_at DOUBLE PRECISION NOT NULL,
+    attempts    INTEGER NOT NULL DEFAULT 0,
+    lease_until DOUBLE PRECISION
+);
+CREATE INDEX IF NOT EXISTS shannon_jobs_queue ON shannon_jobs (queue, lease_until, enqueued_at);
+"""
+
+_PUT = """
+INSERT INTO shannon_jobs (id, queue, kind, reason, enqueued_at, attempts)
+SELECT %s, %s, %s, %s, %s, %s
+WHERE NOT EXISTS (
+    SELECT 1 FROM shannon_jobs WHERE queue = %s AND kind = %s AND lease_until IS NULL
+)
+"""
+
+_TAKE = """
+UPDATE shannon_jobs SET lease_until = %s, attempts = attempts + 1
+WHERE id = (
+    SELECT id FROM shannon_jobs
+    WHERE queue = %s AND lease_until IS NULL
+    ORDER BY enqueued_at
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING kind, reason, id, enqueued_at, attempts
+"""
+
+_COLUMNS = ("kind", "reason", "id", "enqueued_at", "attempts")
+
+
+def _psycopg() -> Any:
+    try:
+        import psycopg
+
+        return psycopg
+    except ImportError:
+        raise ImportError(
+            "psycopg is required for the Postgres job queue. "
+            "Install with: pip install shannon-codebase-insight[postgres]"
+        )
+
+
+class PostgresJobQueue:
+    """JobQueue stored in Postgres, shared by every replica using *name*."""
+
+    def __init__(self, url: str, name: str, lease_seconds: float = LEASE_SECONDS) -> None:
+        self._conn = _psycopg().connect(url, autocommit=True)
+        self._lock = threading.Lock()  # one connection, shared by server threads
+        self.name = name
+        self.lease_seconds = lease_seconds
+        with self._lock:
+            self._conn.execute(_SCHEMA)
+
+    def _execute(self, sql: str, params: tuple = ()) -> Any:
+        with self._lock:
+            return self._conn.execute(sql, params)
+
+    def put(self, job: Job) -> bool:
+        row = (job.id, self.name, job.kind, job.reason, job.enqueued_at, job.attempts)
+        cur = self._execute(_PUT, row + (self.name, job.kind))
+        return cur.rowcount == 1
+
+    def get(self, timeout: Optional[float] = None) -> Optional[Job]:
+        deadline = None if timeout is None else time.monotonic() + timeout
+        while True:
+            self._reap()
+            row = self._execute(_TAKE, (time.time() + self.lease_seconds, self.name)).fetchone()
+            if row:
+                return Job(**dict(zip(_COLUMNS, row)))
+            if deadline is not None and time.monotonic() >= deadline:
+                return None
+            wait = POLL_SECONDS if deadline is None else deadline - time.monotonic()
+            time.sleep(max(0.0, min(POLL_SECONDS, wait)))
+
+    def _reap(self) -> None:
+        """Return jobs whose lease expired (their replica died) to pending."""
+        now = time.time()
+        cur = self._execute(
+            "DELETE FROM shannon_jobs WHERE queue = %s AND lease_until < %s AND attempts >= %s"
+            " RETURNING id, kind, attempts",
+            (self.name, now, MAX_ATTEMPTS),
+        )
+        for job_id, kind, attempts in cur.fetchall():
+            logger.warning("Dropping job %s (%s): interrupted %d times", job_id, kind, attempts)
+        self._execute(
+            "DELETE FROM shannon_jobs j WHERE queue = %s AND lease_until < %s AND EXISTS ("
+            " SELECT 1 FROM shannon_jobs p WHERE p.queue = j.queue AND p.kind = j.kind"
+            " AND p.lease_until IS NULL)",
+            (self.name, now),
+        )
+        self._execute(
+            "UPDATE shannon_jobs SET lease_until = NULL WHERE queue = %s AND lease_until < %s",
+            (self.name, now),
+        )
+
+    def requeue(self, job: Job) -> None:
+        job.attempts -= 1
+        self._execute(
+            "DELETE FROM shannon_jobs WHERE id = %s AND EXISTS ("
+            " SELECT 1 FROM shannon_jobs WHERE queue = %s AND kind = %s"
+            " AND lease_until IS NULL)",
+            (job.id, self.name, job.kind),
+        )
+        self._execute(
+            "UPDATE shannon_jobs SET lease_until = NULL, attempts = %s WHERE id = %s",
+            (job.attempts, job.id),
+        )
+
+    def done(self, job: Job) -> None:
+        self._execute("DELETE FROM shannon_jobs WHERE id = %s", (job.id,))
+
+    def status(self) -> dict:
+        rows = self._execute(
+            "SELECT kind, reason, id, enqueued_at, attempts, lease_until FROM shannon_jobs"
+            " WHERE queue = %s ORDER BY enqueued_at",
+            (self.name,),
+        ).fetchall()
+        pending = [dict(zip(_COLUMNS, r[:5])) for r in rows if r[5] is None]
+        running = [dict(zip(_COLUMNS, r[:5])) for r in rows if r[5] is not None]
+        return {"pending": pending, "running": running}
+
+    def __len__(self) -> int:
+        cur = self._execute(
+            "SELECT count(*) FROM shannon_jobs WHERE queue = %s AND lease_until IS NULL",
+            (self.name,),
+        )
+        return int(cur.fetchone()[0])
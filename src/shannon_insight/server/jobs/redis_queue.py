@@ -0,0 +1,133 @@
+"""Job queue shared between replicas through Redis.
+
+Requires the ``[redis]`` extra. One queue uses three keys under
+``shannon:jobs:<name>:``
+
+    pending   list of job JSON, oldest first
+    kinds     set of the kinds in ``pending`` (for de-duplication)
+    running   hash of job id -> {"job": ..., "lease_until": ...}
+
+Every change that touches more than one key (putting, taking and
+returning a job) is one Lua script, so two replicas never get the same job
+and a crash or dropped connection halfway cannot lose a job or leave its
+kind marked as pending without it. Expired leases are returned to
+``pending`` by whichever replica polls next.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+import time
+from typing import Any, Optional
+
+from .base import LEASE_SECONDS, MAX_ATTEMPTS, Job
+
+logger = logging.getLogger(__name__)
+
+POLL_SECONDS = 0.5
+
+# KEYS: pending, running, kinds; ARGV: kind, job JSON
+_PUT = """
+if redis.call('SADD', KEYS[3], ARGV[1]) == 0 then return 0 end
+redis.call('RPUSH', KEYS[1], ARGV[2])
+return 1
+"""
+
+# KEYS: pending, running, kinds; ARGV: lease_until
+_TAKE = """
+local raw = redis.call('LPOP', KEYS[1])
+if not raw then return nil end
+local job = cjson.decode(raw)
+job['attempts'] = job['attempts'] + 1
+redis.call('SREM', KEYS[3], job['kind'])
+redis.call('HSET', KEYS[2], job['id'], cjson.encode({job = job, lease_until = tonumber(ARGV[1])}))
+return cjson.encode(job)
+"""
+
+# KEYS: pending, running, kinds; ARGV: job id, job JSON. A job whose kind
+# was queued again while it ran is dropped: the pending one supersedes it.
+_REQUEUE = """
+if redis.call('HDEL', KEYS[2], ARGV[1]) == 0 then return 0 end
+local job = cjson.decode(ARGV[2])
+if redis.call('SADD', KEYS[3], job['kind']) == 0 then return 0 end
+redis.call('LPUSH', KEYS[1], ARGV[2])
+return 1
+"""
+
+
+def _redis() -> Any:
+    try:
+        import redis
+
+        return redis
+    except ImportError:
+        raise ImportError(
+            "redis is required for the Redis job queue. "
+            "Install with: pip install shannon-codebase-insight[redis]"
+        )
+
+
+class RedisJobQueue:
+    """JobQueue stored in Redis, shared by every replica using *name*."""
+
+    def __init__(self, url: str, name: str, lease_seconds: float = LEASE_SECONDS) -> None:
+        self._client = _redis().Redis.from_url(url, decode_responses=True)
+        self.lease_seconds = lease_seconds
+        prefix = f"shannon:jobs:{name}:"
+        self._pending = prefix + "pending"
+        self._running = prefix + "running"
+        self._kinds = prefix + "kinds"
+        self._put = self._client.register_script(_PUT)
+        self._take = self._client.register_script(_TAKE)
+        self._return = self._client.register_script(_REQUEUE)
+
+    @property
+    def _keys(self) -> list[str]:
+        return [self._pending, self._running, self._kinds]
+
+    def put(self, job: Job) -> bool:
+        return bool(self._put(keys=self._keys, args=[job.kind, json.dumps(job.to_dict())]))
+
+    def get(self, timeout: Optional[float] = None) -> Optional[Job]:
+        deadline = None if timeout is None else time.monotonic() + timeout
+        while True:
+            self._reap()
+            raw = self._take(keys=self._keys, args=[time.time() + self.lease_seconds])
+            if raw:
+                return Job.from_dict(json.loads(raw))
+            if deadline is not None and time.monotonic() >= deadline:
+                return None
+            wait = POLL_SECONDS if deadline is None else deadline - time.monotonic()
+            time.sleep(max(0.0, min(POLL_SECONDS, wait)))
+
+    def _reap(self) -> None:
+        """Return jobs whose lease expired (their replica died) to pending."""
+        now = time.time()
+        for job_id, raw in self._client.hgetall(self._running).items():
+            leased = json.loads(raw)
+            if leased["lease_until"] > now:
+                continue
+            job = Job.from_dict(leased["job"])
+            if job.attempts >= MAX_ATTEMPTS:
+                if self._client.hdel(self._running, job_id):
+                    logger.warning(
+                        "Dropping job %s (%s): interrupted %d times", job.id, job.kind, job.attempts
+                    )
+            else:
+                self._return(keys=self._keys, args=[job_id, json.dumps(job.to_dict())])
+
+    def requeue(self, job: Job) -> None:
+        job.attempts -= 1
+        self._return(keys=self._keys, args=[job.id, json.dumps(job.to_dict())])
+
+    def done(self, job: Job) -> None:
+        self._client.hdel(self._running, job.id)
+
+    def status(self) -> dict:
+        pending = [json.loads(raw) for raw in self._client.lrange(self._pending, 0, -1)]
+        running = [json.loads(raw)["job"] for raw in self._client.hvals(self._running)]
+        return {"pending": pending, "running": running}
+
+    def __len__(self) -> int:
+        return int(self._client.llen(self._pending))
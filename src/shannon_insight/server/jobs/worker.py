@@ -0,0 +1,87 @@
+"""Background worker that runs queued jobs, with graceful drain.
+
+On SIGTERM the worker drains: it stops taking jobs, lets the running one
+finish within ``drain_timeout_seconds``, and leaves the rest in the queue
+for the next start (or another replica, with a shared backend).
+"""
+
+from __future__ import annotations
+
+import logging
+import threading
+from typing import Any, Callable, Optional
+
+from .base import Job, JobQueue
+
+logger = logging.getLogger(__name__)
+
+
+class JobWorker:
+    """Runs queued jobs on a background thread, one at a time."""
+
+    def __init__(self, queue: JobQueue, handlers: dict[str, Callable[[Job], Any]]) -> None:
+        self.queue = queue
+        self.handlers = handlers
+        self._thread: Optional[threading.Thread] = None
+        self._lock = threading.Lock()
+        self._draining = threading.Event()
+        self._idle = threading.Event()
+        self._idle.set()
+
+    @property
+    def draining(self) -> bool:
+        return self._draining.is_set()
+
+    def submit(self, kind: str, reason: str = "") -> bool:
+        """Queue a job; refused (False) once draining has started."""
+        if self.draining:
+            return False
+        return self.queue.put(Job(kind=kind, reason=reason))
+
+    def start(self) -> None:
+        if self._thread is not None and self._thread.is_alive():
+            return
+        self._draining.clear()
+        self._thread = threading.Thread(target=self._loop, name="job-worker", daemon=True)
+        self._thread.start()
+
+    def _loop(self) -> None:
+        while not self.draining:
+            job = self.queue.get(timeout=0.5)
+            if job is None:
+                continue
+            with self._lock:
+                if self.draining:
+                    # Taken just as the drain began: leave it for the next start
+                    self.queue.requeue(job)
+                    break
+                self._idle.clear()
+            try:
+                handler = self.handlers.get(job.kind)
+                if handler is None:
+                    logger.error("No handler for job kind %r, dropping %s", job.kind, job.id)
+                else:
+                    handler(job)
+            except Exception as e:
+                logger.exception("Job %s (%s) failed: %s", job.id, job.kind, e)
+            finally:
+                self.queue.done(job)
+                self._idle.set()
+
+    def begin_drain(self) -> None:
+        """Stop taking new jobs; the running one carries on."""
+        with self._lock:
+            self._draining.set()
+
+    def drain(self, timeout: float) -> bool:
+        """Stop taking jobs and wait for the running one to finish.
+
+        Returns False if it was still running after *timeout* seconds;
+        it stays recorded as running and is retried on the next start.
+        """
+        self.begin_drain()
+        finished = self._idle.wait(timeout)
+        if self._thread is not None and finished:
+            self._thread.join(timeout=1.0)
+            self._thread = None
+        return finished
@@ -52,6 +52,10 @@ class ShutdownManager:
         self._shutdown_lock = threading.Lock()
         self._shutdown_complete = False
         self._watcher: Any = None
+        self._rotator: Any = None
+        self._tenants: Any = None
+        self._jobs: Any = None
+        self._drain_timeout = 0.0
         self._state: ServerState | None = None
         self._uvicorn_server: Any = None
 
@@ -59,6 +63,26 @@ class ShutdownManager:
         """Register the file watcher for cleanup."""
         self._watcher = watcher
 
+    def register_baseline_rotator(self, rotator: Any) -> None:
+        """Register the baseline rotator for cleanup."""
+        self._rotator = rotator
+
+    def register_job_worker(self, worker: Any, drain_timeout: float) -> None:
+        """Register the analysis job worker, drained before the watcher stops."""
+        self._jobs = worker
+        self._drain_timeout = drain_timeout
+
+    def begin_drain(self) -> None:
+        """Stop accepting analyses so /readyz fails while uvicorn winds down."""
+        if self._jobs is not None:
+            self._jobs.begin_drain()
+        if self._tenants is not None:
+            self._tenants.begin_drain()
+
+    def register_tenants(self, registry: Any) -> None:
+        """Register a tenant registry; its watchers and rotators are stopped."""
+        self._tenants = registry
+
     def register_state(self, state: ServerState) -> None:
         """Register the server state for cleanup."""
         self._state = state
@@ -90,21 +114,38 @@ class ShutdownManager:
             else:
                 steps.append("No active WebSocket connections")
 
-        # 2. Stop file watcher
+        # 2. Let the running analysis finish; queued ones persist for the next start
+        if self._jobs is not None:
+            if self._jobs.drain(self._drain_timeout):
+                steps.append("Drained analysis queue")
+            else:
+                steps.append("Analysis still running after drain timeout (resumes on restart)")
+            queued = len(self._jobs.queue)
+            if queued:
+                steps.append(f"Persisted {queued} queued analysis job{'s' if queued != 1 else ''}")
+
+        # 3. Stop file watcher
         if self._watcher is not None:
             self._watcher.stop()
             steps.append("Stopped file watcher thread")
-
-        # 3. Signal uvicorn to stop
+        if self._rotator is not None and self._rotator.mode != "off":
+            self._rotator.stop()
+            steps.append("Stopped baseline rotation thread")
+        if self._tenants is not None:
+            self._tenants.stop()
+            n = len(self._tenants.tenants)
+            steps.append(f"Stopped {n} tenant{'s' if n != 1 else ''}")
+
+        # 4. Signal uvicorn to stop
         if self._uvicorn_server is not None:
             self._uvicorn_server.should_exit = True
             steps.append("Signaled uvicorn to stop")
 
-        # 4. Clean up PID file
+        # 5. Clean up PID file
         if remove_pid_file(self.project_root):
             steps.append("Cleaned up PID file")
 
-        # 5. Clean up browser session marker (allows new tab on next start)
+        # 6. Clean up browser session marker (allows new tab on next start)
         browser_marker = Path(self.project_root) / ".shannon" / ".browser_session"
         try:
             if browser_marker.exists():
@@ -113,7 +154,7 @@ class ShutdownManager:
         except OSError:
             pass
 
-        # 6. Report active threads
+        # 7. Report active threads
         active = threading.active_count()
         if active > 1:
             thread_names = [
@@ -182,6 +223,7 @@ def launch_server(
     port: int = 8765,
     no_browser: bool = False,
     verbose: bool = False,
+    bundle_path: str | None = None,
 ) -> None:
     """Full server lifecycle: startup, serve, shutdown.
 
@@ -195,6 +237,8 @@ def launch_server(
     import uvicorn
 
     from .app import create_app
+    from .baseline import BaselineRotator
+    from .jobs import JobWorker, open_job_queue
     from .watcher import FileWatcher
 
     project_root = str(Path(root_dir).resolve())
@@ -235,7 +279,18 @@ def launch_server(
     # ── Step 4: Create state and watcher ──────────────────────────
     state = ServerState()
     watcher = FileWatcher(root_dir=project_root, settings=settings, state=state)
+    rotator = BaselineRotator(root_dir=project_root, settings=settings)
+    # Analyses requested while serving go through a queue that survives restarts
+    try:
+        queue = open_job_queue(settings.job_queue, project_root)
+    except Exception as exc:
+        console.print(f"[red]Error:[/red] Could not open job queue: {exc}")
+        return
+    jobs = JobWorker(queue, {"analyze": lambda job: watcher.run_analysis()})
+    watcher.jobs = jobs
+    shutdown_mgr.register_job_worker(jobs, settings.drain_timeout_seconds)
     shutdown_mgr.register_watcher(watcher)
+    shutdown_mgr.register_baseline_rotator(rotator)
     shutdown_mgr.register_state(state)
 
     # ── Step 5: Write PID file ────────────────────────────────────
@@ -263,8 +318,14 @@ def launch_server(
     else:
         console.print("[yellow]Analysis produced no results[/yellow]")
 
-    # ── Step 7: Start file watcher ────────────────────────────────
+    # ── Step 7: Start job worker, file watcher and baseline rotation
+    if len(queue):
+        console.print(f"[dim]Resuming {len(queue)} queued analysis job(s)[/dim]")
+    jobs.start()
     watcher.start()
+    rotator.start()
+    if rotator.mode != "off":
+        console.print(f"[dim]Baseline rotation: {rotator.mode}[/dim]")
 
     # ── Step 8: Open browser ──────────────────────────────────────
     url = f"http://{host}:{actual_port}"
@@ -301,7 +362,15 @@ def launch_server(
     console.print()
 
     # ── Step 10: Start ASGI server ────────────────────────────────
-    asgi_app = create_app(state, watcher=watcher)
+    bundle = None
+    if bundle_path:
+        from ..persistence.bundle import BundleError, ResultBundle
+
+        try:
+            bundle = ResultBundle(bundle_path)
+        except (OSError, BundleError) as exc:
+            console.print(f"[yellow]Warning: Could not open bundle: {exc}[/yellow]")
+    asgi_app = create_app(state, watcher=watcher, rotator=rotator, bundle=bundle, jobs=jobs)
 
     config = uvicorn.Config(
         asgi_app,
@@ -312,6 +381,72 @@ def launch_server(
     server = uvicorn.Server(config)
     shutdown_mgr.register_uvicorn(server)
 
+    _serve(server, console, shutdown_mgr)
+
+
+def launch_multi_tenant_server(
+    manifest_path: str,
+    console: Console,
+    host: str = "127.0.0.1",
+    port: int = 8765,
+    verbose: bool = False,
+) -> None:
+    """Serve every repository of a tenant manifest from one server.
+
+    Unlike ``launch_server`` there is no browser, PID file or blocking
+    initial analysis: each tenant's watcher analyzes it in the background,
+    within the manifest's concurrency limits.
+    """
+    import uvicorn
+
+    from ..exceptions import ShannonInsightError
+    from .app import create_tenant_app
+    from .tenants import TenantRegistry, load_manifest
+
+    try:
+        registry = TenantRegistry(load_manifest(Path(manifest_path)))
+    except ShannonInsightError as exc:
+        console.print(f"[red]Error:[/red] {exc}")
+        return
+    except Exception as exc:  # invalid tenant config, unreachable job queue
+        console.print(f"[red]Error:[/red] Could not start tenants: {exc}")
+        return
+
+    shutdown_mgr = ShutdownManager(str(Path(manifest_path).resolve().parent), console)
+    shutdown_mgr.register_tenants(registry)
+
+    try:
+        actual_port = find_available_port(host, port)
+    except RuntimeError as exc:
+        console.print(f"[red]{exc}[/red]")
+        return
+    if actual_port != port:
+        console.print(f"[yellow]Port {port} in use, using {actual_port} instead[/yellow]")
+
+    registry.start()
+
+    url = f"http://{host}:{actual_port}"
+    console.print()
+    console.print(f"[bold]Tenants[/bold] -> [link={url}/api/tenants]{url}/api/tenants[/link]")
+    for name, tenant in registry.tenants.items():
+        console.print(f"[dim]  {url}/t/{name}/api/state  {tenant.root}[/dim]")
+    console.print(f"[dim]Concurrent analyses: {registry.max_concurrent} (server)[/dim]")
+    console.print("[dim]Watching for changes... (Ctrl+C to stop)[/dim]")
+    console.print()
+
+    config = uvicorn.Config(
+        create_tenant_app(registry),
+        host=host,
+        port=actual_port,
+        log_level="warning" if not verbose else "info",
+    )
+    server = uvicorn.Server(config)
+    shutdown_mgr.register_uvicorn(server)
+    _serve(server, console, shutdown_mgr)
+
+
+def _serve(server: Any, console: Console, shutdown_mgr: ShutdownManager) -> None:
+    """Run uvicorn until SIGINT/SIGTERM, then shut everything down."""
     # Install signal handlers BEFORE server.run()
     # uvicorn installs its own, but we want to intercept first
     _original_sigint = signal.getsignal(signal.SIGINT)
@@ -322,6 +457,7 @@ def launch_server(
         sig_name = "SIGINT" if signum == signal.SIGINT else "SIGTERM"
         logger.info("Received %s, initiating shutdown...", sig_name)
         console.print(f"\n[yellow]Received {sig_name}, stopping server...[/yellow]")
+        shutdown_mgr.begin_drain()
         server.should_exit = True
 
     signal.signal(signal.SIGINT, _signal_handler)
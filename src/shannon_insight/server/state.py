@@ -23,6 +23,7 @@ class ServerState:
         self._previous_state: dict[str, Any] | None = None
         self._recent_changes: list[str] = []
         self._listeners: list[Any] = []  # asyncio.Queue objects
+        self._rpc_listeners: list[Any] = []  # asyncio.Queue objects for WS /rpc
 
     def update(self, state: dict[str, Any]) -> None:
         """Replace the current dashboard state (called from watcher thread)."""
@@ -110,6 +111,30 @@ class ServerState:
             except ValueError:
                 pass
 
+    def add_rpc_listener(self, queue: Any) -> None:
+        """Register an asyncio.Queue to receive JSON-RPC notifications."""
+        with self._lock:
+            self._rpc_listeners.append(queue)
+
+    def remove_rpc_listener(self, queue: Any) -> None:
+        """Unregister a JSON-RPC listener queue."""
+        with self._lock:
+            try:
+                self._rpc_listeners.remove(queue)
+            except ValueError:
+                pass
+
+    def publish_notification(self, msg: dict[str, Any]) -> None:
+        """Broadcast a JSON-RPC notification to all editor connections.
+
+        Notifications are incremental, so a full queue drops the message
+        rather than draining older ones.
+        """
+        with self._lock:
+            listeners = list(self._rpc_listeners)
+        for queue in listeners:
+            self._send_to_queue(queue, msg, is_state_update=False)
+
     def send_progress(self, message: str, phase: str = "", percent: float | None = None) -> None:
         """Broadcast a progress message to all WebSocket listeners."""
         msg: dict[str, Any] = {"type": "progress", "message": message, "phase": phase}
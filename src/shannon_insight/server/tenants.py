@@ -0,0 +1,280 @@
+"""Multi-tenant serve mode: one server, many repositories.
+
+A tenant manifest (TOML) lists the repositories::
+
+    max_concurrent_analyses = 4      # whole server
+
+    [tenants.api]
+    path = "/srv/repos/api"
+    config = "/srv/shannon/api.toml" # optional
+    max_concurrent = 1               # this tenant's quota
+
+    [tenants.web]
+    path = "/srv/repos/web"
+
+Each tenant is isolated:
+
+    config    its own config file, plus shannon-insight.toml in its root;
+              never the server's working directory
+    history   .shannon/ in its root (history.db, commit cache, baselines,
+              queued analyses)
+    cache     a relative cache_dir is resolved against its root
+    state     its own ServerState, file watcher and baseline rotator
+    API       every endpoint under /t/<name>/ (e.g. /t/api/api/state)
+
+Analyses (watcher runs, refreshes, baseline rotations) hold a slot of the
+tenant's quota and of the server-wide limit while they run, so one busy
+repository cannot starve the others.
+"""
+
+from __future__ import annotations
+
+import logging
+import re
+import threading
+import time
+from contextlib import contextmanager
+from dataclasses import dataclass
+from pathlib import Path
+from typing import Any, Iterator, Optional
+
+from ..exceptions import ShannonInsightError
+from .baseline import BaselineRotator
+from .jobs import JobWorker, open_job_queue
+from .state import ServerState
+from .watcher import FileWatcher
+
+logger = logging.getLogger(__name__)
+
+# Tenant names become URL path segments
+TENANT_NAME_RE = re.compile(r"^[a-z0-9][a-z0-9_-]{0,62}$")
+
+DEFAULT_MAX_CONCURRENT_ANALYSES = 4
+
+
+@dataclass
+class TenantSpec:
+    name: str
+    root: str
+    config_file: Optional[str] = None
+    max_concurrent: int = 1
+
+
+@dataclass
+class TenantManifest:
+    tenants: list[TenantSpec]
+    max_concurrent: int = DEFAULT_MAX_CONCURRENT_ANALYSES
+
+
+def _positive_int(value: Any, what: str) -> int:
+    if not isinstance(value, int) or isinstance(value, bool) or value < 1:
+        raise ShannonInsightError(f"{what} must be a positive integer, got {value!r}")
+    return value
+
+
+def load_manifest(path: Path) -> TenantManifest:
+    """Parse and validate a tenant manifest.
+
+    Relative tenant paths and config files are resolved against the
+    manifest's directory.
+    """
+    from ..config import _load_toml_file
+
+    try:
+        data = _load_toml_file(path)
+    except ShannonInsightError:
+        raise
+    except Exception as e:
+        raise ShannonInsightError(f"Invalid tenant manifest '{path}': {e}")
+
+    base = path.resolve().parent
+    tables = data.get("tenants")
+    if not isinstance(tables, dict) or not tables:
+        raise ShannonInsightError(f"Tenant manifest '{path}' defines no [tenants.<name>] tables")
+
+    specs = []
+    for name, table in tables.items():
+        if not TENANT_NAME_RE.match(name):
+            raise ShannonInsightError(
+                f"Invalid tenant name {name!r}: use lowercase letters, digits, '-' and '_'"
+            )
+        if not isinstance(table, dict) or "path" not in table:
+            raise ShannonInsightError(f"Tenant {name!r} needs a path")
+        root = (base / table["path"]).resolve()
+        if not root.is_dir():
+            raise ShannonInsightError(f"Tenant {name!r}: {root} is not a directory")
+        config_file = None
+        if table.get("config"):
+            config_file = (base / table["config"]).resolve()
+            if not config_file.is_file():
+                raise ShannonInsightError(f"Tenant {name!r}: config {config_file} not found")
+        specs.append(
+            TenantSpec(
+                name=name,
+                root=str(root),
+                config_file=str(config_file) if config_file else None,
+                max_concurrent=_positive_int(
+                    table.get("max_concurrent", 1), f"tenants.{name}.max_concurrent"
+                ),
+            )
+        )
+
+    roots = [s.root for s in specs]
+    duplicates = sorted({r for r in roots if roots.count(r) > 1})
+    if duplicates:
+        raise ShannonInsightError(f"Repositories listed by several tenants: {duplicates}")
+
+    return TenantManifest(
+        tenants=specs,
+        max_concurrent=_positive_int(
+            data.get("max_concurrent_analyses", DEFAULT_MAX_CONCURRENT_ANALYSES),
+            "max_concurrent_analyses",
+        ),
+    )
+
+
+class AnalysisQuota:
+    """Concurrent analysis limit for one tenant, within a server-wide limit."""
+
+    def __init__(self, limit: int, shared: threading.Semaphore) -> None:
+        self.limit = limit
+        self._shared = shared
+        self._active = 0
+        self._cond = threading.Condition()
+
+    @property
+    def active(self) -> int:
+        return self._active
+
+    @property
+    def full(self) -> bool:
+        """True when a new analysis would have to wait for this tenant."""
+        return self._active >= self.limit
+
+    @contextmanager
+    def slot(self) -> Iterator[None]:
+        """Hold one analysis slot; waits for the tenant, then for the server."""
+        with self._cond:
+            while self._active >= self.limit:
+                self._cond.wait()
+            self._active += 1
+        try:
+            with self._shared:
+                yield
+        finally:
+            with self._cond:
+                self._active -= 1
+                self._cond.notify()
+
+
+class Tenant:
+    """One repository served by a multi-tenant server."""
+
+    def __init__(self, spec: TenantSpec, shared: threading.Semaphore) -> None:
+        from ..config import load_config
+
+        self.spec = spec
+        self.name = spec.name
+        self.root = spec.root
+        self.config_file = Path(spec.config_file) if spec.config_file else None
+        self.settings = load_config(config_file=self.config_file, project_dir=Path(self.root))
+        self.quota = AnalysisQuota(spec.max_concurrent, shared)
+        self.state = ServerState()
+        self.watcher = FileWatcher(
+            root_dir=self.root, settings=self.settings, state=self.state, analyze_fn=self.analyze
+        )
+        self.rotator = BaselineRotator(
+            root_dir=self.root, settings=self.settings, analyze_fn=self._analyze_snapshot
+        )
+        queue = open_job_queue(self.settings.job_queue, self.root, name=self.name)
+        self.jobs = JobWorker(queue, {"analyze": lambda job: self.watcher.run_analysis()})
+        self.watcher.jobs = self.jobs
+
+    @property
+    def cache_dir(self) -> str:
+        """The tenant's cache directory (relative settings resolve to its root)."""
+        return str(Path(self.root) / self.settings.cache_dir)
+
+    def analyze(self, path: str) -> tuple[Any, Any]:
+        """Analyze *path* with this tenant's config, inside its quota."""
+        from ..api import analyze
+
+        with self.quota.slot():
+            return analyze(
+                path=path,
+                config_file=self.config_file,
+                project_dir=Path(self.root),
+                cache_dir=self.cache_dir,
+                verbose=False,
+                quiet=True,
+                max_findings=100,
+            )
+
+    def _analyze_snapshot(self, path: str) -> Any:
+        return self.analyze(path)[1]
+
+    def start(self) -> None:
+        self.jobs.start()
+        self.watcher.start()
+        self.rotator.start()
+
+    def stop(self, drain_timeout: Optional[float] = None) -> None:
+        """Drain the running analysis, then stop the background threads."""
+        if drain_timeout is None:
+            drain_timeout = self.settings.drain_timeout_seconds
+        self.jobs.drain(drain_timeout)
+        self.watcher.stop()
+        if self.rotator.mode != "off":
+            self.rotator.stop()
+
+    def status(self) -> dict:
+        current = self.state.get_state()
+        return {
+            "name": self.name,
+            "path": self.root,
+            "config": self.spec.config_file,
+            "max_concurrent": self.quota.limit,
+            "active_analyses": self.quota.active,
+            "queued_analyses": len(self.jobs.queue),
+            "health": current.get("health") if current else None,
+            "status": "ready" if current else "analyzing",
+        }
+
+
+class TenantRegistry:
+    """All tenants of a server, sharing one server-wide analysis limit."""
+
+    def __init__(self, manifest: TenantManifest) -> None:
+        self.max_concurrent = manifest.max_concurrent
+        self.draining = False
+        shared = threading.BoundedSemaphore(manifest.max_concurrent)
+        self.tenants: dict[str, Tenant] = {s.name: Tenant(s, shared) for s in manifest.tenants}
+
+    def get(self, name: str) -> Optional[Tenant]:
+        return self.tenants.get(name)
+
+    def start(self) -> None:
+        """Start every tenant's watcher; the first poll runs its initial analysis."""
+        for tenant in self.tenants.values():
+            tenant.start()
+            logger.info("Tenant %s serving %s", tenant.name, tenant.root)
+
+    def begin_drain(self) -> None:
+        """Fail readiness and stop every tenant taking new analyses."""
+        self.draining = True
+        for tenant in self.tenants.values():
+            tenant.jobs.begin_drain()
+
+    def stop(self) -> None:
+        """Drain all tenants at once, each within its own drain timeout."""
+        self.begin_drain()
+        start = time.monotonic()
+        for tenant in self.tenants.values():
+            remaining = tenant.settings.drain_timeout_seconds - (time.monotonic() - start)
+            tenant.stop(drain_timeout=max(remaining, 0.0))
+
+    def status(self) -> dict:
+        return {
+            "max_concurrent_analyses": self.max_concurrent,
+            "tenants": [t.status() for t in self.tenants.values()],
+        }
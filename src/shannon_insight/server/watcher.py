@@ -8,14 +8,28 @@ from __future__ import annotations
 
 import logging
 import threading
+import time
 from pathlib import Path
-from typing import TYPE_CHECKING, Any
+from typing import TYPE_CHECKING, Any, Callable, Optional
+
+from ..portable import portable_path
 
 if TYPE_CHECKING:
+    from .decorations import DecorationStream
+    from .jobs import JobWorker
     from .state import ServerState
 
 logger = logging.getLogger(__name__)
 
+# With history_auto_prune, how often retention is applied after a save
+PRUNE_INTERVAL_SECONDS = 24 * 3600
+
+
+def _analyze(path: str) -> tuple[Any, Any]:
+    from ..api import analyze
+
+    return analyze(path=path, verbose=False, max_findings=100)
+
 
 class FileWatcher:
     """Watches for file changes and triggers re-analysis.
@@ -30,16 +44,22 @@ class FileWatcher:
         settings: Any,
         state: ServerState,
         poll_interval: float = 2.0,
+        analyze_fn: Optional[Callable[[str], tuple[Any, Any]]] = None,
     ) -> None:
         self.root_dir = str(Path(root_dir).resolve())
         self.settings = settings
         self.state = state
         self.poll_interval = poll_interval
+        self._analyze = analyze_fn or _analyze
 
         self._stop_event = threading.Event()
         self._thread: threading.Thread | None = None
         self._last_mtime: dict[str, float] = {}
         self._analyzing = False
+        self._decorations: DecorationStream | None = None
+        # Set by the server so analyses go through its persistent job queue
+        self.jobs: JobWorker | None = None
+        self._last_prune: float | None = None
 
     def start(self) -> None:
         """Start the file watcher thread."""
@@ -59,11 +79,19 @@ class FileWatcher:
             self._thread = None
         logger.info("File watcher stopped")
 
+    def request_analysis(self, reason: str = "change") -> None:
+        """Queue an analysis on the attached job worker, or run it now."""
+        if self.jobs is not None:
+            self.jobs.submit("analyze", reason)
+        else:
+            self.run_analysis()
+
     def run_analysis(self) -> None:
         """Run analysis and update server state.
 
         This is the main analysis entry point. It uses api.analyze()
-        which handles all the session/kernel setup correctly.
+        (or the analyze_fn given at construction, which returns the same
+        result/snapshot pair) for all the session/kernel setup.
         """
         if self._analyzing:
             logger.debug("Analysis already in progress, skipping")
@@ -73,13 +101,7 @@ class FileWatcher:
         try:
             self.state.send_progress("Analyzing...", phase="analyze", percent=0.1)
 
-            from ..api import analyze
-
-            result, snapshot = analyze(
-                path=self.root_dir,
-                verbose=False,
-                max_findings=100,
-            )
+            result, snapshot = self._analyze(self.root_dir)
 
             # Convert to dashboard state format
             dashboard_state = self._build_dashboard_state(result, snapshot)
@@ -102,16 +124,29 @@ class FileWatcher:
         """Main watch loop - polls for file changes."""
         while not self._stop_event.is_set():
             try:
+                initial_scan = not self._last_mtime
                 changed = self._check_for_changes()
                 if changed:
                     logger.info("Files changed, re-analyzing...")
                     self.state.set_recent_changes(changed)
-                    self.run_analysis()
+                    # The first poll reports every file; only stream real edits
+                    if not initial_scan:
+                        self._publish_decorations(changed)
+                    self.request_analysis("change")
             except Exception as e:
                 logger.error("Watch loop error: %s", e)
 
             self._stop_event.wait(self.poll_interval)
 
+    def _publish_decorations(self, changed: list[str]) -> None:
+        """Push per-function metrics for changed files before full re-analysis."""
+        from .decorations import DecorationStream
+
+        if self._decorations is None:
+            self._decorations = DecorationStream(self.root_dir)
+        for msg in self._decorations.notifications(changed):
+            self.state.publish_notification(msg)
+
     def _check_for_changes(self) -> list[str]:
         """Check for file modifications since last check."""
         changed: list[str] = []
@@ -148,7 +183,7 @@ class FileWatcher:
                     continue
 
                 try:
-                    rel_path = str(p.relative_to(root))
+                    rel_path = portable_path(p, root)
                     current_mtimes[rel_path] = p.stat().st_mtime
                 except OSError:
                     continue
@@ -172,6 +207,21 @@ class FileWatcher:
         self._last_mtime = current_mtimes
         return changed
 
+    def _auto_prune(self, db: Any) -> None:
+        """Apply the history retention policy, at most once a day."""
+        if not self.settings.history_auto_prune:
+            return
+        now = time.monotonic()
+        if self._last_prune is not None and now - self._last_prune < PRUNE_INTERVAL_SECONDS:
+            return
+        self._last_prune = now
+        from ..persistence.retention import RetentionPolicy, prune_history
+
+        try:
+            prune_history(db, RetentionPolicy.from_config(self.settings))
+        except Exception as e:
+            logger.warning("Could not prune history: %s", e)
+
     def _build_dashboard_state(self, result, snapshot) -> dict:
         """Convert analysis result to dashboard state format.
 
@@ -190,9 +240,10 @@ class FileWatcher:
             from ..persistence import HistoryDB
             from ..persistence.writer import save_snapshot
 
-            with HistoryDB(self.root_dir) as db:
+            with HistoryDB(self.root_dir, url=self.settings.history_url) as db:
                 save_snapshot(db.conn, snapshot)
                 db_path = str(db.db_path)
+                self._auto_prune(db)
             logger.debug("Saved snapshot to %s", db_path)
         except Exception as e:
             logger.debug("Could not save snapshot to history: %s", e)
@@ -203,4 +254,6 @@ class FileWatcher:
                 if history_db.exists():
                     db_path = str(history_db)
 
-        return build_dashboard_state(result, snapshot, db_path=db_path)
+        return build_dashboard_state(
+            result, snapshot, db_path=db_path, history_url=self.settings.history_url
+        )
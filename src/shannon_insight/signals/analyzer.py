@@ -168,6 +168,28 @@ class SignalFusionAnalyzer:
         fs.set_signal(
             entity_id, Signal.COMPRESSION_RATIO, signals.compression_ratio, producer=producer
         )
+        fs.set_signal(entity_id, Signal.TOKEN_ENTROPY, signals.token_entropy, producer=producer)
+        fs.set_signal(entity_id, Signal.HALSTEAD_VOLUME, signals.halstead_volume, producer=producer)
+        fs.set_signal(
+            entity_id, Signal.HALSTEAD_DIFFICULTY, signals.halstead_difficulty, producer=producer
+        )
+        fs.set_signal(entity_id, Signal.HALSTEAD_EFFORT, signals.halstead_effort, producer=producer)
+        fs.set_signal(
+            entity_id,
+            Signal.MAINTAINABILITY_INDEX,
+            signals.maintainability_index,
+            producer=producer,
+        )
+        fs.set_signal(entity_id, Signal.COMMENT_DENSITY, signals.comment_density, producer=producer)
+        fs.set_signal(
+            entity_id, Signal.COMMENTED_OUT_LINES, signals.commented_out_lines, producer=producer
+        )
+        fs.set_signal(
+            entity_id,
+            Signal.UNDOCUMENTED_COMPLEXITY,
+            signals.undocumented_complexity,
+            producer=producer,
+        )
 
         # Composites (computed in fusion step 5)
         fs.set_signal(entity_id, Signal.RISK_SCORE, signals.risk_score, producer=producer)
@@ -216,6 +238,12 @@ class SignalFusionAnalyzer:
         fs.set_signal(
             entity_id, Signal.MEAN_COGNITIVE_LOAD, signals.mean_cognitive_load, producer=producer
         )
+        fs.set_signal(
+            entity_id,
+            Signal.MODULE_MAINTAINABILITY,
+            signals.module_maintainability,
+            producer=producer,
+        )
         fs.set_signal(entity_id, Signal.FILE_COUNT, signals.file_count, producer=producer)
         fs.set_signal(entity_id, Signal.HEALTH_SCORE, signals.health_score, producer=producer)
 
@@ -0,0 +1,275 @@
+"""API surface: how much of each package other code can reach.
+
+Counted per package (directory), test files left out:
+
+    go      exported top-level identifiers: functions, types, constants and
+            variables whose names start upper-case, and exported methods of
+            exported types (a method of an unexported type is only reachable
+            through an interface, which is counted once as a type)
+    others  public members of public classes: methods not marked private
+            (no leading underscore in Python, no private/protected keyword,
+            ``public`` required in Java and C#, ``pub`` in Rust), and
+            Python fields without a leading underscore (elsewhere a field's
+            visibility is on its declaration, which the syntax does not keep)
+
+A package's surface is measured against its size as exported identifiers
+per thousand non-blank lines. A package whose surface is SURFACE_RATIO
+times the codebase median, with at least MIN_SURFACE exported identifiers,
+is reported as ``wide_api_surface``: every exported name is a promise to
+callers, and a thin layer of code making many promises is hard to change
+without breaking someone. Snapshots record each package's api_surface, so
+its growth shows up in trends and diffs.
+"""
+
+from __future__ import annotations
+
+import math
+import re
+import statistics
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING
+
+from ..hygiene.naming import is_exported
+from ..hygiene.sources import SourceSet
+from .dead_code import _definition_line, _is_test_path
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import ClassDef, FileSyntax
+
+WIDE_API_TYPE = "wide_api_surface"
+
+# Exported identifiers per thousand lines, as a multiple of the median package
+SURFACE_RATIO = 3.0
+
+# Below this many exported identifiers a package is never worth reporting
+MIN_SURFACE = 20
+
+# Too few packages give no meaningful median
+MIN_PACKAGES = 3
+
+_GO_FUNC_RE = re.compile(r"^func\s+(?:\(\s*(?:\w+\s+)?\*?\s*(\w+)[^)]*\)\s*)?(\w+)")
+_GO_DECL_RE = re.compile(r"^(?:type|const|var)\s+(\(|\w+(?:\s*,\s*\w+)*)")
+_GO_SPEC_RE = re.compile(r"^(\s+)(\w+(?:\s*,\s*\w+)*)")
+
+# Members that are public unless marked, and those that must say so
+_NOT_PUBLIC_RE = re.compile(r"\b(?:private|protected|internal|fileprivate)\b")
+_PUBLIC_RE = re.compile(r"\bpublic\b")
+_PUB_RE = re.compile(r"\bpub\b")
+_OPT_IN_LANGUAGES = frozenset({"java", "csharp"})
+
+
+@dataclass
+class PackageSurface:
+    """Exported identifiers of one package against its size."""
+
+    package: str
+    files: list[str] = field(default_factory=list)
+    lines: int = 0  # non-blank
+    exported: int = 0
+    declared: int = 0  # exported or not
+
+    @property
+    def share(self) -> float:
+        """Share of declared identifiers that are exported."""
+        return self.exported / self.declared if self.declared else 0.0
+
+    @property
+    def per_kloc(self) -> float:
+        """Exported identifiers per thousand non-blank lines."""
+        return 1000 * self.exported / self.lines if self.lines else 0.0
+
+    def to_dict(self) -> dict:
+        return {
+            "package": self.package,
+            "files": len(self.files),
+            "lines": self.lines,
+            "exported": self.exported,
+            "declared": self.declared,
+            "share": round(self.share, 4),
+            "per_kloc": round(self.per_kloc, 2),
+        }
+
+
+@dataclass
+class SurfaceReport:
+    """API surface of every package, widest first."""
+
+    packages: list[PackageSurface] = field(default_factory=list)
+
+    @property
+    def exported(self) -> int:
+        return sum(p.exported for p in self.packages)
+
+    @property
+    def median_per_kloc(self) -> float:
+        measured = [p.per_kloc for p in self.packages if p.exported]
+        return statistics.median(measured) if measured else 0.0
+
+    def wide(
+        self, ratio: float = SURFACE_RATIO, min_surface: int = MIN_SURFACE
+    ) -> list[PackageSurface]:
+        """Packages exporting far more per line than the median package."""
+        if sum(1 for p in self.packages if p.exported) < MIN_PACKAGES:
+            return []
+        limit = ratio * self.median_per_kloc
+        return [p for p in self.packages if p.exported >= min_surface and p.per_kloc >= limit]
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        if not self.packages:
+            return {}, {}
+        global_signals = {"api_surface": float(self.exported)}
+        package_signals = {
+            p.package: {"api_surface": float(p.exported), "api_surface_share": round(p.share, 4)}
+            for p in self.packages
+        }
+        return global_signals, package_signals
+
+    def to_dict(self) -> dict:
+        return {
+            "exported": self.exported,
+            "median_per_kloc": round(self.median_per_kloc, 2),
+            "packages": [p.to_dict() for p in self.packages],
+            "wide": [p.package for p in self.wide()],
+        }
+
+
+def measure_surface(files: dict[str, FileSyntax], contents: dict[str, str]) -> SurfaceReport:
+    """Count exported and declared identifiers of every package."""
+    packages: dict[str, PackageSurface] = {}
+    for path, syntax in sorted(files.items()):
+        if _is_test_path(path):
+            continue
+        content = contents.get(path, "")
+        if syntax.language == "go":
+            exported, declared = _go_surface(content)
+        else:
+            exported, declared = _class_surface(syntax, content.splitlines())
+        name = SourceSet.package_of(path)
+        package = packages.setdefault(name, PackageSurface(name))
+        package.files.append(path)
+        package.lines += sum(1 for line in content.splitlines() if line.strip())
+        package.exported += exported
+        package.declared += declared
+    ordered = sorted(packages.values(), key=lambda p: (-p.exported, p.package))
+    return SurfaceReport(ordered)
+
+
+def _go_surface(content: str) -> tuple[int, int]:
+    """(exported, declared) top-level identifiers of a Go file."""
+    exported = declared = 0
+
+    def count(names: str, receiver: str | None = None) -> None:
+        nonlocal exported, declared
+        for name in (n.strip() for n in names.split(",")):
+            if name == "_":
+                continue
+            declared += 1
+            if name[:1].isupper() and (receiver is None or receiver[:1].isupper()):
+                exported += 1
+
+    block_indent: str | None = None
+    in_block = False
+    for line in content.splitlines():
+        if in_block:
+            if line.startswith(")"):
+                in_block, block_indent = False, None
+                continue
+            spec = _GO_SPEC_RE.match(line)
+            if spec and block_indent is None:
+                block_indent = spec.group(1)
+            if spec and spec.group(1) == block_indent:
+                count(spec.group(2))
+            continue
+        func = _GO_FUNC_RE.match(line)
+        if func:
+            count(func.group(2), func.group(1))
+            continue
+        decl = _GO_DECL_RE.match(line)
+        if decl and decl.group(1) == "(":
+            in_block = True
+        elif decl:
+            count(decl.group(1))
+    return exported, declared
+
+
+def _class_surface(syntax: FileSyntax, lines: list[str]) -> tuple[int, int]:
+    """(public, declared) members of the classes in a file."""
+    exported = declared = 0
+    for cls in syntax.classes:
+        public_class = _is_public_class(cls, syntax.language, lines)
+        for method in cls.methods:
+            declared += 1
+            definition = _definition_line(method, lines)
+            if public_class and _is_public_member(method.name, definition, syntax.language):
+                exported += 1
+        if syntax.language == "python":
+            declared += len(cls.fields)
+            if public_class:
+                exported += sum(1 for name in cls.fields if is_exported(name, "python"))
+    return exported, declared
+
+
+def _is_public_class(cls: ClassDef, language: str, lines: list[str]) -> bool:
+    if language == "python":
+        return is_exported(cls.name, language)
+    keyword = r"\b(?:class|struct|interface|object|enum|trait)\s+"
+    pattern = re.compile(keyword + re.escape(cls.name) + r"\b")
+    definition = next((line for line in lines if pattern.search(line)), "")
+    return _is_public_member(cls.name, definition, language)
+
+
+def _is_public_member(name: str, definition: str, language: str) -> bool:
+    """Whether code outside the class can reach a member, from its definition line."""
+    if language == "python":
+        return is_exported(name, language)
+    if language == "rust":
+        return bool(_PUB_RE.search(definition))
+    if language in _OPT_IN_LANGUAGES:
+        return bool(_PUBLIC_RE.search(definition))
+    return not _NOT_PUBLIC_RE.search(definition) and not name.startswith(("_", "#"))
+
+
+def to_findings(report: SurfaceReport) -> list:
+    """Convert packages with a disproportionate surface to ``wide_api_surface`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    median = report.median_per_kloc
+    findings = []
+    for package in report.wide():
+        excess = package.per_kloc / median
+        findings.append(
+            Finding(
+                finding_type=WIDE_API_TYPE,
+                severity=round(min(0.7, 0.4 + 0.1 * math.log2(excess / SURFACE_RATIO)), 4),
+                title=(
+                    f"{package.package}/ exports {package.exported} identifiers "
+                    f"in {package.lines} lines ({package.per_kloc:.0f} per 1000 lines, "
+                    f"{excess:.1f}x the median package)"
+                ),
+                files=sorted(package.files),
+                evidence=[
+                    Evidence(
+                        signal="api_surface",
+                        value=float(package.exported),
+                        percentile=0.0,
+                        description=(
+                            f"{package.exported} of {package.declared} identifiers exported"
+                        ),
+                    ),
+                    Evidence(
+                        signal="api_surface_per_kloc",
+                        value=round(package.per_kloc, 2),
+                        percentile=0.0,
+                        description=f"median package: {median:.0f} per 1000 lines",
+                    ),
+                ],
+                suggestion=(
+                    f"Unexport what only {package.package}/ uses, or split the package "
+                    f"behind a smaller public API"
+                ),
+                effort="MEDIUM",
+                scope="MODULE",
+            )
+        )
+    return findings
@@ -0,0 +1,386 @@
+"""Type cohesion (LCOM4), and the low-cohesion types it reveals.
+
+LCOM4 (Hitz & Montazeri, "Measuring Coupling and Cohesion in Object-Oriented
+Systems") links two methods of a type when both use the same field or one
+calls the other, and counts the connected groups. One group is a cohesive
+type; two or more are types sharing a name but not their state: a handler
+whose request methods use the router and whose other methods use the cache
+and the metrics client is two or three types, and splits along its groups.
+
+Members are read from each method's body:
+
+    receiver access   self.x, this.x, $this->x, @x (Ruby), and in Go the
+                      method's receiver: h.cache in func (h *Handler) Get
+    implicit access   bare field and method names, in languages where
+                      ``this.`` is optional (Java, Kotlin, Scala, Swift, C++)
+
+A member that names a method of the type is a call; any other is a field.
+Constructors, which set every field, are left out, and so are methods that
+use no field and call or are called by no other method (static helpers):
+they would link every group or count as groups of their own.
+
+Go types have no class body: a struct's method set is every method whose
+receiver is the struct, in any file of its package (directory). Other types
+get their methods from the parser, or else from the functions inside the
+class body, found by indentation. Swift extensions are not merged in.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING, Optional
+
+from .complexity import _indent, is_comment_line
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import ClassDef, FileSyntax, FunctionDef
+
+LOW_COHESION_TYPE = "low_cohesion"
+
+# Method groups (LCOM4) at which a type is reported...
+LOW_COHESION_LCOM4 = 2
+
+# ...provided this many of its methods use its members
+LOW_COHESION_METHODS = 6
+
+# Groups listed in a finding
+MAX_GROUPS = 4
+
+_CONSTRUCTORS = frozenset(
+    {"__init__", "__new__", "__post_init__", "__construct", "constructor", "init", "initialize"}
+)
+_IMPLICIT_THIS = frozenset({"java", "kotlin", "scala", "swift", "cpp"})
+
+_RECEIVER_RE = re.compile(r"(?:\$this|\bself|\bthis|\bcls)\s*(?:\.|->)\s*(\w+)")
+_IVAR_RE = re.compile(r"(?<![@\w])@(\w+)")
+_WORD_RE = re.compile(r"\b[A-Za-z_]\w*\b")
+_GO_RECEIVER_RE = re.compile(r"^\s*func\s*\(\s*(\w+)\s+\*?\s*(\w+)")
+_CLASS_KEYWORDS = r"(?:class|struct|object|record|trait|enum)"
+
+# Field declarations at the top level of a class body: int x; / private Map<K, V> m = ...;
+# / val x: Int / var x = 1 / x: int = 0 (Python)
+_FIELD_RES = (
+    re.compile(r"^(?:[\w<>\[\],.?@]+\s+)+(\w+)\s*(?:=(?!=).*)?;$"),
+    re.compile(
+        r"^(?:(?:private|public|protected|internal|readonly|static|final|lateinit|override"
+        r"|open|weak|lazy)\s+)*(?:val|var|let)\s+(\w+)"
+    ),
+    re.compile(r"^(\w+)\s*(?::[^=]+)?=(?!=)"),
+    re.compile(r"^(\w+)\s*:\s*[\w\[\], .|]+$"),
+)
+
+
+@dataclass
+class MethodGroup:
+    """Methods linked by shared fields or calls, and the fields they use."""
+
+    methods: list[str]
+    fields: list[str]
+
+
+@dataclass
+class TypeCohesion:
+    """One type's methods split into groups that share no state.
+
+    Attributes:
+        name: Type name
+        path: File of the declaration
+        files: The declaration's file, then the files of its methods (Go)
+        groups: Method groups, largest first; LCOM4 is their count
+        methods: Methods in the groups (constructors and helpers excluded)
+    """
+
+    name: str
+    path: str
+    files: list[str] = field(default_factory=list)
+    groups: list[MethodGroup] = field(default_factory=list)
+
+    @property
+    def lcom4(self) -> int:
+        return len(self.groups)
+
+    @property
+    def methods(self) -> int:
+        return sum(len(g.methods) for g in self.groups)
+
+    @property
+    def severity(self) -> float:
+        return min(0.7, 0.4 + 0.1 * (self.lcom4 - 1))
+
+    def to_dict(self) -> dict:
+        return {
+            "name": self.name,
+            "path": self.path,
+            "lcom4": self.lcom4,
+            "methods": self.methods,
+            "groups": [{"methods": g.methods, "fields": g.fields} for g in self.groups],
+        }
+
+
+def lcom4_groups(members: dict[str, set[str]], method_names: set[str]) -> list[MethodGroup]:
+    """Connected groups of methods, given the members each one uses.
+
+    *members* maps each method to the member names its body uses; names in
+    *method_names* are calls, the rest fields. Methods linked to nothing
+    and using no field are left out.
+    """
+    parent = {name: name for name in members}
+
+    def find(name: str) -> str:
+        while parent[name] != name:
+            parent[name] = parent[parent[name]]
+            name = parent[name]
+        return name
+
+    linked: set[str] = set()
+    field_owner: dict[str, str] = {}
+    for method, used in sorted(members.items()):
+        for member in sorted(used):
+            if member in method_names:
+                if member == method or member not in parent:
+                    continue
+                other = member
+                linked.update((method, other))
+            else:
+                other = field_owner.setdefault(member, method)
+                linked.add(method)
+            parent[find(method)] = find(other)
+
+    groups: dict[str, MethodGroup] = {}
+    for method in sorted(linked):
+        group = groups.setdefault(find(method), MethodGroup([], []))
+        group.methods.append(method)
+        for member in sorted(members[method]):
+            if member not in method_names and member not in group.fields:
+                group.fields.append(member)
+    return sorted(groups.values(), key=lambda g: (-len(g.methods), g.methods))
+
+
+def used_members(
+    body: list[str], language: str, receiver: Optional[str], declared: set[str]
+) -> set[str]:
+    """Member names *body* uses through its receiver or, implicitly, by name."""
+    receiver_re = re.compile(rf"\b{re.escape(receiver)}\s*\.\s*(\w+)") if receiver else None
+    used: set[str] = set()
+    for line in body:
+        if is_comment_line(line):
+            continue
+        used.update(_RECEIVER_RE.findall(line))
+        if receiver_re:
+            used.update(receiver_re.findall(line))
+        if language == "ruby":
+            used.update(_IVAR_RE.findall(line))
+        if language in _IMPLICIT_THIS:
+            used.update(w for w in _WORD_RE.findall(line) if w in declared)
+    return used
+
+
+def collect_cohesion(files: dict[str, FileSyntax], contents: dict[str, str]) -> list[TypeCohesion]:
+    """LCOM4 groups of every type with methods, Go structs by method set."""
+    types: list[TypeCohesion] = []
+    go_structs: dict[tuple[str, str], str] = {}
+    go_methods: dict[tuple[str, str], list[tuple[str, FunctionDef, str]]] = {}
+    for path, syntax in sorted(files.items()):
+        lines = contents.get(path, "").splitlines()
+        if syntax.language == "go":
+            directory = PurePosixPath(path).parent.as_posix()
+            for cls in syntax.classes:
+                go_structs.setdefault((directory, cls.name), path)
+            for fn in syntax.functions:
+                start = _first_code_line(lines, fn.start_line - 1)
+                match = _GO_RECEIVER_RE.match(lines[start]) if start < len(lines) else None
+                if match:
+                    receiver, type_name = match.groups()
+                    go_methods.setdefault((directory, type_name), []).append((path, fn, receiver))
+            continue
+        for cls in syntax.classes:
+            if cls.is_extension:
+                continue
+            methods = cls.methods or _body_methods(cls, syntax, lines)
+            fields = set(cls.fields) | _declared_fields(cls, lines)
+            bodies: dict[str, tuple[list[str], Optional[str]]] = {}
+            for fn in methods:
+                # Overloads share a name, and count as one method
+                body = bodies.get(fn.name, ([], None))[0] + _body(fn, lines, syntax.language)
+                bodies[fn.name] = (body, None)
+            result = _cohesion(cls.name, path, [path], bodies, syntax.language, fields)
+            if result is not None:
+                types.append(result)
+
+    for key, path in sorted(go_structs.items()):
+        entries = go_methods.get(key, [])
+        bodies = {
+            fn.name: (_body(fn, contents.get(method_path, "").splitlines(), "go"), receiver)
+            for method_path, fn, receiver in entries
+        }
+        type_files = [path, *dict.fromkeys(p for p, _, _ in entries if p != path)]
+        result = _cohesion(key[1], path, type_files, bodies, "go", set())
+        if result is not None:
+            types.append(result)
+    return types
+
+
+def find_low_cohesion(
+    types: list[TypeCohesion],
+    lcom4: int = LOW_COHESION_LCOM4,
+    min_methods: int = LOW_COHESION_METHODS,
+) -> list[TypeCohesion]:
+    """Types splitting into several method groups, most groups first."""
+    found = [t for t in types if t.lcom4 >= lcom4 and t.methods >= min_methods]
+    return sorted(found, key=lambda t: (-t.lcom4, -t.methods, t.path, t.name))
+
+
+def to_findings(types: list[TypeCohesion]) -> list:
+    """Convert low-cohesion types to ``low_cohesion`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for cohesion in types:
+        evidence = [
+            Evidence(
+                signal="lcom4",
+                value=float(cohesion.lcom4),
+                percentile=0.0,
+                description=f"{cohesion.lcom4} method groups sharing no fields or calls",
+            ),
+            Evidence(
+                signal="methods",
+                value=float(cohesion.methods),
+                percentile=0.0,
+                description=f"{cohesion.methods} methods using the type's members",
+            ),
+        ]
+        for group in cohesion.groups[:MAX_GROUPS]:
+            fields = ", ".join(group.fields) if group.fields else "no fields"
+            evidence.append(
+                Evidence(
+                    signal="method_group",
+                    value=float(len(group.methods)),
+                    percentile=0.0,
+                    description=f"{', '.join(group.methods)} -- {fields}",
+                )
+            )
+        findings.append(
+            Finding(
+                finding_type=LOW_COHESION_TYPE,
+                severity=cohesion.severity,
+                title=(
+                    f"{cohesion.name} has {cohesion.lcom4} unrelated method groups "
+                    f"(LCOM4 {cohesion.lcom4})"
+                ),
+                files=list(cohesion.files),
+                evidence=evidence,
+                suggestion=f"Split {cohesion.name} into one type per method group",
+                effort="HIGH",
+                identity_hint=cohesion.name,
+            )
+        )
+    return findings
+
+
+def _cohesion(
+    name: str,
+    path: str,
+    files: list[str],
+    bodies: dict[str, tuple[list[str], Optional[str]]],
+    language: str,
+    fields: set[str],
+) -> Optional[TypeCohesion]:
+    method_names = {m for m in bodies if m not in _CONSTRUCTORS and m != name}
+    declared = fields | method_names
+    members = {
+        method: used_members(body, language, receiver, declared)
+        for method, (body, receiver) in bodies.items()
+        if method in method_names
+    }
+    groups = lcom4_groups(members, method_names)
+    return TypeCohesion(name, path, files, groups) if groups else None
+
+
+def _first_code_line(lines: list[str], index: int) -> int:
+    """Index of the first non-blank line at or after *index*."""
+    while index < len(lines) and not lines[index].strip():
+        index += 1
+    return index
+
+
+def _block_end(lines: list[str], start: int) -> int:
+    """Index one past the block opened at line index *start*, by indentation.
+
+    The block runs until a line indented no deeper than *start*; a closing
+    ``}`` or ``end`` there belongs to it, and a lone ``{`` or a closing
+    bracket of a multi-line signature does not end it.
+    """
+    indent = _indent(lines[start])
+    i = start + 1
+    while i < len(lines):
+        stripped = lines[i].strip()
+        if stripped and _indent(lines[i]) <= indent:
+            if stripped == "{" or stripped.startswith((")", "]")):
+                i += 1
+                continue
+            if stripped.startswith("}") or re.match(r"end\b", stripped):
+                return i + 1
+            return i
+        i += 1
+    return i
+
+
+def _body(fn: FunctionDef, lines: list[str], language: str) -> list[str]:
+    """Lines of *fn*: by indentation in Python and where the parser found no end."""
+    start = _first_code_line(lines, fn.start_line - 1)
+    if start >= len(lines):
+        return []
+    if language != "python" and fn.end_line > fn.start_line:
+        return lines[start : min(fn.end_line, len(lines))]
+    return lines[start : _block_end(lines, start)]
+
+
+def _class_span(cls: ClassDef, lines: list[str]) -> Optional[tuple[int, int]]:
+    """Line indexes of the body of *cls*, from its declaration in *lines*."""
+    declaration = re.compile(rf"\b{_CLASS_KEYWORDS}\s+{re.escape(cls.name)}\b")
+    for i, line in enumerate(lines):
+        if declaration.search(line) and not is_comment_line(line):
+            return i, _block_end(lines, i)
+    return None
+
+
+def _body_methods(cls: ClassDef, syntax: FileSyntax, lines: list[str]) -> list[FunctionDef]:
+    """Functions defined directly in the body of *cls*, not nested in one another."""
+    span = _class_span(cls, lines)
+    if span is None:
+        return []
+    methods: list[FunctionDef] = []
+    end = -1
+    for fn in sorted(syntax.functions, key=lambda fn: fn.start_line):
+        start = _first_code_line(lines, fn.start_line - 1)
+        if not span[0] < start < span[1] or start < end:
+            continue  # nested in the previous method
+        methods.append(fn)
+        end = start + len(_body(fn, lines, syntax.language))
+    return methods
+
+
+def _declared_fields(cls: ClassDef, lines: list[str]) -> set[str]:
+    """Names declared as fields at the top level of the body of *cls*."""
+    span = _class_span(cls, lines)
+    if span is None:
+        return set()
+    depth: Optional[int] = None
+    fields: set[str] = set()
+    for line in lines[span[0] + 1 : span[1]]:
+        stripped = line.strip()
+        if not stripped or stripped in ("{", "}") or is_comment_line(line):
+            continue
+        depth = _indent(line) if depth is None else depth
+        if _indent(line) != depth:
+            continue
+        stripped = stripped.removesuffix(",")
+        for pattern in _FIELD_RES:
+            match = pattern.match(stripped)
+            if match and match.group(1) not in ("return", "def", "fn", "func", "fun"):
+                fields.add(match.group(1))
+                break
+    return fields
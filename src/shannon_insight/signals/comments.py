@@ -0,0 +1,235 @@
+"""Comment density and quality, per function and per file.
+
+A comment-to-code ratio alone rewards noise: commented-out code and a
+banner above every block count as much as an explanation of a tricky loop.
+So comment lines are told apart, per function:
+
+    doc_lines            the function's doc comment: the block of comment
+                         lines directly above its definition (JSDoc,
+                         Javadoc, Go and Rust doc comments, ...) or, in
+                         Python, its docstring
+    comment_lines        comment lines inside the body
+    commented_out_lines  comment lines that read as code (``# x = load()``,
+                         ``// return cache[key];``), counted apart from both
+    comment_density      (doc_lines + comment_lines) per line of code
+
+A lone code-like line right below a prose comment (``e.g.:`` followed by
+an example) is prose; two or more in a row, or one on its own, is
+commented-out code. Only whole comment lines count; a comment after code
+on the same line is part of the code line.
+
+Per file, the same measures feed three signals:
+
+    comment_density          comment lines per line of code
+    commented_out_lines      lines of commented-out code anywhere in the file
+    undocumented_complexity  decision points (complexity - 1) summed over
+                             the functions without a doc comment
+
+so a file whose complex functions are undocumented ranks above one whose
+complex functions are explained, while undocumented one-liners cost
+nothing.
+"""
+
+from __future__ import annotations
+
+import re
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Optional
+
+from .function_outliers import function_complexity
+from .halstead import _HASH_COMMENTS
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+
+BLANK, CODE, COMMENT, DOCSTRING, COMMENTED_OUT = range(5)
+
+_C_PREFIXES = ("//", "/*", "*")
+_MARKER_RE = re.compile(r"^(?:#+|//+!?|/\*+!?|\*+/?)\s?")
+_TRIPLE_RE = re.compile(r"""^[rRuUbBfF]{0,2}("{3}|'{3})""")
+
+# Comment text that reads as a statement rather than prose
+_CODE_RE = re.compile(
+    r"[;{}]$"  # statement or block end
+    r"|^(?:(?:el)?if|while|with|except|def|class)\s.*[\w)\]]:$|^for\s.*\sin\s.*:$"  # Python
+    r"|^(?:else|try|finally):$"
+    r"|^(?:return|import|from|var|let|const|func|fn|print|throw|raise)\b.*[()\[\]=;'\"]"
+    r"|^[\w.\[\]\"']+\s*(?:[-+*/|&]?=)\s*[^\s=]"  # assignment
+    r"|^[\w.]+\(.*\)$"  # call
+)
+
+# Directives written as comments
+_DIRECTIVE_RE = re.compile(r"^(?:type:|noqa|pragma|eslint|prettier|nolint|go:|@ts-|-\*-|!)")
+
+# Three words in a row that are not keywords: a sentence, not a statement
+_KEYWORD = r"(?:if|in|is|not|and|or|for|else|return|with|as|from|import|while|new|var|let|const)"
+_WORD = rf"(?!{_KEYWORD}\b)[A-Za-z]{{2,}}"
+_PROSE_RE = re.compile(rf"(?<![\w.]){_WORD} {_WORD} {_WORD}(?![\w.(])")
+_STRING_RE = re.compile(r"\"[^\"]*\"|'[^']*'")
+
+# Formulas: short capitalized names (``G = ...``, ``IDF = ...``) and chained ``a = b = c``
+_FORMULA_RE = re.compile(r"^[A-Z][A-Z0-9]{0,3}\s*=[^=]|\s=\s.*\s=\s")
+
+
+@dataclass(frozen=True)
+class FunctionComments:
+    path: str
+    name: str
+    line: int
+    code_lines: int
+    doc_lines: int
+    comment_lines: int
+    commented_out_lines: int
+    complexity: int  # 1 + decision points
+
+    @property
+    def documented(self) -> bool:
+        return self.doc_lines > 0
+
+    @property
+    def comment_density(self) -> float:
+        return (self.doc_lines + self.comment_lines) / max(1, self.code_lines)
+
+    def to_dict(self) -> dict[str, float]:
+        return {
+            "doc_lines": self.doc_lines,
+            "comment_lines": self.comment_lines,
+            "commented_out_lines": self.commented_out_lines,
+            "comment_density": round(self.comment_density, 2),
+        }
+
+
+@dataclass(frozen=True)
+class FileComments:
+    comment_density: float
+    commented_out_lines: int
+    undocumented_complexity: int
+    functions: list[FunctionComments]
+
+
+def _is_comment(stripped: str, language: str) -> bool:
+    if language in _HASH_COMMENTS:
+        return stripped.startswith("#") and not stripped.startswith("#!")
+    return stripped.startswith(_C_PREFIXES)
+
+
+def looks_like_code(text: str) -> bool:
+    """True when comment *text*, markers stripped, reads as a statement."""
+    text = text.strip()
+    if not text or text.endswith(".") or _DIRECTIVE_RE.match(text) or _FORMULA_RE.search(text):
+        return False
+    if _PROSE_RE.search(_STRING_RE.sub('""', text)):
+        return False
+    return bool(_CODE_RE.search(text))
+
+
+def classify_lines(lines: list[str], language: str) -> list[int]:
+    """Kind of every line: BLANK, CODE, COMMENT, DOCSTRING or COMMENTED_OUT."""
+    kinds = []
+    quote: Optional[str] = None  # open triple quote, in Python
+    in_docstring = False
+    for line in lines:
+        stripped = line.strip()
+        if quote is not None:
+            kinds.append(DOCSTRING if in_docstring else CODE)
+            if quote in stripped:
+                quote = None
+            continue
+        if not stripped:
+            kinds.append(BLANK)
+        elif _is_comment(stripped, language):
+            code = looks_like_code(_MARKER_RE.sub("", stripped).rstrip("*/ "))
+            kinds.append(COMMENTED_OUT if code else COMMENT)
+        elif language == "python" and _TRIPLE_RE.match(stripped):
+            delimiter = _TRIPLE_RE.match(stripped).group(1)
+            kinds.append(DOCSTRING)
+            if stripped.count(delimiter) == 1:
+                quote, in_docstring = delimiter, True
+        else:
+            kinds.append(CODE)
+            if language == "python":
+                for delimiter in ('"""', "'''"):
+                    if stripped.count(delimiter) % 2:
+                        quote, in_docstring = delimiter, False
+                        break
+    return _keep_examples(kinds, lines)
+
+
+def _keep_examples(kinds: list[int], lines: list[str]) -> list[int]:
+    """Turn a lone code-like line below a prose comment back into a comment."""
+    for i, kind in enumerate(kinds):
+        if kind != COMMENTED_OUT or i == 0 or kinds[i - 1] != COMMENT:
+            continue
+        after = kinds[i + 1] if i + 1 < len(kinds) else BLANK
+        prose = _MARKER_RE.sub("", lines[i - 1].strip()).strip("*/ ")
+        if after != COMMENTED_OUT and prose:
+            kinds[i] = COMMENT
+    return kinds
+
+
+def _doc_above(kinds: list[int], lines: list[str], start: int) -> int:
+    """Comment lines directly above line index *start*, past decorators."""
+    i = start - 1
+    while i >= 0 and kinds[i] == CODE and lines[i].lstrip().startswith("@"):
+        i -= 1
+    count = 0
+    while i >= 0 and kinds[i] == COMMENT:
+        count += 1
+        i -= 1
+    return count
+
+
+def _docstring(kinds: list[int], lines: list[str], start: int, end: int) -> set[int]:
+    """Line indexes of the docstring opening the function at *start*, if any."""
+    i = start
+    while i < end and not lines[i].rstrip().endswith(":"):
+        i += 1
+    i += 1
+    while i < end and kinds[i] in (BLANK, COMMENT):
+        i += 1
+    found = set()
+    while i < end and kinds[i] == DOCSTRING:
+        found.add(i)
+        i += 1
+    return found
+
+
+def function_comments(
+    path: str, fn: FunctionDef, lines: list[str], kinds: list[int]
+) -> FunctionComments:
+    """Comment measures of *fn*, given its file's lines and their kinds."""
+    start, end = fn.start_line - 1, min(fn.end_line, len(lines))
+    docstring = _docstring(kinds, lines, start, end)
+    counts = {CODE: 0, COMMENT: 0, DOCSTRING: 0, COMMENTED_OUT: 0, BLANK: 0}
+    for i in range(start, end):
+        if i not in docstring:
+            counts[kinds[i]] += 1
+    return FunctionComments(
+        path=path,
+        name=fn.name,
+        line=fn.start_line,
+        code_lines=counts[CODE],
+        doc_lines=len(docstring) + _doc_above(kinds, lines, start),
+        comment_lines=counts[COMMENT] + counts[DOCSTRING],
+        commented_out_lines=counts[COMMENTED_OUT],
+        complexity=function_complexity(lines[start:end]),
+    )
+
+
+def file_comments(syntax: FileSyntax, content: str) -> FileComments:
+    """Comment measures of one file and of each of its functions."""
+    lines = content.splitlines()
+    kinds = classify_lines(lines, syntax.language)
+    functions = [
+        function_comments(syntax.path, fn, lines, kinds)
+        for fn in sorted(syntax.functions, key=lambda f: f.start_line)
+        if 0 < fn.start_line <= fn.end_line
+    ]
+    code = kinds.count(CODE)
+    comments = kinds.count(COMMENT) + kinds.count(DOCSTRING)
+    return FileComments(
+        comment_density=comments / code if code else 0.0,
+        commented_out_lines=kinds.count(COMMENTED_OUT),
+        undocumented_complexity=sum(f.complexity - 1 for f in functions if not f.documented),
+        functions=functions,
+    )
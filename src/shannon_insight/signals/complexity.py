@@ -0,0 +1,134 @@
+"""Function-size normalization for the complexity term of cognitive_load.
+
+Raw complexity grows with function length, so long-but-flat code (generated
+CRUD handlers, table-driven switch statements) outranks short functions that
+are genuinely tangled. ``complexity_normalization`` selects how the per-file
+complexity value is computed before it enters cognitive_load:
+
+    none             Parser complexity as-is (default, backwards compatible)
+    function_length  Complexity discounted by mean function length beyond
+                     REFERENCE_FUNCTION_LINES (short functions are never inflated)
+    decision_point   Mean cost per decision point, where each decision costs
+                     1 + its nesting level inside the function. Flat branching
+                     scores ~1 regardless of how many branches there are.
+"""
+
+from __future__ import annotations
+
+import math
+import re
+from typing import TYPE_CHECKING
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+
+COMPLEXITY_NORMALIZATIONS = ("none", "function_length", "decision_point")
+
+# Functions up to this length keep their full complexity under function_length
+REFERENCE_FUNCTION_LINES = 25
+
+# Keywords and operators that add a decision point (language-agnostic)
+DECISION_RE = re.compile(
+    r"\b(?:if|elif|else\s+if|for|foreach|while|case|catch|except|when|unless|until|guard)\b"
+    r"|&&|\|\||\?(?![.?:])"
+)
+
+_COMMENT_PREFIXES = ("#", "//", "/*", "*")
+
+
+def is_comment_line(line: str) -> bool:
+    """True for lines that are entirely a comment (any common syntax)."""
+    return line.lstrip().startswith(_COMMENT_PREFIXES)
+
+
+def _indent(line: str) -> int:
+    expanded = line.expandtabs(4)
+    return len(expanded) - len(expanded.lstrip())
+
+
+def line_levels(lines: list[str]) -> list[tuple[int, int]]:
+    """(index, nesting level) of every code line of a block.
+
+    Nesting is inferred from indentation relative to the block's first line,
+    using the smallest positive indent step in the block as one level. Body
+    lines sit one level below the signature line, at level 0.
+    """
+    code = [(i, ln) for i, ln in enumerate(lines) if ln.strip() and not is_comment_line(ln)]
+    if not code:
+        return []
+    base = _indent(code[0][1])
+    steps = [_indent(ln) - base for _, ln in code if _indent(ln) > base]
+    unit = min(steps) if steps else 4
+    return [(i, max(0, (_indent(ln) - base) // unit - 1)) for i, ln in code]
+
+
+def decision_costs(lines: list[str]) -> list[int]:
+    """Cost of every decision point in a block: 1 + nesting level."""
+    costs = []
+    for i, level in line_levels(lines):
+        hits = len(DECISION_RE.findall(lines[i]))
+        costs.extend([1 + level] * hits)
+    return costs
+
+
+def _function_blocks(lines: list[str], functions: list[FunctionDef]) -> list[list[str]]:
+    if not functions:
+        return [lines]
+    return [lines[fn.start_line - 1 : fn.end_line] for fn in functions]
+
+
+def complexity_per_decision_point(syntax: FileSyntax, content: str) -> float:
+    """Mean decision cost across all functions (1.0 when there are no decisions)."""
+    lines = content.splitlines()
+    costs: list[int] = []
+    for block in _function_blocks(lines, syntax.functions):
+        costs.extend(decision_costs(block))
+    if not costs:
+        return 1.0
+    return sum(costs) / len(costs)
+
+
+def normalized_complexity(syntax: FileSyntax, content: str | None, mode: str) -> float:
+    """Complexity value for cognitive_load under the given normalization mode."""
+    if mode == "function_length":
+        sizes = syntax.function_sizes
+        if not sizes:
+            return syntax.complexity
+        mean_size = sum(sizes) / len(sizes)
+        return syntax.complexity / max(1.0, mean_size / REFERENCE_FUNCTION_LINES)
+    if mode == "decision_point" and content:
+        return complexity_per_decision_point(syntax, content)
+    return syntax.complexity
+
+
+def cognitive_load(syntax: FileSyntax, content: str | None, mode: str = "none") -> float:
+    """Compute cognitive load from FileSyntax.
+
+    Cognitive load: weighted sum of complexity factors.
+    Based on research on code comprehension difficulty:
+    - Lines of code (log-scaled, diminishing returns)
+    - Cyclomatic complexity (decision points to track)
+    - Nesting depth (working memory load)
+    - Gini inequality (god functions harder to understand)
+
+    Formula: log2(lines+1) * (1 + complexity/10) * (1 + nesting/5) * (1 + gini)
+
+    The complexity term honours *mode* (``complexity_normalization``) so
+    long-but-flat functions can be discounted.
+
+    Output is typically 0-50 for normal files, 50-100 for complex files.
+    """
+    # Log-scaled lines (1000 lines = ~10, 100 lines = ~7)
+    lines_factor = math.log2(syntax.lines + 1) if syntax.lines > 0 else 0
+
+    # Complexity factor: average cyclomatic complexity (optionally size-normalized)
+    complexity_factor = 1 + normalized_complexity(syntax, content, mode) / 10
+
+    # Nesting penalty: deep nesting is hard to follow
+    nesting_factor = 1 + syntax.max_nesting / 5
+
+    # Gini penalty: unequal function sizes suggest god functions
+    gini = syntax.impl_gini if syntax.impl_gini else 0.0
+    gini_factor = 1 + gini
+
+    return lines_factor * complexity_factor * nesting_factor * gini_factor
@@ -10,11 +10,25 @@ Implements all 7 composites from registry/composites.md:
 - codebase_health (global #62)
 
 All composites computed as [0,1]. Display uses to_display_scale() for [1,10].
-ABSOLUTE tier (<15 files): composites NOT computed (needs percentiles).
+ABSOLUTE tier (<15 files): only the composites that need no percentiles
+are computed; codebase_health is set only when a health_formula is
+configured, from its terms other than architecture and team.
+
+codebase_health is a weighted mean of the HEALTH_TERMS, each in [0,1] with
+1 the healthiest. The weights come from a formula such as
+"0.4*nesting + 0.3*duplication + 0.3*churn" (the ``health_formula``
+config key; DEFAULT_HEALTH_FORMULA when empty) and are scaled to add up
+to 1, so the score stays in [0,1]. The formula sets codebase_health only,
+and so what reads it (dashboard, health labels and verdicts, trends,
+diffs, diligence reports). It scores the codebase as a whole, so the
+per-file risk_score, file ranking and finding severities keep their own
+weights and do not use it.
 """
 
 from __future__ import annotations
 
+import logging
+import re
 from typing import TYPE_CHECKING
 
 from shannon_insight.session import Tier
@@ -26,17 +40,103 @@ if TYPE_CHECKING:
         SignalField,
     )
 
+logger = logging.getLogger(__name__)
 
-def compute_composites(field: SignalField) -> None:
+# Share of duplicated lines at which codebase_health gives no credit for
+# duplication; codebases are typically a few percent duplicated
+DUPLICATION_CEILING = 0.25
+
+# Terms a health formula can weigh
+HEALTH_TERMS = {
+    "architecture": "architecture_health",
+    "wiring": "wiring_score",
+    "bus_factor": "bus factor of the most central files over the team size",
+    "modularity": "modularity of the dependency graph",
+    "duplication": "1 - duplication_ratio / 0.25 (no credit from 25% duplicated)",
+    "team": "1 - team_risk",
+    "nesting": "share of lines in files nested at most 4 levels deep",
+    "churn": "share of files neither churning nor spiking",
+    "maintainability": "mean Maintainability Index / 100, weighted by lines",
+}
+
+DEFAULT_HEALTH_FORMULA = (
+    "0.25*architecture + 0.25*wiring + 0.20*bus_factor + 0.15*modularity + 0.15*duplication"
+)
+
+# Nesting beyond which a file counts against the nesting term
+_DEEP_NESTING = 4
+
+# Terms that need percentile-based composites, unset in the ABSOLUTE tier
+_PERCENTILE_TERMS = ("architecture", "team")
+
+_FORMULA_TERM_RE = re.compile(r"^(?:(\d+(?:\.\d*)?|\.\d+)\s*\*\s*)?([a-z_]+)$")
+
+
+def parse_health_formula(formula: str) -> dict[str, float]:
+    """Weight of each term in a formula like "0.4*nesting + 0.3*churn".
+
+    A leading "health =" is allowed; a term without a weight counts once.
+    Raises ValueError for unknown terms and formulas that cannot be read.
+    """
+    text = formula.strip()
+    target, equals, rest = text.partition("=")
+    if equals:
+        if target.strip() not in ("health", "codebase_health"):
+            raise ValueError(f"health_formula: only health can be defined, not {target.strip()!r}")
+        text = rest
+    weights: dict[str, float] = {}
+    for term in (t.strip() for t in text.split("+")):
+        match = _FORMULA_TERM_RE.match(term)
+        if not match:
+            raise ValueError(f"health_formula: cannot read {term!r} (expected weight*term)")
+        name = match.group(2)
+        if name not in HEALTH_TERMS:
+            known = ", ".join(HEALTH_TERMS)
+            raise ValueError(f"health_formula: unknown term {name!r} (known: {known})")
+        weights[name] = weights.get(name, 0.0) + float(match.group(1) or 1.0)
+    if sum(weights.values()) <= 0:
+        raise ValueError("health_formula: the weights must add up to more than 0")
+    return weights
+
+
+def health_terms(field: SignalField) -> dict[str, float]:
+    """Every HEALTH_TERMS value of a field whose other global composites are set."""
+    g = field.global_signals
+    files = list(field.per_file.values())
+    lines = sum(fs.lines for fs in files)
+
+    # Team size: distinct authors (use a reasonable default if not available)
+    team_size = _get_team_size(field)
+    global_bf = min(_get_min_bus_factor_critical(field), team_size)
+    shallow = sum(fs.lines for fs in files if fs.max_nesting <= _DEEP_NESTING)
+    calm = sum(1 for fs in files if fs.churn_trajectory not in ("CHURNING", "SPIKING"))
+    maintainability = sum(fs.maintainability_index * fs.lines for fs in files)
+    return {
+        "architecture": g.architecture_health,
+        "wiring": g.wiring_score,
+        "bus_factor": global_bf / max(team_size, 1),
+        "modularity": g.modularity,
+        "duplication": 1 - min(g.duplication_ratio / DUPLICATION_CEILING, 1.0),
+        "team": 1 - g.team_risk,
+        "nesting": shallow / lines if lines else 1.0,
+        "churn": calm / len(files) if files else 1.0,
+        "maintainability": min(maintainability / lines / 100, 1.0) if lines else 1.0,
+    }
+
+
+def compute_composites(field: SignalField, health_formula: str = "") -> None:
     """Compute all composite scores.
 
     For BAYESIAN/FULL tiers: uses percentile normalization.
     For ABSOLUTE tier (<15 files): uses absolute thresholds from spec.
-    Modifies field in place.
+    codebase_health follows *health_formula* (DEFAULT_HEALTH_FORMULA when
+    empty). Modifies field in place.
     """
     if field.tier == Tier.ABSOLUTE:
         # < 15 files: use absolute thresholds instead of percentiles
         _compute_absolute_tier_composites(field)
+        if health_formula:
+            _apply_absolute_tier_formula(field, parse_health_formula(health_formula))
         return
 
     # Per-file composites (percentile-based)
@@ -55,7 +155,9 @@ def compute_composites(field: SignalField) -> None:
     g.wiring_score = _compute_wiring_score(field)
     g.architecture_health = _compute_architecture_health(field)
     g.team_risk = _compute_team_risk(field)
-    g.codebase_health = _compute_codebase_health(field)
+    g.codebase_health = _compute_codebase_health(
+        field, parse_health_formula(health_formula or DEFAULT_HEALTH_FORMULA)
+    )
 
 
 # ── Per-file composites ────────────────────────────────────────────────
@@ -326,28 +428,21 @@ def _compute_team_risk(field: SignalField) -> float:
     return max(0.0, min(1.0, 1.0 - good_score))
 
 
-def _compute_codebase_health(field: SignalField) -> float:
+def _compute_codebase_health(field: SignalField, weights: dict[str, float]) -> float:
     """Signal #62: The one number.
 
-    codebase_health = 0.30 * architecture_health
-                    + 0.30 * wiring_score
+    With the default formula:
+
+    codebase_health = 0.25 * architecture_health
+                    + 0.25 * wiring_score
                     + 0.20 * (global_bus_factor / team_size)
-                    + 0.20 * modularity
+                    + 0.15 * modularity
+                    + 0.15 * (1 - min(duplication_ratio / DUPLICATION_CEILING, 1))
 
     global_bus_factor = min_bus_factor_critical (capped at team_size)
     """
-    g = field.global_signals
-
-    # Team size: distinct authors (use a reasonable default if not available)
-    team_size = _get_team_size(field)
-    global_bf = min(_get_min_bus_factor_critical(field), team_size)
-
-    bf_ratio = global_bf / max(team_size, 1)
-
-    health = (
-        0.30 * g.architecture_health + 0.30 * g.wiring_score + 0.20 * bf_ratio + 0.20 * g.modularity
-    )
-
+    terms = health_terms(field)
+    health = sum(w * terms[name] for name, w in weights.items()) / sum(weights.values())
     return max(0.0, min(1.0, health))
 
 
@@ -498,3 +593,20 @@ def _compute_absolute_tier_composites(field: SignalField) -> None:
     # Skip percentile-dependent globals for ABSOLUTE tier
     # architecture_health, team_risk, codebase_health need module data
     # which may not be meaningful for tiny codebases
+
+
+def _apply_absolute_tier_formula(field: SignalField, weights: dict[str, float]) -> None:
+    """codebase_health from a configured formula in the ABSOLUTE tier.
+
+    architecture_health and team_risk are not computed below 15 files, so
+    their terms are left out, with a warning, and the other weights are
+    rescaled. When nothing is left, codebase_health stays unset.
+    """
+    dropped = [name for name in weights if name in _PERCENTILE_TERMS]
+    usable = {name: w for name, w in weights.items() if name not in _PERCENTILE_TERMS}
+    if dropped:
+        outcome = "left out of codebase_health" if usable else "codebase_health not computed"
+        names = ", ".join(dropped)
+        logger.warning(f"health_formula: {names} not available below 15 files; {outcome}")
+    if usable:
+        field.global_signals.codebase_health = _compute_codebase_health(field, usable)
@@ -0,0 +1,204 @@
+"""Coverage risk: complex functions that tests do not run.
+
+Line coverage from a report (shannon_insight.coverage: Cobertura XML from
+coverage.py, LCOV, Go cover profiles) is joined with each function's line
+span and cyclomatic complexity (function_outliers.collect_functions). A
+function's coverage is the share of its instrumented lines that ran;
+functions without instrumented lines (files missing from the report) are
+left out as unmeasured.
+
+The functions are placed in a risk matrix:
+
+                        coverage < COVERAGE_THRESHOLD   >= COVERAGE_THRESHOLD
+    complexity >= T     high risk: untested_complexity  covered
+    complexity <  T     simple, untested                simple, covered
+
+where T is COMPLEXITY_THRESHOLD. The high-risk quadrant is reported as
+``untested_complexity`` findings, riskiest first by complexity times the
+share of lines not run.
+"""
+
+from __future__ import annotations
+
+import math
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Optional
+
+from ..hygiene.sources import SourceSet
+from .function_outliers import MIN_OUTLIER_COMPLEXITY, FunctionSample, collect_functions
+
+if TYPE_CHECKING:
+    from ..coverage import LineCoverage
+    from ..scanning.syntax import FileSyntax
+
+UNTESTED_COMPLEXITY_TYPE = "untested_complexity"
+
+COMPLEXITY_THRESHOLD = MIN_OUTLIER_COMPLEXITY
+COVERAGE_THRESHOLD = 0.5
+
+
+@dataclass(frozen=True)
+class FunctionCoverage:
+    function: FunctionSample
+    instrumented: int  # lines the report has hit counts for
+    covered: int  # of those, lines that ran
+
+    @property
+    def coverage(self) -> float:
+        return self.covered / self.instrumented
+
+    @property
+    def risk(self) -> float:
+        """Complexity weighted by the share of lines not run."""
+        return self.function.complexity * (1.0 - self.coverage)
+
+    @property
+    def severity(self) -> float:
+        excess = math.log2(max(self.function.complexity / COMPLEXITY_THRESHOLD, 1.0))
+        return min(0.8, 0.3 + 0.3 * (1.0 - self.coverage) + 0.1 * excess)
+
+
+@dataclass
+class CoverageRisk:
+    functions: list[FunctionCoverage]  # measured functions, riskiest first
+    unmeasured: int  # functions with no instrumented line
+    complexity_threshold: int = COMPLEXITY_THRESHOLD
+    coverage_threshold: float = COVERAGE_THRESHOLD
+
+    def is_complex(self, fc: FunctionCoverage) -> bool:
+        return fc.function.complexity >= self.complexity_threshold
+
+    def is_covered(self, fc: FunctionCoverage) -> bool:
+        return fc.coverage >= self.coverage_threshold
+
+    def matrix(self) -> dict[str, dict[str, int]]:
+        """Function counts by complexity (high/low), then coverage (low/high)."""
+        counts = {c: {"low": 0, "high": 0} for c in ("high", "low")}
+        for fc in self.functions:
+            complexity = "high" if self.is_complex(fc) else "low"
+            counts[complexity]["high" if self.is_covered(fc) else "low"] += 1
+        return counts
+
+    def hotspots(self) -> list[FunctionCoverage]:
+        """The high-risk quadrant: complex functions tests barely run."""
+        return [fc for fc in self.functions if self.is_complex(fc) and not self.is_covered(fc)]
+
+    def line_coverage(self, package: Optional[str] = None) -> Optional[float]:
+        """Share of instrumented function lines that ran, in *package* or overall."""
+        measured = [
+            fc
+            for fc in self.functions
+            if package is None or SourceSet.package_of(fc.function.path) == package
+        ]
+        instrumented = sum(fc.instrumented for fc in measured)
+        if not instrumented:
+            return None
+        return sum(fc.covered for fc in measured) / instrumented
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        if not self.functions:
+            return {}, {}
+        hotspots: dict[str, int] = {}
+        for fc in self.hotspots():
+            package = SourceSet.package_of(fc.function.path)
+            hotspots[package] = hotspots.get(package, 0) + 1
+        global_signals = {
+            "line_coverage": round(self.line_coverage() or 0.0, 4),
+            "untested_complex_functions": float(len(self.hotspots())),
+        }
+        package_signals = {
+            package: {
+                "line_coverage": round(self.line_coverage(package) or 0.0, 4),
+                "untested_complex_functions": float(hotspots.get(package, 0)),
+            }
+            for package in sorted({SourceSet.package_of(fc.function.path) for fc in self.functions})
+        }
+        return global_signals, package_signals
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "complexity_threshold": self.complexity_threshold,
+            "coverage_threshold": self.coverage_threshold,
+            "measured": len(self.functions),
+            "unmeasured": self.unmeasured,
+            "line_coverage": _round(self.line_coverage()),
+            "matrix": {
+                f"{c}_complexity": {f"{v}_coverage": n for v, n in row.items()}
+                for c, row in self.matrix().items()
+            },
+            "hotspots": [
+                {
+                    "path": fc.function.path,
+                    "name": fc.function.name,
+                    "line": fc.function.line,
+                    "complexity": fc.function.complexity,
+                    "coverage": round(fc.coverage, 4),
+                    "risk": round(fc.risk, 2),
+                }
+                for fc in self.hotspots()[:top]
+            ],
+        }
+
+
+def analyze_coverage_risk(
+    files: dict[str, FileSyntax],
+    contents: dict[str, str],
+    coverage: LineCoverage,
+    complexity_threshold: int = COMPLEXITY_THRESHOLD,
+    coverage_threshold: float = COVERAGE_THRESHOLD,
+) -> CoverageRisk:
+    """Join line coverage with the span and complexity of every function."""
+    measured = []
+    unmeasured = 0
+    for fn in collect_functions(files, contents):
+        hits = coverage.get(fn.path, {})
+        span = [hits[n] for n in range(fn.line, fn.line + fn.lines) if n in hits]
+        if not span:
+            unmeasured += 1
+            continue
+        measured.append(FunctionCoverage(fn, len(span), sum(1 for h in span if h > 0)))
+    measured.sort(key=lambda fc: (-fc.risk, fc.function.path, fc.function.line))
+    return CoverageRisk(measured, unmeasured, complexity_threshold, coverage_threshold)
+
+
+def to_findings(report: CoverageRisk) -> list:
+    """Convert the high-risk quadrant to ``untested_complexity`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for fc in report.hotspots():
+        fn = fc.function
+        findings.append(
+            Finding(
+                finding_type=UNTESTED_COMPLEXITY_TYPE,
+                severity=fc.severity,
+                title=(
+                    f"{fn.name} at {fn.location}: complexity {fn.complexity}, "
+                    f"{fc.coverage:.0%} of lines covered"
+                ),
+                files=[fn.path],
+                evidence=[
+                    Evidence(
+                        signal="complexity",
+                        value=float(fn.complexity),
+                        percentile=0.0,
+                        description=f"complexity {fn.complexity}",
+                    ),
+                    Evidence(
+                        signal="coverage",
+                        value=round(fc.coverage, 4),
+                        percentile=0.0,
+                        description=f"{fc.covered} of {fc.instrumented} lines ran",
+                    ),
+                ],
+                suggestion="Add tests for the branches that never run before changing it",
+                effort="MEDIUM",
+                identity_hint=fn.name,
+            )
+        )
+    return findings
+
+
+def _round(value: Optional[float]) -> Optional[float]:
+    return round(value, 4) if value is not None else None
@@ -0,0 +1,218 @@
+"""Likely dead code: functions nothing in the repository refers to.
+
+A function is dead when the call graph (graph/symbols.py) gives it no
+caller and its name appears nowhere else in the repository's source: not
+called, not passed as a callback, not imported or re-exported, not named
+in a string (``getattr``, route tables, ``__all__``) and not mentioned in
+a comment. Names are compared as whole identifiers, so two definitions
+of ``helper`` are both dead only when neither is used. Without
+tree-sitter there are no call targets and the name check decides alone.
+
+Never reported: entry points (main functions, route handlers, consumers
+and CLI commands; see reachability.py), dunder methods, functions in test
+and migration files, and ``test*`` functions.
+
+How likely a dead function is to be dead, as the finding's confidence:
+
+    high    private to its file or package (a leading underscore in
+            Python, lower case in Go, no ``export`` in JavaScript and
+            TypeScript, no ``pub`` in Rust, ``private`` or ``static``
+            elsewhere), so no other repository can call it either
+    medium  exported, in a repository with entry points (an application)
+    low     exported in a library (no entry points: its callers may live
+            in other repositories), decorated (decorators register
+            functions with frameworks), or a method of a class with base
+            classes (it may override a method called through the base)
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Optional
+
+from ..graph.reachability import find_entry_points
+from ..graph.symbols import call_graph
+from ..semantics.roles import TEST_PATH_PATTERNS
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+
+DEAD_CODE_TYPE = "dead_code"
+
+CONFIDENCE = {"high": 0.9, "medium": 0.6, "low": 0.3}
+
+_SEVERITY = {"high": 0.35, "medium": 0.25, "low": 0.15}
+
+# Roles whose functions are run by tools, not called by the code
+_SKIPPED_ROLES = frozenset({"test", "migration"})
+
+_IDENTIFIER_RE = re.compile(r"[A-Za-z_$][\w$]*")
+
+# Definition-line keywords that make a function private, by language
+_PRIVATE_RE = re.compile(r"\b(?:private|fileprivate|static)\b")
+_PUB_RE = re.compile(r"\bpub\b")
+_EXPORT_RE = re.compile(r"\bexport\b")
+
+
+@dataclass(frozen=True)
+class DeadFunction:
+    path: str
+    name: str
+    line: int
+    lines: int  # length of the definition
+    exported: bool
+    confidence: str  # "high", "medium" or "low"
+    reason: str  # why the confidence is what it is
+
+    @property
+    def location(self) -> str:
+        return f"{self.path}:{self.line}"
+
+
+def find_dead_code(
+    files: dict[str, FileSyntax],
+    contents: dict[str, str],
+    imports: Optional[dict[str, list[str]]] = None,
+    roles: Optional[dict[str, str]] = None,
+) -> list[DeadFunction]:
+    """Functions nothing refers to, most confident first, then by path and line.
+
+    Args:
+        files: path -> FileSyntax of every parsed file
+        contents: path -> source text; every file counts for references
+        imports: path -> paths it imports (the dependency graph adjacency)
+        roles: path -> semantic role; test and migration files are skipped
+    """
+    roles = roles or {}
+    symbols, edges = call_graph(files, imports or {})
+    called = {callee for callees in edges.values() for callee in callees}
+    entries = find_entry_points(files)
+    entry_ids = {entry.symbol.id for entry in entries}
+
+    mentions: Counter[str] = Counter()
+    for text in contents.values():
+        mentions.update(_IDENTIFIER_RE.findall(text))
+    definitions: Counter[str] = Counter(s.name for s in symbols.values())
+    for fs in files.values():
+        for cls in fs.classes:
+            definitions.update(m.name for m in cls.methods if m not in fs.functions)
+
+    dead = []
+    for path, fs in files.items():
+        if roles.get(path, "").lower() in _SKIPPED_ROLES or _is_test_path(path):
+            continue
+        lines = contents.get(path, "").splitlines()
+        overriding = {m.name for cls in fs.classes if cls.bases for m in cls.methods}
+        seen: set[str] = set()
+        methods = [m for cls in fs.classes for m in cls.methods if m not in fs.functions]
+        for fn in fs.functions + methods:
+            symbol_id = f"{path}:{fn.name}"
+            if fn.name in seen or _always_live(fn.name):
+                continue
+            seen.add(fn.name)
+            if symbol_id in entry_ids or symbol_id in called:
+                continue
+            if mentions[fn.name] > definitions[fn.name]:
+                continue
+            exported = _is_exported(fn, fs.language, lines)
+            if not exported:
+                confidence, reason = "high", "private and never referenced"
+            elif fn.decorators:
+                confidence = "low"
+                reason = f"decorated with @{fn.decorators[0]}, which may register it"
+            elif fn.name in overriding:
+                confidence, reason = "low", "may override a base class method"
+            elif not entries:
+                confidence, reason = "low", "exported by a library"
+            else:
+                confidence, reason = "medium", "exported but never referenced"
+            dead.append(
+                DeadFunction(
+                    path=path,
+                    name=fn.name,
+                    line=fn.start_line,
+                    lines=max(1, fn.end_line - fn.start_line + 1),
+                    exported=exported,
+                    confidence=confidence,
+                    reason=reason,
+                )
+            )
+    return sorted(dead, key=lambda d: (-CONFIDENCE[d.confidence], d.path, d.line))
+
+
+def to_findings(dead: list[DeadFunction]) -> list:
+    """Convert dead functions to dead_code findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for fn in dead:
+        if fn.confidence == "high":
+            suggestion = f"Delete {fn.name}; nothing in the repository refers to it"
+        else:
+            suggestion = (
+                f"Check for callers outside the repository (plugins, reflection, other "
+                f"services) and delete {fn.name} if there are none"
+            )
+        findings.append(
+            Finding(
+                finding_type=DEAD_CODE_TYPE,
+                severity=_SEVERITY[fn.confidence],
+                title=f"{fn.name} at {fn.location} is likely dead code",
+                files=[fn.path],
+                evidence=[
+                    Evidence(
+                        signal="dead_code_confidence",
+                        value=CONFIDENCE[fn.confidence],
+                        percentile=0.0,
+                        description=f"{fn.confidence} confidence: {fn.reason}",
+                    ),
+                    Evidence(
+                        signal="function_lines",
+                        value=float(fn.lines),
+                        percentile=0.0,
+                        description=f"{fn.lines} line(s) no caller or reference reaches",
+                    ),
+                ],
+                suggestion=suggestion,
+                confidence=CONFIDENCE[fn.confidence],
+                effort="LOW",
+                identity_hint=fn.name,
+            )
+        )
+    return findings
+
+
+def _always_live(name: str) -> bool:
+    """Names run by the language or by test runners rather than by callers."""
+    if name.startswith("__") and name.endswith("__"):
+        return True
+    return name.lower().startswith("test") or name in ("init", "main")
+
+
+def _is_exported(fn: FunctionDef, language: str, lines: list[str]) -> bool:
+    """Whether code outside the function's file or package can call it."""
+    if language == "python":
+        return not fn.name.startswith("_")
+    if language == "go":
+        return fn.name[:1].isupper()
+    definition = _definition_line(fn, lines)
+    if language == "rust":
+        return bool(_PUB_RE.search(definition))
+    if language in ("javascript", "typescript"):
+        return bool(_EXPORT_RE.search(definition))
+    return not _PRIVATE_RE.search(definition)
+
+
+def _definition_line(fn: FunctionDef, lines: list[str]) -> str:
+    """The line naming the function, past any decorator lines."""
+    start = max(fn.start_line - 1, 0)
+    for text in lines[start : start + len(fn.decorators) + 2]:
+        if re.search(rf"\b{re.escape(fn.name)}\b", text):
+            return text
+    return lines[start] if start < len(lines) else ""
+
+
+def _is_test_path(path: str) -> bool:
+    return any(pattern.search(path.lower()) for pattern in TEST_PATH_PATTERNS)
@@ -0,0 +1,446 @@
+"""Token-based duplicate code: type-1 and type-2 clone classes.
+
+Every source file (YAML aside, which duplicate_yaml_block covers) is read
+as the token stream Halstead metrics count, comments and layout dropped
+(see halstead.py). A run of tokens copied into two or more places, in
+one file or across files, is a clone:
+
+    type-1  the copies are the same token for token
+    type-2  the copies differ only in identifiers and literals; names,
+            strings and numbers are compared by their kind alone
+
+A clone is at least CLONE_MIN_TOKENS tokens long (``clone_min_tokens``,
+about five to ten lines) and extends as far as its copies keep matching.
+Import, package and using lines are left out of the stream. Runs of
+fewer than MIN_DISTINCT_TOKENS distinct normalized tokens are lists and
+tables rather than logic and are not clones, and neither are copies
+overlapping each other (a table matching itself one row down).
+Candidates come from winnowing fingerprints of the normalized stream
+(winnowing.py), so every copy of CLONE_MIN_TOKENS or more tokens is found
+while only a few positions per file are indexed; each shared fingerprint
+is then extended both ways into the longest common run.
+
+The copies of one run form a clone class. A class whose copies all lie
+inside the copies of a larger class is not reported on its own, and
+classes made only of test and migration files are left out, as for the
+NCD clone pairs (clone_detection.py).
+
+A package's duplication is the share of its files' lines that lie inside
+some copy. The codebase's share, duplication_ratio, is part of
+codebase_health (see composites.py).
+"""
+
+from __future__ import annotations
+
+import hashlib
+import math
+from dataclasses import dataclass, field
+from itertools import compress
+from typing import TYPE_CHECKING, Optional
+
+from ..graph.clone_detection import EXCLUDED_ROLES
+from ..graph.winnowing import kgram_hashes, winnow
+from ..hygiene.sources import SourceSet
+from .halstead import KEYWORDS, token_matches
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+CODE_CLONE_TYPE = "code_clone"
+
+# Shorter copied runs (a call with its arguments, a guard clause) are normal
+CLONE_MIN_TOKENS = 50
+
+# Fingerprinted k-gram length; shared runs are found from their k-grams
+KGRAM_TOKENS = 12
+
+# Runs of fewer distinct normalized tokens are lists and tables (string
+# lists, rows of similar assignments), not copied logic
+MIN_DISTINCT_TOKENS = 10
+
+# A fingerprint found in more places than this is boilerplate, and would
+# cost quadratically many comparisons
+MAX_COPIES = 50
+
+# Copies listed in a finding's evidence
+MAX_LISTED = 10
+
+# Languages whose duplication is reported by their own finders
+SKIPPED_LANGUAGES = frozenset({"yaml"})
+
+# Lines starting with one of these words declare dependencies, not logic
+_PREAMBLE = frozenset(
+    {"import", "from", "package", "using", "use", "require", "include", "namespace"}
+)
+
+# Placeholders identifiers and literals are normalized to
+_IDENTIFIER = "<id>"
+_LITERAL = {"string": "<str>", "number": "<num>"}
+
+
+@dataclass(frozen=True, order=True)
+class Fragment:
+    """One copy of a clone: lines start_line-end_line of path."""
+
+    path: str
+    start_line: int
+    end_line: int
+
+    @property
+    def lines(self) -> int:
+        return self.end_line - self.start_line + 1
+
+    @property
+    def location(self) -> str:
+        return f"{self.path}:{self.start_line}-{self.end_line}"
+
+
+@dataclass(frozen=True)
+class CloneClass:
+    clone_type: int  # 1 (identical tokens) or 2 (identifiers and literals differ)
+    tokens: int  # tokens in each copy
+    fragments: tuple[Fragment, ...]  # sorted by path and line
+    digest: str  # of the normalized tokens, stable across runs
+
+    @property
+    def files(self) -> list[str]:
+        return list(dict.fromkeys(f.path for f in self.fragments))
+
+    @property
+    def lines(self) -> int:
+        """Lines of the longest copy."""
+        return max(f.lines for f in self.fragments)
+
+    @property
+    def severity(self) -> float:
+        extra = self.tokens * (len(self.fragments) - 1) / CLONE_MIN_TOKENS
+        return max(0.3, min(0.7, 0.3 + 0.1 * math.log2(extra)))
+
+    def to_dict(self) -> dict:
+        return {
+            "type": self.clone_type,
+            "tokens": self.tokens,
+            "lines": self.lines,
+            "fragments": [
+                {"path": f.path, "start_line": f.start_line, "end_line": f.end_line}
+                for f in self.fragments
+            ],
+        }
+
+
+@dataclass
+class PackageDuplication:
+    """Lines of one package's files, and how many of them are in a clone."""
+
+    package: str
+    files: int = 0
+    lines: int = 0
+    duplicated_lines: int = 0
+
+    @property
+    def duplication(self) -> float:
+        return self.duplicated_lines / self.lines if self.lines else 0.0
+
+    def to_dict(self) -> dict:
+        return {
+            "package": self.package,
+            "files": self.files,
+            "lines": self.lines,
+            "duplicated_lines": self.duplicated_lines,
+            "duplication": round(self.duplication, 4),
+        }
+
+
+@dataclass
+class DuplicationReport:
+    """Clone classes of a codebase, with duplication per package.
+
+    Attributes:
+        classes: Clone classes, those duplicating the most tokens first
+        packages: Every package, most duplicated first
+    """
+
+    classes: list[CloneClass] = field(default_factory=list)
+    packages: list[PackageDuplication] = field(default_factory=list)
+
+    @property
+    def lines(self) -> int:
+        return sum(p.lines for p in self.packages)
+
+    @property
+    def duplicated_lines(self) -> int:
+        return sum(p.duplicated_lines for p in self.packages)
+
+    @property
+    def duplication_ratio(self) -> float:
+        return self.duplicated_lines / self.lines if self.lines else 0.0
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        global_signals = {
+            "duplication_ratio": round(self.duplication_ratio, 4),
+            "duplicated_lines": float(self.duplicated_lines),
+            "clone_classes": float(len(self.classes)),
+        }
+        package_signals = {
+            p.package: {
+                "duplication": round(p.duplication, 4),
+                "duplicated_lines": float(p.duplicated_lines),
+            }
+            for p in self.packages
+        }
+        return global_signals, package_signals
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "lines": self.lines,
+            "duplicated_lines": self.duplicated_lines,
+            "duplication_ratio": round(self.duplication_ratio, 4),
+            "clone_classes": len(self.classes),
+            "classes": [c.to_dict() for c in self.classes[:top]],
+            "packages": [p.to_dict() for p in self.packages[:top]],
+        }
+
+
+@dataclass
+class _Stream:
+    path: str
+    normalized: list[str]
+    raw: list[str]
+    lines: list[int]
+
+
+def clone_tokens(content: str, language: str = "") -> tuple[list[str], list[str], list[int]]:
+    """(normalized token, token, line) lists of *content*, preamble lines left out."""
+    normalized: list[str] = []
+    raw: list[str] = []
+    lines: list[int] = []
+    line, offset = 1, 0
+    current, skipped = 0, False
+    for match in token_matches(content, language):
+        kind = match.lastgroup
+        if kind == "comment":
+            continue
+        line += content.count("\n", offset, match.start())
+        offset = match.start()
+        text = match.group()
+        if line != current:
+            current = line
+            skipped = kind == "word" and text in _PREAMBLE
+        if skipped:
+            continue
+        if kind == "word" and text not in KEYWORDS:
+            normalized.append(_IDENTIFIER)
+        else:
+            normalized.append(_LITERAL.get(kind or "", text))
+        raw.append(text)
+        lines.append(line)
+    return normalized, raw, lines
+
+
+def find_clone_classes(
+    files: dict[str, tuple[list[str], list[str], list[int]]],
+    min_tokens: int = CLONE_MIN_TOKENS,
+    roles: Optional[dict[str, str]] = None,
+) -> list[CloneClass]:
+    """Clone classes among token streams, as clone_tokens() returns them.
+
+    Args:
+        files: path -> (normalized tokens, tokens, lines)
+        min_tokens: Shortest clone, in tokens
+        roles: path -> role; classes entirely in TEST/MIGRATION files are dropped
+    """
+    roles = roles or {}
+    streams = [
+        _Stream(path, *files[path])
+        for path in sorted(files)
+        if len(files[path][0]) >= min_tokens
+    ]
+    k = min(KGRAM_TOKENS, min_tokens)
+    window = min_tokens - k + 1
+
+    vocabulary: dict[str, int] = {}
+    index: dict[int, list[tuple[int, int]]] = {}
+    for number, stream in enumerate(streams):
+        ids = [vocabulary.setdefault(t, len(vocabulary)) for t in stream.normalized]
+        hashes = kgram_hashes(ids, k)
+        selected = winnow(hashes, window)
+        for position in compress(range(len(hashes)), map(selected.__contains__, hashes)):
+            index.setdefault(hashes[position], []).append((number, position))
+
+    # Maximal runs shared by two places, found once per diagonal stretch
+    found: dict[tuple[int, int, int], list[tuple[int, int]]] = {}
+    groups: dict[tuple[int, ...], set[tuple[int, int]]] = {}
+    for postings in index.values():
+        if len(postings) < 2 or len(postings) > MAX_COPIES:
+            continue
+        for i, (fa, a) in enumerate(postings):
+            for fb, b in postings[i + 1 :]:
+                diagonal = found.setdefault((fa, fb, a - b), [])
+                if any(start <= a < start + length for start, length in diagonal):
+                    continue
+                start_a, start_b, length = _extend(streams[fa], a, streams[fb], b)
+                diagonal.append((start_a, max(length, 1)))
+                if length < min_tokens:
+                    continue
+                if fa == fb and start_a + length > start_b:
+                    continue  # overlaps its own copy
+                key = tuple(
+                    vocabulary[t] for t in streams[fa].normalized[start_a : start_a + length]
+                )
+                if len(set(key)) < MIN_DISTINCT_TOKENS:
+                    continue
+                groups.setdefault(key, set()).update({(fa, start_a), (fb, start_b)})
+    del index, found
+
+    classes = []
+    for key, members in groups.items():
+        length = len(key)
+        places = _disjoint(sorted(members), length)
+        if len(places) < 2:
+            continue
+        if all(roles.get(streams[f].path, "").upper() in EXCLUDED_ROLES for f, _ in places):
+            continue
+        texts = {tuple(streams[f].raw[s : s + length]) for f, s in places}
+        normalized = streams[places[0][0]].normalized[places[0][1] : places[0][1] + length]
+        classes.append(
+            CloneClass(
+                clone_type=1 if len(texts) == 1 else 2,
+                tokens=length,
+                fragments=tuple(
+                    sorted(
+                        Fragment(
+                            streams[f].path, streams[f].lines[s], streams[f].lines[s + length - 1]
+                        )
+                        for f, s in places
+                    )
+                ),
+                digest=hashlib.sha1(" ".join(normalized).encode()).hexdigest()[:12],
+            )
+        )
+    classes.sort(key=lambda c: (-c.tokens * (len(c.fragments) - 1), c.fragments))
+    return _outermost(classes)
+
+
+def analyze_duplication(
+    syntax: dict[str, FileSyntax],
+    contents: dict[str, str],
+    min_tokens: int = CLONE_MIN_TOKENS,
+    roles: Optional[dict[str, str]] = None,
+) -> DuplicationReport:
+    """Clone classes of the files, and the share of each package's lines in them."""
+    files = {
+        path: clone_tokens(contents[path], fs.language)
+        for path, fs in syntax.items()
+        if fs.language not in SKIPPED_LANGUAGES and contents.get(path)
+    }
+    report = DuplicationReport(classes=find_clone_classes(files, min_tokens, roles))
+
+    duplicated: dict[str, set[int]] = {}
+    for clone in report.classes:
+        for fragment in clone.fragments:
+            duplicated.setdefault(fragment.path, set()).update(
+                range(fragment.start_line, fragment.end_line + 1)
+            )
+    packages: dict[str, PackageDuplication] = {}
+    for path in sorted(files):
+        name = SourceSet.package_of(path)
+        package = packages.setdefault(name, PackageDuplication(name))
+        package.files += 1
+        package.lines += contents[path].count("\n") + 1
+        package.duplicated_lines += len(duplicated.get(path, ()))
+    report.packages = sorted(
+        packages.values(), key=lambda p: (-p.duplication, -p.duplicated_lines, p.package)
+    )
+    return report
+
+
+def _extend(a: _Stream, i: int, b: _Stream, j: int) -> tuple[int, int, int]:
+    """(start in a, start in b, length) of the longest common run through i and j."""
+    left = 0
+    while min(i, j) > left and a.normalized[i - left - 1] == b.normalized[j - left - 1]:
+        left += 1
+    right = 0
+    limit = min(len(a.normalized) - i, len(b.normalized) - j)
+    while right < limit and a.normalized[i + right] == b.normalized[j + right]:
+        right += 1
+    return i - left, j - left, left + right
+
+
+def _disjoint(places: list[tuple[int, int]], length: int) -> list[tuple[int, int]]:
+    """Sorted (file, start) places, leaving out those overlapping the one before.
+
+    A repetitive stretch of code matches a copy elsewhere at several shifts.
+    """
+    kept: list[tuple[int, int]] = []
+    for number, start in places:
+        if kept and kept[-1][0] == number and start < kept[-1][1] + length:
+            continue
+        kept.append((number, start))
+    return kept
+
+
+def _outermost(classes: list[CloneClass]) -> list[CloneClass]:
+    """Classes, leaving out those whose copies all lie in the copies of a larger one."""
+    kept: list[CloneClass] = []
+    spans: dict[str, list[tuple[int, int]]] = {}
+    for clone in sorted(classes, key=lambda c: -c.tokens):
+        covered = all(
+            any(s <= f.start_line and f.end_line <= e for s, e in spans.get(f.path, ()))
+            for f in clone.fragments
+        )
+        if covered:
+            continue
+        kept.append(clone)
+        for f in clone.fragments:
+            spans.setdefault(f.path, []).append((f.start_line, f.end_line))
+    order = {id(c): n for n, c in enumerate(classes)}
+    return sorted(kept, key=lambda c: order[id(c)])
+
+
+def to_findings(report: DuplicationReport) -> list:
+    """Convert clone classes to code_clone findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for clone in report.classes:
+        places = ", ".join(f.location for f in clone.fragments[:MAX_LISTED])
+        if len(clone.fragments) > MAX_LISTED:
+            places += f" and {len(clone.fragments) - MAX_LISTED} more"
+        kind = (
+            "identical tokens"
+            if clone.clone_type == 1
+            else "same tokens up to identifiers and literals"
+        )
+        findings.append(
+            Finding(
+                finding_type=CODE_CLONE_TYPE,
+                severity=clone.severity,
+                title=(
+                    f"{clone.lines} lines of code are repeated in {len(clone.fragments)} "
+                    f"places (type-{clone.clone_type} clone)"
+                ),
+                files=clone.files,
+                evidence=[
+                    Evidence(
+                        signal="clone_tokens",
+                        value=float(clone.tokens),
+                        percentile=0.0,
+                        description=f"{clone.tokens} tokens per copy, {kind}",
+                    ),
+                    Evidence(
+                        signal="copies",
+                        value=float(len(clone.fragments)),
+                        percentile=0.0,
+                        description=places,
+                    ),
+                ],
+                suggestion=(
+                    "Extract the repeated code into one function or shared module, "
+                    "passing what differs between the copies as parameters"
+                ),
+                effort="MEDIUM",
+                identity_hint=clone.digest,
+            )
+        )
+    return findings
@@ -0,0 +1,212 @@
+"""Complexity of SQL and HTML embedded in code, charged to the host function.
+
+scanning/embedded.py finds the snippets; each is measured by the analyzer
+for its language:
+
+    sql   scanning.sql.statement_complexity of its statements, summed,
+          and the tables it references
+    html  the nesting depth of its elements (scanning.sfc.template_depth),
+          and 1 + its template branches: {{ if }}, {{ range }}, {% for %},
+          {{#each}}, v-if, *ngFor, th:each
+
+A function's embedded complexity is the sum over the snippets inside it. It
+is reported next to the function's own complexity, counted with the snippet
+lines blanked: otherwise a query's CASE/WHEN and a template's ifs count as
+branches of the host. Snippets outside every function belong to
+``<module>``.
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Optional
+
+from ..scanning.embedded import EMBEDDED_LANGUAGES, find_snippets, is_host
+from ..scanning.sfc import template_depth
+from ..scanning.sql import referenced_tables, split_statements
+from .function_outliers import function_complexity
+
+if TYPE_CHECKING:
+    from ..scanning.embedded import Snippet
+    from ..scanning.syntax import FileSyntax
+
+MODULE_FUNCTION = "<module>"
+
+_TEMPLATE_BRANCH_RE = re.compile(
+    r"\{\{-?\s*(?:if|else\s+if|range|with)\b|\{%-?\s*(?:if|elif|for)\b"
+    r"|\{\{[#^](?:if|each|unless|with)\b"
+    r"|(?<![\w-])(?:v-if|v-else-if|v-for|\*ngIf|\*ngFor|th:if|th:each|x-if|x-for)="
+)
+
+
+@dataclass(frozen=True)
+class EmbeddedSample:
+    """A measured snippet and the function it sits in."""
+
+    path: str
+    function: str
+    line: int
+    lines: int
+    language: str  # one of EMBEDDED_LANGUAGES
+    complexity: int
+    depth: int = 0  # element nesting, html only
+    tables: tuple[str, ...] = ()  # sql only
+
+    @property
+    def label(self) -> str:
+        return f"{self.path}:{self.line} {self.function}"
+
+    def to_dict(self) -> dict:
+        return {**self.__dict__, "tables": list(self.tables)}
+
+
+@dataclass(frozen=True)
+class HostFunction:
+    """A function holding snippets: its own complexity and theirs."""
+
+    path: str
+    function: str
+    line: int
+    complexity: int  # 1 + decision points outside the snippets
+    embedded_complexity: int
+    snippets: int
+    languages: tuple[str, ...]
+
+    @property
+    def label(self) -> str:
+        return f"{self.path}:{self.line} {self.function}"
+
+    def to_dict(self) -> dict:
+        return {**self.__dict__, "languages": list(self.languages)}
+
+
+@dataclass
+class EmbeddedReport:
+    """Embedded snippets and the functions that hold them.
+
+    Attributes:
+        snippets: Most complex first
+        functions: Most embedded complexity first
+    """
+
+    snippets: list[EmbeddedSample] = field(default_factory=list)
+    functions: list[HostFunction] = field(default_factory=list)
+
+    def by_language(self) -> dict[str, int]:
+        counts = dict.fromkeys(EMBEDDED_LANGUAGES, 0)
+        counts.update(Counter(s.language for s in self.snippets))
+        return counts
+
+    def tables(self) -> dict[str, list[str]]:
+        """Table -> functions querying it, most queried first."""
+        found: dict[str, list[str]] = {}
+        for s in sorted(self.snippets, key=lambda s: (s.path, s.line)):
+            for table in s.tables:
+                if s.label not in found.setdefault(table, []):
+                    found[table].append(s.label)
+        return dict(sorted(found.items(), key=lambda item: (-len(item[1]), item[0])))
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "by_language": self.by_language(),
+            "snippets": [s.to_dict() for s in self.snippets[:top]],
+            "functions": [f.to_dict() for f in self.functions[:top]],
+            "tables": dict(list(self.tables().items())[:top]),
+        }
+
+
+def collect_embedded(
+    files: dict[str, FileSyntax], contents: dict[str, str], language: Optional[str] = None
+) -> EmbeddedReport:
+    """Measure the embedded snippets of every host file in *files*.
+
+    Args:
+        files: path -> FileSyntax
+        contents: path -> source text
+        language: Only snippets of this language (sql or html)
+    """
+    report = EmbeddedReport()
+    for path, syntax in sorted(files.items()):
+        if not is_host(syntax.language):
+            continue
+        content = contents.get(path, "")
+        found = find_snippets(content)
+        snippets = [s for s in found if language is None or s.language == language]
+        if not snippets:
+            continue
+        lines = content.splitlines()
+        hosts: dict[Optional[int], list[EmbeddedSample]] = {}
+        functions = _functions(syntax)
+        for snippet in snippets:
+            fn = _host(functions, snippet.start_line)
+            sample = _measure(path, fn.name if fn else MODULE_FUNCTION, snippet)
+            report.snippets.append(sample)
+            hosts.setdefault(id(fn) if fn else None, []).append(sample)
+        for fn in [None, *functions]:
+            samples = hosts.get(id(fn) if fn else None)
+            if samples:
+                report.functions.append(_host_function(path, fn, samples, found, lines))
+
+    report.snippets.sort(key=lambda s: (-s.complexity, s.path, s.line))
+    report.functions.sort(key=lambda f: (-f.embedded_complexity, f.path, f.line))
+    return report
+
+
+def _functions(syntax: FileSyntax) -> list:
+    functions = list(syntax.functions)
+    seen = {id(fn) for fn in functions}
+    for cls in syntax.classes:
+        functions.extend(m for m in cls.methods if id(m) not in seen)
+    return functions
+
+
+def _host(functions: list, line: int):
+    """The innermost function whose span holds *line*."""
+    holding = [fn for fn in functions if fn.start_line <= line <= fn.end_line]
+    return max(holding, key=lambda fn: fn.start_line, default=None)
+
+
+def _measure(path: str, function: str, snippet: Snippet) -> EmbeddedSample:
+    if snippet.language == "sql":
+        statements = split_statements(snippet.text)
+        return EmbeddedSample(
+            path,
+            function,
+            snippet.start_line,
+            snippet.lines,
+            "sql",
+            sum(s.complexity for s in statements) or 1,
+            tables=tuple(referenced_tables(snippet.text)),
+        )
+    return EmbeddedSample(
+        path,
+        function,
+        snippet.start_line,
+        snippet.lines,
+        "html",
+        1 + len(_TEMPLATE_BRANCH_RE.findall(snippet.text)),
+        depth=template_depth(snippet.text),
+    )
+
+
+def _host_function(
+    path: str, fn, samples: list[EmbeddedSample], snippets: list[Snippet], lines: list[str]
+) -> HostFunction:
+    if fn is None:
+        name, line, own = MODULE_FUNCTION, 1, 0
+    else:
+        name, line = fn.name, fn.start_line
+        inside = {n for s in snippets for n in range(s.start_line, s.end_line + 1)}
+        span = range(fn.start_line, min(fn.end_line, len(lines)) + 1)
+        own = function_complexity(["" if n in inside else lines[n - 1] for n in span])
+    return HostFunction(
+        path,
+        name,
+        line,
+        own,
+        sum(s.complexity for s in samples),
+        len(samples),
+        tuple(sorted({s.language for s in samples})),
+    )
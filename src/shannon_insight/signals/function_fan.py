@@ -0,0 +1,183 @@
+"""Fan-in and fan-out of functions in the call graph.
+
+    fan_in   distinct functions that call it
+    fan_out  distinct functions and classes of the codebase it calls
+
+Both are counted on the symbol call graph (graph/symbols.py), so calls into
+libraries are not counted and same-named methods of one file count once.
+The two extremes are reported as separate findings:
+
+    load_bearing_function   fan-in above ``fan_in_threshold`` (10): a
+                            change to it reaches every caller, so its
+                            behaviour is effectively frozen
+    orchestrator_function   fan-out above ``fan_out_threshold`` (10): it
+                            coordinates so many parts that it must change
+                            whenever any of them does
+
+Call targets come from tree-sitter; files parsed by the regex fallback have
+none, so without tree-sitter nothing is reported.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from typing import TYPE_CHECKING
+
+from ..graph.symbols import call_graph
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+LOAD_BEARING_TYPE = "load_bearing_function"
+ORCHESTRATOR_TYPE = "orchestrator_function"
+
+# Defaults for AnalysisConfig.fan_in_threshold and fan_out_threshold
+FAN_IN_THRESHOLD = 10
+FAN_OUT_THRESHOLD = 10
+
+
+@dataclass(frozen=True)
+class FunctionFan:
+    path: str
+    name: str
+    line: int
+    fan_in: int
+    fan_out: int
+    caller_files: int  # distinct files the callers are in
+    callee_files: int  # distinct files the callees are in
+
+    @property
+    def location(self) -> str:
+        return f"{self.path}:{self.line}"
+
+
+def collect_fan(
+    files: dict[str, FileSyntax], imports: dict[str, list[str]]
+) -> list[FunctionFan]:
+    """Fan-in and fan-out of every function, in file and line order.
+
+    Args:
+        files: path -> FileSyntax of every parsed file
+        imports: path -> paths it imports (the dependency graph adjacency)
+    """
+    symbols, edges = call_graph(files, imports)
+    callers: dict[str, set[str]] = {symbol_id: set() for symbol_id in symbols}
+    for caller, callees in edges.items():
+        for callee in callees:
+            callers[callee].add(caller)
+
+    measured = []
+    for symbol_id, symbol in symbols.items():
+        if symbol.kind != "function":
+            continue
+        callees = edges[symbol_id]
+        measured.append(
+            FunctionFan(
+                path=symbol.path,
+                name=symbol.name,
+                line=symbol.line,
+                fan_in=len(callers[symbol_id]),
+                fan_out=len(callees),
+                caller_files=len({symbols[c].path for c in callers[symbol_id]}),
+                callee_files=len({symbols[c].path for c in callees}),
+            )
+        )
+    return sorted(measured, key=lambda fn: (fn.path, fn.line, fn.name))
+
+
+def find_load_bearing(
+    functions: list[FunctionFan], threshold: int = FAN_IN_THRESHOLD
+) -> list[FunctionFan]:
+    """Functions called by more than *threshold* others, most callers first."""
+    found = [fn for fn in functions if fn.fan_in > threshold]
+    return sorted(found, key=lambda fn: (-fn.fan_in, fn.path, fn.line))
+
+
+def find_orchestrators(
+    functions: list[FunctionFan], threshold: int = FAN_OUT_THRESHOLD
+) -> list[FunctionFan]:
+    """Functions calling more than *threshold* others, most callees first."""
+    found = [fn for fn in functions if fn.fan_out > threshold]
+    return sorted(found, key=lambda fn: (-fn.fan_out, fn.path, fn.line))
+
+
+def to_findings(
+    load_bearing: list[FunctionFan],
+    orchestrators: list[FunctionFan],
+    fan_in_threshold: int = FAN_IN_THRESHOLD,
+    fan_out_threshold: int = FAN_OUT_THRESHOLD,
+) -> list:
+    """Convert high fan-in and high fan-out functions to findings of their own types."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for fn in load_bearing:
+        findings.append(
+            Finding(
+                finding_type=LOAD_BEARING_TYPE,
+                severity=_severity(fn.fan_in, fan_in_threshold),
+                title=(
+                    f"{fn.name} at {fn.location} is called by {fn.fan_in} functions "
+                    f"(threshold {fan_in_threshold})"
+                ),
+                files=[fn.path],
+                evidence=[
+                    Evidence(
+                        signal="fan_in",
+                        value=float(fn.fan_in),
+                        percentile=0.0,
+                        description=f"{fn.fan_in} callers in {fn.caller_files} file(s)",
+                    ),
+                    Evidence(
+                        signal="fan_out",
+                        value=float(fn.fan_out),
+                        percentile=0.0,
+                        description=f"calls {fn.fan_out} function(s) itself",
+                    ),
+                ],
+                suggestion=(
+                    f"Treat {fn.name} as an interface: keep its behaviour stable, test it "
+                    "thoroughly and change it behind a new function rather than in place"
+                ),
+                effort="LOW",
+                identity_hint=fn.name,
+            )
+        )
+    for fn in orchestrators:
+        findings.append(
+            Finding(
+                finding_type=ORCHESTRATOR_TYPE,
+                severity=_severity(fn.fan_out, fan_out_threshold),
+                title=(
+                    f"{fn.name} at {fn.location} calls {fn.fan_out} functions "
+                    f"(threshold {fan_out_threshold})"
+                ),
+                files=[fn.path],
+                evidence=[
+                    Evidence(
+                        signal="fan_out",
+                        value=float(fn.fan_out),
+                        percentile=0.0,
+                        description=f"{fn.fan_out} callees in {fn.callee_files} file(s)",
+                    ),
+                    Evidence(
+                        signal="fan_in",
+                        value=float(fn.fan_in),
+                        percentile=0.0,
+                        description=f"called by {fn.fan_in} function(s)",
+                    ),
+                ],
+                suggestion=(
+                    f"Split {fn.name} into steps that each coordinate a few callees, "
+                    "or move decisions into the functions it calls"
+                ),
+                effort="MEDIUM",
+                identity_hint=fn.name,
+            )
+        )
+    return findings
+
+
+def _severity(count: int, threshold: int) -> float:
+    """0.3 just past the threshold, 0.7 at three times it."""
+    return min(0.7, 0.3 + 0.2 * (count - threshold) / threshold)
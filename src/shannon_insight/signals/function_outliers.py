@@ -0,0 +1,257 @@
+"""Function complexity outliers, explained by nearest-neighbor examples.
+
+A function is an outlier when its cyclomatic complexity is extreme compared
+with functions of about its size: a 300-line parser is judged against other
+long functions, not against one-line getters.
+
+What is typical at each size is aggregated as a stream: every function
+adds its complexity to a t-digest (math/sketches.py) of its size band,
+half an octave of lengths wide, and is dropped (ComplexityBands, also
+built by FunctionStats). A band with fewer than NEIGHBORHOOD functions is
+widened by the bands next to it. A second pass over the functions keeps
+only the outliers and, per length, a few others as reference points, so
+memory grows with the number of distinct sizes, not of functions.
+
+Scores alone rarely convince anyone, so each outlier carries the
+REFERENCE_POINTS most similar-sized functions in the same repo that were NOT
+flagged, and a sentence comparing them:
+
+    functions of similar size (~120 lines) typically have complexity 8, this has 41
+"""
+
+from __future__ import annotations
+
+import math
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Iterable, Iterator, Mapping, Optional
+
+from ..math.robust import OUTLIER_Z
+from ..math.sketches import TDigest
+from .complexity import DECISION_RE, is_comment_line
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+OUTLIER_TYPE = "complexity_outlier"
+
+# Functions compared against each other to find the typical complexity
+NEIGHBORHOOD = 20
+
+# Size bands per doubling of function length
+BANDS_PER_OCTAVE = 2
+
+# Non-anomalous functions reported alongside each outlier
+REFERENCE_POINTS = 3
+
+# Below this, a function is never worth reporting however unusual it is
+MIN_OUTLIER_COMPLEXITY = 10
+
+# Too few functions give no meaningful notion of "typical"
+MIN_FUNCTIONS = 30
+
+
+@dataclass(frozen=True)
+class FunctionSample:
+    path: str
+    name: str
+    line: int
+    lines: int
+    complexity: int  # 1 + decision points
+    cell: Optional[int] = None  # notebook cell the function starts in
+
+    @property
+    def location(self) -> str:
+        if self.cell is not None:
+            return f"{self.path} cell {self.cell}"
+        return f"{self.path}:{self.line}"
+
+    @property
+    def label(self) -> str:
+        return f"{self.location} {self.name}"
+
+
+@dataclass
+class FunctionOutlier:
+    function: FunctionSample
+    typical_complexity: float  # median of the size neighborhood
+    modified_z: float
+    references: list[FunctionSample]
+
+    @property
+    def explanation(self) -> str:
+        return (
+            f"functions of similar size (~{self.function.lines} lines) typically have "
+            f"complexity {self.typical_complexity:g}, this has {self.function.complexity}"
+        )
+
+    @property
+    def severity(self) -> float:
+        ratio = self.function.complexity / max(self.typical_complexity, 1.0)
+        return min(0.8, 0.4 + 0.1 * math.log2(ratio))
+
+
+def function_complexity(block: list[str]) -> int:
+    """Cyclomatic complexity of a function body: 1 + decision points."""
+    code = (ln for ln in block if ln.strip() and not is_comment_line(ln))
+    return 1 + sum(len(DECISION_RE.findall(ln)) for ln in code)
+
+
+def collect_functions(
+    files: Mapping[str, FileSyntax], contents: Mapping[str, str]
+) -> Iterator[FunctionSample]:
+    """Size and complexity of every function with a known line span, file by file."""
+    for path, syntax in files.items():
+        lines = contents.get(path, "").splitlines()
+        for fn in syntax.functions:
+            if fn.end_line < fn.start_line or fn.end_line > len(lines):
+                continue
+            yield FunctionSample(
+                path=path,
+                name=fn.name,
+                line=fn.start_line,
+                lines=fn.end_line - fn.start_line + 1,
+                complexity=function_complexity(lines[fn.start_line - 1 : fn.end_line]),
+                cell=fn.cell,
+            )
+
+
+def size_band(lines: int) -> int:
+    """Size band of a function of *lines* lines."""
+    return math.floor(BANDS_PER_OCTAVE * math.log2(max(lines, 1)))
+
+
+@dataclass
+class ComplexityBands:
+    """A t-digest of function complexity per size band; merges like FunctionStats."""
+
+    digests: dict[int, TDigest] = field(default_factory=dict)
+
+    @property
+    def functions(self) -> int:
+        return round(sum(digest.count for digest in self.digests.values()))
+
+    def add(self, lines: int, complexity: int) -> None:
+        self.digests.setdefault(size_band(lines), TDigest()).add(complexity)
+
+    def merge(self, other: ComplexityBands) -> None:
+        for band, digest in other.digests.items():
+            self.digests.setdefault(band, TDigest()).merge(digest)
+
+    def typical(self, band: int) -> tuple[float, float]:
+        """(median, MAD) complexity around *band*, widened to NEIGHBORHOOD functions."""
+        neighborhood = TDigest()
+        lo = hi = band
+        bands = sorted(self.digests)
+        while True:
+            for b in {lo, hi}:
+                if b in self.digests:
+                    neighborhood.merge(self.digests[b])
+            if neighborhood.count >= NEIGHBORHOOD or not bands:
+                break
+            if lo <= bands[0] and hi >= bands[-1]:
+                break
+            lo, hi = lo - 1, hi + 1
+        median = neighborhood.quantile(0.5)
+        deviations = TDigest()
+        for mean, weight in neighborhood.centroids():
+            deviations.add(abs(mean - median), weight)
+        return median, deviations.quantile(0.5)
+
+
+def find_function_outliers(
+    samples: Iterable[FunctionSample],
+    threshold: float = OUTLIER_Z,
+    bands: Optional[ComplexityBands] = None,
+) -> list[FunctionOutlier]:
+    """Functions far more complex than others of their size, worst first.
+
+    *bands* is the first pass over the same functions (FunctionStats.bands);
+    without it *samples* is read twice.
+    """
+    if bands is None:
+        samples = list(samples)
+        bands = ComplexityBands()
+        for sample in samples:
+            bands.add(sample.lines, sample.complexity)
+    if bands.functions < MIN_FUNCTIONS:
+        return []
+
+    typical: dict[int, tuple[float, float]] = {}
+    flagged: list[tuple[FunctionSample, float, float]] = []
+    references: dict[int, list[FunctionSample]] = {}  # by length, REFERENCE_POINTS at most
+    for sample in samples:
+        if sample.complexity >= MIN_OUTLIER_COMPLEXITY:
+            band = size_band(sample.lines)
+            if band not in typical:
+                typical[band] = bands.typical(band)
+            median, mad = typical[band]
+            # MAD of 0 is common (many trivial functions); 1 keeps the score finite
+            z = 0.6745 * (sample.complexity - median) / max(mad, 1.0)
+            if z > threshold:
+                flagged.append((sample, round(median, 1), z))
+                continue
+        same_size = references.setdefault(sample.lines, [])
+        same_size.append(sample)
+        same_size.sort(key=lambda s: (s.path, s.line))
+        del same_size[REFERENCE_POINTS:]
+
+    outliers = []
+    for sample, median, z in flagged:
+        size = math.log(sample.lines)
+        nearest = sorted(
+            (ref for same_size in references.values() for ref in same_size),
+            key=lambda r: (abs(math.log(r.lines) - size), r.lines, r.path, r.line),
+        )
+        outliers.append(FunctionOutlier(sample, median, z, nearest[:REFERENCE_POINTS]))
+    return sorted(outliers, key=lambda o: (-o.modified_z, o.function.path, o.function.line))
+
+
+def to_findings(outliers: list[FunctionOutlier]) -> list:
+    """Convert outliers to ``complexity_outlier`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for outlier in outliers:
+        fn = outlier.function
+        evidence = [
+            Evidence(
+                signal="complexity",
+                value=float(fn.complexity),
+                percentile=0.0,
+                description=f"complexity {fn.complexity}",
+            ),
+            Evidence(
+                signal="typical_complexity",
+                value=outlier.typical_complexity,
+                percentile=0.0,
+                description=outlier.explanation,
+            ),
+            Evidence(
+                signal="lines",
+                value=float(fn.lines),
+                percentile=0.0,
+                description=f"{fn.lines} lines",
+            ),
+        ]
+        evidence.extend(
+            Evidence(
+                signal="reference",
+                value=float(ref.complexity),
+                percentile=0.0,
+                description=f"{ref.label}: {ref.lines} lines, complexity {ref.complexity}",
+            )
+            for ref in outlier.references
+        )
+        findings.append(
+            Finding(
+                finding_type=OUTLIER_TYPE,
+                severity=outlier.severity,
+                title=f"{fn.name} at {fn.location}: {outlier.explanation}",
+                files=[fn.path],
+                evidence=evidence,
+                suggestion="Compare with the reference functions of similar size",
+                effort="MEDIUM",
+                identity_hint=fn.name,
+            )
+        )
+    return findings
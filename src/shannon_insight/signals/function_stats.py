@@ -0,0 +1,169 @@
+"""Function size and complexity per package, aggregated as a stream.
+
+Every function contributes its length in lines and its cyclomatic
+complexity (function_outliers.function_complexity) to its package's
+aggregate, and is then dropped:
+
+    counters   functions, their summed lines and complexity, the most
+               complex one, and the longest one (with its name)
+    sketches   a t-digest each of function lengths and complexities
+               (math/sketches.py), for the median and 90th percentile
+
+The same pass fills the codebase's complexity digests by size band
+(function_outliers.ComplexityBands), which complexity outliers are judged
+against, so the functions are read once for both.
+
+How unevenly a package's code is spread over its functions is the Gini
+coefficient of function lengths, computed over the length sketch's
+centroids (exact while every length is its own centroid), next to the
+share of the package's function lines in its longest function: a package
+whose mass sits in one 800-line function scores near 1 on both, one of
+evenly sized functions near 0.
+
+So memory grows with the number of packages, not of functions, and a
+file's content is only needed while it is being added. Aggregates merge:
+partial ones built over different files (by workers, or per shard of a
+monorepo) combine into the aggregate of all of them, and the codebase's
+distribution is the merge of its packages'.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Iterable, Optional
+
+from ..hygiene.sources import SourceSet
+from ..math.gini import Gini
+from ..math.sketches import TDigest
+from .function_outliers import ComplexityBands, collect_functions
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+
+@dataclass
+class PackageFunctions:
+    """Counters and sketches over the functions of one package."""
+
+    package: str
+    functions: int = 0
+    lines: int = 0  # summed over the functions
+    complexity: int = 0  # summed over the functions
+    max_complexity: int = 0
+    max_lines: int = 0
+    longest: str = ""  # "name (path:line)" of the longest function
+    line_digest: TDigest = field(default_factory=TDigest, repr=False)
+    complexity_digest: TDigest = field(default_factory=TDigest, repr=False)
+
+    @property
+    def mean_complexity(self) -> float:
+        return self.complexity / self.functions if self.functions else 0.0
+
+    @property
+    def size_gini(self) -> float:
+        """Gini coefficient of function lengths: 0 evenly sized, near 1 one function."""
+        return Gini.weighted_gini_coefficient(self.line_digest.centroids())
+
+    @property
+    def longest_share(self) -> float:
+        """Share of the package's function lines in its longest function."""
+        return self.max_lines / self.lines if self.lines else 0.0
+
+    def add(self, lines: int, complexity: int, name: str = "") -> None:
+        self.functions += 1
+        self.lines += lines
+        self.complexity += complexity
+        self.max_complexity = max(self.max_complexity, complexity)
+        if lines > self.max_lines:
+            self.max_lines, self.longest = lines, name
+        self.line_digest.add(lines)
+        self.complexity_digest.add(complexity)
+
+    def merge(self, other: PackageFunctions) -> None:
+        self.functions += other.functions
+        self.lines += other.lines
+        self.complexity += other.complexity
+        self.max_complexity = max(self.max_complexity, other.max_complexity)
+        if other.max_lines > self.max_lines:
+            self.max_lines, self.longest = other.max_lines, other.longest
+        self.line_digest.merge(other.line_digest)
+        self.complexity_digest.merge(other.complexity_digest)
+
+    def to_dict(self) -> dict:
+        return {
+            "package": self.package,
+            "functions": self.functions,
+            "lines": self.lines,
+            "lines_p50": round(self.line_digest.quantile(0.5), 2),
+            "lines_p90": round(self.line_digest.quantile(0.9), 2),
+            "max_lines": self.max_lines,
+            "longest": self.longest,
+            "longest_share": round(self.longest_share, 4),
+            "size_gini": round(self.size_gini, 4),
+        }
+
+
+@dataclass
+class FunctionStats:
+    """Streaming aggregate of function size and complexity, by package."""
+
+    packages: dict[str, PackageFunctions] = field(default_factory=dict)
+    bands: ComplexityBands = field(default_factory=ComplexityBands, repr=False)
+
+    def add_file(self, path: str, syntax: FileSyntax, content: str) -> None:
+        """Fold the functions of one file into its package."""
+        package: Optional[PackageFunctions] = None
+        for fn in collect_functions({path: syntax}, {path: content}):
+            if package is None:
+                name = SourceSet.package_of(path)
+                package = self.packages.setdefault(name, PackageFunctions(name))
+            package.add(fn.lines, fn.complexity, f"{fn.name} ({path}:{fn.line})")
+            self.bands.add(fn.lines, fn.complexity)
+
+    def merge(self, other: FunctionStats) -> None:
+        """Fold another aggregate, over other files, into this one."""
+        for name, package in other.packages.items():
+            self.packages.setdefault(name, PackageFunctions(name)).merge(package)
+        self.bands.merge(other.bands)
+
+    def total(self) -> PackageFunctions:
+        """The whole codebase, as one package named ''."""
+        total = PackageFunctions("")
+        for package in self.packages.values():
+            total.merge(package)
+        return total
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        total = self.total()
+        global_signals: dict[str, float] = {}
+        if total.functions:
+            global_signals = {
+                "function_complexity_p50": round(total.complexity_digest.quantile(0.5), 2),
+                "function_complexity_p90": round(total.complexity_digest.quantile(0.9), 2),
+                "function_lines_p90": round(total.line_digest.quantile(0.9), 2),
+            }
+        package_signals = {
+            name: {
+                "function_count": float(p.functions),
+                "function_complexity_mean": round(p.mean_complexity, 2),
+                "function_complexity_max": float(p.max_complexity),
+                "function_complexity_p90": round(p.complexity_digest.quantile(0.9), 2),
+                "function_lines_p50": round(p.line_digest.quantile(0.5), 2),
+                "function_lines_p90": round(p.line_digest.quantile(0.9), 2),
+                "function_size_gini": round(p.size_gini, 4),
+                "longest_function_share": round(p.longest_share, 4),
+            }
+            for name, p in self.packages.items()
+        }
+        return global_signals, package_signals
+
+
+def aggregate_functions(
+    files: Iterable[tuple[str, FileSyntax, str]],
+) -> FunctionStats:
+    """Aggregate (path, syntax, content) triples; each is released once added."""
+    stats = FunctionStats()
+    for path, syntax, content in files:
+        stats.add_file(path, syntax, content)
+    return stats
@@ -28,10 +28,18 @@ from collections import Counter
 from typing import TYPE_CHECKING
 
 from shannon_insight.math.gini import Gini
+from shannon_insight.signals.comments import file_comments
+from shannon_insight.signals.complexity import cognitive_load
 from shannon_insight.signals.composites import compute_composites
 from shannon_insight.signals.health_laplacian import compute_all_raw_risks, compute_health_laplacian
+from shannon_insight.signals.maintainability import (
+    file_maintainability,
+    package_maintainability,
+    resolve_weights,
+)
 from shannon_insight.signals.models import FileSignals, ModuleSignals, SignalField
 from shannon_insight.signals.normalization import normalize
+from shannon_insight.signals.token_entropy import token_entropy
 
 if TYPE_CHECKING:
     from shannon_insight.insights.store import AnalysisStore
@@ -115,8 +123,10 @@ class FusionPipeline:
     def _fill_from_graph(self, fs: FileSignals, syntax) -> None:
         """Fill IR3 graph signals from structural analysis.
 
-        Also computes compression_ratio and cognitive_load here (signal layer)
-        instead of in graph layer.
+        Also computes compression_ratio, token_entropy and cognitive_load here
+        (signal layer) instead of in graph layer, except for generated files.
+        Halstead metrics, maintainability_index and the comment signals are
+        likewise left at 0 for generated files.
         """
         if not self.store.structural.available:
             return
@@ -137,15 +147,31 @@ class FusionPipeline:
             fs.phantom_import_count = fa.phantom_import_count
             fs.community = fa.community_id
 
-        # Compute compression_ratio from cached content
-        content = self.store.get_content(path)
-        if content:
-            from shannon_insight.math.compression import Compression
-
-            fs.compression_ratio = Compression.compression_ratio(content.encode("utf-8"))
-
-        # Compute cognitive_load from syntax
-        fs.cognitive_load = self._compute_cognitive_load(syntax)
+        # Generated files are not scored for entropy or complexity
+        if path not in self.store.generated_files.get(default={}):
+            # Compute compression_ratio from cached content
+            content = self.store.get_content(path)
+            if content:
+                from shannon_insight.math.compression import Compression
+
+                fs.compression_ratio = Compression.compression_ratio(content.encode("utf-8"))
+                fs.token_entropy = self._compute_token_entropy(content, syntax)
+
+            # Compute cognitive_load from syntax
+            fs.cognitive_load = self._compute_cognitive_load(syntax, content)
+
+            if fa:
+                fs.halstead_volume = fa.halstead_volume
+                fs.halstead_difficulty = fa.halstead_difficulty
+                fs.halstead_effort = fa.halstead_effort
+            fs.maintainability_index = self._compute_maintainability(
+                content or "", fs.halstead_volume
+            )
+            if content:
+                comments = file_comments(syntax, content)
+                fs.comment_density = comments.comment_density
+                fs.commented_out_lines = comments.commented_out_lines
+                fs.undocumented_complexity = comments.undocumented_complexity
 
         # Re-compute is_orphan with role awareness (structural runs before semantics,
         # so the initial orphan detection has no role info).
@@ -206,37 +232,25 @@ class FusionPipeline:
         fs.refactor_ratio = churn.refactor_ratio
         fs.change_entropy = getattr(churn, "change_entropy", 0.0)
 
-    def _compute_cognitive_load(self, syntax) -> float:
-        """Compute cognitive load from FileSyntax.
-
-        Cognitive load: weighted sum of complexity factors.
-        Based on research on code comprehension difficulty:
-        - Lines of code (log-scaled, diminishing returns)
-        - Cyclomatic complexity (decision points to track)
-        - Nesting depth (working memory load)
-        - Function count (context switches)
-        - Gini inequality (god functions harder to understand)
-
-        Formula: log2(lines+1) * (1 + complexity/10) * (1 + nesting/5) * (1 + gini)
-
-        Output is typically 0-50 for normal files, 50-100 for complex files.
-        """
-        import math
-
-        # Log-scaled lines (1000 lines = ~10, 100 lines = ~7)
-        lines_factor = math.log2(syntax.lines + 1) if syntax.lines > 0 else 0
-
-        # Complexity factor: average cyclomatic complexity
-        complexity_factor = 1 + syntax.complexity / 10
-
-        # Nesting penalty: deep nesting is hard to follow
-        nesting_factor = 1 + syntax.max_nesting / 5
-
-        # Gini penalty: unequal function sizes suggest god functions
-        gini = syntax.impl_gini if syntax.impl_gini else 0.0
-        gini_factor = 1 + gini
+    def _compute_cognitive_load(self, syntax, content: str | None = None) -> float:
+        """Cognitive load of one file (see signals.complexity.cognitive_load)."""
+        mode = getattr(self.session.config, "complexity_normalization", "none")
+        return cognitive_load(syntax, content, mode)
+
+    def _compute_token_entropy(self, content: str, syntax) -> float:
+        """token_entropy of one file, over the configured scope and normalization."""
+        config = self.session.config
+        return token_entropy(
+            content,
+            getattr(syntax, "language", ""),
+            getattr(config, "token_entropy_scope", "all"),
+            getattr(config, "token_entropy_normalization", "none"),
+        )
 
-        return lines_factor * complexity_factor * nesting_factor * gini_factor
+    def _compute_maintainability(self, content: str, volume: float) -> float:
+        """Maintainability Index of one file (see signals.maintainability)."""
+        table = getattr(self.session.config, "maintainability_weights", {})
+        return file_maintainability(content, volume, resolve_weights(table))
 
     def _fill_hierarchy(self) -> None:
         """Fill hierarchical context fields for each file."""
@@ -358,6 +372,13 @@ class FusionPipeline:
                 if fs:
                     cog_loads.append(fs.cognitive_load)
             ms.mean_cognitive_load = sum(cog_loads) / len(cog_loads) if cog_loads else 0.0
+            # Maintainability of the package, weighted by file length
+            sized = [
+                (self.field.per_file[fpath].maintainability_index, self.field.per_file[fpath].lines)
+                for fpath in mod.files
+                if fpath in self.field.per_file
+            ]
+            ms.module_maintainability = package_maintainability(sized)
 
             self.field.per_module[path] = ms
 
@@ -403,6 +424,10 @@ class FusionPipeline:
             total_files = len(self.field.per_file)
             g.clone_ratio = len(files_in_clones) / total_files if total_files > 0 else 0.0
 
+        # Share of lines inside token-based clone classes
+        if self.store.duplication.available:
+            g.duplication_ratio = self.store.duplication.value.duplication_ratio
+
         # Violation rate from Phase 4 architecture
         if self.store.architecture.available:
             arch = self.store.architecture.value
@@ -596,9 +621,9 @@ class _ModuleTemporal:
         self.field = field
         self.store = store
 
-    def step5_composites(self) -> _Composited:
+    def step5_composites(self, health_formula: str = "") -> _Composited:
         """Compute all composite scores. Requires percentiles + module temporal."""
-        compute_composites(self.field)
+        compute_composites(self.field, health_formula)
         return _Composited(self.field, self.store)
 
 
@@ -632,6 +657,6 @@ def build(store: AnalysisStore, session: AnalysisSession) -> SignalField:
         .step2_raw_risk()
         .step3_normalize()
         .step4_module_temporal()
-        .step5_composites()
+        .step5_composites(getattr(session.config, "health_formula", ""))
         .step6_laplacian()
     )
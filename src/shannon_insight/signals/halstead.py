@@ -0,0 +1,231 @@
+"""Halstead metrics: volume, difficulty and effort from the token stream.
+
+Halstead (Elements of Software Science, 1977) splits the tokens of a
+program into operators and operands:
+
+    operators   keywords, punctuation and operator symbols; a bracket pair
+                counts once (at its opening bracket)
+    operands    identifiers and literals (strings, numbers)
+
+From the distinct operators n1 and operands n2, and their occurrences N1
+and N2:
+
+    vocabulary  n = n1 + n2
+    length      N = N1 + N2
+    volume      V = N * log2(n)            bits needed to write the code down
+    difficulty  D = (n1 / 2) * (N2 / n2)   how often operands are reused
+    effort      E = D * V                  mental effort to write or read it
+
+Tokens are read the way the regex fallback parser counts them
+(``\\w+`` words and operator characters), with comments dropped and string
+literals kept whole. Keywords are one language-agnostic set, so the same
+code scores the same in any language.
+"""
+
+from __future__ import annotations
+
+import math
+import re
+from collections import Counter
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Iterator
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+
+# Words that are operators, not operands (control flow, declarations, operators)
+KEYWORDS = frozenset(
+    {
+        "and",
+        "as",
+        "assert",
+        "async",
+        "await",
+        "break",
+        "case",
+        "catch",
+        "class",
+        "const",
+        "continue",
+        "def",
+        "default",
+        "defer",
+        "del",
+        "delete",
+        "do",
+        "elif",
+        "else",
+        "enum",
+        "except",
+        "extends",
+        "finally",
+        "fn",
+        "for",
+        "foreach",
+        "from",
+        "fun",
+        "func",
+        "function",
+        "global",
+        "go",
+        "goto",
+        "guard",
+        "if",
+        "impl",
+        "implements",
+        "import",
+        "in",
+        "instanceof",
+        "interface",
+        "is",
+        "lambda",
+        "let",
+        "match",
+        "new",
+        "nonlocal",
+        "not",
+        "or",
+        "package",
+        "pass",
+        "raise",
+        "return",
+        "select",
+        "static",
+        "struct",
+        "switch",
+        "throw",
+        "throws",
+        "try",
+        "type",
+        "typeof",
+        "unless",
+        "until",
+        "use",
+        "using",
+        "val",
+        "var",
+        "when",
+        "where",
+        "while",
+        "with",
+        "yield",
+    }
+)
+
+# Comment syntax by language; languages not listed use C-style comments
+_HASH_COMMENTS = frozenset({"python", "ruby", "yaml", "shell", "r", "perl", "elixir"})
+_DASH_COMMENTS = frozenset({"sql", "lua", "haskell"})
+
+_STRING = (
+    r'"""[\s\S]*?"""|\'\'\'[\s\S]*?\'\'\''
+    r"|\"(?:\\.|[^\"\\\n])*\"|'(?:\\.|[^'\\\n])*'|`(?:\\.|[^`\\])*`"
+)
+_NUMBER = r"\b\d[\w.]*"
+_WORD = r"[A-Za-z_$][\w$]*"
+_OPERATOR = (
+    r"\*\*=|<<=|>>=|//=|\.\.\.|===|!==|->|=>|::|\?\?|\?\.|&&|\|\||<<|>>|\*\*|//"
+    r"|[-+*/%&|^<>=!:]=|\+\+|--|[-+*/%&|^~<>=!?:;,.@({\[]"
+)
+_CLOSING = r"[)}\]]"
+
+
+def _token_re(comment: str) -> re.Pattern[str]:
+    return re.compile(
+        rf"(?P<comment>{comment})|(?P<string>{_STRING})|(?P<number>{_NUMBER})"
+        rf"|(?P<word>{_WORD})|(?P<operator>{_OPERATOR})|{_CLOSING}"
+    )
+
+
+_C_TOKENS = _token_re(r"/\*[\s\S]*?\*/|//[^\n]*")
+_HASH_TOKENS = _token_re(r"#[^\n]*")
+_DASH_TOKENS = _token_re(r"/\*[\s\S]*?\*/|--[^\n]*")
+
+
+@dataclass(frozen=True)
+class Halstead:
+    distinct_operators: int  # n1
+    distinct_operands: int  # n2
+    operators: int  # N1
+    operands: int  # N2
+
+    @property
+    def vocabulary(self) -> int:
+        return self.distinct_operators + self.distinct_operands
+
+    @property
+    def length(self) -> int:
+        return self.operators + self.operands
+
+    @property
+    def volume(self) -> float:
+        if self.vocabulary < 2:
+            return 0.0
+        return self.length * math.log2(self.vocabulary)
+
+    @property
+    def difficulty(self) -> float:
+        if self.distinct_operands == 0:
+            return 0.0
+        return (self.distinct_operators / 2) * (self.operands / self.distinct_operands)
+
+    @property
+    def effort(self) -> float:
+        return self.difficulty * self.volume
+
+    def to_dict(self) -> dict[str, float]:
+        return {
+            "halstead_volume": round(self.volume, 1),
+            "halstead_difficulty": round(self.difficulty, 2),
+            "halstead_effort": round(self.effort, 1),
+        }
+
+
+def token_matches(content: str, language: str = "") -> Iterator[re.Match[str]]:
+    """Every lexeme of *content*, comments included.
+
+    The group that matched (``match.lastgroup``) is "comment", "string",
+    "number", "word" or "operator"; closing brackets match no group.
+    """
+    if language in _HASH_COMMENTS:
+        pattern = _HASH_TOKENS
+    elif language in _DASH_COMMENTS:
+        pattern = _DASH_TOKENS
+    else:
+        pattern = _C_TOKENS
+    return pattern.finditer(content)
+
+
+def tokens(content: str, language: str = "") -> Iterator[tuple[str, str]]:
+    """("operator" | "operand", text) for every counted token of *content*."""
+    for match in token_matches(content, language):
+        kind = match.lastgroup
+        text = match.group()
+        if kind in ("string", "number"):
+            yield "operand", text
+        elif kind == "word":
+            yield ("operator" if text in KEYWORDS else "operand"), text
+        elif kind == "operator":
+            yield "operator", text
+
+
+def halstead(content: str, language: str = "") -> Halstead:
+    """Halstead counts of a piece of code."""
+    operators: Counter[str] = Counter()
+    operands: Counter[str] = Counter()
+    for kind, text in tokens(content, language):
+        (operators if kind == "operator" else operands)[text] += 1
+    return Halstead(
+        distinct_operators=len(operators),
+        distinct_operands=len(operands),
+        operators=sum(operators.values()),
+        operands=sum(operands.values()),
+    )
+
+
+def function_halstead(syntax: FileSyntax, content: str) -> list[tuple[FunctionDef, Halstead]]:
+    """Halstead counts of every function of a file, in source order."""
+    lines = content.splitlines()
+    return [
+        (fn, halstead("\n".join(lines[fn.start_line - 1 : fn.end_line]), syntax.language))
+        for fn in sorted(syntax.functions, key=lambda f: f.start_line)
+    ]
@@ -0,0 +1,260 @@
+"""Information density: how much a codebase says per byte, per line and per name.
+
+Shannon measured a message by what is left once its redundancy is squeezed
+out. The same questions, asked of a whole codebase:
+
+    compression_ratio      zlib-compressed size / raw size, files compressed
+                           one at a time. Source code typically lands at
+                           0.20-0.45 (see math/compression.py); below, it
+                           repeats itself; above, it is dense (generated
+                           tables, minified code, embedded data)
+    cross_file_redundancy  share of the per-file compressed size that goes
+                           away when the files are compressed as one stream
+                           (LZMA, whose window spans the corpus): boilerplate
+                           and copies repeated *between* files, which
+                           per-file compression cannot see
+    vocabulary growth      distinct identifiers against identifiers read,
+                           files taken in path order. Heaps' law V = K * N^b
+                           fits most corpora; b is fitted in log-log space.
+                           Near 1, every file brings names of its own; the
+                           lower, the more the code reuses one vocabulary
+                           (English prose sits around 0.4-0.6)
+
+Per package (directory), information_density is compressed bits per
+non-blank line, how much a reader takes in per line, reported next to the
+package's files, lines and compression ratio, with the package's own
+vocabulary exponent. Snapshots record the codebase measures and each
+package's information_density and vocabulary_exponent.
+"""
+
+from __future__ import annotations
+
+import lzma
+import math
+import zlib
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Optional
+
+from ..hygiene.sources import SourceSet
+from .token_entropy import identifiers
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+# Calibration bands of Compression.compression_ratio for source code
+REPETITIVE_RATIO = 0.20
+DENSE_RATIO = 0.45
+
+# Files past this many bytes (in path order) are left out of cross_file_redundancy
+MAX_CORPUS_BYTES = 8 * 1024 * 1024
+
+# Checkpoints of the vocabulary growth curve, log-spaced
+CURVE_POINTS = 20
+
+# Identifiers read before a checkpoint is used in the Heaps' law fit, and
+# before a package gets an exponent at all
+MIN_FIT_TOKENS = 100
+MIN_EXPONENT_TOKENS = 1000
+
+_LZMA_PRESET = 6
+
+
+@dataclass
+class PackageDensity:
+    """Size, compressed size and vocabulary of one package."""
+
+    package: str
+    files: int
+    lines: int  # non-blank
+    raw_bytes: int
+    compressed_bytes: int
+    identifiers: int
+    distinct_identifiers: int
+    vocabulary_exponent: Optional[float]
+
+    @property
+    def compression_ratio(self) -> float:
+        return self.compressed_bytes / self.raw_bytes if self.raw_bytes else 0.0
+
+    @property
+    def information_density(self) -> float:
+        """Compressed bits per non-blank line."""
+        return 8 * self.compressed_bytes / self.lines if self.lines else 0.0
+
+    def to_dict(self) -> dict:
+        return {
+            "package": self.package,
+            "files": self.files,
+            "lines": self.lines,
+            "compression_ratio": round(self.compression_ratio, 4),
+            "information_density": round(self.information_density, 2),
+            "identifiers": self.identifiers,
+            "distinct_identifiers": self.distinct_identifiers,
+            "vocabulary_exponent": _round(self.vocabulary_exponent),
+        }
+
+
+@dataclass
+class DensityReport:
+    """Codebase-wide information density, with a row per package.
+
+    Attributes:
+        separate_bytes: LZMA size of the files compressed one at a time
+        corpus_bytes: LZMA size of the same files as one stream
+        curve: (identifiers read, distinct identifiers) at each checkpoint
+        packages: Every package, densest first
+    """
+
+    files: int = 0
+    lines: int = 0
+    raw_bytes: int = 0
+    compressed_bytes: int = 0
+    separate_bytes: int = 0
+    corpus_bytes: int = 0
+    curve: list[tuple[int, int]] = field(default_factory=list)
+    vocabulary_exponent: Optional[float] = None
+    packages: list[PackageDensity] = field(default_factory=list)
+
+    @property
+    def compression_ratio(self) -> float:
+        return self.compressed_bytes / self.raw_bytes if self.raw_bytes else 0.0
+
+    @property
+    def band(self) -> str:
+        """Where compression_ratio falls against typical source code."""
+        if self.compression_ratio < REPETITIVE_RATIO:
+            return "repetitive"
+        return "dense" if self.compression_ratio > DENSE_RATIO else "typical"
+
+    @property
+    def cross_file_redundancy(self) -> float:
+        if not self.separate_bytes:
+            return 0.0
+        return max(0.0, 1 - self.corpus_bytes / self.separate_bytes)
+
+    @property
+    def information_density(self) -> float:
+        """Compressed bits per non-blank line."""
+        return 8 * self.compressed_bytes / self.lines if self.lines else 0.0
+
+    def signals(self) -> tuple[dict[str, float], dict[str, dict[str, float]]]:
+        """(global, per-package) signals for snapshots."""
+        global_signals: dict[str, float] = {}
+        if self.raw_bytes:
+            global_signals["corpus_compression_ratio"] = self.compression_ratio
+            global_signals["cross_file_redundancy"] = self.cross_file_redundancy
+            global_signals["information_density"] = self.information_density
+        if self.vocabulary_exponent is not None:
+            global_signals["vocabulary_exponent"] = self.vocabulary_exponent
+        package_signals: dict[str, dict[str, float]] = {}
+        for p in self.packages:
+            signals = {"information_density": p.information_density}
+            if p.vocabulary_exponent is not None:
+                signals["vocabulary_exponent"] = p.vocabulary_exponent
+            package_signals[p.package] = signals
+        return global_signals, package_signals
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "files": self.files,
+            "lines": self.lines,
+            "raw_bytes": self.raw_bytes,
+            "compression_ratio": round(self.compression_ratio, 4),
+            "band": self.band,
+            "cross_file_redundancy": round(self.cross_file_redundancy, 4),
+            "information_density": round(self.information_density, 2),
+            "vocabulary_exponent": _round(self.vocabulary_exponent),
+            "vocabulary_curve": [{"identifiers": n, "distinct": v} for n, v in self.curve],
+            "packages": [p.to_dict() for p in self.packages[:top]],
+        }
+
+
+def vocabulary_curve(names: list[str], points: int = CURVE_POINTS) -> list[tuple[int, int]]:
+    """(identifiers read, distinct so far) at *points* log-spaced checkpoints of *names*."""
+    total = len(names)
+    if not total:
+        return []
+    marks = sorted({max(1, round(total ** (k / points))) for k in range(1, points + 1)})
+    curve = []
+    seen: set[str] = set()
+    for read, name in enumerate(names, 1):
+        seen.add(name)
+        if read == marks[len(curve)]:
+            curve.append((read, len(seen)))
+    return curve
+
+
+def heaps_exponent(curve: list[tuple[int, int]]) -> Optional[float]:
+    """Least-squares slope of log(distinct) on log(read); None below three usable points."""
+    points = [(math.log(n), math.log(v)) for n, v in curve if n >= MIN_FIT_TOKENS]
+    if len(points) < 3:
+        return None
+    mean_x = sum(x for x, _ in points) / len(points)
+    mean_y = sum(y for _, y in points) / len(points)
+    spread = sum((x - mean_x) ** 2 for x, _ in points)
+    return sum((x - mean_x) * (y - mean_y) for x, y in points) / spread
+
+
+def _lzma_size(data: bytes) -> int:
+    """Raw LZMA2 stream size, without container headers that would swamp small files."""
+    filters = [{"id": lzma.FILTER_LZMA2, "preset": _LZMA_PRESET, "dict_size": max(4096, len(data))}]
+    return len(lzma.compress(data, format=lzma.FORMAT_RAW, filters=filters))
+
+
+def _exponent(names: list[str]) -> Optional[float]:
+    if len(names) < MIN_EXPONENT_TOKENS:
+        return None
+    return heaps_exponent(vocabulary_curve(names))
+
+
+def _round(value: Optional[float]) -> Optional[float]:
+    return round(value, 4) if value is not None else None
+
+
+def analyze_density(syntax: dict[str, FileSyntax], contents: dict[str, str]) -> DensityReport:
+    """Information density of the files of *syntax*, read from *contents*."""
+    report = DensityReport()
+    corpus: list[bytes] = []
+    corpus_size = 0
+    names: list[str] = []
+    groups: dict[str, list[tuple[int, int, int, list[str]]]] = {}
+    for path in sorted(syntax):
+        content = contents.get(path) or ""
+        data = content.encode("utf-8")
+        if not data:
+            continue
+        lines = sum(1 for line in content.splitlines() if line.strip())
+        compressed = len(zlib.compress(data, 9))
+        file_names = list(identifiers(content, syntax[path].language))
+        report.files += 1
+        report.lines += lines
+        report.raw_bytes += len(data)
+        report.compressed_bytes += compressed
+        names.extend(file_names)
+        package = SourceSet.package_of(path)
+        groups.setdefault(package, []).append((lines, len(data), compressed, file_names))
+        if corpus_size + len(data) <= MAX_CORPUS_BYTES:
+            corpus.append(data)
+            corpus_size += len(data)
+            report.separate_bytes += _lzma_size(data)
+    if corpus:
+        report.corpus_bytes = _lzma_size(b"".join(corpus))
+    report.curve = vocabulary_curve(names)
+    report.vocabulary_exponent = _exponent(names)
+
+    for package, files in groups.items():
+        package_names = [name for *_, file_names in files for name in file_names]
+        report.packages.append(
+            PackageDensity(
+                package=package,
+                files=len(files),
+                lines=sum(f[0] for f in files),
+                raw_bytes=sum(f[1] for f in files),
+                compressed_bytes=sum(f[2] for f in files),
+                identifiers=len(package_names),
+                distinct_identifiers=len(set(package_names)),
+                vocabulary_exponent=_exponent(package_names),
+            )
+        )
+    report.packages.sort(key=lambda p: (-p.information_density, p.package))
+    return report
@@ -0,0 +1,102 @@
+"""Maintainability Index: one 0-100 score from size, complexity and comments.
+
+The index of Oman & Hagemeister (1992), in the form with a comment term
+used by the SEI and radon, rescaled to 0-100 as Visual Studio does:
+
+    MI = max(0, (171 - a * ln(V) - b * G - c * ln(LOC)
+                 + d * sin(sqrt(2.4 * radians(CM)))) * 100 / 171)
+
+    V    Halstead volume of the file (halstead_volume)
+    G    cyclomatic complexity of the file: 1 + its decision points
+    LOC  lines of code, blank and comment lines left out
+    CM   comment lines, as a percentage of non-blank lines
+
+The weights a, b, c, d default to the published 5.2, 0.23, 16.2 and 50,
+and are set with ``maintainability_weights`` in the config file (a table
+with ``volume``, ``complexity``, ``lines`` and ``comments`` keys, any of
+which may be left out). Higher is better: above 20 is usually read as
+maintainable, below 10 as hard to maintain.
+
+A package's index (module_maintainability) is the mean of its files'
+weighted by file length, so a large unmaintainable file drags the package
+down more than a small one.
+"""
+
+from __future__ import annotations
+
+import math
+from typing import Any, Optional
+
+from .complexity import DECISION_RE, is_comment_line
+
+DEFAULT_WEIGHTS = {"volume": 5.2, "complexity": 0.23, "lines": 16.2, "comments": 50.0}
+
+
+def resolve_weights(table: Optional[dict[str, Any]] = None) -> dict[str, float]:
+    """The default weights, overridden by the ``maintainability_weights`` table.
+
+    Raises ValueError for unknown keys and negative or non-numeric weights.
+    """
+    weights = dict(DEFAULT_WEIGHTS)
+    for key, value in (table or {}).items():
+        if key not in DEFAULT_WEIGHTS:
+            known = ", ".join(DEFAULT_WEIGHTS)
+            raise ValueError(f"maintainability_weights: unknown weight {key!r} (known: {known})")
+        if isinstance(value, bool) or not isinstance(value, (int, float)) or value < 0:
+            raise ValueError(f"maintainability_weights: {key} must be a non-negative number")
+        weights[key] = float(value)
+    return weights
+
+
+def maintainability_index(
+    volume: float,
+    complexity: float,
+    lines: int,
+    comment_ratio: float,
+    weights: Optional[dict[str, float]] = None,
+) -> float:
+    """Maintainability Index, 0-100, of code with these measurements."""
+    if lines <= 0:
+        return 100.0
+    w = weights or DEFAULT_WEIGHTS
+    raw = (
+        171
+        - w["volume"] * math.log(max(volume, 1.0))
+        - w["complexity"] * complexity
+        - w["lines"] * math.log(lines)
+        + w["comments"] * math.sin(math.sqrt(2.4 * math.radians(100 * comment_ratio)))
+    )
+    return min(100.0, max(0.0, raw * 100 / 171))
+
+
+def file_measurements(content: str) -> tuple[int, int, float]:
+    """(cyclomatic complexity, lines of code, comment ratio) of a file's text."""
+    code = 0
+    comments = 0
+    decisions = 0
+    for line in content.splitlines():
+        if not line.strip():
+            continue
+        if is_comment_line(line):
+            comments += 1
+        else:
+            code += 1
+            decisions += len(DECISION_RE.findall(line))
+    total = code + comments
+    return 1 + decisions, code, comments / total if total else 0.0
+
+
+def file_maintainability(
+    content: str, volume: float, weights: Optional[dict[str, float]] = None
+) -> float:
+    """Maintainability Index of a file, given its Halstead volume."""
+    complexity, lines, comment_ratio = file_measurements(content)
+    return maintainability_index(volume, complexity, lines, comment_ratio, weights)
+
+
+def package_maintainability(files: list[tuple[float, int]]) -> float:
+    """Length-weighted mean of (index, lines) pairs of files; 0 without lines."""
+    total = sum(lines for _, lines in files)
+    if total == 0:
+        return 0.0
+    return sum(index * lines for index, lines in files) / total
@@ -0,0 +1,527 @@
+"""Mobile resource debt: duplicated strings, unused resources, oversized files.
+
+Android resources (res/, AndroidManifest.xml) and iOS resources (.strings
+tables, asset catalogs, storyboards and xibs, property lists) are read
+with scanning/resources.py and cross-referenced with the code and with
+each other.
+
+Three kinds of finding come out of it:
+
+    duplicate_string_resource   one text defined under two or more names
+                                in the default (untranslated) strings of a
+                                platform: it is translated twice, and the
+                                copies drift apart
+    unused_resource             a resource nothing refers to: no
+                                R.<type>.<name> or @<type>/<name> on
+                                Android; no string literal, generated asset
+                                member, storyboard or plist reference on iOS
+    oversized_resource          a resource file of OVERSIZED_BYTES or more,
+                                or a storyboard of MAX_SCENES scenes or more
+
+Resources looked up by a computed name (getIdentifier or UIImage(named:)
+with a variable) cannot be seen: when the code of a platform does that,
+its unused_resource findings carry confidence 0.5. Android styles are
+never reported unused, since they inherit from each other by name, and
+neither are iOS tables that localize a storyboard or Info.plist.
+"""
+
+from __future__ import annotations
+
+import hashlib
+import math
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import Callable, Iterable, Optional
+
+from ..scanning.resources import (
+    ResourceDecl,
+    android_dynamic_names,
+    android_file_resource,
+    android_references,
+    android_resource_type,
+    asset_member,
+    asset_set,
+    interface_builder_references,
+    interface_builder_scenes,
+    ios_code_references,
+    ios_dynamic_lookup,
+    ios_locale,
+    parse_android_values,
+    parse_strings_file,
+    plist_values,
+)
+
+DUPLICATE_STRING_TYPE = "duplicate_string_resource"
+UNUSED_RESOURCE_TYPE = "unused_resource"
+OVERSIZED_RESOURCE_TYPE = "oversized_resource"
+
+# An image this large is worth compressing or converting (WebP, vector)
+OVERSIZED_BYTES = 256 * 1024
+
+# Storyboards this large are slow to open and conflict on every merge
+MAX_SCENES = 15
+
+# Android types reported unused; styles inherit by name, ids are declared inline
+UNUSED_ANDROID_TYPES = frozenset(
+    {
+        "anim",
+        "animator",
+        "array",
+        "bool",
+        "color",
+        "dimen",
+        "drawable",
+        "font",
+        "integer",
+        "layout",
+        "menu",
+        "mipmap",
+        "navigation",
+        "plurals",
+        "raw",
+        "string",
+        "xml",
+    }
+)
+
+_ANDROID_CODE = (".java", ".kt", ".kts")
+_IOS_CODE = (".swift", ".m", ".mm", ".h")
+_INTERFACE_BUILDER = (".storyboard", ".xib")
+
+# .strings tables that localize something other than code
+_NON_CODE_TABLES = frozenset({"InfoPlist"})
+
+_IMAGES = (".png", ".jpg", ".jpeg", ".webp", ".gif", ".heic", ".pdf", ".svg")
+_PLATFORM = {"android": "Android", "ios": "iOS"}
+
+
+@dataclass
+class ResourceFile:
+    path: str
+    platform: str  # "android" or "ios"
+    kind: str  # "values", "drawable", ..., "manifest", "strings", "asset", "storyboard", "plist"
+    size: int  # bytes
+    resources: int = 0  # declared in this file
+    scenes: int = 0  # storyboards only
+
+    def to_dict(self) -> dict:
+        return {
+            "path": self.path,
+            "platform": self.platform,
+            "kind": self.kind,
+            "size": self.size,
+            "resources": self.resources,
+            "scenes": self.scenes,
+        }
+
+
+@dataclass(frozen=True)
+class DuplicateString:
+    platform: str
+    value: str
+    resources: tuple[ResourceDecl, ...]  # one per name, by path and line
+
+    @property
+    def files(self) -> list[str]:
+        return list(dict.fromkeys(r.path for r in self.resources))
+
+    @property
+    def digest(self) -> str:
+        return hashlib.sha1(self.value.encode()).hexdigest()[:12]
+
+    @property
+    def severity(self) -> float:
+        return min(0.5, 0.3 + 0.05 * (len(self.resources) - 2))
+
+
+@dataclass(frozen=True)
+class UnusedResource:
+    resource: ResourceDecl
+    variants: int  # declarations: translations and qualified copies included
+    dynamic_lookups: bool  # the platform's code looks resources up by computed names
+
+    @property
+    def is_entry(self) -> bool:
+        """Declared inside a values file or .strings table, rather than being a file."""
+        return (
+            self.resource.type == "localized_string"
+            or android_resource_type(self.resource.path) == "values"
+        )
+
+    @property
+    def severity(self) -> float:
+        # Files (images, layouts) weigh on the app's size; entries barely do
+        return 0.25 if self.is_entry else 0.3
+
+
+@dataclass(frozen=True)
+class OversizedResource:
+    file: ResourceFile
+
+    @property
+    def too_large(self) -> bool:
+        return self.file.size >= OVERSIZED_BYTES
+
+    @property
+    def too_many_scenes(self) -> bool:
+        return self.file.scenes >= MAX_SCENES
+
+    @property
+    def severity(self) -> float:
+        size = self.file.size / OVERSIZED_BYTES
+        scenes = self.file.scenes / MAX_SCENES
+        return min(0.6, 0.3 + 0.1 * math.log2(max(size, scenes, 1.0)))
+
+
+@dataclass
+class MobileReport:
+    """Resource files and what is wrong with them.
+
+    Attributes:
+        files: Every resource file, largest first
+        resources: Every declared resource, by path and line
+        duplicates: Texts defined under several names, most names first
+        unused: Resources nothing refers to, by platform, type and name
+        oversized: Resource files over a size or scene limit, worst first
+    """
+
+    files: list[ResourceFile] = field(default_factory=list)
+    resources: list[ResourceDecl] = field(default_factory=list)
+    duplicates: list[DuplicateString] = field(default_factory=list)
+    unused: list[UnusedResource] = field(default_factory=list)
+    oversized: list[OversizedResource] = field(default_factory=list)
+
+    def platforms(self) -> dict[str, int]:
+        """Resource files per platform."""
+        counts: dict[str, int] = {}
+        for file in self.files:
+            counts[file.platform] = counts.get(file.platform, 0) + 1
+        return counts
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "file_count": len(self.files),
+            "resource_count": len(self.resources),
+            "platforms": self.platforms(),
+            "files": [f.to_dict() for f in self.files[:top]],
+            "duplicates": [
+                {
+                    "platform": d.platform,
+                    "value": d.value,
+                    "resources": [
+                        {"name": r.name, "path": r.path, "line": r.line} for r in d.resources
+                    ],
+                }
+                for d in self.duplicates[:top]
+            ],
+            "unused": [
+                {
+                    "platform": u.resource.platform,
+                    "type": u.resource.type,
+                    "name": u.resource.name,
+                    "path": u.resource.path,
+                    "line": u.resource.line,
+                    "variants": u.variants,
+                    "dynamic_lookups": u.dynamic_lookups,
+                }
+                for u in self.unused[:top]
+            ],
+            "oversized": [
+                {"path": o.file.path, "size": o.file.size, "scenes": o.file.scenes}
+                for o in self.oversized[:top]
+            ],
+        }
+
+
+def resource_kind(path: str) -> Optional[tuple[str, str]]:
+    """(platform, kind) of a resource file, None for other files."""
+    pure = PurePosixPath(path)
+    resource_type = android_resource_type(path)
+    if resource_type is not None:
+        return "android", resource_type
+    if pure.name == "AndroidManifest.xml":
+        return "android", "manifest"
+    if pure.suffix == ".strings" and ios_locale(path) is not None:
+        return "ios", "strings"
+    if any(part.endswith(".xcassets") for part in pure.parts[:-1]):
+        return "ios", "asset"
+    if pure.suffix in _INTERFACE_BUILDER:
+        return "ios", pure.suffix[1:]
+    if pure.suffix == ".plist":
+        return "ios", "plist"
+    return None
+
+
+def collect_mobile_resources(
+    root: Path, paths: Iterable[str], code: dict[str, str]
+) -> MobileReport:
+    """Read the resource files among *paths* and check them against *code*.
+
+    Args:
+        root: Directory the paths are relative to
+        paths: Candidate files; those that are not resource files are ignored
+        code: Text of the source files, by path
+    """
+    report = MobileReport()
+    android_texts = [text for path, text in code.items() if path.endswith(_ANDROID_CODE)]
+    ios_texts = [text for path, text in code.items() if path.endswith(_IOS_CODE)]
+    ios_references: set[str] = set()
+    interface_builder_stems = set()
+
+    for path in sorted(paths):
+        kind = resource_kind(path)
+        if kind is None:
+            continue
+        platform, file_kind = kind
+        try:
+            size = (root / path).stat().st_size
+            data = (root / path).read_bytes() if _is_text(path) else b""
+        except OSError:
+            continue
+        file = ResourceFile(path, platform, file_kind, size)
+        text = _decode(data)
+        declared: list[ResourceDecl] = []
+        if file_kind == "values" and path.endswith(".xml"):
+            declared = parse_android_values(path, text)
+        elif platform == "android" and file_kind != "manifest":
+            decl = android_file_resource(path)
+            declared = [decl] if decl is not None else []
+        elif file_kind == "strings":
+            declared = parse_strings_file(path, text)
+        elif file_kind == "asset":
+            decl = asset_set(path)
+            declared = [decl] if decl is not None else []
+        elif file_kind in ("storyboard", "xib"):
+            file.scenes = interface_builder_scenes(text)
+            ios_references |= interface_builder_references(text)
+            interface_builder_stems.add(PurePosixPath(path).stem)
+        elif file_kind == "plist":
+            keys, values = plist_values(data)
+            file.resources = keys
+            ios_references |= values
+        if platform == "android" and path.endswith(".xml"):
+            android_texts.append(text)
+        if declared:
+            file.resources = len(declared)
+            report.resources.extend(declared)
+        report.files.append(file)
+
+    for text in ios_texts:
+        ios_references |= ios_code_references(text)
+    localized_by_code = [
+        r
+        for r in report.resources
+        if r.type != "localized_string"
+        or PurePosixPath(r.path).stem not in interface_builder_stems | _NON_CODE_TABLES
+    ]
+    report.files.sort(key=lambda f: (-f.size, f.path))
+    report.duplicates = _duplicates(localized_by_code)
+    report.unused = _unused_android(
+        [r for r in localized_by_code if r.platform == "android"], android_texts
+    ) + _unused_ios(
+        [r for r in localized_by_code if r.platform == "ios"],
+        ios_references,
+        dynamic=any(ios_dynamic_lookup(text) for text in ios_texts),
+    )
+    report.oversized = sorted(
+        (
+            OversizedResource(f)
+            for f in report.files
+            if f.size >= OVERSIZED_BYTES or f.scenes >= MAX_SCENES
+        ),
+        key=lambda o: (-o.severity, o.file.path),
+    )
+    return report
+
+
+def _duplicates(resources: list[ResourceDecl]) -> list[DuplicateString]:
+    groups: dict[tuple[str, str], dict[str, ResourceDecl]] = {}
+    for resource in resources:
+        value = resource.value
+        if (
+            resource.type not in ("string", "localized_string")
+            or not resource.default_locale
+            or value is None
+            or value.startswith("@")
+            or not any(c.isalpha() for c in value)
+        ):
+            continue
+        groups.setdefault((resource.platform, value), {}).setdefault(resource.name, resource)
+    duplicates = [
+        DuplicateString(
+            platform, value, tuple(sorted(named.values(), key=lambda r: (r.path, r.line)))
+        )
+        for (platform, value), named in groups.items()
+        if len(named) > 1
+    ]
+    return sorted(duplicates, key=lambda d: (-len(d.resources), d.platform, d.value))
+
+
+def _unused_android(resources: list[ResourceDecl], texts: list[str]) -> list[UnusedResource]:
+    referenced: set[tuple[str, str]] = set()
+    looked_up: set[str] = set()
+    dynamic = False
+    for text in texts:
+        referenced |= android_references(text)
+        names, computed = android_dynamic_names(text)
+        looked_up |= names
+        dynamic = dynamic or computed
+    return _unused(
+        [r for r in resources if r.type in UNUSED_ANDROID_TYPES],
+        lambda r: (r.type, r.name) in referenced or r.name in looked_up,
+        dynamic,
+    )
+
+
+def _unused_ios(
+    resources: list[ResourceDecl], references: set[str], dynamic: bool
+) -> list[UnusedResource]:
+    return _unused(
+        resources,
+        lambda r: r.name in references or asset_member(r.name) in references,
+        dynamic,
+    )
+
+
+def _unused(
+    resources: list[ResourceDecl], used: Callable[[ResourceDecl], bool], dynamic: bool
+) -> list[UnusedResource]:
+    """One entry per unused (type, name), at its default declaration."""
+    by_key: dict[tuple[str, str], list[ResourceDecl]] = {}
+    for resource in resources:
+        by_key.setdefault((resource.type, resource.name), []).append(resource)
+    unused = []
+    for key in sorted(by_key):
+        variants = sorted(by_key[key], key=lambda r: (not r.default_locale, r.path, r.line))
+        if not used(variants[0]):
+            unused.append(UnusedResource(variants[0], len(variants), dynamic))
+    return unused
+
+
+def _is_text(path: str) -> bool:
+    """Whether the resource file is read: XML, tables, manifests; not images."""
+    suffix = PurePosixPath(path).suffix
+    return suffix in (".xml", ".strings", ".json", ".plist", *_INTERFACE_BUILDER)
+
+
+def _decode(data: bytes) -> str:
+    """.strings tables are often UTF-16; everything else is UTF-8."""
+    if data.startswith((b"\xff\xfe", b"\xfe\xff")):
+        return data.decode("utf-16", errors="replace")
+    return data.decode("utf-8", errors="replace")
+
+
+def to_findings(report: MobileReport) -> list:
+    """Convert duplicated strings, unused resources and oversized files to findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for duplicate in report.duplicates:
+        names = ", ".join(r.name for r in duplicate.resources)
+        findings.append(
+            Finding(
+                finding_type=DUPLICATE_STRING_TYPE,
+                severity=duplicate.severity,
+                title=(
+                    f'"{_shorten(duplicate.value)}" is defined as {len(duplicate.resources)} '
+                    f"string resources: {names}"
+                ),
+                files=duplicate.files,
+                evidence=[
+                    Evidence(
+                        signal="names",
+                        value=float(len(duplicate.resources)),
+                        percentile=0.0,
+                        description=", ".join(
+                            f"{r.name} at {r.path}:{r.line}" for r in duplicate.resources
+                        ),
+                    ),
+                ],
+                suggestion=(
+                    "Keep one string and refer to it everywhere, so it is translated once"
+                ),
+                effort="LOW",
+                identity_hint=f"{duplicate.platform}:{duplicate.digest}",
+            )
+        )
+    for item in report.unused:
+        resource = item.resource
+        where = f"{resource.path}:{resource.line}" if item.is_entry else resource.path
+        evidence = [
+            Evidence(signal="declared", value=0.0, percentile=0.0, description=where),
+        ]
+        if item.variants > 1:
+            evidence.append(
+                Evidence(
+                    signal="variants",
+                    value=float(item.variants),
+                    percentile=0.0,
+                    description=f"{item.variants} translations or qualified copies",
+                )
+            )
+        if item.dynamic_lookups:
+            evidence.append(
+                Evidence(
+                    signal="dynamic_lookups",
+                    value=1.0,
+                    percentile=0.0,
+                    description="the code also looks resources up by computed names",
+                )
+            )
+        findings.append(
+            Finding(
+                finding_type=UNUSED_RESOURCE_TYPE,
+                severity=item.severity,
+                title=f"{_PLATFORM[resource.platform]} {resource.key} is never referenced",
+                files=[resource.path],
+                evidence=evidence,
+                suggestion="Delete the resource, with its translations and qualified copies",
+                confidence=0.5 if item.dynamic_lookups else 1.0,
+                effort="LOW",
+                identity_hint=f"{resource.platform}:{resource.key}",
+            )
+        )
+    for oversized in report.oversized:
+        file = oversized.file
+        evidence = [
+            Evidence(
+                signal="size",
+                value=float(file.size),
+                percentile=0.0,
+                description=f"{file.size // 1024} KiB",
+            ),
+        ]
+        if file.kind in ("storyboard", "xib"):
+            evidence.append(
+                Evidence(
+                    signal="scenes",
+                    value=float(file.scenes),
+                    percentile=0.0,
+                    description=f"{file.scenes} scenes",
+                )
+            )
+        if oversized.too_many_scenes:
+            title = f"Storyboard {file.path} has {file.scenes} scenes"
+            suggestion = "Split it into storyboards per flow, linked by storyboard references"
+        elif file.path.lower().endswith(_IMAGES):
+            title = f"Image resource {file.path} is {file.size // 1024} KiB"
+            suggestion = "Compress it, convert it to WebP or HEIC, or use a vector drawable"
+        else:
+            title = f"Resource file {file.path} is {file.size // 1024} KiB"
+            suggestion = "Split it, or download it at run time instead of bundling it"
+        findings.append(
+            Finding(
+                finding_type=OVERSIZED_RESOURCE_TYPE,
+                severity=oversized.severity,
+                title=title,
+                files=[file.path],
+                evidence=evidence,
+                suggestion=suggestion,
+                effort="MEDIUM",
+            )
+        )
+    return findings
+
+
+def _shorten(text: str, limit: int = 40) -> str:
+    return text if len(text) <= limit else text[: limit - 3] + "..."
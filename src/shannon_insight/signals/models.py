@@ -57,8 +57,16 @@ class FileSignals:
     broken_call_count: int = 0  # 0 until CALL edges exist
     community: int = -1
     compression_ratio: float = 0.0
+    token_entropy: float = 0.0  # see signals/token_entropy.py
     semantic_coherence: float = 0.0  # import-based coherence
     cognitive_load: float = 0.0
+    halstead_volume: float = 0.0  # N * log2(n), see signals/halstead.py
+    halstead_difficulty: float = 0.0
+    halstead_effort: float = 0.0
+    maintainability_index: float = 0.0  # 0-100, see signals/maintainability.py
+    comment_density: float = 0.0  # comment lines per code line, see signals/comments.py
+    commented_out_lines: int = 0
+    undocumented_complexity: int = 0  # decision points of functions without a doc comment
 
     # IR5t (temporal) - signals #27-34
     total_changes: int = 0
@@ -112,6 +120,7 @@ class ModuleSignals:
 
     # Aggregated file signals - signals #49-50
     mean_cognitive_load: float = 0.0
+    module_maintainability: float = 0.0  # lines-weighted mean maintainability_index
     file_count: int = 0
 
     # Composite - signal #51
@@ -175,6 +184,7 @@ class GlobalSignals:
 
     # Phase 3/4 derived signals (needed for composites)
     clone_ratio: float = 0.0  # From Phase 3 clone detection
+    duplication_ratio: float = 0.0  # Share of lines in token-based clones (Phase 3)
     violation_rate: float = 0.0  # From Phase 4 architecture
     conway_alignment: float = 1.0  # From Phase 3 author distances (1.0 = perfect alignment)
     team_size: int = 1  # From Phase 3 git history
@@ -0,0 +1,141 @@
+"""Nesting depth of functions, maximum and average, apart from complexity.
+
+Cyclomatic complexity counts decision points but not how they are stacked:
+ten ifs one after another score the same as ten ifs inside each other,
+though only the second makes a reader hold ten conditions at once. So
+nesting is measured on its own, per function:
+
+    max_nesting  the deepest nesting inside the function, as the parser
+                 reports it (FunctionDef.nesting_depth)
+    avg_nesting  the mean nesting level of the function's code lines,
+                 inferred from indentation (see complexity.line_levels) and
+                 capped at max_nesting
+
+A function with one deep corner has a high maximum and a low average; a
+function that is deep throughout has both high. Functions nested deeper
+than ``nesting_threshold`` (4 by default) are reported as ``deep_nesting``
+findings, with the first line at the deepest level.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from typing import TYPE_CHECKING, Optional
+
+from .complexity import line_levels
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+
+DEEP_NESTING_TYPE = "deep_nesting"
+
+# Default for AnalysisConfig.nesting_threshold
+NESTING_THRESHOLD = 4
+
+
+@dataclass(frozen=True)
+class FunctionNesting:
+    path: str
+    name: str
+    line: int
+    max_nesting: int
+    avg_nesting: float
+    deepest_line: int  # first line at the deepest indentation
+    cell: Optional[int] = None  # notebook cell the function starts in
+
+    @property
+    def location(self) -> str:
+        if self.cell is not None:
+            return f"{self.path} cell {self.cell}"
+        return f"{self.path}:{self.line}"
+
+    def severity(self, threshold: int) -> float:
+        return min(0.7, 0.3 + 0.1 * (self.max_nesting - threshold))
+
+
+def function_nesting(fn: FunctionDef, lines: list[str]) -> tuple[float, int]:
+    """Average nesting level of *fn*'s body and its first deepest line."""
+    block = lines[fn.start_line - 1 : fn.end_line]
+    body = [(i, level) for i, level in line_levels(block) if i > 0]
+    if not body:
+        return 0.0, fn.start_line
+    average = min(sum(level for _, level in body) / len(body), float(fn.nesting_depth))
+    deepest = max(body, key=lambda entry: (entry[1], -entry[0]))[0]
+    return average, fn.start_line + deepest
+
+
+def collect_nesting(
+    files: dict[str, FileSyntax], contents: dict[str, str]
+) -> list[FunctionNesting]:
+    """Maximum and average nesting of every function with a known line span."""
+    measured = []
+    for path, syntax in files.items():
+        lines = contents.get(path, "").splitlines()
+        for fn in syntax.functions:
+            # Regex-parsed spans may end a line past the file; the slice allows it
+            if fn.end_line < fn.start_line or fn.start_line > len(lines):
+                continue
+            average, deepest = function_nesting(fn, lines)
+            measured.append(
+                FunctionNesting(
+                    path=path,
+                    name=fn.name,
+                    line=fn.start_line,
+                    max_nesting=fn.nesting_depth,
+                    avg_nesting=average,
+                    deepest_line=deepest,
+                    cell=fn.cell,
+                )
+            )
+    return measured
+
+
+def find_deep_nesting(
+    functions: list[FunctionNesting], threshold: int = NESTING_THRESHOLD
+) -> list[FunctionNesting]:
+    """Functions nested deeper than *threshold*, deepest first."""
+    deep = [fn for fn in functions if fn.max_nesting > threshold]
+    return sorted(deep, key=lambda fn: (-fn.max_nesting, -fn.avg_nesting, fn.path, fn.line))
+
+
+def to_findings(functions: list[FunctionNesting], threshold: int = NESTING_THRESHOLD) -> list:
+    """Convert deeply nested functions to ``deep_nesting`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for fn in functions:
+        findings.append(
+            Finding(
+                finding_type=DEEP_NESTING_TYPE,
+                severity=fn.severity(threshold),
+                title=(
+                    f"{fn.name} at {fn.location} nests {fn.max_nesting} levels deep "
+                    f"(threshold {threshold})"
+                ),
+                files=[fn.path],
+                evidence=[
+                    Evidence(
+                        signal="max_nesting",
+                        value=float(fn.max_nesting),
+                        percentile=0.0,
+                        description=(
+                            f"deepest block {fn.max_nesting} levels in, "
+                            f"from line {fn.deepest_line}"
+                        ),
+                    ),
+                    Evidence(
+                        signal="avg_nesting",
+                        value=round(fn.avg_nesting, 2),
+                        percentile=0.0,
+                        description=f"{fn.avg_nesting:.1f} levels on average per line",
+                    ),
+                ],
+                suggestion=(
+                    f"Flatten {fn.name} to {threshold} levels or fewer: return early from "
+                    "guard conditions or extract the inner loops into functions"
+                ),
+                effort="MEDIUM",
+                identity_hint=fn.name,
+            )
+        )
+    return findings
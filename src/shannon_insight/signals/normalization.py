@@ -40,8 +40,16 @@ NORMALIZABLE_SIGNALS = [
     # "depth" excluded: registry says percentileable=False
     "phantom_import_count",
     "compression_ratio",
+    "token_entropy",
     "semantic_coherence",
     "cognitive_load",
+    "halstead_volume",
+    "halstead_difficulty",
+    "halstead_effort",
+    "maintainability_index",
+    "comment_density",
+    "commented_out_lines",
+    "undocumented_complexity",
     "total_changes",
     "churn_slope",
     "churn_cv",
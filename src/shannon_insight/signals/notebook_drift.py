@@ -0,0 +1,239 @@
+"""Functions copied between notebooks and modules that have since diverged.
+
+Data-science code often starts in a notebook and is pasted into a module
+(or the other way round) once it works. Both copies then live on, and a
+fix made to one rarely reaches the other. A notebook function and a module
+function are copies of one another when they share a name (methods:
+``Class.method``) and their bodies are at least MIN_SIMILARITY alike;
+identical copies are not drift and are left alone.
+
+Bodies are compared line by line with blank and comment lines dropped and
+indentation stripped, so reformatting is not drift. The canonical copy is
+the one whose file was committed last, when git history says so, and the
+module copy otherwise: modules are what production imports.
+"""
+
+from __future__ import annotations
+
+import difflib
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Optional
+
+from ..scanning.notebook import is_notebook
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+
+DRIFT_TYPE = "notebook_drift"
+
+# Share of matching lines at which two same-named functions are copies
+MIN_SIMILARITY = 0.6
+
+# Functions with fewer code lines are too small to call copies
+MIN_LINES = 3
+
+# Lines of the drift diff kept in a finding
+MAX_DIFF_LINES = 12
+
+
+@dataclass
+class FunctionCopy:
+    """One copy of a function, with its code lines normalized."""
+
+    path: str
+    name: str
+    start_line: int
+    end_line: int
+    cell: Optional[int] = None
+    lines: list[str] = field(default_factory=list)
+
+    @property
+    def location(self) -> str:
+        if self.cell is not None:
+            return f"{self.path} cell {self.cell}"
+        return f"{self.path}:{self.start_line}"
+
+
+@dataclass
+class NotebookDrift:
+    """A notebook function and its diverged copy in a module.
+
+    Attributes:
+        notebook: The notebook copy
+        module: The module copy
+        similarity: Share of matching lines, 0..1
+        diff: Unified diff from the module copy to the notebook copy
+        changed_lines: Lines added or removed between the copies
+        canonical: "notebook" or "module", the copy to keep
+        newer: "notebook" or "module" when git history dates both files, else None
+    """
+
+    notebook: FunctionCopy
+    module: FunctionCopy
+    similarity: float
+    diff: list[str]
+    changed_lines: int
+    canonical: str = "module"
+    newer: Optional[str] = None
+
+    @property
+    def severity(self) -> float:
+        return min(0.6, 0.3 + 0.02 * self.changed_lines)
+
+
+def find_notebook_drift(
+    files: dict[str, FileSyntax],
+    contents: dict[str, str],
+    last_changed: Optional[dict[str, int]] = None,
+) -> list[NotebookDrift]:
+    """Diverged copies of notebook functions in Python modules, most drifted first.
+
+    *last_changed* maps paths to the unix time of their last commit.
+    """
+    last_changed = last_changed or {}
+    notebook_copies: dict[str, list[FunctionCopy]] = {}
+    module_copies: dict[str, list[FunctionCopy]] = {}
+    for path, syntax in sorted(files.items()):
+        if is_notebook(path):
+            target = notebook_copies
+        elif syntax.language == "python":
+            target = module_copies
+        else:
+            continue
+        lines = contents.get(path, "").splitlines()
+        for name, fn in _functions(syntax):
+            copy = _copy(path, name, fn, lines)
+            if len(copy.lines) >= MIN_LINES:
+                target.setdefault(name, []).append(copy)
+
+    drifts = []
+    for name, notebook_list in sorted(notebook_copies.items()):
+        for notebook in notebook_list:
+            best = None
+            for module in module_copies.get(name, []):
+                ratio = difflib.SequenceMatcher(None, module.lines, notebook.lines).ratio()
+                if best is None or ratio > best[0]:
+                    best = (ratio, module)
+            if best is None or best[0] < MIN_SIMILARITY or best[1].lines == notebook.lines:
+                continue
+            drifts.append(_drift(notebook, best[1], best[0], last_changed))
+    drifts.sort(key=lambda d: (-d.changed_lines, d.notebook.path, d.notebook.name))
+    return drifts
+
+
+def _functions(syntax: FileSyntax) -> list[tuple[str, FunctionDef]]:
+    """(name, function) of the functions and methods of a file, dunders left out."""
+    named = [(fn.name, fn) for fn in syntax.functions]
+    for cls in syntax.classes:
+        named.extend((f"{cls.name}.{fn.name}", fn) for fn in cls.methods)
+    return [(name, fn) for name, fn in named if not fn.name.startswith("__")]
+
+
+def _copy(path: str, name: str, fn: FunctionDef, lines: list[str]) -> FunctionCopy:
+    code = []
+    for line in lines[fn.start_line - 1 : fn.end_line]:
+        stripped = line.strip()
+        if stripped and not stripped.startswith("#"):
+            code.append(stripped)
+    return FunctionCopy(
+        path=path,
+        name=name,
+        start_line=fn.start_line,
+        end_line=fn.end_line,
+        cell=fn.cell,
+        lines=code,
+    )
+
+
+def _drift(
+    notebook: FunctionCopy, module: FunctionCopy, similarity: float, last_changed: dict[str, int]
+) -> NotebookDrift:
+    diff = list(
+        difflib.unified_diff(
+            module.lines, notebook.lines, module.location, notebook.location, n=0, lineterm=""
+        )
+    )
+    changed = sum(
+        1 for line in diff if line[:1] in "+-" and not line.startswith(("+++", "---"))
+    )
+    newer = None
+    notebook_time = last_changed.get(notebook.path)
+    module_time = last_changed.get(module.path)
+    if notebook_time is not None and module_time is not None and notebook_time != module_time:
+        newer = "notebook" if notebook_time > module_time else "module"
+    return NotebookDrift(
+        notebook=notebook,
+        module=module,
+        similarity=similarity,
+        diff=diff[:MAX_DIFF_LINES],
+        changed_lines=changed,
+        canonical=newer or "module",
+        newer=newer,
+    )
+
+
+def to_findings(drifts: list[NotebookDrift]) -> list:
+    """Convert diverged copies to ``notebook_drift`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for drift in drifts:
+        notebook, module = drift.notebook, drift.module
+        evidence = [
+            Evidence(
+                signal="similarity_ratio",
+                value=drift.similarity,
+                percentile=0.0,
+                description=f"{drift.similarity:.0%} of lines match",
+            ),
+            Evidence(
+                signal="changed_count",
+                value=float(drift.changed_lines),
+                percentile=0.0,
+                description=f"{drift.changed_lines} lines differ",
+            ),
+        ]
+        if drift.newer is not None:
+            newer = notebook if drift.newer == "notebook" else module
+            evidence.append(
+                Evidence(
+                    signal="newer",
+                    value=1.0 if drift.newer == "notebook" else 0.0,
+                    percentile=0.0,
+                    description=f"{newer.path} was committed last",
+                )
+            )
+        evidence.append(
+            Evidence(
+                signal="diff",
+                value=float(len(drift.diff)),
+                percentile=0.0,
+                description="\n".join(drift.diff),
+            )
+        )
+        if drift.canonical == "module":
+            suggestion = (
+                f"Import {module.name} from {module.path} in the notebook "
+                "instead of keeping a copy"
+            )
+        else:
+            suggestion = (
+                f"Port the notebook's changes to {module.path}, "
+                "then import it in the notebook"
+            )
+        findings.append(
+            Finding(
+                finding_type=DRIFT_TYPE,
+                severity=drift.severity,
+                title=(
+                    f"{notebook.name} in {notebook.location} has drifted from "
+                    f"{module.location} ({drift.similarity:.0%} similar)"
+                ),
+                files=[notebook.path, module.path],
+                evidence=evidence,
+                suggestion=suggestion,
+                effort="LOW",
+                identity_hint=notebook.name,
+            )
+        )
+    return findings
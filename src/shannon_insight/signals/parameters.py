@@ -0,0 +1,216 @@
+"""Parameter counts, long parameter lists and repeated parameter groups.
+
+Every function's parameters are counted without its receiver (``self``,
+``cls``, ``this``) or variadic collectors (``*args``, ``**kwargs``, ``...``),
+which do not lengthen a call. Two smells are reported from the counts:
+
+    long_parameter_list  more than ``parameter_threshold`` (5) parameters:
+                         calculateMetric(a, b, c, d, e, f int) is hard to
+                         call right, and usually does several things
+    data_clump           the same MIN_CLUMP_SIZE (3) or more parameter names
+                         taken by MIN_CLUMP_FUNCTIONS (3) or more differently
+                         named functions: values that always travel together
+                         are a struct or value object that was never written
+
+A clump is the largest group its functions share, so (start, end, step) in
+three functions is one clump, not three pairs. Same-named functions (the
+implementations of one interface method) count once, since their shared
+parameters are imposed, not chosen.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass
+from itertools import combinations
+from typing import TYPE_CHECKING
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+LONG_PARAMETER_LIST_TYPE = "long_parameter_list"
+DATA_CLUMP_TYPE = "data_clump"
+
+# Default for AnalysisConfig.parameter_threshold
+PARAMETER_THRESHOLD = 5
+
+# Parameters shared, by differently named functions, that make a clump
+MIN_CLUMP_SIZE = 3
+MIN_CLUMP_FUNCTIONS = 3
+
+# Parameters of one function looked at for clumps (C(12, 3) = 220 groups)
+MAX_CLUMP_PARAMS = 12
+
+# Functions listed in a data_clump finding
+MAX_LISTED = 5
+
+_RECEIVERS = frozenset({"self", "cls", "this"})
+
+
+@dataclass(frozen=True)
+class FunctionParams:
+    path: str
+    name: str
+    line: int
+    params: tuple[str, ...]
+
+    @property
+    def count(self) -> int:
+        return len(self.params)
+
+    @property
+    def location(self) -> str:
+        return f"{self.path}:{self.line}"
+
+
+@dataclass(frozen=True)
+class DataClump:
+    """Parameters that several functions take together."""
+
+    params: tuple[str, ...]
+    functions: tuple[FunctionParams, ...]
+
+    @property
+    def files(self) -> list[str]:
+        return list(dict.fromkeys(fn.path for fn in self.functions))
+
+
+def parameter_names(raw: list[str]) -> tuple[str, ...]:
+    """Parameter names as written, without receivers, defaults, types or collectors."""
+    names = []
+    for param in raw:
+        param = param.strip()
+        if not param or param.startswith(("*", "...")) or param.endswith("..."):
+            continue
+        name = param.split("=")[0].split(":")[0].strip().lstrip("&$").strip()
+        if name and name not in _RECEIVERS:
+            names.append(name)
+    return tuple(names)
+
+
+def collect_parameters(files: dict[str, FileSyntax]) -> list[FunctionParams]:
+    """Parameters of every function, in file and line order."""
+    functions = [
+        FunctionParams(path, fn.name, fn.start_line, parameter_names(fn.params))
+        for path, syntax in files.items()
+        for fn in syntax.functions
+    ]
+    return sorted(functions, key=lambda fn: (fn.path, fn.line, fn.name))
+
+
+def find_long_parameter_lists(
+    functions: list[FunctionParams], threshold: int = PARAMETER_THRESHOLD
+) -> list[FunctionParams]:
+    """Functions taking more than *threshold* parameters, most first."""
+    found = [fn for fn in functions if fn.count > threshold]
+    return sorted(found, key=lambda fn: (-fn.count, fn.path, fn.line))
+
+
+def find_data_clumps(
+    functions: list[FunctionParams],
+    min_size: int = MIN_CLUMP_SIZE,
+    min_functions: int = MIN_CLUMP_FUNCTIONS,
+) -> list[DataClump]:
+    """Largest parameter groups shared by *min_functions* differently named functions."""
+    candidates = [fn for fn in functions if min_size <= fn.count <= MAX_CLUMP_PARAMS]
+    takers: dict[frozenset[str], list[FunctionParams]] = {}
+    for fn in candidates:
+        for group in combinations(sorted(set(fn.params)), min_size):
+            takers.setdefault(frozenset(group), []).append(fn)
+
+    # Grow each frequent group to everything its functions have in common
+    clumps: dict[frozenset[str], list[FunctionParams]] = {}
+    for sharing in takers.values():
+        if len({fn.name for fn in sharing}) < min_functions:
+            continue
+        common = frozenset.intersection(*(frozenset(fn.params) for fn in sharing))
+        if common not in clumps:
+            clumps[common] = [fn for fn in candidates if common <= set(fn.params)]
+
+    ranked = sorted(
+        clumps.items(),
+        key=lambda item: (-len(item[0]) * len(item[1]), -len(item[0]), sorted(item[0])),
+    )
+    found: list[DataClump] = []
+    for params, sharing in ranked:
+        if any(params < set(kept.params) for kept in found):
+            continue
+        # Keep the order the first function declares them in
+        first = sharing[0].params
+        ordered = tuple(sorted(params, key=first.index))
+        found.append(DataClump(ordered, tuple(sharing)))
+    return found
+
+
+def to_findings(
+    long_lists: list[FunctionParams],
+    clumps: list[DataClump],
+    threshold: int = PARAMETER_THRESHOLD,
+) -> list:
+    """Convert long parameter lists and data clumps to findings of their own types."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for fn in long_lists:
+        findings.append(
+            Finding(
+                finding_type=LONG_PARAMETER_LIST_TYPE,
+                severity=min(0.6, 0.3 + 0.1 * (fn.count - threshold)),
+                title=(
+                    f"{fn.name} at {fn.location} takes {fn.count} parameters "
+                    f"(threshold {threshold})"
+                ),
+                files=[fn.path],
+                evidence=[
+                    Evidence(
+                        signal="parameter_count",
+                        value=float(fn.count),
+                        percentile=0.0,
+                        description=", ".join(fn.params),
+                    )
+                ],
+                suggestion=(
+                    f"Group related parameters of {fn.name} into a struct or value object, "
+                    "or split it into functions that each need fewer"
+                ),
+                effort="MEDIUM",
+                identity_hint=fn.name,
+            )
+        )
+    for clump in clumps:
+        names = ", ".join(clump.params)
+        evidence = [
+            Evidence(
+                signal="clump_size",
+                value=float(len(clump.params)),
+                percentile=0.0,
+                description=f"{len(clump.params)} parameters: {names}",
+            ),
+            Evidence(
+                signal="functions",
+                value=float(len(clump.functions)),
+                percentile=0.0,
+                description=f"taken together by {len(clump.functions)} functions",
+            ),
+        ]
+        for fn in clump.functions[:MAX_LISTED]:
+            evidence.append(
+                Evidence(
+                    signal="function",
+                    value=float(fn.count),
+                    percentile=0.0,
+                    description=f"{fn.name} at {fn.location}",
+                )
+            )
+        findings.append(
+            Finding(
+                finding_type=DATA_CLUMP_TYPE,
+                severity=min(0.6, 0.3 + 0.05 * len(clump.params) * len(clump.functions) / 3),
+                title=f"({names}) are passed together to {len(clump.functions)} functions",
+                files=clump.files,
+                evidence=evidence,
+                suggestion=f"Introduce a struct or value object holding {names} and pass that",
+                effort="MEDIUM",
+                identity_hint=",".join(sorted(clump.params)),
+            )
+        )
+    return findings
@@ -0,0 +1,166 @@
+"""SQL statement complexity, long stored procedures and table usage.
+
+Every statement of every SQL file is measured with
+scanning.sql.statement_complexity: 1 + joins, subqueries, set operations,
+CASE branches, AND/OR and IF/loop branches. Stored procedures and
+functions (CREATE FUNCTION/PROCEDURE/TRIGGER) are measured as the
+statement that creates them, so a PL/pgSQL body counts as a whole.
+
+A procedure of LONG_PROCEDURE_LINES or more is reported as a
+``long_procedure`` finding: logic that size is rarely tested, and in a
+database it is also rarely reviewed or versioned as carefully as code.
+"""
+
+from __future__ import annotations
+
+import math
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Optional
+
+from ..scanning.sql import split_statements
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+LONG_PROCEDURE_TYPE = "long_procedure"
+
+# Procedures at least this long are reported
+LONG_PROCEDURE_LINES = 200
+
+
+@dataclass(frozen=True)
+class StatementSample:
+    path: str
+    line: int
+    lines: int
+    keyword: str  # SELECT, INSERT, CREATE...
+    complexity: int
+
+    @property
+    def label(self) -> str:
+        return f"{self.path}:{self.line} {self.keyword}"
+
+
+@dataclass(frozen=True)
+class ProcedureSample:
+    path: str
+    name: str
+    line: int
+    lines: int
+    complexity: int
+
+    @property
+    def severity(self) -> float:
+        return min(0.8, 0.4 + 0.1 * math.log2(self.lines / LONG_PROCEDURE_LINES))
+
+
+@dataclass
+class SqlReport:
+    """Statements, procedures and table references of the SQL files.
+
+    Attributes:
+        statements: Every statement, most complex first
+        procedures: Functions, procedures and triggers, longest first
+        tables: Table -> SQL files referencing it, most referenced first
+    """
+
+    statements: list[StatementSample] = field(default_factory=list)
+    procedures: list[ProcedureSample] = field(default_factory=list)
+    tables: dict[str, list[str]] = field(default_factory=dict)
+
+    @property
+    def files(self) -> int:
+        return len({s.path for s in self.statements})
+
+    def long_procedures(self, min_lines: int = LONG_PROCEDURE_LINES) -> list[ProcedureSample]:
+        return [p for p in self.procedures if p.lines >= min_lines]
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "files": self.files,
+            "statement_count": len(self.statements),
+            "statements": [s.__dict__ for s in self.statements[:top]],
+            "procedures": [p.__dict__ for p in self.procedures[:top]],
+            "long_procedures": [p.__dict__ for p in self.long_procedures()],
+            "tables": {
+                table: paths for table, paths in list(self.tables.items())[:top]
+            },
+        }
+
+
+def collect_sql(files: dict[str, FileSyntax], contents: dict[str, str]) -> SqlReport:
+    """Measure every statement and procedure of the SQL files in *files*."""
+    report = SqlReport()
+    tables: dict[str, list[str]] = {}
+    for path, syntax in sorted(files.items()):
+        if syntax.language != "sql":
+            continue
+        statements = split_statements(contents.get(path, ""))
+        for statement in statements:
+            report.statements.append(
+                StatementSample(
+                    path=path,
+                    line=statement.start_line,
+                    lines=statement.end_line - statement.start_line + 1,
+                    keyword=statement.keyword,
+                    complexity=statement.complexity,
+                )
+            )
+        for fn in syntax.functions:
+            # The routine is the statement its definition starts
+            statement = next(
+                (s for s in statements if s.start_line <= fn.start_line <= s.end_line), None
+            )
+            report.procedures.append(
+                ProcedureSample(
+                    path=path,
+                    name=fn.name,
+                    line=fn.start_line,
+                    lines=fn.end_line - fn.start_line + 1,
+                    complexity=statement.complexity if statement else 1,
+                )
+            )
+        for imp in syntax.imports:
+            tables.setdefault(imp.source, []).append(path)
+
+    report.statements.sort(key=lambda s: (-s.complexity, s.path, s.line))
+    report.procedures.sort(key=lambda p: (-p.lines, p.path, p.line))
+    report.tables = dict(sorted(tables.items(), key=lambda item: (-len(item[1]), item[0])))
+    return report
+
+
+def to_findings(procedures: list[ProcedureSample]) -> list:
+    """Convert long procedures to ``long_procedure`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for procedure in procedures:
+        findings.append(
+            Finding(
+                finding_type=LONG_PROCEDURE_TYPE,
+                severity=procedure.severity,
+                title=(
+                    f"{procedure.name} at {procedure.path}:{procedure.line} "
+                    f"is {procedure.lines} lines of SQL"
+                ),
+                files=[procedure.path],
+                evidence=[
+                    Evidence(
+                        signal="lines",
+                        value=float(procedure.lines),
+                        percentile=0.0,
+                        description=f"{procedure.lines} lines",
+                    ),
+                    Evidence(
+                        signal="statement_complexity",
+                        value=float(procedure.complexity),
+                        percentile=0.0,
+                        description=f"statement complexity {procedure.complexity}",
+                    ),
+                ],
+                suggestion="Split into smaller routines, or move the logic into tested code",
+                effort="HIGH",
+                identity_hint=procedure.name,
+            )
+        )
+    return findings
@@ -0,0 +1,295 @@
+"""Terraform modules: resources, variables and nested dynamic blocks.
+
+A module is a directory of ``.tf`` files (scanning/hcl.py parses them).
+For each one the report counts resources (per type), data sources, input
+variables, outputs and module calls.
+
+Two kinds of finding come out of it:
+
+    nested_dynamic_block    a ``dynamic`` block inside the ``content`` of
+                            another, DEEP_DYNAMIC_DEPTH or more levels deep:
+                            generated configuration nobody can read back
+    variable_count_outlier  a module declaring far more input variables
+                            than the other modules of the repo, by the same
+                            modified z-score (MAD) test AnalysisEngine uses
+                            for file outliers such as a file with many imports
+"""
+
+from __future__ import annotations
+
+import math
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Optional
+
+from ..math.robust import OUTLIER_Z
+from ..scanning.hcl import HclBlock, parse_hcl
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+NESTED_DYNAMIC_TYPE = "nested_dynamic_block"
+VARIABLE_OUTLIER_TYPE = "variable_count_outlier"
+
+# Dynamic blocks nested at least this deep are reported
+DEEP_DYNAMIC_DEPTH = 2
+
+# Too few modules give no meaningful notion of "typical"
+MIN_MODULES = 5
+
+# Below this, a module is never worth reporting however unusual it is
+MIN_OUTLIER_VARIABLES = 10
+
+
+@dataclass
+class TerraformModule:
+    path: str  # directory, "" for the root module
+    files: list[str] = field(default_factory=list)
+    resource_types: dict[str, int] = field(default_factory=dict)
+    data_sources: int = 0
+    variables: list[str] = field(default_factory=list)
+    variable_files: list[str] = field(default_factory=list)
+    outputs: int = 0
+    module_calls: int = 0
+    max_dynamic_depth: int = 0
+
+    @property
+    def label(self) -> str:
+        return self.path or "."
+
+    @property
+    def resources(self) -> int:
+        return sum(self.resource_types.values())
+
+    def to_dict(self) -> dict:
+        return {
+            "path": self.label,
+            "files": self.files,
+            "resources": self.resources,
+            "resource_types": self.resource_types,
+            "data_sources": self.data_sources,
+            "variables": len(self.variables),
+            "outputs": self.outputs,
+            "module_calls": self.module_calls,
+            "max_dynamic_depth": self.max_dynamic_depth,
+        }
+
+
+@dataclass(frozen=True)
+class DynamicBlockSample:
+    path: str
+    line: int  # of the innermost dynamic block
+    block: str  # the resource (or other top-level block) holding it
+    chain: tuple[str, ...]  # dynamic block names, outermost first
+    depth: int
+
+    @property
+    def severity(self) -> float:
+        return min(0.7, 0.4 + 0.1 * (self.depth - DEEP_DYNAMIC_DEPTH))
+
+
+@dataclass(frozen=True)
+class VariableOutlier:
+    module: TerraformModule
+    typical_variables: float  # median over all modules
+    modified_z: float
+
+    @property
+    def severity(self) -> float:
+        ratio = len(self.module.variables) / max(self.typical_variables, 1.0)
+        return min(0.7, 0.4 + 0.1 * math.log2(ratio))
+
+
+@dataclass
+class TerraformReport:
+    """Modules, deeply nested dynamic blocks and variable-count outliers.
+
+    Attributes:
+        modules: Every module, most resources first
+        dynamic_blocks: Dynamic blocks DEEP_DYNAMIC_DEPTH or more deep, deepest first
+        variable_outliers: Modules with anomalous variable counts, worst first
+    """
+
+    modules: list[TerraformModule] = field(default_factory=list)
+    dynamic_blocks: list[DynamicBlockSample] = field(default_factory=list)
+    variable_outliers: list[VariableOutlier] = field(default_factory=list)
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "module_count": len(self.modules),
+            "resources": sum(m.resources for m in self.modules),
+            "modules": [m.to_dict() for m in self.modules[:top]],
+            "nested_dynamic_blocks": [
+                {**d.__dict__, "chain": list(d.chain)} for d in self.dynamic_blocks[:top]
+            ],
+            "variable_outliers": [
+                {
+                    "module": o.module.label,
+                    "variables": len(o.module.variables),
+                    "typical_variables": o.typical_variables,
+                    "modified_z": round(o.modified_z, 2),
+                }
+                for o in self.variable_outliers
+            ],
+        }
+
+
+def collect_terraform(files: dict[str, FileSyntax], contents: dict[str, str]) -> TerraformReport:
+    """Group the HCL files in *files* into modules and measure them."""
+    modules: dict[str, TerraformModule] = {}
+    dynamic_blocks: list[DynamicBlockSample] = []
+    for path, syntax in sorted(files.items()):
+        if syntax.language != "hcl":
+            continue
+        directory = path.rpartition("/")[0]
+        module = modules.setdefault(directory, TerraformModule(directory))
+        module.files.append(path)
+        for block in parse_hcl(contents.get(path, "")):
+            _count(module, path, block)
+            chain = _deepest_dynamic_chain(block)
+            module.max_dynamic_depth = max(module.max_dynamic_depth, len(chain))
+            if len(chain) >= DEEP_DYNAMIC_DEPTH:
+                dynamic_blocks.append(
+                    DynamicBlockSample(
+                        path=path,
+                        line=chain[-1].start_line,
+                        block=block.name or block.type,
+                        chain=tuple(d.name for d in chain),
+                        depth=len(chain),
+                    )
+                )
+
+    report = TerraformReport(
+        modules=sorted(modules.values(), key=lambda m: (-m.resources, m.path)),
+        dynamic_blocks=sorted(dynamic_blocks, key=lambda d: (-d.depth, d.path, d.line)),
+    )
+    report.variable_outliers = find_variable_outliers(report.modules)
+    return report
+
+
+def _count(module: TerraformModule, path: str, block: HclBlock) -> None:
+    if block.type == "resource" and block.labels:
+        kind = block.labels[0]
+        module.resource_types[kind] = module.resource_types.get(kind, 0) + 1
+    elif block.type == "data":
+        module.data_sources += 1
+    elif block.type == "variable" and block.labels:
+        module.variables.append(block.labels[0])
+        if path not in module.variable_files:
+            module.variable_files.append(path)
+    elif block.type == "output":
+        module.outputs += 1
+    elif block.type == "module":
+        module.module_calls += 1
+
+
+def _deepest_dynamic_chain(block: HclBlock) -> list[HclBlock]:
+    """The longest run of dynamic blocks nested in one another, outermost first."""
+    best: list[HclBlock] = []
+    for child in block.blocks:
+        chain = _deepest_dynamic_chain(child)
+        if child.type == "dynamic":
+            chain = [child, *chain]
+        if len(chain) > len(best):
+            best = chain
+    return best
+
+
+def _median(values: list[float]) -> float:
+    values = sorted(values)
+    mid = len(values) // 2
+    return values[mid] if len(values) % 2 else (values[mid - 1] + values[mid]) / 2
+
+
+def find_variable_outliers(
+    modules: list[TerraformModule], threshold: float = OUTLIER_Z
+) -> list[VariableOutlier]:
+    """Modules declaring far more variables than the typical module, worst first."""
+    if len(modules) < MIN_MODULES:
+        return []
+    counts = [float(len(m.variables)) for m in modules]
+    typical = _median(counts)
+    # MAD of 0 is common (many small modules); 1 keeps the score finite
+    mad = max(_median([abs(c - typical) for c in counts]), 1.0)
+    outliers = []
+    for module, count in zip(modules, counts):
+        z = 0.6745 * (count - typical) / mad
+        if z > threshold and count >= MIN_OUTLIER_VARIABLES:
+            outliers.append(VariableOutlier(module, typical, z))
+    return sorted(outliers, key=lambda o: (-o.modified_z, o.module.path))
+
+
+def to_findings(report: TerraformReport) -> list:
+    """Convert nested dynamic blocks and variable outliers to findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for sample in report.dynamic_blocks:
+        findings.append(
+            Finding(
+                finding_type=NESTED_DYNAMIC_TYPE,
+                severity=sample.severity,
+                title=(
+                    f"{sample.block} at {sample.path}:{sample.line} nests dynamic blocks "
+                    f"{sample.depth} deep"
+                ),
+                files=[sample.path],
+                evidence=[
+                    Evidence(
+                        signal="dynamic_depth",
+                        value=float(sample.depth),
+                        percentile=0.0,
+                        description=f"dynamic {' > '.join(sample.chain)}",
+                    ),
+                ],
+                suggestion=(
+                    "Flatten the input (flatten(), setproduct()) so one dynamic block "
+                    "iterates over it, or split the resource"
+                ),
+                effort="MEDIUM",
+                identity_hint=f"{sample.block}:{'.'.join(sample.chain)}",
+            )
+        )
+    for outlier in report.variable_outliers:
+        module = outlier.module
+        count = len(module.variables)
+        findings.append(
+            Finding(
+                finding_type=VARIABLE_OUTLIER_TYPE,
+                severity=outlier.severity,
+                title=(
+                    f"Module {module.label} declares {count} variables, "
+                    f"typical is {outlier.typical_variables:g}"
+                ),
+                files=module.variable_files,
+                evidence=[
+                    Evidence(
+                        signal="variables",
+                        value=float(count),
+                        percentile=0.0,
+                        description=f"{count} variables",
+                    ),
+                    Evidence(
+                        signal="typical_variables",
+                        value=outlier.typical_variables,
+                        percentile=0.0,
+                        description=(
+                            f"modules of this repo typically declare "
+                            f"{outlier.typical_variables:g} (modified z {outlier.modified_z:.1f})"
+                        ),
+                    ),
+                    Evidence(
+                        signal="resources",
+                        value=float(module.resources),
+                        percentile=0.0,
+                        description=f"{module.resources} resources",
+                    ),
+                ],
+                suggestion=(
+                    "Split the module by concern, or group related settings into "
+                    "object-typed variables with defaults"
+                ),
+                effort="HIGH",
+                identity_hint=module.label,
+            )
+        )
+    return findings
@@ -0,0 +1,174 @@
+"""Per-type size and complexity, and the god classes they reveal.
+
+A type's weighted method count (WMC) is the summed cyclomatic complexity
+of its methods. A god class has both many methods and a high WMC: it
+centralizes behavior that belongs in several types (Lanza & Marinescu,
+"Object-Oriented Metrics in Practice", use WMC >= 47).
+
+Swift splits types across ``extension`` blocks, usually one per protocol
+conformance and often one per file (``FeedViewController+DataSource.swift``).
+Counted separately, no block looks large, so each extension is merged
+into the declaration of the same name; with several declarations of that
+name, the one sharing the most directories with the extension wins.
+Extensions of types declared outside the codebase (``extension String``)
+belong to no type and are not counted.
+"""
+
+from __future__ import annotations
+
+import math
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING
+
+from .function_outliers import function_complexity
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import ClassDef, FileSyntax
+
+GOD_CLASS_TYPE = "god_class"
+
+# Weighted method count at which a type is a god class
+GOD_CLASS_WMC = 47
+
+# ...provided it also has this many methods (one huge method is an outlier, not a god class)
+GOD_CLASS_METHODS = 15
+
+
+@dataclass
+class TypeSample:
+    """One type, its extensions merged in.
+
+    Attributes:
+        name: Type name
+        path: File of the declaration
+        files: The declaration's file, then its extensions' files
+        methods: Number of methods, extensions included
+        lines: Summed line spans of the methods
+        complexity: Weighted method count (summed cyclomatic complexity)
+        extensions: Number of extension blocks merged in
+    """
+
+    name: str
+    path: str
+    files: list[str] = field(default_factory=list)
+    methods: int = 0
+    lines: int = 0
+    complexity: int = 0
+    extensions: int = 0
+
+    @property
+    def severity(self) -> float:
+        return min(0.8, 0.5 + 0.1 * math.log2(self.complexity / GOD_CLASS_WMC))
+
+
+def collect_types(
+    files: dict[str, FileSyntax], contents: dict[str, str]
+) -> list[TypeSample]:
+    """Size and complexity of every declared type, extensions merged in."""
+    samples: list[TypeSample] = []
+    declared: dict[str, list[TypeSample]] = {}
+    extensions: list[tuple[str, ClassDef]] = []
+    for path, syntax in sorted(files.items()):
+        lines = contents.get(path, "").splitlines()
+        for cls in syntax.classes:
+            if cls.is_extension:
+                extensions.append((path, cls))
+                continue
+            sample = TypeSample(name=cls.name, path=path, files=[path])
+            _add_methods(sample, cls, lines)
+            samples.append(sample)
+            declared.setdefault(cls.name, []).append(sample)
+
+    for path, cls in extensions:
+        candidates = declared.get(cls.name)
+        if not candidates:
+            continue
+        sample = max(candidates, key=lambda s: (_shared_directories(s.path, path), -len(s.path)))
+        _add_methods(sample, cls, contents.get(path, "").splitlines())
+        sample.extensions += 1
+        if path not in sample.files:
+            sample.files.append(path)
+    return samples
+
+
+def find_god_classes(
+    samples: list[TypeSample],
+    wmc: int = GOD_CLASS_WMC,
+    min_methods: int = GOD_CLASS_METHODS,
+) -> list[TypeSample]:
+    """Types with many methods and a high weighted method count, worst first."""
+    found = [s for s in samples if s.complexity >= wmc and s.methods >= min_methods]
+    return sorted(found, key=lambda s: (-s.complexity, s.path, s.name))
+
+
+def to_findings(god_classes: list[TypeSample]) -> list:
+    """Convert god classes to ``god_class`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for sample in god_classes:
+        evidence = [
+            Evidence(
+                signal="weighted_method_count",
+                value=float(sample.complexity),
+                percentile=0.0,
+                description=f"summed method complexity {sample.complexity}",
+            ),
+            Evidence(
+                signal="methods",
+                value=float(sample.methods),
+                percentile=0.0,
+                description=f"{sample.methods} methods",
+            ),
+            Evidence(
+                signal="lines",
+                value=float(sample.lines),
+                percentile=0.0,
+                description=f"{sample.lines} lines of methods",
+            ),
+        ]
+        if sample.extensions:
+            evidence.append(
+                Evidence(
+                    signal="extensions",
+                    value=float(sample.extensions),
+                    percentile=0.0,
+                    description=(
+                        f"{sample.extensions} extensions across {len(sample.files)} files"
+                    ),
+                )
+            )
+        findings.append(
+            Finding(
+                finding_type=GOD_CLASS_TYPE,
+                severity=sample.severity,
+                title=(
+                    f"{sample.name} has {sample.methods} methods "
+                    f"with summed complexity {sample.complexity}"
+                ),
+                files=list(sample.files),
+                evidence=evidence,
+                suggestion="Split responsibilities into smaller, focused types",
+                effort="HIGH",
+                identity_hint=sample.name,
+            )
+        )
+    return findings
+
+
+def _add_methods(sample: TypeSample, cls: ClassDef, lines: list[str]) -> None:
+    for fn in cls.methods:
+        sample.methods += 1
+        if fn.start_line <= fn.end_line <= len(lines):
+            sample.lines += fn.end_line - fn.start_line + 1
+            sample.complexity += function_complexity(lines[fn.start_line - 1 : fn.end_line])
+
+
+def _shared_directories(path: str, other: str) -> int:
+    """Number of leading directories two paths have in common."""
+    count = 0
+    for a, b in zip(path.split("/")[:-1], other.split("/")[:-1]):
+        if a != b:
+            break
+        count += 1
+    return count
@@ -0,0 +1,229 @@
+"""Vocabulary drift: one concept spelled several ways in the same directory.
+
+``user_id`` in one function, ``userId`` in the next and ``uid`` in a third
+all name the same thing; a reader has to learn that they do. Identifiers
+of variables and functions (those starting lower-case, leading underscores
+ignored) are grouped into concepts by their words, split camelCase and
+snake_case aware and mapped through the glossary's abbreviations and
+British spellings, so ``user_id``, ``userId`` and ``usr_id`` share the
+concept (user, id). A single-word name that is the compact form of a
+concept used nearby joins it too: ``userid``, or ``uid`` (initials of the
+leading words, then the last word) when at least MIN_COMPACT_LENGTH long.
+Comments, strings and docstrings are skipped.
+
+Capitalised names are left out: ``User``, ``UserId`` and ``USER_ID`` are
+types and constants, told apart from variables by convention, not drift.
+So are single-word concepts, which have only one spelling.
+
+A directory's drift is the Shannon entropy of the spellings of each
+concept, weighted by how often the concept is used:
+
+    vocabulary_drift = sum(uses(c) * H(spellings of c)) / sum(uses(c))
+
+over its multi-word concepts. 0 means every concept has one spelling;
+one concept used evenly in two spellings among as many uses of consistent
+concepts gives 0.5. Directories at MIN_DRIFT or above are reported.
+"""
+
+from __future__ import annotations
+
+import re
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import PurePosixPath
+from typing import TYPE_CHECKING
+
+from ..hygiene.glossary import STRING_RE, canonical_term
+from ..hygiene.naming import split_words
+from ..math.entropy import Entropy
+from .complexity import is_comment_line
+from .halstead import KEYWORDS
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+DRIFT_TYPE = "vocabulary_drift"
+
+# Weighted spelling entropy (bits) at which a directory is reported
+MIN_DRIFT = 0.05
+
+# Concepts listed in a finding
+MAX_CONCEPTS = 5
+
+# Keys and columns follow the schema they describe, not the code's naming
+DATA_LANGUAGES = frozenset({"hcl", "proto", "sql", "yaml"})
+
+# Compact forms shorter than this (fa, of) are as likely words of their own
+MIN_COMPACT_LENGTH = 3
+
+_IDENTIFIER = re.compile(r"\b[A-Za-z_][A-Za-z0-9_]*")
+# Docstrings and other multi-line strings are prose, not identifiers
+_TRIPLE_QUOTED = re.compile(r'"""[\s\S]*?"""|\'\'\'[\s\S]*?\'\'\'')
+
+
+@dataclass
+class Concept:
+    """One concept of a directory and the spellings it is used under."""
+
+    words: tuple[str, ...]
+    spellings: Counter[str] = field(default_factory=Counter)
+    first_use: dict[str, str] = field(default_factory=dict)  # spelling -> "path:line"
+    paths: dict[str, set[str]] = field(default_factory=dict)  # spelling -> files using it
+
+    @property
+    def uses(self) -> int:
+        return sum(self.spellings.values())
+
+    @property
+    def entropy(self) -> float:
+        return Entropy.shannon(self.spellings)
+
+    @property
+    def preferred(self) -> str:
+        """Most used spelling; on a tie the longest, the most spelled out."""
+        return self._ranked()[0]
+
+    def others(self) -> list[str]:
+        """The other spellings, most used first."""
+        return self._ranked()[1:]
+
+    def _ranked(self) -> list[str]:
+        return sorted(self.spellings, key=lambda s: (-self.spellings[s], -len(s), s))
+
+
+@dataclass
+class DirectoryVocabulary:
+    """Concepts of one directory spelled more than one way."""
+
+    directory: str
+    drift: float
+    uses: int  # uses of multi-word concepts in the directory
+    concepts: list[Concept]  # with several spellings, most used first
+
+    @property
+    def files(self) -> list[str]:
+        """Files using a spelling other than the preferred one, sorted."""
+        paths = {
+            path
+            for concept in self.concepts
+            for spelling in concept.others()
+            for path in concept.paths[spelling]
+        }
+        return sorted(paths)
+
+    @property
+    def severity(self) -> float:
+        return min(0.5, 0.2 + self.drift / 2)
+
+
+def find_vocabulary_drift(
+    files: dict[str, FileSyntax], contents: dict[str, str]
+) -> list[DirectoryVocabulary]:
+    """Directories whose concepts are spelled inconsistently, worst first."""
+    by_directory: dict[str, dict[tuple[str, ...], Concept]] = {}
+    for path in sorted(files):
+        parent = PurePosixPath(path).parent.as_posix()
+        concepts = by_directory.setdefault(parent if parent else ".", {})
+        code = _TRIPLE_QUOTED.sub(_blank, contents.get(path) or "")
+        for lineno, line in enumerate(code.splitlines(), 1):
+            if is_comment_line(line):
+                continue
+            for name in _IDENTIFIER.findall(STRING_RE.sub(" ", line)):
+                spelling = name.lstrip("_")
+                if not spelling[:1].islower() or spelling in KEYWORDS:
+                    continue
+                words = tuple(canonical_term(w.lower()) for w in split_words(spelling))
+                concept = concepts.setdefault(words, Concept(words))
+                concept.spellings[spelling] += 1
+                concept.first_use.setdefault(spelling, f"{path}:{lineno}")
+                concept.paths.setdefault(spelling, set()).add(path)
+
+    drifted = []
+    for directory, concepts in sorted(by_directory.items()):
+        merged = _merge_compact_forms(concepts)
+        uses = sum(c.uses for c in merged)
+        if uses == 0:
+            continue
+        drift = sum(c.uses * c.entropy for c in merged) / uses
+        if drift < MIN_DRIFT:
+            continue
+        inconsistent = [c for c in merged if len(c.spellings) > 1]
+        inconsistent.sort(key=lambda c: (-c.uses, c.words))
+        drifted.append(DirectoryVocabulary(directory, drift, uses, inconsistent))
+    drifted.sort(key=lambda d: (-d.drift, d.directory))
+    return drifted
+
+
+def _merge_compact_forms(concepts: dict[tuple[str, ...], Concept]) -> list[Concept]:
+    """Multi-word concepts, with the single-word compact forms of each merged in."""
+    multi = {words: c for words, c in concepts.items() if len(words) > 1}
+    for words, concept in sorted(multi.items()):
+        compact = ("".join(words), "".join(w[0] for w in words[:-1]) + words[-1])
+        for form in dict.fromkeys(compact):
+            if len(form) < MIN_COMPACT_LENGTH:
+                continue
+            single = concepts.get((form,))
+            if single is None or (form,) in multi:
+                continue
+            concept.spellings.update(single.spellings)
+            for spelling, where in single.first_use.items():
+                concept.first_use.setdefault(spelling, where)
+                concept.paths.setdefault(spelling, set()).update(single.paths[spelling])
+            del concepts[(form,)]  # merged into one concept only
+    return list(multi.values())
+
+
+def _blank(match: re.Match[str]) -> str:
+    """The line breaks of a match, so later line numbers stay right."""
+    return "\n" * match.group().count("\n")
+
+
+def to_findings(directories: list[DirectoryVocabulary]) -> list:
+    """Convert inconsistent directories to ``vocabulary_drift`` findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for vocabulary in directories:
+        evidence = [
+            Evidence(
+                signal="vocabulary_drift",
+                value=round(vocabulary.drift, 3),
+                percentile=0.0,
+                description=(
+                    f"{vocabulary.drift:.2f} bits of spelling entropy per use "
+                    f"({len(vocabulary.concepts)} concepts spelled several ways)"
+                ),
+            )
+        ]
+        for concept in vocabulary.concepts[:MAX_CONCEPTS]:
+            variants = ", ".join(
+                f"{s} x{concept.spellings[s]} ({concept.first_use[s]})"
+                for s in [concept.preferred, *concept.others()]
+            )
+            evidence.append(
+                Evidence(
+                    signal="variants",
+                    value=float(len(concept.spellings)),
+                    percentile=0.0,
+                    description=variants,
+                )
+            )
+        top = vocabulary.concepts[0]
+        findings.append(
+            Finding(
+                finding_type=DRIFT_TYPE,
+                severity=vocabulary.severity,
+                title=(
+                    f"{vocabulary.directory}/ spells {len(vocabulary.concepts)} concepts "
+                    f"several ways ({' / '.join([top.preferred, *top.others()])})"
+                ),
+                files=vocabulary.files,
+                evidence=evidence,
+                suggestion=(
+                    f"Settle on one spelling per concept in {vocabulary.directory}/, "
+                    f"e.g. {top.preferred} instead of {', '.join(top.others())}"
+                ),
+                effort="LOW",
+            )
+        )
+    return findings
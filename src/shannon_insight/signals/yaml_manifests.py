@@ -0,0 +1,387 @@
+"""YAML structure: duplicated blocks, deep nesting and oversized values files.
+
+Every YAML file (Kubernetes manifests, Helm charts, CI and compose files;
+scanning/yaml.py parses them) is measured by its documents, the depth of
+the collections in each and the number of scalar values it sets.
+
+Three kinds of finding come out of it:
+
+    duplicate_yaml_block   a mapping or sequence item of MIN_DUPLICATE_LINES
+                           or more lines copied into two or more places,
+                           comments and indentation aside; only the largest
+                           copied block is reported, not the blocks in it
+    deep_yaml_nesting      a document whose collections nest
+                           DEEP_NESTING_DEPTH or more levels deep
+    values_file_outlier    a Helm values file (values.yaml, values-prod.yaml)
+                           setting far more values than the YAML files of the
+                           repo typically do, by the same modified z-score
+                           (MAD) test AnalysisEngine uses for file outliers
+"""
+
+from __future__ import annotations
+
+import hashlib
+import math
+import re
+from dataclasses import dataclass, field
+from typing import TYPE_CHECKING, Optional
+
+from ..math.robust import OUTLIER_Z
+from ..scanning.yaml import (
+    SEQUENCE_ITEM,
+    YamlNode,
+    key_path,
+    parse_yaml,
+    resource_name,
+    strip_comment,
+)
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax
+
+DUPLICATE_BLOCK_TYPE = "duplicate_yaml_block"
+DEEP_NESTING_TYPE = "deep_yaml_nesting"
+VALUES_OUTLIER_TYPE = "values_file_outlier"
+
+# Smaller copied blocks (a resources: or labels: stanza) are normal in manifests
+MIN_DUPLICATE_LINES = 8
+
+# A Deployment reading an env var from a secret is 10 levels deep
+DEEP_NESTING_DEPTH = 12
+
+# Too few YAML files give no meaningful notion of "typical"
+MIN_FILES = 5
+
+# Below this, a values file is never worth reporting however unusual it is
+MIN_OUTLIER_VALUES = 50
+
+_VALUES_FILE_RE = re.compile(r"(?:^|/)values(?:[-._][\w.-]+)?\.ya?ml$")
+
+
+@dataclass
+class YamlFile:
+    path: str
+    lines: int
+    documents: int = 0
+    resources: list[str] = field(default_factory=list)  # Kind/name of Kubernetes documents
+    values: int = 0  # scalar values set
+    max_depth: int = 0
+
+    @property
+    def is_values_file(self) -> bool:
+        return bool(_VALUES_FILE_RE.search(self.path))
+
+    def to_dict(self) -> dict:
+        return {
+            "path": self.path,
+            "lines": self.lines,
+            "documents": self.documents,
+            "resources": self.resources,
+            "values": self.values,
+            "max_depth": self.max_depth,
+            "values_file": self.is_values_file,
+        }
+
+
+@dataclass(frozen=True)
+class DuplicateBlock:
+    key: str  # key path of the first copy
+    lines: int  # significant lines of one copy
+    locations: tuple[tuple[str, int, int], ...]  # (path, start line, end line), sorted
+    digest: str
+
+    @property
+    def files(self) -> list[str]:
+        return list(dict.fromkeys(path for path, _, _ in self.locations))
+
+    @property
+    def severity(self) -> float:
+        extra = self.lines * (len(self.locations) - 1) / MIN_DUPLICATE_LINES
+        return min(0.6, 0.3 + 0.1 * math.log2(extra))
+
+
+@dataclass(frozen=True)
+class DeepNesting:
+    path: str
+    line: int  # of the innermost collection
+    document: str  # Kind/name, or the top-level key holding the deepest collection
+    keys: str  # key path down to the innermost collection
+    depth: int
+
+    @property
+    def severity(self) -> float:
+        return min(0.7, 0.4 + 0.1 * (self.depth - DEEP_NESTING_DEPTH))
+
+
+@dataclass(frozen=True)
+class ValuesOutlier:
+    file: YamlFile
+    typical_values: float  # median over all YAML files
+    modified_z: float
+
+    @property
+    def severity(self) -> float:
+        ratio = self.file.values / max(self.typical_values, 1.0)
+        return min(0.7, 0.4 + 0.1 * math.log2(ratio))
+
+
+@dataclass
+class YamlReport:
+    """YAML files, duplicated blocks, deep documents and values-file outliers.
+
+    Attributes:
+        files: Every YAML file, most values first
+        duplicates: Blocks copied into several places, largest first
+        deep_nesting: Documents DEEP_NESTING_DEPTH or more deep, deepest first
+        values_outliers: Values files with anomalous value counts, worst first
+    """
+
+    files: list[YamlFile] = field(default_factory=list)
+    duplicates: list[DuplicateBlock] = field(default_factory=list)
+    deep_nesting: list[DeepNesting] = field(default_factory=list)
+    values_outliers: list[ValuesOutlier] = field(default_factory=list)
+
+    def to_dict(self, top: Optional[int] = None) -> dict:
+        return {
+            "file_count": len(self.files),
+            "files": [f.to_dict() for f in self.files[:top]],
+            "duplicates": [
+                {
+                    "key": d.key,
+                    "lines": d.lines,
+                    "locations": [
+                        {"path": path, "start_line": start, "end_line": end}
+                        for path, start, end in d.locations
+                    ],
+                }
+                for d in self.duplicates[:top]
+            ],
+            "deep_nesting": [d.__dict__ for d in self.deep_nesting[:top]],
+            "values_outliers": [
+                {
+                    "path": o.file.path,
+                    "values": o.file.values,
+                    "typical_values": o.typical_values,
+                    "modified_z": round(o.modified_z, 2),
+                }
+                for o in self.values_outliers
+            ],
+        }
+
+
+def collect_yaml(files: dict[str, FileSyntax], contents: dict[str, str]) -> YamlReport:
+    """Measure the YAML files in *files* and find their structural problems."""
+    report = YamlReport()
+    blocks: dict[str, list[tuple[str, YamlNode, int]]] = {}  # digest -> (path, node, lines)
+    for path, syntax in sorted(files.items()):
+        if syntax.language != "yaml":
+            continue
+        content = contents.get(path, "")
+        lines = content.splitlines()
+        documents = parse_yaml(content)
+        measured = YamlFile(path, len(lines), documents=len(documents))
+        for document in documents:
+            resource = resource_name(document)
+            if resource is not None:
+                measured.resources.append(resource)
+            measured.values += document.scalars
+            measured.max_depth = max(measured.max_depth, document.depth)
+            deep = _deep_nesting(path, document, resource)
+            if deep is not None:
+                report.deep_nesting.append(deep)
+            for node in document.walk():
+                if node.children:
+                    text = _canonical(lines, node)
+                    if text.count("\n") + 1 >= MIN_DUPLICATE_LINES:
+                        digest = hashlib.sha1(text.encode()).hexdigest()[:12]
+                        copies = blocks.setdefault(digest, [])
+                        # A document and its only key cover the same lines
+                        if not any(
+                            p == path and n.start_line == node.start_line for p, n, _ in copies
+                        ):
+                            copies.append((path, node, text.count("\n") + 1))
+        report.files.append(measured)
+
+    report.files.sort(key=lambda f: (-f.values, f.path))
+    report.duplicates = _duplicates(blocks)
+    report.deep_nesting.sort(key=lambda d: (-d.depth, d.path, d.line))
+    report.values_outliers = find_values_outliers(report.files)
+    return report
+
+
+def _canonical(lines: list[str], node: YamlNode) -> str:
+    """The node's lines without comments and blank lines, dedented to its first line."""
+    body = [strip_comment(line) for line in lines[node.start_line - 1 : node.end_line]]
+    body = [line for line in body if line.strip()]
+    indent = len(body[0]) - len(body[0].lstrip(" "))
+    return "\n".join(line[indent:] for line in body)
+
+
+def _duplicates(blocks: dict[str, list[tuple[str, YamlNode, int]]]) -> list[DuplicateBlock]:
+    """Groups of copies, leaving out blocks that are only copied as part of a larger one."""
+    groups = sorted(
+        (
+            DuplicateBlock(
+                key=_node_key(copies[0][1]),
+                lines=copies[0][2],
+                locations=tuple(sorted((p, n.start_line, n.end_line) for p, n, _ in copies)),
+                digest=digest,
+            )
+            for digest, copies in blocks.items()
+            if len(copies) > 1
+        ),
+        key=lambda d: (-d.lines, d.locations),
+    )
+    kept: list[DuplicateBlock] = []
+    for group in groups:
+        covered = all(
+            any(
+                path == outer_path and outer_start <= start and end <= outer_end
+                for larger in kept
+                for outer_path, outer_start, outer_end in larger.locations
+            )
+            for path, start, end in group.locations
+        )
+        if not covered:
+            kept.append(group)
+    return kept
+
+
+def _node_key(node: YamlNode) -> str:
+    return "[]" if node.key == SEQUENCE_ITEM else node.key or "document"
+
+
+def _deep_nesting(path: str, document: YamlNode, resource: Optional[str]) -> Optional[DeepNesting]:
+    chain = document.deepest_chain()
+    if len(chain) < DEEP_NESTING_DEPTH:
+        return None
+    return DeepNesting(
+        path=path,
+        line=chain[-1].start_line,
+        document=resource or _node_key(chain[1]),
+        keys=key_path(chain),
+        depth=len(chain),
+    )
+
+
+def _median(values: list[float]) -> float:
+    values = sorted(values)
+    mid = len(values) // 2
+    return values[mid] if len(values) % 2 else (values[mid - 1] + values[mid]) / 2
+
+
+def find_values_outliers(
+    files: list[YamlFile], threshold: float = OUTLIER_Z
+) -> list[ValuesOutlier]:
+    """Values files setting far more values than the typical YAML file, worst first."""
+    if len(files) < MIN_FILES:
+        return []
+    counts = [float(f.values) for f in files]
+    typical = _median(counts)
+    # MAD of 0 is common (many small files); 1 keeps the score finite
+    mad = max(_median([abs(c - typical) for c in counts]), 1.0)
+    outliers = []
+    for file, count in zip(files, counts):
+        z = 0.6745 * (count - typical) / mad
+        if file.is_values_file and z > threshold and count >= MIN_OUTLIER_VALUES:
+            outliers.append(ValuesOutlier(file, typical, z))
+    return sorted(outliers, key=lambda o: (-o.modified_z, o.file.path))
+
+
+def to_findings(report: YamlReport) -> list:
+    """Convert duplicated blocks, deep documents and values outliers to findings."""
+    from ..insights.models import Evidence, Finding
+
+    findings = []
+    for block in report.duplicates:
+        places = ", ".join(f"{path}:{start}-{end}" for path, start, end in block.locations)
+        findings.append(
+            Finding(
+                finding_type=DUPLICATE_BLOCK_TYPE,
+                severity=block.severity,
+                title=(
+                    f"{block.lines}-line YAML block {block.key} is repeated in "
+                    f"{len(block.locations)} places"
+                ),
+                files=block.files,
+                evidence=[
+                    Evidence(
+                        signal="duplicate_lines",
+                        value=float(block.lines),
+                        percentile=0.0,
+                        description=f"{block.lines} lines per copy",
+                    ),
+                    Evidence(
+                        signal="copies",
+                        value=float(len(block.locations)),
+                        percentile=0.0,
+                        description=places,
+                    ),
+                ],
+                suggestion=(
+                    "Keep one copy: a Helm named template, a Kustomize base or patch, "
+                    "or a YAML anchor"
+                ),
+                effort="MEDIUM",
+                identity_hint=f"{block.key}:{block.digest}",
+            )
+        )
+    for deep in report.deep_nesting:
+        findings.append(
+            Finding(
+                finding_type=DEEP_NESTING_TYPE,
+                severity=deep.severity,
+                title=f"{deep.document} in {deep.path} nests {deep.depth} levels deep",
+                files=[deep.path],
+                evidence=[
+                    Evidence(
+                        signal="yaml_depth",
+                        value=float(deep.depth),
+                        percentile=0.0,
+                        description=f"{deep.keys} at line {deep.line}",
+                    ),
+                ],
+                suggestion=(
+                    "Flatten the structure, or move the nested part into a values key, "
+                    "ConfigMap or document of its own"
+                ),
+                effort="LOW",
+                identity_hint=f"{deep.document}:{deep.keys}",
+            )
+        )
+    for outlier in report.values_outliers:
+        file = outlier.file
+        findings.append(
+            Finding(
+                finding_type=VALUES_OUTLIER_TYPE,
+                severity=outlier.severity,
+                title=(
+                    f"Values file {file.path} sets {file.values} values, "
+                    f"typical is {outlier.typical_values:g}"
+                ),
+                files=[file.path],
+                evidence=[
+                    Evidence(
+                        signal="values",
+                        value=float(file.values),
+                        percentile=0.0,
+                        description=f"{file.values} values in {file.lines} lines",
+                    ),
+                    Evidence(
+                        signal="typical_values",
+                        value=outlier.typical_values,
+                        percentile=0.0,
+                        description=(
+                            f"YAML files of this repo typically set "
+                            f"{outlier.typical_values:g} (modified z {outlier.modified_z:.1f})"
+                        ),
+                    ),
+                ],
+                suggestion=(
+                    "Move settings that never change into the chart's templates, or split "
+                    "the chart so each values file configures one component"
+                ),
+                effort="HIGH",
+            )
+        )
+    return findings
@@ -0,0 +1,263 @@
+"""Output sinks: where the rendered formats of one analysis run go.
+
+``--format`` may be given several times; each format is rendered once into
+an ``Output`` and every ``--sink`` receives all of them, so SARIF for code
+scanning, an HTML report for people and a Slack summary come from one run::
+
+    shannon-insight --format sarif --format html --sink reports/ --sink slack
+
+Sinks (``parse_sink``):
+
+    stdout, -           print the output (the default when no --sink is given);
+                        only one format fits on stdout
+    DIR, file:DIR       write each output to DIR/FILENAMES[format], creating DIR
+    s3://bucket/prefix  upload each output to prefix/FILENAMES[format] (needs
+                        boto3: pip install shannon-codebase-insight[s3])
+    slack, slack:URL    post a summary -- finding counts and the top findings --
+                        to a Slack incoming webhook, $SLACK_WEBHOOK_URL by default
+    github              suggested changes on the pull request of a GitHub Actions run
+    gitlab              suggested changes on the merge request of a GitLab CI pipeline
+
+The github and gitlab sinks post the machine-applicable patches findings
+carry as review comments (see review.py) and need no format.
+
+Delivery is best effort, as with webhooks: every sink is tried, and the ones
+that failed are reported afterwards instead of stopping the others.
+"""
+
+from __future__ import annotations
+
+import json
+import os
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import TYPE_CHECKING, Any, Mapping, Optional, Protocol
+
+from .logging_config import get_logger
+from .webhooks import Post, _post
+
+if TYPE_CHECKING:
+    from .review import Review
+
+logger = get_logger(__name__)
+
+# File name of each format in directory and S3 sinks
+FILENAMES = {
+    "json": "shannon-insight.json",
+    "sarif": "shannon-insight.sarif",
+    "html": "shannon-insight.html",
+    "editor": "shannon-insight.editor.txt",
+    "vscode": "shannon-insight.vscode.txt",
+    "quickfix": "shannon-insight.quickfix.txt",
+    "quickfix-json": "shannon-insight.quickfix.json",
+}
+
+_CONTENT_TYPES = {
+    "json": "application/json",
+    "sarif": "application/sarif+json",
+    "html": "text/html; charset=utf-8",
+    "quickfix-json": "application/json",
+}
+
+# Findings listed in a Slack summary
+SLACK_TOP = 5
+
+
+@dataclass(frozen=True)
+class Output:
+    """One format rendered for the sinks."""
+
+    format: str
+    text: str
+
+    @property
+    def filename(self) -> str:
+        return FILENAMES[self.format]
+
+    @property
+    def content_type(self) -> str:
+        return _CONTENT_TYPES.get(self.format, "text/plain; charset=utf-8")
+
+
+@dataclass
+class Run:
+    """What the sinks receive: the rendered outputs and the findings behind them."""
+
+    outputs: list[Output]
+    findings: list = field(default_factory=list)
+    file_count: int = 0
+    project: str = ""
+
+
+@dataclass(frozen=True)
+class SinkResult:
+    sink: str
+    detail: str = ""
+    error: str = ""
+
+    @property
+    def ok(self) -> bool:
+        return not self.error
+
+
+class Sink(Protocol):
+    name: str
+    needs_outputs: bool
+
+    def write(self, run: Run) -> str:
+        """Deliver *run*; a short description of what was written."""
+        ...
+
+
+@dataclass
+class StdoutSink:
+    name: str = "stdout"
+    needs_outputs: bool = True
+
+    def write(self, run: Run) -> str:
+        for output in run.outputs:
+            # Plain print() so documents are never wrapped or styled
+            print(output.text)
+        return ""
+
+
+@dataclass
+class DirectorySink:
+    path: Path
+    needs_outputs: bool = True
+
+    @property
+    def name(self) -> str:
+        return str(self.path)
+
+    def write(self, run: Run) -> str:
+        self.path.mkdir(parents=True, exist_ok=True)
+        for output in run.outputs:
+            (self.path / output.filename).write_text(output.text, encoding="utf-8")
+        return f"wrote {', '.join(o.filename for o in run.outputs)} to {self.path}"
+
+
+@dataclass
+class S3Sink:
+    bucket: str
+    prefix: str = ""
+    client: Any = None
+    needs_outputs: bool = True
+
+    @property
+    def name(self) -> str:
+        return f"s3://{self.bucket}/{self.prefix}"
+
+    def write(self, run: Run) -> str:
+        client = self.client or _boto3().client("s3")
+        for output in run.outputs:
+            client.put_object(
+                Bucket=self.bucket,
+                Key=self._key(output.filename),
+                Body=output.text.encode("utf-8"),
+                ContentType=output.content_type,
+            )
+        return f"uploaded {len(run.outputs)} outputs to {self.name}"
+
+    def _key(self, filename: str) -> str:
+        prefix = self.prefix.strip("/")
+        return f"{prefix}/{filename}" if prefix else filename
+
+
+@dataclass
+class SlackSink:
+    url: str
+    post: Optional[Post] = None
+    name: str = "slack"
+    needs_outputs: bool = False
+
+    def write(self, run: Run) -> str:
+        body = json.dumps({"text": slack_summary(run)}).encode()
+        headers = {"Content-Type": "application/json", "User-Agent": "shannon-insight"}
+        status = (self.post or _post)(self.url, body, headers)
+        if not 200 <= status < 300:
+            raise RuntimeError(f"HTTP {status}")
+        return "posted summary to Slack"
+
+
+@dataclass
+class ReviewSink:
+    review: Review
+    name: str  # github or gitlab
+    needs_outputs: bool = False
+
+    def write(self, run: Run) -> str:
+        return self.review.publish(run.findings)
+
+
+def parse_sink(spec: str, environ: Optional[Mapping[str, str]] = None) -> Sink:
+    """The sink a ``--sink`` value names; ValueError when it is malformed."""
+    environ = os.environ if environ is None else environ
+    if spec in ("stdout", "-"):
+        return StdoutSink()
+    if spec.startswith("s3://"):
+        bucket, _, prefix = spec[len("s3://") :].partition("/")
+        if not bucket:
+            raise ValueError(f"sink {spec!r}: s3:// needs a bucket (s3://bucket/prefix)")
+        return S3Sink(bucket, prefix)
+    if spec == "slack" or spec.startswith("slack:"):
+        url = spec[len("slack:") :] if spec != "slack" else environ.get("SLACK_WEBHOOK_URL", "")
+        if not url:
+            raise ValueError(
+                "sink 'slack' needs a webhook URL: --sink slack:https://hooks.slack.com/... "
+                "or SLACK_WEBHOOK_URL"
+            )
+        if not url.startswith(("https://", "http://")):
+            raise ValueError(f"sink {spec!r}: the Slack webhook must be an http(s) URL")
+        return SlackSink(url)
+    if spec in ("github", "gitlab"):
+        from .review import parse_review
+
+        return ReviewSink(parse_review(spec, environ), spec)
+    if spec.startswith("file:"):
+        spec = spec[len("file:") :]
+    elif "://" in spec:
+        raise ValueError(
+            f"sink {spec!r}: unknown scheme (use a directory, s3://, slack, github or gitlab)"
+        )
+    if not spec:
+        raise ValueError("sink 'file:' needs a directory")
+    return DirectorySink(Path(spec))
+
+
+def run_sinks(sinks: list[Sink], run: Run) -> list[SinkResult]:
+    """Deliver *run* to every sink, carrying on past the ones that fail."""
+    results = []
+    for sink in sinks:
+        try:
+            results.append(SinkResult(sink.name, sink.write(run)))
+        except Exception as e:
+            logger.warning(f"Sink {sink.name} failed: {e}")
+            results.append(SinkResult(sink.name, error=str(e)))
+    return results
+
+
+def slack_summary(run: Run) -> str:
+    """Slack mrkdwn: finding counts by severity and the most severe findings."""
+    high = sum(1 for f in run.findings if f.severity > 0.7)
+    medium = sum(1 for f in run.findings if 0.4 < f.severity <= 0.7)
+    project = f" {run.project}" if run.project else ""
+    lines = [
+        f"*Shannon Insight*{project}: {len(run.findings)} findings in {run.file_count} files "
+        f"({high} high, {medium} medium)"
+    ]
+    top = sorted(run.findings, key=lambda f: -f.severity)[:SLACK_TOP]
+    lines += [f"• {f.title} (`{f.finding_type}`, {f.severity:.2f})" for f in top]
+    return "\n".join(lines)
+
+
+def _boto3() -> Any:
+    try:
+        import boto3
+
+        return boto3
+    except ImportError:
+        raise ImportError(
+            "boto3 is required for s3:// sinks. "
+            "Install with: pip install shannon-codebase-insight[s3]"
+        )
@@ -0,0 +1,174 @@
+"""How one function became complex: its complexity at every commit that changed it.
+
+``shannon-insight complexity-blame src/engine.py Engine.run`` walks the
+history of the file (following renames, merges left out) and re-parses the
+function in each commit and in that commit's parent:
+
+    version   a commit that changed the function's text, with the
+              function's METRICS after it (None once it was removed)
+    added     complexity after the commit minus complexity before it, so
+              each commit is charged for exactly what it did, whichever
+              branch it was made on
+
+The versions, oldest first, are the function's complexity over time;
+``ComplexityBlame.growth`` ranks the commits that added the most. Functions
+are matched by name (methods as ``Class.method``), so a rename of the
+function itself ends its history: the oldest version is then where it got
+its current name.
+"""
+
+from __future__ import annotations
+
+from dataclasses import dataclass, field
+from pathlib import Path
+from typing import TYPE_CHECKING, Any, Optional
+
+from .recent import _git, _named, _parse, function_metrics
+
+if TYPE_CHECKING:
+    from ..scanning.syntax_extractor import SyntaxExtractor
+
+# Commits of the file read at most, newest first
+MAX_COMMITS = 500
+
+
+@dataclass
+class FunctionVersion:
+    """A commit that changed the function."""
+
+    commit: str
+    author: str
+    timestamp: int
+    subject: str
+    path: str  # the file's path at the commit
+    metrics: Optional[dict[str, int]]  # None when the commit removed the function
+    before: Optional[dict[str, int]]  # None when the commit added it
+
+    @property
+    def complexity(self) -> int:
+        return self.metrics["complexity"] if self.metrics is not None else 0
+
+    @property
+    def added(self) -> int:
+        """Complexity the commit added; negative when it simplified the function."""
+        before = self.before["complexity"] if self.before is not None else 0
+        return self.complexity - before
+
+    def to_dict(self) -> dict[str, Any]:
+        return {
+            "commit": self.commit,
+            "author": self.author,
+            "timestamp": self.timestamp,
+            "subject": self.subject,
+            "path": self.path,
+            "metrics": self.metrics,
+            "complexity_added": self.added,
+        }
+
+
+@dataclass
+class ComplexityBlame:
+    path: str
+    name: str
+    commits: int  # commits of the file read
+    truncated: bool  # stopped at max_commits before the file's first commit
+    versions: list[FunctionVersion] = field(default_factory=list)  # oldest first
+
+    @property
+    def current(self) -> Optional[dict[str, int]]:
+        """METRICS after the newest change; None if that change removed the function."""
+        return self.versions[-1].metrics if self.versions else None
+
+    def growth(self, top: Optional[int] = None) -> list[FunctionVersion]:
+        """Commits that added complexity, most first, then newest first."""
+        grew = [(i, v) for i, v in enumerate(self.versions) if v.added > 0]
+        return [v for _, v in sorted(grew, key=lambda e: (-e[1].added, -e[0]))][:top]
+
+    def to_dict(self, top: Optional[int] = None) -> dict[str, Any]:
+        return {
+            "path": self.path,
+            "name": self.name,
+            "commits": self.commits,
+            "truncated": self.truncated,
+            "current": self.current,
+            "versions": [v.to_dict() for v in self.versions],
+            "growth": [v.to_dict() for v in self.growth(top)],
+        }
+
+
+def blame_complexity(
+    root: Path,
+    path: str,
+    name: str,
+    extractor: SyntaxExtractor,
+    max_commits: int = MAX_COMMITS,
+) -> ComplexityBlame:
+    """Complexity of function *name* in *path* at every commit that changed it.
+
+    Raises:
+        ValueError: If *root* is not a git repository, *path* has no history,
+            or no version of the file has a function called *name*.
+    """
+    log = _git(
+        root,
+        "log",
+        "--follow",
+        "--no-merges",
+        f"--max-count={max_commits}",
+        "--format=%x00%H%x1f%P%x1f%an%x1f%at%x1f%s",
+        "--name-only",
+        "HEAD",
+        "--",
+        path,
+    )
+    if log is None:
+        raise ValueError(f"{root} is not a git repository with commits")
+    entries = []
+    for entry in log.split("\x00")[1:]:
+        header, _, names = entry.partition("\n")
+        sha, parents, author, timestamp, subject = header.split("\x1f", 4)
+        paths = [n for n in names.splitlines() if n]
+        entries.append((sha, parents.split()[:1], author, int(timestamp), subject, paths))
+    if not entries:
+        raise ValueError(f"{path} has no history")
+
+    cache: dict[tuple[str, str], tuple[Optional[dict[str, int]], Optional[list[str]]]] = {}
+
+    def version(revision: str, at: str) -> tuple[Optional[dict[str, int]], Optional[list[str]]]:
+        """METRICS and text of the function at *revision*, or (None, None)."""
+        if (revision, at) not in cache:
+            syntax, content = _parse(root, revision, at, extractor)
+            found = None
+            if syntax is not None:
+                found = next((fn for n, fn in _named(syntax) if n == name), None)
+            if found is None:
+                cache[revision, at] = (None, None)
+            else:
+                lines = content.splitlines()
+                text = lines[found.start_line - 1 : found.end_line]
+                cache[revision, at] = (function_metrics(found, lines), text)
+        return cache[revision, at]
+
+    blame = ComplexityBlame(
+        path=path,
+        name=name,
+        commits=len(entries),
+        truncated=len(entries) == max_commits,
+    )
+    seen = False
+    for i, (sha, parents, author, timestamp, subject, paths) in enumerate(entries):
+        at = paths[0] if paths else path
+        # The parent knows the file by the name the next older commit used
+        older = entries[i + 1][5] if i + 1 < len(entries) else paths
+        after, text = version(sha, at)
+        before, old_text = (None, None)
+        if parents:
+            before, old_text = version(parents[0], older[0] if older else at)
+        seen = seen or after is not None or before is not None
+        if text == old_text:
+            continue
+        blame.versions.append(FunctionVersion(sha, author, timestamp, subject, at, after, before))
+    if not seen:
+        raise ValueError(f"No version of {path} has a function named {name}")
+    blame.versions.reverse()
+    return blame
@@ -0,0 +1,61 @@
+"""How many times each line of a file has changed, from its git history.
+
+File-level churn (temporal/churn.py) says a file changes often, not where.
+``line_churn`` replays the file's history along the first-parent chain
+(following renames, each merge counted once as the change it brought in),
+applying every commit's zero-context diff to a count per line:
+
+    added line     count 1
+    changed lines  1 + the highest count among the lines they replaced
+    removed line   gone, with its count
+
+The counts left at HEAD are how often each current line, or the lines it
+replaced, has been rewritten. A rewritten region keeps its history; a line
+that was moved elsewhere by delete-and-insert starts over at 1.
+"""
+
+from __future__ import annotations
+
+import re
+from pathlib import Path
+from typing import Optional
+
+from .recent import _git
+
+_HUNK_RE = re.compile(r"^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@", re.MULTILINE)
+
+
+def line_churn(root: Path, path: str, lines: int) -> Optional[list[int]]:
+    """Change count of each of the *lines* lines of *path* at HEAD.
+
+    None when *root* is not a git repository or *path* has no history. The
+    result always has *lines* entries; lines the history does not account
+    for (uncommitted edits) count 0.
+    """
+    log = _git(
+        root,
+        "log",
+        "--first-parent",
+        "--diff-merges=first-parent",
+        "--follow",
+        "--format=%x00",
+        "-p",
+        "-U0",
+        "--no-color",
+        "--no-ext-diff",
+        "HEAD",
+        "--",
+        path,
+    )
+    if not log:
+        return None
+    counts: list[int] = []
+    for commit in reversed(log.split("\x00")[1:]):
+        # A missing length in "@@ -a,b +c,d @@" means one line
+        hunks = [(int(a), int(b or 1), int(d or 1)) for a, b, _, d in _HUNK_RE.findall(commit)]
+        # Later hunks first, so earlier old-file positions stay valid
+        for start, removed, added in sorted(hunks, reverse=True):
+            at = start - 1 if removed else start
+            replaced = counts[at : at + removed]
+            counts[at : at + removed] = [1 + max(replaced, default=0)] * added
+    return (counts + [0] * lines)[:lines]
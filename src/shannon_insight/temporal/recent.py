@@ -0,0 +1,334 @@
+"""Recently touched code: new and changed functions, by committer and package.
+
+``shannon-insight recent --days 30`` tells what changed lately and how
+healthy it is, without mining the whole history. Two git calls set up the
+window, and each touched file costs two ``git show`` and one ``git blame``:
+
+    window     commits of the last N days (git log --since), and the files
+               they touched that still exist at HEAD
+    base       the last commit before the window (git rev-list --before);
+               none when the repository is younger than the window
+
+Each file is parsed at HEAD and at the base. A function (methods as
+``Class.method``) is new when the base has no function of that name in the
+file, and touched when the base has one with different text. Its committer
+is the author of most of its lines that ``git blame --since`` places in the
+window, or, for a change that only removed lines, the last author in the
+window to commit the file.
+
+Per function: lines, cyclomatic complexity (1 + decision points), nesting
+depth and parameters. Touched functions also carry the change of each since
+the base, so a group's health reads as "how complex is the new code" and
+"did the code it touched get simpler or worse".
+"""
+
+from __future__ import annotations
+
+import subprocess
+import time
+from collections import Counter
+from dataclasses import dataclass, field
+from pathlib import Path, PurePosixPath
+from typing import TYPE_CHECKING, Any, Optional, Sequence
+
+from ..logging_config import get_logger
+from ..signals.function_outliers import function_complexity
+
+if TYPE_CHECKING:
+    from ..scanning.syntax import FileSyntax, FunctionDef
+    from ..scanning.syntax_extractor import SyntaxExtractor
+
+logger = get_logger(__name__)
+
+METRICS = ("lines", "complexity", "nesting_depth", "params")
+
+_GIT_TIMEOUT_SECONDS = 30
+
+
+@dataclass
+class RecentFunction:
+    """A function added or changed in the window.
+
+    Attributes:
+        path: File at HEAD
+        name: Function name, ``Class.method`` for methods
+        start_line: First line at HEAD
+        committer: Author of most of its recent lines
+        metrics: METRICS at HEAD
+        before: METRICS at the base; None for new functions
+    """
+
+    path: str
+    name: str
+    start_line: int
+    committer: str
+    metrics: dict[str, int]
+    before: Optional[dict[str, int]] = None
+
+    @property
+    def status(self) -> str:
+        return "new" if self.before is None else "touched"
+
+    @property
+    def package(self) -> str:
+        parent = PurePosixPath(self.path).parent.as_posix()
+        return parent if parent else "."
+
+    @property
+    def delta(self) -> dict[str, int]:
+        """Change of each metric since the base; empty for new functions."""
+        if self.before is None:
+            return {}
+        return {m: self.metrics[m] - self.before[m] for m in METRICS}
+
+    def to_dict(self) -> dict[str, Any]:
+        result: dict[str, Any] = {
+            "path": self.path,
+            "name": self.name,
+            "start_line": self.start_line,
+            "status": self.status,
+            "committer": self.committer,
+            **self.metrics,
+        }
+        if self.before is not None:
+            result["delta"] = self.delta
+        return result
+
+
+@dataclass
+class GroupSummary:
+    """New and touched functions of one committer or package."""
+
+    name: str
+    new: int = 0
+    touched: int = 0
+    new_complexity: int = 0  # summed complexity of the new functions
+    complexity_delta: int = 0  # summed complexity change of the touched ones
+    worsened: int = 0  # touched functions whose complexity rose
+
+    @property
+    def mean_new_complexity(self) -> float:
+        return self.new_complexity / self.new if self.new else 0.0
+
+    def add(self, fn: RecentFunction) -> None:
+        if fn.before is None:
+            self.new += 1
+            self.new_complexity += fn.metrics["complexity"]
+        else:
+            self.touched += 1
+            change = fn.delta["complexity"]
+            self.complexity_delta += change
+            self.worsened += change > 0
+
+    def to_dict(self) -> dict[str, Any]:
+        return {
+            "name": self.name,
+            "new": self.new,
+            "touched": self.touched,
+            "mean_new_complexity": round(self.mean_new_complexity, 1),
+            "complexity_delta": self.complexity_delta,
+            "worsened": self.worsened,
+        }
+
+
+@dataclass
+class RecentReport:
+    days: int
+    since: int  # unix time the window starts at
+    base: Optional[str]  # last commit before the window
+    commits: int
+    files: list[str] = field(default_factory=list)
+    functions: list[RecentFunction] = field(default_factory=list)
+
+    @property
+    def new(self) -> list[RecentFunction]:
+        """New functions, most complex first."""
+        return sorted(
+            (f for f in self.functions if f.before is None),
+            key=lambda f: (-f.metrics["complexity"], f.path, f.start_line),
+        )
+
+    @property
+    def touched(self) -> list[RecentFunction]:
+        """Touched functions, largest complexity increase first."""
+        return sorted(
+            (f for f in self.functions if f.before is not None),
+            key=lambda f: (-f.delta["complexity"], f.path, f.start_line),
+        )
+
+    def by_committer(self) -> list[GroupSummary]:
+        return self._group(lambda f: f.committer)
+
+    def by_package(self) -> list[GroupSummary]:
+        return self._group(lambda f: f.package)
+
+    def _group(self, key: Any) -> list[GroupSummary]:
+        groups: dict[str, GroupSummary] = {}
+        for fn in self.functions:
+            name = key(fn)
+            groups.setdefault(name, GroupSummary(name)).add(fn)
+        return sorted(groups.values(), key=lambda g: (-(g.new + g.touched), g.name))
+
+    def to_dict(self, top: Optional[int] = None) -> dict[str, Any]:
+        return {
+            "days": self.days,
+            "since": self.since,
+            "base": self.base,
+            "commits": self.commits,
+            "files": len(self.files),
+            "by_committer": [g.to_dict() for g in self.by_committer()],
+            "by_package": [g.to_dict() for g in self.by_package()],
+            "new": [f.to_dict() for f in self.new[:top]],
+            "touched": [f.to_dict() for f in self.touched[:top]],
+        }
+
+
+def collect_recent(
+    root: Path,
+    days: int,
+    extractor: SyntaxExtractor,
+    exclude_patterns: Sequence[str] = (),
+    now: Optional[float] = None,
+) -> RecentReport:
+    """New and touched functions of the last *days* days.
+
+    Raises:
+        ValueError: If *root* is not a git repository.
+    """
+    from ..file_ops import should_skip_file
+
+    since = int(now if now is not None else time.time()) - days * 86400
+    log = _git(root, "log", f"--since=@{since}", "--format=%x00%an", "--name-only", "HEAD")
+    if log is None:
+        raise ValueError(f"{root} is not a git repository with commits")
+    # Newest commit first, so the first author seen per file is its last one
+    last_author: dict[str, str] = {}
+    commits = 0
+    for entry in log.split("\x00")[1:]:
+        commits += 1
+        author, _, names = entry.partition("\n")
+        for name in names.splitlines():
+            if name:
+                last_author.setdefault(name, author)
+    base = (_git(root, "rev-list", "-1", f"--before=@{since}", "HEAD") or "").strip() or None
+
+    report = RecentReport(days=days, since=since, base=base, commits=commits)
+    for path in sorted(last_author):
+        if should_skip_file(Path(path), list(exclude_patterns)):
+            continue
+        syntax, content = _parse(root, "HEAD", path, extractor)
+        if syntax is None:
+            continue  # deleted or renamed away since, or not source code
+        previous, old = _parse(root, base, path, extractor) if base else (None, "")
+        authors = _recent_authors(root, path, since)
+        functions = _changed_functions(syntax, content, previous, old, authors, last_author[path])
+        if functions:
+            report.files.append(path)
+            report.functions.extend(functions)
+    return report
+
+
+def function_metrics(fn: FunctionDef, lines: list[str]) -> dict[str, int]:
+    """METRICS of one function, from its syntax and the file's lines."""
+    return {
+        "lines": max(1, fn.end_line - fn.start_line + 1),
+        "complexity": function_complexity(lines[fn.start_line - 1 : fn.end_line]),
+        "nesting_depth": fn.nesting_depth,
+        "params": len(fn.params),
+    }
+
+
+def _changed_functions(
+    syntax: FileSyntax,
+    content: str,
+    previous: Optional[FileSyntax],
+    old_content: str,
+    authors: dict[int, str],
+    fallback_author: str,
+) -> list[RecentFunction]:
+    lines = content.splitlines()
+    old_lines = old_content.splitlines()
+    old_functions = dict(_named(previous)) if previous is not None else {}
+    changed = []
+    for name, fn in _named(syntax):
+        text = lines[fn.start_line - 1 : fn.end_line]
+        old = old_functions.get(name)
+        if old is not None and old_lines[old.start_line - 1 : old.end_line] == text:
+            continue
+        recent = Counter(
+            authors[n] for n in range(fn.start_line, fn.end_line + 1) if n in authors
+        )
+        if old is None and not recent:
+            continue  # unchanged text in a file the window only touched elsewhere
+        changed.append(
+            RecentFunction(
+                path=syntax.path,
+                name=name,
+                start_line=fn.start_line,
+                committer=recent.most_common(1)[0][0] if recent else fallback_author,
+                metrics=function_metrics(fn, lines),
+                before=function_metrics(old, old_lines) if old is not None else None,
+            )
+        )
+    return changed
+
+
+def _parse(
+    root: Path, revision: str, path: str, extractor: SyntaxExtractor
+) -> tuple[Optional[FileSyntax], str]:
+    """Syntax of *path* at *revision*, and the text its line numbers refer to."""
+    content = _git(root, "show", f"{revision}:{path}")
+    if content is None:
+        return None, ""
+    # Notebooks are parsed as the Python source of their code cells
+    parsed: dict[str, str] = {}
+    syntax = extractor.extract_source(path, content, root, content_cache=parsed)
+    return syntax, parsed.get(path, content)
+
+
+def _named(syntax: FileSyntax) -> list[tuple[str, FunctionDef]]:
+    named = [(fn.name, fn) for fn in syntax.functions]
+    for cls in syntax.classes:
+        named.extend((f"{cls.name}.{fn.name}", fn) for fn in cls.methods)
+    return named
+
+
+def _recent_authors(root: Path, path: str, since: int) -> dict[int, str]:
+    """Line number at HEAD -> author, for the lines last changed in the window."""
+    out = _git(
+        root, "blame", "--root", "--line-porcelain", f"--since=@{since}", "HEAD", "--", path
+    )
+    if out is None:
+        return {}
+    authors: dict[int, str] = {}
+    final_line = 0
+    author = ""
+    boundary = False
+    for row in out.splitlines():
+        if row.startswith("\t"):
+            if not boundary:
+                authors[final_line] = author
+            boundary = False
+            continue
+        parts = row.split()
+        if len(parts) >= 3 and len(parts[0]) == 40 and parts[2].isdigit():
+            final_line = int(parts[2])
+        elif row.startswith("author "):
+            author = row[len("author ") :]
+        elif row == "boundary":
+            boundary = True
+    return authors
+
+
+def _git(root: Path, *args: str) -> Optional[str]:
+    try:
+        result = subprocess.run(
+            ["git", "-C", str(root), *args],
+            capture_output=True,
+            text=True,
+            timeout=_GIT_TIMEOUT_SECONDS,
+        )
+    except (FileNotFoundError, subprocess.TimeoutExpired) as e:
+        logger.debug(f"git {args[0]} failed: {e}")
+        return None
+    return result.stdout if result.returncode == 0 else None
@@ -2,7 +2,9 @@
 
 The report embeds all data as a JSON blob inside a ``<script>`` tag and
 uses a pure-SVG squarified treemap (zero external dependencies) so it
-can be opened from any local file:// path or served statically.
+can be opened from any local file:// path or served statically. Flagged
+files are embedded with their source and a per-line metric gutter (see
+source_view.py).
 """
 
 import json
@@ -10,6 +12,7 @@ from pathlib import Path
 from typing import Optional, Union
 
 from ..persistence.models import Snapshot, TensorSnapshot
+from .source_view import flagged_files, source_views
 from .treemap import build_treemap_data
 
 
@@ -19,6 +22,8 @@ def generate_report(
     trends: Optional[dict[str, list]] = None,
     output_path: str = "shannon-report.html",
     default_metric: str = "cognitive_load",
+    include_source: bool = True,
+    source_root: Optional[str] = None,
 ) -> str:
     """Generate a self-contained HTML report with interactive treemap.
 
@@ -35,12 +40,32 @@ def generate_report(
         Where to write the HTML file.
     default_metric:
         Which signal to colour the treemap by on first render.
+    include_source:
+        Embed the source of the most severely flagged files with a
+        nesting, complexity and churn gutter per line.
+    source_root:
+        Where to read those files; the snapshot's ``analyzed_path`` by
+        default. Files missing there are left out.
 
     Returns
     -------
     str
         Absolute path to the generated HTML file.
     """
+    html = render_report(snapshot, trends, default_metric, include_source, source_root)
+    out = Path(output_path).resolve()
+    out.write_text(html, encoding="utf-8")
+    return str(out)
+
+
+def render_report(
+    snapshot: Union[Snapshot, TensorSnapshot],
+    trends: Optional[dict[str, list]] = None,
+    default_metric: str = "cognitive_load",
+    include_source: bool = True,
+    source_root: Optional[str] = None,
+) -> str:
+    """The HTML of the report ``generate_report`` writes (same parameters)."""
     treemap_data = build_treemap_data(snapshot.file_signals, default_metric)
 
     # ── Findings data ──────────────────────────────────────────────
@@ -81,6 +106,12 @@ def generate_report(
         or getattr(snapshot, "global_signals", {}),
     }
 
+    # ── Flagged file sources ───────────────────────────────────────
+    sources_data: list[dict] = []
+    if include_source:
+        root = Path(source_root or snapshot.analyzed_path)
+        sources_data = source_views(root, flagged_files(snapshot.findings))
+
     # ── Available metrics for dropdown ─────────────────────────────
     metrics: set[str] = set()
     for sigs in snapshot.file_signals.values():
@@ -95,14 +126,13 @@ def generate_report(
             "summary": summary_data,
             "metrics": metrics_list,
             "default_metric": default_metric,
+            "sources": sources_data,
         }
     )
+    # Source text may contain "</script>"; "<\/" is the same JSON string
+    data_json = data_json.replace("</", "<\\/")
 
-    html = _build_html(data_json)
-
-    out = Path(output_path).resolve()
-    out.write_text(html, encoding="utf-8")
-    return str(out)
+    return _build_html(data_json)
 
 
 # ── Private helpers ──────────────────────────────────────────────────
@@ -153,6 +183,21 @@ body {{ font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans
 .finding-files {{ font-size: 13px; color: #58a6ff; margin-bottom: 6px; }}
 .finding-evidence {{ font-size: 13px; color: #8b949e; }}
 .finding-suggestion {{ font-size: 13px; color: #3fb950; margin-top: 8px; }}
+.finding-files a {{ color: #58a6ff; }}
+#sources {{ padding: 24px 32px; }}
+#sources h2 {{ font-size: 18px; color: #58a6ff; margin-bottom: 8px; }}
+.source-legend {{ font-size: 12px; color: #8b949e; margin-bottom: 16px; }}
+.source-file {{ background: #161b22; border: 1px solid #21262d; border-radius: 8px; margin-bottom: 12px; }}
+.source-file summary {{ padding: 10px 16px; cursor: pointer; font-size: 14px; color: #58a6ff; }}
+.source-file summary .source-meta {{ color: #8b949e; font-size: 12px; margin-left: 8px; }}
+.source-table {{ border-collapse: collapse; width: 100%; font-family: SFMono-Regular, Consolas, Menlo, monospace; font-size: 12px; }}
+.source-table td {{ padding: 0 8px; white-space: pre; vertical-align: top; line-height: 18px; }}
+.source-table .g {{ text-align: right; color: #8b949e; width: 1%; border-right: 1px solid #21262d; }}
+.source-table .g.cx {{ color: #f0883e; font-weight: 600; }}
+.source-table .ln {{ text-align: right; color: #484f58; width: 1%; user-select: none; }}
+.source-table tr.fn-start td {{ border-top: 1px solid #30363d; }}
+.source-table .fn-name {{ color: #d2a8ff; padding: 4px 8px; font-family: -apple-system, sans-serif; }}
+.depth-bar {{ display: inline-block; height: 10px; background: #58a6ff; opacity: 0.7; vertical-align: middle; }}
 #trends {{ padding: 24px 32px; }}
 #trends h2 {{ font-size: 18px; color: #58a6ff; margin-bottom: 16px; }}
 .trend-row {{ display: flex; align-items: center; gap: 16px; padding: 8px 0; border-bottom: 1px solid #21262d; }}
@@ -172,6 +217,10 @@ footer {{ padding: 24px 32px; text-align: center; color: #484f58; font-size: 12p
 </div>
 <div id="treemap-container"><div id="treemap"></div></div>
 <div id="findings"><h2>Findings</h2><div id="finding-cards"></div></div>
+<div id="sources"><h2>Flagged Files</h2>
+  <div class="source-legend">Gutter: nesting depth &middot; decision points on the line &middot; times the line changed (background: churn heat)</div>
+  <div id="source-files"></div>
+</div>
 <div id="trends"><h2>File Trends</h2><div id="trend-rows"></div></div>
 <footer>Generated by Shannon Insight</footer>
 
@@ -351,6 +400,60 @@ function squarify(areas, x, y, w, h) {{
   return rects;
 }}
 
+// ── Flagged file sources ─────────────────────────────────────────
+var SOURCE_INDEX = {{}};
+(DATA.sources || []).forEach(function(src, i) {{ SOURCE_INDEX[src.path] = i; }});
+
+function churnColor(churn, max) {{
+  if (churn === null || !max) return "transparent";
+  return "rgba(248,81,73," + (0.6 * churn / max).toFixed(3) + ")";
+}}
+
+function renderSource(details, src) {{
+  var starts = {{}};
+  src.functions.forEach(function(fn) {{ starts[fn.start_line] = fn; }});
+  var rows = src.lines.map(function(line, i) {{
+    var fn = starts[i + 1];
+    var head = fn ? '<tr><td colspan="5" class="fn-name">' + escapeHtml(fn.name) +
+      ' &middot; lines ' + fn.start_line + '-' + fn.end_line + ' &middot; complexity ' + fn.complexity + '</td></tr>' : "";
+    var depth = line[1] ? '<span class="depth-bar" style="width:' + (4 * line[1]) + 'px"></span> ' + line[1] : "";
+    return head + '<tr' + (fn ? ' class="fn-start"' : "") + '>' +
+      '<td class="g">' + depth + '</td>' +
+      '<td class="g' + (line[2] ? ' cx' : '') + '">' + (line[2] || "") + '</td>' +
+      '<td class="g" style="background:' + churnColor(line[3], src.max_churn) + '">' + (line[3] || "") + '</td>' +
+      '<td class="ln">' + (i + 1) + '</td>' +
+      '<td>' + escapeHtml(line[0]) + '</td></tr>';
+  }});
+  if (src.truncated) rows.push('<tr><td colspan="5" class="fn-name">&hellip; truncated</td></tr>');
+  details.insertAdjacentHTML("beforeend", '<table class="source-table">' + rows.join("") + '</table>');
+}}
+
+function openSource(i) {{
+  var details = document.getElementById("src-" + i);
+  if (details) details.open = true;
+}}
+
+(function() {{
+  var el = document.getElementById("source-files");
+  if (!DATA.sources || !DATA.sources.length) {{
+    el.innerHTML = '<p style="color:#8b949e">No flagged file sources embedded.</p>';
+    return;
+  }}
+  DATA.sources.forEach(function(src, i) {{
+    var details = document.createElement("details");
+    details.className = "source-file";
+    details.id = "src-" + i;
+    details.innerHTML = '<summary>' + escapeHtml(src.path) + '<span class="source-meta">' +
+      src.lines.length + ' lines &middot; ' + src.functions.length + ' functions' +
+      (src.max_churn ? ' &middot; changed up to ' + src.max_churn + ' times' : '') + '</span></summary>';
+    // Rendered on first open, so large reports stay quick to load
+    details.addEventListener("toggle", function() {{
+      if (details.open && !details.querySelector("table")) renderSource(details, src);
+    }});
+    el.appendChild(details);
+  }});
+}})();
+
 // ── Finding cards ────────────────────────────────────────────────
 (function() {{
   var el = document.getElementById("finding-cards");
@@ -363,7 +466,10 @@ function squarify(areas, x, y, w, h) {{
     var evidence = f.evidence.map(function(e) {{
       return '<div class="finding-evidence">&bull; ' + escapeHtml(e.description) + '</div>';
     }}).join("");
-    var filesHtml = f.files.map(function(fp) {{ return escapeHtml(fp); }}).join(", ");
+    var filesHtml = f.files.map(function(fp) {{
+      if (!(fp in SOURCE_INDEX)) return escapeHtml(fp);
+      return '<a href="#src-' + SOURCE_INDEX[fp] + '" onclick="openSource(' + SOURCE_INDEX[fp] + ')">' + escapeHtml(fp) + '</a>';
+    }}).join(", ");
     return '<div class="finding-card' + sev + '">' +
       '<div class="finding-type">' + escapeHtml(f.type.replace(/_/g, " ")) + '</div>' +
       '<div class="finding-title">' + escapeHtml(f.title) + '</div>' +
@@ -0,0 +1,92 @@
+"""Per-line metric gutters for the flagged files of the HTML report.
+
+A finding names a file and a score; the source view shows which lines earn
+it. Every line of a flagged file gets three gutter values:
+
+    nesting     nesting level inside its function, from indentation
+                (see signals.complexity.line_levels); 0 outside functions
+    decisions   decision points on the line (if, for, while, case, &&,
+                ||, ...): its share of the function's cyclomatic complexity
+    churn       how many times the line, or the lines it replaced, has
+                changed in git history (see temporal.line_churn); None
+                without git history
+
+Functions are listed with their span and complexity, so a reader of a
+2000-line function sees the nested, branching, often-rewritten region
+that drives its score. Files are read from the analyzed tree as it is
+now, so views are only as current as the working copy.
+"""
+
+from __future__ import annotations
+
+from collections.abc import Iterable
+from pathlib import Path
+from typing import Any, Optional
+
+from ..scanning.syntax_extractor import SyntaxExtractor
+from ..signals.complexity import DECISION_RE, is_comment_line, line_levels
+from ..temporal.line_churn import line_churn
+
+# Flagged files rendered, most severe first
+MAX_FILES = 20
+
+# Longer files are cut at this many lines
+MAX_LINES = 3000
+
+
+def source_view(
+    root: Path, path: str, extractor: SyntaxExtractor, churn: bool = True
+) -> Optional[dict[str, Any]]:
+    """Lines of *path* with their gutter values; None when it cannot be read."""
+    try:
+        text = (root / path).read_text(encoding="utf-8", errors="replace")
+    except OSError:
+        return None
+    lines = text.splitlines()
+    nesting = [0] * len(lines)
+    decisions = [0 if is_comment_line(line) else len(DECISION_RE.findall(line)) for line in lines]
+    functions = []
+    syntax = extractor.extract(root / path, root)
+    # Outer functions first, so nested ones overwrite the lines they own
+    for fn in sorted(syntax.functions if syntax else [], key=lambda f: f.start_line):
+        start, end = fn.start_line - 1, min(fn.end_line, len(lines))
+        if start < 0 or start >= end:
+            continue
+        for i, level in line_levels(lines[start:end]):
+            nesting[start + i] = level
+        functions.append(
+            {
+                "name": fn.name,
+                "start_line": fn.start_line,
+                "end_line": end,
+                "complexity": 1 + sum(decisions[start:end]),
+            }
+        )
+    changes = line_churn(root, path, len(lines)) if churn else None
+    shown = min(len(lines), MAX_LINES)
+    return {
+        "path": path,
+        "truncated": len(lines) > shown,
+        "lines": [
+            [lines[i], nesting[i], decisions[i], changes[i] if changes else None]
+            for i in range(shown)
+        ],
+        "max_churn": max(changes[:shown], default=0) if changes else 0,
+        "functions": functions,
+    }
+
+
+def flagged_files(findings: Iterable[Any], limit: int = MAX_FILES) -> list[str]:
+    """Files named by *findings*, the most severely flagged first."""
+    worst: dict[str, float] = {}
+    for finding in findings:
+        for path in finding.files:
+            worst[path] = max(worst.get(path, 0.0), finding.severity)
+    return sorted(worst, key=lambda p: (-worst[p], p))[:limit]
+
+
+def source_views(root: Path, paths: list[str], churn: bool = True) -> list[dict[str, Any]]:
+    """Source views of *paths* under *root*, leaving out files that cannot be read."""
+    extractor = SyntaxExtractor(max_workers=1)
+    views = (source_view(root, path, extractor, churn) for path in paths)
+    return [view for view in views if view is not None]
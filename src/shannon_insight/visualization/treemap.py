@@ -41,7 +41,7 @@ def build_treemap_data(
     * **value** -- ``lines`` signal (or 1), used for rectangle area sizing.
     * **color_value** -- percentile rank of *color_metric* across all files
       (0.0 = lowest, 1.0 = highest).
-    * **signals** -- the full signal dict, rounded to 4 decimal places.
+    * **signals** -- the full signal dict, numbers rounded to 4 decimal places.
 
     Parameters
     ----------
@@ -79,7 +79,10 @@ def build_treemap_data(
                     "path": filepath,
                     "value": max(1, int(signals.get("lines", 1))),
                     "color_value": round(percentile, 3),
-                    "signals": {k: round(v, 4) for k, v in signals.items()},
+                    "signals": {
+                        k: round(v, 4) if isinstance(v, (int, float)) else v
+                        for k, v in signals.items()
+                    },
                 }
                 node["children"].append(leaf)
             else:
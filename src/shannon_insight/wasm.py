@@ -0,0 +1,233 @@
+"""In-memory analysis core for the WebAssembly build.
+
+The browser build (wasm/, loaded with Pyodide) has no file system to
+scan, no git and no threads, so it cannot run the InsightKernel. It hands
+this module the text of the files instead, and gets back what can be
+computed from the text alone:
+
+    files      per file: language, lines, Halstead metrics, the
+               Maintainability Index, comment signals, and per-function
+               metrics (the rows of server/decorations.py plus cyclomatic
+               complexity, average nesting, Halstead and comment metrics)
+    findings   the findings that need no dependency graph or history:
+               complexity_outlier, deep_nesting, god_class, low_cohesion,
+               long_parameter_list, data_clump, long_procedure,
+               code_clone, the Terraform and YAML findings,
+               crypto_policy_violation and auth_flow_issue
+
+Files are parsed one after another with the regex fallback parsers
+(tree-sitter is not available in the browser), so results match a
+``shannon-insight`` run without tree-sitter installed. Everything crosses
+the JS boundary as JSON: ``analyze_json`` and ``function_metrics_json``
+are what wasm/src/index.js calls.
+"""
+
+from __future__ import annotations
+
+import json
+import logging
+from dataclasses import asdict
+from pathlib import Path
+from typing import Any, Mapping, Optional
+
+from . import __version__
+from .config import AnalysisConfig
+from .portable import portable_path
+from .scanning.languages import detect_language
+from .scanning.syntax import FileSyntax
+from .scanning.syntax_extractor import SyntaxExtractor
+from .signals.comments import file_comments
+from .signals.halstead import halstead
+from .signals.maintainability import file_maintainability, resolve_weights
+
+logger = logging.getLogger(__name__)
+
+# Root the extractor resolves paths against; nothing is read from it
+_ROOT = Path("/")
+
+
+def parse_sources(
+    files: Mapping[str, str], config: Optional[AnalysisConfig] = None
+) -> dict[str, FileSyntax]:
+    """Parse every file of a known language among *files* (path -> text)."""
+    config = config or AnalysisConfig()
+    extractor = SyntaxExtractor(
+        max_workers=1,
+        c_defines=config.c_defines,
+        c_conditionals=config.c_conditionals,
+    )
+    parsed = {}
+    for path in sorted(files):
+        rel_path = portable_path(path)
+        if detect_language(rel_path, files[path]) == "unknown":
+            continue
+        syntax = extractor.extract_source(rel_path, files[path], _ROOT)
+        if syntax is not None:
+            parsed[syntax.path] = syntax
+    return parsed
+
+
+def function_metrics(syntax: FileSyntax, content: str) -> list[dict[str, Any]]:
+    """Decoration rows of one file's functions, plus complexity, nesting, Halstead, comments."""
+    from .server.decorations import function_metrics as decoration_rows
+    from .signals.function_outliers import function_complexity
+    from .signals.halstead import function_halstead
+    from .signals.nesting import function_nesting
+
+    lines = content.splitlines()
+    rows = decoration_rows(syntax)
+    comments = {(c.line, c.name): c for c in file_comments(syntax, content).functions}
+    for row, (fn, counts) in zip(rows, function_halstead(syntax, content)):
+        row["complexity"] = function_complexity(lines[row["start_line"] - 1 : row["end_line"]])
+        row["avg_nesting"] = round(function_nesting(fn, lines)[0], 2)
+        row.update(counts.to_dict())
+        if (fn.start_line, fn.name) in comments:
+            row.update(comments[fn.start_line, fn.name].to_dict())
+    return rows
+
+
+def analyze_sources(
+    files: Mapping[str, str], overrides: Optional[Mapping[str, Any]] = None
+) -> dict[str, Any]:
+    """Per-file metrics and text-only findings for *files* (path -> text).
+
+    Args:
+        files: Text of each file, by path relative to the project root
+        overrides: AnalysisConfig fields, as in shannon-insight.toml
+
+    Raises:
+        ValueError: An override is not a valid configuration value
+    """
+    try:
+        config = AnalysisConfig(**dict(overrides or {}))
+    except TypeError as e:
+        raise ValueError(str(e)) from e
+    contents = {portable_path(path): text for path, text in files.items()}
+    parsed = parse_sources(contents, config)
+    findings = sorted(
+        _findings(parsed, contents, config), key=lambda f: (-f.severity, f.files, f.title)
+    )
+    weights = resolve_weights(config.maintainability_weights)
+    return {
+        "version": __version__,
+        "files": [
+            _file_metrics(syntax, contents[path], weights) for path, syntax in parsed.items()
+        ],
+        "findings": [asdict(f) for f in findings[: config.max_findings]],
+    }
+
+
+def _file_metrics(syntax: FileSyntax, content: str, weights: dict[str, float]) -> dict[str, Any]:
+    counts = halstead(content, syntax.language)
+    comments = file_comments(syntax, content)
+    return {
+        "path": syntax.path,
+        "language": syntax.language,
+        "lines": syntax.lines,
+        **counts.to_dict(),
+        "maintainability_index": round(file_maintainability(content, counts.volume, weights), 1),
+        "comment_density": round(comments.comment_density, 3),
+        "commented_out_lines": comments.commented_out_lines,
+        "undocumented_complexity": comments.undocumented_complexity,
+        "functions": function_metrics(syntax, content),
+    }
+
+
+def analyze_json(files_json: str, overrides_json: str = "{}") -> str:
+    """analyze_sources() with JSON in and out; errors are ``{"error": message}``."""
+    try:
+        return json.dumps(analyze_sources(json.loads(files_json), json.loads(overrides_json)))
+    except ValueError as e:
+        return json.dumps({"error": str(e)})
+
+
+def function_metrics_json(path: str, content: str) -> str:
+    """Per-function metrics of a single file as JSON; ``[]`` for unknown languages."""
+    syntax = parse_sources({path: content}).get(portable_path(path))
+    return json.dumps(function_metrics(syntax, content) if syntax is not None else [])
+
+
+def _findings(
+    files: dict[str, FileSyntax], contents: dict[str, str], config: AnalysisConfig
+) -> list:
+    """The kernel's text-only findings (see insights/analyzers and finders/reports.py)."""
+    from .hygiene import auth, crypto, errors, literals
+    from .scanning.generated import find_generated_files
+    from .signals import (
+        api_surface,
+        cohesion,
+        duplication,
+        function_outliers,
+        nesting,
+        parameters,
+        sql_statements,
+        terraform_modules,
+        type_sizes,
+        yaml_manifests,
+    )
+
+    def of(language: str) -> dict[str, FileSyntax]:
+        return {path: syntax for path, syntax in files.items() if syntax.language == language}
+
+    generated = find_generated_files(contents)
+    scored = {path: syntax for path, syntax in files.items() if path not in generated}
+    findings = []
+    checks = [
+        lambda: function_outliers.to_findings(
+            function_outliers.find_function_outliers(
+                function_outliers.collect_functions(scored, contents)
+            )
+        ),
+        lambda: nesting.to_findings(
+            nesting.find_deep_nesting(
+                nesting.collect_nesting(scored, contents), config.nesting_threshold
+            ),
+            config.nesting_threshold,
+        ),
+        lambda: type_sizes.to_findings(
+            type_sizes.find_god_classes(type_sizes.collect_types(scored, contents))
+        ),
+        lambda: cohesion.to_findings(
+            cohesion.find_low_cohesion(cohesion.collect_cohesion(scored, contents))
+        ),
+        lambda: parameters.to_findings(
+            parameters.find_long_parameter_lists(
+                parameters.collect_parameters(scored), config.parameter_threshold
+            ),
+            parameters.find_data_clumps(parameters.collect_parameters(scored)),
+            config.parameter_threshold,
+        ),
+        lambda: sql_statements.to_findings(
+            sql_statements.collect_sql(of("sql"), contents).long_procedures()
+        ),
+        lambda: duplication.to_findings(
+            duplication.analyze_duplication(
+                scored, contents, config.thresholds.clone_min_tokens
+            )
+        ),
+        lambda: terraform_modules.to_findings(
+            terraform_modules.collect_terraform(of("hcl"), contents)
+        ),
+        lambda: yaml_manifests.to_findings(yaml_manifests.collect_yaml(of("yaml"), contents)),
+        lambda: api_surface.to_findings(api_surface.measure_surface(scored, contents)),
+        lambda: literals.to_findings(
+            literals.analyze_literals(
+                {path: syntax.language for path, syntax in scored.items()},
+                contents,
+                config.literal_repeat_threshold,
+            )
+        ),
+        lambda: crypto.to_findings(
+            crypto.analyze_crypto(
+                files, contents, crypto.resolve_policy(config.crypto_policy)
+            ).violations
+        ),
+        lambda: auth.to_findings(auth.analyze_auth(files, contents).issues),
+        lambda: errors.to_findings(errors.analyze_errors(contents).issues),
+    ]
+    for check in checks:
+        try:
+            findings.extend(check())
+        except Exception as e:
+            logger.warning(f"Browser analysis check failed: {e}")
+    return findings
@@ -0,0 +1,283 @@
+"""Webhook exporter: POST finding and gate events to any HTTP endpoint.
+
+Covers integrations without native support (chat, incident tools, internal
+dashboards). Each ``[[webhooks]]`` entry names a URL, the events it
+receives, optional filters and an optional payload template::
+
+    [[webhooks]]
+    url = "https://chat.example.com/hooks/quality"
+    events = ["finding_created", "finding_resolved"]
+    finding_types = ["high_risk_hub", "hidden_coupling"]
+    min_severity = 0.7
+    headers = { Authorization = "Bearer ${CHAT_TOKEN}" }
+    template = '{"text": "[$project] $event: $title ($files)"}'
+
+Events:
+
+    finding_created   a finding absent from the previous baseline, sent when
+                      serve mode rotates the baseline (see server/baseline.py)
+    finding_resolved  a finding of the previous baseline that is gone
+    gate              the outcome of every ``shannon-insight gate`` run
+
+Without a template the body is the event as JSON. A template is a
+``string.Template``: ``$name`` placeholders are replaced by the event's
+fields (``FIELDS``), escaped to sit inside a JSON string, and ``$payload``
+by the whole event as JSON. Fields an event does not have are empty.
+``${VAR}`` in header values is read from the environment when sending, so
+tokens stay out of the config file.
+
+Delivery is best effort: a failed POST is logged and reported, never
+raised, so an unreachable endpoint cannot fail a gate or a rotation.
+"""
+
+from __future__ import annotations
+
+import json
+import os
+import re
+import urllib.error
+import urllib.request
+from dataclasses import dataclass, field
+from string import Template
+from typing import Any, Callable, Optional
+
+from .logging_config import get_logger
+
+logger = get_logger(__name__)
+
+EVENTS = ("finding_created", "finding_resolved", "gate")
+
+# Template placeholders; finding fields are empty in gate events and vice versa
+FIELDS = (
+    "event",
+    "project",
+    "commit_sha",
+    "finding_type",
+    "identity_key",
+    "title",
+    "severity",
+    "files",
+    "suggestion",
+    "passed",
+    "status",
+    "summary",
+)
+
+_TIMEOUT_SECONDS = 10
+_ENV_RE = re.compile(r"\$\{(\w+)\}")
+
+# (url, body, headers) -> HTTP status
+Post = Callable[[str, bytes, dict], int]
+
+
+@dataclass(frozen=True)
+class WebhookEvent:
+    """One event: its name and fields (a subset of FIELDS)."""
+
+    event: str
+    fields: dict[str, Any]
+
+    def to_dict(self) -> dict:
+        return {"event": self.event, **self.fields}
+
+
+@dataclass
+class Webhook:
+    """A configured endpoint and the slice of events it receives."""
+
+    url: str
+    events: tuple[str, ...] = EVENTS
+    finding_types: tuple[str, ...] = ()  # empty = every type
+    min_severity: float = 0.0
+    headers: dict[str, str] = field(default_factory=dict)
+    template: str = ""
+
+    def matches(self, event: WebhookEvent) -> bool:
+        if event.event not in self.events:
+            return False
+        if event.event == "gate":
+            return True
+        if self.finding_types and event.fields.get("finding_type") not in self.finding_types:
+            return False
+        return float(event.fields.get("severity", 0.0)) >= self.min_severity
+
+    def payload(self, event: WebhookEvent) -> bytes:
+        document = event.to_dict()
+        if not self.template:
+            return json.dumps(document).encode()
+        values = {name: _escape(document.get(name, "")) for name in FIELDS}
+        values["payload"] = json.dumps(document)
+        return Template(self.template).substitute(values).encode()
+
+    def request_headers(self) -> dict[str, str]:
+        headers = {"Content-Type": "application/json", "User-Agent": "shannon-insight"}
+        for name, value in self.headers.items():
+            headers[name] = _ENV_RE.sub(lambda m: os.environ.get(m[1], ""), value)
+        return headers
+
+
+@dataclass(frozen=True)
+class Delivery:
+    """The result of POSTing one event to one webhook."""
+
+    url: str
+    event: str
+    status: Optional[int] = None  # HTTP status; None when the request failed
+    error: str = ""
+
+    @property
+    def ok(self) -> bool:
+        return self.status is not None and 200 <= self.status < 300
+
+
+def parse_webhooks(entries: list) -> list[Webhook]:
+    """Webhooks of the ``webhooks`` config list; ValueError when one is malformed."""
+    hooks = []
+    for index, entry in enumerate(entries):
+        where = f"webhooks[{index}]"
+        if not isinstance(entry, dict):
+            raise ValueError(f"{where} must be a table")
+        unknown = set(entry) - {
+            "url",
+            "events",
+            "finding_types",
+            "min_severity",
+            "headers",
+            "template",
+        }
+        if unknown:
+            raise ValueError(f"{where}: unknown keys {', '.join(sorted(unknown))}")
+        url = entry.get("url")
+        if not isinstance(url, str) or not url.startswith(("https://", "http://")):
+            raise ValueError(f"{where}: url must be an http:// or https:// URL")
+        events = entry.get("events", list(EVENTS))
+        if not isinstance(events, list) or not events or not set(events) <= set(EVENTS):
+            raise ValueError(f"{where}: events must be a non-empty list of {', '.join(EVENTS)}")
+        finding_types = entry.get("finding_types", [])
+        if not isinstance(finding_types, list) or not all(
+            isinstance(t, str) for t in finding_types
+        ):
+            raise ValueError(f"{where}: finding_types must be a list of finding types")
+        min_severity = entry.get("min_severity", 0.0)
+        if not isinstance(min_severity, (int, float)) or not 0.0 <= min_severity <= 1.0:
+            raise ValueError(f"{where}: min_severity must be between 0.0 and 1.0")
+        headers = entry.get("headers", {})
+        if not isinstance(headers, dict) or not all(
+            isinstance(v, str) for v in headers.values()
+        ):
+            raise ValueError(f"{where}: headers must map header names to strings")
+        template = entry.get("template", "")
+        if not isinstance(template, str):
+            raise ValueError(f"{where}: template must be a string")
+        try:
+            Template(template).substitute(dict.fromkeys((*FIELDS, "payload"), ""))
+        except (KeyError, ValueError) as e:
+            raise ValueError(f"{where}: template has an unknown or invalid placeholder {e}")
+        hooks.append(
+            Webhook(
+                url,
+                tuple(events),
+                tuple(finding_types),
+                float(min_severity),
+                dict(headers),
+                template,
+            )
+        )
+    return hooks
+
+
+def finding_events(
+    old: list, new: list, project: str, commit_sha: Optional[str] = None
+) -> list[WebhookEvent]:
+    """finding_created and finding_resolved events between two lists of FindingRecords.
+
+    Findings are matched by identity key, as in history diffs.
+    """
+    old_keys = {f.identity_key for f in old}
+    new_keys = {f.identity_key for f in new}
+    created = [f for f in new if f.identity_key not in old_keys]
+    resolved = [f for f in old if f.identity_key not in new_keys]
+    return [
+        WebhookEvent(event, _finding_fields(finding, project, commit_sha))
+        for event, findings in (("finding_created", created), ("finding_resolved", resolved))
+        for finding in sorted(findings, key=lambda f: (-f.severity, f.identity_key))
+    ]
+
+
+def _finding_fields(finding: Any, project: str, commit_sha: Optional[str]) -> dict[str, Any]:
+    return {
+        "project": project,
+        "commit_sha": commit_sha or "",
+        "finding_type": finding.finding_type,
+        "identity_key": finding.identity_key,
+        "title": finding.title,
+        "severity": round(finding.severity, 3),
+        "files": ", ".join(finding.files),
+        "suggestion": finding.suggestion,
+    }
+
+
+def gate_event(
+    passed: bool, checks: dict, project: str, commit_sha: Optional[str] = None
+) -> WebhookEvent:
+    """A gate event; *checks* is the gate's JSON document (ratchet, fail_on)."""
+    failed = [
+        name
+        for name, check in checks.items()
+        if not check.get("passed", True) or check.get("complete") is False
+    ]
+    if passed:
+        summary = "gate passed"
+    else:
+        summary = f"gate failed: {', '.join(failed)}" if failed else "gate failed"
+    return WebhookEvent(
+        "gate",
+        {
+            "project": project,
+            "commit_sha": commit_sha or "",
+            "passed": passed,
+            "status": "passed" if passed else "failed",
+            "summary": summary,
+            "checks": checks,
+        },
+    )
+
+
+def deliver(
+    hooks: list[Webhook], events: list[WebhookEvent], post: Optional[Post] = None
+) -> list[Delivery]:
+    """POST every event to every webhook it matches, one request each."""
+    post = post or _post
+    deliveries = []
+    for hook in hooks:
+        for event in events:
+            if not hook.matches(event):
+                continue
+            try:
+                status = post(hook.url, hook.payload(event), hook.request_headers())
+                delivery = Delivery(hook.url, event.event, status)
+            except (urllib.error.URLError, OSError, ValueError) as e:
+                delivery = Delivery(hook.url, event.event, error=str(e))
+            if not delivery.ok:
+                logger.warning(
+                    f"Webhook {hook.url} rejected {event.event}: "
+                    f"{delivery.error or f'HTTP {delivery.status}'}"
+                )
+            deliveries.append(delivery)
+    return deliveries
+
+
+def _post(url: str, body: bytes, headers: dict) -> int:
+    request = urllib.request.Request(url, data=body, headers=headers, method="POST")
+    try:
+        with urllib.request.urlopen(request, timeout=_TIMEOUT_SECONDS) as response:
+            return response.status
+    except urllib.error.HTTPError as e:
+        return e.code
+
+
+def _escape(value: Any) -> str:
+    """*value* as the inside of a JSON string."""
+    if isinstance(value, bool):
+        value = str(value).lower()
+    return json.dumps(str(value))[1:-1]
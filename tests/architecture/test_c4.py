@@ -0,0 +1,114 @@
+"""Tests for C4 diagram export."""
+
+import pytest
+
+from shannon_insight.architecture.c4 import build_model, find_containers, render
+from shannon_insight.hygiene import load_sources
+
+FILES = {
+    "go.mod": "module example.com/shop\n",
+    "services/billing/Dockerfile": "FROM golang\n",
+    "services/billing/main.go": "package main\n\nfunc main() {}\n",
+    "services/billing/api/handler.go": (
+        'package api\n\nconst payments = "http://payments-service:8080/charge"\n\n'
+        "func ServeHTTP() {}\n"
+    ),
+    "services/billing/store/db.go": "package store\n\nfunc Save() {}\n",
+    "services/payments/package.json": "{}\n",
+    "services/payments/index.js": "export function charge() {}\n",
+    "libs/money/money.go": "package money\n\nfunc Add() {}\n",
+}
+
+IMPORTS = {
+    "services/billing/main.go": ["services/billing/api/handler.go"],
+    "services/billing/api/handler.go": ["services/billing/store/db.go", "libs/money/money.go"],
+    "services/billing/store/db.go": ["libs/money/money.go"],
+}
+
+
+@pytest.fixture
+def model(tmp_path):
+    root = tmp_path / "shop"
+    for name, text in FILES.items():
+        (root / name).parent.mkdir(parents=True, exist_ok=True)
+        (root / name).write_text(text)
+    return build_model(load_sources(root), IMPORTS, paths=sorted(FILES))
+
+
+def test_find_containers_names_and_ids():
+    containers = find_containers(
+        "shop",
+        ["Dockerfile", "api/Dockerfile", "web/api/package.json", "tools/user/go.mod"],
+        ["jobs"],
+    )
+
+    assert [(c.id, c.name, c.path, c.manifests) for c in containers] == [
+        ("shop", "shop", ".", ["Dockerfile"]),
+        ("api", "api", "api", ["Dockerfile"]),
+        ("jobs", "jobs", "jobs", ["build.sbt"]),
+        ("user_2", "user", "tools/user", ["go.mod"]),  # "user" is the person
+        ("web_api", "web/api", "web/api", ["package.json"]),
+    ]
+
+
+def test_containers_components_and_relations(model):
+    billing, payments, shop = sorted(model.containers, key=lambda c: c.name)
+
+    assert (billing.path, billing.language, billing.entries) == (
+        "services/billing",
+        "go",
+        {"main": 1, "http": 1},
+    )
+    assert billing.description == "services/billing: 1 main, 1 HTTP handler"
+    assert [m.name for m in billing.components] == ["billing", "api", "store"]
+    assert payments.language == "javascript"
+    assert shop.files == ["libs/money/money.go"]
+    assert [(r.source, r.target, r.kind, r.count) for r in model.relations] == [
+        ("billing", "payments", "http", 1),
+        ("billing", "shop", "imports", 2),
+    ]
+    assert [(r.source, r.target, r.kind) for r in model.component_relations] == [
+        ("billing_api", "billing_store", "imports"),
+        ("billing_api", "payments_payments", "http"),
+        ("billing_api", "shop_libs_money", "imports"),
+        ("billing_billing", "billing_api", "imports"),
+        ("billing_store", "shop_libs_money", "imports"),
+    ]
+
+
+def test_structurizr_dsl(model):
+    dsl = render(model, "structurizr")
+
+    assert dsl.startswith('workspace "')
+    assert '        system = softwareSystem "' in dsl
+    assert (
+        '            billing = container "billing" "services/billing: 1 main, 1 HTTP handler" "Go"'
+        in dsl
+    )
+    assert '        user -> billing "Uses" "HTTP"' in dsl
+    assert '        billing -> payments "Calls" "HTTP"' in dsl
+    assert '        billing -> shop "Uses" "imports (2 files)"' in dsl
+    assert "component" not in dsl.replace("components", "")
+
+    components = render(model, "structurizr", "component")
+
+    assert '                billing_api = component "api" "services/billing/api" "Go"' in components
+    assert "        component billing {" in components
+
+
+def test_plantuml_component_relations_skip_boundaries(model):
+    puml = render(model, "plantuml", "component")
+
+    assert puml.startswith("@startuml\n!include <C4/C4_Component>\n")
+    assert '    Container_Boundary(billing, "billing") {' in puml
+    assert 'Rel(user, billing_api, "Uses", "HTTP")' in puml
+    assert 'Rel(billing_api, payments_payments, "Calls", "HTTP")' in puml
+    assert "Rel(billing, " not in puml
+    assert puml.endswith("@enduml\n")
+
+    assert 'Rel(billing, payments, "Calls", "HTTP")' in render(model, "plantuml")
+
+
+def test_render_rejects_unknown_format(model):
+    with pytest.raises(ValueError, match="unknown C4 format"):
+        render(model, "mermaid")
@@ -0,0 +1,133 @@
+"""Tests for service-to-service request flows."""
+
+import pytest
+
+from shannon_insight.architecture.flows import (
+    Endpoint,
+    build_flows,
+    find_routes,
+    match_call,
+    render,
+)
+from shannon_insight.hygiene import load_sources
+
+FILES = {
+    "services/users/go.mod": "module example.com/users\n",
+    "services/users/main.go": (
+        "package main\n\n"
+        "func main() {\n"
+        '\tmux.HandleFunc("GET /users/{id}", getUser)\n'
+        '\tmux.HandleFunc("POST /users", createUser)\n'
+        '\tmux.HandleFunc("GET /health", health)\n'
+        "\tpb.RegisterProfilesServer(server, &profiles{})\n"
+        "}\n"
+    ),
+    "services/orders/pyproject.toml": "[project]\nname = 'orders'\n",
+    "services/orders/app.py": (
+        "import requests\n\n"
+        'USERS = "http://users-service:8080"\n\n\n'
+        '@app.get("/orders/{order_id}")\n'
+        "def get_order(order_id):\n"
+        '    user = requests.get(f"{USERS}/users/{order_id}")\n'
+        '    requests.post(USERS + "/users", json={})\n'
+        '    requests.get(f"{BILLING}/invoices/" + order_id)\n'
+        '    requests.get("https://api.stripe.com/v1/charges")\n'
+        '    requests.get(f"{USERS}/health")\n'
+        "    stub = ProfilesStub(channel)\n"
+        "    stub.GetProfile(request)\n"
+        "    return stub.GetProfile(other)\n"
+    ),
+    "services/billing/package.json": "{}\n",
+    "services/billing/index.js": (
+        "app.get('/invoices/:id', (req, res) => res.json({}));\n"
+        "app.get('/health', (req, res) => res.send('ok'));\n"
+        "const order = await fetch(`${ORDERS}/orders/${id}`);\n"
+    ),
+    "protos/profiles.proto": (
+        'syntax = "proto3";\n\n'
+        "service Profiles {\n"
+        "  rpc GetProfile(GetProfileRequest) returns (Profile);\n"
+        "  rpc UpdateProfile(UpdateProfileRequest) returns (Profile);\n"
+        "}\n"
+    ),
+}
+
+
+@pytest.fixture
+def flows(tmp_path):
+    root = tmp_path / "shop"
+    for name, text in FILES.items():
+        (root / name).parent.mkdir(parents=True, exist_ok=True)
+        (root / name).write_text(text)
+    return build_flows(load_sources(root), paths=sorted(FILES))
+
+
+def test_routes_by_framework():
+    spring = (
+        '@RestController\n@RequestMapping("/api/users")\npublic class UserController {\n'
+        '    @GetMapping("/{id}")\n    public User get() {}\n'
+        '    @RequestMapping(value = "", method = RequestMethod.POST)\n    public void add() {}\n'
+        "}\n"
+    )
+    routes, _ = find_routes("UserController.java", spring, "users")
+    assert [r.label for r in routes] == ["GET /api/users/{id}", "POST /api/users"]
+
+    flask = '@bp.route("/items/<int:item_id>", methods=["GET", "DELETE"])\ndef item(): ...\n'
+    routes, _ = find_routes("views.py", flask, "items")
+    assert [r.label for r in routes] == ["GET /items/<int:item_id>", "DELETE /items/<int:item_id>"]
+
+    # A client call with no handler argument is not a route
+    routes, _ = find_routes("client.js", "api.get('/users/1');\n", "web")
+    assert routes == []
+
+
+def test_match_call_prefers_specific_routes():
+    endpoints = [
+        Endpoint("users", "http", "GET", "/users/{id}", "u.go", 1),
+        Endpoint("users", "http", "GET", "/users/me", "u.go", 2),
+        Endpoint("billing", "http", "ANY", "/{tenant}/{id}", "b.js", 1),
+    ]
+    assert [e.path for e in match_call("/users/me", "GET", endpoints, "web")] == ["/users/me"]
+    assert [e.path for e in match_call("/users/", None, endpoints, "web")] == ["/users/{id}"]
+    assert match_call("/users/me", "POST", endpoints, "web") == []
+    assert match_call("/users/me", "GET", endpoints, "users") == []  # its own routes
+
+
+def test_calls_matched_to_other_services(flows):
+    edges = {(e.source, e.target, e.protocol): e for e in flows.edges()}
+
+    orders_users = edges[("orders", "users", "http")]
+    assert orders_users.calls == 3
+    assert dict(orders_users.endpoints) == {
+        "GET /users/{id}": 1,
+        "POST /users": 1,
+        "GET /health": 1,  # the host picks users over billing's /health
+    }
+    assert edges[("orders", "billing", "http")].endpoints == {"GET /invoices/:id": 1}
+    assert edges[("billing", "orders", "http")].endpoints == {"GET /orders/{order_id}": 1}
+
+    grpc = edges[("orders", "users", "grpc")]
+    assert (grpc.calls, dict(grpc.endpoints)) == (2, {"Profiles/GetProfile": 2})
+    # Stripe is not a service of the repository
+    assert len(flows.calls) == 7 and flows.ambiguous == 0
+
+
+def test_ambiguous_calls_are_not_guessed(flows):
+    call = match_call("/health", None, flows.endpoints, "orders")
+    assert {e.container for e in call} == {"users", "billing"}
+
+
+def test_render(flows):
+    mermaid = render(flows, "mermaid")
+    assert mermaid.startswith("flowchart LR\n")
+    assert (
+        '    orders -->|"3 HTTP calls<br/>GET /health<br/>GET /users/{id}<br/>POST /users"| users'
+        in mermaid
+    )
+    assert '    orders -->|"2 gRPC calls<br/>Profiles/GetProfile"| users' in mermaid
+
+    dot = render(flows, "dot")
+    assert 'billing -> orders [label="1 HTTP call\\nGET /orders/{order_id}"];' in dot
+
+    with pytest.raises(ValueError):
+        render(flows, "svg")
@@ -0,0 +1,104 @@
+"""Tests for the ports-and-adapters conformance check."""
+
+import pytest
+
+from shannon_insight.architecture.hexagonal import (
+    HexagonalViolation,
+    check_hexagonal,
+    resolve_spec,
+    to_findings,
+)
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.persistence.identity import compute_identity_key
+
+TABLE = {
+    "domain": ["internal/domain"],
+    "ports": ["internal/*/port"],
+    "adapters": ["internal/adapters/", "cmd"],
+}
+
+ADJACENCY = {
+    "internal/domain/order.go": [
+        "internal/domain/money.go",
+        "internal/adapters/postgres/db.go",
+        "pkg/log/log.go",
+    ],
+    "internal/billing/port/repo.go": ["internal/domain/order.go"],
+    "internal/adapters/postgres/db.go": [
+        "internal/billing/port/repo.go",
+        "internal/domain/order.go",
+    ],
+    "cmd/main.go": ["internal/adapters/postgres/db.go"],
+    "pkg/log/log.go": ["internal/domain/money.go"],
+}
+
+
+def _edges(report):
+    return [(v.source, v.target, v.rule) for v in report.violations]
+
+
+def test_rings():
+    spec = resolve_spec(TABLE)
+
+    assert spec.ring_of("internal/domain/order.go") == "domain"
+    assert spec.ring_of("internal/billing/port/repo.go") == "ports"
+    assert spec.ring_of("internal/billing/service.go") == "other"
+    assert spec.ring_of("internal/domainx/a.go") == "other"
+    assert spec.ring_of("cmd/main.go") == "adapters"
+
+
+def test_default_rules():
+    report = check_hexagonal(resolve_spec(TABLE), ADJACENCY)
+
+    assert _edges(report) == [
+        ("internal/adapters/postgres/db.go", "internal/domain/order.go", "adapters -> domain"),
+        ("internal/domain/order.go", "internal/adapters/postgres/db.go", "domain -> adapters"),
+        ("internal/domain/order.go", "pkg/log/log.go", "domain -> other"),
+    ]
+    assert report.counts() == {"domain": 2, "ports": 1, "adapters": 2}
+    assert "pkg/log/log.go" not in report.rings
+
+
+def test_allow_replaces_a_rings_rules():
+    spec = resolve_spec({**TABLE, "allow": {"adapters": ["ports", "adapters", "domain"]}})
+
+    assert [rule for _, _, rule in _edges(check_hexagonal(spec, ADJACENCY))] == [
+        "domain -> adapters",
+        "domain -> other",
+    ]
+
+
+def test_findings_one_per_edge():
+    violations = [
+        HexagonalViolation("d/a.go", "x/b.go", "domain", "adapters"),
+        HexagonalViolation("d/a.go", "x/c.go", "domain", "adapters"),
+    ]
+
+    first, second = to_findings(violations)
+
+    assert first.title == "Domain file d/a.go imports adapters file x/b.go"
+    assert (first.severity, first.files, first.scope) == (0.8, ["d/a.go", "x/b.go"], "FILE_PAIR")
+    assert first.evidence[0].description == "domain -> adapters"
+    assert compute_identity_key(
+        first.finding_type, first.files, hint=first.identity_hint
+    ) != compute_identity_key(second.finding_type, second.files, hint=second.identity_hint)
+
+
+def test_empty_table_disables_the_check():
+    assert resolve_spec({}) is None
+    assert resolve_spec({"domain": []}) is None
+
+
+@pytest.mark.parametrize(
+    "table, message",
+    [
+        ({"core": ["x"]}, "unknown keys"),
+        ({"domain": "internal/domain"}, "list of path patterns"),
+        ({"domain": ["d"], "allow": {"infra": ["domain"]}}, "unknown ring"),
+        ({"domain": ["d"], "allow": {"domain": ["infra"]}}, "must list rings"),
+        ({"allow": {"domain": ["domain"]}}, "needs at least one"),
+    ],
+)
+def test_invalid_tables(table, message):
+    with pytest.raises(ValueError, match=message):
+        AnalysisConfig(hexagonal=table)
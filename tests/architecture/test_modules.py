@@ -99,3 +99,24 @@ class TestDetectModules:
         modules = detect_modules(file_paths, root_dir="")
         assert modules["src/graph"].file_count == 3
         assert len(modules["src/graph"].files) == 3
+
+    def test_sbt_subprojects_are_modules(self, tmp_path):
+        (tmp_path / "build.sbt").write_text(
+            "lazy val core = project\n"
+            'lazy val api = (project in file("modules/api")).dependsOn(core)\n'
+        )
+        file_paths = [
+            "core/src/main/scala/com/acme/model/User.scala",
+            "core/src/main/scala/com/acme/util/Strings.scala",
+            "modules/api/src/main/scala/com/acme/api/Routes.scala",
+            "project/Dependencies.scala",
+        ]
+        modules = detect_modules(file_paths, root_dir=str(tmp_path))
+        assert set(modules) == {"core", "modules/api", "."}
+        assert modules["core"].file_count == 2
+
+    def test_single_sbt_project_uses_directories(self, tmp_path):
+        (tmp_path / "build.sbt").write_text('lazy val root = project.in(file("."))\n')
+        file_paths = ["src/graph/a.scala", "src/graph/b.scala", "src/model/c.scala"]
+        modules = detect_modules(file_paths, root_dir=str(tmp_path))
+        assert "src/graph" in modules
@@ -0,0 +1,126 @@
+"""Tests for line-oriented editor output formats."""
+
+import re
+
+from shannon_insight.cli._formats import (
+    QUICKFIX_VERSION,
+    editor_severity,
+    format_editor,
+    format_quickfix,
+    format_vscode,
+    quickfix_document,
+    sarif_document,
+)
+from shannon_insight.insights.models import Finding, Patch
+
+# Same pattern documented for the VS Code problem matcher
+VSCODE_PATTERN = re.compile(r"^(.*):(\d+):(\d+):\s+(error|warning|info):\s+(.*)$")
+
+
+def _finding(files, severity=0.85, finding_type="god_file", title="engine.py is a god file"):
+    return Finding(
+        finding_type=finding_type,
+        severity=severity,
+        title=title,
+        files=files,
+        evidence=[],
+        suggestion="",
+    )
+
+
+class TestEditorFormat:
+    def test_single_file_finding(self):
+        lines = format_editor([_finding(["src/engine.py"])])
+        assert lines == ["src/engine.py:1:1: engine.py is a god file [god_file]"]
+
+    def test_pair_finding_emits_line_per_file(self):
+        f = _finding(["a.py", "b.py"], finding_type="hidden_coupling", title="a and b co-change")
+        lines = format_editor([f])
+        assert len(lines) == 2
+        assert lines[0].startswith("a.py:1:1: ")
+        assert "(with b.py)" in lines[0]
+        assert "(with a.py)" in lines[1]
+
+    def test_line_resolver_used(self):
+        lines = format_editor([_finding(["x.py"])], resolve_line=lambda f, p: 42)
+        assert lines[0].startswith("x.py:42:1: ")
+
+    def test_multiline_title_collapsed(self):
+        lines = format_editor([_finding(["x.py"], title="first\nsecond")])
+        assert "\n" not in lines[0]
+
+    def test_empty(self):
+        assert format_editor([]) == []
+
+
+class TestVSCodeFormat:
+    def test_matches_problem_matcher(self):
+        lines = format_vscode([_finding(["src/engine.py"], severity=0.5)])
+        m = VSCODE_PATTERN.match(lines[0])
+        assert m is not None
+        assert m.group(1) == "src/engine.py"
+        assert m.group(4) == "warning"
+
+    def test_severity_levels(self):
+        assert editor_severity(0.9) == "error"
+        assert editor_severity(0.8) == "error"
+        assert editor_severity(0.5) == "warning"
+        assert editor_severity(0.1) == "info"
+
+
+class TestQuickfixFormat:
+    # Vim errorformat %f:%l:%c:%t:%m
+    ERRORFORMAT = re.compile(r"^([^:]+):(\d+):(\d+):([EWI]):(.*)$")
+
+    def test_matches_errorformat(self):
+        lines = format_quickfix([_finding(["src/engine.py"], severity=0.9)])
+        m = self.ERRORFORMAT.match(lines[0])
+        assert m is not None
+        assert m.groups()[:4] == ("src/engine.py", "1", "1", "E")
+        assert m.group(5) == "engine.py is a god file [god_file]"
+
+    def test_document_is_versioned(self):
+        doc = quickfix_document([_finding(["a.py"], severity=0.5)])
+        assert doc["version"] == QUICKFIX_VERSION
+        [item] = doc["items"]
+        # setqflist() field names are part of the stable contract
+        for key in ("filename", "lnum", "col", "type", "text"):
+            assert key in item
+        assert item["type"] == "W"
+
+
+class TestSarifDocument:
+    def test_rules_results_and_levels(self):
+        findings = [
+            _finding(["src/engine.py"]),
+            _finding(["a.py", "b.py"], 0.3, "hidden_coupling", "a and b co-change"),
+        ]
+        doc = sarif_document(findings, "1.2.3", resolve_line=lambda f, p: 7)
+        (run,) = doc["runs"]
+
+        assert doc["version"] == "2.1.0"
+        assert run["tool"]["driver"]["version"] == "1.2.3"
+        assert [r["id"] for r in run["tool"]["driver"]["rules"]] == ["god_file", "hidden_coupling"]
+        first, second = run["results"]
+        assert (first["ruleId"], first["ruleIndex"], first["level"]) == ("god_file", 0, "error")
+        assert second["level"] == "note"
+        uris = [loc["physicalLocation"]["artifactLocation"]["uri"] for loc in second["locations"]]
+        assert uris == ["a.py", "b.py"]
+        assert first["locations"][0]["physicalLocation"]["region"] == {"startLine": 7}
+        assert "fixes" not in first
+
+    def test_patches_become_fixes(self):
+        finding = _finding(["store/store.go"], 0.3, "error_hygiene_issue", "err is not wrapped")
+        finding.patches = [Patch("store/store.go", 11, 11, '\treturn fmt.Errorf("x: %w", err)')]
+        (result,) = sarif_document([finding], "1.2.3")["runs"][0]["results"]
+
+        (change,) = result["fixes"][0]["artifactChanges"]
+        assert change["artifactLocation"] == {"uri": "store/store.go"}
+        (replacement,) = change["replacements"]
+        assert replacement["deletedRegion"] == {
+            "startLine": 11,
+            "startColumn": 1,
+            "endLine": 12,
+            "endColumn": 1,
+        }
+        assert replacement["insertedContent"] == {"text": '\treturn fmt.Errorf("x: %w", err)\n'}
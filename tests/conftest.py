@@ -1,8 +1,19 @@
-"""Shared test fixtures for Shannon Insight math tests."""
+"""Shared test fixtures for Shannon Insight math tests, and syntax factories."""
+
+import subprocess
+from collections.abc import Sequence
+from pathlib import Path
+from typing import Optional
 
 import numpy as np
 import pytest
 
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.hcl import annotate_hcl
+from shannon_insight.scanning.sql import annotate_sql
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, FunctionDef, ImportDecl
+from shannon_insight.scanning.yaml import annotate_yaml
+
 
 def pytest_addoption(parser):
     """Add --run-slow option for slow tests."""
@@ -29,6 +40,55 @@ def pytest_collection_modifyitems(config, items):
             item.add_marker(skip_slow)
 
 
+@pytest.fixture
+def write_files(tmp_path):
+    """Writer of {relative path: text} under tmp_path; it returns tmp_path."""
+
+    def write(files):
+        for name, text in files.items():
+            path = tmp_path / name
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_text(text)
+        return tmp_path
+
+    return write
+
+
+class GitRepo:
+    """Git repository under a test's tmp_path."""
+
+    def __init__(self, root: Path) -> None:
+        self.root = root
+
+    def git(self, *args: str, author: str = "t") -> str:
+        """Run git in the repository as *author*; its stripped stdout."""
+        identity = ["-c", f"user.name={author}", "-c", "user.email=t@t"]
+        return subprocess.run(
+            ["git", "-C", str(self.root), *identity, *args],
+            check=True,
+            capture_output=True,
+            text=True,
+        ).stdout.strip()
+
+    def commit(self, files: dict[str, str], message: str = "change", author: str = "t") -> str:
+        """Write and commit *files*; the new HEAD sha."""
+        for name, text in files.items():
+            path = self.root / name
+            path.parent.mkdir(parents=True, exist_ok=True)
+            path.write_text(text)
+        self.git("add", "--", *files)
+        self.git("commit", "-qm", message, author=author)
+        return self.git("rev-parse", "HEAD")
+
+
+@pytest.fixture
+def git_repo(tmp_path):
+    """Empty git repository in tmp_path, on branch main."""
+    repo = GitRepo(tmp_path)
+    repo.git("init", "-q", "-b", "main")
+    return repo
+
+
 @pytest.fixture
 def uniform_distribution():
     """Uniform distribution over 4 events."""
@@ -116,3 +176,59 @@ def constant_values():
 def identity_cov_3d():
     """3x3 identity covariance matrix."""
     return np.eye(3)
+
+
+def make_function(
+    name: str = "f",
+    *,
+    start_line: int = 1,
+    end_line: Optional[int] = None,
+    params: Sequence[str] = (),
+    calls: Optional[Sequence[str]] = None,
+    decorators: Sequence[str] = (),
+    nesting_depth: int = 1,
+    body_tokens: int = 10,
+    signature_tokens: int = 2,
+    cell: Optional[int] = None,
+) -> FunctionDef:
+    """FunctionDef for testing; it spans four lines unless *end_line* is given.
+
+    *calls* are its call targets; None (the default) is a function parsed
+    without them, as by the regex fallback.
+    """
+    return FunctionDef(
+        name=name,
+        params=list(params),
+        body_tokens=body_tokens,
+        signature_tokens=signature_tokens,
+        nesting_depth=nesting_depth,
+        start_line=start_line,
+        end_line=start_line + 3 if end_line is None else end_line,
+        call_targets=None if calls is None else list(calls),
+        decorators=list(decorators),
+        cell=cell,
+    )
+
+
+def make_syntax(
+    path: str,
+    functions: Sequence[FunctionDef] = (),
+    classes: Sequence[ClassDef] = (),
+    imports: Sequence[ImportDecl] = (),
+    language: str = "python",
+) -> FileSyntax:
+    """FileSyntax for testing."""
+    return FileSyntax(path, list(functions), list(classes), list(imports), language)
+
+
+_ANNOTATE = {"hcl": annotate_hcl, "sql": annotate_sql, "yaml": annotate_yaml}
+
+
+def make_sources(language: str, **files: str) -> tuple[dict[str, FileSyntax], dict[str, str]]:
+    """(syntax, contents) of *files*, regex-parsed and annotated as *language*."""
+    syntax, contents = {}, {}
+    for path, content in files.items():
+        parsed = RegexFallbackScanner().parse(content, path, language)
+        _ANNOTATE[language](parsed, content)
+        syntax[path], contents[path] = parsed, content
+    return syntax, contents
@@ -0,0 +1,89 @@
+"""Tests for dependency manifests and freshness."""
+
+import json
+
+from shannon_insight.diligence.dependencies import (
+    CURRENT,
+    MAJOR_BEHIND,
+    OUTDATED,
+    UNKNOWN,
+    UNPINNED,
+    Dependency,
+    check_freshness,
+    find_dependencies,
+)
+
+
+def _write_manifests(root):
+    (root / "requirements.txt").write_text(
+        "# runtime\nrequests>=2.28,<3\nnumpy==1.24.0 ; python_version>'3.8'\n"
+        "-r other.txt\nlocal @ file:///src/local\nflask\n"
+    )
+    (root / "requirements-dev.txt").write_text("pytest~=7.0\n")
+    (root / "web").mkdir()
+    (root / "web" / "package.json").write_text(
+        json.dumps(
+            {
+                "dependencies": {"react": "^17.0.2", "mine": "file:../mine"},
+                "devDependencies": {"jest": "~29.1.0"},
+            }
+        )
+    )
+    (root / "svc").mkdir()
+    (root / "svc" / "go.mod").write_text(
+        "module example.com/svc\n\ngo 1.21\n\nrequire github.com/pkg/errors v0.9.1\n"
+        "require (\n\tgolang.org/x/sync v0.3.0\n\tgolang.org/x/sys v0.10.0 // indirect\n)\n"
+    )
+
+
+class TestFindDependencies:
+    def test_manifests(self, tmp_path):
+        _write_manifests(tmp_path)
+
+        found = {(d.ecosystem, d.name): d for d in find_dependencies(tmp_path)}
+
+        assert set(found) == {
+            ("pypi", "requests"),
+            ("pypi", "numpy"),
+            ("pypi", "flask"),
+            ("pypi", "pytest"),
+            ("npm", "react"),
+            ("npm", "jest"),
+            ("go", "github.com/pkg/errors"),
+            ("go", "golang.org/x/sync"),
+        }
+        assert found[("pypi", "numpy")].spec == "==1.24.0"
+        assert found[("pypi", "pytest")].dev
+        assert found[("npm", "jest")].dev
+        assert found[("npm", "react")].manifest == "web/package.json"
+
+    def test_declared_version(self):
+        assert Dependency("a", "pypi", ">=2.28,<3", "r.txt").declared == "2.28"
+        assert Dependency("a", "pypi", "", "r.txt").declared is None
+        assert Dependency("a", "pypi", "<3", "r.txt").declared is None
+        assert Dependency("a", "npm", "^17.0.2", "p.json").declared == "17.0.2"
+        assert Dependency("a", "go", "v0.9.1", "go.mod").declared == "0.9.1"
+
+
+class TestCheckFreshness:
+    def test_statuses(self):
+        latest = {"a": "2.5.0", "b": "3.0.0", "c": "0.4.0", "d": "1.0.0", "e": None}
+        dependencies = [
+            Dependency("a", "pypi", ">=2.5", "r.txt"),
+            Dependency("b", "pypi", "==2.9.0", "r.txt"),
+            Dependency("c", "npm", "^0.3.1", "p.json"),
+            Dependency("d", "npm", "*", "p.json"),
+            Dependency("e", "npm", "1.0.0", "p.json"),
+            Dependency("a", "pypi", "==2.4", "other/r.txt"),
+        ]
+        calls = []
+
+        def lookup(ecosystem, name):
+            calls.append(name)
+            return latest[name]
+
+        statuses = [f.status for f in check_freshness(dependencies, lookup=lookup)]
+
+        assert statuses == [CURRENT, MAJOR_BEHIND, MAJOR_BEHIND, UNPINNED, UNKNOWN, OUTDATED]
+        # Each package is looked up once
+        assert sorted(calls) == ["a", "b", "c", "d", "e"]
@@ -0,0 +1,84 @@
+"""Tests for license identification and the license scan."""
+
+from shannon_insight.diligence.inventory import source_inventory
+from shannon_insight.diligence.licenses import (
+    PERMISSIVE,
+    STRONG_COPYLEFT,
+    UNKNOWN,
+    WEAK_COPYLEFT,
+    identify_license,
+    license_category,
+    scan_licenses,
+)
+
+_MIT = """MIT License
+
+Copyright (c) 2024 Acme
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+"""
+
+_GPL3 = """                    GNU GENERAL PUBLIC LICENSE
+                       Version 3, 29 June 2007
+
+ Everyone is permitted to copy and distribute verbatim copies ... the GNU
+ Lesser General Public License ...
+"""
+
+_LGPL21 = """                  GNU LESSER GENERAL PUBLIC LICENSE
+                       Version 2.1, February 1999
+"""
+
+
+class TestIdentifyLicense:
+    def test_common_licenses(self):
+        assert identify_license(_MIT) == "MIT"
+        assert identify_license(_GPL3) == "GPL-3.0"
+        assert identify_license(_LGPL21) == "LGPL-2.1"
+        assert identify_license("Apache License\nVersion 2.0, January 2004") == "Apache-2.0"
+
+    def test_bsd_variants(self):
+        two = "Redistribution and use in source and binary forms, with or without"
+        three = two + "\n3. Neither the name of the copyright holder"
+        assert identify_license(two) == "BSD-2-Clause"
+        assert identify_license(three) == "BSD-3-Clause"
+
+    def test_unrecognized(self):
+        assert identify_license("All rights reserved.") is None
+
+
+class TestLicenseCategory:
+    def test_single_ids(self):
+        assert license_category("MIT") == PERMISSIVE
+        assert license_category("GPL-2.0-only") == STRONG_COPYLEFT
+        assert license_category("MPL-2.0") == WEAK_COPYLEFT
+        assert license_category("Proprietary") == UNKNOWN
+        assert license_category(None) == UNKNOWN
+
+    def test_expressions(self):
+        assert license_category("MIT OR GPL-3.0") == PERMISSIVE
+        assert license_category("MIT AND GPL-3.0") == STRONG_COPYLEFT
+        assert license_category("GPL-2.0 WITH Classpath-exception-2.0") == STRONG_COPYLEFT
+
+
+class TestScanLicenses:
+    def test_project_vendored_and_spdx(self, tmp_path):
+        (tmp_path / "LICENSE").write_text(_MIT)
+        (tmp_path / "vendor" / "lib").mkdir(parents=True)
+        (tmp_path / "vendor" / "lib" / "COPYING").write_text(_GPL3)
+        (tmp_path / "src").mkdir()
+        (tmp_path / "src" / "a.c").write_text("/* SPDX-License-Identifier: LGPL-2.1 */\nint a;\n")
+        (tmp_path / "src" / "b.py").write_text("# SPDX-License-Identifier: MIT\nb = 1\n")
+
+        scan = scan_licenses(tmp_path, source_inventory(tmp_path))
+
+        assert scan.project == ["MIT"]
+        assert {f.path: f.license for f in scan.files} == {
+            "LICENSE": "MIT",
+            "vendor/lib/COPYING": "GPL-3.0",
+        }
+        assert scan.spdx == {"LGPL-2.1": 1, "MIT": 1}
+        assert sorted(scan.copyleft) == [
+            ("src/a.c", "LGPL-2.1"),
+            ("vendor/lib/COPYING", "GPL-3.0"),
+        ]
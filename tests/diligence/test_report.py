@@ -0,0 +1,66 @@
+"""Tests for the due-diligence report."""
+
+from shannon_insight.diligence import build_report
+from shannon_insight.persistence.models import TensorSnapshot
+
+
+def _repo(root):
+    (root / "LICENSE").write_text("Permission is hereby granted, free of charge, to any person")
+    (root / "app").mkdir()
+    (root / "app" / "core.py").write_text("x = 1\n" * 80)
+    (root / "app" / "util.py").write_text("y = 2\n" * 20)
+    (root / "tests").mkdir()
+    (root / "tests" / "test_core.py").write_text("def test():\n    pass\n" * 5)
+    (root / "requirements.txt").write_text("requests==1.0\n")
+
+
+def _snapshot(commits=10):
+    return TensorSnapshot(
+        commit_sha="0123456789abcdef",
+        commits_analyzed=commits,
+        file_signals={
+            "app/core.py": {"lines": 80, "cognitive_load": 9.0, "max_nesting": 6, "bus_factor": 1},
+            "app/util.py": {"lines": 20, "cognitive_load": 1.0, "max_nesting": 1, "bus_factor": 3},
+        },
+        module_signals={"app": {"module_bus_factor": 1.0}},
+        global_signals={"codebase_health": 0.72, "team_size": 3},
+    )
+
+
+class TestBuildReport:
+    def test_sections(self, tmp_path):
+        _repo(tmp_path)
+
+        report = build_report(
+            tmp_path, _snapshot(), ["God file"], lookup=lambda ecosystem, name: "2.31.0"
+        )
+
+        assert (report.code_files, report.code_lines) == (2, 100)
+        assert (report.test_files, report.test_lines) == (1, 10)
+        assert [(s.language, s.lines) for s in report.languages] == [("python", 110)]
+        assert report.cognitive_load == {"p50": 1.0, "p90": 9.0, "max": 9.0}
+        assert report.deeply_nested == 1
+        assert report.most_complex[0] == ("app/core.py", 9.0)
+        assert report.single_owner_share == 0.8
+        assert report.single_owner_modules == ["app"]
+        assert report.licenses.project == ["MIT"]
+        assert [f.status for f in report.dependencies] == ["major behind"]
+        assert report.health_label == "Moderate"
+
+        risks = report.key_risks()
+        assert any("single author" in r for r in risks)
+        assert any("test lines per line" in r for r in risks)
+        assert any("major version" in r for r in risks)
+
+    def test_offline_without_history(self, tmp_path):
+        _repo(tmp_path)
+
+        report = build_report(tmp_path, _snapshot(commits=0), [], offline=True)
+
+        assert not report.has_history
+        assert not report.freshness_checked
+        assert [f.status for f in report.dependencies] == ["unknown"]
+        markdown = report.render_markdown()
+        assert "Unavailable: no git history" in markdown
+        assert "Registries were not consulted (offline)" in markdown
+        assert report.to_dict()["bus_factor"]["has_history"] is False
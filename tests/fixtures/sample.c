@@ -4,11 +4,30 @@
 #include <stdlib.h>
 #include <string.h>
 
+#ifdef __cplusplus
+extern "C" {
+#endif
+
 /* Struct for greeter */
 struct Greeter {
     char prefix[64];
 };
 
+typedef struct {
+    int count;
+    int total;
+} Stats;
+
+#ifdef _WIN32
+static const char *line_end(void) {
+    return "\r\n";
+}
+#else
+static const char *line_end(void) {
+    return "\n";
+}
+#endif
+
 /* Initialize greeter */
 void greeter_init(struct Greeter *g, const char *prefix) {
     strncpy(g->prefix, prefix, sizeof(g->prefix) - 1);
@@ -44,3 +63,7 @@ int main(int argc, char *argv[]) {
     greeter_greet(&g, "World");
     return 0;
 }
+
+#ifdef __cplusplus
+}
+#endif
@@ -0,0 +1,47 @@
+// Sample Kotlin file for testing tree-sitter parsing.
+
+package com.example.sync
+
+import kotlinx.coroutines.CoroutineScope
+import kotlinx.coroutines.launch
+import com.example.sync.net.ApiClient
+
+interface Repository {
+    suspend fun fetch(id: String): Item?
+}
+
+data class Item(val id: String, val title: String)
+
+abstract class BaseRepository(protected val client: ApiClient) : Repository {
+    abstract fun cacheKey(id: String): String
+}
+
+class ItemRepository(client: ApiClient) : BaseRepository(client) {
+    private val cache = mutableMapOf<String, Item>()
+
+    override suspend fun fetch(id: String): Item? {
+        val key = cacheKey(id)
+        if (cache.containsKey(key)) {
+            return cache[key]
+        }
+        val item = client.get(id) ?: return null
+        cache[key] = item
+        return item
+    }
+
+    override fun cacheKey(id: String) = "item:$id"
+}
+
+object SyncScheduler {
+    fun start(scope: CoroutineScope, repo: Repository, ids: List<String>) {
+        for (id in ids) {
+            scope.launch {
+                repo.fetch(id)
+            }
+        }
+    }
+}
+
+fun String.toItemId(): String = trim().lowercase()
+
+fun <T> List<T>.secondOrNull(): T? = if (size > 1) this[1] else null
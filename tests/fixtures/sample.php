@@ -0,0 +1,51 @@
+<?php
+// Sample PHP file for testing tree-sitter parsing.
+
+namespace App\Services;
+
+use App\Models\User;
+use Psr\Log\LoggerInterface as Logger;
+
+interface Greeter
+{
+    public function greet(string $name): string;
+}
+
+trait Politeness
+{
+    protected function please(string $text): string
+    {
+        return $text . ', please';
+    }
+}
+
+final class HelloGreeter implements Greeter
+{
+    use Politeness;
+
+    private string $prefix;
+
+    public function __construct(private Logger $logger, string $prefix = 'Hello')
+    {
+        $this->prefix = $prefix;
+    }
+
+    public function greet(string $name): string
+    {
+        $this->logger->info("greeting {$name}");
+        return sprintf('%s, %s!', $this->prefix, $name);
+    }
+}
+
+function process_data(array $items): array
+{
+    $result = [];
+    foreach ($items as $item) {
+        if ($item instanceof User && $item->active) {
+            $result[] = $item;
+        } elseif ($item === null) {
+            continue;
+        }
+    }
+    return $result;
+}
@@ -0,0 +1,42 @@
+// Sample Scala file for testing tree-sitter parsing.
+
+package com.example.sync
+
+import scala.concurrent.{ExecutionContext, Future}
+import com.example.sync.net.{ApiClient, Response => Reply}
+
+trait Repository {
+  def fetch(id: String): Future[Option[Item]]
+}
+
+case class Item(id: String, title: String)
+
+abstract class BaseRepository(protected val client: ApiClient) extends Repository {
+  def cacheKey(id: String): String
+}
+
+class ItemRepository(client: ApiClient)(implicit ec: ExecutionContext)
+    extends BaseRepository(client) {
+  private val cache = scala.collection.mutable.Map.empty[String, Item]
+
+  override def fetch(id: String): Future[Option[Item]] = {
+    val key = cacheKey(id)
+    if (cache.contains(key)) {
+      Future.successful(cache.get(key))
+    } else {
+      client.get(id).map { item =>
+        cache(key) = item
+        Some(item)
+      }
+    }
+  }
+
+  override def cacheKey(id: String): String = s"item:$id"
+}
+
+object SyncScheduler {
+  def start(repo: Repository, ids: Seq[String])(implicit ec: ExecutionContext): Unit =
+    ids.foreach { id =>
+      repo.fetch(id)
+    }
+}
@@ -0,0 +1,46 @@
+// Sample Swift file for testing tree-sitter parsing.
+
+import Foundation
+import struct SyncNet.ApiClient
+
+protocol Repository {
+    func fetch(id: String) async throws -> Item?
+}
+
+struct Item: Codable, Hashable {
+    let id: String
+    var title: String
+}
+
+final class ItemRepository: Repository {
+    private let client: ApiClient
+    private var cache: [String: Item] = [:]
+
+    init(client: ApiClient) {
+        self.client = client
+    }
+
+    func fetch(id: String) async throws -> Item? {
+        let key = cacheKey(for: id)
+        if let cached = cache[key] {
+            return cached
+        }
+        let item = try await client.get(id)
+        cache[key] = item
+        return item
+    }
+}
+
+extension ItemRepository {
+    func cacheKey(for id: String) -> String {
+        "item:\(id)"
+    }
+}
+
+enum SyncScheduler {
+    static func start(repo: Repository, ids: [String]) {
+        for id in ids {
+            Task { _ = try? await repo.fetch(id: id) }
+        }
+    }
+}
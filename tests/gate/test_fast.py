@@ -0,0 +1,79 @@
+"""Tests for the diff-only fast gate (gate --fast)."""
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.gate.fast import changed_files, run_fast_gate
+
+_NESTED = """def f(x):
+    if x:
+        for i in x:
+            if i:
+                while i:
+                    i -= 1
+    return x
+"""
+
+
+@pytest.fixture
+def repo(git_repo):
+    git_repo.commit({"a.py": "x = 1\n", "b.py": "y = 2\n", "notes.txt": "hi\n"})
+    git_repo.git("checkout", "-qb", "feature")
+    return git_repo
+
+
+def _settings(**kwargs):
+    return AnalysisConfig(ratchet_metrics=["cognitive_load", "pagerank"], **kwargs)
+
+
+class TestChangedFiles:
+    def test_since_merge_base(self, repo):
+        repo.commit({"a.py": "x = 2\n", "notes.txt": "bye\n"})
+        (repo.root / "b.py").unlink()
+        repo.git("commit", "-qam", "remove b")
+
+        assert changed_files(repo.root, "main") == ["a.py", "notes.txt"]
+
+    def test_relative_to_subdirectory_root(self, repo):
+        repo.commit({"a.py": "x = 2\n", "pkg/c.py": "z = 1\n"})
+
+        assert changed_files(repo.root / "pkg", "main") == ["c.py"]
+
+    def test_unknown_base(self, repo):
+        assert changed_files(repo.root, "no-such-branch") is None
+
+
+class TestRunFastGate:
+    def test_checks_only_changed_files(self, repo):
+        repo.commit({"a.py": _NESTED, "notes.txt": "bye\n"})
+        ceilings = {"a.py": {"cognitive_load": 1.0}, "b.py": {"cognitive_load": 0.0}}
+
+        result = run_fast_gate(repo.root, _settings(), ceilings, "main", budget_seconds=30)
+
+        assert result.complete and not result.passed
+        violations = [(v.path, v.metric) for v in result.ratchet.violations]
+        assert violations == [("a.py", "cognitive_load")]
+        assert result.skipped_metrics == ["pagerank"]
+
+    def test_new_file_is_added_not_violation(self, repo):
+        repo.commit({"c.py": _NESTED})
+
+        result = run_fast_gate(repo.root, _settings(), {}, "main", budget_seconds=30)
+
+        assert result.passed
+        assert result.ratchet.added == ["c.py"]
+
+    def test_budget_exhausted_is_incomplete(self, repo):
+        repo.commit({"a.py": "x = 3\n", "c.py": "z = 1\n"})
+        ticks = iter(range(100))
+
+        result = run_fast_gate(
+            repo.root, _settings(), {}, "main", budget_seconds=2, clock=lambda: next(ticks)
+        )
+
+        assert not result.complete and not result.passed
+        assert result.unchecked == ["c.py"]
+
+    def test_bad_base_raises(self, repo):
+        with pytest.raises(ValueError):
+            run_fast_gate(repo.root, _settings(), {}, "nope", budget_seconds=30)
@@ -0,0 +1,78 @@
+"""Tests for per-file metric ceilings (gate --ratchet)."""
+
+import json
+
+import pytest
+
+from shannon_insight.gate.ratchet import (
+    apply_ratchet,
+    current_values,
+    load_ceilings,
+    save_ceilings,
+)
+
+_METRICS = ["cognitive_load", "max_nesting"]
+
+
+class TestCurrentValues:
+    def test_picks_numeric_metrics(self):
+        signals = {
+            "a.py": {"cognitive_load": 4.123456, "max_nesting": 2, "role": "MODEL"},
+            "b.py": {"role": "TEST"},
+        }
+
+        assert current_values(signals, _METRICS + ["role"]) == {
+            "a.py": {"cognitive_load": 4.1235, "max_nesting": 2.0}
+        }
+
+
+class TestApplyRatchet:
+    def test_violation_only_above_own_ceiling(self):
+        ceilings = {"a.py": {"cognitive_load": 10.0}, "b.py": {"cognitive_load": 2.0}}
+        current = {"a.py": {"cognitive_load": 9.0}, "b.py": {"cognitive_load": 3.0}}
+
+        result = apply_ratchet(ceilings, current)
+
+        assert not result.passed
+        assert [(v.path, v.ceiling, v.value) for v in result.violations] == [("b.py", 2.0, 3.0)]
+
+    def test_improvement_tightens(self):
+        result = apply_ratchet({"a.py": {"max_nesting": 5.0}}, {"a.py": {"max_nesting": 3.0}})
+
+        assert result.passed
+        assert result.ceilings == {"a.py": {"max_nesting": 3.0}}
+        assert [(t.old, t.new) for t in result.tightened] == [(5.0, 3.0)]
+
+    def test_added_and_removed_files(self):
+        result = apply_ratchet({"gone.py": {"max_nesting": 1.0}}, {"new.py": {"max_nesting": 7.0}})
+
+        assert result.passed
+        assert result.added == ["new.py"]
+        assert result.removed == ["gone.py"]
+        assert result.ceilings == {"new.py": {"max_nesting": 7.0}}
+
+    def test_unchanged(self):
+        ceilings = {"a.py": {"max_nesting": 2.0}}
+        result = apply_ratchet(ceilings, {"a.py": {"max_nesting": 2.0}})
+
+        assert result.passed
+        assert not result.changed
+
+
+class TestCeilingsFile:
+    def test_roundtrip(self, tmp_path):
+        path = tmp_path / "shannon-ratchet.json"
+        save_ceilings(path, {"b.py": {"max_nesting": 2.0}, "a.py": {"max_nesting": 1.0}}, _METRICS)
+
+        assert list(json.loads(path.read_text())["files"]) == ["a.py", "b.py"]
+        assert load_ceilings(path) == {"a.py": {"max_nesting": 1.0}, "b.py": {"max_nesting": 2.0}}
+
+    def test_missing_file(self, tmp_path):
+        assert load_ceilings(tmp_path / "none.json") is None
+
+    def test_unsupported_version(self, tmp_path):
+        path = tmp_path / "r.json"
+        path.write_text('{"version": 99, "files": {}}')
+
+        with pytest.raises(ValueError):
+            load_ceilings(path)
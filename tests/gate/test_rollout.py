@@ -0,0 +1,105 @@
+"""Tests for warn-only rollout windows of gate rules (gate_rollout)."""
+
+from datetime import datetime, timedelta, timezone
+from types import SimpleNamespace
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.gate.ratchet import apply_ratchet
+from shannon_insight.gate.rollout import (
+    RolloutRule,
+    demote_violations,
+    parse_rollout,
+    record_run,
+    reset_rules,
+    split_findings,
+    warned_rules,
+)
+from shannon_insight.persistence.database import HistoryDB
+
+_T0 = datetime(2026, 10, 1, tzinfo=timezone.utc)
+
+
+@pytest.fixture
+def conn(tmp_path):
+    with HistoryDB(str(tmp_path)) as db:
+        yield db.conn
+
+
+class TestParseRollout:
+    def test_rules(self):
+        rules = parse_rollout({"max_nesting": {"runs": 5}, "deep_nesting": {"days": 14}})
+        assert rules == [RolloutRule("deep_nesting", days=14), RolloutRule("max_nesting", runs=5)]
+
+    @pytest.mark.parametrize(
+        "entry, message",
+        [
+            (7, "must be a table"),
+            ({"weeks": 2}, "unknown keys weeks"),
+            ({"days": -1}, "days must be"),
+            ({"runs": True}, "runs must be"),
+            ({}, "set days and/or runs"),
+        ],
+    )
+    def test_malformed(self, entry, message):
+        with pytest.raises(ValueError, match=message):
+            parse_rollout({"deep_nesting": entry})
+
+    def test_config_validates_table(self):
+        with pytest.raises(ValueError, match="gate_rollout.deep_nesting"):
+            AnalysisConfig(gate_rollout={"deep_nesting": {"days": "soon"}})
+
+
+class TestRecordRun:
+    def test_days_window(self, conn):
+        rule = RolloutRule("deep_nesting", days=14)
+        (first,) = record_run(conn, [rule], now=_T0)
+        assert first.warn_only and first.runs == 1
+        assert first.describe() == "enforced from 2026-10-15"
+
+        (later,) = record_run(conn, [rule], now=_T0 + timedelta(days=14))
+        assert not later.warn_only and later.started == _T0 and later.runs == 2
+        assert later.describe() == "enforced"
+
+    def test_runs_window(self, conn):
+        rule = RolloutRule("max_nesting", runs=3)
+        statuses = [record_run(conn, [rule], now=_T0)[0] for _ in range(3)]
+        assert [s.warn_only for s in statuses] == [True, True, False]
+        assert statuses[0].describe() == "enforced after 2 more run(s)"
+
+    def test_both_set_whichever_ends_later(self, conn):
+        rule = RolloutRule("deep_nesting", days=1, runs=2)
+        record_run(conn, [rule], now=_T0)
+        (status,) = record_run(conn, [rule], now=_T0 + timedelta(hours=1))
+        assert status.runs_left == 0 and status.warn_only
+        assert status.to_dict()["enforced_at"] == (_T0 + timedelta(days=1)).isoformat()
+
+    def test_reset_starts_over(self, conn):
+        rule = RolloutRule("max_nesting", runs=2)
+        record_run(conn, [rule], now=_T0)
+        record_run(conn, [rule], now=_T0)
+
+        assert reset_rules(conn, ["max_nesting", "unknown"]) == 1
+        (status,) = record_run(conn, [rule], now=_T0 + timedelta(days=3))
+        assert status.runs == 1 and status.started == _T0 + timedelta(days=3)
+        assert warned_rules([status]) == {"max_nesting"}
+
+
+class TestApply:
+    def test_split_findings_by_type(self):
+        findings = [SimpleNamespace(finding_type=t) for t in ("deep_nesting", "god_file")]
+        enforced, held_back = split_findings(findings, {"deep_nesting"})
+        assert [f.finding_type for f in enforced] == ["god_file"]
+        assert [f.finding_type for f in held_back] == ["deep_nesting"]
+
+    def test_demoted_violations_keep_their_ceilings(self):
+        ceilings = {"a.py": {"max_nesting": 2.0, "cognitive_load": 5.0}}
+        result = apply_ratchet(ceilings, {"a.py": {"max_nesting": 4.0, "cognitive_load": 6.0}})
+
+        demote_violations(result, {"max_nesting"})
+
+        assert [v.metric for v in result.violations] == ["cognitive_load"]
+        assert [(v.metric, v.value) for v in result.warnings] == [("max_nesting", 4.0)]
+        assert result.ceilings["a.py"]["max_nesting"] == 2.0
+        assert result.to_dict()["warnings"][0]["metric"] == "max_nesting"
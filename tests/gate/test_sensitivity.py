@@ -0,0 +1,132 @@
+"""Tests for what failed gate checks would need to pass."""
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.gate.ratchet import Violation
+from shannon_insight.gate.sensitivity import (
+    _cognitive_load_changes,
+    finding_remedies,
+    markdown_comment,
+    ratchet_remedies,
+)
+from shannon_insight.insights.models import Finding
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+from shannon_insight.signals.complexity import cognitive_load
+
+
+def _syntax(*depths, lines=200, complexity=4.0):
+    functions = [
+        FunctionDef(name, [], 50 + 10 * i, 5, depth, 1 + 20 * i, 20 + 20 * i)
+        for i, (name, depth) in enumerate(depths)
+    ]
+    return FileSyntax("svc.go", functions, [], [], "go", _lines=lines, _complexity=complexity)
+
+
+class _Extractor:
+    def __init__(self, syntax):
+        self.syntax = syntax
+
+    def extract(self, path, root, content):
+        return self.syntax
+
+
+class TestRatchetRemedies:
+    def test_every_function_deeper_than_the_ceiling(self, tmp_path):
+        syntax = _syntax(("CreateUser", 6), ("DeleteUser", 5), ("GetUser", 2))
+
+        [remedy] = ratchet_remedies(
+            tmp_path,
+            [Violation("svc.go", "max_nesting", 4.0, 6.0)],
+            AnalysisConfig(),
+            _Extractor(syntax),
+        )
+
+        assert remedy.excess == 2.0
+        assert remedy.describe() == (
+            "max_nesting 6 exceeds 4 by 2: reduce CreateUser nesting from 6 to ≤4 "
+            "and reduce DeleteUser nesting from 5 to ≤4"
+        )
+
+    def test_cognitive_load_factors_each_pass(self):
+        syntax = _syntax(("CreateUser", 4), ("GetUser", 1))
+        ceiling = round(cognitive_load(syntax, None) * 0.9, 4)
+
+        changes = _cognitive_load_changes(syntax, None, "none", ceiling)
+
+        # impl_gini is already near 0: no value of it alone passes
+        assert [(c.subject, c.current, c.required) for c in changes] == [
+            ("max_nesting", 4, 3),
+            ("mean function complexity", 4.0, 2.59),
+            ("lines", 200, 117),
+        ]
+        for passing in (
+            _syntax(("CreateUser", 3), ("GetUser", 1)),
+            _syntax(("CreateUser", 4), ("GetUser", 1), complexity=2.59),
+            _syntax(("CreateUser", 4), ("GetUser", 1), lines=117),
+        ):
+            assert cognitive_load(passing, None) <= ceiling
+
+    def test_other_metrics_state_the_value_to_reach(self, tmp_path):
+        [remedy] = ratchet_remedies(
+            tmp_path,
+            [Violation("svc.go", "function_count", 12.0, 15.0)],
+            AnalysisConfig(),
+            _Extractor(None),
+        )
+
+        assert [c.describe() for c in remedy.changes] == [
+            "reduce function_count from 15 to ≤12"
+        ]
+
+
+class TestFindingRemedies:
+    def test_findings_over_the_threshold(self):
+        findings = [
+            Finding("high_risk_hub", 0.82, "a.py is a hub", ["a.py"], [], ""),
+            Finding("god_file", 0.5, "b.py is big", ["b.py"], [], ""),
+            Finding("orphan_code", 0.91, "c.py is unused", ["c.py"], [], ""),
+        ]
+
+        high = finding_remedies(findings, "high")
+        every = finding_remedies(findings, "any")
+
+        assert [(r.metric, r.excess) for r in high] == [
+            ("orphan_code", 0.91 - 0.7),
+            ("high_risk_hub", 0.82 - 0.7),
+        ]
+        assert high[1].changes[0].describe() == (
+            "reduce high_risk_hub severity from 0.82 to ≤0.7"
+        )
+        assert len(every) == 3
+        assert every[0].changes[0].describe() == "resolve c.py is unused"
+
+
+class TestMarkdownComment:
+    def test_failed_gate(self, tmp_path):
+        syntax = _syntax(("CreateUser", 6))
+        remedies = ratchet_remedies(
+            tmp_path,
+            [Violation("svc.go", "max_nesting", 4.0, 6.0)],
+            AnalysisConfig(),
+            _Extractor(syntax),
+        )
+
+        comment = markdown_comment(False, remedies, ["1 changed file not checked."])
+
+        assert comment.splitlines() == [
+            "### ❌ Quality gate failed",
+            "",
+            "**Ratchet ceilings**",
+            "",
+            "| File | Metric | Threshold | Now | Over by |",
+            "|---|---|---:|---:|---:|",
+            "| `svc.go` | max_nesting | 4 | 6 | 2 |",
+            "",
+            "To pass:",
+            "",
+            "- `svc.go`: reduce CreateUser nesting from 6 to ≤4",
+            "",
+            "_1 changed file not checked._",
+        ]
+
+    def test_passed_gate(self):
+        assert markdown_comment(True, []) == "### ✅ Quality gate passed\n"
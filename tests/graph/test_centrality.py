@@ -0,0 +1,77 @@
+"""Tests for symbol and package centrality."""
+
+from shannon_insight.graph.centrality import build_centrality, rank_packages, symbol_complexity
+from shannon_insight.signals.function_outliers import FunctionSample
+from tests.conftest import make_function, make_syntax
+
+
+class TestRankPackages:
+    def test_file_imports_collapse_into_package_edges(self):
+        imports = {
+            "api/routes.py": ["core/db.py", "core/models.py", "api/auth.py"],
+            "api/auth.py": ["core/db.py"],
+            "jobs/sync.py": ["core/db.py"],
+            "core/db.py": [],
+            "core/models.py": ["core/db.py"],
+            "main.py": ["api/routes.py"],
+        }
+
+        ranks = {r.package: r for r in rank_packages(imports)}
+
+        assert [r.package for r in rank_packages(imports)][0] == "core"
+        assert (ranks["core"].dependents, ranks["core"].files) == (2, 2)
+        assert (ranks["api"].dependencies, ranks["api"].dependents) == (1, 1)
+        # api sits between the entry point and core; nothing passes through jobs
+        assert ranks["api"].betweenness > ranks["jobs"].betweenness == 0
+        assert ranks["."].files == 1
+
+
+class TestCentralityReport:
+    def _report(self):
+        syntax = {
+            "app/api.py": make_syntax(
+                "app/api.py", [make_function("handle", calls=["parse", "save", "log"])]
+            ),
+            "app/jobs.py": make_syntax(
+                "app/jobs.py", [make_function("sync", calls=["save", "log"])]
+            ),
+            "app/core.py": make_syntax(
+                "app/core.py",
+                [
+                    make_function("parse"),
+                    make_function("save", calls=["log"]),
+                    make_function("log"),
+                ],
+            ),
+        }
+        complexity = symbol_complexity(
+            [
+                FunctionSample("app/core.py", "parse", 1, 40, 25),
+                FunctionSample("app/core.py", "save", 5, 10, 4),
+                FunctionSample("app/core.py", "log", 9, 3, 1),
+            ]
+        )
+        return build_centrality(syntax, {"app/api.py": ["app/core.py"]}, complexity)
+
+    def test_hotspots_weigh_centrality_and_complexity(self):
+        hotspots = self._report().hotspots()
+
+        # log is the most called but trivial, parse complex but called once;
+        # save is both fairly central and fairly complex
+        assert hotspots[0].rank.symbol.name == "save"
+        assert hotspots[0].complexity == 4
+        assert {h.rank.symbol.name for h in hotspots[1:]} == {"parse", "log"}
+
+    def test_signals_for_snapshots(self):
+        global_signals, package_signals = self._report().signals()
+
+        assert 0 <= global_signals["call_centrality_gini"] <= 1
+        assert set(package_signals["app"]) == {"package_pagerank", "package_betweenness"}
+
+    def test_to_dict_limits_entries(self):
+        doc = self._report().to_dict(top=2)
+
+        assert doc["call_graph_available"]
+        assert [s["name"] for s in doc["symbols"]] == ["log", "save"]
+        assert doc["symbols"][0]["complexity"] == 1
+        assert len(doc["hotspots"]) == 2
@@ -1,11 +1,15 @@
 """Tests for Phase 3 NCD-based clone detection."""
 
+import struct
+
 import pytest
 
 from shannon_insight.graph.clone_detection import (
     compute_ncd,
     detect_clones,
+    detect_clones_winnowed,
 )
+from shannon_insight.graph.winnowing import fingerprints, kgram_hashes, winnow
 
 
 class TestComputeNCD:
@@ -121,3 +125,74 @@ class TestDetectClones:
         assert len(clones) == 1
         assert clones[0].size_a == 700
         assert clones[0].size_b == 700
+
+
+def _module(seed: int, n_funcs: int = 15) -> bytes:
+    lines = []
+    for i in range(n_funcs):
+        lines.append(f"def handler_{seed}_{i}(request, limit={i + seed}):")
+        lines.append(f"    items = fetch_{i % 7}(request.user, offset={i * seed})")
+        lines.append(f"    return [x for x in items if x.score > {i}][:limit]")
+    return "\n".join(lines).encode()
+
+
+class TestWinnowing:
+    """Test winnowing fingerprint selection."""
+
+    def test_guarantee_threshold(self):
+        # A shared run of >= guarantee tokens always yields a shared fingerprint
+        shared = b" ".join(b"tok%d" % i for i in range(40))
+        a = b"alpha beta gamma " * 5 + shared + b" omega" * 7
+        b = b"one two three four " * 9 + shared + b" end"
+        assert fingerprints(a, 40, 8) & fingerprints(b, 40, 8)
+
+    def test_noise_threshold(self):
+        # Runs shorter than the noise threshold never match
+        a = b"x = compute(a, b)\n" + b" ".join(b"a%d" % i for i in range(60))
+        b = b"x = compute(a, b)\n" + b" ".join(b"b%d" % i for i in range(60))
+        assert not fingerprints(a, 40, 8) & fingerprints(b, 40, 8)
+
+    def test_density_bounded_by_window(self):
+        hashes = kgram_hashes(list(range(5000)), 8)
+        window = 33
+        assert len(winnow(hashes, window)) <= 2 * len(hashes) / (window + 1) + window
+
+    def test_kgram_hashes_are_digests_of_packed_tokens(self):
+        xxhash = pytest.importorskip("xxhash")
+        packed = struct.pack("<3I", 7, 8, 9)
+        assert kgram_hashes([7, 8, 9], 2) == [
+            xxhash.xxh3_64_intdigest(packed[:8]),
+            xxhash.xxh3_64_intdigest(packed[4:]),
+        ]
+
+    def test_whitespace_ignored(self):
+        assert fingerprints(b"a = f(b,  c)\n" * 20) == fingerprints(b"a=f(b,c)\n" * 20)
+
+    def test_invalid_thresholds(self):
+        with pytest.raises(ValueError):
+            fingerprints(b"x", guarantee_tokens=4, noise_tokens=8)
+
+
+class TestDetectClonesWinnowed:
+    """Test fingerprint-indexed clone detection."""
+
+    def test_matches_pairwise(self):
+        files = {
+            "a.py": _module(1),
+            "b.py": _module(1).replace(b"handler_1_3", b"legacy_3"),  # Edited copy
+            "c.py": _module(2),
+            "d.py": _module(3),
+        }
+        pairwise = {(c.file_a, c.file_b) for c in detect_clones(files, {})}
+        winnowed = {(c.file_a, c.file_b) for c in detect_clones_winnowed(files, files.get, {})}
+        assert winnowed == pairwise
+        assert ("a.py", "b.py") in winnowed
+
+    def test_excludes_test_pairs(self):
+        files = {"test_a.py": _module(1), "test_b.py": _module(1)}
+        roles = {"test_a.py": "TEST", "test_b.py": "TEST"}
+        assert detect_clones_winnowed(files, files.get, roles) == []
+
+    def test_unreadable_files_skipped(self):
+        files = {"a.py": _module(1), "b.py": _module(1)}
+        assert detect_clones_winnowed(["a.py", "b.py", "gone.py"], files.get, {})[0].ncd < 0.3
@@ -0,0 +1,91 @@
+"""Tests for the sparse file coupling matrix."""
+
+import io
+import json
+
+from shannon_insight.graph.coupling import build_coupling_matrix, write_coupling
+
+FILES = ["api.py", "db.py", "models.py", "schema.sql", "utils.py"]
+DEPENDENCIES = [
+    ("api.py", "db.py"),
+    ("models.py", "db.py"),
+    ("db.py", "models.py"),
+    ("api.py", "vendor/lib.py"),  # not analyzed
+]
+COCHANGES = [
+    # (file_a, file_b, weight, lift, confidence_ab, confidence_ba, count)
+    ("api.py", "db.py", 3.0, 1.5, 0.5, 0.6, 4),
+    ("models.py", "schema.sql", 5.0, 4.0, 0.8, 0.9, 6),
+    ("api.py", "utils.py", 2.0, 2.5, 0.4, 0.3, 2),
+]
+
+
+def _matrix():
+    return build_coupling_matrix(FILES, DEPENDENCIES, COCHANGES)
+
+
+class TestBuildCouplingMatrix:
+    def test_joins_structural_and_temporal_edges_per_pair(self):
+        pairs = {(p.file_a, p.file_b): p for p in _matrix().pairs}
+
+        assert list(pairs) == [
+            ("api.py", "db.py"),
+            ("api.py", "utils.py"),
+            ("db.py", "models.py"),
+            ("models.py", "schema.sql"),
+        ]
+        both = pairs[("api.py", "db.py")]
+        assert (both.imports_ab, both.imports_ba, both.cochange_count) == (True, False, 4)
+        assert both.coupling == 1.6
+        cycle = pairs[("db.py", "models.py")]
+        assert (cycle.imports_ab, cycle.imports_ba, cycle.cochange_count) == (True, True, 0)
+        assert cycle.coupling == 1.0
+
+    def test_reversed_cochange_edge_swaps_confidences(self):
+        matrix = build_coupling_matrix(FILES, [], [("db.py", "api.py", 1.0, 2.0, 0.2, 0.7, 3)])
+
+        (pair,) = matrix.pairs
+        assert (pair.file_a, pair.confidence_ab, pair.confidence_ba) == ("api.py", 0.7, 0.2)
+
+    def test_hidden_pairs_lack_imports_most_coupled_first(self):
+        hidden = _matrix().hidden()
+
+        assert [(p.file_a, p.file_b) for p in hidden] == [
+            ("models.py", "schema.sql"),
+            ("api.py", "utils.py"),
+        ]
+        assert len(_matrix().hidden(1)) == 1
+
+
+class TestWriteCoupling:
+    def test_csv_has_one_row_per_pair(self):
+        out = io.StringIO()
+        write_coupling(_matrix(), out, "csv")
+
+        lines = out.getvalue().splitlines()
+        assert lines[0].startswith("file_a,file_b,imports_ab,imports_ba,cochange_count")
+        assert lines[1] == "api.py,db.py,1,0,4,3.0,1.5,0.5,0.6,1.6"
+        assert len(lines) == 5
+
+    def test_matrix_market_is_symmetric_lower_triangle(self):
+        out = io.StringIO()
+        write_coupling(_matrix(), out, "mtx")
+
+        lines = out.getvalue().splitlines()
+        assert lines[0] == "%%MatrixMarket matrix coordinate real symmetric"
+        assert lines[1:6] == [f"% {i} {path}" for i, path in enumerate(FILES, 1)]
+        assert lines[6] == "5 5 4"
+        # models.py (3) and schema.sql (4): row > column
+        assert "4 3 0.9" in lines[7:]
+        assert all(int(line.split()[0]) > int(line.split()[1]) for line in lines[7:])
+
+    def test_json_lists_files_and_selected_pairs(self):
+        matrix = _matrix()
+        out = io.StringIO()
+        write_coupling(matrix, out, "json", matrix.hidden(1))
+
+        doc = json.loads(out.getvalue())
+        assert doc["files"] == FILES
+        assert [(p["file_a"], p["file_b"], p["coupling"]) for p in doc["pairs"]] == [
+            ("models.py", "schema.sql", 0.9)
+        ]
@@ -0,0 +1,154 @@
+"""Tests for PII data-flow tagging."""
+
+import pytest
+
+from shannon_insight.graph.pii import find_pii_flows, parse_pii_fields
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, ImportDecl
+from tests.conftest import make_function
+
+_VIEWS = """\
+import logging
+import sentry_sdk
+from segment import analytics
+
+@app.get("/me")
+def me():
+    user = current_user()
+    logger.info(f"profile for {user.email}")
+    contact = user.email
+    analytics.track(user.id, "viewed", {"email": contact})
+    sentry_sdk.set_user({"id": user.id})
+    logger.info("masked %s", mask_email(user.email))
+    return jsonify(name=user.full_name)
+
+def notify(order):
+    print(order.customer.phone_number)
+    logging.warning("order %s", order.id)
+    return {"email": order.email}
+"""
+
+_FIELDS = {"User.email": "contact", "User.full_name": "identity", "phone_number": "contact"}
+
+
+def _report(files, fields=_FIELDS, external=None, classes=None):
+    syntax = {
+        path: FileSyntax(path, functions, (classes or {}).get(path, []), imports, language)
+        for path, (_, functions, imports, language) in files.items()
+    }
+    contents = {path: content for path, (content, *_) in files.items()}
+    return find_pii_flows(syntax, contents, parse_pii_fields(fields), external or {})
+
+
+def _views():
+    imports = [ImportDecl("logging", []), ImportDecl("sentry_sdk", []), ImportDecl("segment", [])]
+    functions = [
+        make_function("me", start_line=6, end_line=13, decorators=["app.get"]),
+        make_function("notify", start_line=15, end_line=18),
+    ]
+    return _report(
+        {"app/views.py": (_VIEWS, functions, imports, "python")},
+        external={"app/views.py": ["sentry_sdk", "segment"]},
+    )
+
+
+def _summary(report):
+    return [(f.kind, f.line, f.tag, f.read, f.call) for f in report.flows]
+
+
+class TestParsePiiFields:
+    def test_fields(self):
+        fields = parse_pii_fields({"User.email": "contact", "ssn": " government id "})
+
+        assert [(f.tag, f.name, f.model, f.category) for f in fields] == [
+            ("User.email", "email", "User", "contact"),
+            ("ssn", "ssn", None, "government id"),
+        ]
+
+    @pytest.mark.parametrize(
+        "table",
+        [{"User.profile.email": "contact"}, {"e-mail": "contact"}, {"email": ""}, {"email": 1}],
+    )
+    def test_malformed(self, table):
+        with pytest.raises(ValueError, match="pii_fields"):
+            parse_pii_fields(table)
+
+
+class TestFindPiiFlows:
+    def test_flows(self):
+        assert _summary(_views()) == [
+            ("log", 8, "User.email", "user.email", "logger.info("),
+            ("third_party", 10, "User.email", "user.email", "analytics.track("),
+            ("response", 13, "User.full_name", "user.full_name", "jsonify("),
+            ("log", 16, "phone_number", "customer.phone_number", "print("),
+        ]
+
+    def test_masked_values_and_other_models_are_not_reported(self):
+        lines = [f.line for f in _views().flows]
+
+        assert 12 not in lines  # mask_email(user.email)
+        assert 18 not in lines  # order.email is not User.email
+
+    def test_counts(self):
+        report = _views()
+
+        assert report.by_kind() == {"log": 2, "response": 1, "third_party": 1}
+        assert report.by_tag() == {"User.email": 2, "User.full_name": 1, "phone_number": 1}
+        assert report.functions == 2
+
+    def test_models(self):
+        content = "class User:\n    def greet(self):\n        print(self.email)\n"
+        greet = make_function("greet", start_line=2, end_line=3)
+        user = ClassDef("User", [], [greet], [])
+        report = _report(
+            {"app/models.py": (content, [], [], "python")},
+            classes={"app/models.py": [user]},
+        )
+
+        assert report.models == {
+            "User.email": ["app/models.py"],
+            "User.full_name": ["app/models.py"],
+        }
+        assert _summary(report) == [("log", 3, "User.email", "self.email", "print(")]
+
+    def test_javascript(self):
+        content = """\
+import Mixpanel from "mixpanel";
+import express from "express";
+
+function signup(req, res) {
+  const email = req.body.email;
+  console.log("signup", email);
+  Mixpanel.track("signup", { email });
+  res.status(201).json({ id: 1, email: email });
+}
+"""
+        report = _report(
+            {
+                "src/signup.js": (
+                    content, [make_function("signup", start_line=4, end_line=9)], [], "javascript"
+                ),
+            },
+            fields={"email": "contact"},
+            external={"src/signup.js": ["mixpanel", "express"]},
+        )
+
+        assert _summary(report) == [
+            ("log", 6, "email", "body.email", "console.log("),
+            ("third_party", 7, "email", "body.email", "Mixpanel.track("),
+            ("response", 8, "email", "body.email", "res.status(201).json("),
+        ]
+
+    def test_handler_return_is_a_response(self):
+        content = '@app.get("/me")\ndef me():\n    return {"email": current_user.email}\n'
+        report = _report(
+            {
+                "app/me.py": (
+                    content,
+                    [make_function("me", start_line=2, end_line=3, decorators=["app.get"])],
+                    [],
+                    "python",
+                ),
+            },
+        )
+
+        assert _summary(report) == [("response", 3, "User.email", "current_user.email", "return")]
@@ -0,0 +1,116 @@
+"""Tests for entry points and reachability."""
+
+from shannon_insight.graph.reachability import build_surface, find_entry_points
+from shannon_insight.scanning.syntax import ClassDef
+from tests.conftest import make_function, make_syntax
+
+
+def _syntax():
+    return {
+        "app/api.py": make_syntax(
+            "app/api.py",
+            [
+                make_function(
+                    "create_user", calls=["validate", "save"], decorators=["router.post"]
+                ),
+                make_function("health", calls=[], start_line=10, decorators=["app.get"]),
+            ],
+        ),
+        "app/jobs.py": make_syntax(
+            "app/jobs.py",
+            [make_function("sync", calls=["save", "notify"], decorators=["shared_task"])],
+        ),
+        "app/cli.py": make_syntax(
+            "app/cli.py", [make_function("export", calls=["save"], decorators=["cli.command"])]
+        ),
+        "app/core.py": make_syntax(
+            "app/core.py",
+            [
+                make_function("validate", calls=[]),
+                make_function("save", calls=["log"], start_line=5),
+                make_function("log", calls=[], start_line=9),
+                make_function("legacy_import", calls=["log"], start_line=13),
+                make_function("__repr__", calls=[], start_line=17),
+            ],
+        ),
+        "app/notify.py": make_syntax("app/notify.py", [make_function("notify", calls=[])]),
+        "cmd/server/main.go": make_syntax(
+            "cmd/server/main.go",
+            [make_function("main", calls=["ListenAndServe"], start_line=3)],
+            language="go",
+        ),
+        "tests/test_core.py": make_syntax(
+            "tests/test_core.py", [make_function("main", calls=["legacy_import"])]
+        ),
+    }
+
+
+_IMPORTS = {
+    "app/api.py": ["app/core.py"],
+    "app/jobs.py": ["app/core.py", "app/notify.py"],
+    "app/cli.py": ["app/core.py"],
+}
+
+
+class TestFindEntryPoints:
+    def test_kinds_from_decorators_and_names(self):
+        entries = {e.symbol.id: (e.kind, e.trigger) for e in find_entry_points(_syntax())}
+
+        assert entries == {
+            "app/api.py:create_user": ("http", "@router.post"),
+            "app/api.py:health": ("http", "@app.get"),
+            "app/jobs.py:sync": ("consumer", "@shared_task"),
+            "app/cli.py:export": ("cli", "@cli.command"),
+            "cmd/server/main.go:main": ("main", "main"),
+        }
+
+    def test_class_methods_are_entry_points(self):
+        handler = ClassDef("Handler", [], [make_function("ServeHTTP", calls=[], start_line=5)], [])
+        syntax = {
+            "server/handler.go": make_syntax("server/handler.go", classes=[handler], language="go")
+        }
+
+        (entry,) = find_entry_points(syntax)
+
+        assert (entry.symbol.name, entry.kind) == ("ServeHTTP", "http")
+
+
+class TestBuildSurface:
+    def test_reachable_code_and_totals(self):
+        complexity = {"app/core.py:validate": 6, "app/core.py:save": 3, "app/core.py:log": 1}
+        risk = {"app/api.py": 0.5, "app/core.py": 0.25}
+
+        report = build_surface(_syntax(), _IMPORTS, complexity, risk)
+        reach = {r.entry.symbol.name: r for r in report.entries}
+
+        assert reach["create_user"].reachable == [
+            "app/api.py:create_user",
+            "app/core.py:validate",
+            "app/core.py:save",
+            "app/core.py:log",
+        ]
+        assert (reach["create_user"].complexity, reach["create_user"].max_complexity) == (10, 6)
+        assert reach["create_user"].files == 2
+        assert reach["create_user"].risk == 0.75
+        # Most complexity reached first
+        assert report.entries[0].entry.symbol.name == "create_user"
+        assert report.by_kind() == {"main": 1, "http": 2, "consumer": 1, "cli": 1}
+
+    def test_unreachable_functions(self):
+        report = build_surface(_syntax(), _IMPORTS)
+
+        # Tests calling legacy_import do not make it reachable; dunders are
+        # called implicitly
+        assert [s.id for s in report.unreachable] == ["app/core.py:legacy_import"]
+
+    def test_no_unreachable_code_without_a_call_graph(self):
+        syntax = {
+            "main.py": make_syntax("main.py", [make_function("main")]),
+            "util.py": make_syntax("util.py", [make_function("helper")]),
+        }
+
+        report = build_surface(syntax, {})
+
+        assert not report.call_graph_available
+        assert report.unreachable == []
+        assert [r.entry.symbol.name for r in report.entries] == ["main"]
@@ -0,0 +1,82 @@
+"""Tests for sbt sub-project discovery."""
+
+from shannon_insight.graph.sbt import (
+    dependency_closure,
+    discover_sbt_projects,
+    parse_build_sbt,
+    project_of,
+)
+
+_BUILD = """\
+ThisBuild / scalaVersion := "2.13.12"
+
+lazy val root = (project in file("."))
+  .aggregate(core, api, shared.jvm)
+
+lazy val core = project
+  .settings(libraryDependencies += "org.typelevel" %% "cats-core" % "2.10.0")
+
+// lazy val old = project
+
+lazy val shared = crossProject(JVMPlatform, JSPlatform).in(file("modules/shared"))
+
+lazy val sharedJVM = shared.jvm
+
+lazy val api = project.in(file("modules/api"))
+  .dependsOn(core % "compile->compile;test->test", sharedJVM, LocalProject("root"))
+
+lazy val web = Project("web", file("./modules/web/")).dependsOn(api)
+
+val catsVersion = "2.10.0"
+"""
+
+
+class TestParseBuildSbt:
+    def test_projects_and_directories(self):
+        projects = {p.name: p.directory for p in parse_build_sbt(_BUILD)}
+
+        assert projects == {
+            "root": ".",
+            "core": "core",
+            "shared": "modules/shared",
+            "api": "modules/api",
+            "web": "modules/web",
+        }
+
+    def test_depends_on_resolves_aliases_and_ignores_aggregate(self):
+        projects = {p.name: p.depends_on for p in parse_build_sbt(_BUILD)}
+
+        assert projects["root"] == []
+        assert projects["api"] == ["core", "shared", "root"]
+        assert projects["web"] == ["api"]
+
+    def test_dependency_closure_is_transitive(self):
+        projects = parse_build_sbt(_BUILD)
+
+        assert dependency_closure("web", projects) == {"api", "core", "shared", "root"}
+        assert dependency_closure("missing", projects) == set()
+
+
+class TestProjectOf:
+    def test_deepest_directory_wins(self):
+        projects = parse_build_sbt(_BUILD)
+
+        assert project_of("modules/api/src/main/scala/A.scala", projects).name == "api"
+        assert project_of("core/src/main/scala/B.scala", projects).name == "core"
+        # Anything else belongs to the root project
+        assert project_of("project/Deps.scala", projects).name == "root"
+
+    def test_no_project_without_root(self):
+        projects = parse_build_sbt("lazy val core = project\n")
+
+        assert project_of("tools/gen.scala", projects) is None
+
+
+class TestDiscover:
+    def test_reads_build_sbt(self, tmp_path):
+        (tmp_path / "build.sbt").write_text(_BUILD)
+
+        assert len(discover_sbt_projects(tmp_path)) == 5
+
+    def test_no_build_file(self, tmp_path):
+        assert discover_sbt_projects(tmp_path) == []
@@ -0,0 +1,84 @@
+"""Tests for the symbol call graph."""
+
+from shannon_insight.graph.symbols import call_graph, has_call_targets, rank_symbols
+from shannon_insight.scanning.syntax import ClassDef
+from tests.conftest import make_function, make_syntax
+
+
+class TestCallGraph:
+    def test_calls_resolve_locally_then_through_imports(self):
+        syntax = {
+            "app/api.py": make_syntax(
+                "app/api.py", [make_function("handle", calls=["self.repo.save", "get", "log"])]
+            ),
+            "app/repo.py": make_syntax(
+                "app/repo.py", [make_function("save", calls=["get"]), make_function("get")]
+            ),
+            "app/other.py": make_syntax("app/other.py", [make_function("get")]),
+            "app/logs.py": make_syntax("app/logs.py", [make_function("log")]),
+        }
+        imports = {"app/api.py": ["app/repo.py"]}
+
+        _, edges = call_graph(syntax, imports)
+
+        # get is ambiguous globally but imported from repo; log is unique
+        assert edges["app/api.py:handle"] == [
+            "app/repo.py:save",
+            "app/repo.py:get",
+            "app/logs.py:log",
+        ]
+        assert edges["app/repo.py:save"] == ["app/repo.py:get"]
+
+    def test_ambiguous_calls_are_dropped(self):
+        syntax = {
+            "a.py": make_syntax("a.py", [make_function("main", calls=["run"])]),
+            "b.py": make_syntax("b.py", [make_function("run")]),
+            "c.py": make_syntax("c.py", [make_function("run")]),
+        }
+        _, edges = call_graph(syntax, {})
+        assert edges["a.py:main"] == []
+
+    def test_classes_are_symbols(self):
+        syntax = {
+            "a.py": make_syntax("a.py", [make_function("main", calls=["models.User"])]),
+            "models.py": make_syntax("models.py", classes=[ClassDef("User", [], [], ["name"])]),
+        }
+        symbols, edges = call_graph(syntax, {})
+        assert symbols["models.py:User"].kind == "class"
+        assert edges["a.py:main"] == ["models.py:User"]
+
+
+class TestRankSymbols:
+    def test_most_called_first_and_uncalled_left_out(self):
+        syntax = {
+            "a.py": make_syntax(
+                "a.py",
+                [make_function("a", calls=["core"]), make_function("b", calls=["core", "helper"])],
+            ),
+            "core.py": make_syntax(
+                "core.py", [make_function("core", calls=["helper"]), make_function("helper")]
+            ),
+        }
+        symbols, edges = call_graph(syntax, {})
+
+        ranks = rank_symbols(symbols, edges)
+
+        assert [r.symbol.id for r in ranks] == ["core.py:helper", "core.py:core"]
+        assert [r.callers for r in ranks] == [2, 2]
+
+    def test_regex_parsed_files_have_no_call_targets(self):
+        assert not has_call_targets({"a.py": make_syntax("a.py", [make_function("main")])})
+        assert has_call_targets({"a.py": make_syntax("a.py", [make_function("main", calls=[])])})
+
+    def test_betweenness_marks_symbols_between_others(self):
+        syntax = {
+            "a.py": make_syntax("a.py", [make_function("main", calls=["route"])]),
+            "b.py": make_syntax("b.py", [make_function("route", calls=["save", "render"])]),
+            "c.py": make_syntax("c.py", [make_function("save"), make_function("render")]),
+        }
+        symbols, edges = call_graph(syntax, {})
+
+        ranks = {r.symbol.name: r for r in rank_symbols(symbols, edges)}
+
+        assert ranks["route"].betweenness > 0
+        assert ranks["save"].betweenness == ranks["render"].betweenness == 0
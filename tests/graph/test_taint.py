@@ -0,0 +1,168 @@
+"""Tests for taint paths from inputs to sensitive calls."""
+
+from shannon_insight.graph.taint import find_taint_paths
+from shannon_insight.scanning.syntax import FunctionDef
+from tests.conftest import make_function, make_syntax
+
+_API = """\
+@app.get("/users")
+def search():
+    name = request.args.get("name")
+    query = f"SELECT * FROM users WHERE name = '{name}'"
+    cursor.execute(query)
+    cursor.execute("SELECT * FROM users WHERE name = %s", (name,))
+    limit = int(request.args["limit"])
+    cursor.execute(f"SELECT * FROM t LIMIT {limit}")
+    return export(name, fmt="csv")
+
+@app.post("/reports/<report_id>")
+def report(report_id):
+    return render(report_id)
+
+def export(filename, fmt):
+    return write_out(filename)
+
+def write_out(target):
+    with open(target) as f:
+        return f.read()
+
+def render(ident):
+    return render_template_string("<h1>" + ident + "</h1>")
+
+def deploy():
+    region = os.environ["REGION"]
+    subprocess.run("deploy --region " + region, shell=True)
+    cleanup()
+
+def cleanup(path=None):
+    os.system("rm -rf " + path)
+"""
+
+
+_FUNCTIONS = [
+    make_function(
+        "search",
+        start_line=2,
+        end_line=9,
+        calls=["request.args.get", "cursor.execute", "export"],
+        decorators=["app.get"],
+    ),
+    make_function(
+        "report",
+        start_line=12,
+        end_line=13,
+        params=["report_id"],
+        calls=["render"],
+        decorators=["app.post"],
+    ),
+    make_function(
+        "export", start_line=15, end_line=16, params=["filename", "fmt"], calls=["write_out"]
+    ),
+    make_function("write_out", start_line=18, end_line=20, params=["target"], calls=["open"]),
+    make_function(
+        "render", start_line=22, end_line=23, params=["ident"], calls=["render_template_string"]
+    ),
+    make_function("deploy", start_line=25, end_line=28, calls=["subprocess.run", "cleanup"]),
+    make_function("cleanup", start_line=30, end_line=31, params=["path"], calls=["os.system"]),
+]
+
+
+def _report(path="app/api.py", content=_API, functions=_FUNCTIONS, language="python"):
+    syntax = {path: make_syntax(path, list(functions), language=language)}
+    return find_taint_paths(syntax, {path: content}, {})
+
+
+def _summary(report):
+    return [
+        (p.confidence, p.source.expression, p.source.line, p.sink.kind, p.sink.call, p.sink.line)
+        for p in report.paths
+    ]
+
+
+class TestFindTaintPaths:
+    def test_paths_by_confidence(self):
+        report = _report()
+
+        assert _summary(report) == [
+            ("high", "request.args", 3, "sql", "cursor.execute(", 5),
+            ("high", "parameter report_id", 12, "template", "render_template_string(", 23),
+            ("medium", "request.args", 3, "path", "open(", 19),
+            ("medium", "os.environ", 26, "exec", "subprocess.run(", 27),
+            ("low", "request.args", 3, "sql", "cursor.execute(", 8),
+            ("low", "os.environ", 26, "exec", "os.system(", 31),
+        ]
+        assert report.by_confidence() == {"high": 2, "medium": 2, "low": 2}
+        assert report.functions == 7
+
+    def test_bind_parameters_are_not_injection(self):
+        # cursor.execute("... %s", (name,)) passes the input as a bind parameter
+        assert 6 not in [p.sink.line for p in _report().paths]
+
+    def test_path_through_calls(self):
+        (path,) = [p for p in _report().paths if p.sink.kind == "path"]
+
+        assert path.via == ["app/api.py:search", "app/api.py:export", "app/api.py:write_out"]
+        assert path.calls == 2
+
+    def test_untainted_call_is_low_confidence(self):
+        # cleanup() is called without the environment value, but its parameter feeds a sink
+        (path,) = [p for p in _report().paths if p.sink.call == "os.system("]
+
+        assert path.via == ["app/api.py:deploy", "app/api.py:cleanup"]
+        assert path.confidence == "low"
+
+    def test_without_call_graph_paths_stay_in_one_function(self):
+        functions = [
+            FunctionDef(f.name, f.params, 10, 2, 1, f.start_line, f.end_line, None, f.decorators)
+            for f in _FUNCTIONS
+        ]
+        report = _report(functions=functions)
+
+        assert not report.call_graph_available
+        assert _summary(report) == [
+            ("high", "request.args", 3, "sql", "cursor.execute(", 5),
+            ("medium", "os.environ", 26, "exec", "subprocess.run(", 27),
+            ("low", "request.args", 3, "sql", "cursor.execute(", 8),
+        ]
+
+    def test_go_handler(self):
+        content = """\
+package api
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+\tid := r.URL.Query().Get("id")
+\trows, err := h.db.Query(
+\t\t"SELECT * FROM t WHERE id = " + id,
+\t)
+\texec.Command("sh", "-c", "echo "+id).Run()
+\texec.Command("ls", "-la").Run()
+}
+"""
+        report = _report(
+            "api/handler.go",
+            content,
+            [make_function("ServeHTTP", start_line=3, end_line=10, params=["w", "r"], calls=[])],
+            "go",
+        )
+
+        # r.URL.Query() reads input, it does not run SQL; any command argument can inject
+        assert _summary(report) == [
+            ("high", "r.URL.Query", 4, "sql", "h.db.Query(", 5),
+            ("high", "r.URL.Query", 4, "exec", "exec.Command(", 8),
+        ]
+
+    def test_sanitized_input_is_not_followed(self):
+        content = """\
+def download():
+    name = secure_filename(request.args["file"])
+    return send_file(name)
+"""
+        report = _report("app/files.py", content, [make_function("download", end_line=3, calls=[])])
+
+        assert _summary(report) == [("low", "request.args", 2, "path", "send_file(", 3)]
+
+    def test_test_files_are_skipped(self):
+        report = _report(path="tests/test_api.py")
+
+        assert report.paths == []
+        assert report.functions == 0
@@ -0,0 +1,167 @@
+"""Tests for the JWT/auth flow review rules."""
+
+from pathlib import Path
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.auth import AUTH_ISSUE_TYPE, analyze_auth, to_findings
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+
+_FIXTURE = Path(__file__).parent.parent / "fixtures" / "polyglot_baseline"
+
+_PY = """import os
+
+import jwt
+
+SECRET_KEY = os.environ.get("JWT_SECRET", "dev-secret")
+SECRET_ENV = "JWT_SECRET"
+
+
+def issue(user_id):
+    return jwt.encode({"sub": user_id}, SECRET_KEY, algorithm="HS256")
+
+
+def verify(token):
+    return jwt.decode(
+        token,
+        SECRET_KEY,
+        algorithms=["HS256"],
+        audience="api",
+        issuer="auth",
+    )
+
+
+def lenient(token):
+    options = {"verify_exp": False}
+    return jwt.decode(token, SECRET_KEY, algorithms=["HS256"], options=options)
+
+
+def refresh_token(token):
+    try:
+        payload = jwt.decode(token, SECRET_KEY, algorithms=["HS256"], audience="api", issuer="a")
+    except jwt.ExpiredSignatureError:
+        payload = {}
+    return issue(payload.get("sub"))
+"""
+
+_JS = """const jwt = require("jsonwebtoken");
+const secret = process.env.JWT_SECRET || "changeme";
+
+function login(user) {
+  return jwt.sign({ sub: user.id }, secret, { expiresIn: "1h" });
+}
+
+function refreshSession(req, res) {
+  const options = { ignoreExpiration: true, audience: "a", issuer: "b" };
+  const claims = jwt.verify(req.body.token, secret, options);
+  res.json({ token: login(claims) });
+}
+
+function check(token) {
+  return jwt.verify(token, "hunter2", { audience: "a", issuer: "b" });
+}
+"""
+
+_PY_FUNCTIONS = [
+    ("issue", 9, 10),
+    ("verify", 13, 20),
+    ("lenient", 23, 25),
+    ("refresh_token", 28, 33),
+]
+_JS_FUNCTIONS = [("login", 4, 6), ("refreshSession", 8, 12), ("check", 14, 16)]
+
+
+def _analyze(content, functions, path="auth.py", language="python"):
+    defs = [FunctionDef(name, [], 10, 2, 1, start, end) for name, start, end in functions]
+    syntax = {path: FileSyntax(path, defs, [], [], language)}
+    return analyze_auth(syntax, {path: content})
+
+
+def _found(report, path):
+    return sorted((i.line, i.rule, i.subject) for i in report.issues if i.path == path)
+
+
+class TestRules:
+    def test_python(self):
+        report = _analyze(_PY, _PY_FUNCTIONS)
+
+        assert _found(report, "auth.py") == [
+            (5, "default_secret", "JWT_SECRET"),
+            (10, "token_without_expiry", "exp"),
+            (25, "expiry_not_verified", "exp"),
+            (25, "unvalidated_claim", "aud"),
+            (25, "unvalidated_claim", "iss"),
+            (30, "refresh_accepts_expired", "refresh_token"),
+        ]
+        assert (report.issue_sites, report.verify_sites) == (1, 3)
+
+    def test_javascript(self):
+        report = _analyze(_JS, _JS_FUNCTIONS, "auth.js", "javascript")
+
+        assert _found(report, "auth.js") == [
+            (2, "default_secret", "JWT_SECRET"),
+            (10, "refresh_accepts_expired", "refreshSession"),
+            (15, "default_secret", "signing key"),
+        ]
+
+    def test_refresh_that_rejects_expired_tokens(self):
+        source = _PY.replace("        payload = {}", "        raise PermissionError('expired')")
+        report = _analyze(source, _PY_FUNCTIONS)
+
+        assert "refresh_accepts_expired" not in report.counts()
+
+    def test_test_files_are_skipped(self):
+        assert _analyze(_PY, _PY_FUNCTIONS, "tests/test_auth.py").issues == []
+
+    def test_fixture_auth_service(self):
+        sources = load_sources(_FIXTURE)
+        report = analyze_auth(sources.syntax, sources.content)
+
+        assert _found(report, "go_backend/services/auth_service.go") == [
+            (21, "default_secret", "jwtSecret"),
+            (50, "unvalidated_claim", "aud"),
+            (50, "unvalidated_claim", "iss"),
+            (65, "expiry_not_verified", "exp"),
+        ]
+        assert _found(report, "python_service/config.py") == [
+            (25, "default_secret", "secret_key"),
+        ]
+        assert _found(report, "go_backend/config/config.go") == [
+            (22, "default_secret", "JWT_SECRET"),
+        ]
+
+
+class TestFindings:
+    def test_grouped_per_rule_and_subject(self):
+        source = _PY + '\n\ndef again(token):\n    return jwt.decode(token, "k2")\n'
+        report = _analyze(source, [*_PY_FUNCTIONS, ("again", 36, 37)])
+        findings = to_findings(report.issues)
+
+        assert {f.finding_type for f in findings} == {AUTH_ISSUE_TYPE}
+        by_hint = {f.identity_hint: f for f in findings}
+        assert sorted(by_hint) == [
+            "default_secret:JWT_SECRET",
+            "default_secret:signing key",
+            "expiry_not_verified:exp",
+            "refresh_accepts_expired:refresh_token",
+            "token_without_expiry:exp",
+            "unvalidated_claim:aud",
+            "unvalidated_claim:iss",
+        ]
+        aud = by_hint["unvalidated_claim:aud"]
+        assert aud.evidence[1].description == "lines 25, 37"
+        assert aud.title == "Token audience (aud) is never validated in auth.py"
+        assert by_hint["refresh_accepts_expired:refresh_token"].severity == 0.7
+
+    def test_expiry_flags_are_patched(self):
+        py = {f.identity_hint: f for f in to_findings(_analyze(_PY, _PY_FUNCTIONS).issues)}
+        js_report = _analyze(_JS, _JS_FUNCTIONS, "auth.js", "javascript")
+        js = {f.identity_hint: f for f in to_findings(js_report.issues)}
+
+        [patch] = py["expiry_not_verified:exp"].patches
+        assert (patch.path, patch.start_line, patch.end_line) == ("auth.py", 24, 24)
+        assert patch.replacement == '    options = {"verify_exp": True}'
+        [patch] = js["refresh_accepts_expired:refreshSession"].patches
+        assert patch.start_line == 9
+        assert "ignoreExpiration: false" in patch.replacement
+        # Carrying on after an expired-token error has no mechanical fix
+        assert py["refresh_accepts_expired:refresh_token"].patches == []
@@ -0,0 +1,201 @@
+"""Tests for the crypto usage inventory and policy checks."""
+
+from pathlib import Path
+
+import pytest
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.crypto import (
+    CRYPTO_VIOLATION_TYPE,
+    CryptoPolicy,
+    analyze_crypto,
+    resolve_policy,
+    to_findings,
+)
+
+_FIXTURE = Path(__file__).parent.parent / "fixtures" / "polyglot_baseline"
+
+_PY = """import hashlib
+import ssl
+
+from cryptography.hazmat.primitives.asymmetric import rsa
+
+
+def digest(data):
+    # hashlib.md5 was used here before
+    etag = hashlib.md5(data, usedforsecurity=False).hexdigest()
+    return hashlib.sha256(data).hexdigest(), etag
+
+
+def legacy(data):
+    return hashlib.md5(data).hexdigest()
+
+
+def keys():
+    return rsa.generate_private_key(
+        public_exponent=65537,
+        key_size=1024,
+    )
+
+
+def fetch(url):
+    return requests.get(url, verify=False)
+"""
+
+_GO = """package auth
+
+import "crypto/aes"
+import "crypto/tls"
+import "github.com/golang-jwt/jwt/v5"
+
+func sign(claims jwt.Claims) *jwt.Token {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+}
+
+func client() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS10}
+}
+
+func block(key []byte) {
+	aes.NewCipher(key)
+}
+"""
+
+_JAVA = """import javax.crypto.Cipher;
+import java.security.KeyPairGenerator;
+
+class Box {
+    void seal() throws Exception {
+        Cipher c = Cipher.getInstance("AES");
+        Cipher g = Cipher.getInstance("AES/GCM/NoPadding");
+        KeyPairGenerator gen = KeyPairGenerator.getInstance("RSA");
+        gen.initialize(4096);
+    }
+}
+"""
+
+_JS = """const crypto = require("crypto");
+const jwt = require("jsonwebtoken");
+
+const cipher = crypto.createCipheriv("des-ede3-cbc", key, iv);
+const token = jwt.sign(payload, secret, { algorithm: "none" });
+"""
+
+
+def _analyze(write_files, files, policy=None):
+    sources = load_sources(write_files(files))
+    return analyze_crypto(sources.syntax, sources.content, policy)
+
+
+def _found(report, category):
+    return {(u.line, u.algorithm, u.mode) for u in report.usages if u.category == category}
+
+
+class TestInventory:
+    def test_python_hashes_keys_and_tls(self, write_files):
+        report = _analyze(write_files, {"app.py": _PY})
+        assert _found(report, "hash") == {(9, "md5", ""), (10, "sha256", ""), (14, "md5", "")}
+        keys = [u for u in report.usages if u.category == "key"]
+        assert [(k.algorithm, k.key_bits) for k in keys] == [("rsa", 1024)]
+        assert _found(report, "tls") == {(25, "no-verify", "")}
+        libraries = _found(report, "library")
+        assert libraries == {(1, "hashlib", ""), (2, "ssl", ""), (4, "cryptography", "")}
+
+    def test_go_jwt_tls_and_cipher(self, write_files):
+        report = _analyze(write_files, {"auth/auth.go": _GO})
+        assert _found(report, "jwt") == {(8, "hs256", "")}
+        assert _found(report, "tls") == {(12, "tls1.0", "")}
+        assert _found(report, "cipher") == {(16, "aes", "")}
+        libraries = {u.algorithm for u in report.usages if u.category == "library"}
+        assert libraries == {"crypto/aes", "crypto/tls", "golang-jwt"}
+
+    def test_java_cipher_defaults_to_ecb(self, write_files):
+        report = _analyze(write_files, {"Box.java": _JAVA})
+        assert _found(report, "cipher") == {(6, "aes", "ecb"), (7, "aes", "gcm")}
+        keys = [u for u in report.usages if u.category == "key"]
+        assert [(k.algorithm, k.key_bits) for k in keys] == [("rsa", 4096)]
+
+    def test_node_cipher_spec_and_jwt_none(self, write_files):
+        report = _analyze(write_files, {"token.js": _JS})
+        assert _found(report, "cipher") == {(4, "3des", "cbc")}
+        assert _found(report, "jwt") == {(5, "none", "")}
+
+    def test_inventory_counts(self, write_files):
+        inventory = _analyze(write_files, {"app.py": _PY, "Box.java": _JAVA}).inventory()
+        assert inventory["hash"] == {"md5": 2, "sha256": 1}
+        assert inventory["cipher"] == {"aes/ecb": 1, "aes/gcm": 1}
+
+    def test_fixture_jwt_signing(self):
+        sources = load_sources(_FIXTURE)
+        report = analyze_crypto(sources.syntax, sources.content)
+        jwt = {(u.path, u.algorithm) for u in report.usages if u.category == "jwt"}
+        assert ("python_service/config.py", "hs256") in jwt
+        assert ("go_backend/services/auth_service.go", "hs256") in jwt
+
+
+class TestPolicy:
+    def test_default_policy(self, write_files):
+        report = _analyze(write_files, {"app.py": _PY, "auth/auth.go": _GO})
+        found = {(v.usage.path, v.usage.line, v.rule, v.subject) for v in report.violations}
+        assert found == {
+            ("app.py", 14, "banned_algorithm", "md5"),
+            ("app.py", 18, "weak_key", "rsa"),
+            ("app.py", 25, "banned_algorithm", "no-verify"),
+            ("auth/auth.go", 12, "banned_algorithm", "tls1.0"),
+        }
+        assert report.violations[0].subject == "no-verify"
+
+    def test_banned_mode_and_jwt_none(self, write_files):
+        report = _analyze(write_files, {"Box.java": _JAVA, "token.js": _JS})
+        found = {(v.usage.line, v.subject) for v in report.violations}
+        assert found == {(6, "ecb"), (4, "3des"), (5, "none")}
+        assert max(v.severity for v in report.violations) == 0.8
+
+    def test_overrides(self, write_files):
+        policy = resolve_policy(
+            {
+                "banned_algorithms": ["HS256"],
+                "min_key_bits": {"rsa": 8192},
+                "approved_libraries": ["golang-jwt"],
+            }
+        )
+        assert policy.min_key_bits["ec"] == 224
+        report = _analyze(write_files, {"auth/auth.go": _GO, "Box.java": _JAVA}, policy)
+        found = {(v.rule, v.subject) for v in report.violations}
+        assert found == {
+            ("banned_algorithm", "hs256"),
+            ("weak_key", "rsa"),
+            ("unapproved_library", "crypto/aes"),
+            ("unapproved_library", "crypto/tls"),
+            ("unapproved_library", "javax.crypto"),
+            ("unapproved_library", "java.security"),
+        }
+
+    def test_no_usage(self, write_files):
+        report = _analyze(write_files, {"a.py": "def f():\n    return 1\n"}, CryptoPolicy())
+        assert report.usages == [] and report.violations == []
+
+    @pytest.mark.parametrize(
+        "overrides",
+        [
+            {"banned": ["md5"]},
+            {"banned_algorithms": "md5"},
+            {"min_key_bits": {"rsa": "2048"}},
+            {"approved_libraries": [1]},
+        ],
+    )
+    def test_invalid_policy(self, overrides):
+        with pytest.raises(ValueError, match="crypto_policy"):
+            resolve_policy(overrides)
+
+
+class TestFindings:
+    def test_grouped_per_file_rule_and_subject(self, write_files):
+        report = _analyze(write_files, {"app.py": _PY + "\nx = hashlib.md5(b'').digest()\n"})
+        findings = to_findings(report.violations)
+        md5 = next(f for f in findings if f.identity_hint == "banned_algorithm:md5")
+        assert md5.finding_type == CRYPTO_VIOLATION_TYPE
+        assert md5.files == ["app.py"]
+        assert md5.evidence[1].value == 2.0
+        assert md5.evidence[1].description == "lines 14, 27"
+        assert len(findings) == 3
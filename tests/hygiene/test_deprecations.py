@@ -0,0 +1,115 @@
+"""Tests for the deprecated-symbol usage tracker."""
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.deprecations import analyze_deprecations, find_deprecated
+
+_GO_OLD = """package client
+
+// OldDial connects to the server.
+//
+// Deprecated: use Dial instead.
+func OldDial(addr string) error {
+	return Dial(addr)
+}
+
+func Dial(addr string) error { return nil }
+"""
+
+_GO_USER = """package app
+
+// OldDial is still used here
+func run() {
+	client.OldDial("a")
+	client.OldDial("b")
+	log("OldDial")
+}
+"""
+
+_PY = '''import warnings
+from typing_extensions import deprecated
+
+
+@deprecated("Use fetch_all")
+@staticmethod
+def fetch_legacy():
+    return []
+
+
+class LegacyStore:
+    def __init__(self):
+        warnings.warn(
+            "LegacyStore is deprecated", DeprecationWarning, stacklevel=2
+        )
+
+
+def current():
+    return fetch_legacy() + [LegacyStore()]
+'''
+
+_TS = """/**
+ * Formats a date.
+ * @deprecated Use formatDate
+ */
+export function fmtDate(d: Date): string {
+  return formatDate(d);
+}
+
+/** @deprecated */
+export const LEGACY_LIMIT = 10;
+
+fmtDate(new Date());
+"""
+
+
+class TestFindDeprecated:
+    def test_go_doc_comment(self, write_files):
+        symbols = find_deprecated(load_sources(write_files({"client/client.go": _GO_OLD})))
+
+        assert [(s.name, s.line, s.note) for s in symbols] == [
+            ("OldDial", 6, "use Dial instead.")
+        ]
+
+    def test_python_decorator_and_warning(self, write_files):
+        symbols = find_deprecated(load_sources(write_files({"store.py": _PY})))
+        names = {s.name: s for s in symbols}
+
+        assert set(names) == {"fetch_legacy", "LegacyStore"}
+        assert names["fetch_legacy"].note == "Use fetch_all"
+        assert names["LegacyStore"].line == 11
+
+    def test_jsdoc(self, write_files):
+        symbols = find_deprecated(load_sources(write_files({"date.ts": _TS})))
+
+        assert {s.name: s.note for s in symbols} == {
+            "fmtDate": "Use formatDate",
+            "LEGACY_LIMIT": "",
+        }
+
+
+class TestAnalyzeDeprecations:
+    def test_counts_call_sites_outside_comments_and_strings(self, write_files):
+        sources = load_sources(write_files({"client/client.go": _GO_OLD, "app/app.go": _GO_USER}))
+        report = analyze_deprecations(sources)
+
+        (old,) = report.symbols
+        assert old.call_sites == ["app/app.go:5", "app/app.go:6"]
+        assert report.by_package() == {"app": 2}
+
+    def test_python_call_sites(self, write_files):
+        report = analyze_deprecations(load_sources(write_files({"store.py": _PY})))
+
+        assert {s.name: s.count for s in report.symbols} == {"fetch_legacy": 1, "LegacyStore": 1}
+
+    def test_signals(self, write_files):
+        sources = load_sources(write_files({"client/client.go": _GO_OLD, "app/app.go": _GO_USER}))
+        global_signals, per_package = analyze_deprecations(sources).signals()
+
+        assert global_signals == {"deprecated_symbols": 1.0, "deprecated_call_sites": 2.0}
+        assert per_package == {"app": {"deprecated_call_sites": 2.0}}
+
+    def test_no_deprecations(self, write_files):
+        sources = load_sources(write_files({"a.py": "def f():\n    return 1\n"}))
+        report = analyze_deprecations(sources)
+
+        assert report.symbols == []
+        assert report.call_sites == 0
@@ -0,0 +1,146 @@
+"""Tests for the Go error hygiene rules."""
+
+from pathlib import Path
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.errors import ERROR_HYGIENE_TYPE, analyze_errors, to_findings
+
+_FIXTURE = Path(__file__).parent.parent / "fixtures" / "polyglot_baseline"
+
+_GO = """package store
+
+func Save(db *DB) error {
+	err := db.Flush()
+	_ = err
+	if err := db.Sync(); err != nil {
+		// best effort
+	}
+	if closeErr := db.Close(); closeErr != nil {}
+	if err != nil {
+		return fmt.Errorf("save failed: %v", err)
+	}
+	if err != nil && err.Error() == "timeout" {
+		return errors.New(err.Error())
+	}
+	switch err.Error() {
+	}
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("save %s: %w", "x", err) // err.Error() == "x"
+	}
+	return fmt.Errorf("bad code %d", errorCode)
+}
+"""
+
+
+def _rules(report):
+    return [(i.line, i.rule, i.subject) for i in sorted(report.issues, key=lambda i: i.line)]
+
+
+class TestAnalyzeErrors:
+    def test_rules(self):
+        report = analyze_errors({"store/store.go": _GO})
+
+        assert _rules(report) == [
+            (5, "swallowed_error", "err"),
+            (6, "empty_error_branch", "err"),
+            (9, "empty_error_branch", "closeErr"),
+            (11, "unwrapped_error", "err"),
+            (13, "string_matched_error", "timeout"),
+            (14, "unwrapped_error", "err"),
+            (16, "string_matched_error", "err"),
+        ]
+        assert report.issues[0].rule == "swallowed_error"
+        assert report.checks == {"store": 4}
+        assert report.typed_checks == {"store": 1}
+
+    def test_handled_errors_are_clean(self):
+        source = (
+            "package api\n\nfunc Load() error {\n\tif err := f(); err != nil {\n"
+            "\t\treturn fmt.Errorf(\"load: %w\", err)\n\t}\n"
+            "\tif errors.Is(err, ErrGone) {\n\t\treturn nil\n\t}\n"
+            "\treturn fmt.Errorf(\"bad id %d\", id)\n}\n"
+        )
+        report = analyze_errors({"api/load.go": source})
+
+        assert report.issues == []
+        assert report.signals() == (
+            {"error_checks": 1.0, "error_hygiene_issues": 0.0},
+            {"api": {"error_checks": 1.0, "error_hygiene_issues": 0.0}},
+        )
+
+    def test_other_languages_and_tests_are_skipped(self):
+        contents = {"store/store_test.go": _GO, "store/store.py": "_ = err\n"}
+        report = analyze_errors(contents)
+
+        assert report.issues == [] and report.checks == {}
+        assert report.signals() == ({}, {})
+
+    def test_fixture_user_service(self):
+        report = analyze_errors(load_sources(_FIXTURE).content)
+
+        user = [i for i in report.issues if i.path.endswith("services/user_service.go")]
+        assert [(i.line, i.rule, i.subject) for i in user] == [
+            (31, "string_matched_error", "not found"),
+            (46, "string_matched_error", "not found"),
+        ]
+        assert report.by_package()["go_backend/services"] == 4
+
+
+def test_to_findings_grouped_per_rule_and_subject():
+    report = analyze_errors({"store/store.go": _GO})
+    findings = {f.identity_hint: f for f in to_findings(report.issues)}
+
+    assert set(findings) == {
+        "swallowed_error:err",
+        "empty_error_branch:err",
+        "empty_error_branch:closeErr",
+        "string_matched_error:timeout",
+        "string_matched_error:err",
+        "unwrapped_error:err",
+    }
+    unwrapped = findings["unwrapped_error:err"]
+    assert unwrapped.finding_type == ERROR_HYGIENE_TYPE
+    assert unwrapped.title == "Error err is formatted into a new error without %w in store/store.go"
+    assert unwrapped.evidence[1].description == "lines 11, 14"
+    assert "%w" in unwrapped.suggestion
+    # Line 11 formats err with %v; line 14 copies its message, which has no mechanical fix
+    (patch,) = unwrapped.patches
+    assert (patch.path, patch.start_line, patch.end_line) == ("store/store.go", 11, 11)
+    assert patch.replacement == '\t\treturn fmt.Errorf("save failed: %w", err)'
+    assert findings["swallowed_error:err"].patches == []
+
+
+def test_wrap_fix_only_when_the_verb_lines_up():
+    source = (
+        "package api\n\nfunc Load() error {\n"
+        '\treturn fmt.Errorf("load %s: %v", name, err) // retried\n'
+        '\treturn fmt.Errorf("load %d: %d", id, err)\n'
+        '\treturn fmt.Errorf("load %*d: %v", 3, id, err)\n'
+        '\treturn fmt.Errorf("load %v: %v", wrap(a, b), err)\n'
+        "}\n"
+    )
+    report = analyze_errors({"api/load.go": source})
+
+    assert [(i.line, i.fix) for i in report.issues] == [
+        (4, '\treturn fmt.Errorf("load %s: %w", name, err) // retried'),
+        (5, ""),
+        (6, ""),
+        (7, '\treturn fmt.Errorf("load %v: %w", wrap(a, b), err)'),
+    ]
+
+
+def test_error_argument_preferred_over_err_like_names():
+    source = (
+        "package api\n\nfunc Load() error {\n"
+        '\treturn fmt.Errorf("load %s: %v", errMsg, err)\n'
+        '\treturn fmt.Errorf("load %v: %v", wrapErr(x), loadErr)\n'
+        '\treturn fmt.Errorf("load %d: %s", errCount, err.Error())\n'
+        "}\n"
+    )
+    report = analyze_errors({"api/load.go": source})
+
+    assert [(i.line, i.subject, i.fix) for i in report.issues] == [
+        (4, "err", '\treturn fmt.Errorf("load %s: %w", errMsg, err)'),
+        (5, "loadErr", '\treturn fmt.Errorf("load %v: %w", wrapErr(x), loadErr)'),
+        (6, "err", ""),
+    ]
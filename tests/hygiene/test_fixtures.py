@@ -0,0 +1,84 @@
+"""Tests for stale and oversized test fixture detection."""
+
+from shannon_insight.hygiene.fixtures import collect_fixtures, fixture_root
+
+
+def _repo(write_files, files):
+    return collect_fixtures(write_files(files), list(files))
+
+
+class TestFixtureRoot:
+    def test_fixture_directories_and_golden_files(self):
+        assert fixture_root("tests/fixtures/api/user.json") == "tests/fixtures"
+        assert fixture_root("pkg/parser/testdata/expr.txt") == "pkg/parser/testdata"
+        assert fixture_root("cmd/output.golden") == "cmd"
+        assert fixture_root("tests/test_api.py") is None
+
+
+class TestCollectFixtures:
+    def test_unreferenced_fixtures_are_stale(self, write_files):
+        report = _repo(
+            write_files,
+            {
+                "tests/test_api.py": 'load("user.json")\ngolden("render")\n',
+                "tests/fixtures/user.json": "{}",
+                "tests/fixtures/orders.json": "[]",
+                "tests/golden/render.txt": "ok",
+                "README.md": "orders.json is documented, not read",
+            },
+        )
+
+        assert [f.path for f in report.stale()] == ["tests/fixtures/orders.json"]
+        fixture = next(f for f in report.fixtures if f.path == "tests/fixtures/user.json")
+        assert fixture.referenced_by == ["tests/test_api.py"]
+
+    def test_sample_project_is_referenced_by_its_directory(self, write_files):
+        report = _repo(
+            write_files,
+            {
+                "tests/test_scan.py": 'analyze(FIXTURES / "polyglot")\n',
+                "tests/fixtures/polyglot/app.py": "import util\n",
+                "tests/fixtures/polyglot/lib/util.py": "x = 1\n",
+                "tests/fixtures/legacy/main.go": "package main\n",
+            },
+        )
+
+        assert [f.path for f in report.stale()] == ["tests/fixtures/legacy/main.go"]
+
+    def test_listing_the_fixture_directory_references_every_file(self, write_files):
+        files = {
+            "tests/test_cases.py": 'CASES = sorted((HERE / "testdata").glob("*.txt"))\n',
+            "tests/testdata/a.txt": "a",
+            "tests/testdata/b.txt": "b",
+        }
+        assert _repo(write_files, files).stale() == []
+
+        files["tests/test_cases.py"] = 'HERE / "testdata"\n'
+        assert len(_repo(write_files, files).stale()) == 2
+
+    def test_jest_snapshot_follows_its_test_file(self, write_files):
+        report = _repo(
+            write_files,
+            {
+                "src/Button.test.js": "it('renders', () => {})\n",
+                "src/__snapshots__/Button.test.js.snap": "exports[`renders`] = `<b/>`;\n",
+                "src/__snapshots__/Link.test.js.snap": "exports[`renders`] = `<a/>`;\n",
+            },
+        )
+
+        assert [f.path for f in report.stale()] == ["src/__snapshots__/Link.test.js.snap"]
+
+    def test_fixture_dwarfing_its_test_is_oversized(self, write_files):
+        report = _repo(
+            write_files,
+            {
+                "tests/test_dump.py": 'read("dump.sql")\n',
+                "tests/fixtures/dump.sql": "INSERT INTO t VALUES (1);\n" * 4000,
+            },
+        )
+
+        (fixture,) = report.oversized()
+        assert fixture.path == "tests/fixtures/dump.sql"
+        assert fixture.ratio > 1000
+        roots = report.to_dict()["roots"]
+        assert [(r["root"], r["files"], r["stale"]) for r in roots] == [("tests/fixtures", 1, 0)]
@@ -0,0 +1,204 @@
+"""Tests for formatter drift detection."""
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.formatting import (
+    FormatStyle,
+    analyze_formatting,
+    check_file,
+    load_style,
+)
+
+_GO = """package shop
+
+import "fmt"
+
+func Total(items []int) int {
+	sum := 0
+	for _, n := range items {
+		if n > 0 {
+			sum += n
+		} else {
+			fmt.Println("skip")
+		}
+	}
+	handlers := map[string]func(int){
+		"a": nil,
+	}
+	_ = handlers
+	return sum
+}
+"""
+
+_GO_DRIFTED = """package shop
+
+func Total(items []int) int {
+    sum := 0;
+	if(sum > 0){
+		return sum\x20
+	}
+
+
+	return 0
+}"""
+
+_PY = '''"""Shop totals."""
+
+import os
+
+LIMIT = 10
+
+
+def total(items, *, limit=LIMIT):
+    """Sum positive items."""
+    # Skip negatives
+    return sum(n for n in items[:limit] if n > 0)
+
+
+class Cart:
+    name: str = "cart"
+
+    def add(self, item):
+        return self.items.append(item)
+'''
+
+_PY_DRIFTED = """import os
+def total(items, limit = 10):
+    return sum(items[ : limit])
+class Cart:
+    name='cart'  #cart
+    def add(self, item):
+        pass
+"""
+
+_JS = """import { total } from "./shop";
+
+export function render(items) {
+  const rows = [
+    "a",
+    "b",
+  ];
+  if (items.length) {
+    return total(items, rows);
+  }
+  return null;
+}
+"""
+
+_JS_DRIFTED = """import { total } from './shop'
+
+export function render(items){
+  const rows = [
+    "a",
+    "b"
+  ];
+  if(items.length) {
+   return total(items, rows);
+  }
+}
+"""
+
+
+class TestCheckFile:
+    def test_go_gofmt_rules(self):
+        assert check_file("shop.go", "go", _GO, FormatStyle()).issues == {}
+
+        drift = check_file("shop.go", "go", _GO_DRIFTED, FormatStyle())
+
+        assert drift.issues == {
+            4: "indent",
+            5: "spacing",
+            6: "trailing-space",
+            10: "blank-lines",
+            11: "final-newline",
+        }
+        assert drift.formatter == "gofmt"
+
+    def test_python_black_rules(self):
+        assert check_file("shop.py", "python", _PY, FormatStyle()).issues == {}
+
+        drift = check_file("shop.py", "python", _PY_DRIFTED, FormatStyle())
+
+        assert drift.issues == {
+            2: "spacing",
+            3: "spacing",
+            4: "blank-lines",
+            5: "quotes",
+            6: "blank-lines",
+        }
+        assert drift.rules() == {"blank-lines": 2, "spacing": 2, "quotes": 1}
+
+    def test_javascript_prettier_rules(self):
+        assert check_file("shop.js", "javascript", _JS, FormatStyle()).issues == {}
+
+        drift = check_file("shop.js", "javascript", _JS_DRIFTED, FormatStyle())
+
+        assert drift.issues == {
+            1: "quotes",
+            3: "spacing",
+            6: "trailing-comma",
+            8: "spacing",
+            9: "indent",
+        }
+
+
+class TestFormatStyle:
+    def test_reads_black_and_prettier_options(self, tmp_path):
+        (tmp_path / "pyproject.toml").write_text(
+            "[tool.black]\nline-length = 100\nskip-string-normalization = true\n"
+        )
+        (tmp_path / ".prettierrc").write_text("semi: false\nsingleQuote: true\ntabWidth: 4\n")
+
+        style = load_style(tmp_path)
+
+        assert (style.python_line_length, style.python_quote) == (100, None)
+        assert (style.js_semi, style.js_quote, style.js_tab_width) == (False, "'", 4)
+        assert check_file("a.py", "python", "x = 'a'\n", style).issues == {}
+        assert check_file("a.js", "javascript", "const x = 'a'\n", style).issues == {}
+        assert check_file("a.js", "javascript", 'const x = "a";\n', style).issues == {
+            1: "quotes"
+        }
+
+    def test_ruff_settings_and_defaults(self, tmp_path):
+        assert load_style(tmp_path) == FormatStyle()
+
+        (tmp_path / "pyproject.toml").write_text(
+            '[tool.ruff]\nline-length = 120\n\n[tool.ruff.format]\nquote-style = "single"\n'
+        )
+
+        style = load_style(tmp_path)
+
+        assert (style.python_line_length, style.python_quote) == (120, "'")
+
+
+class TestAnalyzeFormatting:
+    def test_counts_drift_per_language_and_package(self, write_files):
+        root = write_files(
+            {
+                "shop/shop.go": _GO_DRIFTED,
+                "shop/cart.py": _PY,
+                "web/app.js": _JS_DRIFTED,
+                "web/vendor.min.js": "var a='b'" + ";" * 2000 + "\n",
+            },
+        )
+
+        report = analyze_formatting(load_sources(root))
+
+        assert [f.path for f in report.files] == ["shop/shop.go", "web/app.js", "shop/cart.py"]
+        assert report.by_language()["python"]["drifted"] == 0
+        global_signals, per_package = report.signals()
+        assert global_signals == {"format_drift_files": 2.0, "format_drift_lines": 10.0}
+        assert per_package == {
+            "shop": {"format_drift_lines": 5.0},
+            "web": {"format_drift_lines": 5.0},
+        }
+
+    def test_formatter_suppressions_are_honoured(self, write_files):
+        root = write_files(
+            {
+                "table.py": "# fmt: off\nGRID = [\n  1,0,\n  0,1,\n]\n# fmt: on\n"
+                "x = [1,2]  # fmt: skip\n",
+                "table.js": "// prettier-ignore\nconst grid = [1,0,  0,1]\nconst y = 1;\n",
+            },
+        )
+
+        assert analyze_formatting(load_sources(root)).drifted() == []
@@ -0,0 +1,68 @@
+"""Tests for the domain glossary and synonym conflicts."""
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.glossary import analyze_glossary, canonical_term, identifier_words
+
+
+class TestCanonicalTerm:
+    def test_abbreviations(self):
+        assert canonical_term("org") == "organization"
+        assert canonical_term("repo") == "repository"
+
+    def test_british_spelling(self):
+        assert canonical_term("organisation") == "organization"
+        assert canonical_term("colour") == "color"
+        assert canonical_term("analyse") == "analyze"
+
+    def test_plain_words_unchanged(self):
+        assert canonical_term("invoice") == "invoice"
+        assert canonical_term("raise") == "raise"
+
+
+class TestIdentifierWords:
+    def test_splits_and_skips_strings(self):
+        words = identifier_words('orgID := lookupOrganisation("org_name")')
+        assert words == ["org", "id", "lookup", "organisation"]
+
+
+class TestAnalyzeGlossary:
+    def test_conflict_within_package(self, tmp_path):
+        (tmp_path / "billing").mkdir()
+        (tmp_path / "billing" / "a.py").write_text(
+            "def load_org(org_id: int) -> int:\n    return org_id\n"
+        )
+        (tmp_path / "billing" / "b.py").write_text(
+            "def organisation_name(organisation) -> str:\n    return organisation.name\n"
+        )
+
+        report = analyze_glossary(load_sources(tmp_path))
+
+        assert len(report.conflicts) == 1
+        conflict = report.conflicts[0]
+        assert conflict.package == "billing"
+        assert conflict.canonical == "organization"
+        assert set(conflict.variants) == {"org", "organisation"}
+        assert conflict.examples["org"] == "billing/a.py:1"
+
+    def test_variants_in_different_packages_do_not_conflict(self, tmp_path):
+        (tmp_path / "a").mkdir()
+        (tmp_path / "b").mkdir()
+        (tmp_path / "a" / "x.py").write_text("def get_org(org) -> int:\n    return org\n")
+        (tmp_path / "b" / "y.py").write_text(
+            "def get_organization(organization) -> int:\n    return organization\n"
+        )
+
+        report = analyze_glossary(load_sources(tmp_path))
+
+        assert report.conflicts == []
+        org = next(t for t in report.terms if t.canonical == "organization")
+        assert set(org.variants) == {"org", "organization"}
+
+    def test_generic_words_excluded(self, tmp_path):
+        (tmp_path / "x.py").write_text("def get_invoice(self) -> int:\n    return self.invoice\n")
+
+        report = analyze_glossary(load_sources(tmp_path))
+
+        terms = {t.canonical for t in report.terms}
+        assert "invoice" in terms
+        assert not terms & {"get", "self", "return", "def", "int"}
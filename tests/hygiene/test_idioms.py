@@ -0,0 +1,107 @@
+"""Tests for the idiom consistency report."""
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.idioms import analyze_idioms, function_casing
+
+_GO_WRAP = """package {pkg}
+
+import "fmt"
+
+func NewStore() *Store {{
+	return &Store{{}}
+}}
+
+func (s *Store) Load(id string) error {{
+	if err := s.db.Get(id); err != nil {{
+		return fmt.Errorf("load %s: %w", id, err)
+	}}
+	return nil
+}}
+"""
+
+_GO_DIVERGENT = """package legacy
+
+func MakeCache() *Cache {
+	return &Cache{}
+}
+
+func (cache *Cache) Load(id string) error {
+	if err := cache.db.Get(id); err != nil {
+		panic(err)
+	}
+	return nil
+}
+"""
+
+
+def _repo(tmp_path):
+    for pkg in ("api", "store", "jobs"):
+        (tmp_path / pkg).mkdir()
+        (tmp_path / pkg / "store.go").write_text(_GO_WRAP.format(pkg=pkg))
+    (tmp_path / "legacy").mkdir()
+    (tmp_path / "legacy" / "cache.go").write_text(_GO_DIVERGENT)
+    return tmp_path
+
+
+class TestFunctionCasing:
+    def test_snake_and_camel(self):
+        assert function_casing("load_user", "python") == "snake_case"
+        assert function_casing("loadUser", "typescript") == "camelCase"
+        assert function_casing("LoadUser", "java") == "PascalCase"
+
+    def test_go_exported_and_unexported_are_equivalent(self):
+        assert function_casing("LoadUser", "go") == "mixedCaps"
+        assert function_casing("loadUser", "go") == "mixedCaps"
+
+    def test_single_word_is_ambiguous(self):
+        assert function_casing("load", "python") is None
+        assert function_casing("__init__", "python") is None
+
+
+class TestAnalyzeIdioms:
+    def test_norms_follow_majority(self, tmp_path):
+        report = analyze_idioms(load_sources(_repo(tmp_path)))
+        norms = {(n.dimension, n.language): n for n in report.norms}
+
+        assert norms[("error_handling", "go")].dominant == "wrap_fmt"
+        assert norms[("receiver_naming", "go")].dominant == "short"
+        assert norms[("constructor", "go")].dominant == "New"
+        assert norms[("constructor", "go")].consistency == 0.75
+
+    def test_divergent_package_ranks_first(self, tmp_path):
+        report = analyze_idioms(load_sources(_repo(tmp_path)))
+
+        top = report.packages[0]
+        assert top.package == "legacy"
+        # Everything but function casing deviates from the norm
+        assert top.divergence > 0.5
+        assert all(d.dimension != "function_casing" for d in top.deviations)
+        variants = {(d.dimension, d.variant) for d in top.deviations}
+        assert variants == {
+            ("error_handling", "panic"),
+            ("receiver_naming", "long"),
+            ("constructor", "Make"),
+        }
+        assert all(p.divergence == 0.0 for p in report.packages[1:])
+
+    def test_python_except_styles(self, tmp_path):
+        good = "def f() -> int:\n    try:\n        g()\n    except ValueError:\n        pass\n"
+        (tmp_path / "a.py").write_text(good)
+        (tmp_path / "b.py").write_text(good)
+        (tmp_path / "c.py").write_text(good)
+        (tmp_path / "pkg").mkdir()
+        (tmp_path / "pkg" / "d.py").write_text(good.replace("except ValueError", "except"))
+
+        report = analyze_idioms(load_sources(tmp_path))
+
+        assert report.packages[0].package == "pkg"
+        assert report.packages[0].deviations[0].variant == "bare_except"
+        assert report.packages[0].deviations[0].example == "pkg/d.py:4"
+
+    def test_too_few_observations_have_no_norm(self, tmp_path):
+        (tmp_path / "only.go").write_text(_GO_DIVERGENT)
+
+        report = analyze_idioms(load_sources(tmp_path))
+
+        assert report.norms == []
+        assert report.packages == []
@@ -0,0 +1,82 @@
+"""Tests for the license header check."""
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.license import (
+    analyze_license,
+    check_header,
+    header_pattern,
+    insert_header,
+    render_header,
+)
+
+_TEMPLATE = """
+Copyright {year} {owner}
+SPDX-License-Identifier: Apache-2.0
+"""
+
+
+def _patterns(owner=""):
+    return header_pattern(_TEMPLATE, owner)
+
+
+class TestCheckHeader:
+    def test_line_comments(self):
+        content = (
+            "// Copyright 2021 Acme Inc\n// SPDX-License-Identifier: Apache-2.0\n\npackage a\n"
+        )
+
+        assert check_header(content, _patterns("Acme Inc")) is None
+
+    def test_block_comment_and_year_range(self):
+        content = (
+            "/*\n * Copyright 2019-2024 Acme Inc\n * SPDX-License-Identifier: Apache-2.0\n */\n"
+        )
+
+        assert check_header(content, _patterns()) is None
+
+    def test_after_shebang(self):
+        content = (
+            "#!/usr/bin/env python\n# Copyright 2024 Acme\n# SPDX-License-Identifier: Apache-2.0\n"
+        )
+
+        assert check_header(content, _patterns()) is None
+
+    def test_missing(self):
+        issue = check_header("// Package a does things.\npackage a\n", _patterns())
+
+        assert issue.status == "missing"
+
+    def test_malformed_owner(self):
+        content = "# Copyright 2024 Someone Else\n# SPDX-License-Identifier: Apache-2.0\n"
+        issue = check_header(content, _patterns("Acme Inc"))
+
+        assert issue.status == "malformed"
+        assert issue.detail == "Copyright 2024 Someone Else"
+
+
+class TestFix:
+    def test_render_and_insert_roundtrip(self):
+        header = render_header(_TEMPLATE, "python", "Acme Inc", 2025)
+        fixed = insert_header("#!/usr/bin/env python\nimport os\n", header)
+
+        assert fixed.startswith("#!/usr/bin/env python\n\n# Copyright 2025 Acme Inc\n")
+        assert fixed.endswith("\nimport os\n")
+        assert check_header(fixed, _patterns("Acme Inc")) is None
+
+    def test_unknown_language(self):
+        assert render_header(_TEMPLATE, "unknown", "Acme", 2025) is None
+
+
+class TestAnalyzeLicense:
+    def test_by_package(self, tmp_path):
+        (tmp_path / "a").mkdir()
+        (tmp_path / "a" / "ok.py").write_text(
+            "# Copyright 2024 Acme\n# SPDX-License-Identifier: Apache-2.0\nx = 1\n"
+        )
+        (tmp_path / "a" / "bad.py").write_text("# Copyright Acme\nx = 1\n")
+        (tmp_path / "b.py").write_text("x = 1\n")
+
+        report = analyze_license(load_sources(tmp_path), _TEMPLATE, "Acme")
+
+        assert report.checked == 3
+        assert report.by_package() == {".": {"missing": 1}, "a": {"malformed": 1}}
@@ -0,0 +1,119 @@
+"""Tests for magic numbers and repeated literals."""
+
+from pathlib import Path
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.literals import REPEATED_LITERAL_TYPE, analyze_literals, to_findings
+
+_FIXTURE = Path(__file__).parent.parent / "fixtures" / "polyglot_baseline"
+
+_GO = """package auth
+
+import (
+\t"context"
+\t"time"
+)
+
+const (
+\tmaxRetries = 5
+\tissuer = "shannon"
+)
+
+type Claims struct {
+\tUserID int64 `json:"user_id"`
+}
+
+func Issue(ctx context.Context) map[string]any {
+\t// expires in 3600 seconds
+\tctx = context.WithValue(ctx, "user_id", 1)
+\tfor i := 0; i < 2; i++ {}
+\treturn map[string]any{"user_id": 7, "expires_in": 3600, "ratio": 0.75, "mask": 0x1F}
+}
+"""
+
+_PY = '''"""Sessions."""
+
+TIMEOUT = 3600
+__all__ = ["Session"]
+
+
+class Session:
+    """One user session."""
+
+    LIMIT = 50
+
+    def ttl(self, key="user_id"):
+        # 3600 in a comment
+        return {"user_id": key, "expires_in": 3600, "n": 2, "f": f"user_id {key}"}
+
+    def renew(self):
+        GRACE = 300
+        return GRACE
+'''
+
+
+def _found(report, path):
+    (f,) = [f for f in report.files if f.path == path]
+    return [(lit.line, lit.kind, lit.value) for lit in f.literals]
+
+
+class TestAnalyzeLiterals:
+    def test_go(self):
+        report = analyze_literals({"auth/token.go": "go"}, {"auth/token.go": _GO})
+        assert _found(report, "auth/token.go") == [
+            (19, "string", "user_id"),
+            (21, "number", "7"),
+            (21, "number", "3600"),
+            (21, "number", "0.75"),
+            (21, "number", "0x1F"),
+            (21, "string", "user_id"),
+            (21, "string", "expires_in"),
+            (21, "string", "ratio"),
+            (21, "string", "mask"),
+        ]
+        (f,) = report.files
+        assert (f.magic_numbers, f.strings, f.repeated_strings) == (4, 5, 1)
+
+    def test_python(self):
+        report = analyze_literals({"auth/session.py": "python"}, {"auth/session.py": _PY})
+        # Only module-level upper-case names are constants
+        assert _found(report, "auth/session.py") == [
+            (10, "number", "50"),
+            (12, "string", "user_id"),
+            (14, "string", "user_id"),
+            (14, "string", "expires_in"),
+            (14, "number", "3600"),
+            (17, "number", "300"),
+        ]
+        # Dedents and the end marker after the last line are not code lines
+        assert report.files[0].code_lines == 11
+
+    def test_repeated_across_files(self):
+        languages = {"auth/token.go": "go", "auth/session.py": "python", "auth/x_test.go": "go"}
+        contents = {"auth/token.go": _GO, "auth/session.py": _PY, "auth/x_test.go": _GO}
+        report = analyze_literals(languages, contents, threshold=3)
+
+        assert [(r.kind, r.value, len(r.uses)) for r in report.repeated] == [
+            ("string", "user_id", 4)
+        ]
+        assert report.signals() == (
+            {"magic_numbers": 7.0, "repeated_literals": 1.0},
+            {"auth": {"magic_numbers": 7.0, "repeated_literals": 1.0}},
+        )
+        (finding,) = to_findings(report)
+        assert finding.finding_type == REPEATED_LITERAL_TYPE
+        assert finding.title == 'String literal "user_id" is used 4 times in 2 files'
+        assert finding.files == ["auth/session.py", "auth/token.go"]
+        assert finding.severity == 0.3415
+
+
+def test_polyglot_fixture_context_key():
+    sources = load_sources(_FIXTURE)
+    languages = {path: syntax.language for path, syntax in sources.syntax.items()}
+    report = analyze_literals(languages, sources.content)
+    repeated = {(r.kind, r.value): r for r in report.repeated}
+
+    user_id = repeated["string", "user_id"]
+    assert "go_backend/handlers/middleware.go" in user_id.files
+    # The struct tags `json:"user_id"` are not uses
+    assert "go_backend/models/organization.go" not in user_id.files
@@ -0,0 +1,115 @@
+"""Tests for per-language naming convention rules."""
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.naming import (
+    abbreviation_forms,
+    analyze_naming,
+    case_matches,
+    resolve_rules,
+    split_words,
+    stutters,
+)
+
+
+class TestHelpers:
+    def test_split_words_keeps_initialisms(self):
+        assert split_words("HTTPServer") == ["HTTP", "Server"]
+        assert split_words("getUserID") == ["get", "User", "ID"]
+        assert split_words("user_id") == ["user", "id"]
+
+    def test_case_styles(self):
+        assert case_matches("load_user", "snake_case")
+        assert case_matches("_private_helper", "snake_case")
+        assert not case_matches("loadUser", "snake_case")
+        assert case_matches("HTTPServer", "PascalCase")
+        assert not case_matches("httpServer", "PascalCase")
+        assert case_matches("loadUser", "mixedCaps")
+        assert not case_matches("load_user", "mixedCaps")
+
+    def test_abbreviation_forms(self):
+        assert list(abbreviation_forms("UserID")) == [("ID", "upper")]
+        assert list(abbreviation_forms("UserId")) == [("ID", "capitalized")]
+        assert list(abbreviation_forms("idToken")) == []
+
+    def test_stutter(self):
+        assert stutters("UserRepository", "repository")
+        assert stutters("NewCache", "cache")
+        assert not stutters("Repository", "repository")
+        assert not stutters("UserStore", "repository")
+
+
+class TestResolveRules:
+    def test_overrides_merge_with_defaults(self):
+        rules = resolve_rules({"python": {"abbreviations": "upper"}})
+        assert rules["python"]["abbreviations"] == "upper"
+        assert rules["python"]["function"] == "snake_case"
+
+    def test_invalid_style_rejected(self):
+        with pytest.raises(ValueError, match="naming_rules.go.function"):
+            resolve_rules({"go": {"function": "kebab"}})
+
+    def test_config_field(self):
+        assert AnalysisConfig().naming_rules == {}
+        with pytest.raises(ValueError, match="naming_rules"):
+            AnalysisConfig(naming_rules={"go": "upper"})
+
+
+_GO = """package repository
+
+type UserRepository struct{}
+
+func NewUserRepository() *UserRepository {
+	return &UserRepository{}
+}
+
+func FindUserId(id string) string {
+	return id
+}
+
+func load_all() {}
+"""
+
+
+class TestAnalyzeNaming:
+    def test_go_rules(self, tmp_path):
+        (tmp_path / "repo.go").write_text(_GO)
+
+        report = analyze_naming(load_sources(tmp_path))
+        by_rule = {(i.rule, i.name) for i in report.issues}
+
+        assert ("stutter", "UserRepository") in by_rule
+        assert ("stutter", "NewUserRepository") in by_rule
+        assert ("abbreviation", "FindUserId") in by_rule
+        assert ("case", "load_all") in by_rule
+        assert all(i.severity < 0.4 for i in report.issues)
+
+    def test_type_line_is_resolved(self, tmp_path):
+        (tmp_path / "repo.go").write_text(_GO)
+
+        report = analyze_naming(load_sources(tmp_path))
+        stutter = next(i for i in report.issues if i.name == "UserRepository")
+
+        assert stutter.line == 3
+
+    def test_consistent_abbreviations_follow_majority(self, tmp_path):
+        (tmp_path / "a.py").write_text(
+            "class UserID:\n    pass\n\n\nclass OrgID:\n    pass\n\n\nclass TeamId:\n    pass\n"
+        )
+
+        report = analyze_naming(load_sources(tmp_path))
+
+        assert [(i.rule, i.name) for i in report.issues] == [("abbreviation", "TeamId")]
+        assert "'ID'" in report.issues[0].message
+
+    def test_rules_can_be_disabled(self, tmp_path):
+        (tmp_path / "repo.go").write_text(_GO)
+
+        report = analyze_naming(
+            load_sources(tmp_path),
+            {"go": {"stutter": "off", "abbreviations": "off", "function": "any"}},
+        )
+
+        assert report.issues == []
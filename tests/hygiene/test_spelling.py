@@ -0,0 +1,70 @@
+"""Tests for the identifier/comment/string spellcheck."""
+
+# spelling: ignore-file
+
+from shannon_insight.hygiene import load_sources
+from shannon_insight.hygiene.spelling import analyze_spelling
+
+_GO = """package api
+
+// Handler recieves the reponse.
+func RecieveMessage(msg string) string {
+	seperator := "untill done"
+	return seperator + msg
+}
+
+func parseAdress() {}
+"""
+
+
+def _write(tmp_path, name="api.go", text=_GO):
+    (tmp_path / name).write_text(text)
+    return load_sources(tmp_path)
+
+
+class TestAnalyzeSpelling:
+    def test_kinds(self, tmp_path):
+        report = analyze_spelling(_write(tmp_path))
+        found = {(i.kind, i.word) for i in report.issues}
+
+        assert ("comment", "reponse") in found
+        assert ("api", "recieve") in found
+        assert ("string", "untill") in found
+        assert ("identifier", "seperator") in found
+        assert ("identifier", "adress") in found
+
+    def test_exported_definitions_first(self, tmp_path):
+        report = analyze_spelling(_write(tmp_path))
+
+        assert report.issues[0].kind == "api"
+        assert report.issues[0].token == "RecieveMessage"
+        assert report.issues[0].suggestion == "receive"
+
+    def test_unexported_definition_is_identifier(self, tmp_path):
+        report = analyze_spelling(_write(tmp_path))
+
+        adress = next(i for i in report.issues if i.word == "adress")
+        assert adress.kind == "identifier"
+        assert adress.line == 9
+
+    def test_allowlist(self, tmp_path):
+        report = analyze_spelling(_write(tmp_path), allowlist=["Seperator", "untill"])
+        words = {i.word for i in report.issues}
+
+        assert "seperator" not in words
+        assert "untill" not in words
+        assert "recieve" in words
+
+    def test_ignore_markers(self, tmp_path):
+        text = "x = 1  # recieve\ny = 2  # recieve  spelling: ignore\n"
+        report = analyze_spelling(_write(tmp_path, "a.py", text))
+        assert [i.line for i in report.issues] == [1]
+
+        report = analyze_spelling(_write(tmp_path, "a.py", "# spelling: ignore-file\n" + text))
+        assert report.issues == []
+
+    def test_word_summary(self, tmp_path):
+        report = analyze_spelling(_write(tmp_path))
+
+        words = {w: n for w, _, n in report.words()}
+        assert words["seperator"] == 2
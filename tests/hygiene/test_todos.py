@@ -0,0 +1,129 @@
+"""Tests for TODO/FIXME comment debt tracking."""
+
+import os
+import subprocess
+
+from shannon_insight.hygiene.todos import (
+    COMMENT_DEBT_TYPE,
+    DebtItem,
+    collect_comment_debt,
+    debt_signals,
+    extract_markers,
+    identity_key,
+    lifecycle_states,
+    summarize_by_package,
+    to_findings,
+)
+
+_PY = """def load():
+    # TODO(alice): cache this
+    return 1  # FIXME: off by one
+    # TODO(alice): cache this
+"""
+
+_DAY = 86400
+_NOW = 1_700_000_000
+
+
+class TestExtractMarkers:
+    def test_tags_assignee_and_text(self):
+        items = extract_markers("pkg/a.py", _PY)
+
+        assert [(i.line, i.tag) for i in items] == [(2, "TODO"), (3, "FIXME"), (4, "TODO")]
+        assert items[0].assignee == "alice"
+        assert items[0].text == "cache this"
+        assert items[1].text == "off by one"
+
+    def test_fingerprint_survives_line_moves(self):
+        before = extract_markers("a.py", _PY)
+        after = extract_markers("a.py", "\n\n" + _PY)
+
+        assert [i.fingerprint for i in before] == [i.fingerprint for i in after]
+        assert after[0].line == before[0].line + 2
+
+    def test_duplicates_get_distinct_fingerprints(self):
+        items = extract_markers("a.py", _PY)
+
+        assert items[0].fingerprint != items[2].fingerprint
+
+    def test_ignores_words_outside_comments(self):
+        assert extract_markers("a.py", 'todo_list = "TODO items"\n') == []
+
+
+def _git(cwd, *args, date=None):
+    env = dict(os.environ, GIT_AUTHOR_NAME="Bob", GIT_AUTHOR_EMAIL="bob@example.com")
+    env.update(GIT_COMMITTER_NAME="Bob", GIT_COMMITTER_EMAIL="bob@example.com")
+    if date:
+        env.update(GIT_AUTHOR_DATE=date, GIT_COMMITTER_DATE=date)
+    subprocess.run(["git", *args], cwd=cwd, env=env, check=True, capture_output=True)
+
+
+class TestCollectCommentDebt:
+    def test_blame_age_and_owner(self, tmp_path):
+        _git(tmp_path, "init", "-q")
+        (tmp_path / "a.py").write_text("# HACK: temporary\nx = 1\n")
+        _git(tmp_path, "add", "a.py")
+        _git(tmp_path, "commit", "-q", "-m", "init", date=f"{_NOW - 10 * _DAY} +0000")
+
+        items = collect_comment_debt(tmp_path, {"a.py": "# HACK: temporary\nx = 1\n"}, now=_NOW)
+
+        assert len(items) == 1
+        assert items[0].author == "Bob"
+        assert items[0].owner == "Bob"
+        assert items[0].age_days == 10.0
+
+    def test_without_git(self, tmp_path):
+        items = collect_comment_debt(tmp_path, {"a.py": _PY}, use_git=False)
+
+        assert len(items) == 3
+        assert all(i.age_days is None for i in items)
+        assert items[0].owner == "alice"
+
+
+def _item(path, age, tag="TODO", text="x"):
+    return DebtItem(path=path, line=1, tag=tag, text=text, age_days=age, fingerprint=text)
+
+
+class TestSummaries:
+    def test_by_package(self):
+        items = [_item("a/x.py", 10), _item("a/y.py", 30, "FIXME"), _item("b/z.py", None)]
+        summaries = summarize_by_package(items)
+
+        assert summaries[0].package == "a"
+        assert summaries[0].count == 2
+        assert summaries[0].median_age_days == 20
+        assert summaries[0].by_tag == {"FIXME": 1, "TODO": 1}
+        assert summaries[1].median_age_days is None
+
+    def test_signals(self):
+        global_signals, per_package = debt_signals([_item("a/x.py", 10), _item("x.py", 50)])
+
+        assert global_signals == {"comment_debt_count": 2.0, "comment_debt_median_age_days": 30.0}
+        assert per_package["."]["comment_debt_count"] == 1.0
+
+    def test_severity_grows_with_age(self):
+        assert _item("a.py", 365).severity > _item("a.py", 0).severity
+        assert _item("a.py", 0, "FIXME").severity > _item("a.py", 0).severity
+
+
+class TestLifecycle:
+    def test_findings_have_distinct_identities(self):
+        items = extract_markers("a.py", _PY)
+        findings = to_findings(items)
+
+        assert all(f.finding_type == COMMENT_DEBT_TYPE for f in findings)
+        assert len({identity_key(i) for i in items}) == 3
+        assert findings[0].identity_hint == items[0].fingerprint
+
+    def test_states(self):
+        old, new = _item("a.py", 1, text="old"), _item("a.py", 1, text="new")
+        lifecycle = {
+            identity_key(old): {"finding_type": COMMENT_DEBT_TYPE, "current_status": "active"},
+            "gone": {"finding_type": COMMENT_DEBT_TYPE, "current_status": "resolved"},
+            "other": {"finding_type": "god_file", "current_status": "resolved"},
+        }
+
+        states, resolved = lifecycle_states([old, new], lifecycle)
+
+        assert states == ["persisting", "new"]
+        assert resolved == 1
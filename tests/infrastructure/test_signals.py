@@ -1,6 +1,6 @@
 """Tests for the Signal enum and registry (infrastructure/signals.py).
 
-Validates the single source of truth for all 64 signals:
+Validates the single source of truth for all 73 signals:
 - Enum completeness and value uniqueness
 - Registry completeness and metadata correctness
 - Collision detection (single-owner rule)
@@ -34,8 +34,8 @@ class TestSignalEnum:
     """Tests for the Signal enum itself."""
 
     def test_total_signal_count(self) -> None:
-        """There are exactly 64 signals in the enum."""
-        assert len(Signal) == 64
+        """There are exactly 73 signals in the enum."""
+        assert len(Signal) == 73
 
     def test_all_values_are_strings(self) -> None:
         """Every Signal value is a non-empty string."""
@@ -144,10 +144,11 @@ class TestSignalEnum:
             Signal.KNOWLEDGE_GINI,
             Signal.MODULE_BUS_FACTOR,
             Signal.MEAN_COGNITIVE_LOAD,
+            Signal.MODULE_MAINTAINABILITY,
             Signal.FILE_COUNT,
             Signal.HEALTH_SCORE,
         }
-        assert len(module) == 15
+        assert len(module) == 16
 
     def test_known_global_signals_exist(self) -> None:
         """Global signals are present."""
@@ -167,11 +168,11 @@ class TestSignalEnum:
         assert len(global_sigs) == 11
 
     def test_signal_scope_counts_add_up(self) -> None:
-        """38 file + 15 module + 11 global = 64 total."""
-        # 7 IR1 + 6 IR2 + 13 IR3 + 9 IR5t + 3 composites = 38 file
-        # 15 module + 11 global = 26
-        # 38 + 26 = 64
-        assert len(Signal) == 64
+        """46 file + 16 module + 11 global = 73 total."""
+        # 7 IR1 + 6 IR2 + 21 IR3 + 9 IR5t + 3 composites = 46 file
+        # 16 module + 11 global = 27
+        # 46 + 27 = 73
+        assert len(Signal) == 73
 
 
 # ---------------------------------------------------------------------------
@@ -188,8 +189,8 @@ class TestRegistry:
             assert signal in REGISTRY, f"Signal '{signal.value}' is not registered in REGISTRY"
 
     def test_registry_count(self) -> None:
-        """REGISTRY has exactly 64 entries."""
-        assert len(REGISTRY) == 64
+        """REGISTRY has the expected number of entries."""
+        assert len(REGISTRY) == 73
 
     def test_no_extra_entries(self) -> None:
         """REGISTRY has no entries that aren't Signal enum members."""
@@ -477,7 +478,7 @@ class TestHelperFunctions:
             assert sig in phase0, f"Signal {sig.value} should be in phase 0"
 
     def test_signals_by_phase_5_is_all(self) -> None:
-        """Phase 5 includes all 64 signals."""
+        """Phase 5 includes all 73 signals."""
         phase5 = signals_by_phase(5)
         assert phase5 == set(Signal)
 
@@ -494,12 +495,12 @@ class TestHelperFunctions:
     def test_signals_by_scope_file(self) -> None:
         """File-scope signals include the expected count."""
         file_signals = signals_by_scope("file")
-        assert len(file_signals) == 38
+        assert len(file_signals) == 46
 
     def test_signals_by_scope_module(self) -> None:
         """Module-scope signals include the expected count."""
         module_signals = signals_by_scope("module")
-        assert len(module_signals) == 15
+        assert len(module_signals) == 16
 
     def test_signals_by_scope_global(self) -> None:
         """Global-scope signals include the expected count."""
@@ -507,13 +508,13 @@ class TestHelperFunctions:
         assert len(global_signals) == 11
 
     def test_signals_by_scope_covers_all(self) -> None:
-        """File + module + global = all 64 signals."""
+        """File + module + global = all 73 signals."""
         file_s = signals_by_scope("file")
         module_s = signals_by_scope("module")
         global_s = signals_by_scope("global")
         assert file_s | module_s | global_s == set(Signal)
         # No overlaps
-        assert len(file_s) + len(module_s) + len(global_s) == 64
+        assert len(file_s) + len(module_s) + len(global_s) == 73
 
     def test_signals_by_polarity_coverage(self) -> None:
         """Every signal has exactly one polarity that is accounted for."""
@@ -521,7 +522,7 @@ class TestHelperFunctions:
         good = signals_by_polarity("high_is_good")
         neutral = signals_by_polarity("neutral")
         assert bad | good | neutral == set(Signal)
-        assert len(bad) + len(good) + len(neutral) == 64
+        assert len(bad) + len(good) + len(neutral) == 73
 
 
 # ---------------------------------------------------------------------------
@@ -692,6 +693,8 @@ class TestPolarityCorrectness:
         expected_good = {
             Signal.DOCSTRING_COVERAGE,
             Signal.SEMANTIC_COHERENCE,
+            Signal.MAINTAINABILITY_INDEX,
+            Signal.COMMENT_DENSITY,
             Signal.BUS_FACTOR,
             Signal.AUTHOR_ENTROPY,
             Signal.REFACTOR_RATIO,
@@ -701,6 +704,7 @@ class TestPolarityCorrectness:
             Signal.BOUNDARY_ALIGNMENT,
             Signal.ROLE_CONSISTENCY,
             Signal.MODULE_BUS_FACTOR,
+            Signal.MODULE_MAINTAINABILITY,
             Signal.HEALTH_SCORE,
             Signal.MODULARITY,
             Signal.FIEDLER_VALUE,
@@ -725,6 +729,7 @@ class TestPolarityCorrectness:
             Signal.DEPTH,
             Signal.COMMUNITY,
             Signal.COMPRESSION_RATIO,
+            Signal.TOKEN_ENTROPY,
             Signal.CHURN_TRAJECTORY,
             Signal.INSTABILITY,
             Signal.ABSTRACTNESS,
@@ -0,0 +1,65 @@
+"""Tests for the finders of analyzer reports in the store."""
+
+from shannon_insight.insights.finders import (
+    ReportFinder,
+    get_comment_debt_finder,
+    get_report_finders,
+)
+from shannon_insight.insights.store import AnalysisStore
+from shannon_insight.signals.parameters import FunctionParams
+
+
+def _wide(path: str, n_params: int) -> FunctionParams:
+    return FunctionParams(
+        path=path, name="f", line=1, params=tuple(f"p{i}" for i in range(n_params))
+    )
+
+
+class TestReportFinder:
+    def test_unset_slot_gives_no_findings(self):
+        assert ReportFinder("sql", lambda report, config: ["x"]).find(AnalysisStore()) == []
+
+    def test_failed_slot_gives_no_findings(self):
+        store = AnalysisStore()
+        store.sql.set_error("bad dump", produced_by="sql")
+
+        assert ReportFinder("sql", lambda report, config: ["x"]).find(store) == []
+
+    def test_converter_gets_report_and_config(self):
+        store = AnalysisStore()
+        store.literals.set("report", produced_by="literals")
+        seen = []
+        finder = ReportFinder("literals", lambda report, config: seen.append((report, config)))
+
+        finder.find(store)
+
+        assert seen == [("report", store.config)]
+
+    def test_requires_its_slot(self):
+        finder = ReportFinder("crypto", lambda report, config: [])
+
+        assert finder.name == "crypto"
+        assert finder.requires == {"crypto"}
+
+
+class TestReportFinders:
+    def test_one_per_store_slot(self):
+        store = AnalysisStore()
+        names = [finder.name for finder in get_report_finders()]
+
+        assert len(names) == len(set(names))
+        assert all(hasattr(store, name) for name in names)
+        assert "comment_debt" not in names
+        assert get_comment_debt_finder().name == "comment_debt"
+
+    def test_parameter_threshold_comes_from_config(self):
+        store = AnalysisStore()
+        store.parameters.set([_wide("a.py", 5), _wide("b.py", 8)], produced_by="parameters")
+        finder = next(f for f in get_report_finders() if f.name == "parameters")
+
+        findings = finder.find(store)
+
+        # Only b.py exceeds the default parameter_threshold
+        assert [f.files for f in findings if f.finding_type == "long_parameter_list"] == [
+            ["b.py"]
+        ]
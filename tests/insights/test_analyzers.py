@@ -0,0 +1,82 @@
+"""Tests for the optional report analyzers and their registration."""
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.insights.analyzers import OPTIONAL_ANALYZERS, get_default_analyzers
+from shannon_insight.insights.analyzers.functions import DeepNestingAnalyzer, ParameterAnalyzer
+from shannon_insight.insights.analyzers.manifests import SqlAnalyzer
+from shannon_insight.insights.scheduler import AnalyzerScheduler
+from shannon_insight.insights.store import AnalysisStore
+from tests.conftest import make_function, make_syntax
+
+
+def _names(config: AnalysisConfig) -> set[str]:
+    return {analyzer.name for analyzer in get_default_analyzers(config)}
+
+
+def _store(files, generated=None) -> AnalysisStore:
+    store = AnalysisStore()
+    store.file_syntax.set({syntax.path: syntax for syntax in files}, produced_by="scanning")
+    if generated is not None:
+        store.generated_files.set(generated, produced_by="scanning")
+    return store
+
+
+class TestRegistry:
+    def test_all_optional_analyzers_run_by_default(self):
+        assert set(OPTIONAL_ANALYZERS) <= _names(AnalysisConfig())
+
+    def test_each_provides_its_own_slot(self):
+        for analyzer in get_default_analyzers(AnalysisConfig()):
+            if analyzer.name in OPTIONAL_ANALYZERS:
+                assert analyzer.provides == {analyzer.name}
+                assert hasattr(AnalysisStore(), analyzer.name)
+
+    def test_disabled_analyzers_are_left_out(self):
+        names = _names(AnalysisConfig(disabled_analyzers=["literals"]))
+
+        assert "literals" not in names
+        assert "structural" in names
+
+    def test_comment_debt_follows_enable_comment_debt(self):
+        assert "comment_debt" not in _names(AnalysisConfig(enable_comment_debt=False))
+
+    def test_schedules_without_slot_collisions_or_cycles(self):
+        AnalyzerScheduler(get_default_analyzers(AnalysisConfig())).close()
+
+    def test_unknown_name_rejected(self):
+        with pytest.raises(ValueError, match="disabled_analyzers: unknown analyzers nope"):
+            AnalysisConfig(disabled_analyzers=["nope"])
+
+
+class TestReportAnalyzers:
+    def test_generated_files_are_not_scored(self):
+        wide = make_function("f", params=["a", "b", "c", "d", "e", "f", "g"])
+        store = _store(
+            [make_syntax("app.py", [wide]), make_syntax("gen_pb2.py", [wide])],
+            generated={"gen_pb2.py": "protobuf"},
+        )
+
+        ParameterAnalyzer().analyze(store)
+
+        assert [f.path for f in store.parameters.value] == ["app.py"]
+        assert store.parameters.produced_by == "parameters"
+
+    def test_uses_config_defaults_without_session(self):
+        body = "".join("    " * depth + "if x:\n" for depth in range(1, 7)) + "    " * 7 + "pass\n"
+        store = _store([make_syntax("a.py", [make_function("f", end_line=8, nesting_depth=6)])])
+        store._content_cache["a.py"] = "def f():\n" + body
+
+        DeepNestingAnalyzer().analyze(store)
+
+        # Six levels against the default nesting_threshold of 4
+        assert [fn.name for fn in store.deep_nesting.value] == ["f"]
+
+    def test_slot_stays_unset_without_files_of_its_kind(self):
+        store = _store([make_syntax("a.py")])
+
+        SqlAnalyzer().analyze(store)
+
+        assert not store.sql.available
+        assert store.sql.error is None
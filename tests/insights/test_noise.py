@@ -0,0 +1,72 @@
+"""Tests for collapsing findings that cover a whole subtree."""
+
+from shannon_insight.insights.models import Evidence, Finding
+from shannon_insight.insights.noise import (
+    COLLAPSED_HINT,
+    collapse_noisy_findings,
+    find_noisy_subtrees,
+)
+
+
+def _finding(finding_type, *files, severity=0.4):
+    return Finding(
+        finding_type=finding_type,
+        severity=severity,
+        title=f"{finding_type} in {files[0]}",
+        files=list(files),
+        evidence=[Evidence(signal="x", value=1.0, percentile=0.0, description="x")],
+        suggestion="fix it",
+    )
+
+
+# 12 generated clients, 2 hand-written files beside them, 6 files elsewhere
+_FILES = (
+    [f"sdk/gen/client_{i}.go" for i in range(12)]
+    + ["sdk/gen/doc.go", "sdk/auth.go"]
+    + [f"internal/svc_{i}.go" for i in range(6)]
+)
+
+
+class TestFindNoisySubtrees:
+    def test_topmost_directory_over_the_threshold(self):
+        findings = [_finding("long_parameter_list", f) for f in _FILES[:12]]
+        (subtree,) = find_noisy_subtrees(findings, _FILES, threshold=0.8, min_files=10)
+        # sdk/ has 12 of 14 flagged (86%), so it wins over sdk/gen/ (12 of 13)
+        assert (subtree.directory, subtree.flagged, subtree.files) == ("sdk", 12, 14)
+        assert subtree.exclude_pattern == "sdk/*"
+
+    def test_below_the_share_or_count_is_left_alone(self):
+        findings = [_finding("long_parameter_list", f) for f in _FILES[:12]]
+        assert find_noisy_subtrees(findings, _FILES, threshold=0.95, min_files=10) == []
+        assert find_noisy_subtrees(findings, _FILES, threshold=0.8, min_files=13) == []
+
+    def test_project_root_and_multi_file_findings_never_collapse(self):
+        files = [f"f{i}.go" for i in range(12)]
+        findings = [_finding("god_file", f) for f in files]
+        findings += [_finding("hidden_coupling", a, b) for a, b in zip(_FILES, _FILES[1:13])]
+        assert find_noisy_subtrees(findings, files + _FILES, min_files=10) == []
+
+
+class TestCollapseNoisyFindings:
+    def test_collapses_into_one_directory_finding(self):
+        noisy = [_finding("long_parameter_list", f, severity=0.3) for f in _FILES[:12]]
+        # A second finding on one file is collapsed too, but counted once per file
+        noisy.append(_finding("long_parameter_list", _FILES[0], severity=0.5))
+        other = _finding("god_file", "sdk/gen/client_0.go", severity=0.9)
+        elsewhere = _finding("long_parameter_list", "internal/svc_0.go")
+
+        result = collapse_noisy_findings([*noisy, other, elsewhere], _FILES, min_files=10)
+
+        assert result[:2] == [other, elsewhere]
+        (aggregate,) = result[2:]
+        assert aggregate.finding_type == "long_parameter_list"
+        assert aggregate.files == ["sdk"]
+        assert aggregate.scope == "MODULE"
+        assert aggregate.identity_hint == COLLAPSED_HINT
+        assert aggregate.severity == round((0.3 * 12 + 0.5) / 13, 4)
+        assert [e.value for e in aggregate.evidence] == [12.0, 13.0]
+        assert '"sdk/*"' in aggregate.suggestion
+
+    def test_nothing_noisy_returns_findings_unchanged(self):
+        findings = [_finding("god_file", f) for f in _FILES[:3]]
+        assert collapse_noisy_findings(findings, _FILES) is findings
@@ -0,0 +1,161 @@
+"""Tests for concurrent DAG scheduling of analyzers."""
+
+import threading
+import time
+
+import pytest
+
+from shannon_insight.insights.kernel_toposort import AnalyzerCycleError
+from shannon_insight.insights.scheduler import AnalyzerScheduler
+from shannon_insight.insights.store import AnalysisStore
+
+
+class FakeStore:
+    """Store stand-in: only the available slot set matters to the scheduler."""
+
+    def __init__(self, available=()):
+        self.available = set(available)
+        self.log: list[str] = []
+
+
+class MockAnalyzer:
+    def __init__(self, name, requires=(), provides=(), uses=(), work=None):
+        self.name = name
+        self.requires = set(requires)
+        self.provides = set(provides)
+        self.uses = set(uses)
+        self.work = work
+
+    def analyze(self, store):
+        store.log.append(f"start:{self.name}")
+        if self.work is not None:
+            self.work(store)
+        store.available |= self.provides
+        store.log.append(f"end:{self.name}")
+
+
+class TestScheduling:
+    def test_independent_analyzers_run_concurrently(self):
+        barrier = threading.Barrier(2, timeout=5)
+        a = MockAnalyzer("a", provides={"x"}, work=lambda s: barrier.wait())
+        b = MockAnalyzer("b", provides={"y"}, work=lambda s: barrier.wait())
+
+        outcomes = AnalyzerScheduler([a, b], max_workers=2).run(FakeStore())
+
+        assert outcomes == {"a": "completed", "b": "completed"}
+
+    def test_dependent_waits_for_provider(self):
+        store = FakeStore()
+        a = MockAnalyzer("a", provides={"x"}, work=lambda s: time.sleep(0.05))
+        b = MockAnalyzer("b", requires={"x"})
+
+        AnalyzerScheduler([b, a], max_workers=4).run(store)
+
+        assert store.log.index("end:a") < store.log.index("start:b")
+
+    def test_single_worker_is_sequential(self):
+        store = FakeStore()
+        analyzers = [
+            MockAnalyzer("c", requires={"y"}),
+            MockAnalyzer("b", requires={"x"}, provides={"y"}),
+            MockAnalyzer("a", provides={"x"}),
+        ]
+
+        AnalyzerScheduler(analyzers, max_workers=1).run(store)
+
+        assert store.log == ["start:a", "end:a", "start:b", "end:b", "start:c", "end:c"]
+
+    def test_cycle_rejected(self):
+        a = MockAnalyzer("a", requires={"y"}, provides={"x"})
+        b = MockAnalyzer("b", requires={"x"}, provides={"y"})
+
+        with pytest.raises(AnalyzerCycleError):
+            AnalyzerScheduler([a, b])
+
+
+class TestDegradation:
+    def test_failure_skips_requirers_but_not_users(self):
+        def boom(store):
+            raise RuntimeError("boom")
+
+        store = FakeStore()
+        analyzers = [
+            MockAnalyzer("a", provides={"x"}, work=boom),
+            MockAnalyzer("needs", requires={"x"}),
+            MockAnalyzer("wants", uses={"x"}),
+        ]
+
+        outcomes = AnalyzerScheduler(analyzers).run(store)
+
+        assert outcomes == {"a": "failed", "needs": "skipped", "wants": "completed"}
+
+    def test_failure_leaves_error_in_provided_slots(self):
+        class Broken:
+            name = "sql"
+            requires: set[str] = set()
+            provides = {"sql"}
+
+            def analyze(self, store):
+                raise ValueError("bad dump")
+
+        store = AnalysisStore()
+
+        AnalyzerScheduler([Broken()]).run(store)
+
+        assert not store.sql.available
+        assert store.sql.error == "bad dump"
+        assert store.sql.produced_by == "sql"
+
+    def test_timeout_unblocks_dependents(self):
+        release = threading.Event()
+        store = FakeStore()
+        analyzers = [
+            MockAnalyzer("slow", provides={"x"}, work=lambda s: release.wait(5)),
+            MockAnalyzer("wants", uses={"x"}),
+        ]
+
+        outcomes = AnalyzerScheduler(analyzers, timeout=0.1).run(store)
+        release.set()
+
+        assert outcomes == {"slow": "timeout", "wants": "completed"}
+
+    def test_on_done_only_for_completed(self):
+        store = FakeStore()
+        done: list[str] = []
+        analyzers = [MockAnalyzer("a", provides={"x"}), MockAnalyzer("b", requires={"missing"})]
+
+        AnalyzerScheduler(analyzers).run(store, on_done=lambda a: done.append(a.name))
+
+        assert done == ["a"]
+
+
+class TestPrepare:
+    def test_prepare_overlaps_and_precedes_analyze(self):
+        started = threading.Event()
+
+        class GitLike(MockAnalyzer):
+            def prepare(self, store):
+                started.set()
+                time.sleep(0.05)
+                self.history = "log"
+
+            def analyze(self, store):
+                store.log.append(self.history)
+
+        store = FakeStore()
+        scheduler = AnalyzerScheduler([GitLike("git")])
+        scheduler.prepare(store)
+        assert started.wait(5)  # running while the caller "scans"
+        scheduler.run(store)
+
+        assert store.log == ["log"]
+
+    def test_failed_prepare_fails_analyzer(self):
+        class Broken(MockAnalyzer):
+            def prepare(self, store):
+                raise OSError("no git")
+
+        scheduler = AnalyzerScheduler([Broken("git")])
+        scheduler.prepare(FakeStore())
+
+        assert scheduler.run(FakeStore()) == {"git": "failed"}
@@ -0,0 +1,122 @@
+"""Tests for triage decisions and the committed triage file."""
+
+import json
+from urllib.parse import unquote
+
+from shannon_insight.insights.models import Evidence, Finding
+from shannon_insight.insights.triage import (
+    Decision,
+    Triage,
+    apply_triage,
+    code_excerpt,
+    load_triage,
+    save_triage,
+    similar_cases,
+    ticket_url,
+    untriaged,
+)
+from shannon_insight.persistence.identity import compute_identity_key
+from shannon_insight.persistence.models import EvidenceRecord, FindingRecord
+from shannon_insight.persistence.queries import ChronicFindingInfo
+
+
+def _record(finding_type, path, title="", severity=0.5, hint=None):
+    key = compute_identity_key(finding_type, [path], hint=hint)
+    evidence = [EvidenceRecord("methods", 40.0, 0.0, "40 methods")]
+    return FindingRecord(finding_type, key, severity, title or path, [path], evidence, "Split it")
+
+
+def _decision(record, action, **fields):
+    return Decision(
+        record.identity_key, record.finding_type, record.title, list(record.files), action, **fields
+    )
+
+
+def _finding(finding_type, path, hint=None):
+    return Finding(finding_type, 0.5, path, [path], [], "", identity_hint=hint)
+
+
+class TestTriage:
+    def test_dismiss_hides_one_finding_and_suppress_its_type_on_the_file(self):
+        triage = Triage()
+        triage.decide(_decision(_record("god_class", "a.py"), "dismiss"))
+        triage.decide(_decision(_record("deep_nesting", "b.py", hint="parse"), "suppress"))
+        triage.decide(_decision(_record("god_file", "c.py"), "assign", owner="dana"))
+
+        findings = [
+            _finding("god_class", "a.py"),
+            _finding("god_class", "b.py"),
+            _finding("deep_nesting", "b.py", hint="render"),  # another function, same file
+            _finding("god_file", "c.py"),
+        ]
+        kept = apply_triage(findings, triage)
+        assert [(f.finding_type, f.files) for f in kept] == [
+            ("god_class", ["b.py"]),
+            ("god_file", ["c.py"]),
+        ]
+
+    def test_untriaged_skips_decided_findings(self):
+        assigned = _record("god_file", "c.py", severity=0.9)
+        triage = Triage()
+        triage.decide(_decision(assigned, "ticket", ticket="#12"))
+        records = [_record("god_class", "a.py", severity=0.4), assigned, _record("x", "b.py", 0.7)]
+        assert [r.files[0] for r in untriaged(records, triage)] == ["b.py", "a.py"]
+
+    def test_file_round_trip(self, tmp_path):
+        path = tmp_path / "shannon-triage.json"
+        assert load_triage(path).decisions == {}
+
+        record = _record("god_class", "src/a.py", title="A has 40 methods")
+        triage = Triage()
+        triage.decide(_decision(record, "assign", owner="dana", decided="2026-10-16"))
+        save_triage(path, triage)
+
+        data = json.loads(path.read_text())
+        assert data == {
+            "version": 1,
+            "findings": {
+                record.identity_key: {
+                    "finding_type": "god_class",
+                    "title": "A has 40 methods",
+                    "files": ["src/a.py"],
+                    "action": "assign",
+                    "owner": "dana",
+                    "decided": "2026-10-16",
+                }
+            },
+        }
+        assert load_triage(path).decisions == triage.decisions
+
+
+def test_similar_cases_nearest_first():
+    record = _record("god_class", "src/billing/invoice.py")
+    resolved = [
+        ChronicFindingInfo("r1", "god_class", 1, 2, 2, "resolved", 0.6, ["lib/x.py"], "X fixed"),
+        ChronicFindingInfo(
+            "r2", "god_class", 1, 2, 2, "resolved", 0.6, ["src/billing/tax.py"], "Tax fixed"
+        ),
+    ]
+    triage = Triage()
+    other = _record("god_class", "src/api.py", title="Api dismissed")
+    triage.decide(_decision(other, "dismiss", reason="facade"))
+    triage.decide(_decision(_record("god_file", "src/billing/a.py"), "dismiss"))
+
+    cases = similar_cases(record, resolved, triage)
+    assert [(c.title, c.outcome) for c in cases] == [
+        ("Tax fixed", "fixed"),
+        ("Api dismissed", "dismissed: facade"),
+        ("X fixed", "fixed"),
+    ]
+
+
+def test_ticket_url_and_code_excerpt(tmp_path):
+    record = _record("deep_nesting", "pkg/run.py", title="run at pkg/run.py:6: nesting 5")
+    url = ticket_url("https://example.com/new?title={title}&body={body}", record)
+    assert url.startswith("https://example.com/new?title=run%20at%20pkg%2Frun.py%3A6")
+    assert f"shannon-insight finding {record.identity_key}" in unquote(url)
+
+    (tmp_path / "pkg").mkdir()
+    (tmp_path / "pkg" / "run.py").write_text("".join(f"line {i}\n" for i in range(1, 21)))
+    path, start, lines = code_excerpt(tmp_path, record, context=2)
+    assert (path, start) == ("pkg/run.py", 4)
+    assert lines == ["line 4", "line 5", "line 6", "line 7", "line 8"]
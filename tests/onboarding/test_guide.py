@@ -0,0 +1,118 @@
+"""Tests for the orientation guide."""
+
+from pathlib import Path
+
+from shannon_insight.hygiene.sources import SourceSet
+from shannon_insight.onboarding import build_guide, in_scope
+from shannon_insight.persistence.models import TensorSnapshot
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, ImportDecl
+from tests.conftest import make_function, make_syntax
+
+
+def _sources():
+    files = [
+        FileSyntax(
+            "billing/api.py",
+            [
+                make_function("create", calls=["charge"], decorators=["app.post"]),
+                make_function("refund", calls=["charge"], decorators=["app.post"]),
+            ],
+            [],
+            [ImportDecl(".service", []), ImportDecl("fastapi", []), ImportDecl("stripe", [])],
+            "python",
+        ),
+        FileSyntax(
+            "billing/service.py",
+            [make_function("charge", calls=["Invoice"], start_line=5)],
+            [],
+            [ImportDecl(".models", []), ImportDecl("stripe.error", []), ImportDecl("os", [])],
+            "python",
+        ),
+        make_syntax(
+            "billing/models.py",
+            [],
+            [ClassDef("Invoice", [], [], ["id", "amount"]), ClassDef("Refund", [], [], ["id"])],
+        ),
+        FileSyntax("billing/cli.py", [], [], [], "python", has_main_guard=True),
+        make_syntax("search/index.py", [make_function("reindex", calls=["charge"])]),
+    ]
+    return SourceSet(Path("/repos/shop"), syntax={fs.path: fs for fs in files})
+
+
+def _snapshot():
+    return TensorSnapshot(
+        commits_analyzed=40,
+        file_signals={
+            "billing/api.py": {"role": "service", "risk_score": 0.2, "bus_factor": 3},
+            "billing/service.py": {
+                "role": "service",
+                "risk_score": 0.7,
+                "bus_factor": 1,
+                "percentiles": {"cognitive_load": 0.95, "total_changes": 0.5},
+            },
+            "billing/models.py": {"role": "model", "risk_score": 0.1, "in_degree": 1},
+            "billing/cli.py": {"role": "entry_point", "risk_score": 0.0},
+            "search/index.py": {"role": "utility", "risk_score": 0.9},
+        },
+    )
+
+
+class TestBuildGuide:
+    def test_sections(self):
+        guide = build_guide(_sources(), _snapshot(), scope="billing/")
+
+        assert guide.scope == "billing"
+        assert guide.files == 4
+        assert [(e.path, e.handlers) for e in guide.entry_points] == [
+            ("billing/api.py", ["create (app.post)", "refund (app.post)"]),
+            ("billing/cli.py", []),
+        ]
+        # charge is called from api.py twice and from search/, which is out of scope
+        central = [(r.symbol.name, r.callers) for r in guide.central_symbols]
+        assert central == [("Invoice", 1), ("charge", 3)]
+        assert [(m.name, m.fields) for m in guide.data_models] == [
+            ("Invoice", ["id", "amount"]),
+            ("Refund", ["id"]),
+        ]
+        assert [(i.package, len(i.files)) for i in guide.integrations] == [
+            ("stripe", 2),
+            ("fastapi", 1),
+        ]
+        risky = guide.riskiest_files
+        assert [f.path for f in risky] == [
+            "billing/service.py",
+            "billing/api.py",
+            "billing/models.py",
+        ]
+        assert risky[0].reasons == ["hard to follow", "one author knows it"]
+
+    def test_markdown_and_dict(self):
+        guide = build_guide(_sources(), _snapshot(), scope="billing", top=1)
+
+        markdown = guide.render_markdown()
+        data = guide.to_dict()
+
+        assert markdown.startswith("# Orientation guide: shop/billing")
+        assert "- `billing/api.py` (service)\n  - create (app.post)" in markdown
+        assert "`stripe` (used in 2 file(s))" in markdown
+        assert len(data["riskiest_files"]) == 1
+        assert data["central_symbols"]["call_graph_available"] is True
+
+    def test_without_call_targets(self):
+        sources = _sources()
+        for fs in sources.syntax.values():
+            for fn in fs.functions:
+                fn.call_targets = None
+
+        guide = build_guide(sources, _snapshot())
+
+        assert not guide.call_graph_available
+        assert guide.central_symbols == []
+        assert "Call graph unavailable" in guide.render_markdown()
+
+
+def test_in_scope():
+    assert in_scope("billing/api.py", "billing")
+    assert in_scope("billing/api.py", "")
+    assert in_scope("billing/api.py", "billing/api.py")
+    assert not in_scope("billing_v2/api.py", "billing")
@@ -0,0 +1,141 @@
+"""Tests for custom query rules and their fixture harness."""
+
+import re
+
+import pytest
+
+from shannon_insight.rules import RuleError, load_rule, run_rule_tests
+from shannon_insight.rules.harness import annotations, fixture_files
+from shannon_insight.rules.query_rules import QueryMatcher
+from shannon_insight.scanning.treesitter_parser import (
+    TREE_SITTER_AVAILABLE,
+    get_supported_languages,
+)
+
+_GO_SUPPORTED = TREE_SITTER_AVAILABLE and "go" in get_supported_languages()
+
+_RULE = """\
+id = "no-fmt-println"
+language = "go"
+message = "Log through the structured logger"
+query = '''
+(call_expression
+  function: (selector_expression
+    operand: (identifier) @pkg
+    field: (field_identifier) @fn)
+  (#eq? @pkg "fmt")
+  (#eq? @fn "Println")) @match
+'''
+"""
+
+_FIXTURE = """\
+package main
+
+func main() {
+	// ruleid: no-fmt-println
+	fmt.Println("debug")
+	fmt.Println("again") // ruleid: no-fmt-println
+	// ok: no-fmt-println
+	fmt.Printf("%d\\n", 1)
+	log.Println("fine")
+}
+"""
+
+
+def _regex_matcher(rule, content):
+    """Stands in for tree-sitter: matches fmt.Println calls."""
+    return [n for n, line in enumerate(content.splitlines(), 1) if "fmt.Println(" in line]
+
+
+class TestAnnotations:
+    def test_own_line_and_trailing_annotations(self):
+        assert annotations(_FIXTURE, "no-fmt-println") == ([5, 6], [8])
+
+    def test_other_rules_and_comment_styles(self):
+        source = "# ruleid: a, b\nx = 1\n\n# ruleid: c\ny = 2\n-- ruleid: b\nSELECT 1;\n"
+        assert annotations(source, "b") == ([2, 7], [])
+        assert annotations(source, "c") == ([5], [])
+        assert annotations(source, "d") == ([], [])
+
+
+class TestLoadRule:
+    def test_valid_rule(self, write_files):
+        rule = load_rule(write_files({"r.toml": _RULE}) / "r.toml")
+        assert (rule.id, rule.language, rule.severity) == ("no-fmt-println", "go", 0.3)
+        assert rule.query.lstrip().startswith("(call_expression")
+
+    @pytest.mark.parametrize(
+        "text, error",
+        [
+            ('id = "x"\nlanguage = "go"\n', "'query' must be a non-empty string"),
+            ('id = "a b"\nlanguage = "go"\nquery = "(x)"\n', "may only use"),
+            ('id = "x"\nlanguage = "go"\nquery = "(x)"\nseverity = 2\n', "between 0 and 1"),
+            ('id = "x"\nlanguage = "go"\nquery = "(x)"\nlevel = 1\n', "unknown key(s): level"),
+            ("id = ", "r.toml"),
+        ],
+    )
+    def test_invalid_rules(self, write_files, text, error):
+        with pytest.raises(RuleError, match=re.escape(error)):
+            load_rule(write_files({"r.toml": text}) / "r.toml")
+
+
+class TestRunRuleTests:
+    def test_pass_fail_error_and_untested(self, write_files):
+        failing = _FIXTURE.replace("// ruleid: no-fmt-println\n", "// debug output\n", 1)
+        failing += '// ruleid: no-fmt-println\nlog.Print("not a match")\n'
+        pack = write_files(
+            {
+                "go/no-fmt-println.toml": _RULE,
+                "go/no-fmt-println.go": _FIXTURE,
+                "go/strict.toml": _RULE.replace('"no-fmt-println"', '"strict"'),
+                "go/strict/main.go": failing.replace("no-fmt-println", "strict"),
+                "go/untested.toml": _RULE.replace('"no-fmt-println"', '"untested"'),
+                "broken.toml": 'id = "broken"\n',
+            },
+        )
+        report = run_rule_tests([f"{pack}/..."], matcher=_regex_matcher)
+
+        status = {r.path.rsplit("/", 1)[-1]: r.status for r in report.results}
+        assert status == {
+            "broken.toml": "error",
+            "no-fmt-println.toml": "pass",
+            "strict.toml": "fail",
+            "untested.toml": "untested",
+        }
+        (strict,) = [r for r in report.results if r.status == "fail"]
+        (fixture,) = strict.fixtures
+        assert (fixture.missing, fixture.unexpected) == ([12], [5])
+        assert report.counts() == {"pass": 1, "fail": 1, "error": 1, "untested": 1}
+        assert not report.passed
+        assert report.to_dict()["rules"][2]["fixtures"][0]["missing"] == [12]
+
+    def test_fixtures_beside_and_below_the_rule(self, write_files):
+        pack = write_files(
+            {
+                "r.toml": _RULE,
+                "r.go": "",
+                "r.legacy.go": "",
+                "r/a.go": "",
+                "rx.go": "",
+                "other.go": "",
+            },
+        )
+        names = [p.relative_to(pack).as_posix() for p in fixture_files(pack / "r.toml")]
+        assert names == ["r.go", "r.legacy.go", "r/a.go"]
+
+    def test_missing_path(self, tmp_path):
+        with pytest.raises(RuleError, match="no such file or directory"):
+            run_rule_tests([str(tmp_path / "nope")], matcher=_regex_matcher)
+
+
+@pytest.mark.skipif(not _GO_SUPPORTED, reason="tree-sitter or Go grammar not installed")
+class TestQueryMatcher:
+    def test_match_capture_lines(self, write_files):
+        rule = load_rule(write_files({"r.toml": _RULE}) / "r.toml")
+        assert QueryMatcher()(rule, _FIXTURE) == [5, 6]
+
+    def test_invalid_query(self, write_files):
+        text = _RULE.replace("(call_expression", "(no_such_node")
+        rule = load_rule(write_files({"r.toml": text}) / "r.toml")
+        with pytest.raises(RuleError):
+            QueryMatcher()(rule, _FIXTURE)
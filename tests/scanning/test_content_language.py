@@ -0,0 +1,75 @@
+"""Tests for detecting the language of files without a source extension."""
+
+import pytest
+
+from shannon_insight.environment import discover_environment
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.languages import detect_language, read_shebang
+
+
+@pytest.mark.parametrize(
+    "path, content, language",
+    [
+        ("bin/manage", "#!/usr/bin/env python3\nimport sys\n", "python"),
+        ("bin/migrate", "#!/usr/bin/python3.12 -u\n", "python"),
+        ("bin/serve", "#!/usr/bin/env -S deno run --allow-net\n", "typescript"),
+        ("bin/cli", "#!/usr/bin/env NODE_ENV=production node\n", "javascript"),
+        ("scripts/release", "#!/bin/bash\nset -e\n", "shell"),
+        ("Dockerfile", None, "dockerfile"),
+        ("docker/Dockerfile.dev", None, "dockerfile"),
+        ("GNUmakefile", None, "make"),
+        ("rules.mk", None, "make"),
+        ("Gemfile", None, "ruby"),
+        ("notes.txt", "#!/bin/sh\n", "unknown"),
+        ("bin/tool", "#!/usr/bin/env awk -f\n", "unknown"),
+        ("LICENSE", "MIT License\n", "unknown"),
+    ],
+)
+def test_detect_language_by_name_and_shebang(path, content, language):
+    assert detect_language(path, content) == language
+
+
+def test_shebang_is_only_read_from_the_first_line(tmp_path):
+    (tmp_path / "tool").write_text("#!/usr/bin/env ruby\nputs 1\n")
+    (tmp_path / "data").write_bytes(b"\x7fELF\x02\x01#!/bin/sh\n")
+
+    assert read_shebang(tmp_path / "tool") == "#!/usr/bin/env ruby"
+    assert read_shebang(tmp_path / "data") == ""
+    assert read_shebang(tmp_path / "missing") == ""
+
+
+def test_discovers_scripts_and_build_files(tmp_path):
+    (tmp_path / "bin").mkdir()
+    (tmp_path / "bin" / "release").write_text("#!/usr/bin/env bash\nbuild() {\n  make\n}\n")
+    (tmp_path / "Dockerfile").write_text("FROM python:3.12\n")
+    (tmp_path / "Makefile").write_text("all:\n\tgo build ./...\n")
+    (tmp_path / "LICENSE").write_text("MIT License\n")
+    (tmp_path / "app.py").write_text("x = 1\n")
+
+    env = discover_environment(tmp_path)
+
+    assert sorted(p.as_posix() for p in env.file_paths) == [
+        "Dockerfile",
+        "Makefile",
+        "app.py",
+        "bin/release",
+    ]
+    assert env.detected_languages == {"dockerfile", "make", "python", "shell"}
+
+
+def test_shell_functions_in_both_forms():
+    content = (
+        "#!/bin/sh\n"
+        "function deploy {\n"
+        '  echo "$(date)"\n'
+        "}\n"
+        "\n"
+        "build() {\n"
+        "  make all\n"
+        "}\n"
+    )
+
+    syntax = RegexFallbackScanner().parse(content, "bin/release", "shell")
+
+    assert [(f.name, f.start_line) for f in syntax.functions] == [("deploy", 2), ("build", 6)]
+    assert RegexFallbackScanner().parse("all:\n\techo\n", "Makefile", "make").functions == []
@@ -0,0 +1,62 @@
+"""Tests for content-identical file detection."""
+
+import os
+
+import pytest
+
+from shannon_insight.scanning.duplicates import MIN_DUPLICATE_LINES, find_identical_files
+
+BODY = "".join(f"line_{i} = {i}\n" for i in range(MIN_DUPLICATE_LINES))
+
+
+def _lines(contents):
+    return {path: content.count("\n") for path, content in contents.items()}
+
+
+class TestFindIdenticalFiles:
+    def test_groups_identical_files(self):
+        contents = {
+            "vendor/lib/util.py": BODY,
+            "lib/util.py": BODY,
+            "docs/examples/util.py": BODY,
+            "lib/other.py": BODY + "extra = 1\n",
+        }
+
+        groups = find_identical_files(contents, _lines(contents))
+
+        assert len(groups) == 1
+        assert groups[0].canonical == "lib/util.py"
+        assert groups[0].copies == ["docs/examples/util.py", "vendor/lib/util.py"]
+        assert groups[0].lines == MIN_DUPLICATE_LINES
+
+    def test_line_endings_ignored(self):
+        contents = {"a.py": BODY, "b.py": BODY.replace("\n", "\r\n")}
+
+        groups = find_identical_files(contents, _lines(contents))
+
+        assert [g.paths for g in groups] == [["a.py", "b.py"]]
+
+    def test_small_files_never_grouped(self):
+        contents = {"a/__init__.py": "", "b/__init__.py": "", "c.py": "x = 1\n", "d.py": "x = 1\n"}
+
+        assert find_identical_files(contents, _lines(contents)) == []
+
+    def test_symlinks_reported(self, tmp_path):
+        (tmp_path / "lib").mkdir()
+        (tmp_path / "lib" / "util.py").write_text(BODY)
+        (tmp_path / "copy.py").write_text(BODY)
+        try:
+            os.symlink(tmp_path / "lib", tmp_path / "linked")
+        except OSError:
+            pytest.skip("symlinks not supported")
+        contents = {"lib/util.py": BODY, "linked/util.py": BODY, "copy.py": BODY}
+
+        groups = find_identical_files(contents, _lines(contents), root=tmp_path)
+
+        assert groups[0].canonical == "copy.py"
+        assert groups[0].symlinks == []
+
+        contents.pop("copy.py")
+        groups = find_identical_files(contents, _lines(contents), root=tmp_path)
+
+        assert groups[0].symlinks == ["linked/util.py"]
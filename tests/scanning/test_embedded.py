@@ -0,0 +1,106 @@
+"""Tests for SQL and HTML embedded in string literals."""
+
+from shannon_insight.scanning.embedded import find_snippets, snippet_language
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+from shannon_insight.signals.embedded_code import MODULE_FUNCTION, collect_embedded
+
+_GO = '''package repository
+
+const listColumns = "id, email, name, created_at, updated_at, deleted_at, last_login_at, role"
+
+func (r *Repo) ActiveUsers(ctx context.Context, since time.Time) ([]User, error) {
+	if since.IsZero() {
+		return nil, errNoSince
+	}
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.email, CASE WHEN o.total > 100 THEN 'gold' ELSE 'basic' END
+		FROM users u
+		JOIN orders o ON o.user_id = u.id
+		WHERE u.created_at > $1 AND u.deleted_at IS NULL
+	`, since)
+	return scan(rows, err)
+}
+'''
+
+_PY = """TEMPLATE = '''
+<ul class="users">
+  {% for user in users %}
+    <li>{% if user.admin %}<b>{{ user.name }}</b>{% endif %}</li>
+  {% endfor %}
+</ul>
+'''
+"""
+
+
+class TestFindSnippets:
+    def test_go_raw_string_query(self):
+        [snippet] = find_snippets(_GO)
+
+        assert snippet.language == "sql"
+        assert (snippet.start_line, snippet.end_line) == (10, 13)
+
+    def test_python_triple_quoted_template(self):
+        [snippet] = find_snippets(_PY)
+
+        assert snippet.language == "html"
+        assert (snippet.start_line, snippet.end_line) == (2, 6)
+
+    def test_template_literal_placeholders(self):
+        content = (
+            "const q = `SELECT * FROM accounts WHERE owner = ${owner.id} "
+            "AND region = ${region} ORDER BY created_at`;\n"
+        )
+        [snippet] = find_snippets(content)
+
+        assert "owner = ? AND region = ?" in snippet.text
+
+    def test_heredoc_tag_names_the_language(self):
+        content = "sql = <<~SQL\n  " + "select id from accounts where paid\n" * 3 + "SQL\n"
+
+        [snippet] = find_snippets(content)
+
+        assert snippet.language == "sql"
+        assert (snippet.start_line, snippet.end_line) == (2, 4)
+
+    def test_prose_is_not_sql(self):
+        prose = "Select the users from the list below and update them before the release is cut"
+        assert snippet_language(prose) is None
+        assert snippet_language("select id from users where id = ?") == "sql"
+        assert snippet_language("SELECT id FROM users") == "sql"
+        assert snippet_language("<p>only</p> one tag pair") is None
+
+
+class TestCollectEmbedded:
+    def test_snippet_charged_to_host_function(self):
+        fn = FunctionDef("ActiveUsers", [], 80, 10, 1, 5, 16)
+        files = {"repository/users.go": FileSyntax("repository/users.go", [fn], [], [], "go")}
+
+        report = collect_embedded(files, {"repository/users.go": _GO})
+
+        [sample] = report.snippets
+        assert (sample.function, sample.language) == ("ActiveUsers", "sql")
+        assert sample.tables == ("users", "orders")
+        assert sample.complexity > 1
+        [host] = report.functions
+        # The query's CASE/WHEN and AND are not branches of the host
+        assert host.complexity == 2
+        assert host.embedded_complexity == sample.complexity
+        assert report.tables() == {
+            "orders": ["repository/users.go:10 ActiveUsers"],
+            "users": ["repository/users.go:10 ActiveUsers"],
+        }
+
+    def test_module_level_template(self):
+        files = {"views.py": FileSyntax("views.py", [], [], [], "python")}
+
+        report = collect_embedded(files, {"views.py": _PY})
+
+        [sample] = report.snippets
+        assert sample.function == MODULE_FUNCTION
+        assert (sample.complexity, sample.depth) == (3, 3)
+        assert collect_embedded(files, {"views.py": _PY}, "sql").snippets == []
+
+    def test_sql_files_are_not_hosts(self):
+        files = {"q.sql": FileSyntax("q.sql", [], [], [], "sql")}
+
+        assert collect_embedded(files, {"q.sql": "SELECT '" + "x" * 100 + "';"}).snippets == []
@@ -121,6 +121,19 @@ def foo():
         stub_fn = next(fn for fn in result.functions if fn.name == "stub_function")
         assert stub_fn.is_stub is True
 
+    def test_params_span_annotations_and_call_defaults(self):
+        """Commas and parentheses inside annotations and defaults do not split params."""
+        source = (
+            "def top(\n"
+            "    files: dict[str, FileSyntax],\n"
+            '    top: int = Option(15, "--top", help="Rows, at most"),\n'
+            "    *args,\n"
+            "):\n"
+            "    pass\n"
+        )
+        result = RegexFallbackScanner().parse(source, "/test.py", "python")
+        assert result.functions[0].params == ["files", "top", "*args"]
+
 
 class TestGoFallback:
     """Test Go language support."""
@@ -146,6 +159,40 @@ func helper(x int) int {
         assert "main" in fn_names
         assert "helper" in fn_names
 
+    def test_detects_generic_functions_types_and_methods(self):
+        """Type parameters and receivers are skipped, not taken for the name or parameters."""
+        go_code = """
+package slices
+
+func Map[T, U any](xs []T, f func(T) U) []U {
+    return nil
+}
+
+func Sort[S ~[]E, E cmp.Ordered](x S) {}
+
+type Stack[T any] struct {
+    items []T
+}
+
+type Pairs [2]struct{ a, b int }
+
+func (s *Stack[T]) Push(v T) {}
+
+func Count(a, b int, rest ...string) int { return 0 }
+
+func Apply(func(int) int, int) {}
+"""
+        result = RegexFallbackScanner().parse(go_code, "/slices.go", "go")
+
+        assert [(fn.name, fn.params) for fn in result.functions] == [
+            ("Map", ["xs", "f"]),
+            ("Sort", ["x"]),
+            ("Push", ["v"]),
+            ("Count", ["a", "b", "rest"]),
+            ("Apply", []),
+        ]
+        assert [cls.name for cls in result.classes] == ["Stack"]
+
 
 class TestTypeScriptFallback:
     """Test TypeScript language support."""
@@ -199,6 +246,456 @@ public class MyClass {
         assert "doSomething" in fn_names or len(result.functions) > 0
 
 
+class TestRustFallback:
+    """Test Rust language support."""
+
+    RUST_CODE = """
+pub use crate::config::Settings;
+
+pub trait Greeter: Clone {
+    fn greet(&self, name: &str) -> String;
+}
+
+pub(crate) struct Hello {
+    prefix: String,
+}
+
+enum Status {
+    Active,
+}
+
+impl Greeter for Hello {
+    fn greet(&self, name: &str) -> String {
+        format!("{}{}", self.prefix, name)
+    }
+}
+
+pub(crate) const fn answer(mut seed: u8) -> u8 {
+    seed
+}
+
+macro_rules! square {
+    ($x:expr) => {
+        $x * $x
+    };
+}
+"""
+
+    def test_detects_methods_const_fns_and_macros(self):
+        result = RegexFallbackScanner().parse(self.RUST_CODE, "/lib.rs", "rust")
+
+        fns = {fn.name: fn for fn in result.functions}
+        assert set(fns) == {"greet", "answer", "square"}
+        assert fns["answer"].params == ["seed"]
+        assert fns["square"].start_line == 26
+
+    def test_detects_types_and_traits(self):
+        result = RegexFallbackScanner().parse(self.RUST_CODE, "/lib.rs", "rust")
+
+        kinds = {cls.name: cls.is_abstract for cls in result.classes}
+        assert kinds == {"Greeter": True, "Hello": False, "Status": False}
+
+    def test_detects_pub_use(self):
+        result = RegexFallbackScanner().parse(self.RUST_CODE, "/lib.rs", "rust")
+
+        assert [imp.source for imp in result.imports] == ["crate::config::Settings"]
+
+
+class TestKotlinFallback:
+    """Test Kotlin language support."""
+
+    KOTLIN_CODE = """
+import kotlinx.coroutines.launch
+import com.example.net.ApiClient
+
+sealed interface Result
+
+data class Item(val id: String)
+
+abstract class Base {
+    abstract fun key(id: String): String
+}
+
+object Scheduler {
+    @JvmStatic
+    suspend fun sync(vararg ids: String, retries: Int = 3) {
+        for (id in ids) {
+            launch { fetch(id) }
+        }
+    }
+}
+
+fun <T> List<T>.secondOrNull(): T? = if (size > 1) this[1] else null
+
+internal fun String.toItemId(): String {
+    return trim()
+}
+"""
+
+    def test_detects_suspend_and_extension_functions(self):
+        result = RegexFallbackScanner().parse(self.KOTLIN_CODE, "/Sync.kt", "kotlin")
+
+        fns = {fn.name: fn for fn in result.functions}
+        assert set(fns) == {"key", "sync", "secondOrNull", "toItemId"}
+        assert fns["sync"].params == ["ids", "retries"]
+        assert (fns["sync"].start_line, fns["sync"].end_line) == (14, 19)
+        # Expression bodies end on their own line
+        assert (fns["secondOrNull"].start_line, fns["secondOrNull"].end_line) == (22, 22)
+
+    def test_detects_classes_interfaces_and_objects(self):
+        result = RegexFallbackScanner().parse(self.KOTLIN_CODE, "/Sync.kt", "kotlin")
+
+        kinds = {cls.name: cls.is_abstract for cls in result.classes}
+        assert kinds == {"Result": True, "Item": False, "Base": True, "Scheduler": False}
+
+    def test_detects_imports(self):
+        result = RegexFallbackScanner().parse(self.KOTLIN_CODE, "/Sync.kt", "kotlin")
+
+        assert [imp.source for imp in result.imports] == [
+            "kotlinx.coroutines.launch",
+            "com.example.net.ApiClient",
+        ]
+
+
+class TestPhpFallback:
+    """Test PHP language support."""
+
+    PHP_CODE = """<?php
+namespace App\\Models;
+
+use Illuminate\\Database\\Eloquent\\Model;
+use function App\\helpers\\format;
+
+interface Publishable {}
+
+abstract class Post extends Model implements Publishable
+{
+    public function publish(int $at, bool $force = false): void
+    {
+        foreach ($this->tags as $tag) {
+            if ($force) {
+                $tag->touch();
+            }
+        }
+    }
+
+    abstract protected function slug(): string;
+
+    public static function &make(array ...$attrs) { return new static($attrs); }
+}
+"""
+
+    def test_detects_functions_and_methods(self):
+        result = RegexFallbackScanner().parse(self.PHP_CODE, "/Post.php", "php")
+
+        fns = {fn.name: fn for fn in result.functions}
+        assert set(fns) == {"publish", "slug", "make"}
+        assert fns["publish"].params == ["at", "force"]
+        assert (fns["publish"].start_line, fns["publish"].end_line) == (11, 18)
+        # Abstract methods have no body; one-liners end on their own line
+        assert (fns["slug"].body_tokens, fns["slug"].end_line) == (0, 20)
+        assert (fns["make"].start_line, fns["make"].end_line) == (22, 22)
+
+    def test_detects_classes_and_interfaces(self):
+        result = RegexFallbackScanner().parse(self.PHP_CODE, "/Post.php", "php")
+
+        classes = {cls.name: cls for cls in result.classes}
+        assert {name: cls.is_abstract for name, cls in classes.items()} == {
+            "Publishable": True,
+            "Post": True,
+        }
+        assert classes["Post"].bases == ["Model", "Publishable"]
+
+    def test_detects_class_imports_only(self):
+        result = RegexFallbackScanner().parse(self.PHP_CODE, "/Post.php", "php")
+
+        assert [imp.source for imp in result.imports] == ["Illuminate\\Database\\Eloquent\\Model"]
+
+
+class TestScalaFallback:
+    """Test Scala language support."""
+
+    SCALA_CODE = """package com.acme.repo
+
+import scala.concurrent.Future
+import com.acme.model.{User, Order}
+
+trait Repository[A] {
+  def find(id: Long): Future[Option[A]]
+}
+
+abstract class Base(val name: String) extends Closeable
+
+object Repos {
+  def find(id: Long, cache: Boolean)(implicit ec: ExecutionContext): Future[User] = {
+    if (id > 0) {
+      db.query(id)
+    } else Future.failed(new NoSuchElementException)
+  }
+
+  def count(limit: Int) =
+    db.count(limit)
+      .map(_ + 1)
+
+  def apply[A](db: Database): Repos = new Repos(db)
+}
+"""
+
+    def test_detects_functions(self):
+        result = RegexFallbackScanner().parse(self.SCALA_CODE, "/Repos.scala", "scala")
+
+        fns = [(fn.name, fn.start_line, fn.end_line) for fn in result.functions]
+        # Abstract defs have no body; expression bodies run while indented
+        assert fns == [
+            ("find", 7, 7),
+            ("find", 13, 17),
+            ("count", 19, 21),
+            ("apply", 23, 23),
+        ]
+        assert result.functions[0].body_tokens == 0
+        # Implicit parameter lists count too, the keyword does not
+        assert result.functions[1].params == ["id", "cache", "ec"]
+        assert result.functions[3].params == ["db"]
+
+    def test_detects_classes_traits_and_objects(self):
+        result = RegexFallbackScanner().parse(self.SCALA_CODE, "/Repos.scala", "scala")
+
+        classes = {cls.name: cls for cls in result.classes}
+        assert {name: cls.is_abstract for name, cls in classes.items()} == {
+            "Repository": True,
+            "Base": True,
+            "Repos": False,
+        }
+        assert classes["Base"].bases == ["Closeable"]
+
+    def test_detects_imports(self):
+        result = RegexFallbackScanner().parse(self.SCALA_CODE, "/Repos.scala", "scala")
+
+        # Selector groups are expanded afterwards (scanning/scala.py)
+        assert [imp.source for imp in result.imports] == [
+            "scala.concurrent.Future",
+            "com.acme.model",
+        ]
+
+
+class TestSwiftFallback:
+    """Test Swift language support."""
+
+    SWIFT_CODE = """import UIKit
+@testable import FeedCore
+
+protocol FeedDelegate: AnyObject {
+    func feedDidLoad(_ feed: Feed)
+}
+
+final class FeedViewController: UIViewController, UITableViewDelegate {
+    init(service: FeedService) {
+        self.service = service
+    }
+
+    @objc private func refresh(_ sender: UIRefreshControl, with animated: Bool = true) {
+        if animated {
+            sender.endRefreshing()
+        }
+    }
+
+    class func make() -> FeedViewController { FeedViewController(service: .shared) }
+}
+
+extension FeedViewController: UITableViewDataSource {
+    func load(completion: @escaping (Result<[Item], Error>) -> Void)
+    {
+        service.fetch(completion: completion)
+    }
+}
+"""
+
+    def test_detects_functions_and_initializers(self):
+        result = RegexFallbackScanner().parse(self.SWIFT_CODE, "/Feed.swift", "swift")
+
+        fns = {fn.name: fn for fn in result.functions}
+        assert {name: (fn.start_line, fn.end_line) for name, fn in fns.items()} == {
+            "feedDidLoad": (5, 5),
+            "refresh": (13, 17),
+            "make": (19, 19),
+            "load": (23, 26),
+            "init": (9, 11),
+        }
+        # Protocol requirements have no body
+        assert fns["feedDidLoad"].body_tokens == 0
+        # Internal names, not argument labels; closure types nest parentheses
+        assert fns["refresh"].params == ["sender", "animated"]
+        assert fns["load"].params == ["completion"]
+
+    def test_detects_types_and_extensions(self):
+        result = RegexFallbackScanner().parse(self.SWIFT_CODE, "/Feed.swift", "swift")
+
+        # class func is a method, not a type
+        assert [(cls.name, cls.bases, cls.is_abstract) for cls in result.classes] == [
+            ("FeedDelegate", ["AnyObject"], True),
+            ("FeedViewController", ["UIViewController", "UITableViewDelegate"], False),
+            ("FeedViewController", ["UITableViewDataSource"], False),
+        ]
+
+    def test_detects_imports(self):
+        result = RegexFallbackScanner().parse(self.SWIFT_CODE, "/Feed.swift", "swift")
+
+        assert [imp.source for imp in result.imports] == ["UIKit", "FeedCore"]
+
+
+class TestSqlFallback:
+    """Test SQL support: routines are functions, tables and views are classes."""
+
+    SQL_CODE = """-- CREATE TABLE commented_out (id int);
+CREATE TABLE IF NOT EXISTS app.users (
+    id SERIAL PRIMARY KEY,
+    email TEXT NOT NULL
+);
+
+CREATE MATERIALIZED VIEW active_users AS
+    SELECT * FROM users WHERE active;
+
+create or replace function touch(p_id integer, out touched_at timestamptz)
+returns timestamptz as $$
+begin
+    update users set seen = now() where id = p_id;
+end;
+$$ language plpgsql;
+
+DELIMITER //
+CREATE DEFINER=`root`@`localhost` PROCEDURE purge(IN days INT)
+BEGIN
+    DELETE FROM users WHERE created < NOW() - INTERVAL days DAY;
+END //
+DELIMITER ;
+"""
+
+    def test_detects_routines(self):
+        result = RegexFallbackScanner().parse(self.SQL_CODE, "/schema.sql", "sql")
+
+        assert [(fn.name, fn.params, fn.start_line, fn.end_line) for fn in result.functions] == [
+            ("touch", ["p_id", "touched_at"], 10, 15),
+            ("purge", ["days"], 18, 21),
+        ]
+
+    def test_detects_tables_and_views(self):
+        result = RegexFallbackScanner().parse(self.SQL_CODE, "/schema.sql", "sql")
+
+        assert [cls.name for cls in result.classes] == ["users", "active_users"]
+
+
+class TestCFallback:
+    """Test C and C++ support."""
+
+    C_CODE = """
+#include <stdio.h>
+#include "util/buffer.h"
+
+typedef struct {
+    int count;
+} Stats;
+
+struct node {
+    struct node *next;
+};
+
+static const char *
+node_label(struct node *n, int depth)
+{
+    if (n->next) {
+        return "inner";
+    }
+    return "leaf";
+}
+
+int main(void) {
+    return 0;
+}
+"""
+
+    CPP_CODE = """
+class Shape {
+public:
+    virtual double area() const = 0;
+};
+
+class Square final : public Shape, private util::Noncopyable {
+public:
+    double area() const override {
+        if (side_ > 0) {
+            return side_ * side_;
+        }
+        return 0;
+    }
+};
+
+void Square::resize(double side, int steps = 1) {
+    side_ = side;
+}
+"""
+
+    def test_detects_c_functions(self):
+        result = RegexFallbackScanner().parse(self.C_CODE, "/node.c", "c")
+
+        fns = {fn.name: fn for fn in result.functions}
+        assert set(fns) == {"node_label", "main"}
+        assert fns["node_label"].params == ["n", "depth"]
+        assert (fns["node_label"].start_line, fns["node_label"].end_line) == (13, 20)
+        assert fns["main"].params == []
+
+    def test_detects_c_structs_and_includes(self):
+        result = RegexFallbackScanner().parse(self.C_CODE, "/node.c", "c")
+
+        assert [cls.name for cls in result.classes] == ["node", "Stats"]
+        assert [imp.source for imp in result.imports] == ["stdio.h", "util/buffer.h"]
+
+    def test_detects_cpp_methods_and_bases(self):
+        result = RegexFallbackScanner().parse(self.CPP_CODE, "/shape.cpp", "cpp")
+
+        fns = {fn.name: fn for fn in result.functions}
+        assert set(fns) == {"area", "resize"}
+        assert fns["resize"].params == ["side", "steps"]
+        assert (fns["area"].start_line, fns["area"].end_line) == (9, 14)
+        bases = {cls.name: cls.bases for cls in result.classes}
+        assert bases == {"Shape": [], "Square": ["Shape", "Noncopyable"]}
+
+
+class TestRubyFallback:
+    """Test Ruby language support."""
+
+    RUBY_CODE = """
+class Admin::Report < ApplicationRecord
+
+  def self.build(rows)
+    new(rows)
+  end
+
+  def ready?
+    true
+  end
+
+  private def reset!
+    @rows = []
+  end
+end
+"""
+
+    def test_methods(self):
+        scanner = RegexFallbackScanner()
+        result = scanner.parse(self.RUBY_CODE, "/report.rb", "ruby")
+        # Singleton, predicate and inline-private methods; lines are the def lines
+        assert [(fn.name, fn.start_line) for fn in result.functions] == [
+            ("build", 4),
+            ("ready?", 8),
+            ("reset!", 12),
+        ]
+
+    def test_namespaced_class(self):
+        scanner = RegexFallbackScanner()
+        result = scanner.parse(self.RUBY_CODE, "/report.rb", "ruby")
+        assert [cls.name for cls in result.classes] == ["Admin::Report"]
+
+
 class TestEmptyFile:
     """Test handling of empty/minimal files."""
 
@@ -0,0 +1,42 @@
+"""Tests for generated-file detection."""
+
+from shannon_insight.scanning.generated import find_generated_files, generated_reason
+
+_BUNDLE = "var a=" + ",".join(f"b{i}=function(c){{return c+{i}}}" for i in range(60)) + ";\n"
+
+
+class TestGeneratedReason:
+    def test_protobuf_names(self):
+        assert generated_reason("api/user_pb2.py", "") == "protobuf"
+        assert generated_reason("api/user.pb.go", "package api\n") == "protobuf"
+        assert generated_reason("web/user_pb.js", "") == "protobuf"
+        assert generated_reason("api/user.py", "") is None
+
+    def test_header_markers(self):
+        go = "// Code generated by MockGen. DO NOT EDIT.\n// Source: store.go\n\npackage mocks\n"
+        php = "<?php\n/**\n * @generated by the schema compiler\n */\nclass User {}\n"
+        cs = "//------\n// <auto-generated>\n//     by a tool\n// </auto-generated>\n"
+        py = "#!/usr/bin/env python\n# Autogenerated by Thrift Compiler\n\nimport sys\n"
+        for content in (go, php, cs, py):
+            assert generated_reason("x", content) == "header"
+
+    def test_marker_after_code_is_ignored(self):
+        content = 'package gen\n\n// Code generated by hand. DO NOT EDIT.\nconst x = "y"\n'
+        assert generated_reason("gen.go", content) is None
+        docstring = '"""This file was generated once, then edited."""\n'
+        assert generated_reason("gen.py", docstring) is None
+
+    def test_minified(self):
+        assert generated_reason("static/app.js", _BUNDLE) == "minified"
+        assert generated_reason("static/app.py", _BUNDLE) is None
+        assert generated_reason("static/data.js", "var x = " + "1," * 200 + "\n") is None
+        readable = "".join(f"function f{i}(c) {{\n  return c + {i};\n}}\n" for i in range(60))
+        assert generated_reason("static/app.js", readable) is None
+
+    def test_find_generated_files(self):
+        contents = {
+            "z.pb.go": "",
+            "app.min.js": _BUNDLE,
+            "main.go": "package main\n",
+        }
+        assert find_generated_files(contents) == {"app.min.js": "minified", "z.pb.go": "protobuf"}
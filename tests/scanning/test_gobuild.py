@@ -0,0 +1,122 @@
+"""Tests for Go build constraints."""
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.scanning.gobuild import (
+    GoBuildConfig,
+    build_constraint,
+    evaluate,
+    go_build_config,
+)
+
+LINUX = GoBuildConfig()
+WINDOWS = GoBuildConfig(goos="windows", goarch="arm64")
+
+
+class TestEvaluate:
+    @pytest.mark.parametrize(
+        "expression, expected",
+        [
+            ("linux", True),
+            ("!linux", False),
+            ("linux && amd64", True),
+            ("darwin || amd64", True),
+            ("!(darwin || windows) && amd64", True),
+            ("linux && !cgo || windows", False),
+        ],
+    )
+    def test_operators(self, expression, expected):
+        assert evaluate(expression, {"linux", "amd64", "cgo"}.__contains__) is expected
+
+    @pytest.mark.parametrize("expression", ["", "linux &&", "(linux", "linux)", "linux, amd64"])
+    def test_malformed(self, expression):
+        with pytest.raises(ValueError):
+            evaluate(expression, lambda tag: True)
+
+
+class TestBuildConstraint:
+    def test_go_build_line(self):
+        content = "// Copyright\n\n//go:build linux && !cgo\n\npackage net\n"
+
+        assert build_constraint(content) == "linux && !cgo"
+
+    def test_plus_build_lines_are_converted(self):
+        content = "// +build linux,amd64 darwin\n// +build !cgo\n\npackage net\n"
+
+        assert build_constraint(content) == "((linux && amd64) || (darwin)) && ((!cgo))"
+
+    def test_only_the_header_counts(self):
+        content = "/* docs\n//go:build windows\n*/\npackage net\n\n//go:build windows\n"
+
+        assert build_constraint(content) is None
+
+
+class TestSatisfies:
+    def test_platform_tags(self):
+        assert LINUX.satisfies("unix") and LINUX.satisfies("cgo") and LINUX.satisfies("go1.21")
+        assert not WINDOWS.satisfies("unix")
+        assert GoBuildConfig(goos="android").satisfies("linux")
+        assert not LINUX.satisfies("gccgo") and not LINUX.satisfies("ignore")
+
+    def test_user_tags_and_cgo(self):
+        config = GoBuildConfig(tags=frozenset({"integration"}), cgo=False)
+
+        assert config.satisfies("integration")
+        assert not config.satisfies("cgo")
+
+
+class TestCompiles:
+    @pytest.mark.parametrize(
+        "path, linux, windows",
+        [
+            ("net/fd.go", True, True),
+            ("net/fd_windows.go", False, True),
+            ("net/fd_unix_test.go", True, True),  # "unix" is not a GOOS suffix
+            ("net/fd_linux_test.go", True, False),
+            ("net/fd_windows_arm64.go", False, True),
+            ("net/fd_linux_arm64.go", False, False),
+            ("net/fd_arm64.go", False, True),
+            ("cmd/windows.go", True, True),  # the whole name is not a suffix
+        ],
+    )
+    def test_file_names(self, path, linux, windows):
+        assert LINUX.compiles(path, "package net\n") is linux
+        assert WINDOWS.compiles(path, "package net\n") is windows
+
+    def test_constraints(self):
+        unix = "//go:build unix\n\npackage net\n"
+
+        assert LINUX.compiles("net/fd.go", unix)
+        assert not WINDOWS.compiles("net/fd.go", unix)
+        assert not LINUX.compiles("net/fd.go", "//go:build ignore\n\npackage main\n")
+        assert LINUX.compiles("net/fd.go", "//go:build linux &&\n\npackage net\n")
+
+    def test_cgo_files(self):
+        cgo = '// #include <stdio.h>\nimport "C"\n'
+
+        assert LINUX.compiles("net/cgo.go", "package net\n\n" + cgo)
+        assert not GoBuildConfig(cgo=False).compiles("net/cgo.go", "package net\n\n" + cgo)
+
+
+def test_go_build_config():
+    config = AnalysisConfig(go_os="windows", go_tags=["integration"], go_cgo=False)
+
+    assert go_build_config(config) == GoBuildConfig(
+        goos="windows", goarch="amd64", tags=frozenset({"integration"}), cgo=False
+    )
+    assert go_build_config(AnalysisConfig(go_constraints="all")) is None
+
+
+@pytest.mark.parametrize(
+    "settings, message",
+    [
+        ({"go_os": "win32"}, "go_os"),
+        ({"go_arch": "x64"}, "go_arch"),
+        ({"go_tags": ["a b"]}, "go_tags"),
+        ({"go_constraints": "some"}, "go_constraints"),
+    ],
+)
+def test_config_validation(settings, message):
+    with pytest.raises(ValueError, match=message):
+        AnalysisConfig(**settings)
@@ -0,0 +1,171 @@
+"""Tests for custom tree-sitter grammars."""
+
+import importlib.util
+from pathlib import Path
+
+import pytest
+
+from shannon_insight.environment import _is_source_file
+from shannon_insight.scanning.grammars import (
+    CustomGrammar,
+    CustomGrammarParser,
+    extract_syntax,
+    parse_grammars,
+)
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+
+ELIXIR = {
+    "library": "grammars/elixir.so",
+    "extensions": [".ex", ".EXS"],
+    "function": ["def"],
+    "class": ["module"],
+    "import": ["import"],
+    "call": ["call"],
+    "nesting": ["if"],
+}
+
+SOURCE = """import "lib/repo"
+defmodule Shop do
+  def total(items) do
+    if empty do
+      repo.save(items)
+    end
+  end
+end
+"""
+
+
+class _Node:
+    """Enough of tree_sitter.Node for the generic walk, spanning *text* in SOURCE."""
+
+    def __init__(self, type, text, children=(), after=0, **fields):
+        self.type = type
+        self.start_byte = SOURCE.index(text, after)
+        self.end_byte = self.start_byte + len(text)
+        self.children = list(children)
+        self.named_children = self.children
+        self.start_point = (SOURCE.count("\n", 0, self.start_byte), 0)
+        self.end_point = (SOURCE.count("\n", 0, self.end_byte), 0)
+        self._fields = fields
+
+    def child_by_field_name(self, name):
+        return self._fields.get(name)
+
+
+def _tree():
+    def leaf(type, text, after=0):
+        return _Node(type, text, after=after)
+
+    at_def = SOURCE.index("def total")
+    call = _Node(
+        "call",
+        "repo.save(items)",
+        [leaf("identifier", "repo.save"), leaf("identifier", "items", SOURCE.index("save"))],
+        function=leaf("identifier", "repo.save"),
+    )
+    branch = _Node(
+        "if", "if empty do\n      repo.save(items)\n    end", [leaf("identifier", "empty"), call]
+    )
+    params = _Node("params", "(items)", [leaf("identifier", "items", at_def)])
+    body = _Node("block", "do\n    if", [branch], after=at_def)
+    body.end_byte = branch.end_byte
+    name = leaf("identifier", "total")
+    fn = _Node("def", SOURCE[at_def : SOURCE.index("  end\nend")], [name, params, body])
+    fn.children = [leaf("keyword", "def", at_def), name, params, body]
+    fn._fields = {"name": name, "parameters": params, "body": body}
+    module_name = leaf("alias", "Shop")
+    module = _Node("module", SOURCE[SOURCE.index("defmodule") :].rstrip(), [module_name, fn])
+    module._fields = {"name": module_name}
+    imported = _Node("import", 'import "lib/repo"', [leaf("string", '"lib/repo"')])
+    return _Node("source", SOURCE, [imported, module])
+
+
+class TestParseGrammars:
+    def test_reads_library_extensions_and_node_types(self):
+        (grammar,) = parse_grammars({"elixir": ELIXIR})
+
+        assert grammar.extensions == (".ex", ".exs")
+        assert grammar.symbol == "tree_sitter_elixir"
+        assert grammar.function == frozenset({"def"})
+        assert grammar.class_ == frozenset({"module"})
+
+    @pytest.mark.parametrize(
+        "table, message",
+        [
+            ({**ELIXIR, "library": "elixir.jar"}, "library must be a path ending in"),
+            ({**ELIXIR, "extensions": []}, "extensions must list suffixes"),
+            ({**ELIXIR, "extensions": ["ex"]}, "extensions must list suffixes"),
+            ({**ELIXIR, "function": "def"}, "function must be a list of strings"),
+            ({**ELIXIR, "functions": ["def"]}, "unknown keys functions"),
+        ],
+    )
+    def test_rejects_invalid_tables(self, table, message):
+        with pytest.raises(ValueError, match=message):
+            parse_grammars({"elixir": table})
+
+
+class TestExtractSyntax:
+    def test_maps_node_types_to_functions_classes_and_imports(self):
+        (grammar,) = parse_grammars({"elixir": ELIXIR})
+
+        syntax = extract_syntax(_tree(), SOURCE.encode(), grammar, "lib/shop.ex")
+
+        assert syntax.language == "elixir"
+        (fn,) = syntax.functions
+        assert (fn.name, fn.params, fn.start_line, fn.end_line) == ("total", ["items"], 3, 7)
+        assert fn.nesting_depth == 1
+        assert fn.call_targets == ["save"]
+        (cls,) = syntax.classes
+        assert cls.name == "Shop"
+        assert cls.methods == [fn]
+        assert [i.source for i in syntax.imports] == ["lib/repo"]
+
+    def test_without_call_types_functions_have_no_call_targets(self):
+        (grammar,) = parse_grammars({"elixir": {**ELIXIR, "call": []}})
+
+        syntax = extract_syntax(_tree(), SOURCE.encode(), grammar, "lib/shop.ex")
+
+        assert syntax.functions[0].call_targets is None
+
+
+class TestCustomGrammarFiles:
+    def test_extensions_are_source_files(self):
+        assert _is_source_file("lib/shop.ex", extra_extensions={".ex"})
+        assert not _is_source_file("lib/shop.ex")
+
+    def test_unloadable_grammar_falls_back_to_regex(self, tmp_path):
+        path = tmp_path / "shop.ex"
+        path.write_text(SOURCE)
+        extractor = SyntaxExtractor(grammars={"elixir": ELIXIR})
+
+        syntax = extractor.extract(path, tmp_path)
+
+        assert syntax.language == "elixir"
+        assert (extractor.fallback_count, extractor.treesitter_count) == (1, 0)
+
+    @pytest.mark.skipif(
+        importlib.util.find_spec("tree_sitter") is None
+        or importlib.util.find_spec("tree_sitter_python") is None,
+        reason="tree-sitter-python not installed",
+    )
+    def test_loads_a_compiled_grammar(self, tmp_path):
+        import tree_sitter_python
+
+        library = next(Path(tree_sitter_python.__file__).parent.glob("_binding*"))
+        grammar = CustomGrammar(
+            "py",
+            str(library),
+            (".pyx",),
+            "tree_sitter_python",
+            function=frozenset({"function_definition"}),
+            call=frozenset({"call"}),
+            nesting=frozenset({"if_statement"}),
+        )
+        parser = CustomGrammarParser([grammar])
+
+        syntax = parser.parse_file(
+            "def f(x):\n    if x:\n        g(x)\n", "m.pyx", grammar, tmp_path
+        )
+
+        (fn,) = syntax.functions
+        assert (fn.name, fn.params, fn.nesting_depth, fn.call_targets) == ("f", ["x"], 1, ["g"])
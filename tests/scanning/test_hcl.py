@@ -0,0 +1,141 @@
+"""Tests for HCL block parsing and Terraform resource/module extraction."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.hcl import annotate_hcl, parse_hcl
+from shannon_insight.scanning.languages import detect_language
+
+_MAIN = """\
+# Network for the web tier { not a block
+terraform {
+  required_version = ">= 1.5"
+}
+
+locals {
+  tags = {
+    Name = "web-${var.env}"
+    Team = "core" # }
+  }
+  public_ids = [
+    for s in var.subnets : s.id
+    if s.public
+  ]
+}
+
+module "vpc" {
+  source = "./modules/vpc"
+  cidr   = "10.0.0.0/16"
+}
+
+module "dns" {
+  source  = "terraform-aws-modules/route53/aws"
+  version = "~> 2.0"
+}
+
+data "aws_ami" "ubuntu" {
+  most_recent = true
+}
+
+resource "aws_security_group" "web" {
+  name      = "web"
+  user_data = <<-EOT
+    #!/bin/bash
+    echo "{"
+  EOT
+
+  dynamic "ingress" {
+    for_each = var.rules
+    content {
+      from_port = ingress.value.port
+      dynamic "cidr" {
+        for_each = ingress.value.cidrs
+        content {
+          block = cidr.value
+        }
+      }
+    }
+  }
+
+  lifecycle { create_before_destroy = true }
+}
+"""
+
+
+def _annotated(content, path="main.tf"):
+    syntax = RegexFallbackScanner().parse(content, path, "hcl")
+    annotate_hcl(syntax, content)
+    return syntax
+
+
+class TestParseHcl:
+    def test_top_level_blocks(self):
+        blocks = parse_hcl(_MAIN)
+
+        assert [(b.type, b.labels, b.start_line, b.end_line) for b in blocks] == [
+            ("terraform", [], 2, 4),
+            ("locals", [], 6, 15),
+            ("module", ["vpc"], 17, 20),
+            ("module", ["dns"], 22, 25),
+            ("data", ["aws_ami", "ubuntu"], 27, 29),
+            ("resource", ["aws_security_group", "web"], 31, 52),
+        ]
+
+    def test_object_and_for_expressions_are_attributes(self):
+        locals_block = parse_hcl(_MAIN)[1]
+
+        assert locals_block.blocks == []
+        assert list(locals_block.attributes) == ["tags", "public_ids"]
+
+    def test_heredocs_comments_and_interpolations_open_no_blocks(self):
+        resource = parse_hcl(_MAIN)[-1]
+
+        assert list(resource.attributes) == ["name", "user_data"]
+        assert [b.type for b in resource.blocks] == ["dynamic", "lifecycle"]
+        assert resource.depth == 4
+
+    def test_nested_blocks(self):
+        ingress = parse_hcl(_MAIN)[-1].blocks[0]
+
+        assert [(b.type, b.name, b.start_line) for b in ingress.walk()] == [
+            ("dynamic", "ingress", 38),
+            ("content", "", 40),
+            ("dynamic", "cidr", 42),
+            ("content", "", 44),
+        ]
+
+    def test_string_attributes(self):
+        vpc = parse_hcl(_MAIN)[2]
+
+        assert vpc.string("source") == "./modules/vpc"
+        assert vpc.string("missing") is None
+        assert parse_hcl('x "y" {\n  a = "${var.b}"\n}\n')[0].string("a") is None
+
+    def test_unterminated_block_ends_at_last_line(self):
+        (block,) = parse_hcl('resource "a" "b" {\n  x = 1\n')
+
+        assert block.end_line == 2
+
+
+class TestAnnotateHcl:
+    def test_resources_data_and_modules_are_functions(self):
+        syntax = _annotated(_MAIN)
+
+        functions = [(f.name, f.start_line, f.end_line, f.nesting_depth) for f in syntax.functions]
+        assert functions == [
+            ("module.vpc", 17, 20, 0),
+            ("module.dns", 22, 25, 0),
+            ("data.aws_ami.ubuntu", 27, 29, 0),
+            ("aws_security_group.web", 31, 52, 4),
+        ]
+        assert syntax.classes == []
+
+    def test_module_sources_are_imports(self):
+        syntax = _annotated(_MAIN)
+
+        assert [(i.source, i.names) for i in syntax.imports] == [
+            ("./modules/vpc", ["vpc"]),
+            ("terraform-aws-modules/route53/aws", ["dns"]),
+        ]
+
+    def test_detect_language(self):
+        assert detect_language("infra/main.tf") == "hcl"
+        assert detect_language("live/terragrunt.hcl") == "hcl"
@@ -86,6 +86,60 @@ class TestLanguageFixtures:
 
         assert result is not None
         assert result.language == "rust"
+        assert {"new", "greet", "process_data", "main"} <= {fn.name for fn in result.functions}
+        assert {"Greeter", "HelloGreeter", "Status"} <= {cls.name for cls in result.classes}
+
+    def test_kotlin_fixture(self, extractor):
+        """Parse Kotlin fixture file."""
+        fixture = FIXTURES_DIR / "sample.kt"
+        assert fixture.exists(), f"Missing fixture: {fixture}"
+
+        result = extractor.extract(fixture, FIXTURES_DIR)
+
+        assert result is not None
+        assert result.language == "kotlin"
+        fns = {fn.name for fn in result.functions}
+        assert {"fetch", "cacheKey", "start", "toItemId", "secondOrNull"} <= fns
+        classes = {cls.name for cls in result.classes}
+        assert {"Repository", "BaseRepository", "ItemRepository", "SyncScheduler"} <= classes
+
+    def test_scala_fixture(self, extractor):
+        """Parse Scala fixture file."""
+        fixture = FIXTURES_DIR / "sample.scala"
+        assert fixture.exists(), f"Missing fixture: {fixture}"
+
+        result = extractor.extract(fixture, FIXTURES_DIR)
+
+        assert result is not None
+        assert result.language == "scala"
+        assert {"fetch", "cacheKey", "start"} <= {fn.name for fn in result.functions}
+        classes = {cls.name: cls for cls in result.classes}
+        assert {"Repository", "Item", "BaseRepository", "ItemRepository", "SyncScheduler"} <= set(
+            classes
+        )
+        assert classes["ItemRepository"].bases == ["BaseRepository"]
+        assert classes["Item"].fields == ["id", "title"]
+        assert {"com.example.sync.net.ApiClient", "scala.concurrent.Future"} <= set(
+            result.import_sources
+        )
+
+    def test_swift_fixture(self, extractor):
+        """Parse Swift fixture file."""
+        fixture = FIXTURES_DIR / "sample.swift"
+        assert fixture.exists(), f"Missing fixture: {fixture}"
+
+        result = extractor.extract(fixture, FIXTURES_DIR)
+
+        assert result is not None
+        assert result.language == "swift"
+        assert {"fetch", "cacheKey", "start", "init"} <= {fn.name for fn in result.functions}
+        repositories = [cls for cls in result.classes if cls.name == "ItemRepository"]
+        assert [cls.is_extension for cls in repositories] == [False, True]
+        assert [m.name for m in repositories[1].methods] == ["cacheKey"]
+        item = next(cls for cls in result.classes if cls.name == "Item")
+        assert item.fields == ["id", "title"]
+        assert next(cls for cls in result.classes if cls.name == "Repository").is_abstract
+        assert result.import_sources == ["Foundation", "SyncNet"]
 
     def test_ruby_fixture(self, extractor):
         """Parse Ruby fixture file."""
@@ -98,6 +152,21 @@ class TestLanguageFixtures:
         assert result.language == "ruby"
         assert result.function_count > 0 or result.class_count > 0
 
+    def test_php_fixture(self, extractor):
+        """Parse PHP fixture file."""
+        fixture = FIXTURES_DIR / "sample.php"
+        assert fixture.exists(), f"Missing fixture: {fixture}"
+
+        result = extractor.extract(fixture, FIXTURES_DIR)
+
+        assert result is not None
+        assert result.language == "php"
+        assert {"greet", "please", "process_data"} <= {fn.name for fn in result.functions}
+        classes = {cls.name: cls for cls in result.classes}
+        assert {"Greeter", "Politeness", "HelloGreeter"} <= set(classes)
+        assert classes["HelloGreeter"].fields == ["prefix", "logger"]
+        assert {"App\\Models\\User", "Psr\\Log\\LoggerInterface"} <= set(result.import_sources)
+
     def test_c_fixture(self, extractor):
         """Parse C fixture file."""
         fixture = FIXTURES_DIR / "sample.c"
@@ -107,6 +176,12 @@ class TestLanguageFixtures:
 
         assert result is not None
         assert result.language == "c"
+        fns = [fn.name for fn in result.functions]
+        # Only the non-_WIN32 branch of line_end is parsed
+        assert fns.count("line_end") == 1
+        assert {"greeter_init", "process_data", "helper", "main"} <= set(fns)
+        assert {"Greeter", "Stats"} <= {cls.name for cls in result.classes}
+        assert {"stdio.h", "string.h"} <= {imp.source for imp in result.imports}
 
     def test_cpp_fixture(self, extractor):
         """Parse C++ fixture file."""
@@ -116,8 +191,9 @@ class TestLanguageFixtures:
         result = extractor.extract(fixture, FIXTURES_DIR)
 
         assert result is not None
-        # .cpp files are handled by C config (includes .c, .cpp, .h, .hpp)
-        assert result.language in ("c", "cpp")
+        assert result.language == "cpp"
+        assert {"greet", "process_data", "main"} <= {fn.name for fn in result.functions}
+        assert {"Greeter", "HelloGreeter"} <= {cls.name for cls in result.classes}
 
 
 class TestEncodingFallback:
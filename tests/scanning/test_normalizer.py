@@ -158,6 +158,48 @@ func helper(x int) int {
         fn_names = [fn.name for fn in result.functions]
         assert "main" in fn_names or len(result.functions) > 0
 
+    def test_go_generics_and_methods(self):
+        """Type parameters and receivers are not names; instantiations call the generic."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "go" not in get_supported_languages():
+            pytest.skip("Go grammar not installed")
+
+        go_code = """
+package slices
+
+func Map[T, U any](xs []T, f func(T) U) []U {
+\tout := make([]U, 0, len(xs))
+\tfor _, x := range xs {
+\t\tout = append(out, f(x))
+\t}
+\treturn out
+}
+
+type Stack[T any] struct {
+\titems []T
+}
+
+func (s *Stack[T]) Push(v T) {
+\ts.items = append(s.items, v)
+}
+
+func Lengths(words []string, a, b int) []int {
+\treturn Map[string, int](words, strings.Count)
+}
+"""
+        result = TreeSitterNormalizer().parse_file(go_code, "/slices.go", "go")
+
+        assert result is not None
+        assert [fn.name for fn in result.functions] == ["Map", "Push", "Lengths"]
+        assert [cls.name for cls in result.classes] == ["Stack"]
+        mapper, push, lengths = result.functions
+        assert mapper.params == ["xs", "f"]
+        assert push.params == ["v"]
+        assert lengths.params == ["words", "a", "b"]
+        assert "Map" in (lengths.call_targets or [])
+        assert "append" in (push.call_targets or [])
+
     def test_typescript_parsing(self):
         """Parse TypeScript code."""
         from shannon_insight.scanning.treesitter_parser import get_supported_languages
@@ -254,6 +296,304 @@ fn helper(x: i32) -> i32 {
 
         assert result is not None
 
+    def test_rust_impls_traits_and_macros(self):
+        """Impl methods attach to their type; macros count as functions and calls."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "rust" not in get_supported_languages():
+            pytest.skip("Rust grammar not installed")
+
+        rust_code = """
+pub trait Greeter: Clone {
+    fn greet(&self, name: &str) -> String;
+    fn wave(&self) {}
+}
+
+pub struct Hello {
+    prefix: std::string::String,
+    count: u32,
+}
+
+impl Greeter for Hello {
+    fn greet(&self, name: &str) -> String {
+        match name {
+            "" => square!(2).to_string(),
+            _ => Hello::build(name),
+        }
+    }
+}
+
+macro_rules! square {
+    ($x:expr) => { $x * $x };
+}
+"""
+        result = TreeSitterNormalizer().parse_file(rust_code, "/lib.rs", "rust")
+
+        assert result is not None
+        classes = {cls.name: cls for cls in result.classes}
+        assert classes["Hello"].fields == ["prefix", "count"]
+        assert classes["Hello"].bases == ["Greeter"]
+        assert [m.name for m in classes["Hello"].methods] == ["greet"]
+        assert classes["Greeter"].is_abstract
+        assert classes["Greeter"].bases == ["Clone"]
+        assert [m.name for m in classes["Greeter"].methods] == ["greet", "wave"]
+        fns = {fn.name: fn for fn in result.functions}
+        assert "square" in fns
+        greet = next(fn for fn in result.functions if fn.name == "greet" and fn.body_tokens)
+        assert {"square", "build"} <= set(greet.call_targets or [])
+        assert greet.nesting_depth >= 1
+
+    def test_kotlin_classes_extensions_and_coroutines(self):
+        """Members attach to their class; extension and suspend funs count as functions."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "kotlin" not in get_supported_languages():
+            pytest.skip("Kotlin grammar not installed")
+
+        kotlin_code = """
+import kotlinx.coroutines.launch
+
+interface Repository {
+    suspend fun fetch(id: String): String?
+}
+
+@Serializable
+class ItemRepository(private val client: Client, retries: Int) : Base(), Repository {
+    private val cache = mutableMapOf<String, String>()
+
+    override suspend fun fetch(id: String): String? {
+        when (id) {
+            "" -> return null
+            else -> scope.launch { client.get(id) }
+        }
+        return cache[id]
+    }
+}
+
+fun <T> List<T>.secondOrNull(): T? = if (size > 1) this[1] else null
+"""
+        result = TreeSitterNormalizer().parse_file(kotlin_code, "/Repo.kt", "kotlin")
+
+        assert result is not None
+        classes = {cls.name: cls for cls in result.classes}
+        assert classes["Repository"].is_abstract
+        repo = classes["ItemRepository"]
+        assert repo.bases == ["Base", "Repository"]
+        assert repo.fields == ["client", "cache"]
+        assert [m.name for m in repo.methods] == ["fetch"]
+        assert [imp.source for imp in result.imports] == ["kotlinx.coroutines.launch"]
+        fns = {fn.name: fn for fn in result.functions}
+        assert fns["secondOrNull"].params == []
+        fetch = next(fn for fn in result.functions if fn.name == "fetch" and fn.body_tokens)
+        assert fetch.params == ["id"]
+        assert {"launch", "get"} <= set(fetch.call_targets or [])
+        assert fetch.nesting_depth >= 1
+
+    def test_php_methods_parameters_and_calls(self):
+        """Methods, traits and enums are found; calls name their callee without scope."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "php" not in get_supported_languages():
+            pytest.skip("PHP grammar not installed")
+
+        php_code = """<?php
+namespace App\\Services;
+
+trait Loggable {}
+
+enum Status: string { case Draft = 'draft'; }
+
+class Publisher
+{
+    public function __construct(private Mailer $mailer) {}
+
+    public function publish(Post $post, string ...$tags): void
+    {
+        foreach ($tags as $tag) {
+            if ($post->hasTag($tag)) {
+                Cache::forget($tag);
+            }
+        }
+        $this->mailer?->send(new \\App\\Mail\\Published($post));
+        logger($post);
+    }
+}
+"""
+        result = TreeSitterNormalizer().parse_file(php_code, "/Publisher.php", "php")
+
+        assert result is not None
+        assert {cls.name for cls in result.classes} == {"Loggable", "Status", "Publisher"}
+        fns = {fn.name: fn for fn in result.functions}
+        assert fns["__construct"].params == ["mailer"]
+        publish = fns["publish"]
+        assert publish.params == ["post", "tags"]
+        assert {"hasTag", "forget", "send", "Published", "logger"} <= set(
+            publish.call_targets or []
+        )
+        assert publish.nesting_depth >= 2
+
+    def test_scala_definitions_parameters_and_calls(self):
+        """Curried parameter lists count; method calls name the method, not the receiver."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "scala" not in get_supported_languages():
+            pytest.skip("Scala grammar not installed")
+
+        scala_code = """
+package com.acme
+
+trait Publisher {
+  def publish(post: Post): Unit
+}
+
+object Mailer
+
+class Service(repo: Repo) extends Publisher {
+  def publish(post: Post): Unit = {
+    for (tag <- post.tags) {
+      if (repo.hasTag(tag)) {
+        notify(tag)
+      }
+    }
+  }
+
+  def fold[A](zero: A)(op: (A, Post) => A): A = zero
+}
+"""
+        result = TreeSitterNormalizer().parse_file(scala_code, "/Service.scala", "scala")
+
+        assert result is not None
+        assert {cls.name for cls in result.classes} == {"Publisher", "Mailer", "Service"}
+        fns = [fn for fn in result.functions if fn.name == "publish"]
+        assert [fn.body_tokens > 0 for fn in fns] == [False, True]
+        assert fns[1].params == ["post"]
+        assert {"hasTag", "notify"} <= set(fns[1].call_targets or [])
+        assert fns[1].nesting_depth >= 2
+        fold = next(fn for fn in result.functions if fn.name == "fold")
+        assert fold.params == ["zero", "op"]
+
+    def test_swift_initializers_extensions_and_calls(self):
+        """init is a function; extensions are classes; parameters use internal names."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "swift" not in get_supported_languages():
+            pytest.skip("Swift grammar not installed")
+
+        swift_code = """
+import Foundation
+
+protocol Publisher {
+    func publish(_ post: Post)
+}
+
+final class Service: Publisher {
+    init(repo: Repo) {
+        self.repo = repo
+    }
+
+    func publish(_ post: Post) {
+        for tag in post.tags {
+            guard repo.hasTag(tag) else { continue }
+            notify(tag)
+        }
+    }
+}
+
+extension Service {
+    func archive(post: Post, into folder: Folder) {}
+}
+"""
+        result = TreeSitterNormalizer().parse_file(swift_code, "/Service.swift", "swift")
+
+        assert result is not None
+        assert [cls.name for cls in result.classes] == ["Publisher", "Service", "Service"]
+        fns = [fn for fn in result.functions if fn.name == "publish"]
+        assert [fn.body_tokens > 0 for fn in fns] == [False, True]
+        assert fns[1].params == ["post"]
+        assert {"hasTag", "notify"} <= set(fns[1].call_targets or [])
+        assert fns[1].nesting_depth >= 2
+        assert "init" in {fn.name for fn in result.functions}
+        archive = next(fn for fn in result.functions if fn.name == "archive")
+        assert archive.params == ["post", "folder"]
+
+    def test_c_structs_typedefs_and_pointer_functions(self):
+        """typedef'd anonymous structs are classes; pointer returns keep their names."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "c" not in get_supported_languages():
+            pytest.skip("C grammar not installed")
+
+        c_code = """
+#include <stdlib.h>
+#include "buffer.h"
+
+typedef struct {
+    char *data;
+    size_t len, cap;
+} Buffer;
+
+struct node {
+    int value;
+    struct node *next;
+};
+
+static char *buffer_take(Buffer *buf, size_t n) {
+    while (buf->len < n) {
+        grow(buf);
+    }
+    return buf->data;
+}
+"""
+        result = TreeSitterNormalizer().parse_file(c_code, "/buffer.c", "c")
+
+        assert result is not None
+        classes = {cls.name: cls for cls in result.classes}
+        assert classes["Buffer"].fields == ["data", "len", "cap"]
+        assert classes["node"].fields == ["value", "next"]
+        fns = {fn.name: fn for fn in result.functions}
+        assert fns["buffer_take"].params == ["buf", "n"]
+        assert "grow" in (fns["buffer_take"].call_targets or [])
+        assert [imp.source for imp in result.imports] == ["stdlib.h", "buffer.h"]
+
+    def test_cpp_methods_bases_and_pure_virtuals(self):
+        """Out-of-line and inline methods are named; pure virtual classes are abstract."""
+        from shannon_insight.scanning.treesitter_parser import get_supported_languages
+
+        if "cpp" not in get_supported_languages():
+            pytest.skip("C++ grammar not installed")
+
+        cpp_code = """
+#include <string>
+
+class Shape {
+public:
+    virtual ~Shape() = default;
+    virtual double area() const = 0;
+};
+
+class Square : public Shape, private util::Noncopyable {
+    double side_;
+public:
+    double area() const override { return side_ * side_; }
+    void resize(double side);
+};
+
+void Square::resize(double side) {
+    side_ = side;
+}
+"""
+        result = TreeSitterNormalizer().parse_file(cpp_code, "/shape.cpp", "cpp")
+
+        assert result is not None
+        classes = {cls.name: cls for cls in result.classes}
+        assert classes["Shape"].is_abstract
+        square = classes["Square"]
+        assert not square.is_abstract
+        assert square.bases == ["Shape", "Noncopyable"]
+        assert square.fields == ["side_"]
+        assert [m.name for m in square.methods] == ["area"]
+        assert {"area", "resize"} <= {fn.name for fn in result.functions}
+
     def test_ruby_parsing(self):
         """Parse Ruby code."""
         from shannon_insight.scanning.treesitter_parser import get_supported_languages
@@ -282,3 +622,58 @@ end
         result = normalizer.parse_file(ruby_code, "/test.rb", "ruby")
 
         assert result is not None
+
+
+class _GoNode:
+    """Enough of tree_sitter.Node for Go callee names."""
+
+    def __init__(self, kind, text="", **fields):
+        self.type = kind
+        self.text = text.encode()
+        self._fields = fields
+        self.named_children = list(fields.values())
+
+    def child_by_field_name(self, name):
+        return self._fields.get(name)
+
+
+class TestGoCallee:
+    """Names of Go calls, whichever way the grammar reads an instantiation."""
+
+    @pytest.mark.parametrize(
+        "function, name",
+        [
+            (_GoNode("identifier", "Map"), "Map"),
+            (
+                _GoNode(
+                    "selector_expression",
+                    operand=_GoNode("identifier", "slices"),
+                    field=_GoNode("field_identifier", "Map"),
+                ),
+                "Map",
+            ),
+            # Map[int](xs) in grammars without type_arguments on calls
+            (
+                _GoNode(
+                    "index_expression",
+                    operand=_GoNode("identifier", "Map"),
+                    index=_GoNode("type_identifier", "int"),
+                ),
+                "Map",
+            ),
+            (
+                _GoNode(
+                    "generic_type",
+                    type=_GoNode(
+                        "qualified_type",
+                        package=_GoNode("package_identifier", "slices"),
+                        name=_GoNode("type_identifier", "Map"),
+                    ),
+                ),
+                "Map",
+            ),
+            (_GoNode("func_literal"), None),
+        ],
+    )
+    def test_go_callee(self, function, name):
+        assert TreeSitterNormalizer()._go_callee(function) == name
@@ -0,0 +1,88 @@
+"""Tests for Jupyter notebook extraction and cell mapping."""
+
+import json
+
+from shannon_insight.scanning.languages import detect_language
+from shannon_insight.scanning.notebook import cell_at, notebook_source
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+
+
+def _notebook(*cells, language="python", nbformat=4):
+    """A notebook from (cell_type, source) pairs."""
+    metadata = {"kernelspec": {"name": language, "language": language}}
+    if nbformat == 3:
+        cells = [{"cell_type": kind, "input": source} for kind, source in cells]
+        return json.dumps({"metadata": metadata, "worksheets": [{"cells": cells}]})
+    cells = [
+        {"cell_type": kind, "source": source, "metadata": {}, "outputs": []}
+        for kind, source in cells
+    ]
+    return json.dumps({"metadata": metadata, "nbformat": 4, "cells": cells})
+
+
+_EDA = _notebook(
+    ("markdown", "# Exploration"),
+    ("code", ["import pandas as pd\n", "%matplotlib inline\n", "df = pd.read_csv('x.csv')"]),
+    ("markdown", "Cleaning"),
+    ("code", "def clean(df):\n    return df.dropna()\n\nfiles = !ls data\ndf.head?"),
+    ("code", "%%bash\nls -la\necho done"),
+    ("code", "%%time\nclean(df)"),
+)
+
+
+class TestNotebookSource:
+    def test_code_cells_under_markers(self):
+        assert notebook_source(_EDA).splitlines() == [
+            "# %% [1]",
+            "import pandas as pd",
+            "# %matplotlib inline",
+            "df = pd.read_csv('x.csv')",
+            "# %% [3]",
+            "def clean(df):",
+            "    return df.dropna()",
+            "",
+            "# files = !ls data",
+            "# df.head?",
+            "# %% [4]",
+            "# %%bash",
+            "# ls -la",
+            "# echo done",
+            "# %% [5]",
+            "# %%time",
+            "clean(df)",
+        ]
+
+    def test_nbformat_3(self):
+        content = _notebook(("markdown", "x"), ("code", ["x = 1\n", "y = 2"]), nbformat=3)
+        assert notebook_source(content) == "# %% [1]\nx = 1\ny = 2\n"
+
+    def test_not_python(self):
+        assert notebook_source(_notebook(("code", "x <- 1"), language="R")) is None
+        assert notebook_source("{not json") is None
+        assert notebook_source(_notebook(("markdown", "notes only"))) == ""
+
+    def test_cell_at(self):
+        source = notebook_source(_EDA)
+        assert cell_at(source, 2) == (1, 1)
+        assert cell_at(source, 7) == (3, 2)
+        assert cell_at(source, 17) == (5, 2)
+        assert cell_at("x = 1\n", 1) is None
+
+
+class TestExtractNotebook:
+    def test_notebook_is_a_python_module(self, tmp_path):
+        (tmp_path / "eda.ipynb").write_text(_EDA)
+        content = {}
+
+        syntax = SyntaxExtractor().extract(tmp_path / "eda.ipynb", tmp_path, content)
+
+        assert detect_language("eda.ipynb") == "python"
+        assert syntax.language == "python"
+        assert content["eda.ipynb"] == notebook_source(_EDA)
+        assert [(f.name, f.cell) for f in syntax.functions] == [("clean", 3)]
+        assert [i.source for i in syntax.imports] == ["pandas"]
+
+    def test_other_kernels_are_skipped(self, tmp_path):
+        (tmp_path / "stats.ipynb").write_text(_notebook(("code", "x <- 1"), language="R"))
+
+        assert SyntaxExtractor().extract(tmp_path / "stats.ipynb", tmp_path) is None
@@ -0,0 +1,114 @@
+"""Tests for PHP namespace resolution and class members."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.php import annotate_php
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+
+_CONTROLLER = """\
+<?php
+
+declare(strict_types=1);
+
+namespace App\\Http\\Controllers;
+
+use App\\Models\\{Post, Comment as Reply};
+use Illuminate\\Http\\Request;
+use function App\\Support\\format_date;
+
+require_once __DIR__ . '/../helpers.php';
+
+final class PostController extends Controller implements \\JsonSerializable
+{
+    use AuthorizesRequests;
+
+    #[Inject]
+    private ?Request $request = null, $cache;
+
+    public function __construct(private readonly PostRepository $posts) {}
+
+    public function show(int $id): Post
+    {
+        // new Draft() in a comment is not a reference
+        $label = "new Preview";
+        try {
+            return Post::findOrFail($id);
+        } catch (\\RuntimeException | NotFound $e) {
+            return new Reply();
+        }
+    }
+}
+"""
+
+_GLOBAL = """\
+<?php
+interface Shape
+{
+    public function area(): float;
+}
+
+abstract class Base implements Shape {}
+?>
+<p>new Widget</p>
+<?php
+$shape = new Circle();
+"""
+
+
+def _annotated(content, path="app/Http/Controllers/PostController.php"):
+    syntax = RegexFallbackScanner().parse(content, path, "php")
+    annotate_php(syntax, content)
+    return syntax
+
+
+class TestImports:
+    def test_class_names_are_fully_qualified(self):
+        syntax = _annotated(_CONTROLLER)
+
+        assert syntax.import_sources == [
+            "App\\Models\\Post",
+            "App\\Models\\Comment",
+            "Illuminate\\Http\\Request",
+            "App\\Http\\Controllers\\Controller",
+            "JsonSerializable",
+            "App\\Http\\Controllers\\AuthorizesRequests",
+            "App\\Http\\Controllers\\PostRepository",
+            "App\\Http\\Controllers\\NotFound",
+            "RuntimeException",
+            "./../helpers.php",
+        ]
+
+    def test_global_namespace_skips_own_classes_and_html(self):
+        syntax = _annotated(_GLOBAL, path="shapes.php")
+
+        # Widget sits in inline HTML; Shape and Base are declared here
+        assert syntax.import_sources == ["Circle"]
+
+
+class TestClasses:
+    def test_members_bases_and_traits(self):
+        (controller,) = _annotated(_CONTROLLER).classes
+
+        assert controller.bases == ["Controller", "JsonSerializable"]
+        assert controller.fields == ["request", "cache", "posts"]
+        assert [fn.name for fn in controller.methods] == ["__construct", "show"]
+        assert not controller.is_abstract
+
+    def test_interfaces_and_abstract_classes(self):
+        classes = {cls.name: cls for cls in _annotated(_GLOBAL, path="shapes.php").classes}
+
+        assert classes["Shape"].is_abstract
+        assert [fn.name for fn in classes["Shape"].methods] == ["area"]
+        assert classes["Base"].is_abstract
+        assert classes["Base"].bases == ["Shape"]
+
+
+class TestSyntaxExtractor:
+    def test_php_files_are_annotated(self, tmp_path):
+        (tmp_path / "PostController.php").write_text(_CONTROLLER)
+
+        result = SyntaxExtractor().extract(tmp_path / "PostController.php", tmp_path)
+
+        assert result is not None
+        assert "App\\Models\\Post" in result.import_sources
+        (controller,) = result.classes
+        assert "posts" in controller.fields
@@ -0,0 +1,132 @@
+"""Tests for C/C++ preprocessor conditional resolution."""
+
+import pytest
+
+from shannon_insight.scanning.preprocessor import ConditionalResolver, evaluate, parse_defines
+
+PLATFORM_CODE = """\
+#include <stdio.h>
+#ifdef _WIN32
+int open_port(void) { return win_open(); }
+#elif defined(__linux__) && LINUX_VERSION >= 5
+int open_port(void) { return linux_open(); }
+#else
+int open_port(void) { return posix_open(); }
+#endif
+int close_port(void);
+"""
+
+
+def _kept(content: str) -> list[str]:
+    return [line for line in content.split("\n") if line.strip()]
+
+
+class TestParseDefines:
+    def test_names_default_to_one(self):
+        assert parse_defines(["DEBUG", "LEVEL=2", "EMPTY="]) == {
+            "DEBUG": "1",
+            "LEVEL": "2",
+            "EMPTY": "",
+        }
+
+    def test_rejects_invalid_names(self):
+        with pytest.raises(ValueError, match="invalid macro definition"):
+            parse_defines(["2FAST"])
+
+
+class TestEvaluate:
+    def test_arithmetic_and_logic(self):
+        macros = {"B": "2"}
+        assert evaluate("!defined(A) && (B << 2) == 8", macros) == 1
+        assert evaluate("defined B || 0x10 > 20", macros) == 1
+        assert evaluate("B * 3 - 7", macros) == -1
+
+    def test_undefined_identifiers_are_zero(self):
+        assert evaluate("UNKNOWN + 1", {}) == 1
+
+    def test_macros_expand_to_other_macros(self):
+        assert evaluate("VERSION >= 3", {"VERSION": "MAJOR", "MAJOR": "3L"}) == 1
+
+    def test_unsupported_expressions(self):
+        assert evaluate("F(1)", {"F": "?"}) is None
+        assert evaluate("A ? 1 : 2", {}) is None
+        assert evaluate("1 / 0", {}) is None
+        assert evaluate("09", {}) is None
+
+
+class TestConditionalResolver:
+    def test_else_branch_by_default(self):
+        resolved = ConditionalResolver().resolve(PLATFORM_CODE)
+
+        assert _kept(resolved) == [
+            "#include <stdio.h>",
+            "int open_port(void) { return posix_open(); }",
+            "int close_port(void);",
+        ]
+
+    def test_line_numbers_are_preserved(self):
+        resolved = ConditionalResolver(["_WIN32"]).resolve(PLATFORM_CODE)
+
+        lines = resolved.split("\n")
+        assert len(lines) == len(PLATFORM_CODE.split("\n"))
+        assert lines[2] == "int open_port(void) { return win_open(); }"
+        assert lines[8] == "int close_port(void);"
+
+    def test_elif_with_values(self):
+        resolver = ConditionalResolver(["__linux__", "LINUX_VERSION=6"])
+
+        assert "linux_open" in resolver.resolve(PLATFORM_CODE)
+        assert "posix_open" not in resolver.resolve(PLATFORM_CODE)
+
+    def test_cplusplus_guard(self):
+        code = '#ifdef __cplusplus\nextern "C" {\n#endif\nvoid f(void);\n'
+
+        assert 'extern "C"' not in ConditionalResolver().resolve(code)
+        assert 'extern "C"' in ConditionalResolver().resolve(code, cplusplus=True)
+
+    def test_defines_and_undefs_in_file(self):
+        code = """\
+#define USE_CACHE
+#if defined(USE_CACHE)
+int cached;
+#endif
+#undef USE_CACHE
+#ifndef USE_CACHE
+int uncached;
+#endif
+"""
+        assert _kept(ConditionalResolver().resolve(code)) == [
+            "#define USE_CACHE",
+            "int cached;",
+            "#undef USE_CACHE",
+            "int uncached;",
+        ]
+
+    def test_nested_blocks_inside_untaken_branch(self):
+        code = """\
+#if 0
+#ifdef A
+int a;
+#else
+int not_a;
+#endif
+#else
+int b;
+#endif
+"""
+        assert _kept(ConditionalResolver(["A"]).resolve(code)) == ["int b;"]
+
+    def test_continued_directive_lines(self):
+        code = "#if defined(A) && \\\n    defined(B)\nint ab;\n#endif\nint rest;\n"
+
+        resolved = ConditionalResolver(["A"]).resolve(code)
+
+        assert _kept(resolved) == ["int rest;"]
+        assert resolved.split("\n")[4] == "int rest;"
+
+    def test_unevaluable_condition_takes_first_branch(self):
+        code = "#if HAS_FEATURE(x)\nint first;\n#else\nint second;\n#endif\n"
+
+        resolved = ConditionalResolver().resolve(code)
+
+        assert _kept(resolved) == ["int first;"]
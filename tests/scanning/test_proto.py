@@ -0,0 +1,156 @@
+"""Tests for .proto parsing and protoc stub linking."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.languages import detect_language
+from shannon_insight.scanning.proto import (
+    annotate_proto,
+    catalog_protos,
+    link_stubs,
+    parse_proto,
+    stub_stem,
+)
+
+_USER = """\
+syntax = "proto3";
+
+// The user service { not a block
+package acme.user.v1;
+
+import "google/protobuf/timestamp.proto";
+import public "acme/common/v1/page.proto";
+
+option go_package = "github.com/acme/api/user/v1;userv1";
+
+message User {
+  string id = 1;
+  repeated string emails = 2 [deprecated = true];
+  map<string, string> labels = 3;
+  oneof contact {
+    string phone = 4;
+    Address address = 5;
+  }
+  reserved 6, 7;
+
+  message Address {
+    string city = 1;
+  }
+}
+
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+  ROLE_ADMIN = 1;
+}
+
+/* Lookups and
+   change feeds */
+service UserService {
+  option (acme.auth) = "user";
+  rpc GetUser(GetUserRequest) returns (User);
+  rpc Watch(stream .acme.user.v1.WatchRequest) returns (stream User) {
+    option (google.api.http) = { get: "/v1/users:watch" };
+  }
+}
+"""
+
+
+def test_parse_definitions():
+    proto = parse_proto(_USER)
+
+    assert proto.package == "acme.user.v1"
+    assert proto.imports == ["google/protobuf/timestamp.proto", "acme/common/v1/page.proto"]
+    assert proto.options == {"go_package": "github.com/acme/api/user/v1;userv1"}
+    assert [(m.name, m.kind, m.fields) for m in proto.messages] == [
+        ("User", "message", ["id", "emails", "labels", "phone", "address"]),
+        ("User.Address", "message", ["city"]),
+        ("Role", "enum", ["ROLE_UNSPECIFIED", "ROLE_ADMIN"]),
+    ]
+    (service,) = proto.services
+    assert (service.name, service.start_line, service.end_line) == ("UserService", 33, 39)
+    get, watch = service.rpcs
+    assert (get.name, get.request, get.response, get.start_line) == (
+        "GetUser",
+        "GetUserRequest",
+        "User",
+        35,
+    )
+    assert not get.client_streaming and not get.server_streaming
+    assert (watch.request, watch.client_streaming, watch.server_streaming) == (
+        "acme.user.v1.WatchRequest",
+        True,
+        True,
+    )
+    assert (watch.start_line, watch.end_line) == (36, 38)
+
+
+def test_annotate_replaces_fallback_results():
+    assert detect_language("api/user.proto") == "proto"
+    syntax = RegexFallbackScanner().parse(_USER, "api/user.proto", "proto")
+
+    annotate_proto(syntax, _USER)
+
+    assert [(f.name, f.params) for f in syntax.functions] == [
+        ("UserService.GetUser", ["GetUserRequest"]),
+        ("UserService.Watch", ["acme.user.v1.WatchRequest"]),
+    ]
+    service = syntax.classes[0]
+    assert service.name == "UserService" and service.is_abstract
+    assert [m.name for m in service.methods] == ["UserService.GetUser", "UserService.Watch"]
+    assert [c.name for c in syntax.classes[1:]] == ["User", "User.Address", "Role"]
+    assert [i.source for i in syntax.imports] == [
+        "google/protobuf/timestamp.proto",
+        "acme/common/v1/page.proto",
+    ]
+
+
+def test_stub_stem():
+    assert stub_stem("gen/user_grpc.pb.go") == "user"
+    assert stub_stem("gen/user.pb.gw.go") == "user"
+    assert stub_stem("clients/user_pb2_grpc.py") == "user"
+    assert stub_stem("clients/user_pb2.pyi") == "user"
+    assert stub_stem("web/user-admin_pb.d.ts") == "user_admin"
+    assert stub_stem("src/user.py") is None
+    assert stub_stem("gen/.pb.go") is None
+
+
+def test_link_stubs_prefers_the_closest_directory():
+    links = link_stubs(
+        [
+            "api/v1/user.proto",
+            "api/v2/user.proto",
+            "proto/user-admin.proto",
+            "gen/go/api/v2/user.pb.go",
+            "gen/go/api/v1/user_grpc.pb.go",
+            "clients/user_admin_pb2.py",
+            "clients/order_pb2.py",
+            "src/user.go",
+        ]
+    )
+
+    assert links == {
+        "gen/go/api/v2/user.pb.go": "api/v2/user.proto",
+        "gen/go/api/v1/user_grpc.pb.go": "api/v1/user.proto",
+        "clients/user_admin_pb2.py": "proto/user-admin.proto",
+    }
+
+
+def test_catalog():
+    scanner = RegexFallbackScanner()
+    contents = {
+        "api/user.proto": _USER,
+        "gen/user.pb.go": "package userv1\n",
+        "gen/user_grpc.pb.go": "package userv1\n",
+    }
+    files = {
+        path: scanner.parse(text, path, detect_language(path)) for path, text in contents.items()
+    }
+
+    catalog = catalog_protos(files, contents)
+
+    assert list(catalog.files) == ["api/user.proto"]
+    assert catalog.stubs == {"api/user.proto": ["gen/user.pb.go", "gen/user_grpc.pb.go"]}
+    (entry,) = catalog.to_dict()["files"]
+    assert entry["package"] == "acme.user.v1"
+    assert entry["messages"] == ["User", "User.Address"]
+    assert entry["enums"] == ["Role"]
+    assert [r["name"] for r in entry["services"][0]["rpcs"]] == ["GetUser", "Watch"]
+    assert entry["stubs"] == ["gen/user.pb.go", "gen/user_grpc.pb.go"]
@@ -0,0 +1,140 @@
+"""Tests for Ruby class structure and Rails DSL recognition."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.rails import annotate_ruby
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+
+_MODEL = """\
+class Post < ApplicationRecord
+  include Searchable
+
+  belongs_to :author, class_name: "User"
+  has_many :comments, dependent: :destroy
+  has_many :categories, through: :taggings
+  has_one :cover_image
+  belongs_to :subject, polymorphic: true
+
+  before_save :normalize_title, if: :title_changed?
+  validate :publishable
+
+  def self.recent
+    order(created_at: :desc)
+  end
+
+  def title_changed?
+    true
+  end
+
+  private
+
+  def normalize_title
+    self.title = title.strip
+  end
+
+  def publishable
+    errors.add(:base, "empty") if body.blank?
+  end
+end
+"""
+
+_CONTROLLER = """\
+module Admin
+  class PostsController < ApplicationController
+    before_action :set_post, only: [:show, :update]
+
+    def index
+      @posts = Post.all
+    end
+
+    def show; end
+
+    def update
+      if @post.update(post_params)
+        redirect_to @post
+      end
+    end
+
+    class << self
+      def controller_path
+        "admin/posts"
+      end
+    end
+
+    private
+
+    def set_post
+      @post = Post.find(params[:id])
+    end
+
+    def post_params
+      params.require(:post).permit(:title)
+    end
+  end
+end
+"""
+
+
+def _annotated(content, path="app/models/post.rb"):
+    syntax = RegexFallbackScanner().parse(content, path, "ruby")
+    annotate_ruby(syntax, content)
+    return syntax
+
+
+def _methods(cls):
+    return {fn.name: fn.decorators for fn in cls.methods}
+
+
+class TestModels:
+    def test_associations_become_fields_and_imports(self):
+        syntax = _annotated(_MODEL)
+
+        (post,) = syntax.classes
+        assert post.bases == ["ApplicationRecord"]
+        assert post.fields == ["author", "comments", "categories", "cover_image", "subject"]
+        imports = {imp.source: imp.names for imp in syntax.imports}
+        assert imports == {
+            "ApplicationRecord": [],
+            "Searchable": [],
+            "User": ["author"],
+            "Comment": ["comments"],
+            "Category": ["categories"],
+            "CoverImage": ["cover_image"],
+        }
+
+    def test_callbacks_decorate_referenced_methods(self):
+        (post,) = _annotated(_MODEL).classes
+
+        methods = _methods(post)
+        assert methods["normalize_title"] == ["before_save"]
+        assert methods["title_changed?"] == ["before_save"]
+        assert methods["publishable"] == ["validate"]
+        assert methods["recent"] == []
+
+
+class TestControllers:
+    def test_public_instance_methods_are_actions(self):
+        syntax = _annotated(_CONTROLLER, "app/controllers/admin/posts_controller.rb")
+
+        (controller,) = syntax.classes
+        assert controller.name == "PostsController"
+        methods = _methods(controller)
+        assert methods["index"] == ["action"]
+        assert methods["show"] == ["action"]
+        assert methods["update"] == ["action"]
+        # Callbacks are not actions; only: lists actions, not callbacks
+        assert methods["set_post"] == ["before_action"]
+        assert methods["post_params"] == []
+        # Singleton methods belong to class << self, not the controller
+        assert "controller_path" not in methods
+
+
+class TestSyntaxExtractor:
+    def test_ruby_files_are_annotated(self, tmp_path):
+        (tmp_path / "post.rb").write_text(_MODEL)
+
+        result = SyntaxExtractor().extract(tmp_path / "post.rb", tmp_path)
+
+        assert result is not None
+        assert "Comment" in result.import_sources
+        (post,) = result.classes
+        assert "comments" in post.fields
@@ -0,0 +1,134 @@
+"""Tests for parsing around syntax errors."""
+
+from shannon_insight.insights.diagnostics import DiagnosticReport, _check_parse_errors
+from shannon_insight.insights.store import AnalysisStore
+from shannon_insight.scanning.recovery import (
+    blank_rows,
+    describe,
+    error_lines,
+    parse_with_recovery,
+)
+from shannon_insight.scanning.syntax import ErrorSpan, FileSyntax
+
+
+class _Node:
+    """Enough of tree_sitter.Node for the recovery walk."""
+
+    def __init__(self, type, start_row, end_row, children=(), missing=False):
+        self.type = type
+        self.start_point = (start_row, 0)
+        self.end_point = (end_row, 0)
+        self.children = list(children)
+        self.is_missing = missing
+        self.has_error = type == "ERROR" or missing or any(c.has_error for c in self.children)
+
+
+class _Tree:
+    def __init__(self, root):
+        self.root_node = root
+
+
+def _parse(code):
+    """A line-per-statement language.
+
+    A line with an unclosed "(" is an error that swallows the lines after
+    it up to the next blank line; a line ending in "," gets a MISSING ";".
+    """
+    lines = code.decode().split("\n")
+    statements = []
+    row = 0
+    while row < len(lines):
+        line = lines[row]
+        if line.count("(") > line.count(")"):
+            end = row
+            while end + 1 < len(lines) and lines[end + 1].strip():
+                end += 1
+            statements.append(_Node("ERROR", row, end))
+            row = end + 1
+            continue
+        if line.rstrip().endswith(","):
+            statements.append(_Node("statement", row, row, [_Node(";", row, row, missing=True)]))
+        elif line.strip():
+            statements.append(_Node("statement", row, row))
+        row += 1
+    return _Tree(_Node("module", 0, len(lines) - 1, statements))
+
+
+def _statements(tree):
+    return [node.start_point[0] for node in tree.root_node.children if node.type == "statement"]
+
+
+class TestParseWithRecovery:
+    def test_clean_code_is_parsed_once(self):
+        code = b"a = 1\nb = 2\n"
+
+        tree, parsed, spans = parse_with_recovery(_parse, code)
+
+        assert _statements(tree) == [0, 1]
+        assert parsed is code
+        assert spans == []
+
+    def test_blanks_only_the_broken_line(self):
+        code = b"a = 1\nb = f(1\nc = 2\nd = 3\n\ne = 4\n"
+
+        tree, parsed, spans = parse_with_recovery(_parse, code)
+
+        assert _statements(tree) == [0, 2, 3, 5]
+        assert spans == [ErrorSpan(2, 2)]
+        assert len(parsed) == len(code)
+        assert parsed.split(b"\n")[1] == b" " * len("b = f(1")
+
+    def test_consecutive_broken_lines_merge(self):
+        code = b"a = (\nb = (\nc = 2\n"
+
+        tree, _, spans = parse_with_recovery(_parse, code)
+
+        assert _statements(tree) == [2]
+        assert spans == [ErrorSpan(1, 2)]
+        assert error_lines(spans) == 2
+
+    def test_missing_tokens_are_reported_but_kept(self):
+        code = b"a = 1,\nb = 2\n"
+
+        tree, parsed, spans = parse_with_recovery(_parse, code)
+
+        assert _statements(tree) == [0, 1]
+        assert parsed == code
+        assert spans == [ErrorSpan(1, 1, missing=";")]
+        assert error_lines(spans) == 0
+
+    def test_failed_parse(self):
+        assert parse_with_recovery(lambda code: None, b"a = (\n") == (None, b"a = (\n", [])
+
+
+def test_blank_rows_keeps_offsets():
+    code = b"one\ntwo\nthree"
+
+    assert blank_rows(code, {1, 2, 7}) == b"one\n   \n     "
+    assert blank_rows(code, set()) is code
+
+
+def test_describe():
+    spans = [ErrorSpan(4, 5), ErrorSpan(9, 9), ErrorSpan(12, 12, missing="}")]
+
+    assert describe(spans) == 'lines 4-5, line 9, line 12 (missing "}")'
+
+
+def test_diagnostics_report_files_with_syntax_errors():
+    store = AnalysisStore()
+    store.file_syntax.set(
+        {
+            "a.py": FileSyntax(
+                "a.py", [], [], [], "python", parse_errors=[ErrorSpan(3, 4), ErrorSpan(9, 9)]
+            ),
+            "b.py": FileSyntax("b.py", [], [], [], "python"),
+        },
+        produced_by="test",
+    )
+    report = DiagnosticReport()
+
+    _check_parse_errors(store, report)
+
+    (issue,) = report.issues
+    assert issue.message == "Syntax errors in 1 files: 3 lines left out of their metrics"
+    assert issue.detail == "a.py: lines 3-4, line 9"
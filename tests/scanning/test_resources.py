@@ -0,0 +1,127 @@
+"""Tests for Android and iOS resource declarations and references."""
+
+import plistlib
+
+from shannon_insight.scanning.resources import (
+    android_dynamic_names,
+    android_file_resource,
+    android_references,
+    asset_member,
+    asset_set,
+    interface_builder_references,
+    interface_builder_scenes,
+    ios_code_references,
+    ios_dynamic_lookup,
+    parse_android_values,
+    parse_strings_file,
+    plist_values,
+)
+
+_VALUES = """\
+<resources>
+    <string name="terms"><![CDATA[Read the <a href="x">terms</a>]]></string>
+    <string name="quoted">"Don\\'t  stop"</string>
+    <string name="empty" />
+    <string-array name="planets">
+        <item>@string/terms</item>
+    </string-array>
+    <style name="Theme.App.Dark" parent="Theme.App" />
+    <declare-styleable name="Chart"><attr name="lineColor" format="color" /></declare-styleable>
+</resources>
+"""
+
+
+def test_android_values():
+    resources = parse_android_values("res/values/strings.xml", _VALUES)
+
+    assert [(r.type, r.name, r.line, r.value) for r in resources] == [
+        ("string", "terms", 2, "Read the terms"),
+        ("string", "quoted", 3, "Don\\'t stop"),
+        ("string", "empty", 4, None),
+        ("array", "planets", 5, None),
+        ("style", "Theme_App_Dark", 8, None),
+    ]
+    assert all(r.default_locale for r in resources)
+    assert not parse_android_values("res/values-fr/strings.xml", _VALUES)[0].default_locale
+
+
+def test_android_file_resources():
+    logo = android_file_resource("app/res/drawable-xxhdpi/ic_logo.9.png")
+
+    assert (logo.type, logo.name, logo.default_locale) == ("drawable", "ic_logo", False)
+    assert android_file_resource("app/res/values/strings.xml") is None
+    assert android_file_resource("app/src/drawable/logo.png") is None
+
+
+def test_android_references():
+    code = """\
+        setContentView(R.layout.main)
+        val ok = android.R.string.ok
+        val binding = FragmentHomeBinding.inflate(inflater)
+        val icon = resources.getIdentifier("ic_" + name, "drawable", packageName)
+        val flag = resources.getIdentifier("flag_fr", "drawable", packageName)
+    """
+    xml = (
+        '<View android:background="@drawable/bg" android:id="@+id/row" '
+        'style="@style/Theme.App" android:textColor="@android:color/white" />'
+    )
+
+    assert android_references(code) == {("layout", "main"), ("layout", "fragment_home")}
+    assert android_references(xml) == {("drawable", "bg"), ("style", "Theme_App")}
+    assert android_dynamic_names(code) == ({"flag_fr"}, True)
+    assert android_dynamic_names("R.string.x") == (set(), False)
+
+
+def test_strings_table():
+    content = """\
+/* "commented" = "out"; */
+"greeting" = "Hello, \\"%@\\"";
+// "also" = "out";
+"farewell"="Bye";
+"""
+    entries = parse_strings_file("App/fr.lproj/Localizable.strings", content)
+
+    assert [(e.name, e.value, e.line) for e in entries] == [
+        ("greeting", 'Hello, \\"%@\\"', 2),
+        ("farewell", "Bye", 4),
+    ]
+    assert not entries[0].default_locale
+    assert parse_strings_file("App/Base.lproj/Localizable.strings", content)[0].default_locale
+
+
+def test_asset_catalogs():
+    image = asset_set("App/Assets.xcassets/Icons/hero-banner.imageset/Contents.json")
+
+    assert (image.type, image.name) == ("image", "hero-banner")
+    assert asset_set("App/Assets.xcassets/Contents.json") is None
+    assert asset_set("App/Assets.xcassets/AppIcon.appiconset/Contents.json") is None
+    assert asset_set("App/Data/x.imageset/Contents.json") is None
+    assert asset_member("hero-banner") == "heroBanner"
+    assert asset_member("Hero Banner 2") == "heroBanner2"
+
+
+def test_interface_builder():
+    storyboard = """\
+<scenes><scene sceneID="a"><imageView image="header"/></scene><scene sceneID="b"/></scenes>
+<resources><image name="header" width="1"/><namedColor name="Brand"/></resources>
+"""
+
+    assert interface_builder_scenes(storyboard) == 2
+    assert interface_builder_references(storyboard) == {"header", "Brand"}
+
+
+def test_ios_code():
+    swift = 'let a = UIImage(named: "logo"); let b = Color(.brandBlue)'
+    objc = '[UIImage imageNamed:@"legacy"]; [UIImage imageNamed:name];'
+
+    assert {"logo", "brandBlue"} <= ios_code_references(swift)
+    assert "legacy" in ios_code_references(objc)
+    assert not ios_dynamic_lookup(swift)
+    assert ios_dynamic_lookup(objc)
+
+
+def test_plist_values():
+    data = plistlib.dumps({"UILaunchScreen": {"UIImageName": "splash"}, "Tags": ["a", 1]})
+
+    assert plist_values(data) == (3, {"splash", "a"})
+    assert plist_values(b"not a plist") == (0, set())
@@ -0,0 +1,131 @@
+"""Tests for Scala import expansion and class members."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.scala import annotate_scala
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+
+_REPO = """\
+package com.acme.repo
+
+import scala.concurrent.{ExecutionContext, Future}
+import com.acme.model.{User, Order => Purchase, Secret => _, _}
+import _root_.cats.effect.IO
+import com.acme.util.Strings.slug, java.time.Instant
+// import com.acme.legacy.Dao
+
+trait Repository[A] {
+  def find(id: Long): Future[Option[A]]
+}
+
+case class UserRepo(db: Database, label: String = "import x.Y")(implicit ec: ExecutionContext)
+    extends Base(db.name) with Repository[User] {
+  private val cache = Map.empty[Long, User]
+  var hits = 0
+
+  def find(id: Long): Future[Option[User]] = {
+    def key = id.toString
+    Future(cache.get(id))
+  }
+}
+
+object UserRepo {
+  def apply(db: Database): UserRepo = new UserRepo(db)(ExecutionContext.global)
+}
+"""
+
+_SCALA3 = """\
+package com.acme
+
+import com.acme.model.{User as Member, *}
+import com.acme.model.given
+
+enum Color:
+  case Red, Green
+
+class Service(val repo: Repo) extends Runner:
+  def run(): Unit =
+    repo.load()
+
+  def stop(): Unit = ()
+
+def helper(x: Int): Int = x
+"""
+
+
+def _annotated(content, path="src/main/scala/com/acme/repo/UserRepo.scala"):
+    syntax = RegexFallbackScanner().parse(content, path, "scala")
+    annotate_scala(syntax, content)
+    return syntax
+
+
+class TestImports:
+    def test_selectors_are_expanded(self):
+        syntax = _annotated(_REPO)
+
+        assert syntax.import_sources == [
+            "scala.concurrent.ExecutionContext",
+            "scala.concurrent.Future",
+            "com.acme.model.User",
+            "com.acme.model.Order",
+            "com.acme.model._",
+            "cats.effect.IO",
+            "com.acme.util.Strings.slug",
+            "java.time.Instant",
+        ]
+
+    def test_renames_keep_the_alias(self):
+        names = {imp.source: imp.names for imp in _annotated(_REPO).imports}
+
+        assert names["com.acme.model.Order"] == ["Purchase"]
+        assert names["com.acme.model._"] == []
+
+    def test_scala3_as_and_wildcards(self):
+        syntax = _annotated(_SCALA3, path="src/main/scala/com/acme/Service.scala")
+
+        assert syntax.import_sources == ["com.acme.model.User", "com.acme.model._"]
+        assert syntax.imports[0].names == ["Member"]
+
+
+class TestClasses:
+    def test_members_fields_and_bases(self):
+        # The case class, not its companion object
+        repo = next(cls for cls in _annotated(_REPO).classes if cls.name == "UserRepo")
+        assert repo.bases == ["Base", "Repository"]
+        # Every first-list parameter of a case class is a field; implicits are not
+        assert repo.fields == ["db", "label", "cache", "hits"]
+        # The local def key belongs to find, not the class
+        assert [fn.name for fn in repo.methods] == ["find"]
+        assert not repo.is_abstract
+
+    def test_traits_are_abstract(self):
+        classes = {cls.name: cls for cls in _annotated(_REPO).classes}
+
+        assert classes["Repository"].is_abstract
+        assert [fn.name for fn in classes["Repository"].methods] == ["find"]
+
+    def test_companion_object_gets_its_own_methods(self):
+        objects = [cls for cls in _annotated(_REPO).classes if cls.name == "UserRepo"]
+
+        assert [[fn.name for fn in cls.methods] for cls in objects] == [["find"], ["apply"]]
+
+    def test_scala3_indented_bodies(self):
+        syntax = _annotated(_SCALA3, path="src/main/scala/com/acme/Service.scala")
+        classes = {cls.name: cls for cls in syntax.classes}
+
+        service = classes["Service"]
+        assert service.bases == ["Runner"]
+        assert service.fields == ["repo"]
+        # helper is a top-level def after the indented body ends
+        assert [fn.name for fn in service.methods] == ["run", "stop"]
+
+
+class TestSyntaxExtractor:
+    def test_scala_files_are_annotated(self, tmp_path):
+        (tmp_path / "UserRepo.scala").write_text(_REPO)
+
+        result = SyntaxExtractor().extract(tmp_path / "UserRepo.scala", tmp_path)
+
+        assert result is not None
+        assert "com.acme.model.User" in result.import_sources
+        repo = next(cls for cls in result.classes if cls.name == "UserRepo")
+        assert "cache" in repo.fields
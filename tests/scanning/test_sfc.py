@@ -0,0 +1,188 @@
+"""Tests for Vue and Svelte single-file components."""
+
+from shannon_insight.scanning.languages import detect_language
+from shannon_insight.scanning.sfc import (
+    TEMPLATE_FUNCTION,
+    annotate_component,
+    script_language,
+    script_source,
+    split_component,
+    template_depth,
+    template_names,
+)
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+
+_CART = """\
+<template>
+  <div class="cart">
+    <template v-if="items.length">
+      <ul>
+        <li v-for="item in items" :key="item.id">
+          {{ formatPrice(item.price) }}
+          <input v-model="item.qty" />
+        </li>
+      </ul>
+    </template>
+    <button @click="checkout">Pay {{ total() }}</button>
+    <CartFooter />
+  </div>
+</template>
+
+<script setup lang="ts">
+import { ref } from "vue";
+import CartFooter from "./CartFooter.vue";
+
+const items = ref([]);
+
+function formatPrice(value: number): string {
+  return `$${value.toFixed(2)}`;
+}
+
+function total(): number {
+  return items.value.reduce((sum, item) => sum + item.price * item.qty, 0);
+}
+
+function checkout() {
+  console.log("checkout", total());
+}
+</script>
+
+<style scoped>
+.cart { color: red; }
+</style>
+"""
+
+_COUNTER = """\
+<script context="module">
+  export const prerender = true;
+</script>
+
+<script>
+  import Item from "./Item.svelte";
+  let count = 0;
+  function increment() {
+    count += 1;
+  }
+</script>
+
+<h1>Count {count}</h1>
+{#if count > 2}
+  <div>
+    {#each [1, 2] as n}
+      <Item value={n} />
+    {/each}
+  </div>
+{/if}
+<button on:click={increment}>+</button>
+
+<style>
+  h1 { color: red; }
+</style>
+"""
+
+
+def _blocks(blocks):
+    return [(b.kind, b.start_line, b.end_line) for b in blocks]
+
+
+class TestSplitComponent:
+    def test_vue_blocks(self):
+        blocks = split_component(_CART)
+
+        assert _blocks(blocks) == [("template", 2, 13), ("script", 17, 32), ("style", 36, 36)]
+        assert blocks[1].attrs == {"setup": "", "lang": "ts"}
+        assert script_language(blocks) == "typescript"
+
+    def test_svelte_markup_is_the_template(self):
+        blocks = split_component(_COUNTER, svelte=True)
+
+        assert _blocks(blocks) == [
+            ("script", 2, 2),
+            ("script", 6, 10),
+            ("template", 13, 21),
+            ("style", 24, 24),
+        ]
+        assert blocks[0].attrs == {"context": "module"}
+        assert script_language(blocks) == "javascript"
+
+    def test_script_source_keeps_line_numbers(self):
+        source = script_source(_COUNTER, split_component(_COUNTER, svelte=True)).splitlines()
+
+        assert len(source) == len(_COUNTER.splitlines())
+        assert source[:3] == ["", "export const prerender = true;", ""]
+        assert source[7:10] == ["function increment() {", "  count += 1;", "}"]
+        assert not any(source[12:])
+
+
+class TestTemplate:
+    def test_depth(self):
+        (template,) = [b for b in split_component(_CART) if b.kind == "template"]
+
+        # div > template > ul > li; input and CartFooter close themselves
+        assert template_depth(template.text) == 4
+        assert template_depth("{#if a}\n  <p>{#each b as c}<i>{c}</i>{/each}</p>\n{/if}") == 4
+
+    def test_names(self):
+        assert template_names('<b :class="{ on: isOn(x) }" @click="save">{{ n + "m" }}</b>') == [
+            "n",
+            "on",
+            "isOn",
+            "x",
+            "save",
+        ]
+        svelte = "{#each rows as row}<i on:click={() => pick(row)} />{/each}"
+        assert template_names(svelte, svelte=True) == ["rows", "as", "row", "pick"]
+
+    def test_template_calls_script_functions(self):
+        functions = [
+            FunctionDef(name, [], 10, 2, 0, line, line + 2, call_targets=[])
+            for name, line in (("formatPrice", 22), ("total", 26), ("checkout", 30))
+        ]
+        syntax = FileSyntax("Cart.vue", functions, [], [], "typescript")
+
+        annotate_component(syntax, split_component(_CART))
+
+        template = syntax.functions[-1]
+        assert (template.name, template.start_line, template.end_line) == (
+            TEMPLATE_FUNCTION,
+            2,
+            13,
+        )
+        assert template.nesting_depth == 4
+        assert template.call_targets == ["formatPrice", "total", "checkout"]
+
+
+class TestExtractComponent:
+    def test_vue(self, tmp_path):
+        (tmp_path / "Cart.vue").write_text(_CART)
+        content = {}
+
+        syntax = SyntaxExtractor().extract(tmp_path / "Cart.vue", tmp_path, content)
+
+        assert detect_language("Cart.vue") == "javascript"
+        assert syntax.language == "typescript"
+        assert content["Cart.vue"] == _CART
+        assert [f.name for f in syntax.functions] == [
+            "formatPrice",
+            "total",
+            "checkout",
+            TEMPLATE_FUNCTION,
+        ]
+        assert [i.source for i in syntax.imports] == ["vue", "./CartFooter.vue"]
+
+    def test_svelte(self, tmp_path):
+        (tmp_path / "Counter.svelte").write_text(_COUNTER)
+
+        syntax = SyntaxExtractor().extract(tmp_path / "Counter.svelte", tmp_path)
+
+        assert [f.name for f in syntax.functions] == ["increment", TEMPLATE_FUNCTION]
+        assert [i.source for i in syntax.imports] == ["./Item.svelte"]
+
+    def test_script_src_is_an_import(self, tmp_path):
+        content = '<template><form /></template>\n<script src="./form.js" />\n'
+        (tmp_path / "Form.vue").write_text(content)
+
+        syntax = SyntaxExtractor().extract(tmp_path / "Form.vue", tmp_path)
+
+        assert [i.source for i in syntax.imports] == ["./form.js"]
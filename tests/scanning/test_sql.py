@@ -0,0 +1,164 @@
+"""Tests for SQL statement splitting, complexity and table references."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.sql import (
+    annotate_sql,
+    split_statements,
+    statement_complexity,
+    table_name,
+)
+
+_MIGRATION = """\
+-- Migration 002: orders
+CREATE TABLE IF NOT EXISTS public.orders (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    "total" NUMERIC(10, 2) DEFAULT 0,
+    status TEXT CHECK (status IN ('new', 'paid')),
+    CONSTRAINT orders_total_positive CHECK (total >= 0)
+);
+
+CREATE INDEX orders_user_idx ON orders (user_id);
+
+ALTER TABLE users ADD COLUMN last_order_at TIMESTAMPTZ;
+
+BEGIN;
+INSERT INTO audit_log (msg) VALUES ('orders; created');
+COMMIT;
+
+CREATE OR REPLACE FUNCTION refresh_totals(p_user BIGINT, p_since TIMESTAMPTZ DEFAULT now())
+RETURNS void AS $$
+DECLARE
+    t NUMERIC;
+BEGIN
+    WITH recent AS (
+        SELECT o.total FROM orders o JOIN users u ON u.id = o.user_id
+        WHERE o.created_at > p_since AND u.id = p_user
+    )
+    SELECT sum(total) INTO t FROM recent;
+    IF t IS NULL THEN
+        t := 0;
+    ELSIF t > 1000 THEN
+        UPDATE users SET vip = true WHERE id = p_user;
+    END IF;
+    FOR r IN SELECT * FROM generate_series(1, 3) LOOP
+        PERFORM 1;
+    END LOOP;
+    SELECT EXTRACT(YEAR FROM created_at) FROM order_items i, products p WHERE i.id = p.id;
+END;
+$$ LANGUAGE plpgsql;
+
+DELIMITER //
+CREATE PROCEDURE archive_orders(IN cutoff DATE)
+BEGIN
+    DELETE FROM orders WHERE created_at < cutoff;
+    SELECT CASE WHEN 1 = 1 THEN 'a' ELSE 'b' END;
+END //
+DELIMITER ;
+
+SELECT * FROM users FOR UPDATE SKIP LOCKED;
+"""
+
+
+def _annotated(content, path="/db/002_orders.sql"):
+    syntax = RegexFallbackScanner().parse(content, path, "sql")
+    annotate_sql(syntax, content)
+    return syntax
+
+
+class TestSplitStatements:
+    def test_statements_and_line_spans(self):
+        statements = split_statements(_MIGRATION)
+
+        assert [(s.start_line, s.end_line, s.keyword) for s in statements] == [
+            (2, 8, "CREATE"),
+            (10, 10, "CREATE"),
+            (12, 12, "ALTER"),
+            (14, 14, "BEGIN"),
+            (15, 15, "INSERT"),
+            (16, 16, "COMMIT"),
+            (18, 38, "CREATE"),
+            (41, 45, "CREATE"),
+            (48, 48, "SELECT"),
+        ]
+
+    def test_dollar_quoted_body_is_one_statement(self):
+        (statement,) = [s for s in split_statements(_MIGRATION) if s.start_line == 18]
+
+        assert "END IF" in statement.text
+        assert statement.text.endswith("LANGUAGE plpgsql")
+
+    def test_semicolons_in_strings_and_comments_do_not_split(self):
+        content = "INSERT INTO t VALUES ('a;b'); -- x; y\n/* ; */ SELECT 1;\n"
+
+        assert [s.keyword for s in split_statements(content)] == ["INSERT", "SELECT"]
+
+    def test_go_separates_tsql_batches(self):
+        statements = split_statements("SELECT 1\nGO\nSELECT 2\ngo\n")
+
+        assert [(s.text, s.start_line) for s in statements] == [("SELECT 1", 1), ("SELECT 2", 3)]
+
+
+class TestStatementComplexity:
+    def test_simple_statement(self):
+        assert statement_complexity("SELECT * FROM users") == 1
+
+    def test_joins_conditions_and_set_operations(self):
+        query = (
+            "SELECT a FROM t JOIN u ON u.id = t.id "
+            "WHERE t.x = 1 AND (t.y = 2 OR t.z = 3) "
+            "UNION SELECT b FROM v"
+        )
+        # 1 + JOIN + AND + OR + UNION
+        assert statement_complexity(query) == 5
+
+    def test_subqueries_and_case_branches(self):
+        query = (
+            "SELECT CASE WHEN a > 1 THEN 'x' WHEN a > 0 THEN 'y' ELSE 'z' END "
+            "FROM t WHERE id IN (SELECT id FROM u)"
+        )
+        assert statement_complexity(query) == 4
+
+    def test_ddl_guards_and_between_do_not_count(self):
+        assert statement_complexity("DROP TABLE IF EXISTS t") == 1
+        assert statement_complexity("SELECT a FROM t WHERE x BETWEEN 1 AND 2") == 1
+
+    def test_procedural_branches(self):
+        (routine,) = [s for s in split_statements(_MIGRATION) if s.start_line == 18]
+
+        # JOIN, AND, IF, ELSIF, FOR ... LOOP (END IF/END LOOP close, not branch)
+        assert routine.complexity == 8
+
+
+class TestAnnotateSql:
+    def test_referenced_tables_are_imports(self):
+        syntax = _annotated(_MIGRATION)
+
+        # CTEs (recent), set-returning functions and EXTRACT(... FROM ...) are not tables
+        assert [imp.source for imp in syntax.imports] == [
+            "orders",
+            "users",
+            "audit_log",
+            "order_items",
+            "products",
+        ]
+
+    def test_create_table_columns_are_fields(self):
+        syntax = _annotated(_MIGRATION)
+
+        (orders,) = syntax.classes
+        assert orders.name == "orders"
+        assert orders.fields == ["id", "user_id", "total", "status"]
+
+    def test_routines_are_functions(self):
+        syntax = _annotated(_MIGRATION)
+
+        assert [(fn.name, fn.params, fn.start_line, fn.end_line) for fn in syntax.functions] == [
+            ("refresh_totals", ["p_user", "p_since"], 18, 38),
+            ("archive_orders", ["cutoff"], 41, 45),
+        ]
+
+    def test_table_name_drops_schema_and_quotes(self):
+        assert table_name('"public"."Users"') == "users"
+        assert table_name("[dbo].[Orders]") == "orders"
+        assert table_name("`shop` . `items`") == "items"
@@ -0,0 +1,116 @@
+"""Tests for Swift module imports, type members and extensions."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.swift import annotate_swift
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+
+_FEED = """\
+import UIKit
+@testable import FeedCore
+import struct Models.User
+import class Models.Session
+// import Legacy
+
+protocol FeedDelegate: AnyObject {
+    func feedDidLoad(_ feed: Feed)
+}
+
+final class FeedViewController<C: Cell>: UIViewController, Loading where C: Reusable {
+    private let service: FeedService
+    @Published var items: [Item] = []
+    weak var delegate: FeedDelegate?
+    static let reuseID = "class Fake {"
+
+    init(service: FeedService) {
+        self.service = service
+        super.init(nibName: nil, bundle: nil)
+    }
+
+    override func viewDidLoad() {
+        func configure() {
+            let local = 1
+        }
+        configure()
+    }
+
+    class func make() -> FeedViewController { FeedViewController(service: .shared) }
+}
+
+extension FeedViewController: UITableViewDataSource {
+    var isEmpty: Bool { items.isEmpty }
+
+    func tableView(_ tableView: UITableView, numberOfRowsInSection section: Int) -> Int {
+        items.count
+    }
+}
+
+extension Foo.Bar {
+    func baz() {}
+}
+"""
+
+
+def _annotated(content, path="App/Feed/FeedViewController.swift"):
+    syntax = RegexFallbackScanner().parse(content, path, "swift")
+    annotate_swift(syntax, content)
+    return syntax
+
+
+class TestImports:
+    def test_one_import_per_module(self):
+        syntax = _annotated(_FEED)
+
+        assert syntax.import_sources == ["UIKit", "FeedCore", "Models"]
+
+    def test_declaration_imports_name_their_symbols(self):
+        names = {imp.source: imp.names for imp in _annotated(_FEED).imports}
+
+        assert names["Models"] == ["User", "Session"]
+        assert names["UIKit"] == []
+
+
+class TestTypes:
+    def test_members_fields_and_bases(self):
+        controller = _annotated(_FEED).classes[1]
+
+        assert controller.name == "FeedViewController"
+        # Generic parameters and where clauses are not bases
+        assert controller.bases == ["UIViewController", "Loading"]
+        # Strings are masked, so "class Fake {" declares nothing
+        assert controller.fields == ["service", "items", "delegate", "reuseID"]
+        # The local func configure belongs to viewDidLoad, not the class
+        assert [fn.name for fn in controller.methods] == ["viewDidLoad", "make", "init"]
+        assert not controller.is_extension
+
+    def test_protocols_are_abstract(self):
+        delegate = _annotated(_FEED).classes[0]
+
+        assert delegate.is_abstract
+        assert [fn.name for fn in delegate.methods] == ["feedDidLoad"]
+
+    def test_extensions_get_their_own_members(self):
+        classes = _annotated(_FEED).classes
+
+        extension = classes[2]
+        assert extension.name == "FeedViewController"
+        assert extension.is_extension
+        assert extension.bases == ["UITableViewDataSource"]
+        assert extension.fields == ["isEmpty"]
+        assert [fn.name for fn in extension.methods] == ["tableView"]
+
+    def test_nested_type_extensions_use_the_last_segment(self):
+        bar = _annotated(_FEED).classes[3]
+
+        assert (bar.name, bar.is_extension) == ("Bar", True)
+        assert [fn.name for fn in bar.methods] == ["baz"]
+
+
+class TestSyntaxExtractor:
+    def test_swift_files_are_annotated(self, tmp_path):
+        (tmp_path / "FeedViewController.swift").write_text(_FEED)
+
+        result = SyntaxExtractor().extract(tmp_path / "FeedViewController.swift", tmp_path)
+
+        assert result is not None
+        assert "Models" in result.import_sources
+        assert [cls.is_extension for cls in result.classes] == [False, False, True, True]
@@ -5,6 +5,7 @@ from pathlib import Path
 
 import pytest
 
+from shannon_insight.scanning.gobuild import GoBuildConfig
 from shannon_insight.scanning.syntax import FileSyntax
 from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
 from shannon_insight.scanning.treesitter_parser import (
@@ -284,6 +285,60 @@ class TestSyntaxExtractorMultiLanguage:
             assert result is not None
             assert result.language == "java"
 
+    def test_c_conditionals(self):
+        """C files are parsed as configured by c_defines; "all" keeps every branch."""
+        code = (
+            "#ifdef USE_EPOLL\nint poll_wait(int fd) {\n    return 1;\n}\n"
+            "#else\nint poll_wait(int fd) {\n    return 0;\n}\n#endif\n"
+        )
+        with tempfile.TemporaryDirectory() as tmp:
+            root = Path(tmp)
+            (root / "poll.c").write_text(code)
+
+            default = SyntaxExtractor().extract(root / "poll.c", root)
+            epoll = SyntaxExtractor(c_defines=["USE_EPOLL"]).extract(root / "poll.c", root)
+            every = SyntaxExtractor(c_conditionals="all").extract(root / "poll.c", root)
+
+            assert default is not None and epoll is not None and every is not None
+            assert [fn.start_line for fn in default.functions] == [6]
+            assert [fn.start_line for fn in epoll.functions] == [2]
+            assert len(every.functions) == 2
+
+    def test_go_build_constraints(self):
+        """Go files for other platforms are skipped unless go_build is None."""
+        with tempfile.TemporaryDirectory() as tmp:
+            root = Path(tmp)
+            (root / "open_windows.go").write_text("package fs\n\nfunc open() {}\n")
+            (root / "open_unix.go").write_text(
+                "//go:build unix\n\npackage fs\n\nfunc open() {}\n"
+            )
+            cache: dict[str, str] = {}
+
+            linux = SyntaxExtractor(go_build=GoBuildConfig())
+            every = SyntaxExtractor()
+
+            assert linux.extract(root / "open_windows.go", root, content_cache=cache) is None
+            assert cache == {}
+            assert linux.extract(root / "open_unix.go", root) is not None
+            assert every.extract(root / "open_windows.go", root) is not None
+
+    def test_extract_source_needs_no_file(self):
+        """extract_source() parses text that is not on disk, like extract()."""
+        with tempfile.TemporaryDirectory() as tmp:
+            root = Path(tmp)
+            (root / "pkg").mkdir()
+            (root / "pkg" / "greet.py").write_text(SAMPLE_PYTHON)
+            extractor = SyntaxExtractor()
+
+            from_disk = extractor.extract(root / "pkg" / "greet.py", root)
+            from_text = extractor.extract_source("pkg/greet.py", SAMPLE_PYTHON, Path("/"))
+
+            assert from_text.path == "pkg/greet.py"
+            assert from_text.language == "python"
+            assert [fn.name for fn in from_text.functions] == [
+                fn.name for fn in from_disk.functions
+            ]
+
     def test_unknown_language(self):
         """Falls back to unknown for unrecognized extensions."""
         with tempfile.TemporaryDirectory() as tmp:
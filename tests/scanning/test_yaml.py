@@ -0,0 +1,137 @@
+"""Tests for YAML document parsing and Kubernetes resource extraction."""
+
+from shannon_insight.scanning.fallback import RegexFallbackScanner
+from shannon_insight.scanning.languages import detect_language
+from shannon_insight.scanning.yaml import (
+    annotate_yaml,
+    key_path,
+    parse_yaml,
+    resource_name,
+    strip_comment,
+)
+
+_MANIFEST = """\
+# Web tier
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web # the web app
+  labels: {app: web, tier: "front#end"}
+spec:
+  template:
+    spec:
+      containers:
+      - name: web
+        args: [
+          "--port", "80",
+        ]
+        env:
+          - name: TOKEN
+            valueFrom:
+              secretKeyRef:
+                name: web
+                key: token
+        command: |
+          echo start: now
+          - not an item
+      {{- if .Values.sidecar }}
+      - name: sidecar
+        image: busybox
+      {{- end }}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+...
+"""
+
+
+def _tree(node):
+    children = [_tree(child) for child in node.children]
+    return (node.key, node.start_line, node.end_line, node.value, children)
+
+
+class TestParseYaml:
+    def test_documents(self):
+        deployment, service = parse_yaml(_MANIFEST)
+
+        assert (deployment.start_line, deployment.end_line) == (2, 26)
+        assert (service.start_line, service.end_line) == (29, 32)
+        assert [resource_name(d) for d in (deployment, service)] == [
+            "Deployment/web",
+            "Service/web",
+        ]
+
+    def test_mappings_sequences_and_scalars(self):
+        deployment, _ = parse_yaml(_MANIFEST)
+        containers = deployment.child("spec").child("template").child("spec").child("containers")
+
+        web, sidecar = containers.children
+        assert [(c.key, c.value) for c in web.children] == [
+            ("name", "web"),
+            ("args", "["),
+            ("env", ""),
+            ("command", ""),
+        ]
+        assert (web.start_line, web.end_line) == (11, 23)
+        assert _tree(sidecar) == (
+            "-",
+            25,
+            26,
+            "",
+            [("name", 25, 25, "sidecar", []), ("image", 26, 26, "busybox", [])],
+        )
+        assert deployment.child("metadata").child("labels").value == (
+            '{app: web, tier: "front#end"}'
+        )
+
+    def test_depth_and_scalars(self):
+        deployment, service = parse_yaml(_MANIFEST)
+
+        assert deployment.depth == 10
+        assert key_path(deployment.deepest_chain()) == (
+            "spec.template.spec.containers[].env[].valueFrom.secretKeyRef"
+        )
+        assert (service.depth, service.scalars) == (2, 3)
+
+    def test_empty_documents_are_skipped(self):
+        (document,) = parse_yaml("---\n# nothing\n---\na: 1\n")
+        assert _tree(document) == ("", 4, 4, "", [("a", 4, 4, "1", [])])
+        assert parse_yaml("") == []
+
+    def test_strip_comment(self):
+        assert strip_comment("a: b # c") == "a: b"
+        assert strip_comment("url: http://x/#anchor") == "url: http://x/#anchor"
+        assert strip_comment("a: 'x # y'  # z") == "a: 'x # y'"
+
+
+class TestAnnotateYaml:
+    def test_resources_are_functions(self):
+        syntax = RegexFallbackScanner().parse(_MANIFEST, "k8s/web.yaml", "yaml")
+        annotate_yaml(syntax, _MANIFEST)
+
+        functions = [(f.name, f.start_line, f.end_line, f.nesting_depth) for f in syntax.functions]
+        assert functions == [("Deployment/web", 2, 26, 10), ("Service/web", 29, 32, 2)]
+        assert syntax.classes == [] and syntax.imports == []
+
+    def test_other_yaml_has_one_function_per_top_level_entry(self):
+        content = "services:\n  web:\n    image: nginx\nvolumes:\n  data: {}\n"
+        syntax = RegexFallbackScanner().parse(content, "compose.yml", "yaml")
+        annotate_yaml(syntax, content)
+
+        assert [(f.name, f.start_line, f.end_line) for f in syntax.functions] == [
+            ("services", 1, 3),
+            ("volumes", 4, 5),
+        ]
+
+    def test_top_level_sequence_items_are_named(self):
+        content = "- name: Install\n  apt: {name: nginx}\n- shell: echo hi\n"
+        syntax = RegexFallbackScanner().parse(content, "playbook.yml", "yaml")
+        annotate_yaml(syntax, content)
+
+        assert [f.name for f in syntax.functions] == ["Install", "item 1"]
+
+    def test_detect_language(self):
+        assert detect_language("chart/values.yaml") == "yaml"
+        assert detect_language(".github/workflows/ci.yml") == "yaml"
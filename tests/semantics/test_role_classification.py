@@ -103,6 +103,11 @@ class TestRoleClassificationEntryPoint:
         syntax = make_syntax(functions=[fn])
         assert classify_role(syntax) == Role.ENTRY_POINT
 
+    def test_notebook(self):
+        """Notebooks are run, not imported: ENTRY_POINT."""
+        syntax = make_syntax(path="analysis/eda.ipynb", functions=[make_function()])
+        assert classify_role(syntax) == Role.ENTRY_POINT
+
 
 class TestRoleClassificationInterface:
     """Test INTERFACE role classification."""
@@ -185,6 +190,12 @@ class TestRoleClassificationModel:
         syntax = make_syntax(classes=[cls])
         assert classify_role(syntax) == Role.MODEL
 
+    def test_active_record_model(self):
+        """Rails models inheriting from ApplicationRecord are MODEL."""
+        cls = make_class(name="Post", bases=["ApplicationRecord"])
+        syntax = make_syntax(path="app/models/post.rb", classes=[cls], language="ruby")
+        assert classify_role(syntax) == Role.MODEL
+
 
 class TestRoleClassificationCLI:
     """Test CLI role classification."""
@@ -205,6 +216,12 @@ class TestRoleClassificationService:
         syntax = make_syntax(classes=[cls])
         assert classify_role(syntax) == Role.SERVICE
 
+    def test_rails_controller_actions(self):
+        """Files with Rails controller actions are SERVICE."""
+        fn = make_function(name="index", decorators=["action"])
+        syntax = make_syntax(path="app/controllers/posts_controller.rb", functions=[fn])
+        assert classify_role(syntax) == Role.SERVICE
+
     def test_stateful_class(self):
         """Classes with >= 3 non-dunder methods and state are SERVICE."""
         cls = make_class(
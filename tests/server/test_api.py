@@ -68,7 +68,7 @@ class TestCategoryMap:
 
     def test_all_categories_present(self):
         cats = set(CATEGORY_MAP.values())
-        assert cats == {"incomplete", "fragile", "tangled", "team"}
+        assert cats == {"incomplete", "fragile", "tangled", "team", "security", "errors"}
 
     def test_hollow_code_is_incomplete(self):
         assert CATEGORY_MAP["hollow_code"] == "incomplete"
@@ -82,6 +82,15 @@ class TestCategoryMap:
     def test_knowledge_silo_is_team(self):
         assert CATEGORY_MAP["knowledge_silo"] == "team"
 
+    def test_crypto_policy_violation_is_security(self):
+        assert CATEGORY_MAP["crypto_policy_violation"] == "security"
+
+    def test_auth_flow_issue_is_security(self):
+        assert CATEGORY_MAP["auth_flow_issue"] == "security"
+
+    def test_error_hygiene_issue_is_errors(self):
+        assert CATEGORY_MAP["error_hygiene_issue"] == "errors"
+
 
 class TestBuildDashboardState:
     """Test the full state builder."""
@@ -0,0 +1,104 @@
+"""Tests for server.baseline mainline rotation."""
+
+import json
+from types import SimpleNamespace
+
+import pytest
+
+from shannon_insight.persistence.database import HistoryDB
+from shannon_insight.persistence.models import FindingRecord, TensorSnapshot
+from shannon_insight.server.baseline import BaselineRotator, detect_mainline
+
+
+def _settings(mode="merge", branch="", webhooks=()):
+    return SimpleNamespace(
+        baseline_rotation=mode,
+        baseline_interval_minutes=60,
+        baseline_branch=branch,
+        history_url="",
+        webhooks=list(webhooks),
+    )
+
+
+def _rotator(root, analyzed, **kwargs):
+    def analyze(path):
+        analyzed.append(path)
+        return TensorSnapshot(file_count=1, timestamp="2025-01-01T00:00:00")
+
+    return BaselineRotator(str(root), _settings(**kwargs), analyze_fn=analyze)
+
+
+@pytest.fixture
+def repo(git_repo):
+    git_repo.commit({"a.py": "x = 1\n"})
+    return git_repo
+
+
+class TestBaselineRotator:
+    def test_detects_local_main(self, repo):
+        assert detect_mainline(str(repo.root)) == "main"
+
+    def test_rotates_then_skips_when_current(self, repo):
+        sha = repo.git("rev-parse", "HEAD")
+        analyzed = []
+        rotator = _rotator(repo.root, analyzed)
+
+        first = rotator.rotate(reason="merge")
+        second = rotator.rotate(reason="merge")
+
+        assert first.rotated and first.commit_sha == sha and first.ref == "main"
+        assert not second.rotated and second.snapshot_id == first.snapshot_id
+        assert len(analyzed) == 1
+        with HistoryDB(str(repo.root)) as db:
+            assert db.get_baseline_snapshot_id() == first.snapshot_id
+
+    def test_rotates_on_new_mainline_commit(self, repo):
+        rotator = _rotator(repo.root, [])
+        first = rotator.rotate()
+        sha = repo.commit({"a.py": "x = 2\n"})
+
+        second = rotator.rotate(reason="schedule")
+
+        assert second.rotated and second.commit_sha == sha
+        history = rotator.status()["history"]
+        assert [h["reason"] for h in history] == ["schedule", "manual"]
+        assert history[1]["snapshot_id"] == first.snapshot_id
+
+    def test_unknown_branch(self, repo):
+        result = _rotator(repo.root, [], branch="release").rotate()
+
+        assert not result.rotated
+        assert "release" in result.message
+
+    def test_finding_changes_are_sent_to_webhooks(self, repo, monkeypatch):
+        posted = []
+        monkeypatch.setattr(
+            "shannon_insight.webhooks._post",
+            lambda url, body, headers: posted.append(json.loads(body)) or 200,
+        )
+        runs = iter([["a", "b"], ["b", "c"]])
+
+        def analyze(path):
+            findings = [
+                FindingRecord("high_risk_hub", key, 0.8, key, ["a.py"], [], "")
+                for key in next(runs)
+            ]
+            return TensorSnapshot(file_count=1, timestamp="2025-01-01T00:00:00", findings=findings)
+
+        hooks = [
+            {
+                "url": "https://hooks.example.com/q",
+                "events": ["finding_created", "finding_resolved"],
+            }
+        ]
+        rotator = BaselineRotator(str(repo.root), _settings(webhooks=hooks), analyze_fn=analyze)
+        rotator.rotate()
+        assert posted == []  # nothing to compare the first baseline with
+
+        sha = repo.commit({"a.py": "x = 2\n"})
+        rotator.rotate()
+
+        assert [(p["event"], p["identity_key"], p["commit_sha"]) for p in posted] == [
+            ("finding_created", "c", sha),
+            ("finding_resolved", "a", sha),
+        ]
@@ -0,0 +1,82 @@
+"""Tests for server.decorations metric decoration stream."""
+
+import json
+
+from shannon_insight.server.decorations import (
+    DECORATIONS_METHOD,
+    DecorationStream,
+    function_heat,
+)
+from tests.conftest import make_function
+
+SMALL = "def small() -> int:\n    return 1\n"
+
+BIGGER = """def small() -> int:
+    for i in range(3):
+        if i:
+            while i:
+                i -= 1
+    return 1
+"""
+
+
+class TestFunctionHeat:
+    def test_trivial_function_is_cool(self):
+        assert function_heat(make_function(end_line=2, nesting_depth=0)) < 0.1
+
+    def test_saturates_at_one(self):
+        assert function_heat(make_function(end_line=500, nesting_depth=20)) == 1.0
+
+    def test_nesting_increases_heat(self):
+        deep, shallow = make_function(end_line=10, nesting_depth=4), make_function(end_line=10)
+        assert function_heat(deep) > function_heat(shallow)
+
+
+class TestDecorationStream:
+    def test_notification_shape(self, tmp_path):
+        (tmp_path / "a.py").write_text(SMALL)
+        stream = DecorationStream(str(tmp_path))
+
+        [msg] = stream.notifications(["a.py"])
+
+        assert msg["jsonrpc"] == "2.0"
+        assert msg["method"] == DECORATIONS_METHOD
+        assert "id" not in msg  # notifications carry no id
+        params = msg["params"]
+        assert params["path"] == "a.py"
+        assert params["version"] == 1
+        [fn] = params["functions"]
+        assert fn["name"] == "small"
+        assert fn["trend"] == "new"
+        assert 0.0 <= fn["heat"] <= 1.0
+        assert "cell" not in fn
+
+    def test_notebook_functions_carry_their_cell(self, tmp_path):
+        cells = [
+            {"cell_type": "markdown", "source": "# Notes", "metadata": {}},
+            {"cell_type": "code", "source": SMALL, "metadata": {}, "outputs": []},
+        ]
+        (tmp_path / "eda.ipynb").write_text(json.dumps({"cells": cells, "metadata": {}}))
+        stream = DecorationStream(str(tmp_path))
+
+        [msg] = stream.notifications(["eda.ipynb"])
+
+        [fn] = msg["params"]["functions"]
+        assert (fn["name"], fn["cell"], fn["start_line"]) == ("small", 1, 2)
+
+    def test_trend_tracks_previous_heat(self, tmp_path):
+        target = tmp_path / "a.py"
+        target.write_text(SMALL)
+        stream = DecorationStream(str(tmp_path))
+        stream.notifications(["a.py"])
+
+        target.write_text(BIGGER)
+        [msg] = stream.notifications(["a.py"])
+
+        assert msg["params"]["version"] == 2
+        assert msg["params"]["functions"][0]["trend"] == "up"
+
+    def test_deleted_file_sends_empty_functions(self, tmp_path):
+        stream = DecorationStream(str(tmp_path))
+        [msg] = stream.notifications(["gone.py"])
+        assert msg["params"]["functions"] == []
@@ -0,0 +1,58 @@
+"""Tests for server.heatmap per-line heat documents."""
+
+import pytest
+
+from shannon_insight.server.heatmap import HeatmapBuilder, HeatmapError, line_complexity
+
+SOURCE = """def f(x):
+    # if this were code it would count
+    if x and y or z:
+        return 1
+    return 0
+"""
+
+
+class TestLineComplexity:
+    def test_counts_decision_points(self):
+        assert line_complexity(SOURCE.splitlines()) == [0, 0, 1, 0, 0]
+
+    def test_boolean_operators(self):
+        assert line_complexity(["if (a && b || c) {"]) == [3]
+
+
+class TestHeatmapBuilder:
+    def test_worktree_document(self, tmp_path):
+        (tmp_path / "a.py").write_text(SOURCE)
+        doc = HeatmapBuilder(str(tmp_path)).build("a.py")
+
+        assert doc["path"] == "a.py"
+        assert doc["sha"] is None
+        assert doc["lines"] == 5
+        assert len(doc["complexity"]) == 5
+        assert doc["recency"] is None  # not a git repo
+        assert doc["coverage"] is None  # no coverage report
+
+    def test_rejects_path_escape(self, tmp_path):
+        with pytest.raises(HeatmapError):
+            HeatmapBuilder(str(tmp_path)).build("../etc/passwd")
+
+    def test_rejects_bad_sha(self, tmp_path):
+        (tmp_path / "a.py").write_text(SOURCE)
+        with pytest.raises(HeatmapError):
+            HeatmapBuilder(str(tmp_path)).build("a.py", sha="HEAD; rm -rf /")
+
+    def test_coverage_from_lcov(self, tmp_path):
+        (tmp_path / "a.py").write_text(SOURCE)
+        (tmp_path / "lcov.info").write_text("SF:a.py\nDA:1,1\nDA:3,0\nend_of_record\n")
+        doc = HeatmapBuilder(str(tmp_path)).build("a.py")
+        assert doc["coverage"] == [1, None, 0, None, None]
+
+    def test_sha_and_recency(self, git_repo):
+        first = git_repo.commit({"a.py": "x = 1\n"}, message="one")
+        (git_repo.root / "a.py").write_text(SOURCE)
+
+        doc = HeatmapBuilder(str(git_repo.root)).build("a.py", sha=first)
+
+        assert doc["sha"] == first
+        assert doc["lines"] == 1
+        assert doc["recency"] == [1.0]
@@ -0,0 +1,183 @@
+"""Tests for server.jobs analysis queue backends and graceful drain.
+
+The Redis and Postgres backends run the same contract tests when
+SHANNON_TEST_REDIS_URL / SHANNON_TEST_POSTGRES_URL point at a server.
+"""
+
+import json
+import os
+import threading
+import uuid
+
+import pytest
+
+from shannon_insight.server.jobs import (
+    MAX_ATTEMPTS,
+    Job,
+    JobQueue,
+    JobWorker,
+    MemoryJobQueue,
+    open_job_queue,
+)
+
+BACKENDS = [
+    pytest.param("memory", id="memory"),
+    pytest.param(
+        os.environ.get("SHANNON_TEST_REDIS_URL", ""),
+        id="redis",
+        marks=pytest.mark.skipif(
+            not os.environ.get("SHANNON_TEST_REDIS_URL"), reason="SHANNON_TEST_REDIS_URL not set"
+        ),
+    ),
+    pytest.param(
+        os.environ.get("SHANNON_TEST_POSTGRES_URL", ""),
+        id="postgres",
+        marks=pytest.mark.skipif(
+            not os.environ.get("SHANNON_TEST_POSTGRES_URL"),
+            reason="SHANNON_TEST_POSTGRES_URL not set",
+        ),
+    ),
+]
+
+
+@pytest.fixture(params=BACKENDS)
+def queue(request, tmp_path):
+    # A fresh queue name per test keeps shared backends isolated
+    return open_job_queue(request.param, str(tmp_path), name=f"test-{uuid.uuid4().hex[:8]}")
+
+
+class TestQueueContract:
+    def test_implements_protocol(self, queue):
+        assert isinstance(queue, JobQueue)
+
+    def test_pending_kind_not_queued_twice(self, queue):
+        assert queue.put(Job(kind="analyze"))
+        assert not queue.put(Job(kind="analyze", reason="refresh"))
+        assert queue.put(Job(kind="rotate"))
+        assert len(queue) == 2
+
+    def test_fifo_with_running_tracked_until_done(self, queue):
+        queue.put(Job(kind="analyze"))
+        queue.put(Job(kind="rotate"))
+
+        job = queue.get(timeout=0)
+
+        assert (job.kind, job.attempts) == ("analyze", 1)
+        assert [j["id"] for j in queue.status()["running"]] == [job.id]
+        # Running jobs do not block a new one of the same kind
+        assert queue.put(Job(kind="analyze"))
+        queue.done(job)
+        assert queue.status()["running"] == []
+        assert queue.get(timeout=0).kind == "rotate"
+
+    def test_requeue_puts_job_back_first(self, queue):
+        queue.put(Job(kind="analyze"))
+        queue.put(Job(kind="rotate"))
+        job = queue.get(timeout=0)
+
+        queue.requeue(job)
+
+        again = queue.get(timeout=0)
+        assert (again.id, again.attempts) == (job.id, 1)
+
+    def test_requeue_superseded_by_pending_job_of_its_kind(self, queue):
+        queue.put(Job(kind="analyze"))
+        job = queue.get(timeout=0)
+        assert queue.put(Job(kind="analyze", reason="refresh"))
+
+        queue.requeue(job)
+
+        assert [j["reason"] for j in queue.status()["pending"]] == ["refresh"]
+        assert queue.status()["running"] == []
+        assert not queue.put(Job(kind="analyze"))
+
+    def test_get_times_out_when_empty(self, queue):
+        assert queue.get(timeout=0.05) is None
+
+
+class TestOpenJobQueue:
+    def test_default_snapshots_under_repo(self, tmp_path):
+        queue = open_job_queue("", str(tmp_path))
+
+        assert isinstance(queue, MemoryJobQueue)
+        assert queue.path == tmp_path / ".shannon" / "jobs.json"
+
+    def test_memory_has_no_snapshot(self, tmp_path):
+        assert open_job_queue("memory", str(tmp_path)).path is None
+
+    def test_unknown_backend_rejected(self, tmp_path):
+        with pytest.raises(ValueError, match="Unsupported job_queue"):
+            open_job_queue("amqp://broker", str(tmp_path))
+
+
+class TestMemoryJobQueue:
+    def test_queue_survives_restart(self, tmp_path):
+        path = tmp_path / "jobs.json"
+        queue = MemoryJobQueue(path)
+        queue.put(Job(kind="analyze", reason="change"))
+        queue.put(Job(kind="rotate"))
+        running = queue.get(timeout=0)
+
+        restored = MemoryJobQueue(path)
+
+        # The interrupted job comes back first, then the pending one
+        first = restored.get(timeout=0)
+        assert (first.id, first.kind, first.attempts) == (running.id, "analyze", 2)
+        assert restored.get(timeout=0).kind == "rotate"
+
+    def test_repeatedly_interrupted_job_dropped(self, tmp_path):
+        path = tmp_path / "jobs.json"
+        job = Job(kind="analyze", attempts=MAX_ATTEMPTS)
+        path.write_text(json.dumps({"pending": [], "running": [job.to_dict()]}))
+
+        assert len(MemoryJobQueue(path)) == 0
+
+    def test_interrupted_job_superseded_by_pending_one(self, tmp_path):
+        path = tmp_path / "jobs.json"
+        running, pending = Job(kind="analyze", attempts=1), Job(kind="analyze")
+        data = {"pending": [pending.to_dict()], "running": [running.to_dict()]}
+        path.write_text(json.dumps(data))
+
+        restored = MemoryJobQueue(path)
+
+        assert [j["id"] for j in restored.status()["pending"]] == [pending.id]
+
+    def test_unreadable_file_ignored(self, tmp_path):
+        path = tmp_path / "jobs.json"
+        path.write_text("{not json")
+
+        assert len(MemoryJobQueue(path)) == 0
+
+
+class TestJobWorker:
+    def test_runs_jobs_and_refuses_new_ones_while_draining(self, tmp_path):
+        ran = threading.Event()
+        worker = JobWorker(MemoryJobQueue(), {"analyze": lambda job: ran.set()})
+        worker.start()
+
+        assert worker.submit("analyze")
+        assert ran.wait(5)
+        assert worker.drain(timeout=5)
+        assert worker.draining
+        assert not worker.submit("analyze")
+
+    def test_drain_waits_for_running_job_and_keeps_the_rest(self, tmp_path):
+        path = tmp_path / "jobs.json"
+        started, release = threading.Event(), threading.Event()
+
+        def slow(job):
+            started.set()
+            release.wait(5)
+
+        worker = JobWorker(MemoryJobQueue(path), {"analyze": slow, "rotate": lambda job: None})
+        worker.start()
+        worker.submit("analyze")
+        assert started.wait(5)
+        worker.submit("rotate")
+
+        assert not worker.drain(timeout=0.05)  # still running
+        release.set()
+        assert worker.drain(timeout=5)
+
+        restored = MemoryJobQueue(path)
+        assert [j["kind"] for j in restored.status()["pending"]] == ["rotate"]
@@ -174,3 +174,39 @@ class TestQueueOverflowHandling:
         state.send_progress("Testing...", phase="test")
 
         # No exception means success
+
+
+class TestRpcNotifications:
+    """JSON-RPC notification broadcast for editor connections."""
+
+    def test_rpc_listener_receives_notification(self):
+        state = ServerState()
+        q: queue.Queue = queue.Queue()
+        state.add_rpc_listener(q)
+
+        msg = {"jsonrpc": "2.0", "method": "shannon/metricDecorations", "params": {}}
+        state.publish_notification(msg)
+        assert q.get(timeout=1) == msg
+
+    def test_rpc_and_dashboard_listeners_are_separate(self):
+        state = ServerState()
+        dashboard: queue.Queue = queue.Queue()
+        rpc: queue.Queue = queue.Queue()
+        state.add_listener(dashboard)
+        state.add_rpc_listener(rpc)
+
+        state.publish_notification({"jsonrpc": "2.0", "method": "x"})
+        state.update({"health": 5.0})
+
+        assert rpc.qsize() == 1
+        assert dashboard.qsize() == 1
+        assert dashboard.get()["type"] == "complete"
+
+    def test_remove_rpc_listener(self):
+        state = ServerState()
+        q: queue.Queue = queue.Queue()
+        state.add_rpc_listener(q)
+        state.remove_rpc_listener(q)
+
+        state.publish_notification({"jsonrpc": "2.0", "method": "x"})
+        assert q.empty()
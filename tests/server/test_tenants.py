@@ -0,0 +1,115 @@
+"""Tests for server.tenants multi-tenant isolation and quotas."""
+
+import threading
+import time
+
+import pytest
+
+from shannon_insight.exceptions import ShannonInsightError
+from shannon_insight.server.tenants import (
+    AnalysisQuota,
+    Tenant,
+    TenantSpec,
+    load_manifest,
+)
+
+
+def _manifest(tmp_path, body):
+    path = tmp_path / "tenants.toml"
+    path.write_text(body)
+    return path
+
+
+class TestLoadManifest:
+    def test_parses_tenants_relative_to_manifest(self, tmp_path):
+        (tmp_path / "api").mkdir()
+        (tmp_path / "web").mkdir()
+        (tmp_path / "api.toml").write_text("max_findings = 10\n")
+        path = _manifest(
+            tmp_path,
+            "max_concurrent_analyses = 2\n"
+            '[tenants.api]\npath = "api"\nconfig = "api.toml"\nmax_concurrent = 2\n'
+            '[tenants.web]\npath = "web"\n',
+        )
+
+        manifest = load_manifest(path)
+
+        assert manifest.max_concurrent == 2
+        api, web = manifest.tenants
+        assert (api.name, api.root, api.max_concurrent) == ("api", str(tmp_path / "api"), 2)
+        assert api.config_file == str(tmp_path / "api.toml")
+        assert (web.config_file, web.max_concurrent) == (None, 1)
+
+    @pytest.mark.parametrize(
+        "body, message",
+        [
+            ("max_concurrent_analyses = 2\n", "no \\[tenants"),
+            ('[tenants."Bad Name"]\npath = "."\n', "Invalid tenant name"),
+            ('[tenants.x]\nconfig = "a.toml"\n', "needs a path"),
+            ('[tenants.x]\npath = "missing"\n', "not a directory"),
+            ('[tenants.x]\npath = "."\nmax_concurrent = 0\n', "positive integer"),
+            ('[tenants.a]\npath = "."\n[tenants.b]\npath = "./"\n', "several tenants"),
+        ],
+    )
+    def test_rejects_invalid_manifests(self, tmp_path, body, message):
+        with pytest.raises(ShannonInsightError, match=message):
+            load_manifest(_manifest(tmp_path, body))
+
+
+class TestAnalysisQuota:
+    def test_full_while_slots_held(self):
+        quota = AnalysisQuota(2, threading.Semaphore(4))
+
+        with quota.slot():
+            assert not quota.full
+            with quota.slot():
+                assert quota.full
+        assert quota.active == 0
+
+    def test_server_limit_shared_across_tenants(self):
+        shared = threading.Semaphore(1)
+        quotas = [AnalysisQuota(2, shared) for _ in range(3)]
+        running, peak = [0], [0]
+        lock = threading.Lock()
+
+        def work(quota):
+            with quota.slot():
+                with lock:
+                    running[0] += 1
+                    peak[0] = max(peak[0], running[0])
+                time.sleep(0.01)
+                with lock:
+                    running[0] -= 1
+
+        threads = [threading.Thread(target=work, args=(q,)) for q in quotas for _ in range(2)]
+        for t in threads:
+            t.start()
+        for t in threads:
+            t.join()
+
+        assert peak[0] == 1
+
+
+class TestTenant:
+    def test_config_comes_from_tenant_root_not_cwd(self, tmp_path, monkeypatch):
+        repo = tmp_path / "repo"
+        repo.mkdir()
+        (repo / "shannon-insight.toml").write_text('cache_dir = "build/cache"\n')
+        elsewhere = tmp_path / "server"
+        elsewhere.mkdir()
+        (elsewhere / "shannon-insight.toml").write_text('cache_dir = "server-cache"\n')
+        monkeypatch.chdir(elsewhere)
+
+        tenant = Tenant(TenantSpec(name="repo", root=str(repo)), threading.Semaphore(1))
+
+        assert tenant.settings.cache_dir == "build/cache"
+        assert tenant.cache_dir == str(repo / "build" / "cache")
+
+    def test_status_before_first_analysis(self, tmp_path):
+        tenant = Tenant(TenantSpec(name="repo", root=str(tmp_path)), threading.Semaphore(1))
+
+        status = tenant.status()
+
+        assert status["name"] == "repo"
+        assert status["status"] == "analyzing"
+        assert status["active_analyses"] == 0
@@ -0,0 +1,135 @@
+"""Tests for API surface: exported identifiers per package against its size."""
+
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, FunctionDef
+from shannon_insight.signals.api_surface import (
+    WIDE_API_TYPE,
+    measure_surface,
+    to_findings,
+)
+
+_GO = """\
+package server
+
+const (
+\tMaxConns = 10
+\tdefaultPort = 80
+\tA, b = 1, 2
+)
+
+type (
+\tServer struct {
+\t\tAddr string
+\t}
+\thandler func()
+)
+
+var ErrClosed = errors.New("closed")
+
+func New() *Server {
+\tvar local = 1
+\treturn nil
+}
+
+func (s *Server) Start() error { return nil }
+func (s *Server) stop() {}
+func (h handler) Serve() {}
+func (s *Set[T]) Add(v T) {}
+"""
+
+
+def _go(path: str) -> FileSyntax:
+    return FileSyntax(path=path, functions=[], classes=[], imports=[], language="go")
+
+
+def _method(name: str, line: int) -> FunctionDef:
+    return FunctionDef(name, ["self"], 5, 2, 0, start_line=line, end_line=line + 1)
+
+
+class TestMeasureSurface:
+    def test_go_exported_identifiers(self):
+        report = measure_surface(
+            {"server/server.go": _go("server/server.go"), "server/x_test.go": _go("x_test.go")},
+            {"server/server.go": _GO, "server/x_test.go": "func TestX(t *testing.T) {}\n"},
+        )
+        (package,) = report.packages
+        # MaxConns, A, Server, ErrClosed, New, Start and Add; Serve's receiver is unexported
+        assert (package.exported, package.declared) == (7, 12)
+        assert package.files == ["server/server.go"]
+
+    def test_python_public_members(self):
+        content = (
+            "class Store:\n"
+            "    def get(self):\n"
+            "        pass\n"
+            "    def _load(self):\n"
+            "        pass\n"
+        )
+        syntax = FileSyntax(
+            path="pkg/store.py",
+            functions=[],
+            classes=[
+                ClassDef(
+                    "Store", [], [_method("get", 2), _method("_load", 4)], ["items", "_cache"]
+                ),
+                ClassDef("_Hidden", [], [_method("run", 6)], []),
+            ],
+            imports=[],
+            language="python",
+        )
+        report = measure_surface({"pkg/store.py": syntax}, {"pkg/store.py": content})
+        (package,) = report.packages
+        assert (package.exported, package.declared) == (2, 5)
+        assert report.signals() == (
+            {"api_surface": 2.0},
+            {"pkg": {"api_surface": 2.0, "api_surface_share": 0.4}},
+        )
+
+    def test_java_members_need_public(self):
+        content = (
+            "public class Api {\n"
+            "    public void open() {}\n"
+            "    void close() {}\n"
+            "    private void reset() {}\n"
+            "}\n"
+        )
+        syntax = FileSyntax(
+            path="api/Api.java",
+            functions=[],
+            classes=[
+                ClassDef(
+                    "Api", [], [_method("open", 2), _method("close", 3), _method("reset", 4)], []
+                )
+            ],
+            imports=[],
+            language="java",
+        )
+        (package,) = measure_surface({"api/Api.java": syntax}, {"api/Api.java": content}).packages
+        assert (package.exported, package.declared) == (1, 3)
+
+
+def _package(name: str, exported: int, lines: int) -> tuple[str, str]:
+    body = "".join(f"func F{i}() {{}}\n" for i in range(exported))
+    body += "".join(f"// line {i}\n" for i in range(lines - exported))
+    return f"{name}/{name}.go", body
+
+
+def test_wide_packages_and_findings():
+    packages = [_package("api", 40, 100), *(_package(p, 5, 500) for p in ("a", "b", "c"))]
+    contents = dict(packages)
+    report = measure_surface({path: _go(path) for path in contents}, contents)
+
+    assert report.median_per_kloc == 10.0
+    assert [p.package for p in report.wide()] == ["api"]
+    (finding,) = to_findings(report)
+    assert finding.finding_type == WIDE_API_TYPE
+    assert finding.files == ["api/api.go"]
+    assert finding.title == (
+        "api/ exports 40 identifiers in 100 lines (400 per 1000 lines, 40.0x the median package)"
+    )
+    assert 0.4 <= finding.severity <= 0.7
+
+
+def test_too_few_packages_are_not_compared():
+    contents = dict([_package("api", 40, 100), _package("a", 5, 500)])
+    report = measure_surface({path: _go(path) for path in contents}, contents)
+    assert report.wide() == []
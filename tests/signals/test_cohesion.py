@@ -0,0 +1,235 @@
+"""Tests for LCOM4 type cohesion, Go method sets included."""
+
+from shannon_insight.scanning.syntax import ClassDef
+from shannon_insight.signals.cohesion import (
+    LOW_COHESION_TYPE,
+    collect_cohesion,
+    find_low_cohesion,
+    lcom4_groups,
+    to_findings,
+)
+from tests.conftest import make_function, make_syntax
+
+_HANDLER = """package api
+
+type Handler struct {
+\tcache map[string]int
+\tdb    *DB
+}
+
+func (h *Handler) Get(k string) int {
+\treturn h.cache[k]
+}
+
+func (h *Handler) Put(k string, v int) {
+\th.cache[k] = v
+}
+
+func (h *Handler) Load(id string) error {
+\treturn h.db.Load(id)
+}
+"""
+
+_HANDLER_DB = """package api
+
+func (s *Handler) Save() error {
+\treturn s.db.Save()
+}
+
+func (h *Handler) Reload(id string) error {
+\th.Put(id, 0)
+\treturn h.Load(id)
+}
+"""
+
+_PYTHON = '''class Report:
+    """Two reports in one."""
+
+    def __init__(self, rows, chart):
+        self.rows = rows
+        self.chart = chart
+
+    def total(self):
+        return sum(self.rows)
+
+    def mean(self):
+        return self.total() / len(self.rows)
+
+    def render(self):
+        def axis():
+            return self.chart.axis
+        return self.chart.draw(axis())
+
+    @staticmethod
+    def version():
+        return 2
+'''
+
+_JAVA = """public class Account {
+    private int balance;
+    private final List<String> log = new ArrayList<>();
+
+    public void deposit(int amount) {
+        balance += amount;
+    }
+
+    public int balance() {
+        return this.balance;
+    }
+
+    public void note(String line) {
+        log.add(line);
+    }
+}
+"""
+
+
+def _go_file(path, content, functions, classes=()):
+    return make_syntax(path, functions, list(classes), language="go"), content
+
+
+def _collect(*files):
+    return collect_cohesion(
+        {syntax.path: syntax for syntax, _ in files},
+        {syntax.path: content for syntax, content in files},
+    )
+
+
+class TestLcom4Groups:
+    def test_shared_fields_and_calls_link_methods(self):
+        members = {
+            "get": {"cache"},
+            "put": {"cache"},
+            "load": {"db"},
+            "reload": {"load"},  # a call joins reload to load's group
+            "helper": set(),
+        }
+        groups = lcom4_groups(members, set(members))
+
+        assert [(g.methods, g.fields) for g in groups] == [
+            (["get", "put"], ["cache"]),
+            (["load", "reload"], ["db"]),
+        ]
+
+    def test_one_group(self):
+        groups = lcom4_groups({"a": {"x"}, "b": {"x", "y"}, "c": {"y"}}, {"a", "b", "c"})
+        assert len(groups) == 1 and groups[0].fields == ["x", "y"]
+
+
+class TestCollectCohesion:
+    def test_go_method_set_spans_package_files(self):
+        handler = _go_file(
+            "api/handler.go",
+            _HANDLER,
+            [
+                make_function("Get", start_line=8, end_line=10),
+                make_function("Put", start_line=12, end_line=14),
+                make_function("Load", start_line=16, end_line=18),
+            ],
+            [ClassDef("Handler", [], [], [])],
+        )
+        store = _go_file(
+            "api/store.go",
+            _HANDLER_DB,
+            [
+                make_function("Save", start_line=3, end_line=5),
+                make_function("Reload", start_line=7, end_line=10),
+            ],
+        )
+        # Another package
+        other = _go_file(
+            "web/store.go", _HANDLER_DB, [make_function("Save", start_line=3, end_line=5)]
+        )
+
+        (cohesion,) = _collect(handler, store, other)
+
+        # Reload calls Put and Load, tying the cache and db groups together
+        assert cohesion.name == "Handler" and cohesion.lcom4 == 1
+        assert cohesion.files == ["api/handler.go", "api/store.go"]
+        assert cohesion.groups[0].methods == ["Get", "Load", "Put", "Reload", "Save"]
+
+    def test_go_unrelated_groups(self):
+        handler = _go_file(
+            "api/handler.go",
+            _HANDLER,
+            [
+                make_function("Get", start_line=8, end_line=10),
+                make_function("Put", start_line=12, end_line=14),
+                make_function("Load", start_line=16, end_line=18),
+            ],
+            [ClassDef("Handler", [], [], [])],
+        )
+        store = _go_file(
+            "api/store.go", _HANDLER_DB, [make_function("Save", start_line=3, end_line=5)]
+        )
+
+        (cohesion,) = _collect(handler, store)
+
+        assert cohesion.lcom4 == 2
+        assert [(g.methods, g.fields) for g in cohesion.groups] == [
+            (["Get", "Put"], ["cache"]),
+            (["Load", "Save"], ["db"]),
+        ]
+
+    def test_python_methods_found_by_indentation(self):
+        # Spans as the regex fallback reports them: start line only
+        functions = [
+            make_function("__init__", start_line=4, end_line=4),
+            make_function("total", start_line=8, end_line=8),
+            make_function("mean", start_line=11, end_line=11),
+            make_function("render", start_line=14, end_line=14),
+        ]
+        functions += [
+            make_function("axis", start_line=15, end_line=15),
+            make_function("version", start_line=20, end_line=20),
+        ]
+        syntax = make_syntax("report.py", functions, [ClassDef("Report", [], [], [])])
+
+        (cohesion,) = collect_cohesion({"report.py": syntax}, {"report.py": _PYTHON})
+
+        # __init__ and the static version() are left out; axis() is part of render()
+        assert [g.methods for g in cohesion.groups] == [["mean", "total"], ["render"]]
+        assert cohesion.groups[1].fields == ["chart"]
+
+    def test_implicit_this_uses_declared_fields(self):
+        functions = [
+            make_function("deposit", start_line=5, end_line=7),
+            make_function("balance", start_line=9, end_line=11),
+            make_function("note", start_line=13, end_line=15),
+        ]
+        classes = [ClassDef("Account", [], [], [])]
+        syntax = make_syntax("Account.java", functions, classes, language="java")
+
+        (cohesion,) = collect_cohesion({"Account.java": syntax}, {"Account.java": _JAVA})
+
+        # balance() is both a field and a method name: a call, linking deposit's field
+        assert [g.methods for g in cohesion.groups] == [["balance", "deposit"], ["note"]]
+        assert cohesion.groups[1].fields == ["log"]
+
+
+class TestFindLowCohesion:
+    def test_thresholds_and_findings(self):
+        handler = _go_file(
+            "api/handler.go",
+            _HANDLER,
+            [
+                make_function("Get", start_line=8, end_line=10),
+                make_function("Put", start_line=12, end_line=14),
+                make_function("Load", start_line=16, end_line=18),
+            ],
+            [ClassDef("Handler", [], [], [])],
+        )
+        store = _go_file(
+            "api/store.go", _HANDLER_DB, [make_function("Save", start_line=3, end_line=5)]
+        )
+        types = _collect(handler, store)
+
+        assert find_low_cohesion(types) == []  # 4 methods, below the default 6
+        (low,) = find_low_cohesion(types, min_methods=4)
+
+        (finding,) = to_findings([low])
+        assert finding.finding_type == LOW_COHESION_TYPE
+        assert finding.title == "Handler has 2 unrelated method groups (LCOM4 2)"
+        assert finding.files == ["api/handler.go", "api/store.go"]
+        assert finding.evidence[2].description == "Get, Put -- cache"
+        assert finding.severity == 0.5
@@ -0,0 +1,146 @@
+"""Tests for comment density, doc comments and commented-out code."""
+
+import pytest
+
+from shannon_insight.signals.comments import (
+    CODE,
+    COMMENT,
+    COMMENTED_OUT,
+    DOCSTRING,
+    classify_lines,
+    file_comments,
+    looks_like_code,
+)
+from tests.conftest import make_function, make_syntax
+
+_PYTHON = '''import os
+
+
+# Load the settings file.
+# Falls back to the defaults.
+@cache
+def load(path):
+    """Read the file.
+
+    Returns a dict.
+    """
+    # data = os.path.join(path, "a")
+    # data = parse(data)
+    if path:
+        # prefer the environment, e.g.:
+        #   os.environ["X"] = 1
+        # then the file
+        return os.environ
+    return {}
+
+
+def bare(a):
+    if a:
+        for b in a:
+            # return cache[b]
+            yield b
+'''
+
+_GO = """package main
+
+// Run starts the server.
+// It blocks until ctx is done.
+func Run(ctx context.Context) error {
+\tif ctx == nil {
+\t\treturn nil
+\t}
+\t// srv.Close();
+\treturn serve(ctx)
+}
+
+func helper() {
+\tif x {
+\t}
+}
+"""
+
+
+class TestLooksLikeCode:
+    @pytest.mark.parametrize(
+        "text",
+        ["x = compute()", "return cache[key];", "if not ready:", "}", "foo(bar, 2)"],
+    )
+    def test_code(self, text):
+        assert looks_like_code(text)
+
+    @pytest.mark.parametrize(
+        "text",
+        [
+            "Returns the user, or None.",
+            "return early when the cache is cold",
+            "TODO: handle errors",
+            "type: ignore[attr-defined]",
+            "e.g. x = 1",
+            "Majority = more than half",
+            "Q = e_in/m - sum(sigma)",
+            "mean = (1.0 + 0) / 2 = 0.5",
+        ],
+    )
+    def test_prose_and_directives(self, text):
+        assert not looks_like_code(text)
+
+
+class TestClassifyLines:
+    def test_python_docstrings_and_strings(self):
+        lines = ['x = """', "# not a comment", '"""', '"""Doc."""', "# y = 1", "#!/bin/sh"]
+        assert classify_lines(lines, "python") == [CODE, CODE, CODE, DOCSTRING, COMMENTED_OUT, CODE]
+
+    def test_example_among_prose_stays_a_comment(self):
+        lines = ["# for instance:", "#   x = load()", "# works"]
+        assert classify_lines(lines, "python") == [COMMENT, COMMENT, COMMENT]
+
+    def test_block_comments(self):
+        lines = ["/**", " * Adds two numbers.", " */", "// a = b + c;", "#include <x.h>"]
+        assert classify_lines(lines, "c") == [COMMENT, COMMENT, COMMENT, COMMENTED_OUT, CODE]
+
+
+class TestFileComments:
+    def test_python_functions(self):
+        syntax = make_syntax(
+            "a.py",
+            [
+                make_function("load", start_line=7, end_line=19),
+                make_function("bare", start_line=22, end_line=27),
+            ],
+        )
+        result = file_comments(syntax, _PYTHON)
+
+        load, bare = result.functions
+        assert (load.doc_lines, load.comment_lines, load.commented_out_lines) == (6, 3, 2)
+        assert load.code_lines == 4 and load.documented
+        assert load.to_dict()["comment_density"] == pytest.approx(2.25)
+        assert (bare.doc_lines, bare.commented_out_lines, bare.complexity) == (0, 1, 3)
+        assert not bare.documented
+        assert result.commented_out_lines == 3
+        assert result.undocumented_complexity == 2  # bare only
+
+    def test_go_doc_comments(self):
+        syntax = make_syntax(
+            "main.go",
+            [
+                make_function("Run", start_line=5, end_line=11),
+                make_function("helper", start_line=13, end_line=16),
+            ],
+            language="go",
+        )
+        result = file_comments(syntax, _GO)
+
+        run, helper = result.functions
+        assert run.doc_lines == 2 and run.commented_out_lines == 1
+        assert not helper.documented
+        assert result.undocumented_complexity == 1
+
+    def test_documenting_lowers_undocumented_complexity(self):
+        body = "def f(x):\n    if x:\n        return 1\n    return 2\n"
+        syntax = make_syntax("f.py", [make_function(end_line=4)])
+        bare = file_comments(syntax, body)
+        documented = file_comments(syntax, body.replace("\n", '\n    """Doc."""\n', 1))
+
+        assert bare.undocumented_complexity == 1
+        assert documented.undocumented_complexity == 0
+        assert documented.comment_density > bare.comment_density == 0.0
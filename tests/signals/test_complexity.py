@@ -0,0 +1,84 @@
+"""Tests for function-size complexity normalization."""
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.scanning.syntax import FileSyntax
+from shannon_insight.signals.complexity import (
+    complexity_per_decision_point,
+    decision_costs,
+    normalized_complexity,
+)
+from tests.conftest import make_function
+
+FLAT = """def handler(req) -> Resp:
+    if req.a:
+        pass
+    if req.b:
+        pass
+    if req.c:
+        pass
+    if req.d:
+        pass
+"""
+
+TANGLED = """def tangled(x) -> int:
+    for i in x:
+        if i:
+            while i:
+                i -= 1
+"""
+
+
+def _syntax(functions, complexity=4.0):
+    return FileSyntax(
+        path="f.py",
+        functions=functions,
+        classes=[],
+        imports=[],
+        language="python",
+        _complexity=complexity,
+    )
+
+
+class TestDecisionCosts:
+    def test_flat_branches_cost_one_each(self):
+        assert decision_costs(FLAT.splitlines()) == [1, 1, 1, 1]
+
+    def test_nested_branches_cost_more(self):
+        assert decision_costs(TANGLED.splitlines()) == [1, 2, 3]
+
+    def test_comments_ignored(self):
+        assert decision_costs(["def f():", "    # if while for"]) == []
+
+
+class TestNormalizedComplexity:
+    def test_none_is_passthrough(self):
+        syntax = _syntax([make_function(end_line=200)])
+        assert normalized_complexity(syntax, FLAT, "none") == 4.0
+
+    def test_function_length_discounts_long_functions(self):
+        syntax = _syntax([make_function(end_line=100)])
+        assert normalized_complexity(syntax, None, "function_length") == pytest.approx(1.0)
+
+    def test_function_length_never_inflates_short_functions(self):
+        syntax = _syntax([make_function(end_line=5)])
+        assert normalized_complexity(syntax, None, "function_length") == 4.0
+
+    def test_decision_point_separates_flat_from_tangled(self):
+        flat = complexity_per_decision_point(_syntax([make_function(end_line=9)]), FLAT)
+        tangled = complexity_per_decision_point(_syntax([make_function(end_line=5)]), TANGLED)
+        assert flat == 1.0
+        assert tangled == 2.0
+
+    def test_decision_point_without_content_falls_back(self):
+        assert normalized_complexity(_syntax([]), None, "decision_point") == 4.0
+
+
+class TestConfig:
+    def test_default_is_none(self):
+        assert AnalysisConfig().complexity_normalization == "none"
+
+    def test_invalid_mode_rejected(self):
+        with pytest.raises(ValueError, match="complexity_normalization"):
+            AnalysisConfig(complexity_normalization="per_token")
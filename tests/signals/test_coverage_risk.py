@@ -0,0 +1,88 @@
+"""Tests for the coverage risk matrix: function complexity against line coverage."""
+
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+from shannon_insight.signals.coverage_risk import (
+    UNTESTED_COMPLEXITY_TYPE,
+    analyze_coverage_risk,
+    to_findings,
+)
+
+# parse: complexity 4 over lines 1-6; tiny: complexity 1 over lines 9-10
+_CONTENT = """\
+def parse(x):
+    if x:
+        for i in x:
+            if i and x:
+                pass
+    return x
+
+
+def tiny():
+    return 1
+"""
+
+_SYNTAX = FileSyntax(
+    path="pkg/parse.py",
+    functions=[
+        FunctionDef("parse", ["x"], 20, 3, 3, start_line=1, end_line=6),
+        FunctionDef("tiny", [], 2, 2, 0, start_line=9, end_line=10),
+    ],
+    classes=[],
+    imports=[],
+    language="python",
+)
+
+
+def _risk(coverage, **thresholds):
+    return analyze_coverage_risk(
+        {"pkg/parse.py": _SYNTAX, "pkg/other.py": _SYNTAX},
+        {"pkg/parse.py": _CONTENT, "pkg/other.py": _CONTENT},
+        coverage,
+        **thresholds,
+    )
+
+
+class TestAnalyzeCoverageRisk:
+    def test_matrix_and_hotspots(self):
+        # parse runs 1 of 4 instrumented lines; tiny runs its only one
+        coverage = {"pkg/parse.py": {1: 1, 2: 0, 3: 0, 6: 0, 10: 3}}
+        risk = _risk(coverage, complexity_threshold=4, coverage_threshold=0.5)
+
+        assert risk.unmeasured == 2  # pkg/other.py is not in the report
+        assert risk.matrix() == {"high": {"low": 1, "high": 0}, "low": {"low": 0, "high": 1}}
+        (hotspot,) = risk.hotspots()
+        assert (hotspot.function.name, hotspot.function.complexity) == ("parse", 4)
+        assert (hotspot.covered, hotspot.instrumented, hotspot.risk) == (1, 4, 3.0)
+        assert risk.line_coverage() == 2 / 5
+
+    def test_covered_complexity_is_not_a_hotspot(self):
+        coverage = {"pkg/parse.py": {1: 1, 2: 1, 3: 0, 6: 1}}
+        risk = _risk(coverage, complexity_threshold=4)
+        assert risk.hotspots() == []
+        assert risk.matrix()["high"] == {"low": 0, "high": 1}
+
+    def test_signals_and_dict(self):
+        risk = _risk({"pkg/parse.py": {1: 0, 10: 1}}, complexity_threshold=4)
+        assert risk.signals() == (
+            {"line_coverage": 0.5, "untested_complex_functions": 1.0},
+            {"pkg": {"line_coverage": 0.5, "untested_complex_functions": 1.0}},
+        )
+        data = risk.to_dict()
+        assert data["matrix"]["high_complexity"] == {"low_coverage": 1, "high_coverage": 0}
+        assert data["hotspots"][0]["name"] == "parse"
+
+    def test_no_report_no_signals(self):
+        risk = _risk({})
+        assert risk.functions == [] and risk.unmeasured == 4
+        assert risk.signals() == ({}, {})
+        assert risk.line_coverage() is None
+
+
+def test_to_findings():
+    risk = _risk({"pkg/parse.py": {1: 0, 2: 0}}, complexity_threshold=4)
+    (finding,) = to_findings(risk)
+    assert finding.finding_type == UNTESTED_COMPLEXITY_TYPE
+    assert finding.files == ["pkg/parse.py"]
+    assert finding.identity_hint == "parse"
+    assert finding.title == "parse at pkg/parse.py:1: complexity 4, 0% of lines covered"
+    assert 0.3 < finding.severity <= 0.8
@@ -0,0 +1,147 @@
+"""Tests for likely dead code detection."""
+
+from shannon_insight.scanning.syntax import ClassDef
+from shannon_insight.signals.dead_code import (
+    DEAD_CODE_TYPE,
+    find_dead_code,
+    to_findings,
+)
+from tests.conftest import make_function, make_syntax
+
+
+def _file(path, functions, language="python", classes=None):
+    return make_syntax(path, functions, classes or [], language=language)
+
+
+def _dead(files, contents, **kwargs):
+    return {d.name: d for d in find_dead_code(files, contents, **kwargs)}
+
+
+APP = """\
+def main():
+    run(load())
+
+def load():
+    return read_config()
+
+def _old_loader():
+    pass
+
+def render_legacy():
+    pass
+
+HANDLERS = {"on_event": None}  # on_event is looked up by name
+def on_event():
+    pass
+"""
+
+
+def _app(calls=None):
+    calls = calls or {}
+    return {
+        "app.py": _file(
+            "app.py",
+            [
+                make_function("main", calls=calls.get("main")),
+                make_function("load", start_line=4, calls=calls.get("load")),
+                make_function("_old_loader", start_line=7, calls=calls.get("_old_loader")),
+                make_function("render_legacy", start_line=10, calls=calls.get("render_legacy")),
+                make_function("on_event", start_line=14, calls=calls.get("on_event")),
+            ],
+        ),
+        "util.py": _file("util.py", [make_function("read_config")]),
+    }
+
+
+class TestFindDeadCode:
+    def test_unreferenced_functions_with_confidence(self):
+        files = _app()
+        contents = {"app.py": APP, "util.py": "def read_config():\n    return {}\n"}
+        dead = _dead(files, contents)
+
+        assert set(dead) == {"_old_loader", "render_legacy"}
+        assert (dead["_old_loader"].confidence, dead["_old_loader"].line) == ("high", 7)
+        # main is an entry point, so this is an application
+        assert dead["render_legacy"].confidence == "medium"
+        assert list(dead) == ["_old_loader", "render_legacy"]
+
+    def test_call_graph_callers_count_as_references(self):
+        # render_legacy is only mentioned in its own file, but the call
+        # graph resolves a call to it
+        files = _app({"main": ["render_legacy"]})
+        contents = {"app.py": APP, "util.py": "def read_config():\n    return {}\n"}
+        assert set(_dead(files, contents)) == {"_old_loader"}
+
+    def test_libraries_decorators_and_overrides_are_low(self):
+        source = (
+            "class Plugin(Base):\n    def setup(self):\n        pass\n\n"
+            "@hook\ndef after_load():\n    pass\n\ndef public_api():\n    pass\n"
+        )
+        files = {
+            "lib.py": _file(
+                "lib.py",
+                [
+                    make_function("after_load", start_line=5, decorators=["hook"]),
+                    make_function("public_api", start_line=9),
+                ],
+                classes=[ClassDef("Plugin", ["Base"], [make_function("setup", start_line=2)], [])],
+            )
+        }
+        dead = _dead(files, {"lib.py": source})
+
+        assert {name: d.confidence for name, d in dead.items()} == {
+            "setup": "low",
+            "after_load": "low",
+            "public_api": "low",
+        }
+        assert dead["public_api"].reason == "exported by a library"
+
+    def test_exports_by_language(self):
+        go = "package store\n\nfunc Save() {}\n\nfunc flush() {}\n"
+        ts = "export function render() {}\nfunction helper() {}\n"
+        files = {
+            "store.go": _file(
+                "store.go",
+                [make_function("Save", start_line=3), make_function("flush", start_line=5)],
+                "go",
+            ),
+            "view.ts": _file(
+                "view.ts",
+                [make_function("render"), make_function("helper", start_line=2)],
+                "typescript",
+            ),
+        }
+        dead = _dead(files, {"store.go": go, "view.ts": ts})
+
+        assert {name: d.exported for name, d in dead.items()} == {
+            "Save": True,
+            "flush": False,
+            "render": True,
+            "helper": False,
+        }
+
+    def test_tests_dunders_and_entry_points_are_never_dead(self):
+        files = {
+            "tests/test_app.py": _file("tests/test_app.py", [make_function("helper")]),
+            "fixtures.py": _file("fixtures.py", [make_function("make_user")]),
+            "model.py": _file(
+                "model.py",
+                [make_function("__repr__"), make_function("serve", decorators=["app.get"])],
+            ),
+        }
+        contents = {path: "" for path in files}
+        roles = {"fixtures.py": "test"}
+        assert find_dead_code(files, contents, roles=roles) == []
+
+
+def test_to_findings():
+    files = _app()
+    contents = {"app.py": APP, "util.py": "def read_config():\n    return {}\n"}
+    high, medium = to_findings(find_dead_code(files, contents))
+
+    assert high.finding_type == DEAD_CODE_TYPE
+    assert high.title == "_old_loader at app.py:7 is likely dead code"
+    assert (high.confidence, medium.confidence) == (0.9, 0.6)
+    assert high.severity > medium.severity
+    assert high.evidence[0].description == "high confidence: private and never referenced"
+    assert medium.suggestion.startswith("Check for callers outside the repository")
@@ -0,0 +1,101 @@
+"""Tests for token-based clone classes and per-package duplication."""
+
+from shannon_insight.scanning.syntax import FileSyntax
+from shannon_insight.signals.duplication import (
+    CODE_CLONE_TYPE,
+    analyze_duplication,
+    clone_tokens,
+    find_clone_classes,
+    to_findings,
+)
+
+BODY = """\
+def load_orders(client, limit):
+    rows = client.fetch("orders", limit=limit)
+    result = []
+    for row in rows:
+        if row.get("status") == "open" and row["total"] > 10:
+            result.append({"id": row["id"], "total": row["total"] * 1.2})
+        elif row.get("status") == "held":
+            client.release(row["id"], reason="stale")
+    return sorted(result, key=lambda item: item["total"])
+"""
+
+RENAMED = (
+    BODY.replace("load_orders", "load_invoices")
+    .replace("orders", "invoices")
+    .replace("rows", "records")
+    .replace("1.2", "0.8")
+)
+
+
+def _corpus(files):
+    syntax = {path: FileSyntax(path, [], [], [], "python") for path in files}
+    return syntax, files
+
+
+def _classes(files, **kwargs):
+    streams = {path: clone_tokens(text, "python") for path, text in files.items()}
+    return find_clone_classes(streams, **kwargs)
+
+
+class TestCloneTokens:
+    def test_comments_and_preamble_are_left_out(self):
+        text = "import os\nfrom a import b\n# note\nx = os.path  # trailing\n"
+        normalized, raw, lines = clone_tokens(text, "python")
+
+        assert raw == ["x", "=", "os", ".", "path"]
+        assert normalized == ["<id>", "=", "<id>", ".", "<id>"]
+        assert lines == [4] * 5
+
+
+class TestFindCloneClasses:
+    def test_identical_copies_are_type_1(self):
+        (clone,) = _classes({"a.py": "x = 1\n\n" + BODY, "b.py": BODY})
+
+        assert clone.clone_type == 1
+        assert [f.location for f in clone.fragments] == ["a.py:3-11", "b.py:1-9"]
+        assert clone.files == ["a.py", "b.py"]
+
+    def test_renamed_copies_are_type_2(self):
+        (clone,) = _classes({"a.py": BODY, "b.py": RENAMED, "c.py": BODY})
+
+        assert clone.clone_type == 2
+        assert len(clone.fragments) == 3 and clone.lines == 9
+
+    def test_short_copies_and_tables_are_not_clones(self):
+        table = "".join(f'NAMES.append("name{i}")\n' for i in range(40))
+        assert _classes({"a.py": BODY, "b.py": BODY}, min_tokens=500) == []
+        assert _classes({"a.py": table, "b.py": table}) == []
+        # A table matching itself one row down is not a clone of itself
+        assert _classes({"a.py": table + table}) == []
+
+    def test_copies_in_one_file(self):
+        (clone,) = _classes({"a.py": BODY + "\n" + RENAMED})
+        assert [f.location for f in clone.fragments] == ["a.py:1-9", "a.py:11-19"]
+
+    def test_test_only_classes_are_left_out(self):
+        files = {"tests/test_a.py": BODY, "tests/test_b.py": BODY}
+        assert _classes(files, roles={p: "test" for p in files}) == []
+        assert len(_classes(files, roles={"tests/test_a.py": "test"})) == 1
+
+
+class TestAnalyzeDuplication:
+    def test_package_share_and_findings(self):
+        filler = "".join(f"value_{i} = compute({i})\n" for i in range(9))
+        syntax, contents = _corpus(
+            {"billing/a.py": BODY, "orders/b.py": filler + RENAMED, "orders/c.py": filler}
+        )
+        report = analyze_duplication(syntax, contents)
+
+        shares = {p.package: (p.lines, p.duplicated_lines) for p in report.packages}
+        assert shares == {"billing": (10, 9), "orders": (29, 9)}
+        assert report.packages[0].package == "billing"
+        assert report.duplication_ratio == 18 / 39
+        assert report.signals()[1]["orders"]["duplicated_lines"] == 9.0
+
+        (finding,) = to_findings(report)
+        assert finding.finding_type == CODE_CLONE_TYPE
+        assert finding.title == "9 lines of code are repeated in 2 places (type-2 clone)"
+        assert finding.evidence[1].description == "billing/a.py:1-9, orders/b.py:10-18"
+        assert finding.identity_hint == report.classes[0].digest
@@ -0,0 +1,100 @@
+"""Tests for function fan-in and fan-out in the call graph."""
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.signals.function_fan import (
+    LOAD_BEARING_TYPE,
+    ORCHESTRATOR_TYPE,
+    collect_fan,
+    find_load_bearing,
+    find_orchestrators,
+    to_findings,
+)
+from tests.conftest import make_function, make_syntax
+
+
+def _files():
+    """util.py:log called by every handler; main.py:run calls every handler."""
+    handlers = [
+        make_function(f"handle_{i}", start_line=10 * i + 1, calls=["util.log"]) for i in range(4)
+    ]
+    run = make_function(
+        "run", calls=[f"handlers.handle_{i}" for i in range(4)] + ["util.log", "print"]
+    )
+    return {
+        "util.py": make_syntax("util.py", [make_function("log", calls=[])]),
+        "handlers.py": make_syntax("handlers.py", handlers),
+        "main.py": make_syntax("main.py", [run]),
+    }
+
+
+IMPORTS = {"main.py": ["handlers.py", "util.py"], "handlers.py": ["util.py"]}
+
+
+def _by_name(functions):
+    return {fn.name: fn for fn in functions}
+
+
+class TestCollectFan:
+    def test_distinct_callers_and_callees(self):
+        fans = _by_name(collect_fan(_files(), IMPORTS))
+        assert (fans["log"].fan_in, fans["log"].fan_out) == (5, 0)
+        assert fans["log"].caller_files == 2
+        # print is not defined in the codebase, so is not counted
+        assert (fans["run"].fan_in, fans["run"].fan_out) == (0, 5)
+        assert fans["run"].callee_files == 2
+        assert (fans["handle_0"].fan_in, fans["handle_0"].fan_out) == (1, 1)
+
+    def test_repeated_calls_count_once(self):
+        files = {
+            "a.py": make_syntax(
+                "a.py",
+                [
+                    make_function(calls=["g", "g", "self.g"]),
+                    make_function("g", start_line=10, calls=[]),
+                ],
+            )
+        }
+        fans = _by_name(collect_fan(files, {}))
+        assert fans["f"].fan_out == 1
+        assert fans["g"].fan_in == 1
+
+    def test_regex_parsed_files_have_no_edges(self):
+        files = {"a.py": make_syntax("a.py", [make_function()])}
+        [f] = collect_fan(files, {})
+        assert (f.fan_in, f.fan_out) == (0, 0)
+
+
+class TestFindings:
+    def test_thresholds_pick_each_category(self):
+        functions = collect_fan(_files(), IMPORTS)
+        assert [fn.name for fn in find_load_bearing(functions, threshold=4)] == ["log"]
+        assert [fn.name for fn in find_orchestrators(functions, threshold=4)] == ["run"]
+        assert find_load_bearing(functions, threshold=5) == []
+
+    def test_separate_finding_types(self):
+        functions = collect_fan(_files(), IMPORTS)
+        load_bearing, orchestrator = to_findings(
+            find_load_bearing(functions, 4), find_orchestrators(functions, 4), 4, 4
+        )
+        assert load_bearing.finding_type == LOAD_BEARING_TYPE
+        assert load_bearing.title == "log at util.py:1 is called by 5 functions (threshold 4)"
+        assert load_bearing.files == ["util.py"]
+        assert load_bearing.severity == pytest.approx(0.35)
+        assert load_bearing.identity_hint == "log"
+        assert orchestrator.finding_type == ORCHESTRATOR_TYPE
+        assert orchestrator.title == "run at main.py:1 calls 5 functions (threshold 4)"
+        evidence = {e.signal: e.value for e in orchestrator.evidence}
+        assert evidence == {"fan_out": 5.0, "fan_in": 0.0}
+
+    def test_severity_capped(self):
+        functions = collect_fan(_files(), IMPORTS)
+        [finding] = to_findings(find_load_bearing(functions, 1), [], 1, 1)
+        assert finding.severity == 0.7
+
+    def test_thresholds_must_be_positive(self):
+        with pytest.raises(ValueError, match="fan_in_threshold"):
+            AnalysisConfig(fan_in_threshold=0)
+        with pytest.raises(ValueError, match="fan_out_threshold"):
+            AnalysisConfig(fan_out_threshold=0)
@@ -0,0 +1,118 @@
+"""Tests for function complexity outliers and their reference examples."""
+
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+from shannon_insight.signals.function_outliers import (
+    MIN_FUNCTIONS,
+    ComplexityBands,
+    FunctionSample,
+    collect_functions,
+    find_function_outliers,
+    function_complexity,
+    to_findings,
+)
+
+
+def _typical_repo() -> list[FunctionSample]:
+    """Functions of 10-49 lines with complexity growing gently with size."""
+    return [
+        FunctionSample(path=f"m{n}.py", name=f"f{n}", line=1, lines=n, complexity=2 + n // 10)
+        for n in range(10, 50)
+    ]
+
+
+class TestFunctionComplexity:
+    def test_counts_decision_points(self):
+        block = [
+            "def f(x):",
+            "    # if this comment counted, complexity would be off",
+            "    if x and y:",
+            "        for i in x:",
+            "            pass",
+            "    return a if b else c",
+        ]
+        assert function_complexity(block) == 4
+
+    def test_collect_uses_function_span(self):
+        content = "def a():\n    return 1\n\ndef b(x):\n    if x:\n        return 2\n"
+        syntax = FileSyntax(
+            path="f.py",
+            functions=[
+                FunctionDef("a", [], 2, 2, 0, start_line=1, end_line=2),
+                FunctionDef("b", ["x"], 6, 3, 1, start_line=4, end_line=6),
+                FunctionDef("broken", [], 1, 1, 0, start_line=9, end_line=12),
+            ],
+            classes=[],
+            imports=[],
+            language="python",
+        )
+
+        samples = collect_functions({"f.py": syntax}, {"f.py": content})
+
+        assert [(s.name, s.lines, s.complexity) for s in samples] == [("a", 2, 1), ("b", 3, 2)]
+
+
+class TestFindFunctionOutliers:
+    def test_flags_complex_function_with_references(self):
+        tangled = FunctionSample(path="core.py", name="parse", line=7, lines=30, complexity=41)
+
+        outliers = find_function_outliers(_typical_repo() + [tangled])
+
+        assert [o.function for o in outliers] == [tangled]
+        outlier = outliers[0]
+        assert outlier.typical_complexity == 5
+        assert sorted(r.lines for r in outlier.references) == [29, 30, 31]
+        assert "typically have complexity 5, this has 41" in outlier.explanation
+
+    def test_references_skip_other_outliers(self):
+        a = FunctionSample(path="a.py", name="a", line=1, lines=30, complexity=41)
+        b = FunctionSample(path="b.py", name="b", line=1, lines=30, complexity=45)
+
+        outliers = find_function_outliers(_typical_repo() + [a, b])
+
+        assert {o.function.name for o in outliers} == {"a", "b"}
+        for outlier in outliers:
+            assert all(r.name not in ("a", "b") for r in outlier.references)
+
+    def test_long_functions_judged_against_long_functions(self):
+        # Complexity 12 is high for 10-line functions but normal at 200 lines
+        long_ones = [
+            FunctionSample(path=f"l{n}.py", name=f"l{n}", line=1, lines=n, complexity=12)
+            for n in range(200, 230)
+        ]
+
+        assert find_function_outliers(_typical_repo() + long_ones) == []
+
+    def test_streams_with_bands_from_a_first_pass(self):
+        tangled = FunctionSample(path="core.py", name="parse", line=7, lines=30, complexity=41)
+        samples = _typical_repo() + [tangled]
+        bands = ComplexityBands()
+        for sample in samples:
+            bands.add(sample.lines, sample.complexity)
+
+        streamed = find_function_outliers(iter(samples), bands=bands)
+
+        assert streamed == find_function_outliers(samples)
+        assert bands.functions == len(samples)
+
+    def test_small_repos_skipped(self):
+        samples = _typical_repo()[: MIN_FUNCTIONS - 2]
+        samples.append(FunctionSample(path="x.py", name="x", line=1, lines=30, complexity=90))
+
+        assert find_function_outliers(samples) == []
+
+
+class TestToFindings:
+    def test_finding_carries_explanation_and_references(self):
+        tangled = FunctionSample(path="core.py", name="parse", line=7, lines=30, complexity=41)
+        outliers = find_function_outliers(_typical_repo() + [tangled])
+
+        (finding,) = to_findings(outliers)
+
+        assert finding.finding_type == "complexity_outlier"
+        assert finding.files == ["core.py"]
+        assert finding.identity_hint == "parse"
+        assert "this has 41" in finding.title
+        references = [e for e in finding.evidence if e.signal == "reference"]
+        assert len(references) == 3
+        assert "30 lines, complexity 5" in references[0].description
+        assert 0.4 < finding.severity <= 0.8
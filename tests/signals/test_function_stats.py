@@ -0,0 +1,71 @@
+"""Tests for streaming per-package function statistics."""
+
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+from shannon_insight.signals.function_stats import FunctionStats, aggregate_functions
+
+
+def _file(path, spans):
+    functions = [FunctionDef(f"f{i}", [], 10, 3, 1, s, e) for i, (s, e) in enumerate(spans)]
+    return FileSyntax(path, functions, [], [], "python")
+
+
+SIMPLE = "def f0():\n    return 1\n"
+BRANCHY = (
+    "def f0(x):\n"
+    "    if x:\n"
+    "        return 1\n"
+    "    for i in x:\n"
+    "        if i and x:\n"
+    "            return i\n"
+    "    return 0\n"
+)
+
+
+def _triples():
+    return [
+        ("api/a.py", _file("api/a.py", [(1, 2)]), SIMPLE),
+        ("api/b.py", _file("api/b.py", [(1, 7)]), BRANCHY),
+        ("core/c.py", _file("core/c.py", [(1, 2), (9, 12)]), SIMPLE),  # second span is past EOF
+    ]
+
+
+def test_counters_and_percentiles_per_package():
+    stats = aggregate_functions(_triples())
+    api = stats.packages["api"]
+
+    assert (api.functions, api.complexity, api.max_complexity) == (2, 5, 4)
+    assert stats.packages["core"].functions == 1
+
+    global_signals, packages = stats.signals()
+    assert global_signals["function_complexity_p50"] == 1.0
+    assert packages["api"]["function_count"] == 2.0
+    assert packages["api"]["function_complexity_mean"] == 2.5
+    assert packages["api"]["function_lines_p90"] == 7.0
+
+
+def test_partial_aggregates_merge():
+    first, second = FunctionStats(), FunctionStats()
+    for n, (path, syntax, content) in enumerate(_triples()):
+        (first if n % 2 else second).add_file(path, syntax, content)
+    first.merge(second)
+
+    assert first.signals() == aggregate_functions(_triples()).signals()
+    assert first.bands.functions == 3
+    assert FunctionStats().signals() == ({}, {})
+
+
+def test_size_inequality_and_longest_function():
+    body = "def f():\n" + "    x = 1\n" * 900
+    even = _file("even/a.py", [(1, 100), (101, 200), (201, 300), (301, 400)])
+    lopsided = _file("big/a.py", [(1, 800), (801, 820), (821, 840), (841, 860)])
+    stats = aggregate_functions([("even/a.py", even, body), ("big/a.py", lopsided, body)])
+
+    assert stats.packages["even"].size_gini == 0.0
+    big = stats.packages["big"]
+    assert (big.lines, big.max_lines, big.longest) == (860, 800, "f0 (big/a.py:1)")
+    assert round(big.size_gini, 3) == 0.907
+    assert round(big.longest_share, 3) == 0.930
+
+    _, packages = stats.signals()
+    assert packages["big"]["function_lines_p50"] == 20.0
+    assert packages["even"]["longest_function_share"] == 0.25
@@ -8,14 +8,19 @@ Tests for:
 5. Laplacian Δh > 0 for bad file in good neighborhood
 6. ABSOLUTE tier skips composites
 7. Backward compat: Primitives.from_file_signals()
+8. Configurable codebase_health formula
 """
 
+import logging
+
+import pytest
+
 from shannon_insight.config import AnalysisConfig
 from shannon_insight.environment import Environment
 from shannon_insight.insights.store import AnalysisStore
 from shannon_insight.math.gini import Gini
 from shannon_insight.session import AnalysisSession, Tier
-from shannon_insight.signals.composites import compute_composites
+from shannon_insight.signals.composites import compute_composites, parse_health_formula
 from shannon_insight.signals.display import to_display_scale
 from shannon_insight.signals.fusion import FusionPipeline, build
 from shannon_insight.signals.health_laplacian import (
@@ -148,6 +153,9 @@ class MockFileAnalysis:
         community_id: int = 0,
         compression_ratio: float = 0.5,
         cognitive_load: float = 15.0,
+        halstead_volume: float = 400.0,
+        halstead_difficulty: float = 12.0,
+        halstead_effort: float = 4800.0,
     ):
         self.path = path
         self.pagerank = pagerank
@@ -161,6 +169,9 @@ class MockFileAnalysis:
         self.community_id = community_id
         self.compression_ratio = compression_ratio
         self.cognitive_load = cognitive_load
+        self.halstead_volume = halstead_volume
+        self.halstead_difficulty = halstead_difficulty
+        self.halstead_effort = halstead_effort
 
 
 class MockGraph:
@@ -591,6 +602,93 @@ class TestAbsoluteTierSkipsComposites:
         assert fs.wiring_quality == original_wiring
 
 
+class TestHealthFormula:
+    """codebase_health follows the configured formula."""
+
+    def _field(self):
+        field = SignalField(tier=Tier.FULL)
+        for path, lines, nesting, trajectory in [
+            ("/a.py", 300, 6, "CHURNING"),
+            ("/b.py", 100, 2, "STABLE"),
+        ]:
+            fs = FileSignals(
+                path=path, lines=lines, max_nesting=nesting, churn_trajectory=trajectory
+            )
+            fs.percentiles = {"pagerank": 0.5}
+            field.per_file[path] = fs
+        field.global_signals.duplication_ratio = 0.05
+        return field
+
+    def test_parse(self):
+        assert parse_health_formula("health = 0.4*nesting + 0.3*duplication + .3*churn") == {
+            "nesting": 0.4,
+            "duplication": 0.3,
+            "churn": 0.3,
+        }
+        assert parse_health_formula("nesting + 2*churn") == {"nesting": 1.0, "churn": 2.0}
+
+    @pytest.mark.parametrize(
+        "formula", ["0.5*speed", "risk = 1*churn", "0.5 nesting", "0*churn", "nesting +"]
+    )
+    def test_invalid_formulas_rejected_by_config(self, formula):
+        with pytest.raises(ValueError, match="health_formula"):
+            AnalysisConfig(health_formula=formula)
+
+    def test_custom_formula(self):
+        field = self._field()
+        compute_composites(field, "0.4*nesting + 0.3*duplication + 0.3*churn")
+        # 100 of 400 lines nest at most 4 deep, 20% of the duplication ceiling, 1 of 2 calm
+        expected = 0.4 * 0.25 + 0.3 * 0.8 + 0.3 * 0.5
+        assert field.global_signals.codebase_health == pytest.approx(expected)
+
+    def test_absolute_tier_applies_formula(self):
+        field = self._field()
+        field.tier = Tier.ABSOLUTE
+        compute_composites(field, "0.4*nesting + 0.3*duplication + 0.3*churn")
+        expected = 0.4 * 0.25 + 0.3 * 0.8 + 0.3 * 0.5
+        assert field.global_signals.codebase_health == pytest.approx(expected)
+
+    def test_absolute_tier_leaves_out_percentile_terms(self, caplog):
+        field = self._field()
+        field.tier = Tier.ABSOLUTE
+        with caplog.at_level(logging.WARNING):
+            compute_composites(field, "architecture + 2*duplication + 2*churn")
+        assert field.global_signals.codebase_health == pytest.approx(0.65)
+        assert "architecture not available below 15 files" in caplog.text
+
+    def test_absolute_tier_without_formula_leaves_health_unset(self):
+        field = self._field()
+        field.tier = Tier.ABSOLUTE
+        compute_composites(field)
+        assert field.global_signals.codebase_health == 0.0
+
+    def test_weights_are_scaled_to_one(self):
+        field = self._field()
+        compute_composites(field, "2*duplication + 2*churn")
+        assert field.global_signals.codebase_health == pytest.approx(0.65)
+
+    def test_file_risk_keeps_its_own_weights(self):
+        default, custom = self._field(), self._field()
+        compute_composites(default)
+        compute_composites(custom, "nesting")
+        for path, fs in custom.per_file.items():
+            assert fs.risk_score == default.per_file[path].risk_score
+            assert fs.file_health_score == default.per_file[path].file_health_score
+
+    def test_default_formula(self):
+        field = self._field()
+        compute_composites(field)
+        g = field.global_signals
+        expected = (
+            0.25 * g.architecture_health
+            + 0.25 * g.wiring_score
+            + 0.20 * 1.0  # bus factor 1 of a team of 1
+            + 0.15 * g.modularity
+            + 0.15 * 0.8
+        )
+        assert g.codebase_health == pytest.approx(expected)
+
+
 class TestBackwardCompatibility:
     """Test backward compatibility with Primitives class."""
 
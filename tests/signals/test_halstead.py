@@ -0,0 +1,81 @@
+"""Tests for Halstead volume, difficulty and effort."""
+
+import math
+
+from shannon_insight.graph.engine import AnalysisEngine
+from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+from shannon_insight.signals.halstead import Halstead, function_halstead, halstead, tokens
+
+
+class TestTokens:
+    def test_keywords_and_symbols_are_operators(self):
+        assert list(tokens("if (a >= 10) return a;", "java")) == [
+            ("operator", "if"),
+            ("operator", "("),
+            ("operand", "a"),
+            ("operator", ">="),
+            ("operand", "10"),
+            ("operator", "return"),
+            ("operand", "a"),
+            ("operator", ";"),
+        ]
+
+    def test_comments_dropped_and_strings_kept_whole(self):
+        code = 'url = "http://x # y"  # note\n'
+        assert list(tokens(code, "python")) == [
+            ("operand", "url"),
+            ("operator", "="),
+            ("operand", '"http://x # y"'),
+        ]
+        assert list(tokens("x = 1; // a /* b */\n/* c */", "go")) == [
+            ("operand", "x"),
+            ("operator", "="),
+            ("operand", "1"),
+            ("operator", ";"),
+        ]
+
+
+class TestHalstead:
+    def test_counts_and_derived_metrics(self):
+        # operators: = (2), + (1), ; (2) -> n1=3, N1=5
+        # operands: a (2), b (1), 1 (1), c (1) -> n2=4, N2=5
+        counts = halstead("a = b + 1; c = a;", "c")
+        assert counts == Halstead(3, 4, 5, 5)
+        assert counts.volume == 10 * math.log2(7)
+        assert counts.difficulty == (3 / 2) * (5 / 4)
+        assert counts.effort == counts.difficulty * counts.volume
+
+    def test_empty_code_scores_zero(self):
+        counts = halstead("", "python")
+        assert (counts.volume, counts.difficulty, counts.effort) == (0.0, 0.0, 0.0)
+
+    def test_function_halstead_uses_function_span(self):
+        content = "def a():\n    return 1\n\ndef b(x, y):\n    return x * y + x\n"
+        syntax = FileSyntax(
+            path="f.py",
+            language="python",
+            functions=[
+                FunctionDef("b", ["x", "y"], 6, 3, 0, start_line=4, end_line=5),
+                FunctionDef("a", [], 2, 2, 0, start_line=1, end_line=2),
+            ],
+            classes=[],
+            imports=[],
+        )
+        results = function_halstead(syntax, content)
+        assert [fn.name for fn, _ in results] == ["a", "b"]
+        assert results[0][1] == halstead("def a():\n    return 1", "python")
+        assert results[1][1].operands == 6  # b, x, y, x, y, x
+
+
+class TestEngineOutliers:
+    def test_halstead_effort_outlier_reported(self):
+        contents = {
+            f"m{i}.py": "".join(f"x{j} = y{j} + {j}\n" for j in range(i + 1)) for i in range(6)
+        }
+        contents["big.py"] = "\n".join(
+            f"v{i} = (a{i} + b{i}) * c{i} - d{i} / e{i}" for i in range(200)
+        )
+        files = [FileSyntax(path, [], [], [], "python") for path in contents]
+        result = AnalysisEngine(files, content_getter=contents.get).run()
+        assert result.files["big.py"].halstead_effort > result.files["m0.py"].halstead_effort
+        assert any("Halstead effort" in reason for reason in result.outliers["big.py"])
@@ -0,0 +1,97 @@
+"""Tests for repo-level information density."""
+
+import random
+
+import pytest
+
+from shannon_insight.scanning.syntax import FileSyntax
+from shannon_insight.signals.information_density import (
+    analyze_density,
+    heaps_exponent,
+    vocabulary_curve,
+)
+
+
+def _corpus(files):
+    syntax = {path: FileSyntax(path, [], [], [], "python") for path in files}
+    return syntax, files
+
+
+def _module(seed, words=300):
+    """A module of assignments over its own random identifiers."""
+    rng = random.Random(seed)
+    names = [f"name_{seed}_{rng.randrange(10**6)}" for _ in range(words)]
+    return "\n".join(f"{a} = {b}({c})" for a, b, c in zip(names, names[1:], names[2:])) + "\n"
+
+
+class TestVocabularyCurve:
+    def test_checkpoints_are_log_spaced_and_end_at_total(self):
+        names = [f"n{i % 50}" for i in range(10_000)]
+        curve = vocabulary_curve(names, points=10)
+
+        assert curve[-1] == (10_000, 50)
+        assert [n for n, _ in curve] == sorted({round(10_000 ** (k / 10)) for k in range(1, 11)})
+
+    def test_heaps_exponent(self):
+        # distinct = read ** 0.5 exactly
+        curve = [(n * n, n) for n in (10, 20, 40, 80, 160)]
+        assert heaps_exponent(curve) == pytest.approx(0.5)
+        assert heaps_exponent(curve[:2]) is None
+
+    def test_all_new_names_grow_linearly(self):
+        names = [f"n{i}" for i in range(5_000)]
+        assert heaps_exponent(vocabulary_curve(names)) == pytest.approx(1.0)
+
+
+class TestAnalyzeDensity:
+    def test_copied_files_are_redundant_across_files(self):
+        original = _module(1)
+        syntax, contents = _corpus({"a/one.py": original, "b/two.py": original})
+        copied = analyze_density(syntax, contents)
+
+        syntax, contents = _corpus({"a/one.py": original, "b/two.py": _module(2)})
+        distinct = analyze_density(syntax, contents)
+
+        assert copied.cross_file_redundancy > 0.4
+        assert distinct.cross_file_redundancy < 0.1
+        # Per-file compression cannot see the copy
+        assert copied.compression_ratio == pytest.approx(distinct.compression_ratio, abs=0.05)
+
+    def test_band_against_typical_source(self):
+        syntax, contents = _corpus({"gen.py": "x = [0, 0, 0, 0]\n" * 500})
+        report = analyze_density(syntax, contents)
+
+        assert report.band == "repetitive"
+        assert report.to_dict()["band"] == "repetitive"
+
+    def test_packages_and_signals(self):
+        files = {
+            "core/db.py": _module(3) + _module(4),
+            "core/models.py": _module(5),
+            "tables.py": "ROWS = [\n" + "    (0, 0),\n" * 400 + "]\n",
+        }
+        syntax, contents = _corpus(files)
+        report = analyze_density(syntax, contents)
+
+        packages = {p.package: p for p in report.packages}
+        assert (packages["core"].files, packages["core"].lines) == (2, 894)
+        assert report.packages[0].package == "core"  # more bits per line than the table
+        assert packages["core"].vocabulary_exponent is not None
+        assert packages["."].vocabulary_exponent is None  # too few identifiers
+
+        global_signals, package_signals = report.signals()
+        assert set(global_signals) == {
+            "corpus_compression_ratio",
+            "cross_file_redundancy",
+            "information_density",
+            "vocabulary_exponent",
+        }
+        assert set(package_signals["."]) == {"information_density"}
+        assert package_signals["core"]["information_density"] == pytest.approx(
+            packages["core"].information_density
+        )
+
+    def test_empty(self):
+        report = analyze_density({}, {})
+        assert report.signals() == ({}, {})
+        assert report.to_dict()["vocabulary_curve"] == []
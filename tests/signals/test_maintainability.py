@@ -0,0 +1,64 @@
+"""Tests for the Maintainability Index."""
+
+import math
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.signals.maintainability import (
+    file_maintainability,
+    file_measurements,
+    maintainability_index,
+    package_maintainability,
+    resolve_weights,
+)
+
+
+class TestMaintainabilityIndex:
+    def test_published_formula(self):
+        raw = (
+            171
+            - 5.2 * math.log(1000)
+            - 0.23 * 10
+            - 16.2 * math.log(100)
+            + 50 * math.sin(math.sqrt(2.4 * math.radians(20)))
+        )
+        assert maintainability_index(1000, 10, 100, 0.2) == pytest.approx(raw * 100 / 171)
+
+    def test_clamped_to_0_100(self):
+        assert maintainability_index(1e9, 500, 50_000, 0.0) == 0.0
+        assert maintainability_index(0, 1, 1, 0.5) == 100.0
+        assert maintainability_index(500, 3, 0, 0.0) == 100.0  # no code
+
+    def test_bigger_and_more_branching_code_scores_lower(self):
+        small = maintainability_index(200, 2, 20, 0.1)
+        assert maintainability_index(2000, 2, 20, 0.1) < small
+        assert maintainability_index(200, 30, 20, 0.1) < small
+        assert maintainability_index(200, 2, 400, 0.1) < small
+        assert maintainability_index(200, 2, 20, 0.0) < small
+
+
+class TestWeights:
+    def test_overrides_merge_with_defaults(self):
+        weights = resolve_weights({"comments": 0})
+        assert weights == {"volume": 5.2, "complexity": 0.23, "lines": 16.2, "comments": 0.0}
+        assert maintainability_index(200, 2, 20, 0.3, weights) == maintainability_index(
+            200, 2, 20, 0.0
+        )
+
+    @pytest.mark.parametrize("table", [{"loc": 1}, {"volume": -1}, {"lines": "high"}])
+    def test_invalid_weights_rejected_by_config(self, table):
+        with pytest.raises(ValueError, match="maintainability_weights"):
+            AnalysisConfig(maintainability_weights=table)
+
+
+class TestFileMeasurements:
+    def test_complexity_lines_and_comment_ratio(self):
+        content = "// clamp\nfunction f(x) {\n\n  if (x > 1 || x < -1) {\n    return 1;\n  }\n}\n"
+        assert file_measurements(content) == (3, 5, 1 / 6)  # if, ||; 1 comment of 6 lines
+
+    def test_file_and_package_index(self):
+        index = file_maintainability("x = 1\n", volume=4.75)
+        assert 0 < index <= 100
+        assert package_maintainability([(80.0, 100), (20.0, 300)]) == 35.0
+        assert package_maintainability([]) == 0.0
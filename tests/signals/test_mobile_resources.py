@@ -0,0 +1,206 @@
+"""Tests for duplicated strings, unused resources and oversized mobile resource files."""
+
+import plistlib
+
+from shannon_insight.signals.mobile_resources import (
+    MAX_SCENES,
+    OVERSIZED_BYTES,
+    collect_mobile_resources,
+    resource_kind,
+    to_findings,
+)
+
+_STRINGS = """\
+<?xml version="1.0" encoding="utf-8"?>
+<resources>
+    <string name="app_name" translatable="false">Shop</string>
+    <string name="sign_in">Sign in</string>
+    <string name="login_button">Sign in</string>
+    <string name="greeting">Hello, <b>%1$s</b>!</string>
+    <string name="old_banner">Summer sale</string>
+    <plurals name="items">
+        <item quantity="one">%d item</item>
+        <item quantity="other">%d items</item>
+    </plurals>
+    <color name="brand">#FF0000</color>
+    <dimen name="gutter">16dp</dimen>
+    <style name="Theme.Shop" parent="Theme.Material3.DayNight" />
+</resources>
+"""
+
+_STRINGS_FR = """\
+<resources>
+    <string name="sign_in">Se connecter</string>
+    <string name="login_button">Se connecter</string>
+    <string name="old_banner">Soldes</string>
+</resources>
+"""
+
+_LAYOUT = """\
+<LinearLayout xmlns:android="http://schemas.android.com/apk/res/android"
+    android:padding="@dimen/gutter">
+    <Button android:id="@+id/login" android:text="@string/login_button"
+        android:textColor="@android:color/white" />
+</LinearLayout>
+"""
+
+_MANIFEST = """\
+<manifest><application android:label="@string/app_name" android:icon="@mipmap/ic_launcher"
+    android:theme="@style/Theme.Shop" /></manifest>
+"""
+
+_ACTIVITY = """\
+class LoginActivity : AppCompatActivity() {
+    private lateinit var binding: ActivityLoginBinding
+    fun greet(name: String) = getString(R.string.greeting, name) + getString(R.string.sign_in)
+    fun count(n: Int) = resources.getQuantityString(R.plurals.items, n, n)
+    fun ok() = getString(android.R.string.ok)
+}
+"""
+
+_LOCALIZABLE = """\
+/* Shown on the welcome screen */
+"welcome_title" = "Welcome";
+"welcome_back" = "Welcome";
+"unused_key" = "Never shown";
+"""
+
+_MAIN_STRINGS = """\
+"abc-12-xyz.text" = "Welcome";
+"""
+
+_VIEW = """\
+struct WelcomeView: View {
+    var body: some View {
+        VStack {
+            Text("welcome_title")
+            Image(.heroBanner)
+            Image(uiImage: UIImage(named: "logo")!)
+        }
+    }
+}
+"""
+
+
+def _tree(tmp_path):
+    files = {
+        "app/src/main/res/values/strings.xml": _STRINGS,
+        "app/src/main/res/values-fr/strings.xml": _STRINGS_FR,
+        "app/src/main/res/layout/activity_login.xml": _LAYOUT,
+        "app/src/main/res/layout/unused_row.xml": "<FrameLayout />\n",
+        "app/src/main/res/drawable/old_logo.xml": "<vector />\n",
+        "app/src/main/AndroidManifest.xml": _MANIFEST,
+        "ios/App/en.lproj/Localizable.strings": _LOCALIZABLE,
+        "ios/App/Base.lproj/Main.strings": _MAIN_STRINGS,
+        "ios/App/Assets.xcassets/hero-banner.imageset/Contents.json": "{}",
+        "ios/App/Assets.xcassets/logo.imageset/Contents.json": "{}",
+        "ios/App/Assets.xcassets/stale.imageset/Contents.json": "{}",
+        "ios/App/Assets.xcassets/splash.imageset/Contents.json": "{}",
+        "ios/App/Assets.xcassets/AppIcon.appiconset/Contents.json": "{}",
+    }
+    for path, text in files.items():
+        (tmp_path / path).parent.mkdir(parents=True, exist_ok=True)
+        (tmp_path / path).write_text(text)
+    mipmap = tmp_path / "app/src/main/res/mipmap-xxhdpi/ic_launcher.png"
+    mipmap.parent.mkdir(parents=True)
+    mipmap.write_bytes(b"\x89PNG" + b"\0" * OVERSIZED_BYTES)
+    (tmp_path / "ios/App/Info.plist").write_bytes(
+        plistlib.dumps({"UILaunchScreen": {"UIImageName": "splash"}, "CFBundleName": "Shop"})
+    )
+    storyboard = "".join(f'<scene sceneID="s{i}"/>\n' for i in range(MAX_SCENES))
+    (tmp_path / "ios/App/Base.lproj/Main.storyboard").write_text(storyboard)
+    code = {
+        "app/src/main/java/shop/LoginActivity.kt": _ACTIVITY,
+        "ios/App/WelcomeView.swift": _VIEW,
+    }
+    paths = [
+        *files,
+        "app/src/main/res/mipmap-xxhdpi/ic_launcher.png",
+        "ios/App/Info.plist",
+        "ios/App/Base.lproj/Main.storyboard",
+        *code,
+    ]
+    return collect_mobile_resources(tmp_path, paths, code)
+
+
+def test_resource_kind():
+    assert resource_kind("app/src/main/res/drawable-hdpi/a.png") == ("android", "drawable")
+    assert resource_kind("app/src/main/res/values-fr/strings.xml") == ("android", "values")
+    assert resource_kind("app/src/main/AndroidManifest.xml") == ("android", "manifest")
+    assert resource_kind("ios/de.lproj/Localizable.strings") == ("ios", "strings")
+    assert resource_kind("ios/A.xcassets/a.imageset/a@2x.png") == ("ios", "asset")
+    assert resource_kind("ios/Main.storyboard") == ("ios", "storyboard")
+    assert resource_kind("ios/Info.plist") == ("ios", "plist")
+    assert resource_kind("docs/res/notes.txt") is None
+    assert resource_kind("src/main.kt") is None
+
+
+def test_duplicate_strings_in_default_locale(tmp_path):
+    report = _tree(tmp_path)
+
+    assert [(d.platform, d.value, [r.name for r in d.resources]) for d in report.duplicates] == [
+        ("android", "Sign in", ["sign_in", "login_button"]),
+        ("ios", "Welcome", ["welcome_title", "welcome_back"]),
+    ]
+
+
+def test_unused_resources(tmp_path):
+    report = _tree(tmp_path)
+
+    assert sorted((u.resource.platform, u.resource.key, u.variants) for u in report.unused) == [
+        ("android", "color/brand", 1),
+        ("android", "drawable/old_logo", 1),
+        ("android", "layout/unused_row", 1),
+        ("android", "string/old_banner", 2),
+        ("ios", "image/stale", 1),
+        ("ios", "localized_string/unused_key", 1),
+        ("ios", "localized_string/welcome_back", 1),
+    ]
+    assert not any(u.dynamic_lookups for u in report.unused)
+
+
+def test_dynamic_lookups_lower_confidence(tmp_path):
+    contents = tmp_path / "ios/A.xcassets/stale.imageset/Contents.json"
+    contents.parent.mkdir(parents=True)
+    contents.write_text("{}")
+
+    report = collect_mobile_resources(
+        tmp_path,
+        ["ios/A.xcassets/stale.imageset/Contents.json"],
+        {"Code.swift": "let image = UIImage(named: name)\n"},
+    )
+
+    (finding,) = to_findings(report)
+    assert finding.confidence == 0.5
+    assert finding.title == "iOS image/stale is never referenced"
+
+
+def test_oversized_files(tmp_path):
+    report = _tree(tmp_path)
+
+    assert [o.file.path for o in report.oversized] == [
+        "app/src/main/res/mipmap-xxhdpi/ic_launcher.png",
+        "ios/App/Base.lproj/Main.storyboard",
+    ]
+    assert report.platforms() == {"android": 7, "ios": 9}
+
+
+def test_findings(tmp_path):
+    findings = to_findings(_tree(tmp_path))
+    by_type = {}
+    for finding in findings:
+        by_type.setdefault(finding.finding_type, []).append(finding)
+
+    duplicate = by_type["duplicate_string_resource"][0]
+    assert duplicate.title == '"Sign in" is defined as 2 string resources: sign_in, login_button'
+    assert duplicate.files == ["app/src/main/res/values/strings.xml"]
+    unused = {f.identity_hint: f for f in by_type["unused_resource"]}
+    banner = unused["android:string/old_banner"]
+    assert banner.evidence[0].description == "app/src/main/res/values/strings.xml:7"
+    assert banner.severity == 0.25
+    assert unused["android:drawable/old_logo"].severity == 0.3
+    image, storyboard = by_type["oversized_resource"]
+    assert image.title.startswith("Image resource app/src/main/res/mipmap-xxhdpi/ic_launcher.png")
+    assert storyboard.title == (
+        f"Storyboard ios/App/Base.lproj/Main.storyboard has {MAX_SCENES} scenes"
+    )
@@ -0,0 +1,107 @@
+"""Tests for per-function maximum and average nesting depth."""
+
+from pathlib import Path
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.scanning.syntax_extractor import SyntaxExtractor
+from shannon_insight.signals.nesting import (
+    DEEP_NESTING_TYPE,
+    collect_nesting,
+    find_deep_nesting,
+    function_nesting,
+    to_findings,
+)
+from tests.conftest import make_function, make_syntax
+
+# One deep corner: decisions stacked four levels in
+DEEP = """\
+func Walk(tree *Node) {
+    count := 0
+    if tree != nil {
+        for _, child := range tree.Children {
+            if child.Visible {
+                for _, leaf := range child.Leaves {
+                    count++
+                }
+            }
+        }
+    }
+    report(count)
+}
+"""
+
+# As many decisions, one after the other
+FLAT = """\
+func Check(a, b, c, d bool) {
+    if a {
+        log("a")
+    }
+    for b {
+        log("b")
+    }
+    if c {
+        log("c")
+    }
+    if d {
+        log("d")
+    }
+}
+"""
+
+
+def _measure(source, nesting, path="walk.go"):
+    lines = source.splitlines()
+    fn = make_function(end_line=len(lines), nesting_depth=nesting)
+    files = {path: make_syntax(path, [fn], language="go")}
+    return collect_nesting(files, {path: source})
+
+
+class TestFunctionNesting:
+    def test_average_over_body_lines_and_deepest_line(self):
+        lines = DEEP.splitlines()
+        average, deepest = function_nesting(
+            make_function("Walk", end_line=len(lines), nesting_depth=4), lines
+        )
+        # 12 body lines at levels 0 0 1 2 3 4 3 2 1 0 0 0
+        assert average == pytest.approx(16 / 12)
+        assert deepest == 7
+
+    def test_flat_decisions_are_shallow(self):
+        lines = FLAT.splitlines()
+        average, _ = function_nesting(make_function("Check", end_line=len(lines)), lines)
+        assert average < 0.5
+
+    def test_average_capped_at_parser_maximum(self):
+        lines = DEEP.splitlines()
+        average, _ = function_nesting(make_function("Walk", end_line=len(lines)), lines)
+        assert average == 1.0
+
+    def test_regex_parser_counts_blocks_not_the_body(self):
+        syntax = SyntaxExtractor(max_workers=1).extract_source("walk.go", DEEP, Path("."))
+        [walk] = syntax.functions
+        assert walk.nesting_depth == 4
+        flat = SyntaxExtractor(max_workers=1).extract_source("check.go", FLAT, Path("."))
+        assert flat.functions[0].nesting_depth == 1
+
+
+class TestFindDeepNesting:
+    def test_only_functions_deeper_than_threshold(self):
+        [walk] = _measure(DEEP, 4)
+        assert find_deep_nesting([walk], threshold=4) == []
+        assert find_deep_nesting([walk], threshold=3) == [walk]
+
+    def test_finding_carries_maximum_and_average(self):
+        [finding] = to_findings(find_deep_nesting(_measure(DEEP, 6)), threshold=4)
+        assert finding.finding_type == DEEP_NESTING_TYPE
+        assert finding.files == ["walk.go"]
+        assert finding.title == "f at walk.go:1 nests 6 levels deep (threshold 4)"
+        assert finding.severity == pytest.approx(0.5)
+        evidence = {e.signal: e.value for e in finding.evidence}
+        assert evidence == {"max_nesting": 6.0, "avg_nesting": 1.33}
+        assert finding.identity_hint == "f"
+
+    def test_threshold_must_be_positive(self):
+        with pytest.raises(ValueError, match="nesting_threshold"):
+            AnalysisConfig(nesting_threshold=0)
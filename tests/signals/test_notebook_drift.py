@@ -0,0 +1,88 @@
+"""Tests for functions copied between notebooks and modules."""
+
+from shannon_insight.scanning.syntax import ClassDef
+from shannon_insight.signals.notebook_drift import DRIFT_TYPE, find_notebook_drift, to_findings
+from tests.conftest import make_function, make_syntax
+
+MODULE = """\
+def clean(df):
+    df = df.dropna()
+    df = df[df.price > 0]
+    df["total"] = df.price * df.qty
+    return df
+"""
+
+NOTEBOOK = """\
+# %% [1]
+def clean(df):
+    # keep rows without a price for now
+    df = df.dropna(subset=["qty"])
+    df = df[df.price > 0]
+    df["total"] = df.price * df.qty
+    return df
+"""
+
+
+def _files(notebook_fn=None, module_fn=None):
+    return {
+        "nb/explore.ipynb": make_syntax(
+            "nb/explore.ipynb",
+            [notebook_fn or make_function("clean", start_line=2, end_line=7, cell=1)],
+        ),
+        "pipeline/prices.py": make_syntax(
+            "pipeline/prices.py", [module_fn or make_function("clean", end_line=5)]
+        ),
+    }
+
+
+CONTENTS = {"nb/explore.ipynb": NOTEBOOK, "pipeline/prices.py": MODULE}
+
+
+class TestFindNotebookDrift:
+    def test_diverged_copy_reported_with_diff(self):
+        [drift] = find_notebook_drift(_files(), CONTENTS)
+        assert drift.notebook.location == "nb/explore.ipynb cell 1"
+        assert drift.module.location == "pipeline/prices.py:1"
+        assert 0.6 <= drift.similarity < 1.0
+        assert drift.changed_lines == 2
+        assert "-df = df.dropna()" in drift.diff
+        assert '+df = df.dropna(subset=["qty"])' in drift.diff
+        assert drift.canonical == "module" and drift.newer is None
+
+    def test_identical_and_unrelated_functions_ignored(self):
+        same = dict(CONTENTS, **{"nb/explore.ipynb": "# %% [1]\n" + MODULE})
+        copied = make_function("clean", start_line=2, end_line=6, cell=1)
+        assert find_notebook_drift(_files(copied), same) == []
+        other = dict(
+            CONTENTS,
+            **{"nb/explore.ipynb": "# %% [1]\ndef clean(df):\n    a = 1\n    b = 2\n    c = 3\n"},
+        )
+        unrelated = make_function("clean", start_line=2, end_line=5, cell=1)
+        assert find_notebook_drift(_files(unrelated), other) == []
+
+    def test_methods_matched_by_qualified_name(self):
+        module = "class Loader:\n" + "".join(f"    {line}\n" for line in MODULE.splitlines())
+        method = make_function("clean", start_line=2, end_line=6)
+        files = _files()
+        files["pipeline/prices.py"] = make_syntax(
+            "pipeline/prices.py", [], [ClassDef("Loader", [], [method], [])]
+        )
+        contents = dict(CONTENTS, **{"pipeline/prices.py": module})
+        assert find_notebook_drift(files, contents) == []  # clean is not Loader.clean
+
+    def test_newer_notebook_is_canonical(self):
+        last_changed = {"nb/explore.ipynb": 2_000, "pipeline/prices.py": 1_000}
+        [drift] = find_notebook_drift(_files(), CONTENTS, last_changed)
+        assert drift.canonical == drift.newer == "notebook"
+
+
+class TestToFindings:
+    def test_finding_names_both_copies(self):
+        [finding] = to_findings(find_notebook_drift(_files(), CONTENTS))
+        assert finding.finding_type == DRIFT_TYPE
+        assert finding.files == ["nb/explore.ipynb", "pipeline/prices.py"]
+        assert "drifted from pipeline/prices.py:1" in finding.title
+        assert finding.identity_hint == "clean"
+        assert "Import clean from pipeline/prices.py" in finding.suggestion
+        diff = next(e for e in finding.evidence if e.signal == "diff")
+        assert diff.description.startswith("--- pipeline/prices.py:1")
@@ -0,0 +1,114 @@
+"""Tests for parameter counts, long parameter lists and data clumps."""
+
+from shannon_insight.signals.parameters import (
+    DATA_CLUMP_TYPE,
+    LONG_PARAMETER_LIST_TYPE,
+    FunctionParams,
+    collect_parameters,
+    find_data_clumps,
+    find_long_parameter_lists,
+    parameter_names,
+    to_findings,
+)
+from tests.conftest import make_function, make_syntax
+
+
+def _params(name, names, path="a.py", line=1):
+    return FunctionParams(path, name, line, tuple(names))
+
+
+class TestParameterNames:
+    def test_receivers_defaults_and_collectors_left_out(self):
+        raw = ["self", "a", "b=1", "c: int = 2", "*args", "**kwargs", "$d", "&e", "...rest"]
+        assert parameter_names(raw) == ("a", "b", "c", "d", "e")
+
+    def test_collect_parameters(self):
+        files = {
+            "metrics.go": make_syntax(
+                "metrics.go",
+                [make_function("calculateMetric", params=["a", "b", "c", "d"], start_line=3)],
+                language="go",
+            ),
+            "app.py": make_syntax(
+                "app.py", [make_function("run", params=["self", "argv"], start_line=7)]
+            ),
+        }
+        functions = collect_parameters(files)
+
+        assert [(f.location, f.name, f.count) for f in functions] == [
+            ("app.py:7", "run", 1),
+            ("metrics.go:3", "calculateMetric", 4),
+        ]
+
+
+class TestLongParameterLists:
+    def test_threshold_and_order(self):
+        functions = [
+            _params("five", "abcde"),
+            _params("six", "abcdef"),
+            _params("eight", "abcdefgh"),
+        ]
+        assert [f.name for f in find_long_parameter_lists(functions)] == ["eight", "six"]
+        assert [f.name for f in find_long_parameter_lists(functions, 7)] == ["eight"]
+
+    def test_finding(self):
+        fn = _params("calculateMetric", "abcdefg", "metrics.go", 12)
+        (finding,) = to_findings([fn], [])
+
+        assert finding.finding_type == LONG_PARAMETER_LIST_TYPE
+        assert finding.title == "calculateMetric at metrics.go:12 takes 7 parameters (threshold 5)"
+        assert finding.evidence[0].description == "a, b, c, d, e, f, g"
+        assert finding.severity == 0.5
+
+
+class TestDataClumps:
+    def test_largest_shared_group(self):
+        functions = [
+            _params("connect", ["host", "port", "timeout", "retries"], "net/client.py", 3),
+            _params("probe", ["host", "port", "timeout", "path"], "net/health.py", 8),
+            _params("dial", ["timeout", "host", "port"], "net/dial.py", 1),
+            _params("other", ["host", "port", "verbose"], "cli.py", 1),
+        ]
+        (clump,) = find_data_clumps(functions)
+
+        # One clump of three, in the first function's order, not its subsets
+        assert clump.params == ("host", "port", "timeout")
+        assert [f.name for f in clump.functions] == ["connect", "probe", "dial"]
+        assert clump.files == ["net/client.py", "net/health.py", "net/dial.py"]
+
+    def test_same_named_functions_count_once(self):
+        # Implementations of one interface method share its signature
+        functions = [
+            _params("Serve", ["ctx", "req", "resp"], f"handlers/{name}.go") for name in "abcd"
+        ]
+        functions.append(_params("log", ["ctx", "req", "resp"]))
+        assert find_data_clumps(functions) == []
+
+        functions.append(_params("trace", ["ctx", "req", "resp", "span"]))
+        (clump,) = find_data_clumps(functions)
+        assert len(clump.functions) == 6
+
+    def test_overlapping_clumps(self):
+        functions = [
+            _params("a", ["x", "y", "z", "w"]),
+            _params("b", ["x", "y", "z", "w"]),
+            _params("c", ["x", "y", "z", "w"]),
+            _params("d", ["x", "y", "z"]),
+            _params("e", ["u", "v", "t", "x"]),
+            _params("f", ["u", "v", "t"]),
+            _params("g", ["t", "u", "v"]),
+        ]
+        clumps = find_data_clumps(functions)
+
+        assert [c.params for c in clumps] == [("x", "y", "z", "w"), ("u", "v", "t")]
+
+    def test_finding(self):
+        functions = [_params(name, ["host", "port", "timeout"], f"{name}.py") for name in "abc"]
+        (finding,) = to_findings([], find_data_clumps(functions))
+
+        assert finding.finding_type == DATA_CLUMP_TYPE
+        assert finding.title == "(host, port, timeout) are passed together to 3 functions"
+        assert finding.files == ["a.py", "b.py", "c.py"]
+        assert finding.identity_hint == "host,port,timeout"
+        assert finding.evidence[2].description == "a at a.py:1"
+        assert round(finding.severity, 2) == 0.45
@@ -1,4 +1,4 @@
-"""Tests for v2 Signal registry (64 signals)."""
+"""Tests for v2 Signal registry (73 signals)."""
 
 import pytest
 
@@ -14,11 +14,11 @@ from shannon_insight.signals.registry import (
 
 
 class TestSignalEnum:
-    """Test Signal enum has all 64 signals."""
+    """Test Signal enum has all 73 signals."""
 
     def test_signal_count(self):
-        """Must have exactly 64 signals (from spec)."""
-        assert len(Signal) == 64
+        """Must have exactly 73 signals (from spec)."""
+        assert len(Signal) == 73
 
     def test_per_file_scanning_signals(self):
         """IR1 scanning signals (#1-7)."""
@@ -238,14 +238,14 @@ class TestSignalCounts:
     """Verify signal count breakdown from spec."""
 
     def test_file_signal_count(self):
-        """Per-file signals: 38."""
+        """Per-file signals: 46."""
         file_signals = signals_by_scope("file")
-        assert len(file_signals) == 38
+        assert len(file_signals) == 46
 
     def test_module_signal_count(self):
-        """Per-module signals: 15."""
+        """Per-module signals: 16."""
         module_signals = signals_by_scope("module")
-        assert len(module_signals) == 15
+        assert len(module_signals) == 16
 
     def test_global_signal_count(self):
         """Global signals: 11."""
@@ -0,0 +1,91 @@
+"""Tests for SQL statement metrics, long procedures and table usage."""
+
+from shannon_insight.signals.sql_statements import (
+    LONG_PROCEDURE_LINES,
+    ProcedureSample,
+    collect_sql,
+    to_findings,
+)
+from tests.conftest import make_sources
+
+_SCHEMA = """\
+CREATE TABLE users (id INT PRIMARY KEY, email TEXT);
+
+CREATE VIEW active_users AS
+    SELECT u.id FROM users u JOIN sessions s ON s.user_id = u.id WHERE s.live AND u.email <> '';
+"""
+
+
+def _procedure(lines):
+    body = "".join(f"    UPDATE users SET n = {i} WHERE id = p_id;\n" for i in range(lines - 4))
+    return (
+        "CREATE FUNCTION bump(p_id INT) RETURNS void AS $$\n"
+        f"BEGIN\n{body}END;\n$$ LANGUAGE plpgsql;\n"
+    )
+
+
+class TestCollectSql:
+    def test_statements_most_complex_first(self):
+        report = collect_sql(*make_sources("sql", **{"db/schema.sql": _SCHEMA}))
+
+        assert [(s.label, s.complexity) for s in report.statements] == [
+            ("db/schema.sql:3 CREATE", 3),
+            ("db/schema.sql:1 CREATE", 1),
+        ]
+        assert report.files == 1
+
+    def test_procedures_longest_first(self):
+        report = collect_sql(
+            *make_sources("sql", **{"db/a.sql": _procedure(10), "db/b.sql": _procedure(30)})
+        )
+
+        assert [(p.path, p.name, p.line, p.lines) for p in report.procedures] == [
+            ("db/b.sql", "bump", 1, 30),
+            ("db/a.sql", "bump", 1, 10),
+        ]
+
+    def test_tables_most_referenced_first(self):
+        report = collect_sql(
+            *make_sources("sql", **{"db/schema.sql": _SCHEMA, "db/p.sql": _procedure(10)})
+        )
+
+        assert report.tables == {
+            "users": ["db/p.sql", "db/schema.sql"],
+            "sessions": ["db/schema.sql"],
+        }
+
+    def test_ignores_other_languages(self):
+        syntax, contents = make_sources("sql", **{"db/schema.sql": _SCHEMA})
+        syntax["db/schema.sql"].language = "python"
+
+        assert collect_sql(syntax, contents).statements == []
+
+    def test_long_procedures(self):
+        report = collect_sql(
+            *make_sources(
+                "sql",
+                **{
+                    "db/short.sql": _procedure(LONG_PROCEDURE_LINES - 1),
+                    "db/long.sql": _procedure(LONG_PROCEDURE_LINES),
+                }
+            )
+        )
+
+        assert [p.path for p in report.long_procedures()] == ["db/long.sql"]
+
+
+class TestFindings:
+    def test_long_procedure_finding(self):
+        procedure = ProcedureSample("db/p.sql", "bump", 3, LONG_PROCEDURE_LINES * 2, 12)
+        (finding,) = to_findings([procedure])
+
+        assert finding.finding_type == "long_procedure"
+        assert finding.files == ["db/p.sql"]
+        assert finding.title == "bump at db/p.sql:3 is 400 lines of SQL"
+        assert abs(finding.severity - 0.5) < 1e-9
+        assert finding.identity_hint == "bump"
+
+    def test_severity_is_capped(self):
+        procedure = ProcedureSample("db/p.sql", "bump", 1, LONG_PROCEDURE_LINES * 64, 1)
+
+        assert procedure.severity == 0.8
@@ -0,0 +1,124 @@
+"""Tests for Terraform module metrics, nested dynamic blocks and variable outliers."""
+
+from shannon_insight.signals.terraform_modules import (
+    MIN_MODULES,
+    TerraformModule,
+    collect_terraform,
+    find_variable_outliers,
+    to_findings,
+)
+from tests.conftest import make_sources
+
+_NESTED = """\
+resource "aws_security_group" "web" {
+  dynamic "ingress" {
+    for_each = var.rules
+    content {
+      dynamic "cidr" {
+        for_each = ingress.value.cidrs
+        content {
+          block = cidr.value
+        }
+      }
+    }
+  }
+}
+
+resource "aws_instance" "web" {
+  dynamic "ebs_block_device" {
+    for_each = var.disks
+    content {
+      size = ebs_block_device.value
+    }
+  }
+}
+
+resource "aws_instance" "worker" {}
+"""
+
+
+def _variables(count):
+    return "".join(f'variable "v{i}" {{\n  type = string\n}}\n' for i in range(count))
+
+
+class TestCollectTerraform:
+    def test_module_counts(self):
+        report = collect_terraform(
+            *make_sources(
+                "hcl",
+                **{
+                    "main.tf": 'module "web" {\n  source = "./web"\n}\n',
+                    "web/main.tf": _NESTED + 'data "aws_ami" "ubuntu" {}\n',
+                    "web/variables.tf": _variables(3),
+                    "web/outputs.tf": 'output "id" {\n  value = aws_instance.web.id\n}\n',
+                }
+            )
+        )
+
+        assert [m.label for m in report.modules] == ["web", "."]
+        web = report.modules[0]
+        assert web.files == ["web/main.tf", "web/outputs.tf", "web/variables.tf"]
+        assert web.resource_types == {"aws_security_group": 1, "aws_instance": 2}
+        assert (web.resources, web.data_sources, web.outputs) == (3, 1, 1)
+        assert web.variables == ["v0", "v1", "v2"]
+        assert web.variable_files == ["web/variables.tf"]
+        assert web.max_dynamic_depth == 2
+        assert report.modules[1].module_calls == 1
+
+    def test_nested_dynamic_blocks(self):
+        report = collect_terraform(*make_sources("hcl", **{"main.tf": _NESTED}))
+
+        assert [(d.line, d.block, d.chain, d.depth) for d in report.dynamic_blocks] == [
+            (5, "aws_security_group.web", ("ingress", "cidr"), 2),
+        ]
+
+    def test_ignores_other_languages(self):
+        syntax, contents = make_sources("hcl", **{"main.tf": _NESTED})
+        syntax["main.tf"].language = "python"
+
+        assert collect_terraform(syntax, contents).modules == []
+
+
+class TestVariableOutliers:
+    def test_module_with_many_variables(self):
+        files = {f"m{i}/variables.tf": _variables(3 + i % 2) for i in range(MIN_MODULES)}
+        files["big/variables.tf"] = _variables(40)
+        report = collect_terraform(*make_sources("hcl", **files))
+
+        (outlier,) = report.variable_outliers
+        assert outlier.module.label == "big"
+        assert outlier.typical_variables == 3.5
+        assert outlier.modified_z > 5
+
+    def test_too_few_modules(self):
+        modules = [TerraformModule(f"m{i}", variables=["v"] * 3) for i in range(MIN_MODULES - 2)]
+        modules.append(TerraformModule("big", variables=["v"] * 40))
+
+        assert find_variable_outliers(modules) == []
+
+    def test_small_modules_are_never_outliers(self):
+        modules = [TerraformModule(f"m{i}", variables=[]) for i in range(MIN_MODULES)]
+        modules.append(TerraformModule("small", variables=["v"] * 8))
+
+        assert find_variable_outliers(modules) == []
+
+
+class TestFindings:
+    def test_findings(self):
+        files = {f"m{i}/variables.tf": _variables(2) for i in range(MIN_MODULES)}
+        files["big/variables.tf"] = _variables(30)
+        files["big/main.tf"] = _NESTED
+        findings = to_findings(collect_terraform(*make_sources("hcl", **files)))
+
+        nested, outlier = findings
+        assert nested.finding_type == "nested_dynamic_block"
+        assert nested.files == ["big/main.tf"]
+        assert nested.title == "aws_security_group.web at big/main.tf:5 nests dynamic blocks 2 deep"
+        assert nested.identity_hint == "aws_security_group.web:ingress.cidr"
+        assert abs(nested.severity - 0.4) < 1e-9
+
+        assert outlier.finding_type == "variable_count_outlier"
+        assert outlier.files == ["big/variables.tf"]
+        assert outlier.title == "Module big declares 30 variables, typical is 2"
+        assert outlier.identity_hint == "big"
+        assert outlier.severity == 0.7
@@ -0,0 +1,59 @@
+"""Tests for token-level Shannon entropy."""
+
+import math
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.signals.token_entropy import token_counts, token_entropy
+
+CONSISTENT = """\
+def load(user_id):
+    user = fetch(user_id)
+    return user
+"""
+
+CHAOTIC = """\
+def load(userId):
+    usr = fetch(user_id)
+    return uid
+"""
+
+
+class TestTokenCounts:
+    def test_comments_dropped_and_keywords_kept(self):
+        counts = token_counts("x = 1  # set x\n", "python")
+        assert counts == {"x": 1, "=": 1, "1": 1}
+        assert token_counts("if x:\n    return x\n", "python")["if"] == 1
+
+    def test_identifiers_scope_keeps_names_only(self):
+        counts = token_counts(CONSISTENT, "python", scope="identifiers")
+        assert counts == {"load": 1, "user_id": 2, "user": 2, "fetch": 1}
+
+
+class TestTokenEntropy:
+    def test_bits(self):
+        assert token_entropy("a b c d", scope="identifiers") == pytest.approx(2.0)
+        assert token_entropy("") == 0.0
+
+    def test_inconsistent_names_raise_identifier_entropy(self):
+        consistent = token_entropy(CONSISTENT, "python", "identifiers")
+        assert token_entropy(CHAOTIC, "python", "identifiers") > consistent
+
+    def test_length_normalization_is_0_to_1_for_any_size(self):
+        short = token_entropy("a b c d", normalization="length")
+        assert short == pytest.approx(1.0)  # every token distinct
+        text = CONSISTENT * 50
+        long = token_entropy(text, "python", normalization="length")
+        assert 0.0 < long < 1.0
+        total = sum(token_counts(text, "python").values())
+        assert long == pytest.approx(token_entropy(text, "python") / math.log2(total))
+        assert token_entropy("x", normalization="length") == 0.0
+
+    @pytest.mark.parametrize(
+        "options",
+        [{"token_entropy_scope": "names"}, {"token_entropy_normalization": "lines"}],
+    )
+    def test_invalid_options_rejected_by_config(self, options):
+        with pytest.raises(ValueError, match="token_entropy"):
+            AnalysisConfig(**options)
@@ -0,0 +1,111 @@
+"""Tests for per-type size and complexity, with Swift extensions merged in."""
+
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, FunctionDef
+from shannon_insight.signals.type_sizes import (
+    GOD_CLASS_METHODS,
+    GOD_CLASS_WMC,
+    TypeSample,
+    collect_types,
+    find_god_classes,
+    to_findings,
+)
+
+_BRANCHY = "    func f{n}() {{\n        if a {{ }}\n        if b && c {{ }}\n    }}\n"
+
+
+def _swift_file(path, type_name, methods, first=0, is_extension=False):
+    """A file with one type (or extension) whose methods each have complexity 4."""
+    header = "extension" if is_extension else "class"
+    content = f"{header} {type_name} {{\n"
+    functions = []
+    for n in range(first, first + methods):
+        start = content.count("\n") + 1
+        content += _BRANCHY.format(n=n)
+        functions.append(FunctionDef(f"f{n}", [], 10, 4, 1, start_line=start, end_line=start + 3))
+    content += "}\n"
+    cls = ClassDef(type_name, [], functions, [], is_extension=is_extension)
+    syntax = FileSyntax(path, functions, [cls], [], language="swift")
+    return syntax, content
+
+
+def _collect(*files):
+    return collect_types(
+        {syntax.path: syntax for syntax, _ in files},
+        {syntax.path: content for syntax, content in files},
+    )
+
+
+class TestCollectTypes:
+    def test_counts_methods_lines_and_complexity(self):
+        (sample,) = _collect(_swift_file("App/Feed.swift", "Feed", 3))
+
+        assert (sample.name, sample.methods, sample.lines, sample.complexity) == ("Feed", 3, 12, 12)
+        assert sample.extensions == 0
+
+    def test_extensions_merge_into_their_type(self):
+        samples = _collect(
+            _swift_file("App/Feed/Feed.swift", "Feed", 3),
+            _swift_file("App/Feed/Feed+DataSource.swift", "Feed", 2, first=3, is_extension=True),
+            _swift_file("App/Feed/Feed+Layout.swift", "Feed", 1, first=5, is_extension=True),
+        )
+
+        (feed,) = samples
+        assert feed.path == "App/Feed/Feed.swift"
+        assert (feed.methods, feed.complexity, feed.extensions) == (6, 24, 2)
+        assert feed.files == [
+            "App/Feed/Feed.swift",
+            "App/Feed/Feed+DataSource.swift",
+            "App/Feed/Feed+Layout.swift",
+        ]
+
+    def test_extension_joins_the_nearest_declaration(self):
+        samples = _collect(
+            _swift_file("App/Feed/Item.swift", "Item", 1),
+            _swift_file("Widget/Item.swift", "Item", 1),
+            _swift_file("Widget/Views/Item+View.swift", "Item", 2, is_extension=True),
+        )
+
+        methods = {s.path: s.methods for s in samples}
+        assert methods == {"App/Feed/Item.swift": 1, "Widget/Item.swift": 3}
+
+    def test_extensions_of_external_types_are_not_counted(self):
+        samples = _collect(_swift_file("App/String+Trim.swift", "String", 4, is_extension=True))
+
+        assert samples == []
+
+
+class TestGodClasses:
+    def test_split_type_is_a_god_class_once_merged(self):
+        # 8 + 8 methods of complexity 4: neither block alone reaches the thresholds
+        files = [
+            _swift_file("App/Feed.swift", "Feed", 8),
+            _swift_file("App/Feed+Table.swift", "Feed", 8, first=8, is_extension=True),
+        ]
+
+        (god,) = find_god_classes(_collect(*files))
+
+        assert (god.methods, god.complexity) == (16, 64)
+        assert find_god_classes(_collect(files[0])) == []
+
+    def test_one_complex_method_is_not_a_god_class(self):
+        sample = TypeSample(name="Parser", path="p.swift", methods=2, complexity=90)
+
+        assert find_god_classes([sample]) == []
+
+    def test_findings_name_the_type_and_its_files(self):
+        sample = TypeSample(
+            name="Feed",
+            path="App/Feed.swift",
+            files=["App/Feed.swift", "App/Feed+Table.swift"],
+            methods=GOD_CLASS_METHODS,
+            complexity=GOD_CLASS_WMC * 2,
+            extensions=2,
+        )
+
+        (finding,) = to_findings([sample])
+
+        assert finding.finding_type == "god_class"
+        assert finding.files == ["App/Feed.swift", "App/Feed+Table.swift"]
+        assert finding.identity_hint == "Feed"
+        assert "2 extensions across 2 files" in [e.description for e in finding.evidence]
+        assert abs(finding.severity - 0.6) < 1e-9
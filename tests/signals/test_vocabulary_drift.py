@@ -0,0 +1,78 @@
+"""Tests for per-directory vocabulary drift."""
+
+import pytest
+
+from shannon_insight.scanning.syntax import FileSyntax
+from shannon_insight.signals.vocabulary_drift import (
+    DRIFT_TYPE,
+    find_vocabulary_drift,
+    to_findings,
+)
+
+ACCOUNTS = """\
+def load(user_id):
+    cache(user_id)
+    return fetch(user_id)
+
+
+def save(userId, usr_id):
+    store(userId, usr_id)
+"""
+
+SESSIONS = """\
+def open_session(uid, last_seen):
+    # userId in a comment is not a use
+    log("userId in a string is not a use either")
+    return uid, last_seen
+"""
+
+MODELS = """\
+class UserId:
+    USER_ID = 1
+
+    def __init__(self, _user_id):
+        self.value = _user_id
+"""
+
+
+def _drift(contents):
+    files = {path: FileSyntax(path, [], [], [], "python") for path in contents}
+    return find_vocabulary_drift(files, contents)
+
+
+class TestFindVocabularyDrift:
+    def test_spellings_of_one_concept_grouped(self):
+        [accounts] = _drift({"accounts/api.py": ACCOUNTS, "accounts/sessions.py": SESSIONS})
+        assert accounts.directory == "accounts"
+        user_id = accounts.concepts[0]
+        assert user_id.words == ("user", "id")
+        # usr is the glossary abbreviation of user; uid the compact form
+        assert dict(user_id.spellings) == {"user_id": 3, "userId": 2, "usr_id": 2, "uid": 2}
+        assert user_id.first_use["uid"] == "accounts/sessions.py:1"
+        assert accounts.files == ["accounts/api.py", "accounts/sessions.py"]
+
+    def test_drift_is_use_weighted_spelling_entropy(self):
+        content = "def f(user_id, userId, last_seen, last_seen):\n    pass\n"
+        [vocabulary] = _drift({"pkg/a.py": content})
+        assert vocabulary.uses == 4
+        assert vocabulary.drift == pytest.approx(0.5)  # 2 uses at 1 bit, 2 at 0
+
+    def test_directories_measured_separately(self):
+        assert _drift({"a/x.py": "user_id = 1\n", "b/y.py": "userId = 1\n"}) == []
+
+    def test_types_constants_and_private_names_are_not_drift(self):
+        assert _drift({"models/user.py": MODELS}) == []
+
+
+class TestToFindings:
+    def test_finding_per_directory(self):
+        [finding] = to_findings(
+            _drift({"accounts/api.py": ACCOUNTS, "accounts/sessions.py": SESSIONS})
+        )
+        assert finding.finding_type == DRIFT_TYPE
+        assert finding.files == ["accounts/api.py", "accounts/sessions.py"]
+        assert "user_id / userId / usr_id / uid" in finding.title
+        assert 0.2 < finding.severity <= 0.5
+        variants = [e for e in finding.evidence if e.signal == "variants"]
+        assert variants[0].value == 4.0
+        assert variants[0].description.startswith("user_id x3 (accounts/api.py:1)")
@@ -0,0 +1,138 @@
+"""Tests for YAML duplicated blocks, deep nesting and values-file outliers."""
+
+from shannon_insight.signals.yaml_manifests import (
+    DEEP_NESTING_DEPTH,
+    MIN_FILES,
+    YamlFile,
+    collect_yaml,
+    find_values_outliers,
+    to_findings,
+)
+from tests.conftest import make_sources
+
+_DEPLOYMENT = """\
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {name}
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: registry/app:1.0
+          resources:
+            limits:
+              cpu: 500m
+              memory: 256Mi
+            requests:
+              cpu: 100m
+              memory: 128Mi
+          ports:
+            - containerPort: 8080
+"""
+
+
+def _nested(depth):
+    """A document whose collections nest *depth* levels deep."""
+    lines = [f"{'  ' * level}k{level}:" for level in range(depth - 1)]
+    lines.append(f"{'  ' * (depth - 1)}leaf: 1")
+    return "\n".join(lines) + "\n"
+
+
+def _values(count):
+    return "".join(f"key{i}: {i}\n" for i in range(count))
+
+
+class TestCollectYaml:
+    def test_file_metrics(self):
+        files = {"k8s/web.yaml": _DEPLOYMENT.format(name="web")}
+        report = collect_yaml(*make_sources("yaml", **files))
+
+        (web,) = report.files
+        assert (web.documents, web.resources, web.values, web.max_depth) == (
+            1,
+            ["Deployment/web"],
+            10,
+            8,
+        )
+        assert not web.is_values_file
+
+    def test_duplicated_containers_reported_once(self):
+        report = collect_yaml(
+            *make_sources(
+                "yaml",
+                **{
+                    "k8s/web.yaml": _DEPLOYMENT.format(name="web"),
+                    "k8s/worker.yaml": "# worker\n" + _DEPLOYMENT.format(name="worker"),
+                }
+            )
+        )
+
+        (block,) = report.duplicates
+        assert block.key == "spec"
+        assert block.lines == 15
+        assert block.locations == (("k8s/web.yaml", 5, 19), ("k8s/worker.yaml", 6, 20))
+
+    def test_deep_nesting(self):
+        report = collect_yaml(
+            *make_sources(
+                "yaml",
+                **{
+                    "deep.yaml": _nested(DEEP_NESTING_DEPTH),
+                    "shallow.yaml": _nested(DEEP_NESTING_DEPTH - 1),
+                }
+            )
+        )
+
+        (deep,) = report.deep_nesting
+        assert (deep.path, deep.depth, deep.document) == ("deep.yaml", DEEP_NESTING_DEPTH, "k0")
+        assert deep.keys == ".".join(f"k{i}" for i in range(DEEP_NESTING_DEPTH - 1))
+        assert deep.line == DEEP_NESTING_DEPTH - 1
+
+
+class TestValuesOutliers:
+    def test_large_values_file(self):
+        files = {f"k8s/m{i}.yaml": _values(3 + i % 2) for i in range(MIN_FILES)}
+        files["chart/values.yaml"] = _values(80)
+        report = collect_yaml(*make_sources("yaml", **files))
+
+        (outlier,) = report.values_outliers
+        assert outlier.file.path == "chart/values.yaml"
+        assert outlier.typical_values == 3.5
+        assert outlier.modified_z > 5
+
+    def test_only_values_files_are_reported(self):
+        files = [YamlFile(f"m{i}.yaml", 3, values=3) for i in range(MIN_FILES)]
+        files.append(YamlFile("big.yaml", 80, values=80))
+
+        assert find_values_outliers(files) == []
+
+    def test_values_file_names(self):
+        names = ["values.yaml", "chart/values-prod.yaml", "values.staging.yml"]
+        assert all(YamlFile(name, 1).is_values_file for name in names)
+        assert not YamlFile("chart/myvalues.yaml", 1).is_values_file
+
+
+class TestFindings:
+    def test_findings(self):
+        files = {f"k8s/m{i}.yaml": _values(2) for i in range(MIN_FILES)}
+        files["chart/values.yaml"] = _values(60)
+        files["chart/templates/web.yaml"] = _DEPLOYMENT.format(name="web")
+        files["chart/templates/api.yaml"] = _DEPLOYMENT.format(name="api")
+        files["deep.yaml"] = _nested(DEEP_NESTING_DEPTH + 1)
+        findings = to_findings(collect_yaml(*make_sources("yaml", **files)))
+
+        duplicate, deep, outlier = findings
+        assert duplicate.finding_type == "duplicate_yaml_block"
+        assert duplicate.files == ["chart/templates/api.yaml", "chart/templates/web.yaml"]
+        assert duplicate.title == "15-line YAML block spec is repeated in 2 places"
+        assert duplicate.identity_hint.startswith("spec:")
+
+        assert deep.finding_type == "deep_yaml_nesting"
+        assert deep.title == "k0 in deep.yaml nests 13 levels deep"
+        assert abs(deep.severity - 0.5) < 1e-9
+
+        assert outlier.finding_type == "values_file_outlier"
+        assert outlier.title == "Values file chart/values.yaml sets 60 values, typical is 2"
+        assert outlier.severity == 0.7
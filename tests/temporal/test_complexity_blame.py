@@ -0,0 +1,131 @@
+"""Tests for per-function complexity history (shannon-insight complexity-blame)."""
+
+import pytest
+
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, FunctionDef
+from shannon_insight.temporal.complexity_blame import blame_complexity
+
+V1 = """def route(req):
+    return handle(req)
+"""
+
+V2 = """def route(req):
+    if req.admin:
+        return admin(req)
+    return handle(req)
+"""
+
+V3 = """def helper():
+    return 1
+
+
+def route(req):
+    if req.admin:
+        return admin(req)
+    for rule in RULES:
+        if rule.match(req) and rule.enabled:
+            return rule.handle(req)
+    return handle(req)
+"""
+
+V4 = """def helper():
+    return 1
+
+
+def route(req):
+    for rule in RULES:
+        if rule.match(req) and rule.enabled:
+            return rule.handle(req)
+    return handle(req)
+"""
+
+METHOD = """class Router:
+    def route(self, req):
+        if req:
+            return req
+"""
+
+
+class _Extractor:
+    """Exact function spans for the simple Python of these tests."""
+
+    def extract_source(self, path, content, root, content_cache=None):
+        lines = content.splitlines()
+        functions, classes = [], []
+        for i, line in enumerate(lines):
+            indent = len(line) - len(line.lstrip())
+            if line.startswith("class "):
+                classes.append(ClassDef(line.split()[1].rstrip(":"), [], [], []))
+            if not line.lstrip().startswith("def "):
+                continue
+            end = i + 1
+            for j in range(i + 1, len(lines)):
+                if lines[j].strip() and len(lines[j]) - len(lines[j].lstrip()) <= indent:
+                    break
+                if lines[j].strip():
+                    end = j + 1
+            name = line.split("def ", 1)[1].partition("(")[0]
+            fn = FunctionDef(name, ["req"], 10, 3, 1, start_line=i + 1, end_line=end)
+            (classes[-1].methods if indent else functions).append(fn)
+        return FileSyntax(path, functions, classes, [], "python")
+
+
+@pytest.fixture
+def repo(git_repo):
+    shas = [
+        git_repo.commit({"router.py": V1}, "add router", author="ana"),
+        git_repo.commit({"router.py": V2}, "admin routes", author="bo"),
+    ]
+    git_repo.git("mv", "router.py", "routes.py")
+    git_repo.git("commit", "-qm", "rename", author="ana")
+    shas.append(git_repo.commit({"routes.py": V3}, "rule engine", author="cy"))
+    shas.append(git_repo.commit({"routes.py": V3 + "\nX = 1\n"}, "unrelated", author="ana"))
+    shas.append(git_repo.commit({"routes.py": V4}, "drop admin", author="bo"))
+    return git_repo.root, shas
+
+
+class TestBlameComplexity:
+    def test_versions_follow_renames_and_skip_unrelated_commits(self, repo):
+        root, shas = repo
+        blame = blame_complexity(root, "routes.py", "route", _Extractor())
+        assert blame.commits == 6 and not blame.truncated
+        assert [v.subject for v in blame.versions] == [
+            "add router",
+            "admin routes",
+            "rule engine",
+            "drop admin",
+        ]
+        assert [v.complexity for v in blame.versions] == [1, 2, 4, 3]
+        assert [v.added for v in blame.versions] == [1, 1, 2, -1]
+        assert blame.versions[0].path == "router.py"
+        assert blame.current["complexity"] == 3
+
+    def test_growth_ranks_commits_by_complexity_added(self, repo):
+        root, shas = repo
+        blame = blame_complexity(root, "routes.py", "route", _Extractor())
+        growth = blame.growth()
+        assert [(v.author, v.added) for v in growth] == [("cy", 2), ("bo", 1), ("ana", 1)]
+        assert growth[0].commit == shas[2]
+        assert blame.to_dict(top=1)["growth"][0]["complexity_added"] == 2
+
+    def test_max_commits_truncates(self, repo):
+        root, _ = repo
+        blame = blame_complexity(root, "routes.py", "route", _Extractor(), max_commits=2)
+        assert blame.truncated
+        assert [v.subject for v in blame.versions] == ["drop admin"]
+
+    def test_methods_by_class_name(self, git_repo):
+        git_repo.commit({"router.py": METHOD}, "router class", author="ana")
+        blame = blame_complexity(git_repo.root, "router.py", "Router.route", _Extractor())
+        assert [v.complexity for v in blame.versions] == [2]
+
+    def test_unknown_function_and_file(self, repo):
+        root, _ = repo
+        with pytest.raises(ValueError, match="no function|named"):
+            blame_complexity(root, "routes.py", "missing", _Extractor())
+        with pytest.raises(ValueError, match="no history"):
+            blame_complexity(root, "nowhere.py", "route", _Extractor())
+
+    def test_not_a_repository(self, tmp_path):
+        with pytest.raises(ValueError, match="not a git repository"):
+            blame_complexity(tmp_path, "a.py", "f", _Extractor())
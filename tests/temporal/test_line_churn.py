@@ -0,0 +1,41 @@
+"""Tests for per-line change counts from git history."""
+
+import subprocess
+
+from shannon_insight.temporal.line_churn import line_churn
+
+
+def _git(root, *args):
+    subprocess.run(
+        ["git", "-C", str(root), "-c", "user.name=t", "-c", "user.email=t@t", *args],
+        check=True,
+        capture_output=True,
+    )
+
+
+def _commit(root, name, content):
+    (root / name).write_text(content)
+    _git(root, "add", "-A")
+    _git(root, "commit", "-qm", "change")
+
+
+class TestLineChurn:
+    def test_counts_follow_edits_inserts_and_renames(self, tmp_path):
+        _git(tmp_path, "init", "-q")
+        _commit(tmp_path, "a.py", "a\nb\nc\n")
+        _commit(tmp_path, "a.py", "a\nB\nc\n")
+        _commit(tmp_path, "a.py", "a\nB2\nc\nd\n")
+        _git(tmp_path, "mv", "a.py", "b.py")
+        _git(tmp_path, "commit", "-qm", "rename")
+        _commit(tmp_path, "b.py", "x\na\nB2\nd\n")
+
+        # x inserted, a untouched, B2 rewritten twice, c removed, d added
+        assert line_churn(tmp_path, "b.py", 4) == [1, 1, 3, 1]
+
+    def test_uncommitted_lines_and_no_history(self, tmp_path):
+        _git(tmp_path, "init", "-q")
+        _commit(tmp_path, "a.py", "a\n")
+
+        assert line_churn(tmp_path, "a.py", 3) == [1, 0, 0]
+        assert line_churn(tmp_path, "new.py", 1) is None
+        assert line_churn(tmp_path / "missing", "a.py", 1) is None
@@ -0,0 +1,137 @@
+"""Tests for the recent-changes report (shannon-insight recent)."""
+
+import os
+import subprocess
+import time
+
+import pytest
+
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, FunctionDef
+from shannon_insight.temporal.recent import collect_recent
+
+DAY = 86400
+NOW = int(time.time())
+
+OLD = """def keep(x):
+    return x
+
+
+def grow(x):
+    return x + 1
+"""
+
+NEW = """def keep(x):
+    return x
+
+
+def grow(x, y):
+    if x:
+        return x + y
+    return y
+"""
+
+ADDED = """class Parser:
+    def parse(self, text):
+        for line in text:
+            if line:
+                yield line
+"""
+
+
+class _Extractor:
+    """Exact function spans for the simple Python of these tests, with or
+    without tree-sitter installed."""
+
+    def extract_source(self, path, content, root, content_cache=None):
+        lines = content.splitlines()
+        functions, classes = [], []
+        for i, line in enumerate(lines):
+            indent = len(line) - len(line.lstrip())
+            if line.lstrip().startswith("class "):
+                classes.append(ClassDef(line.split()[1].rstrip(":"), [], [], []))
+            if not line.lstrip().startswith("def "):
+                continue
+            end = i + 1
+            for j in range(i + 1, len(lines)):
+                if lines[j].strip() and len(lines[j]) - len(lines[j].lstrip()) <= indent:
+                    break
+                if lines[j].strip():
+                    end = j + 1
+            body = lines[i + 1 : end]
+            depth = max(len(b) - len(b.lstrip()) for b in body) // 4 - indent // 4 - 1
+            name, _, rest = line.split("def ", 1)[1].partition("(")
+            params = [p.strip() for p in rest.split(")")[0].split(",") if p.strip()]
+            fn = FunctionDef(name, params, 10, 3, depth, start_line=i + 1, end_line=end)
+            (classes[-1].methods if indent else functions).append(fn)
+        return FileSyntax(path, functions, classes, [], "python")
+
+
+def _commit(root, files, author, when):
+    for name, content in files.items():
+        path = root / name
+        path.parent.mkdir(parents=True, exist_ok=True)
+        path.write_text(content)
+    env = {
+        **os.environ,
+        "GIT_AUTHOR_DATE": f"@{when}",
+        "GIT_COMMITTER_DATE": f"@{when}",
+    }
+    for args in (["add", "-A"], ["commit", "-qm", "change"]):
+        subprocess.run(
+            ["git", "-C", str(root), "-c", f"user.name={author}", "-c", "user.email=a@a", *args],
+            check=True,
+            capture_output=True,
+            env=env,
+        )
+
+
+@pytest.fixture
+def repo(tmp_path):
+    subprocess.run(["git", "init", "-q", str(tmp_path)], check=True, capture_output=True)
+    _commit(tmp_path, {"app/core.py": OLD, "README": "hi\n"}, "old", NOW - 100 * DAY)
+    _commit(tmp_path, {"app/core.py": NEW}, "ana", NOW - 5 * DAY)
+    _commit(tmp_path, {"lib/parser.py": ADDED, "README": "bye\n"}, "bo", NOW - 2 * DAY)
+    return tmp_path
+
+
+def _recent(root, days=30):
+    return collect_recent(root, days, _Extractor(), now=NOW)
+
+
+class TestCollectRecent:
+    def test_new_and_touched_functions(self, repo):
+        report = _recent(repo)
+        assert report.commits == 2 and report.base is not None
+        assert report.files == ["app/core.py", "lib/parser.py"]
+        assert [(f.name, f.committer) for f in report.new] == [("Parser.parse", "bo")]
+        assert report.new[0].metrics == {
+            "lines": 4,
+            "complexity": 3,
+            "nesting_depth": 2,
+            "params": 2,
+        }
+        [grow] = report.touched
+        assert (grow.name, grow.committer) == ("grow", "ana")
+        assert grow.delta == {"lines": 2, "complexity": 1, "nesting_depth": 1, "params": 1}
+
+    def test_grouped_by_committer_and_package(self, repo):
+        report = _recent(repo)
+        committers = {g.name: g.to_dict() for g in report.by_committer()}
+        assert committers["bo"]["new"] == 1 and committers["bo"]["mean_new_complexity"] == 3.0
+        assert committers["ana"]["touched"] == 1 and committers["ana"]["worsened"] == 1
+        assert [g.name for g in report.by_package()] == ["app", "lib"]
+
+    def test_window_excludes_older_commits(self, repo):
+        report = _recent(repo, days=3)
+        assert report.commits == 1
+        assert [f.name for f in report.functions] == ["Parser.parse"]
+
+    def test_young_repository_has_no_base(self, repo):
+        report = _recent(repo, days=365)
+        assert report.base is None
+        assert {f.status for f in report.functions} == {"new"}
+        assert "keep" in {f.name for f in report.functions}
+
+    def test_not_a_repository(self, tmp_path):
+        with pytest.raises(ValueError):
+            _recent(tmp_path)
@@ -0,0 +1,117 @@
+"""Tests for metric comments written above functions."""
+
+from shannon_insight.annotate import (
+    annotate_files,
+    annotate_text,
+    rank_churn,
+    remove_annotations,
+)
+from shannon_insight.scanning.syntax import ClassDef
+from tests.conftest import make_function, make_syntax
+
+SOURCE = """\
+import time
+
+
+@retry
+def sync(batch):
+    for item in batch:
+        if item:
+            send(item)
+
+
+class Client:
+    # Talks to the server
+    def close(self):
+        pass
+"""
+
+
+def _syntax(text):
+    """Syntax of SOURCE-shaped *text*, annotations included in the line numbers."""
+    lines = text.splitlines()
+    sync = lines.index("def sync(batch):") + 1
+    close = lines.index("    def close(self):") + 1
+    method = make_function("close", start_line=close, end_line=close + 1, nesting_depth=0)
+    client = ClassDef("Client", [], [method], [])
+    return make_syntax(
+        "client.py",
+        [make_function("sync", start_line=sync, end_line=sync + 3, nesting_depth=2)],
+        [client],
+    )
+
+
+class TestAnnotateText:
+    def test_comment_above_decorators_at_function_indentation(self):
+        text, count = annotate_text(SOURCE, _syntax(SOURCE), churn="high")
+        assert count == 2
+        lines = text.splitlines()
+        assert lines[3] == "# shannon: complexity=3 nesting=2 lines=4 churn=high"
+        assert lines[4] == "@retry"
+        assert lines[12] == "    # Talks to the server"
+        assert lines[13] == "    # shannon: complexity=1 nesting=0 lines=2 churn=high"
+        assert lines[14] == "    def close(self):"
+
+    def test_rerun_updates_in_place(self):
+        once, _ = annotate_text(SOURCE, _syntax(SOURCE), churn="high")
+        twice, count = annotate_text(once, _syntax(once), churn="low")
+        assert count == 2
+        assert twice == once.replace("churn=high", "churn=low")
+
+    def test_churn_left_out_without_history(self):
+        text, _ = annotate_text(SOURCE, _syntax(SOURCE))
+        assert "# shannon: complexity=3 nesting=2 lines=4\n" in text
+
+    def test_crlf_kept(self):
+        crlf = SOURCE.replace("\n", "\r\n")
+        text, _ = annotate_text(crlf, _syntax(SOURCE))
+        assert "lines=4\r\n@retry\r\n" in text
+        assert "\n" not in text.replace("\r\n", "")
+
+
+class TestRemoveAnnotations:
+    def test_removes_only_annotation_lines(self):
+        text, _ = annotate_text(SOURCE, _syntax(SOURCE), churn="medium")
+        mixed = text + "x = 1  # shannon: not an annotation\n// shannon: notes for later\n"
+        assert remove_annotations(mixed) == (
+            SOURCE + "x = 1  # shannon: not an annotation\n// shannon: notes for later\n",
+            2,
+        )
+
+
+class TestAnnotateFiles:
+    def test_writes_and_reverts(self, tmp_path):
+        (tmp_path / "client.py").write_text(SOURCE)
+        (tmp_path / "lab.ipynb").write_text("{}")
+        files = {
+            "client.py": _syntax(SOURCE),
+            "lab.ipynb": make_syntax("lab.ipynb"),
+        }
+        dry = annotate_files(tmp_path, files, dry_run=True)
+        assert dry.changed == ["client.py"]
+        assert dry.skipped == ["lab.ipynb"]
+        assert (tmp_path / "client.py").read_text() == SOURCE
+
+        annotate_files(tmp_path, files, churn={"client.py": "high"})
+        assert "churn=high" in (tmp_path / "client.py").read_text()
+
+        result = annotate_files(tmp_path, files, remove=True)
+        assert result.functions == 2
+        assert (tmp_path / "client.py").read_text() == SOURCE
+
+
+class TestRankChurn:
+    def test_levels_by_share_of_files_changed_less(self):
+        counts = {f"f{i}.py": i for i in range(10)}
+        levels = rank_churn(counts)
+        assert [levels[f"f{i}.py"] for i in (0, 4, 5, 7, 8, 9)] == [
+            "low",
+            "low",
+            "medium",
+            "medium",
+            "high",
+            "high",
+        ]
+
+    def test_ties_share_a_level(self):
+        assert set(rank_churn({"a.py": 3, "b.py": 3, "c.py": 3}).values()) == {"low"}
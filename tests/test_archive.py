@@ -0,0 +1,173 @@
+"""Tests for history archives: ``history export`` / ``history import``."""
+
+import gzip
+import json
+
+import pytest
+
+from shannon_insight.persistence.archive import (
+    ArchiveError,
+    export_history,
+    import_history,
+    path_rewriter,
+)
+from shannon_insight.persistence.database import HistoryDB
+from shannon_insight.persistence.identity import compute_identity_key
+from shannon_insight.persistence.models import FindingRecord, Snapshot
+from shannon_insight.persistence.queries import HistoryQuery
+from shannon_insight.persistence.writer import save_snapshot
+
+
+def _snap(day: int, path: str = "src/app.py") -> Snapshot:
+    return Snapshot(
+        tool_version="0.8.0",
+        timestamp=f"2025-01-0{day}T00:00:00Z",
+        analyzed_path="/home/me/secret-project",
+        file_count=1,
+        commit_sha=f"sha{day}",
+        file_signals={path: {"cognitive_load": day / 10}},
+        codebase_signals={"codebase_health": 0.5},
+        findings=[
+            FindingRecord(
+                "god_file",
+                compute_identity_key("god_file", [path]),
+                0.7,
+                f"{path} does too much",
+                [path],
+                [],
+                "split it",
+            )
+        ],
+        dependency_edges=[(path, "src/util.py")],
+    )
+
+
+def _store(root, *snaps) -> HistoryDB:
+    db = HistoryDB(str(root), url="")
+    db.connect()
+    ids = [save_snapshot(db.conn, s) for s in snaps]
+    if ids:
+        db.set_baseline(ids[0])
+    return db
+
+
+def _records(path):
+    with gzip.open(path, "rt") as f:
+        return [json.loads(line) for line in f]
+
+
+@pytest.fixture
+def archive(tmp_path):
+    source = _store(tmp_path / "a", _snap(1), _snap(2))
+    path = tmp_path / "history.jsonl.gz"
+    export_history(source, path)
+    source.close()
+    return path
+
+
+class TestRoundTrip:
+    def test_import_into_empty_store(self, archive, tmp_path):
+        target = _store(tmp_path / "b")
+
+        stats = import_history(target, archive)
+
+        query = HistoryQuery(target.conn)
+        assert (stats.snapshots, stats.skipped) == (2, 0)
+        assert [p.value for p in query.file_trend("src/app.py", "cognitive_load")] == [0.1, 0.2]
+        assert [c["count"] for c in query.persistent_findings(min_snapshots=2)] == [2]
+        assert target.get_baseline_snapshot_id() == 1
+        lifecycle = target.conn.execute("SELECT * FROM finding_lifecycle").fetchone()
+        assert (lifecycle["persistence_count"], lifecycle["current_status"]) == (2, "active")
+
+    def test_merge_renumbers_and_skips_present_snapshots(self, archive, tmp_path):
+        target = _store(tmp_path / "b", _snap(2), _snap(3))
+
+        stats = import_history(target, archive)
+        again = import_history(target, archive)
+
+        shas = target.conn.execute("SELECT id, commit_sha FROM snapshots ORDER BY id").fetchall()
+        assert [(r["id"], r["commit_sha"]) for r in shas] == [(1, "sha2"), (2, "sha3"), (3, "sha1")]
+        assert (stats.snapshots, stats.skipped) == (1, 1)
+        assert (again.snapshots, again.skipped) == (0, 2)
+        # The target keeps its own baseline
+        assert target.get_baseline_snapshot_id() == 1
+
+    def test_rewrite_moves_paths_and_rekeys_findings(self, archive, tmp_path):
+        target = _store(tmp_path / "b")
+
+        import_history(target, archive, rewrite=path_rewriter([("src", "")]))
+
+        finding = target.conn.execute("SELECT identity_key, files FROM findings").fetchone()
+        edge = target.conn.execute("SELECT src, dst FROM dependency_edges").fetchone()
+        assert json.loads(finding["files"]) == ["app.py"]
+        assert finding["identity_key"] == compute_identity_key("god_file", ["app.py"])
+        assert (edge["src"], edge["dst"]) == ("app.py", "util.py")
+
+    def test_damaged_archive_leaves_store_untouched(self, archive, tmp_path):
+        truncated = tmp_path / "truncated.jsonl.gz"
+        lines = gzip.open(archive, "rt").read().splitlines()
+        with gzip.open(truncated, "wt") as f:
+            f.write("\n".join(lines[:-1]) + "\n")
+        target = _store(tmp_path / "b")
+
+        with pytest.raises(ArchiveError, match="truncated"):
+            import_history(target, truncated)
+
+        assert target.conn.execute("SELECT COUNT(*) AS n FROM snapshots").fetchone()["n"] == 0
+
+    def test_not_an_archive(self, tmp_path):
+        bogus = tmp_path / "bogus.jsonl.gz"
+        with gzip.open(bogus, "wt") as f:
+            f.write('{"hello": 1}\n')
+
+        with pytest.raises(ArchiveError, match="not a history archive"):
+            import_history(_store(tmp_path / "b"), bogus)
+
+
+class TestAnonymize:
+    def test_hides_paths_commits_and_text(self, tmp_path):
+        source = _store(tmp_path / "a", _snap(1))
+        path = tmp_path / "shared.jsonl.gz"
+
+        export_history(source, path, anonymize=True)
+
+        records = _records(path)
+        text = json.dumps(records)
+        assert records[0]["anonymized"] is True
+        for secret in ("src/app.py", "sha1", "secret-project", "does too much", "split it"):
+            assert secret not in text
+        files = [r["row"] for r in records if r.get("table") == "file_signals"]
+        edges = [r["row"] for r in records if r.get("table") == "dependency_edges"]
+        # Extensions survive and the same path maps to the same pseudonym
+        assert files[0]["file_path"].endswith(".py")
+        assert files[0]["file_path"].split("/")[0] == edges[0]["dst"].split("/")[0]
+
+    def test_anonymized_archive_imports(self, tmp_path):
+        source = _store(tmp_path / "a", _snap(1), _snap(2))
+        path = tmp_path / "shared.jsonl.gz"
+        export_history(source, path, anonymize=True)
+        target = _store(tmp_path / "b")
+
+        stats = import_history(target, path)
+
+        assert stats.snapshots == 2
+        assert len(HistoryQuery(target.conn).persistent_findings(min_snapshots=2)) == 1
+
+
+class TestPathRewriter:
+    @pytest.mark.parametrize(
+        "path, expected",
+        [
+            ("services/billing/api.py", "api.py"),
+            ("services/billing", ""),
+            ("services/billing2/x.py", "services/billing2/x.py"),
+            ("lib/x.py", "vendor/lib/x.py"),
+        ],
+    )
+    def test_prefixes(self, path, expected):
+        rewrite = path_rewriter([("services/billing/", ""), ("lib", "vendor/lib")])
+
+        assert rewrite(path) == expected
+
+    def test_empty_old_prefixes_everything(self):
+        assert path_rewriter([("", "svc")])("a/b.py") == "svc/a/b.py"
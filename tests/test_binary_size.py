@@ -0,0 +1,195 @@
+"""Tests for Go binary size attribution."""
+
+import shutil
+import struct
+
+import pytest
+
+from shannon_insight.binary_size import (
+    RUNTIME_TABLES,
+    STANDARD_LIBRARY,
+    BinaryError,
+    GoBinary,
+    Symbol,
+    analyze_binary,
+    build_binary,
+    local_modules,
+    package_of_symbol,
+    read_binary,
+    short_symbol,
+)
+from shannon_insight.hygiene import load_sources
+
+MODINFO = (
+    "path\texample.com/shop/cmd/server\n"
+    "mod\texample.com/shop\t(devel)\t\n"
+    "dep\tgithub.com/lib/pq\tv1.10.9\th1:abc=\n"
+)
+
+
+def _elf(symbols, modinfo=MODINFO):
+    """A little-endian ELF64 file with (name, size, section) symbols.
+
+    Sections: 1 .text (code), 2 .rodata, 3 .bss, then .go.buildinfo.
+    """
+    names = b"\0"
+    name_at = {}
+    for name in (".text", ".rodata", ".bss", ".go.buildinfo", ".symtab", ".strtab", ".shstrtab"):
+        name_at[name] = len(names)
+        names += name.encode() + b"\0"
+    strtab = b"\0"
+    symtab = bytes(24)  # the null symbol
+    for name, size, section in symbols:
+        symtab += struct.pack("<IBBHQQ", len(strtab), 0x11, 0, section, 0, size)
+        strtab += name.encode() + b"\0"
+    info = b"\xf0" * 16 + modinfo.encode() + b"\xf1" * 16  # between sentinels
+    buildinfo = b"\xff Go buildinf:\x08\x02" + bytes(16)
+    for text in (b"go1.22.0", info):
+        buildinfo += bytes([len(text) & 0x7F | 0x80, len(text) >> 7]) + text
+
+    body = b""
+    offsets = {}
+    blobs = {".go.buildinfo": buildinfo, ".symtab": symtab, ".strtab": strtab, ".shstrtab": names}
+    for name, blob in blobs.items():
+        offsets[name] = 64 + len(body)
+        body += blob
+    shoff = 64 + len(body)
+
+    def section(name, kind, flags=0, offset=0, size=0, link=0):
+        fields = (name_at.get(name, 0), kind, flags, 0, offset, size, link, 0, 1, 0)
+        return struct.pack("<IIQQQQIIQQ", *fields)
+
+    headers = section("", 0)
+    headers += section(".text", 1, flags=0x6)
+    headers += section(".rodata", 1, flags=0x2)
+    headers += section(".bss", 8, flags=0x3)
+    headers += section(".go.buildinfo", 1, 0x3, offsets[".go.buildinfo"], len(buildinfo))
+    headers += section(".symtab", 2, 0, offsets[".symtab"], len(symtab), link=6)
+    headers += section(".strtab", 3, 0, offsets[".strtab"], len(strtab))
+    headers += section(".shstrtab", 3, 0, offsets[".shstrtab"], len(names))
+    elf_header = b"\x7fELF\x02\x01\x01" + bytes(9)
+    elf_header += struct.pack("<HHIQQQIHHHHHH", 2, 62, 1, 0, 0, shoff, 0, 64, 0, 0, 64, 8, 7)
+    return elf_header + body + headers
+
+
+@pytest.mark.parametrize(
+    "name, package",
+    [
+        ("example.com/shop/store.(*DB).Get", "example.com/shop/store"),
+        ("example.com/shop/store.Map[go.shape.string]", "example.com/shop/store"),
+        ("main.main", "main"),
+        ("fmt.Println", "fmt"),
+        ("type:*example.com/shop/store.DB", "example.com/shop/store"),
+        ("gopkg.in/yaml%2ev3.Marshal", "gopkg.in/yaml.v3"),
+        ("vendor/golang.org/x/net/http2.init", "golang.org/x/net/http2"),
+        ("go:func.*", None),
+        ("type:.eq.M16", None),
+    ],
+)
+def test_package_of_symbol(name, package):
+    assert package_of_symbol(name) == package
+
+
+def test_short_symbol():
+    assert short_symbol("example.com/shop/store.(*DB).Get") == "store.(*DB).Get"
+    assert short_symbol("type:*example.com/shop/store.DB") == "type:*store.DB"
+
+
+class TestReadBinary:
+    def test_elf_symbols_and_build_info(self, tmp_path):
+        path = tmp_path / "server"
+        path.write_bytes(
+            _elf(
+                [
+                    ("main.main", 300, 1),
+                    ("example.com/shop/assets.Table", 4000, 2),
+                    ("runtime.mheap_", 9000, 3),
+                    ("runtime.zero", 0, 2),
+                ]
+            )
+        )
+
+        binary = read_binary(path)
+
+        assert binary.symbols == [
+            Symbol("main.main", 300, True),
+            Symbol("example.com/shop/assets.Table", 4000, False),
+        ]
+        assert binary.main_path == "example.com/shop/cmd/server"
+        assert binary.modules == ["example.com/shop", "github.com/lib/pq"]
+
+    def test_stripped_binary(self, tmp_path):
+        path = tmp_path / "server"
+        path.write_bytes(_elf([]))
+
+        with pytest.raises(BinaryError, match="no symbol table"):
+            read_binary(path)
+
+
+class TestAnalyzeBinary:
+    def test_attributes_bytes_and_flags_bloated_packages(self, tmp_path):
+        files = {
+            "go.mod": "module example.com/shop\n\ngo 1.22\n",
+            "cmd/server/main.go": "package main\n\n" + "func f() {}\n" * 20,
+            "store/db.go": "package store\n\n" + "func g() {}\n" * 40,
+            "store/db_test.go": "package store\n\n" + "func TestG() {}\n" * 500,
+            "assets/assets.go": 'package assets\n\nimport "embed"\n\n//go:embed static\n'
+            "var Static embed.FS\n",
+        }
+        for name, text in files.items():
+            (tmp_path / name).parent.mkdir(parents=True, exist_ok=True)
+            (tmp_path / name).write_text(text)
+        binary = GoBinary(
+            tmp_path / "server",
+            1_000_000,
+            [
+                Symbol("main.main", 2_000, True),
+                Symbol("example.com/shop/store.(*DB).Get", 6_000, True),
+                Symbol("example.com/shop/assets.Static", 150_000, False),
+                Symbol("github.com/lib/pq/oid.init", 30_000, True),
+                Symbol("fmt.Println", 10_000, True),
+                Symbol("go:func.*", 5_000, False),
+            ],
+            main_path="example.com/shop/cmd/server",
+            modules=["example.com/shop", "github.com/lib/pq"],
+        )
+
+        report = analyze_binary(binary, load_sources(tmp_path))
+
+        assert [(p.directory, p.bytes, p.lines) for p in report.local] == [
+            ("assets", 150_000, 4),
+            ("store", 6_000, 41),
+            ("cmd/server", 2_000, 21),
+        ]
+        (bloated,) = report.bloated()
+        assert bloated.package == "example.com/shop/assets"
+        assert bloated.ratio > 10
+        assert bloated.causes == ["embedded files", "large symbol assets.Static"]
+        assert report.external == {
+            "github.com/lib/pq": 30_000,
+            STANDARD_LIBRARY: 10_000,
+            RUNTIME_TABLES: 5_000,
+        }
+
+    def test_nested_modules(self, tmp_path):
+        (tmp_path / "services" / "api").mkdir(parents=True)
+        (tmp_path / "services" / "api" / "go.mod").write_text("module example.com/api // api\n")
+
+        assert local_modules(tmp_path, ["services/api/go.mod"]) == {
+            "example.com/api": "services/api"
+        }
+
+
+@pytest.mark.skipif(shutil.which("go") is None, reason="go not installed")
+def test_builds_and_reads_a_real_binary(tmp_path):
+    (tmp_path / "go.mod").write_text("module example.com/tiny\n\ngo 1.21\n")
+    (tmp_path / "main.go").write_text(
+        'package main\n\nimport "fmt"\n\nfunc main() { fmt.Println("hi") }\n'
+    )
+
+    binary = read_binary(build_binary(tmp_path, ".", tmp_path))
+    report = analyze_binary(binary, load_sources(tmp_path))
+
+    assert binary.main_path == "example.com/tiny"
+    assert [p.package for p in report.local] == ["example.com/tiny"]
+    assert report.external[STANDARD_LIBRARY] > report.local_bytes
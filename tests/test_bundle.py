@@ -0,0 +1,117 @@
+"""Tests for compressed result bundles."""
+
+import pytest
+
+from shannon_insight.persistence.bundle import (
+    BLOCK_RECORDS,
+    BundleError,
+    ResultBundle,
+    compare_bundles,
+    is_bundle,
+    write_bundle,
+)
+from shannon_insight.persistence.models import EvidenceRecord, FindingRecord, TensorSnapshot
+
+
+def _snapshot(n_files=3, load=1.0):
+    files = {
+        f"src/f{i:04d}.py": {"cognitive_load": load * i, "role": "MODEL"} for i in range(n_files)
+    }
+    return TensorSnapshot(
+        commit_sha="abc123",
+        timestamp="2025-01-01T00:00:00Z",
+        file_count=n_files,
+        file_signals=files,
+        module_signals={"src": {"instability": 0.5}},
+        global_signals={"codebase_health": 0.7},
+        findings=[
+            FindingRecord(
+                finding_type="god_file",
+                identity_key="k1",
+                severity=0.9,
+                title="God file",
+                files=["src/f0001.py"],
+                evidence=[EvidenceRecord("cognitive_load", 9.0, 99.0, "top 1%")],
+                suggestion="Split it",
+            )
+        ],
+        dependency_edges=[("src/f0001.py", "src/f0002.py")],
+        delta_h={"src/f0001.py": 0.25},
+    )
+
+
+class TestRoundTrip:
+    def test_zlib_roundtrip(self, tmp_path):
+        path = tmp_path / "run.sib"
+        snapshot = _snapshot()
+        write_bundle(path, snapshot, codec="zlib")
+
+        with ResultBundle(path) as bundle:
+            restored = bundle.to_snapshot()
+
+        assert is_bundle(path)
+        assert restored.file_signals == snapshot.file_signals
+        assert restored.module_signals == snapshot.module_signals
+        assert restored.global_signals == snapshot.global_signals
+        assert restored.findings == snapshot.findings
+        assert restored.dependency_edges == snapshot.dependency_edges
+        assert restored.delta_h == snapshot.delta_h
+        assert restored.commit_sha == "abc123"
+
+    def test_random_access_across_blocks(self, tmp_path):
+        path = tmp_path / "big.sib"
+        write_bundle(path, _snapshot(n_files=BLOCK_RECORDS * 3), codec="zlib")
+
+        with ResultBundle(path) as bundle:
+            last = f"src/f{BLOCK_RECORDS * 3 - 1:04d}.py"
+            assert bundle.file_signals(last)["cognitive_load"] == BLOCK_RECORDS * 3 - 1
+            assert bundle.file_signals("src/f0005.py")["cognitive_load"] == 5
+            assert bundle.file_signals("missing.py") is None
+            assert len(bundle.files()) == BLOCK_RECORDS * 3
+            assert sum(1 for _ in bundle.iter_file_signals()) == BLOCK_RECORDS * 3
+
+    def test_smaller_than_json(self, tmp_path):
+        import json
+        from dataclasses import asdict
+
+        snapshot = _snapshot(n_files=500)
+        size = write_bundle(tmp_path / "run.sib", snapshot, codec="zlib")
+
+        assert size < len(json.dumps(asdict(snapshot))) / 3
+
+
+class TestErrors:
+    def test_not_a_bundle(self, tmp_path):
+        path = tmp_path / "x.json"
+        path.write_text("{}")
+
+        with pytest.raises(BundleError):
+            ResultBundle(path)
+        assert not is_bundle(path)
+
+    def test_truncated(self, tmp_path):
+        path = tmp_path / "run.sib"
+        write_bundle(path, _snapshot(), codec="zlib")
+        path.write_bytes(path.read_bytes()[:-4])
+
+        with pytest.raises(BundleError):
+            ResultBundle(path)
+
+    def test_unknown_codec(self, tmp_path):
+        with pytest.raises(ValueError):
+            write_bundle(tmp_path / "run.sib", _snapshot(), codec="lz4")
+
+
+class TestCompare:
+    def test_file_deltas(self, tmp_path):
+        old, new = tmp_path / "old.sib", tmp_path / "new.sib"
+        write_bundle(old, _snapshot(n_files=3), codec="zlib")
+        write_bundle(new, _snapshot(n_files=4, load=2.0), codec="zlib")
+
+        with ResultBundle(old) as a, ResultBundle(new) as b:
+            result = compare_bundles(a, b, signals=["cognitive_load"])
+
+        assert result.files_added == ["src/f0003.py"]
+        assert sorted(result.signal_deltas) == ["src/f0001.py", "src/f0002.py"]
+        assert result.signal_deltas["src/f0002.py"][0].delta == pytest.approx(2.0)
+        assert result.worsening == ["src/f0001.py", "src/f0002.py"]
@@ -0,0 +1,50 @@
+"""Tests for line coverage report ingestion."""
+
+from shannon_insight.coverage import find_report, load_line_coverage
+
+COBERTURA = """<?xml version="1.0" ?>
+<coverage>
+  <sources><source>{root}</source></sources>
+  <packages><package><classes>
+    <class filename="pkg/mod.py">
+      <lines>
+        <line number="1" hits="2"/>
+        <line number="2" hits="0"/>
+      </lines>
+    </class>
+  </classes></package></packages>
+</coverage>
+"""
+
+
+class TestLoadLineCoverage:
+    def test_no_report(self, tmp_path):
+        assert find_report(tmp_path) is None
+        assert load_line_coverage(tmp_path) == {}
+
+    def test_cobertura(self, tmp_path):
+        (tmp_path / "pkg").mkdir()
+        (tmp_path / "pkg" / "mod.py").write_text("a\nb\n")
+        (tmp_path / "coverage.xml").write_text(COBERTURA.format(root=tmp_path))
+        assert load_line_coverage(tmp_path) == {"pkg/mod.py": {1: 2, 2: 0}}
+
+    def test_lcov_absolute_paths(self, tmp_path):
+        (tmp_path / "m.go").write_text("package m\n")
+        (tmp_path / "lcov.info").write_text(f"TN:\nSF:{tmp_path / 'm.go'}\nDA:1,5\nend_of_record\n")
+        assert load_line_coverage(tmp_path) == {"m.go": {1: 5}}
+
+    def test_garbage_report_is_ignored(self, tmp_path):
+        (tmp_path / "coverage.xml").write_text("<coverage><unclosed>")
+        assert load_line_coverage(tmp_path) == {}
+
+    def test_go_cover_profile(self, tmp_path):
+        (tmp_path / "go.mod").write_text("module github.com/acme/app\n\ngo 1.22\n")
+        (tmp_path / "coverage.out").write_text(
+            "mode: count\n"
+            "github.com/acme/app/pkg/x.go:3.20,5.16 2 4\n"
+            "github.com/acme/app/pkg/x.go:5.16,7.3 1 0\n"
+            "github.com/other/dep/y.go:1.1,1.9 1 1\n"
+        )
+        coverage = load_line_coverage(tmp_path)
+        assert coverage["pkg/x.go"] == {3: 4, 4: 4, 5: 4, 6: 0, 7: 0}
+        assert coverage["github.com/other/dep/y.go"] == {1: 1}
@@ -13,6 +13,7 @@ from shannon_insight.persistence.queries import (
     get_finding_lifecycle_map,
     get_global_signal_time_series,
     get_module_signal_time_series,
+    get_resolved_findings,
     get_signal_time_series,
     update_finding_lifecycle,
 )
@@ -443,6 +444,42 @@ class TestFindingLifecycleQueries:
                 assert chronic[1].files == ["main.py"]
                 assert chronic[1].title == "God file: main.py"
 
+    def test_get_resolved_findings(self):
+        """Test querying fixed findings of one type, for triage."""
+        with tempfile.TemporaryDirectory() as tmpdir:
+            with HistoryDB(tmpdir) as db:
+                for i in range(1, 4):
+                    db.conn.execute(
+                        "INSERT INTO snapshots (id, tool_version, timestamp, analyzed_path) "
+                        "VALUES (?, '0.7.0', '2025-01-01', '/tmp')",
+                        (i,),
+                    )
+                rows = [
+                    ("g1", 2, "resolved", "god_class", "Parser has 40 methods", "src/parse.py"),
+                    ("g2", 3, "resolved", "god_class", "Engine has 30 methods", "src/engine.py"),
+                    ("g3", 3, "active", "god_class", "Store has 25 methods", "src/store.py"),
+                    ("h1", 3, "resolved", "god_file", "God file: main.py", "main.py"),
+                ]
+                for key, last, status, finding_type, title, path in rows:
+                    db.conn.execute(
+                        "INSERT INTO finding_lifecycle VALUES (?, 1, ?, 1, ?, ?, 0.6)",
+                        (key, last, status, finding_type),
+                    )
+                    db.conn.execute(
+                        "INSERT INTO findings "
+                        "(snapshot_id, finding_type, identity_key, severity, title, files) "
+                        "VALUES (?, ?, ?, 0.6, ?, ?)",
+                        (last, finding_type, key, title, f'["{path}"]'),
+                    )
+                db.conn.commit()
+
+                resolved = get_resolved_findings(db.conn, "god_class")
+
+                # Most recently seen first; active findings and other types left out
+                assert [r.identity_key for r in resolved] == ["g2", "g1"]
+                assert resolved[0].files == ["src/engine.py"]
+                assert resolved[0].title == "Engine has 30 methods"
+
     def test_update_finding_lifecycle_new(self):
         """Test updating lifecycle for a new finding."""
         with tempfile.TemporaryDirectory() as tmpdir:
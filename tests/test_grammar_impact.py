@@ -0,0 +1,138 @@
+"""Tests for recorded grammar versions and the grammar-impact report."""
+
+import subprocess
+
+from shannon_insight import __version__
+from shannon_insight.persistence.database import HistoryDB
+from shannon_insight.persistence.grammar_impact import (
+    changed_files,
+    grammar_impact,
+    latest_upgrade,
+    recorded_grammars,
+)
+from shannon_insight.persistence.models import TensorSnapshot
+from shannon_insight.persistence.reader import load_tensor_snapshot
+from shannon_insight.persistence.writer import save_tensor_snapshot
+from shannon_insight.scanning.grammar_versions import grammar_versions
+
+OLD = {"go": "tree-sitter-go 0.21.0 (tree-sitter 0.22.0)", "python": "regex 0.9.0"}
+NEW = {"go": "tree-sitter-go 0.23.1 (tree-sitter 0.22.0)", "python": "regex 0.9.0"}
+
+
+def _snapshot(versions, file_signals, commit="abc123"):
+    return TensorSnapshot(
+        commit_sha=commit,
+        timestamp="2026-10-01T12:00:00Z",
+        analyzed_path="/project",
+        file_count=len(file_signals),
+        config_hash="cfg",
+        grammar_versions=versions,
+        file_signals=file_signals,
+    )
+
+
+def _save(db, *snapshots):
+    return [save_tensor_snapshot(db.conn, snap) for snap in snapshots]
+
+
+class TestGrammarVersions:
+    def test_regex_fallback_and_custom_library(self, tmp_path):
+        (tmp_path / "elixir.so").write_bytes(b"grammar")
+        versions = grammar_versions(
+            ["python", "elixir", "python"],
+            grammars={"elixir": {"library": "elixir.so", "extensions": [".ex"]}},
+            root=tmp_path,
+        )
+        assert versions["elixir"].startswith("custom elixir.so ")
+        assert len(versions["elixir"].split()[-1]) == 12
+        assert set(versions) == {"elixir", "python"}
+        if versions["python"].startswith("regex"):
+            assert versions["python"] == f"regex {__version__}"
+
+    def test_missing_custom_library(self, tmp_path):
+        versions = grammar_versions(
+            ["elixir"],
+            grammars={"elixir": {"library": "gone.so", "extensions": [".ex"]}},
+            root=tmp_path,
+        )
+        assert versions["elixir"] == "custom gone.so missing"
+
+    def test_round_trip_through_history(self, tmp_path):
+        with HistoryDB(str(tmp_path)) as db:
+            [snap_id] = _save(db, _snapshot(OLD, {"a.go": {"lines": 10}}))
+            assert load_tensor_snapshot(db.conn, snap_id).grammar_versions == OLD
+
+
+class TestGrammarImpact:
+    def test_latest_upgrade_skips_unchanged_pairs(self):
+        recorded = {1: OLD, 2: NEW, 3: NEW}
+        assert latest_upgrade(recorded) == (1, 2)
+        assert latest_upgrade({1: OLD, 2: OLD}) is None
+
+    def test_only_unchanged_files_are_attributed(self, tmp_path):
+        before = {
+            "a.go": {"lines": 40, "function_count": 3, "max_nesting": 2},
+            "b.go": {"lines": 20, "function_count": 1, "max_nesting": 1},
+            "c.go": {"lines": 30, "function_count": 2},
+            "d.py": {"lines": 10, "function_count": 1},
+        }
+        after = {
+            "a.go": {"lines": 40, "function_count": 4, "max_nesting": 3},
+            "b.go": {"lines": 20, "function_count": 1, "max_nesting": 1},
+            "c.go": {"lines": 35, "function_count": 5},  # edited as well
+            "d.py": {"lines": 10, "function_count": 2},  # parser unchanged
+        }
+        with HistoryDB(str(tmp_path)) as db:
+            first, second = _save(db, _snapshot(OLD, before), _snapshot(NEW, after))
+            assert recorded_grammars(db.conn) == {first: OLD, second: NEW}
+            impact = grammar_impact(db.conn, tmp_path, first, second)
+
+        assert impact.git_diff  # same commit: nothing changed per git
+        assert not impact.config_changed
+        [go] = impact.languages
+        assert go.language == "go"
+        assert (go.unchanged, go.code_changed) == (3, 0)
+        [a, c] = go.files
+        assert a.path == "a.go"
+        assert a.shifts == {"function_count": (3.0, 4.0), "max_nesting": (2.0, 3.0)}
+        assert c.path == "c.go"
+        assert go.signal_totals()["function_count"] == (2, 2.0)
+
+    def test_line_counts_stand_in_without_git(self, tmp_path):
+        before = {"a.go": {"lines": 40, "function_count": 3}, "c.go": {"lines": 30}}
+        after = {"a.go": {"lines": 40, "function_count": 4}, "c.go": {"lines": 35}}
+        with HistoryDB(str(tmp_path)) as db:
+            first, second = _save(
+                db, _snapshot(OLD, before, commit="1" * 40), _snapshot(NEW, after, commit="2" * 40)
+            )
+            impact = grammar_impact(db.conn, tmp_path, first, second)
+
+        assert not impact.git_diff
+        [go] = impact.languages
+        assert (go.unchanged, go.code_changed) == (1, 1)
+        assert impact.to_dict()["languages"][0]["signals"] == {
+            "function_count": {"files": 1, "mean_delta": 1.0}
+        }
+
+    def test_changed_files_from_git(self, tmp_path):
+        def git(*args):
+            return subprocess.run(
+                ["git", "-C", str(tmp_path), *args], capture_output=True, text=True, check=True
+            ).stdout.strip()
+
+        git("init", "-q")
+        git("config", "user.email", "dev@example.com")
+        git("config", "user.name", "Dev")
+        (tmp_path / "a.go").write_text("package a\n")
+        (tmp_path / "b.go").write_text("package b\n")
+        git("add", ".")
+        git("commit", "-q", "-m", "first")
+        old = git("rev-parse", "HEAD")
+        (tmp_path / "b.go").write_text("package b\n\nfunc B() {}\n")
+        git("commit", "-q", "-am", "second")
+        new = git("rev-parse", "HEAD")
+
+        assert changed_files(tmp_path, old, new) == {"b.go"}
+        assert changed_files(tmp_path, old, old) == set()
+        assert changed_files(tmp_path, None, new) is None
+        assert changed_files(tmp_path, old, "0" * 40) is None
@@ -10,10 +10,10 @@ from shannon_insight.graph.algorithms import (
 )
 from shannon_insight.graph.builder import build_dependency_graph
 from shannon_insight.graph.models import DependencyGraph
-from shannon_insight.scanning.syntax import FileSyntax, ImportDecl
+from shannon_insight.scanning.syntax import ClassDef, FileSyntax, ImportDecl
 
 
-def _fs(path: str, imports: list[str] | None = None) -> FileSyntax:
+def _fs(path: str, imports: list[str] | None = None, language: str = "python") -> FileSyntax:
     """Shortcut to build a minimal FileSyntax."""
     import_decls = [ImportDecl(source=imp, names=[]) for imp in (imports or [])]
     return FileSyntax(
@@ -21,7 +21,7 @@ def _fs(path: str, imports: list[str] | None = None) -> FileSyntax:
         functions=[],
         classes=[],
         imports=import_decls,
-        language="python",
+        language=language,
     )
 
 
@@ -76,6 +76,207 @@ class TestBuildDependencyGraph:
         graph = build_dependency_graph(metrics)
         assert graph.edge_count == 0
 
+    def test_alias_resolves_to_canonical_copy(self):
+        # vendor/pkg/b.py was collapsed into pkg/b.py
+        metrics = [
+            _fs("vendor/pkg/a.py", imports=[".b"]),
+            _fs("pkg/b.py"),
+        ]
+        graph = build_dependency_graph(metrics, aliases={"vendor/pkg/b.py": "pkg/b.py"})
+        assert graph.adjacency["vendor/pkg/a.py"] == ["pkg/b.py"]
+        assert "vendor/pkg/b.py" not in graph.all_nodes
+        assert graph.unresolved_imports == {}
+
+    def test_c_includes_resolve_like_the_compiler(self):
+        metrics = [
+            _fs("src/net/socket.c", ["socket.h", "util/log.h", "../core/io.h"], language="c"),
+            _fs("src/net/socket.h", language="c"),
+            _fs("src/core/io.h", language="c"),
+            _fs("src/include/util/log.h", language="c"),
+            _fs("third_party/util/log.h", language="c"),
+            _fs("src/main.cpp", imports=["net/socket.h", "stdio.h"], language="cpp"),
+        ]
+        graph = build_dependency_graph(metrics)
+        # Beside the includer, then from the root, then the closest include directory
+        assert sorted(graph.adjacency["src/net/socket.c"]) == [
+            "src/core/io.h",
+            "src/include/util/log.h",
+            "src/net/socket.h",
+        ]
+        assert graph.adjacency["src/main.cpp"] == ["src/net/socket.h"]
+        assert "src/main.cpp" not in graph.unresolved_imports
+
+    def test_ruby_constants_resolve_through_rails_autoloading(self):
+        imports = ["ApplicationRecord", "Admin::UserSettings", "Searchable", "Devise"]
+        metrics = [
+            _fs("app/models/post.rb", imports=imports, language="ruby"),
+            _fs("app/models/application_record.rb", language="ruby"),
+            _fs("app/models/admin/user_settings.rb", language="ruby"),
+            _fs("app/models/concerns/searchable.rb", language="ruby"),
+            _fs("spec/models/searchable.rb", language="ruby"),
+        ]
+        graph = build_dependency_graph(metrics)
+        assert sorted(graph.adjacency["app/models/post.rb"]) == [
+            "app/models/admin/user_settings.rb",
+            "app/models/application_record.rb",
+            "app/models/concerns/searchable.rb",
+        ]
+        # Constants from gems are not phantoms
+        assert graph.unresolved_imports == {}
+
+    def test_php_classes_resolve_through_psr4(self):
+        imports = [
+            "App\\Models\\User",
+            "App\\Support\\Slug",
+            "App\\Models\\Missing",
+            "Illuminate\\Support\\Str",
+            "RuntimeException",
+            "./../../bootstrap.php",
+        ]
+        metrics = [
+            _fs("app/Http/PostController.php", imports=imports, language="php"),
+            _fs("app/Models/User.php", language="php"),
+            _fs("app/Support/Slug.php", language="php"),
+            _fs("tests/Support/Slug.php", language="php"),
+            _fs("bootstrap.php", language="php"),
+        ]
+        graph = build_dependency_graph(metrics)
+        assert sorted(graph.adjacency["app/Http/PostController.php"]) == [
+            "app/Models/User.php",
+            "app/Support/Slug.php",
+            "bootstrap.php",
+        ]
+        # App\ maps to app/, so a class missing there is a phantom; vendor
+        # classes are third-party and global ones built in
+        assert graph.unresolved_imports == {
+            "app/Http/PostController.php": ["App\\Models\\Missing"]
+        }
+        assert graph.external_imports == {
+            "app/Http/PostController.php": ["Illuminate\\Support\\Str"]
+        }
+
+    def test_scala_imports_resolve_within_sbt_projects(self, tmp_path):
+        (tmp_path / "build.sbt").write_text(
+            "lazy val core = project\n"
+            "lazy val legacy = project\n"
+            "lazy val api = project.dependsOn(core)\n"
+        )
+        imports = [
+            "com.acme.model.User",
+            "com.acme.model.Order",
+            "com.acme.util._",
+            "com.acme.util.Strings.slug",
+            "com.acme.model.Missing",
+            "cats.effect.IO",
+            "scala.concurrent.Future",
+        ]
+        metrics = [
+            _fs("api/src/main/scala/com/acme/api/Routes.scala", imports, language="scala"),
+            _fs("core/src/main/scala/com/acme/model/User.scala", language="scala"),
+            _fs("legacy/src/main/scala/com/acme/model/User.scala", language="scala"),
+            _fs("core/src/main/scala/com/acme/model/Models.scala", language="scala"),
+            _fs("core/src/main/scala/com/acme/util/package.scala", language="scala"),
+            _fs("core/src/main/scala/com/acme/util/Strings.scala", language="scala"),
+        ]
+        # Order is declared in Models.scala, not a file of its own
+        metrics[3].classes.append(ClassDef(name="Order", bases=[], methods=[], fields=[]))
+        graph = build_dependency_graph(metrics, str(tmp_path))
+        # api depends on core, so legacy's User is not the one imported
+        assert graph.adjacency["api/src/main/scala/com/acme/api/Routes.scala"] == [
+            "core/src/main/scala/com/acme/model/User.scala",
+            "core/src/main/scala/com/acme/model/Models.scala",
+            "core/src/main/scala/com/acme/util/package.scala",
+            "core/src/main/scala/com/acme/util/Strings.scala",
+        ]
+        assert graph.unresolved_imports == {}
+        assert graph.external_imports == {
+            "api/src/main/scala/com/acme/api/Routes.scala": ["cats.effect.IO"]
+        }
+
+    def test_swift_module_imports(self):
+        imports = ["UIKit", "Combine", "FeedCore", "Alamofire"]
+        metrics = [
+            _fs("App/Feed/FeedViewController.swift", imports, language="swift"),
+            _fs("Sources/FeedCore/Models/Item.swift", language="swift"),
+        ]
+        graph = build_dependency_graph(metrics)
+        # Modules are not files: FeedCore is this codebase's own, Alamofire a package
+        assert graph.adjacency["App/Feed/FeedViewController.swift"] == []
+        assert graph.unresolved_imports == {}
+        assert graph.external_imports == {"App/Feed/FeedViewController.swift": ["Alamofire"]}
+
+    def test_sql_tables_resolve_to_creating_migration(self):
+        create = _fs("db/001_users.sql", ["roles"], language="sql")
+        create.classes = [ClassDef("Users", [], [], [])]
+        roles = _fs("db/000_roles.sql", language="sql")
+        roles.classes = [ClassDef("roles", [], [], [])]
+        alter = _fs("db/002_add_email.sql", ["users", "pg_catalog_table"], language="sql")
+        graph = build_dependency_graph([create, roles, alter])
+        # Tables created nowhere in the codebase are neither edges nor phantoms
+        assert graph.adjacency["db/002_add_email.sql"] == ["db/001_users.sql"]
+        assert graph.adjacency["db/001_users.sql"] == ["db/000_roles.sql"]
+        assert graph.unresolved_imports == {}
+
+    def test_terraform_module_sources_resolve_to_module_directory(self):
+        root = _fs(
+            "envs/prod/main.tf",
+            ["../../modules/vpc", "./dns", "terraform-aws-modules/eks/aws"],
+            language="hcl",
+        )
+        vpc_vars = _fs("modules/vpc/variables.tf", language="hcl")
+        vpc_main = _fs("modules/vpc/main.tf", language="hcl")
+        graph = build_dependency_graph([root, vpc_vars, vpc_main])
+        assert graph.adjacency["envs/prod/main.tf"] == ["modules/vpc/main.tf"]
+        # A local module that does not exist is a phantom; registry modules are external
+        assert graph.unresolved_imports == {"envs/prod/main.tf": ["./dns"]}
+        assert graph.external_imports == {"envs/prod/main.tf": ["terraform-aws-modules/eks/aws"]}
+
+    def test_proto_imports_and_stubs_resolve_to_definitions(self):
+        user = _fs(
+            "proto/acme/user.proto",
+            [
+                "acme/page.proto",
+                "google/protobuf/timestamp.proto",
+                "acme/missing.proto",
+                "validate/validate.proto",
+            ],
+            language="proto",
+        )
+        page = _fs("proto/acme/page.proto", language="proto")
+        go_stub = _fs("gen/go/acme/user.pb.go", language="go")
+        py_stub = _fs("clients/user_pb2_grpc.py", ["clients.user_pb2"])
+        py_messages = _fs("clients/user_pb2.py")
+        graph = build_dependency_graph([user, page, go_stub, py_stub, py_messages])
+        assert graph.adjacency["proto/acme/user.proto"] == ["proto/acme/page.proto"]
+        assert graph.adjacency["gen/go/acme/user.pb.go"] == ["proto/acme/user.proto"]
+        assert graph.adjacency["clients/user_pb2_grpc.py"] == [
+            "clients/user_pb2.py",
+            "proto/acme/user.proto",
+        ]
+        # Well-known types are built in; missing files next to other .proto files are
+        # phantoms, and directories the codebase does not have belong to dependencies
+        assert graph.unresolved_imports == {"proto/acme/user.proto": ["acme/missing.proto"]}
+        assert graph.external_imports == {"proto/acme/user.proto": ["validate/validate.proto"]}
+
+    def test_notebook_imports_resolve_from_its_directory(self):
+        notebook = _fs("analysis/eda.ipynb", ["helpers", "pandas"])
+        helpers = _fs("analysis/helpers.py")
+        other = _fs("helpers.py")
+        graph = build_dependency_graph([notebook, helpers, other])
+        assert graph.adjacency["analysis/eda.ipynb"] == ["analysis/helpers.py"]
+
+    def test_third_party_imports_tracked(self):
+        metrics = [
+            _fs("app/a.py", imports=["os.path", "requests.adapters", ".missing", ".b"]),
+            _fs("app/b.py"),
+            _fs("web/app.ts", ["@tanstack/react-query", "node:fs"], language="typescript"),
+        ]
+        graph = build_dependency_graph(metrics)
+        assert graph.external_imports == {
+            "app/a.py": ["requests.adapters"],
+            "web/app.ts": ["@tanstack/react-query"],
+        }
+
 
 # ── tarjan_scc ────────────────────────────────────────────────────
 
@@ -0,0 +1,61 @@
+"""Throughput benchmarks for the per-file hashing fast path.
+
+Content digests (cache keys) and winnowing fingerprints (clone detection)
+run over every analyzed file, so they must stay above 100k LOC/sec on one
+core. Measured on this package's own source as a realistic corpus.
+
+Marked with @pytest.mark.slow to skip in normal test runs.
+Run with: pytest tests/test_hashing_performance.py -v --run-slow
+"""
+
+import time
+from pathlib import Path
+
+import pytest
+
+from shannon_insight.graph.winnowing import fingerprints
+from shannon_insight.portable import content_digest, file_digest
+
+SOURCE_ROOT = Path(__file__).parent.parent / "src" / "shannon_insight"
+MIN_LOC_PER_SEC = 100_000
+
+
+def _corpus() -> list[bytes]:
+    return [path.read_bytes() for path in sorted(SOURCE_ROOT.rglob("*.py"))]
+
+
+def _loc_per_sec(fn, corpus: list[bytes], repeats: int = 3) -> float:
+    """Best of *repeats* runs, to keep scheduler noise out of the gate."""
+    loc = sum(content.count(b"\n") for content in corpus)
+    best = float("inf")
+    for _ in range(repeats):
+        start = time.perf_counter()
+        for content in corpus:
+            fn(content)
+        best = min(best, time.perf_counter() - start)
+    return loc / best
+
+
+class TestHashingThroughput:
+    @pytest.mark.slow
+    def test_content_digest(self):
+        assert _loc_per_sec(content_digest, _corpus()) > MIN_LOC_PER_SEC
+
+    @pytest.mark.slow
+    def test_file_digest(self):
+        paths = sorted(SOURCE_ROOT.rglob("*.py"))
+        loc = sum(path.read_bytes().count(b"\n") for path in paths)
+        start = time.perf_counter()
+        for path in paths:
+            file_digest(path)
+        assert loc / (time.perf_counter() - start) > MIN_LOC_PER_SEC
+
+    @pytest.mark.slow
+    def test_quick_profile(self):
+        """Digest plus clone fingerprints: the full per-file hashing work."""
+
+        def quick(content: bytes) -> None:
+            content_digest(content)
+            fingerprints(content)
+
+        assert _loc_per_sec(quick, _corpus()) > MIN_LOC_PER_SEC
@@ -0,0 +1,214 @@
+"""Tests for the Postgres history store.
+
+The SQL translation and the sqlite3-compatible rows run everywhere. The
+store contract runs on SQLite, and on Postgres too when
+SHANNON_TEST_POSTGRES_URL points at a server.
+"""
+
+import os
+import uuid
+from decimal import Decimal
+
+import pytest
+
+from shannon_insight.persistence.archive import export_history, import_history
+from shannon_insight.persistence.database import HistoryDB
+from shannon_insight.persistence.models import FindingRecord, Snapshot
+from shannon_insight.persistence.postgres import PostgresCursor, Row, split_url, translate
+from shannon_insight.persistence.queries import HistoryQuery
+from shannon_insight.persistence.retention import RetentionPolicy, prune_history
+from shannon_insight.persistence.writer import save_snapshot
+
+
+class TestTranslate:
+    def test_placeholders_and_percent_signs(self):
+        sql = "SELECT * FROM t WHERE a = ? AND b LIKE 'x%?' AND c = ?"
+
+        assert translate(sql) == "SELECT * FROM t WHERE a = %s AND b LIKE 'x%%?' AND c = %s"
+
+    def test_group_concat(self):
+        assert translate("SELECT GROUP_CONCAT(snapshot_id) FROM findings") == (
+            "SELECT string_agg(CAST(snapshot_id AS TEXT), ',') FROM findings"
+        )
+
+    def test_explicit_sort_orders_nulls_like_sqlite(self):
+        sql = "SELECT * FROM t ORDER BY a DESC, b ASC, c DESC NULLS FIRST"
+
+        assert translate(sql) == (
+            "SELECT * FROM t ORDER BY a DESC NULLS LAST, b ASC NULLS FIRST, c DESC NULLS FIRST"
+        )
+
+
+class TestSplitUrl:
+    def test_default_schema_from_directory_name(self, tmp_path):
+        root = tmp_path / "My-Repo"
+        root.mkdir()
+
+        assert split_url("postgresql://db/shannon", str(root)) == (
+            "postgresql://db/shannon",
+            "shannon_my_repo",
+        )
+
+    def test_schema_parameter_removed_from_url(self, tmp_path):
+        url, schema = split_url("postgresql://db/shannon?sslmode=require&schema=team_a", "x")
+
+        assert (url, schema) == ("postgresql://db/shannon?sslmode=require", "team_a")
+
+    def test_invalid_schema_rejected(self):
+        with pytest.raises(ValueError, match="Invalid history schema"):
+            split_url('postgresql://db/shannon?schema=a"b', "x")
+
+
+class _FakeRawCursor:
+    def __init__(self):
+        self.executed = []
+        self.description = None
+        self.rowcount = 1
+
+    def execute(self, sql, params):
+        self.executed.append(sql)
+        self._rows = [(7,)] if "RETURNING" in sql else []
+
+    def fetchone(self):
+        return self._rows.pop(0)
+
+
+class TestCompatibility:
+    def test_row_behaves_like_sqlite_row(self):
+        row = Row(["id", "avg"], (3, Decimal("0.5")))
+
+        assert (row["id"], row[1], list(row)) == (3, 0.5, [3, 0.5])
+        assert dict(row) == {"id": 3, "avg": 0.5}
+
+    def test_insert_into_serial_table_sets_lastrowid(self):
+        raw = _FakeRawCursor()
+        cur = PostgresCursor(raw)
+
+        cur.execute("BEGIN")
+        cur.execute("INSERT INTO snapshots (tool_version) VALUES (?)", ("1",))
+        cur.execute("INSERT INTO delta_h (snapshot_id) VALUES (?)", (7,))
+
+        assert cur.lastrowid is None
+        assert raw.executed == [
+            "INSERT INTO snapshots (tool_version) VALUES (%s) RETURNING id",
+            "INSERT INTO delta_h (snapshot_id) VALUES (%s)",
+        ]
+
+    def test_url_resolved_from_project_config(self, tmp_path, monkeypatch):
+        monkeypatch.delenv("SHANNON_HISTORY_URL", raising=False)
+        (tmp_path / "shannon-insight.toml").write_text('history_url = "postgresql://db/x"\n')
+
+        assert HistoryDB(str(tmp_path)).url == "postgresql://db/x"
+        assert HistoryDB(str(tmp_path), url="").url == ""
+
+    def test_sqlite_exists_only_once_created(self, tmp_path):
+        history = HistoryDB(str(tmp_path), url="")
+
+        assert not history.exists()
+        with history:
+            pass
+        assert history.exists()
+
+
+STORES = [
+    pytest.param("", id="sqlite"),
+    pytest.param(
+        os.environ.get("SHANNON_TEST_POSTGRES_URL", ""),
+        id="postgres",
+        marks=pytest.mark.skipif(
+            not os.environ.get("SHANNON_TEST_POSTGRES_URL"),
+            reason="SHANNON_TEST_POSTGRES_URL not set",
+        ),
+    ),
+]
+
+
+@pytest.fixture(params=STORES)
+def history(request, tmp_path):
+    url = request.param
+    if url:
+        sep = "&" if "?" in url else "?"
+        url = f"{url}{sep}schema=test_{uuid.uuid4().hex[:8]}"
+    db = HistoryDB(str(tmp_path), url=url)
+    db.connect()
+    yield db
+    if db.is_postgres:
+        db.conn.rollback()
+        db.conn.execute(f'DROP SCHEMA "{db.conn.schema}" CASCADE')
+        db.conn.commit()
+    db.close()
+
+
+def _snap(day, load, findings):
+    return Snapshot(
+        tool_version="0.6.0",
+        timestamp=f"2025-01-0{day}T00:00:00Z",
+        analyzed_path="/tmp",
+        file_count=1,
+        commit_sha=f"sha{day}",
+        file_signals={"a.py": {"cognitive_load": load}},
+        codebase_signals={"codebase_health": day / 10},
+        findings=[FindingRecord("god_file", k, 0.5, "t", ["a.py"], [], "fix") for k in findings],
+    )
+
+
+class TestStoreContract:
+    def test_snapshot_ids_increase(self, history):
+        first = save_snapshot(history.conn, _snap(1, 0.1, []))
+        second = save_snapshot(history.conn, _snap(2, 0.2, []))
+
+        assert 0 < first < second
+
+    def test_trends_and_persistent_findings(self, history):
+        for day in range(1, 6):
+            keys = ["chronic"] + (["gone"] if day < 3 else [])
+            save_snapshot(history.conn, _snap(day, day / 10, keys))
+        query = HistoryQuery(history.conn)
+
+        trend = query.file_trend("a.py", "cognitive_load", last_n=3)
+        chronic = query.persistent_findings(min_snapshots=3)
+        health = query.codebase_health(last_n=2)
+
+        assert [p.value for p in trend] == [0.3, 0.4, 0.5]
+        assert [(c["identity_key"], c["count"]) for c in chronic] == [("chronic", 5)]
+        assert [p.metrics["codebase_health"] for p in health] == [0.4, 0.5]
+        assert [p.metrics["active_findings"] for p in health] == [1.0, 1.0]
+
+    def test_baseline_replaced_and_history_kept(self, history):
+        first = save_snapshot(history.conn, _snap(1, 0.1, []))
+        second = save_snapshot(history.conn, _snap(2, 0.2, []))
+
+        history.set_baseline(first)
+        history.set_baseline(second, reason="merge", ref="main")
+
+        changes = [
+            (h["snapshot_id"], h["reason"], h["commit_sha"]) for h in history.get_baseline_history()
+        ]
+        assert history.get_baseline_snapshot_id() == second
+        assert changes == [(second, "merge", "sha2"), (first, "manual", "sha1")]
+
+    def test_prune_rolls_up_removed_snapshots(self, history):
+        for day in range(1, 4):
+            save_snapshot(history.conn, _snap(day, day / 10, []))
+
+        result = prune_history(history, RetentionPolicy(0, 0, 0), vacuum=True)
+        prune_history(history, RetentionPolicy(0, 0, 0))
+
+        [point] = HistoryQuery(history.conn).signal_rollups("file", "a.py", "cognitive_load")
+        assert (len(result.removed), result.kept) == (2, 1)
+        assert (point.period, point.samples, point.maximum) == ("2025-01", 2, 0.2)
+        assert point.mean == pytest.approx(0.15)
+
+    def test_archive_round_trip(self, history, tmp_path):
+        source = HistoryDB(str(tmp_path / "source"), url="")
+        with source:
+            for day in range(1, 3):
+                save_snapshot(source.conn, _snap(day, day / 10, ["chronic"]))
+            export_history(source, tmp_path / "history.jsonl.gz")
+
+        stats = import_history(history, tmp_path / "history.jsonl.gz")
+        exported = export_history(history, tmp_path / "again.jsonl.gz")
+
+        trend = HistoryQuery(history.conn).file_trend("a.py", "cognitive_load")
+        assert (stats.snapshots, exported.snapshots) == (2, 2)
+        assert [p.value for p in trend] == [0.1, 0.2]
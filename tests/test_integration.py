@@ -263,6 +263,41 @@ class TestSnapshotCapture:
         assert len(snapshot.findings) == len(result.findings)
 
 
+class TestDuplicateFiles:
+    """Content-identical files are analyzed once"""
+
+    def test_identical_copies_collapsed(self):
+        body = "".join(f"def f{i}(x):\n    return x + {i}\n\n" for i in range(8))
+        with tempfile.TemporaryDirectory() as tmpdir:
+            root = Path(tmpdir)
+            for rel in ("lib/retry.py", "plugins/lib/retry.py", "docs/examples/retry.py"):
+                (root / rel).parent.mkdir(parents=True, exist_ok=True)
+                (root / rel).write_text(body)
+            (root / "main.py").write_text("from lib import retry\n\nretry.f0(1)\n")
+
+            result, snapshot = _make_kernel(tmpdir).run(max_findings=50)
+
+            assert snapshot.file_count == 2
+            duplicates = [f for f in result.findings if f.finding_type == "duplicate_files"]
+            assert len(duplicates) == 1
+            assert duplicates[0].files == [
+                "lib/retry.py",
+                "docs/examples/retry.py",
+                "plugins/lib/retry.py",
+            ]
+
+    def test_collapse_can_be_disabled(self):
+        body = "".join(f"def f{i}(x):\n    return x + {i}\n\n" for i in range(8))
+        with tempfile.TemporaryDirectory() as tmpdir:
+            for name in ("a.py", "b.py"):
+                (Path(tmpdir) / name).write_text(body)
+
+            result, snapshot = _make_kernel(tmpdir, collapse_identical_files=False).run()
+
+            assert snapshot.file_count == 2
+            assert not any(f.finding_type == "duplicate_files" for f in result.findings)
+
+
 class TestErrorHandling:
     """Tests for error handling"""
 
@@ -276,3 +311,33 @@ class TestErrorHandling:
             result, snapshot = kernel.run()
             assert len(result.findings) == 0
             assert snapshot.file_count == 0
+
+
+class TestGeneratedFiles:
+    """Generated files are analyzed but not scored"""
+
+    _GENERATED = "// Code generated by MockGen. DO NOT EDIT.\n\npackage mocks\n\n" + "".join(
+        f"func (m *Mock) F{i}(x int) int {{\n\tif x > {i} {{\n\t\treturn x\n\t}}\n"
+        f"\treturn {i}\n}}\n\n"
+        for i in range(12)
+    )
+
+    def test_generated_files_are_not_scored(self):
+        with tempfile.TemporaryDirectory() as tmpdir:
+            (Path(tmpdir) / "mock_store.go").write_text(self._GENERATED)
+
+            _, snapshot = _make_kernel(tmpdir).run()
+
+            signals = snapshot.file_signals["mock_store.go"]
+            assert signals["compression_ratio"] == 0.0
+            assert signals["cognitive_load"] == 0.0
+
+    def test_include_generated(self):
+        with tempfile.TemporaryDirectory() as tmpdir:
+            (Path(tmpdir) / "mock_store.go").write_text(self._GENERATED)
+
+            _, snapshot = _make_kernel(tmpdir, include_generated=True).run()
+
+            signals = snapshot.file_signals["mock_store.go"]
+            assert signals["compression_ratio"] > 0.0
+            assert signals["cognitive_load"] > 0.0
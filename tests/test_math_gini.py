@@ -80,3 +80,20 @@ class TestGiniCoefficient:
     def test_floats_work(self):
         gini = Gini.gini_coefficient([1.0, 2.0, 3.0, 4.0])
         assert 0.0 <= gini <= 1.0
+
+
+class TestWeightedGiniCoefficient:
+    """Test Gini over (value, weight) pairs."""
+
+    def test_unit_weights_match_unweighted(self):
+        sizes = [3, 3, 3, 3, 3, 3, 3, 3, 80, 80]
+        weighted = Gini.weighted_gini_coefficient([(s, 1) for s in sizes])
+        assert weighted == pytest.approx(Gini.gini_coefficient(sizes))
+
+    def test_weight_counts_repeats(self):
+        weighted = Gini.weighted_gini_coefficient([(1, 2), (5, 1)])
+        assert weighted == pytest.approx(Gini.gini_coefficient([1, 1, 5]))
+
+    def test_nothing_to_compare(self):
+        assert Gini.weighted_gini_coefficient([]) == 0.0
+        assert Gini.weighted_gini_coefficient([(800, 1)]) == 0.0
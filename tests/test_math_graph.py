@@ -97,6 +97,17 @@ class TestBetweennessCentrality:
             assert 0.0 <= v <= 1.0
 
 
+    def test_sampled_sources_estimate_exact_values(self):
+        """Expanding every source as a "sample" is exact; fewer scale up."""
+        adj = {str(i): [str(i + 1)] for i in range(9)}
+        exact = GraphMetrics.betweenness_centrality(adj)
+        assert GraphMetrics.betweenness_centrality(adj, sample=10) == exact
+
+        sampled = GraphMetrics.betweenness_centrality(adj, sample=5)
+        assert sampled["0"] == 0.0
+        assert sampled["5"] > sampled["8"]
+
+
 class TestEigenvectorCentrality:
     """Tests for eigenvector centrality."""
 
@@ -0,0 +1,60 @@
+"""Tests for the t-digest quantile sketch."""
+
+import random
+from bisect import bisect_right
+
+import pytest
+
+from shannon_insight.math.sketches import TDigest
+
+
+def _digest(values, compression=100.0):
+    digest = TDigest(compression)
+    for value in values:
+        digest.add(value)
+    return digest
+
+
+class TestTDigest:
+    def test_empty(self):
+        digest = TDigest()
+        assert digest.quantile(0.5) == 0.0
+        assert digest.count == 0
+
+    def test_small_streams_are_exact(self):
+        digest = _digest([3, 1, 2])
+
+        assert len(digest) == 3
+        assert (digest.quantile(0.0), digest.quantile(0.5), digest.quantile(1.0)) == (1, 2, 3)
+
+    def test_large_stream_stays_small_and_accurate(self):
+        rng = random.Random(7)
+        values = [rng.lognormvariate(2, 1) for _ in range(50_000)]
+        digest = _digest(values)
+        ordered = sorted(values)
+
+        assert len(digest) <= 100
+        for q in (0.1, 0.5, 0.9, 0.99, 0.999):
+            # The estimate's rank in the stream is within 0.2% of q
+            rank = bisect_right(ordered, digest.quantile(q)) / len(ordered)
+            assert rank == pytest.approx(q, abs=0.002)
+        assert (digest.min, digest.max) == (ordered[0], ordered[-1])
+
+    def test_merge_equals_one_stream(self):
+        rng = random.Random(3)
+        values = [rng.gauss(10, 3) for _ in range(20_000)]
+        halves = _digest(values[::2])
+        halves.merge(_digest(values[1::2]))
+        whole = _digest(values)
+
+        assert halves.count == 20_000
+        for q in (0.05, 0.5, 0.95):
+            assert halves.quantile(q) == pytest.approx(whole.quantile(q), rel=0.01)
+
+    def test_round_trip(self):
+        digest = _digest(range(1000))
+        restored = TDigest.from_dict(digest.to_dict())
+
+        assert restored.count == 1000
+        assert restored.quantile(0.9) == pytest.approx(digest.quantile(0.9))
+        assert TDigest.from_dict(TDigest().to_dict()).quantile(0.5) == 0.0
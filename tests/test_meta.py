@@ -0,0 +1,84 @@
+"""Tests for the metric and finding type registry."""
+
+import json
+
+from shannon_insight.cli._finding_display import FINDING_DISPLAY
+from shannon_insight.cli.health import _HEALTH_METRICS
+from shannon_insight.infrastructure.signals import REGISTRY
+from shannon_insight.meta import UNITS, finding_types, metric_types, registry
+from shannon_insight.server.api import CATEGORY_LABELS, CATEGORY_MAP
+
+
+class TestMetricTypes:
+    def test_every_registry_signal_is_listed_with_its_polarity(self):
+        metrics = {m.id: m for m in metric_types()}
+        for signal, meta in REGISTRY.items():
+            metric = metrics[signal.value]
+            assert metric.direction == meta.polarity
+            assert meta.scope in metric.scopes
+
+    def test_units_directions_and_unique_ids(self):
+        metrics = metric_types()
+        assert len({m.id for m in metrics}) == len(metrics)
+        for m in metrics:
+            assert m.unit in UNITS, m.id
+            assert m.direction in ("high_is_bad", "high_is_good", "neutral"), m.id
+            assert m.scopes and set(m.scopes) <= {"file", "module", "global"}, m.id
+            assert m.label and m.label != m.id, m.id
+
+    def test_health_metrics_agree_on_direction(self):
+        metrics = {m.id: m for m in metric_types()}
+        expected = {
+            "lower_better": "high_is_bad",
+            "higher_better": "high_is_good",
+            "neutral": "neutral",
+        }
+        for key, (_label, direction, _aspect) in _HEALTH_METRICS.items():
+            assert metrics[key].direction == expected[direction], key
+
+    def test_package_metrics(self):
+        metrics = {m.id: m for m in metric_types()}
+        assert metrics["function_count"].scopes == ("file", "module")
+        assert metrics["error_hygiene_issues"].scopes == ("module", "global")
+        assert metrics["duplicated_lines"].higher_is_worse
+        assert not metrics["error_checks"].higher_is_worse
+
+
+class TestFindingTypes:
+    def test_every_known_finding_type_is_listed(self):
+        ids = {f.id for f in finding_types()}
+        assert set(FINDING_DISPLAY) <= ids
+        assert set(CATEGORY_MAP) <= ids
+        assert "comment_debt" in ids
+
+    def test_categories_concerns_and_rules(self):
+        types = {f.id: f for f in finding_types()}
+        errors = types["error_hygiene_issue"]
+        assert (errors.category, errors.concern) == ("errors", "errors")
+        assert "swallowed_error" in errors.rules
+        assert types["crypto_policy_violation"].rules == (
+            "banned_algorithm",
+            "weak_key",
+            "unapproved_library",
+        )
+        assert types["directory_hotspot"].concern is None
+        assert types["comment_debt"].category is None
+        assert all(f.category is None or f.category in CATEGORY_LABELS for f in types.values())
+
+
+def test_registry_is_json_serializable():
+    data = json.loads(json.dumps(registry()))
+    assert data["registry_version"] == 1
+    assert {m["id"] for m in data["metrics"]} >= {"lines", "codebase_health", "clone_classes"}
+    lines = next(m for m in data["metrics"] if m["id"] == "lines")
+    assert lines == {
+        "id": "lines",
+        "label": "Lines of Code",
+        "unit": "lines",
+        "direction": "high_is_bad",
+        "higher_is_worse": True,
+        "scopes": ["file"],
+        "dtype": "int",
+    }
+    assert data["categories"]["errors"] == "Error Hygiene"
+    assert data["concerns"]["complexity"] == "Complexity"
@@ -0,0 +1,90 @@
+"""Tests for OS-independent paths, digests and cache keys."""
+
+import unicodedata
+from pathlib import PureWindowsPath
+
+from shannon_insight.cache import AnalysisCache, compute_config_hash
+from shannon_insight.persistence.identity import compute_identity_key
+from shannon_insight import portable
+from shannon_insight.portable import content_digest, file_digest, fold_case, portable_path
+
+
+class TestPortablePath:
+    def test_windows_separators(self):
+        root = PureWindowsPath("C:/repo")
+
+        assert portable_path("src\\pkg\\mod.py") == "src/pkg/mod.py"
+        assert portable_path(root / "src" / "a.py", root) == "src/a.py"
+
+    def test_relative_to_root(self, tmp_path):
+        assert portable_path(tmp_path / "src" / "a.py", tmp_path) == "src/a.py"
+        assert portable_path("./src/a.py") == "src/a.py"
+
+    def test_nfd_names_match_nfc(self):
+        nfd = unicodedata.normalize("NFD", "café.py")
+
+        assert portable_path(nfd) == portable_path("café.py")
+
+    def test_case_preserved(self):
+        assert portable_path("Src/README.md") == "Src/README.md"
+
+
+class TestDigests:
+    def test_line_endings_and_bom_ignored(self):
+        lf = content_digest(b"a\nb\n")
+
+        assert content_digest(b"a\r\nb\r\n") == lf
+        assert content_digest(b"a\rb\r") == lf
+        assert content_digest(b"\xef\xbb\xbfa\nb\n") == lf
+        assert content_digest("a\nb\n") == lf
+
+    def test_file_digest_matches_content_digest(self, tmp_path, monkeypatch):
+        # Tiny reads put CRLF pairs and the BOM across chunk boundaries
+        monkeypatch.setattr(portable, "_CHUNK_SIZE", 3)
+        path = tmp_path / "a.py"
+        for body in (b"ab\r\ncd\r\n", b"\xef\xbb\xbfx\ry\r", b"abc\r", b"plain text\n", b""):
+            path.write_bytes(body)
+            assert file_digest(path) == content_digest(body)
+
+    def test_fold_case_is_unicode_aware(self):
+        assert fold_case("STRASSE") == fold_case("straße")
+        assert fold_case("TITLE") == "title"
+
+
+class TestCacheKeys:
+    def test_file_key_ignores_checkout_location_and_newlines(self, tmp_path):
+        laptop = tmp_path / "laptop"
+        ci = tmp_path / "ci"
+        for root, body in ((laptop, b"x = 1\r\n"), (ci, b"x = 1\n")):
+            (root / "src").mkdir(parents=True)
+            (root / "src" / "a.py").write_bytes(body)
+
+        key_laptop = AnalysisCache(enabled=False, root_dir=laptop)._get_file_key(
+            laptop / "src" / "a.py", "cfg"
+        )
+        key_ci = AnalysisCache(enabled=False, root_dir=ci)._get_file_key(ci / "src" / "a.py", "cfg")
+
+        assert key_laptop == key_ci
+
+    def test_file_key_changes_with_content(self, tmp_path):
+        path = tmp_path / "a.py"
+        cache = AnalysisCache(enabled=False, root_dir=tmp_path)
+        path.write_text("x = 1\n")
+        before = cache._get_file_key(path, "cfg")
+        path.write_text("x = 2\n")
+
+        assert cache._get_file_key(path, "cfg") != before
+
+    def test_config_hash_ignores_machine_settings(self):
+        base = {"max_files": 100, "workers": 8, "cache_dir": "/Users/me/.cache"}
+        other = {"max_files": 100, "workers": 2, "cache_dir": "/home/ci/.cache"}
+
+        assert compute_config_hash(base) == compute_config_hash(other)
+        assert compute_config_hash(base) != compute_config_hash({**base, "max_files": 5})
+
+
+class TestIdentityKeys:
+    def test_separator_independent(self):
+        assert compute_identity_key("god_file", ["src\\a.py"]) == compute_identity_key(
+            "god_file", ["src/a.py"]
+        )
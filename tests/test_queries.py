@@ -107,6 +107,19 @@ class TestPersistentFindings:
             gap_items = [c for c in chronic if c["identity_key"] == "gap_key"]
             assert len(gap_items) == 0
 
+    def test_reports_latest_occurrence(self):
+        with tempfile.TemporaryDirectory() as tmpdir:
+            with HistoryDB(tmpdir) as db:
+                for i, severity in enumerate([0.4, 0.6, 0.9]):
+                    snap = _snap(
+                        ts=f"2025-01-0{i + 1}T00:00:00Z",
+                        findings=[_finding("growing_key", severity=severity)],
+                    )
+                    save_snapshot(db.conn, snap)
+                chronic = HistoryQuery(db.conn).persistent_findings(min_snapshots=3)
+
+            assert [(c["identity_key"], c["severity"]) for c in chronic] == [("growing_key", 0.9)]
+
 
 class TestTopMovers:
     def test_finds_movers(self):
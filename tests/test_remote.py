@@ -0,0 +1,127 @@
+"""Tests for read-only checkouts of remote repositories."""
+
+import shutil
+import subprocess
+
+import pytest
+
+from shannon_insight.remote import RemoteSpec, checkout, mirror_path, parse_remote
+
+requires_git = pytest.mark.skipif(shutil.which("git") is None, reason="git not found")
+
+
+def _git(repo, *args):
+    subprocess.run(
+        ["git", "-C", str(repo), "-c", "user.name=T", "-c", "user.email=t@t", *args],
+        check=True,
+        capture_output=True,
+    )
+
+
+@pytest.fixture
+def origin(tmp_path):
+    """A repository with a main branch, a feature branch and a tag."""
+    repo = tmp_path / "origin"
+    repo.mkdir()
+    _git(repo, "init", "--quiet", "--initial-branch=main")
+    (repo / "app.py").write_text("def main():\n    return 1\n")
+    _git(repo, "add", "-A")
+    _git(repo, "commit", "--quiet", "-m", "first")
+    _git(repo, "tag", "v1")
+    _git(repo, "checkout", "--quiet", "-b", "feature")
+    (repo / "feature.py").write_text("def feature():\n    return 2\n")
+    _git(repo, "add", "-A")
+    _git(repo, "commit", "--quiet", "-m", "feature")
+    _git(repo, "checkout", "--quiet", "main")
+    return repo
+
+
+class TestParseRemote:
+    def test_local_paths_are_not_remote(self):
+        assert parse_remote(".") is None
+        assert parse_remote("/srv/code") is None
+        assert parse_remote("C:\\code") is None
+
+    def test_https_with_ref(self):
+        assert parse_remote("https://github.com/org/repo@release/2.x") == RemoteSpec(
+            "https://github.com/org/repo", "release/2.x"
+        )
+        assert parse_remote("https://github.com/org/repo.git") == RemoteSpec(
+            "https://github.com/org/repo.git"
+        )
+
+    def test_user_in_host_is_not_a_ref(self):
+        assert parse_remote("ssh://git@host/org/repo@v1") == RemoteSpec(
+            "ssh://git@host/org/repo", "v1"
+        )
+        assert parse_remote("git@github.com:org/repo.git@abc123") == RemoteSpec(
+            "git@github.com:org/repo.git", "abc123"
+        )
+
+    def test_empty_ref(self):
+        with pytest.raises(ValueError, match="missing ref"):
+            parse_remote("https://github.com/org/repo@")
+
+    def test_option_like_url_or_ref(self):
+        with pytest.raises(ValueError, match="starts with '-'"):
+            parse_remote("https://github.com/org/repo@--upload-pack=touch /tmp/x")
+        with pytest.raises(ValueError, match="starts with '-'"):
+            parse_remote("-oProxyCommand@host:org/repo")
+
+    def test_name(self):
+        assert RemoteSpec("https://github.com/org/repo.git").name == "repo"
+        assert RemoteSpec("git@github.com:org/my-lib").name == "my-lib"
+
+
+@requires_git
+class TestCheckout:
+    def test_shallow_checkout_of_default_branch(self, origin):
+        with checkout(RemoteSpec(f"file://{origin}")) as work_tree:
+            assert work_tree.name == "origin"
+            assert (work_tree / "app.py").exists()
+            assert not (work_tree / "feature.py").exists()
+            log = subprocess.run(
+                ["git", "-C", str(work_tree), "rev-list", "--count", "HEAD"],
+                capture_output=True,
+                text=True,
+            )
+            assert log.stdout.strip() == "1"
+        assert not work_tree.exists()
+
+    def test_shallow_checkout_of_branch(self, origin):
+        with checkout(RemoteSpec(f"file://{origin}", "feature")) as work_tree:
+            assert (work_tree / "feature.py").exists()
+
+    def test_unknown_ref(self, origin):
+        with pytest.raises(RuntimeError, match="git fetch failed"):
+            with checkout(RemoteSpec(f"file://{origin}", "nope")):
+                pass
+
+    def test_option_like_ref_is_not_an_option(self, origin, tmp_path):
+        marker = tmp_path / "pwned"
+        spec = RemoteSpec(f"file://{origin}", f"--upload-pack=touch {marker}")
+        for mirror_dir in ("", str(tmp_path / "m")):
+            with pytest.raises(RuntimeError):
+                with checkout(spec, mirror_dir):
+                    pass
+        assert not marker.exists()
+
+    def test_mirror_is_reused_and_updated(self, origin, tmp_path):
+        mirrors = tmp_path / "mirrors"
+        url = f"file://{origin}"
+
+        with checkout(RemoteSpec(url, "v1"), str(mirrors)) as work_tree:
+            assert (work_tree / "app.py").exists()
+            assert not (work_tree / "feature.py").exists()
+        assert (mirror_path(url, mirrors) / "HEAD").exists()
+
+        _git(origin, "merge", "--quiet", "feature")
+        with checkout(RemoteSpec(url), str(mirrors)) as work_tree:
+            # The mirror was fetched again: main now has the merged feature
+            assert (work_tree / "feature.py").exists()
+        assert not work_tree.exists()
+
+    def test_mirror_unknown_ref(self, origin, tmp_path):
+        with pytest.raises(RuntimeError, match="has no ref 'nope'"):
+            with checkout(RemoteSpec(f"file://{origin}", "nope"), str(tmp_path / "m")):
+                pass
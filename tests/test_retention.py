@@ -0,0 +1,135 @@
+"""Tests for history retention, rollups and ``history prune``."""
+
+from datetime import datetime, timedelta, timezone
+
+import pytest
+
+from shannon_insight.config import AnalysisConfig
+from shannon_insight.persistence.database import HistoryDB
+from shannon_insight.persistence.models import Snapshot
+from shannon_insight.persistence.queries import HistoryQuery
+from shannon_insight.persistence.retention import RetentionPolicy, plan, prune_history
+from shannon_insight.persistence.writer import save_snapshot
+
+NOW = datetime(2025, 6, 30, 12, tzinfo=timezone.utc)
+POLICY = RetentionPolicy(keep_all_days=1, keep_daily_days=10, keep_weekly_days=60)
+
+
+def _ago(**delta) -> str:
+    return (NOW - timedelta(**delta)).isoformat()
+
+
+class TestPlan:
+    def test_keeps_everything_recent(self):
+        snapshots = [(1, _ago(hours=5)), (2, _ago(hours=3)), (3, _ago(hours=1))]
+
+        assert plan(snapshots, POLICY, NOW) == {}
+
+    def test_keeps_last_of_each_day_then_week(self):
+        snapshots = [
+            (1, _ago(days=3, hours=2)),
+            (2, _ago(days=3, hours=1)),  # same day, later
+            (3, _ago(days=20)),  # Tue 2025-06-10
+            (4, _ago(days=18)),  # Thu, same ISO week
+            (5, _ago(hours=1)),
+        ]
+
+        assert plan(snapshots, POLICY, NOW) == {
+            1: ("day", "2025-06-27"),
+            3: ("week", "2025-W24"),
+        }
+
+    def test_expired_snapshots_roll_up_by_month(self):
+        snapshots = [(1, _ago(days=100)), (2, _ago(days=99)), (3, _ago(hours=1))]
+
+        assert plan(snapshots, POLICY, NOW) == {1: ("month", "2025-03"), 2: ("month", "2025-03")}
+
+    def test_protected_newest_and_unreadable_kept(self):
+        snapshots = [(1, _ago(days=100)), (2, "not a date"), (3, _ago(days=90))]
+
+        assert plan(snapshots, POLICY, NOW, protected=frozenset({1})) == {}
+
+
+def _snap(taken: str, load: float) -> Snapshot:
+    return Snapshot(
+        tool_version="0.6.0",
+        timestamp=taken,
+        analyzed_path="/tmp",
+        file_count=1,
+        file_signals={"a.py": {"cognitive_load": load}},
+        codebase_signals={"codebase_health": load / 10},
+    )
+
+
+@pytest.fixture
+def history(tmp_path):
+    db = HistoryDB(str(tmp_path), url="")
+    db.connect()
+    yield db
+    db.close()
+
+
+class TestPruneHistory:
+    def test_rolls_up_then_deletes(self, history):
+        for days, load in ((100, 2.0), (99, 4.0), (80, 9.0)):
+            save_snapshot(history.conn, _snap(_ago(days=days), load))
+        newest = save_snapshot(history.conn, _snap(_ago(hours=1), 1.0))
+
+        result = prune_history(history, POLICY, now=NOW)
+
+        # file and codebase signals, for March and April
+        assert (result.kept, len(result.removed), result.rollup_rows) == (1, 3, 4)
+        remaining = history.conn.execute("SELECT snapshot_id FROM file_signals").fetchall()
+        assert [r["snapshot_id"] for r in remaining] == [newest]
+        march, april = HistoryQuery(history.conn).signal_rollups("file", "a.py", "cognitive_load")
+        assert (march.granularity, march.period, march.samples) == ("month", "2025-03", 2)
+        assert (march.minimum, march.maximum, march.mean) == (2.0, 4.0, 3.0)
+        assert (april.period, april.samples, april.mean) == ("2025-04", 1, 9.0)
+
+    def test_rollups_accumulate_across_runs(self, history):
+        save_snapshot(history.conn, _snap(_ago(days=100), 2.0))
+        save_snapshot(history.conn, _snap(_ago(hours=1), 1.0))
+        prune_history(history, POLICY, now=NOW)
+        save_snapshot(history.conn, _snap(_ago(days=99), 6.0))
+
+        prune_history(history, POLICY, now=NOW)
+
+        [point] = HistoryQuery(history.conn).signal_rollups("codebase", "", "codebase_health")
+        assert point.samples == 2
+        assert point.mean == pytest.approx(0.4)
+
+    def test_baseline_kept_and_stale_baseline_history_dropped(self, history):
+        old = save_snapshot(history.conn, _snap(_ago(days=100), 2.0))
+        older = save_snapshot(history.conn, _snap(_ago(days=120), 2.0))
+        save_snapshot(history.conn, _snap(_ago(hours=1), 1.0))
+        history.set_baseline(older)
+        history.conn.execute("UPDATE baseline_history SET set_at = ?", (_ago(days=120),))
+        history.set_baseline(old)
+        history.conn.commit()
+
+        result = prune_history(history, POLICY, now=NOW)
+
+        assert result.removed == [older]
+        assert history.get_baseline_snapshot_id() == old
+        assert [h["snapshot_id"] for h in history.get_baseline_history()] == [old]
+
+    def test_dry_run_changes_nothing(self, history):
+        save_snapshot(history.conn, _snap(_ago(days=100), 2.0))
+        save_snapshot(history.conn, _snap(_ago(hours=1), 1.0))
+
+        result = prune_history(history, POLICY, now=NOW, dry_run=True, vacuum=True)
+
+        assert (result.dry_run, len(result.removed), result.rollup_rows) == (True, 1, 0)
+        count = history.conn.execute("SELECT COUNT(*) AS n FROM snapshots").fetchone()["n"]
+        assert count == 2
+
+
+class TestRetentionConfig:
+    def test_policy_from_config(self):
+        config = AnalysisConfig(history_keep_daily_days=30, history_keep_weekly_days=365)
+
+        assert RetentionPolicy.from_config(config) == RetentionPolicy(1, 30, 365)
+
+    def test_windows_must_be_ordered(self):
+        with pytest.raises(ValueError, match="history retention"):
+            AnalysisConfig(history_keep_daily_days=800)
@@ -0,0 +1,166 @@
+"""Tests for suggested-change review comments."""
+
+import json
+
+import pytest
+
+from shannon_insight.insights.models import Finding, Patch
+from shannon_insight.review import GitHubReview, GitLabReview, parse_review, review_comment
+
+
+def _finding(title, severity, finding_type="god_file"):
+    return Finding(
+        finding_type=finding_type,
+        severity=severity,
+        title=title,
+        files=["auth/tokens.py"],
+        evidence=[],
+        suggestion="",
+    )
+
+
+class _Forge:
+    """A forge API: comments already posted, and the status of each new one."""
+
+    def __init__(self, existing=(), statuses=()):
+        self.existing = list(existing)
+        self.statuses = list(statuses)
+        self.requests = []
+        self.fetched = []
+
+    def post(self, url, body, headers):
+        self.requests.append((url, json.loads(body)))
+        return self.statuses.pop(0) if self.statuses else 201
+
+    def fetch(self, url, headers):
+        self.fetched.append(url)
+        return self.existing if url.endswith("page=1") else []
+
+
+_REPLACEMENT = '    options = {"verify_exp": True}'
+
+
+def _patched_findings():
+    finding = _finding("Token expiry is not verified", 0.6, "auth_flow_issue")
+    finding.suggestion = "Verify the exp claim"
+    finding.patches = [
+        Patch("auth/tokens.py", 11, 11, _REPLACEMENT),
+        Patch("auth/tokens.py", 20, 20, _REPLACEMENT.replace("options", "opts")),
+    ]
+    return [_finding("engine.py is a god file", 0.9), finding]
+
+
+def _github(forge):
+    return GitHubReview("shop/api", 7, "abc123", "t0k", post=forge.post, fetch=forge.fetch)
+
+
+class TestParseReview:
+    def test_github_from_actions_environment(self, tmp_path):
+        event = tmp_path / "event.json"
+        event.write_text(json.dumps({"pull_request": {"number": 7, "head": {"sha": "abc123"}}}))
+        environ = {
+            "GITHUB_TOKEN": "t0k",
+            "GITHUB_REPOSITORY": "shop/api",
+            "GITHUB_EVENT_PATH": str(event),
+        }
+        review = parse_review("github", environ)
+
+        assert (review.repository, review.pull_request, review.commit) == ("shop/api", 7, "abc123")
+        assert review.api_url == "https://api.github.com"
+        event.write_text(json.dumps({"ref": "refs/heads/main"}))
+        with pytest.raises(ValueError, match="pull_request events"):
+            parse_review("github", environ)
+        with pytest.raises(ValueError, match="GITHUB_TOKEN"):
+            parse_review("github", {"GITHUB_REPOSITORY": "shop/api"})
+
+    def test_gitlab_from_ci_environment(self):
+        environ = {
+            "GITLAB_TOKEN": "t0k",
+            "CI_API_V4_URL": "https://git.example.com/api/v4",
+            "CI_PROJECT_ID": "42",
+            "CI_MERGE_REQUEST_IID": "3",
+            "CI_MERGE_REQUEST_DIFF_BASE_SHA": "base1",
+            "CI_COMMIT_SHA": "head1",
+        }
+        review = parse_review("gitlab", environ)
+
+        assert (review.project, review.merge_request) == ("42", 3)
+        assert (review.base_commit, review.commit) == ("base1", "head1")
+        assert review.api_url == "https://git.example.com/api/v4"
+        with pytest.raises(ValueError, match="merge request pipelines"):
+            parse_review("gitlab", {**environ, "CI_MERGE_REQUEST_IID": ""})
+
+    def test_unknown_target(self):
+        with pytest.raises(ValueError, match="github or gitlab"):
+            parse_review("bitbucket", {})
+
+
+class TestReviewComment:
+    def test_has_a_suggestion_block(self):
+        finding = _patched_findings()[1]
+        body = review_comment(finding, finding.patches[0])
+
+        assert body.startswith("**Token expiry is not verified** (`auth_flow_issue`)")
+        assert f"```suggestion\n{_REPLACEMENT}\n```\n" in body
+        assert body.splitlines()[-1].startswith("<!-- shannon-insight:auth_flow_issue:")
+
+    def test_fences_and_gitlab_ranges(self):
+        finding = _finding("x", 0.5)
+        patch = Patch("a.md", 4, 6, "use ```code``` here")
+        body = review_comment(finding, patch, gitlab=True)
+
+        assert "````suggestion:-2+0\nuse ```code``` here\n````" in body
+        assert "````suggestion\n" in review_comment(finding, patch)
+
+
+class TestPublish:
+    def test_github_posts_one_comment_per_patch(self):
+        forge = _Forge()
+        detail = _github(forge).publish(_patched_findings())
+
+        assert detail == "posted 2 suggested changes to pull request #7"
+        (url, comment), _ = forge.requests
+        assert url == "https://api.github.com/repos/shop/api/pulls/7/comments"
+        assert comment["commit_id"] == "abc123"
+        assert (comment["path"], comment["line"]) == ("auth/tokens.py", 11)
+        assert comment["side"] == "RIGHT"
+        assert "start_line" not in comment
+        assert forge.fetched == [
+            "https://api.github.com/repos/shop/api/pulls/7/comments?per_page=100&page=1"
+        ]
+
+    def test_posted_patches_and_lines_outside_the_diff_are_skipped(self):
+        findings = _patched_findings()
+        finding = findings[1]
+        forge = _Forge(existing=[{"body": review_comment(finding, finding.patches[0])}])
+        assert _github(forge).publish(findings) == "posted 1 suggested changes to pull request #7"
+        assert [c["line"] for _, c in forge.requests] == [20]
+
+        forge = _Forge(statuses=[422, 201])
+        detail = _github(forge).publish(findings)
+        assert detail.endswith("#7 (1 outside the diff skipped)")
+        assert detail.startswith("posted 1 suggested changes")
+
+    def test_refusal_raises(self):
+        with pytest.raises(RuntimeError, match="HTTP 401"):
+            _github(_Forge(statuses=[401])).publish(_patched_findings())
+
+    def test_gitlab_posts_positioned_discussions(self):
+        forge = _Forge(existing=[{"notes": [{"body": "LGTM"}]}])
+        review = GitLabReview(
+            "shop/api", 3, "base1", "head1", "t0k", post=forge.post, fetch=forge.fetch
+        )
+        detail = review.publish(_patched_findings())
+
+        assert detail == "posted 2 suggested changes to merge request !3"
+        (url, discussion), _ = forge.requests
+        assert url == "https://gitlab.com/api/v4/projects/shop%2Fapi/merge_requests/3/discussions"
+        assert discussion["position"] == {
+            "position_type": "text",
+            "base_sha": "base1",
+            "start_sha": "base1",
+            "head_sha": "head1",
+            "new_path": "auth/tokens.py",
+            "new_line": 11,
+        }
+        assert "```suggestion\n" in discussion["body"]
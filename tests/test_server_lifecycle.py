@@ -346,6 +346,29 @@ class TestShutdownManager:
 
         mock_watcher.stop.assert_called_once()
 
+    def test_shutdown_drains_jobs_before_stopping_watcher(self, tmp_path):
+        from rich.console import Console
+
+        from shannon_insight.server.lifecycle import ShutdownManager
+
+        console = Console(file=open(os.devnull, "w"))
+
+        calls = []
+        mock_jobs = MagicMock()
+        mock_jobs.drain.side_effect = lambda timeout: calls.append(("drain", timeout)) or True
+        mock_jobs.queue.__len__.return_value = 0
+        mock_watcher = MagicMock()
+        mock_watcher.stop.side_effect = lambda: calls.append(("stop", None))
+        mgr = ShutdownManager(str(tmp_path), console)
+        mgr.register_job_worker(mock_jobs, drain_timeout=12)
+        mgr.register_watcher(mock_watcher)
+
+        mgr.begin_drain()
+        mock_jobs.begin_drain.assert_called_once()
+        mgr.shutdown()
+
+        assert calls == [("drain", 12), ("stop", None)]
+
     def test_shutdown_signals_uvicorn(self, tmp_path):
         from rich.console import Console
 
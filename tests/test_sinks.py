@@ -0,0 +1,145 @@
+"""Tests for output sinks."""
+
+import json
+from pathlib import Path
+
+import pytest
+
+from shannon_insight.insights.models import Finding
+from shannon_insight.review import GitLabReview
+from shannon_insight.sinks import (
+    DirectorySink,
+    Output,
+    ReviewSink,
+    Run,
+    S3Sink,
+    SlackSink,
+    StdoutSink,
+    parse_sink,
+    run_sinks,
+    slack_summary,
+)
+
+
+def _finding(title, severity, finding_type="god_file"):
+    return Finding(
+        finding_type=finding_type,
+        severity=severity,
+        title=title,
+        files=["src/engine.py"],
+        evidence=[],
+        suggestion="",
+    )
+
+
+def _run():
+    outputs = [Output("sarif", '{"version": "2.1.0"}'), Output("html", "<html></html>")]
+    findings = [
+        _finding("engine.py is a god file", 0.9),
+        _finding("a and b co-change", 0.5, "hidden_coupling"),
+        _finding("util.py is orphaned", 0.2, "orphan_code"),
+    ]
+    return Run(outputs, findings, file_count=42, project="shop")
+
+
+class _S3:
+    def __init__(self):
+        self.objects = {}
+
+    def put_object(self, Bucket, Key, Body, ContentType):
+        self.objects[(Bucket, Key)] = (Body.decode(), ContentType)
+
+
+class _Endpoint:
+    def __init__(self, status=200):
+        self.status = status
+        self.requests = []
+
+    def __call__(self, url, body, headers):
+        self.requests.append((url, json.loads(body)))
+        return self.status
+
+
+class TestParseSink:
+    def test_kinds(self):
+        assert isinstance(parse_sink("stdout"), StdoutSink)
+        assert isinstance(parse_sink("-"), StdoutSink)
+        assert parse_sink("reports/ci") == DirectorySink(Path("reports/ci"))
+        assert parse_sink("file:out") == DirectorySink(Path("out"))
+        s3 = parse_sink("s3://builds/shop/main")
+        assert (s3.bucket, s3.prefix) == ("builds", "shop/main")
+
+    def test_slack_url_from_environment(self):
+        hook = "https://hooks.slack.com/services/T0/B0/x"
+        assert parse_sink("slack", {"SLACK_WEBHOOK_URL": hook}).url == hook
+        assert parse_sink(f"slack:{hook}", {}).url == hook
+        with pytest.raises(ValueError, match="SLACK_WEBHOOK_URL"):
+            parse_sink("slack", {})
+
+    def test_review_from_ci_environment(self):
+        environ = {
+            "GITLAB_TOKEN": "t0k",
+            "CI_PROJECT_ID": "42",
+            "CI_MERGE_REQUEST_IID": "3",
+            "CI_MERGE_REQUEST_DIFF_BASE_SHA": "base1",
+            "CI_COMMIT_SHA": "head1",
+        }
+        sink = parse_sink("gitlab", environ)
+
+        assert isinstance(sink, ReviewSink)
+        assert (sink.name, sink.needs_outputs) == ("gitlab", False)
+        assert isinstance(sink.review, GitLabReview)
+        with pytest.raises(ValueError, match="GITHUB_TOKEN"):
+            parse_sink("github", {})
+
+    @pytest.mark.parametrize("spec", ["s3://", "ftp://host/dir", "slack:hooks", "file:"])
+    def test_malformed(self, spec):
+        with pytest.raises(ValueError):
+            parse_sink(spec, {})
+
+
+class TestSinks:
+    def test_every_sink_receives_every_output(self, tmp_path):
+        client = _S3()
+        endpoint = _Endpoint()
+        sinks = [
+            DirectorySink(tmp_path / "reports"),
+            S3Sink("builds", "shop/", client=client),
+            SlackSink("https://hooks.slack.com/x", post=endpoint),
+        ]
+        results = run_sinks(sinks, _run())
+
+        assert all(r.ok for r in results)
+        assert (tmp_path / "reports" / "shannon-insight.html").read_text() == "<html></html>"
+        assert client.objects[("builds", "shop/shannon-insight.sarif")] == (
+            '{"version": "2.1.0"}',
+            "application/sarif+json",
+        )
+        ((url, body),) = endpoint.requests
+        assert body["text"].startswith("*Shannon Insight* shop: 3 findings in 42 files")
+
+    def test_failing_sink_does_not_stop_the_others(self, tmp_path):
+        sinks = [
+            SlackSink("https://hooks.slack.com/x", post=_Endpoint(status=404)),
+            DirectorySink(tmp_path),
+        ]
+        failed, written = run_sinks(sinks, _run())
+
+        assert (failed.ok, failed.error) == (False, "HTTP 404")
+        assert written.ok and (tmp_path / "shannon-insight.sarif").exists()
+
+    def test_review_sink_hands_over_the_findings(self):
+        class _Review:
+            def publish(self, findings):
+                return f"posted {len(findings)} suggested changes"
+
+        (result,) = run_sinks([ReviewSink(_Review(), "github")], _run())
+
+        assert (result.ok, result.detail) == (True, "posted 3 suggested changes")
+
+    def test_slack_summary(self):
+        lines = slack_summary(_run()).splitlines()
+
+        assert lines[0].endswith("(1 high, 1 medium)")
+        assert lines[1] == "• engine.py is a god file (`god_file`, 0.90)"
+        assert len(lines) == 4
@@ -141,3 +141,19 @@ class TestWriterReader:
                 assert db.get_baseline_snapshot_id() == sid
                 db.clear_baseline()
                 assert db.get_baseline_snapshot_id() is None
+
+    def test_baseline_history(self):
+        with tempfile.TemporaryDirectory() as tmpdir:
+            with HistoryDB(tmpdir) as db:
+                first = save_snapshot(db.conn, _make_snapshot(commit_sha="aaa"))
+                second = save_snapshot(db.conn, _make_snapshot(commit_sha="bbb"))
+                db.set_baseline(first)
+                db.set_baseline(second, reason="schedule", ref="origin/main")
+
+                history = db.get_baseline_history()
+                assert db.get_baseline_snapshot_id() == second
+                assert [h["snapshot_id"] for h in history] == [second, first]
+                assert history[0]["reason"] == "schedule"
+                assert history[0]["ref"] == "origin/main"
+                assert history[0]["commit_sha"] == "bbb"
+                assert history[1]["reason"] == "manual"
@@ -114,3 +114,73 @@ class TestGenerateReport:
             assert "<!DOCTYPE html>" in html
         finally:
             os.unlink(output)
+
+
+class _Extractor:
+    """One function spanning the whole file, whatever the parser would say."""
+
+    def extract(self, path, root):
+        from shannon_insight.scanning.syntax import FileSyntax, FunctionDef
+
+        end = len(path.read_text().splitlines())
+        return FileSyntax(
+            "big.py",
+            [FunctionDef("big", ["x"], 10, 3, 3, start_line=1, end_line=end)],
+            [],
+            [],
+            "python",
+        )
+
+
+_BIG = """def big(x):
+    if x:
+        for i in x:
+            if i and x:
+                return "</script>"
+    return 0
+"""
+
+
+class TestSourceView:
+    def test_gutter_values(self, tmp_path):
+        from shannon_insight.visualization.source_view import source_view
+
+        (tmp_path / "big.py").write_text(_BIG)
+        view = source_view(tmp_path, "big.py", _Extractor())
+
+        assert [line[1] for line in view["lines"]] == [0, 0, 1, 2, 3, 0]
+        assert [line[2] for line in view["lines"]] == [0, 1, 1, 1, 0, 0]
+        assert all(line[3] is None for line in view["lines"])  # not a git repository
+        assert view["functions"] == [
+            {"name": "big", "start_line": 1, "end_line": 6, "complexity": 4}
+        ]
+        assert source_view(tmp_path, "missing.py", _Extractor()) is None
+
+    def test_flagged_files_most_severe_first(self):
+        from shannon_insight.visualization.source_view import flagged_files
+
+        findings = [
+            FindingRecord("a", "k1", 0.4, "t", ["low.py", "high.py"], [], "s"),
+            FindingRecord("b", "k2", 0.9, "t", ["high.py"], [], "s"),
+        ]
+        assert flagged_files(findings) == ["high.py", "low.py"]
+        assert flagged_files(findings, limit=1) == ["high.py"]
+
+    def test_report_embeds_flagged_sources_safely(self, tmp_path):
+        (tmp_path / "big.py").write_text(_BIG)
+        snap = Snapshot(
+            tool_version="0.6.0",
+            timestamp="2025-01-01T00:00:00Z",
+            analyzed_path=str(tmp_path),
+            file_count=1,
+            file_signals={"big.py": {"lines": 6}},
+            findings=[FindingRecord("god_file", "k1", 0.8, "t", ["big.py", "gone.py"], [], "s")],
+        )
+        html = open(generate_report(snap, output_path=str(tmp_path / "r.html"))).read()
+        script = html.split("<script>", 1)[1]
+
+        assert script.count("</script>") == 1  # the source's own is escaped
+        assert '"path": "big.py"' in script and '"path": "gone.py"' not in script
+        assert "renderSource" in script
+        bare = generate_report(snap, output_path=str(tmp_path / "b.html"), include_source=False)
+        assert '"sources": []' in open(bare).read()
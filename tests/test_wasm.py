@@ -0,0 +1,59 @@
+"""Tests for the in-memory analysis core of the WebAssembly build."""
+
+import json
+
+from shannon_insight.wasm import analyze_json, analyze_sources, function_metrics_json
+
+_ENGINE = """\
+package engine
+
+import "crypto/md5"
+
+func Digest(data []byte) []byte {
+	if len(data) == 0 {
+		return nil
+	}
+	for _, b := range data {
+		if b > 4 && b < 10 {
+			return md5.New().Sum(data)
+		}
+	}
+	return data
+}
+"""
+
+
+def test_metrics_and_findings_from_text():
+    result = analyze_sources(
+        {"src/engine.go": _ENGINE, "notes.txt": "no language", "./src/empty.go": ""}
+    )
+
+    assert [f["path"] for f in result["files"]] == ["src/empty.go", "src/engine.go"]
+    engine = result["files"][1]
+    assert engine["language"] == "go"
+    (digest,) = engine["functions"]
+    assert digest["name"] == "Digest"
+    assert digest["start_line"] == 5
+    assert digest["complexity"] == 5
+    assert 0 < digest["heat"] <= 1
+    (finding,) = result["findings"]
+    assert finding["finding_type"] == "crypto_policy_violation"
+    assert finding["files"] == ["src/engine.go"]
+
+
+def test_config_overrides():
+    files = {"src/engine.go": _ENGINE}
+    allowed = analyze_sources(files, {"crypto_policy": {"banned_algorithms": []}})
+
+    assert allowed["findings"] == []
+    assert json.loads(analyze_json(json.dumps(files), '{"max_findings": 0}')) == {
+        "error": "max_findings must be at least 1"
+    }
+    assert "error" in json.loads(analyze_json("{}", '{"no_such_option": 1}'))
+
+
+def test_function_metrics_of_one_file():
+    rows = json.loads(function_metrics_json("src/engine.go", _ENGINE))
+
+    assert [row["name"] for row in rows] == ["Digest"]
+    assert json.loads(function_metrics_json("notes.txt", "text")) == []
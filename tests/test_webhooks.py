@@ -0,0 +1,148 @@
+"""Tests for the webhook exporter."""
+
+import json
+
+import pytest
+
+from shannon_insight.persistence.models import FindingRecord
+from shannon_insight.webhooks import (
+    deliver,
+    finding_events,
+    gate_event,
+    parse_webhooks,
+)
+
+
+def _finding(key, finding_type="high_risk_hub", severity=0.8, title=None):
+    return FindingRecord(
+        finding_type=finding_type,
+        identity_key=key,
+        severity=severity,
+        title=title or f"Finding {key}",
+        files=["src/core.py", "src/api.py"],
+        evidence=[],
+        suggestion="Split it",
+    )
+
+
+class _Endpoint:
+    """Records POSTs and answers with *status*."""
+
+    def __init__(self, status=200):
+        self.status = status
+        self.requests = []
+
+    def __call__(self, url, body, headers):
+        self.requests.append((url, json.loads(body), headers))
+        if isinstance(self.status, Exception):
+            raise self.status
+        return self.status
+
+
+class TestParseWebhooks:
+    def test_defaults(self):
+        (hook,) = parse_webhooks([{"url": "https://hooks.example.com/q"}])
+
+        assert hook.events == ("finding_created", "finding_resolved", "gate")
+        assert (hook.finding_types, hook.min_severity, hook.template) == ((), 0.0, "")
+
+    @pytest.mark.parametrize(
+        "entry",
+        [
+            {"url": "ftp://example.com"},
+            {"url": "https://x", "events": ["finding_updated"]},
+            {"url": "https://x", "events": []},
+            {"url": "https://x", "min_severity": 2},
+            {"url": "https://x", "headers": {"X-Retries": 3}},
+            {"url": "https://x", "template": '{"text": "$nope"}'},
+            {"url": "https://x", "secret": "s"},
+        ],
+    )
+    def test_malformed(self, entry):
+        with pytest.raises(ValueError, match=r"webhooks\[0\]"):
+            parse_webhooks([entry])
+
+
+class TestFindingEvents:
+    def test_created_and_resolved(self):
+        old = [_finding("a"), _finding("b")]
+        new = [_finding("b"), _finding("c", severity=0.4), _finding("d", severity=0.9)]
+
+        events = finding_events(old, new, "shop", "abc123")
+
+        assert [(e.event, e.fields["identity_key"]) for e in events] == [
+            ("finding_created", "d"),
+            ("finding_created", "c"),
+            ("finding_resolved", "a"),
+        ]
+        assert events[0].to_dict()["files"] == "src/core.py, src/api.py"
+        assert events[0].fields["commit_sha"] == "abc123"
+
+
+class TestDeliver:
+    def test_filters_and_default_payload(self):
+        hooks = parse_webhooks(
+            [
+                {
+                    "url": "https://hooks.example.com/q",
+                    "events": ["finding_created"],
+                    "finding_types": ["high_risk_hub"],
+                    "min_severity": 0.5,
+                }
+            ]
+        )
+        events = finding_events(
+            [],
+            [_finding("a"), _finding("b", severity=0.3), _finding("c", "dead_code")],
+            "shop",
+        )
+        endpoint = _Endpoint()
+
+        (delivery,) = deliver(hooks, events, post=endpoint)
+
+        assert delivery.ok
+        (url, body, headers) = endpoint.requests[0]
+        assert body["event"] == "finding_created" and body["identity_key"] == "a"
+        assert headers["Content-Type"] == "application/json"
+
+    def test_template_and_headers(self, monkeypatch):
+        monkeypatch.setenv("CHAT_TOKEN", "s3cret")
+        (hook,) = parse_webhooks(
+            [
+                {
+                    "url": "https://chat.example.com/hook",
+                    "headers": {"Authorization": "Bearer ${CHAT_TOKEN}"},
+                    "template": '{"text": "[$project] $event: $title", "raw": $payload}',
+                }
+            ]
+        )
+        event = finding_events([], [_finding("a", title='Hub "core"')], "shop")[0]
+        endpoint = _Endpoint()
+
+        deliver([hook], [event], post=endpoint)
+
+        (_, body, headers) = endpoint.requests[0]
+        assert body["text"] == '[shop] finding_created: Hub "core"'
+        assert body["raw"]["severity"] == 0.8
+        assert headers["Authorization"] == "Bearer s3cret"
+
+    def test_gate_events(self):
+        (hook,) = parse_webhooks(
+            [{"url": "https://x.example.com", "template": '{"ok": $passed, "s": "$summary"}'}]
+        )
+        checks = {"ratchet": {"passed": False}, "fail_on": {"passed": True}}
+        endpoint = _Endpoint()
+
+        deliver([hook], [gate_event(False, checks, "shop")], post=endpoint)
+
+        assert endpoint.requests[0][1] == {"ok": False, "s": "gate failed: ratchet"}
+
+    def test_failures_are_reported_not_raised(self):
+        hooks = parse_webhooks([{"url": "https://down.example.com"}])
+        event = gate_event(True, {}, "shop")
+
+        (refused,) = deliver(hooks, [event], post=_Endpoint(OSError("connection refused")))
+        (rejected,) = deliver(hooks, [event], post=_Endpoint(500))
+
+        assert not refused.ok and refused.error == "connection refused"
+        assert not rejected.ok and rejected.status == 500
@@ -0,0 +1,72 @@
+export interface FunctionMetrics {
+  name: string;
+  start_line: number;
+  end_line: number;
+  lines: number;
+  nesting_depth: number;
+  body_tokens: number;
+  params: number;
+  heat: number;
+  complexity: number;
+  halstead_volume: number;
+  halstead_difficulty: number;
+  halstead_effort: number;
+  doc_lines: number;
+  comment_lines: number;
+  commented_out_lines: number;
+  comment_density: number;
+  cell?: number;
+}
+
+export interface FileMetrics {
+  path: string;
+  language: string;
+  lines: number;
+  halstead_volume: number;
+  halstead_difficulty: number;
+  halstead_effort: number;
+  maintainability_index: number;
+  comment_density: number;
+  commented_out_lines: number;
+  undocumented_complexity: number;
+  functions: FunctionMetrics[];
+}
+
+export interface Evidence {
+  signal: string;
+  value: number;
+  percentile: number;
+  description: string;
+}
+
+export interface Finding {
+  finding_type: string;
+  severity: number;
+  title: string;
+  files: string[];
+  evidence: Evidence[];
+  suggestion: string;
+  confidence: number;
+  effort: "LOW" | "MEDIUM" | "HIGH";
+  scope: string;
+  identity_hint: string | null;
+}
+
+export interface AnalysisResult {
+  version: string;
+  files: FileMetrics[];
+  findings: Finding[];
+}
+
+export interface LoadOptions {
+  indexURL?: string;
+  wheelURL?: string;
+  loadPyodide?: (options: { indexURL: string }) => Promise<unknown>;
+}
+
+export declare class Analyzer {
+  analyze(files: Record<string, string>, config?: Record<string, unknown>): AnalysisResult;
+  functionMetrics(path: string, content: string): FunctionMetrics[];
+}
+
+export declare function loadAnalyzer(options?: LoadOptions): Promise<Analyzer>;
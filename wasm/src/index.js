@@ -0,0 +1,78 @@
+/**
+ * Shannon Insight's parse and metric core, compiled to WebAssembly.
+ *
+ * The Python package runs on Pyodide (CPython built for WebAssembly): the
+ * Pyodide runtime is loaded from `indexURL`, then the wheel built by
+ * scripts/build-wasm.sh is installed into it. Analysis is synchronous and
+ * takes the calling thread, so web IDEs should call it from a Web Worker.
+ * See shannon_insight/wasm.py for what is computed.
+ */
+
+const PYODIDE_VERSION = "0.26.4";
+const DEFAULT_INDEX_URL = `https://cdn.jsdelivr.net/pyodide/v${PYODIDE_VERSION}/full/`;
+
+// Pyodide builds of the compiled dependencies the core imports
+const PYODIDE_PACKAGES = ["micropip", "numpy"];
+// Pure-Python dependencies installed from PyPI by micropip
+const PYPI_PACKAGES = ["rich"];
+
+/**
+ * Load Pyodide and the analyzer core.
+ * @param {object} [options]
+ * @param {string} [options.indexURL] - Where the Pyodide runtime is served from
+ * @param {string} [options.wheelURL] - The shannon-insight wheel (default: dist/ of this package)
+ * @param {Function} [options.loadPyodide] - An already imported loadPyodide
+ * @returns {Promise<Analyzer>}
+ */
+export async function loadAnalyzer(options = {}) {
+  const indexURL = options.indexURL || DEFAULT_INDEX_URL;
+  const load =
+    options.loadPyodide || (await import(/* @vite-ignore */ `${indexURL}pyodide.mjs`)).loadPyodide;
+  const pyodide = await load({ indexURL });
+  await pyodide.loadPackage(PYODIDE_PACKAGES);
+  const micropip = pyodide.pyimport("micropip");
+  await micropip.install(PYPI_PACKAGES);
+  // The wheel's other dependencies (tree-sitter, textual, ...) are not used by the core
+  await micropip.install.callKwargs(options.wheelURL || (await bundledWheel()), { deps: false });
+  return new Analyzer(pyodide.pyimport("shannon_insight.wasm"));
+}
+
+/** Analyzer core loaded into a Pyodide runtime. */
+export class Analyzer {
+  constructor(core) {
+    this.core = core;
+  }
+
+  /**
+   * Per-file metrics and the findings computable from file text alone.
+   * @param {Object<string, string>} files - File text by project-relative path
+   * @param {object} [config] - shannon-insight.toml options (max_findings, crypto_policy, ...)
+   * @returns {{version: string, files: object[], findings: object[]}}
+   * @throws {Error} When *config* is invalid
+   */
+  analyze(files, config = {}) {
+    const result = JSON.parse(this.core.analyze_json(JSON.stringify(files), JSON.stringify(config)));
+    if (result.error) throw new Error(result.error);
+    return result;
+  }
+
+  /**
+   * Per-function metrics of one file, for gutter decorations.
+   * @param {string} path - Project-relative path; its extension selects the language
+   * @param {string} content - File text
+   * @returns {object[]} One row per function; empty for unsupported languages
+   */
+  functionMetrics(path, content) {
+    return JSON.parse(this.core.function_metrics_json(path, content));
+  }
+}
+
+async function bundledWheel() {
+  const manifest = new URL("../dist/manifest.json", import.meta.url);
+  const response = await fetch(manifest);
+  if (!response.ok) {
+    throw new Error(`No wheel at ${manifest}: run scripts/build-wasm.sh or pass wheelURL`);
+  }
+  const { wheel } = await response.json();
+  return new URL(`../dist/${wheel}`, import.meta.url).href;
+}